@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/liuscraft/orion-x/internal/agent"
 	"github.com/liuscraft/orion-x/internal/config"
@@ -28,11 +29,20 @@ func main() {
 	if err := logging.Init(logging.Config{
 		Level:  appConfig.Logging.Level,
 		Format: appConfig.Logging.Format,
+		Levels: appConfig.Logging.Levels,
+		File: logging.FileConfig{
+			Path:           appConfig.Logging.File.Path,
+			Level:          appConfig.Logging.File.Level,
+			MaxSizeMB:      appConfig.Logging.File.MaxSizeMB,
+			RotateInterval: time.Duration(appConfig.Logging.File.RotateHours) * time.Hour,
+			MaxBackups:     appConfig.Logging.File.MaxBackups,
+		},
 	}); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to init logger: %v\n", err)
 		os.Exit(1)
 	}
 	defer logging.Sync()
+	logging.RegisterSecrets(appConfig.Secrets()...)
 	logging.SetTraceID(logging.NewTraceID())
 
 	ctx := context.Background()
@@ -44,6 +54,7 @@ func main() {
 
 	// 创建 VoiceAgent
 	voiceAgent, err := agent.NewVoiceAgentWithConfig(ctx, agent.Config{
+		Provider:        appConfig.LLM.Provider,
 		APIKey:          appConfig.LLM.APIKey,
 		BaseURL:         appConfig.LLM.BaseURL,
 		Model:           appConfig.LLM.Model,
@@ -77,6 +88,10 @@ func main() {
 			logging.Infof("[EmotionChanged] %s", e.Emotion)
 		case *agent.ToolCallRequestedEvent:
 			logging.Infof("[ToolCall] Tool: %s, Type: %s, Args: %v", e.Tool, e.ToolType, e.Args)
+		case *agent.MissingParamEvent:
+			logging.Infof("[MissingParam] Tool: %s, Args: %v, Missing: %v", e.Tool, e.Args, e.Missing)
+		case *agent.FollowUpEvent:
+			logging.Infof("[FollowUp] %s", e.Question)
 		case *agent.FinishedEvent:
 			if e.Error != nil {
 				logging.Errorf("[Finished] Error: %v", e.Error)
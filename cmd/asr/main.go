@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"encoding/binary"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
@@ -12,7 +14,11 @@ import (
 
 	"github.com/gordonklaus/portaudio"
 	"github.com/liuscraft/orion-x/internal/asr"
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/audio/source"
+	"github.com/liuscraft/orion-x/internal/config"
 	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/transcript"
 )
 
 const (
@@ -21,12 +27,25 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "vocab" {
+		os.Exit(runVocab(os.Args[2:], os.Stdout))
+	}
+
 	model := flag.String("model", "fun-asr-realtime", "ASR model name")
 	endpoint := flag.String("endpoint", "", "WebSocket endpoint (optional)")
 	sampleRate := flag.Int("sample-rate", defaultSampleRate, "Sample rate in Hz")
 	framesPerBuffer := flag.Int("frames", defaultFramesPerBlock, "Frames per buffer (samples)")
 	semanticPunc := flag.Bool("semantic-punctuation", false, "Enable semantic punctuation")
 	languageHints := flag.String("language-hints", "", "Comma-separated language hints (e.g. zh,en)")
+	vocabularyID := flag.String("vocabulary-id", "", "Hotword vocabulary id (see: asr vocab sync)")
+	configPath := flag.String("config", "", "voicebot config file; when set, capture through AudioInPipe with the configured device, VAD and reconnect settings")
+	output := flag.String("output", "", "write timestamped transcript to this file")
+	format := flag.String("format", "", "transcript format: txt, srt or vtt (default: from -output extension)")
+	input := flag.String("input", "", "transcribe an audio file (WAV, raw PCM, or MP3 and other formats via ffmpeg) instead of the microphone")
+	inputRate := flag.Int("input-rate", defaultSampleRate, "Sample rate of raw PCM input (.pcm/.raw)")
+	inputChannels := flag.Int("input-channels", 1, "Channels of raw PCM input (.pcm/.raw)")
+	inputFormat := flag.String("input-format", "s16", "Sample format of raw PCM input (.pcm/.raw): s16, s24, s32 or f32")
+	speed := flag.Float64("speed", 1, "Streaming speed for -input: 1 = real time, 0 = as fast as possible")
 	flag.Parse()
 	if err := logging.InitFromEnv(); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to init logger: %v\n", err)
@@ -35,6 +54,64 @@ func main() {
 	defer logging.Sync()
 	logging.SetTraceID(logging.NewTraceID())
 
+	if *input != "" && *configPath != "" {
+		logging.Fatalf("-input and -config cannot be used together")
+	}
+
+	printResult := func(text string, isFinal bool) {
+		label := "partial"
+		if isFinal {
+			label = "final"
+		}
+		fmt.Printf("%s: %s\n", label, text)
+	}
+	onResult := printResult
+	var writer *transcript.Writer
+	if *output != "" {
+		transcriptFormat := transcript.FormatFromPath(*output)
+		if *format != "" {
+			parsed, err := transcript.ParseFormat(*format)
+			if err != nil {
+				logging.Fatalf("%v", err)
+			}
+			transcriptFormat = parsed
+		}
+		file, err := os.Create(*output)
+		if err != nil {
+			logging.Fatalf("create transcript file failed: %v", err)
+		}
+		defer file.Close()
+		writer = transcript.NewWriter(file, transcriptFormat)
+		recorder := transcript.NewRecorder(func(seg transcript.Segment) {
+			if err := writer.Write(seg); err != nil {
+				logging.Errorf("write transcript failed: %v", err)
+			}
+		})
+		onResult = func(text string, isFinal bool) {
+			printResult(text, isFinal)
+			recorder.OnResult(text, isFinal)
+		}
+		defer func() {
+			logging.Infof("transcript saved to %s (%d segments, %s)", *output, writer.Count(), transcriptFormat)
+		}()
+		logging.Infof("writing %s transcript to %s", transcriptFormat, *output)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if *configPath != "" {
+		appConfig, err := config.Load(*configPath)
+		if err != nil {
+			logging.Fatalf("load config failed: %v", err)
+		}
+		if err := appConfig.ValidateKeys(true, false, false); err != nil {
+			logging.Fatalf("invalid config: %v", err)
+		}
+		runInPipe(ctx, appConfig, onResult)
+		return
+	}
+
 	apiKey := os.Getenv("DASHSCOPE_API_KEY")
 	if apiKey == "" {
 		logging.Fatalf("DASHSCOPE_API_KEY is not set")
@@ -54,27 +131,53 @@ func main() {
 	if strings.TrimSpace(*languageHints) != "" {
 		cfg.LanguageHints = splitComma(*languageHints)
 	}
+	cfg.VocabularyID = strings.TrimSpace(*vocabularyID)
 
 	recognizer, err := asr.NewDashScopeRecognizer(cfg)
 	if err != nil {
 		logging.Fatalf("init recognizer failed: %v", err)
 	}
+
+	var fileSource *source.FileSource
+	finishTimeout := 5 * time.Second
+	if *input != "" {
+		fileCfg := source.DefaultFileConfig()
+		fileCfg.SampleRate = *sampleRate
+		fileCfg.Speed = *speed
+		fileCfg.PCMSampleRate = *inputRate
+		fileCfg.PCMChannels = *inputChannels
+		if fileCfg.PCMFormat, err = audio.ParseSampleFormat(*inputFormat); err != nil {
+			logging.Fatalf("invalid -input-format: %v", err)
+		}
+		fileSource, err = source.NewFileSource(*input, fileCfg)
+		if err != nil {
+			logging.Fatalf("open input failed: %v", err)
+		}
+		defer fileSource.Close()
+		// 尽快送出时服务端识别可能落后于上传，留足时间等待最后的结果
+		finishTimeout = 30 * time.Second
+		logging.Infof("transcribing %s (%v of audio, speed=%v)", *input, fileSource.Duration(), *speed)
+	}
+
 	recognizer.OnResult(func(result asr.Result) {
-		label := "partial"
-		if result.IsFinal {
-			label = "final"
+		if fileSource == nil {
+			onResult(result.Text, result.IsFinal)
+			return
+		}
+		// 文件输入使用服务端返回的句子时间（相对音频开头），与送入速度无关
+		printResult(result.Text, result.IsFinal)
+		if writer != nil && result.IsFinal {
+			if err := writer.Write(resultSegment(result)); err != nil {
+				logging.Errorf("write transcript failed: %v", err)
+			}
 		}
-		fmt.Printf("%s: %s\n", label, result.Text)
 	})
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
-	defer stop()
-
 	if err := recognizer.Start(ctx); err != nil {
 		logging.Fatalf("start recognizer failed: %v", err)
 	}
 	defer func() {
-		finishCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		finishCtx, cancel := context.WithTimeout(context.Background(), finishTimeout)
 		defer cancel()
 		if err := recognizer.Finish(finishCtx); err != nil {
 			logging.Errorf("finish task failed: %v", err)
@@ -84,6 +187,11 @@ func main() {
 		}
 	}()
 
+	if fileSource != nil {
+		streamFile(ctx, recognizer, fileSource)
+		return
+	}
+
 	if err := portaudio.Initialize(); err != nil {
 		logging.Fatalf("portaudio init failed: %v", err)
 	}
@@ -121,6 +229,109 @@ func main() {
 	}
 }
 
+// streamFile 把文件音频按节奏送入识别器，读完后返回，由调用方 Finish 等待剩余结果
+func streamFile(ctx context.Context, recognizer asr.Recognizer, fileSource *source.FileSource) {
+	for {
+		data, err := fileSource.Read(ctx)
+		if errors.Is(err, io.EOF) {
+			logging.Infof("input finished, waiting for final results...")
+			return
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				logging.Errorf("read input failed: %v", err)
+			}
+			return
+		}
+		if err := recognizer.SendAudio(ctx, data); err != nil {
+			logging.Errorf("send audio error: %v", err)
+			return
+		}
+	}
+}
+
+// resultSegment 用识别服务返回的句子起止时间生成转写片段
+func resultSegment(result asr.Result) transcript.Segment {
+	seg := transcript.Segment{
+		Start: time.Duration(result.BeginTimeMs) * time.Millisecond,
+		Text:  result.Text,
+	}
+	seg.End = seg.Start
+	if result.EndTimeMs != nil {
+		seg.End = time.Duration(*result.EndTimeMs) * time.Millisecond
+	}
+	return seg
+}
+
+// runInPipe 按 voicebot 配置打开麦克风（设备、声道映射、重采样）并通过 AudioInPipe 识别，
+// 复用其 VAD 与断线重连；不创建 Agent 和 TTS
+func runInPipe(ctx context.Context, appConfig *config.AppConfig, onResult func(text string, isFinal bool)) {
+	if err := portaudio.Initialize(); err != nil {
+		logging.Fatalf("portaudio init failed: %v", err)
+	}
+	defer portaudio.Terminate()
+
+	inCfg := appConfig.Audio.InPipe
+	inPipeCfg := &audio.InPipeConfig{
+		SampleRate:   inCfg.SampleRate,
+		Channels:     1,
+		EnableVAD:    inCfg.EnableVAD,
+		VADThreshold: inCfg.VADThreshold,
+		ASRModel:     appConfig.ASR.Model,
+		ASREndpoint:  appConfig.ASR.Endpoint,
+		ASRHeartbeat: appConfig.ASR.Heartbeat,
+		ASRKeepalive: time.Duration(appConfig.ASR.KeepaliveMs) * time.Millisecond,
+
+		ASRLanguageHints: appConfig.ASR.LanguageHints,
+		ASRVocabularyID:  appConfig.ASR.VocabularyID,
+
+		ReconnectInitialBackoff: time.Duration(inCfg.ReconnectInitialBackoffMs) * time.Millisecond,
+		ReconnectMaxBackoff:     time.Duration(inCfg.ReconnectMaxBackoffMs) * time.Millisecond,
+		ReplayBufferMs:          inCfg.ReplayBufferMs,
+	}
+
+	bufferSize := inCfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = 3200
+	}
+	inputChannels := inCfg.InputChannels
+	if inputChannels <= 0 {
+		inputChannels = inCfg.Channels
+	}
+	mic, err := source.NewMicrophoneSourceWithDevice(inCfg.SampleRate, inputChannels, bufferSize, inCfg.HighLatency, inCfg.InputDevice)
+	if err != nil {
+		logging.Fatalf("open microphone failed: %v", err)
+	}
+	var audioSource audio.AudioSource = mic
+	if mic.Channels() > 1 {
+		audioSource = audio.NewChannelMapSource(audioSource, mic.Channels(), inCfg.ChannelSelect)
+	}
+	if mic.SampleRate() != inPipeCfg.SampleRate {
+		logging.Infof("resampling input from %d Hz to %d Hz", mic.SampleRate(), inPipeCfg.SampleRate)
+		audioSource = audio.NewResamplingSource(audioSource, mic.SampleRate(), inPipeCfg.SampleRate, 1, nil)
+	}
+
+	inPipe, err := audio.NewInPipeWithAudioSource(appConfig.ASR.APIKey, inPipeCfg, audioSource)
+	if err != nil {
+		logging.Fatalf("create AudioInPipe failed: %v", err)
+	}
+	inPipe.OnASRResult(onResult)
+	inPipe.OnRecognizerStatus(func(available bool, err error) {
+		if !available {
+			logging.Warnf("recognizer unavailable, reconnecting: %v", err)
+		}
+	})
+	if err := inPipe.Start(ctx); err != nil {
+		logging.Fatalf("start AudioInPipe failed: %v", err)
+	}
+	logging.Infof("listening via AudioInPipe (device=%q, vad=%v)... press Ctrl+C to stop", inCfg.InputDevice, inCfg.EnableVAD)
+
+	<-ctx.Done()
+	if err := inPipe.Stop(); err != nil {
+		logging.Errorf("stop AudioInPipe failed: %v", err)
+	}
+}
+
 func encodeInt16LE(dst []byte, src []int16) {
 	for i, v := range src {
 		binary.LittleEndian.PutUint16(dst[i*2:], uint16(v))
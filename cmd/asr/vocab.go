@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/asr"
+	"github.com/liuscraft/orion-x/internal/config"
+)
+
+const vocabUsage = `usage: asr vocab <command> [flags]
+
+commands:
+  sync    create or update the vocabulary from asr.vocabulary in the config file
+          and write the vocabulary id back to asr.vocabulary_id
+  show    print the hotwords of a vocabulary (default: asr.vocabulary_id)
+  list    list vocabularies under a prefix (default: asr.vocabulary.prefix)
+  delete  delete a vocabulary (-id required)
+`
+
+// runVocab 热词表管理子命令，返回进程退出码
+func runVocab(args []string, out io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, vocabUsage)
+		return 2
+	}
+	command := args[0]
+	fs := flag.NewFlagSet("vocab "+command, flag.ContinueOnError)
+	configPath := fs.String("config", config.DefaultPath, "voicebot config file")
+	endpoint := fs.String("endpoint", "", "vocabulary management endpoint (optional)")
+	id := fs.String("id", "", "vocabulary id (overrides asr.vocabulary_id)")
+	prefix := fs.String("prefix", "", "vocabulary prefix (overrides asr.vocabulary.prefix)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	appConfig, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "load config failed: %v\n", err)
+		return 1
+	}
+	if err := appConfig.ValidateKeys(true, false, false); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid config: %v\n", err)
+		return 1
+	}
+	client, err := asr.NewVocabularyClient(appConfig.ASR.APIKey, strings.TrimSpace(*endpoint))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return 1
+	}
+	vocabularyID := appConfig.ASR.VocabularyID
+	if *id != "" {
+		vocabularyID = *id
+	}
+	vocabularyPrefix := appConfig.ASR.Vocabulary.Prefix
+	if *prefix != "" {
+		vocabularyPrefix = *prefix
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	switch command {
+	case "sync":
+		err = syncVocabulary(ctx, client, appConfig, *configPath, vocabularyID, vocabularyPrefix, out)
+	case "show":
+		err = showVocabulary(ctx, client, vocabularyID, out)
+	case "list":
+		err = listVocabularies(ctx, client, vocabularyPrefix, out)
+	case "delete":
+		if *id == "" {
+			err = errors.New("-id is required for delete")
+			break
+		}
+		if err = client.Delete(ctx, *id); err == nil {
+			fmt.Fprintf(out, "deleted %s\n", *id)
+			if *id == appConfig.ASR.VocabularyID {
+				fmt.Fprintln(out, "note: asr.vocabulary_id still refers to the deleted vocabulary")
+			}
+		}
+	default:
+		fmt.Fprint(os.Stderr, vocabUsage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "vocab %s failed: %v\n", command, err)
+		return 1
+	}
+	return 0
+}
+
+// syncVocabulary 已配置 vocabulary_id 时整体更新，否则创建并写回配置文件
+func syncVocabulary(ctx context.Context, client *asr.VocabularyClient, appConfig *config.AppConfig, configPath, vocabularyID, prefix string, out io.Writer) error {
+	words := hotwordsFromConfig(appConfig.ASR.Vocabulary.Hotwords)
+	if len(words) == 0 {
+		return errors.New("asr.vocabulary.hotwords is empty")
+	}
+	if vocabularyID != "" {
+		if err := client.Update(ctx, vocabularyID, words); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "updated %s (%d hotwords)\n", vocabularyID, len(words))
+		return nil
+	}
+
+	created, err := client.Create(ctx, appConfig.ASR.Model, prefix, words)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "created %s (%d hotwords) for %s\n", created, len(words), appConfig.ASR.Model)
+	if err := config.UpdateFile(configPath, map[string]interface{}{"asr.vocabulary_id": created}); err != nil {
+		return fmt.Errorf("write asr.vocabulary_id to %s: %w", configPath, err)
+	}
+	fmt.Fprintf(out, "asr.vocabulary_id written to %s\n", configPath)
+	return nil
+}
+
+func showVocabulary(ctx context.Context, client *asr.VocabularyClient, vocabularyID string, out io.Writer) error {
+	if vocabularyID == "" {
+		return errors.New("no vocabulary id: set asr.vocabulary_id or pass -id")
+	}
+	words, err := client.Get(ctx, vocabularyID)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s (%d hotwords)\n", vocabularyID, len(words))
+	for _, w := range words {
+		lang := w.Lang
+		if lang == "" {
+			lang = "-"
+		}
+		fmt.Fprintf(out, "  %-4s weight=%d  %s\n", lang, w.Weight, w.Text)
+	}
+	return nil
+}
+
+func listVocabularies(ctx context.Context, client *asr.VocabularyClient, prefix string, out io.Writer) error {
+	list, err := client.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	if len(list) == 0 {
+		fmt.Fprintln(out, "no vocabularies")
+		return nil
+	}
+	for _, v := range list {
+		fmt.Fprintf(out, "%s  %-10s  modified %s\n", v.ID, v.Status, v.GmtModified)
+	}
+	return nil
+}
+
+func hotwordsFromConfig(words []config.HotwordConfig) []asr.Hotword {
+	out := make([]asr.Hotword, 0, len(words))
+	for _, w := range words {
+		out = append(out, asr.Hotword{Text: w.Text, Weight: w.Weight, Lang: w.Lang})
+	}
+	return out
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// 回声延迟测量参数：以 ASR 采样率打开全双工流，与 voicebot 的回声参考对齐方式一致
+const (
+	echoSampleRate   = asrSampleRate
+	echoLeadMs       = 300  // 播放扫频前的静音，录下底噪并等待流稳定
+	echoChirpMs      = 250  // 扫频时长
+	echoTailMs       = 700  // 扫频后继续录音的时长，决定可测量的最大延迟
+	echoMaxDelayMs   = 600  // 互相关搜索的最大延迟
+	echoChirpStartHz = 300  // 扫频起始频率
+	echoChirpEndHz   = 4000 // 扫频终止频率（低于 8kHz 奈奎斯特频率，避开小喇叭失真的高频段）
+	echoChirpGain    = 0.5
+	echoMinPeak      = 0.3 // 归一化相关峰低于该值时认为没有录到回声
+)
+
+// echoMeasurement 单次测量结果
+type echoMeasurement struct {
+	Delay time.Duration // 播放到录回扫频的延迟
+	Peak  float64       // 归一化互相关峰值，0～1，越高越可信
+}
+
+// generateChirp 生成线性扫频信号，首尾各 10% 用汉宁窗渐变，避免喇叭爆音
+func generateChirp(sampleRate, durationMs int, startHz, endHz float64, gain float32) []float32 {
+	n := sampleRate * durationMs / 1000
+	out := make([]float32, n)
+	if n == 0 {
+		return out
+	}
+	duration := float64(n) / float64(sampleRate)
+	sweep := (endHz - startHz) / duration
+	taper := n / 10
+	for i := range out {
+		t := float64(i) / float64(sampleRate)
+		v := math.Sin(2 * math.Pi * (startHz*t + sweep*t*t/2))
+		switch {
+		case taper > 0 && i < taper:
+			v *= 0.5 - 0.5*math.Cos(math.Pi*float64(i)/float64(taper))
+		case taper > 0 && i >= n-taper:
+			v *= 0.5 - 0.5*math.Cos(math.Pi*float64(n-1-i)/float64(taper))
+		}
+		out[i] = gain * float32(v)
+	}
+	return out
+}
+
+// estimateEchoDelay 在 [0, maxLag] 内搜索 recorded 与 played 归一化互相关的峰值，返回对应的延迟样本数与峰值
+// played 与 recorded 按同一时间轴对齐（第 i 个样本在同一次流回调中播放 / 录制）
+func estimateEchoDelay(played, recorded []float32, maxLag int) (int, float64) {
+	// 只对播放信号的非零部分做相关，前导静音不参与
+	start, end := 0, len(played)
+	for start < end && played[start] == 0 {
+		start++
+	}
+	for end > start && played[end-1] == 0 {
+		end--
+	}
+	ref := played[start:end]
+	if len(ref) == 0 {
+		return 0, 0
+	}
+
+	var refEnergy float64
+	for _, v := range ref {
+		refEnergy += float64(v) * float64(v)
+	}
+
+	bestLag, bestPeak := 0, 0.0
+	for lag := 0; lag <= maxLag; lag++ {
+		offset := start + lag
+		if offset+len(ref) > len(recorded) {
+			break
+		}
+		window := recorded[offset : offset+len(ref)]
+		var dot, energy float64
+		for i, v := range window {
+			dot += float64(ref[i]) * float64(v)
+			energy += float64(v) * float64(v)
+		}
+		if energy == 0 {
+			continue
+		}
+		// 取绝对值：部分声卡或喇叭接线会让回声反相
+		peak := math.Abs(dot) / math.Sqrt(refEnergy*energy)
+		if peak > bestPeak {
+			bestLag, bestPeak = lag, peak
+		}
+	}
+	return bestLag, bestPeak
+}
+
+// summarizeEchoDelays 取可信测量结果的中位数；没有可信结果时返回 false
+func summarizeEchoDelays(results []echoMeasurement, minPeak float64) (time.Duration, bool) {
+	var delays []time.Duration
+	for _, r := range results {
+		if r.Peak >= minPeak {
+			delays = append(delays, r.Delay)
+		}
+	}
+	if len(delays) == 0 {
+		return 0, false
+	}
+	sort.Slice(delays, func(i, j int) bool { return delays[i] < delays[j] })
+	return delays[len(delays)/2], true
+}
+
+// recommendFarEndDelayMs 将测得的延迟换算为 aec.far_end_delay_ms：向下取整到 AEC 帧长的整数倍
+// （voicebot 按 far_end_delay_ms / frame_ms 帧延迟参考信号，宁可略早也不要晚于回声）
+func recommendFarEndDelayMs(delay time.Duration, frameMs int) int {
+	ms := int(delay / time.Millisecond)
+	if frameMs <= 0 {
+		return ms
+	}
+	return ms / frameMs * frameMs
+}
+
+// measureEchoDelay 在默认输入、输出设备上打开全双工流，播放扫频的同时录音，并估计回声延迟
+func measureEchoDelay() (echoMeasurement, error) {
+	chirp := generateChirp(echoSampleRate, echoChirpMs, echoChirpStartHz, echoChirpEndHz, echoChirpGain)
+	lead := echoSampleRate * echoLeadMs / 1000
+	total := lead + len(chirp) + echoSampleRate*echoTailMs/1000
+
+	played := make([]float32, total)
+	copy(played[lead:], chirp)
+	recorded := make([]float32, 0, total)
+
+	var (
+		mu   sync.Mutex
+		pos  int
+		done = make(chan struct{})
+	)
+	callback := func(in, out []float32) {
+		mu.Lock()
+		defer mu.Unlock()
+		for i := range out {
+			out[i] = 0
+			if pos < total {
+				out[i] = played[pos]
+				if i < len(in) {
+					recorded = append(recorded, in[i])
+				}
+				pos++
+				if pos == total {
+					close(done)
+				}
+			}
+		}
+	}
+
+	stream, err := portaudio.OpenDefaultStream(1, 1, echoSampleRate, 256, callback)
+	if err != nil {
+		return echoMeasurement{}, fmt.Errorf("open full-duplex stream: %w", err)
+	}
+	defer stream.Close()
+	if err := stream.Start(); err != nil {
+		return echoMeasurement{}, fmt.Errorf("start full-duplex stream: %w", err)
+	}
+
+	timeout := time.Duration(total/echoSampleRate+3) * time.Second
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		stream.Stop()
+		return echoMeasurement{}, fmt.Errorf("full-duplex stream stalled (no callback within %v)", timeout)
+	}
+	if err := stream.Stop(); err != nil {
+		return echoMeasurement{}, fmt.Errorf("stop full-duplex stream: %w", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	lag, peak := estimateEchoDelay(played, recorded, echoSampleRate*echoMaxDelayMs/1000)
+	return echoMeasurement{
+		Delay: time.Duration(lag) * time.Second / echoSampleRate,
+		Peak:  peak,
+	}, nil
+}
+
+// runEchoDelayTest 多次测量回声延迟并输出推荐的 aec.far_end_delay_ms
+func runEchoDelayTest(runs, frameMs int) {
+	fmt.Println("=== Echo Delay Measurement ===")
+	fmt.Println("A short chirp will be played through the default output device while the default")
+	fmt.Println("input device records. Use speakers (not headphones) at a normal listening volume")
+	fmt.Println("and keep the room quiet.")
+	fmt.Println()
+
+	if runs < 1 {
+		runs = 1
+	}
+	results := make([]echoMeasurement, 0, runs)
+	for i := 0; i < runs; i++ {
+		m, err := measureEchoDelay()
+		if err != nil {
+			fmt.Printf("❌ Run %d: %v\n", i+1, err)
+			fmt.Println("   The devices may not support full-duplex at 16 kHz; try -test-duplex for details.")
+			return
+		}
+		mark := "✅"
+		if m.Peak < echoMinPeak {
+			mark = "⚠️ "
+		}
+		fmt.Printf("%s Run %d: delay %.1fms (correlation %.2f)\n", mark, i+1, m.Delay.Seconds()*1000, m.Peak)
+		results = append(results, m)
+		time.Sleep(300 * time.Millisecond)
+	}
+	fmt.Println()
+
+	delay, ok := summarizeEchoDelays(results, echoMinPeak)
+	if !ok {
+		fmt.Println("❌ The chirp was not picked up by the microphone.")
+		fmt.Println("   Turn up the output volume, move the microphone closer to the speaker, or check")
+		fmt.Println("   that the default devices are the ones voicebot uses.")
+		return
+	}
+
+	recommended := recommendFarEndDelayMs(delay, frameMs)
+	fmt.Printf("Measured echo delay (median): %.1fms\n", delay.Seconds()*1000)
+	fmt.Println()
+	fmt.Println("Merge this into your config/voicebot.json:")
+	fmt.Println()
+	fmt.Printf("    \"audio\": {\"in_pipe\": {\"aec\": {\"far_end_delay_ms\": %d}}}\n", recommended)
+	fmt.Println()
+	fmt.Printf("💡 Rounded down to a multiple of aec.frame_ms (%dms). The value applies to\n", frameMs)
+	fmt.Println("   audio.full_duplex mode; with separate input/output streams, output buffering")
+	fmt.Println("   usually adds a few tens of milliseconds, so re-check with echo gating logs.")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// simulateEcho 把 played 延迟 delay 个样本、衰减后叠加噪声，模拟麦克风录到的回声
+func simulateEcho(played []float32, delay int, gain, noise float32) []float32 {
+	rng := rand.New(rand.NewSource(1))
+	recorded := make([]float32, len(played))
+	for i := range recorded {
+		if j := i - delay; j >= 0 {
+			recorded[i] = gain * played[j]
+		}
+		recorded[i] += noise * (rng.Float32()*2 - 1)
+	}
+	return recorded
+}
+
+func TestEstimateEchoDelay(t *testing.T) {
+	chirp := generateChirp(echoSampleRate, echoChirpMs, echoChirpStartHz, echoChirpEndHz, echoChirpGain)
+	lead := echoSampleRate * echoLeadMs / 1000
+	played := make([]float32, lead+len(chirp)+echoSampleRate*echoTailMs/1000)
+	copy(played[lead:], chirp)
+	maxLag := echoSampleRate * echoMaxDelayMs / 1000
+
+	tests := []struct {
+		name     string
+		delay    int
+		gain     float32
+		noise    float32
+		wantEcho bool
+	}{
+		{"no delay", 0, 0.5, 0.01, true},
+		{"85ms", 85 * echoSampleRate / 1000, 0.2, 0.02, true},
+		{"inverted 300ms", 300 * echoSampleRate / 1000, -0.1, 0.02, true},
+		{"noise only", 0, 0, 0.05, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lag, peak := estimateEchoDelay(played, simulateEcho(played, tt.delay, tt.gain, tt.noise), maxLag)
+			if got := peak >= echoMinPeak; got != tt.wantEcho {
+				t.Fatalf("peak = %.2f, want echo detected = %v", peak, tt.wantEcho)
+			}
+			if tt.wantEcho && lag != tt.delay {
+				t.Errorf("lag = %d, want %d", lag, tt.delay)
+			}
+		})
+	}
+}
+
+func TestEstimateEchoDelaySilentReference(t *testing.T) {
+	if lag, peak := estimateEchoDelay(make([]float32, 100), make([]float32, 100), 10); lag != 0 || peak != 0 {
+		t.Errorf("estimateEchoDelay() = %d, %v, want 0, 0", lag, peak)
+	}
+}
+
+func TestSummarizeEchoDelays(t *testing.T) {
+	results := []echoMeasurement{
+		{Delay: 90 * time.Millisecond, Peak: 0.8},
+		{Delay: 400 * time.Millisecond, Peak: 0.1}, // 未录到回声，忽略
+		{Delay: 70 * time.Millisecond, Peak: 0.6},
+		{Delay: 80 * time.Millisecond, Peak: 0.7},
+	}
+	if got, ok := summarizeEchoDelays(results, echoMinPeak); !ok || got != 80*time.Millisecond {
+		t.Errorf("summarizeEchoDelays() = %v, %v, want 80ms, true", got, ok)
+	}
+	if _, ok := summarizeEchoDelays(results[1:2], echoMinPeak); ok {
+		t.Error("summarizeEchoDelays() should fail without confident measurements")
+	}
+}
+
+func TestRecommendFarEndDelayMs(t *testing.T) {
+	tests := []struct {
+		delay   time.Duration
+		frameMs int
+		want    int
+	}{
+		{87 * time.Millisecond, 10, 80},
+		{87 * time.Millisecond, 20, 80},
+		{87*time.Millisecond + 900*time.Microsecond, 0, 87},
+		{5 * time.Millisecond, 10, 0},
+	}
+	for _, tt := range tests {
+		if got := recommendFarEndDelayMs(tt.delay, tt.frameMs); got != tt.want {
+			t.Errorf("recommendFarEndDelayMs(%v, %d) = %d, want %d", tt.delay, tt.frameMs, got, tt.want)
+		}
+	}
+}
@@ -8,13 +8,24 @@ import (
 	"time"
 
 	"github.com/gordonklaus/portaudio"
+	"github.com/liuscraft/orion-x/internal/audio"
 )
 
 func main() {
 	testFullDuplex := flag.Bool("test-duplex", false, "Run full-duplex test (simultaneous input/output)")
 	duplexDuration := flag.Int("duration", 5, "Duration of full-duplex test in seconds")
+	deviceName := flag.String("device", "", "Input device for the recommended config (partial name match, default: default input)")
+	measureDelay := flag.Bool("measure-delay", false, "Measure the acoustic echo delay with a chirp and recommend aec.far_end_delay_ms")
+	delayRuns := flag.Int("delay-runs", 3, "Number of chirps played by -measure-delay (the median is reported)")
+	listPulse := flag.Bool("pulse", false, "List PulseAudio/PipeWire sinks and sources for audio.backend \"pulse\" and exit")
+	aecFrameMs := flag.Int("aec-frame-ms", audio.DefaultEchoCancelConfig().FrameMs, "aec.frame_ms used to round the recommended far_end_delay_ms")
 	flag.Parse()
 
+	if *listPulse {
+		runPulseList()
+		return
+	}
+
 	fmt.Println("=== PortAudio Audio Device Diagnostics ===")
 	fmt.Println()
 
@@ -29,6 +40,11 @@ func main() {
 		return
 	}
 
+	if *measureDelay {
+		runEchoDelayTest(*delayRuns, *aecFrameMs)
+		return
+	}
+
 	// Get host APIs
 	hostAPIs, err := portaudio.HostApis()
 	if err != nil {
@@ -104,14 +120,24 @@ func main() {
 			dev.DefaultLowOutputLatency.Seconds()*1000,
 			dev.DefaultHighOutputLatency.Seconds()*1000)
 
+		// 按实际探测结果输出兼容性矩阵，而不是只看默认采样率
+		var inputFormats formatMatrix
+		if dev.MaxInputChannels > 0 {
+			inputFormats = probeDevice(dev, true)
+			fmt.Printf("    Supported input formats (int16):\n")
+			inputFormats.print(os.Stdout, "    ")
+		}
+		if dev.MaxOutputChannels > 0 {
+			fmt.Printf("    Supported output formats (float32):\n")
+			probeDevice(dev, false).print(os.Stdout, "    ")
+		}
+
 		// Provide recommendations for input devices
 		if dev.MaxInputChannels > 0 {
 			fmt.Printf("    --- Recommendations for Input ---\n")
 
-			// Check sample rate
-			if dev.DefaultSampleRate != 16000 {
-				fmt.Printf("    ⚠️  Sample rate is %.0f Hz, not 16000 Hz\n", dev.DefaultSampleRate)
-				fmt.Printf("       Consider setting sample_rate to %.0f in config\n", dev.DefaultSampleRate)
+			if !inputFormats.supports(asrSampleRate) {
+				fmt.Printf("    ⚠️  %d Hz is not supported, voicebot will resample the input for ASR\n", asrSampleRate)
 			}
 
 			// Check latency
@@ -134,42 +160,75 @@ func main() {
 		fmt.Println()
 	}
 
-	// Print config recommendations for default input
-	if defaultInput != nil && defaultInput.MaxInputChannels > 0 {
-		fmt.Println("=== Recommended Config for Default Input Device ===")
-		fmt.Println()
+	printRecommendedConfig(devices, defaultInput, defaultOutput, *deviceName)
+}
 
-		sampleRate := int(defaultInput.DefaultSampleRate)
-		if sampleRate == 0 {
-			sampleRate = 16000
+// printRecommendedConfig 探测选定的输入设备和默认输出设备，输出可直接粘贴的配置
+func printRecommendedConfig(devices []*portaudio.DeviceInfo, input, output *portaudio.DeviceInfo, deviceName string) {
+	if deviceName != "" {
+		input = nil
+		for _, dev := range devices {
+			if dev.MaxInputChannels > 0 && strings.Contains(strings.ToLower(dev.Name), strings.ToLower(deviceName)) {
+				input = dev
+				break
+			}
 		}
-
-		highLatency := defaultInput.DefaultHighInputLatency.Seconds()*1000 > 50
-
-		bufferMs := int(defaultInput.DefaultHighInputLatency.Seconds() * 1000 * 3)
-		if bufferMs < 200 {
-			bufferMs = 200
+		if input == nil {
+			fmt.Printf("❌ No input device matches %q\n", deviceName)
+			return
 		}
-		bufferSize := sampleRate * bufferMs / 1000
+	}
+	if input != nil && input.MaxInputChannels == 0 {
+		input = nil
+	}
+	if output != nil && output.MaxOutputChannels == 0 {
+		output = nil
+	}
+	if input == nil && output == nil {
+		return
+	}
 
-		fmt.Println("Add this to your config/voicebot.json:")
-		fmt.Println()
-		fmt.Println("\"in_pipe\": {")
-		fmt.Printf("    \"sample_rate\": %d,\n", sampleRate)
-		fmt.Println("    \"channels\": 1,")
-		fmt.Println("    \"enable_vad\": true,")
-		fmt.Println("    \"vad_threshold\": 0.5,")
-		fmt.Printf("    \"buffer_size\": %d,\n", bufferSize)
-		fmt.Printf("    \"high_latency\": %v\n", highLatency)
-		fmt.Println("}")
-		fmt.Println()
+	var inputMatrix, outputMatrix formatMatrix
+	if input != nil {
+		inputMatrix = probeDevice(input, true)
+	}
+	if output != nil {
+		outputMatrix = probeDevice(output, false)
+	}
 
-		if defaultInput.DefaultSampleRate != 16000 {
-			fmt.Printf("⚠️  NOTE: Your device uses %.0f Hz, but ASR expects 16000 Hz.\n", defaultInput.DefaultSampleRate)
-			fmt.Println("   Audio resampling may be needed, which could affect quality.")
-			fmt.Println("   For best results, try to use a device that supports 16000 Hz.")
+	fmt.Println("=== Recommended Config ===")
+	fmt.Println()
+	if input != nil {
+		fmt.Printf("Input:  %s\n", input.Name)
+	}
+	if output != nil {
+		fmt.Printf("Output: %s\n", output.Name)
+	}
+	fmt.Println()
+	fmt.Println("Merge this into your config/voicebot.json:")
+	fmt.Println()
+	block := buildConfigBlock(input, inputMatrix, output, outputMatrix)
+	if err := block.print(os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to encode config: %v\n", err)
+		return
+	}
+	fmt.Println()
+
+	if input != nil {
+		rec := recommendInput(inputMatrix)
+		switch {
+		case !rec.OK:
+			fmt.Printf("❌ %s did not accept any probed input format.\n", input.Name)
+		case rec.DeviceRate != asrSampleRate:
+			fmt.Printf("⚠️  NOTE: %s does not support %d Hz; voicebot will open it at %.0f Hz and resample for ASR.\n",
+				input.Name, asrSampleRate, rec.DeviceRate)
+		case rec.Channels > 1:
+			fmt.Printf("⚠️  NOTE: %s does not support mono input; voicebot will downmix %d channels.\n", input.Name, rec.Channels)
 		}
 	}
+	if output != nil && block.Audio.Mixer == nil {
+		fmt.Printf("❌ %s did not accept any probed stereo output format.\n", output.Name)
+	}
 }
 
 func runFullDuplexTest(durationSec int) {
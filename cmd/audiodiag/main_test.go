@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// fakeProbe 只接受 formats 中列出的 (采样率, 声道数)
+func fakeProbe(formats ...[2]int) probeFunc {
+	return func(rate float64, channels int) error {
+		for _, f := range formats {
+			if float64(f[0]) == rate && f[1] == channels {
+				return nil
+			}
+		}
+		return errors.New("invalid sample rate")
+	}
+}
+
+func TestRecommendInput(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxChannels int
+		probe       probeFunc
+		want        inputRecommendation
+	}{
+		{"mono 16k", 2, fakeProbe([2]int{16000, 1}, [2]int{48000, 2}), inputRecommendation{Channels: 1, DeviceRate: 16000, OK: true}},
+		{"stereo-only 16k", 2, fakeProbe([2]int{16000, 2}, [2]int{48000, 1}), inputRecommendation{Channels: 2, DeviceRate: 16000, OK: true}},
+		{"bluetooth 44.1k/48k", 1, fakeProbe([2]int{44100, 1}, [2]int{48000, 1}), inputRecommendation{Channels: 1, DeviceRate: 48000, OK: true}},
+		{"stereo not probed beyond max channels", 1, fakeProbe([2]int{16000, 2}), inputRecommendation{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := buildMatrix(tt.maxChannels, tt.probe)
+			if got := recommendInput(m); got != tt.want {
+				t.Errorf("recommendInput() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommendOutputRate(t *testing.T) {
+	tests := []struct {
+		name   string
+		probe  probeFunc
+		want   float64
+		wantOK bool
+	}{
+		{"prefers 16k", fakeProbe([2]int{16000, 2}, [2]int{48000, 2}), 16000, true},
+		{"falls back to 48k", fakeProbe([2]int{44100, 2}, [2]int{48000, 2}), 48000, true},
+		{"mono only", fakeProbe([2]int{16000, 1}), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := recommendOutputRate(buildMatrix(2, tt.probe))
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("recommendOutputRate() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	for rate, want := range map[float64]string{8000: "8k", 22050: "22.05k", 44100: "44.1k", 48000: "48k"} {
+		if got := formatRate(rate); got != want {
+			t.Errorf("formatRate(%v) = %q, want %q", rate, got, want)
+		}
+	}
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// probeRates 逐一探测的常见采样率
+var probeRates = []float64{8000, 16000, 22050, 24000, 44100, 48000}
+
+// probeChannels 逐一探测的声道数（不超过设备最大声道数）
+var probeChannels = []int{1, 2}
+
+// asrSampleRate ASR 要求的采样率
+const asrSampleRate = 16000
+
+// formatMatrix 设备在各采样率 × 声道数下是否支持（由 IsFormatSupported 实际探测）
+type formatMatrix struct {
+	Rates     []float64
+	Channels  []int
+	Supported map[int]map[float64]bool // 声道数 -> 采样率 -> 是否支持
+}
+
+// probeFunc 探测一种格式，支持时返回 nil
+type probeFunc func(rate float64, channels int) error
+
+// buildMatrix 对每个采样率和不超过 maxChannels 的声道数调用 probe
+func buildMatrix(maxChannels int, probe probeFunc) formatMatrix {
+	m := formatMatrix{Rates: probeRates, Supported: make(map[int]map[float64]bool)}
+	for _, channels := range probeChannels {
+		if channels > maxChannels {
+			continue
+		}
+		m.Channels = append(m.Channels, channels)
+		m.Supported[channels] = make(map[float64]bool)
+		for _, rate := range probeRates {
+			m.Supported[channels][rate] = probe(rate, channels) == nil
+		}
+	}
+	return m
+}
+
+// probeDevice 探测设备的输入或输出格式；样本格式与 voicebot 一致（输入 int16 交错，输出 float32 分声道）
+func probeDevice(dev *portaudio.DeviceInfo, input bool) formatMatrix {
+	if input {
+		return buildMatrix(dev.MaxInputChannels, func(rate float64, channels int) error {
+			params := portaudio.StreamParameters{
+				Input:           portaudio.StreamDeviceParameters{Device: dev, Channels: channels, Latency: dev.DefaultHighInputLatency},
+				SampleRate:      rate,
+				FramesPerBuffer: portaudio.FramesPerBufferUnspecified,
+			}
+			return portaudio.IsFormatSupported(params, make([]int16, 1024*channels))
+		})
+	}
+	return buildMatrix(dev.MaxOutputChannels, func(rate float64, channels int) error {
+		params := portaudio.StreamParameters{
+			Output:          portaudio.StreamDeviceParameters{Device: dev, Channels: channels, Latency: dev.DefaultHighOutputLatency},
+			SampleRate:      rate,
+			FramesPerBuffer: portaudio.FramesPerBufferUnspecified,
+		}
+		buf := make([][]float32, channels)
+		for i := range buf {
+			buf[i] = make([]float32, 1024)
+		}
+		return portaudio.IsFormatSupported(params, buf)
+	})
+}
+
+// supports 任一声道数下支持该采样率
+func (m formatMatrix) supports(rate float64) bool {
+	for _, channels := range m.Channels {
+		if m.Supported[channels][rate] {
+			return true
+		}
+	}
+	return false
+}
+
+// print 输出兼容性矩阵，每行一个声道数
+func (m formatMatrix) print(w io.Writer, indent string) {
+	if len(m.Channels) == 0 {
+		return
+	}
+	header := indent + "      "
+	for _, rate := range m.Rates {
+		header += fmt.Sprintf("%8s", formatRate(rate))
+	}
+	fmt.Fprintln(w, header)
+	for _, channels := range m.Channels {
+		row := fmt.Sprintf("%s  %dch ", indent, channels)
+		for _, rate := range m.Rates {
+			mark := "-"
+			if m.Supported[channels][rate] {
+				mark = "✓"
+			}
+			row += fmt.Sprintf("%8s", mark)
+		}
+		fmt.Fprintln(w, row)
+	}
+}
+
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate/1000, 'f', -1, 64) + "k"
+}
+
+// inputRecommendation 输入设备的推荐配置
+type inputRecommendation struct {
+	Channels   int     // 打开设备的声道数
+	DeviceRate float64 // 设备实际打开的采样率，与 ASR 采样率不同时 voicebot 自动重采样
+	OK         bool
+}
+
+// recommendInput 优先单声道 16kHz；不支持时选支持 16kHz 的声道数，再退回到最高采样率（voicebot 会重采样）
+func recommendInput(m formatMatrix) inputRecommendation {
+	for _, channels := range m.Channels {
+		if m.Supported[channels][asrSampleRate] {
+			return inputRecommendation{Channels: channels, DeviceRate: asrSampleRate, OK: true}
+		}
+	}
+	for i := len(m.Rates) - 1; i >= 0; i-- {
+		for _, channels := range m.Channels {
+			if m.Supported[channels][m.Rates[i]] {
+				return inputRecommendation{Channels: channels, DeviceRate: m.Rates[i], OK: true}
+			}
+		}
+	}
+	return inputRecommendation{}
+}
+
+// mixerChannels Mixer 固定以立体声输出
+const mixerChannels = 2
+
+// recommendOutputRate Mixer 采样率：优先与 ASR 相同的 16kHz（提示音与回声参考无需重采样），其次 48k / 44.1k / 24k
+func recommendOutputRate(m formatMatrix) (float64, bool) {
+	for _, rate := range []float64{asrSampleRate, 48000, 44100, 24000, 22050, 8000} {
+		if m.Supported[mixerChannels][rate] {
+			return rate, true
+		}
+	}
+	return 0, false
+}
+
+// configBlock 可直接粘贴到 config/voicebot.json 的 audio 配置
+type configBlock struct {
+	Audio struct {
+		Mixer *mixerBlock `json:"mixer,omitempty"`
+		// InPipe 只列出与设备相关的字段，其余保持默认
+		InPipe *inPipeBlock `json:"in_pipe,omitempty"`
+	} `json:"audio"`
+}
+
+type mixerBlock struct {
+	SampleRate   int    `json:"sample_rate"`
+	Channels     int    `json:"channels"`
+	OutputDevice string `json:"output_device,omitempty"`
+}
+
+type inPipeBlock struct {
+	SampleRate    int    `json:"sample_rate"`
+	Channels      int    `json:"channels"`
+	InputDevice   string `json:"input_device,omitempty"`
+	InputChannels int    `json:"input_channels"`
+	ChannelSelect int    `json:"channel_select"`
+	BufferSize    int    `json:"buffer_size"`
+	HighLatency   bool   `json:"high_latency"`
+}
+
+// buildConfigBlock 按探测结果生成配置；input / output 为 nil 时省略对应部分
+func buildConfigBlock(input *portaudio.DeviceInfo, inputMatrix formatMatrix, output *portaudio.DeviceInfo, outputMatrix formatMatrix) configBlock {
+	var block configBlock
+	if input != nil {
+		if rec := recommendInput(inputMatrix); rec.OK {
+			bufferMs := max(int(input.DefaultHighInputLatency.Seconds()*1000*3), 200)
+			inPipe := &inPipeBlock{
+				SampleRate:    asrSampleRate,
+				Channels:      1,
+				InputDevice:   input.Name,
+				ChannelSelect: -1,
+				BufferSize:    asrSampleRate * bufferMs / 1000,
+				HighLatency:   input.DefaultHighInputLatency.Seconds()*1000 > 50,
+			}
+			if rec.Channels > 1 {
+				inPipe.InputChannels = rec.Channels
+			}
+			block.Audio.InPipe = inPipe
+		}
+	}
+	if output != nil {
+		if rate, ok := recommendOutputRate(outputMatrix); ok {
+			block.Audio.Mixer = &mixerBlock{SampleRate: int(rate), Channels: mixerChannels, OutputDevice: output.Name}
+		}
+	}
+	return block
+}
+
+func (b configBlock) print(w io.Writer) error {
+	data, err := json.MarshalIndent(b, "", "    ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+// runPulseList 列出 PulseAudio / PipeWire 的 sink 和 source，用于 audio.backend 为 pulse 时的设备配置
+func runPulseList() {
+	fmt.Println("=== PulseAudio / PipeWire Devices ===")
+	fmt.Println()
+	sinks, err := audio.ListPulseDevices(true)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list sinks (is pactl installed and the sound server running?): %v\n", err)
+		os.Exit(1)
+	}
+	sources, err := audio.ListPulseDevices(false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list sources: %v\n", err)
+		os.Exit(1)
+	}
+	printPulseDevices(os.Stdout, "Sinks", sinks)
+	printPulseDevices(os.Stdout, "Sources", sources)
+	fmt.Println(`Use with "audio": {"backend": "pulse", "mixer": {"output_device": "<sink>"}, "in_pipe": {"input_device": "<source>"}}`)
+	fmt.Println("(names or descriptions, partial match)")
+}
+
+// printPulseDevices 输出设备名称、描述和默认标记
+func printPulseDevices(w io.Writer, title string, devices []audio.PulseDevice) {
+	fmt.Fprintf(w, "%s (%d):\n", title, len(devices))
+	for _, dev := range devices {
+		marker := ""
+		if dev.Default {
+			marker = " [DEFAULT]"
+		}
+		fmt.Fprintf(w, "  %s%s\n", dev.Name, marker)
+		if dev.Description != "" {
+			fmt.Fprintf(w, "      %s\n", dev.Description)
+		}
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+func TestPrintPulseDevices(t *testing.T) {
+	var buf bytes.Buffer
+	printPulseDevices(&buf, "Sinks", []audio.PulseDevice{
+		{Name: "alsa_output.pci-0000_00_1f.3.analog-stereo", Description: "Built-in Audio Analog Stereo"},
+		{Name: "bluez_output.AC_80_0A_11_22_33.1", Description: "WH-1000XM4", Default: true},
+	})
+	want := `Sinks (2):
+  alsa_output.pci-0000_00_1f.3.analog-stereo
+      Built-in Audio Analog Stereo
+  bluez_output.AC_80_0A_11_22_33.1 [DEFAULT]
+      WH-1000XM4
+
+`
+	if buf.String() != want {
+		t.Fatalf("output =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
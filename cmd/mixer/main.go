@@ -2,20 +2,19 @@ package main
 
 import (
 	"bytes"
-	"encoding/binary"
 	"flag"
 	"fmt"
 	"io"
 	"math"
-	"os"
 	"time"
 
 	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/audio/wav"
 )
 
 var (
-	ttsFile      = flag.String("tts", "", "TTS 音频文件路径（WAV 格式）")
-	resourceFile = flag.String("resource", "", "Resource 音频文件路径（WAV 格式）")
+	ttsFile      = flag.String("tts", "", "TTS 音频文件路径（WAV 格式，任意采样率 / 声道）")
+	resourceFile = flag.String("resource", "", "Resource 音频文件路径（WAV 格式，任意采样率 / 声道）")
 	duration     = flag.Float64("duration", 2.0, "生成音频的持续时间（秒）")
 	help         = flag.Bool("h", false, "显示帮助信息")
 )
@@ -31,7 +30,8 @@ func main() {
 	fmt.Println("=== AudioMixer 验证工具 ===")
 	fmt.Println()
 
-	mixer, err := audio.NewMixer(audio.DefaultMixerConfig())
+	mixerConfig := audio.DefaultMixerConfig()
+	mixer, err := audio.NewMixer(mixerConfig)
 	if err != nil {
 		fmt.Printf("创建 Mixer 失败: %v\n", err)
 		return
@@ -43,7 +43,7 @@ func main() {
 
 	if *ttsFile != "" {
 		fmt.Println("1. 读取 TTS 音频文件...")
-		ttsData, err := readWAVFile(*ttsFile)
+		ttsData, err := readWAVFile(*ttsFile, mixerConfig.SampleRate)
 		if err != nil {
 			fmt.Printf("读取 TTS 文件失败: %v\n", err)
 			return
@@ -52,14 +52,14 @@ func main() {
 		ttsSource = fmt.Sprintf("文件: %s", *ttsFile)
 	} else {
 		fmt.Println("1. 生成 TTS 测试音频...")
-		ttsSignal := generateSineWave(440, *duration)
+		ttsSignal := generateSineWave(440, *duration, mixerConfig.SampleRate)
 		ttsReader = &loopReader{data: ttsSignal}
 		ttsSource = fmt.Sprintf("440Hz 正弦波, %.1f秒", *duration)
 	}
 
 	if *resourceFile != "" {
 		fmt.Println("2. 读取 Resource 音频文件...")
-		resourceData, err := readWAVFile(*resourceFile)
+		resourceData, err := readWAVFile(*resourceFile, mixerConfig.SampleRate)
 		if err != nil {
 			fmt.Printf("读取 Resource 文件失败: %v\n", err)
 			return
@@ -68,7 +68,7 @@ func main() {
 		resourceSource = fmt.Sprintf("文件: %s", *resourceFile)
 	} else {
 		fmt.Println("2. 生成 Resource 测试音频...")
-		resourceSignal := generateSineWave(880, *duration)
+		resourceSignal := generateSineWave(880, *duration, mixerConfig.SampleRate)
 		resourceReader = &loopReader{data: resourceSignal}
 		resourceSource = fmt.Sprintf("880Hz 正弦波, %.1f秒", *duration)
 	}
@@ -133,81 +133,30 @@ func printHelp() {
 	fmt.Println("    - 生成 5 秒的测试音频")
 }
 
-type wavHeader struct {
-	ChunkID       [4]byte
-	FileSize      uint32
-	Format        [4]byte
-	Subchunk1ID   [4]byte
-	Subchunk1Size uint32
-	AudioFormat   uint16
-	NumChannels   uint16
-	SampleRate    uint32
-	ByteRate      uint32
-	BlockAlign    uint16
-	BitsPerSample uint16
-	Subchunk2ID   [4]byte
-	Subchunk2Size uint32
-}
-
-func readWAVFile(filename string) ([]byte, error) {
-	data, err := os.ReadFile(filename)
+// readWAVFile 读取 WAV 文件（任意采样率、声道与 16/24/32-bit、浮点格式），转换为 Mixer 播放的 16-bit 单声道 PCM
+func readWAVFile(filename string, sampleRate int) ([]byte, error) {
+	header, pcm, err := wav.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
-
-	if len(data) < 44 {
-		return nil, fmt.Errorf("文件太小，不是有效的 WAV 文件")
-	}
-
-	header := wavHeader{}
-	reader := bytes.NewReader(data)
-	binary.Read(reader, binary.LittleEndian, &header)
-
-	if string(header.ChunkID[:]) != "RIFF" || string(header.Format[:]) != "WAVE" {
-		return nil, fmt.Errorf("不是有效的 WAV 文件")
-	}
-
-	if string(header.Subchunk1ID[:]) != "fmt " {
-		return nil, fmt.Errorf("找不到 fmt chunk")
-	}
-
-	pcmData := data[44:]
-
-	if header.NumChannels == 1 && header.BitsPerSample == 16 {
-		pcmData = monoToStereo(pcmData)
-	}
-
-	return pcmData, nil
-}
-
-func monoToStereo(data []byte) []byte {
-	if len(data)%2 != 0 {
-		return data
-	}
-
-	stereoData := make([]byte, len(data)*2)
-	for i := 0; i < len(data); i += 2 {
-		stereoData[i*2] = data[i]
-		stereoData[i*2+1] = data[i+1]
-		stereoData[i*2+2] = data[i]
-		stereoData[i*2+3] = data[i+1]
+	format, err := audio.WAVSampleFormat(header)
+	if err != nil {
+		return nil, err
 	}
-	return stereoData
+	return io.ReadAll(audio.NewMixerStreamReader(bytes.NewReader(pcm), header.SampleRate, header.Channels, format, sampleRate))
 }
 
-func generateSineWave(freq, duration float64) []byte {
-	sampleRate := 16000
+// generateSineWave 生成 16-bit 单声道正弦波（Mixer 把单声道输入复制到各输出声道）
+func generateSineWave(freq, duration float64, sampleRate int) []byte {
 	samples := int(duration * float64(sampleRate))
-	data := make([]byte, samples*4)
+	data := make([]byte, samples*2)
 
 	for i := 0; i < samples; i++ {
 		t := float64(i) / float64(sampleRate)
 		sample := int16(32767 * math.Sin(2*math.Pi*freq*t))
 
-		data[i*4] = byte(sample)
-		data[i*4+1] = byte(sample >> 8)
-		data[i*4+2] = byte(sample)
-		data[i*4+3] = byte(sample >> 8)
+		data[i*2] = byte(sample)
+		data[i*2+1] = byte(sample >> 8)
 	}
 	return data
 }
@@ -0,0 +1,236 @@
+// speaker 声纹注册工具：录音或读取 WAV 文件注册说话人，供 voicebot 的 speaker 配置使用
+//
+//	go run ./cmd/speaker enroll -name alice -seconds 8
+//	go run ./cmd/speaker enroll -name alice -wav alice.wav
+//	go run ./cmd/speaker identify -seconds 5
+//	go run ./cmd/speaker list
+//	go run ./cmd/speaker remove -name alice
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gordonklaus/portaudio"
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/audio/source"
+	"github.com/liuscraft/orion-x/internal/config"
+	"github.com/liuscraft/orion-x/internal/speaker"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "enroll":
+		err = runEnroll(os.Args[2:])
+	case "identify":
+		err = runIdentify(os.Args[2:])
+	case "list":
+		err = runList(os.Args[2:])
+	case "remove":
+		err = runRemove(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: speaker <enroll|identify|list|remove> [flags]")
+	fmt.Fprintln(os.Stderr, "  enroll   -name NAME (-wav FILE | -seconds N)  register a voiceprint (repeat to refine)")
+	fmt.Fprintln(os.Stderr, "  identify (-wav FILE | -seconds N)            match a recording against enrolled speakers")
+	fmt.Fprintln(os.Stderr, "  list                                         list enrolled speakers")
+	fmt.Fprintln(os.Stderr, "  remove   -name NAME                          delete a speaker")
+}
+
+// commonFlags 各子命令共用的参数：配置文件（读取 speaker.voiceprints 与 speaker.threshold）
+type commonFlags struct {
+	configPath string
+}
+
+func newFlagSet(name string) (*flag.FlagSet, *commonFlags) {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	common := &commonFlags{}
+	fs.StringVar(&common.configPath, "config", config.DefaultPath, "config file path")
+	return fs, common
+}
+
+func (c *commonFlags) load() (*config.SpeakerConfig, *speaker.Store, error) {
+	appConfig, err := config.Load(c.configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	store, err := speaker.LoadStore(appConfig.Speaker.Voiceprints)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &appConfig.Speaker, store, nil
+}
+
+func runEnroll(args []string) error {
+	fs, common := newFlagSet("enroll")
+	name := fs.String("name", "", "speaker name")
+	wavPath := fs.String("wav", "", "16-bit PCM WAV file (records from microphone when empty)")
+	seconds := fs.Int("seconds", 8, "recording length in seconds")
+	fs.Parse(args)
+	if *name == "" {
+		return errors.New("-name is required")
+	}
+
+	cfg, store, err := common.load()
+	if err != nil {
+		return err
+	}
+	pcm, sampleRate, err := readAudio(*wavPath, *seconds)
+	if err != nil {
+		return err
+	}
+	if err := store.EnrollPCM(*name, pcm, sampleRate); err != nil {
+		return err
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Enrolled %s in %s\n", *name, cfg.Voiceprints)
+	return nil
+}
+
+func runIdentify(args []string) error {
+	fs, common := newFlagSet("identify")
+	wavPath := fs.String("wav", "", "16-bit PCM WAV file (records from microphone when empty)")
+	seconds := fs.Int("seconds", 5, "recording length in seconds")
+	fs.Parse(args)
+
+	cfg, store, err := common.load()
+	if err != nil {
+		return err
+	}
+	pcm, sampleRate, err := readAudio(*wavPath, *seconds)
+	if err != nil {
+		return err
+	}
+	match, err := speaker.NewIdentifier(store, cfg.Threshold).IdentifyPCM(pcm, sampleRate)
+	if err != nil {
+		return err
+	}
+	if match.Known {
+		fmt.Printf("Speaker: %s (score=%.3f)\n", match.Speaker, match.Score)
+	} else {
+		fmt.Printf("Unknown speaker (closest=%q, score=%.3f, threshold=%.2f)\n", match.Speaker, match.Score, cfg.Threshold)
+	}
+	return nil
+}
+
+func runList(args []string) error {
+	fs, common := newFlagSet("list")
+	fs.Parse(args)
+
+	cfg, store, err := common.load()
+	if err != nil {
+		return err
+	}
+	speakers := store.List()
+	if len(speakers) == 0 {
+		fmt.Printf("No speakers enrolled in %s\n", cfg.Voiceprints)
+		return nil
+	}
+	for _, vp := range speakers {
+		fmt.Printf("%-16s samples=%d updated=%s\n", vp.Name, vp.Samples, vp.UpdatedAt.Format(time.DateTime))
+	}
+	return nil
+}
+
+func runRemove(args []string) error {
+	fs, common := newFlagSet("remove")
+	name := fs.String("name", "", "speaker name")
+	fs.Parse(args)
+
+	_, store, err := common.load()
+	if err != nil {
+		return err
+	}
+	if !store.Remove(*name) {
+		return fmt.Errorf("speaker %q not enrolled", *name)
+	}
+	if err := store.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Removed %s\n", *name)
+	return nil
+}
+
+// readAudio 读取 WAV 文件，或从默认麦克风录音，返回单声道 16-bit PCM 与采样率
+func readAudio(wavPath string, seconds int) ([]byte, int, error) {
+	if wavPath != "" {
+		data, err := os.ReadFile(wavPath)
+		if err != nil {
+			return nil, 0, err
+		}
+		wav, err := audio.DecodeWAV(data)
+		if err != nil {
+			return nil, 0, err
+		}
+		return monoPCM(wav.Samples, wav.Channels), wav.SampleRate, nil
+	}
+	return record(time.Duration(seconds) * time.Second)
+}
+
+func record(duration time.Duration) ([]byte, int, error) {
+	if err := portaudio.Initialize(); err != nil {
+		return nil, 0, err
+	}
+	defer portaudio.Terminate()
+
+	mic, err := source.NewMicrophoneSource(16000, 1, 1600)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer mic.Close()
+	mono := audio.NewChannelMapSource(mic, mic.Channels(), -1)
+
+	fmt.Printf("Recording for %s, please speak naturally...\n", duration)
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	var pcm []byte
+	for {
+		chunk, err := mono.Read(ctx)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				break
+			}
+			return nil, 0, err
+		}
+		pcm = append(pcm, chunk...)
+	}
+	fmt.Println("Recording finished")
+	return pcm, mic.SampleRate(), nil
+}
+
+// monoPCM 把交错的多声道采样平均下混为单声道 PCM 字节
+func monoPCM(samples []int16, channels int) []byte {
+	channels = max(channels, 1)
+	pcm := make([]byte, len(samples)/channels*2)
+	for i := 0; i < len(samples)/channels; i++ {
+		var sum int
+		for c := 0; c < channels; c++ {
+			sum += int(samples[i*channels+c])
+		}
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(int16(sum/channels)))
+	}
+	return pcm
+}
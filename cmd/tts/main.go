@@ -1,17 +1,20 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"strings"
-	"sync"
 	"time"
 
+	"github.com/liuscraft/orion-x/internal/audio/wav"
 	"github.com/liuscraft/orion-x/internal/logging"
 	"github.com/liuscraft/orion-x/internal/text"
 	"github.com/liuscraft/orion-x/internal/tts"
@@ -33,6 +36,9 @@ func main() {
 	endpoint := flag.String("endpoint", "", "WebSocket endpoint (optional)")
 	workspace := flag.String("workspace", "", "DashScope workspace ID (optional)")
 	output := flag.String("output", "", "Write audio to file instead of playing")
+	outputWAV := flag.String("output-wav", "", "Write audio to a WAV file (requests PCM and adds a header with the stream's sample rate)")
+	batch := flag.String("batch", "", "Batch file: one text per line, or JSONL with text/voice/name fields; writes one audio file per entry")
+	outDir := flag.String("out-dir", "tts-out", "Output directory for -batch")
 	player := flag.String("player", "ffplay", "Player executable for streaming playback")
 	dataInspection := flag.Bool("data-inspection", true, "Enable X-DashScope-DataInspection header")
 	flag.Parse()
@@ -67,59 +73,231 @@ func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
 	defer stop()
 
+	opts := synthOptions{chunkSize: *chunkSize, chunkDelay: *chunkDelay}
+	if *segmenter {
+		opts.segmenterMax = *segmenterMax
+	}
+
+	if *batch != "" {
+		if err := runBatch(ctx, provider, cfg, *batch, *outDir); err != nil {
+			logging.Fatalf("batch synthesis failed: %v", err)
+		}
+		return
+	}
+
+	sink := func(stream tts.Stream) error {
+		return playAudio(ctx, stream.AudioReader(), *output, *player)
+	}
+	if *outputWAV != "" {
+		if cfg.Format != "pcm" {
+			logging.Infof("-output-wav requests pcm audio (ignoring -format %s)", cfg.Format)
+			cfg.Format = "pcm"
+		}
+		sink = func(stream tts.Stream) error {
+			return writeWAVFile(*outputWAV, stream)
+		}
+	}
+	if err := synthesize(ctx, provider, cfg, *inputText, opts, sink); err != nil {
+		logging.Errorf("synthesis failed: %v", err)
+	}
+}
+
+type synthOptions struct {
+	chunkSize    int
+	chunkDelay   time.Duration
+	segmenterMax int // 0 表示不分句
+}
+
+// synthesize 用一个 TTS 流合成整段文本，模拟 LLM 按块送入；sink 在后台消费音频
+func synthesize(ctx context.Context, provider tts.Provider, cfg tts.Config, input string, opts synthOptions, sink func(tts.Stream) error) error {
 	stream, err := provider.Start(ctx, cfg)
 	if err != nil {
-		logging.Fatalf("start tts stream failed: %v", err)
+		return fmt.Errorf("start tts stream: %w", err)
 	}
 
-	var wg sync.WaitGroup
-	playErrCh := make(chan error, 1)
-	wg.Add(1)
+	sinkErrCh := make(chan error, 1)
 	go func() {
-		defer wg.Done()
-		playErrCh <- playAudio(ctx, stream.AudioReader(), *output, *player)
+		sinkErrCh <- sink(stream)
 	}()
 
+	writeErr := writeText(ctx, stream, input, opts)
+
+	finishCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	if err := stream.Close(finishCtx); err != nil {
+		logging.Errorf("finish task failed: %v", err)
+	}
+
+	sinkErr := <-sinkErrCh
+	if writeErr != nil {
+		return fmt.Errorf("send text chunk: %w", writeErr)
+	}
+	if sinkErr != nil {
+		return fmt.Errorf("write audio: %w", sinkErr)
+	}
+	return nil
+}
+
+func writeText(ctx context.Context, stream tts.Stream, input string, opts synthOptions) error {
 	var seg *text.Segmenter
-	if *segmenter {
-		seg = text.NewSegmenter(*segmenterMax)
+	if opts.segmenterMax > 0 {
+		seg = text.NewSegmenter(opts.segmenterMax)
 	}
 
-	for _, chunk := range chunkText(*inputText, *chunkSize) {
+	for _, chunk := range chunkText(input, opts.chunkSize) {
 		if seg == nil {
 			if err := stream.WriteTextChunk(ctx, chunk); err != nil {
-				logging.Fatalf("send text chunk failed: %v", err)
+				return err
 			}
 		} else {
 			for _, sentence := range seg.Feed(chunk) {
 				if err := stream.WriteTextChunk(ctx, sentence); err != nil {
-					logging.Fatalf("send text chunk failed: %v", err)
+					return err
 				}
 			}
 		}
-		if *chunkDelay > 0 {
-			time.Sleep(*chunkDelay)
+		if opts.chunkDelay > 0 {
+			time.Sleep(opts.chunkDelay)
 		}
 	}
 
 	if seg != nil {
 		if sentence := seg.Flush(); sentence != "" {
-			if err := stream.WriteTextChunk(ctx, sentence); err != nil {
-				logging.Fatalf("send text chunk failed: %v", err)
+			return stream.WriteTextChunk(ctx, sentence)
+		}
+	}
+	return nil
+}
+
+// batchEntry 批量合成的一条输入
+type batchEntry struct {
+	Text  string `json:"text"`
+	Voice string `json:"voice,omitempty"`
+	Name  string `json:"name,omitempty"` // 输出文件名（不含扩展名），为空时按序号命名
+}
+
+// parseBatch 解析批量输入：每行一条文本，或每行一个 JSON 对象；空行和 # 开头的行忽略
+func parseBatch(r io.Reader) ([]batchEntry, error) {
+	var entries []batchEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		entry := batchEntry{Text: line}
+		if strings.HasPrefix(line, "{") {
+			entry = batchEntry{}
+			if err := json.Unmarshal([]byte(line), &entry); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			entry.Text = strings.TrimSpace(entry.Text)
+			if entry.Text == "" {
+				return nil, fmt.Errorf("line %d: text is required", lineNo)
 			}
 		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
 	}
+	return entries, nil
+}
 
-	finishCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
-	defer cancel()
-	if err := stream.Close(finishCtx); err != nil {
-		logging.Errorf("finish task failed: %v", err)
+// batchFileName 返回第 index 条（从 0 开始）的输出文件名
+func batchFileName(entry batchEntry, index int, ext string) string {
+	name := strings.TrimSpace(entry.Name)
+	if name == "" {
+		name = fmt.Sprintf("%03d", index+1)
 	}
+	return filepath.Base(name) + ext
+}
 
-	wg.Wait()
-	if err := <-playErrCh; err != nil {
-		logging.Errorf("playback error: %v", err)
+// runBatch 逐条合成批量文件中的文本，每条写出一个音频文件；pcm / wav 格式写为带头的 WAV
+func runBatch(ctx context.Context, provider tts.Provider, cfg tts.Config, batchPath, outDir string) error {
+	file, err := os.Open(batchPath)
+	if err != nil {
+		return err
+	}
+	entries, err := parseBatch(file)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", batchPath, err)
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("%s contains no text", batchPath)
+	}
+	if err := os.MkdirAll(outDir, 0o755); err != nil {
+		return err
+	}
+
+	wav := cfg.Format == "pcm" || cfg.Format == "wav"
+	ext := "." + cfg.Format
+	if wav {
+		cfg.Format = "pcm"
+		ext = ".wav"
+	}
+
+	// 批量模式不模拟流式送入：整段文本分句后直接送出
+	opts := synthOptions{segmenterMax: 120}
+	failed := 0
+	for i, entry := range entries {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		entryCfg := cfg
+		if entry.Voice != "" {
+			entryCfg.Voice = entry.Voice
+		}
+		path := filepath.Join(outDir, batchFileName(entry, i, ext))
+		sink := func(stream tts.Stream) error {
+			if wav {
+				return writeWAVFile(path, stream)
+			}
+			return playAudio(ctx, stream.AudioReader(), path, "")
+		}
+		if err := synthesize(ctx, provider, entryCfg, entry.Text, opts, sink); err != nil {
+			failed++
+			logging.Errorf("[%d/%d] %s failed: %v", i+1, len(entries), path, err)
+			continue
+		}
+		logging.Infof("[%d/%d] %s", i+1, len(entries), path)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d entries failed", failed, len(entries))
+	}
+	return nil
+}
+
+// writeWAVFile 把 PCM 音频流写为 WAV 文件，头部使用流的采样率与声道数
+func writeWAVFile(path string, stream tts.Stream) error {
+	reader := stream.AudioReader()
+	defer reader.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	channels := stream.Channels()
+	if channels <= 0 {
+		channels = 1
+	}
+	writer, err := wav.NewWriter(file, wav.PCM16(stream.SampleRate(), channels))
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(writer, reader); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
 	}
+	return file.Close()
 }
 
 func chunkText(text string, size int) []string {
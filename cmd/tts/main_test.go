@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []batchEntry
+		wantErr bool
+	}{
+		{
+			name:  "plain lines",
+			input: "你好。\n\n# 注释\n  欢迎使用。  \n",
+			want:  []batchEntry{{Text: "你好。"}, {Text: "欢迎使用。"}},
+		},
+		{
+			name:  "jsonl",
+			input: `{"text":"早上好","voice":"longxiaochun","name":"morning"}` + "\n" + `{"text":"晚安"}`,
+			want: []batchEntry{
+				{Text: "早上好", Voice: "longxiaochun", Name: "morning"},
+				{Text: "晚安"},
+			},
+		},
+		{
+			name:    "jsonl missing text",
+			input:   `{"voice":"longxiaochun"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			input:   `{"text":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBatch(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBatch() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchFileName(t *testing.T) {
+	if got := batchFileName(batchEntry{Text: "x"}, 4, ".wav"); got != "005.wav" {
+		t.Errorf("batchFileName() = %q, want 005.wav", got)
+	}
+	if got := batchFileName(batchEntry{Text: "x", Name: "../greeting"}, 0, ".mp3"); got != "greeting.mp3" {
+		t.Errorf("batchFileName() = %q, want greeting.mp3", got)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// printAuditRecords 输出审计文件中指定会话的工具执行记录，session 为 all 时输出全部会话
+func printAuditRecords(w io.Writer, path, session string) error {
+	if session == "all" {
+		session = ""
+	}
+	records, err := tools.ReadAuditLog(path, session)
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		fmt.Fprintf(w, "no tool audit records for session %q in %s\n", session, path)
+		return nil
+	}
+	for _, record := range records {
+		args, _ := json.Marshal(record.Args)
+		outcome := "-> " + record.Result
+		if record.Error != "" {
+			outcome = "error: " + record.Error
+		}
+		fmt.Fprintf(w, "%s %s turn=%d %s %s %dms %s\n", record.Time.Format("2006-01-02 15:04:05"),
+			record.Session, record.Turn, record.Tool, args, record.DurationMs, outcome)
+	}
+	return nil
+}
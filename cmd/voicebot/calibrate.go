@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/config"
+)
+
+const (
+	calibrateNoiseDuration  = 3 * time.Second
+	calibrateSpeechDuration = 5 * time.Second
+	calibrateFrameMs        = 100 // 与 VAD 判定粒度相近的电平统计窗口
+
+	// 语音阶段电平高于底噪该倍数的帧视为有效语音
+	calibrateSpeechFactor = 2.0
+	// 有效语音帧少于该比例时认为没有录到说话
+	calibrateMinSpeechRatio = 0.2
+	// AGC 目标电平范围；峰值因子决定不触发软限幅的上限
+	calibrateMinAGCTarget = 0.05
+	calibrateMaxAGCTarget = 0.3
+	calibrateLimiterKnee  = 0.9
+)
+
+// calibrationLevels 一个采样阶段的电平统计
+type calibrationLevels struct {
+	FrameRMS []float64 // 每 calibrateFrameMs 的 RMS（归一化 0~1）
+	Peak     float64   // 最大采样绝对值
+}
+
+// calibrationResult 校准结果
+type calibrationResult struct {
+	NoiseRMS     float64 // 底噪电平（95 分位）
+	SpeechRMS    float64 // 有效语音电平（中位数）
+	VADThreshold float64 // 推荐 audio.in_pipe.vad_threshold（AGC 开启时为增益后的电平）
+	AGCTarget    float64 // 推荐 audio.in_pipe.dsp.agc.target_rms
+}
+
+// computeCalibration 由底噪和语音两段电平计算推荐的 VAD 阈值与 AGC 目标电平。
+// VAD 在 DSP 之后判定，agcEnabled 时按 AGC 稳定后的增益折算阈值。
+func computeCalibration(noise, speech calibrationLevels, agcEnabled bool, maxGain float64) (calibrationResult, error) {
+	if len(noise.FrameRMS) == 0 || len(speech.FrameRMS) == 0 {
+		return calibrationResult{}, errors.New("no audio captured")
+	}
+	noiseRMS := percentile(noise.FrameRMS, 0.95)
+	// 底噪为数字静音时给一个下限，避免阈值为 0
+	noiseFloor := math.Max(noiseRMS, 1e-4)
+
+	var active []float64
+	for _, rms := range speech.FrameRMS {
+		if rms >= noiseFloor*calibrateSpeechFactor {
+			active = append(active, rms)
+		}
+	}
+	if float64(len(active)) < float64(len(speech.FrameRMS))*calibrateMinSpeechRatio {
+		return calibrationResult{}, fmt.Errorf("speech is not clearly louder than the room noise (noise RMS %.4f)", noiseRMS)
+	}
+	speechRMS := percentile(active, 0.5)
+
+	// AGC 目标：语音峰值放大后仍低于软限幅拐点
+	target := calibrateMaxAGCTarget
+	if speech.Peak > 0 {
+		target = calibrateLimiterKnee * speechRMS / speech.Peak
+	}
+	target = math.Min(math.Max(target, calibrateMinAGCTarget), calibrateMaxAGCTarget)
+
+	// 阈值取底噪与语音电平的几何平均，两侧余量按比例相同
+	noiseLevel, speechLevel := noiseFloor, speechRMS
+	if agcEnabled {
+		gain := target / speechRMS
+		if maxGain > 0 {
+			gain = math.Min(gain, maxGain)
+		}
+		noiseLevel *= gain
+		speechLevel *= gain
+	}
+	return calibrationResult{
+		NoiseRMS:     noiseRMS,
+		SpeechRMS:    speechRMS,
+		VADThreshold: roundTo(math.Sqrt(noiseLevel*speechLevel), 4),
+		AGCTarget:    roundTo(target, 3),
+	}, nil
+}
+
+// measureLevels 从 source 读取 duration 时长的 16-bit PCM，按 frameSamples 统计 RMS 与峰值
+func measureLevels(ctx context.Context, src audio.AudioSource, frameSamples int, duration time.Duration) (calibrationLevels, error) {
+	ctx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	var levels calibrationLevels
+	var sum float64
+	var count int
+	for {
+		data, err := src.Read(ctx)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return levels, nil
+			}
+			return levels, err
+		}
+		for i := 0; i+1 < len(data); i += 2 {
+			v := float64(int16(data[i])|int16(data[i+1])<<8) / 32768.0
+			sum += v * v
+			count++
+			if a := math.Abs(v); a > levels.Peak {
+				levels.Peak = a
+			}
+			if count == frameSamples {
+				levels.FrameRMS = append(levels.FrameRMS, math.Sqrt(sum/float64(count)))
+				sum, count = 0, 0
+			}
+		}
+	}
+}
+
+// runCalibration 交互式校准：依次录制环境噪声和说话声，输出推荐值并询问是否写回配置文件
+func runCalibration(ctx context.Context, appConfig *config.AppConfig, configPath string, in io.Reader, out io.Writer) error {
+	src, rate, err := openCalibrationSource(appConfig)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	reader := bufio.NewReader(in)
+	frameSamples := rate * calibrateFrameMs / 1000
+
+	fmt.Fprintln(out, "=== VAD Calibration ===")
+	fmt.Fprintf(out, "Step 1: stay quiet for %v so the room noise can be measured. Press Enter to start...", calibrateNoiseDuration)
+	reader.ReadString('\n')
+	noise, err := measureLevels(ctx, src, frameSamples, calibrateNoiseDuration)
+	if err != nil {
+		return fmt.Errorf("record room noise: %w", err)
+	}
+	fmt.Fprintf(out, "Step 2: speak normally for %v from where you usually talk to the bot. Press Enter to start...", calibrateSpeechDuration)
+	reader.ReadString('\n')
+	speech, err := measureLevels(ctx, src, frameSamples, calibrateSpeechDuration)
+	if err != nil {
+		return fmt.Errorf("record speech: %w", err)
+	}
+
+	agcCfg := appConfig.Audio.InPipe.DSP.AGC
+	maxGain := agcCfg.MaxGain
+	if maxGain <= 0 {
+		maxGain = audio.DefaultAGCConfig().MaxGain
+	}
+	result, err := computeCalibration(noise, speech, agcCfg.Enable, maxGain)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintf(out, "Room noise RMS:   %.4f\n", result.NoiseRMS)
+	fmt.Fprintf(out, "Speech RMS:       %.4f (peak %.3f)\n", result.SpeechRMS, speech.Peak)
+	fmt.Fprintf(out, "vad_threshold:    %.4f (current %.4f)\n", result.VADThreshold, appConfig.Audio.InPipe.VADThreshold)
+	fmt.Fprintf(out, "agc.target_rms:   %.3f (current %.3f)\n", result.AGCTarget, agcCfg.TargetRMS)
+	if agcCfg.Enable {
+		fmt.Fprintln(out, "AGC is enabled: the threshold is scaled to the level after automatic gain.")
+	}
+	fmt.Fprintln(out)
+
+	// 启用了配置档时写入最后一个配置档，不同设备（有线、蓝牙）各自保留校准结果
+	prefix, target := "", configPath
+	if n := len(appConfig.ActiveProfiles); n > 0 {
+		prefix = "profiles." + appConfig.ActiveProfiles[n-1] + "."
+		target = fmt.Sprintf("profile %q in %s", appConfig.ActiveProfiles[n-1], configPath)
+	}
+	fmt.Fprintf(out, "Write these values to %s? [y/N] ", target)
+	answer, _ := reader.ReadString('\n')
+	if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+		fmt.Fprintln(out, "Config not changed.")
+		return nil
+	}
+	if err := config.UpdateFile(configPath, map[string]interface{}{
+		prefix + "audio.in_pipe.vad_threshold":      result.VADThreshold,
+		prefix + "audio.in_pipe.dsp.agc.target_rms": result.AGCTarget,
+	}); err != nil {
+		return fmt.Errorf("update config: %w", err)
+	}
+	fmt.Fprintf(out, "Updated %s.\n", target)
+	return nil
+}
+
+// openCalibrationSource 按 voicebot 的输入链路打开麦克风（声道映射、重采样、高通），不含降噪与 AGC，测得原始电平
+func openCalibrationSource(appConfig *config.AppConfig) (audio.AudioSource, int, error) {
+	inCfg := appConfig.Audio.InPipe
+	bufferSize := inCfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = 3200
+	}
+	inputChannels := inCfg.InputChannels
+	if inputChannels <= 0 {
+		inputChannels = inCfg.Channels
+	}
+
+	mic, err := openMicrophone(appConfig, inCfg.InputDevice, inCfg.SampleRate, inputChannels, bufferSize)
+	if err != nil {
+		return nil, 0, fmt.Errorf("create microphone source: %w", err)
+	}
+	var src audio.AudioSource = mic
+	if mic.Channels() > 1 {
+		src = audio.NewChannelMapSource(src, mic.Channels(), inCfg.ChannelSelect)
+	}
+	if mic.SampleRate() != inCfg.SampleRate {
+		src = audio.NewResamplingSource(src, mic.SampleRate(), inCfg.SampleRate, 1, nil)
+	}
+	dspCfg := buildInputDSPConfig(inCfg.DSP)
+	dspCfg.NoiseSuppression.Enabled = false
+	dspCfg.AGC.Enabled = false
+	if dspCfg.Enabled() {
+		src = audio.NewInputDSPSource(src, dspCfg, inCfg.SampleRate, 1)
+	}
+	return src, inCfg.SampleRate, nil
+}
+
+// percentile 返回 values 的 p 分位数（0~1），不修改 values
+func percentile(values []float64, p float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	idx := int(math.Round(p * float64(len(sorted)-1)))
+	return sorted[idx]
+}
+
+func roundTo(v float64, digits int) float64 {
+	scale := math.Pow(10, float64(digits))
+	return math.Round(v*scale) / scale
+}
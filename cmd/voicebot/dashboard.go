@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/voicebot"
+)
+
+const (
+	dashboardRefresh  = 200 * time.Millisecond
+	dashboardLogLines = 10
+	dashboardMeterLen = 24
+	dashboardMinDBFS  = -60.0
+)
+
+// dashboardSource 仪表盘读取的编排器状态（voicebot.Orchestrator）
+type dashboardSource interface {
+	GetState() voicebot.State
+	Stats() voicebot.UsageStats
+}
+
+// dashboard --tui 的终端仪表盘：定时重绘状态机状态、识别中间结果、TTS 队列、电平和最近的日志，
+// 只使用 ANSI 转义序列（备用屏幕），不依赖终端 UI 库
+type dashboard struct {
+	out      io.Writer
+	source   dashboardSource
+	pipeline func() audio.PipelineStats
+	width    int
+	// logOut 仪表盘显示之前（启动阶段）和退出之后日志直接写到这里，避免启动失败时看不到错误
+	logOut io.Writer
+
+	mu      sync.Mutex
+	showing bool
+	user    string // 最近一句 final
+	partial string // 进行中的中间结果
+	reply   strings.Builder
+	replied bool // 当前回复已结束，下一段文本开始新回复
+	logs    []string
+	pending string // 日志中尚未换行的部分
+}
+
+func newDashboard(out, logOut io.Writer) *dashboard {
+	width := 80
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols >= 40 {
+		width = cols
+	}
+	return &dashboard{out: out, logOut: logOut, width: width}
+}
+
+// Write 接收日志输出（logging.Config.Output），只保留最近的若干行
+func (d *dashboard) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.showing && d.logOut != nil {
+		if _, err := d.logOut.Write(p); err != nil {
+			return 0, err
+		}
+	}
+	lines := strings.Split(d.pending+string(p), "\n")
+	d.pending = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		if line = strings.TrimSpace(strings.ReplaceAll(line, "\t", " ")); line != "" {
+			d.logs = append(d.logs, line)
+		}
+	}
+	if extra := len(d.logs) - dashboardLogLines; extra > 0 {
+		d.logs = append(d.logs[:0], d.logs[extra:]...)
+	}
+	return len(p), nil
+}
+
+// OnEvent 记录识别结果，订阅 EventTypePartialTranscript 和 EventTypeASRFinal
+func (d *dashboard) OnEvent(event voicebot.Event) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch e := event.(type) {
+	case *voicebot.PartialTranscriptEvent:
+		d.partial = e.Text
+	case *voicebot.ASRFinalEvent:
+		d.user = e.Text
+		d.partial = ""
+	}
+}
+
+// OnReplyText 记录流式回复，作为 OrchestratorConfig.OnReplyText
+func (d *dashboard) OnReplyText(chunk string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.replied {
+		d.reply.Reset()
+		d.replied = false
+	}
+	d.reply.WriteString(chunk)
+}
+
+// OnReplyFinished 结束当前回复，作为 OrchestratorConfig.OnReplyFinished
+func (d *dashboard) OnReplyFinished() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.replied = true
+}
+
+// Run 切换到备用屏幕并定时重绘，ctx 结束时恢复终端；source 和 pipeline 需在调用前设置
+func (d *dashboard) Run(ctx context.Context) {
+	d.setShowing(true)
+	fmt.Fprint(d.out, "\x1b[?1049h\x1b[?25l")
+	defer func() {
+		fmt.Fprint(d.out, "\x1b[?25h\x1b[?1049l")
+		d.setShowing(false)
+	}()
+
+	ticker := time.NewTicker(dashboardRefresh)
+	defer ticker.Stop()
+	for {
+		fmt.Fprint(d.out, "\x1b[H\x1b[2J"+d.render())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *dashboard) setShowing(showing bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.showing = showing
+}
+
+// render 渲染一帧
+func (d *dashboard) render() string {
+	state := d.source.GetState()
+	stats := d.source.Stats()
+	var pipeline audio.PipelineStats
+	if d.pipeline != nil {
+		pipeline = d.pipeline()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var b strings.Builder
+	rule := strings.Repeat("─", d.width)
+	line := func(format string, args ...interface{}) {
+		b.WriteString(d.clip(fmt.Sprintf(format, args...)))
+		b.WriteString("\n")
+	}
+
+	mic := "on"
+	if stats.MicMuted {
+		mic = "MUTED"
+	}
+	line("orion-x voicebot    state: %-10s mic: %s", state, mic)
+	b.WriteString(rule + "\n")
+	line("Mic  %s", levelMeter(stats.InputLevel.Current))
+	line("Out  %s", levelMeter(stats.OutputLevel.Current))
+	playing := "idle"
+	if pipeline.IsPlaying {
+		playing = "playing"
+	}
+	line("TTS  queue %d text / %d audio  %s  played %d  interrupts %d  underruns %d",
+		pipeline.TextQueueSize, pipeline.TTSBufferSize, playing, pipeline.TotalPlayed, pipeline.TotalInterrupts, pipeline.Underruns)
+	line("LLM  turns %d  tokens %d+%d  first token avg %v",
+		stats.Turns, stats.PromptTokens, stats.CompletionTokens, stats.AvgFirstTokenLatency.Round(time.Millisecond))
+	b.WriteString(rule + "\n")
+	line("you: %s", d.user)
+	if d.partial != "" {
+		line("     … %s", d.partial)
+	}
+	line("bot: %s", strings.ReplaceAll(d.reply.String(), "\n", " "))
+	b.WriteString(rule + "\n")
+	for _, l := range d.logs {
+		line("%s", l)
+	}
+	b.WriteString(rule + "\n")
+	line("m + Enter: mute / unmute mic    Ctrl+C: quit")
+	return b.String()
+}
+
+// clip 按终端宽度截断一行（按字符数，全角字符可能略超出）
+func (d *dashboard) clip(s string) string {
+	runes := []rune(s)
+	if len(runes) <= d.width {
+		return s
+	}
+	return string(runes[:d.width-1]) + "…"
+}
+
+// levelMeter 把 -60~0 dBFS 映射为电平条
+func levelMeter(level audio.AudioLevel) string {
+	db := level.DBFS()
+	if math.IsInf(db, -1) || math.IsNaN(db) || db < dashboardMinDBFS {
+		db = dashboardMinDBFS
+	}
+	filled := int(math.Round((db - dashboardMinDBFS) / -dashboardMinDBFS * dashboardMeterLen))
+	filled = min(max(filled, 0), dashboardMeterLen)
+	bar := strings.Repeat("█", filled) + strings.Repeat("·", dashboardMeterLen-filled)
+	if db <= dashboardMinDBFS {
+		return fmt.Sprintf("[%s]   silent  peak %.2f", bar, level.Peak)
+	}
+	return fmt.Sprintf("[%s] %4.0f dBFS peak %.2f", bar, db, level.Peak)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// micSwitch 麦克风开关（voicebot.Orchestrator）
+type micSwitch interface {
+	SetMicMuted(muted bool) bool
+	MicMuted() bool
+}
+
+// runHotkeys 语音模式下逐行读取终端快捷键（输入后回车）：m 开关麦克风（隐私模式），遇到 EOF 时返回
+func runHotkeys(in io.Reader, out io.Writer, mic micSwitch) error {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+		case "m":
+			muted := !mic.MicMuted()
+			mic.SetMicMuted(muted)
+			if muted {
+				fmt.Fprintln(out, "Microphone muted (privacy mode), press m + Enter to unmute.")
+			} else {
+				fmt.Fprintln(out, "Microphone unmuted.")
+			}
+		case "":
+		default:
+			fmt.Fprintln(out, "Hotkeys: m + Enter toggles the microphone.")
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/voicebot"
+)
+
+// lineIndicator 把语音活动逐行写入 w（voicebot.ActivityIndicator），写入失败后不再写入
+type lineIndicator struct {
+	mu sync.Mutex
+	w  io.WriteCloser // 写入失败或关闭后为 nil
+}
+
+func (l *lineIndicator) OnActivity(activity voicebot.Activity) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.w == nil {
+		return
+	}
+	if _, err := fmt.Fprintln(l.w, activity); err != nil {
+		logging.Warnf("Indicator: write %s: %v, indicator disabled", activity, err)
+		l.w.Close()
+		l.w = nil
+	}
+}
+
+func (l *lineIndicator) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.w == nil {
+		return nil
+	}
+	err := l.w.Close()
+	l.w = nil
+	return err
+}
+
+// commandIndicator 启动外部程序（如驱动 LED 灯环的脚本），语音活动写入其标准输入
+type commandIndicator struct {
+	lineIndicator
+	cmd *exec.Cmd
+}
+
+// startCommandIndicator 启动 indicator.command，程序输出转到 stderr
+func startCommandIndicator(command []string) (*commandIndicator, error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("start indicator %s: %w", command[0], err)
+	}
+	return &commandIndicator{lineIndicator: lineIndicator{w: stdin}, cmd: cmd}, nil
+}
+
+// Close 关闭标准输入（程序读到 EOF 后应熄灯退出）并等待程序结束
+func (c *commandIndicator) Close() error {
+	c.lineIndicator.Close()
+	return c.cmd.Wait()
+}
@@ -2,46 +2,145 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
-	"github.com/gordonklaus/portaudio"
 	"github.com/liuscraft/orion-x/internal/agent"
 	"github.com/liuscraft/orion-x/internal/audio"
 	"github.com/liuscraft/orion-x/internal/audio/source"
+	"github.com/liuscraft/orion-x/internal/calendar"
 	"github.com/liuscraft/orion-x/internal/config"
+	"github.com/liuscraft/orion-x/internal/knowledge"
 	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/mqtt"
+	"github.com/liuscraft/orion-x/internal/news"
+	"github.com/liuscraft/orion-x/internal/realtime"
+	"github.com/liuscraft/orion-x/internal/routine"
+	"github.com/liuscraft/orion-x/internal/speaker"
+	"github.com/liuscraft/orion-x/internal/telephony"
+	"github.com/liuscraft/orion-x/internal/text"
 	"github.com/liuscraft/orion-x/internal/tools"
 	"github.com/liuscraft/orion-x/internal/tts"
 	"github.com/liuscraft/orion-x/internal/voicebot"
+	"github.com/liuscraft/orion-x/internal/webui"
 )
 
 func main() {
 	configPath := flag.String("config", config.DefaultPath, "config file path")
+	profile := flag.String("profile", "", "config profiles to overlay, comma separated (overrides VOICEBOT_PROFILE and the profile field)")
+	mode := flag.String("mode", "", "conversation mode: assistant or translate (overrides conversation.mode)")
+	target := flag.String("target", "", "target language in translate mode, e.g. en (overrides translation.target)")
+	sourceLang := flag.String("source", "", "other party's language for two-way translation (overrides translation.source)")
+	textMode := flag.Bool("text-mode", false, "read turns from stdin instead of the microphone and print streamed replies")
+	mute := flag.Bool("mute", false, "synthesize replies but play them silently (useful with --text-mode)")
+	tui := flag.Bool("tui", false, "show a live dashboard (state, transcripts, TTS queue, levels, recent logs) instead of the log stream")
+	calibrate := flag.Bool("calibrate", false, "measure room noise and speech levels, recommend vad_threshold and agc.target_rms, then exit")
+	auditSession := flag.String("audit-session", "", "print tool audit records of a session (trace_id in the log, or \"all\") from tools.audit.path, then exit")
+	printConfig := flag.Bool("print-config", false, "print the effective config (profiles, env and flags applied, secrets masked), then exit")
 	flag.Parse()
 
-	appConfig, err := config.Load(*configPath)
+	appConfig, err := config.LoadProfile(*configPath, *profile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load config: %v\n", err)
 		os.Exit(1)
 	}
-	if err := appConfig.ValidateKeys(true, true, true); err != nil {
+	if *mode != "" || *target != "" || *sourceLang != "" {
+		applyModeFlags(appConfig, *mode, *target, *sourceLang)
+		if err := appConfig.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *printConfig {
+		out, err := json.MarshalIndent(appConfig.Redacted(), "", "    ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to encode config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(out))
+		return
+	}
+	if *calibrate {
+		// 校准只需要麦克风，不校验 API Key，也不初始化日志（避免日志与提示交错）
+		backend, err := audio.NewAudioBackend(appConfig.Audio.Backend)
+		if err == nil {
+			err = backend.Initialize()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to initialize audio backend: %v\n", err)
+			os.Exit(1)
+		}
+		err = runCalibration(context.Background(), appConfig, *configPath, os.Stdin, os.Stdout)
+		backend.Terminate()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Calibration failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *auditSession != "" {
+		if err := printAuditRecords(os.Stdout, appConfig.Tools.Audit.Path, *auditSession); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to read tool audit log: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if *tui && *textMode {
+		fmt.Fprintln(os.Stderr, "--tui cannot be combined with --text-mode")
+		os.Exit(1)
+	}
+	if *textMode {
+		// 文本模式不打开麦克风，也不需要与输入共用的全双工流
+		appConfig.Audio.FullDuplex = false
+	}
+	realtimeMode := appConfig.Realtime.Enable
+	if realtimeMode && appConfig.Realtime.APIKey == "" {
+		appConfig.Realtime.APIKey = appConfig.LLM.APIKey
+	}
+	// 实时语音模式由模型完成识别和合成，不需要 ASR / TTS / LLM 的 Key
+	if err := appConfig.ValidateKeys(!*textMode && !realtimeMode, !realtimeMode, !realtimeMode); err != nil {
 		fmt.Fprintf(os.Stderr, "Invalid config: %v\n", err)
 		os.Exit(1)
 	}
 
-	if err := logging.Init(logging.Config{
+	logCfg := logging.Config{
 		Level:  appConfig.Logging.Level,
 		Format: appConfig.Logging.Format,
-	}); err != nil {
+		Levels: appConfig.Logging.Levels,
+		File: logging.FileConfig{
+			Path:           appConfig.Logging.File.Path,
+			Level:          appConfig.Logging.File.Level,
+			MaxSizeMB:      appConfig.Logging.File.MaxSizeMB,
+			RotateInterval: time.Duration(appConfig.Logging.File.RotateHours) * time.Hour,
+			MaxBackups:     appConfig.Logging.File.MaxBackups,
+		},
+	}
+	if *textMode && (logCfg.Level == "" || logCfg.Level == "info") {
+		// 文本模式下 info 日志会淹没对话内容，默认只输出警告和错误；日志文件仍记录 info
+		if logCfg.File.Level == "" {
+			logCfg.File.Level = "info"
+		}
+		logCfg.Level = "warn"
+	}
+	var dash *dashboard
+	if *tui {
+		// 日志写入仪表盘的日志面板，仪表盘显示之前照常输出到 stderr
+		dash = newDashboard(os.Stdout, os.Stderr)
+		logCfg.Output = dash
+	}
+	if err := logging.Init(logCfg); err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to init logger: %v\n", err)
 		os.Exit(1)
 	}
 	defer logging.Sync()
+	logging.RegisterSecrets(appConfig.Secrets()...)
 
 	logging.SetTraceID(logging.NewTraceID())
 
@@ -56,14 +155,118 @@ func main() {
 		logging.Fatalf("Invalid tool types: %v", err)
 	}
 
+	// 工具定义是唯一来源：同时注册到 ToolExecutor、绑定到 LLM 并渲染到系统提示词
+	logging.Infof("Creating ToolExecutor and registering tools...")
+	// 整体音量在 Mixer 创建后绑定，setVolume 工具、语音命令共用并保存到 audio.mixer.volume_state
+	volumeControl, err := audio.NewVolumeControl(appConfig.Audio.Mixer.VolumeState, appConfig.Audio.Mixer.Volume)
+	if err != nil {
+		logging.Warnf("Failed to load volume state, using audio.mixer.volume: %v", err)
+		volumeControl, _ = audio.NewVolumeControl("", appConfig.Audio.Mixer.Volume)
+	}
+	toolExecutor := tools.NewToolExecutor()
+	toolExecutor.Register(tools.GetTimeDefinition, tools.GetTimeTool)
+	toolExecutor.Register(tools.GetWeatherDefinition, tools.GetWeatherTool)
+	toolExecutor.Register(tools.SetVolumeDefinition, tools.NewSetVolumeTool(volumeControl))
+	if appConfig.Calendar.Enable {
+		calendarTools, err := buildCalendarToolConfig(appConfig.Calendar)
+		if err != nil {
+			logging.Fatalf("Failed to create calendar: %v", err)
+		}
+		toolExecutor.Register(tools.CreateEventDefinition, tools.NewCreateEventTool(calendarTools))
+		toolExecutor.Register(tools.GetEventsDefinition, tools.NewGetEventsTool(calendarTools))
+		logging.Infof("Calendar tools enabled: provider=%s, timezone=%s", appConfig.Calendar.Provider, calendarTools.Location)
+	}
+	if appConfig.News.Enable {
+		reader, feeds := buildNewsReader(appConfig.News)
+		toolExecutor.Register(tools.NewsDefinition(feeds), tools.NewGetNewsTool(reader, appConfig.News.Limit))
+		logging.Infof("News tool enabled: feeds=%v", feeds)
+	}
+	for _, cfg := range appConfig.Tools.HTTP {
+		httpTool := buildHTTPToolConfig(cfg)
+		executor, err := tools.NewHTTPTool(httpTool, nil)
+		if err != nil {
+			logging.Fatalf("Failed to create HTTP tool: %v", err)
+		}
+		toolExecutor.Register(httpTool.Definition, executor)
+	}
+	if appConfig.Tools.Shell.Enable {
+		// 配置校验保证此时 tools.audit 已开启，命令工具的每次执行都会写入审计文件
+		for _, cfg := range appConfig.Tools.Shell.Tools {
+			shellTool := buildShellToolConfig(cfg)
+			executor, err := tools.NewShellTool(shellTool)
+			if err != nil {
+				logging.Fatalf("Failed to create shell tool: %v", err)
+			}
+			toolExecutor.Register(shellTool.Definition, executor)
+			logging.Warnf("Shell tool %s enabled: %q (dir: %q, env: %v)", cfg.Name, cfg.Command, cfg.Dir, cfg.Env)
+		}
+	}
+	if appConfig.Tools.Audit.Enable {
+		auditLog, err := tools.OpenAuditLog(tools.AuditConfig{
+			Path:           appConfig.Tools.Audit.Path,
+			RedactFields:   appConfig.Tools.Audit.RedactFields,
+			ResultMaxChars: appConfig.Tools.Audit.ResultMaxChars,
+		})
+		if err != nil {
+			logging.Fatalf("Failed to open tool audit log: %v", err)
+		}
+		defer auditLog.Close()
+		toolExecutor = tools.NewAuditedExecutor(toolExecutor, auditLog)
+		logging.Infof("Tool audit log: %s", appConfig.Tools.Audit.Path)
+	}
+	logging.Infof("Tools registered successfully")
+
+	var knowledgeRetriever agent.KnowledgeRetriever
+	if appConfig.Knowledge.Enable {
+		logging.Infof("Loading knowledge base...")
+		base, err := buildKnowledgeBase(context.Background(), appConfig)
+		if err != nil {
+			logging.Warnf("Failed to load knowledge base, continuing without it: %v", err)
+		} else {
+			knowledgeRetriever = base
+		}
+	}
+
 	logging.Infof("Creating VoiceAgent...")
-	voiceAgent, err := agent.NewVoiceAgentWithConfig(context.Background(), agent.Config{
+	agentCfg := agent.Config{
+		Provider:        appConfig.LLM.Provider,
 		APIKey:          appConfig.LLM.APIKey,
 		BaseURL:         appConfig.LLM.BaseURL,
 		Model:           appConfig.LLM.Model,
 		ToolTypes:       toolTypes,
-		ActionResponses: appConfig.Tools.ActionResponses,
-	})
+		ActionResponses: actionResponses(appConfig.Tools),
+		Prompt:          buildPromptConfig(appConfig.LLM, toolTypes),
+		Fallbacks:       buildLLMFallbacks(appConfig.LLM.Fallbacks),
+		MaxRetries:      appConfig.LLM.MaxRetries,
+		RetryBackoff:    time.Duration(appConfig.LLM.RetryBackoffMs) * time.Millisecond,
+		Tools:           toolExecutor.Definitions(),
+		Knowledge:       knowledgeRetriever,
+		IntentRouter:    buildIntentRouterConfig(appConfig.LLM.IntentRouter),
+		ToolSelector:    buildToolSelector(appConfig),
+	}
+	agentCfg.ToolSelectionShadow = appConfig.Tools.Retrieval.Enable && appConfig.Tools.Retrieval.Shadow
+	responseCfg := appConfig.Conversation.Response
+	agentCfg.Prompt.Response = agent.ResponsePolicy{
+		MaxSentences:  responseCfg.MaxSentences,
+		MaxChars:      responseCfg.MaxChars,
+		SummarizeOver: responseCfg.SummarizeOver,
+	}
+	translating := appConfig.Conversation.Mode == config.ModeTranslate
+	var voiceAgent agent.VoiceAgent
+	var realtimeSession *realtime.Session
+	if realtimeMode {
+		logging.Infof("Realtime mode: provider=%s, speech is sent directly to the model", appConfig.Realtime.Provider)
+		realtimeSession, err = buildRealtimeSession(appConfig, agentCfg, toolExecutor)
+		voiceAgent = realtimeSession
+	} else if translating {
+		logging.Infof("Translate mode: target=%s, source=%s", appConfig.Translation.Target, appConfig.Translation.Source)
+		voiceAgent, err = agent.NewTranslatorAgent(context.Background(), agentCfg, agent.TranslationConfig{
+			Target: appConfig.Translation.Target,
+			Source: appConfig.Translation.Source,
+		})
+	} else {
+		voiceAgent, err = agent.NewVoiceAgentWithConfig(context.Background(), agentCfg)
+	}
 	if err != nil {
 		logging.Fatalf("Failed to create VoiceAgent: %v", err)
 	}
@@ -73,26 +276,120 @@ func main() {
 	mixerCfg := &audio.MixerConfig{
 		TTSVolume:      appConfig.Audio.Mixer.TTSVolume,
 		ResourceVolume: appConfig.Audio.Mixer.ResourceVolume,
+		SampleRate:     appConfig.Audio.Mixer.SampleRate,
+		Channels:       appConfig.Audio.Mixer.Channels,
+		OutputDevice:   appConfig.Audio.Mixer.OutputDevice,
+		CrossfadeMs:    appConfig.Audio.Mixer.CrossfadeMs,
+		FadeOutMs:      appConfig.Audio.Mixer.FadeOutMs,
 	}
-	// Initialize PortAudio once for all audio components
-	logging.Infof("Initializing PortAudio...")
-	if err := portaudio.Initialize(); err != nil {
-		logging.Fatalf("Failed to initialize PortAudio: %v", err)
+	// 浏览器或电话作为音频设备时不使用本机声卡（文本模式仍在本机播放）
+	browserAudio := appConfig.Web.Enable && appConfig.Web.Audio && !*textMode
+	phoneAudio := appConfig.Telephony.Enable && !*textMode
+	if !browserAudio && !phoneAudio {
+		// Initialize the audio backend once for all audio components
+		backend, err := audio.NewAudioBackend(appConfig.Audio.Backend)
+		if err != nil {
+			logging.Fatalf("Failed to create audio backend: %v", err)
+		}
+		logging.Infof("Initializing audio backend %s...", backend.Name())
+		if err := backend.Initialize(); err != nil {
+			logging.Fatalf("Failed to initialize audio backend %s: %v", backend.Name(), err)
+		}
+		defer backend.Terminate()
+		mixerCfg.Backend = backend
+		logging.Infof("Audio backend %s initialized successfully", backend.Name())
 	}
-	defer portaudio.Terminate()
-	logging.Infof("PortAudio initialized successfully")
 
 	logging.Infof("Creating AudioMixer...")
+	mixerCfg.ExternalStream = appConfig.Audio.FullDuplex || browserAudio || phoneAudio
 	mixer, err := audio.NewMixer(mixerCfg)
 	if err != nil {
 		logging.Fatalf("Failed to create AudioMixer: %v", err)
 	}
 	logging.Infof("AudioMixer created successfully")
 
+	var duplex *audio.DuplexStream
+	var remote *audio.RemoteStream
+	if browserAudio || phoneAudio {
+		logging.Infof("Using a remote audio device (web.audio=%v, telephony=%v)", browserAudio, phoneAudio)
+		remote, err = openRemoteStream(appConfig, mixerCfg, mixer)
+		if err != nil {
+			logging.Fatalf("Failed to create remote audio stream: %v", err)
+		}
+	} else if appConfig.Audio.FullDuplex {
+		logging.Infof("Opening full-duplex stream...")
+		duplex, err = openDuplexStream(appConfig, mixerCfg, mixer)
+		if err != nil {
+			// 设备不支持全双工时回退为独立的输入、输出流
+			logging.Warnf("Failed to open full-duplex stream, falling back to separate streams: %v", err)
+			mixer.Stop()
+			mixerCfg.ExternalStream = false
+			mixer, err = audio.NewMixer(mixerCfg)
+			if err != nil {
+				logging.Fatalf("Failed to create AudioMixer: %v", err)
+			}
+		} else {
+			logging.Infof("Full-duplex stream started")
+		}
+	}
+
+	var outputRecorder *audio.WAVRecorder
+	if recorderMixer, ok := mixer.(audio.OutputRecorder); ok {
+		outputRate := mixerCfg.SampleRate
+		if outputRate == 0 {
+			outputRate = audio.DefaultMixerConfig().SampleRate
+		}
+		outputRecorder, err = openDebugRecorder(appConfig.Audio.DebugRecord, "output", outputRate, 1)
+		if err != nil {
+			logging.Fatalf("Failed to start debug recording: %v", err)
+		}
+		if outputRecorder != nil {
+			recorderMixer.SetOutputRecorder(outputRecorder)
+		}
+	}
+
 	logging.Infof("Starting AudioMixer...")
 	mixer.Start()
+	if volume, ok := mixer.(audio.VolumeController); ok {
+		volumeControl.Bind(volume)
+		logging.Infof("Output volume: %.2f", volumeControl.Volume())
+	}
+	if *mute {
+		mixer.SetTTSVolume(0)
+		mixer.SetResourceVolume(0)
+	}
 	logging.Infof("AudioMixer started")
 
+	var prompts audio.Prompts
+	fillerEnabled := appConfig.Conversation.FillerDelayMs > 0
+	apologyEnabled := appConfig.Conversation.ErrorText != ""
+	repromptEnabled := appConfig.Conversation.Reprompt.Enable && appConfig.Conversation.Reprompt.MaxConsecutive > 0
+	contentPolicy, err := buildContentPolicy(appConfig.ContentFilter)
+	if err != nil {
+		logging.Fatalf("Failed to load content filter: %v", err)
+	}
+	refuseEnabled := appConfig.ContentFilter.RejectText != "" &&
+		(contentPolicy.Input == voicebot.ContentActionReject || contentPolicy.Output == voicebot.ContentActionReject)
+	earconEnabled := appConfig.Conversation.MicMuteEarcon
+	if appConfig.Audio.Prompts.Enable || fillerEnabled || apologyEnabled || repromptEnabled || refuseEnabled || earconEnabled {
+		logging.Infof("Loading prompts...")
+		promptsCfg := audio.DefaultPromptsConfig()
+		promptsCfg.Dir = appConfig.Audio.Prompts.Dir
+		if !appConfig.Audio.Prompts.Enable {
+			// 仅启用填充音 / 致歉语时不加载其他提示音文件
+			promptsCfg.Files = nil
+		} else if len(appConfig.Audio.Prompts.Files) > 0 {
+			promptsCfg.Files = appConfig.Audio.Prompts.Files
+		}
+		if mixerCfg.SampleRate > 0 {
+			promptsCfg.SampleRate = mixerCfg.SampleRate
+		}
+		prompts, err = audio.NewPrompts(promptsCfg, mixer)
+		if err != nil {
+			logging.Fatalf("Failed to load prompts: %v", err)
+		}
+	}
+
 	logging.Infof("Creating AudioOutPipe...")
 	outPipeCfg := audio.DefaultOutPipeConfig()
 	outPipeCfg.Mixer = mixerCfg
@@ -101,6 +398,10 @@ func main() {
 		MaxTTSBuffer:     appConfig.Audio.TTSPipeline.MaxTTSBuffer,
 		MaxConcurrentTTS: appConfig.Audio.TTSPipeline.MaxConcurrentTTS,
 		TextQueueSize:    appConfig.Audio.TTSPipeline.TextQueueSize,
+		PrerollMs:        appConfig.Audio.TTSPipeline.PrerollMs,
+		BatchMaxChars:    appConfig.Audio.TTSPipeline.BatchMaxChars,
+		BatchMinChars:    appConfig.Audio.TTSPipeline.BatchMinChars,
+		BatchWaitMs:      appConfig.Audio.TTSPipeline.BatchWaitMs,
 	}
 	// 如果配置值为 0，使用默认值
 	if outPipeCfg.TTSPipeline.MaxTTSBuffer <= 0 {
@@ -126,16 +427,507 @@ func main() {
 		EnableSSML:           appConfig.TTS.EnableSSML,
 		TextType:             appConfig.TTS.TextType,
 		EnableDataInspection: appConfig.TTS.EnableDataInspection,
+		BufferBytes:          appConfig.TTS.BufferBytes,
+		BufferPolicy:         tts.BufferPolicy(appConfig.TTS.BufferPolicy),
 	}
 	if len(appConfig.TTS.VoiceMap) > 0 {
-		outPipeCfg.VoiceMap = appConfig.TTS.VoiceMap
+		outPipeCfg.VoiceMap = make(map[string]string)
+		outPipeCfg.EmotionProsody = make(map[string]tts.Prosody)
+		for emotion, v := range appConfig.TTS.VoiceMap {
+			if v.Voice != "" {
+				outPipeCfg.VoiceMap[emotion] = v.Voice
+			}
+			if v.Rate != 0 || v.Pitch != 0 || v.Volume != 0 {
+				outPipeCfg.EmotionProsody[emotion] = tts.Prosody{Rate: v.Rate, Pitch: v.Pitch, Volume: v.Volume}
+			}
+		}
+	}
+	if appConfig.TTS.EnableSSML {
+		ssmlCfg := tts.SSMLConfig{
+			CommaBreakMs:          appConfig.TTS.SSML.CommaBreakMs,
+			ExclamationPitchBoost: appConfig.TTS.SSML.ExclamationPitchBoost,
+			EmotionProsody:        make(map[string]tts.Prosody),
+			VoiceProsody:          make(map[string]tts.Prosody),
+		}
+		for emotion, p := range appConfig.TTS.SSML.EmotionProsody {
+			ssmlCfg.EmotionProsody[emotion] = tts.Prosody{Rate: p.Rate, Pitch: p.Pitch, Volume: p.Volume}
+		}
+		for voice, p := range appConfig.TTS.SSML.VoiceProsody {
+			ssmlCfg.VoiceProsody[voice] = tts.Prosody{Rate: p.Rate, Pitch: p.Pitch, Volume: p.Volume}
+		}
+		outPipeCfg.SSML = &ssmlCfg
 	}
+	fillerPrompt := appConfig.Conversation.FillerPrompt
+	if fillerPrompt == "" {
+		fillerPrompt = audio.PromptThinking
+	}
+	if fillerEnabled && !prompts.Has(fillerPrompt) && appConfig.Conversation.FillerText != "" {
+		// 没有现成的填充音文件时，启动阶段用 TTS 预合成并缓存
+		logging.Infof("Synthesizing filler prompt %q...", appConfig.Conversation.FillerText)
+		synthCtx, synthCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		clip, err := audio.SynthesizePrompt(synthCtx, tts.NewDashScopeProvider(), outPipeCfg.TTS,
+			appConfig.Conversation.FillerText, mixerCfg.SampleRate)
+		synthCancel()
+		if err != nil {
+			logging.Warnf("Failed to synthesize filler prompt, filler disabled: %v", err)
+		} else {
+			prompts.Register(fillerPrompt, clip)
+		}
+	}
+	if apologyEnabled && !prompts.Has(audio.PromptError) {
+		// 出错时网络可能已经不可用，致歉语在启动阶段预合成并缓存
+		logging.Infof("Synthesizing apology prompt %q...", appConfig.Conversation.ErrorText)
+		synthCtx, synthCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		clip, err := audio.SynthesizePrompt(synthCtx, tts.NewDashScopeProvider(), outPipeCfg.TTS,
+			appConfig.Conversation.ErrorText, mixerCfg.SampleRate)
+		synthCancel()
+		if err != nil {
+			logging.Warnf("Failed to synthesize apology prompt, errors will not be announced: %v", err)
+		} else {
+			prompts.Register(audio.PromptError, clip)
+		}
+	}
+	if repromptEnabled && !prompts.Has(audio.PromptRepeat) && appConfig.Conversation.Reprompt.Text != "" {
+		logging.Infof("Synthesizing reprompt %q...", appConfig.Conversation.Reprompt.Text)
+		synthCtx, synthCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		clip, err := audio.SynthesizePrompt(synthCtx, tts.NewDashScopeProvider(), outPipeCfg.TTS,
+			appConfig.Conversation.Reprompt.Text, mixerCfg.SampleRate)
+		synthCancel()
+		if err != nil {
+			logging.Warnf("Failed to synthesize reprompt, unclear utterances will be dropped silently: %v", err)
+		} else {
+			prompts.Register(audio.PromptRepeat, clip)
+		}
+	}
+	if refuseEnabled && !prompts.Has(audio.PromptRefuse) {
+		logging.Infof("Synthesizing content filter response %q...", appConfig.ContentFilter.RejectText)
+		synthCtx, synthCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		clip, err := audio.SynthesizePrompt(synthCtx, tts.NewDashScopeProvider(), outPipeCfg.TTS,
+			appConfig.ContentFilter.RejectText, mixerCfg.SampleRate)
+		synthCancel()
+		if err != nil {
+			logging.Warnf("Failed to synthesize content filter response, rejected turns will be silent: %v", err)
+		} else {
+			prompts.Register(audio.PromptRefuse, clip)
+		}
+	}
+
+	if earconEnabled {
+		// 麦克风开关确认音：没有提示音文件时用内置的降调 / 升调短音
+		if !prompts.Has(audio.PromptMicOff) {
+			prompts.Register(audio.PromptMicOff, audio.GenerateEarcon(mixerCfg.SampleRate, 880, 660))
+		}
+		if !prompts.Has(audio.PromptMicOn) {
+			prompts.Register(audio.PromptMicOn, audio.GenerateEarcon(mixerCfg.SampleRate, 660, 880))
+		}
+	}
+
 	audioOutPipe := audio.NewOutPipeWithConfig(outPipeCfg)
 	audioOutPipe.SetMixer(mixer)
 	logging.Infof("AudioOutPipe created successfully (async TTS pipeline: maxBuffer=%d, maxConcurrent=%d)",
 		outPipeCfg.TTSPipeline.MaxTTSBuffer, outPipeCfg.TTSPipeline.MaxConcurrentTTS)
 
 	logging.Infof("Creating AudioInPipe...")
+	var audioInPipe audio.AudioInPipe
+	var textIn *voicebot.TextInPipe
+	if *textMode {
+		// 文本模式：标准输入的每一行作为一句 ASR final，不打开麦克风
+		textIn = voicebot.NewTextInPipe()
+		audioInPipe = textIn
+	} else {
+		var input streamInput
+		if remote != nil {
+			input = remote
+		} else if duplex != nil {
+			input = duplex
+		}
+		if realtimeSession != nil {
+			// 实时语音模式：处理后的麦克风音频直接发送给模型，会话同时充当 AudioInPipe
+			audioSource, inPipeCfg, err := buildAudioSource(appConfig, input, audioOutPipe)
+			if err != nil {
+				logging.Fatalf("Failed to create audio source: %v", err)
+			}
+			realtimeSession.SetAudioSource(audioSource, inPipeCfg.SampleRate)
+			audioInPipe = realtimeSession
+		} else {
+			audioInPipe, err = buildAudioInPipe(appConfig, input, audioOutPipe)
+			if err != nil {
+				logging.Fatalf("Failed to create AudioInPipe: %v", err)
+			}
+		}
+	}
+	logging.Infof("AudioInPipe created successfully")
+
+	logging.Infof("Creating Orchestrator...")
+	orchestratorCfg := voicebot.DefaultOrchestratorConfig()
+	orchestratorCfg.ResumeInterrupted = appConfig.Conversation.ResumeInterrupted
+	if len(appConfig.Conversation.ResumePhrases) > 0 {
+		orchestratorCfg.ResumePhrases = appConfig.Conversation.ResumePhrases
+	}
+	orchestratorCfg.FillerDelay = time.Duration(appConfig.Conversation.FillerDelayMs) * time.Millisecond
+	orchestratorCfg.FillerPrompt = fillerPrompt
+	orchestratorCfg.SegmentFlushDelay = time.Duration(appConfig.Conversation.SegmentFlushMs) * time.Millisecond
+	if textIn == nil {
+		// 文本模式每行就是完整的一轮，不等待停顿
+		orchestratorCfg.EndOfTurnSilence = time.Duration(appConfig.Conversation.EndOfTurnSilenceMs) * time.Millisecond
+	}
+	orchestratorCfg.ProcessingTimeout = time.Duration(appConfig.Conversation.ProcessingTimeoutMs) * time.Millisecond
+	orchestratorCfg.SpeakingTimeout = time.Duration(appConfig.Conversation.SpeakingTimeoutMs) * time.Millisecond
+	orchestratorCfg.FollowUpWindow = time.Duration(appConfig.Conversation.FollowUpWindowMs) * time.Millisecond
+	orchestratorCfg.ErrorPolicy.MaxRetries = appConfig.Conversation.ErrorRetries
+	orchestratorCfg.ErrorPolicy.RetryDelay = time.Duration(appConfig.Conversation.ErrorRetryDelayMs) * time.Millisecond
+	if repromptEnabled {
+		orchestratorCfg.Reprompt.MinConfidence = appConfig.Conversation.Reprompt.MinConfidence
+		orchestratorCfg.Reprompt.MinChars = appConfig.Conversation.Reprompt.MinChars
+		orchestratorCfg.Reprompt.MaxConsecutive = appConfig.Conversation.Reprompt.MaxConsecutive
+		if len(appConfig.Conversation.Reprompt.Fillers) > 0 {
+			orchestratorCfg.Reprompt.Fillers = appConfig.Conversation.Reprompt.Fillers
+		}
+	} else {
+		orchestratorCfg.Reprompt.MaxConsecutive = 0
+	}
+	if echoGuard := appConfig.Conversation.EchoGuard; echoGuard.Enable {
+		orchestratorCfg.EchoGuard = voicebot.EchoGuardPolicy{
+			Window:        time.Duration(echoGuard.WindowMs) * time.Millisecond,
+			MinSimilarity: echoGuard.MinSimilarity,
+			MinChars:      echoGuard.MinChars,
+		}
+	} else {
+		orchestratorCfg.EchoGuard = voicebot.EchoGuardPolicy{}
+	}
+	sleepCfg := appConfig.Conversation.Sleep
+	orchestratorCfg.Sleep.IdleTimeout = time.Duration(sleepCfg.IdleMinutes) * time.Minute
+	orchestratorCfg.Sleep.WakeWords = sleepCfg.WakeWords
+	orchestratorCfg.Sleep.PauseASR = sleepCfg.PauseASR
+	orchestratorCfg.Sleep.WakePrompt = sleepCfg.WakePrompt
+	if sleepCfg.Text != "" {
+		orchestratorCfg.Sleep.Text = sleepCfg.Text
+	}
+	orchestratorCfg.ReplyLimit = voicebot.ReplyLimit{
+		MaxSentences: responseCfg.MaxSentences,
+		MaxChars:     responseCfg.MaxChars,
+		FollowUp:     responseCfg.FollowUp,
+	}
+	orchestratorCfg.Content.Filter = contentPolicy.Filter
+	orchestratorCfg.Content.Input = contentPolicy.Input
+	orchestratorCfg.Content.Output = contentPolicy.Output
+	orchestratorCfg.Commands = buildCommandPolicy(appConfig.Conversation.Commands)
+	orchestratorCfg.Confirmation = buildConfirmationPolicy(appConfig.Tools.Confirmation)
+	orchestratorCfg.SlotFilling = buildSlotFillingPolicy(appConfig.Tools.SlotFilling)
+	orchestratorCfg.ToolProgress = voicebot.ToolProgressPolicy{
+		Delay:    time.Duration(appConfig.Tools.Progress.DelayMs) * time.Millisecond,
+		Interval: time.Duration(appConfig.Tools.Progress.IntervalMs) * time.Millisecond,
+		Speak:    appConfig.Tools.Progress.Speak,
+		Text:     appConfig.Tools.Progress.Text,
+	}
+	orchestratorCfg.Volume = volumeControl
+	orchestratorCfg.ReplayAudio = appConfig.Conversation.ReplayAudio
+	orchestratorCfg.HalfDuplex = appConfig.Conversation.HalfDuplex
+	orchestratorCfg.LevelMonitor = voicebot.LevelMonitorConfig{
+		SilenceThreshold: appConfig.Audio.Levels.SilenceThreshold,
+		SilenceDuration:  time.Duration(appConfig.Audio.Levels.SilenceWarnMs) * time.Millisecond,
+		WarnInterval:     time.Duration(appConfig.Audio.Levels.ClipWarnMs) * time.Millisecond,
+	}
+	orchestratorCfg.NormalizeText = appConfig.TTS.Normalization.Enable
+	orchestratorCfg.NormalizeLocale = appConfig.TTS.Normalization.Locale
+	orchestratorCfg.DetectLanguage = appConfig.Conversation.DetectLanguage
+	if translating {
+		// 翻译模式：按目标语言选择音色；“继续”“停”等话术、填充音和回复长度上限都不适用
+		orchestratorCfg.ReplyLanguage = appConfig.Translation.Target
+		orchestratorCfg.ResumeInterrupted = false
+		orchestratorCfg.Commands = voicebot.CommandPolicy{}
+		orchestratorCfg.FillerDelay = 0
+		orchestratorCfg.Reprompt.MaxConsecutive = 0
+		orchestratorCfg.ReplyLimit = voicebot.ReplyLimit{}
+	}
+	if appConfig.Speaker.Enable {
+		identifier, err := buildSpeakerIdentifier(appConfig.Speaker)
+		if err != nil {
+			logging.Warnf("Failed to load voiceprints, speaker identification disabled: %v", err)
+		} else {
+			orchestratorCfg.SpeakerID = identifier
+			orchestratorCfg.SpeakerSampleRate = appConfig.Audio.InPipe.SampleRate
+			orchestratorCfg.IgnoreUnknownSpeakers = appConfig.Speaker.IgnoreUnknown
+		}
+	}
+	// 流式回复同时交给文本控制台 / 仪表盘和网页界面
+	var replyText []func(string)
+	var replyFinished []func()
+	var console *textConsole
+	if textIn != nil {
+		console = newTextConsole(os.Stdout)
+		replyText = append(replyText, console.OnReplyText)
+		replyFinished = append(replyFinished, console.OnReplyFinished)
+	}
+	if dash != nil {
+		replyText = append(replyText, dash.OnReplyText)
+		replyFinished = append(replyFinished, dash.OnReplyFinished)
+	}
+	var web *webui.Server
+	if appConfig.Web.Enable {
+		webCfg := webui.Config{HistorySize: appConfig.Web.HistorySize, ICEServers: appConfig.Web.ICEServers}
+		if browserAudio {
+			webCfg.Audio = remote
+		}
+		web = webui.New(webCfg)
+		replyText = append(replyText, web.OnReplyText)
+		replyFinished = append(replyFinished, web.OnReplyFinished)
+	}
+	if len(replyText) > 0 {
+		orchestratorCfg.OnReplyText = func(chunk string) {
+			for _, fn := range replyText {
+				fn(chunk)
+			}
+		}
+		orchestratorCfg.OnReplyFinished = func() {
+			for _, fn := range replyFinished {
+				fn()
+			}
+		}
+	}
+	var indicator *commandIndicator
+	if len(appConfig.Indicator.Command) > 0 {
+		indicator, err = startCommandIndicator(appConfig.Indicator.Command)
+		if err != nil {
+			logging.Warnf("Device indicator disabled: %v", err)
+		} else {
+			orchestratorCfg.Indicator = indicator
+		}
+	}
+	orchestrator := voicebot.NewOrchestratorWithConfig(voiceAgent, audioOutPipe, audioInPipe, toolExecutor, orchestratorCfg)
+	if prompts != nil {
+		orchestrator.SetPrompts(prompts)
+	}
+	if console != nil {
+		orchestrator.Subscribe(voicebot.EventTypeToolResult, console.OnToolResult)
+	}
+	if dash != nil {
+		dash.source = orchestrator
+		dash.pipeline = audioOutPipe.Stats
+		orchestrator.Subscribe(voicebot.EventTypePartialTranscript, dash.OnEvent)
+		orchestrator.Subscribe(voicebot.EventTypeASRFinal, dash.OnEvent)
+	}
+	if web != nil {
+		web.Attach(orchestrator)
+	}
+	logging.Infof("Orchestrator created successfully")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logging.Infof("Setting up signal handler...")
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+
+	go func() {
+		sig := <-sigCh
+		logging.Infof("\n========================================")
+		logging.Infof("     Received %v signal...       ", sig)
+		logging.Infof("========================================")
+
+		// 关闭顺序：从外到内，先停止依赖方，再停止被依赖方
+		// Orchestrator 依赖 Mixer，所以先停 Orchestrator
+		var stopErr error
+		drainTimeout := time.Duration(appConfig.Conversation.ShutdownDrainMs) * time.Millisecond
+		if sig == syscall.SIGTERM && drainTimeout > 0 {
+			// SIGTERM：说完当前回复再退出；期间再次收到信号则立即停止
+			logging.Infof("Stopping Orchestrator gracefully (drain up to %v)...", drainTimeout)
+			drainCtx, drainCancel := context.WithTimeout(context.Background(), drainTimeout)
+			go func() {
+				select {
+				case <-sigCh:
+					drainCancel()
+				case <-drainCtx.Done():
+				}
+			}()
+			stopErr = orchestrator.StopGracefully(drainCtx)
+			drainCancel()
+		} else {
+			logging.Infof("Stopping Orchestrator...")
+			stopErr = orchestrator.Stop()
+		}
+		if stopErr != nil {
+			logging.Errorf("Error stopping orchestrator: %v", stopErr)
+		}
+		if indicator != nil {
+			if err := indicator.Close(); err != nil {
+				logging.Warnf("Device indicator exited: %v", err)
+			}
+		}
+
+		stats := orchestrator.Stats()
+		logging.Infof("Session usage: turns=%d, tokens=%d+%d, asr=%v, tts_chars=%d, first_token(avg/max)=%v/%v",
+			stats.Turns, stats.PromptTokens, stats.CompletionTokens, stats.ASRDuration, stats.TTSChars,
+			stats.AvgFirstTokenLatency, stats.MaxFirstTokenLatency)
+		logging.Infof("Audio levels: mic peak=%.3f clipped=%d, speaker peak=%.3f clipped=%d",
+			stats.InputLevel.MaxPeak, stats.InputLevel.ClippedSamples, stats.OutputLevel.MaxPeak, stats.OutputLevel.ClippedSamples)
+		pipelineStats := audioOutPipe.Stats()
+		logging.Infof("Playback stats: played=%d, interrupts=%d, underruns=%d (%dms)",
+			pipelineStats.TotalPlayed, pipelineStats.TotalInterrupts, pipelineStats.Underruns, pipelineStats.UnderrunMs)
+		if reporter, ok := voiceAgent.(agent.ToolSelectionReporter); ok {
+			if selection := reporter.ToolSelectionStats(); selection.Turns+selection.Fallbacks > 0 {
+				logging.Infof("Tool selection: turns=%d, fallbacks=%d, avg_selected=%.1f/%d, recall=%.2f (%d/%d calls), avg_latency=%v",
+					selection.Turns, selection.Fallbacks, selection.AvgSelected(), selection.Catalog,
+					selection.Recall(), selection.Hits, selection.ToolCalls, selection.AvgLatency())
+			}
+		}
+
+		logging.Infof("Stopping Mixer...")
+		mixer.Stop()
+		if duplex != nil {
+			logging.Infof("Closing full-duplex stream...")
+			if err := duplex.Close(); err != nil {
+				logging.Errorf("Error closing full-duplex stream: %v", err)
+			}
+		}
+		if remote != nil {
+			remote.Close()
+		}
+		if outputRecorder != nil {
+			if err := outputRecorder.Close(); err != nil {
+				logging.Errorf("Error closing debug recording: %v", err)
+			}
+		}
+		if realtimeSession != nil && textIn != nil {
+			// 文本模式下会话只作为 Agent 使用，不随 AudioInPipe 停止
+			realtimeSession.Stop()
+		}
+
+		// 取消 context，让 main 函数自然退出
+		// 不使用 os.Exit(0)，这样 defer 语句（如 backend.Terminate()）才会被执行
+		cancel()
+	}()
+
+	routines, err := buildRoutines(appConfig.Routines)
+	if err != nil {
+		logging.Fatalf("Invalid routines: %v", err)
+	}
+
+	logging.Infof("Starting Orchestrator...")
+	if err := orchestrator.Start(ctx); err != nil {
+		logging.Fatalf("Failed to start orchestrator: %v", err)
+	}
+
+	logging.Infof("========================================")
+	logging.Infof("     VoiceBot is Running! 🎤          ")
+	logging.Infof("     Press Ctrl+C to stop.             ")
+	logging.Infof("========================================")
+
+	if web != nil {
+		go func() {
+			if err := web.ListenAndServe(ctx, appConfig.Web.Addr); err != nil {
+				logging.Errorf("WebUI error: %v", err)
+			}
+		}()
+	}
+
+	if appConfig.MQTT.Enable {
+		bridge := mqtt.NewBridge(orchestrator, mqtt.BridgeConfig{
+			Client: mqtt.ClientConfig{
+				Broker:    appConfig.MQTT.Broker,
+				ClientID:  appConfig.MQTT.ClientID,
+				Username:  appConfig.MQTT.Username,
+				Password:  appConfig.MQTT.Password,
+				KeepAlive: time.Duration(appConfig.MQTT.KeepAliveSec) * time.Second,
+			},
+			EventTopic: appConfig.MQTT.EventTopic,
+			SayTopics:  appConfig.MQTT.SayTopics,
+			AskTopics:  appConfig.MQTT.AskTopics,
+		})
+		go bridge.Run(ctx)
+	}
+	if len(routines) > 0 {
+		go routine.NewScheduler(orchestrator, routine.Config{Routines: routines}).Run(ctx)
+	}
+	if phoneAudio {
+		go func() {
+			err := telephony.Serve(ctx, telephony.Config{
+				Protocol:    appConfig.Telephony.Protocol,
+				Addr:        appConfig.Telephony.Addr,
+				IdleTimeout: time.Duration(appConfig.Telephony.IdleTimeoutMs) * time.Millisecond,
+				Device:      remote,
+				OnCall: func(active bool) {
+					if !active {
+						// 挂断时打断未播完的回复，避免留给下一通电话
+						orchestrator.OnUserSpeakingDetected()
+					}
+				},
+			})
+			if err != nil {
+				logging.Errorf("Telephony error: %v", err)
+			}
+		}()
+	}
+
+	dashDone := make(chan struct{})
+	if dash != nil {
+		go func() {
+			dash.Run(ctx)
+			close(dashDone)
+		}()
+	} else {
+		close(dashDone)
+	}
+
+	if console == nil {
+		// 语音模式下标准输入用作快捷键，m + 回车开关麦克风；仪表盘上已显示麦克风状态，不再打印提示
+		logging.Infof("     Press m + Enter to mute/unmute the microphone.")
+		var hotkeyOut io.Writer = os.Stdout
+		if dash != nil {
+			hotkeyOut = io.Discard
+		}
+		go func() {
+			if err := runHotkeys(os.Stdin, hotkeyOut, orchestrator); err != nil {
+				logging.Errorf("Hotkey input error: %v", err)
+			}
+		}()
+	}
+	if console != nil {
+		go func() {
+			if err := console.Run(os.Stdin, textIn.Submit); err != nil {
+				logging.Errorf("Text input error: %v", err)
+			}
+			// 输入结束（EOF 或 /quit）时按 SIGTERM 关闭：开启 shutdown_drain_ms 时先说完当前回复
+			select {
+			case sigCh <- syscall.SIGTERM:
+			default:
+			}
+		}()
+	}
+
+	// Wait for context cancellation (triggered by signal handler)
+	<-ctx.Done()
+	// 等仪表盘恢复终端后再输出退出日志
+	<-dashDone
+
+	logging.Infof("\n========================================")
+	logging.Infof("     VoiceBot Shutting Down...          ")
+	logging.Infof("========================================")
+
+	// 音频后端会在 defer backend.Terminate() 中被清理
+	logging.Infof("VoiceBot stopped.")
+}
+
+// streamInput 与 Mixer 渲染同步的输入流（audio.DuplexStream 或浏览器音频的 audio.RemoteStream）
+type streamInput interface {
+	Source() audio.AudioSource
+	SampleRate() int
+	Channels() int
+	SetReferenceSink(sink audio.ReferenceSink)
+}
+
+// buildAudioInPipe 创建麦克风（或全双工流、浏览器音频）输入链路并接入 ASR
+func buildAudioInPipe(appConfig *config.AppConfig, duplex streamInput, audioOutPipe audio.AudioOutPipe) (audio.AudioInPipe, error) {
+	audioSource, inPipeCfg, err := buildAudioSource(appConfig, duplex, audioOutPipe)
+	if err != nil {
+		return nil, err
+	}
+	return audio.NewInPipeWithAudioSource(appConfig.ASR.APIKey, inPipeCfg, audioSource)
+}
+
+// buildAudioSource 创建麦克风（或全双工流、浏览器音频）输入链路：声道映射、重采样、DSP、回声消除，
+// 输出 inPipeCfg.SampleRate 采样率的单声道音频
+func buildAudioSource(appConfig *config.AppConfig, duplex streamInput, audioOutPipe audio.AudioOutPipe) (audio.AudioSource, *audio.InPipeConfig, error) {
 	inPipeCfg := &audio.InPipeConfig{
 		SampleRate:   appConfig.Audio.InPipe.SampleRate,
 		Channels:     appConfig.Audio.InPipe.Channels,
@@ -143,6 +935,18 @@ func main() {
 		VADThreshold: appConfig.Audio.InPipe.VADThreshold,
 		ASRModel:     appConfig.ASR.Model,
 		ASREndpoint:  appConfig.ASR.Endpoint,
+		ASRHeartbeat: appConfig.ASR.Heartbeat,
+		ASRKeepalive: time.Duration(appConfig.ASR.KeepaliveMs) * time.Millisecond,
+
+		ASRLanguageHints: appConfig.ASR.LanguageHints,
+		ASRVocabularyID:  appConfig.ASR.VocabularyID,
+
+		ReconnectInitialBackoff: time.Duration(appConfig.Audio.InPipe.ReconnectInitialBackoffMs) * time.Millisecond,
+		ReconnectMaxBackoff:     time.Duration(appConfig.Audio.InPipe.ReconnectMaxBackoffMs) * time.Millisecond,
+		ReplayBufferMs:          appConfig.Audio.InPipe.ReplayBufferMs,
+
+		ASRFrameMs:     appConfig.Audio.InPipe.ASRFrameMs,
+		ASRMaxSendRate: appConfig.Audio.InPipe.ASRMaxSendRate,
 	}
 
 	// 配置缓冲区大小，默认 3200 样本 (200ms @ 16kHz)
@@ -151,19 +955,42 @@ func main() {
 		bufferSize = 3200
 	}
 
-	logging.Infof("Creating Microphone source (bufferSize=%d, highLatency=%v, inputDevice=%q)...",
-		bufferSize, appConfig.Audio.InPipe.HighLatency, appConfig.Audio.InPipe.InputDevice)
-	micSource, err := source.NewMicrophoneSourceWithDevice(
-		inPipeCfg.SampleRate,
-		inPipeCfg.Channels,
-		bufferSize,
-		appConfig.Audio.InPipe.HighLatency,
-		appConfig.Audio.InPipe.InputDevice,
-	)
-	if err != nil {
-		logging.Fatalf("Failed to create Microphone source: %v", err)
+	inputChannels := appConfig.Audio.InPipe.InputChannels
+	if inputChannels <= 0 {
+		inputChannels = inPipeCfg.Channels
+	}
+
+	var audioSource audio.AudioSource
+	sourceRate := inPipeCfg.SampleRate
+	sourceChannels := inputChannels
+	inputDevice := appConfig.Audio.InPipe.InputDevice
+	if devices := appConfig.Audio.InPipe.InputDevices; len(devices) == 1 {
+		inputDevice = devices[0]
+	}
+	if duplex != nil {
+		audioSource = duplex.Source()
+		sourceRate = duplex.SampleRate()
+		sourceChannels = duplex.Channels()
+	} else if devices := appConfig.Audio.InPipe.InputDevices; len(devices) > 1 {
+		multiMic, err := buildMultiMicSource(appConfig, devices, inPipeCfg.SampleRate, inputChannels, bufferSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		audioSource = multiMic
+		sourceChannels = 1
+		inPipeCfg.Channels = 1
+	} else {
+		logging.Infof("Creating Microphone source (bufferSize=%d, highLatency=%v, inputDevice=%q)...",
+			bufferSize, appConfig.Audio.InPipe.HighLatency, inputDevice)
+		micSource, err := openMicrophone(appConfig, inputDevice, inPipeCfg.SampleRate, inputChannels, bufferSize)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create microphone source: %w", err)
+		}
+		logging.Infof("Microphone source created successfully")
+		audioSource = micSource
+		sourceRate = micSource.SampleRate()
+		sourceChannels = micSource.Channels()
 	}
-	logging.Infof("Microphone source created successfully")
 
 	aecCfg := audio.DefaultEchoCancelConfig()
 	aecCfg.Enabled = appConfig.Audio.InPipe.AEC.Enable
@@ -177,8 +1004,43 @@ func main() {
 	if appConfig.Audio.InPipe.AEC.ReferenceActiveWindowMs > 0 {
 		aecCfg.ReferenceActiveWindowMs = appConfig.Audio.InPipe.AEC.ReferenceActiveWindowMs
 	}
+	// 全双工流的参考与输入在同一回调中产生，按固定帧数对齐即可
+	duplexReference := duplex != nil && duplex.SampleRate() == inPipeCfg.SampleRate
+	aecCfg.AlignPlayback = appConfig.Audio.InPipe.AEC.AlignPlayback && !duplexReference
+	aecCfg.GateAttenuationDb = appConfig.Audio.InPipe.AEC.GateAttenuationDb
+	aecCfg.GateHangoverMs = appConfig.Audio.InPipe.AEC.GateHangoverMs
+	aecCfg.GateDoubleTalkRatio = appConfig.Audio.InPipe.AEC.GateDoubleTalkRatio
+
+	// ASR 需要单声道：设备为多声道时按 mic_array 合并，未配置时下混或按配置选取声道
+	if sourceChannels > 1 && appConfig.Audio.InPipe.MicArray.Strategy != "" {
+		logging.Infof("Combining %d input channels as a mic array (strategy=%s)",
+			sourceChannels, appConfig.Audio.InPipe.MicArray.Strategy)
+		arrayMic, err := audio.NewArrayMicSource(audioSource, sourceChannels, buildMultiMicConfig(appConfig.Audio.InPipe.MicArray, sourceRate))
+		if err != nil {
+			return nil, nil, fmt.Errorf("create mic array source: %w", err)
+		}
+		audioSource = arrayMic
+		inPipeCfg.Channels = 1
+	} else if sourceChannels > 1 {
+		logging.Infof("Mapping %d input channels to mono (channel_select=%d)",
+			sourceChannels, appConfig.Audio.InPipe.ChannelSelect)
+		audioSource = audio.NewChannelMapSource(audioSource, sourceChannels, appConfig.Audio.InPipe.ChannelSelect)
+		inPipeCfg.Channels = 1
+	}
+
+	// 设备不支持 ASR 采样率（常见于只支持 44.1/48kHz 的蓝牙/USB 设备）时透明重采样
+	if sourceRate != inPipeCfg.SampleRate {
+		logging.Infof("Resampling input from %d Hz to %d Hz", sourceRate, inPipeCfg.SampleRate)
+		audioSource = audio.NewResamplingSource(audioSource, sourceRate, inPipeCfg.SampleRate, inPipeCfg.Channels, nil)
+	}
+
+	dspCfg := buildInputDSPConfig(appConfig.Audio.InPipe.DSP)
+	if dspCfg.Enabled() {
+		logging.Infof("Input DSP enabled (high_pass=%v, agc=%v, noise_suppression=%v)",
+			dspCfg.HighPass.Enabled, dspCfg.AGC.Enabled, dspCfg.NoiseSuppression.Enabled)
+		audioSource = audio.NewInputDSPSource(audioSource, dspCfg, inPipeCfg.SampleRate, inPipeCfg.Channels)
+	}
 
-	audioSource := audio.AudioSource(micSource)
 	if aecCfg.Enabled {
 		frameBytes := audio.FrameBytes(inPipeCfg.SampleRate, inPipeCfg.Channels, aecCfg.FrameMs)
 		delayFrames := 0
@@ -187,9 +1049,15 @@ func main() {
 		}
 		referenceBuffer := audio.NewReferenceBuffer(frameBytes, 200, delayFrames)
 		referenceBuffer.SetActiveWindow(time.Duration(aecCfg.ReferenceActiveWindowMs) * time.Millisecond)
-		audioOutPipe.SetReferenceSink(referenceBuffer)
+		referenceBuffer.SetFrameDuration(time.Duration(aecCfg.FrameMs) * time.Millisecond)
+		if duplexReference {
+			// 全双工流（或浏览器音频）回调中直接写入实际播放的音频，参考信号与麦克风输入严格对齐
+			duplex.SetReferenceSink(referenceBuffer)
+		} else {
+			audioOutPipe.SetReferenceSink(referenceBuffer)
+		}
 		audioSource = audio.NewEchoCancellingSource(
-			micSource,
+			audioSource,
 			aecCfg,
 			referenceBuffer,
 			audio.NewNoopEchoCanceller(),
@@ -198,67 +1066,571 @@ func main() {
 		)
 	}
 
-	audioInPipe, err := audio.NewInPipeWithAudioSource(appConfig.ASR.APIKey, inPipeCfg, audioSource)
+	// 录制的是经过全部处理、实际送入 ASR 的音频
+	recorder, err := openDebugRecorder(appConfig.Audio.DebugRecord, "mic", inPipeCfg.SampleRate, inPipeCfg.Channels)
 	if err != nil {
-		logging.Fatalf("Failed to create AudioInPipe: %v", err)
+		return nil, nil, err
+	}
+	if recorder != nil {
+		audioSource = audio.NewRecordingSource(audioSource, recorder)
 	}
-	logging.Infof("AudioInPipe created successfully")
 
-	logging.Infof("Creating ToolExecutor and registering tools...")
-	toolExecutor := tools.NewToolExecutor()
-	toolExecutor.RegisterTool("getTime", tools.GetTimeTool)
-	toolExecutor.RegisterTool("getWeather", tools.GetWeatherTool)
-	logging.Infof("Tools registered successfully")
+	return audioSource, inPipeCfg, nil
+}
 
-	logging.Infof("Creating Orchestrator...")
-	orchestrator := voicebot.NewOrchestrator(voiceAgent, audioOutPipe, audioInPipe, toolExecutor)
-	logging.Infof("Orchestrator created successfully")
+// debugRecordTime 本次启动的调试录音文件时间戳，麦克风与输出录音共用
+var debugRecordTime = time.Now().Format("20060102-150405")
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// openDebugRecorder 在 audio.debug_record.dir 下创建 <name>-<启动时间>.wav，未开启调试录音时返回 nil
+func openDebugRecorder(cfg config.DebugRecordConfig, name string, sampleRate, channels int) (*audio.WAVRecorder, error) {
+	if !cfg.Enable {
+		return nil, nil
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create debug record dir: %w", err)
+	}
+	recorder, err := audio.NewWAVRecorder(filepath.Join(cfg.Dir, fmt.Sprintf("%s-%s.wav", name, debugRecordTime)), sampleRate, channels)
+	if err != nil {
+		return nil, fmt.Errorf("open debug recording: %w", err)
+	}
+	logging.Infof("Recording %s audio to %s", name, recorder.Path())
+	return recorder, nil
+}
 
-	logging.Infof("Setting up signal handler...")
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+// buildRealtimeSession 创建实时语音会话，工具和提示词与文本 Agent 共用配置；
+// 未配置 llm.system_prompt 时使用不要求情绪标签的实时指令模板
+func buildRealtimeSession(appConfig *config.AppConfig, agentCfg agent.Config, executor tools.ToolExecutor) (*realtime.Session, error) {
+	prompt := agentCfg.Prompt
+	if prompt.SystemPrompt == "" {
+		prompt.SystemPrompt = realtime.DefaultInstructions
+	}
+	cfg := appConfig.Realtime
+	return realtime.NewSession(realtime.Config{
+		Provider:           cfg.Provider,
+		URL:                cfg.URL,
+		APIKey:             cfg.APIKey,
+		Model:              cfg.Model,
+		Voice:              cfg.Voice,
+		Instructions:       agent.NewPromptBuilder(prompt).Build(),
+		TranscriptionModel: cfg.TranscriptionModel,
+		SampleRate:         cfg.SampleRate,
+		ClipDuration:       time.Duration(cfg.ClipMs) * time.Millisecond,
+		Tools:              agentCfg.Tools,
+		ToolTypes:          agentCfg.ToolTypes,
+		Executor:           executor,
+	})
+}
 
-	go func() {
-		<-sigCh
-		logging.Infof("\n========================================")
-		logging.Infof("     Received interrupt signal...       ")
-		logging.Infof("========================================")
+// buildPromptConfig 将配置文件中的提示词设置转换为 agent.PromptConfig
+func buildPromptConfig(cfg config.LLMConfig, toolTypes map[string]agent.ToolType) agent.PromptConfig {
+	prompt := agent.PromptConfig{
+		SystemPrompt: cfg.SystemPrompt,
+		Persona:      cfg.Persona,
+		Language:     cfg.Language,
+		Variables:    cfg.PromptVariables,
+	}
+	if len(cfg.ToolDescriptions) > 0 {
+		for name, description := range cfg.ToolDescriptions {
+			prompt.Tools = append(prompt.Tools, agent.ToolInfo{
+				Name:        name,
+				Description: description,
+				Type:        toolTypes[name],
+			})
+		}
+	}
+	return prompt
+}
 
-		// 关闭顺序：从外到内，先停止依赖方，再停止被依赖方
-		// Orchestrator 依赖 Mixer，所以先停 Orchestrator
-		logging.Infof("Stopping Orchestrator...")
-		if err := orchestrator.Stop(); err != nil {
-			logging.Errorf("Error stopping orchestrator: %v", err)
+// applyModeFlags 用命令行参数覆盖对话模式与翻译语言，只给出 -target 时视为翻译模式
+func applyModeFlags(appConfig *config.AppConfig, mode, target, sourceLang string) {
+	if mode != "" {
+		appConfig.Conversation.Mode = mode
+	} else if target != "" {
+		appConfig.Conversation.Mode = config.ModeTranslate
+	}
+	if target != "" {
+		appConfig.Translation.Target = target
+	}
+	if sourceLang != "" {
+		appConfig.Translation.Source = sourceLang
+	}
+}
+
+// buildKnowledgeBase 按配置创建知识库并导入文档，向量服务未单独配置时复用 LLM 的地址和密钥
+func buildKnowledgeBase(ctx context.Context, appConfig *config.AppConfig) (*knowledge.Base, error) {
+	cfg := appConfig.Knowledge
+	base := knowledge.NewBase(knowledge.Config{
+		ChunkSize:    cfg.ChunkSize,
+		ChunkOverlap: cfg.ChunkOverlap,
+		TopK:         cfg.TopK,
+		MinScore:     cfg.MinScore,
+	}, buildEmbedder(cfg.Embedding, appConfig.LLM))
+	if err := base.IngestPaths(ctx, cfg.Paths...); err != nil {
+		return nil, err
+	}
+	return base, nil
+}
+
+// buildEmbedder 按配置创建向量服务，openai 未单独配置密钥和地址时复用 LLM 的；local 时返回 nil（使用本地哈希向量）
+func buildEmbedder(cfg config.EmbeddingConfig, llm config.LLMConfig) knowledge.Embedder {
+	if cfg.Provider != "openai" {
+		return nil
+	}
+	apiKey := cfg.APIKey
+	if apiKey == "" {
+		apiKey = llm.APIKey
+	}
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = llm.BaseURL
+	}
+	if baseURL == "" {
+		baseURL = agent.DefaultEndpoint(llm.Provider).BaseURL
+	}
+	return knowledge.NewOpenAIEmbedder(apiKey, baseURL, cfg.Model)
+}
+
+// buildToolSelector 开启 tools.retrieval 时创建基于检索的工具选择器，否则返回 nil（由 llm.intent_router 决定）
+func buildToolSelector(appConfig *config.AppConfig) agent.ToolSelector {
+	cfg := appConfig.Tools.Retrieval
+	if !cfg.Enable {
+		return nil
+	}
+	logging.Infof("Tool retrieval enabled: top_k=%d, min_score=%.2f, always=%v, embedding=%s, shadow=%v",
+		cfg.TopK, cfg.MinScore, cfg.Always, cfg.Embedding.Provider, cfg.Shadow)
+	return agent.NewToolRetriever(agent.ToolRetrieverConfig{
+		TopK:     cfg.TopK,
+		MinScore: cfg.MinScore,
+		Always:   cfg.Always,
+		Embedder: buildEmbedder(cfg.Embedding, appConfig.LLM),
+	})
+}
+
+// buildSpeakerIdentifier 加载声纹库并创建说话人识别器
+func buildSpeakerIdentifier(cfg config.SpeakerConfig) (*speaker.Identifier, error) {
+	store, err := speaker.LoadStore(cfg.Voiceprints)
+	if err != nil {
+		return nil, err
+	}
+	speakers := store.List()
+	if len(speakers) == 0 {
+		logging.Warnf("No voiceprints enrolled in %s, every speaker is unknown (enroll with: go run ./cmd/speaker enroll)", cfg.Voiceprints)
+	} else {
+		logging.Infof("Speaker identification enabled with %d enrolled speaker(s)", len(speakers))
+	}
+	return speaker.NewIdentifier(store, cfg.Threshold), nil
+}
+
+// buildContentPolicy 加载敏感词表并编译过滤规则，未开启时返回空策略
+func buildContentPolicy(cfg config.ContentFilterConfig) (voicebot.ContentPolicy, error) {
+	if !cfg.Enable {
+		return voicebot.ContentPolicy{}, nil
+	}
+	words := append([]string(nil), cfg.Words...)
+	if cfg.WordsFile != "" {
+		fileWords, err := text.LoadWordList(cfg.WordsFile)
+		if err != nil {
+			return voicebot.ContentPolicy{}, err
 		}
+		words = append(words, fileWords...)
+	}
+	filter, err := text.NewContentFilter(text.ContentFilterConfig{
+		Words:    words,
+		Patterns: cfg.Patterns,
+		Mask:     cfg.Mask,
+	})
+	if err != nil {
+		return voicebot.ContentPolicy{}, err
+	}
+	logging.Infof("Content filter enabled with %d word(s) and %d pattern(s) (input: %s, output: %s)",
+		len(words), len(cfg.Patterns), cfg.InputAction, cfg.OutputAction)
+	return voicebot.ContentPolicy{
+		Filter: filter,
+		Input:  contentAction(cfg.InputAction),
+		Output: contentAction(cfg.OutputAction),
+	}, nil
+}
 
-		logging.Infof("Stopping Mixer...")
-		mixer.Stop()
+func contentAction(action string) voicebot.ContentAction {
+	switch action {
+	case config.FilterActionMask:
+		return voicebot.ContentActionMask
+	case config.FilterActionReject:
+		return voicebot.ContentActionReject
+	default:
+		return voicebot.ContentActionNone
+	}
+}
 
-		// 取消 context，让 main 函数自然退出
-		// 不使用 os.Exit(0)，这样 defer 语句（如 portaudio.Terminate()）才会被执行
-		cancel()
-	}()
+// buildCommandPolicy 配置中列出的命令覆盖默认话术
+func buildCommandPolicy(cfg config.CommandsConfig) voicebot.CommandPolicy {
+	if !cfg.Enable {
+		return voicebot.CommandPolicy{}
+	}
+	policy := voicebot.DefaultCommandPolicy()
+	for command, phrases := range cfg.Phrases {
+		policy.Phrases[voicebot.ControlCommand(command)] = phrases
+	}
+	if cfg.VolumeStep > 0 {
+		policy.VolumeStep = cfg.VolumeStep
+	}
+	return policy
+}
 
-	logging.Infof("Starting Orchestrator...")
-	if err := orchestrator.Start(ctx); err != nil {
-		logging.Fatalf("Failed to start orchestrator: %v", err)
+// buildConfirmationPolicy 将配置文件中的动作确认配置转换为 voicebot.ConfirmationPolicy，未设置的回答话术使用默认值
+func buildConfirmationPolicy(cfg config.ToolConfirmationConfig) voicebot.ConfirmationPolicy {
+	policy := voicebot.DefaultConfirmationPolicy()
+	policy.Tools = cfg.Tools
+	if len(cfg.YesPhrases) > 0 {
+		policy.YesPhrases = cfg.YesPhrases
+	}
+	if len(cfg.NoPhrases) > 0 {
+		policy.NoPhrases = cfg.NoPhrases
 	}
+	policy.Timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
+	policy.ConfirmedText = cfg.ConfirmedText
+	policy.CancelledText = cfg.CancelledText
+	policy.DryRun = cfg.DryRun
+	return policy
+}
 
-	logging.Infof("========================================")
-	logging.Infof("     VoiceBot is Running! 🎤          ")
-	logging.Infof("     Press Ctrl+C to stop.             ")
-	logging.Infof("========================================")
+// buildSlotFillingPolicy 将配置文件中的参数追问配置转换为 voicebot.SlotFillingPolicy，未设置的取消话术使用默认值
+func buildSlotFillingPolicy(cfg config.ToolSlotFillingConfig) voicebot.SlotFillingPolicy {
+	policy := voicebot.DefaultSlotFillingPolicy()
+	policy.Enable = cfg.Enable
+	policy.Timeout = time.Duration(cfg.TimeoutMs) * time.Millisecond
+	policy.MaxAnswerChars = cfg.MaxAnswerChars
+	if len(cfg.CancelPhrases) > 0 {
+		policy.CancelPhrases = cfg.CancelPhrases
+	}
+	policy.FilledText = cfg.FilledText
+	policy.CancelledText = cfg.CancelledText
+	return policy
+}
 
-	// Wait for context cancellation (triggered by signal handler)
-	<-ctx.Done()
+// actionResponses 返回动作类工具的固定回复；需要确认的工具由 Orchestrator 播报确认话术，不再播报“正在处理”类回复
+func actionResponses(cfg config.ToolsConfig) map[string]string {
+	if len(cfg.Confirmation.Tools) == 0 {
+		return cfg.ActionResponses
+	}
+	responses := make(map[string]string, len(cfg.ActionResponses)+len(cfg.Confirmation.Tools))
+	for tool, response := range cfg.ActionResponses {
+		responses[tool] = response
+	}
+	for tool := range cfg.Confirmation.Tools {
+		responses[tool] = ""
+	}
+	return responses
+}
 
-	logging.Infof("\n========================================")
-	logging.Infof("     VoiceBot Shutting Down...          ")
-	logging.Infof("========================================")
+// buildLLMFallbacks 将配置文件中的备用 LLM 转换为 agent.LLMEndpoint
+func buildLLMFallbacks(endpoints []config.LLMEndpoint) []agent.LLMEndpoint {
+	fallbacks := make([]agent.LLMEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		fallbacks = append(fallbacks, agent.LLMEndpoint{
+			Name:     endpoint.Name,
+			Provider: endpoint.Provider,
+			APIKey:   endpoint.APIKey,
+			BaseURL:  endpoint.BaseURL,
+			Model:    endpoint.Model,
+		})
+	}
+	return fallbacks
+}
 
-	// PortAudio 会在 defer portaudio.Terminate() 中被清理
-	logging.Infof("VoiceBot stopped.")
+// buildToolParameters 将配置声明的工具参数转换为 tools.Parameter，类型为空时为 string
+func buildToolParameters(params map[string]config.ToolParameterConfig) map[string]tools.Parameter {
+	parameters := make(map[string]tools.Parameter, len(params))
+	for name, param := range params {
+		paramType := param.Type
+		if paramType == "" {
+			paramType = "string"
+		}
+		parameters[name] = tools.Parameter{
+			Type:        paramType,
+			Description: param.Description,
+			Required:    param.Required,
+			Enum:        param.Enum,
+			Ask:         param.Ask,
+		}
+	}
+	return parameters
+}
+
+// buildHTTPToolConfig 将 tools.http 中的一项转换为 tools.HTTPToolConfig
+func buildHTTPToolConfig(cfg config.HTTPToolConfig) tools.HTTPToolConfig {
+	return tools.HTTPToolConfig{
+		Definition: tools.ToolDefinition{
+			Name:        cfg.Name,
+			Description: cfg.Description,
+			Action:      cfg.Action,
+			Parameters:  buildToolParameters(cfg.Parameters),
+		},
+		Method:  cfg.Method,
+		URL:     cfg.URL,
+		Headers: cfg.Headers,
+		Body:    cfg.Body,
+		Result:  cfg.Result,
+		Timeout: time.Duration(cfg.TimeoutMs) * time.Millisecond,
+	}
+}
+
+// buildShellToolConfig 将 tools.shell.tools 中的一项转换为 tools.ShellToolConfig
+func buildShellToolConfig(cfg config.ShellToolConfig) tools.ShellToolConfig {
+	return tools.ShellToolConfig{
+		Definition: tools.ToolDefinition{
+			Name:        cfg.Name,
+			Description: cfg.Description,
+			Action:      cfg.Action,
+			Parameters:  buildToolParameters(cfg.Parameters),
+		},
+		Command:   cfg.Command,
+		Dir:       cfg.Dir,
+		Timeout:   time.Duration(cfg.TimeoutMs) * time.Millisecond,
+		Env:       cfg.Env,
+		MaxOutput: cfg.MaxOutputBytes,
+	}
+}
+
+// buildCalendarToolConfig 按 calendar.provider 创建日历后端，时区为空时使用本机时区
+func buildCalendarToolConfig(cfg config.CalendarConfig) (tools.CalendarToolConfig, error) {
+	location := time.Local
+	if cfg.Timezone != "" {
+		loaded, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return tools.CalendarToolConfig{}, err
+		}
+		location = loaded
+	}
+	var backend calendar.Calendar
+	var err error
+	switch cfg.Provider {
+	case "google":
+		backend, err = calendar.NewGoogle(calendar.GoogleConfig{
+			CalendarID:   cfg.Google.CalendarID,
+			ClientID:     cfg.Google.ClientID,
+			ClientSecret: cfg.Google.ClientSecret,
+			RefreshToken: cfg.Google.RefreshToken,
+			Location:     location,
+		})
+	default:
+		backend, err = calendar.NewCalDAV(calendar.CalDAVConfig{
+			URL:      cfg.CalDAV.URL,
+			Username: cfg.CalDAV.Username,
+			Password: cfg.CalDAV.Password,
+			Location: location,
+		})
+	}
+	if err != nil {
+		return tools.CalendarToolConfig{}, err
+	}
+	return tools.CalendarToolConfig{
+		Calendar:        backend,
+		Location:        location,
+		DefaultDuration: time.Duration(cfg.DefaultDurationMinutes) * time.Minute,
+	}, nil
+}
+
+// buildNewsReader 创建新闻源读取器，同时返回新闻源名称（作为 getNews 的 source 可选值）
+func buildNewsReader(cfg config.NewsConfig) (*news.Reader, []string) {
+	feeds := make([]news.Feed, 0, len(cfg.Feeds))
+	for _, feed := range cfg.Feeds {
+		feeds = append(feeds, news.Feed{Name: feed.Name, URL: feed.URL})
+	}
+	reader := news.NewReader(news.Config{
+		Feeds:        feeds,
+		CacheTTL:     time.Duration(cfg.CacheMinutes) * time.Minute,
+		SummaryChars: cfg.SummaryChars,
+	})
+	return reader, reader.FeedNames()
+}
+
+// buildRoutines 解析 routines 中的 cron 表达式
+func buildRoutines(cfgs []config.RoutineConfig) ([]routine.Routine, error) {
+	routines := make([]routine.Routine, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		schedule, err := routine.ParseSchedule(cfg.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("routines[%s]: %w", cfg.Name, err)
+		}
+		routines = append(routines, routine.Routine{
+			Name:     cfg.Name,
+			Schedule: schedule,
+			Text:     cfg.Text,
+			Announce: cfg.Action == config.RoutineActionSay,
+		})
+	}
+	return routines, nil
+}
+
+// buildIntentRouterConfig 将 llm.intent_router 转换为 agent.IntentRouterConfig，未填写的端点字段由 Agent 沿用主 LLM
+func buildIntentRouterConfig(cfg config.IntentRouterConfig) agent.IntentRouterConfig {
+	return agent.IntentRouterConfig{
+		Enable: cfg.Enable,
+		Endpoint: agent.LLMEndpoint{
+			Provider: cfg.Provider,
+			APIKey:   cfg.APIKey,
+			BaseURL:  cfg.BaseURL,
+			Model:    cfg.Model,
+		},
+		MaxTools: cfg.MaxTools,
+		Timeout:  time.Duration(cfg.TimeoutMs) * time.Millisecond,
+	}
+}
+
+// buildInputDSPConfig 将配置文件中的输入处理配置转换为 audio.InputDSPConfig，未设置的字段使用默认值
+// openDuplexStream 打开并启动全双工流，由流回调驱动 Mixer 渲染
+func openDuplexStream(appConfig *config.AppConfig, mixerCfg *audio.MixerConfig, mixer audio.AudioMixer) (*audio.DuplexStream, error) {
+	renderer, ok := mixer.(audio.AudioRenderer)
+	if !ok {
+		return nil, fmt.Errorf("mixer %T does not support external stream", mixer)
+	}
+
+	duplexCfg := audio.DefaultDuplexConfig()
+	// 采样率跟随 Mixer 输出，输入侧如与 ASR 采样率不同再重采样
+	if mixerCfg.SampleRate > 0 {
+		duplexCfg.SampleRate = mixerCfg.SampleRate
+	}
+	duplexCfg.InputChannels = appConfig.Audio.InPipe.InputChannels
+	if duplexCfg.InputChannels <= 0 {
+		duplexCfg.InputChannels = appConfig.Audio.InPipe.Channels
+	}
+	if appConfig.Audio.InPipe.BufferSize > 0 {
+		duplexCfg.ReadFrames = appConfig.Audio.InPipe.BufferSize
+	}
+
+	duplex, err := audio.NewDuplexStream(duplexCfg, renderer)
+	if err != nil {
+		return nil, err
+	}
+	if err := duplex.Start(); err != nil {
+		duplex.Close()
+		return nil, err
+	}
+	return duplex, nil
+}
+
+// openRemoteStream 创建远端音频流，由网页 /audio 连接或电话通话收到的麦克风块驱动 Mixer 渲染
+func openRemoteStream(appConfig *config.AppConfig, mixerCfg *audio.MixerConfig, mixer audio.AudioMixer) (*audio.RemoteStream, error) {
+	renderer, ok := mixer.(audio.AudioRenderer)
+	if !ok {
+		return nil, fmt.Errorf("mixer %T does not support external stream", mixer)
+	}
+
+	streamCfg := audio.DefaultDuplexConfig()
+	if mixerCfg.SampleRate > 0 {
+		streamCfg.SampleRate = mixerCfg.SampleRate
+	}
+	if appConfig.Audio.InPipe.BufferSize > 0 {
+		streamCfg.ReadFrames = appConfig.Audio.InPipe.BufferSize
+	}
+	return audio.NewRemoteStream(streamCfg, renderer)
+}
+
+// openMicrophone 通过 audio.backend 以 audio.in_pipe.sample_format 打开麦克风
+func openMicrophone(appConfig *config.AppConfig, device string, sampleRate, channels, bufferSize int) (*source.MicrophoneSource, error) {
+	backend, err := audio.NewAudioBackend(appConfig.Audio.Backend)
+	if err != nil {
+		return nil, err
+	}
+	format, err := audio.ParseSampleFormat(appConfig.Audio.InPipe.SampleFormat)
+	if err != nil {
+		return nil, err
+	}
+	return source.NewMicrophoneSourceWithBackend(backend, audio.StreamConfig{
+		Device:          device,
+		SampleRate:      sampleRate,
+		Channels:        channels,
+		FramesPerBuffer: bufferSize,
+		HighLatency:     appConfig.Audio.InPipe.HighLatency,
+		Format:          format,
+	})
+}
+
+// buildMultiMicSource 打开多个输入设备，各自映射为单声道并重采样到 sampleRate 后按 mic_array 合并
+func buildMultiMicSource(appConfig *config.AppConfig, devices []string, sampleRate, inputChannels, bufferSize int) (audio.AudioSource, error) {
+	sources := make([]audio.AudioSource, 0, len(devices))
+	closeAll := func() {
+		for _, s := range sources {
+			s.Close()
+		}
+	}
+	for _, device := range devices {
+		logging.Infof("Creating Microphone source for mic array (bufferSize=%d, inputDevice=%q)...", bufferSize, device)
+		micSource, err := openMicrophone(appConfig, device, sampleRate, inputChannels, bufferSize)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("create microphone source %q: %w", device, err)
+		}
+		var src audio.AudioSource = micSource
+		if micSource.Channels() > 1 {
+			src = audio.NewChannelMapSource(src, micSource.Channels(), appConfig.Audio.InPipe.ChannelSelect)
+		}
+		if micSource.SampleRate() != sampleRate {
+			src = audio.NewResamplingSource(src, micSource.SampleRate(), sampleRate, 1, nil)
+		}
+		sources = append(sources, src)
+	}
+
+	cfg := appConfig.Audio.InPipe.MicArray
+	if cfg.Strategy == "" {
+		cfg.Strategy = string(audio.MultiMicSelect)
+	}
+	logging.Infof("Combining %d input devices (strategy=%s)", len(sources), cfg.Strategy)
+	multiMic, err := audio.NewMultiMicSource(sources, buildMultiMicConfig(cfg, sampleRate))
+	if err != nil {
+		closeAll()
+		return nil, fmt.Errorf("create multi mic source: %w", err)
+	}
+	return multiMic, nil
+}
+
+func buildMultiMicConfig(cfg config.MicArrayConfig, sampleRate int) audio.MultiMicConfig {
+	multiMicCfg := audio.DefaultMultiMicConfig()
+	multiMicCfg.Strategy = audio.MultiMicStrategy(cfg.Strategy)
+	multiMicCfg.SampleRate = sampleRate
+	if cfg.SpeechSNRDb > 0 {
+		multiMicCfg.SpeechSNRDb = cfg.SpeechSNRDb
+	}
+	if cfg.HangoverMs > 0 {
+		multiMicCfg.HangoverMs = cfg.HangoverMs
+	}
+	if cfg.MaxDelayMs > 0 {
+		multiMicCfg.MaxDelayMs = cfg.MaxDelayMs
+	}
+	return multiMicCfg
+}
+
+func buildInputDSPConfig(cfg config.DSPConfig) audio.InputDSPConfig {
+	dspCfg := audio.DefaultInputDSPConfig()
+	dspCfg.HighPass.Enabled = cfg.HighPass.Enable
+	if cfg.HighPass.CutoffHz > 0 {
+		dspCfg.HighPass.CutoffHz = cfg.HighPass.CutoffHz
+	}
+	if cfg.HighPass.Q > 0 {
+		dspCfg.HighPass.Q = cfg.HighPass.Q
+	}
+
+	dspCfg.AGC.Enabled = cfg.AGC.Enable
+	if cfg.AGC.TargetRMS > 0 {
+		dspCfg.AGC.TargetRMS = cfg.AGC.TargetRMS
+	}
+	if cfg.AGC.MaxGain > 0 {
+		dspCfg.AGC.MaxGain = cfg.AGC.MaxGain
+	}
+	if cfg.AGC.AttackMs > 0 {
+		dspCfg.AGC.AttackMs = cfg.AGC.AttackMs
+	}
+	if cfg.AGC.ReleaseMs > 0 {
+		dspCfg.AGC.ReleaseMs = cfg.AGC.ReleaseMs
+	}
+
+	dspCfg.NoiseSuppression.Enabled = cfg.NoiseSuppression.Enable
+	if cfg.NoiseSuppression.Strength > 0 {
+		dspCfg.NoiseSuppression.Strength = cfg.NoiseSuppression.Strength
+	}
+	if cfg.NoiseSuppression.Floor > 0 {
+		dspCfg.NoiseSuppression.Floor = cfg.NoiseSuppression.Floor
+	}
+	return dspCfg
 }
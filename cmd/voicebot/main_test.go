@@ -2,10 +2,18 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/liuscraft/orion-x/internal/agent"
 	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/config"
 	"github.com/liuscraft/orion-x/internal/tools"
 	"github.com/liuscraft/orion-x/internal/voicebot"
 )
@@ -52,3 +60,309 @@ func TestVoiceBotIntegration(t *testing.T) {
 		t.Errorf("Expected state Idle, got %s", state)
 	}
 }
+
+func TestApplyModeFlags(t *testing.T) {
+	tests := []struct {
+		name                     string
+		mode, target, sourceLang string
+		wantMode, wantTarget     string
+		wantSource               string
+	}{
+		{"mode and target", "translate", "en", "", config.ModeTranslate, "en", ""},
+		{"target implies translate", "", "ja", "zh", config.ModeTranslate, "ja", "zh"},
+		{"explicit assistant", "assistant", "", "", config.ModeAssistant, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			applyModeFlags(cfg, tt.mode, tt.target, tt.sourceLang)
+			if cfg.Conversation.Mode != tt.wantMode || cfg.Translation.Target != tt.wantTarget || cfg.Translation.Source != tt.wantSource {
+				t.Errorf("got mode=%s target=%s source=%s", cfg.Conversation.Mode, cfg.Translation.Target, cfg.Translation.Source)
+			}
+		})
+	}
+}
+
+func TestTextConsole(t *testing.T) {
+	var out strings.Builder
+	console := newTextConsole(&out)
+
+	var submitted []string
+	input := "你好\n\n  现在几点  \n/quit\n不会被提交\n"
+	if err := console.Run(strings.NewReader(input), func(text string) error {
+		submitted = append(submitted, text)
+		return nil
+	}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := []string{"你好", "现在几点"}; !reflect.DeepEqual(submitted, want) {
+		t.Errorf("submitted = %q, want %q", submitted, want)
+	}
+
+	out.Reset()
+	console.OnReplyText("现在")
+	console.OnToolResult(voicebot.NewToolResultEvent("getTime", map[string]interface{}{"zone": "UTC"}, "10:00", 12*time.Millisecond, nil))
+	console.OnReplyText("是十点。")
+	console.OnReplyFinished()
+	want := "bot: 现在\n[tool] getTime({\"zone\":\"UTC\"}) -> \"10:00\" (12ms)\nbot: 是十点。\n> "
+	if out.String() != want {
+		t.Errorf("output = %q, want %q", out.String(), want)
+	}
+}
+
+type fakeMic struct{ muted []bool }
+
+func (m *fakeMic) MicMuted() bool { return len(m.muted) > 0 && m.muted[len(m.muted)-1] }
+func (m *fakeMic) SetMicMuted(muted bool) bool {
+	m.muted = append(m.muted, muted)
+	return true
+}
+
+func TestRunHotkeys(t *testing.T) {
+	var out strings.Builder
+	mic := &fakeMic{}
+	if err := runHotkeys(strings.NewReader("m\n\nx\n M \n"), &out, mic); err != nil {
+		t.Fatalf("runHotkeys() error = %v", err)
+	}
+	if want := []bool{true, false}; !reflect.DeepEqual(mic.muted, want) {
+		t.Fatalf("mic switched to %v, want %v", mic.muted, want)
+	}
+	if !strings.Contains(out.String(), "Hotkeys:") {
+		t.Errorf("unknown key should print help, got %q", out.String())
+	}
+}
+
+func TestCommandIndicator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "activity.log")
+	indicator, err := startCommandIndicator([]string{"sh", "-c", "cat > " + path})
+	if err != nil {
+		t.Skipf("sh not available: %v", err)
+	}
+	indicator.OnActivity(voicebot.ActivityThinking)
+	indicator.OnActivity(voicebot.ActivitySpeakingStarted)
+	if err := indicator.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	// 关闭后不再写入
+	indicator.OnActivity(voicebot.ActivityIdle)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if got := string(data); got != "thinking\nspeaking_started\n" {
+		t.Fatalf("indicator received %q", got)
+	}
+}
+
+type fakeDashboardSource struct{ stats voicebot.UsageStats }
+
+func (s fakeDashboardSource) GetState() voicebot.State   { return voicebot.StateSpeaking }
+func (s fakeDashboardSource) Stats() voicebot.UsageStats { return s.stats }
+
+func TestDashboardRender(t *testing.T) {
+	dash := newDashboard(io.Discard, nil)
+	dash.source = fakeDashboardSource{stats: voicebot.UsageStats{
+		Turns:      2,
+		MicMuted:   true,
+		InputLevel: voicebot.LevelStats{Current: audio.AudioLevel{RMS: 0.1, Peak: 0.4}},
+	}}
+	dash.pipeline = func() audio.PipelineStats { return audio.PipelineStats{TextQueueSize: 3, IsPlaying: true} }
+
+	for i := 0; i < dashboardLogLines+2; i++ {
+		fmt.Fprintf(dash, "line %d\n", i)
+	}
+	fmt.Fprint(dash, "partial")
+	dash.OnEvent(voicebot.NewPartialTranscriptEvent("今天天", time.Now()))
+	dash.OnReplyText("第一轮")
+	dash.OnReplyFinished()
+	dash.OnReplyText("今天晴，")
+	dash.OnReplyText("二十度。")
+
+	frame := dash.render()
+	for _, want := range []string{"state: Speaking", "mic: MUTED", "-20 dBFS", "queue 3 text", "playing", "turns 2", "… 今天天", "bot: 今天晴，二十度。", "line 11"} {
+		if !strings.Contains(frame, want) {
+			t.Errorf("frame missing %q:\n%s", want, frame)
+		}
+	}
+	for _, unwanted := range []string{"line 1\n", "partial", "第一轮"} {
+		if strings.Contains(frame, unwanted) {
+			t.Errorf("frame should not contain %q:\n%s", unwanted, frame)
+		}
+	}
+
+	dash.OnEvent(voicebot.NewASRFinalEvent("今天天气怎么样"))
+	if frame := dash.render(); !strings.Contains(frame, "you: 今天天气怎么样") || strings.Contains(frame, "…") {
+		t.Errorf("final transcript should replace the partial one:\n%s", frame)
+	}
+}
+
+func TestLevelMeter(t *testing.T) {
+	tests := []struct {
+		rms    float64
+		filled int
+	}{
+		{0, 0},
+		{0.0001, 0},
+		{0.1, 16},
+		{1, dashboardMeterLen},
+	}
+	for _, tt := range tests {
+		meter := levelMeter(audio.AudioLevel{RMS: tt.rms})
+		if got := strings.Count(meter, "█"); got != tt.filled {
+			t.Errorf("levelMeter(rms=%v) = %q, filled %d, want %d", tt.rms, meter, got, tt.filled)
+		}
+	}
+}
+
+// constantLevels 生成 n 帧相同 RMS 的电平统计
+func constantLevels(n int, rms, peak float64) calibrationLevels {
+	levels := calibrationLevels{Peak: peak}
+	for i := 0; i < n; i++ {
+		levels.FrameRMS = append(levels.FrameRMS, rms)
+	}
+	return levels
+}
+
+func TestComputeCalibration(t *testing.T) {
+	noise := constantLevels(30, 0.004, 0.02)
+	// 说话中夹杂停顿：一半帧为底噪
+	speech := constantLevels(25, 0.064, 0.4)
+	speech.FrameRMS = append(speech.FrameRMS, constantLevels(25, 0.004, 0).FrameRMS...)
+
+	result, err := computeCalibration(noise, speech, false, 10)
+	if err != nil {
+		t.Fatalf("computeCalibration() error = %v", err)
+	}
+	if result.NoiseRMS != 0.004 || result.SpeechRMS != 0.064 {
+		t.Errorf("levels = %v / %v, want 0.004 / 0.064", result.NoiseRMS, result.SpeechRMS)
+	}
+	if result.VADThreshold != 0.016 {
+		t.Errorf("VADThreshold = %v, want 0.016 (geometric mean of noise and speech)", result.VADThreshold)
+	}
+	if result.AGCTarget != 0.144 {
+		t.Errorf("AGCTarget = %v, want 0.144 (speech peak kept under the limiter knee)", result.AGCTarget)
+	}
+
+	// AGC 开启时阈值按增益 0.144/0.064 折算
+	withAGC, err := computeCalibration(noise, speech, true, 10)
+	if err != nil {
+		t.Fatalf("computeCalibration() error = %v", err)
+	}
+	if withAGC.VADThreshold != 0.036 {
+		t.Errorf("VADThreshold with AGC = %v, want 0.036", withAGC.VADThreshold)
+	}
+
+	if _, err := computeCalibration(noise, constantLevels(50, 0.005, 0.02), false, 10); err == nil {
+		t.Error("expected error when speech is not louder than the room noise")
+	}
+	if _, err := computeCalibration(calibrationLevels{}, speech, false, 10); err == nil {
+		t.Error("expected error without captured audio")
+	}
+}
+
+func TestMeasureLevels(t *testing.T) {
+	// 4 帧，每帧 2 个采样：满幅正负交替 → RMS 0.5
+	pcm := []byte{0x00, 0x40, 0x00, 0xC0}
+	src := &repeatingSource{chunk: pcm, remaining: 4}
+	levels, err := measureLevels(context.Background(), src, 2, time.Second)
+	if err != nil {
+		t.Fatalf("measureLevels() error = %v", err)
+	}
+	if len(levels.FrameRMS) != 4 || levels.FrameRMS[0] != 0.5 || levels.Peak != 0.5 {
+		t.Errorf("levels = %+v, want 4 frames at 0.5 RMS, peak 0.5", levels)
+	}
+}
+
+// repeatingSource 返回 remaining 次 chunk，之后阻塞到 ctx 结束
+type repeatingSource struct {
+	chunk     []byte
+	remaining int
+}
+
+func (s *repeatingSource) Read(ctx context.Context) ([]byte, error) {
+	if s.remaining > 0 {
+		s.remaining--
+		return s.chunk, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (s *repeatingSource) Close() error { return nil }
+
+func TestPrintAuditRecords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	log, err := tools.OpenAuditLog(tools.AuditConfig{Path: path})
+	if err != nil {
+		t.Fatalf("OpenAuditLog() error = %v", err)
+	}
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.Local)
+	log.Record(tools.AuditRecord{Time: at, Session: "s1", Turn: 2, Tool: "setVolume", Args: map[string]interface{}{"level": 50}, DurationMs: 3, Result: "ok"})
+	log.Record(tools.AuditRecord{Time: at, Session: "s2", Tool: "getWeather", Error: "timeout"})
+	log.Close()
+
+	tests := []struct {
+		session string
+		want    string
+	}{
+		{"s1", "2026-01-02 03:04:05 s1 turn=2 setVolume {\"level\":50} 3ms -> ok\n"},
+		{"all", "2026-01-02 03:04:05 s1 turn=2 setVolume {\"level\":50} 3ms -> ok\n" +
+			"2026-01-02 03:04:05 s2 turn=0 getWeather null 0ms error: timeout\n"},
+		{"s3", fmt.Sprintf("no tool audit records for session \"s3\" in %s\n", path)},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		if err := printAuditRecords(&out, path, tt.session); err != nil {
+			t.Fatalf("printAuditRecords(%s) error = %v", tt.session, err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("printAuditRecords(%s) = %q, want %q", tt.session, out.String(), tt.want)
+		}
+	}
+}
+
+func TestActionResponsesSkipConfirmedTools(t *testing.T) {
+	cfg := config.DefaultConfig().Tools
+	cfg.Confirmation.Tools = map[string]string{"sendEmail": "确认发送给{{to}}吗？", "pauseMusic": ""}
+
+	got := actionResponses(cfg)
+	want := map[string]string{
+		"playMusic":  "正在为您播放{{song}}",
+		"setVolume":  "已将音量设置为{{level}}",
+		"pauseMusic": "",
+		"sendEmail":  "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("actionResponses() = %v, want %v", got, want)
+	}
+	if cfg.ActionResponses["pauseMusic"] != "音乐已暂停" {
+		t.Fatal("actionResponses() modified the config map")
+	}
+
+	policy := buildConfirmationPolicy(cfg.Confirmation)
+	if policy.Timeout != 15*time.Second || len(policy.YesPhrases) == 0 || len(policy.NoPhrases) == 0 {
+		t.Fatalf("buildConfirmationPolicy() = %+v", policy)
+	}
+
+	slots := buildSlotFillingPolicy(cfg.SlotFilling)
+	if !slots.Enable || slots.Timeout != 15*time.Second || slots.MaxAnswerChars != 15 || len(slots.CancelPhrases) == 0 {
+		t.Fatalf("buildSlotFillingPolicy() = %+v", slots)
+	}
+}
+
+func TestBuildRoutines(t *testing.T) {
+	routines, err := buildRoutines([]config.RoutineConfig{
+		{Name: "briefing", Schedule: "30 7 * * 1-5", Text: "播报早间简报"},
+		{Name: "water", Schedule: "@hourly", Text: "该喝水了", Action: config.RoutineActionSay},
+	})
+	if err != nil {
+		t.Fatalf("buildRoutines() error = %v", err)
+	}
+	if len(routines) != 2 || routines[0].Announce || !routines[1].Announce {
+		t.Fatalf("buildRoutines() = %+v", routines)
+	}
+	if _, err := buildRoutines([]config.RoutineConfig{{Name: "broken", Schedule: "every morning", Text: "x"}}); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("buildRoutines(invalid) error = %v", err)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/voicebot"
+)
+
+const textPrompt = "> "
+
+// textConsole 文本模式的终端交互：逐行读取输入交给 TextInPipe，打印流式回复和工具调用
+type textConsole struct {
+	out io.Writer
+
+	mu       sync.Mutex
+	replying bool // 当前轮是否已输出回复前缀
+}
+
+func newTextConsole(out io.Writer) *textConsole {
+	return &textConsole{out: out}
+}
+
+// OnReplyText 打印回复文本块，作为 OrchestratorConfig.OnReplyText
+func (c *textConsole) OnReplyText(chunk string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.replying {
+		fmt.Fprint(c.out, "bot: ")
+		c.replying = true
+	}
+	fmt.Fprint(c.out, chunk)
+}
+
+// OnReplyFinished 结束当前回复并重新显示输入提示符，作为 OrchestratorConfig.OnReplyFinished
+func (c *textConsole) OnReplyFinished() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.replying {
+		fmt.Fprintln(c.out)
+		c.replying = false
+	}
+	fmt.Fprint(c.out, textPrompt)
+}
+
+// OnToolResult 打印工具调用及结果，订阅 voicebot.EventTypeToolResult
+func (c *textConsole) OnToolResult(event voicebot.Event) {
+	e, ok := event.(*voicebot.ToolResultEvent)
+	if !ok {
+		return
+	}
+	args, _ := json.Marshal(e.Args)
+	line := fmt.Sprintf("[tool] %s(%s)", e.Tool, args)
+	if e.Error != nil {
+		line += fmt.Sprintf(" error: %v", e.Error)
+	} else {
+		result, err := json.Marshal(e.Result)
+		if err != nil {
+			result = []byte(fmt.Sprint(e.Result))
+		}
+		line += fmt.Sprintf(" -> %s", result)
+	}
+	line += fmt.Sprintf(" (%v)", e.Duration.Round(time.Millisecond))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.replying {
+		// 工具结果插在回复中间时另起一行，后续回复重新加前缀
+		fmt.Fprintln(c.out)
+		c.replying = false
+	}
+	fmt.Fprintln(c.out, line)
+}
+
+// Run 逐行读取输入并提交，遇到 EOF 或 /quit、/exit 时返回
+func (c *textConsole) Run(in io.Reader, submit func(text string) error) error {
+	c.mu.Lock()
+	fmt.Fprintln(c.out, "Text mode: type a message and press Enter, /quit to exit.")
+	fmt.Fprint(c.out, textPrompt)
+	c.mu.Unlock()
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch line {
+		case "":
+			c.mu.Lock()
+			fmt.Fprint(c.out, textPrompt)
+			c.mu.Unlock()
+			continue
+		case "/quit", "/exit":
+			return nil
+		}
+		if err := submit(line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,121 @@
+package agent
+
+import (
+	"strings"
+)
+
+const (
+	emotionTagPrefix = "[EMO:"
+	// maxEmotionTagLen 情绪标签最大长度，超过仍未闭合则视为普通文本
+	maxEmotionTagLen = 24
+)
+
+// EmotionToken 流式解析结果：文本片段或情绪切换，二者只会有一个非空
+type EmotionToken struct {
+	Text    string
+	Emotion string
+}
+
+// EmotionTagParser 流式情绪标签解析器
+// LLM 流式输出时 [EMO:happy] 可能被拆分到多个 chunk，解析器会暂存可能是标签前缀的内容，
+// 直到能够确定是否为标签；输出的文本中不包含情绪标签
+type EmotionTagParser struct {
+	pending string
+}
+
+func NewEmotionTagParser() *EmotionTagParser {
+	return &EmotionTagParser{}
+}
+
+// Feed 输入一段流式文本，按出现顺序返回文本片段和情绪切换
+func (p *EmotionTagParser) Feed(chunk string) []EmotionToken {
+	buf := p.pending + chunk
+	p.pending = ""
+
+	var tokens []EmotionToken
+	var text strings.Builder
+	flushText := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, EmotionToken{Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for len(buf) > 0 {
+		idx := strings.IndexByte(buf, '[')
+		if idx < 0 {
+			text.WriteString(buf)
+			break
+		}
+		text.WriteString(buf[:idx])
+		buf = buf[idx:]
+
+		emotion, consumed, complete := parseEmotionTag(buf)
+		if !complete {
+			// 可能是被拆分的标签，等待后续 chunk
+			p.pending = buf
+			break
+		}
+		if consumed == 0 {
+			// 不是情绪标签，按普通文本输出 '['
+			text.WriteByte('[')
+			buf = buf[1:]
+			continue
+		}
+		flushText()
+		tokens = append(tokens, EmotionToken{Emotion: emotion})
+		buf = buf[consumed:]
+	}
+
+	flushText()
+	return tokens
+}
+
+// Flush 输出暂存的内容（流结束时调用），未闭合的标签前缀按普通文本输出
+func (p *EmotionTagParser) Flush() string {
+	rest := p.pending
+	p.pending = ""
+	return rest
+}
+
+// parseEmotionTag 解析以 '[' 开头的 buf
+// complete 为 false 表示内容不足以判断；consumed 为 0 表示不是情绪标签
+func parseEmotionTag(buf string) (emotion string, consumed int, complete bool) {
+	if len(buf) < len(emotionTagPrefix) {
+		if strings.HasPrefix(emotionTagPrefix, buf) {
+			return "", 0, false
+		}
+		return "", 0, true
+	}
+	if !strings.HasPrefix(buf, emotionTagPrefix) {
+		return "", 0, true
+	}
+
+	end := strings.IndexByte(buf, ']')
+	if end < 0 {
+		if len(buf) >= maxEmotionTagLen {
+			return "", 0, true
+		}
+		for _, r := range buf[len(emotionTagPrefix):] {
+			if !isEmotionNameRune(r) {
+				return "", 0, true
+			}
+		}
+		return "", 0, false
+	}
+
+	name := buf[len(emotionTagPrefix):end]
+	if name == "" || end >= maxEmotionTagLen {
+		return "", 0, true
+	}
+	for _, r := range name {
+		if !isEmotionNameRune(r) {
+			return "", 0, true
+		}
+	}
+	return strings.ToLower(name), end + 1, true
+}
+
+func isEmotionNameRune(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '_' || r == '-'
+}
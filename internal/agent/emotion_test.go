@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEmotionTagParser(t *testing.T) {
+	tests := []struct {
+		name   string
+		chunks []string
+		want   []EmotionToken
+	}{
+		{
+			name:   "tag in single chunk",
+			chunks: []string{"[EMO:happy]今天天气很好"},
+			want: []EmotionToken{
+				{Emotion: "happy"},
+				{Text: "今天天气很好"},
+			},
+		},
+		{
+			name:   "tag split across chunks",
+			chunks: []string{"好的。[E", "MO:s", "ad]很抱歉"},
+			want: []EmotionToken{
+				{Text: "好的。"},
+				{Emotion: "sad"},
+				{Text: "很抱歉"},
+			},
+		},
+		{
+			name:   "tag at end of sentence",
+			chunks: []string{"太棒了 [EMO:Excited]"},
+			want: []EmotionToken{
+				{Text: "太棒了 "},
+				{Emotion: "excited"},
+			},
+		},
+		{
+			name:   "plain brackets are kept",
+			chunks: []string{"参考[1]和[E", "x]"},
+			want: []EmotionToken{
+				{Text: "参考[1]和"},
+				{Text: "[Ex]"},
+			},
+		},
+		{
+			name:   "invalid tag content is kept",
+			chunks: []string{"[EMO:开心]你好"},
+			want: []EmotionToken{
+				{Text: "[EMO:开心]你好"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parser := NewEmotionTagParser()
+			var got []EmotionToken
+			for _, chunk := range tt.chunks {
+				got = append(got, parser.Feed(chunk)...)
+			}
+			if rest := parser.Flush(); rest != "" {
+				got = append(got, EmotionToken{Text: rest})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("tokens = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmotionTagParserFlushUnclosedTag(t *testing.T) {
+	parser := NewEmotionTagParser()
+	if tokens := parser.Feed("结束[EMO:ha"); !reflect.DeepEqual(tokens, []EmotionToken{{Text: "结束"}}) {
+		t.Fatalf("tokens = %#v", tokens)
+	}
+	if rest := parser.Flush(); rest != "[EMO:ha" {
+		t.Fatalf("Flush() = %q, want %q", rest, "[EMO:ha")
+	}
+}
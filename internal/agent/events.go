@@ -1,5 +1,7 @@
 package agent
 
+import "time"
+
 // AgentEvent Agent事件
 type AgentEvent interface {
 	Type() AgentEventType
@@ -13,6 +15,12 @@ const (
 	AgentEventTypeEmotionChanged                          // 情绪变化
 	AgentEventTypeToolCallRequested                       // 工具调用请求
 	AgentEventTypeFinished                                // 完成
+	AgentEventTypeDegraded                                // 降级（已切换到备用 LLM）
+	AgentEventTypeToolResult                              // 工具执行结果
+	AgentEventTypeCitation                                // 引用来源
+	AgentEventTypeAudioChunk                              // 模型直接生成的回复语音
+	AgentEventTypeFollowUp                                // 回复以追问结尾，等待用户回答
+	AgentEventTypeMissingParam                            // 工具调用缺少必填参数
 )
 
 // TextChunkEvent 文本块事件
@@ -48,8 +56,89 @@ func (e *ToolCallRequestedEvent) Type() AgentEventType {
 // FinishedEvent 完成事件
 type FinishedEvent struct {
 	Error error
+	Usage Usage // 本次调用的 LLM 用量
 }
 
 func (e *FinishedEvent) Type() AgentEventType {
 	return AgentEventTypeFinished
 }
+
+// DegradedModeEvent 降级事件：主 LLM 不可用，本轮已切换到备用 LLM
+type DegradedModeEvent struct {
+	Provider string // 实际使用的备用 LLM 名称
+	Reason   error  // 主 LLM 的失败原因
+}
+
+func (e *DegradedModeEvent) Type() AgentEventType {
+	return AgentEventTypeDegraded
+}
+
+// ToolResultEvent 工具执行结果事件，供 UI 展示机器人做了什么
+type ToolResultEvent struct {
+	Tool     string
+	Args     map[string]interface{}
+	Result   interface{} // 工具返回的结构化结果
+	Duration time.Duration
+	Error    error
+}
+
+func (e *ToolResultEvent) Type() AgentEventType {
+	return AgentEventTypeToolResult
+}
+
+// Citation 回复引用的一条来源
+type Citation struct {
+	Title   string
+	URL     string
+	Snippet string
+}
+
+// CitationEvent 引用来源事件（如搜索类工具返回的网页）
+type CitationEvent struct {
+	Tool      string // 产生引用的工具，可为空
+	Citations []Citation
+}
+
+func (e *CitationEvent) Type() AgentEventType {
+	return AgentEventTypeCitation
+}
+
+// AudioChunkEvent 端到端语音模型直接生成的一段回复语音（Mixer 格式：单声道 16-bit PCM），
+// Text 为这段语音对应的文字（可能为空），编排器不再调用 TTS 而是直接排队播放
+type AudioChunkEvent struct {
+	Text string
+	PCM  []byte
+}
+
+func (e *AudioChunkEvent) Type() AgentEventType {
+	return AgentEventTypeAudioChunk
+}
+
+// FollowUpEvent 回复以向用户的追问结尾（如“还需要查别的城市吗？”），在 FinishedEvent 之前发出；
+// 编排器据此在播报后保持聆听，并把 Question 通过 WithFollowUp 带给下一轮
+type FollowUpEvent struct {
+	Question string
+}
+
+func (e *FollowUpEvent) Type() AgentEventType {
+	return AgentEventTypeFollowUp
+}
+
+// MissingParam 工具调用缺少的必填参数
+type MissingParam struct {
+	Name     string
+	Question string // 向用户询问该参数的话术（Parameter.Ask 或按参数说明生成）
+}
+
+// MissingParamEvent 工具调用缺少必填参数（如“天气怎么样”没有说城市），代替 ToolCallRequestedEvent 发出；
+// 编排器依次询问 Missing 中的参数，把用户的回答填入 Args 后再执行工具
+type MissingParamEvent struct {
+	Tool     string
+	Args     map[string]interface{} // LLM 已给出的参数
+	ToolType ToolType
+	Missing  []MissingParam
+}
+
+func (e *MissingParamEvent) Type() AgentEventType {
+	return AgentEventTypeMissingParam
+}
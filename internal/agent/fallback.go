@@ -0,0 +1,164 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// LLMEndpoint LLM 服务端点（用于备用 LLM 配置）
+// Provider、APIKey 为空时沿用主 LLM 的配置；BaseURL、Model 为空时，服务商与主 LLM 相同则沿用主 LLM 的配置，
+// 否则使用该服务商的默认值
+type LLMEndpoint struct {
+	Name     string
+	Provider string
+	APIKey   string
+	BaseURL  string
+	Model    string
+}
+
+// chatStreamer LLM 流式调用接口（openai.ChatModel 满足该接口，便于测试替换）
+type chatStreamer interface {
+	Stream(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error)
+}
+
+// llmProvider 一个可用的 LLM 服务
+type llmProvider struct {
+	name  string
+	model chatStreamer
+}
+
+// retryPolicy LLM 调用重试策略
+type retryPolicy struct {
+	maxRetries int           // 单个服务的瞬时错误重试次数
+	backoff    time.Duration // 重试间隔，按重试次数线性增长
+}
+
+// fallbackStream streamWithFallback 的结果
+type fallbackStream struct {
+	stream   *schema.StreamReader[*schema.Message]
+	provider int   // 成功建立流的服务下标，0 为主 LLM
+	reason   error // 切换到备用服务时，主 LLM 的失败原因
+}
+
+// streamWithFallback 依次尝试各个 LLM 服务建立流式调用
+// 瞬时错误（超时、429、5xx 等）在当前服务上重试，重试耗尽或遇到其他错误时切换到下一个服务；
+// 流建立后、收到首个文本或工具调用之前出错同样重试或切换，之后出错不再重放；
+// 所有服务都失败时返回最后一个错误
+func streamWithFallback(ctx context.Context, providers []llmProvider, policy retryPolicy, messages []*schema.Message, opts ...model.Option) (fallbackStream, error) {
+	var firstErr, lastErr error
+	for i, provider := range providers {
+		for attempt := 0; attempt <= policy.maxRetries; attempt++ {
+			if attempt > 0 {
+				select {
+				case <-ctx.Done():
+					return fallbackStream{}, ctx.Err()
+				case <-time.After(policy.backoff * time.Duration(attempt)):
+				}
+				logging.InfofCtx(ctx, "VoiceAgent: retrying LLM %s (attempt %d/%d)", provider.name, attempt, policy.maxRetries)
+			}
+
+			stream, err := provider.model.Stream(ctx, messages, opts...)
+			if err == nil {
+				stream, err = peekStream(stream)
+			}
+			if err == nil {
+				result := fallbackStream{stream: stream, provider: i}
+				if i > 0 {
+					result.reason = firstErr
+				}
+				return result, nil
+			}
+			if ctx.Err() != nil {
+				return fallbackStream{}, ctx.Err()
+			}
+
+			lastErr = fmt.Errorf("llm %s: %w", provider.name, err)
+			if firstErr == nil {
+				firstErr = lastErr
+			}
+			logging.WarnfCtx(ctx, "VoiceAgent: LLM %s stream error: %v", provider.name, err)
+			if !IsTransientError(err) {
+				break
+			}
+		}
+	}
+	if lastErr == nil {
+		lastErr = errors.New("no llm provider configured")
+	}
+	return fallbackStream{}, lastErr
+}
+
+// peekStream 读到首个文本或工具调用（或正常结束）后返回包含已读消息的新流
+// 在此之前出错时关闭原流并返回错误，调用方可以安全地重试或切换服务
+func peekStream(stream *schema.StreamReader[*schema.Message]) (*schema.StreamReader[*schema.Message], error) {
+	var head []*schema.Message
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			stream.Close()
+			return schema.StreamReaderFromArray(head), nil
+		}
+		if err != nil {
+			stream.Close()
+			return nil, err
+		}
+		head = append(head, msg)
+		if msg.Content != "" || len(msg.ToolCalls) > 0 {
+			break
+		}
+	}
+
+	reader, writer := schema.Pipe[*schema.Message](len(head))
+	for _, msg := range head {
+		writer.Send(msg, nil)
+	}
+	go func() {
+		defer stream.Close()
+		defer writer.Close()
+		for {
+			msg, err := stream.Recv()
+			if err == io.EOF {
+				return
+			}
+			if closed := writer.Send(msg, err); closed || err != nil {
+				return
+			}
+		}
+	}()
+	return reader, nil
+}
+
+// IsTransientError 判断是否为可重试的瞬时错误（超时、限流、5xx、网络错误）
+func IsTransientError(err error) bool {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) && apiErr.HTTPStatusCode > 0 {
+		return transientStatus(apiErr.HTTPStatusCode)
+	}
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) {
+		return transientStatus(statusErr.StatusCode)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// transientStatus 超时、限流和 5xx 可以重试
+func transientStatus(code int) bool {
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
@@ -0,0 +1,248 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// fakeStreamer 按顺序返回预设错误，错误用完后返回成功的流
+// recvErrs 按顺序作为流中首个文本之前的错误，tailErr 在首个文本之后返回
+type fakeStreamer struct {
+	errs     []error
+	recvErrs []error
+	tailErr  error
+	calls    int
+}
+
+func (f *fakeStreamer) Stream(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	f.calls++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return nil, err
+	}
+	reader, writer := schema.Pipe[*schema.Message](3)
+	writer.Send(schema.AssistantMessage("", nil), nil)
+	if len(f.recvErrs) > 0 {
+		writer.Send(nil, f.recvErrs[0])
+		f.recvErrs = f.recvErrs[1:]
+	} else {
+		writer.Send(schema.AssistantMessage("ok", nil), nil)
+		if f.tailErr != nil {
+			writer.Send(nil, f.tailErr)
+		}
+	}
+	writer.Close()
+	return reader, nil
+}
+
+func statusError(code int) error {
+	return &openai.APIError{HTTPStatusCode: code, Message: http.StatusText(code)}
+}
+
+func TestStreamWithFallback(t *testing.T) {
+	tests := []struct {
+		name         string
+		primaryErrs  []error
+		primaryRecv  []error
+		fallbackErrs []error
+		maxRetries   int
+		wantProvider int
+		wantErr      bool
+		wantPrimary  int // 主 LLM 调用次数
+	}{
+		{
+			name:         "primary succeeds",
+			wantProvider: 0,
+			wantPrimary:  1,
+		},
+		{
+			name:         "transient error retried on primary",
+			primaryErrs:  []error{statusError(http.StatusServiceUnavailable)},
+			maxRetries:   1,
+			wantProvider: 0,
+			wantPrimary:  2,
+		},
+		{
+			name:         "retries exhausted fails over",
+			primaryErrs:  []error{statusError(http.StatusTooManyRequests), statusError(http.StatusTooManyRequests)},
+			maxRetries:   1,
+			wantProvider: 1,
+			wantPrimary:  2,
+		},
+		{
+			name:         "non-transient error fails over without retry",
+			primaryErrs:  []error{statusError(http.StatusUnauthorized)},
+			maxRetries:   3,
+			wantProvider: 1,
+			wantPrimary:  1,
+		},
+		{
+			name:         "gemini rate limit retried",
+			primaryErrs:  []error{&httpStatusError{StatusCode: http.StatusTooManyRequests}},
+			maxRetries:   1,
+			wantProvider: 0,
+			wantPrimary:  2,
+		},
+		{
+			name:         "stream error before output retried on primary",
+			primaryRecv:  []error{io.ErrUnexpectedEOF},
+			maxRetries:   1,
+			wantProvider: 0,
+			wantPrimary:  2,
+		},
+		{
+			name:         "stream error before output fails over",
+			primaryRecv:  []error{statusError(http.StatusBadRequest)},
+			maxRetries:   1,
+			wantProvider: 1,
+			wantPrimary:  1,
+		},
+		{
+			name:         "all providers fail",
+			primaryErrs:  []error{statusError(http.StatusBadRequest)},
+			fallbackErrs: []error{statusError(http.StatusBadRequest)},
+			wantErr:      true,
+			wantPrimary:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := &fakeStreamer{errs: tt.primaryErrs, recvErrs: tt.primaryRecv}
+			fallback := &fakeStreamer{errs: tt.fallbackErrs}
+			providers := []llmProvider{
+				{name: "primary", model: primary},
+				{name: "backup", model: fallback},
+			}
+
+			result, err := streamWithFallback(context.Background(), providers, retryPolicy{maxRetries: tt.maxRetries}, nil)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("streamWithFallback() error = %v", err)
+				}
+				if result.provider != tt.wantProvider {
+					t.Fatalf("provider = %d, want %d", result.provider, tt.wantProvider)
+				}
+				if (result.provider > 0) != (result.reason != nil) {
+					t.Fatalf("unexpected degraded reason: %v", result.reason)
+				}
+				if text := readAll(t, result.stream); text != "ok" {
+					t.Fatalf("stream text = %q, want %q", text, "ok")
+				}
+			}
+			if primary.calls != tt.wantPrimary {
+				t.Fatalf("primary calls = %d, want %d", primary.calls, tt.wantPrimary)
+			}
+		})
+	}
+}
+
+// TestStreamWithFallbackAfterOutput 已开始输出后出错不再切换，错误交给调用方
+func TestStreamWithFallbackAfterOutput(t *testing.T) {
+	tailErr := statusError(http.StatusBadGateway)
+	primary := &fakeStreamer{tailErr: tailErr}
+	fallback := &fakeStreamer{}
+	providers := []llmProvider{
+		{name: "primary", model: primary},
+		{name: "backup", model: fallback},
+	}
+
+	result, err := streamWithFallback(context.Background(), providers, retryPolicy{maxRetries: 1}, nil)
+	if err != nil {
+		t.Fatalf("streamWithFallback() error = %v", err)
+	}
+	defer result.stream.Close()
+	if result.provider != 0 || fallback.calls != 0 {
+		t.Fatalf("provider = %d, fallback calls = %d", result.provider, fallback.calls)
+	}
+	for _, want := range []string{"", "ok"} {
+		msg, err := result.stream.Recv()
+		if err != nil || msg.Content != want {
+			t.Fatalf("Recv() = %v, %v, want %q", msg, err, want)
+		}
+	}
+	if _, err := result.stream.Recv(); !errors.Is(err, tailErr) {
+		t.Fatalf("expected tail error, got %v", err)
+	}
+}
+
+// readAll 读完流并拼接文本
+func readAll(t *testing.T, stream *schema.StreamReader[*schema.Message]) string {
+	t.Helper()
+	defer stream.Close()
+	text := ""
+	for {
+		msg, err := stream.Recv()
+		if err == io.EOF {
+			return text
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		text += msg.Content
+	}
+}
+
+func TestStreamWithFallbackCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	providers := []llmProvider{{name: "primary", model: &fakeStreamer{errs: []error{context.Canceled}}}}
+	_, err := streamWithFallback(ctx, providers, retryPolicy{maxRetries: 2}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestNormalizeConfigFallbacks(t *testing.T) {
+	cfg, err := normalizeConfig(Config{
+		APIKey:    "key",
+		Fallbacks: []LLMEndpoint{{Model: "glm-4-air"}},
+	})
+	if err != nil {
+		t.Fatalf("normalizeConfig() error = %v", err)
+	}
+	fallback := cfg.Fallbacks[0]
+	if fallback.Name != "fallback-1" || fallback.APIKey != "key" || fallback.BaseURL != defaultLLMBaseURL || fallback.Model != "glm-4-air" {
+		t.Fatalf("unexpected fallback: %+v", fallback)
+	}
+}
+
+func TestNormalizeConfigProviders(t *testing.T) {
+	cfg, err := normalizeConfig(Config{
+		Provider: ProviderGemini,
+		APIKey:   "key",
+		Fallbacks: []LLMEndpoint{
+			{Name: "flash-lite", Model: "gemini-2.0-flash-lite"},
+			{Name: "glm", Provider: ProviderOpenAI, APIKey: "glm-key"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("normalizeConfig() error = %v", err)
+	}
+	if cfg.BaseURL != defaultGeminiBaseURL || cfg.Model != defaultGeminiModel {
+		t.Fatalf("unexpected primary: %s %s", cfg.BaseURL, cfg.Model)
+	}
+	if lite := cfg.Fallbacks[0]; lite.Provider != ProviderGemini || lite.BaseURL != defaultGeminiBaseURL || lite.APIKey != "key" {
+		t.Fatalf("unexpected gemini fallback: %+v", lite)
+	}
+	if glm := cfg.Fallbacks[1]; glm.BaseURL != defaultLLMBaseURL || glm.Model != defaultLLMModel || glm.APIKey != "glm-key" {
+		t.Fatalf("unexpected openai fallback: %+v", glm)
+	}
+
+	if _, err := normalizeConfig(Config{Provider: "claude", APIKey: "key"}); err == nil {
+		t.Fatal("expected error for unknown provider")
+	}
+}
@@ -0,0 +1,56 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// questionMarks 句末问号，回复以问句结尾时视为追问
+const questionMarks = "？?"
+
+// sentenceEnds 分句标点，用于截取回复中的最后一句
+const sentenceEnds = "。！？!?；;\n"
+
+type followUpKey struct{}
+
+// WithFollowUp 将上一轮回复末尾的追问（如“还需要查别的城市吗？”）附加到 ctx，
+// 表示用户这句话是在回答该追问，VoiceAgent 会把追问带给模型
+func WithFollowUp(ctx context.Context, question string) context.Context {
+	return context.WithValue(ctx, followUpKey{}, question)
+}
+
+// FollowUpFromContext 从 ctx 中取出上一轮的追问
+func FollowUpFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	question, ok := ctx.Value(followUpKey{}).(string)
+	return question, ok && strings.TrimSpace(question) != ""
+}
+
+// followUpQuestion 回复以问句结尾时返回最后一句（即向用户的追问），否则返回空
+func followUpQuestion(reply string) string {
+	reply = strings.TrimSpace(reply)
+	last, _ := utf8.DecodeLastRuneInString(reply)
+	if !strings.ContainsRune(questionMarks, last) {
+		return ""
+	}
+	body := strings.TrimRight(reply, questionMarks)
+	start := 0
+	if i := strings.LastIndexAny(body, sentenceEnds); i >= 0 {
+		_, size := utf8.DecodeRuneInString(body[i:])
+		start = i + size
+	}
+	// 英文句号后跟空格才算分句，避免截断小数
+	if i := strings.LastIndex(body, ". "); i+1 > start {
+		start = i + 1
+	}
+	return strings.TrimSpace(reply[start:])
+}
+
+// followUpPrompt 生成追问说明，让模型把用户这句话理解为对追问的回答
+func followUpPrompt(question string) string {
+	return fmt.Sprintf("注意：你在上一轮回复的最后问了用户「%s」，用户这句话很可能是在回答这个问题，请结合该问题理解用户的意图。", question)
+}
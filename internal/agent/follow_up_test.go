@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestFollowUpQuestion(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		want  string
+	}{
+		{"trailing question", "北京今天晴，气温 20 度。还需要查别的城市吗？", "还需要查别的城市吗？"},
+		{"question only", "  你想查哪个城市？\n", "你想查哪个城市？"},
+		{"english", "It's 20.5 degrees in Beijing. Anything else?", "Anything else?"},
+		{"after exclamation", "好的！要设置几点的闹钟?", "要设置几点的闹钟?"},
+		{"question in the middle", "你问北京吗？北京今天晴。", ""},
+		{"statement", "北京今天晴。", ""},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := followUpQuestion(tt.reply); got != tt.want {
+				t.Errorf("followUpQuestion(%q) = %q, want %q", tt.reply, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildMessagesWithFollowUp(t *testing.T) {
+	ctx := WithFollowUp(context.Background(), "还需要查别的城市吗？")
+
+	messages := buildMessages(ctx, "system", "上海呢")
+	if len(messages) != 4 {
+		t.Fatalf("len(messages) = %d, want 4", len(messages))
+	}
+	if messages[1].Role != schema.Assistant || messages[1].Content != "还需要查别的城市吗？" {
+		t.Fatalf("expected follow-up question as assistant message, got %s %q", messages[1].Role, messages[1].Content)
+	}
+	if messages[2].Role != schema.System || !strings.Contains(messages[2].Content, "回答这个问题") {
+		t.Fatalf("unexpected follow-up note: %q", messages[2].Content)
+	}
+	if messages[3].Role != schema.User || messages[3].Content != "上海呢" {
+		t.Fatalf("unexpected user message: %q", messages[3].Content)
+	}
+
+	if _, ok := FollowUpFromContext(WithFollowUp(context.Background(), " ")); ok {
+		t.Error("blank follow-up should not be reported")
+	}
+}
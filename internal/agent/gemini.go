@@ -0,0 +1,253 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+const (
+	defaultGeminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+	defaultGeminiModel   = "gemini-2.0-flash"
+)
+
+// geminiChatModel Gemini 原生接口的流式调用：系统提示词放在 systemInstruction 中，
+// 回复以 SSE 推送完整的 GenerateContentResponse，工具调用是一次性给出的完整 functionCall（参数为 JSON 对象）
+type geminiChatModel struct {
+	endpoint    LLMEndpoint
+	definitions []tools.ToolDefinition
+	tools       []map[string]interface{}
+	client      *http.Client
+}
+
+func newGeminiChatModel(ctx context.Context, endpoint LLMEndpoint, definitions []tools.ToolDefinition) (chatStreamer, error) {
+	return &geminiChatModel{
+		endpoint:    endpoint,
+		definitions: definitions,
+		tools:       geminiTools(definitions),
+		client:      http.DefaultClient,
+	}, nil
+}
+
+// toolsFor 返回本次请求的工具声明：带 model.WithTools 时只声明其中列出的已绑定工具
+func (g *geminiChatModel) toolsFor(opts []model.Option) []map[string]interface{} {
+	options := model.GetCommonOptions(nil, opts...)
+	if options.Tools == nil {
+		return g.tools
+	}
+	wanted := make(map[string]bool, len(options.Tools))
+	for _, info := range options.Tools {
+		wanted[info.Name] = true
+	}
+	selected := make([]tools.ToolDefinition, 0, len(options.Tools))
+	for _, definition := range g.definitions {
+		if wanted[definition.Name] {
+			selected = append(selected, definition)
+		}
+	}
+	return geminiTools(selected)
+}
+
+// geminiContent 一条对话内容，role 为 user 或 model
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text         string              `json:"text,omitempty"`
+	FunctionCall *geminiFunctionCall `json:"functionCall,omitempty"`
+}
+
+type geminiFunctionCall struct {
+	Name string                 `json:"name"`
+	Args map[string]interface{} `json:"args,omitempty"`
+}
+
+type geminiRequest struct {
+	SystemInstruction *geminiContent           `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent          `json:"contents"`
+	Tools             []map[string]interface{} `json:"tools,omitempty"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	UsageMetadata *struct {
+		PromptTokenCount     int `json:"promptTokenCount"`
+		CandidatesTokenCount int `json:"candidatesTokenCount"`
+		TotalTokenCount      int `json:"totalTokenCount"`
+	} `json:"usageMetadata"`
+}
+
+// geminiTools 把工具定义转换为 Gemini 的 functionDeclarations；没有参数的工具不带 parameters（Gemini 不接受空的 object）
+func geminiTools(definitions []tools.ToolDefinition) []map[string]interface{} {
+	if len(definitions) == 0 {
+		return nil
+	}
+	declarations := make([]map[string]interface{}, 0, len(definitions))
+	for _, definition := range definitions {
+		declaration := map[string]interface{}{
+			"name":        definition.Name,
+			"description": definition.Description,
+		}
+		if len(definition.Parameters) > 0 {
+			declaration["parameters"] = definition.JSONSchema()
+		}
+		declarations = append(declarations, declaration)
+	}
+	return []map[string]interface{}{{"functionDeclarations": declarations}}
+}
+
+// geminiRequestFrom 转换消息列表：开头的系统消息合并为 systemInstruction，之后的系统消息（打断说明、
+// 知识库资料等）作为 user 内容按原顺序插入；相邻同角色的内容合并，满足 Gemini 的角色交替要求
+func geminiRequestFrom(messages []*schema.Message, tools []map[string]interface{}) geminiRequest {
+	request := geminiRequest{Tools: tools}
+	var system []geminiPart
+	leading := true
+	for _, msg := range messages {
+		if msg.Role == schema.System && leading {
+			system = append(system, geminiPart{Text: msg.Content})
+			continue
+		}
+		leading = false
+		role := "user"
+		if msg.Role == schema.Assistant {
+			role = "model"
+		}
+		part := geminiPart{Text: msg.Content}
+		if last := len(request.Contents) - 1; last >= 0 && request.Contents[last].Role == role {
+			request.Contents[last].Parts = append(request.Contents[last].Parts, part)
+			continue
+		}
+		request.Contents = append(request.Contents, geminiContent{Role: role, Parts: []geminiPart{part}})
+	}
+	if len(system) > 0 {
+		request.SystemInstruction = &geminiContent{Parts: system}
+	}
+	return request
+}
+
+// Stream 发起 streamGenerateContent 请求；HTTP 错误在返回前读出，便于重试和切换备用 LLM
+func (g *geminiChatModel) Stream(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	body, err := json.Marshal(geminiRequestFrom(in, g.toolsFor(opts)))
+	if err != nil {
+		return nil, err
+	}
+	endpoint := fmt.Sprintf("%s/models/%s:streamGenerateContent?alt=sse",
+		strings.TrimRight(g.endpoint.BaseURL, "/"), url.PathEscape(g.endpoint.Model))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", g.endpoint.APIKey)
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, newHTTPStatusError(resp)
+	}
+
+	reader, writer := schema.Pipe[*schema.Message](8)
+	go func() {
+		defer resp.Body.Close()
+		defer writer.Close()
+		if err := readGeminiStream(resp.Body, writer); err != nil {
+			writer.Send(nil, err)
+		}
+	}()
+	return reader, nil
+}
+
+// readGeminiStream 逐条解析 SSE 数据，转换为 schema.Message 写入 writer；消费方关闭流时提前返回
+func readGeminiStream(body io.Reader, writer *schema.StreamWriter[*schema.Message]) error {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	calls := 0
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		var chunk geminiResponse
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &chunk); err != nil {
+			return fmt.Errorf("gemini: invalid stream chunk: %w", err)
+		}
+		msg := &schema.Message{Role: schema.Assistant}
+		if len(chunk.Candidates) > 0 {
+			candidate := chunk.Candidates[0]
+			for _, part := range candidate.Content.Parts {
+				if part.FunctionCall != nil {
+					args, err := json.Marshal(part.FunctionCall.Args)
+					if err != nil {
+						return err
+					}
+					calls++
+					msg.ToolCalls = append(msg.ToolCalls, schema.ToolCall{
+						ID:       fmt.Sprintf("call_%d", calls),
+						Function: schema.FunctionCall{Name: part.FunctionCall.Name, Arguments: string(args)},
+					})
+					continue
+				}
+				msg.Content += part.Text
+			}
+			if candidate.FinishReason != "" {
+				msg.ResponseMeta = &schema.ResponseMeta{FinishReason: candidate.FinishReason}
+			}
+		}
+		if usage := chunk.UsageMetadata; usage != nil {
+			if msg.ResponseMeta == nil {
+				msg.ResponseMeta = &schema.ResponseMeta{}
+			}
+			msg.ResponseMeta.Usage = &schema.TokenUsage{
+				PromptTokens:     usage.PromptTokenCount,
+				CompletionTokens: usage.CandidatesTokenCount,
+				TotalTokens:      usage.TotalTokenCount,
+			}
+		}
+		if closed := writer.Send(msg, nil); closed {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// httpStatusError 非 OpenAI 兼容服务返回的 HTTP 错误，IsTransientError 按状态码判断是否重试
+type httpStatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func newHTTPStatusError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var body struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	message := strings.TrimSpace(string(data))
+	if json.Unmarshal(data, &body) == nil && body.Error.Message != "" {
+		message = body.Error.Message
+	}
+	return &httpStatusError{StatusCode: resp.StatusCode, Message: message}
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("http %d: %s", e.StatusCode, e.Message)
+}
@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+func TestGeminiRequestFrom(t *testing.T) {
+	messages := []*schema.Message{
+		schema.SystemMessage("你是语音助手"),
+		schema.AssistantMessage("今天北京", nil),
+		schema.SystemMessage("用户打断了你"),
+		schema.UserMessage("上海呢"),
+	}
+	request := geminiRequestFrom(messages, nil)
+	if request.SystemInstruction == nil || request.SystemInstruction.Parts[0].Text != "你是语音助手" {
+		t.Fatalf("systemInstruction = %+v", request.SystemInstruction)
+	}
+	if len(request.Contents) != 2 {
+		t.Fatalf("contents = %+v", request.Contents)
+	}
+	if request.Contents[0].Role != "model" || request.Contents[1].Role != "user" || len(request.Contents[1].Parts) != 2 {
+		t.Fatalf("contents = %+v", request.Contents)
+	}
+}
+
+func TestGeminiTools(t *testing.T) {
+	declarations := geminiTools([]tools.ToolDefinition{
+		{Name: "getTime", Description: "获取当前时间"},
+		{Name: "getWeather", Description: "查询天气", Parameters: map[string]tools.Parameter{
+			"city": {Type: "string", Description: "城市", Required: true},
+		}},
+	})[0]["functionDeclarations"].([]map[string]interface{})
+	if _, ok := declarations[0]["parameters"]; ok {
+		t.Fatalf("tool without parameters should omit schema: %v", declarations[0])
+	}
+	if _, ok := declarations[1]["parameters"]; !ok {
+		t.Fatalf("tool with parameters should include schema: %v", declarations[1])
+	}
+}
+
+func TestGeminiToolsForSelection(t *testing.T) {
+	streamer, _ := newGeminiChatModel(context.Background(), LLMEndpoint{}, []tools.ToolDefinition{
+		{Name: "getTime", Description: "获取当前时间"},
+		{Name: "getWeather", Description: "查询天气"},
+	})
+	g := streamer.(*geminiChatModel)
+	tests := []struct {
+		name string
+		opts []model.Option
+		want int // 声明的工具数
+	}{
+		{name: "all bound tools", want: 2},
+		{name: "selected subset", opts: []model.Option{model.WithTools([]*schema.ToolInfo{{Name: "getWeather"}})}, want: 1},
+		{name: "no tools", opts: []model.Option{model.WithTools(nil)}, want: 0},
+	}
+	for _, tt := range tests {
+		declared := g.toolsFor(tt.opts)
+		got := 0
+		if len(declared) > 0 {
+			got = len(declared[0]["functionDeclarations"].([]map[string]interface{}))
+		}
+		if got != tt.want {
+			t.Errorf("%s: declared %d tool(s), want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGeminiStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/models/gemini-test:streamGenerateContent" || r.URL.Query().Get("alt") != "sse" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if r.Header.Get("x-goog-api-key") != "key" {
+			t.Errorf("missing api key header")
+		}
+		var request geminiRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil || len(request.Tools) != 1 {
+			t.Errorf("unexpected body: %+v, %v", request, err)
+		}
+		io.WriteString(w, "data: {\"candidates\":[{\"content\":{\"role\":\"model\",\"parts\":[{\"text\":\"好的，\"}]}}]}\n\n")
+		io.WriteString(w, "data: {\"candidates\":[{\"content\":{\"role\":\"model\",\"parts\":[{\"functionCall\":{\"name\":\"getWeather\",\"args\":{\"city\":\"上海\"}}}]},\"finishReason\":\"STOP\"}],\"usageMetadata\":{\"promptTokenCount\":12,\"candidatesTokenCount\":4,\"totalTokenCount\":16}}\n\n")
+	}))
+	defer server.Close()
+
+	model, err := newGeminiChatModel(context.Background(), LLMEndpoint{BaseURL: server.URL, Model: "gemini-test", APIKey: "key"},
+		[]tools.ToolDefinition{{Name: "getWeather", Description: "查询天气"}})
+	if err != nil {
+		t.Fatalf("newGeminiChatModel() error = %v", err)
+	}
+	stream, err := model.Stream(context.Background(), []*schema.Message{schema.UserMessage("上海天气")})
+	if err != nil {
+		t.Fatalf("Stream() error = %v", err)
+	}
+	defer stream.Close()
+
+	first, err := stream.Recv()
+	if err != nil || first.Content != "好的，" {
+		t.Fatalf("first chunk = %+v, %v", first, err)
+	}
+	second, err := stream.Recv()
+	if err != nil || len(second.ToolCalls) != 1 {
+		t.Fatalf("second chunk = %+v, %v", second, err)
+	}
+	if call := second.ToolCalls[0].Function; call.Name != "getWeather" || call.Arguments != `{"city":"上海"}` {
+		t.Fatalf("tool call = %+v", call)
+	}
+	recorder := newUsageRecorder()
+	recorder.Observe(second)
+	if usage := recorder.Usage(); usage.PromptTokens != 12 || usage.CompletionTokens != 4 {
+		t.Fatalf("usage = %+v", usage)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+}
+
+func TestGeminiStreamHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		io.WriteString(w, `{"error":{"code":429,"message":"quota exceeded","status":"RESOURCE_EXHAUSTED"}}`)
+	}))
+	defer server.Close()
+
+	model, _ := newGeminiChatModel(context.Background(), LLMEndpoint{BaseURL: server.URL, Model: "gemini-test"}, nil)
+	_, err := model.Stream(context.Background(), []*schema.Message{schema.UserMessage("你好")})
+	if err == nil || err.Error() != "http 429: quota exceeded" || !IsTransientError(err) {
+		t.Fatalf("Stream() error = %v, want transient 429", err)
+	}
+}
@@ -0,0 +1,153 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// ToolSelector 每轮按用户输入从工具目录中选择绑定给主 LLM 的工具
+// 返回 nil 表示使用全部工具，返回空切片表示本轮不需要工具
+type ToolSelector interface {
+	SelectTools(ctx context.Context, input string, catalog []tools.ToolDefinition) ([]tools.ToolDefinition, error)
+}
+
+// IntentRouterConfig 意图路由：主 LLM 之前先用小模型从工具目录中选出本轮需要的工具，
+// 只把选中的工具绑定给主 LLM 并渲染到提示词，工具很多时避免每轮都带上全部工具定义
+type IntentRouterConfig struct {
+	Enable bool
+	// Endpoint 路由使用的小模型，Provider、APIKey 为空时沿用主 LLM；BaseURL、Model 为空时，
+	// 服务商与主 LLM 相同则沿用主 LLM，否则使用该服务商的默认值
+	Endpoint LLMEndpoint
+	MaxTools int           // 每轮最多选择的工具数，工具总数不超过该值时不路由；0 表示不限制
+	Timeout  time.Duration // 路由超时，超时或失败时本轮绑定全部工具；0 表示不限制
+}
+
+// routerNoTools 路由模型表示不需要工具时的输出
+const routerNoTools = "none"
+
+// routerPrompt 路由模型的系统提示词，{{tools}} 为工具目录
+const routerPrompt = `你是语音助手的工具路由器。根据用户的话，从下列工具中选出回答时需要调用的工具。
+只输出工具名，多个用逗号分隔，不要解释；不需要任何工具时输出 none。
+
+工具：
+{{tools}}`
+
+// intentRouter 基于小模型的 ToolSelector
+type intentRouter struct {
+	model    chatStreamer
+	maxTools int
+	timeout  time.Duration
+}
+
+func newIntentRouter(ctx context.Context, cfg IntentRouterConfig) (*intentRouter, error) {
+	streamer, err := newChatModel(ctx, cfg.Endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &intentRouter{model: streamer, maxTools: cfg.MaxTools, timeout: cfg.Timeout}, nil
+}
+
+func (r *intentRouter) SelectTools(ctx context.Context, input string, catalog []tools.ToolDefinition) ([]tools.ToolDefinition, error) {
+	if len(catalog) == 0 || (r.maxTools > 0 && len(catalog) <= r.maxTools) {
+		return nil, nil
+	}
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	lines := make([]string, 0, len(catalog))
+	for _, definition := range catalog {
+		lines = append(lines, "- "+definition.Name+": "+definition.Description)
+	}
+	messages := []*schema.Message{
+		schema.SystemMessage(strings.ReplaceAll(routerPrompt, "{{tools}}", strings.Join(lines, "\n"))),
+		schema.UserMessage(input),
+	}
+	// 小模型不绑定工具，显式传空列表避免沿用服务端默认配置
+	stream, err := r.model.Stream(ctx, messages, model.WithTools(nil))
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var reply strings.Builder
+	for {
+		msg, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		reply.WriteString(msg.Content)
+	}
+	return parseToolSelection(reply.String(), catalog, r.maxTools), nil
+}
+
+// parseToolSelection 从路由模型的回复中按出现顺序取出目录中存在的工具名（不区分大小写），
+// 去重并按 maxTools 截断；没有可识别的工具名时返回空切片
+func parseToolSelection(reply string, catalog []tools.ToolDefinition, maxTools int) []tools.ToolDefinition {
+	byName := make(map[string]tools.ToolDefinition, len(catalog))
+	for _, definition := range catalog {
+		byName[strings.ToLower(definition.Name)] = definition
+	}
+	words := strings.FieldsFunc(reply, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '.'
+	})
+	selected := make([]tools.ToolDefinition, 0, len(words))
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		key := strings.ToLower(word)
+		definition, ok := byName[key]
+		if !ok || seen[key] {
+			continue
+		}
+		seen[key] = true
+		selected = append(selected, definition)
+		if maxTools > 0 && len(selected) == maxTools {
+			break
+		}
+	}
+	return selected
+}
+
+// toolNames 返回工具名列表，用于日志和提示词过滤
+func toolNames(definitions []tools.ToolDefinition) []string {
+	names := make([]string, 0, len(definitions))
+	for _, definition := range definitions {
+		names = append(names, definition.Name)
+	}
+	return names
+}
+
+// selectTools 调用 ToolSelector 选择本轮的工具，失败时记录警告并使用全部工具（返回 nil）
+func (v *voiceAgentImpl) selectTools(ctx context.Context, input string) []tools.ToolDefinition {
+	if v.toolSelector == nil || len(v.tools) == 0 {
+		return nil
+	}
+	start := time.Now()
+	selected, err := v.toolSelector.SelectTools(ctx, input, v.tools)
+	v.selection.selection(selected, err, time.Since(start))
+	if err != nil {
+		if ctx.Err() == nil {
+			logging.WarnfCtx(ctx, "VoiceAgent: tool selection failed, binding all %d tool(s): %v", len(v.tools), err)
+		}
+		return nil
+	}
+	if selected != nil {
+		logging.InfofCtx(ctx, "VoiceAgent: selected %d/%d tool(s) in %v: %v",
+			len(selected), len(v.tools), time.Since(start).Round(time.Millisecond), toolNames(selected))
+	}
+	return selected
+}
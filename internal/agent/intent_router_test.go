@@ -0,0 +1,217 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// recordingStreamer 记录每次调用的消息和工具选项，返回固定回复
+type recordingStreamer struct {
+	reply    string
+	err      error
+	messages [][]*schema.Message
+	tools    [][]*schema.ToolInfo // 每次调用的 model.WithTools，未设置时为 nil
+}
+
+func (r *recordingStreamer) Stream(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	r.messages = append(r.messages, in)
+	r.tools = append(r.tools, model.GetCommonOptions(nil, opts...).Tools)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage(r.reply, nil)}), nil
+}
+
+var routerCatalog = []tools.ToolDefinition{
+	{Name: "getTime", Description: "获取当前时间"},
+	{Name: "getWeather", Description: "获取天气"},
+	{Name: "playMusic", Description: "播放音乐", Action: true},
+	{Name: "setVolume", Description: "调整音量", Action: true},
+}
+
+func TestParseToolSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    string
+		maxTools int
+		want     []string
+	}{
+		{name: "comma separated", reply: "getWeather, getTime", want: []string{"getWeather", "getTime"}},
+		{name: "case and punctuation", reply: "`GETWEATHER`；playmusic。", want: []string{"getWeather", "playMusic"}},
+		{name: "unknown and duplicate names", reply: "getWeather, search, getWeather", want: []string{"getWeather"}},
+		{name: "max tools", reply: "getTime,getWeather,setVolume", maxTools: 2, want: []string{"getTime", "getWeather"}},
+		{name: "none", reply: "none", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := toolNames(parseToolSelection(tt.reply, routerCatalog, tt.maxTools))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("parseToolSelection(%q) = %v, want %v", tt.reply, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntentRouterSelectTools(t *testing.T) {
+	tests := []struct {
+		name      string
+		reply     string
+		err       error
+		maxTools  int
+		want      []string
+		wantAll   bool // 返回 nil（使用全部工具）
+		wantCalls int
+	}{
+		{name: "selects subset", reply: "getWeather", maxTools: 3, want: []string{"getWeather"}, wantCalls: 1},
+		{name: "no tools needed", reply: "none", want: []string{}, wantCalls: 1},
+		{name: "small catalog skips routing", maxTools: 4, wantAll: true},
+		{name: "model error", err: errors.New("boom"), wantAll: true, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streamer := &recordingStreamer{reply: tt.reply, err: tt.err}
+			router := &intentRouter{model: streamer, maxTools: tt.maxTools}
+			got, err := router.SelectTools(context.Background(), "北京天气怎么样", routerCatalog)
+			if (err != nil) != (tt.err != nil) {
+				t.Fatalf("SelectTools() error = %v", err)
+			}
+			if len(streamer.messages) != tt.wantCalls {
+				t.Fatalf("router model called %d times, want %d", len(streamer.messages), tt.wantCalls)
+			}
+			if tt.wantAll {
+				if got != nil {
+					t.Errorf("SelectTools() = %v, want nil", toolNames(got))
+				}
+				return
+			}
+			if names := toolNames(got); strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("SelectTools() = %v, want %v", names, tt.want)
+			}
+			if prompt := streamer.messages[0][0].Content; !strings.Contains(prompt, "- playMusic: 播放音乐") {
+				t.Errorf("router prompt missing catalog: %q", prompt)
+			}
+			if tools := streamer.tools[0]; tools == nil || len(tools) != 0 {
+				t.Errorf("router model should be called without tools, got %v", tools)
+			}
+		})
+	}
+}
+
+// fixedSelector 返回固定的工具子集
+type fixedSelector struct {
+	names []string
+	err   error
+}
+
+func (f fixedSelector) SelectTools(ctx context.Context, input string, catalog []tools.ToolDefinition) ([]tools.ToolDefinition, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	wanted := strings.Join(f.names, ",")
+	var selected []tools.ToolDefinition
+	for _, definition := range catalog {
+		if strings.Contains(wanted, definition.Name) {
+			selected = append(selected, definition)
+		}
+	}
+	return selected, nil
+}
+
+func TestVoiceAgentBindsSelectedTools(t *testing.T) {
+	tests := []struct {
+		name       string
+		selector   ToolSelector
+		wantTools  []string // nil 表示未传 model.WithTools
+		wantPrompt []string
+		dropPrompt []string
+	}{
+		{
+			name:       "subset",
+			selector:   fixedSelector{names: []string{"getWeather"}},
+			wantTools:  []string{"getWeather"},
+			wantPrompt: []string{"- getWeather: 获取天气"},
+			dropPrompt: []string{"playMusic", "getTime"},
+		},
+		{
+			name:       "selector failure binds all tools",
+			selector:   fixedSelector{err: errors.New("timeout")},
+			wantPrompt: []string{"- getWeather: 获取天气", "- playMusic: 播放音乐"},
+		},
+		{
+			name:       "no selector",
+			wantPrompt: []string{"- getTime: 获取当前时间", "- setVolume: 调整音量"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streamer := &recordingStreamer{reply: "好的"}
+			types := toolTypesFromDefinitions(routerCatalog, nil)
+			v := &voiceAgentImpl{
+				providers:         []llmProvider{{name: "primary", model: streamer}},
+				emotionExtractor:  NewEmotionExtractor(),
+				markdownFilter:    NewMarkdownFilter(),
+				toolClassifier:    NewToolClassifierWithTypes(types),
+				actionResponseGen: NewActionResponseGenerator(),
+				promptBuilder:     NewPromptBuilder(PromptConfig{Tools: mergeToolInfos(routerCatalog, nil, types)}),
+				tools:             routerCatalog,
+				toolSelector:      tt.selector,
+			}
+			events, err := v.Process(context.Background(), "北京天气怎么样")
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			for range events {
+			}
+
+			if len(streamer.tools) != 1 {
+				t.Fatalf("LLM called %d times", len(streamer.tools))
+			}
+			bound := streamer.tools[0]
+			if (bound == nil) != (tt.wantTools == nil) {
+				t.Fatalf("bound tools = %v, want %v", bound, tt.wantTools)
+			}
+			for i, name := range tt.wantTools {
+				if bound[i].Name != name {
+					t.Errorf("bound tool %d = %s, want %s", i, bound[i].Name, name)
+				}
+			}
+			prompt := streamer.messages[0][0].Content
+			for _, want := range tt.wantPrompt {
+				if !strings.Contains(prompt, want) {
+					t.Errorf("system prompt missing %q", want)
+				}
+			}
+			for _, drop := range tt.dropPrompt {
+				if strings.Contains(prompt, drop) {
+					t.Errorf("system prompt should not mention %q", drop)
+				}
+			}
+		})
+	}
+}
+
+func TestNormalizeConfigIntentRouter(t *testing.T) {
+	cfg, err := normalizeConfig(Config{
+		APIKey:       "key",
+		Model:        "glm-4-plus",
+		IntentRouter: IntentRouterConfig{Enable: true, Endpoint: LLMEndpoint{Model: "glm-4-flash"}, MaxTools: -1},
+	})
+	if err != nil {
+		t.Fatalf("normalizeConfig() error = %v", err)
+	}
+	router := cfg.IntentRouter
+	if router.Endpoint.Name != "intent-router" || router.Endpoint.APIKey != "key" || router.Endpoint.BaseURL != defaultLLMBaseURL ||
+		router.Endpoint.Model != "glm-4-flash" || router.MaxTools != 0 {
+		t.Fatalf("unexpected intent router: %+v", router)
+	}
+
+	if _, err := normalizeConfig(Config{APIKey: "key", IntentRouter: IntentRouterConfig{Enable: true, Endpoint: LLMEndpoint{Provider: "claude"}}}); err == nil {
+		t.Fatal("expected error for unknown router provider")
+	}
+}
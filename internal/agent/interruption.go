@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Interruption 上一轮被用户打断（barge-in）的回复上下文
+// 用于让模型基于用户“实际听到的内容”理解纠正，例如“不对，我是说…”
+type Interruption struct {
+	SpokenText    string // 打断前已完整播放给用户的文本
+	FullText      string // 打断时 LLM 已生成的完整回复（已过滤 Markdown）
+	InterruptedAt int    // 打断位置：FullText 中的 rune 偏移，等于 SpokenText 的长度
+	Truncated     bool   // 回复超过长度上限被截断（用户被问是否继续），而不是被用户打断
+}
+
+// IsEmpty 是否没有任何可用的打断上下文
+func (i Interruption) IsEmpty() bool {
+	return strings.TrimSpace(i.FullText) == "" && strings.TrimSpace(i.SpokenText) == ""
+}
+
+// UnspokenText 返回生成了但尚未播放给用户的部分
+func (i Interruption) UnspokenText() string {
+	runes := []rune(i.FullText)
+	at := i.InterruptedAt
+	if at < 0 {
+		at = 0
+	}
+	if at > len(runes) {
+		at = len(runes)
+	}
+	return string(runes[at:])
+}
+
+// Prompt 生成注入给 LLM 的打断说明
+func (i Interruption) Prompt() string {
+	var b strings.Builder
+	if i.Truncated {
+		b.WriteString("注意：你的上一条回复太长，只朗读了一部分，随后询问了用户是否继续。")
+	} else {
+		b.WriteString("注意：你的上一条回复在播放过程中被用户打断了。")
+	}
+	if strings.TrimSpace(i.SpokenText) == "" {
+		b.WriteString("用户在听到任何内容之前就打断了你。")
+	} else {
+		fmt.Fprintf(&b, "用户只听到了前 %d 个字：「%s」。", i.InterruptedAt, i.SpokenText)
+	}
+	if unspoken := strings.TrimSpace(i.UnspokenText()); unspoken != "" {
+		fmt.Fprintf(&b, "以下内容已生成但用户没有听到：「%s」。", unspoken)
+	}
+	if i.Truncated {
+		b.WriteString("如果用户希望继续，请从用户没有听到的部分接着讲，不要重复已经朗读的内容。")
+	} else {
+		b.WriteString("如果用户在纠正或补充（例如“不对，我是说…”），请以用户实际听到的内容为准理解用户的意图。")
+	}
+	return b.String()
+}
+
+type interruptionKey struct{}
+
+// WithInterruption 将打断上下文附加到 ctx，供 VoiceAgent.Process 使用
+func WithInterruption(ctx context.Context, interruption Interruption) context.Context {
+	return context.WithValue(ctx, interruptionKey{}, interruption)
+}
+
+// InterruptionFromContext 从 ctx 中取出打断上下文
+func InterruptionFromContext(ctx context.Context) (Interruption, bool) {
+	if ctx == nil {
+		return Interruption{}, false
+	}
+	interruption, ok := ctx.Value(interruptionKey{}).(Interruption)
+	if !ok || interruption.IsEmpty() {
+		return Interruption{}, false
+	}
+	return interruption, true
+}
@@ -0,0 +1,81 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestBuildMessagesWithoutInterruption(t *testing.T) {
+	messages := buildMessages(context.Background(), "system", "你好")
+	if len(messages) != 2 {
+		t.Fatalf("len(messages) = %d, want 2", len(messages))
+	}
+	if messages[0].Role != schema.System || messages[1].Role != schema.User {
+		t.Fatalf("unexpected roles: %s, %s", messages[0].Role, messages[1].Role)
+	}
+}
+
+func TestBuildMessagesWithInterruption(t *testing.T) {
+	ctx := WithInterruption(context.Background(), Interruption{
+		SpokenText:    "好的，已为您预订明天",
+		FullText:      "好的，已为您预订明天上午九点的会议室。",
+		InterruptedAt: len([]rune("好的，已为您预订明天")),
+	})
+
+	messages := buildMessages(ctx, "system", "不对，我是说后天")
+	if len(messages) != 4 {
+		t.Fatalf("len(messages) = %d, want 4", len(messages))
+	}
+	if messages[1].Role != schema.Assistant || messages[1].Content != "好的，已为您预订明天" {
+		t.Fatalf("expected spoken text as assistant message, got %s %q", messages[1].Role, messages[1].Content)
+	}
+	if messages[2].Role != schema.System || !strings.Contains(messages[2].Content, "上午九点的会议室") {
+		t.Fatalf("expected unspoken text in interruption note, got %q", messages[2].Content)
+	}
+	if messages[3].Role != schema.User || messages[3].Content != "不对，我是说后天" {
+		t.Fatalf("unexpected user message: %q", messages[3].Content)
+	}
+}
+
+func TestBuildMessagesInterruptedBeforeSpeaking(t *testing.T) {
+	ctx := WithInterruption(context.Background(), Interruption{
+		FullText: "北京今天晴。",
+	})
+
+	messages := buildMessages(ctx, "system", "我问的是上海")
+	if len(messages) != 3 {
+		t.Fatalf("len(messages) = %d, want 3", len(messages))
+	}
+	if !strings.Contains(messages[1].Content, "听到任何内容之前") {
+		t.Fatalf("unexpected interruption note: %q", messages[1].Content)
+	}
+}
+
+func TestInterruptionFromContextIgnoresEmpty(t *testing.T) {
+	ctx := WithInterruption(context.Background(), Interruption{})
+	if _, ok := InterruptionFromContext(ctx); ok {
+		t.Fatalf("expected empty interruption to be ignored")
+	}
+}
+
+func TestBuildMessagesWithLanguage(t *testing.T) {
+	ctx := WithLanguage(context.Background(), "en")
+
+	messages := buildMessages(ctx, "system", "What's the weather today?")
+	if len(messages) != 3 {
+		t.Fatalf("len(messages) = %d, want 3", len(messages))
+	}
+	if messages[1].Role != schema.System || !strings.Contains(messages[1].Content, "English") {
+		t.Fatalf("unexpected language message: %+v", messages[1])
+	}
+	if messages[2].Role != schema.User {
+		t.Fatalf("last message role = %s, want user", messages[2].Role)
+	}
+
+	if _, ok := LanguageFromContext(WithLanguage(context.Background(), "")); ok {
+		t.Error("empty language should not be reported")
+	}
+}
@@ -0,0 +1,49 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/liuscraft/orion-x/internal/knowledge"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// KnowledgeRetriever 知识库检索接口，由 knowledge.Base 实现
+type KnowledgeRetriever interface {
+	Retrieve(ctx context.Context, query string) ([]knowledge.Snippet, error)
+}
+
+// withKnowledge 检索与用户输入相关的资料，作为系统消息插入到用户消息之前
+// 检索失败只记录日志，不影响本轮对话
+func withKnowledge(ctx context.Context, retriever KnowledgeRetriever, messages []*schema.Message, input string) []*schema.Message {
+	if retriever == nil || len(messages) == 0 {
+		return messages
+	}
+	snippets, err := retriever.Retrieve(ctx, input)
+	if err != nil {
+		logging.WarnfCtx(ctx, "VoiceAgent: knowledge retrieval failed: %v", err)
+		return messages
+	}
+	if len(snippets) == 0 {
+		return messages
+	}
+	logging.InfofCtx(ctx, "VoiceAgent: retrieved %d knowledge snippet(s)", len(snippets))
+
+	last := len(messages) - 1
+	result := make([]*schema.Message, 0, len(messages)+1)
+	result = append(result, messages[:last]...)
+	result = append(result, schema.SystemMessage(knowledgePrompt(snippets)))
+	return append(result, messages[last])
+}
+
+// knowledgePrompt 把检索到的片段渲染为参考资料提示
+func knowledgePrompt(snippets []knowledge.Snippet) string {
+	var b strings.Builder
+	b.WriteString("参考资料（来自本地知识库，回答相关问题时优先依据这些内容；资料未涉及时如实说明）：\n")
+	for i, snippet := range snippets {
+		fmt.Fprintf(&b, "[%d] %s\n", i+1, strings.TrimSpace(snippet.Text))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,57 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/liuscraft/orion-x/internal/knowledge"
+)
+
+type stubRetriever struct {
+	snippets []knowledge.Snippet
+	err      error
+}
+
+func (s stubRetriever) Retrieve(ctx context.Context, query string) ([]knowledge.Snippet, error) {
+	return s.snippets, s.err
+}
+
+func TestWithKnowledge(t *testing.T) {
+	snippets := []knowledge.Snippet{
+		{Source: "expense.md", Text: "报销需在30天内提交发票。", Score: 0.8},
+		{Source: "expense.md", Text: "经理审批后财务打款。", Score: 0.6},
+	}
+	tests := []struct {
+		name      string
+		retriever KnowledgeRetriever
+		wantLen   int
+	}{
+		{"nil retriever", nil, 2},
+		{"no snippets", stubRetriever{}, 2},
+		{"retrieval error", stubRetriever{err: errors.New("boom")}, 2},
+		{"snippets", stubRetriever{snippets: snippets}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			messages := buildMessages(context.Background(), "system", "我们公司的报销流程")
+			messages = withKnowledge(context.Background(), tt.retriever, messages, "我们公司的报销流程")
+			if len(messages) != tt.wantLen {
+				t.Fatalf("len(messages) = %d, want %d", len(messages), tt.wantLen)
+			}
+			last := messages[len(messages)-1]
+			if last.Role != schema.User {
+				t.Errorf("last message role = %s, want user", last.Role)
+			}
+			if tt.wantLen == 3 {
+				ref := messages[1]
+				if ref.Role != schema.System || !strings.Contains(ref.Content, "[1] 报销需在30天内提交发票。") ||
+					!strings.Contains(ref.Content, "[2] 经理审批后财务打款。") {
+					t.Errorf("unexpected knowledge message: %q", ref.Content)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+)
+
+// languageNames 语言代码对应的名称，用于提示模型回复语言
+var languageNames = map[string]string{
+	"zh": "中文",
+	"en": "English",
+	"ja": "日本語",
+	"ko": "한국어",
+}
+
+type languageKey struct{}
+
+// WithLanguage 将检测到的用户语言（如 "zh"、"en"）附加到 ctx，VoiceAgent 会要求模型用该语言回复
+func WithLanguage(ctx context.Context, language string) context.Context {
+	return context.WithValue(ctx, languageKey{}, language)
+}
+
+// LanguageFromContext 从 ctx 中取出用户语言
+func LanguageFromContext(ctx context.Context) (string, bool) {
+	if ctx == nil {
+		return "", false
+	}
+	language, ok := ctx.Value(languageKey{}).(string)
+	return language, ok && language != ""
+}
+
+// LanguageName 返回语言代码对应的名称，未知代码原样返回（允许直接传入语言名称）
+func LanguageName(language string) string {
+	if name, ok := languageNames[language]; ok {
+		return name
+	}
+	return language
+}
+
+// languagePrompt 生成回复语言说明，覆盖系统提示词中的默认语言
+func languagePrompt(language string) string {
+	name := LanguageName(language)
+	return fmt.Sprintf("用户这句话使用的语言是%s，请使用%s回答。", name, name)
+}
@@ -0,0 +1,18 @@
+package agent
+
+import "github.com/liuscraft/orion-x/internal/tools"
+
+// missingParams 按工具定义检查必填参数，返回缺少的参数及追问话术；没有定义的工具不检查
+func missingParams(definitions []tools.ToolDefinition, tool string, args map[string]interface{}) []MissingParam {
+	for _, definition := range definitions {
+		if definition.Name != tool {
+			continue
+		}
+		var missing []MissingParam
+		for _, name := range definition.MissingParameters(args) {
+			missing = append(missing, MissingParam{Name: name, Question: definition.AskFor(name)})
+		}
+		return missing
+	}
+	return nil
+}
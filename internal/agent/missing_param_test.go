@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// toolCallsStreamer 返回一条只包含给定工具调用的回复
+type toolCallsStreamer struct {
+	calls []schema.ToolCall
+}
+
+func (s *toolCallsStreamer) Stream(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("", s.calls)}), nil
+}
+
+func TestVoiceAgentReportsMissingParams(t *testing.T) {
+	catalog := []tools.ToolDefinition{tools.GetWeatherDefinition, tools.CreateEventDefinition}
+	types := toolTypesFromDefinitions(catalog, nil)
+	call := func(name, args string) schema.ToolCall {
+		return schema.ToolCall{Function: schema.FunctionCall{Name: name, Arguments: args}}
+	}
+	v := &voiceAgentImpl{
+		providers: []llmProvider{{name: "primary", model: &toolCallsStreamer{calls: []schema.ToolCall{
+			call("getWeather", `{}`),
+			call("createEvent", `{"title": "开会", "start": ""}`),
+			call("getWeather", `{"city": "北京"}`),
+			call("unknownTool", `{}`),
+		}}}},
+		emotionExtractor:  NewEmotionExtractor(),
+		markdownFilter:    NewMarkdownFilter(),
+		toolClassifier:    NewToolClassifierWithTypes(types),
+		actionResponseGen: NewActionResponseGenerator(),
+		promptBuilder:     NewPromptBuilder(PromptConfig{}),
+		tools:             catalog,
+	}
+	events, err := v.Process(context.Background(), "天气怎么样")
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	var missing []*MissingParamEvent
+	var requested []string
+	for event := range events {
+		switch e := event.(type) {
+		case *MissingParamEvent:
+			missing = append(missing, e)
+		case *ToolCallRequestedEvent:
+			requested = append(requested, e.Tool)
+		}
+	}
+	if !reflect.DeepEqual(requested, []string{"getWeather", "unknownTool"}) {
+		t.Errorf("requested tools = %v", requested)
+	}
+	if len(missing) != 2 {
+		t.Fatalf("got %d MissingParamEvent, want 2", len(missing))
+	}
+	if want := []MissingParam{{Name: "city", Question: "请问要查哪个城市？"}}; missing[0].Tool != "getWeather" || !reflect.DeepEqual(missing[0].Missing, want) {
+		t.Errorf("missing[0] = %+v", missing[0])
+	}
+	if missing[1].Args["title"] != "开会" || len(missing[1].Missing) != 1 || missing[1].Missing[0].Name != "start" {
+		t.Errorf("missing[1] = %+v", missing[1])
+	}
+}
@@ -52,16 +52,23 @@ type MarkdownFilter interface {
 }
 
 // markdownFilter Markdown过滤器实现（使用 pkg/markdown）
-type markdownFilter struct{}
+type markdownFilter struct {
+	options markdown.Options
+}
 
 func NewMarkdownFilter() MarkdownFilter {
 	return &markdownFilter{}
 }
 
+// NewMarkdownFilterWithOptions 创建带选项的 Markdown 过滤器（如 markdown.SpeechOptions 去除 emoji、代码并转换符号）
+func NewMarkdownFilterWithOptions(options markdown.Options) MarkdownFilter {
+	return &markdownFilter{options: options}
+}
+
 // Filter 过滤Markdown标记
 func (f *markdownFilter) Filter(text string) string {
 	// 使用 pkg/markdown 进行过滤
-	result := markdown.Filter(text)
+	result := markdown.FilterWithOptions(text, f.options)
 	// 移除情绪标签
 	return removeEmotionTags(result)
 }
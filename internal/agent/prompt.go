@@ -0,0 +1,135 @@
+package agent
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPersona  = "你是一个语音助手。"
+	defaultLanguage = "中文"
+)
+
+// defaultSystemPromptTemplate 默认系统提示词模板
+// 支持的变量：{{persona}}、{{language}}、{{date}}、{{time}}、{{weekday}}、{{tools}}，
+// 以及 PromptConfig.Variables 中的自定义变量
+const defaultSystemPromptTemplate = `{{persona}}
+请使用{{language}}回答。今天是{{date}}，{{weekday}}。
+
+规则：
+需要实时信息（如时间、天气）或执行操作时，请调用下列工具获取准确结果，不要编造。
+
+工具定义：
+{{tools}}
+
+情绪标注：
+在每句回复的开头用 [EMO:情绪] 标注语气，情绪可选 happy、sad、angry、calm、excited，
+例如：[EMO:happy]今天天气很好！语气不变时可以省略标签。`
+
+// PromptConfig 系统提示词配置
+type PromptConfig struct {
+	SystemPrompt string            // 系统提示词模板，为空时使用默认模板
+	Persona      string            // 人设，对应 {{persona}}
+	Language     string            // 回复语言，对应 {{language}}
+	Tools        []ToolInfo        // 工具说明，渲染为 {{tools}}
+	Variables    map[string]string // 自定义模板变量
+	Response     ResponsePolicy    // 回复长度要求，追加在模板之后
+}
+
+// DefaultPromptConfig 默认系统提示词配置
+func DefaultPromptConfig() PromptConfig {
+	return PromptConfig{
+		SystemPrompt: defaultSystemPromptTemplate,
+		Persona:      defaultPersona,
+		Language:     defaultLanguage,
+		Tools: []ToolInfo{
+			{Name: "getTime", Description: "获取当前时间，返回日期、时间、星期、时区等信息", Type: ToolTypeQuery},
+			{Name: "getWeather", Description: "获取指定城市的天气信息，需要参数 city（城市名称）", Type: ToolTypeQuery},
+		},
+	}
+}
+
+// PromptBuilder 根据配置渲染系统提示词
+// 每次调用 Build 都会重新计算 {{date}} 等时间变量，保证长时间运行时日期正确
+type PromptBuilder struct {
+	config PromptConfig
+	now    func() time.Time
+}
+
+func NewPromptBuilder(config PromptConfig) *PromptBuilder {
+	defaults := DefaultPromptConfig()
+	if strings.TrimSpace(config.SystemPrompt) == "" {
+		config.SystemPrompt = defaults.SystemPrompt
+	}
+	if strings.TrimSpace(config.Persona) == "" {
+		config.Persona = defaults.Persona
+	}
+	if strings.TrimSpace(config.Language) == "" {
+		config.Language = defaults.Language
+	}
+	if config.Tools == nil {
+		config.Tools = defaults.Tools
+	}
+	return &PromptBuilder{
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// Build 渲染系统提示词
+func (b *PromptBuilder) Build() string {
+	return b.render(b.config.Tools)
+}
+
+// BuildWithTools 渲染系统提示词，{{tools}} 只包含 names 中列出的工具（意图路由选出的子集）
+func (b *PromptBuilder) BuildWithTools(names []string) string {
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+	selected := make([]ToolInfo, 0, len(names))
+	for _, tool := range b.config.Tools {
+		if wanted[tool.Name] {
+			selected = append(selected, tool)
+		}
+	}
+	return b.render(selected)
+}
+
+func (b *PromptBuilder) render(tools []ToolInfo) string {
+	now := b.now()
+	vars := map[string]interface{}{
+		"persona":  b.config.Persona,
+		"language": b.config.Language,
+		"date":     now.Format("2006-01-02"),
+		"time":     now.Format("15:04"),
+		"weekday":  chineseWeekday(now.Weekday()),
+		"tools":    formatTools(tools),
+	}
+	for key, value := range b.config.Variables {
+		vars[key] = value
+	}
+	prompt := applyTemplate(b.config.SystemPrompt, vars)
+	if response := b.config.Response.Prompt(); response != "" {
+		prompt += "\n\n" + response
+	}
+	return prompt
+}
+
+// formatTools 将工具说明渲染为列表，按名称排序保证输出稳定
+func formatTools(tools []ToolInfo) string {
+	sorted := append([]ToolInfo(nil), tools...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	lines := make([]string, 0, len(sorted))
+	for _, tool := range sorted {
+		lines = append(lines, "- "+tool.Name+": "+tool.Description)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func chineseWeekday(weekday time.Weekday) string {
+	names := [...]string{"星期日", "星期一", "星期二", "星期三", "星期四", "星期五", "星期六"}
+	return names[weekday]
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPromptBuilderBuild(t *testing.T) {
+	fixed := time.Date(2025, 3, 14, 9, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		config   PromptConfig
+		contains []string
+		excludes []string
+	}{
+		{
+			name:   "default template",
+			config: PromptConfig{},
+			contains: []string{
+				defaultPersona,
+				"请使用中文回答",
+				"2025-03-14",
+				"星期五",
+				"- getTime: ",
+				"- getWeather: ",
+				"[EMO:情绪]",
+			},
+			excludes: []string{"{{"},
+		},
+		{
+			name: "custom persona and tools",
+			config: PromptConfig{
+				Persona:  "你是一个幽默的管家，名叫小O。",
+				Language: "English",
+				Tools: []ToolInfo{
+					{Name: "playMusic", Description: "播放音乐"},
+				},
+			},
+			contains: []string{"小O", "请使用English回答", "- playMusic: 播放音乐"},
+			excludes: []string{"- getTime"},
+		},
+		{
+			name: "response policy appended to custom template",
+			config: PromptConfig{
+				SystemPrompt: "{{persona}}",
+				Response:     ResponsePolicy{MaxSentences: 5, MaxChars: 200, SummarizeOver: 3},
+			},
+			contains: []string{"不超过 5 句话、200 个字", "超过 3 句话，先用一两句概括要点"},
+		},
+		{
+			name: "custom template with variables",
+			config: PromptConfig{
+				SystemPrompt: "{{persona}} 现在是 {{time}}，主人是{{owner}}。",
+				Variables:    map[string]string{"owner": "小明"},
+			},
+			contains: []string{defaultPersona + " 现在是 09:30，主人是小明。"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := NewPromptBuilder(tt.config)
+			builder.now = func() time.Time { return fixed }
+
+			got := builder.Build()
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("prompt missing %q:\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("prompt should not contain %q:\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// 支持的 LLM 服务商
+const (
+	ProviderOpenAI = "openai" // OpenAI 兼容的 Chat Completions 接口（智谱、DashScope 兼容模式等）
+	ProviderGemini = "gemini" // Google Gemini 原生 streamGenerateContent 接口
+)
+
+// chatModelFactory 按服务商创建流式调用接口，definitions 为需要绑定的工具（function calling）
+type chatModelFactory func(ctx context.Context, endpoint LLMEndpoint, definitions []tools.ToolDefinition) (chatStreamer, error)
+
+// chatModelFactories 服务商到创建函数的映射
+var chatModelFactories = map[string]chatModelFactory{
+	ProviderOpenAI: newOpenAIChatModel,
+	ProviderGemini: newGeminiChatModel,
+}
+
+// providerDefaults 服务商的默认地址和模型
+var providerDefaults = map[string]LLMEndpoint{
+	ProviderOpenAI: {BaseURL: defaultLLMBaseURL, Model: defaultLLMModel},
+	ProviderGemini: {BaseURL: defaultGeminiBaseURL, Model: defaultGeminiModel},
+}
+
+// DefaultEndpoint 返回服务商的默认地址和模型，未知服务商返回零值
+func DefaultEndpoint(provider string) LLMEndpoint {
+	if provider == "" {
+		provider = ProviderOpenAI
+	}
+	endpoint := providerDefaults[provider]
+	endpoint.Provider = provider
+	return endpoint
+}
+
+// newChatModel 按 endpoint.Provider 创建 LLM 流式调用接口
+func newChatModel(ctx context.Context, endpoint LLMEndpoint, definitions []tools.ToolDefinition) (chatStreamer, error) {
+	factory, ok := chatModelFactories[endpoint.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown llm provider %q", endpoint.Provider)
+	}
+	return factory(ctx, endpoint, definitions)
+}
+
+// newOpenAIChatModel 基于 eino OpenAI 适配器创建流式调用接口
+func newOpenAIChatModel(ctx context.Context, endpoint LLMEndpoint, definitions []tools.ToolDefinition) (chatStreamer, error) {
+	chatModel, err := openai.NewChatModel(ctx, &openai.ChatModelConfig{
+		BaseURL: endpoint.BaseURL,
+		Model:   endpoint.Model,
+		APIKey:  endpoint.APIKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(definitions) == 0 {
+		return chatModel, nil
+	}
+	bound, err := chatModel.WithTools(toEinoTools(definitions))
+	if err != nil {
+		return nil, fmt.Errorf("bind tools: %w", err)
+	}
+	return bound, nil
+}
@@ -0,0 +1,35 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResponsePolicy 回复长度要求，渲染后追加到系统提示词末尾（自定义模板同样生效）
+// 语音回复需要逐字朗读，过长的回答会让用户等上几分钟；Orchestrator 另按同样的上限硬截断
+type ResponsePolicy struct {
+	MaxSentences  int // 回答不超过的句数，0 表示不限制
+	MaxChars      int // 回答不超过的字数，0 表示不限制
+	SummarizeOver int // 完整回答超过该句数时先概括要点并询问是否展开，0 表示不要求
+}
+
+// Prompt 生成回复长度说明，没有任何限制时返回空字符串
+func (p ResponsePolicy) Prompt() string {
+	if p.MaxSentences <= 0 && p.MaxChars <= 0 && p.SummarizeOver <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("回复长度：你的回答会被逐字朗读给用户，请简洁、口语化，不要使用 Markdown、列表或表格。")
+	switch {
+	case p.MaxSentences > 0 && p.MaxChars > 0:
+		fmt.Fprintf(&b, "每次回答不超过 %d 句话、%d 个字。", p.MaxSentences, p.MaxChars)
+	case p.MaxSentences > 0:
+		fmt.Fprintf(&b, "每次回答不超过 %d 句话。", p.MaxSentences)
+	case p.MaxChars > 0:
+		fmt.Fprintf(&b, "每次回答不超过 %d 个字。", p.MaxChars)
+	}
+	if p.SummarizeOver > 0 {
+		fmt.Fprintf(&b, "如果完整回答会超过 %d 句话，先用一两句概括要点，再问用户是否需要详细展开。", p.SummarizeOver)
+	}
+	return b.String()
+}
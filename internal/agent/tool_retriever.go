@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/liuscraft/orion-x/internal/knowledge"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// defaultToolTopK 未配置 TopK 时每轮选择的工具数
+const defaultToolTopK = 5
+
+// ToolRetrieverConfig 工具检索：按用户输入与工具名称、说明的向量相似度，每轮选出最相关的 TopK 个工具
+type ToolRetrieverConfig struct {
+	TopK     int                // 每轮选择的工具数，工具总数不超过该值时不检索；<= 0 时为 5
+	MinScore float64            // 相似度低于该值的工具不选，0 表示只按 TopK
+	Always   []string           // 每轮都绑定的工具（如 setVolume），不占 TopK
+	Embedder knowledge.Embedder // nil 时使用本地哈希向量（字符 n-gram，相当于关键词匹配）
+}
+
+// toolRetriever 基于向量检索的 ToolSelector，工具向量按名称缓存，说明变化时重新计算
+type toolRetriever struct {
+	config   ToolRetrieverConfig
+	embedder knowledge.Embedder
+	always   map[string]bool
+
+	mu      sync.Mutex
+	vectors map[string]toolVector
+}
+
+type toolVector struct {
+	text   string
+	vector []float32
+}
+
+// NewToolRetriever 创建基于检索的工具选择器
+func NewToolRetriever(config ToolRetrieverConfig) ToolSelector {
+	if config.TopK <= 0 {
+		config.TopK = defaultToolTopK
+	}
+	embedder := config.Embedder
+	if embedder == nil {
+		embedder = knowledge.NewHashEmbedder(0)
+	}
+	always := make(map[string]bool, len(config.Always))
+	for _, name := range config.Always {
+		always[name] = true
+	}
+	return &toolRetriever{
+		config:   config,
+		embedder: embedder,
+		always:   always,
+		vectors:  make(map[string]toolVector),
+	}
+}
+
+func (r *toolRetriever) SelectTools(ctx context.Context, input string, catalog []tools.ToolDefinition) ([]tools.ToolDefinition, error) {
+	if len(catalog) <= r.config.TopK+len(r.always) {
+		return nil, nil
+	}
+	vectors, err := r.catalogVectors(ctx, catalog)
+	if err != nil {
+		return nil, err
+	}
+	query, err := r.embedder.Embed(ctx, []string{input})
+	if err != nil {
+		return nil, fmt.Errorf("embed input: %w", err)
+	}
+	if len(query) != 1 {
+		return nil, fmt.Errorf("embed input: got %d vectors", len(query))
+	}
+
+	type scored struct {
+		index int
+		score float64
+	}
+	candidates := make([]scored, 0, len(catalog))
+	for i, definition := range catalog {
+		if r.always[definition.Name] {
+			continue
+		}
+		if score := cosine(query[0], vectors[i]); score > 0 && score >= r.config.MinScore {
+			candidates = append(candidates, scored{index: i, score: score})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	if len(candidates) > r.config.TopK {
+		candidates = candidates[:r.config.TopK]
+	}
+
+	selected := make([]tools.ToolDefinition, 0, len(candidates)+len(r.always))
+	for _, candidate := range candidates {
+		selected = append(selected, catalog[candidate.index])
+	}
+	for _, definition := range catalog {
+		if r.always[definition.Name] {
+			selected = append(selected, definition)
+		}
+	}
+	return selected, nil
+}
+
+// catalogVectors 返回与 catalog 顺序一致的工具向量，只为新增或说明变化的工具调用 Embedder
+func (r *toolRetriever) catalogVectors(ctx context.Context, catalog []tools.ToolDefinition) ([][]float32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var missing []int
+	var texts []string
+	for i, definition := range catalog {
+		text := toolSearchText(definition)
+		if cached, ok := r.vectors[definition.Name]; !ok || cached.text != text {
+			missing = append(missing, i)
+			texts = append(texts, text)
+		}
+	}
+	if len(texts) > 0 {
+		embedded, err := r.embedder.Embed(ctx, texts)
+		if err != nil {
+			return nil, fmt.Errorf("embed tools: %w", err)
+		}
+		if len(embedded) != len(texts) {
+			return nil, fmt.Errorf("embed tools: got %d vectors for %d tools", len(embedded), len(texts))
+		}
+		for j, i := range missing {
+			r.vectors[catalog[i].Name] = toolVector{text: texts[j], vector: embedded[j]}
+		}
+	}
+
+	vectors := make([][]float32, len(catalog))
+	for i, definition := range catalog {
+		vectors[i] = r.vectors[definition.Name].vector
+	}
+	return vectors, nil
+}
+
+// toolSearchText 参与检索的工具文本：拆开驼峰的名称、说明和参数说明
+func toolSearchText(definition tools.ToolDefinition) string {
+	var name strings.Builder
+	for i, r := range definition.Name {
+		if i > 0 && unicode.IsUpper(r) {
+			name.WriteByte(' ')
+		}
+		name.WriteRune(unicode.ToLower(r))
+	}
+	return name.String() + " " + definition.Summary()
+}
+
+// cosine 余弦相似度，任一向量为零向量时为 0
+func cosine(a, b []float32) float64 {
+	n := min(len(a), len(b))
+	var sum, normA, normB float64
+	for i := 0; i < n; i++ {
+		sum += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return sum / math.Sqrt(normA*normB)
+}
@@ -0,0 +1,138 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/liuscraft/orion-x/internal/knowledge"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// countingEmbedder 记录 Embed 调用的文本数
+type countingEmbedder struct {
+	knowledge.Embedder
+	texts int
+}
+
+func (c *countingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	c.texts += len(texts)
+	return c.Embedder.Embed(ctx, texts)
+}
+
+func TestToolRetrieverSelectTools(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ToolRetrieverConfig
+		input  string
+		want   []string // nil 表示不检索、使用全部工具
+	}{
+		{name: "top match", config: ToolRetrieverConfig{TopK: 1}, input: "北京天气怎么样", want: []string{"getWeather"}},
+		{name: "name keywords", config: ToolRetrieverConfig{TopK: 1}, input: "play music please", want: []string{"playMusic"}},
+		{name: "always bound", config: ToolRetrieverConfig{TopK: 1, Always: []string{"setVolume"}}, input: "现在几点了，当前时间", want: []string{"getTime", "setVolume"}},
+		{name: "min score filters unrelated tools", config: ToolRetrieverConfig{TopK: 2, MinScore: 0.3}, input: "讲个笑话", want: []string{}},
+		{name: "small catalog", config: ToolRetrieverConfig{TopK: 4}, input: "北京天气怎么样"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := NewToolRetriever(tt.config).SelectTools(context.Background(), tt.input, routerCatalog)
+			if err != nil {
+				t.Fatalf("SelectTools() error = %v", err)
+			}
+			if (selected == nil) != (tt.want == nil) {
+				t.Fatalf("SelectTools() = %v, want %v", selected, tt.want)
+			}
+			if got := toolNames(selected); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("SelectTools() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolRetrieverCachesToolVectors(t *testing.T) {
+	embedder := &countingEmbedder{Embedder: knowledge.NewHashEmbedder(0)}
+	retriever := NewToolRetriever(ToolRetrieverConfig{TopK: 1, Embedder: embedder})
+	ctx := context.Background()
+
+	for _, input := range []string{"天气", "音乐"} {
+		if _, err := retriever.SelectTools(ctx, input, routerCatalog); err != nil {
+			t.Fatalf("SelectTools() error = %v", err)
+		}
+	}
+	if want := len(routerCatalog) + 2; embedder.texts != want {
+		t.Fatalf("embedded %d texts, want %d (tools once, then one per input)", embedder.texts, want)
+	}
+
+	changed := append([]tools.ToolDefinition(nil), routerCatalog...)
+	changed[0].Description = "获取当前日期和时间"
+	if _, err := retriever.SelectTools(ctx, "几点", changed); err != nil {
+		t.Fatalf("SelectTools() error = %v", err)
+	}
+	if want := len(routerCatalog) + 4; embedder.texts != want {
+		t.Errorf("embedded %d texts, want %d (only the changed tool re-embedded)", embedder.texts, want)
+	}
+}
+
+// toolCallStreamer 记录工具选项，回复一次固定的工具调用
+type toolCallStreamer struct {
+	tool  string
+	tools [][]*schema.ToolInfo
+}
+
+func (s *toolCallStreamer) Stream(ctx context.Context, in []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	s.tools = append(s.tools, model.GetCommonOptions(nil, opts...).Tools)
+	call := schema.ToolCall{ID: "call_1", Function: schema.FunctionCall{Name: s.tool, Arguments: "{}"}}
+	return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("", []schema.ToolCall{call})}), nil
+}
+
+func TestVoiceAgentToolSelectionStats(t *testing.T) {
+	tests := []struct {
+		name      string
+		shadow    bool
+		called    string
+		wantBound bool // 是否只绑定了选中的工具
+		wantHits  int
+	}{
+		{name: "hit", called: "getWeather", wantBound: true, wantHits: 1},
+		{name: "miss", called: "getTime", wantBound: true},
+		{name: "shadow binds all tools", shadow: true, called: "getTime"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			streamer := &toolCallStreamer{tool: tt.called}
+			types := toolTypesFromDefinitions(routerCatalog, nil)
+			v := &voiceAgentImpl{
+				providers:         []llmProvider{{name: "primary", model: streamer}},
+				emotionExtractor:  NewEmotionExtractor(),
+				markdownFilter:    NewMarkdownFilter(),
+				toolClassifier:    NewToolClassifierWithTypes(types),
+				actionResponseGen: NewActionResponseGenerator(),
+				promptBuilder:     NewPromptBuilder(PromptConfig{Tools: mergeToolInfos(routerCatalog, nil, types)}),
+				tools:             routerCatalog,
+				toolSelector:      fixedSelector{names: []string{"getWeather"}},
+				selectionShadow:   tt.shadow,
+			}
+			events, err := v.Process(context.Background(), "北京天气怎么样")
+			if err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			for range events {
+			}
+
+			if bound := streamer.tools[0] != nil; bound != tt.wantBound {
+				t.Errorf("bound selected tools = %v, want %v", bound, tt.wantBound)
+			}
+			stats := v.ToolSelectionStats()
+			want := ToolSelectionStats{Catalog: len(routerCatalog), Turns: 1, Selected: 1, ToolCalls: 1, Hits: tt.wantHits}
+			stats.Latency = 0
+			if stats != want {
+				t.Errorf("ToolSelectionStats() = %+v, want %+v", stats, want)
+			}
+			if got := stats.Recall(); got != float64(tt.wantHits) {
+				t.Errorf("Recall() = %v, want %d", got, tt.wantHits)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package agent
+
+import (
+	"github.com/cloudwego/eino/schema"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// toEinoTools 把工具定义转换为 eino ToolInfo，用于绑定到 ChatModel（function calling）
+func toEinoTools(definitions []tools.ToolDefinition) []*schema.ToolInfo {
+	infos := make([]*schema.ToolInfo, 0, len(definitions))
+	for _, definition := range definitions {
+		params := make(map[string]*schema.ParameterInfo, len(definition.Parameters))
+		for name, param := range definition.Parameters {
+			params[name] = &schema.ParameterInfo{
+				Type:     schema.DataType(param.Type),
+				Desc:     param.Description,
+				Enum:     param.Enum,
+				Required: param.Required,
+			}
+		}
+		infos = append(infos, &schema.ToolInfo{
+			Name:        definition.Name,
+			Desc:        definition.Description,
+			ParamsOneOf: schema.NewParamsOneOfByParams(params),
+		})
+	}
+	return infos
+}
+
+// toolTypesFromDefinitions 按定义中的 Action 标记生成工具分类，explicit 中的配置优先
+func toolTypesFromDefinitions(definitions []tools.ToolDefinition, explicit map[string]ToolType) map[string]ToolType {
+	types := make(map[string]ToolType, len(definitions)+len(explicit))
+	for _, definition := range definitions {
+		if definition.Action {
+			types[definition.Name] = ToolTypeAction
+		} else {
+			types[definition.Name] = ToolTypeQuery
+		}
+	}
+	for name, toolType := range explicit {
+		types[name] = toolType
+	}
+	return types
+}
+
+// mergeToolInfos 由工具定义生成提示词中的工具说明
+// overrides 中同名工具的说明优先（如配置文件的 tool_descriptions），仅出现在 overrides 中的工具保留
+func mergeToolInfos(definitions []tools.ToolDefinition, overrides []ToolInfo, types map[string]ToolType) []ToolInfo {
+	overrideByName := make(map[string]ToolInfo, len(overrides))
+	for _, info := range overrides {
+		overrideByName[info.Name] = info
+	}
+
+	infos := make([]ToolInfo, 0, len(definitions)+len(overrides))
+	seen := make(map[string]bool, len(definitions))
+	for _, definition := range definitions {
+		info := ToolInfo{Name: definition.Name, Description: definition.Summary(), Type: types[definition.Name]}
+		if override, ok := overrideByName[definition.Name]; ok && override.Description != "" {
+			info.Description = override.Description
+		}
+		infos = append(infos, info)
+		seen[definition.Name] = true
+	}
+	for _, info := range overrides {
+		if !seen[info.Name] {
+			infos = append(infos, info)
+		}
+	}
+	return infos
+}
@@ -0,0 +1,50 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+func TestToEinoTools(t *testing.T) {
+	infos := toEinoTools([]tools.ToolDefinition{tools.GetWeatherDefinition})
+	if len(infos) != 1 || infos[0].Name != "getWeather" {
+		t.Fatalf("toEinoTools() = %+v", infos)
+	}
+	schema, err := infos[0].ParamsOneOf.ToJSONSchema()
+	if err != nil {
+		t.Fatalf("ToJSONSchema() error = %v", err)
+	}
+	if _, ok := schema.Properties.Get("city"); !ok {
+		t.Fatalf("schema properties missing city")
+	}
+	if len(schema.Required) != 1 || schema.Required[0] != "city" {
+		t.Fatalf("required = %v, want [city]", schema.Required)
+	}
+}
+
+func TestMergeToolInfos(t *testing.T) {
+	definitions := []tools.ToolDefinition{tools.GetTimeDefinition, tools.PlayMusicDefinition}
+	types := toolTypesFromDefinitions(definitions, map[string]ToolType{"getTime": ToolTypeAction})
+	if types["playMusic"] != ToolTypeAction || types["getTime"] != ToolTypeAction {
+		t.Fatalf("types = %v, want explicit config to override definitions", types)
+	}
+
+	infos := mergeToolInfos(definitions, []ToolInfo{
+		{Name: "getTime", Description: "查询时间"},
+		{Name: "custom", Description: "自定义工具"},
+	}, types)
+	want := map[string]string{
+		"getTime":   "查询时间",
+		"playMusic": tools.PlayMusicDefinition.Summary(),
+		"custom":    "自定义工具",
+	}
+	if len(infos) != len(want) {
+		t.Fatalf("mergeToolInfos() = %+v", infos)
+	}
+	for _, info := range infos {
+		if info.Description != want[info.Name] {
+			t.Errorf("%s description = %q, want %q", info.Name, info.Description, want[info.Name])
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package agent
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// ToolSelectionStats 工具选择的累计统计，用于评估每轮选出的工具是否覆盖 LLM 实际调用的工具
+type ToolSelectionStats struct {
+	Catalog   int           // 工具目录大小
+	Turns     int           // 选出了工具子集的轮次
+	Fallbacks int           // 选择失败、绑定全部工具的轮次
+	Selected  int           // 累计选中的工具数
+	ToolCalls int           // 选出子集的轮次中 LLM 请求的工具调用数
+	Hits      int           // 其中工具在本轮选择结果内的调用数
+	Latency   time.Duration // 累计选择耗时
+}
+
+// Recall 选择结果覆盖的工具调用比例，没有工具调用时为 1；
+// 非影子模式下 LLM 只看得到选中的工具，未命中通常是模型编造了工具名，影子模式下才能反映真实的漏选
+func (s ToolSelectionStats) Recall() float64 {
+	if s.ToolCalls == 0 {
+		return 1
+	}
+	return float64(s.Hits) / float64(s.ToolCalls)
+}
+
+// AvgSelected 平均每轮选中的工具数
+func (s ToolSelectionStats) AvgSelected() float64 {
+	if s.Turns == 0 {
+		return 0
+	}
+	return float64(s.Selected) / float64(s.Turns)
+}
+
+// AvgLatency 平均每轮的选择耗时（包括失败的轮次）
+func (s ToolSelectionStats) AvgLatency() time.Duration {
+	if n := s.Turns + s.Fallbacks; n > 0 {
+		return s.Latency / time.Duration(n)
+	}
+	return 0
+}
+
+// ToolSelectionReporter 可选接口：VoiceAgent 实现后可查询工具选择统计
+type ToolSelectionReporter interface {
+	ToolSelectionStats() ToolSelectionStats
+}
+
+// toolSelectionRecorder 累计工具选择统计，并发安全
+type toolSelectionRecorder struct {
+	mu    sync.Mutex
+	stats ToolSelectionStats
+}
+
+func (r *toolSelectionRecorder) selection(selected []tools.ToolDefinition, err error, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats.Latency += latency
+	switch {
+	case err != nil:
+		r.stats.Fallbacks++
+	case selected != nil:
+		r.stats.Turns++
+		r.stats.Selected += len(selected)
+	}
+}
+
+// toolCall 记录选出子集的轮次中的一次工具调用，返回工具是否在选择结果内
+func (r *toolSelectionRecorder) toolCall(ctx context.Context, tool string, selected []tools.ToolDefinition) bool {
+	hit := false
+	for _, definition := range selected {
+		if definition.Name == tool {
+			hit = true
+			break
+		}
+	}
+	r.mu.Lock()
+	r.stats.ToolCalls++
+	if hit {
+		r.stats.Hits++
+	}
+	r.mu.Unlock()
+	if !hit {
+		logging.InfofCtx(ctx, "VoiceAgent: tool %s was not in selection %v", tool, toolNames(selected))
+	}
+	return hit
+}
+
+func (r *toolSelectionRecorder) snapshot(catalog int) ToolSelectionStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := r.stats
+	stats.Catalog = catalog
+	return stats
+}
+
+// ToolSelectionStats 返回工具选择统计，未配置工具选择时为零值
+func (v *voiceAgentImpl) ToolSelectionStats() ToolSelectionStats {
+	if v.toolSelector == nil {
+		return ToolSelectionStats{}
+	}
+	return v.selection.snapshot(len(v.tools))
+}
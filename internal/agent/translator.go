@@ -0,0 +1,53 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// translationPromptTemplate 翻译模式系统提示词，{{language}} 为目标语言
+const translationPromptTemplate = `你是一名同声传译员。把用户说的每一句话翻译成{{language}}，只输出译文本身。
+不要回答用户的问题，不要解释、总结或添加任何其他内容；保留原意和语气，数字、人名和专有名词要准确。`
+
+// translationReversePrompt 双向翻译时追加的说明，{{source}} 为另一方使用的语言
+const translationReversePrompt = `
+如果用户说的已经是{{language}}，则把它翻译成{{source}}。`
+
+// TranslationConfig 翻译模式配置，语言使用代码（zh、en、ja、ko）或语言名称
+type TranslationConfig struct {
+	Target string // 目标语言
+	Source string // 另一方的语言，非空时双向翻译：目标语言的输入翻译成该语言
+}
+
+// TranslationPromptConfig 生成翻译模式的系统提示词配置（不含工具与情绪标注）
+func TranslationPromptConfig(translation TranslationConfig) PromptConfig {
+	template := translationPromptTemplate
+	variables := map[string]string{}
+	if source := strings.TrimSpace(translation.Source); source != "" {
+		template += translationReversePrompt
+		variables["source"] = LanguageName(source)
+	}
+	return PromptConfig{
+		SystemPrompt: template,
+		Language:     LanguageName(translation.Target),
+		Tools:        []ToolInfo{},
+		Variables:    variables,
+	}
+}
+
+// NewTranslatorAgent 创建翻译模式（同声传译）的 VoiceAgent：把每句识别结果翻译成目标语言，
+// 不调用工具、不检索知识库，其余行为（流式输出、备用 LLM、重试）与普通 Agent 相同
+func NewTranslatorAgent(ctx context.Context, cfg Config, translation TranslationConfig) (VoiceAgent, error) {
+	if strings.TrimSpace(translation.Target) == "" {
+		return nil, errors.New("translation target language is required")
+	}
+	cfg.Prompt = TranslationPromptConfig(translation)
+	cfg.Tools = nil
+	cfg.ToolTypes = nil
+	cfg.ActionResponses = nil
+	cfg.Knowledge = nil
+	cfg.IntentRouter = IntentRouterConfig{}
+	cfg.ToolSelector = nil
+	return NewVoiceAgentWithConfig(ctx, cfg)
+}
@@ -0,0 +1,61 @@
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTranslationPromptConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		translation TranslationConfig
+		contains    []string
+		excludes    []string
+	}{
+		{
+			name:        "one way",
+			translation: TranslationConfig{Target: "en"},
+			contains:    []string{"翻译成English", "只输出译文"},
+			excludes:    []string{"{{source}}", "工具", "EMO"},
+		},
+		{
+			name:        "bidirectional",
+			translation: TranslationConfig{Target: "en", Source: "zh"},
+			contains:    []string{"翻译成English", "如果用户说的已经是English，则把它翻译成中文"},
+		},
+		{
+			name:        "language name",
+			translation: TranslationConfig{Target: "Français"},
+			contains:    []string{"翻译成Français"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompt := NewPromptBuilder(TranslationPromptConfig(tt.translation)).Build()
+			for _, want := range tt.contains {
+				if !strings.Contains(prompt, want) {
+					t.Errorf("prompt missing %q:\n%s", want, prompt)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(prompt, unwanted) {
+					t.Errorf("prompt should not contain %q:\n%s", unwanted, prompt)
+				}
+			}
+		})
+	}
+}
+
+func TestNewTranslatorAgentRequiresTarget(t *testing.T) {
+	if _, err := NewTranslatorAgent(context.Background(), Config{APIKey: "test"}, TranslationConfig{}); err == nil {
+		t.Fatal("expected error without target language")
+	}
+	voiceAgent, err := NewTranslatorAgent(context.Background(), Config{APIKey: "test"}, TranslationConfig{Target: "en"})
+	if err != nil {
+		t.Fatalf("NewTranslatorAgent failed: %v", err)
+	}
+	if impl := voiceAgent.(*voiceAgentImpl); impl.knowledge != nil {
+		t.Error("translator should not use the knowledge base")
+	}
+}
@@ -0,0 +1,45 @@
+package agent
+
+import (
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Usage 单次 Agent 调用的 LLM 用量
+type Usage struct {
+	PromptTokens      int
+	CompletionTokens  int
+	FirstTokenLatency time.Duration // 从发起调用到收到首个文本的耗时，未收到文本时为 0
+}
+
+// TotalTokens 总 token 数
+func (u Usage) TotalTokens() int {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// usageRecorder 从流式响应中收集用量
+type usageRecorder struct {
+	start time.Time
+	usage Usage
+}
+
+func newUsageRecorder() *usageRecorder {
+	return &usageRecorder{start: time.Now()}
+}
+
+// Observe 处理一条流式消息
+// 首个非空文本记录首 token 延迟；token 用量通常只在最后一个 chunk 中返回，取最新值
+func (r *usageRecorder) Observe(msg *schema.Message) {
+	if msg.Content != "" && r.usage.FirstTokenLatency == 0 {
+		r.usage.FirstTokenLatency = time.Since(r.start)
+	}
+	if msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil {
+		r.usage.PromptTokens = msg.ResponseMeta.Usage.PromptTokens
+		r.usage.CompletionTokens = msg.ResponseMeta.Usage.CompletionTokens
+	}
+}
+
+func (r *usageRecorder) Usage() Usage {
+	return r.usage
+}
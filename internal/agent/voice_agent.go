@@ -2,6 +2,9 @@ package agent
 
 import (
 	"context"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/tools"
 )
 
 // VoiceAgent 语音Agent，负责LLM流式调用、工具调用、情绪标注、Markdown过滤
@@ -38,9 +41,25 @@ type ToolInfo struct {
 
 // Config VoiceAgent配置
 type Config struct {
+	Provider        string // LLM 服务商：openai（OpenAI 兼容接口，默认）或 gemini，决定 BaseURL、Model 的默认值
 	APIKey          string
 	BaseURL         string
 	Model           string
 	ToolTypes       map[string]ToolType
 	ActionResponses map[string]string
+	Prompt          PromptConfig  // 系统提示词配置，零值时使用默认提示词
+	Fallbacks       []LLMEndpoint // 备用 LLM，主 LLM 失败时按顺序切换
+	MaxRetries      int           // 每个 LLM 在瞬时错误时的重试次数，0 表示不重试
+	RetryBackoff    time.Duration // 重试间隔
+	// Tools 工具定义（通常来自 ToolExecutor.Definitions），绑定到 LLM 并渲染为提示词中的 {{tools}}
+	// ToolTypes 未指定的工具按定义的 Action 分类，Prompt.Tools 中同名条目的说明优先
+	Tools []tools.ToolDefinition
+	// Knowledge 知识库检索器，非 nil 时每轮把相关资料插入到用户消息之前
+	Knowledge KnowledgeRetriever
+	// IntentRouter 意图路由，开启时每轮先用小模型选择需要的工具
+	IntentRouter IntentRouterConfig
+	// ToolSelector 自定义的每轮工具选择（如 NewToolRetriever），非 nil 时优先于 IntentRouter
+	ToolSelector ToolSelector
+	// ToolSelectionShadow 影子模式：照常选择工具并统计准确率，但仍绑定全部工具，用于上线前评估漏选
+	ToolSelectionShadow bool
 }
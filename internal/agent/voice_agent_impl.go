@@ -4,22 +4,31 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"strings"
 	"sync"
 
-	"github.com/cloudwego/eino-ext/components/model/openai"
+	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/schema"
 	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/tools"
 )
 
 type voiceAgentImpl struct {
-	chatModel         *openai.ChatModel
+	providers         []llmProvider
+	retry             retryPolicy
 	emotionExtractor  EmotionExtractor
 	markdownFilter    MarkdownFilter
 	toolClassifier    *ToolClassifier
 	actionResponseGen *ActionResponseGenerator
+	promptBuilder     *PromptBuilder
+	knowledge         KnowledgeRetriever
+	tools             []tools.ToolDefinition
+	toolSelector      ToolSelector
+	selectionShadow   bool
+	selection         toolSelectionRecorder
 }
 
 const (
@@ -43,29 +52,65 @@ func NewVoiceAgentWithConfig(ctx context.Context, cfg Config) (VoiceAgent, error
 		return nil, err
 	}
 
-	chatModel, err := openai.NewChatModel(ctx, &openai.ChatModelConfig{
-		BaseURL: normalized.BaseURL,
-		Model:   normalized.Model,
-		APIKey:  normalized.APIKey,
-	})
-	if err != nil {
-		return nil, err
+	endpoints := append([]LLMEndpoint{{
+		Name:     "primary",
+		Provider: normalized.Provider,
+		APIKey:   normalized.APIKey,
+		BaseURL:  normalized.BaseURL,
+		Model:    normalized.Model,
+	}}, normalized.Fallbacks...)
+
+	providers := make([]llmProvider, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		streamer, err := newChatModel(ctx, endpoint, normalized.Tools)
+		if err != nil {
+			return nil, fmt.Errorf("create llm %s: %w", endpoint.Name, err)
+		}
+		providers = append(providers, llmProvider{name: endpoint.Name, model: streamer})
+	}
+
+	toolTypes := normalized.ToolTypes
+	prompt := normalized.Prompt
+	if len(normalized.Tools) > 0 {
+		toolTypes = toolTypesFromDefinitions(normalized.Tools, normalized.ToolTypes)
+		prompt.Tools = mergeToolInfos(normalized.Tools, prompt.Tools, toolTypes)
+		logging.Infof("VoiceAgent: bound %d tool(s) to LLM", len(normalized.Tools))
 	}
 
-	classifier := NewToolClassifierWithTypes(normalized.ToolTypes)
+	classifier := NewToolClassifierWithTypes(toolTypes)
 	responseGen := NewActionResponseGeneratorWithTemplates(normalized.ActionResponses)
 
+	selector := normalized.ToolSelector
+	if selector == nil && normalized.IntentRouter.Enable && len(normalized.Tools) > 0 {
+		router, err := newIntentRouter(ctx, normalized.IntentRouter)
+		if err != nil {
+			return nil, fmt.Errorf("create intent router: %w", err)
+		}
+		selector = router
+		logging.Infof("VoiceAgent: intent router enabled (model: %s, max tools: %d)",
+			normalized.IntentRouter.Endpoint.Model, normalized.IntentRouter.MaxTools)
+	}
+	if selector != nil && normalized.ToolSelectionShadow {
+		logging.Infof("VoiceAgent: tool selection in shadow mode, binding all %d tool(s)", len(normalized.Tools))
+	}
+
 	return &voiceAgentImpl{
-		chatModel:         chatModel,
+		providers:         providers,
+		retry:             retryPolicy{maxRetries: normalized.MaxRetries, backoff: normalized.RetryBackoff},
 		emotionExtractor:  NewEmotionExtractor(),
 		markdownFilter:    NewMarkdownFilter(),
 		toolClassifier:    classifier,
 		actionResponseGen: responseGen,
+		promptBuilder:     NewPromptBuilder(prompt),
+		knowledge:         normalized.Knowledge,
+		tools:             normalized.Tools,
+		toolSelector:      selector,
+		selectionShadow:   normalized.ToolSelectionShadow,
 	}, nil
 }
 
 func (v *voiceAgentImpl) Process(ctx context.Context, input string) (<-chan AgentEvent, error) {
-	logging.Infof("VoiceAgent: processing input: %s", input)
+	logging.InfofCtx(ctx, "VoiceAgent: processing input: %s", input)
 	eventChan := make(chan AgentEvent)
 	var wg sync.WaitGroup
 
@@ -74,74 +119,103 @@ func (v *voiceAgentImpl) Process(ctx context.Context, input string) (<-chan Agen
 		defer wg.Done()
 		defer close(eventChan)
 
-		messages := []*schema.Message{
-			schema.SystemMessage(`你是一个语音助手。
-
-规则：
-1. 当用户询问时间时，请使用 getTime 工具获取准确时间。
-
-2. 当用户询问天气时，请使用 getWeather 工具。
-
-工具定义：
-- getTime: 获取当前时间，返回日期、时间、星期、时区等信息
-- getWeather: 获取指定城市的天气信息，需要参数 city（城市名称）`),
-			schema.UserMessage(input),
+		// 选择了工具子集时只绑定和渲染这些工具，影子模式下只统计、仍绑定全部工具
+		systemPrompt := v.promptBuilder.Build()
+		var opts []model.Option
+		selected := v.selectTools(ctx, input)
+		if selected != nil && !v.selectionShadow {
+			systemPrompt = v.promptBuilder.BuildWithTools(toolNames(selected))
+			opts = append(opts, model.WithTools(toEinoTools(selected)))
 		}
+		messages := buildMessages(ctx, systemPrompt, input)
+		messages = withKnowledge(ctx, v.knowledge, messages, input)
 
-		logging.Infof("VoiceAgent: starting LLM stream...")
-		stream, err := v.chatModel.Stream(ctx, messages)
+		logging.InfofCtx(ctx, "VoiceAgent: starting LLM stream...")
+		recorder := newUsageRecorder()
+		result, err := streamWithFallback(ctx, v.providers, v.retry, messages, opts...)
 		if err != nil {
-			logging.Errorf("VoiceAgent: LLM stream error: %v", err)
+			logging.ErrorfCtx(ctx, "VoiceAgent: LLM stream error: %v", err)
 			eventChan <- &FinishedEvent{Error: err}
 			return
 		}
+		stream := result.stream
 		defer stream.Close()
 
+		if result.provider > 0 {
+			provider := v.providers[result.provider].name
+			logging.WarnfCtx(ctx, "VoiceAgent: primary LLM unavailable, degraded to %s: %v", provider, result.reason)
+			eventChan <- &DegradedModeEvent{Provider: provider, Reason: result.reason}
+		}
+
 		currentEmotion := "default"
 		fullText := ""
 		bufferedContent := ""
 		lastFilteredLength := 0
+		emotionParser := NewEmotionTagParser()
+
+		// emitTokens 按顺序输出文本片段，遇到情绪标签时切换情绪
+		emitTokens := func(tokens []EmotionToken) {
+			for _, token := range tokens {
+				if token.Emotion != "" {
+					if token.Emotion != currentEmotion {
+						currentEmotion = token.Emotion
+						logging.InfofCtx(ctx, "VoiceAgent: emotion changed to: %s", currentEmotion)
+						eventChan <- &EmotionChangedEvent{Emotion: currentEmotion}
+					}
+					continue
+				}
+				logging.InfofCtx(ctx, "VoiceAgent: text chunk: %s (emotion: %s)", token.Text, currentEmotion)
+				eventChan <- &TextChunkEvent{Chunk: token.Text, Emotion: currentEmotion}
+				fullText += token.Text
+			}
+		}
 
 		for {
 			msg, err := stream.Recv()
 			if err == io.EOF {
-				logging.Infof("VoiceAgent: LLM stream completed, total text length: %d", len(fullText))
+				if rest := emotionParser.Flush(); rest != "" {
+					emitTokens([]EmotionToken{{Text: rest}})
+				}
+				logging.InfofCtx(ctx, "VoiceAgent: LLM stream completed, total text length: %d", len(fullText))
 				break
 			}
 			if err != nil {
-				logging.Errorf("VoiceAgent: stream receive error: %v", err)
-				eventChan <- &FinishedEvent{Error: err}
+				logging.ErrorfCtx(ctx, "VoiceAgent: stream receive error: %v", err)
+				eventChan <- &FinishedEvent{Error: err, Usage: recorder.Usage()}
 				return
 			}
+			recorder.Observe(msg)
 
 			if msg.Content != "" {
 				bufferedContent += msg.Content
 
-				// emotion := v.emotionExtractor.Extract(bufferedContent)
-				// if emotion != "" && emotion != currentEmotion {
-				// 	currentEmotion = emotion
-				// 	log.Printf("VoiceAgent: emotion changed to: %s", emotion)
-				// 	eventChan <- &EmotionChangedEvent{Emotion: emotion}
-				// }
-
-				// 移除缓冲内容中的情绪标签
-				// cleanBufferedContent := v.markdownFilter.RemoveEmotionTags(bufferedContent)
-				cleanBufferedContent := bufferedContent
-
-				newContent, nextLength := deltaFromBufferedContent(cleanBufferedContent, lastFilteredLength)
-				if newContent != "" {
-					logging.Infof("VoiceAgent: text chunk: %s (emotion: %s)", newContent, currentEmotion)
-					eventChan <- &TextChunkEvent{Chunk: newContent, Emotion: currentEmotion}
-					fullText += newContent
-				}
+				newContent, nextLength := deltaFromBufferedContent(bufferedContent, lastFilteredLength)
 				lastFilteredLength = nextLength
+
+				// 流式解析情绪标签（标签可能跨 chunk），标签本身不会进入播报文本
+				emitTokens(emotionParser.Feed(newContent))
 			}
 
 			for _, toolCall := range msg.ToolCalls {
 				toolType := v.toolClassifier.GetToolType(toolCall.Function.Name)
+				if selected != nil {
+					v.selection.toolCall(ctx, toolCall.Function.Name, selected)
+				}
 				args := parseToolArgs(toolCall.Function.Arguments)
 
-				logging.Infof("VoiceAgent: tool call requested: %s (type: %s), args: %v", toolCall.Function.Name, toolType, args)
+				// 缺少必填参数时不执行，由编排器向用户追问后补全
+				if missing := missingParams(v.tools, toolCall.Function.Name, args); len(missing) > 0 {
+					logging.InfofCtx(ctx, "VoiceAgent: tool call %s is missing required parameter(s) %v, args: %v", toolCall.Function.Name, missing, args)
+					eventChan <- &MissingParamEvent{
+						Tool:     toolCall.Function.Name,
+						Args:     args,
+						ToolType: toolType,
+						Missing:  missing,
+					}
+					continue
+				}
+
+				logging.InfofCtx(ctx, "VoiceAgent: tool call requested: %s (type: %s), args: %v", toolCall.Function.Name, toolType, args)
 				eventChan <- &ToolCallRequestedEvent{
 					Tool:     toolCall.Function.Name,
 					Args:     args,
@@ -155,20 +229,27 @@ func (v *voiceAgentImpl) Process(ctx context.Context, input string) (<-chan Agen
 
 					if emotion != "" && emotion != currentEmotion {
 						currentEmotion = emotion
-						logging.Infof("VoiceAgent: emotion changed to: %s (from action response)", emotion)
+						logging.InfofCtx(ctx, "VoiceAgent: emotion changed to: %s (from action response)", emotion)
 						eventChan <- &EmotionChangedEvent{Emotion: emotion}
 					}
 
 					if filtered != "" {
-						logging.Infof("VoiceAgent: action response: %s", filtered)
+						logging.InfofCtx(ctx, "VoiceAgent: action response: %s", filtered)
 						eventChan <- &TextChunkEvent{Chunk: filtered, Emotion: currentEmotion}
 					}
 				}
 			}
 		}
 
-		logging.Infof("VoiceAgent: processing finished")
-		eventChan <- &FinishedEvent{Error: nil}
+		if question := followUpQuestion(fullText); question != "" {
+			logging.InfofCtx(ctx, "VoiceAgent: reply ends with follow-up question: %s", question)
+			eventChan <- &FollowUpEvent{Question: question}
+		}
+
+		usage := recorder.Usage()
+		logging.InfofCtx(ctx, "VoiceAgent: processing finished (tokens: %d+%d, first token: %v)",
+			usage.PromptTokens, usage.CompletionTokens, usage.FirstTokenLatency)
+		eventChan <- &FinishedEvent{Error: nil, Usage: usage}
 	}()
 
 	return eventChan, nil
@@ -178,6 +259,35 @@ func (v *voiceAgentImpl) GetToolType(tool string) ToolType {
 	return v.toolClassifier.GetToolType(tool)
 }
 
+// buildMessages 构建发送给 LLM 的消息列表
+// 如果 ctx 中带有上一轮的打断上下文，会把用户实际听到的部分作为 assistant 消息，
+// 并追加一条说明，让模型基于实际播放的内容理解用户的纠正；
+// ctx 中带有上一轮的追问时，把追问作为 assistant 消息并说明用户在回答该问题；
+// ctx 中带有检测到的用户语言时，追加一条回复语言说明
+func buildMessages(ctx context.Context, systemPrompt string, input string) []*schema.Message {
+	messages := []*schema.Message{
+		schema.SystemMessage(systemPrompt),
+	}
+
+	if interruption, ok := InterruptionFromContext(ctx); ok {
+		logging.InfofCtx(ctx, "VoiceAgent: carrying over interrupted reply (interruptedAt=%d, spoken=%q)",
+			interruption.InterruptedAt, interruption.SpokenText)
+		if strings.TrimSpace(interruption.SpokenText) != "" {
+			messages = append(messages, schema.AssistantMessage(interruption.SpokenText, nil))
+		}
+		messages = append(messages, schema.SystemMessage(interruption.Prompt()))
+	}
+	if question, ok := FollowUpFromContext(ctx); ok {
+		logging.InfofCtx(ctx, "VoiceAgent: carrying over follow-up question: %s", question)
+		messages = append(messages, schema.AssistantMessage(question, nil), schema.SystemMessage(followUpPrompt(question)))
+	}
+	if language, ok := LanguageFromContext(ctx); ok {
+		messages = append(messages, schema.SystemMessage(languagePrompt(language)))
+	}
+
+	return append(messages, schema.UserMessage(input))
+}
+
 func deltaFromBufferedContent(content string, lastLength int) (string, int) {
 	if lastLength < 0 {
 		lastLength = 0
@@ -192,15 +302,78 @@ func normalizeConfig(cfg Config) (Config, error) {
 	if strings.TrimSpace(cfg.APIKey) == "" {
 		return Config{}, errors.New("llm api_key is required")
 	}
+	if strings.TrimSpace(cfg.Provider) == "" {
+		cfg.Provider = ProviderOpenAI
+	}
+	defaults, ok := providerDefaults[cfg.Provider]
+	if !ok {
+		return Config{}, fmt.Errorf("unknown llm provider %q", cfg.Provider)
+	}
 	if strings.TrimSpace(cfg.BaseURL) == "" {
-		cfg.BaseURL = defaultLLMBaseURL
+		cfg.BaseURL = defaults.BaseURL
 	}
 	if strings.TrimSpace(cfg.Model) == "" {
-		cfg.Model = defaultLLMModel
+		cfg.Model = defaults.Model
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	}
+
+	fallbacks := make([]LLMEndpoint, 0, len(cfg.Fallbacks))
+	for i, endpoint := range cfg.Fallbacks {
+		if strings.TrimSpace(endpoint.Name) == "" {
+			endpoint.Name = fmt.Sprintf("fallback-%d", i+1)
+		}
+		endpoint, err := inheritEndpoint(cfg, endpoint)
+		if err != nil {
+			return Config{}, err
+		}
+		fallbacks = append(fallbacks, endpoint)
+	}
+	cfg.Fallbacks = fallbacks
+
+	if cfg.IntentRouter.Enable {
+		router := cfg.IntentRouter.Endpoint
+		if strings.TrimSpace(router.Name) == "" {
+			router.Name = "intent-router"
+		}
+		router, err := inheritEndpoint(cfg, router)
+		if err != nil {
+			return Config{}, err
+		}
+		cfg.IntentRouter.Endpoint = router
+		if cfg.IntentRouter.MaxTools < 0 {
+			cfg.IntentRouter.MaxTools = 0
+		}
 	}
 	return cfg, nil
 }
 
+// inheritEndpoint 为备用 LLM、路由小模型等端点补全未填写的字段：Provider、APIKey 沿用主 LLM；
+// BaseURL、Model 在服务商与主 LLM 相同时沿用主 LLM，否则使用该服务商的默认值
+func inheritEndpoint(cfg Config, endpoint LLMEndpoint) (LLMEndpoint, error) {
+	if strings.TrimSpace(endpoint.Provider) == "" {
+		endpoint.Provider = cfg.Provider
+	}
+	if strings.TrimSpace(endpoint.APIKey) == "" {
+		endpoint.APIKey = cfg.APIKey
+	}
+	inherit := LLMEndpoint{BaseURL: cfg.BaseURL, Model: cfg.Model}
+	if endpoint.Provider != cfg.Provider {
+		var ok bool
+		if inherit, ok = providerDefaults[endpoint.Provider]; !ok {
+			return LLMEndpoint{}, fmt.Errorf("unknown llm provider %q for %s", endpoint.Provider, endpoint.Name)
+		}
+	}
+	if strings.TrimSpace(endpoint.BaseURL) == "" {
+		endpoint.BaseURL = inherit.BaseURL
+	}
+	if strings.TrimSpace(endpoint.Model) == "" {
+		endpoint.Model = inherit.Model
+	}
+	return endpoint, nil
+}
+
 func parseToolArgs(argsJSON string) map[string]interface{} {
 	result := make(map[string]interface{})
 	if argsJSON == "" {
@@ -1,6 +1,10 @@
 package agent
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/cloudwego/eino/schema"
+)
 
 func TestDeltaFromBufferedContent(t *testing.T) {
 	cases := []struct {
@@ -59,3 +63,29 @@ func TestDeltaFromBufferedContent(t *testing.T) {
 		})
 	}
 }
+
+func TestUsageRecorder(t *testing.T) {
+	recorder := newUsageRecorder()
+
+	recorder.Observe(&schema.Message{})
+	if recorder.Usage().FirstTokenLatency != 0 {
+		t.Fatal("first token latency should wait for content")
+	}
+
+	recorder.Observe(&schema.Message{Content: "你好"})
+	first := recorder.Usage().FirstTokenLatency
+	if first <= 0 {
+		t.Fatal("expected first token latency recorded")
+	}
+
+	recorder.Observe(&schema.Message{
+		Content: "。",
+		ResponseMeta: &schema.ResponseMeta{
+			Usage: &schema.TokenUsage{PromptTokens: 42, CompletionTokens: 7},
+		},
+	})
+	usage := recorder.Usage()
+	if usage.FirstTokenLatency != first || usage.PromptTokens != 42 || usage.CompletionTokens != 7 || usage.TotalTokens() != 49 {
+		t.Fatalf("unexpected usage: %+v", usage)
+	}
+}
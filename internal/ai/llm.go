@@ -1,8 +1,12 @@
+// Package ai 早期的工具调用实验：小模型识别意图、大模型调用多个工具后生成回复。
+//
+// Deprecated: 语音链路的工具路由由 agent.Config.IntentRouter（agent.ToolSelector）提供，本包不再维护。
 package ai
 
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -16,38 +20,74 @@ import (
 	"github.com/liuscraft/orion-x/internal/logging"
 )
 
+const (
+	defaultBaseURL     = "https://open.bigmodel.cn/api/coding/paas/v4"
+	defaultIntentModel = "glm-4-flash"
+	defaultLargeModel  = "glm-4.7"
+)
+
+// Config 工具调用 Agent 的模型配置，由调用方从 AppConfig 的 llm 段填充
+type Config struct {
+	APIKey  string
+	BaseURL string // OpenAI 兼容接口地址，为空时使用智谱默认地址
+	// IntentModel 意图识别与单工具调用的小模型（ReAct Agent 也使用该模型），为空时使用 glm-4-flash
+	IntentModel string
+	// LargeModel 多工具场景下调用工具的大模型，为空时使用 glm-4.7
+	LargeModel string
+	// FinalModel 根据工具结果生成最终回复的模型，为空时与 LargeModel 相同
+	FinalModel string
+}
+
+// normalize 校验 API Key 并填充默认地址和模型
+func (c Config) normalize() (Config, error) {
+	if strings.TrimSpace(c.APIKey) == "" {
+		return c, errors.New("ai: api key is required")
+	}
+	if c.BaseURL == "" {
+		c.BaseURL = defaultBaseURL
+	}
+	if c.IntentModel == "" {
+		c.IntentModel = defaultIntentModel
+	}
+	if c.LargeModel == "" {
+		c.LargeModel = defaultLargeModel
+	}
+	if c.FinalModel == "" {
+		c.FinalModel = c.LargeModel
+	}
+	return c, nil
+}
+
+func (c Config) chatModel(ctx context.Context, model string) (*openai.ChatModel, error) {
+	return openai.NewChatModel(ctx, &openai.ChatModelConfig{
+		BaseURL: c.BaseURL,
+		Model:   model,
+		APIKey:  c.APIKey,
+	})
+}
+
 // CreateToolCallGraph 创建工具调用 Agent (线性流程)
 // 流程：意图识别(小模型) → 工具调用 → 最终生成(大模型)
-func CreateToolCallGraph(ctx context.Context) (compose.Runnable[[]*schema.Message, *schema.Message], error) {
-	key := "b69a4b0f05124a59835be2adf1ac84a5.yvFfw6QQNJDXT7IP"
-	baseURL := "https://open.bigmodel.cn/api/coding/paas/v4"
+func CreateToolCallGraph(ctx context.Context, cfg Config) (compose.Runnable[[]*schema.Message, *schema.Message], error) {
+	cfg, err := cfg.normalize()
+	if err != nil {
+		return nil, err
+	}
 
 	// 1. 小模型：意图识别 + 工具调用
-	intentModel, err := openai.NewChatModel(ctx, &openai.ChatModelConfig{
-		BaseURL: baseURL,
-		Model:   "glm-4-flash",
-		APIKey:  key,
-	})
+	intentModel, err := cfg.chatModel(ctx, cfg.IntentModel)
 	if err != nil {
 		return nil, err
 	}
 
 	// 2. 大模型：用于多工具调用
-	largeModel, err := openai.NewChatModel(ctx, &openai.ChatModelConfig{
-		BaseURL: baseURL,
-		Model:   "glm-4.7",
-		APIKey:  key,
-	})
+	largeModel, err := cfg.chatModel(ctx, cfg.LargeModel)
 	if err != nil {
 		return nil, err
 	}
 
 	// 3. 最终生成模型
-	finalModel, err := openai.NewChatModel(ctx, &openai.ChatModelConfig{
-		BaseURL: baseURL,
-		Model:   "glm-4.7",
-		APIKey:  key,
-	})
+	finalModel, err := cfg.chatModel(ctx, cfg.FinalModel)
 	if err != nil {
 		return nil, err
 	}
@@ -253,16 +293,14 @@ func CreateToolCallGraph(ctx context.Context) (compose.Runnable[[]*schema.Messag
 
 // CreateReActAgent 创建 ReAct Agent
 // ReAct 循环：思考 → 行动 → 观察 → 思考 ... → 最终答案
-func CreateReActAgent(ctx context.Context) (*react.Agent, error) {
-	key := "b69a4b0f05124a59835be2adf1ac84a5.yvFfw6QQNJDXT7IP"
-	baseURL := "https://open.bigmodel.cn/api/coding/paas/v4"
+func CreateReActAgent(ctx context.Context, cfg Config) (*react.Agent, error) {
+	cfg, err := cfg.normalize()
+	if err != nil {
+		return nil, err
+	}
 
 	// 1. 创建 ChatModel（支持工具调用）
-	chatModel, err := openai.NewChatModel(ctx, &openai.ChatModelConfig{
-		BaseURL: baseURL,
-		Model:   "glm-4-flash",
-		APIKey:  key,
-	})
+	chatModel, err := cfg.chatModel(ctx, cfg.IntentModel)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,47 @@
+package ai
+
+import (
+	"context"
+	"testing"
+)
+
+func TestConfigNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		want    Config
+		wantErr bool
+	}{
+		{
+			name:   "defaults",
+			config: Config{APIKey: "k"},
+			want:   Config{APIKey: "k", BaseURL: defaultBaseURL, IntentModel: "glm-4-flash", LargeModel: "glm-4.7", FinalModel: "glm-4.7"},
+		},
+		{
+			name:   "final follows large model",
+			config: Config{APIKey: "k", BaseURL: "http://llm", IntentModel: "small", LargeModel: "large"},
+			want:   Config{APIKey: "k", BaseURL: "http://llm", IntentModel: "small", LargeModel: "large", FinalModel: "large"},
+		},
+		{name: "missing key", config: Config{BaseURL: "http://llm"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.normalize()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("normalize() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateAgentsRequireKey(t *testing.T) {
+	if _, err := CreateToolCallGraph(context.Background(), Config{}); err == nil {
+		t.Error("CreateToolCallGraph() without api key should fail")
+	}
+	if _, err := CreateReActAgent(context.Background(), Config{}); err == nil {
+		t.Error("CreateReActAgent() without api key should fail")
+	}
+}
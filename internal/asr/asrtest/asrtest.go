@@ -0,0 +1,246 @@
+// Package asrtest 提供确定性的 ASR 测试替身，供嵌入 orion-x 的下游代码在不访问真实服务的情况下测试
+package asrtest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/asr"
+)
+
+// ErrNotStarted 未 Start 或已 Close 时发送音频返回
+var ErrNotStarted = errors.New("asrtest: recognizer not started")
+
+// Step 脚本中的一条识别结果：累计收到 AfterBytes 字节音频后回调
+type Step struct {
+	AfterBytes int
+	Result     asr.Result
+}
+
+// Config 假 Recognizer 配置
+type Config struct {
+	// Script 按顺序回放的识别结果，Finish 时把剩余结果全部回调
+	Script []Step
+	// StartDelay Start 返回前的等待时长，模拟建连耗时
+	StartDelay time.Duration
+	// ResultDelay 回调每条结果前的等待时长，模拟识别延迟（在 SendAudio / Finish 中同步等待，保证顺序确定）
+	ResultDelay time.Duration
+
+	// StartErr / SendErr / FinishErr 非空时对应调用返回该错误
+	StartErr  error
+	SendErr   error
+	FinishErr error
+}
+
+// Recognizer 实现 asr.Recognizer：按脚本回放识别结果，并记录收到的音频与调用
+type Recognizer struct {
+	mu       sync.Mutex
+	cfg      Config
+	handler  func(asr.Result)
+	next     int
+	audio    []byte
+	started  bool
+	starts   int
+	finishes int
+	closes   int
+	restarts int
+}
+
+var (
+	_ asr.Recognizer       = (*Recognizer)(nil)
+	_ asr.SessionRestarter = (*Recognizer)(nil)
+)
+
+// NewRecognizer 创建假 Recognizer
+func NewRecognizer(cfg Config) *Recognizer {
+	return &Recognizer{cfg: cfg}
+}
+
+// Final 构造一条 final 结果的脚本步骤
+func Final(afterBytes int, text string) Step {
+	return Step{AfterBytes: afterBytes, Result: asr.Result{Text: text, IsFinal: true}}
+}
+
+// Partial 构造一条中间结果的脚本步骤
+func Partial(afterBytes int, text string) Step {
+	return Step{AfterBytes: afterBytes, Result: asr.Result{Text: text}}
+}
+
+// SetStartErr 修改后续 Start 返回的错误，nil 表示恢复正常
+func (r *Recognizer) SetStartErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cfg.StartErr = err
+}
+
+// SetSendErr 修改后续 SendAudio 返回的错误，用于模拟识别中途断连
+func (r *Recognizer) SetSendErr(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cfg.SendErr = err
+}
+
+func (r *Recognizer) Start(ctx context.Context) error {
+	r.mu.Lock()
+	r.starts++
+	delay, err := r.cfg.StartDelay, r.cfg.StartErr
+	r.mu.Unlock()
+
+	if err := sleep(ctx, delay); err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.started = true
+	r.mu.Unlock()
+	return nil
+}
+
+// Restart 开始新的识别任务，与 Start 一样受 StartDelay / StartErr 控制；脚本继续按累计音频回放
+func (r *Recognizer) Restart(ctx context.Context) error {
+	r.mu.Lock()
+	r.restarts++
+	delay, err := r.cfg.StartDelay, r.cfg.StartErr
+	r.mu.Unlock()
+
+	if err := sleep(ctx, delay); err != nil {
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	r.started = true
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *Recognizer) SendAudio(ctx context.Context, data []byte) error {
+	r.mu.Lock()
+	if r.cfg.SendErr != nil {
+		err := r.cfg.SendErr
+		r.mu.Unlock()
+		return err
+	}
+	if !r.started {
+		r.mu.Unlock()
+		return ErrNotStarted
+	}
+	r.audio = append(r.audio, data...)
+	due := r.takeDue(len(r.audio))
+	r.mu.Unlock()
+
+	return r.deliver(ctx, due)
+}
+
+// Finish 回调剩余的全部脚本结果
+func (r *Recognizer) Finish(ctx context.Context) error {
+	r.mu.Lock()
+	r.finishes++
+	due := r.takeDue(-1)
+	err := r.cfg.FinishErr
+	r.mu.Unlock()
+
+	if derr := r.deliver(ctx, due); derr != nil {
+		return derr
+	}
+	return err
+}
+
+func (r *Recognizer) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closes++
+	r.started = false
+	return nil
+}
+
+func (r *Recognizer) OnResult(handler func(asr.Result)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handler = handler
+}
+
+// Emit 立即回调一条结果，不经过脚本
+func (r *Recognizer) Emit(result asr.Result) {
+	r.mu.Lock()
+	handler := r.handler
+	r.mu.Unlock()
+	if handler != nil {
+		handler(result)
+	}
+}
+
+// Audio 返回收到的全部音频
+func (r *Recognizer) Audio() []byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]byte(nil), r.audio...)
+}
+
+// Starts / Finishes / Closes / Restarts 返回对应方法被调用的次数
+func (r *Recognizer) Starts() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.starts
+}
+
+func (r *Recognizer) Finishes() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.finishes
+}
+
+func (r *Recognizer) Closes() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closes
+}
+
+func (r *Recognizer) Restarts() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.restarts
+}
+
+// takeDue 取出累计音频达到阈值的脚本结果，received<0 表示全部取出；调用方持有锁
+func (r *Recognizer) takeDue(received int) []asr.Result {
+	var due []asr.Result
+	for r.next < len(r.cfg.Script) {
+		step := r.cfg.Script[r.next]
+		if received >= 0 && step.AfterBytes > received {
+			break
+		}
+		due = append(due, step.Result)
+		r.next++
+	}
+	return due
+}
+
+func (r *Recognizer) deliver(ctx context.Context, results []asr.Result) error {
+	for _, result := range results {
+		if err := sleep(ctx, r.cfg.ResultDelay); err != nil {
+			return err
+		}
+		r.Emit(result)
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
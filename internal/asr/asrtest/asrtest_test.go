@@ -0,0 +1,96 @@
+package asrtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/asr"
+)
+
+func TestRecognizerScript(t *testing.T) {
+	r := NewRecognizer(Config{Script: []Step{
+		Partial(320, "你"),
+		Final(640, "你好"),
+		Final(10000, "再见"),
+	}})
+	var got []asr.Result
+	r.OnResult(func(result asr.Result) { got = append(got, result) })
+
+	ctx := context.Background()
+	if err := r.SendAudio(ctx, make([]byte, 320)); !errors.Is(err, ErrNotStarted) {
+		t.Fatalf("SendAudio() before Start error = %v, want ErrNotStarted", err)
+	}
+	if err := r.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	tests := []struct {
+		bytes int
+		want  int
+	}{
+		{bytes: 160, want: 0},
+		{bytes: 160, want: 1},
+		{bytes: 640, want: 2},
+	}
+	for i, tt := range tests {
+		if err := r.SendAudio(ctx, make([]byte, tt.bytes)); err != nil {
+			t.Fatalf("step %d: SendAudio() error = %v", i, err)
+		}
+		if len(got) != tt.want {
+			t.Fatalf("step %d: %d results, want %d", i, len(got), tt.want)
+		}
+	}
+	if got[1].Text != "你好" || !got[1].IsFinal {
+		t.Errorf("result[1] = %+v, want final 你好", got[1])
+	}
+
+	// Finish 回放剩余脚本
+	if err := r.Finish(ctx); err != nil {
+		t.Fatalf("Finish() error = %v", err)
+	}
+	if len(got) != 3 || got[2].Text != "再见" {
+		t.Errorf("results after Finish = %+v", got)
+	}
+	if len(r.Audio()) != 960 || r.Starts() != 1 || r.Finishes() != 1 {
+		t.Errorf("Audio() = %d bytes, Starts() = %d, Finishes() = %d", len(r.Audio()), r.Starts(), r.Finishes())
+	}
+
+	r.Close()
+	if err := r.SendAudio(ctx, make([]byte, 10)); !errors.Is(err, ErrNotStarted) {
+		t.Errorf("SendAudio() after Close error = %v, want ErrNotStarted", err)
+	}
+}
+
+func TestRecognizerLatencyAndErrors(t *testing.T) {
+	r := NewRecognizer(Config{
+		Script:      []Step{Final(0, "好")},
+		ResultDelay: 30 * time.Millisecond,
+		StartErr:    asr.ErrIdleTimeout,
+	})
+	ctx := context.Background()
+	if err := r.Start(ctx); !errors.Is(err, asr.ErrIdleTimeout) {
+		t.Fatalf("Start() error = %v, want ErrIdleTimeout", err)
+	}
+	r.SetStartErr(nil)
+	if err := r.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	var results int
+	r.OnResult(func(asr.Result) { results++ })
+	start := time.Now()
+	if err := r.SendAudio(ctx, []byte{0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if results != 1 || time.Since(start) < 30*time.Millisecond {
+		t.Errorf("results = %d after %v, want 1 after >= ResultDelay", results, time.Since(start))
+	}
+
+	sendErr := errors.New("connection reset")
+	r.SetSendErr(sendErr)
+	if err := r.SendAudio(ctx, []byte{0, 0}); !errors.Is(err, sendErr) {
+		t.Errorf("SendAudio() error = %v, want injected error", err)
+	}
+}
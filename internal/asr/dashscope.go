@@ -8,27 +8,57 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
 
 const defaultDashScopeEndpoint = "wss://dashscope.aliyuncs.com/api-ws/v1/inference"
 
+// DashScopeRecognizer DashScope 实时识别：一个 WebSocket 连接上可以依次运行多个识别任务（run-task），
+// Finish 结束当前任务后可通过 Restart 开始下一个任务
 type DashScopeRecognizer struct {
-	cfg       Config
-	conn      *websocket.Conn
-	onResult  func(Result)
-	writeMu   sync.Mutex
+	cfg      Config
+	onResult func(Result)
+	writeMu  sync.Mutex
+
+	mu   sync.Mutex
+	conn *websocket.Conn // 接收循环在连接断开时置空
+	task *dashScopeTask  // 当前（或最近一次）识别任务
+
+	lastAudioAt atomic.Int64 // 最近一次发送音频的时间（UnixNano）
+}
+
+// dashScopeTask 一次识别任务的状态
+type dashScopeTask struct {
+	id        string
 	startedCh chan struct{}
 	doneCh    chan struct{}
 	errCh     chan error
-	taskID    string
 
 	startedOnce sync.Once
 	doneOnce    sync.Once
+
+	errMu           sync.Mutex
+	err             error       // 任务结束原因，Err() 返回
+	finishRequested atomic.Bool // 已调用 Finish，task-finished 属于正常结束
+}
+
+func newDashScopeTask() *dashScopeTask {
+	return &dashScopeTask{
+		id:        newTaskID(),
+		startedCh: make(chan struct{}),
+		doneCh:    make(chan struct{}),
+		errCh:     make(chan error, 1),
+	}
 }
 
+// keepaliveSilenceMs 保活时补发的静音时长
+const keepaliveSilenceMs = 100
+
 func NewDashScopeRecognizer(cfg Config) (*DashScopeRecognizer, error) {
 	if cfg.APIKey == "" {
 		return nil, errors.New("DASHSCOPE_API_KEY is required")
@@ -47,10 +77,8 @@ func NewDashScopeRecognizer(cfg Config) (*DashScopeRecognizer, error) {
 	}
 
 	return &DashScopeRecognizer{
-		cfg:       cfg,
-		startedCh: make(chan struct{}),
-		doneCh:    make(chan struct{}),
-		errCh:     make(chan error, 1),
+		cfg:  cfg,
+		task: newDashScopeTask(),
 	}, nil
 }
 
@@ -58,36 +86,79 @@ func (r *DashScopeRecognizer) OnResult(handler func(Result)) {
 	r.onResult = handler
 }
 
+// Start 建立连接并开始识别任务；Close 之后可以再次 Start
 func (r *DashScopeRecognizer) Start(ctx context.Context) error {
-	if r.conn != nil {
+	if r.currentConn() != nil {
 		return errors.New("recognizer already started")
 	}
+	return r.runTask(ctx)
+}
 
-	conn, err := r.connect(ctx)
-	if err != nil {
-		return err
+// Restart 结束仍在进行的任务后开始新的识别任务：连接可用时复用，已断开时重新连接
+func (r *DashScopeRecognizer) Restart(ctx context.Context) error {
+	task := r.currentTask()
+	if r.currentConn() != nil && task.started() && !task.done() {
+		if err := r.Finish(ctx); err != nil {
+			// 旧任务无法正常结束，丢弃连接重新建立
+			_ = r.Close()
+		}
 	}
-	r.conn = conn
+	return r.runTask(ctx)
+}
 
-	r.taskID = newTaskID()
-	if err := r.sendRunTask(ctx); err != nil {
-		return err
+// runTask 在现有连接（没有时新建）上发送 run-task 并等待任务开始
+func (r *DashScopeRecognizer) runTask(ctx context.Context) error {
+	task := newDashScopeTask()
+	conn := r.currentConn()
+	if conn == nil {
+		var err error
+		if conn, err = r.connect(ctx); err != nil {
+			return err
+		}
+		r.mu.Lock()
+		r.conn = conn
+		r.task = task
+		r.mu.Unlock()
+		go r.receive(conn)
+	} else {
+		r.mu.Lock()
+		r.task = task
+		r.mu.Unlock()
 	}
 
-	r.startReceiver()
+	if err := r.sendRunTask(conn, task.id); err != nil {
+		return err
+	}
 
 	select {
-	case <-r.startedCh:
+	case <-task.startedCh:
+		r.lastAudioAt.Store(time.Now().UnixNano())
+		if r.cfg.KeepaliveInterval > 0 {
+			go r.keepaliveLoop(task)
+		}
 		return nil
-	case err := <-r.errCh:
+	case err := <-task.errCh:
 		return err
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 }
 
+func (r *DashScopeRecognizer) currentConn() *websocket.Conn {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.conn
+}
+
+func (r *DashScopeRecognizer) currentTask() *dashScopeTask {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.task
+}
+
 func (r *DashScopeRecognizer) SendAudio(ctx context.Context, data []byte) error {
-	if r.conn == nil {
+	conn := r.currentConn()
+	if conn == nil {
 		return errors.New("recognizer not started")
 	}
 	if ctx == nil {
@@ -99,10 +170,11 @@ func (r *DashScopeRecognizer) SendAudio(ctx context.Context, data []byte) error
 	default:
 	}
 
+	r.lastAudioAt.Store(time.Now().UnixNano())
 	result := make(chan error, 1)
 	r.writeMu.Lock()
 	go func() {
-		err := r.conn.WriteMessage(websocket.BinaryMessage, data)
+		err := conn.WriteMessage(websocket.BinaryMessage, data)
 		r.writeMu.Unlock()
 		result <- err
 	}()
@@ -111,33 +183,84 @@ func (r *DashScopeRecognizer) SendAudio(ctx context.Context, data []byte) error
 	case err := <-result:
 		return err
 	case <-ctx.Done():
-		_ = r.conn.Close()
+		_ = conn.Close()
 		return ctx.Err()
 	}
 }
 
+// Finish 结束当前任务并等待服务端返回剩余结果；连接保持，可继续 Restart
 func (r *DashScopeRecognizer) Finish(ctx context.Context) error {
-	if r.conn == nil {
+	conn := r.currentConn()
+	if conn == nil {
 		return errors.New("recognizer not started")
 	}
-	if err := r.sendFinishTask(ctx); err != nil {
+	task := r.currentTask()
+	if task.done() {
+		return nil
+	}
+	task.finishRequested.Store(true)
+	if err := r.sendFinishTask(conn, task.id); err != nil {
 		return err
 	}
 	select {
-	case <-r.doneCh:
+	case <-task.doneCh:
 		return nil
-	case err := <-r.errCh:
+	case err := <-task.errCh:
 		return err
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 }
 
+// Close 关闭连接，进行中的任务随之结束
 func (r *DashScopeRecognizer) Close() error {
-	if r.conn == nil {
+	r.mu.Lock()
+	conn := r.conn
+	r.conn = nil
+	r.mu.Unlock()
+	if conn == nil {
 		return nil
 	}
-	return r.conn.Close()
+	return conn.Close()
+}
+
+// Done 在当前任务结束（正常完成、失败或连接断开）时关闭；Restart 之后对应新的任务
+func (r *DashScopeRecognizer) Done() <-chan struct{} {
+	return r.currentTask().doneCh
+}
+
+// Err 返回当前任务结束的原因；服务端因空闲结束任务时返回 ErrIdleTimeout
+func (r *DashScopeRecognizer) Err() error {
+	task := r.currentTask()
+	task.errMu.Lock()
+	defer task.errMu.Unlock()
+	return task.err
+}
+
+// keepaliveLoop 长时间未发送音频时补发静音帧，任务结束后退出
+func (r *DashScopeRecognizer) keepaliveLoop(task *dashScopeTask) {
+	interval := r.cfg.KeepaliveInterval
+	ticker := time.NewTicker(interval / 2)
+	defer ticker.Stop()
+
+	silence := make([]byte, r.cfg.SampleRate*2*keepaliveSilenceMs/1000)
+	for {
+		select {
+		case <-task.doneCh:
+			return
+		case <-ticker.C:
+		}
+		if task.finishRequested.Load() {
+			return
+		}
+		idle := time.Since(time.Unix(0, r.lastAudioAt.Load()))
+		if idle < interval {
+			continue
+		}
+		if err := r.SendAudio(context.Background(), silence); err != nil {
+			return
+		}
+	}
 }
 
 func (r *DashScopeRecognizer) connect(ctx context.Context) (*websocket.Conn, error) {
@@ -148,7 +271,7 @@ func (r *DashScopeRecognizer) connect(ctx context.Context) (*websocket.Conn, err
 	return conn, err
 }
 
-func (r *DashScopeRecognizer) sendRunTask(ctx context.Context) error {
+func (r *DashScopeRecognizer) sendRunTask(conn *websocket.Conn, taskID string) error {
 	params := map[string]any{
 		"format":      r.cfg.Format,
 		"sample_rate": r.cfg.SampleRate,
@@ -175,7 +298,7 @@ func (r *DashScopeRecognizer) sendRunTask(ctx context.Context) error {
 	msg := runTaskMessage{
 		Header: taskHeader{
 			Action:    "run-task",
-			TaskID:    r.taskID,
+			TaskID:    taskID,
 			Streaming: "duplex",
 		},
 		Payload: taskPayload{
@@ -193,16 +316,16 @@ func (r *DashScopeRecognizer) sendRunTask(ctx context.Context) error {
 		return err
 	}
 	r.writeMu.Lock()
-	err = r.conn.WriteMessage(websocket.TextMessage, payload)
+	err = conn.WriteMessage(websocket.TextMessage, payload)
 	r.writeMu.Unlock()
 	return err
 }
 
-func (r *DashScopeRecognizer) sendFinishTask(ctx context.Context) error {
+func (r *DashScopeRecognizer) sendFinishTask(conn *websocket.Conn, taskID string) error {
 	msg := finishTaskMessage{
 		Header: taskHeader{
 			Action:    "finish-task",
-			TaskID:    r.taskID,
+			TaskID:    taskID,
 			Streaming: "duplex",
 		},
 		Payload: taskPayload{
@@ -214,38 +337,48 @@ func (r *DashScopeRecognizer) sendFinishTask(ctx context.Context) error {
 		return err
 	}
 	r.writeMu.Lock()
-	err = r.conn.WriteMessage(websocket.TextMessage, payload)
+	err = conn.WriteMessage(websocket.TextMessage, payload)
 	r.writeMu.Unlock()
 	return err
 }
 
-func (r *DashScopeRecognizer) startReceiver() {
-	go func() {
-		for {
-			_, data, err := r.conn.ReadMessage()
-			if err != nil {
-				r.setErr(err)
-				r.markDone()
-				return
-			}
-			var event eventMessage
-			if err := json.Unmarshal(data, &event); err != nil {
-				r.setErr(err)
-				r.markDone()
-				return
+// receive 连接的接收循环：事件交给当前任务，上一个任务的迟到事件忽略；连接断开时结束当前任务
+func (r *DashScopeRecognizer) receive(conn *websocket.Conn) {
+	for {
+		_, data, err := conn.ReadMessage()
+		var event eventMessage
+		if err == nil {
+			err = json.Unmarshal(data, &event)
+		}
+		if err != nil {
+			_ = conn.Close()
+			r.mu.Lock()
+			if r.conn == conn {
+				r.conn = nil
 			}
-			if r.handleEvent(event) {
-				r.markDone()
-				return
+			task := r.task
+			r.mu.Unlock()
+			if !task.done() {
+				task.setErr(err)
+				task.markDone()
 			}
+			return
 		}
-	}()
+
+		task := r.currentTask()
+		if event.Header.TaskID != "" && event.Header.TaskID != task.id {
+			continue
+		}
+		if r.handleEvent(task, event) {
+			task.markDone()
+		}
+	}
 }
 
-func (r *DashScopeRecognizer) handleEvent(event eventMessage) bool {
+func (r *DashScopeRecognizer) handleEvent(task *dashScopeTask, event eventMessage) bool {
 	switch event.Header.Event {
 	case "task-started":
-		r.startedOnce.Do(func() { close(r.startedCh) })
+		task.startedOnce.Do(func() { close(task.startedCh) })
 	case "result-generated":
 		if event.Payload.Output == nil || event.Payload.Output.Sentence == nil {
 			return false
@@ -263,6 +396,8 @@ func (r *DashScopeRecognizer) handleEvent(event eventMessage) bool {
 				IsFinal:     sentence.SentenceEnd,
 				BeginTimeMs: sentence.BeginTime,
 				EndTimeMs:   sentence.EndTime,
+				Words:       sentence.words(),
+				Confidence:  sentence.confidence(),
 			}
 			if event.Payload.Usage != nil {
 				result.UsageDuration = &event.Payload.Usage.Duration
@@ -270,27 +405,64 @@ func (r *DashScopeRecognizer) handleEvent(event eventMessage) bool {
 			r.onResult(result)
 		}
 	case "task-finished":
+		if !task.finishRequested.Load() {
+			// 未调用 Finish 时服务端主动结束任务，通常是空闲超时
+			task.setErr(ErrIdleTimeout)
+		}
 		return true
 	case "task-failed":
-		if event.Header.ErrorMessage != "" {
-			r.setErr(fmt.Errorf("task failed: %s", event.Header.ErrorMessage))
-		} else {
-			r.setErr(errors.New("task failed"))
+		switch {
+		case isIdleTimeoutError(event.Header.ErrorCode, event.Header.ErrorMessage):
+			task.setErr(fmt.Errorf("%w: %s", ErrIdleTimeout, event.Header.ErrorMessage))
+		case event.Header.ErrorMessage != "":
+			task.setErr(fmt.Errorf("task failed: %s", event.Header.ErrorMessage))
+		default:
+			task.setErr(errors.New("task failed"))
 		}
 		return true
 	}
 	return false
 }
 
-func (r *DashScopeRecognizer) setErr(err error) {
+// isIdleTimeoutError 判断任务失败是否由空闲超时引起
+func isIdleTimeoutError(code, message string) bool {
+	text := strings.ToLower(code + " " + message)
+	return strings.Contains(text, "idle") || strings.Contains(text, "timeout")
+}
+
+func (t *dashScopeTask) setErr(err error) {
+	t.errMu.Lock()
+	if t.err == nil {
+		t.err = err
+	}
+	t.errMu.Unlock()
+
 	select {
-	case r.errCh <- err:
+	case t.errCh <- err:
 	default:
 	}
 }
 
-func (r *DashScopeRecognizer) markDone() {
-	r.doneOnce.Do(func() { close(r.doneCh) })
+func (t *dashScopeTask) markDone() {
+	t.doneOnce.Do(func() { close(t.doneCh) })
+}
+
+func (t *dashScopeTask) started() bool {
+	select {
+	case <-t.startedCh:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *dashScopeTask) done() bool {
+	select {
+	case <-t.doneCh:
+		return true
+	default:
+		return false
+	}
 }
 
 type runTaskMessage struct {
@@ -333,11 +505,58 @@ type taskOutput struct {
 }
 
 type taskSentence struct {
-	BeginTime   int64  `json:"begin_time"`
-	EndTime     *int64 `json:"end_time"`
-	Text        string `json:"text"`
-	Heartbeat   bool   `json:"heartbeat"`
-	SentenceEnd bool   `json:"sentence_end"`
+	BeginTime   int64      `json:"begin_time"`
+	EndTime     *int64     `json:"end_time"`
+	Text        string     `json:"text"`
+	Heartbeat   bool       `json:"heartbeat"`
+	SentenceEnd bool       `json:"sentence_end"`
+	Confidence  *float64   `json:"confidence,omitempty"`
+	Words       []taskWord `json:"words,omitempty"`
+}
+
+type taskWord struct {
+	BeginTime   int64    `json:"begin_time"`
+	EndTime     int64    `json:"end_time"`
+	Text        string   `json:"text"`
+	Punctuation string   `json:"punctuation"`
+	Confidence  *float64 `json:"confidence,omitempty"`
+}
+
+func (s *taskSentence) words() []Word {
+	if len(s.Words) == 0 {
+		return nil
+	}
+	words := make([]Word, len(s.Words))
+	for i, w := range s.Words {
+		words[i] = Word{
+			Text:        w.Text,
+			Punctuation: w.Punctuation,
+			BeginTimeMs: w.BeginTime,
+			EndTimeMs:   w.EndTime,
+			Confidence:  w.Confidence,
+		}
+	}
+	return words
+}
+
+// confidence 优先取句子置信度，否则取词级置信度的平均值
+func (s *taskSentence) confidence() *float64 {
+	if s.Confidence != nil {
+		return s.Confidence
+	}
+	var sum float64
+	var count int
+	for _, w := range s.Words {
+		if w.Confidence != nil {
+			sum += *w.Confidence
+			count++
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+	avg := sum / float64(count)
+	return &avg
 }
 
 type taskUsage struct {
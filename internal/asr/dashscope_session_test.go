@@ -0,0 +1,165 @@
+package asr
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// fakeDashScope 模拟 DashScope 实时识别服务：run-task 回 task-started，finish-task 回一条 final 结果和 task-finished
+type fakeDashScope struct {
+	conns  atomic.Int32
+	mu     sync.Mutex
+	runs   []string // 收到的 run-task 的 task_id
+	server *httptest.Server
+}
+
+func newFakeDashScope(t *testing.T) *fakeDashScope {
+	t.Helper()
+	f := &fakeDashScope{}
+	upgrader := websocket.Upgrader{}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		f.conns.Add(1)
+		for {
+			kind, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if kind != websocket.TextMessage {
+				continue
+			}
+			event := decodeEvent(t, string(data))
+			id := event.Header.TaskID
+			switch event.Header.Action {
+			case "run-task":
+				f.mu.Lock()
+				f.runs = append(f.runs, id)
+				f.mu.Unlock()
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"header":{"event":"task-started","task_id":"`+id+`"}}`))
+			case "finish-task":
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"header":{"event":"result-generated","task_id":"`+id+`"},"payload":{"output":{"sentence":{"text":"`+id+`","sentence_end":true}}}}`))
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"header":{"event":"task-finished","task_id":"`+id+`"}}`))
+			}
+		}
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeDashScope) endpoint() string {
+	return "ws" + strings.TrimPrefix(f.server.URL, "http")
+}
+
+func (f *fakeDashScope) runTasks() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.runs...)
+}
+
+func TestDashScopeRecognizerRestart(t *testing.T) {
+	server := newFakeDashScope(t)
+	r, err := NewDashScopeRecognizer(Config{APIKey: "test", Endpoint: server.endpoint()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var finals []string
+	r.OnResult(func(result Result) {
+		mu.Lock()
+		finals = append(finals, result.Text)
+		mu.Unlock()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer r.Close()
+
+	// 连续三轮听写：Finish 后 Restart 在同一连接上开始新任务
+	for round := 0; round < 3; round++ {
+		if round > 0 {
+			if err := r.Restart(ctx); err != nil {
+				t.Fatalf("round %d: Restart() error = %v", round, err)
+			}
+		}
+		done := r.Done()
+		if err := r.SendAudio(ctx, make([]byte, 320)); err != nil {
+			t.Fatalf("round %d: SendAudio() error = %v", round, err)
+		}
+		if err := r.Finish(ctx); err != nil {
+			t.Fatalf("round %d: Finish() error = %v", round, err)
+		}
+		select {
+		case <-done:
+		default:
+			t.Fatalf("round %d: Done() not closed after Finish", round)
+		}
+		if err := r.Err(); err != nil {
+			t.Fatalf("round %d: Err() = %v, want nil", round, err)
+		}
+	}
+
+	// Restart 不需要先 Finish：进行中的任务先结束，剩余结果照常回调
+	for i := 0; i < 2; i++ {
+		if err := r.Restart(ctx); err != nil {
+			t.Fatalf("Restart() while running error = %v", err)
+		}
+	}
+
+	runs := server.runTasks()
+	if len(runs) != 5 {
+		t.Fatalf("run-task count = %d, want 5", len(runs))
+	}
+	if got := server.conns.Load(); got != 1 {
+		t.Errorf("connections = %d, want 1", got)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	// 最后一个任务仍在进行，没有结果
+	if len(finals) != 4 || finals[0] != runs[0] || finals[3] != runs[3] {
+		t.Errorf("finals = %v, want one per task %v", finals, runs)
+	}
+}
+
+func TestDashScopeRecognizerRestartAfterClose(t *testing.T) {
+	server := newFakeDashScope(t)
+	r, err := NewDashScopeRecognizer(Config{APIKey: "test", Endpoint: server.endpoint()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := r.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if err := r.Start(ctx); err == nil {
+		t.Error("second Start() expected error")
+	}
+	_ = r.Close()
+
+	// 连接关闭后 Restart 重新建立连接
+	if err := r.Restart(ctx); err != nil {
+		t.Fatalf("Restart() after Close error = %v", err)
+	}
+	defer r.Close()
+	if err := r.SendAudio(ctx, make([]byte, 320)); err != nil {
+		t.Fatalf("SendAudio() error = %v", err)
+	}
+	if got := server.conns.Load(); got != 2 {
+		t.Errorf("connections = %d, want 2", got)
+	}
+}
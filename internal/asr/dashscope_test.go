@@ -0,0 +1,83 @@
+package asr
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decodeEvent(t *testing.T, data string) eventMessage {
+	t.Helper()
+	var event eventMessage
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatalf("decode event: %v", err)
+	}
+	return event
+}
+
+func TestHandleEventWordsAndConfidence(t *testing.T) {
+	tests := []struct {
+		name           string
+		sentence       string
+		wantWords      int
+		wantConfidence *float64
+	}{
+		{
+			name: "word confidence averaged",
+			sentence: `{"begin_time":100,"end_time":900,"text":"你好，世界。","sentence_end":true,"words":[
+				{"begin_time":100,"end_time":400,"text":"你好","punctuation":"，","confidence":0.9},
+				{"begin_time":400,"end_time":900,"text":"世界","punctuation":"。","confidence":0.5}]}`,
+			wantWords:      2,
+			wantConfidence: floatPtr(0.7),
+		},
+		{
+			name:           "sentence confidence preferred",
+			sentence:       `{"begin_time":0,"text":"好","confidence":0.3,"words":[{"begin_time":0,"end_time":200,"text":"好","confidence":0.9}]}`,
+			wantWords:      1,
+			wantConfidence: floatPtr(0.3),
+		},
+		{
+			name:     "no words",
+			sentence: `{"begin_time":0,"text":"好"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Result
+			r := &DashScopeRecognizer{onResult: func(result Result) { got = result }}
+			r.handleEvent(newDashScopeTask(), decodeEvent(t, `{"header":{"event":"result-generated"},"payload":{"output":{"sentence":`+tt.sentence+`}}}`))
+
+			if len(got.Words) != tt.wantWords {
+				t.Fatalf("words = %+v, want %d", got.Words, tt.wantWords)
+			}
+			switch {
+			case tt.wantConfidence == nil && got.Confidence != nil:
+				t.Errorf("confidence = %v, want nil", *got.Confidence)
+			case tt.wantConfidence != nil && (got.Confidence == nil || !approxEqual(*got.Confidence, *tt.wantConfidence)):
+				t.Errorf("confidence = %v, want %v", got.Confidence, *tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestHandleEventWordTimestamps(t *testing.T) {
+	var got Result
+	r := &DashScopeRecognizer{onResult: func(result Result) { got = result }}
+	r.handleEvent(newDashScopeTask(), decodeEvent(t, `{"header":{"event":"result-generated"},"payload":{"output":{"sentence":{
+		"begin_time":100,"end_time":400,"text":"你好。","sentence_end":true,
+		"words":[{"begin_time":100,"end_time":400,"text":"你好","punctuation":"。"}]}}}}`))
+
+	want := Word{Text: "你好", Punctuation: "。", BeginTimeMs: 100, EndTimeMs: 400}
+	if len(got.Words) != 1 || got.Words[0] != want {
+		t.Errorf("words = %+v, want [%+v]", got.Words, want)
+	}
+	if !got.IsFinal || got.EndTimeMs == nil || *got.EndTimeMs != 400 {
+		t.Errorf("sentence fields = %+v", got)
+	}
+}
+
+func floatPtr(v float64) *float64 { return &v }
+
+func approxEqual(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
@@ -3,10 +3,13 @@ package asr
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 var (
 	ErrAPIKeyRequired = errors.New("DASHSCOPE_API_KEY is required")
+	// ErrIdleTimeout 服务端因长时间静音/无音频结束了识别任务，可直接重新开始
+	ErrIdleTimeout = errors.New("asr task finished due to idle timeout")
 )
 
 type Config struct {
@@ -21,6 +24,8 @@ type Config struct {
 	MultiThresholdModeEnabled  *bool
 	Heartbeat                  *bool
 	LanguageHints              []string
+	// KeepaliveInterval 超过该时长未发送音频时补发一帧静音，防止服务端因无数据结束任务；<=0 表示关闭
+	KeepaliveInterval time.Duration
 }
 
 type Result struct {
@@ -29,6 +34,19 @@ type Result struct {
 	BeginTimeMs   int64
 	EndTimeMs     *int64
 	UsageDuration *int
+	// Words 词级结果（时间相对音频开头），服务未返回时为空
+	Words []Word
+	// Confidence 句子置信度（0~1）：服务返回句子置信度时取该值，否则取词级置信度的平均值；都没有时为 nil
+	Confidence *float64
+}
+
+// Word 词级识别结果
+type Word struct {
+	Text        string
+	Punctuation string // 紧随该词的标点，可能为空
+	BeginTimeMs int64
+	EndTimeMs   int64
+	Confidence  *float64 // 词级置信度（0~1），服务未返回时为 nil
 }
 
 type Recognizer interface {
@@ -38,3 +56,9 @@ type Recognizer interface {
 	Close() error
 	OnResult(handler func(Result))
 }
+
+// SessionRestarter 可选接口：Finish 之后（或服务端结束任务后）在同一识别器上开始新的识别任务，
+// 连接仍可用时复用，已断开时重新连接；不支持的识别器需通过工厂重新创建
+type SessionRestarter interface {
+	Restart(ctx context.Context) error
+}
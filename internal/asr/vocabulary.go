@@ -0,0 +1,252 @@
+package asr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	defaultVocabularyEndpoint = "https://dashscope.aliyuncs.com/api/v1/services/audio/asr/customization"
+	vocabularyServiceModel    = "speech-biasing"
+
+	// MinHotwordWeight / MaxHotwordWeight 热词权重范围，越大越倾向识别为该词
+	MinHotwordWeight = 1
+	MaxHotwordWeight = 5
+	// DefaultHotwordWeight 未指定权重时使用的默认值
+	DefaultHotwordWeight = 4
+)
+
+// vocabularyPrefixPattern 热词表前缀：小写字母和数字，不超过 10 个字符
+var vocabularyPrefixPattern = regexp.MustCompile(`^[a-z0-9]{1,10}$`)
+
+// Hotword 热词表中的一个词
+type Hotword struct {
+	Text   string `json:"text"`
+	Weight int    `json:"weight"`
+	Lang   string `json:"lang,omitempty"` // 语言，如 zh / en，为空时由服务判断
+}
+
+// VocabularyInfo 热词表概要
+type VocabularyInfo struct {
+	ID          string `json:"vocabulary_id"`
+	Status      string `json:"status"`
+	GmtCreate   string `json:"gmt_create"`
+	GmtModified string `json:"gmt_modified"`
+}
+
+// VocabularyClient 管理 DashScope 语音识别热词表（创建、更新、查询、删除），
+// 返回的 vocabulary_id 通过 Config.VocabularyID 在识别时生效
+type VocabularyClient struct {
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+// NewVocabularyClient 创建热词表管理客户端，endpoint 为空时使用 DashScope 默认地址
+func NewVocabularyClient(apiKey, endpoint string) (*VocabularyClient, error) {
+	if apiKey == "" {
+		return nil, ErrAPIKeyRequired
+	}
+	if endpoint == "" {
+		endpoint = defaultVocabularyEndpoint
+	}
+	return &VocabularyClient{
+		apiKey:   apiKey,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// ValidateHotwords 检查热词非空、不重复且权重在 [MinHotwordWeight, MaxHotwordWeight] 内（0 表示默认权重）
+func ValidateHotwords(words []Hotword) error {
+	if len(words) == 0 {
+		return errors.New("vocabulary must contain at least one hotword")
+	}
+	seen := make(map[string]bool, len(words))
+	for i, w := range words {
+		text := strings.TrimSpace(w.Text)
+		if text == "" {
+			return fmt.Errorf("hotword %d: text is empty", i)
+		}
+		if seen[text] {
+			return fmt.Errorf("hotword %q is duplicated", text)
+		}
+		seen[text] = true
+		if w.Weight != 0 && (w.Weight < MinHotwordWeight || w.Weight > MaxHotwordWeight) {
+			return fmt.Errorf("hotword %q: weight must be between %d and %d", text, MinHotwordWeight, MaxHotwordWeight)
+		}
+	}
+	return nil
+}
+
+// ValidateVocabularyPrefix 检查热词表前缀格式
+func ValidateVocabularyPrefix(prefix string) error {
+	if !vocabularyPrefixPattern.MatchString(prefix) {
+		return fmt.Errorf("vocabulary prefix %q must be 1-10 lowercase letters or digits", prefix)
+	}
+	return nil
+}
+
+// Create 为 targetModel 创建热词表，返回 vocabulary_id
+func (c *VocabularyClient) Create(ctx context.Context, targetModel, prefix string, words []Hotword) (string, error) {
+	if err := ValidateVocabularyPrefix(prefix); err != nil {
+		return "", err
+	}
+	if err := ValidateHotwords(words); err != nil {
+		return "", err
+	}
+	var output struct {
+		VocabularyID string `json:"vocabulary_id"`
+	}
+	err := c.call(ctx, map[string]any{
+		"action":       "create_vocabulary",
+		"target_model": targetModel,
+		"prefix":       prefix,
+		"vocabulary":   normalizeHotwords(words),
+	}, &output)
+	if err != nil {
+		return "", err
+	}
+	if output.VocabularyID == "" {
+		return "", errors.New("create vocabulary: response missing vocabulary_id")
+	}
+	return output.VocabularyID, nil
+}
+
+// Update 用 words 整体替换热词表内容
+func (c *VocabularyClient) Update(ctx context.Context, vocabularyID string, words []Hotword) error {
+	if vocabularyID == "" {
+		return errors.New("vocabulary id is required")
+	}
+	if err := ValidateHotwords(words); err != nil {
+		return err
+	}
+	return c.call(ctx, map[string]any{
+		"action":        "update_vocabulary",
+		"vocabulary_id": vocabularyID,
+		"vocabulary":    normalizeHotwords(words),
+	}, nil)
+}
+
+// Get 查询热词表内容
+func (c *VocabularyClient) Get(ctx context.Context, vocabularyID string) ([]Hotword, error) {
+	if vocabularyID == "" {
+		return nil, errors.New("vocabulary id is required")
+	}
+	var output struct {
+		Vocabulary []Hotword `json:"vocabulary"`
+	}
+	if err := c.call(ctx, map[string]any{
+		"action":        "query_vocabulary",
+		"vocabulary_id": vocabularyID,
+	}, &output); err != nil {
+		return nil, err
+	}
+	return output.Vocabulary, nil
+}
+
+// List 列出 prefix 下的热词表，prefix 为空时列出全部
+func (c *VocabularyClient) List(ctx context.Context, prefix string) ([]VocabularyInfo, error) {
+	input := map[string]any{
+		"action":     "list_vocabulary",
+		"page_index": 0,
+		"page_size":  100,
+	}
+	if prefix != "" {
+		input["prefix"] = prefix
+	}
+	var output struct {
+		VocabularyList []VocabularyInfo `json:"vocabulary_list"`
+	}
+	if err := c.call(ctx, input, &output); err != nil {
+		return nil, err
+	}
+	return output.VocabularyList, nil
+}
+
+// Delete 删除热词表
+func (c *VocabularyClient) Delete(ctx context.Context, vocabularyID string) error {
+	if vocabularyID == "" {
+		return errors.New("vocabulary id is required")
+	}
+	return c.call(ctx, map[string]any{
+		"action":        "delete_vocabulary",
+		"vocabulary_id": vocabularyID,
+	}, nil)
+}
+
+type vocabularyRequest struct {
+	Model string         `json:"model"`
+	Input map[string]any `json:"input"`
+}
+
+type vocabularyResponse struct {
+	Output    json.RawMessage `json:"output"`
+	Code      string          `json:"code"`
+	Message   string          `json:"message"`
+	RequestID string          `json:"request_id"`
+}
+
+// call 发送一次热词表管理请求，output 非 nil 时解析响应中的 output 字段
+func (c *VocabularyClient) call(ctx context.Context, input map[string]any, output any) error {
+	action, _ := input["action"].(string)
+	body, err := json.Marshal(vocabularyRequest{Model: vocabularyServiceModel, Input: input})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", action, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("%s: read response: %w", action, err)
+	}
+
+	var result vocabularyResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s failed: %s: %s", action, resp.Status, strings.TrimSpace(string(data)))
+		}
+		return fmt.Errorf("%s: decode response: %w", action, err)
+	}
+	if resp.StatusCode != http.StatusOK || result.Code != "" {
+		return fmt.Errorf("%s failed: %s: %s %s (request_id=%s)", action, resp.Status, result.Code, result.Message, result.RequestID)
+	}
+	if output == nil {
+		return nil
+	}
+	if err := json.Unmarshal(result.Output, output); err != nil {
+		return fmt.Errorf("%s: decode output: %w", action, err)
+	}
+	return nil
+}
+
+// normalizeHotwords 去除首尾空白并补全默认权重
+func normalizeHotwords(words []Hotword) []Hotword {
+	out := make([]Hotword, len(words))
+	for i, w := range words {
+		w.Text = strings.TrimSpace(w.Text)
+		if w.Weight == 0 {
+			w.Weight = DefaultHotwordWeight
+		}
+		out[i] = w
+	}
+	return out
+}
@@ -0,0 +1,137 @@
+package asr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// vocabularyServer 记录请求的 input，并按 action 返回 responses 中的响应体
+func vocabularyServer(t *testing.T, responses map[string]string, inputs *[]map[string]any) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer key" {
+			t.Errorf("unexpected auth header %q", got)
+		}
+		var req vocabularyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Model != vocabularyServiceModel {
+			t.Errorf("model = %q, want %q", req.Model, vocabularyServiceModel)
+		}
+		*inputs = append(*inputs, req.Input)
+		body, ok := responses[req.Input["action"].(string)]
+		if !ok {
+			w.WriteHeader(http.StatusBadRequest)
+			body = `{"code":"InvalidParameter","message":"unknown action","request_id":"r1"}`
+		}
+		w.Write([]byte(body))
+	}))
+}
+
+func TestVocabularyClientCreateAndUpdate(t *testing.T) {
+	var inputs []map[string]any
+	server := vocabularyServer(t, map[string]string{
+		"create_vocabulary": `{"output":{"vocabulary_id":"vocab-orionx-1"},"request_id":"r1"}`,
+		"update_vocabulary": `{"output":{},"request_id":"r2"}`,
+	}, &inputs)
+	defer server.Close()
+
+	client, err := NewVocabularyClient("key", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	words := []Hotword{{Text: " Orion-X ", Lang: "en"}, {Text: "小猎户", Weight: 5}}
+	id, err := client.Create(context.Background(), "fun-asr-realtime", "orionx", words)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if id != "vocab-orionx-1" {
+		t.Errorf("id = %q", id)
+	}
+	if inputs[0]["target_model"] != "fun-asr-realtime" || inputs[0]["prefix"] != "orionx" {
+		t.Errorf("unexpected create input %v", inputs[0])
+	}
+	sent := inputs[0]["vocabulary"].([]any)
+	first := sent[0].(map[string]any)
+	if first["text"] != "Orion-X" || first["weight"] != float64(DefaultHotwordWeight) || first["lang"] != "en" {
+		t.Errorf("hotword not normalized: %v", first)
+	}
+
+	if err := client.Update(context.Background(), id, words); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if inputs[1]["vocabulary_id"] != id {
+		t.Errorf("unexpected update input %v", inputs[1])
+	}
+}
+
+func TestVocabularyClientQueryListDelete(t *testing.T) {
+	var inputs []map[string]any
+	server := vocabularyServer(t, map[string]string{
+		"query_vocabulary":  `{"output":{"vocabulary":[{"text":"Orion-X","weight":4,"lang":"en"}]}}`,
+		"list_vocabulary":   `{"output":{"vocabulary_list":[{"vocabulary_id":"vocab-orionx-1","status":"OK","gmt_modified":"2026-01-01 00:00:00"}]}}`,
+		"delete_vocabulary": `{"output":{}}`,
+	}, &inputs)
+	defer server.Close()
+
+	client, _ := NewVocabularyClient("key", server.URL)
+	words, err := client.Get(context.Background(), "vocab-orionx-1")
+	if err != nil || len(words) != 1 || words[0] != (Hotword{Text: "Orion-X", Weight: 4, Lang: "en"}) {
+		t.Errorf("Get() = %v, %v", words, err)
+	}
+	list, err := client.List(context.Background(), "orionx")
+	if err != nil || len(list) != 1 || list[0].ID != "vocab-orionx-1" || list[0].Status != "OK" {
+		t.Errorf("List() = %v, %v", list, err)
+	}
+	if inputs[1]["prefix"] != "orionx" {
+		t.Errorf("list input missing prefix: %v", inputs[1])
+	}
+	if err := client.Delete(context.Background(), "vocab-orionx-1"); err != nil {
+		t.Errorf("Delete() error = %v", err)
+	}
+}
+
+func TestVocabularyClientError(t *testing.T) {
+	var inputs []map[string]any
+	server := vocabularyServer(t, map[string]string{}, &inputs)
+	defer server.Close()
+
+	client, _ := NewVocabularyClient("key", server.URL)
+	err := client.Delete(context.Background(), "vocab-x")
+	if err == nil || !strings.Contains(err.Error(), "InvalidParameter") || !strings.Contains(err.Error(), "request_id=r1") {
+		t.Errorf("expected service error, got %v", err)
+	}
+}
+
+func TestValidateHotwords(t *testing.T) {
+	tests := []struct {
+		name    string
+		words   []Hotword
+		wantErr bool
+	}{
+		{"ok", []Hotword{{Text: "Orion-X"}, {Text: "小猎户", Weight: 5}}, false},
+		{"empty list", nil, true},
+		{"blank text", []Hotword{{Text: "  "}}, true},
+		{"duplicate", []Hotword{{Text: "a"}, {Text: " a"}}, true},
+		{"weight too high", []Hotword{{Text: "a", Weight: 6}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateHotwords(tt.words); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateHotwords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if _, err := NewVocabularyClient("", ""); err != ErrAPIKeyRequired {
+		t.Errorf("NewVocabularyClient without key: %v", err)
+	}
+	for _, prefix := range []string{"", "Orion", "toolongprefix", "orion-x"} {
+		if ValidateVocabularyPrefix(prefix) == nil {
+			t.Errorf("expected prefix %q to be rejected", prefix)
+		}
+	}
+}
@@ -33,6 +33,19 @@ type EchoCancelConfig struct {
 	FrameMs                 int
 	FarEndDelayMs           int
 	ReferenceActiveWindowMs int
+	// AlignPlayback aligns near-end frames with reference playback timestamps when the
+	// reference implements TimedReferenceSource; FarEndDelayMs then only covers the
+	// acoustic/device latency instead of all buffering between playback and capture.
+	AlignPlayback bool
+	// GateAttenuationDb is how much "gate" mode attenuates the microphone while the reference
+	// is active; 0 mutes it completely.
+	GateAttenuationDb float64
+	// GateHangoverMs keeps the gate closed after the reference stops (echo tail) and open after
+	// near-end speech is detected, avoiding frame-by-frame toggling.
+	GateHangoverMs int
+	// GateDoubleTalkRatio lets near-end speech through the gate when the near-end energy not
+	// explained by the reference exceeds the echo estimate by this ratio; 0 disables the check.
+	GateDoubleTalkRatio float64
 }
 
 func DefaultEchoCancelConfig() EchoCancelConfig {
@@ -42,6 +55,10 @@ func DefaultEchoCancelConfig() EchoCancelConfig {
 		FrameMs:                 10,
 		FarEndDelayMs:           50,
 		ReferenceActiveWindowMs: 200,
+		AlignPlayback:           true,
+		GateAttenuationDb:       30,
+		GateHangoverMs:          150,
+		GateDoubleTalkRatio:     1,
 	}
 }
 
@@ -56,17 +73,33 @@ type ReferenceSource interface {
 	IsActive() bool
 }
 
+// TimedReferenceSink receives reference PCM with the (estimated) playback time of its first sample.
+type TimedReferenceSink interface {
+	WriteReferenceAt(p []byte, at time.Time)
+}
+
+// TimedReferenceSource provides the reference frame that was playing when a near-end frame was captured.
+type TimedReferenceSource interface {
+	ReadReferenceAt(at time.Time) []byte
+}
+
 // ReferenceBuffer stores far-end reference audio in fixed-size frames.
+// Each frame carries a playback timestamp: WriteReferenceAt uses the caller's playback time,
+// WriteReference uses the write time. Partial frames are kept until the next write.
 type ReferenceBuffer struct {
-	mu           sync.Mutex
-	frameBytes   int
-	maxFrames    int
-	delayFrames  int
-	frames       [][]byte
-	head         int
-	size         int
-	lastWrite    time.Time
-	activeWindow time.Duration
+	mu            sync.Mutex
+	frameBytes    int
+	maxFrames     int
+	delayFrames   int
+	frameDuration time.Duration
+	frames        [][]byte
+	times         []time.Time
+	head          int
+	size          int
+	pending       []byte
+	pendingAt     time.Time
+	lastWrite     time.Time
+	activeWindow  time.Duration
 }
 
 func NewReferenceBuffer(frameBytes, maxFrames, delayFrames int) *ReferenceBuffer {
@@ -86,11 +119,23 @@ func NewReferenceBuffer(frameBytes, maxFrames, delayFrames int) *ReferenceBuffer
 	}
 
 	return &ReferenceBuffer{
-		frameBytes:   frameBytes,
-		maxFrames:    maxFrames,
-		delayFrames:  delayFrames,
-		frames:       frames,
-		activeWindow: 200 * time.Millisecond,
+		frameBytes:    frameBytes,
+		maxFrames:     maxFrames,
+		delayFrames:   delayFrames,
+		frameDuration: framesDuration(int64(frameBytes/2), 16000),
+		frames:        frames,
+		times:         make([]time.Time, maxFrames),
+		activeWindow:  200 * time.Millisecond,
+	}
+}
+
+// SetFrameDuration sets the duration of one frame (default: frameBytes of 16kHz mono 16-bit PCM),
+// used to timestamp frames within a write and as the alignment tolerance.
+func (b *ReferenceBuffer) SetFrameDuration(duration time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if duration > 0 {
+		b.frameDuration = duration
 	}
 }
 
@@ -104,23 +149,49 @@ func (b *ReferenceBuffer) SetActiveWindow(window time.Duration) {
 }
 
 func (b *ReferenceBuffer) WriteReference(p []byte) {
+	b.WriteReferenceAt(p, time.Now())
+}
+
+// WriteReferenceAt implements TimedReferenceSink; later frames in p are offset by the frame duration.
+func (b *ReferenceBuffer) WriteReferenceAt(p []byte, at time.Time) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	if len(p) == 0 || b.frameBytes <= 0 {
 		return
 	}
-	for offset := 0; offset+b.frameBytes <= len(p); offset += b.frameBytes {
-		frame := b.frames[(b.head+b.size)%b.maxFrames]
-		copy(frame, p[offset:offset+b.frameBytes])
-		if b.size < b.maxFrames {
-			b.size++
-		} else {
-			b.head = (b.head + 1) % b.maxFrames
+	if len(b.pending) > 0 {
+		need := b.frameBytes - len(b.pending)
+		if len(p) < need {
+			b.pending = append(b.pending, p...)
+			return
 		}
-		b.lastWrite = time.Now()
+		b.pushLocked(append(b.pending, p[:need]...), b.pendingAt)
+		b.pending = b.pending[:0]
+		at = at.Add(b.frameDuration * time.Duration(need) / time.Duration(b.frameBytes))
+		p = p[need:]
+	}
+	for ; len(p) >= b.frameBytes; p = p[b.frameBytes:] {
+		b.pushLocked(p[:b.frameBytes], at)
+		at = at.Add(b.frameDuration)
+	}
+	if len(p) > 0 {
+		b.pending = append(b.pending[:0], p...)
+		b.pendingAt = at
 	}
 }
 
+func (b *ReferenceBuffer) pushLocked(frame []byte, at time.Time) {
+	index := (b.head + b.size) % b.maxFrames
+	copy(b.frames[index], frame)
+	b.times[index] = at
+	if b.size < b.maxFrames {
+		b.size++
+	} else {
+		b.head = (b.head + 1) % b.maxFrames
+	}
+	b.lastWrite = time.Now()
+}
+
 func (b *ReferenceBuffer) ReadReference() []byte {
 	b.mu.Lock()
 	defer b.mu.Unlock()
@@ -137,6 +208,33 @@ func (b *ReferenceBuffer) ReadReference() []byte {
 	return frame
 }
 
+// ReadReferenceAt implements TimedReferenceSource. It returns the frame whose playback time plus
+// delayFrames (the acoustic/device latency) is closest to at, dropping older frames, or silence
+// when nothing was playing at that time.
+func (b *ReferenceBuffer) ReadReferenceAt(at time.Time) []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.frameBytes <= 0 {
+		return nil
+	}
+	target := at.Add(-b.frameDuration * time.Duration(b.delayFrames))
+	half := b.frameDuration / 2
+	for b.size > 0 {
+		played := b.times[b.head]
+		if played.After(target.Add(half)) {
+			break
+		}
+		frame := make([]byte, b.frameBytes)
+		copy(frame, b.frames[b.head])
+		b.head = (b.head + 1) % b.maxFrames
+		b.size--
+		if !played.Before(target.Add(-half)) {
+			return frame
+		}
+	}
+	return make([]byte, b.frameBytes)
+}
+
 func (b *ReferenceBuffer) IsActive() bool {
 	b.mu.Lock()
 	defer b.mu.Unlock()
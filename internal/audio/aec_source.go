@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"strings"
+	"time"
 )
 
 // EchoCancellingSource wraps an AudioSource and applies echo control at read time.
@@ -15,6 +16,7 @@ type EchoCancellingSource struct {
 	sampleRate int
 	channels   int
 	frameBytes int
+	gate       *echoGate
 }
 
 func NewEchoCancellingSource(source AudioSource, config EchoCancelConfig, reference ReferenceSource, canceller EchoCanceller, sampleRate, channels int) *EchoCancellingSource {
@@ -32,6 +34,7 @@ func NewEchoCancellingSource(source AudioSource, config EchoCancelConfig, refere
 		sampleRate: sampleRate,
 		channels:   channels,
 		frameBytes: FrameBytes(sampleRate, channels, config.FrameMs),
+		gate:       newEchoGate(config),
 	}
 }
 
@@ -43,18 +46,18 @@ func (s *EchoCancellingSource) Read(ctx context.Context) ([]byte, error) {
 	if err != nil || len(data) == 0 {
 		return data, err
 	}
+	readAt := time.Now()
 	if !s.config.Enabled {
 		return data, nil
 	}
 	if s.channels != 1 {
 		return data, nil
 	}
+	// The chunk was captured just before Read returned; frame timestamps count back from there.
+	captured := readAt.Add(-s.bytesDuration(len(data)))
 	mode := strings.ToLower(strings.TrimSpace(s.config.Mode))
 	if mode == "gate" {
-		if s.reference != nil && s.reference.IsActive() {
-			return make([]byte, len(data)), nil
-		}
-		return data, nil
+		return s.applyGate(data, captured), nil
 	}
 	if s.canceller == nil || s.reference == nil || s.frameBytes <= 0 {
 		return data, nil
@@ -64,7 +67,7 @@ func (s *EchoCancellingSource) Read(ctx context.Context) ([]byte, error) {
 	copy(processed, data)
 	for offset := 0; offset+s.frameBytes <= len(processed); offset += s.frameBytes {
 		near := processed[offset : offset+s.frameBytes]
-		far := s.reference.ReadReference()
+		far := s.readReference(captured.Add(s.bytesDuration(offset)))
 		if len(far) != len(near) {
 			far = make([]byte, len(near))
 		}
@@ -77,6 +80,55 @@ func (s *EchoCancellingSource) Read(ctx context.Context) ([]byte, error) {
 	return processed, nil
 }
 
+// readReference returns the reference frame for a near-end frame captured at the given time,
+// aligned by playback timestamps when enabled and supported by the reference.
+func (s *EchoCancellingSource) readReference(captured time.Time) []byte {
+	if timed, ok := s.reference.(TimedReferenceSource); ok && s.config.AlignPlayback {
+		return timed.ReadReferenceAt(captured)
+	}
+	return s.reference.ReadReference()
+}
+
+// applyGate attenuates frames while the reference is active (plus hangover), letting near-end
+// speech through when it clearly exceeds the echo estimate.
+func (s *EchoCancellingSource) applyGate(data []byte, captured time.Time) []byte {
+	active := s.reference != nil && s.reference.IsActive()
+	if !active && !s.gate.holding() {
+		return data
+	}
+	frameBytes := s.frameBytes
+	if frameBytes <= 0 || frameBytes > len(data) {
+		frameBytes = len(data)
+	}
+	processed := make([]byte, len(data))
+	copy(processed, data)
+	attenuate := false
+	for offset := 0; offset < len(processed); offset += frameBytes {
+		end := offset + frameBytes
+		if end > len(processed) {
+			// A trailing partial frame follows the previous decision.
+			s.gate.apply(processed[offset:], attenuate)
+			break
+		}
+		near := processed[offset:end]
+		var far []byte
+		if active && s.gate.detectsDoubleTalk() {
+			far = s.readReference(captured.Add(s.bytesDuration(offset)))
+		}
+		attenuate = s.gate.decide(near, far, active)
+		s.gate.apply(near, attenuate)
+	}
+	return processed
+}
+
+// bytesDuration converts a byte count of the near-end PCM to its duration.
+func (s *EchoCancellingSource) bytesDuration(n int) time.Duration {
+	if s.sampleRate <= 0 || s.channels <= 0 {
+		return 0
+	}
+	return framesDuration(int64(n/(2*s.channels)), s.sampleRate)
+}
+
 func (s *EchoCancellingSource) Close() error {
 	if s.canceller != nil {
 		_ = s.canceller.Close()
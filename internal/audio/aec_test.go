@@ -88,3 +88,72 @@ func TestEchoCancellingSource_PassThrough(t *testing.T) {
 		t.Fatalf("expected passthrough output")
 	}
 }
+
+func TestReferenceBuffer_ReadReferenceAt(t *testing.T) {
+	// 2 字节一帧、每帧 10ms，固定延迟 1 帧
+	buf := NewReferenceBuffer(2, 8, 1)
+	buf.SetFrameDuration(10 * time.Millisecond)
+	start := time.Unix(100, 0)
+	buf.WriteReferenceAt([]byte{1, 1, 2, 2, 3}, start)
+	// 不足一帧的数据与下次写入拼接，时间戳沿用第一个样本
+	buf.WriteReferenceAt([]byte{3, 4, 4}, start.Add(25*time.Millisecond))
+
+	tests := []struct {
+		at   time.Duration
+		want []byte
+	}{
+		{4 * time.Millisecond, []byte{0, 0}},   // 尚未播放到麦克风
+		{10 * time.Millisecond, []byte{1, 1}},  // 第 0 帧 + 10ms 延迟
+		{31 * time.Millisecond, []byte{3, 3}},  // 跳过第 1 帧
+		{40 * time.Millisecond, []byte{4, 4}},  // 拼接的帧在 30ms 播放
+		{100 * time.Millisecond, []byte{0, 0}}, // 没有更多参考
+	}
+	for _, tt := range tests {
+		if got := buf.ReadReferenceAt(start.Add(tt.at)); !bytes.Equal(got, tt.want) {
+			t.Errorf("ReadReferenceAt(+%v) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
+
+// timedReference 记录 EchoCancellingSource 请求的采集时间
+type timedReference struct {
+	stubReference
+	requested []time.Time
+}
+
+func (r *timedReference) ReadReferenceAt(at time.Time) []byte {
+	r.requested = append(r.requested, at)
+	return append([]byte(nil), r.frame...)
+}
+
+func TestEchoCancellingSource_AlignPlayback(t *testing.T) {
+	frameBytes := FrameBytes(16000, 1, 10)
+	tests := []struct {
+		align     bool
+		requested int
+	}{
+		{true, 3},
+		{false, 0},
+	}
+	for _, tt := range tests {
+		source := &stubSource{data: make([]byte, 3*frameBytes)}
+		ref := &timedReference{stubReference: stubReference{frame: make([]byte, frameBytes)}}
+		cfg := EchoCancelConfig{Enabled: true, Mode: "aec", FrameMs: 10, AlignPlayback: tt.align}
+
+		before := time.Now()
+		wrapped := NewEchoCancellingSource(source, cfg, ref, NewNoopEchoCanceller(), 16000, 1)
+		if _, err := wrapped.Read(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(ref.requested) != tt.requested {
+			t.Fatalf("align=%v: requested %d frames, want %d", tt.align, len(ref.requested), tt.requested)
+		}
+		for i, at := range ref.requested {
+			// 采集时间从读取返回时刻往前推整个块的时长，每帧递增 10ms
+			want := before.Add(time.Duration(i-3) * 10 * time.Millisecond)
+			if d := at.Sub(want); d < 0 || d > 5*time.Millisecond {
+				t.Errorf("frame %d captured at %v, want about %v", i, at, want)
+			}
+		}
+	}
+}
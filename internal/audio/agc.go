@@ -0,0 +1,136 @@
+package audio
+
+import "math"
+
+// AGCConfig 自动增益控制配置
+type AGCConfig struct {
+	Enabled   bool
+	TargetRMS float64 // 目标电平（归一化 RMS，0~1）
+	MaxGain   float64 // 最大增益倍数，避免把底噪放大
+	MinGain   float64 // 最小增益倍数
+	AttackMs  int     // 增益下降（信号变大）的时间常数
+	ReleaseMs int     // 增益上升（信号变小）的时间常数
+	// GateRMS 低于该电平视为静音，保持当前增益不再提升
+	GateRMS float64
+}
+
+// DefaultAGCConfig 默认 AGC 配置
+func DefaultAGCConfig() AGCConfig {
+	return AGCConfig{
+		TargetRMS: 0.1,
+		MaxGain:   10,
+		MinGain:   0.5,
+		AttackMs:  10,
+		ReleaseMs: 500,
+		GateRMS:   0.003,
+	}
+}
+
+// agcProcessor 按帧计算电平，平滑调整增益，并对输出做软限幅防止削波
+type agcProcessor struct {
+	config    AGCConfig
+	gain      float64
+	attackA   float64
+	releaseA  float64
+	frameSize int
+}
+
+func newAGCProcessor(config AGCConfig, sampleRate int) *agcProcessor {
+	defaults := DefaultAGCConfig()
+	if config.TargetRMS <= 0 {
+		config.TargetRMS = defaults.TargetRMS
+	}
+	if config.MaxGain <= 0 {
+		config.MaxGain = defaults.MaxGain
+	}
+	if config.MinGain <= 0 {
+		config.MinGain = defaults.MinGain
+	}
+	if config.AttackMs <= 0 {
+		config.AttackMs = defaults.AttackMs
+	}
+	if config.ReleaseMs <= 0 {
+		config.ReleaseMs = defaults.ReleaseMs
+	}
+
+	// 10ms 一帧计算电平
+	const frameMs = 10.0
+	frameSize := sampleRate * int(frameMs) / 1000
+	if frameSize <= 0 {
+		frameSize = 160
+	}
+	return &agcProcessor{
+		config:    config,
+		gain:      1,
+		attackA:   smoothingCoefficient(frameMs, float64(config.AttackMs)),
+		releaseA:  smoothingCoefficient(frameMs, float64(config.ReleaseMs)),
+		frameSize: frameSize,
+	}
+}
+
+// smoothingCoefficient 一阶平滑系数：每帧向目标值靠近的比例
+func smoothingCoefficient(frameMs, timeConstantMs float64) float64 {
+	return 1 - math.Exp(-frameMs/timeConstantMs)
+}
+
+// Process 原地处理归一化采样
+func (a *agcProcessor) Process(samples []float64) {
+	for start := 0; start < len(samples); start += a.frameSize {
+		end := start + a.frameSize
+		if end > len(samples) {
+			end = len(samples)
+		}
+		frame := samples[start:end]
+
+		rms := rmsOf(frame)
+		if rms > a.config.GateRMS {
+			desired := clampFloat(a.config.TargetRMS/rms, a.config.MinGain, a.config.MaxGain)
+			coeff := a.releaseA
+			if desired < a.gain {
+				coeff = a.attackA
+			}
+			a.gain += (desired - a.gain) * coeff
+		}
+
+		for i := range frame {
+			frame[i] = softLimit(frame[i] * a.gain)
+		}
+	}
+}
+
+// Gain 当前增益
+func (a *agcProcessor) Gain() float64 {
+	return a.gain
+}
+
+// softLimit 软限幅：|x| 超过 0.9 后平滑压缩到 1 以内
+func softLimit(x float64) float64 {
+	const knee = 0.9
+	ax := math.Abs(x)
+	if ax <= knee {
+		return x
+	}
+	limited := knee + (1-knee)*math.Tanh((ax-knee)/(1-knee))
+	return math.Copysign(limited, x)
+}
+
+func rmsOf(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range samples {
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
+
+func clampFloat(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,90 @@
+package audio
+
+import (
+	"context"
+	"time"
+)
+
+// asrFramer 把任意长度的音频块切分 / 合并为固定长度的帧，不足一帧的尾部留到下次
+type asrFramer struct {
+	frameBytes int
+	pending    []byte
+}
+
+// newASRFramer 按帧长创建分帧器，frameMs <= 0 时返回 nil（按原样发送）
+func newASRFramer(sampleRate, channels, frameMs int) *asrFramer {
+	if frameMs <= 0 {
+		return nil
+	}
+	frames := max(sampleRate*frameMs/1000, 1)
+	return &asrFramer{frameBytes: frames * max(channels, 1) * 2}
+}
+
+// push 追加音频并返回已凑满的帧（返回的帧不与 audio 共享内存）；framer 为 nil 时原样返回
+func (f *asrFramer) push(audio []byte) [][]byte {
+	if f == nil {
+		if len(audio) == 0 {
+			return nil
+		}
+		return [][]byte{audio}
+	}
+	f.pending = append(f.pending, audio...)
+	var frames [][]byte
+	for len(f.pending) >= f.frameBytes {
+		frame := make([]byte, f.frameBytes)
+		copy(frame, f.pending)
+		frames = append(frames, frame)
+		f.pending = f.pending[f.frameBytes:]
+	}
+	if len(f.pending) == 0 {
+		f.pending = nil
+	}
+	return frames
+}
+
+// reset 丢弃不足一帧的尾部
+func (f *asrFramer) reset() {
+	if f != nil {
+		f.pending = nil
+	}
+}
+
+// sendPacer 限制发送速率：已发送的音频时长不超过经过时间 × rate，空闲期间不积累额度
+type sendPacer struct {
+	rate        float64
+	bytesPerSec int
+	start       time.Time
+	sent        time.Duration
+	now         func() time.Time
+}
+
+// newSendPacer 按实时倍数创建限速器，rate <= 0 时返回 nil（不限速）
+func newSendPacer(sampleRate, channels int, rate float64) *sendPacer {
+	if rate <= 0 {
+		return nil
+	}
+	return &sendPacer{rate: rate, bytesPerSec: sampleRate * max(channels, 1) * 2, now: time.Now}
+}
+
+// wait 在发送 n 字节前等待，直到发送速率不超过上限；ctx 取消时返回错误
+func (p *sendPacer) wait(ctx context.Context, n int) error {
+	if p == nil || p.bytesPerSec <= 0 {
+		return nil
+	}
+	now := p.now()
+	allowed := time.Duration(float64(now.Sub(p.start)) * p.rate)
+	if p.start.IsZero() || allowed > p.sent {
+		// 落后于上限（刚开始或中间有空闲）时重新计时，避免之后突发发送
+		p.start = now.Add(-time.Duration(float64(p.sent) / p.rate))
+	} else if ahead := p.sent - allowed; ahead > 0 {
+		timer := time.NewTimer(time.Duration(float64(ahead) / p.rate))
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	p.sent += time.Duration(n) * time.Second / time.Duration(p.bytesPerSec)
+	return nil
+}
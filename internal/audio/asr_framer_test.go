@@ -0,0 +1,80 @@
+package audio
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestASRFramer(t *testing.T) {
+	tests := []struct {
+		name    string
+		frameMs int
+		chunks  []int // 每次送入的字节数
+		want    []int // 每次送入后输出的帧数
+	}{
+		{"disabled", 0, []int{6400, 100}, []int{1, 1}},
+		{"split", 100, []int{6400}, []int{2}},
+		{"coalesce", 100, []int{640, 640, 1920}, []int{0, 0, 1}},
+		{"carry remainder", 100, []int{4800, 4800}, []int{1, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			framer := newASRFramer(16000, 1, tt.frameMs)
+			for i, n := range tt.chunks {
+				frames := framer.push(make([]byte, n))
+				if len(frames) != tt.want[i] {
+					t.Fatalf("push %d: got %d frames, want %d", i, len(frames), tt.want[i])
+				}
+				for _, frame := range frames {
+					if tt.frameMs > 0 && len(frame) != 3200 {
+						t.Fatalf("frame size = %d, want 3200", len(frame))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestASRFramerReset(t *testing.T) {
+	framer := newASRFramer(16000, 1, 100)
+	framer.push(make([]byte, 3000))
+	framer.reset()
+	if frames := framer.push(make([]byte, 3000)); len(frames) != 0 {
+		t.Fatalf("got %d frames after reset, want 0", len(frames))
+	}
+}
+
+func TestSendPacer(t *testing.T) {
+	if newSendPacer(16000, 1, 0) != nil {
+		t.Fatal("rate 0 should disable pacing")
+	}
+
+	pacer := newSendPacer(16000, 1, 2)
+	ctx := context.Background()
+	start := time.Now()
+	// 5 帧 100ms 音频按 2 倍实时速率发送：第一帧立即发送，之后每帧等待约 50ms
+	for i := 0; i < 5; i++ {
+		if err := pacer.wait(ctx, 3200); err != nil {
+			t.Fatalf("wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond || elapsed > 400*time.Millisecond {
+		t.Fatalf("5 frames took %v, want about 200ms", elapsed)
+	}
+
+	// 空闲后不积累额度：第一帧立即发送，第二帧仍需等待
+	time.Sleep(300 * time.Millisecond)
+	start = time.Now()
+	pacer.wait(ctx, 3200)
+	pacer.wait(ctx, 3200)
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Fatalf("burst after idle took %v, want pacing", elapsed)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := pacer.wait(cancelled, 3200); err == nil {
+		t.Fatal("wait() should fail when context is canceled while pacing")
+	}
+}
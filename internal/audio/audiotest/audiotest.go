@@ -0,0 +1,346 @@
+// Package audiotest 提供确定性的音频测试替身（Mixer、AudioSource、ReferenceSink），
+// 供嵌入 orion-x 的下游代码在没有声卡的环境中测试
+package audiotest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+// MixerConfig 假 Mixer 配置
+type MixerConfig struct {
+	// BytesPerSecond >0 时按该速率每 20ms 读取一帧，模拟声卡实时消费；
+	// 否则尽快读完（TTSPipeline 开启 PrerollMs 时欠载期间的静音也会被读入）
+	BytesPerSecond int
+}
+
+// Mixer 实现 audio.AudioMixer：不打开声卡，后台读完加入的音频流并记录内容、音量与回调次数
+type Mixer struct {
+	cfg            MixerConfig
+	mu             sync.Mutex
+	tts            []byte
+	resource       []byte
+	prompt         []byte
+	ttsStreams     int
+	ttsVolume      float64
+	resourceVolume float64
+	volume         float64
+	ttsStarted     int
+	ttsFinished    int
+	finishedCh     chan struct{}
+	resourceModes  []audio.ResourceMode
+	resourceTail   chan struct{}
+	wg             sync.WaitGroup
+}
+
+var (
+	_ audio.AudioMixer       = (*Mixer)(nil)
+	_ audio.ResourceQueuer   = (*Mixer)(nil)
+	_ audio.VolumeController = (*Mixer)(nil)
+)
+
+// NewMixer 创建尽快读完音频流的假 Mixer，音量默认 1.0
+func NewMixer() *Mixer {
+	return NewMixerWithConfig(MixerConfig{})
+}
+
+// NewMixerWithConfig 使用指定配置创建假 Mixer
+func NewMixerWithConfig(cfg MixerConfig) *Mixer {
+	return &Mixer{
+		cfg:            cfg,
+		ttsVolume:      1.0,
+		resourceVolume: 1.0,
+		volume:         1.0,
+		finishedCh:     make(chan struct{}, 16),
+	}
+}
+
+func (m *Mixer) AddTTSStream(r io.Reader) {
+	m.mu.Lock()
+	m.ttsStreams++
+	m.mu.Unlock()
+	m.drain(r, &m.tts)
+}
+
+func (m *Mixer) AddResourceStream(r io.Reader) {
+	m.EnqueueResourceStream(r, audio.ResourceOptions{Mode: audio.ResourcePreempt})
+}
+
+// EnqueueResourceStream 记录播放方式，后台读完音频后回调 OnFinished(false)
+// ResourceQueue 模式的音频按提交顺序依次读取，其他模式立即读取
+func (m *Mixer) EnqueueResourceStream(r io.Reader, opts audio.ResourceOptions) {
+	if r == nil {
+		return
+	}
+	m.mu.Lock()
+	m.resourceModes = append(m.resourceModes, opts.Mode)
+	var prev, done chan struct{}
+	if opts.Mode == audio.ResourceQueue {
+		prev = m.resourceTail
+		done = make(chan struct{})
+		m.resourceTail = done
+	}
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		if prev != nil {
+			<-prev
+		}
+		m.copyStream(r, &m.resource)
+		if done != nil {
+			close(done)
+		}
+		if opts.OnFinished != nil {
+			opts.OnFinished(false)
+		}
+	}()
+}
+
+// ResourceModes 返回每段资源音频的播放方式
+func (m *Mixer) ResourceModes() []audio.ResourceMode {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]audio.ResourceMode(nil), m.resourceModes...)
+}
+
+func (m *Mixer) AddPromptStream(r io.Reader) {
+	m.drain(r, &m.prompt)
+}
+
+func (m *Mixer) RemoveTTSStream()      {}
+func (m *Mixer) RemoveResourceStream() {}
+func (m *Mixer) RemovePromptStream()   {}
+
+func (m *Mixer) SetTTSVolume(volume float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ttsVolume = volume
+}
+
+func (m *Mixer) SetResourceVolume(volume float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resourceVolume = volume
+}
+
+func (m *Mixer) Volume() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.volume
+}
+
+func (m *Mixer) SetVolume(volume float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.volume = volume
+}
+
+func (m *Mixer) OnTTSStarted() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.ttsStarted++
+}
+
+func (m *Mixer) OnTTSFinished() {
+	m.mu.Lock()
+	m.ttsFinished++
+	m.mu.Unlock()
+	select {
+	case m.finishedCh <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Mixer) Start() {}
+
+// Stop 等待所有音频流读完
+func (m *Mixer) Stop() {
+	m.wg.Wait()
+}
+
+// TTSFinished 每次 OnTTSFinished 时收到一个信号
+func (m *Mixer) TTSFinished() <-chan struct{} {
+	return m.finishedCh
+}
+
+// TTSAudio / ResourceAudio / PromptAudio 返回已读到的对应音频
+func (m *Mixer) TTSAudio() []byte      { return m.snapshot(&m.tts) }
+func (m *Mixer) ResourceAudio() []byte { return m.snapshot(&m.resource) }
+func (m *Mixer) PromptAudio() []byte   { return m.snapshot(&m.prompt) }
+
+// TTSStreams 返回 AddTTSStream 的调用次数
+func (m *Mixer) TTSStreams() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ttsStreams
+}
+
+// Volumes 返回当前 TTS 与资源音量
+func (m *Mixer) Volumes() (tts, resource float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ttsVolume, m.resourceVolume
+}
+
+// TTSCallbacks 返回 OnTTSStarted / OnTTSFinished 的调用次数
+func (m *Mixer) TTSCallbacks() (started, finished int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ttsStarted, m.ttsFinished
+}
+
+func (m *Mixer) drain(r io.Reader, dst *[]byte) {
+	if r == nil {
+		return
+	}
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.copyStream(r, dst)
+	}()
+}
+
+// copyStream 读完 r 并追加到 dst，配置了 BytesPerSecond 时按实时速率读取
+func (m *Mixer) copyStream(r io.Reader, dst *[]byte) {
+	const frame = 20 * time.Millisecond
+	buf := make([]byte, 4096)
+	var ticker *time.Ticker
+	if m.cfg.BytesPerSecond > 0 {
+		buf = make([]byte, max(m.cfg.BytesPerSecond*int(frame/time.Millisecond)/1000, 2))
+		ticker = time.NewTicker(frame)
+		defer ticker.Stop()
+	}
+	for {
+		if ticker != nil {
+			<-ticker.C
+		}
+		n, err := r.Read(buf)
+		if n > 0 {
+			m.mu.Lock()
+			*dst = append(*dst, buf[:n]...)
+			m.mu.Unlock()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (m *Mixer) snapshot(src *[]byte) []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]byte(nil), *src...)
+}
+
+// ErrSourceClosed 关闭后读取 Source 返回
+var ErrSourceClosed = errors.New("audiotest: source closed")
+
+// SourceConfig 假音频源配置
+type SourceConfig struct {
+	// Chunks 依次返回的音频块，读完后返回 io.EOF
+	Chunks [][]byte
+	// Interval 每次 Read 前的等待时长，模拟采集节奏
+	Interval time.Duration
+	// Err 非空时在返回 FailAfter 块之后返回该错误，模拟设备断开
+	Err       error
+	FailAfter int
+}
+
+// Source 实现 audio.AudioSource：按脚本返回音频块
+type Source struct {
+	mu     sync.Mutex
+	cfg    SourceConfig
+	next   int
+	closed bool
+	done   chan struct{}
+	once   sync.Once
+}
+
+var _ audio.AudioSource = (*Source)(nil)
+
+// NewSource 创建假音频源
+func NewSource(cfg SourceConfig) *Source {
+	return &Source{cfg: cfg, done: make(chan struct{})}
+}
+
+// Silence 返回 n 块、每块 frameBytes 字节的静音，便于构造 SourceConfig.Chunks
+func Silence(n, frameBytes int) [][]byte {
+	chunks := make([][]byte, n)
+	for i := range chunks {
+		chunks[i] = make([]byte, frameBytes)
+	}
+	return chunks
+}
+
+func (s *Source) Read(ctx context.Context) ([]byte, error) {
+	if s.cfg.Interval > 0 {
+		timer := time.NewTimer(s.cfg.Interval)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-s.done:
+			return nil, ErrSourceClosed
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrSourceClosed
+	}
+	if s.cfg.Err != nil && s.next >= s.cfg.FailAfter {
+		return nil, s.cfg.Err
+	}
+	if s.next >= len(s.cfg.Chunks) {
+		return nil, io.EOF
+	}
+	chunk := s.cfg.Chunks[s.next]
+	s.next++
+	return append([]byte(nil), chunk...), nil
+}
+
+// Close 关闭音频源，阻塞中的 Read 返回 ErrSourceClosed
+func (s *Source) Close() error {
+	s.once.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		s.mu.Unlock()
+		close(s.done)
+	})
+	return nil
+}
+
+// ReferenceSink 实现 audio.ReferenceSink，记录写入的回声参考音频
+type ReferenceSink struct {
+	mu   sync.Mutex
+	data []byte
+}
+
+var _ audio.ReferenceSink = (*ReferenceSink)(nil)
+
+// NewReferenceSink 创建 ReferenceSink
+func NewReferenceSink() *ReferenceSink {
+	return &ReferenceSink{}
+}
+
+func (s *ReferenceSink) WriteReference(p []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = append(s.data, p...)
+}
+
+// Data 返回写入过的全部参考音频
+func (s *ReferenceSink) Data() []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]byte(nil), s.data...)
+}
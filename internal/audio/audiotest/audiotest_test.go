@@ -0,0 +1,137 @@
+package audiotest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/tts"
+	"github.com/liuscraft/orion-x/internal/tts/ttstest"
+)
+
+func TestMixerDrainsStreams(t *testing.T) {
+	m := NewMixer()
+	m.AddTTSStream(bytes.NewReader([]byte{1, 2, 3}))
+	m.AddPromptStream(bytes.NewReader([]byte{4}))
+	m.SetResourceVolume(0.5)
+	m.OnTTSStarted()
+	m.OnTTSFinished()
+	m.Stop()
+
+	if got := m.TTSAudio(); !bytes.Equal(got, []byte{1, 2, 3}) {
+		t.Errorf("TTSAudio() = %v", got)
+	}
+	if got := m.PromptAudio(); !bytes.Equal(got, []byte{4}) {
+		t.Errorf("PromptAudio() = %v", got)
+	}
+	if ttsVol, resVol := m.Volumes(); ttsVol != 1.0 || resVol != 0.5 {
+		t.Errorf("Volumes() = %v, %v", ttsVol, resVol)
+	}
+	if started, finished := m.TTSCallbacks(); started != 1 || finished != 1 {
+		t.Errorf("TTSCallbacks() = %d, %d", started, finished)
+	}
+	select {
+	case <-m.TTSFinished():
+	default:
+		t.Error("TTSFinished() not signaled")
+	}
+}
+
+func TestMixerResourceQueue(t *testing.T) {
+	m := NewMixer()
+	finished := make(chan string, 2)
+	m.EnqueueResourceStream(bytes.NewReader([]byte{1, 1}), audio.ResourceOptions{
+		OnFinished: func(bool) { finished <- "a" },
+	})
+	m.EnqueueResourceStream(bytes.NewReader([]byte{2, 2}), audio.ResourceOptions{
+		OnFinished: func(bool) { finished <- "b" },
+	})
+	m.Stop()
+
+	if got := m.ResourceAudio(); !bytes.Equal(got, []byte{1, 1, 2, 2}) {
+		t.Errorf("ResourceAudio() = %v, want queued order", got)
+	}
+	if first, second := <-finished, <-finished; first != "a" || second != "b" {
+		t.Errorf("finished order = %s, %s", first, second)
+	}
+	if modes := m.ResourceModes(); len(modes) != 2 || modes[0] != audio.ResourceQueue {
+		t.Errorf("ResourceModes() = %v", modes)
+	}
+}
+
+func TestMixerRealtimePacing(t *testing.T) {
+	m := NewMixerWithConfig(MixerConfig{BytesPerSecond: 32000})
+	start := time.Now()
+	// 3 帧 20ms 的音频
+	m.AddTTSStream(bytes.NewReader(make([]byte, 640*3)))
+	m.Stop()
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("drained 60ms of audio in %v, want real-time pace", elapsed)
+	}
+	if got := len(m.TTSAudio()); got != 640*3 {
+		t.Errorf("TTSAudio() = %d bytes, want %d", got, 640*3)
+	}
+}
+
+// TestMixerWithTTSPipeline 用假 Provider 与假 Mixer 驱动真实的 TTSPipeline
+func TestMixerWithTTSPipeline(t *testing.T) {
+	provider := ttstest.NewProvider()
+	mixer := NewMixer()
+	config := audio.DefaultTTSPipelineConfig()
+	config.PrerollMs = 0
+	pipeline := audio.NewTTSPipeline(provider, config, tts.Config{Format: "pcm", SampleRate: 16000}, nil, nil)
+	pipeline.SetMixer(mixer)
+	if err := pipeline.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer pipeline.Stop()
+
+	if err := pipeline.EnqueueText("你好。", "default"); err != nil {
+		t.Fatalf("EnqueueText() error = %v", err)
+	}
+	select {
+	case <-mixer.TTSFinished():
+	case <-time.After(3 * time.Second):
+		t.Fatal("playback not finished")
+	}
+	mixer.Stop()
+	if got := len(mixer.TTSAudio()); got != 300 {
+		t.Errorf("TTSAudio() = %d bytes, want 300", got)
+	}
+	if provider.Starts() != 1 {
+		t.Errorf("provider.Starts() = %d, want 1", provider.Starts())
+	}
+}
+
+func TestSource(t *testing.T) {
+	s := NewSource(SourceConfig{Chunks: Silence(2, 320)})
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		chunk, err := s.Read(ctx)
+		if err != nil || len(chunk) != 320 {
+			t.Fatalf("Read() #%d = %d bytes, %v", i, len(chunk), err)
+		}
+	}
+	if _, err := s.Read(ctx); err != io.EOF {
+		t.Errorf("Read() at end error = %v, want io.EOF", err)
+	}
+
+	deviceErr := errors.New("device unplugged")
+	s = NewSource(SourceConfig{Chunks: Silence(5, 320), Err: deviceErr, FailAfter: 1})
+	if _, err := s.Read(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Read(ctx); !errors.Is(err, deviceErr) {
+		t.Errorf("Read() error = %v, want injected error", err)
+	}
+
+	s = NewSource(SourceConfig{Chunks: Silence(1, 320), Interval: time.Hour})
+	go s.Close()
+	if _, err := s.Read(ctx); !errors.Is(err, ErrSourceClosed) {
+		t.Errorf("Read() after Close error = %v, want ErrSourceClosed", err)
+	}
+}
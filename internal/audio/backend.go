@@ -0,0 +1,72 @@
+package audio
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 音频后端名称（audio.backend）
+const (
+	BackendPortAudio = "portaudio"
+	BackendALSA      = "alsa"
+	BackendPulse     = "pulse"
+)
+
+// StreamConfig 设备流参数
+type StreamConfig struct {
+	// Device 设备名称，为空时使用默认设备；PortAudio 按名称部分匹配，ALSA 为 PCM 名称（如 plughw:1,0），
+	// PulseAudio 为 sink / source 名称或描述（部分匹配）
+	Device          string
+	SampleRate      int
+	Channels        int
+	FramesPerBuffer int
+	// HighLatency 输入流使用设备的高延迟设置（适合蓝牙设备），后端不支持时忽略
+	HighLatency bool
+	// Format 输入流向设备请求的采样格式（只支持 float32 / 24-bit 采集的声卡），
+	// 后端转换为 16-bit 后由 Read 返回；设备不支持时后端退回 16-bit，返回的参数中为实际格式
+	Format SampleFormat
+}
+
+// InputStream 输入设备流
+type InputStream interface {
+	Start() error
+	// Read 阻塞读取一个缓冲（FramesPerBuffer 帧）的交错 16-bit PCM 到 buf，采集格式不是 16-bit 时已转换
+	Read(buf []int16) error
+	// Abort 立即停止，解除阻塞中的 Read
+	Abort() error
+	Stop() error
+	Close() error
+}
+
+// OutputStream 输出设备流，Start 后按设备节奏调用打开时传入的 render 拉取音频
+type OutputStream interface {
+	Start() error
+	Stop() error
+	Close() error
+}
+
+// AudioBackend 音频设备后端，MicrophoneSource 与 Mixer 通过它打开麦克风和扬声器
+type AudioBackend interface {
+	Name() string
+	// Initialize / Terminate 进程级初始化与释放，由 main 在打开设备前后各调用一次
+	Initialize() error
+	Terminate() error
+	// OpenInput 打开输入流；设备不支持请求的格式时可改用设备原生格式，返回实际打开的参数
+	OpenInput(cfg StreamConfig) (InputStream, StreamConfig, error)
+	// OpenOutput 打开输出流，render 每次填充一个缓冲的非交错 float32 样本（每声道一个切片）
+	OpenOutput(cfg StreamConfig, render func(out [][]float32)) (OutputStream, error)
+}
+
+// NewAudioBackend 按名称创建音频后端，为空时使用 PortAudio
+func NewAudioBackend(name string) (AudioBackend, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", BackendPortAudio:
+		return PortAudioBackend(), nil
+	case BackendALSA:
+		return NewALSABackend(), nil
+	case BackendPulse:
+		return NewPulseBackend(), nil
+	default:
+		return nil, fmt.Errorf("unknown audio backend %q (available: %s, %s, %s)", name, BackendPortAudio, BackendALSA, BackendPulse)
+	}
+}
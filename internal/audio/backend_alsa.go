@@ -0,0 +1,34 @@
+package audio
+
+import "strconv"
+
+// NewALSABackend 返回 ALSA 后端：通过 alsa-utils 的 arecord / aplay 读写设备，
+// 适合 PortAudio 难以编译或运行的嵌入式 Linux；设备名为 ALSA PCM 名称（如 plughw:1,0）
+func NewALSABackend() AudioBackend {
+	return &processBackend{
+		name:     BackendALSA,
+		pkg:      "alsa-utils",
+		record:   "arecord",
+		play:     "aplay",
+		args:     alsaArgs,
+		maxAhead: processMaxAhead,
+	}
+}
+
+// alsaFormats 采样格式对应的 arecord / aplay -f 参数
+var alsaFormats = map[SampleFormat]string{
+	SampleFormatS16: "S16_LE",
+	SampleFormatS24: "S24_3LE",
+	SampleFormatS32: "S32_LE",
+	SampleFormatF32: "FLOAT_LE",
+}
+
+// alsaArgs arecord / aplay 的原始 PCM 参数
+func alsaArgs(cfg StreamConfig) []string {
+	args := []string{"-q", "-t", "raw", "-f", alsaFormats[cfg.Format],
+		"-r", strconv.Itoa(cfg.SampleRate), "-c", strconv.Itoa(cfg.Channels)}
+	if cfg.Device != "" {
+		args = append(args, "-D", cfg.Device)
+	}
+	return args
+}
@@ -0,0 +1,252 @@
+package audio
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gordonklaus/portaudio"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// defaultOutputFrames 输出流每次回调的帧数
+const defaultOutputFrames = 1024
+
+// portAudioBackend 基于 PortAudio（cgo）的后端，支持按名称选择设备和格式协商
+type portAudioBackend struct{}
+
+// PortAudioBackend 返回 PortAudio 后端
+func PortAudioBackend() AudioBackend {
+	return portAudioBackend{}
+}
+
+func (portAudioBackend) Name() string { return BackendPortAudio }
+
+func (portAudioBackend) Initialize() error { return portaudio.Initialize() }
+
+func (portAudioBackend) Terminate() error { return portaudio.Terminate() }
+
+// portAudioInput 把绑定在 PortAudio 流上的缓冲转换为 16-bit 后复制给调用方
+type portAudioInput struct {
+	*portaudio.Stream
+	convert func(buf []int16)
+}
+
+func (s *portAudioInput) Read(buf []int16) error {
+	if err := s.Stream.Read(); err != nil {
+		return err
+	}
+	s.convert(buf)
+	return nil
+}
+
+// newInputBuffer 按采样格式分配 PortAudio 输入缓冲，返回传给 OpenStream 的缓冲指针和转换为 16-bit 的函数
+func newInputBuffer(format SampleFormat, samples int) (interface{}, func(buf []int16)) {
+	switch format {
+	case SampleFormatF32:
+		buffer := make([]float32, samples)
+		return &buffer, func(buf []int16) {
+			for i := range buf {
+				buf[i] = floatToInt16(float64(buffer[i]))
+			}
+		}
+	case SampleFormatS24:
+		// Int24 为本机字节序，支持的平台均为小端
+		buffer := make([]portaudio.Int24, samples)
+		return &buffer, func(buf []int16) {
+			for i := range buf {
+				buf[i] = int16(uint16(buffer[i][1]) | uint16(buffer[i][2])<<8)
+			}
+		}
+	case SampleFormatS32:
+		buffer := make([]int32, samples)
+		return &buffer, func(buf []int16) {
+			for i := range buf {
+				buf[i] = int16(buffer[i] >> 16)
+			}
+		}
+	default:
+		buffer := make([]int16, samples)
+		return &buffer, func(buf []int16) { copy(buf, buffer) }
+	}
+}
+
+// OpenInput 打开输入设备；依次尝试候选声道数和采样率，协商出设备支持的格式
+// 部分设备（如仅支持立体声的声卡、只支持 44.1/48kHz 的蓝牙/USB 设备）不能按请求的格式打开，
+// 此时改用设备原生格式，调用方按返回的参数做声道映射和重采样；
+// 请求的采样格式（cfg.Format）无法打开时退回 16-bit
+func (portAudioBackend) OpenInput(cfg StreamConfig) (InputStream, StreamConfig, error) {
+	stream, actual, err := openPortAudioInput(cfg)
+	if err != nil && cfg.Format != SampleFormatS16 {
+		logging.Warnf("PortAudio: failed to open %s input, falling back to s16: %v", cfg.Format, err)
+		cfg.Format = SampleFormatS16
+		return openPortAudioInput(cfg)
+	}
+	return stream, actual, err
+}
+
+// openPortAudioInput 按 cfg.Format 打开输入设备，协商声道数和采样率
+func openPortAudioInput(cfg StreamConfig) (InputStream, StreamConfig, error) {
+	var inputDevice *portaudio.DeviceInfo
+	var err error
+
+	if cfg.Device != "" {
+		inputDevice, err = findInputDeviceByName(cfg.Device)
+		if err != nil {
+			logging.Warnf("PortAudio: device %q not found, falling back to default: %v", cfg.Device, err)
+			inputDevice = nil
+		}
+	}
+	if inputDevice == nil {
+		inputDevice, err = portaudio.DefaultInputDevice()
+		if err != nil {
+			logging.Errorf("PortAudio: failed to get default input device: %v", err)
+			return openDefaultInput(cfg)
+		}
+	}
+
+	// 选择延迟模式
+	latency := inputDevice.DefaultLowInputLatency
+	latencyMode := "low"
+	if cfg.HighLatency {
+		latency = inputDevice.DefaultHighInputLatency
+		latencyMode = "high"
+	}
+	logging.Infof("PortAudio: input device=%s, %s latency=%.1fms", inputDevice.Name, latencyMode, latency.Seconds()*1000)
+
+	for _, format := range candidateFormats(inputDevice, cfg.SampleRate, cfg.Channels) {
+		frames := cfg.FramesPerBuffer * format.sampleRate / cfg.SampleRate
+		buffer, convert := newInputBuffer(cfg.Format, frames*format.channels)
+		streamParams := portaudio.StreamParameters{
+			Input: portaudio.StreamDeviceParameters{
+				Device:   inputDevice,
+				Channels: format.channels,
+				Latency:  latency,
+			},
+			SampleRate:      float64(format.sampleRate),
+			FramesPerBuffer: frames,
+		}
+
+		stream, err := portaudio.OpenStream(streamParams, buffer)
+		if err != nil {
+			logging.Warnf("PortAudio: failed to open input stream (sampleRate=%d, channels=%d, format=%s): %v",
+				format.sampleRate, format.channels, cfg.Format, err)
+			continue
+		}
+		if format.sampleRate != cfg.SampleRate || format.channels != cfg.Channels {
+			logging.Warnf("PortAudio: device does not support %d Hz/%d ch, using native %d Hz/%d ch",
+				cfg.SampleRate, cfg.Channels, format.sampleRate, format.channels)
+		}
+		actual := cfg
+		actual.Device = inputDevice.Name
+		actual.SampleRate, actual.Channels, actual.FramesPerBuffer = format.sampleRate, format.channels, frames
+		return &portAudioInput{Stream: stream, convert: convert}, actual, nil
+	}
+
+	logging.Errorf("PortAudio: no supported input format, falling back to default stream")
+	return openDefaultInput(cfg)
+}
+
+// openDefaultInput 按请求的格式打开默认输入流
+func openDefaultInput(cfg StreamConfig) (InputStream, StreamConfig, error) {
+	buffer, convert := newInputBuffer(cfg.Format, cfg.FramesPerBuffer*cfg.Channels)
+	stream, err := portaudio.OpenDefaultStream(cfg.Channels, 0, float64(cfg.SampleRate), cfg.FramesPerBuffer, buffer)
+	if err != nil {
+		return nil, cfg, err
+	}
+	return &portAudioInput{Stream: stream, convert: convert}, cfg, nil
+}
+
+// OpenOutput 打开输出流；指定的设备不存在时退回默认输出设备
+func (portAudioBackend) OpenOutput(cfg StreamConfig, render func(out [][]float32)) (OutputStream, error) {
+	frames := cfg.FramesPerBuffer
+	if frames <= 0 {
+		frames = defaultOutputFrames
+	}
+	if cfg.Device != "" {
+		device, err := findOutputDeviceByName(cfg.Device)
+		if err == nil {
+			params := portaudio.StreamParameters{
+				Output: portaudio.StreamDeviceParameters{
+					Device:   device,
+					Channels: cfg.Channels,
+					Latency:  device.DefaultLowOutputLatency,
+				},
+				SampleRate:      float64(cfg.SampleRate),
+				FramesPerBuffer: frames,
+			}
+			logging.Infof("PortAudio: output device=%s, sampleRate=%d, channels=%d", device.Name, cfg.SampleRate, cfg.Channels)
+			return portaudio.OpenStream(params, render)
+		}
+		logging.Warnf("PortAudio: output device %q not found, falling back to default: %v", cfg.Device, err)
+	}
+	return portaudio.OpenDefaultStream(0, cfg.Channels, float64(cfg.SampleRate), frames, render)
+}
+
+// streamFormat 输入流格式
+type streamFormat struct {
+	sampleRate int
+	channels   int
+}
+
+// candidateFormats 返回待尝试的输入格式：优先请求的格式，其次设备默认采样率和常见采样率，
+// 声道数在请求值不可用时退回设备最大输入声道数
+func candidateFormats(device *portaudio.DeviceInfo, sampleRate, channels int) []streamFormat {
+	rates := []int{sampleRate}
+	for _, rate := range []int{int(device.DefaultSampleRate), 48000, 44100} {
+		if rate > 0 && !containsInt(rates, rate) {
+			rates = append(rates, rate)
+		}
+	}
+	channelOptions := []int{channels}
+	if device.MaxInputChannels > 0 && device.MaxInputChannels != channels {
+		channelOptions = append(channelOptions, device.MaxInputChannels)
+	}
+
+	formats := make([]streamFormat, 0, len(rates)*len(channelOptions))
+	for _, rate := range rates {
+		for _, ch := range channelOptions {
+			formats = append(formats, streamFormat{sampleRate: rate, channels: ch})
+		}
+	}
+	return formats
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// findInputDeviceByName 按名称查找输入设备（支持部分匹配）
+func findInputDeviceByName(name string) (*portaudio.DeviceInfo, error) {
+	devices, err := portaudio.Devices()
+	if err != nil {
+		return nil, err
+	}
+	nameLower := strings.ToLower(name)
+	for _, dev := range devices {
+		if dev.MaxInputChannels > 0 && strings.Contains(strings.ToLower(dev.Name), nameLower) {
+			logging.Infof("PortAudio: found input device %q matching %q", dev.Name, name)
+			return dev, nil
+		}
+	}
+	return nil, fmt.Errorf("no input device found matching %q", name)
+}
+
+// findOutputDeviceByName 按名称查找输出设备（支持部分匹配）
+func findOutputDeviceByName(name string) (*portaudio.DeviceInfo, error) {
+	devices, err := portaudio.Devices()
+	if err != nil {
+		return nil, err
+	}
+	nameLower := strings.ToLower(name)
+	for _, dev := range devices {
+		if dev.MaxOutputChannels > 0 && strings.Contains(strings.ToLower(dev.Name), nameLower) {
+			return dev, nil
+		}
+	}
+	return nil, fmt.Errorf("no output device found matching %q", name)
+}
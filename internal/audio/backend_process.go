@@ -0,0 +1,249 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// processMaxAhead 输出写入超前实际播放时间的上限，避免管道和播放程序缓冲堆积音频、拖慢打断
+const processMaxAhead = 100 * time.Millisecond
+
+// processBackend 通过录音 / 播放子进程（arecord / aplay、parec / pacat）读写原始 PCM，
+// 不依赖 PortAudio；播放固定为 S16_LE，录音按 StreamConfig.Format 采集后转换为 16-bit，
+// 采样率与声道的转换交给声音服务或 ALSA plug 设备，不做格式协商
+type processBackend struct {
+	name     string
+	pkg      string // 提供录音 / 播放程序的软件包，用于错误提示
+	record   string
+	play     string
+	args     func(cfg StreamConfig) []string       // 播放时 cfg.Format 总是 SampleFormatS16
+	device   func(name string, output bool) string // 把配置的设备名解析为程序使用的名称，为 nil 时原样使用
+	maxAhead time.Duration
+}
+
+func (b *processBackend) Name() string { return b.name }
+
+// Initialize 检查录音 / 播放程序是否可用
+func (b *processBackend) Initialize() error {
+	for _, program := range []string{b.record, b.play} {
+		if _, err := exec.LookPath(program); err != nil {
+			return fmt.Errorf("%s backend requires %s (%s): %w", b.name, program, b.pkg, err)
+		}
+	}
+	return nil
+}
+
+func (b *processBackend) Terminate() error { return nil }
+
+// resolve 按后端规则解析设备名
+func (b *processBackend) resolve(cfg StreamConfig, output bool) StreamConfig {
+	if b.device != nil && cfg.Device != "" {
+		cfg.Device = b.device(cfg.Device, output)
+	}
+	return cfg
+}
+
+func (b *processBackend) OpenInput(cfg StreamConfig) (InputStream, StreamConfig, error) {
+	cfg = b.resolve(cfg, false)
+	cmd := exec.Command(b.record, b.args(cfg)...)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, cfg, err
+	}
+	logging.Infof("%s: input device=%q, sampleRate=%d, channels=%d, format=%s", b.name, cfg.Device, cfg.SampleRate, cfg.Channels, cfg.Format)
+	return &processInput{process: pcmProcess{cmd: cmd}, stdout: stdout, format: cfg.Format}, cfg, nil
+}
+
+func (b *processBackend) OpenOutput(cfg StreamConfig, render func(out [][]float32)) (OutputStream, error) {
+	cfg = b.resolve(cfg, true)
+	cfg.Format = SampleFormatS16
+	if cfg.FramesPerBuffer <= 0 {
+		cfg.FramesPerBuffer = defaultOutputFrames
+	}
+	cmd := exec.Command(b.play, b.args(cfg)...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	logging.Infof("%s: output device=%q, sampleRate=%d, channels=%d", b.name, cfg.Device, cfg.SampleRate, cfg.Channels)
+	return &processOutput{
+		process:  pcmProcess{cmd: cmd},
+		stdin:    stdin,
+		config:   cfg,
+		render:   render,
+		maxAhead: b.maxAhead,
+		stopCh:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// pcmProcess 录音 / 播放子进程，stop 结束进程，close 等待退出
+type pcmProcess struct {
+	mu      sync.Mutex
+	cmd     *exec.Cmd
+	started bool
+	stopped bool
+	closed  bool
+}
+
+func (p *pcmProcess) start() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.started {
+		return nil
+	}
+	if p.stopped {
+		return errors.New("stream closed")
+	}
+	if err := p.cmd.Start(); err != nil {
+		return fmt.Errorf("start %s: %w", p.cmd.Path, err)
+	}
+	p.started = true
+	return nil
+}
+
+func (p *pcmProcess) stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.started || p.stopped {
+		p.stopped = true
+		return nil
+	}
+	p.stopped = true
+	return p.cmd.Process.Kill()
+}
+
+func (p *pcmProcess) close() error {
+	p.stop()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.started || p.closed {
+		return nil
+	}
+	p.closed = true
+	// 进程是被 Kill 的，退出状态不作为错误
+	p.cmd.Wait()
+	return nil
+}
+
+// processInput 从录音程序的标准输出读取 format 格式的交错 PCM
+type processInput struct {
+	process pcmProcess
+	stdout  io.ReadCloser
+	format  SampleFormat
+	data    []byte
+}
+
+func (s *processInput) Start() error { return s.process.start() }
+
+func (s *processInput) Read(buf []int16) error {
+	if size := len(buf) * s.format.BytesPerSample(); len(s.data) != size {
+		s.data = make([]byte, size)
+	}
+	if _, err := io.ReadFull(s.stdout, s.data); err != nil {
+		return fmt.Errorf("read from %s: %w", s.process.cmd.Path, err)
+	}
+	pcm := ConvertToS16(s.data, s.format)
+	for i := range buf {
+		buf[i] = int16(pcm[i*2]) | int16(pcm[i*2+1])<<8
+	}
+	return nil
+}
+
+func (s *processInput) Abort() error { return s.process.stop() }
+func (s *processInput) Stop() error  { return s.process.stop() }
+func (s *processInput) Close() error { return s.process.close() }
+
+// processOutput 按实际时间节奏调用 render 并写入播放程序的标准输入，写入超前不超过 maxAhead
+type processOutput struct {
+	process  pcmProcess
+	stdin    io.WriteCloser
+	config   StreamConfig
+	render   func(out [][]float32)
+	maxAhead time.Duration
+
+	loopOnce sync.Once
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	done     chan struct{}
+}
+
+func (s *processOutput) Start() error {
+	if err := s.process.start(); err != nil {
+		return err
+	}
+	s.loopOnce.Do(func() { go s.loop() })
+	return nil
+}
+
+func (s *processOutput) loop() {
+	defer close(s.done)
+	frames, channels := s.config.FramesPerBuffer, s.config.Channels
+	out := make([][]float32, channels)
+	for i := range out {
+		out[i] = make([]float32, frames)
+	}
+	pcm := make([]byte, frames*channels*2)
+	start := time.Now()
+	var written int64
+	for {
+		select {
+		case <-s.stopCh:
+			return
+		default:
+		}
+		s.render(out)
+		for i := 0; i < frames; i++ {
+			for ch := 0; ch < channels; ch++ {
+				v := floatToInt16(float64(out[ch][i]))
+				n := (i*channels + ch) * 2
+				pcm[n], pcm[n+1] = byte(v), byte(v>>8)
+			}
+		}
+		if _, err := s.stdin.Write(pcm); err != nil {
+			select {
+			case <-s.stopCh:
+			default:
+				logging.Errorf("Audio backend: write to %s: %v", s.process.cmd.Path, err)
+			}
+			return
+		}
+		written += int64(frames)
+		ahead := time.Duration(written)*time.Second/time.Duration(s.config.SampleRate) - time.Since(start)
+		if ahead > s.maxAhead {
+			select {
+			case <-s.stopCh:
+				return
+			case <-time.After(ahead - s.maxAhead):
+			}
+		}
+	}
+}
+
+// Stop 停止拉取音频并结束播放程序（丢弃尚未播放的缓冲）
+func (s *processOutput) Stop() error {
+	s.stopOnce.Do(func() { close(s.stopCh) })
+	err := s.process.stop()
+	s.process.mu.Lock()
+	started := s.process.started
+	s.process.mu.Unlock()
+	if started {
+		<-s.done
+	}
+	return err
+}
+
+func (s *processOutput) Close() error {
+	s.Stop()
+	s.stdin.Close()
+	return s.process.close()
+}
@@ -0,0 +1,161 @@
+package audio
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// pulseLatencyMs parec / pacat 请求的缓冲延迟
+const pulseLatencyMs = 50
+
+// NewPulseBackend 返回 PulseAudio 后端：通过 parec / pacat 读写声音服务，PipeWire（pipewire-pulse）同样适用；
+// 设备名为 sink / source 名称或描述（部分匹配），可把 TTS 输出到指定 sink 而不改系统默认设备
+func NewPulseBackend() AudioBackend {
+	return &processBackend{
+		name:     BackendPulse,
+		pkg:      "pulseaudio-utils",
+		record:   "parec",
+		play:     "pacat",
+		args:     pulseArgs,
+		device:   resolvePulseDevice,
+		maxAhead: processMaxAhead,
+	}
+}
+
+// pulseFormats 采样格式对应的 parec / pacat --format 参数
+var pulseFormats = map[SampleFormat]string{
+	SampleFormatS16: "s16le",
+	SampleFormatS24: "s24le",
+	SampleFormatS32: "s32le",
+	SampleFormatF32: "float32le",
+}
+
+// pulseArgs parec / pacat 的原始 PCM 参数
+func pulseArgs(cfg StreamConfig) []string {
+	args := []string{"--raw", "--format=" + pulseFormats[cfg.Format],
+		"--rate=" + strconv.Itoa(cfg.SampleRate), "--channels=" + strconv.Itoa(cfg.Channels),
+		"--latency-msec=" + strconv.Itoa(pulseLatencyMs), "--client-name=voicebot"}
+	if cfg.Device != "" {
+		args = append(args, "--device="+cfg.Device)
+	}
+	return args
+}
+
+// PulseDevice PulseAudio / PipeWire 的 sink（输出）或 source（输入）
+type PulseDevice struct {
+	Name        string // 设备名称，如 alsa_output.usb-0d8c_USB_Audio-00.analog-stereo
+	Description string // 可读名称，如 USB Audio Analog Stereo
+	Default     bool
+}
+
+// ListPulseDevices 通过 pactl 列出 sink（output 为 true）或 source（不含 monitor）
+func ListPulseDevices(output bool) ([]PulseDevice, error) {
+	kind := "sources"
+	if output {
+		kind = "sinks"
+	}
+	list, err := pactl("list", kind)
+	if err != nil {
+		return nil, err
+	}
+	info, err := pactl("info")
+	if err != nil {
+		return nil, err
+	}
+	return parsePulseDevices(list, pulseDefault(info, output)), nil
+}
+
+// pactl 以 C locale 运行 pactl，输出字段名不随系统语言变化
+func pactl(args ...string) (string, error) {
+	cmd := exec.Command("pactl", args...)
+	cmd.Env = append(os.Environ(), "LC_ALL=C")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("pactl %s: %w", strings.Join(args, " "), err)
+	}
+	return string(out), nil
+}
+
+// parsePulseDevices 解析 `pactl list sinks|sources` 的输出，跳过 sink 的 monitor source
+func parsePulseDevices(list, defaultName string) []PulseDevice {
+	var devices []PulseDevice
+	var current *PulseDevice
+	monitor := false
+	flush := func() {
+		if current != nil && current.Name != "" && !monitor {
+			current.Default = current.Name == defaultName
+			devices = append(devices, *current)
+		}
+		current, monitor = nil, false
+	}
+	for _, line := range strings.Split(list, "\n") {
+		switch {
+		case strings.HasPrefix(line, "Sink #"), strings.HasPrefix(line, "Source #"):
+			flush()
+			current = &PulseDevice{}
+		case current == nil:
+		case strings.HasPrefix(line, "\tName: "):
+			current.Name = strings.TrimSpace(strings.TrimPrefix(line, "\tName: "))
+		case strings.HasPrefix(line, "\tDescription: "):
+			current.Description = strings.TrimSpace(strings.TrimPrefix(line, "\tDescription: "))
+		case strings.HasPrefix(line, "\tMonitor of Sink: "):
+			monitor = strings.TrimSpace(strings.TrimPrefix(line, "\tMonitor of Sink: ")) != "n/a"
+		}
+	}
+	flush()
+	return devices
+}
+
+// pulseDefault 从 `pactl info` 的输出中取默认 sink / source 名称
+func pulseDefault(info string, output bool) string {
+	prefix := "Default Source: "
+	if output {
+		prefix = "Default Sink: "
+	}
+	for _, line := range strings.Split(info, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix))
+		}
+	}
+	return ""
+}
+
+// matchPulseDevice 优先按名称精确匹配，其次按名称或描述部分匹配（不区分大小写）
+func matchPulseDevice(devices []PulseDevice, name string) (PulseDevice, bool) {
+	for _, dev := range devices {
+		if dev.Name == name {
+			return dev, true
+		}
+	}
+	nameLower := strings.ToLower(name)
+	for _, dev := range devices {
+		if strings.Contains(strings.ToLower(dev.Name), nameLower) ||
+			strings.Contains(strings.ToLower(dev.Description), nameLower) {
+			return dev, true
+		}
+	}
+	return PulseDevice{}, false
+}
+
+// resolvePulseDevice 把配置的设备名解析为 sink / source 名称；找不到时使用默认设备，pactl 不可用时原样使用
+func resolvePulseDevice(name string, output bool) string {
+	devices, err := ListPulseDevices(output)
+	if err != nil {
+		logging.Warnf("PulseAudio: failed to list devices, using %q as is: %v", name, err)
+		return name
+	}
+	dev, ok := matchPulseDevice(devices, name)
+	if !ok {
+		logging.Warnf("PulseAudio: device %q not found, falling back to default", name)
+		return ""
+	}
+	if dev.Name != name {
+		logging.Infof("PulseAudio: found device %q (%s) matching %q", dev.Name, dev.Description, name)
+	}
+	return dev.Name
+}
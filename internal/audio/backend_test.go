@@ -0,0 +1,292 @@
+package audio
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+func TestNewAudioBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"", BackendPortAudio, false},
+		{"PortAudio", BackendPortAudio, false},
+		{"alsa", BackendALSA, false},
+		{"pulse", BackendPulse, false},
+		{"coreaudio", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, err := NewAudioBackend(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewAudioBackend(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if err == nil && backend.Name() != tt.want {
+				t.Fatalf("backend = %s, want %s", backend.Name(), tt.want)
+			}
+		})
+	}
+}
+
+func TestCandidateFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		device *portaudio.DeviceInfo
+		want   []streamFormat
+	}{
+		{
+			name:   "device supports request",
+			device: &portaudio.DeviceInfo{DefaultSampleRate: 16000, MaxInputChannels: 1},
+			want:   []streamFormat{{16000, 1}, {48000, 1}, {44100, 1}},
+		},
+		{
+			name:   "stereo bluetooth device at 48k",
+			device: &portaudio.DeviceInfo{DefaultSampleRate: 48000, MaxInputChannels: 2},
+			want:   []streamFormat{{16000, 1}, {16000, 2}, {48000, 1}, {48000, 2}, {44100, 1}, {44100, 2}},
+		},
+		{
+			name:   "44.1k default rate tried first",
+			device: &portaudio.DeviceInfo{DefaultSampleRate: 44100, MaxInputChannels: 1},
+			want:   []streamFormat{{16000, 1}, {44100, 1}, {48000, 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := candidateFormats(tt.device, 16000, 1)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("candidateFormats() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestALSAArgs(t *testing.T) {
+	got := alsaArgs(StreamConfig{Device: "plughw:1,0", SampleRate: 16000, Channels: 2})
+	want := []string{"-q", "-t", "raw", "-f", "S16_LE", "-r", "16000", "-c", "2", "-D", "plughw:1,0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("alsaArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestProcessInputFormatArgs(t *testing.T) {
+	cfg := StreamConfig{SampleRate: 48000, Channels: 2, Format: SampleFormatF32}
+	if got := alsaArgs(cfg)[4]; got != "FLOAT_LE" {
+		t.Errorf("alsa format = %q, want FLOAT_LE", got)
+	}
+	cfg.Format = SampleFormatS24
+	if got := pulseArgs(cfg)[1]; got != "--format=s24le" {
+		t.Errorf("pulse format = %q, want --format=s24le", got)
+	}
+}
+
+func TestPulseArgs(t *testing.T) {
+	got := pulseArgs(StreamConfig{Device: "alsa_output.usb.analog-stereo", SampleRate: 24000, Channels: 1})
+	want := []string{"--raw", "--format=s16le", "--rate=24000", "--channels=1", "--latency-msec=50",
+		"--client-name=voicebot", "--device=alsa_output.usb.analog-stereo"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("pulseArgs() = %v, want %v", got, want)
+	}
+}
+
+const pactlSources = `Source #0
+	State: SUSPENDED
+	Name: alsa_output.pci-0000_00_1f.3.analog-stereo.monitor
+	Description: Monitor of Built-in Audio Analog Stereo
+	Monitor of Sink: alsa_output.pci-0000_00_1f.3.analog-stereo
+	Properties:
+		device.description = "Monitor of Built-in Audio Analog Stereo"
+
+Source #1
+	State: RUNNING
+	Name: alsa_input.usb-0d8c_USB_Audio-00.mono-fallback
+	Description: USB Audio Mono
+	Monitor of Sink: n/a
+	Properties:
+		device.description = "USB Audio Mono"
+`
+
+const pactlSinks = `Sink #0
+	State: SUSPENDED
+	Name: alsa_output.pci-0000_00_1f.3.analog-stereo
+	Description: Built-in Audio Analog Stereo
+
+Sink #2
+	State: RUNNING
+	Name: bluez_output.AC_80_0A_11_22_33.1
+	Description: WH-1000XM4
+`
+
+func TestParsePulseDevices(t *testing.T) {
+	sinks := parsePulseDevices(pactlSinks, pulseDefault("Server Name: PulseAudio (on PipeWire 1.0.5)\nDefault Sink: bluez_output.AC_80_0A_11_22_33.1\n", true))
+	wantSinks := []PulseDevice{
+		{Name: "alsa_output.pci-0000_00_1f.3.analog-stereo", Description: "Built-in Audio Analog Stereo"},
+		{Name: "bluez_output.AC_80_0A_11_22_33.1", Description: "WH-1000XM4", Default: true},
+	}
+	if !reflect.DeepEqual(sinks, wantSinks) {
+		t.Fatalf("sinks = %+v, want %+v", sinks, wantSinks)
+	}
+
+	sources := parsePulseDevices(pactlSources, "")
+	wantSources := []PulseDevice{{Name: "alsa_input.usb-0d8c_USB_Audio-00.mono-fallback", Description: "USB Audio Mono"}}
+	if !reflect.DeepEqual(sources, wantSources) {
+		t.Fatalf("sources = %+v, want %+v (monitor sources skipped)", sources, wantSources)
+	}
+}
+
+func TestMatchPulseDevice(t *testing.T) {
+	devices := parsePulseDevices(pactlSinks, "")
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{"bluez_output.AC_80_0A_11_22_33.1", "bluez_output.AC_80_0A_11_22_33.1", true},
+		{"1000xm4", "bluez_output.AC_80_0A_11_22_33.1", true},
+		{"analog-stereo", "alsa_output.pci-0000_00_1f.3.analog-stereo", true},
+		{"HDMI", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := matchPulseDevice(devices, tt.name)
+			if ok != tt.wantOK || got.Name != tt.want {
+				t.Fatalf("matchPulseDevice(%q) = %q, %v, want %q, %v", tt.name, got.Name, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// writeScript 写入可执行的 shell 脚本，代替录音 / 播放程序
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "fake.sh")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestALSAInputFloat(t *testing.T) {
+	// 0.5 与 -0.5 的 float32 小端编码，读取时转换为 16-bit
+	backend := &processBackend{name: BackendALSA, record: writeScript(t, `printf '\000\000\000\077\000\000\000\277'; exec sleep 10`), args: alsaArgs}
+	stream, actual, err := backend.OpenInput(StreamConfig{SampleRate: 16000, Channels: 1, FramesPerBuffer: 2, Format: SampleFormatF32})
+	if err != nil {
+		t.Fatalf("OpenInput() error = %v", err)
+	}
+	defer stream.Close()
+	if actual.Format != SampleFormatF32 {
+		t.Fatalf("actual format = %s, want f32", actual.Format)
+	}
+	if err := stream.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	buf := make([]int16, 2)
+	if err := stream.Read(buf); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(buf, []int16{16384, -16384}) {
+		t.Fatalf("Read() = %v, want [16384 -16384]", buf)
+	}
+}
+
+func TestNewInputBuffer(t *testing.T) {
+	tests := []struct {
+		format SampleFormat
+		fill   func(buffer interface{})
+	}{
+		{SampleFormatS16, func(b interface{}) { copy(*b.(*[]int16), []int16{16384, -16384}) }},
+		{SampleFormatS24, func(b interface{}) { copy(*b.(*[]portaudio.Int24), []portaudio.Int24{{0, 0, 0x40}, {0, 0, 0xC0}}) }},
+		{SampleFormatS32, func(b interface{}) { copy(*b.(*[]int32), []int32{1 << 30, -1 << 30}) }},
+		{SampleFormatF32, func(b interface{}) { copy(*b.(*[]float32), []float32{0.5, -0.5}) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			buffer, convert := newInputBuffer(tt.format, 2)
+			tt.fill(buffer)
+			buf := make([]int16, 2)
+			convert(buf)
+			if !reflect.DeepEqual(buf, []int16{16384, -16384}) {
+				t.Fatalf("converted = %v, want [16384 -16384]", buf)
+			}
+		})
+	}
+}
+
+func TestALSAInput(t *testing.T) {
+	backend := &processBackend{name: BackendALSA, record: writeScript(t, `printf '\001\000\377\377'; exec sleep 10`), args: alsaArgs}
+	stream, actual, err := backend.OpenInput(StreamConfig{SampleRate: 16000, Channels: 1, FramesPerBuffer: 2})
+	if err != nil {
+		t.Fatalf("OpenInput() error = %v", err)
+	}
+	if actual.SampleRate != 16000 || actual.Channels != 1 {
+		t.Fatalf("actual config = %+v", actual)
+	}
+	if err := stream.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	buf := make([]int16, 2)
+	if err := stream.Read(buf); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if !reflect.DeepEqual(buf, []int16{1, -1}) {
+		t.Fatalf("Read() = %v, want [1 -1]", buf)
+	}
+
+	// Abort 解除阻塞中的 Read
+	readErr := make(chan error, 1)
+	go func() { readErr <- stream.Read(buf) }()
+	stream.Abort()
+	select {
+	case err := <-readErr:
+		if err == nil {
+			t.Fatal("Read() after Abort should fail")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Read() not unblocked by Abort")
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+func TestALSAOutput(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out.pcm")
+	backend := &processBackend{name: BackendALSA, play: writeScript(t, "exec cat > "+out), args: alsaArgs, maxAhead: processMaxAhead}
+	stream, err := backend.OpenOutput(StreamConfig{SampleRate: 16000, Channels: 2, FramesPerBuffer: 160}, func(buf [][]float32) {
+		for i := range buf[0] {
+			buf[0][i], buf[1][i] = 0.5, -0.5
+		}
+	})
+	if err != nil {
+		t.Fatalf("OpenOutput() error = %v", err)
+	}
+	if err := stream.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := stream.Stop(); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) < 4 || !reflect.DeepEqual(data[:4], []byte{0x00, 0x40, 0x00, 0xc0}) {
+		t.Fatalf("first frame = % x, want interleaved 16-bit samples", data[:min(len(data), 4)])
+	}
+	// 按实际时间节奏写入：50ms 内最多写出 maxAhead 加一个缓冲的超前量
+	maxBytes := int((50*time.Millisecond + processMaxAhead + 20*time.Millisecond).Seconds() * 16000 * 2 * 2)
+	if len(data) > maxBytes {
+		t.Fatalf("wrote %d bytes in 50ms, pacing should limit to %d", len(data), maxBytes)
+	}
+}
@@ -0,0 +1,60 @@
+package audio
+
+import "math"
+
+// HighPassConfig 高通滤波配置，用于去除直流偏置和低频轰鸣
+type HighPassConfig struct {
+	Enabled  bool
+	CutoffHz float64 // 截止频率
+	Q        float64 // 品质因数，0.707 为 Butterworth 响应
+}
+
+// DefaultHighPassConfig 默认高通滤波配置（80Hz）
+func DefaultHighPassConfig() HighPassConfig {
+	return HighPassConfig{
+		Enabled:  true,
+		CutoffHz: 80,
+		Q:        math.Sqrt2 / 2,
+	}
+}
+
+// biquad 二阶 IIR 滤波器（转置直接 II 型）
+type biquad struct {
+	b0, b1, b2 float64
+	a1, a2     float64
+	z1, z2     float64
+}
+
+// newHighPassBiquad 按 RBJ Audio EQ Cookbook 计算高通系数
+func newHighPassBiquad(sampleRate int, cutoffHz, q float64) *biquad {
+	if q <= 0 {
+		q = math.Sqrt2 / 2
+	}
+	nyquist := float64(sampleRate) / 2
+	if cutoffHz <= 0 || cutoffHz >= nyquist {
+		cutoffHz = 80
+	}
+
+	w0 := 2 * math.Pi * cutoffHz / float64(sampleRate)
+	cosW0 := math.Cos(w0)
+	alpha := math.Sin(w0) / (2 * q)
+	a0 := 1 + alpha
+
+	return &biquad{
+		b0: (1 + cosW0) / 2 / a0,
+		b1: -(1 + cosW0) / a0,
+		b2: (1 + cosW0) / 2 / a0,
+		a1: -2 * cosW0 / a0,
+		a2: (1 - alpha) / a0,
+	}
+}
+
+// Process 原地滤波归一化采样
+func (f *biquad) Process(samples []float64) {
+	for i, x := range samples {
+		y := f.b0*x + f.z1
+		f.z1 = f.b1*x - f.a1*y + f.z2
+		f.z2 = f.b2*x - f.a2*y
+		samples[i] = y
+	}
+}
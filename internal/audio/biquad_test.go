@@ -0,0 +1,52 @@
+package audio
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHighPassBiquad(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   func(n int) []float64
+		minGain float64
+		maxGain float64
+	}{
+		{"removes DC offset", func(n int) []float64 {
+			out := make([]float64, n)
+			for i := range out {
+				out[i] = 0.2
+			}
+			return out
+		}, 0, 0.01},
+		{"attenuates 20Hz rumble", func(n int) []float64 { return sineSamples(n, 16000, 20, 0.5) }, 0, 0.15},
+		{"passes 1kHz speech band", func(n int) []float64 { return sineSamples(n, 16000, 1000, 0.5) }, 0.95, 1.05},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultHighPassConfig()
+			f := newHighPassBiquad(16000, cfg.CutoffHz, cfg.Q)
+
+			in := tt.input(16000)
+			out := append([]float64(nil), in...)
+			f.Process(out)
+
+			// 跳过前 0.5 秒的瞬态
+			gain := rmsOf(out[8000:]) / rmsOf(in[8000:])
+			if gain < tt.minGain || gain > tt.maxGain {
+				t.Errorf("gain = %.4f, want [%.2f, %.2f]", gain, tt.minGain, tt.maxGain)
+			}
+		})
+	}
+}
+
+func TestHighPassBiquadInvalidParams(t *testing.T) {
+	f := newHighPassBiquad(16000, 0, 0)
+	samples := []float64{1, 1, 1}
+	f.Process(samples)
+	for _, v := range samples {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			t.Fatalf("unexpected sample %v", v)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package audio
+
+import (
+	"context"
+	"io"
+)
+
+// ChannelDownmix 表示对所有声道取平均混为单声道
+const ChannelDownmix = -1
+
+// ChannelMapSource 将多声道交错 PCM 转换为单声道的 AudioSource 装饰器
+// selectChannel 为 ChannelDownmix 时下混，否则取指定声道（从 0 开始）
+type ChannelMapSource struct {
+	source        AudioSource
+	inputChannels int
+	selectChannel int
+}
+
+func NewChannelMapSource(source AudioSource, inputChannels, selectChannel int) *ChannelMapSource {
+	if selectChannel >= inputChannels {
+		selectChannel = ChannelDownmix
+	}
+	return &ChannelMapSource{
+		source:        source,
+		inputChannels: inputChannels,
+		selectChannel: selectChannel,
+	}
+}
+
+func (s *ChannelMapSource) Read(ctx context.Context) ([]byte, error) {
+	if s.source == nil {
+		return nil, io.EOF
+	}
+	data, err := s.source.Read(ctx)
+	if err != nil || s.inputChannels <= 1 || len(data) == 0 {
+		return data, err
+	}
+
+	samples := bytesToInt16(data)
+	// 丢弃不完整的帧
+	samples = samples[:len(samples)/s.inputChannels*s.inputChannels]
+
+	var mono []int16
+	if s.selectChannel >= 0 {
+		mono = selectChannel(samples, s.inputChannels, s.selectChannel)
+	} else {
+		mono = downmixToMono(samples, s.inputChannels)
+	}
+
+	out := make([]byte, len(mono)*2)
+	int16ToBytes(mono, out)
+	return out, nil
+}
+
+func (s *ChannelMapSource) Close() error {
+	if s.source != nil {
+		return s.source.Close()
+	}
+	return nil
+}
+
+// selectChannel 从交错 PCM 中取出指定声道
+func selectChannel(samples []int16, channels, channel int) []int16 {
+	mono := make([]int16, len(samples)/channels)
+	for i := range mono {
+		mono[i] = samples[i*channels+channel]
+	}
+	return mono
+}
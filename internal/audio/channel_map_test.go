@@ -0,0 +1,39 @@
+package audio
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestChannelMapSource(t *testing.T) {
+	// 立体声交错：L=100, R=300；最后一个不完整的帧应被丢弃
+	stereo := []int16{100, 300, -200, 400, 1000, -1000, 7}
+	data := make([]byte, len(stereo)*2)
+	int16ToBytes(stereo, data)
+
+	tests := []struct {
+		name          string
+		inputChannels int
+		selectChannel int
+		want          []int16
+	}{
+		{"downmix", 2, ChannelDownmix, []int16{200, 100, 0}},
+		{"select left", 2, 0, []int16{100, -200, 1000}},
+		{"select right", 2, 1, []int16{300, 400, -1000}},
+		{"out of range falls back to downmix", 2, 5, []int16{200, 100, 0}},
+		{"mono passes through", 1, ChannelDownmix, stereo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := NewChannelMapSource(&stubSource{data: data}, tt.inputChannels, tt.selectChannel)
+			out, err := src.Read(context.Background())
+			if err != nil {
+				t.Fatalf("Read() error = %v", err)
+			}
+			if got := bytesToInt16(out); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Read() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package audio
+
+import (
+	"context"
+	"io"
+)
+
+// InputDSPConfig 输入音频处理链配置（高通 → 降噪 → 自动增益）
+type InputDSPConfig struct {
+	HighPass         HighPassConfig
+	AGC              AGCConfig
+	NoiseSuppression NoiseSuppressionConfig
+}
+
+// DefaultInputDSPConfig 默认输入处理配置（仅开启 80Hz 高通）
+func DefaultInputDSPConfig() InputDSPConfig {
+	return InputDSPConfig{
+		HighPass:         DefaultHighPassConfig(),
+		AGC:              DefaultAGCConfig(),
+		NoiseSuppression: DefaultNoiseSuppressionConfig(),
+	}
+}
+
+// Enabled 是否启用了任一处理
+func (c InputDSPConfig) Enabled() bool {
+	return c.HighPass.Enabled || c.AGC.Enabled || c.NoiseSuppression.Enabled
+}
+
+// InputDSPSource 在读取时对音频做高通滤波、降噪和自动增益的 AudioSource 装饰器
+// 放在 MicrophoneSource 与 EchoCancellingSource 之间，仅处理单声道 16-bit PCM
+type InputDSPSource struct {
+	source     AudioSource
+	config     InputDSPConfig
+	channels   int
+	highPass   *biquad
+	suppressor *noiseSuppressor
+	agc        *agcProcessor
+}
+
+func NewInputDSPSource(source AudioSource, config InputDSPConfig, sampleRate, channels int) *InputDSPSource {
+	s := &InputDSPSource{
+		source:   source,
+		config:   config,
+		channels: channels,
+	}
+	if config.HighPass.Enabled {
+		s.highPass = newHighPassBiquad(sampleRate, config.HighPass.CutoffHz, config.HighPass.Q)
+	}
+	if config.NoiseSuppression.Enabled {
+		s.suppressor = newNoiseSuppressor(config.NoiseSuppression, sampleRate)
+	}
+	if config.AGC.Enabled {
+		s.agc = newAGCProcessor(config.AGC, sampleRate)
+	}
+	return s
+}
+
+func (s *InputDSPSource) Read(ctx context.Context) ([]byte, error) {
+	if s.source == nil {
+		return nil, io.EOF
+	}
+	data, err := s.source.Read(ctx)
+	if err != nil || len(data) < 2 {
+		return data, err
+	}
+	if s.channels != 1 || (s.highPass == nil && s.suppressor == nil && s.agc == nil) {
+		return data, nil
+	}
+
+	pcm := bytesToInt16(data)
+	samples := make([]float64, len(pcm))
+	for i, v := range pcm {
+		samples[i] = float64(v) / 32768.0
+	}
+
+	// 先去直流和低频轰鸣，再降噪，最后增益，避免 AGC 把底噪一起放大
+	if s.highPass != nil {
+		s.highPass.Process(samples)
+	}
+	if s.suppressor != nil {
+		s.suppressor.Process(samples)
+	}
+	if s.agc != nil {
+		s.agc.Process(samples)
+	}
+
+	for i, v := range samples {
+		pcm[i] = floatToInt16(v)
+	}
+	processed := make([]byte, len(data))
+	int16ToBytes(pcm, processed)
+	// 奇数字节长度时保留最后一个字节
+	if len(data)%2 == 1 {
+		processed[len(data)-1] = data[len(data)-1]
+	}
+	return processed, nil
+}
+
+func (s *InputDSPSource) Close() error {
+	if s.source != nil {
+		return s.source.Close()
+	}
+	return nil
+}
+
+func floatToInt16(v float64) int16 {
+	v *= 32768.0
+	if v > 32767 {
+		return 32767
+	}
+	if v < -32768 {
+		return -32768
+	}
+	return int16(v)
+}
@@ -0,0 +1,230 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"math/cmplx"
+	"math/rand"
+	"testing"
+)
+
+func sineSamples(n, sampleRate int, freq, amplitude float64) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = amplitude * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate))
+	}
+	return out
+}
+
+func floatsToPCM(samples []float64) []byte {
+	pcm := make([]int16, len(samples))
+	for i, v := range samples {
+		pcm[i] = floatToInt16(v)
+	}
+	data := make([]byte, len(pcm)*2)
+	int16ToBytes(pcm, data)
+	return data
+}
+
+func pcmRMS(data []byte) float64 {
+	pcm := bytesToInt16(data)
+	samples := make([]float64, len(pcm))
+	for i, v := range pcm {
+		samples[i] = float64(v) / 32768.0
+	}
+	return rmsOf(samples)
+}
+
+func TestFFTRoundTrip(t *testing.T) {
+	x := make([]complex128, 16)
+	for i := range x {
+		x[i] = complex(float64(i%5)-2, 0)
+	}
+	orig := append([]complex128(nil), x...)
+
+	fft(x, false)
+	// 直流分量等于采样之和
+	var sum float64
+	for _, v := range orig {
+		sum += real(v)
+	}
+	if math.Abs(real(x[0])-sum) > 1e-9 {
+		t.Errorf("DC bin = %v, want %v", x[0], sum)
+	}
+
+	fft(x, true)
+	for i := range x {
+		if cmplx.Abs(x[i]-orig[i]) > 1e-9 {
+			t.Fatalf("round trip mismatch at %d: %v vs %v", i, x[i], orig[i])
+		}
+	}
+}
+
+func TestAGCProcessor(t *testing.T) {
+	tests := []struct {
+		name      string
+		amplitude float64
+		check     func(t *testing.T, outRMS float64)
+	}{
+		{"boosts quiet input", 0.01, func(t *testing.T, outRMS float64) {
+			if outRMS < 0.05 {
+				t.Errorf("quiet input RMS = %.4f, expected boost towards target", outRMS)
+			}
+		}},
+		{"attenuates loud input", 0.9, func(t *testing.T, outRMS float64) {
+			if outRMS > 0.9/math.Sqrt2*0.6 {
+				t.Errorf("loud input RMS = %.4f, expected attenuation", outRMS)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultAGCConfig()
+			cfg.Enabled = true
+			agc := newAGCProcessor(cfg, 16000)
+
+			var last []float64
+			for i := 0; i < 300; i++ { // 3 秒
+				frame := sineSamples(160, 16000, 440, tt.amplitude)
+				agc.Process(frame)
+				last = frame
+			}
+			for _, v := range last {
+				if math.Abs(v) > 1 {
+					t.Fatalf("sample %.4f exceeds full scale", v)
+				}
+			}
+			tt.check(t, rmsOf(last))
+		})
+	}
+}
+
+func TestAGCProcessorHoldsGainInSilence(t *testing.T) {
+	cfg := DefaultAGCConfig()
+	cfg.Enabled = true
+	agc := newAGCProcessor(cfg, 16000)
+
+	agc.Process(make([]float64, 16000))
+	if agc.Gain() != 1 {
+		t.Errorf("gain = %v, expected unchanged for silence", agc.Gain())
+	}
+}
+
+func TestSoftLimit(t *testing.T) {
+	for _, x := range []float64{0.5, 0.95, 2, -3} {
+		y := softLimit(x)
+		if math.Abs(y) > 1 || math.Signbit(y) != math.Signbit(x) {
+			t.Errorf("softLimit(%v) = %v", x, y)
+		}
+	}
+	if softLimit(0.5) != 0.5 {
+		t.Error("softLimit should not change samples below knee")
+	}
+}
+
+func TestNoiseSuppressorReducesStationaryNoise(t *testing.T) {
+	cfg := DefaultNoiseSuppressionConfig()
+	cfg.Enabled = true
+	ns := newNoiseSuppressor(cfg, 16000)
+	rng := rand.New(rand.NewSource(1))
+
+	var inRMS, outRMS float64
+	for i := 0; i < 100; i++ { // 2 秒白噪声
+		frame := make([]float64, 320)
+		for j := range frame {
+			frame[j] = rng.NormFloat64() * 0.02
+		}
+		if i >= 50 {
+			inRMS += rmsOf(frame)
+		}
+		ns.Process(frame)
+		if i >= 50 {
+			outRMS += rmsOf(frame)
+		}
+	}
+	if outRMS > inRMS*0.5 {
+		t.Errorf("noise RMS in=%.4f out=%.4f, expected at least 6dB reduction", inRMS/50, outRMS/50)
+	}
+}
+
+func TestNoiseSuppressorKeepsLength(t *testing.T) {
+	cfg := DefaultNoiseSuppressionConfig()
+	cfg.Enabled = true
+	ns := newNoiseSuppressor(cfg, 16000)
+
+	for _, n := range []int{1, 100, 128, 333, 3200} {
+		frame := sineSamples(n, 16000, 300, 0.3)
+		ns.Process(frame)
+		if len(frame) != n {
+			t.Fatalf("length changed: %d -> %d", n, len(frame))
+		}
+	}
+}
+
+func TestInputDSPSource(t *testing.T) {
+	quiet := floatsToPCM(sineSamples(1600, 16000, 440, 0.01))
+
+	tests := []struct {
+		name     string
+		config   InputDSPConfig
+		channels int
+		wantSame bool
+	}{
+		{"disabled passes through", InputDSPConfig{}, 1, true},
+		{"stereo passes through", InputDSPConfig{AGC: AGCConfig{Enabled: true}}, 2, true},
+		{"agc boosts quiet mic", InputDSPConfig{AGC: AGCConfig{Enabled: true}}, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := NewInputDSPSource(&stubSource{data: quiet}, tt.config, 16000, tt.channels)
+			var out []byte
+			for i := 0; i < 20; i++ {
+				var err error
+				out, err = src.Read(context.Background())
+				if err != nil {
+					t.Fatalf("Read() error = %v", err)
+				}
+				if len(out) != len(quiet) {
+					t.Fatalf("Read() len = %d, want %d", len(out), len(quiet))
+				}
+			}
+			same := string(out) == string(quiet)
+			if same != tt.wantSame {
+				t.Fatalf("output unchanged = %v, want %v", same, tt.wantSame)
+			}
+			if !tt.wantSame && pcmRMS(out) <= pcmRMS(quiet) {
+				t.Errorf("expected louder output, in=%.4f out=%.4f", pcmRMS(quiet), pcmRMS(out))
+			}
+		})
+	}
+}
+
+func TestNoiseSuppressorPreservesSpeechBurst(t *testing.T) {
+	cfg := DefaultNoiseSuppressionConfig()
+	cfg.Enabled = true
+	ns := newNoiseSuppressor(cfg, 16000)
+	rng := rand.New(rand.NewSource(2))
+
+	// 前 1 秒只有底噪，之后 300ms 为语音（用正弦代替）
+	tone := sineSamples(320*15, 16000, 440, 0.3)
+	var inRMS, outRMS float64
+	for i := 0; i < 65; i++ {
+		frame := make([]float64, 320)
+		if i >= 50 {
+			copy(frame, tone[(i-50)*320:])
+		}
+		for j := range frame {
+			frame[j] += rng.NormFloat64() * 0.005
+		}
+		if i >= 52 {
+			inRMS += rmsOf(frame)
+		}
+		ns.Process(frame)
+		if i >= 52 {
+			outRMS += rmsOf(frame)
+		}
+	}
+	if outRMS < inRMS*0.8 {
+		t.Errorf("speech RMS in=%.4f out=%.4f, expected speech to be preserved", inRMS/13, outRMS/13)
+	}
+}
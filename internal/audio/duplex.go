@@ -0,0 +1,267 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/gordonklaus/portaudio"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// AudioRenderer 由外部音频流回调驱动的渲染器（Mixer 以 ExternalStream 模式创建时实现）
+type AudioRenderer interface {
+	// Render 填充一帧非交错的输出缓冲
+	Render(out [][]float32)
+}
+
+// DuplexConfig 全双工单流配置
+type DuplexConfig struct {
+	SampleRate      int // 输入输出共用的采样率
+	InputChannels   int
+	OutputChannels  int
+	FramesPerBuffer int // 每次回调的帧数，建议为 AEC 帧长的整数倍
+	ReadFrames      int // Source().Read 每次返回的帧数，对应麦克风缓冲区大小
+	QueueSize       int // 输入缓冲的回调块数，消费过慢时丢弃最旧数据
+}
+
+// DefaultDuplexConfig 默认全双工配置
+func DefaultDuplexConfig() DuplexConfig {
+	return DuplexConfig{
+		SampleRate:      16000,
+		InputChannels:   1,
+		OutputChannels:  2,
+		FramesPerBuffer: 320,
+		ReadFrames:      3200,
+		QueueSize:       64,
+	}
+}
+
+// DuplexStream 单个 PortAudio 全双工流：同一个回调里渲染 Mixer 输出并采集麦克风输入
+// 避免分别打开输入、输出流在 macOS 蓝牙设备上的冲突，同时让回声参考与麦克风输入严格对齐
+type DuplexStream struct {
+	config    DuplexConfig
+	renderer  AudioRenderer
+	stream    *portaudio.Stream
+	input     chan []int16
+	reference ReferenceSink
+	source    *duplexSource
+
+	mu      sync.Mutex
+	started bool
+	dropped int64
+}
+
+// NewDuplexStream 打开默认输入、输出设备上的全双工流（调用方需先初始化 PortAudio）
+func NewDuplexStream(config DuplexConfig, renderer AudioRenderer) (*DuplexStream, error) {
+	config = normalizeDuplexConfig(config)
+	if renderer == nil {
+		return nil, errors.New("duplex stream requires a renderer")
+	}
+
+	d := newDuplexStream(config, renderer)
+	stream, err := portaudio.OpenDefaultStream(config.InputChannels, config.OutputChannels,
+		float64(config.SampleRate), config.FramesPerBuffer, d.callback)
+	if err != nil {
+		return nil, err
+	}
+	d.stream = stream
+	logging.Infof("DuplexStream: created (sampleRate=%d, in=%d, out=%d, framesPerBuffer=%d)",
+		config.SampleRate, config.InputChannels, config.OutputChannels, config.FramesPerBuffer)
+	return d, nil
+}
+
+func newDuplexStream(config DuplexConfig, renderer AudioRenderer) *DuplexStream {
+	input := make(chan []int16, config.QueueSize)
+	return &DuplexStream{
+		config:   config,
+		renderer: renderer,
+		input:    input,
+		source: &duplexSource{
+			input:      input,
+			readFrames: config.ReadFrames,
+			channels:   config.InputChannels,
+			closeCh:    make(chan struct{}),
+		},
+	}
+}
+
+func normalizeDuplexConfig(config DuplexConfig) DuplexConfig {
+	defaults := DefaultDuplexConfig()
+	if config.SampleRate <= 0 {
+		config.SampleRate = defaults.SampleRate
+	}
+	if config.InputChannels <= 0 {
+		config.InputChannels = defaults.InputChannels
+	}
+	// Mixer 回调按双声道写入
+	if config.OutputChannels < 2 {
+		config.OutputChannels = defaults.OutputChannels
+	}
+	if config.FramesPerBuffer <= 0 {
+		config.FramesPerBuffer = defaults.FramesPerBuffer
+	}
+	if config.ReadFrames <= 0 {
+		config.ReadFrames = defaults.ReadFrames
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = defaults.QueueSize
+	}
+	return config
+}
+
+// SetReferenceSink 设置回声参考接收方，回调中把实际播放的音频（下混为单声道）写入
+func (d *DuplexStream) SetReferenceSink(sink ReferenceSink) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.reference = sink
+}
+
+// SampleRate 流的实际采样率
+func (d *DuplexStream) SampleRate() int {
+	return d.config.SampleRate
+}
+
+// Channels 输入声道数
+func (d *DuplexStream) Channels() int {
+	return d.config.InputChannels
+}
+
+// Source 返回从全双工流读取麦克风输入的 AudioSource
+func (d *DuplexStream) Source() AudioSource {
+	return d.source
+}
+
+// Start 启动流，Mixer 的渲染与麦克风采集随之开始
+func (d *DuplexStream) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.started {
+		return nil
+	}
+	if err := d.stream.Start(); err != nil {
+		return err
+	}
+	d.started = true
+	logging.Infof("DuplexStream: started")
+	return nil
+}
+
+// Close 停止并关闭流，Source 的 Read 随后返回 io.EOF
+func (d *DuplexStream) Close() error {
+	d.source.Close()
+
+	d.mu.Lock()
+	started := d.started
+	d.started = false
+	d.mu.Unlock()
+
+	if started {
+		if err := d.stream.Stop(); err != nil {
+			logging.Errorf("DuplexStream: failed to stop stream: %v", err)
+		}
+	}
+	return d.stream.Close()
+}
+
+func (d *DuplexStream) callback(in []int16, out [][]float32) {
+	d.renderer.Render(out)
+
+	d.mu.Lock()
+	reference := d.reference
+	d.mu.Unlock()
+	if reference != nil {
+		if frame, ok := referenceFrame(out); ok {
+			reference.WriteReference(frame)
+		}
+	}
+
+	d.pushInput(append([]int16(nil), in...))
+}
+
+// pushInput 非阻塞入队，队列满时丢弃最旧的块，保证读到的始终是最新输入
+func (d *DuplexStream) pushInput(block []int16) {
+	for {
+		select {
+		case d.input <- block:
+			return
+		default:
+		}
+		select {
+		case <-d.input:
+			d.mu.Lock()
+			d.dropped++
+			dropped := d.dropped
+			d.mu.Unlock()
+			if dropped%100 == 1 {
+				logging.Warnf("DuplexStream: input queue full, dropped %d block(s)", dropped)
+			}
+		default:
+		}
+	}
+}
+
+// referenceFrame 把输出下混为单声道 16-bit PCM；整帧静音时返回 false，避免回声门控误判为正在播放
+func referenceFrame(out [][]float32) ([]byte, bool) {
+	if len(out) == 0 {
+		return nil, false
+	}
+	frames := len(out[0])
+	pcm := make([]int16, frames)
+	silent := true
+	for i := 0; i < frames; i++ {
+		var sum float32
+		for _, channel := range out {
+			sum += channel[i]
+		}
+		pcm[i] = floatToInt16(float64(sum / float32(len(out))))
+		if pcm[i] != 0 {
+			silent = false
+		}
+	}
+	if silent {
+		return nil, false
+	}
+	data := make([]byte, frames*2)
+	int16ToBytes(pcm, data)
+	return data, true
+}
+
+// duplexSource 从全双工流回调收集输入，按 readFrames 组装后返回
+type duplexSource struct {
+	input      chan []int16
+	readFrames int
+	channels   int
+	pending    []int16
+	closeCh    chan struct{}
+	closeOnce  sync.Once
+}
+
+func (s *duplexSource) Read(ctx context.Context) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	want := s.readFrames * s.channels
+	for len(s.pending) < want {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-s.closeCh:
+			return nil, io.EOF
+		case block := <-s.input:
+			s.pending = append(s.pending, block...)
+		}
+	}
+
+	data := make([]byte, want*2)
+	int16ToBytes(s.pending[:want], data)
+	s.pending = append(s.pending[:0], s.pending[want:]...)
+	return data, nil
+}
+
+// Close 只结束读取，流的生命周期由 DuplexStream 管理
+func (s *duplexSource) Close() error {
+	s.closeOnce.Do(func() { close(s.closeCh) })
+	return nil
+}
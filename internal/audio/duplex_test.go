@@ -0,0 +1,151 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// constRenderer 以固定值填充输出
+type constRenderer struct {
+	value float32
+}
+
+func (r *constRenderer) Render(out [][]float32) {
+	for _, channel := range out {
+		for i := range channel {
+			channel[i] = r.value
+		}
+	}
+}
+
+// captureSink 记录写入的参考信号
+type captureSink struct {
+	writes [][]byte
+}
+
+func (s *captureSink) WriteReference(p []byte) {
+	s.writes = append(s.writes, append([]byte(nil), p...))
+}
+
+func newTestDuplex(config DuplexConfig, renderer AudioRenderer) *DuplexStream {
+	return newDuplexStream(normalizeDuplexConfig(config), renderer)
+}
+
+func duplexBuffers(frames, channels int) [][]float32 {
+	out := make([][]float32, channels)
+	for i := range out {
+		out[i] = make([]float32, frames)
+	}
+	return out
+}
+
+func TestDuplexCallbackReference(t *testing.T) {
+	tests := []struct {
+		name       string
+		value      float32
+		wantWrites int
+	}{
+		{"playing writes reference", 0.5, 1},
+		{"silence skips reference", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDuplex(DuplexConfig{FramesPerBuffer: 160}, &constRenderer{value: tt.value})
+			sink := &captureSink{}
+			d.SetReferenceSink(sink)
+
+			out := duplexBuffers(160, 2)
+			d.callback(make([]int16, 160), out)
+
+			if out[0][0] != tt.value || out[1][159] != tt.value {
+				t.Fatalf("output not rendered: got %v/%v, want %v", out[0][0], out[1][159], tt.value)
+			}
+			if len(sink.writes) != tt.wantWrites {
+				t.Fatalf("reference writes = %d, want %d", len(sink.writes), tt.wantWrites)
+			}
+			if tt.wantWrites > 0 {
+				frame := sink.writes[0]
+				if len(frame) != 160*2 {
+					t.Fatalf("reference frame = %d bytes, want %d", len(frame), 160*2)
+				}
+				got := int16(frame[0]) | int16(frame[1])<<8
+				if got != floatToInt16(0.5) {
+					t.Fatalf("reference sample = %d, want %d", got, floatToInt16(0.5))
+				}
+			}
+		})
+	}
+}
+
+func TestDuplexSourceRead(t *testing.T) {
+	d := newTestDuplex(DuplexConfig{InputChannels: 1, ReadFrames: 400}, &constRenderer{})
+	out := duplexBuffers(160, 2)
+	in := make([]int16, 160)
+	for i := 0; i < 3; i++ {
+		for j := range in {
+			in[j] = int16(i*160 + j)
+		}
+		d.callback(in, out)
+	}
+
+	data, err := d.Source().Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(data) != 400*2 {
+		t.Fatalf("Read() = %d bytes, want %d", len(data), 400*2)
+	}
+	for i := 0; i < 400; i++ {
+		got := int16(data[2*i]) | int16(data[2*i+1])<<8
+		if got != int16(i) {
+			t.Fatalf("sample %d = %d, want %d", i, got, i)
+		}
+	}
+
+	// 剩余 80 帧不足一次读取，应阻塞直到 context 超时
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := d.Source().Read(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Read() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestDuplexDropsOldestInput(t *testing.T) {
+	d := newTestDuplex(DuplexConfig{ReadFrames: 1, QueueSize: 2}, &constRenderer{})
+	out := duplexBuffers(1, 2)
+	for i := 1; i <= 4; i++ {
+		d.callback([]int16{int16(i)}, out)
+	}
+
+	for _, want := range []int16{3, 4} {
+		data, err := d.Source().Read(context.Background())
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		if got := int16(data[0]) | int16(data[1])<<8; got != want {
+			t.Fatalf("sample = %d, want %d", got, want)
+		}
+	}
+}
+
+func TestDuplexSourceCloseUnblocksRead(t *testing.T) {
+	d := newTestDuplex(DuplexConfig{}, &constRenderer{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := d.Source().Read(context.Background())
+		done <- err
+	}()
+
+	d.Source().Close()
+	select {
+	case err := <-done:
+		if !errors.Is(err, io.EOF) {
+			t.Fatalf("Read() error = %v, want io.EOF", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read() did not return after Close")
+	}
+}
@@ -0,0 +1,117 @@
+package audio
+
+import "math"
+
+// echoGate holds the state of the "gate" echo control mode: attenuation, hangover counters
+// and the double-talk check. It is only used from EchoCancellingSource.Read.
+type echoGate struct {
+	gain           float64 // linear gain applied to gated frames
+	ratio          float64 // double-talk energy ratio, 0 disables the check
+	hangoverFrames int
+	gateHold       int // frames to keep gating after the reference stopped
+	passHold       int // frames to keep passing after near-end speech was detected
+}
+
+func newEchoGate(config EchoCancelConfig) *echoGate {
+	gate := &echoGate{}
+	if config.GateAttenuationDb > 0 {
+		gate.gain = math.Pow(10, -config.GateAttenuationDb/20)
+	}
+	if config.GateDoubleTalkRatio > 0 {
+		gate.ratio = config.GateDoubleTalkRatio
+	}
+	if config.GateHangoverMs > 0 && config.FrameMs > 0 {
+		gate.hangoverFrames = (config.GateHangoverMs + config.FrameMs - 1) / config.FrameMs
+	}
+	return gate
+}
+
+// holding reports whether the gate stays closed although the reference is no longer active.
+func (g *echoGate) holding() bool {
+	return g.gateHold > 0
+}
+
+func (g *echoGate) detectsDoubleTalk() bool {
+	return g.ratio > 0
+}
+
+// decide returns whether the near-end frame should be attenuated. far is the aligned
+// reference frame, nil when the double-talk check is disabled or the reference is inactive.
+func (g *echoGate) decide(near, far []byte, active bool) bool {
+	if !active {
+		g.passHold = 0
+		if g.gateHold > 0 {
+			g.gateHold--
+			return true
+		}
+		return false
+	}
+	g.gateHold = g.hangoverFrames
+	if far != nil && nearEndSpeech(near, far, g.ratio) {
+		g.passHold = g.hangoverFrames
+		return false
+	}
+	if g.passHold > 0 {
+		g.passHold--
+		return false
+	}
+	return true
+}
+
+// apply scales the frame in place by the gate gain when attenuate is set.
+func (g *echoGate) apply(frame []byte, attenuate bool) {
+	if !attenuate {
+		return
+	}
+	if g.gain == 0 {
+		clear(frame)
+		return
+	}
+	samples := bytesToInt16(frame)
+	for i, sample := range samples {
+		samples[i] = int16(float64(sample) * g.gain)
+	}
+	int16ToBytes(samples, frame)
+}
+
+// nearEndSpeech splits the near-end energy into the part explained by the reference (echo
+// estimate: least-squares projection onto the reference, best over small lags to tolerate
+// alignment error) and the residual, and reports whether the residual exceeds the echo
+// estimate by ratio (in amplitude). A silent reference frame (pause between sentences,
+// echo not arrived yet) cannot tell echo tail from speech, so the gate stays closed.
+func nearEndSpeech(near, far []byte, ratio float64) bool {
+	n := bytesToInt16(near)
+	f := bytesToInt16(far)
+	if len(f) < len(n) || energy(f) == 0 {
+		return false
+	}
+	nearEnergy := energy(n)
+	if nearEnergy == 0 {
+		return false
+	}
+	maxLag := len(n) / 2
+	var echo float64
+	for lag := -maxLag; lag <= maxLag; lag++ {
+		var cross, farEnergy float64
+		for i := range n {
+			j := i - lag
+			if j < 0 || j >= len(f) {
+				continue
+			}
+			cross += float64(n[i]) * float64(f[j])
+			farEnergy += float64(f[j]) * float64(f[j])
+		}
+		if farEnergy > 0 {
+			echo = math.Max(echo, cross*cross/farEnergy)
+		}
+	}
+	return nearEnergy-echo > ratio*ratio*echo
+}
+
+func energy(samples []int16) float64 {
+	var sum float64
+	for _, sample := range samples {
+		sum += float64(sample) * float64(sample)
+	}
+	return sum
+}
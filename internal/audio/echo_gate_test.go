@@ -0,0 +1,115 @@
+package audio
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// toneFrame 生成 16kHz 单声道 16-bit 正弦帧，delay 为整体延迟的样本数
+func toneFrame(samples int, freq, amplitude float64, delay int) []int16 {
+	out := make([]int16, samples)
+	for i := range out {
+		out[i] = int16(amplitude * math.Sin(2*math.Pi*freq*float64(i-delay)/16000))
+	}
+	return out
+}
+
+func mixFrames(frames ...[]int16) []byte {
+	sum := make([]int16, len(frames[0]))
+	for _, frame := range frames {
+		for i, sample := range frame {
+			sum[i] += sample
+		}
+	}
+	data := make([]byte, len(sum)*2)
+	int16ToBytes(sum, data)
+	return data
+}
+
+func TestNearEndSpeech(t *testing.T) {
+	far := mixFrames(toneFrame(160, 440, 8000, 0))
+	tests := []struct {
+		name string
+		near []byte
+		far  []byte
+		want bool
+	}{
+		{"echo only", mixFrames(toneFrame(160, 440, 3000, 3)), far, false},
+		{"quiet speech over echo", mixFrames(toneFrame(160, 440, 3000, 3), toneFrame(160, 1300, 1000, 0)), far, false},
+		{"loud speech over echo", mixFrames(toneFrame(160, 440, 3000, 3), toneFrame(160, 1300, 8000, 0)), far, true},
+		{"silent reference", mixFrames(toneFrame(160, 1300, 8000, 0)), make([]byte, 320), false},
+		{"silent near", make([]byte, 320), far, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nearEndSpeech(tt.near, tt.far, 1); got != tt.want {
+				t.Fatalf("nearEndSpeech() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEchoCancellingSource_GateAttenuationAndHangover(t *testing.T) {
+	frameBytes := FrameBytes(16000, 1, 10)
+	near := make([]int16, 3*frameBytes/2)
+	for i := range near {
+		near[i] = 1000
+	}
+	data := make([]byte, len(near)*2)
+	int16ToBytes(near, data)
+
+	source := &stubSource{data: data}
+	ref := &stubReference{active: true, frame: make([]byte, frameBytes)}
+	cfg := EchoCancelConfig{Enabled: true, Mode: "gate", FrameMs: 10, GateAttenuationDb: 20, GateHangoverMs: 20}
+	wrapped := NewEchoCancellingSource(source, cfg, ref, NewNoopEchoCanceller(), 16000, 1)
+
+	firstSamples := func(out []byte) []int16 {
+		samples := bytesToInt16(out)
+		return []int16{samples[0], samples[frameBytes/2], samples[frameBytes]}
+	}
+
+	// 参考活跃：整块衰减 20dB
+	out, _ := wrapped.Read(context.Background())
+	if got := firstSamples(out); got[0] != 100 || got[1] != 100 || got[2] != 100 {
+		t.Fatalf("active reference: frames = %v, want all 100", got)
+	}
+
+	// 参考停止：前 2 帧（20ms hangover）继续衰减，之后放行
+	ref.active = false
+	out, _ = wrapped.Read(context.Background())
+	if got := firstSamples(out); got[0] != 100 || got[1] != 100 || got[2] != 1000 {
+		t.Fatalf("hangover: frames = %v, want [100 100 1000]", got)
+	}
+	out, _ = wrapped.Read(context.Background())
+	if got := firstSamples(out); got[0] != 1000 {
+		t.Fatalf("after hangover: frames = %v, want passthrough", got)
+	}
+}
+
+func TestEchoCancellingSource_GateDoubleTalk(t *testing.T) {
+	echo := toneFrame(160, 440, 3000, 3)
+	ref := &stubReference{active: true, frame: mixFrames(toneFrame(160, 440, 8000, 0))}
+	cfg := EchoCancelConfig{Enabled: true, Mode: "gate", FrameMs: 10, GateDoubleTalkRatio: 1}
+
+	tests := []struct {
+		name  string
+		near  []byte
+		muted bool
+	}{
+		{"echo is muted", mixFrames(echo), true},
+		{"barge-in passes", mixFrames(echo, toneFrame(160, 1300, 8000, 0)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped := NewEchoCancellingSource(&stubSource{data: tt.near}, cfg, ref, NewNoopEchoCanceller(), 16000, 1)
+			out, err := wrapped.Read(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if muted := energy(bytesToInt16(out)) == 0; muted != tt.muted {
+				t.Fatalf("muted = %v, want %v", muted, tt.muted)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package audio
 
 import (
 	"context"
+	"time"
 
 	"github.com/liuscraft/orion-x/internal/asr"
 )
@@ -13,8 +14,41 @@ type AudioInPipe interface {
 	SendAudio(audio []byte) error
 	OnASRResult(handler func(text string, isFinal bool))
 	OnUserSpeakingDetected(handler func())
+	// OnASRUsage 设置 ASR 计费时长回调（秒），识别服务返回用量时调用
+	OnASRUsage(handler func(durationSec int))
+	// OnRecognizerStatus 设置识别服务可用性回调：连接断开时 available 为 false 并携带原因，
+	// 重连成功后 available 为 true
+	OnRecognizerStatus(handler func(available bool, err error))
 }
 
+// UtteranceAudioReporter 可选接口：ASR final 时连同该句的音频（上一句 final 之后送入的 PCM）一起回调，
+// 用于声纹识别等需要原始音频的处理
+type UtteranceAudioReporter interface {
+	OnASRFinalAudio(handler func(text string, pcm []byte))
+}
+
+// ASRResultDetailReporter 可选接口：回调完整的识别结果（词级时间戳、置信度），
+// 用于字幕、低置信度重问等需要识别细节的处理
+type ASRResultDetailReporter interface {
+	OnASRResultDetail(handler func(result asr.Result))
+}
+
+// MicMuter 可选接口：麦克风静音（隐私模式），静音期间的音频不发送给 ASR，也不做 VAD 检测
+type MicMuter interface {
+	SetMicMuted(muted bool)
+}
+
+// StreamPauser 可选接口：暂停/恢复向 ASR 推流，用于播报期间停止识别等需要交替开关识别的场景。
+// PauseStreaming 结束当前识别任务（剩余结果照常回调），之后的音频不发送给 ASR（VAD 与电平照常）；
+// ResumeStreaming 开始新的识别任务，识别器支持 asr.SessionRestarter 时复用，否则通过工厂重新创建
+type StreamPauser interface {
+	PauseStreaming(ctx context.Context) error
+	ResumeStreaming(ctx context.Context) error
+}
+
+// RecognizerFactory 创建新的识别器，用于连接断开后重连
+type RecognizerFactory func() (asr.Recognizer, error)
+
 // AudioSource 音频输入源接口
 type AudioSource interface {
 	Read(ctx context.Context) ([]byte, error)
@@ -29,6 +63,26 @@ type InPipeConfig struct {
 	VADThreshold float64
 	ASRModel     string
 	ASREndpoint  string
+	// ASRHeartbeat 开启服务端心跳，持续静音时不结束识别任务
+	ASRHeartbeat bool
+	// ASRKeepalive 超过该时长未发送音频时补发静音帧，<=0 表示关闭
+	ASRKeepalive time.Duration
+	// ASRLanguageHints 识别语言提示，为空时由服务自动识别
+	ASRLanguageHints []string
+	// ASRVocabularyID 热词表 ID，为空时不使用热词
+	ASRVocabularyID string
+
+	// ReconnectInitialBackoff ASR 重连的初始退避时间，之后每次失败翻倍
+	ReconnectInitialBackoff time.Duration
+	// ReconnectMaxBackoff ASR 重连退避的上限
+	ReconnectMaxBackoff time.Duration
+	// ReplayBufferMs 重连后补发的音频时长上限（毫秒），<=0 表示不补发
+	ReplayBufferMs int
+
+	// ASRFrameMs 发送给 ASR 的帧长（毫秒）：音频块切分或合并为该长度后发送，<=0 表示按音频源的块原样发送
+	ASRFrameMs int
+	// ASRMaxSendRate 发送速率上限（实时倍数），重连补发等突发音频按该速率发送，<=0 表示不限速
+	ASRMaxSendRate float64
 }
 
 // DefaultInPipeConfig 默认配置
@@ -39,6 +93,12 @@ func DefaultInPipeConfig() *InPipeConfig {
 		EnableVAD:    true,
 		VADThreshold: 0.5,
 		ASRModel:     "fun-asr-realtime",
+		ASRHeartbeat: true,
+		ASRKeepalive: 10 * time.Second,
+
+		ReconnectInitialBackoff: 500 * time.Millisecond,
+		ReconnectMaxBackoff:     30 * time.Second,
+		ReplayBufferMs:          3000,
 	}
 }
 
@@ -48,20 +108,19 @@ func NewInPipe(apiKey string, config *InPipeConfig) (AudioInPipe, error) {
 		config = DefaultInPipeConfig()
 	}
 
-	asrCfg := asr.Config{
-		APIKey:     apiKey,
-		Model:      config.ASRModel,
-		Endpoint:   config.ASREndpoint,
-		Format:     "pcm",
-		SampleRate: config.SampleRate,
-	}
-
-	recognizer, err := asr.NewDashScopeRecognizer(asrCfg)
+	factory := dashScopeRecognizerFactory(newASRConfig(apiKey, config))
+	recognizer, err := factory()
 	if err != nil {
 		return nil, err
 	}
 
-	return NewInPipeWithRecognizer(config, recognizer), nil
+	pipe := NewInPipeWithRecognizer(config, recognizer)
+
+	if impl, ok := pipe.(*inPipeImpl); ok {
+		impl.SetRecognizerFactory(factory)
+	}
+
+	return pipe, nil
 }
 
 // NewInPipeWithAudioSource 创建带有音频源的AudioInPipe
@@ -70,15 +129,8 @@ func NewInPipeWithAudioSource(apiKey string, config *InPipeConfig, source AudioS
 		config = DefaultInPipeConfig()
 	}
 
-	asrCfg := asr.Config{
-		APIKey:     apiKey,
-		Model:      config.ASRModel,
-		Endpoint:   config.ASREndpoint,
-		Format:     "pcm",
-		SampleRate: config.SampleRate,
-	}
-
-	recognizer, err := asr.NewDashScopeRecognizer(asrCfg)
+	factory := dashScopeRecognizerFactory(newASRConfig(apiKey, config))
+	recognizer, err := factory()
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +139,35 @@ func NewInPipeWithAudioSource(apiKey string, config *InPipeConfig, source AudioS
 
 	if impl, ok := pipe.(*inPipeImpl); ok {
 		impl.SetAudioSource(source)
+		impl.SetRecognizerFactory(factory)
 	}
 
 	return pipe, nil
 }
+
+// newASRConfig 根据 InPipe 配置生成识别器配置
+func newASRConfig(apiKey string, config *InPipeConfig) asr.Config {
+	asrCfg := asr.Config{
+		APIKey:     apiKey,
+		Model:      config.ASRModel,
+		Endpoint:   config.ASREndpoint,
+		Format:     "pcm",
+		SampleRate: config.SampleRate,
+
+		KeepaliveInterval: config.ASRKeepalive,
+		LanguageHints:     config.ASRLanguageHints,
+		VocabularyID:      config.ASRVocabularyID,
+	}
+	if config.ASRHeartbeat {
+		heartbeat := true
+		asrCfg.Heartbeat = &heartbeat
+	}
+	return asrCfg
+}
+
+// dashScopeRecognizerFactory 按同一配置创建 DashScope 识别器
+func dashScopeRecognizerFactory(cfg asr.Config) RecognizerFactory {
+	return func() (asr.Recognizer, error) {
+		return asr.NewDashScopeRecognizer(cfg)
+	}
+}
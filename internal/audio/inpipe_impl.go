@@ -34,22 +34,53 @@ func (s InPipeState) String() string {
 	}
 }
 
+// errRecognizerClosed 识别器连接在未调用 Finish 时结束
+var errRecognizerClosed = errors.New("recognizer connection closed")
+
+// recognizerWatcher 可选接口：识别器连接结束时关闭 Done 通道，Err 返回结束原因
+type recognizerWatcher interface {
+	Done() <-chan struct{}
+	Err() error
+}
+
 type inPipeImpl struct {
-	state       InPipeState
-	config      *InPipeConfig
-	recognizer  asr.Recognizer
-	asrHandler  func(text string, isFinal bool)
-	vadHandler  func()
-	audioSource AudioSource
-	ctx         context.Context
-	cancel      context.CancelFunc
-	wg          sync.WaitGroup
-	mu          sync.Mutex
+	state         InPipeState
+	config        *InPipeConfig
+	recognizer    asr.Recognizer
+	asrHandler    func(text string, isFinal bool)
+	detailHandler func(result asr.Result)
+	vadHandler    func()
+	usageHandler  func(durationSec int)
+	statusHandler func(available bool, err error)
+	audioSource   AudioSource
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+	mu            sync.Mutex
+
+	// 断线重连：newRecognizer 为空时不重连，保持原有行为
+	newRecognizer RecognizerFactory
+	reconnecting  bool
+	replay        *audioReplayBuffer // 最近一次 final 结果之后的音频（按发送的帧保存）
+
+	framer *asrFramer // 按 ASRFrameMs 分帧，为 nil 时原样发送
+	pacer  *sendPacer // 按 ASRMaxSendRate 限速，为 nil 时不限速
+
+	// finalAudioHandler 非空时缓存每句的音频，在 final 结果时一并回调
+	finalAudioHandler func(text string, pcm []byte)
+	utterance         *audioReplayBuffer
 
 	vadEnabled     bool
 	vadThreshold   float64
 	vadMinInterval time.Duration
 	lastVADTime    time.Time
+
+	level *levelMeter // 麦克风输入电平
+
+	muted bool // 麦克风静音：丢弃音频，不发送给 ASR
+
+	paused   bool // 暂停推流：识别任务已结束，音频不发送给 ASR
+	watchGen int  // 每次监听识别器递增，旧的监听协程据此忽略已结束的任务
 }
 
 func NewInPipeWithRecognizer(config *InPipeConfig, recognizer asr.Recognizer) AudioInPipe {
@@ -67,7 +98,57 @@ func NewInPipeWithRecognizer(config *InPipeConfig, recognizer asr.Recognizer) Au
 		vadEnabled:     config.EnableVAD,
 		vadThreshold:   vadThreshold,
 		vadMinInterval: 300 * time.Millisecond,
+		replay:         newAudioReplayBuffer(replayBufferBytes(config)),
+		framer:         newASRFramer(config.SampleRate, config.Channels, config.ASRFrameMs),
+		pacer:          newSendPacer(config.SampleRate, config.Channels, config.ASRMaxSendRate),
+		level:          newLevelMeter(config.SampleRate),
+	}
+}
+
+// replayBufferBytes 按采样率和声道数把 ReplayBufferMs 换算为字节数（16-bit PCM）
+func replayBufferBytes(config *InPipeConfig) int {
+	if config.ReplayBufferMs <= 0 {
+		return 0
+	}
+	channels := config.Channels
+	if channels <= 0 {
+		channels = 1
+	}
+	return config.SampleRate * channels * 2 * config.ReplayBufferMs / 1000
+}
+
+// maxUtteranceMs 每句缓存的音频上限，超长语句只保留最后这一段
+const maxUtteranceMs = 15000
+
+// SetRecognizerFactory 设置识别器工厂，设置后识别器断开时会自动重连
+func (p *inPipeImpl) SetRecognizerFactory(factory RecognizerFactory) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.newRecognizer = factory
+}
+
+// SetOnLevel 设置麦克风输入电平回调，统计的是送入 ASR 前的音频
+func (p *inPipeImpl) SetOnLevel(callback LevelCallback) {
+	p.level.SetOnLevel(callback)
+}
+
+// SetMicMuted 静音麦克风：之后的音频直接丢弃（电平照常统计），识别器连接保持，取消静音后立即恢复识别
+func (p *inPipeImpl) SetMicMuted(muted bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.muted == muted {
+		return
+	}
+	p.muted = muted
+	if muted {
+		// 静音前未说完的半句不再补发，也不计入下一句的音频
+		p.replay.Reset()
+		p.framer.reset()
+		if p.utterance != nil {
+			p.utterance.Reset()
+		}
 	}
+	logging.Infof("AudioInPipe: microphone muted=%v", muted)
 }
 
 func (p *inPipeImpl) SetAudioSource(source AudioSource) {
@@ -95,6 +176,11 @@ func (p *inPipeImpl) Start(ctx context.Context) error {
 	})
 
 	p.state = InPipeStateListening
+	p.reconnecting = false
+	p.paused = false
+	p.replay.Reset()
+	p.framer.reset()
+	p.watchRecognizerLocked(p.recognizer)
 
 	if p.audioSource != nil {
 		logging.Infof("AudioInPipe: starting audio source...")
@@ -165,7 +251,7 @@ func (p *inPipeImpl) SendAudio(audio []byte) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	if p.state == InPipeStateStopping {
+	if p.state == InPipeStateStopping || p.muted || p.paused {
 		return nil
 	}
 
@@ -177,11 +263,28 @@ func (p *inPipeImpl) SendAudio(audio []byte) error {
 		return logError("AudioInPipe: recognizer not initialized")
 	}
 
-	if err := p.recognizer.SendAudio(p.ctx, audio); err != nil {
-		if err == context.Canceled {
+	if p.utterance != nil {
+		p.utterance.Write(audio)
+	}
+	for _, frame := range p.framer.push(audio) {
+		p.replay.Write(frame)
+		if p.reconnecting {
+			// 重连期间只缓存，重连成功后补发
+			continue
+		}
+		if err := p.pacer.wait(p.ctx, len(frame)); err != nil {
 			return nil
 		}
-		return logError("AudioInPipe: send audio error: %v", err)
+		if err := p.recognizer.SendAudio(p.ctx, frame); err != nil {
+			if err == context.Canceled {
+				return nil
+			}
+			if p.newRecognizer != nil {
+				p.beginReconnectLocked(err)
+				continue
+			}
+			return logError("AudioInPipe: send audio error: %v", err)
+		}
 	}
 
 	return nil
@@ -193,17 +296,337 @@ func (p *inPipeImpl) OnASRResult(handler func(text string, isFinal bool)) {
 	p.asrHandler = handler
 }
 
+// OnASRResultDetail 设置完整识别结果回调，在 OnASRResult 之后调用
+func (p *inPipeImpl) OnASRResultDetail(handler func(result asr.Result)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.detailHandler = handler
+}
+
+// OnASRFinalAudio 设置带音频的 final 结果回调，设置后开始缓存每句的音频
+func (p *inPipeImpl) OnASRFinalAudio(handler func(text string, pcm []byte)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.finalAudioHandler = handler
+	if handler == nil {
+		p.utterance = nil
+		return
+	}
+	if p.utterance == nil {
+		channels := max(p.config.Channels, 1)
+		p.utterance = newAudioReplayBuffer(p.config.SampleRate * channels * 2 * maxUtteranceMs / 1000)
+	}
+}
+
 func (p *inPipeImpl) OnUserSpeakingDetected(handler func()) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 	p.vadHandler = handler
 }
 
+func (p *inPipeImpl) OnASRUsage(handler func(durationSec int)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.usageHandler = handler
+}
+
+func (p *inPipeImpl) OnRecognizerStatus(handler func(available bool, err error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.statusHandler = handler
+}
+
+// watchRecognizerLocked 识别器支持 Done 时监听连接结束，调用方需持有锁
+func (p *inPipeImpl) watchRecognizerLocked(recognizer asr.Recognizer) {
+	watcher, ok := recognizer.(recognizerWatcher)
+	if !ok || p.newRecognizer == nil {
+		return
+	}
+	ctx := p.ctx
+	done := watcher.Done()
+	p.watchGen++
+	gen := p.watchGen
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		select {
+		case <-ctx.Done():
+		case <-done:
+			cause := watcher.Err()
+			if cause == nil {
+				cause = errRecognizerClosed
+			}
+			p.mu.Lock()
+			if p.recognizer == recognizer && p.watchGen == gen {
+				p.beginReconnectLocked(cause)
+			}
+			p.mu.Unlock()
+		}
+	}()
+}
+
+// beginReconnectLocked 进入重连状态并启动重连协程，调用方需持有锁
+func (p *inPipeImpl) beginReconnectLocked(cause error) {
+	if p.reconnecting || p.paused || p.state != InPipeStateListening || p.newRecognizer == nil {
+		return
+	}
+	p.reconnecting = true
+	if errors.Is(cause, asr.ErrIdleTimeout) {
+		logging.Infof("AudioInPipe: ASR task finished due to idle, restarting")
+	} else {
+		logging.Warnf("AudioInPipe: recognizer unavailable, reconnecting: %v", cause)
+	}
+
+	p.wg.Add(1)
+	go p.reconnectLoop(p.ctx, p.recognizer, cause)
+}
+
+// reconnectLoop 按指数退避重建识别器，成功后补发缓存的音频并切换
+// 空闲超时属于正常的任务结束：立即重启且不上报不可用，保持监听连续
+func (p *inPipeImpl) reconnectLoop(ctx context.Context, old asr.Recognizer, cause error) {
+	defer p.wg.Done()
+
+	idle := errors.Is(cause, asr.ErrIdleTimeout)
+	if restarter, ok := old.(asr.SessionRestarter); ok && idle {
+		// 连接仍在，优先在原识别器上开始新任务
+		err := restarter.Restart(ctx)
+		if err == nil {
+			err = p.swapRecognizer(old)
+		}
+		if err == nil || errors.Is(err, context.Canceled) || ctx.Err() != nil {
+			return
+		}
+		logging.WarnfCtx(ctx, "AudioInPipe: restart ASR task failed, recreating recognizer: %v", err)
+	}
+	if old != nil {
+		_ = old.Close()
+	}
+	if !idle {
+		p.notifyStatus(false, cause)
+	}
+
+	backoff := p.config.ReconnectInitialBackoff
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	maxBackoff := p.config.ReconnectMaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = 30 * time.Second
+	}
+	if maxBackoff < backoff {
+		maxBackoff = backoff
+	}
+
+	for attempt := 1; ; attempt++ {
+		wait := backoff
+		if idle && attempt == 1 {
+			wait = 0
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		recognizer, err := p.connectRecognizer(ctx)
+		if err == nil {
+			err = p.swapRecognizer(recognizer)
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+		}
+		if err == nil {
+			logging.InfofCtx(ctx, "AudioInPipe: recognizer reconnected after %d attempt(s)", attempt)
+			if !idle {
+				p.notifyStatus(true, nil)
+			}
+			return
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		if idle {
+			// 空闲重启失败说明服务确实不可用，按普通断线处理
+			idle = false
+			p.notifyStatus(false, err)
+		}
+
+		logging.WarnfCtx(ctx, "AudioInPipe: reconnect attempt %d failed, retry in %v: %v", attempt, backoff, err)
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// connectRecognizer 创建并启动新的识别器
+func (p *inPipeImpl) connectRecognizer(ctx context.Context) (asr.Recognizer, error) {
+	p.mu.Lock()
+	factory := p.newRecognizer
+	p.mu.Unlock()
+
+	recognizer, err := factory()
+	if err != nil {
+		return nil, err
+	}
+	recognizer.OnResult(func(result asr.Result) {
+		p.handleASRResult(result)
+	})
+	if err := recognizer.Start(ctx); err != nil {
+		_ = recognizer.Close()
+		return nil, err
+	}
+	return recognizer, nil
+}
+
+// swapRecognizer 补发缓存音频后切换到新识别器
+// 补发期间持有锁，保证新音频排在缓存音频之后
+func (p *inPipeImpl) swapRecognizer(recognizer asr.Recognizer) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.state != InPipeStateListening {
+		_ = recognizer.Close()
+		return context.Canceled
+	}
+
+	chunks := p.replay.Chunks()
+	for _, chunk := range chunks {
+		if err := p.pacer.wait(p.ctx, len(chunk)); err != nil {
+			_ = recognizer.Close()
+			return context.Canceled
+		}
+		if err := recognizer.SendAudio(p.ctx, chunk); err != nil {
+			_ = recognizer.Close()
+			return fmt.Errorf("replay audio: %w", err)
+		}
+	}
+	if len(chunks) > 0 {
+		logging.Infof("AudioInPipe: replayed %d bytes of buffered audio", p.replay.Size())
+	}
+
+	p.recognizer = recognizer
+	p.reconnecting = false
+	p.watchRecognizerLocked(recognizer)
+	return nil
+}
+
+// PauseStreaming 结束当前识别任务并暂停推流，剩余识别结果照常回调；已暂停时直接返回
+func (p *inPipeImpl) PauseStreaming(ctx context.Context) error {
+	p.mu.Lock()
+	if p.state != InPipeStateListening {
+		p.mu.Unlock()
+		return logError("AudioInPipe: cannot pause streaming, current state: %s", p.state)
+	}
+	if p.paused {
+		p.mu.Unlock()
+		return nil
+	}
+	p.paused = true
+	recognizer := p.recognizer
+	reconnecting := p.reconnecting
+	// 暂停前未识别完的音频不再补发
+	p.replay.Reset()
+	p.framer.reset()
+	p.mu.Unlock()
+
+	logging.Infof("AudioInPipe: streaming paused")
+	var err error
+	if !reconnecting && recognizer != nil {
+		err = recognizer.Finish(ctx)
+	}
+
+	p.mu.Lock()
+	if p.utterance != nil {
+		p.utterance.Reset()
+	}
+	p.mu.Unlock()
+	if err != nil {
+		return logError("AudioInPipe: finish ASR on pause: %v", err)
+	}
+	return nil
+}
+
+// ResumeStreaming 开始新的识别任务并恢复推流；失败且设置了工厂时转入重连，由重连协程恢复
+func (p *inPipeImpl) ResumeStreaming(ctx context.Context) error {
+	p.mu.Lock()
+	if p.state != InPipeStateListening {
+		p.mu.Unlock()
+		return logError("AudioInPipe: cannot resume streaming, current state: %s", p.state)
+	}
+	if !p.paused {
+		p.mu.Unlock()
+		return nil
+	}
+	if p.reconnecting {
+		// 暂停前已在重连，重连成功后自然恢复
+		p.paused = false
+		p.mu.Unlock()
+		return nil
+	}
+	old := p.recognizer
+	factory := p.newRecognizer
+	p.mu.Unlock()
+
+	recognizer, err := p.restartRecognizer(ctx, old, factory)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.state != InPipeStateListening {
+		if recognizer != nil {
+			_ = recognizer.Close()
+		}
+		return context.Canceled
+	}
+	p.paused = false
+	if err != nil {
+		if factory != nil {
+			p.beginReconnectLocked(err)
+			return nil
+		}
+		return logError("AudioInPipe: resume streaming: %v", err)
+	}
+	p.recognizer = recognizer
+	p.watchRecognizerLocked(recognizer)
+	logging.Infof("AudioInPipe: streaming resumed")
+	return nil
+}
+
+// restartRecognizer 在原识别器上开始新任务，不支持时用工厂重新创建并关闭原识别器
+func (p *inPipeImpl) restartRecognizer(ctx context.Context, old asr.Recognizer, factory RecognizerFactory) (asr.Recognizer, error) {
+	if restarter, ok := old.(asr.SessionRestarter); ok {
+		if err := restarter.Restart(ctx); err != nil {
+			return nil, err
+		}
+		return old, nil
+	}
+	if factory == nil {
+		return nil, errors.New("recognizer does not support restart and no factory is set")
+	}
+	recognizer, err := p.connectRecognizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if old != nil {
+		_ = old.Close()
+	}
+	return recognizer, nil
+}
+
+func (p *inPipeImpl) notifyStatus(available bool, err error) {
+	p.mu.Lock()
+	handler := p.statusHandler
+	p.mu.Unlock()
+	if handler != nil {
+		handler(available, err)
+	}
+}
+
 func (p *inPipeImpl) readAudioFromSource(ctx context.Context) {
 	defer p.wg.Done()
 
-	logging.Infof("AudioInPipe: audio reader goroutine started")
-	defer logging.Infof("AudioInPipe: audio reader goroutine stopped")
+	logging.InfofCtx(ctx, "AudioInPipe: audio reader goroutine started")
+	defer logging.InfofCtx(ctx, "AudioInPipe: audio reader goroutine stopped")
 
 	consecutiveErrors := 0
 	const maxConsecutiveErrors = 5
@@ -225,11 +648,11 @@ func (p *inPipeImpl) readAudioFromSource(ctx context.Context) {
 			// These can happen during startup or under high load
 			consecutiveErrors++
 			if consecutiveErrors >= maxConsecutiveErrors {
-				logging.Errorf("AudioInPipe: too many consecutive errors (%d), stopping: %v", consecutiveErrors, err)
+				logging.ErrorfCtx(ctx, "AudioInPipe: too many consecutive errors (%d), stopping: %v", consecutiveErrors, err)
 				return
 			}
 
-			logging.Warnf("AudioInPipe: transient error reading from audio source (attempt %d/%d): %v",
+			logging.WarnfCtx(ctx, "AudioInPipe: transient error reading from audio source (attempt %d/%d): %v",
 				consecutiveErrors, maxConsecutiveErrors, err)
 
 			// Brief pause before retry to avoid tight error loop
@@ -244,6 +667,7 @@ func (p *inPipeImpl) readAudioFromSource(ctx context.Context) {
 		// Reset error counter on successful read
 		consecutiveErrors = 0
 
+		p.level.WritePCM16(audio, p.config.Channels)
 		p.handleVAD(audio)
 
 		select {
@@ -256,7 +680,7 @@ func (p *inPipeImpl) readAudioFromSource(ctx context.Context) {
 			if err == context.Canceled {
 				return
 			}
-			logging.Errorf("AudioInPipe: error sending audio to ASR: %v", err)
+			logging.ErrorfCtx(ctx, "AudioInPipe: error sending audio to ASR: %v", err)
 		}
 	}
 }
@@ -264,11 +688,37 @@ func (p *inPipeImpl) readAudioFromSource(ctx context.Context) {
 func (p *inPipeImpl) handleASRResult(result asr.Result) {
 	p.mu.Lock()
 	handler := p.asrHandler
+	detailHandler := p.detailHandler
+	usageHandler := p.usageHandler
+	var finalAudioHandler func(text string, pcm []byte)
+	var utterance []byte
+	if result.IsFinal {
+		// 已识别完成的音频无需在重连后补发
+		p.replay.Reset()
+		if p.finalAudioHandler != nil && p.utterance != nil {
+			finalAudioHandler = p.finalAudioHandler
+			utterance = make([]byte, 0, p.utterance.Size())
+			for _, chunk := range p.utterance.Chunks() {
+				utterance = append(utterance, chunk...)
+			}
+			p.utterance.Reset()
+		}
+	}
 	p.mu.Unlock()
 
+	// 用量先于识别结果上报，保证编排器开始新一轮时已拿到本轮的 ASR 用量
+	if usageHandler != nil && result.UsageDuration != nil {
+		usageHandler(*result.UsageDuration)
+	}
 	if handler != nil {
 		handler(result.Text, result.IsFinal)
 	}
+	if detailHandler != nil {
+		detailHandler(result)
+	}
+	if finalAudioHandler != nil {
+		finalAudioHandler(result.Text, utterance)
+	}
 }
 
 func (p *inPipeImpl) handleVAD(audio []byte) {
@@ -276,6 +726,12 @@ func (p *inPipeImpl) handleVAD(audio []byte) {
 		logging.Infof("AudioInPipe: VAD disabled")
 		return
 	}
+	p.mu.Lock()
+	muted := p.muted
+	p.mu.Unlock()
+	if muted {
+		return
+	}
 
 	isSpeech := p.detectSpeech(audio)
 	if !isSpeech {
@@ -1,8 +1,11 @@
 package audio
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -209,6 +212,40 @@ func TestInPipeSendAudio(t *testing.T) {
 	pipe.Stop()
 }
 
+func TestInPipeMicMuted(t *testing.T) {
+	config := DefaultInPipeConfig()
+	config.VADThreshold = 0.2
+	mock := &mockRecognizer{}
+	pipe := NewInPipeWithRecognizer(config, mock)
+	if err := pipe.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer pipe.Stop()
+
+	vadCalls := 0
+	pipe.OnUserSpeakingDetected(func() { vadCalls++ })
+	impl := pipe.(*inPipeImpl)
+	impl.SetMicMuted(true)
+
+	voice := makePCM(12000, 160)
+	impl.handleVAD(voice)
+	if err := pipe.SendAudio(voice); err != nil {
+		t.Fatalf("SendAudio failed: %v", err)
+	}
+	if mock.sendCalled || vadCalls != 0 {
+		t.Fatalf("muted pipe sent audio=%v, vad calls=%d", mock.sendCalled, vadCalls)
+	}
+
+	impl.SetMicMuted(false)
+	impl.handleVAD(voice)
+	if err := pipe.SendAudio(voice); err != nil {
+		t.Fatalf("SendAudio failed: %v", err)
+	}
+	if !mock.sendCalled || vadCalls != 1 {
+		t.Fatalf("unmuted pipe sent audio=%v, vad calls=%d", mock.sendCalled, vadCalls)
+	}
+}
+
 func TestInPipeOnASRResult(t *testing.T) {
 	config := DefaultInPipeConfig()
 	mock := &mockRecognizer{}
@@ -242,6 +279,66 @@ func TestInPipeOnASRResult(t *testing.T) {
 	pipe.Stop()
 }
 
+func TestInPipeOnASRResultDetail(t *testing.T) {
+	mock := &mockRecognizer{}
+	pipe := NewInPipeWithRecognizer(DefaultInPipeConfig(), mock)
+
+	var got asr.Result
+	pipe.(ASRResultDetailReporter).OnASRResultDetail(func(result asr.Result) {
+		got = result
+	})
+	if err := pipe.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer pipe.Stop()
+
+	confidence := 0.42
+	mock.SendResult(asr.Result{
+		Text:       "你好",
+		IsFinal:    true,
+		Confidence: &confidence,
+		Words:      []asr.Word{{Text: "你好", BeginTimeMs: 100, EndTimeMs: 400}},
+	})
+	if got.Text != "你好" || got.Confidence == nil || *got.Confidence != confidence || len(got.Words) != 1 {
+		t.Errorf("detail result = %+v", got)
+	}
+}
+
+func TestInPipeOnASRFinalAudio(t *testing.T) {
+	mock := &mockRecognizer{}
+	pipe := NewInPipeWithRecognizer(DefaultInPipeConfig(), mock)
+
+	var gotText string
+	var gotAudio []byte
+	pipe.(UtteranceAudioReporter).OnASRFinalAudio(func(text string, pcm []byte) {
+		gotText = text
+		gotAudio = pcm
+	})
+
+	if err := pipe.Start(context.Background()); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	defer pipe.Stop()
+
+	pipe.SendAudio([]byte{1, 2})
+	pipe.SendAudio([]byte{3, 4})
+	mock.SendResult(asr.Result{Text: "你好", IsFinal: false})
+	if gotAudio != nil {
+		t.Fatal("partial result should not report audio")
+	}
+	mock.SendResult(asr.Result{Text: "你好呀", IsFinal: true})
+	if gotText != "你好呀" || !bytes.Equal(gotAudio, []byte{1, 2, 3, 4}) {
+		t.Errorf("got text=%q audio=%v, want 你好呀 [1 2 3 4]", gotText, gotAudio)
+	}
+
+	// 下一句只包含上一句 final 之后的音频
+	pipe.SendAudio([]byte{5, 6})
+	mock.SendResult(asr.Result{Text: "再见", IsFinal: true})
+	if !bytes.Equal(gotAudio, []byte{5, 6}) {
+		t.Errorf("second utterance audio = %v, want [5 6]", gotAudio)
+	}
+}
+
 func TestInPipeStopWhenIdle(t *testing.T) {
 	config := DefaultInPipeConfig()
 	mock := &mockRecognizer{}
@@ -407,3 +504,480 @@ func makePCM(sample int16, count int) []byte {
 	}
 	return buf
 }
+
+// flakyRecognizer 可控制发送失败和连接断开的识别器
+type flakyRecognizer struct {
+	mu       sync.Mutex
+	sendErr  error
+	sent     [][]byte
+	closed   bool
+	onResult func(asr.Result)
+	done     chan struct{}
+	doneOnce sync.Once
+	doneErr  error
+}
+
+func newFlakyRecognizer() *flakyRecognizer {
+	return &flakyRecognizer{done: make(chan struct{})}
+}
+
+func (r *flakyRecognizer) Start(ctx context.Context) error { return nil }
+
+func (r *flakyRecognizer) SendAudio(ctx context.Context, data []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.sendErr != nil {
+		return r.sendErr
+	}
+	r.sent = append(r.sent, append([]byte(nil), data...))
+	return nil
+}
+
+func (r *flakyRecognizer) Finish(ctx context.Context) error { return nil }
+
+func (r *flakyRecognizer) Close() error {
+	r.mu.Lock()
+	r.closed = true
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *flakyRecognizer) OnResult(handler func(asr.Result)) {
+	r.mu.Lock()
+	r.onResult = handler
+	r.mu.Unlock()
+}
+
+func (r *flakyRecognizer) Done() <-chan struct{} { return r.done }
+
+func (r *flakyRecognizer) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.doneErr
+}
+
+func (r *flakyRecognizer) drop() { r.dropWith(nil) }
+
+func (r *flakyRecognizer) dropWith(err error) {
+	r.mu.Lock()
+	r.doneErr = err
+	r.mu.Unlock()
+	r.doneOnce.Do(func() { close(r.done) })
+}
+
+func (r *flakyRecognizer) setSendErr(err error) {
+	r.mu.Lock()
+	r.sendErr = err
+	r.mu.Unlock()
+}
+
+func (r *flakyRecognizer) emit(result asr.Result) {
+	r.mu.Lock()
+	handler := r.onResult
+	r.mu.Unlock()
+	handler(result)
+}
+
+func (r *flakyRecognizer) getSent() [][]byte {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]byte(nil), r.sent...)
+}
+
+func (r *flakyRecognizer) isClosed() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.closed
+}
+
+type recognizerStatus struct {
+	available bool
+	err       error
+}
+
+// newReconnectTestPipe 创建带识别器工厂的 InPipe，工厂依次返回 next 中的识别器
+func newReconnectTestPipe(t *testing.T, first *flakyRecognizer, next ...*flakyRecognizer) (*inPipeImpl, chan recognizerStatus) {
+	t.Helper()
+	cfg := DefaultInPipeConfig()
+	cfg.EnableVAD = false
+	cfg.ReconnectInitialBackoff = 5 * time.Millisecond
+	cfg.ReconnectMaxBackoff = 20 * time.Millisecond
+
+	pipe := NewInPipeWithRecognizer(cfg, first).(*inPipeImpl)
+	var mu sync.Mutex
+	pipe.SetRecognizerFactory(func() (asr.Recognizer, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(next) == 0 {
+			return nil, errors.New("no recognizer available")
+		}
+		r := next[0]
+		next = next[1:]
+		return r, nil
+	})
+
+	statusCh := make(chan recognizerStatus, 8)
+	pipe.OnRecognizerStatus(func(available bool, err error) {
+		statusCh <- recognizerStatus{available: available, err: err}
+	})
+	if err := pipe.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = pipe.Stop() })
+	return pipe, statusCh
+}
+
+func waitStatus(t *testing.T, ch chan recognizerStatus) recognizerStatus {
+	t.Helper()
+	select {
+	case status := <-ch:
+		return status
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for recognizer status")
+		return recognizerStatus{}
+	}
+}
+
+func TestInPipeReconnectReplaysBufferedAudio(t *testing.T) {
+	first := newFlakyRecognizer()
+	second := newFlakyRecognizer()
+	pipe, statusCh := newReconnectTestPipe(t, first, second)
+
+	// 已识别完成的音频不应补发
+	if err := pipe.SendAudio([]byte{1, 1}); err != nil {
+		t.Fatalf("SendAudio() error = %v", err)
+	}
+	first.emit(asr.Result{Text: "你好", IsFinal: true})
+
+	if err := pipe.SendAudio([]byte{2, 2}); err != nil {
+		t.Fatalf("SendAudio() error = %v", err)
+	}
+	first.setSendErr(errors.New("websocket: close 1006"))
+	if err := pipe.SendAudio([]byte{3, 3}); err != nil {
+		t.Fatalf("SendAudio() during outage should not fail, got %v", err)
+	}
+
+	status := waitStatus(t, statusCh)
+	if status.available || status.err == nil {
+		t.Fatalf("expected unavailable status with error, got %+v", status)
+	}
+	status = waitStatus(t, statusCh)
+	if !status.available {
+		t.Fatalf("expected available status after reconnect, got %+v", status)
+	}
+	if !first.isClosed() {
+		t.Error("expected old recognizer to be closed")
+	}
+
+	want := [][]byte{{2, 2}, {3, 3}}
+	got := second.getSent()
+	if len(got) != len(want) {
+		t.Fatalf("replayed %d chunks, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Errorf("chunk %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	if err := pipe.SendAudio([]byte{4, 4}); err != nil {
+		t.Fatalf("SendAudio() after reconnect error = %v", err)
+	}
+	if got := second.getSent(); len(got) != 3 {
+		t.Errorf("expected new audio to reach new recognizer, got %v", got)
+	}
+}
+
+func TestInPipeASRFrameMs(t *testing.T) {
+	cfg := DefaultInPipeConfig()
+	cfg.EnableVAD = false
+	cfg.ASRFrameMs = 100
+	recognizer := newFlakyRecognizer()
+	pipe := NewInPipeWithRecognizer(cfg, recognizer).(*inPipeImpl)
+	if err := pipe.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer pipe.Stop()
+
+	audio := make([]byte, 6400)
+	for i := range audio {
+		audio[i] = byte(i / 100)
+	}
+	// 150ms 的块发出一帧，剩余 50ms 与下一块合并
+	pipe.SendAudio(audio[:4800])
+	if got := recognizer.getSent(); len(got) != 1 || !bytes.Equal(got[0], audio[:3200]) {
+		t.Fatalf("after 150ms sent %d frames, want first 100ms frame", len(got))
+	}
+	pipe.SendAudio(audio[4800:])
+	if got := recognizer.getSent(); len(got) != 2 || !bytes.Equal(got[1], audio[3200:]) {
+		t.Fatalf("after 200ms sent %d frames, want second frame with carried remainder", len(got))
+	}
+
+	// 静音丢弃不足一帧的尾部
+	pipe.SendAudio(audio[:1600])
+	pipe.SetMicMuted(true)
+	pipe.SetMicMuted(false)
+	pipe.SendAudio(audio[:3200])
+	if got := recognizer.getSent(); len(got) != 3 || !bytes.Equal(got[2], audio[:3200]) {
+		t.Fatalf("after mute sent %d frames, want remainder dropped", len(got))
+	}
+}
+
+func TestInPipeReconnectOnConnectionDrop(t *testing.T) {
+	first := newFlakyRecognizer()
+	second := newFlakyRecognizer()
+	pipe, statusCh := newReconnectTestPipe(t, first, second)
+
+	first.drop()
+
+	if status := waitStatus(t, statusCh); status.available || !errors.Is(status.err, errRecognizerClosed) {
+		t.Fatalf("expected unavailable status, got %+v", status)
+	}
+	if status := waitStatus(t, statusCh); !status.available {
+		t.Fatalf("expected available status, got %+v", status)
+	}
+
+	var results []string
+	var mu sync.Mutex
+	pipe.OnASRResult(func(text string, isFinal bool) {
+		mu.Lock()
+		results = append(results, text)
+		mu.Unlock()
+	})
+	second.emit(asr.Result{Text: "重连后", IsFinal: true})
+	mu.Lock()
+	defer mu.Unlock()
+	if len(results) != 1 || results[0] != "重连后" {
+		t.Errorf("expected results from new recognizer, got %v", results)
+	}
+}
+
+func TestInPipeRestartsQuietlyAfterIdleTimeout(t *testing.T) {
+	first := newFlakyRecognizer()
+	second := newFlakyRecognizer()
+	pipe, statusCh := newReconnectTestPipe(t, first, second)
+
+	first.dropWith(asr.ErrIdleTimeout)
+
+	deadline := time.After(2 * time.Second)
+	for {
+		pipe.mu.Lock()
+		swapped := pipe.recognizer == asr.Recognizer(second)
+		pipe.mu.Unlock()
+		if swapped {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timeout waiting for idle restart")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	select {
+	case status := <-statusCh:
+		t.Fatalf("idle restart should not report status, got %+v", status)
+	default:
+	}
+
+	if err := pipe.SendAudio([]byte{5, 5}); err != nil {
+		t.Fatalf("SendAudio() error = %v", err)
+	}
+	if got := second.getSent(); len(got) != 1 {
+		t.Errorf("expected audio to reach restarted recognizer, got %v", got)
+	}
+}
+
+func TestInPipeStopDuringReconnect(t *testing.T) {
+	first := newFlakyRecognizer()
+	pipe, statusCh := newReconnectTestPipe(t, first) // 工厂始终失败
+
+	first.drop()
+	waitStatus(t, statusCh)
+
+	done := make(chan struct{})
+	go func() {
+		_ = pipe.Stop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop() blocked while reconnecting")
+	}
+	if pipe.GetState() != InPipeStateIdle {
+		t.Errorf("expected Idle after Stop, got %s", pipe.GetState())
+	}
+}
+
+// restartableRecognizer 支持 asr.SessionRestarter 的 flakyRecognizer：Finish 结束当前任务，Restart 开始新任务
+type restartableRecognizer struct {
+	*flakyRecognizer
+	finishes int
+	restarts int
+}
+
+func newRestartableRecognizer() *restartableRecognizer {
+	return &restartableRecognizer{flakyRecognizer: newFlakyRecognizer()}
+}
+
+func (r *restartableRecognizer) Finish(ctx context.Context) error {
+	r.mu.Lock()
+	r.finishes++
+	r.mu.Unlock()
+	r.drop()
+	return nil
+}
+
+func (r *restartableRecognizer) Restart(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.restarts++
+	r.done = make(chan struct{})
+	r.doneOnce = sync.Once{}
+	r.doneErr = nil
+	return nil
+}
+
+func (r *restartableRecognizer) Done() <-chan struct{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.done
+}
+
+func (r *restartableRecognizer) counts() (finishes, restarts int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.finishes, r.restarts
+}
+
+func TestInPipePauseResumeRestartsSession(t *testing.T) {
+	cfg := DefaultInPipeConfig()
+	cfg.EnableVAD = false
+	recognizer := newRestartableRecognizer()
+	pipe := NewInPipeWithRecognizer(cfg, recognizer).(*inPipeImpl)
+	pipe.SetRecognizerFactory(func() (asr.Recognizer, error) {
+		return nil, errors.New("factory should not be used")
+	})
+	statusCh := make(chan recognizerStatus, 8)
+	pipe.OnRecognizerStatus(func(available bool, err error) {
+		statusCh <- recognizerStatus{available: available, err: err}
+	})
+	if err := pipe.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer pipe.Stop()
+
+	ctx := context.Background()
+	for round := 0; round < 3; round++ {
+		if err := pipe.SendAudio([]byte{byte(round), 1}); err != nil {
+			t.Fatalf("SendAudio() error = %v", err)
+		}
+		if err := pipe.PauseStreaming(ctx); err != nil {
+			t.Fatalf("PauseStreaming() error = %v", err)
+		}
+		// 暂停期间的音频直接丢弃
+		if err := pipe.SendAudio([]byte{byte(round), 2}); err != nil {
+			t.Fatalf("SendAudio() while paused error = %v", err)
+		}
+		if err := pipe.ResumeStreaming(ctx); err != nil {
+			t.Fatalf("ResumeStreaming() error = %v", err)
+		}
+	}
+
+	finishes, restarts := recognizer.counts()
+	if finishes != 3 || restarts != 3 {
+		t.Errorf("finishes = %d, restarts = %d, want 3 and 3", finishes, restarts)
+	}
+	want := [][]byte{{0, 1}, {1, 1}, {2, 1}}
+	if got := recognizer.getSent(); !reflect.DeepEqual(got, want) {
+		t.Errorf("sent = %v, want %v", got, want)
+	}
+	if recognizer.isClosed() {
+		t.Error("recognizer should be reused, not closed")
+	}
+
+	// Finish 结束任务不应触发重连
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case status := <-statusCh:
+		t.Fatalf("pause should not report status, got %+v", status)
+	default:
+	}
+}
+
+func TestInPipeResumeRecreatesRecognizer(t *testing.T) {
+	first := newFlakyRecognizer()
+	second := newFlakyRecognizer()
+	pipe, statusCh := newReconnectTestPipe(t, first, second)
+
+	ctx := context.Background()
+	if err := pipe.PauseStreaming(ctx); err != nil {
+		t.Fatalf("PauseStreaming() error = %v", err)
+	}
+	// 任务结束后连接随之关闭，暂停期间不重连
+	first.drop()
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case status := <-statusCh:
+		t.Fatalf("paused pipe should not reconnect, got %+v", status)
+	default:
+	}
+
+	if err := pipe.ResumeStreaming(ctx); err != nil {
+		t.Fatalf("ResumeStreaming() error = %v", err)
+	}
+	if !first.isClosed() {
+		t.Error("expected old recognizer to be closed")
+	}
+	if err := pipe.SendAudio([]byte{7, 7}); err != nil {
+		t.Fatalf("SendAudio() error = %v", err)
+	}
+	if got := second.getSent(); len(got) != 1 {
+		t.Errorf("expected audio to reach new recognizer, got %v", got)
+	}
+}
+
+func TestInPipeIdleTimeoutRestartsSameRecognizer(t *testing.T) {
+	first := newRestartableRecognizer()
+	cfg := DefaultInPipeConfig()
+	cfg.EnableVAD = false
+	pipe := NewInPipeWithRecognizer(cfg, first).(*inPipeImpl)
+	pipe.SetRecognizerFactory(func() (asr.Recognizer, error) {
+		return nil, errors.New("factory should not be used")
+	})
+	if err := pipe.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer pipe.Stop()
+
+	first.dropWith(asr.ErrIdleTimeout)
+
+	deadline := time.After(2 * time.Second)
+	for {
+		if _, restarts := first.counts(); restarts == 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatal("timeout waiting for idle restart")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	// 等待切换完成后再发送
+	for {
+		pipe.mu.Lock()
+		reconnecting := pipe.reconnecting
+		pipe.mu.Unlock()
+		if !reconnecting {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := pipe.SendAudio([]byte{5, 5}); err != nil {
+		t.Fatalf("SendAudio() error = %v", err)
+	}
+	if got := first.getSent(); len(got) != 1 || first.isClosed() {
+		t.Errorf("expected restarted recognizer to be reused, sent=%v closed=%v", got, first.isClosed())
+	}
+}
@@ -0,0 +1,145 @@
+package audio
+
+import (
+	"io"
+	"sync"
+)
+
+// jitterReadSize 后台从源读取的单次块大小
+const jitterReadSize = 4096
+
+// jitterBuffer TTS 播放抖动缓冲
+// 后台 goroutine 持续从网络流读取，播放端（Mixer 回调）读取时永不阻塞：
+// 预缓冲未达到 preroll 或网络卡顿导致欠载时返回静音，欠载后重新预缓冲再继续播放
+type jitterBuffer struct {
+	src     io.Reader
+	preroll int    // 开始（或欠载后恢复）播放前需要缓冲的字节数
+	onStart func() // 首次输出真实音频时调用，可为 nil
+
+	mu          sync.Mutex
+	buf         []byte
+	srcDone     bool
+	buffering   bool
+	started     bool // 是否已经开始输出过真实音频，用于区分首次预缓冲与欠载
+	closed      bool
+	underruns   int
+	silentBytes int // 欠载期间插入的静音字节数（不含首次预缓冲）
+}
+
+func newJitterBuffer(src io.Reader, prerollBytes int) *jitterBuffer {
+	if prerollBytes < 0 {
+		prerollBytes = 0
+	}
+	// 对齐到 16-bit 采样
+	prerollBytes &^= 1
+	j := &jitterBuffer{
+		src:       src,
+		preroll:   prerollBytes,
+		buffering: true,
+	}
+	go j.fill()
+	return j
+}
+
+// jitterPrerollBytes 预缓冲时长对应的字节数（16-bit PCM）
+func jitterPrerollBytes(sampleRate, channels, prerollMs int) int {
+	if sampleRate <= 0 || channels <= 0 || prerollMs <= 0 {
+		return 0
+	}
+	return sampleRate * prerollMs / 1000 * channels * 2
+}
+
+func (j *jitterBuffer) fill() {
+	chunk := make([]byte, jitterReadSize)
+	for {
+		n, err := j.src.Read(chunk)
+		j.mu.Lock()
+		if j.closed {
+			j.mu.Unlock()
+			return
+		}
+		if n > 0 {
+			j.buf = append(j.buf, chunk[:n]...)
+		}
+		if err != nil {
+			j.srcDone = true
+			j.mu.Unlock()
+			return
+		}
+		j.mu.Unlock()
+	}
+}
+
+// Read 读取已缓冲的音频；缓冲不足时用静音补齐，源结束且缓冲耗尽后返回 io.EOF
+func (j *jitterBuffer) Read(p []byte) (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if j.buffering {
+		if len(j.buf) < j.preroll && !j.srcDone {
+			return j.silenceLocked(p), nil
+		}
+		j.buffering = false
+	}
+
+	if len(j.buf) == 0 {
+		if j.srcDone {
+			return 0, io.EOF
+		}
+		// 欠载：网络数据未及时到达，重新预缓冲
+		j.buffering = true
+		j.underruns++
+		return j.silenceLocked(p), nil
+	}
+
+	n := copy(p, j.buf)
+	j.buf = j.buf[n:]
+	if !j.started {
+		j.started = true
+		if j.onStart != nil {
+			j.onStart()
+		}
+	}
+	return n, nil
+}
+
+func (j *jitterBuffer) silenceLocked(p []byte) int {
+	for i := range p {
+		p[i] = 0
+	}
+	if j.started {
+		j.silentBytes += len(p)
+	}
+	return len(p)
+}
+
+// Stats 返回欠载次数和欠载期间插入的静音字节数
+func (j *jitterBuffer) Stats() (underruns int, silentBytes int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.underruns, j.silentBytes
+}
+
+// Close 停止缓冲并关闭源（若支持），解除后台读取的阻塞
+func (j *jitterBuffer) Close() error {
+	j.mu.Lock()
+	if j.closed {
+		j.mu.Unlock()
+		return nil
+	}
+	j.closed = true
+	j.buf = nil
+	j.mu.Unlock()
+
+	if closer, ok := j.src.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
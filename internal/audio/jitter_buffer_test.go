@@ -0,0 +1,111 @@
+package audio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+// waitBuffered 等待后台 goroutine 缓冲到指定字节数
+func waitBuffered(t *testing.T, j *jitterBuffer, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		j.mu.Lock()
+		n := len(j.buf)
+		j.mu.Unlock()
+		if n >= want {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("jitter buffer did not reach %d bytes", want)
+}
+
+func TestJitterBufferPreroll(t *testing.T) {
+	pr, pw := io.Pipe()
+	j := newJitterBuffer(pr, 8)
+	defer j.Close()
+
+	p := make([]byte, 4)
+	n, err := j.Read(p)
+	if err != nil || n != 4 || !bytes.Equal(p, make([]byte, 4)) {
+		t.Fatalf("Read() before preroll = %v %d %v, want silence", p, n, err)
+	}
+
+	go pw.Write([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	waitBuffered(t, j, 8)
+
+	n, err = j.Read(p)
+	if err != nil || !bytes.Equal(p[:n], []byte{1, 2, 3, 4}) {
+		t.Fatalf("Read() after preroll = %v %v, want audio", p[:n], err)
+	}
+	if underruns, _ := j.Stats(); underruns != 0 {
+		t.Fatalf("underruns = %d, want 0 for initial preroll", underruns)
+	}
+}
+
+func TestJitterBufferUnderrun(t *testing.T) {
+	pr, pw := io.Pipe()
+	j := newJitterBuffer(pr, 4)
+	defer j.Close()
+
+	go pw.Write([]byte{1, 2, 3, 4})
+	waitBuffered(t, j, 4)
+
+	p := make([]byte, 4)
+	if _, err := j.Read(p); err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+
+	// 网络未送达新数据：返回静音并计一次欠载
+	n, err := j.Read(p)
+	if err != nil || n != 4 || !bytes.Equal(p, make([]byte, 4)) {
+		t.Fatalf("Read() on underrun = %v %d %v, want silence", p, n, err)
+	}
+	// 重新预缓冲期间不重复计数
+	j.Read(p)
+	underruns, silentBytes := j.Stats()
+	if underruns != 1 || silentBytes != 8 {
+		t.Fatalf("Stats() = %d, %d, want 1, 8", underruns, silentBytes)
+	}
+}
+
+func TestJitterBufferDrainsAfterEOF(t *testing.T) {
+	j := newJitterBuffer(bytes.NewReader([]byte{1, 2, 3}), 100)
+	defer j.Close()
+
+	got, err := io.ReadAll(readerFunc(func(p []byte) (int, error) {
+		n, err := j.Read(p)
+		// 源结束前可能读到预缓冲静音，跳过
+		if err == nil && n > 0 && bytes.Equal(p[:n], make([]byte, n)) {
+			return 0, nil
+		}
+		return n, err
+	}))
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if !bytes.Equal(got, []byte{1, 2, 3}) {
+		t.Fatalf("ReadAll() = %v, want [1 2 3]", got)
+	}
+}
+
+func TestJitterBufferCloseClosesSource(t *testing.T) {
+	pr, _ := io.Pipe()
+	j := newJitterBuffer(pr, 4)
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := pr.Read(make([]byte, 1)); err != io.ErrClosedPipe {
+		t.Fatalf("source Read() error = %v, want io.ErrClosedPipe", err)
+	}
+	if _, err := j.Read(make([]byte, 4)); err != io.EOF {
+		t.Fatalf("Read() after Close error = %v, want io.EOF", err)
+	}
+}
+
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) { return f(p) }
@@ -0,0 +1,150 @@
+package audio
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// LevelWindow 电平统计窗口，每个窗口回调一次
+const LevelWindow = 100 * time.Millisecond
+
+// clipLevel 样本绝对值达到该值（约 -0.1 dBFS）视为削波
+const clipLevel = 0.99
+
+// AudioLevel 一个统计窗口内的音频电平（归一化到 0~1）
+type AudioLevel struct {
+	RMS     float64
+	Peak    float64
+	Clipped int       // 削波的样本数
+	At      time.Time // 窗口结束时间
+}
+
+// DBFS 以 dBFS 表示的 RMS，静音时返回 -Inf
+func (l AudioLevel) DBFS() float64 {
+	return 20 * math.Log10(l.RMS)
+}
+
+// LevelCallback 每个统计窗口回调一次，在音频线程中调用，不能阻塞
+type LevelCallback func(level AudioLevel)
+
+// LevelReporter 支持上报音频电平的组件（AudioInPipe、Mixer、AudioOutPipe 实现）
+type LevelReporter interface {
+	SetOnLevel(callback LevelCallback)
+}
+
+// levelMeter 按固定窗口统计 RMS、峰值和削波样本数
+type levelMeter struct {
+	mu       sync.Mutex
+	window   int // 每个窗口的帧数
+	frames   int
+	sum      float64
+	samples  int
+	peak     float64
+	clipped  int
+	callback LevelCallback
+	now      func() time.Time
+}
+
+func newLevelMeter(sampleRate int) *levelMeter {
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+	return &levelMeter{
+		window: sampleRate * int(LevelWindow/time.Millisecond) / 1000,
+		now:    time.Now,
+	}
+}
+
+func (m *levelMeter) SetOnLevel(callback LevelCallback) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.callback = callback
+}
+
+// WritePCM16 统计 16-bit PCM，channels 个样本为一帧
+func (m *levelMeter) WritePCM16(data []byte, channels int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	if m.callback == nil {
+		m.mu.Unlock()
+		return
+	}
+	channels = max(channels, 1)
+	var levels []AudioLevel
+	for i := 0; i+1 < len(data); i += 2 {
+		sample := int16(data[i]) | int16(data[i+1])<<8
+		m.add(float64(sample) / 32768.0)
+		if (i/2+1)%channels == 0 {
+			if level, ok := m.endFrame(); ok {
+				levels = append(levels, level)
+			}
+		}
+	}
+	callback := m.callback
+	m.mu.Unlock()
+
+	for _, level := range levels {
+		callback(level)
+	}
+}
+
+// WriteFloat32 统计按声道分开的浮点样本（Mixer 输出）
+func (m *levelMeter) WriteFloat32(buf [][]float32) {
+	if m == nil || len(buf) == 0 {
+		return
+	}
+	m.mu.Lock()
+	if m.callback == nil {
+		m.mu.Unlock()
+		return
+	}
+	var levels []AudioLevel
+	for i := range buf[0] {
+		for _, channel := range buf {
+			if i < len(channel) {
+				m.add(float64(channel[i]))
+			}
+		}
+		if level, ok := m.endFrame(); ok {
+			levels = append(levels, level)
+		}
+	}
+	callback := m.callback
+	m.mu.Unlock()
+
+	for _, level := range levels {
+		callback(level)
+	}
+}
+
+// add 累加一个样本，调用方需持有锁
+func (m *levelMeter) add(v float64) {
+	abs := math.Abs(v)
+	m.sum += v * v
+	m.samples++
+	if abs > m.peak {
+		m.peak = abs
+	}
+	if abs >= clipLevel {
+		m.clipped++
+	}
+}
+
+// endFrame 结束一帧，窗口满时返回该窗口的电平并重置，调用方需持有锁
+func (m *levelMeter) endFrame() (AudioLevel, bool) {
+	m.frames++
+	if m.frames < m.window {
+		return AudioLevel{}, false
+	}
+	level := AudioLevel{
+		RMS:     math.Sqrt(m.sum / float64(m.samples)),
+		Peak:    m.peak,
+		Clipped: m.clipped,
+		At:      m.now(),
+	}
+	m.frames, m.sum, m.samples, m.peak, m.clipped = 0, 0, 0, 0, 0
+	return level, true
+}
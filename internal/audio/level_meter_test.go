@@ -0,0 +1,86 @@
+package audio
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestLevelMeterPCM16(t *testing.T) {
+	tests := []struct {
+		name        string
+		sample      int16
+		wantRMS     float64
+		wantClipped int
+	}{
+		{"silence", 0, 0, 0},
+		{"half scale", 16384, 0.5, 0},
+		{"full scale clips", 32767, 1, 1600},
+		{"negative full scale clips", -32768, 1, 1600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meter := newLevelMeter(16000)
+			var levels []AudioLevel
+			meter.SetOnLevel(func(level AudioLevel) { levels = append(levels, level) })
+
+			// 250ms 单声道音频：两个完整窗口，剩余 50ms 留到下个窗口
+			data := make([]byte, 4000*2)
+			for i := 0; i < 4000; i++ {
+				data[i*2] = byte(tt.sample)
+				data[i*2+1] = byte(uint16(tt.sample) >> 8)
+			}
+			meter.WritePCM16(data, 1)
+
+			if len(levels) != 2 {
+				t.Fatalf("got %d levels, want 2", len(levels))
+			}
+			level := levels[0]
+			if math.Abs(level.RMS-tt.wantRMS) > 0.001 || math.Abs(level.Peak-tt.wantRMS) > 0.001 {
+				t.Errorf("RMS/Peak = %.4f/%.4f, want %.4f", level.RMS, level.Peak, tt.wantRMS)
+			}
+			if level.Clipped != tt.wantClipped {
+				t.Errorf("Clipped = %d, want %d", level.Clipped, tt.wantClipped)
+			}
+		})
+	}
+}
+
+func TestLevelMeterFloat32Stereo(t *testing.T) {
+	meter := newLevelMeter(16000)
+	now := time.Unix(100, 0)
+	meter.now = func() time.Time { return now }
+	var levels []AudioLevel
+	meter.SetOnLevel(func(level AudioLevel) { levels = append(levels, level) })
+
+	// 每次回调 512 帧，左声道 0.5、右声道静音；1600 帧为一个窗口
+	buf := [][]float32{make([]float32, 512), make([]float32, 512)}
+	for i := range buf[0] {
+		buf[0][i] = 0.5
+	}
+	for i := 0; i < 4; i++ {
+		meter.WriteFloat32(buf)
+	}
+
+	if len(levels) != 1 {
+		t.Fatalf("got %d levels, want 1", len(levels))
+	}
+	if want := 0.5 / math.Sqrt2; math.Abs(levels[0].RMS-want) > 1e-6 {
+		t.Errorf("RMS = %.4f, want %.4f", levels[0].RMS, want)
+	}
+	if levels[0].Peak != 0.5 || !levels[0].At.Equal(now) {
+		t.Errorf("level = %+v", levels[0])
+	}
+}
+
+func TestLevelMeterWithoutCallback(t *testing.T) {
+	var meter *levelMeter
+	meter.WritePCM16([]byte{0, 0}, 1)
+	meter.WriteFloat32([][]float32{{0}})
+
+	meter = newLevelMeter(16000)
+	meter.WritePCM16(make([]byte, 6400), 1)
+	if meter.frames != 0 {
+		t.Errorf("frames = %d, want no accumulation without callback", meter.frames)
+	}
+}
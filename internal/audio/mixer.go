@@ -2,6 +2,7 @@ package audio
 
 import (
 	"io"
+	"time"
 )
 
 // AudioMixer 音频混音器，负责音频混合和音量控制
@@ -10,6 +11,9 @@ type AudioMixer interface {
 	AddResourceStream(audio io.Reader)
 	RemoveTTSStream()
 	RemoveResourceStream()
+	// AddPromptStream 添加提示音流（与 TTS 同音量播放，不影响资源音频）
+	AddPromptStream(audio io.Reader)
+	RemovePromptStream()
 	SetTTSVolume(volume float64)
 	SetResourceVolume(volume float64)
 	OnTTSStarted()
@@ -18,12 +22,76 @@ type AudioMixer interface {
 	Stop()
 }
 
+// VolumeController 可选接口：AudioMixer 支持整体输出音量，与 TTS / 资源音量相乘后作用于全部输出
+type VolumeController interface {
+	// Volume 返回当前整体音量（0 为静音，1 为原始音量）
+	Volume() float64
+	SetVolume(volume float64)
+}
+
+// PlaybackPosition Mixer 的播放进度
+type PlaybackPosition struct {
+	Frames int64     // 音频回调已渲染的总帧数（不含正在渲染的一帧缓冲）
+	Time   time.Time // 正在渲染的缓冲第一个样本的估计播放时间，尚未回调时为零值
+}
+
+// PlaybackClock 可选接口：AudioMixer 按音频回调实际消耗的帧数提供播放进度，
+// 供回声参考打时间戳（见 TimedReferenceSink）
+type PlaybackClock interface {
+	PlaybackPosition() PlaybackPosition
+}
+
+// OutputRecorder 可选接口：AudioMixer 把混音后送往扬声器的音频（下混为单声道、输出采样率的 16-bit PCM）写入 w，
+// 用于录制调试音频；w 在音频回调中调用，不能阻塞（见 WAVRecorder），nil 表示停止录制
+type OutputRecorder interface {
+	SetOutputRecorder(w io.Writer)
+}
+
+// ResourceMode 资源音频（工具返回的音乐、音效等）的播放方式
+type ResourceMode int
+
+const (
+	// ResourceQueue 排队：等前面的资源播放完再播放（默认）
+	ResourceQueue ResourceMode = iota
+	// ResourcePreempt 抢占：停止正在播放和排队的资源，立即播放
+	ResourcePreempt
+	// ResourceMix 叠加：与正在播放的资源同时播放，不影响队列
+	ResourceMix
+)
+
+// ResourceOptions 资源音频播放选项
+type ResourceOptions struct {
+	Mode ResourceMode
+	// SampleRate / Channels 资源音频的采样率与声道数，0 表示与 Mixer 输出采样率相同的单声道；
+	// 与 Mixer 不一致时自动重采样，多声道下混为单声道。音频为 wav.Reader 且三项均未设置时按文件头填写
+	SampleRate int
+	Channels   int
+	// Format 资源音频的采样格式，非 16-bit 时由 Mixer 转换后播放
+	Format SampleFormat
+	// OnFinished 资源播放结束时在独立协程中回调，interrupted 为 true 表示被抢占、移除或打断而未播完
+	OnFinished func(interrupted bool)
+}
+
+// ResourceQueuer 可选接口：AudioMixer 支持资源音频排队、抢占与叠加
+// AddResourceStream 相当于 ResourcePreempt，RemoveResourceStream 停止正在播放和排队的全部资源；
+// 资源播完或被停止时，实现 io.Closer 的音频会被关闭
+type ResourceQueuer interface {
+	EnqueueResourceStream(audio io.Reader, opts ResourceOptions)
+}
+
 // MixerConfig Mixer配置
 type MixerConfig struct {
 	TTSVolume      float64 // 默认TTS音量
 	ResourceVolume float64 // 默认资源音频音量
 	SampleRate     int     // 系统采样率 (Hz)，默认 16000
-	Channels       int     // 输出声道数，默认 2 (立体声)
+	Channels       int     // 输出声道数，默认 2 (立体声)，1 为单声道
+	OutputDevice   string  // 输出设备名称（部分匹配），空字符串或找不到时使用默认设备
+	CrossfadeMs    int     // 提示音播放中开始 TTS 时的交叉淡化时长，0 表示直接切换
+	FadeOutMs      int     // 移除 / 打断音频流时的淡出时长，避免硬切产生爆音，0 表示直接切断
+	// ExternalStream 为 true 时不打开输出流，由外部流（如 DuplexStream）通过 AudioRenderer 驱动
+	ExternalStream bool
+	// Backend 打开输出流的音频后端，为 nil 时使用 PortAudio
+	Backend AudioBackend
 	// 当TTS播放时，资源音频自动降为50%
 }
 
@@ -38,5 +106,7 @@ func DefaultMixerConfig() *MixerConfig {
 		ResourceVolume: 1.0,
 		SampleRate:     16000, // 默认 16kHz
 		Channels:       2,     // 默认立体声
+		CrossfadeMs:    120,
+		FadeOutMs:      50,
 	}
 }
@@ -4,21 +4,84 @@ import (
 	"context"
 	"io"
 	"sync"
+	"time"
 
-	"github.com/gordonklaus/portaudio"
 	"github.com/liuscraft/orion-x/internal/logging"
 )
 
+// renderIdleTimeout 超过该时长没有音频回调时认为输出流未在运行，移除音频流不再等待淡出
+const renderIdleTimeout = 200 * time.Millisecond
+
+// resourceItem 一段资源音频及其完成回调
+type resourceItem struct {
+	source     io.Reader // 调用方传入的音频，结束时若实现 io.Closer 则关闭
+	reader     io.Reader
+	onFinished func(interrupted bool)
+	fade       *fadeOut // 非空表示正在淡出，淡出完成后按被打断移除
+}
+
+// fadeOut 移除音频流前的淡出进度，由音频回调推进；字段受 mixerImpl.mu 保护
+type fadeOut struct {
+	remaining int
+	total     int
+	done      chan struct{}
+	closed    bool
+}
+
+func newFadeOut(total int) *fadeOut {
+	return &fadeOut{remaining: total, total: total, done: make(chan struct{})}
+}
+
+// advance 推进 frames 个样本，返回本帧起止增益与淡出是否完成
+func (f *fadeOut) advance(frames int) (from, to float32, finished bool) {
+	from = float32(f.remaining) / float32(f.total)
+	f.remaining -= frames
+	if f.remaining <= 0 {
+		f.remaining = 0
+		f.finish()
+	}
+	return from, float32(f.remaining) / float32(f.total), f.closed
+}
+
+// finish 结束淡出并唤醒等待方，可重复调用
+func (f *fadeOut) finish() {
+	if f != nil && !f.closed {
+		f.closed = true
+		close(f.done)
+	}
+}
+
 type mixerImpl struct {
-	config                *MixerConfig
-	ttsStream             io.Reader
-	resourceStream        io.Reader
+	config       *MixerConfig
+	ttsStream    io.Reader
+	promptStream io.Reader
+	// 资源音频：resourceCurrent 播放完后依次播放 resourceQueue，resourceMixed 与之叠加
+	resourceCurrent *resourceItem
+	resourceQueue   []*resourceItem
+	resourceMixed   []*resourceItem
+	// 交叉淡化进度（剩余样本数），提示音淡出的同时 TTS 淡入
+	fadeRemaining int
+	fadeTotal     int
+	// 移除时的淡出进度，非空时音频流仍在播放，淡出完成后才真正移除
+	ttsFade    *fadeOut
+	promptFade *fadeOut
+	// 流已读完时移除不需要淡出；gen 在替换流时递增，避免把旧流的结束记到新流上
+	ttsEnded              bool
+	ttsGen                int
+	promptEnded           bool
+	promptGen             int
+	lastRender            time.Time // 最近一次音频回调的时间
+	playback              *playbackClock
+	level                 *levelMeter
+	recorder              io.Writer // 调试录音，接收混音后下混为单声道的 16-bit PCM
+	recordBuf             []byte    // 调试录音的下混缓冲，只在音频回调中使用，帧数变化时重新分配
 	currentTTSVolume      float64
 	currentResourceVolume float64
+	volume                float64 // 整体音量，与 TTS / 资源音量相乘
 	mu                    sync.Mutex
 	ctx                   context.Context
 	cancel                context.CancelFunc
-	player                *portaudio.Stream
+	player                OutputStream
 	started               bool
 }
 
@@ -26,15 +89,18 @@ func NewMixer(config *MixerConfig) (AudioMixer, error) {
 	if config == nil {
 		config = DefaultMixerConfig()
 	}
-	// Note: PortAudio should be initialized by the caller before creating Mixer
+	// Note: the audio backend should be initialized by the caller before creating Mixer
 	// This avoids multiple Initialize() calls which can cause device conflicts
 	ctx, cancel := context.WithCancel(context.Background())
 	m := &mixerImpl{
 		config:                config,
 		currentTTSVolume:      config.TTSVolume,
 		currentResourceVolume: config.ResourceVolume,
+		volume:                1.0,
 		ctx:                   ctx,
 		cancel:                cancel,
+		level:                 newLevelMeter(config.SampleRate),
+		playback:              newPlaybackClock(config.SampleRate),
 	}
 	// Use sample rate and channels from config
 	sampleRate := config.SampleRate
@@ -45,8 +111,20 @@ func NewMixer(config *MixerConfig) (AudioMixer, error) {
 	if channels == 0 {
 		channels = 2 // fallback to stereo
 	}
+	if config.ExternalStream {
+		logging.Infof("AudioMixer: using external stream")
+		return m, nil
+	}
 
-	stream, err := portaudio.OpenDefaultStream(0, channels, float64(sampleRate), 1024, m.audioCallback)
+	backend := config.Backend
+	if backend == nil {
+		backend = PortAudioBackend()
+	}
+	stream, err := backend.OpenOutput(StreamConfig{
+		Device:     config.OutputDevice,
+		SampleRate: sampleRate,
+		Channels:   channels,
+	}, m.audioCallback)
 	if err != nil {
 		cancel()
 		return nil, err
@@ -59,24 +137,248 @@ func (m *mixerImpl) AddTTSStream(audio io.Reader) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.ttsStream = audio
+	m.ttsGen++
+	m.ttsEnded = false
+	m.ttsFade.finish()
+	m.ttsFade = nil
+
+	// 提示音（如思考音）仍在播放时，与 TTS 交叉淡化，避免突兀切换
+	if audio != nil && m.promptStream != nil {
+		m.fadeTotal = m.crossfadeSamples()
+		m.fadeRemaining = m.fadeTotal
+		if m.fadeTotal == 0 {
+			m.promptStream = nil
+		}
+	}
 }
 
+// AddResourceStream 停止当前资源音频并立即播放 audio
 func (m *mixerImpl) AddResourceStream(audio io.Reader) {
+	m.EnqueueResourceStream(audio, ResourceOptions{Mode: ResourcePreempt})
+}
+
+// EnqueueResourceStream 按 opts.Mode 排队、抢占或叠加播放资源音频
+func (m *mixerImpl) EnqueueResourceStream(audio io.Reader, opts ResourceOptions) {
+	if audio == nil {
+		return
+	}
+	opts = wavResourceOptions(audio, opts)
+	reader := NewMixerStreamReader(audio, opts.SampleRate, opts.Channels, opts.Format, m.sampleRate())
+	item := &resourceItem{source: audio, reader: reader, onFinished: opts.OnFinished}
+
 	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.resourceStream = audio
+	var stopped []*resourceItem
+	switch opts.Mode {
+	case ResourcePreempt:
+		stopped = m.clearResourcesLocked()
+		m.resourceCurrent = item
+	case ResourceMix:
+		m.resourceMixed = append(m.resourceMixed, item)
+	default:
+		if m.resourceCurrent == nil {
+			m.resourceCurrent = item
+		} else {
+			m.resourceQueue = append(m.resourceQueue, item)
+		}
+	}
+	m.mu.Unlock()
+
+	notifyResourcesFinished(stopped, true)
 }
 
+// RemoveTTSStream 淡出当前 TTS 流后移除，淡出完成（或输出流未运行）才返回
 func (m *mixerImpl) RemoveTTSStream() {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.ttsStream = nil
+	f := m.ttsFade
+	if f == nil && m.ttsStream != nil && !m.ttsEnded {
+		if total := m.fadeOutSamplesLocked(); total > 0 {
+			f = newFadeOut(total)
+			m.ttsFade = f
+		}
+	}
+	if f == nil {
+		m.ttsStream = nil
+		m.mu.Unlock()
+		return
+	}
+	m.mu.Unlock()
+
+	m.waitFadeOut(f)
+
+	m.mu.Lock()
+	if m.ttsFade == f {
+		m.ttsStream = nil
+		m.ttsFade = nil
+	}
+	m.mu.Unlock()
 }
 
+// RemoveResourceStream 停止正在播放和排队的全部资源音频，正在播放的先淡出
 func (m *mixerImpl) RemoveResourceStream() {
+	m.mu.Lock()
+	total := m.fadeOutSamplesLocked()
+	if total == 0 {
+		stopped := m.clearResourcesLocked()
+		m.mu.Unlock()
+		notifyResourcesFinished(stopped, true)
+		return
+	}
+	stopped := m.resourceQueue
+	m.resourceQueue = nil
+	var fading []*resourceItem
+	for _, item := range append([]*resourceItem{m.resourceCurrent}, m.resourceMixed...) {
+		if item == nil {
+			continue
+		}
+		if item.fade == nil {
+			item.fade = newFadeOut(total)
+		}
+		fading = append(fading, item)
+	}
+	m.mu.Unlock()
+	notifyResourcesFinished(stopped, true)
+
+	for _, item := range fading {
+		m.waitFadeOut(item.fade)
+	}
+
+	// 等待超时仍未移除的项在这里移除
+	m.mu.Lock()
+	finished := m.retireResourcesLocked(fading)
+	m.mu.Unlock()
+	notifyResourcesFinished(finished, true)
+}
+
+// clearResourcesLocked 清空全部资源音频，返回被停止的项；调用方持有锁
+func (m *mixerImpl) clearResourcesLocked() []*resourceItem {
+	var stopped []*resourceItem
+	if m.resourceCurrent != nil {
+		stopped = append(stopped, m.resourceCurrent)
+	}
+	stopped = append(stopped, m.resourceQueue...)
+	stopped = append(stopped, m.resourceMixed...)
+	for _, item := range stopped {
+		item.fade.finish()
+	}
+	m.resourceCurrent = nil
+	m.resourceQueue = nil
+	m.resourceMixed = nil
+	return stopped
+}
+
+// retireResourcesLocked 移除已播完的资源，排队的下一段接替播放；调用方持有锁
+// 返回仍在播放列表中的项（已被 Remove / 抢占的项已经回调过，不再返回）
+func (m *mixerImpl) retireResourcesLocked(ended []*resourceItem) []*resourceItem {
+	var finished []*resourceItem
+	for _, item := range ended {
+		item.fade.finish()
+		if item == m.resourceCurrent {
+			m.resourceCurrent = nil
+			if len(m.resourceQueue) > 0 {
+				m.resourceCurrent = m.resourceQueue[0]
+				m.resourceQueue = m.resourceQueue[1:]
+			}
+			finished = append(finished, item)
+			continue
+		}
+		for i, mixed := range m.resourceMixed {
+			if mixed == item {
+				m.resourceMixed = append(m.resourceMixed[:i:i], m.resourceMixed[i+1:]...)
+				finished = append(finished, item)
+				break
+			}
+		}
+	}
+	return finished
+}
+
+// notifyResourcesFinished 在独立协程中关闭资源并回调完成通知，避免阻塞音频回调
+func notifyResourcesFinished(items []*resourceItem, interrupted bool) {
+	for _, item := range items {
+		go item.finish(interrupted)
+	}
+}
+
+func (item *resourceItem) finish(interrupted bool) {
+	if closer, ok := item.source.(io.Closer); ok {
+		closer.Close()
+	}
+	if item.onFinished != nil {
+		item.onFinished(interrupted)
+	}
+}
+
+func (m *mixerImpl) AddPromptStream(audio io.Reader) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	m.resourceStream = nil
+	m.promptStream = audio
+	m.promptGen++
+	m.promptEnded = false
+	m.fadeRemaining = 0
+	m.promptFade.finish()
+	m.promptFade = nil
+}
+
+// RemovePromptStream 淡出提示音后移除；与 TTS 交叉淡化中的提示音由交叉淡化自然结束
+func (m *mixerImpl) RemovePromptStream() {
+	m.mu.Lock()
+	if m.fadeRemaining > 0 {
+		m.mu.Unlock()
+		return
+	}
+	f := m.promptFade
+	if f == nil && m.promptStream != nil && !m.promptEnded {
+		if total := m.fadeOutSamplesLocked(); total > 0 {
+			f = newFadeOut(total)
+			m.promptFade = f
+		}
+	}
+	if f == nil {
+		m.promptStream = nil
+		m.mu.Unlock()
+		return
+	}
+	m.mu.Unlock()
+
+	m.waitFadeOut(f)
+
+	m.mu.Lock()
+	if m.promptFade == f {
+		m.promptStream = nil
+		m.promptFade = nil
+	}
+	m.mu.Unlock()
+}
+
+// fadeOutSamplesLocked 移除音频流时的淡出样本数，输出流未在运行时为 0；调用方持有锁
+func (m *mixerImpl) fadeOutSamplesLocked() int {
+	if m.config.FadeOutMs <= 0 || time.Since(m.lastRender) > renderIdleTimeout {
+		return 0
+	}
+	return m.sampleRate() * m.config.FadeOutMs / 1000
+}
+
+// waitFadeOut 等待音频回调完成淡出，输出流中途停止时最多多等 renderIdleTimeout
+func (m *mixerImpl) waitFadeOut(f *fadeOut) {
+	timer := time.NewTimer(time.Duration(m.config.FadeOutMs)*time.Millisecond + renderIdleTimeout)
+	defer timer.Stop()
+	select {
+	case <-f.done:
+	case <-timer.C:
+	}
+}
+
+// crossfadeSamples 交叉淡化对应的样本数
+func (m *mixerImpl) crossfadeSamples() int {
+	return m.sampleRate() * m.config.CrossfadeMs / 1000
+}
+
+// sampleRate 输出采样率，未配置时为 16000
+func (m *mixerImpl) sampleRate() int {
+	if m.config.SampleRate == 0 {
+		return 16000
+	}
+	return m.config.SampleRate
 }
 
 func (m *mixerImpl) SetTTSVolume(volume float64) {
@@ -91,6 +393,20 @@ func (m *mixerImpl) SetResourceVolume(volume float64) {
 	m.currentResourceVolume = volume
 }
 
+// Volume 返回整体音量
+func (m *mixerImpl) Volume() float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.volume
+}
+
+// SetVolume 设置整体音量，作用于 TTS、提示音和资源音频，不影响 TTS 播放时资源音频的避让比例
+func (m *mixerImpl) SetVolume(volume float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.volume = volume
+}
+
 func (m *mixerImpl) OnTTSStarted() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -144,54 +460,178 @@ func (m *mixerImpl) Stop() {
 		}
 	}
 
-	// 注意：不在这里调用 portaudio.Terminate()
-	// PortAudio 的生命周期由 main.go 统一管理
-	// Mixer 只是 PortAudio 的使用者，不负责其初始化和终止
+	// 注意：不在这里调用 AudioBackend.Terminate()
+	// 后端的生命周期由 main.go 统一管理
+	// Mixer 只是后端的使用者，不负责其初始化和终止
+}
+
+// Render 实现 AudioRenderer，供外部流在回调中拉取混音输出
+func (m *mixerImpl) Render(out [][]float32) {
+	m.audioCallback(out)
+}
+
+// PlaybackPosition 实现 PlaybackClock，在音频回调中调用时返回正在渲染的缓冲的播放进度
+func (m *mixerImpl) PlaybackPosition() PlaybackPosition {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.playback == nil {
+		return PlaybackPosition{}
+	}
+	return m.playback.position
+}
+
+// SetOnLevel 设置输出电平回调，统计的是混音后送往扬声器的信号
+func (m *mixerImpl) SetOnLevel(callback LevelCallback) {
+	if m.level != nil {
+		m.level.SetOnLevel(callback)
+	}
+}
+
+// SetOutputRecorder 实现 OutputRecorder
+func (m *mixerImpl) SetOutputRecorder(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.recorder = w
 }
 
 func (m *mixerImpl) audioCallback(out [][]float32) {
-	for i := range out[0] {
-		out[0][i] = 0
-		out[1][i] = 0
+	for _, channel := range out {
+		clear(channel)
 	}
+	defer m.level.WriteFloat32(out)
+	frames := len(out[0])
 	m.mu.Lock()
-	ttsStream := m.ttsStream
-	resourceStream := m.resourceStream
-	ttsVolume := m.currentTTSVolume
-	resourceVolume := m.currentResourceVolume
+	if recorder := m.recorder; recorder != nil {
+		defer func() {
+			m.recordBuf = downmixS16(out, m.recordBuf)
+			recorder.Write(m.recordBuf)
+		}()
+	}
+	m.lastRender = time.Now()
+	if m.playback != nil {
+		m.playback.advance(frames, m.lastRender)
+	}
+	ttsStream, ttsGen := m.ttsStream, m.ttsGen
+	promptStream, promptGen := m.promptStream, m.promptGen
+	ttsVolume := float32(m.currentTTSVolume * m.volume)
+	resourceVolume := float32(m.currentResourceVolume * m.volume)
+	ttsFrom, ttsTo := ttsVolume, ttsVolume
+	promptFrom, promptTo := ttsVolume, ttsVolume
+
+	// 交叉淡化：本帧内提示音增益从 fadeFrom 线性降到 fadeTo，TTS 增益与之互补
+	if m.fadeRemaining > 0 && m.fadeTotal > 0 {
+		fadeFrom := float32(m.fadeRemaining) / float32(m.fadeTotal)
+		m.fadeRemaining -= frames
+		if m.fadeRemaining <= 0 {
+			m.fadeRemaining = 0
+			m.promptStream = nil
+		}
+		fadeTo := float32(m.fadeRemaining) / float32(m.fadeTotal)
+		ttsFrom, ttsTo = ttsVolume*(1-fadeFrom), ttsVolume*(1-fadeTo)
+		promptFrom, promptTo = ttsVolume*fadeFrom, ttsVolume*fadeTo
+	}
+
+	// 移除前的淡出：本帧结束时淡出完成则在此移除
+	if m.ttsFade != nil {
+		from, to, finished := m.ttsFade.advance(frames)
+		ttsFrom, ttsTo = ttsFrom*from, ttsTo*to
+		if finished {
+			m.ttsStream = nil
+			m.ttsFade = nil
+		}
+	}
+	if m.promptFade != nil {
+		from, to, finished := m.promptFade.advance(frames)
+		promptFrom, promptTo = promptFrom*from, promptTo*to
+		if finished {
+			m.promptStream = nil
+			m.promptFade = nil
+		}
+	}
+
+	type resourceGain struct {
+		item     *resourceItem
+		from, to float32
+		faded    bool
+	}
+	resources := make([]resourceGain, 0, 1+len(m.resourceMixed))
+	for _, item := range append([]*resourceItem{m.resourceCurrent}, m.resourceMixed...) {
+		if item == nil {
+			continue
+		}
+		gain := resourceGain{item: item, from: resourceVolume, to: resourceVolume}
+		if item.fade != nil {
+			from, to, finished := item.fade.advance(frames)
+			gain.from, gain.to, gain.faded = gain.from*from, gain.to*to, finished
+		}
+		resources = append(resources, gain)
+	}
 	m.mu.Unlock()
-	mixFromStream(ttsStream, out, float32(ttsVolume))
-	mixFromStream(resourceStream, out, float32(resourceVolume))
+
+	ttsEnded := mixFromStreamRamp(ttsStream, out, ttsFrom, ttsTo)
+	promptEnded := mixFromStreamRamp(promptStream, out, promptFrom, promptTo)
+	if ttsEnded || promptEnded {
+		m.mu.Lock()
+		m.ttsEnded = m.ttsEnded || (ttsEnded && m.ttsGen == ttsGen)
+		m.promptEnded = m.promptEnded || (promptEnded && m.promptGen == promptGen)
+		m.mu.Unlock()
+	}
+
+	var ended, faded []*resourceItem
+	for _, gain := range resources {
+		if mixFromStreamRamp(gain.item.reader, out, gain.from, gain.to) && !gain.faded {
+			ended = append(ended, gain.item)
+		}
+		if gain.faded {
+			faded = append(faded, gain.item)
+		}
+	}
+	if len(ended) > 0 || len(faded) > 0 {
+		m.mu.Lock()
+		finished := m.retireResourcesLocked(ended)
+		interrupted := m.retireResourcesLocked(faded)
+		m.mu.Unlock()
+		notifyResourcesFinished(finished, false)
+		notifyResourcesFinished(interrupted, true)
+	}
+}
+
+// mixFromStream 混入音频流，返回流是否已读完
+func mixFromStream(stream io.Reader, buf [][]float32, volume float32) bool {
+	return mixFromStreamRamp(stream, buf, volume, volume)
 }
 
-func mixFromStream(stream io.Reader, buf [][]float32, volume float32) {
+// mixFromStreamRamp 混入音频流，音量在本帧内从 from 线性变化到 to（用于淡入淡出）；返回流是否已读完
+func mixFromStreamRamp(stream io.Reader, buf [][]float32, from, to float32) bool {
 	if stream == nil {
-		return
+		return false
 	}
 	// 16-bit PCM uses 2 bytes per sample; read exactly the frame size to avoid dropping data
 	samples := make([]byte, len(buf[0])*2)
 	n, err := io.ReadFull(stream, samples)
 	if err != nil && err != io.ErrUnexpectedEOF {
-		return
+		return true
 	}
+	ended := err == io.ErrUnexpectedEOF
 	limit := n / 2
-	for i := 0; i < limit && i < len(buf[0]); i++ {
+	frames := len(buf[0])
+	for i := 0; i < limit && i < frames; i++ {
 		sample := int16(samples[i*2]) | int16(samples[i*2+1])<<8
 		normalized := float32(sample) / 32768.0
-
-		buf[0][i] += normalized * volume
-		buf[1][i] += normalized * volume
-
-		if buf[0][i] > 1.0 {
-			buf[0][i] = 1.0
-		} else if buf[0][i] < -1.0 {
-			buf[0][i] = -1.0
+		volume := from
+		if from != to {
+			volume = from + (to-from)*float32(i)/float32(frames)
 		}
 
-		if buf[1][i] > 1.0 {
-			buf[1][i] = 1.0
-		} else if buf[1][i] < -1.0 {
-			buf[1][i] = -1.0
+		// 单声道音源复制到每个输出声道
+		for _, channel := range buf {
+			channel[i] += normalized * volume
+			if channel[i] > 1.0 {
+				channel[i] = 1.0
+			} else if channel[i] < -1.0 {
+				channel[i] = -1.0
+			}
 		}
 	}
+	return ended
 }
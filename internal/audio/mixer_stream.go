@@ -0,0 +1,25 @@
+package audio
+
+import "io"
+
+// NewMixerStreamReader 把 sampleRate 采样率、channels 声道、format 格式的 PCM 流转换为 Mixer 播放的
+// outputRate 采样率 16-bit 单声道：依次转换采样格式、下混、重采样。
+// sampleRate <= 0 视为与 outputRate 相同，channels <= 1 视为单声道，格式一致时不做任何包装
+func NewMixerStreamReader(audio io.Reader, sampleRate, channels int, format SampleFormat, outputRate int) io.Reader {
+	if audio == nil {
+		return nil
+	}
+	reader := NewS16Reader(audio, format)
+	if channels > 1 {
+		reader = newFrameReader(reader, channels*2, func(data []byte) []byte {
+			mono := downmixToMono(bytesToInt16(data), channels)
+			out := make([]byte, len(mono)*2)
+			int16ToBytes(mono, out)
+			return out
+		})
+	}
+	if sampleRate > 0 && outputRate > 0 && sampleRate != outputRate {
+		reader = NewResamplingReader(reader, sampleRate, outputRate, 1, NewLinearResampler())
+	}
+	return reader
+}
@@ -0,0 +1,113 @@
+package audio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio/wav"
+)
+
+func TestNewMixerStreamReader(t *testing.T) {
+	// 1 秒立体声：左声道 1000，右声道 3000，下混后为 2000
+	stereo := make([]int16, 44100*2)
+	for i := 0; i < 44100; i++ {
+		stereo[i*2] = 1000
+		stereo[i*2+1] = 3000
+	}
+	pcm := s16Bytes(stereo...)
+
+	tests := []struct {
+		name       string
+		data       []byte
+		sampleRate int
+		channels   int
+		format     SampleFormat
+		wantFrames int
+	}{
+		{"44.1k stereo", pcm, 44100, 2, SampleFormatS16, 16000},
+		{"44.1k stereo f32", ConvertFromS16(pcm, SampleFormatF32), 44100, 2, SampleFormatF32, 16000},
+		{"same rate stereo", pcm, 16000, 2, SampleFormatS16, 44100},
+		{"unknown rate treated as mixer rate", s16Bytes(stereo[:16000]...), 0, 0, SampleFormatS16, 16000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 每次读取的字节数不是帧大小的整数倍，帧跨越 Read 边界
+			source := &chunkReader{data: tt.data, size: 4001}
+			out, err := io.ReadAll(NewMixerStreamReader(source, tt.sampleRate, tt.channels, tt.format, 16000))
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			samples := bytesToInt16(out)
+			// 分块重采样有舍入误差，允许 1%
+			if diff := len(samples) - tt.wantFrames; diff < -tt.wantFrames/100 || diff > tt.wantFrames/100 {
+				t.Errorf("frames = %d, want about %d", len(samples), tt.wantFrames)
+			}
+			if tt.channels == 2 && samples[len(samples)/2] != 2000 {
+				t.Errorf("samples[mid] = %d, want 2000 (downmixed)", samples[len(samples)/2])
+			}
+		})
+	}
+
+	r := bytes.NewReader(pcm)
+	if NewMixerStreamReader(r, 16000, 1, SampleFormatS16, 16000) != io.Reader(r) {
+		t.Error("matching format should return the reader itself")
+	}
+}
+
+func TestMixerResourceResampled(t *testing.T) {
+	m := &mixerImpl{config: DefaultMixerConfig(), currentResourceVolume: 1.0, volume: 1.0}
+
+	// 48kHz 资源音频播放 100 帧（16kHz）应消耗约 300 个输入样本
+	resource := s16Bytes(make([]int16, 4800)...)
+	source := bytes.NewReader(resource)
+	m.EnqueueResourceStream(source, ResourceOptions{SampleRate: 48000})
+	m.audioCallback([][]float32{make([]float32, 100), make([]float32, 100)})
+	if consumed := (len(resource) - source.Len()) / 2; consumed < 300 {
+		t.Errorf("consumed %d input samples, want at least 300", consumed)
+	}
+}
+
+// closeTracker 记录是否被关闭
+type closeTracker struct {
+	io.Reader
+	closed chan struct{}
+}
+
+func (c *closeTracker) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func TestMixerResourceWAV(t *testing.T) {
+	m := &mixerImpl{config: DefaultMixerConfig(), currentResourceVolume: 1.0, volume: 1.0}
+
+	// 48kHz 立体声 WAV 未声明格式，按文件头转换；播完后关闭文件
+	file := wav.Encode(s16Bytes(make([]int16, 480*2)...), wav.PCM16(48000, 2))
+	source := &closeTracker{Reader: bytes.NewReader(file), closed: make(chan struct{})}
+	reader, err := wav.NewReader(source)
+	if err != nil {
+		t.Fatalf("wav.NewReader() error = %v", err)
+	}
+	finished := make(chan bool, 1)
+	m.EnqueueResourceStream(reader, ResourceOptions{OnFinished: func(interrupted bool) { finished <- interrupted }})
+
+	// 10ms 的 48kHz 立体声对应 160 帧 16kHz 输出，两次回调内播完
+	for i := 0; i < 2; i++ {
+		m.audioCallback([][]float32{make([]float32, 100), make([]float32, 100)})
+	}
+	select {
+	case interrupted := <-finished:
+		if interrupted {
+			t.Error("resource reported interrupted")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("resource not finished after 200 frames")
+	}
+	select {
+	case <-source.closed:
+	case <-time.After(time.Second):
+		t.Error("resource reader not closed")
+	}
+}
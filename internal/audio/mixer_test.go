@@ -3,7 +3,9 @@ package audio
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"io"
+	"math"
 	"testing"
 	"time"
 )
@@ -191,6 +193,26 @@ func TestMixFromStreamWithVolume(t *testing.T) {
 	}
 }
 
+func TestMixerMonoOutput(t *testing.T) {
+	config := DefaultMixerConfig()
+	config.Channels = 1
+	m := &mixerImpl{config: config, currentTTSVolume: 1.0, volume: 1.0}
+
+	tts := make([]byte, 8)
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint16(tts[i*2:], uint16(16384))
+	}
+	m.AddTTSStream(bytes.NewReader(tts))
+
+	out := [][]float32{make([]float32, 4)}
+	m.audioCallback(out)
+	for i, v := range out[0] {
+		if math.Abs(float64(v)-0.5) > 1e-6 {
+			t.Fatalf("sample %d = %f, want 0.5", i, v)
+		}
+	}
+}
+
 func TestMixFromStreamNilReader(t *testing.T) {
 	buf := make([][]float32, 2)
 	buf[0] = make([]float32, 512)
@@ -268,3 +290,242 @@ func TestMixerEOFHandling(t *testing.T) {
 	<-ctx.Done()
 	mixer.Stop()
 }
+
+func TestMixerCrossfadePromptToTTS(t *testing.T) {
+	config := DefaultMixerConfig()
+	config.SampleRate = 1000
+	config.CrossfadeMs = 8 // 8 个样本
+	m := &mixerImpl{config: config, currentTTSVolume: 1.0, volume: 1.0}
+
+	prompt := make([]byte, 32)
+	tts := make([]byte, 32)
+	for i := 0; i < 16; i++ {
+		binary.LittleEndian.PutUint16(prompt[i*2:], uint16(16384))
+		binary.LittleEndian.PutUint16(tts[i*2:], uint16(16384))
+	}
+
+	m.AddPromptStream(bytes.NewReader(prompt))
+	m.AddTTSStream(bytes.NewReader(tts))
+
+	out := [][]float32{make([]float32, 4), make([]float32, 4)}
+	m.audioCallback(out)
+	// 交叉淡化期间两路增益互补，总电平保持不变
+	for i, v := range out[0] {
+		if math.Abs(float64(v)-0.5) > 1e-6 {
+			t.Fatalf("sample %d = %f, want 0.5", i, v)
+		}
+	}
+
+	if m.promptStream == nil {
+		t.Fatal("prompt removed too early")
+	}
+	m.audioCallback(out)
+	if m.promptStream != nil {
+		t.Fatal("expected prompt removed after crossfade")
+	}
+}
+
+func TestMixerNoCrossfadeRemovesPrompt(t *testing.T) {
+	config := DefaultMixerConfig()
+	config.CrossfadeMs = 0
+	m := &mixerImpl{config: config, currentTTSVolume: 1.0, volume: 1.0}
+
+	m.AddPromptStream(bytes.NewReader(make([]byte, 8)))
+	m.AddTTSStream(bytes.NewReader(make([]byte, 8)))
+	if m.promptStream != nil {
+		t.Fatal("expected prompt replaced immediately when crossfade disabled")
+	}
+}
+
+func TestMixerRemoveFadesOut(t *testing.T) {
+	constant := func() io.Reader {
+		data := make([]byte, 128)
+		for i := 0; i < 64; i++ {
+			binary.LittleEndian.PutUint16(data[i*2:], uint16(16384))
+		}
+		return bytes.NewReader(data)
+	}
+	interrupted := make(chan bool, 1)
+
+	tests := []struct {
+		name    string
+		add     func(m *mixerImpl)
+		remove  func(m *mixerImpl)
+		fading  func(m *mixerImpl) bool
+		removed func(m *mixerImpl) bool
+	}{
+		{
+			name:    "tts",
+			add:     func(m *mixerImpl) { m.AddTTSStream(constant()) },
+			remove:  func(m *mixerImpl) { m.RemoveTTSStream() },
+			fading:  func(m *mixerImpl) bool { return m.ttsFade != nil },
+			removed: func(m *mixerImpl) bool { return m.ttsStream == nil },
+		},
+		{
+			name: "resource",
+			add: func(m *mixerImpl) {
+				m.EnqueueResourceStream(constant(), ResourceOptions{OnFinished: func(i bool) { interrupted <- i }})
+			},
+			remove:  func(m *mixerImpl) { m.RemoveResourceStream() },
+			fading:  func(m *mixerImpl) bool { return m.resourceCurrent != nil && m.resourceCurrent.fade != nil },
+			removed: func(m *mixerImpl) bool { return m.resourceCurrent == nil },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultMixerConfig()
+			config.SampleRate = 1000
+			config.FadeOutMs = 8 // 8 个样本，两帧
+			m := &mixerImpl{config: config, currentTTSVolume: 1.0, currentResourceVolume: 1.0, volume: 1.0}
+			tt.add(m)
+			out := [][]float32{make([]float32, 4), make([]float32, 4)}
+			m.audioCallback(out)
+
+			done := make(chan struct{})
+			go func() {
+				tt.remove(m)
+				close(done)
+			}()
+			for i := 0; ; i++ {
+				m.mu.Lock()
+				fading := tt.fading(m)
+				m.mu.Unlock()
+				if fading {
+					break
+				}
+				if i > 100 {
+					t.Fatal("fade did not start")
+				}
+				time.Sleep(time.Millisecond)
+			}
+
+			// 淡出期间移除调用阻塞，电平从 0.5 逐样本下降
+			var levels []float32
+			for i := 0; i < 2; i++ {
+				m.audioCallback(out)
+				levels = append(levels, out[0]...)
+			}
+			for i := 1; i < len(levels); i++ {
+				if levels[i] >= levels[i-1] || levels[0] > 0.5 {
+					t.Fatalf("expected decreasing levels during fade, got %v", levels)
+				}
+			}
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("remove did not return after fade")
+			}
+			if !tt.removed(m) {
+				t.Fatal("expected stream removed after fade")
+			}
+		})
+	}
+	if got := <-interrupted; !got {
+		t.Fatal("expected resource reported interrupted")
+	}
+}
+
+func TestMixerRemoveWithoutRenderCutsImmediately(t *testing.T) {
+	m := &mixerImpl{config: DefaultMixerConfig(), currentTTSVolume: 1.0, volume: 1.0}
+	m.AddTTSStream(bytes.NewReader(make([]byte, 64)))
+
+	start := time.Now()
+	m.RemoveTTSStream()
+	if m.ttsStream != nil || time.Since(start) > renderIdleTimeout {
+		t.Fatal("expected immediate removal when output stream is not running")
+	}
+}
+
+func TestMixerResourceModes(t *testing.T) {
+	// 每段 4 个样本，正好一帧
+	clip := func(value int16) io.Reader {
+		data := make([]byte, 8)
+		for i := 0; i < 4; i++ {
+			binary.LittleEndian.PutUint16(data[i*2:], uint16(value))
+		}
+		return bytes.NewReader(data)
+	}
+	type finish struct {
+		name        string
+		interrupted bool
+	}
+
+	tests := []struct {
+		name   string
+		play   func(m *mixerImpl, done func(string) func(bool))
+		frames []float32 // 每帧第一个样本的期望值
+		want   []finish
+	}{
+		{
+			name: "queue plays sequentially",
+			play: func(m *mixerImpl, done func(string) func(bool)) {
+				m.EnqueueResourceStream(clip(8192), ResourceOptions{OnFinished: done("a")})
+				m.EnqueueResourceStream(clip(16384), ResourceOptions{OnFinished: done("b")})
+			},
+			// 第 2 帧 a 读到 EOF，b 从下一帧开始
+			frames: []float32{0.25, 0, 0.5, 0},
+			want:   []finish{{"a", false}, {"b", false}},
+		},
+		{
+			name: "preempt stops current and queued",
+			play: func(m *mixerImpl, done func(string) func(bool)) {
+				m.EnqueueResourceStream(clip(8192), ResourceOptions{OnFinished: done("a")})
+				m.EnqueueResourceStream(clip(8192), ResourceOptions{OnFinished: done("b")})
+				m.EnqueueResourceStream(clip(16384), ResourceOptions{Mode: ResourcePreempt, OnFinished: done("c")})
+			},
+			frames: []float32{0.5, 0},
+			want:   []finish{{"a", true}, {"b", true}, {"c", false}},
+		},
+		{
+			name: "mix overlays current",
+			play: func(m *mixerImpl, done func(string) func(bool)) {
+				m.EnqueueResourceStream(clip(8192), ResourceOptions{OnFinished: done("a")})
+				m.EnqueueResourceStream(clip(16384), ResourceOptions{Mode: ResourceMix, OnFinished: done("b")})
+			},
+			frames: []float32{0.75, 0},
+			want:   []finish{{"a", false}, {"b", false}},
+		},
+		{
+			name: "remove interrupts all",
+			play: func(m *mixerImpl, done func(string) func(bool)) {
+				m.EnqueueResourceStream(clip(8192), ResourceOptions{OnFinished: done("a")})
+				m.EnqueueResourceStream(clip(8192), ResourceOptions{OnFinished: done("b")})
+				m.RemoveResourceStream()
+			},
+			frames: []float32{0},
+			want:   []finish{{"a", true}, {"b", true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &mixerImpl{config: DefaultMixerConfig(), currentResourceVolume: 1.0, volume: 1.0}
+			finished := make(chan finish, 8)
+			tt.play(m, func(name string) func(bool) {
+				return func(interrupted bool) { finished <- finish{name, interrupted} }
+			})
+			for i, want := range tt.frames {
+				out := [][]float32{make([]float32, 4), make([]float32, 4)}
+				m.audioCallback(out)
+				if math.Abs(float64(out[0][0]-want)) > 1e-6 {
+					t.Fatalf("frame %d = %f, want %f", i, out[0][0], want)
+				}
+			}
+
+			got := map[string]bool{}
+			for range tt.want {
+				select {
+				case f := <-finished:
+					got[f.name] = f.interrupted
+				case <-time.After(time.Second):
+					t.Fatalf("finished callbacks = %v, want %v", got, tt.want)
+				}
+			}
+			for _, w := range tt.want {
+				if interrupted, ok := got[w.name]; !ok || interrupted != w.interrupted {
+					t.Errorf("%s finished interrupted=%v (reported %v), want %v", w.name, interrupted, ok, w.interrupted)
+				}
+			}
+		})
+	}
+}
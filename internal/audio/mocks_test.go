@@ -55,6 +55,7 @@ func (p *mockTTSProvider) getLastConfig() tts.Config {
 type mockTTSStream struct {
 	mu          sync.Mutex
 	text        string
+	ssml        tts.SSML
 	closed      bool
 	audioData   []byte
 	reader      *mockAudioReader
@@ -96,6 +97,14 @@ func (s *mockTTSStream) WriteTextChunk(ctx context.Context, text string) error {
 	return nil
 }
 
+// WriteSSML 记录 SSML 文档（tts.SSMLWriter），同样生成模拟音频
+func (s *mockTTSStream) WriteSSML(ctx context.Context, doc tts.SSML) error {
+	s.mu.Lock()
+	s.ssml = doc
+	s.mu.Unlock()
+	return s.WriteTextChunk(ctx, string(doc))
+}
+
 func (s *mockTTSStream) Close(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -189,6 +198,7 @@ type mockMixer struct {
 	mu                   sync.Mutex
 	ttsStream            io.Reader
 	resourceStream       io.Reader
+	promptStream         io.Reader
 	ttsStartedCount      int
 	ttsFinishedCount     int
 	addTTSStreamCount    int
@@ -231,6 +241,18 @@ func (m *mockMixer) RemoveResourceStream() {
 	m.resourceStream = nil
 }
 
+func (m *mockMixer) AddPromptStream(audio io.Reader) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.promptStream = audio
+}
+
+func (m *mockMixer) RemovePromptStream() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.promptStream = nil
+}
+
 func (m *mockMixer) SetTTSVolume(volume float64) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -294,6 +316,12 @@ func (m *mockMixer) getTTSStream() io.Reader {
 	return m.ttsStream
 }
 
+func (m *mockMixer) getPromptStream() io.Reader {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.promptStream
+}
+
 // mockReferenceSink 模拟 ReferenceSink
 type mockReferenceSink struct {
 	mu   sync.Mutex
@@ -0,0 +1,477 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+)
+
+// MultiMicStrategy 多麦克风合并策略
+type MultiMicStrategy string
+
+const (
+	// MultiMicSelect 按句选路：每句话开始时选取信噪比最高的声道，句中不切换
+	MultiMicSelect MultiMicStrategy = "select"
+	// MultiMicDelayAndSum 延迟求和：按互相关估计各声道相对第 0 路的时延，对齐后取平均
+	MultiMicDelayAndSum MultiMicStrategy = "delay_sum"
+)
+
+// ParseMultiMicStrategy 解析合并策略名称，空字符串为 MultiMicSelect
+func ParseMultiMicStrategy(name string) (MultiMicStrategy, error) {
+	switch strategy := MultiMicStrategy(strings.ToLower(strings.TrimSpace(name))); strategy {
+	case "":
+		return MultiMicSelect, nil
+	case MultiMicSelect, MultiMicDelayAndSum:
+		return strategy, nil
+	default:
+		return MultiMicSelect, fmt.Errorf("unknown multi-mic strategy %q (want select or delay_sum)", name)
+	}
+}
+
+// MultiMicConfig 多麦克风合并参数
+type MultiMicConfig struct {
+	Strategy   MultiMicStrategy
+	SampleRate int
+	// FrameMs 信噪比与时延估计的分析帧长
+	FrameMs int
+	// SpeechSNRDb 任一声道帧能量高于其噪声底该分贝数时视为有人说话
+	SpeechSNRDb float64
+	// HangoverMs 连续无语音超过该时长视为一句结束，select 策略之后才允许换路
+	HangoverMs int
+	// MaxDelayMs delay_sum 搜索的最大声道间时延，输出随之延迟同样时长；0 表示不对齐直接平均
+	MaxDelayMs float64
+	// MaxSkewMs 多设备输入时允许的最大积压，超过时丢弃最旧的样本（设备时钟漂移）
+	MaxSkewMs int
+}
+
+// DefaultMultiMicConfig 默认按句选路，1ms 时延搜索范围约对应 34cm 的麦克风间距
+func DefaultMultiMicConfig() MultiMicConfig {
+	return MultiMicConfig{
+		Strategy:    MultiMicSelect,
+		SampleRate:  16000,
+		FrameMs:     20,
+		SpeechSNRDb: 10,
+		HangoverMs:  500,
+		MaxDelayMs:  1,
+		MaxSkewMs:   500,
+	}
+}
+
+func normalizeMultiMicConfig(config MultiMicConfig) MultiMicConfig {
+	defaults := DefaultMultiMicConfig()
+	if config.Strategy == "" {
+		config.Strategy = defaults.Strategy
+	}
+	if config.SampleRate <= 0 {
+		config.SampleRate = defaults.SampleRate
+	}
+	if config.FrameMs <= 0 {
+		config.FrameMs = defaults.FrameMs
+	}
+	if config.SpeechSNRDb <= 0 {
+		config.SpeechSNRDb = defaults.SpeechSNRDb
+	}
+	if config.HangoverMs <= 0 {
+		config.HangoverMs = defaults.HangoverMs
+	}
+	if config.MaxDelayMs < 0 {
+		config.MaxDelayMs = 0
+	}
+	if config.MaxSkewMs <= 0 {
+		config.MaxSkewMs = defaults.MaxSkewMs
+	}
+	return config
+}
+
+// MultiMicSource 把多路麦克风合并为单声道的 AudioSource，用于阵列麦克风或双麦克风
+// 输入可以是单个多声道设备（NewArrayMicSource）或多个单声道设备（NewMultiMicSource），均为 16-bit PCM
+type MultiMicSource struct {
+	inputs   micInputs
+	combiner *micCombiner
+}
+
+// micInputs 按声道读取等长的样本
+type micInputs interface {
+	read(ctx context.Context) ([][]int16, error)
+	close() error
+}
+
+// NewArrayMicSource 合并单个多声道设备（交错 PCM）的各声道
+func NewArrayMicSource(source AudioSource, channels int, config MultiMicConfig) (*MultiMicSource, error) {
+	if source == nil {
+		return nil, errors.New("array mic source requires a source")
+	}
+	if channels < 2 {
+		return nil, fmt.Errorf("array mic source requires at least 2 channels, got %d", channels)
+	}
+	config = normalizeMultiMicConfig(config)
+	return &MultiMicSource{
+		inputs:   &interleavedInputs{source: source, channels: channels},
+		combiner: newMicCombiner(config, channels),
+	}, nil
+}
+
+// NewMultiMicSource 合并多个同采样率的单声道输入（如两个 USB 麦克风），各输入在后台并行读取并按样本数对齐
+func NewMultiMicSource(sources []AudioSource, config MultiMicConfig) (*MultiMicSource, error) {
+	if len(sources) < 2 {
+		return nil, fmt.Errorf("multi mic source requires at least 2 sources, got %d", len(sources))
+	}
+	for i, source := range sources {
+		if source == nil {
+			return nil, fmt.Errorf("multi mic source %d is nil", i)
+		}
+	}
+	config = normalizeMultiMicConfig(config)
+	return &MultiMicSource{
+		inputs:   newDeviceInputs(sources, config.SampleRate*config.MaxSkewMs/1000),
+		combiner: newMicCombiner(config, len(sources)),
+	}, nil
+}
+
+func (s *MultiMicSource) Read(ctx context.Context) ([]byte, error) {
+	for {
+		channels, err := s.inputs.read(ctx)
+		if err != nil {
+			return nil, err
+		}
+		mono := s.combiner.process(channels)
+		if len(mono) == 0 {
+			continue
+		}
+		out := make([]byte, len(mono)*2)
+		int16ToBytes(mono, out)
+		return out, nil
+	}
+}
+
+func (s *MultiMicSource) Close() error {
+	return s.inputs.close()
+}
+
+// SelectedChannel select 策略当前选用的声道
+func (s *MultiMicSource) SelectedChannel() int {
+	return s.combiner.selectedChannel()
+}
+
+// interleavedInputs 从多声道交错 PCM 拆出各声道，不完整的帧留到下次
+type interleavedInputs struct {
+	source   AudioSource
+	channels int
+	pending  []int16
+}
+
+func (in *interleavedInputs) read(ctx context.Context) ([][]int16, error) {
+	for {
+		data, err := in.source.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+		in.pending = append(in.pending, bytesToInt16(data)...)
+		frames := len(in.pending) / in.channels
+		if frames == 0 {
+			continue
+		}
+		out := make([][]int16, in.channels)
+		for c := range out {
+			out[c] = selectChannel(in.pending[:frames*in.channels], in.channels, c)
+		}
+		in.pending = append(in.pending[:0], in.pending[frames*in.channels:]...)
+		return out, nil
+	}
+}
+
+func (in *interleavedInputs) close() error {
+	return in.source.Close()
+}
+
+// deviceInputs 并行读取多个设备，每次取各路都已到达的样本
+type deviceInputs struct {
+	sources    []AudioSource
+	maxPending int
+
+	startOnce sync.Once
+	ctx       context.Context
+	cancel    context.CancelFunc
+	notify    chan struct{}
+
+	mu      sync.Mutex
+	pending [][]int16
+	errs    []error // 各设备读取结束的原因
+}
+
+func newDeviceInputs(sources []AudioSource, maxPending int) *deviceInputs {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &deviceInputs{
+		sources:    sources,
+		maxPending: maxPending,
+		ctx:        ctx,
+		cancel:     cancel,
+		notify:     make(chan struct{}, 1),
+		pending:    make([][]int16, len(sources)),
+		errs:       make([]error, len(sources)),
+	}
+}
+
+func (in *deviceInputs) start() {
+	for i, source := range in.sources {
+		go in.readLoop(i, source)
+	}
+}
+
+func (in *deviceInputs) readLoop(index int, source AudioSource) {
+	for {
+		data, err := source.Read(in.ctx)
+		in.mu.Lock()
+		if err != nil {
+			in.errs[index] = fmt.Errorf("mic %d: %w", index, err)
+			in.mu.Unlock()
+			in.signal()
+			return
+		}
+		in.pending[index] = append(in.pending[index], bytesToInt16(data)...)
+		// 其他设备迟迟不出数据（或时钟偏快）导致积压时丢弃最旧的样本，避免延迟无限增长
+		if over := len(in.pending[index]) - in.maxPending; in.maxPending > 0 && over > 0 {
+			in.pending[index] = append(in.pending[index][:0], in.pending[index][over:]...)
+		}
+		in.mu.Unlock()
+		in.signal()
+	}
+}
+
+func (in *deviceInputs) signal() {
+	select {
+	case in.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (in *deviceInputs) read(ctx context.Context) ([][]int16, error) {
+	in.startOnce.Do(in.start)
+	for {
+		in.mu.Lock()
+		n := len(in.pending[0])
+		for _, samples := range in.pending[1:] {
+			n = min(n, len(samples))
+		}
+		if n > 0 {
+			out := make([][]int16, len(in.pending))
+			for i, samples := range in.pending {
+				out[i] = append([]int16(nil), samples[:n]...)
+				in.pending[i] = append(samples[:0], samples[n:]...)
+			}
+			in.mu.Unlock()
+			return out, nil
+		}
+		// 某路已结束且没有剩余样本时无法再对齐，其他设备的剩余数据丢弃
+		var err error
+		for i, samples := range in.pending {
+			if in.errs[i] != nil && len(samples) == 0 {
+				err = in.errs[i]
+				break
+			}
+		}
+		in.mu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-in.notify:
+		}
+	}
+}
+
+func (in *deviceInputs) close() error {
+	in.cancel()
+	var errs []error
+	for _, source := range in.sources {
+		errs = append(errs, source.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// noiseFloorRise 噪声底每帧最多上升的倍数（20ms 帧约 1dB/s），说话期间噪声底基本不变
+const noiseFloorRise = 1.005
+
+// micCombiner 按帧分析各声道的信噪比，按策略合成单声道
+type micCombiner struct {
+	config         MultiMicConfig
+	channels       int
+	frame          int
+	hangoverFrames int
+	minSNR         float64 // SpeechSNRDb 对应的能量比
+
+	noise  []float64 // 各声道噪声底（帧均方能量）
+	snr    []float64
+	silent int // 句中连续无语音的帧数
+
+	mu       sync.Mutex
+	speaking bool
+	selected int
+
+	// delay_sum
+	maxLag int
+	delays []int     // 各声道相对第 0 路的时延（样本数）
+	hist   [][]int16 // 各声道最近 2*maxLag 个样本
+}
+
+func newMicCombiner(config MultiMicConfig, channels int) *micCombiner {
+	frame := max(config.SampleRate*config.FrameMs/1000, 1)
+	c := &micCombiner{
+		config:         config,
+		channels:       channels,
+		frame:          frame,
+		hangoverFrames: max(config.HangoverMs/config.FrameMs, 1),
+		minSNR:         math.Pow(10, config.SpeechSNRDb/10),
+		noise:          make([]float64, channels),
+		snr:            make([]float64, channels),
+		delays:         make([]int, channels),
+		hist:           make([][]int16, channels),
+	}
+	if config.Strategy == MultiMicDelayAndSum {
+		c.maxLag = int(config.MaxDelayMs * float64(config.SampleRate) / 1000)
+		for i := range c.hist {
+			c.hist[i] = make([]int16, 2*c.maxLag)
+		}
+	}
+	return c
+}
+
+func (c *micCombiner) selectedChannel() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.selected
+}
+
+// process 合成等长的各声道样本
+func (c *micCombiner) process(channels [][]int16) []int16 {
+	n := len(channels[0])
+	out := make([]int16, 0, n)
+	frame := make([][]int16, c.channels)
+	for start := 0; start < n; start += c.frame {
+		end := min(start+c.frame, n)
+		for i := range frame {
+			frame[i] = channels[i][start:end]
+		}
+		speech := c.analyze(frame)
+		if c.config.Strategy == MultiMicDelayAndSum {
+			out = append(out, c.delayAndSum(frame, speech)...)
+		} else {
+			out = append(out, frame[c.selected]...)
+		}
+	}
+	return out
+}
+
+// analyze 更新各声道噪声底与信噪比，按句维护选路，返回本帧是否有人说话
+func (c *micCombiner) analyze(frame [][]int16) bool {
+	best := 0
+	for i, samples := range frame {
+		energy := meanSquare(samples)
+		if c.noise[i] == 0 || energy < c.noise[i] {
+			c.noise[i] = max(energy, 1)
+		} else {
+			c.noise[i] = min(energy, c.noise[i]*noiseFloorRise)
+		}
+		c.snr[i] = energy / c.noise[i]
+		if c.snr[i] > c.snr[best] {
+			best = i
+		}
+	}
+	speech := c.snr[best] >= c.minSNR
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch {
+	case speech && !c.speaking:
+		// 一句话开始时选路，句中不切换以免音色跳变
+		c.speaking = true
+		c.silent = 0
+		c.selected = best
+	case speech:
+		c.silent = 0
+	case c.speaking:
+		c.silent++
+		if c.silent >= c.hangoverFrames {
+			c.speaking = false
+		}
+	}
+	return speech
+}
+
+// delayAndSum 对齐各声道后取平均，输出整体延迟 maxLag 个样本；有人说话时重新估计时延
+func (c *micCombiner) delayAndSum(frame [][]int16, speech bool) []int16 {
+	n := len(frame[0])
+	lookback := 2 * c.maxLag
+	full := make([][]int16, c.channels)
+	for i := range full {
+		full[i] = append(append(make([]int16, 0, lookback+n), c.hist[i]...), frame[i]...)
+	}
+
+	if speech && c.maxLag > 0 {
+		for i := 1; i < c.channels; i++ {
+			if lag, ok := estimateLag(full[0], full[i], c.maxLag, n); ok {
+				c.delays[i] = lag
+			}
+		}
+	}
+
+	out := make([]int16, n)
+	for k := range out {
+		p := k + c.maxLag
+		sum := 0
+		for i := range full {
+			sum += int(full[i][p+c.delays[i]])
+		}
+		out[k] = int16(sum / c.channels)
+	}
+
+	for i := range c.hist {
+		copy(c.hist[i], full[i][len(full[i])-lookback:])
+	}
+	return out
+}
+
+// minLagCorrelation 采纳时延估计所需的最小归一化互相关，过低时多为扩散噪声，沿用上次的估计
+const minLagCorrelation = 0.3
+
+// estimateLag 在 [-maxLag, maxLag] 内搜索使 other[p+lag] 与 ref[p] 互相关最大的 lag，
+// p 取 ref 中当前帧的位置 [maxLag, maxLag+n)
+func estimateLag(ref, other []int16, maxLag, n int) (int, bool) {
+	var refEnergy float64
+	for p := maxLag; p < maxLag+n; p++ {
+		refEnergy += float64(ref[p]) * float64(ref[p])
+	}
+	bestLag, bestScore := 0, 0.0
+	for lag := -maxLag; lag <= maxLag; lag++ {
+		var corr, energy float64
+		for p := maxLag; p < maxLag+n; p++ {
+			v := float64(other[p+lag])
+			corr += float64(ref[p]) * v
+			energy += v * v
+		}
+		if refEnergy == 0 || energy == 0 {
+			continue
+		}
+		if score := corr / math.Sqrt(refEnergy*energy); score > bestScore {
+			bestLag, bestScore = lag, score
+		}
+	}
+	return bestLag, bestScore >= minLagCorrelation
+}
+
+// meanSquare 帧均方能量
+func meanSquare(samples []int16) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range samples {
+		sum += float64(v) * float64(v)
+	}
+	return sum / float64(len(samples))
+}
@@ -0,0 +1,196 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// chunkSource 依次返回预先切好的数据块，读完后返回 io.EOF
+type chunkSource struct {
+	chunks [][]byte
+}
+
+func (s *chunkSource) Read(ctx context.Context) ([]byte, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *chunkSource) Close() error {
+	return nil
+}
+
+// randomSignal 生成幅度为 amplitude 的随机信号
+func randomSignal(rng *rand.Rand, n int, amplitude int) []int16 {
+	out := make([]int16, n)
+	for i := range out {
+		out[i] = int16(rng.Intn(2*amplitude+1) - amplitude)
+	}
+	return out
+}
+
+func scaleSamples(samples []int16, gain float64) []int16 {
+	out := make([]int16, len(samples))
+	for i, v := range samples {
+		out[i] = int16(float64(v) * gain)
+	}
+	return out
+}
+
+// interleave 把各声道交错并按 frame 个样本切块
+func interleave(channels [][]int16, frame int) [][]byte {
+	var chunks [][]byte
+	for start := 0; start < len(channels[0]); start += frame {
+		end := min(start+frame, len(channels[0]))
+		samples := make([]int16, 0, (end-start)*len(channels))
+		for i := start; i < end; i++ {
+			for _, channel := range channels {
+				samples = append(samples, channel[i])
+			}
+		}
+		chunks = append(chunks, s16Bytes(samples...))
+	}
+	return chunks
+}
+
+func readAllSamples(t *testing.T, source AudioSource) []int16 {
+	t.Helper()
+	var got []int16
+	for {
+		data, err := source.Read(context.Background())
+		if errors.Is(err, io.EOF) {
+			return got
+		}
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		got = append(got, bytesToInt16(data)...)
+	}
+}
+
+func TestParseMultiMicStrategy(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    MultiMicStrategy
+		wantErr bool
+	}{
+		{"", MultiMicSelect, false},
+		{"select", MultiMicSelect, false},
+		{" Delay_Sum ", MultiMicDelayAndSum, false},
+		{"mvdr", MultiMicSelect, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMultiMicStrategy(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMultiMicStrategy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMultiMicStrategy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArrayMicSelectPerUtterance(t *testing.T) {
+	const frame = 320 // 20ms @ 16kHz
+	rng := rand.New(rand.NewSource(1))
+	var left, right []int16
+	appendSegment := func(l, r []int16) {
+		left = append(left, l...)
+		right = append(right, r...)
+	}
+
+	// 静音 → 第一句右声道更近 → 静音超过 hangover → 第二句左声道更近，句中右声道变响也不换路
+	appendSegment(randomSignal(rng, 10*frame, 20), randomSignal(rng, 10*frame, 20))
+	first := randomSignal(rng, 10*frame, 8000)
+	appendSegment(scaleSamples(first, 0.3), first)
+	appendSegment(randomSignal(rng, 40*frame, 20), randomSignal(rng, 40*frame, 20))
+	second := randomSignal(rng, 10*frame, 8000)
+	appendSegment(second[:5*frame], scaleSamples(second[:5*frame], 0.3))
+	appendSegment(scaleSamples(second[5*frame:], 0.3), second[5*frame:])
+
+	source, err := NewArrayMicSource(&chunkSource{chunks: interleave([][]int16{left, right}, frame)}, 2, DefaultMultiMicConfig())
+	if err != nil {
+		t.Fatalf("NewArrayMicSource() error = %v", err)
+	}
+	got := readAllSamples(t, source)
+	if len(got) != len(left) {
+		t.Fatalf("got %d samples, want %d", len(got), len(left))
+	}
+	if seg := got[10*frame : 20*frame]; !reflect.DeepEqual(seg, right[10*frame:20*frame]) {
+		t.Error("first utterance should use the right channel")
+	}
+	if seg := got[60*frame:]; !reflect.DeepEqual(seg, left[60*frame:]) {
+		t.Error("second utterance should stay on the left channel")
+	}
+	if source.SelectedChannel() != 0 {
+		t.Errorf("SelectedChannel() = %d, want 0", source.SelectedChannel())
+	}
+}
+
+func TestArrayMicDelayAndSum(t *testing.T) {
+	const (
+		frame = 320
+		delay = 5
+	)
+	rng := rand.New(rand.NewSource(2))
+	signal := append(randomSignal(rng, 10*frame, 20), randomSignal(rng, 20*frame, 8000)...)
+	// 右声道比左声道晚 delay 个样本到达
+	delayed := append(make([]int16, delay), signal[:len(signal)-delay]...)
+
+	config := DefaultMultiMicConfig()
+	config.Strategy = MultiMicDelayAndSum
+	source, err := NewArrayMicSource(&chunkSource{chunks: interleave([][]int16{signal, delayed}, frame)}, 2, config)
+	if err != nil {
+		t.Fatalf("NewArrayMicSource() error = %v", err)
+	}
+	got := readAllSamples(t, source)
+	if len(got) != len(signal) {
+		t.Fatalf("got %d samples, want %d", len(got), len(signal))
+	}
+	// 对齐后两路相同，平均结果即为整体延迟 maxLag 的原信号
+	maxLag := 16
+	for i := 20 * frame; i < len(got); i++ {
+		if got[i] != signal[i-maxLag] {
+			t.Fatalf("sample %d = %d, want %d", i, got[i], signal[i-maxLag])
+		}
+	}
+}
+
+func TestMultiMicSourceDevices(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	signal := randomSignal(rng, 4000, 1000)
+	pcm := s16Bytes(signal...)
+	split := func(size int) [][]byte {
+		var chunks [][]byte
+		for start := 0; start < len(pcm); start += size {
+			chunks = append(chunks, pcm[start:min(start+size, len(pcm))])
+		}
+		return chunks
+	}
+
+	// 两个设备每次读取的长度不同，按样本数对齐后合并
+	source, err := NewMultiMicSource([]AudioSource{
+		&chunkSource{chunks: split(640)},
+		&chunkSource{chunks: split(1000)},
+	}, DefaultMultiMicConfig())
+	if err != nil {
+		t.Fatalf("NewMultiMicSource() error = %v", err)
+	}
+	defer source.Close()
+	if got := readAllSamples(t, source); !reflect.DeepEqual(got, signal) {
+		t.Errorf("got %d samples, want the %d input samples", len(got), len(signal))
+	}
+
+	if _, err := NewMultiMicSource([]AudioSource{&chunkSource{}}, DefaultMultiMicConfig()); err == nil {
+		t.Error("NewMultiMicSource(1 source) expected error")
+	}
+}
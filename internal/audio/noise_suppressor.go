@@ -0,0 +1,154 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// NoiseSuppressionConfig 谱减降噪配置
+type NoiseSuppressionConfig struct {
+	Enabled  bool
+	Strength float64 // 过减因子，越大降噪越强、失真越明显
+	Floor    float64 // 频谱增益下限（0~1），避免“音乐噪声”
+}
+
+// DefaultNoiseSuppressionConfig 默认降噪配置
+func DefaultNoiseSuppressionConfig() NoiseSuppressionConfig {
+	return NoiseSuppressionConfig{
+		Strength: 2.0,
+		Floor:    0.1,
+	}
+}
+
+const (
+	// noiseRiseRate 噪声估计在无更小值时每帧缓慢上升的比例，用于跟踪变化的底噪
+	noiseRiseRate = 0.002
+	// powerSmoothing 功率谱的帧间平滑系数
+	powerSmoothing = 0.8
+	// noiseBiasCompensation 最小值跟踪会低估噪声均值，谱减时乘以该系数补偿
+	noiseBiasCompensation = 2.5
+)
+
+// noiseSuppressor 基于最小值跟踪的谱减降噪
+// 采用 sqrt-Hann 窗、50% 重叠的 STFT，输出比输入延迟一个 hop
+type noiseSuppressor struct {
+	config NoiseSuppressionConfig
+	size   int // FFT 长度
+	hop    int
+	window []float64
+
+	pending []float64 // 未凑满一个 hop 的输入
+	history []float64 // 上一个 hop 的输入
+	overlap []float64 // 重叠相加的尾部
+	output  []float64 // 已处理、待输出的采样
+
+	spectrum    []complex128
+	smoothPower []float64
+	noise       []float64
+	primed      bool
+}
+
+func newNoiseSuppressor(config NoiseSuppressionConfig, sampleRate int) *noiseSuppressor {
+	defaults := DefaultNoiseSuppressionConfig()
+	if config.Strength <= 0 {
+		config.Strength = defaults.Strength
+	}
+	if config.Floor <= 0 || config.Floor >= 1 {
+		config.Floor = defaults.Floor
+	}
+
+	// 约 16ms 一帧（16kHz 下 256 点）
+	size := nextPowerOfTwo(sampleRate * 16 / 1000)
+	if size < 64 {
+		size = 64
+	}
+	hop := size / 2
+
+	window := make([]float64, size)
+	for i := range window {
+		// 周期 Hann 窗开方，分析与合成各乘一次，50% 重叠时可完全重建
+		window[i] = math.Sqrt(0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(size)))
+	}
+
+	bins := size/2 + 1
+	return &noiseSuppressor{
+		config:      config,
+		size:        size,
+		hop:         hop,
+		window:      window,
+		history:     make([]float64, hop),
+		overlap:     make([]float64, hop),
+		output:      make([]float64, hop), // 预置一个 hop 的静音，保证输出长度与输入一致
+		spectrum:    make([]complex128, size),
+		smoothPower: make([]float64, bins),
+		noise:       make([]float64, bins),
+	}
+}
+
+// Process 原地处理归一化采样，输出长度与输入相同
+func (n *noiseSuppressor) Process(samples []float64) {
+	n.pending = append(n.pending, samples...)
+	for len(n.pending) >= n.hop {
+		n.processHop(n.pending[:n.hop])
+		n.pending = n.pending[n.hop:]
+	}
+	n.pending = append([]float64(nil), n.pending...)
+
+	copy(samples, n.output[:len(samples)])
+	n.output = append(n.output[:0], n.output[len(samples):]...)
+}
+
+func (n *noiseSuppressor) processHop(hop []float64) {
+	for i := 0; i < n.hop; i++ {
+		n.spectrum[i] = complex(n.history[i]*n.window[i], 0)
+		n.spectrum[n.hop+i] = complex(hop[i]*n.window[n.hop+i], 0)
+	}
+	copy(n.history, hop)
+
+	fft(n.spectrum, false)
+	n.applyGain()
+	fft(n.spectrum, true)
+
+	for i := 0; i < n.hop; i++ {
+		n.output = append(n.output, n.overlap[i]+real(n.spectrum[i])*n.window[i])
+		n.overlap[i] = real(n.spectrum[n.hop+i]) * n.window[n.hop+i]
+	}
+}
+
+// applyGain 更新噪声估计并对每个频点做谱减
+func (n *noiseSuppressor) applyGain() {
+	bins := len(n.noise)
+	for k := 0; k < bins; k++ {
+		power := sqAbs(n.spectrum[k])
+		if !n.primed {
+			n.smoothPower[k] = power
+			n.noise[k] = power
+		} else {
+			n.smoothPower[k] = powerSmoothing*n.smoothPower[k] + (1-powerSmoothing)*power
+			if n.smoothPower[k] < n.noise[k] {
+				n.noise[k] = n.smoothPower[k]
+			} else {
+				n.noise[k] += n.noise[k] * noiseRiseRate
+			}
+		}
+
+		gain := 1.0
+		if power > 0 {
+			gain = math.Sqrt(math.Max(0, 1-n.config.Strength*noiseBiasCompensation*n.noise[k]/power))
+		}
+		if gain < n.config.Floor {
+			gain = n.config.Floor
+		}
+
+		n.spectrum[k] *= complex(gain, 0)
+		if k > 0 && k < n.size-k {
+			// 实信号频谱共轭对称
+			n.spectrum[n.size-k] = cmplx.Conj(n.spectrum[k])
+		}
+	}
+	n.primed = true
+}
+
+func sqAbs(c complex128) float64 {
+	return real(c)*real(c) + imag(c)*imag(c)
+}
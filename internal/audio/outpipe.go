@@ -24,12 +24,53 @@ type AudioOutPipe interface {
 	Stats() PipelineStats
 }
 
+// ResourcePlayer 可选接口：按选项播放资源音频；PlayResource 相当于 ResourceQueue 模式
+type ResourcePlayer interface {
+	PlayResourceWithOptions(audio io.Reader, opts ResourceOptions) error
+}
+
+// LanguageTTSPlayer 可选接口：按回复语言选择音色播放 TTS（见 VoiceKey）
+type LanguageTTSPlayer interface {
+	PlayTTSWithLanguage(text, emotion, language string) error
+}
+
+// ContextTTSPlayer 可选接口：与 PlayTTSWithLanguage 相同，ctx 中的 trace / 轮次 ID（logging.WithTurn）
+// 会带到该句的合成与播放日志中；ctx 不用于取消，打断仍通过 Interrupt
+type ContextTTSPlayer interface {
+	PlayTTSContext(ctx context.Context, text, emotion, language string) error
+}
+
+// AudioClipPlayer 可选接口：把预先合成的音频（Mixer 格式：单声道 16-bit PCM）作为一句排入 TTS 播放队列，
+// 与 TTS 句子一样按序播放、可被打断并回调播放完成；用于重播缓存的回复
+type AudioClipPlayer interface {
+	PlayAudioClip(ctx context.Context, text string, pcm []byte) error
+}
+
+// PrioritySpeechPlayer 可选接口：按 SpeechOptions 排入 TTS 播放队列，紧急播报插到普通回复之前，
+// Preempt 时丢弃正在播放的普通句子；opts.Audio 非空时播放预合成音频而不调用 TTS。紧急播报不回调播放完成
+type PrioritySpeechPlayer interface {
+	PlayTTSWithOptions(ctx context.Context, text string, opts SpeechOptions) error
+}
+
+// VoiceKey 返回 VoiceMap 中按语言区分的键，如 VoiceKey("happy", "en") 为 "happy:en"
+// 选择音色时依次查找 "情绪:语言"、"default:语言"、"情绪"、"default"
+func VoiceKey(emotion, language string) string {
+	if language == "" {
+		return emotion
+	}
+	return emotion + ":" + language
+}
+
 // OutPipeConfig OutPipe配置
 type OutPipeConfig struct {
 	Mixer       *MixerConfig
 	TTS         tts.Config
 	TTSPipeline *TTSPipelineConfig
 	VoiceMap    map[string]string
+	// EmotionProsody 按情绪调整语速、音调与音量，键与 VoiceMap 相同，为 0 的字段沿用 TTS 配置
+	EmotionProsody map[string]tts.Prosody
+	// SSML SSML 生成配置，仅在 TTS.EnableSSML 为 true 时使用，为 nil 时使用默认配置
+	SSML *tts.SSMLConfig
 }
 
 // DefaultOutPipeConfig 默认配置
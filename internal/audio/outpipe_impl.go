@@ -2,6 +2,7 @@ package audio
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"sync"
@@ -18,6 +19,7 @@ type outPipeImpl struct {
 	mixerConfig *MixerConfig
 	voiceMap    map[string]string
 	ttsConfig   tts.Config
+	onLevel     LevelCallback
 	ctx         context.Context
 	cancel      context.CancelFunc
 	mu          sync.Mutex
@@ -65,6 +67,22 @@ func NewOutPipeWithConfig(cfg *OutPipeConfig) AudioOutPipe {
 		mixerConfig,
 	)
 
+	if len(cfg.EmotionProsody) > 0 {
+		if impl, ok := pipeline.(*ttsPipelineImpl); ok {
+			impl.SetEmotionProsody(cfg.EmotionProsody)
+		}
+	}
+
+	if cfg.TTS.EnableSSML {
+		ssmlConfig := tts.DefaultSSMLConfig()
+		if cfg.SSML != nil {
+			ssmlConfig = *cfg.SSML
+		}
+		if impl, ok := pipeline.(*ttsPipelineImpl); ok {
+			impl.SetSSMLBuilder(tts.NewSSMLBuilder(ssmlConfig))
+		}
+	}
+
 	return &outPipeImpl{
 		pipeline:    pipeline,
 		voiceMap:    voiceMap,
@@ -113,6 +131,20 @@ func (p *outPipeImpl) SetMixer(mixer AudioMixer) {
 
 	p.mixer = mixer
 	p.pipeline.SetMixer(mixer)
+	if reporter, ok := mixer.(LevelReporter); ok && p.onLevel != nil {
+		reporter.SetOnLevel(p.onLevel)
+	}
+}
+
+// SetOnLevel 设置输出电平回调，转发给 Mixer（Mixer 不支持时忽略）
+func (p *outPipeImpl) SetOnLevel(callback LevelCallback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.onLevel = callback
+	if reporter, ok := p.mixer.(LevelReporter); ok {
+		reporter.SetOnLevel(callback)
+	}
 }
 
 func (p *outPipeImpl) SetReferenceSink(sink ReferenceSink) {
@@ -129,6 +161,36 @@ func (p *outPipeImpl) SetOnPlaybackFinished(callback PlaybackFinishedCallback) {
 	p.pipeline.SetOnPlaybackFinished(callback)
 }
 
+// SetOnTTSTiming 设置每句 TTS 开始播放时的时间点回调
+func (p *outPipeImpl) SetOnTTSTiming(callback TTSTimingCallback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if reporter, ok := p.pipeline.(TTSTimingReporter); ok {
+		reporter.SetOnTTSTiming(callback)
+	}
+}
+
+// SetOnTTSError 设置单句 TTS 合成失败回调
+func (p *outPipeImpl) SetOnTTSError(callback TTSErrorCallback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if reporter, ok := p.pipeline.(TTSErrorReporter); ok {
+		reporter.SetOnTTSError(callback)
+	}
+}
+
+// SetOnTTSAudio 设置已完整播放的 TTS 音频回调
+func (p *outPipeImpl) SetOnTTSAudio(callback TTSAudioCallback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if recorder, ok := p.pipeline.(TTSAudioRecorder); ok {
+		recorder.SetOnTTSAudio(callback)
+	}
+}
+
 // PlayTTS 播放 TTS（异步，立即返回）
 // 文本会被加入队列，由 TTSPipeline 异步处理
 func (p *outPipeImpl) PlayTTS(text string, emotion string) error {
@@ -143,8 +205,76 @@ func (p *outPipeImpl) PlayTTS(text string, emotion string) error {
 	return p.pipeline.EnqueueText(text, emotion)
 }
 
-// PlayResource 播放资源音频
+// PlayTTSWithLanguage 播放 TTS，并按回复语言选择音色
+func (p *outPipeImpl) PlayTTSWithLanguage(text, emotion, language string) error {
+	if text == "" {
+		return nil
+	}
+	pipeline, ok := p.pipeline.(interface {
+		EnqueueTextWithLanguage(text, emotion, language string) error
+	})
+	if !ok {
+		return p.PlayTTS(text, emotion)
+	}
+
+	logging.Infof("AudioOutPipe: PlayTTS (async) - text: %.50s..., emotion: %s, language: %s",
+		truncateForLog(text, 50), emotion, language)
+	return pipeline.EnqueueTextWithLanguage(text, emotion, language)
+}
+
+// PlayTTSContext 播放 TTS，ctx 中的 trace / 轮次 ID 带到该句的日志中
+func (p *outPipeImpl) PlayTTSContext(ctx context.Context, text, emotion, language string) error {
+	if text == "" {
+		return nil
+	}
+	pipeline, ok := p.pipeline.(interface {
+		EnqueueTextContext(ctx context.Context, text, emotion, language string) error
+	})
+	if !ok {
+		return p.PlayTTSWithLanguage(text, emotion, language)
+	}
+
+	logging.InfofCtx(ctx, "AudioOutPipe: PlayTTS (async) - text: %.50s..., emotion: %s, language: %s",
+		truncateForLog(text, 50), emotion, language)
+	return pipeline.EnqueueTextContext(ctx, text, emotion, language)
+}
+
+// PlayAudioClip 把预先合成的音频排入 TTS 播放队列，TTSPipeline 不支持时返回错误
+func (p *outPipeImpl) PlayAudioClip(ctx context.Context, text string, pcm []byte) error {
+	pipeline, ok := p.pipeline.(interface {
+		EnqueueAudioContext(ctx context.Context, text string, pcm []byte) error
+	})
+	if !ok {
+		return errors.New("AudioOutPipe: TTSPipeline does not support audio clips")
+	}
+
+	logging.InfofCtx(ctx, "AudioOutPipe: PlayAudioClip (async) - text: %.50s..., %d bytes",
+		truncateForLog(text, 50), len(pcm))
+	return pipeline.EnqueueAudioContext(ctx, text, pcm)
+}
+
+// PlayTTSWithOptions 按优先级排入 TTS 播放队列，TTSPipeline 不支持时返回错误
+func (p *outPipeImpl) PlayTTSWithOptions(ctx context.Context, text string, opts SpeechOptions) error {
+	pipeline, ok := p.pipeline.(interface {
+		EnqueueWithOptions(ctx context.Context, text string, opts SpeechOptions) error
+	})
+	if !ok {
+		return errors.New("AudioOutPipe: TTSPipeline does not support speech options")
+	}
+
+	logging.InfofCtx(ctx, "AudioOutPipe: PlayTTSWithOptions (async) - text: %.50s..., priority: %d, preempt: %v",
+		truncateForLog(text, 50), opts.Priority, opts.Preempt)
+	return pipeline.EnqueueWithOptions(ctx, text, opts)
+}
+
+// PlayResource 播放资源音频，正在播放其他资源时排队
 func (p *outPipeImpl) PlayResource(audio io.Reader) error {
+	return p.PlayResourceWithOptions(audio, ResourceOptions{Mode: ResourceQueue})
+}
+
+// PlayResourceWithOptions 按选项排队、抢占或叠加播放资源音频
+// Mixer 未实现 ResourceQueuer 时直接替换当前资源，且不会回调 OnFinished
+func (p *outPipeImpl) PlayResourceWithOptions(audio io.Reader, opts ResourceOptions) error {
 	p.mu.Lock()
 	mixer := p.mixer
 	p.mu.Unlock()
@@ -153,11 +283,26 @@ func (p *outPipeImpl) PlayResource(audio io.Reader) error {
 		return fmt.Errorf("AudioOutPipe: mixer not set")
 	}
 
-	logging.Infof("AudioOutPipe: adding resource stream to mixer...")
-	mixer.AddResourceStream(audio)
+	queuer, ok := mixer.(ResourceQueuer)
+	if !ok {
+		logging.Infof("AudioOutPipe: adding resource stream to mixer...")
+		opts = wavResourceOptions(audio, opts)
+		mixer.AddResourceStream(NewMixerStreamReader(audio, opts.SampleRate, opts.Channels, opts.Format, p.mixerSampleRate()))
+		return nil
+	}
+	logging.Infof("AudioOutPipe: enqueueing resource stream (mode %d)...", opts.Mode)
+	queuer.EnqueueResourceStream(audio, opts)
 	return nil
 }
 
+// mixerSampleRate Mixer 输出采样率，未配置时为 16000
+func (p *outPipeImpl) mixerSampleRate() int {
+	if p.mixerConfig.SampleRate > 0 {
+		return p.mixerConfig.SampleRate
+	}
+	return 16000
+}
+
 // Interrupt 中断所有任务（清空队列、停止播放）
 func (p *outPipeImpl) Interrupt() error {
 	logging.Infof("AudioOutPipe: interrupting...")
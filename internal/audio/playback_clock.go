@@ -0,0 +1,51 @@
+package audio
+
+import "time"
+
+const (
+	// playbackResyncGap 墙钟与采样时钟的偏差超过该值（欠载、输出流暂停后恢复）时重新锚定
+	playbackResyncGap = 100 * time.Millisecond
+	// playbackSmoothing 每次回调按偏差的 1/playbackSmoothing 修正锚点，平滑回调抖动的同时跟踪设备时钟漂移
+	playbackSmoothing = 32
+)
+
+// playbackClock 按已渲染的帧数推算播放时间：首次回调时以墙钟为锚点，之后按采样率累加，
+// 不受回调调度抖动影响；非并发安全，由调用方加锁
+type playbackClock struct {
+	sampleRate   int
+	frames       int64
+	anchor       time.Time
+	anchorFrames int64
+	position     PlaybackPosition
+}
+
+func newPlaybackClock(sampleRate int) *playbackClock {
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+	return &playbackClock{sampleRate: sampleRate}
+}
+
+// advance 记录一次渲染 frames 帧的音频回调，now 为回调时间；返回本次缓冲的播放进度
+func (c *playbackClock) advance(frames int, now time.Time) PlaybackPosition {
+	if c.anchor.IsZero() {
+		c.anchor, c.anchorFrames = now, c.frames
+	}
+	estimated := c.anchor.Add(framesDuration(c.frames-c.anchorFrames, c.sampleRate))
+	drift := now.Sub(estimated)
+	if drift > playbackResyncGap || drift < -playbackResyncGap {
+		c.anchor, c.anchorFrames = now, c.frames
+		estimated = now
+	} else {
+		c.anchor = c.anchor.Add(drift / playbackSmoothing)
+		estimated = estimated.Add(drift / playbackSmoothing)
+	}
+	c.position = PlaybackPosition{Frames: c.frames, Time: estimated}
+	c.frames += int64(frames)
+	return c.position
+}
+
+// framesDuration frames 帧在 sampleRate 下的时长
+func framesDuration(frames int64, sampleRate int) time.Duration {
+	return time.Duration(frames) * time.Second / time.Duration(sampleRate)
+}
@@ -0,0 +1,45 @@
+package audio
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPlaybackClockAdvance(t *testing.T) {
+	clock := newPlaybackClock(16000)
+	start := time.Unix(100, 0)
+
+	tests := []struct {
+		name   string
+		now    time.Duration // 回调时间（相对 start）
+		frames int64         // 期望的已渲染帧数
+		want   time.Duration // 期望的播放时间（相对 start）
+	}{
+		{"first callback anchors", 0, 0, 0},
+		{"on time", 10 * time.Millisecond, 160, 10 * time.Millisecond},
+		// 回调晚到 3.2ms：只修正 1/32，播放时间按采样时钟推进
+		{"jitter smoothed", 23200 * time.Microsecond, 320, 20100 * time.Microsecond},
+		// 输出流暂停后恢复：偏差过大，重新锚定
+		{"resync after gap", time.Second, 480, time.Second},
+		{"after resync", time.Second + 10*time.Millisecond, 640, time.Second + 10*time.Millisecond},
+	}
+	for _, tt := range tests {
+		got := clock.advance(160, start.Add(tt.now))
+		if got.Frames != tt.frames || !got.Time.Equal(start.Add(tt.want)) {
+			t.Errorf("%s: advance() = {%d, +%v}, want {%d, +%v}", tt.name, got.Frames, got.Time.Sub(start), tt.frames, tt.want)
+		}
+	}
+}
+
+func TestMixerPlaybackPosition(t *testing.T) {
+	m := &mixerImpl{config: DefaultMixerConfig(), currentTTSVolume: 1.0, playback: newPlaybackClock(16000), volume: 1.0}
+	if pos := m.PlaybackPosition(); !pos.Time.IsZero() {
+		t.Fatalf("PlaybackPosition() before render = %+v, want zero", pos)
+	}
+	out := [][]float32{make([]float32, 256), make([]float32, 256)}
+	m.audioCallback(out)
+	m.audioCallback(out)
+	if pos := m.PlaybackPosition(); pos.Frames != 256 || pos.Time.IsZero() {
+		t.Fatalf("PlaybackPosition() = %+v, want 256 frames", pos)
+	}
+}
@@ -0,0 +1,291 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/tts"
+)
+
+// 内置提示音名称
+const (
+	PromptWake     = "wake"     // 唤醒应答（“嗯？”）
+	PromptError    = "error"    // 出错提示音
+	PromptThinking = "thinking" // 思考中提示音
+	PromptRepeat   = "repeat"   // 没听清时请用户重说
+	PromptRefuse   = "refuse"   // 内容过滤拒绝本轮时的回应
+	PromptMicOff   = "mic_off"  // 麦克风静音（隐私模式）确认音
+	PromptMicOn    = "mic_on"   // 麦克风恢复确认音
+)
+
+// ErrPromptNotFound 提示音未加载
+var ErrPromptNotFound = errors.New("prompt not found")
+
+// Prompts 预合成提示音，直接通过 Mixer 播放，不经过 TTS
+// 用于唤醒应答、出错提示等对延迟敏感的场景
+type Prompts interface {
+	// Play 播放指定提示音（异步，立即返回），会替换正在播放的提示音
+	Play(name string) error
+	// Stop 停止正在播放的提示音
+	Stop()
+	// Has 判断提示音是否已加载
+	Has(name string) bool
+	// Names 返回已加载的提示音名称
+	Names() []string
+	// Register 注册提示音，clip 为 Mixer 格式（单声道 16-bit PCM）音频，同名时覆盖
+	Register(name string, clip []byte)
+}
+
+// PromptClipper 可选接口：返回已加载的提示音音频（Mixer 格式），用于把提示音作为一句排入 TTS 播放队列
+type PromptClipper interface {
+	Clip(name string) ([]byte, bool)
+}
+
+// PromptsConfig 提示音配置
+type PromptsConfig struct {
+	Dir        string            // 提示音目录，Files 中的相对路径基于此目录
+	Files      map[string]string // 提示音名称 -> WAV 文件（16-bit PCM）
+	SampleRate int               // 输出采样率，需与 Mixer 一致，默认 16000
+}
+
+// DefaultPromptsConfig 默认提示音配置
+func DefaultPromptsConfig() *PromptsConfig {
+	return &PromptsConfig{
+		Dir: "assets/prompts",
+		Files: map[string]string{
+			PromptWake:     "wake.wav",
+			PromptError:    "error.wav",
+			PromptThinking: "thinking.wav",
+			PromptRepeat:   "repeat.wav",
+			PromptRefuse:   "refuse.wav",
+			PromptMicOff:   "mic_off.wav",
+			PromptMicOn:    "mic_on.wav",
+		},
+		SampleRate: 16000,
+	}
+}
+
+// promptsImpl Prompts 实现
+type promptsImpl struct {
+	mixer AudioMixer
+	clips map[string][]byte // 已转换为 Mixer 格式（单声道 16-bit PCM）的音频
+	mu    sync.Mutex
+}
+
+// NewPrompts 加载提示音
+// 文件不存在时仅记录警告并跳过，避免缺少可选提示音导致启动失败；格式错误则返回错误
+func NewPrompts(config *PromptsConfig, mixer AudioMixer) (Prompts, error) {
+	if config == nil {
+		config = DefaultPromptsConfig()
+	}
+	sampleRate := config.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+
+	p := &promptsImpl{
+		mixer: mixer,
+		clips: make(map[string][]byte),
+	}
+	for name, file := range config.Files {
+		path := file
+		if !filepath.IsAbs(path) && config.Dir != "" {
+			path = filepath.Join(config.Dir, file)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				logging.Warnf("Prompts: %s not found at %s, skipping", name, path)
+				continue
+			}
+			return nil, fmt.Errorf("prompts: read %s: %w", name, err)
+		}
+		clip, err := preparePromptClip(data, sampleRate)
+		if err != nil {
+			return nil, fmt.Errorf("prompts: load %s: %w", name, err)
+		}
+		p.clips[name] = clip
+	}
+
+	logging.Infof("Prompts: loaded %d prompts %v", len(p.clips), p.Names())
+	return p, nil
+}
+
+func (p *promptsImpl) Play(name string) error {
+	p.mu.Lock()
+	clip, ok := p.clips[name]
+	mixer := p.mixer
+	p.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrPromptNotFound, name)
+	}
+	if mixer == nil {
+		return fmt.Errorf("Prompts: mixer not set")
+	}
+
+	logging.Infof("Prompts: playing %s", name)
+	mixer.AddPromptStream(bytes.NewReader(clip))
+	return nil
+}
+
+func (p *promptsImpl) Stop() {
+	p.mu.Lock()
+	mixer := p.mixer
+	p.mu.Unlock()
+
+	if mixer != nil {
+		mixer.RemovePromptStream()
+	}
+}
+
+func (p *promptsImpl) Has(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.clips[name]
+	return ok
+}
+
+func (p *promptsImpl) Names() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	names := make([]string, 0, len(p.clips))
+	for name := range p.clips {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p *promptsImpl) Clip(name string) ([]byte, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	clip, ok := p.clips[name]
+	return clip, ok
+}
+
+func (p *promptsImpl) Register(name string, clip []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clips[name] = clip
+}
+
+// SynthesizePrompt 调用 TTS 预合成一段提示音，返回可直接 Register 的音频
+// 用于没有现成 WAV 时在启动阶段缓存填充语（如“让我想想…”），播放时不再经过 TTS
+func SynthesizePrompt(ctx context.Context, provider tts.Provider, cfg tts.Config, text string, sampleRate int) ([]byte, error) {
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+	cfg.Format = "pcm"
+
+	stream, err := provider.Start(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.WriteTextChunk(ctx, text); err != nil {
+		stream.Close(ctx)
+		return nil, err
+	}
+
+	// 边合成边读取：Stream 的音频缓冲有上限，先 Close 再读取会在音频较长时互相等待
+	reader := stream.AudioReader()
+	defer reader.Close()
+	type readResult struct {
+		data []byte
+		err  error
+	}
+	readCh := make(chan readResult, 1)
+	go func() {
+		data, err := io.ReadAll(reader)
+		readCh <- readResult{data, err}
+	}()
+	if err := stream.Close(ctx); err != nil {
+		return nil, err
+	}
+	result := <-readCh
+	if result.err != nil {
+		return nil, result.err
+	}
+	data := result.data
+
+	return toPromptClip(bytesToInt16(data), stream.SampleRate(), stream.Channels(), sampleRate)
+}
+
+// earconToneMs 提示音中每个音的时长
+const earconToneMs = 90
+
+// GenerateEarcon 生成由若干短音依次组成的提示音（Mixer 格式），用于没有 WAV 文件的开关确认音，
+// 如升调表示开启、降调表示关闭；每个音首尾淡入淡出，避免爆音
+func GenerateEarcon(sampleRate int, frequencies ...float64) []byte {
+	if sampleRate <= 0 {
+		sampleRate = 16000
+	}
+	toneSamples := sampleRate * earconToneMs / 1000
+	fade := toneSamples / 5
+	samples := make([]int16, 0, toneSamples*len(frequencies))
+	for _, freq := range frequencies {
+		for i := 0; i < toneSamples; i++ {
+			gain := 1.0
+			if i < fade {
+				gain = float64(i) / float64(fade)
+			} else if tail := toneSamples - 1 - i; tail < fade {
+				gain = float64(tail) / float64(fade)
+			}
+			v := 0.3 * gain * math.Sin(2*math.Pi*freq*float64(i)/float64(sampleRate))
+			samples = append(samples, int16(v*32767))
+		}
+	}
+	pcm := make([]byte, len(samples)*2)
+	int16ToBytes(samples, pcm)
+	return pcm
+}
+
+// preparePromptClip 将 WAV 转换为 Mixer 使用的单声道 16-bit PCM
+func preparePromptClip(data []byte, sampleRate int) ([]byte, error) {
+	wav, err := DecodeWAV(data)
+	if err != nil {
+		return nil, err
+	}
+	return toPromptClip(wav.Samples, wav.SampleRate, wav.Channels, sampleRate)
+}
+
+// toPromptClip 混为单声道并重采样到 Mixer 采样率
+func toPromptClip(samples []int16, inputRate, channels, outputRate int) ([]byte, error) {
+	samples = downmixToMono(samples, channels)
+	if inputRate != outputRate {
+		var err error
+		samples, err = NewLinearResampler().Resample(samples, inputRate, outputRate, 1)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	clip := make([]byte, len(samples)*2)
+	int16ToBytes(samples, clip)
+	return clip, nil
+}
+
+// downmixToMono 多声道取平均值混为单声道
+func downmixToMono(samples []int16, channels int) []int16 {
+	if channels <= 1 {
+		return samples
+	}
+	mono := make([]int16, len(samples)/channels)
+	for i := range mono {
+		sum := 0
+		for c := 0; c < channels; c++ {
+			sum += int(samples[i*channels+c])
+		}
+		mono[i] = int16(sum / channels)
+	}
+	return mono
+}
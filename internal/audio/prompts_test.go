@@ -0,0 +1,214 @@
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/liuscraft/orion-x/internal/tts"
+)
+
+// buildWAV 生成 16-bit PCM WAV，extraChunk 为 true 时在 fmt 与 data 之间插入 LIST chunk
+func buildWAV(samples []int16, sampleRate, channels int, extraChunk bool) []byte {
+	var body []byte
+	body = append(body, "WAVE"...)
+
+	fmtChunk := make([]byte, 16)
+	binary.LittleEndian.PutUint16(fmtChunk[0:2], 1)
+	binary.LittleEndian.PutUint16(fmtChunk[2:4], uint16(channels))
+	binary.LittleEndian.PutUint32(fmtChunk[4:8], uint32(sampleRate))
+	binary.LittleEndian.PutUint32(fmtChunk[8:12], uint32(sampleRate*channels*2))
+	binary.LittleEndian.PutUint16(fmtChunk[12:14], uint16(channels*2))
+	binary.LittleEndian.PutUint16(fmtChunk[14:16], 16)
+	body = appendChunk(body, "fmt ", fmtChunk)
+
+	if extraChunk {
+		body = appendChunk(body, "LIST", []byte("INFOabc"))
+	}
+
+	pcm := make([]byte, len(samples)*2)
+	int16ToBytes(samples, pcm)
+	body = appendChunk(body, "data", pcm)
+
+	header := make([]byte, 8)
+	copy(header, "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(body)))
+	return append(header, body...)
+}
+
+func appendChunk(buf []byte, id string, data []byte) []byte {
+	header := make([]byte, 8)
+	copy(header, id)
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(data)))
+	buf = append(buf, header...)
+	buf = append(buf, data...)
+	if len(data)%2 == 1 {
+		buf = append(buf, 0)
+	}
+	return buf
+}
+
+func TestDecodeWAV(t *testing.T) {
+	samples := []int16{1, -2, 300, -400}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    *WAVData
+		wantErr bool
+	}{
+		{
+			name: "mono",
+			data: buildWAV(samples, 16000, 1, false),
+			want: &WAVData{Samples: samples, SampleRate: 16000, Channels: 1},
+		},
+		{
+			name: "extra chunk before data",
+			data: buildWAV(samples, 8000, 2, true),
+			want: &WAVData{Samples: samples, SampleRate: 8000, Channels: 2},
+		},
+		{
+			name:    "not wav",
+			data:    []byte("hello world, not a wav"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeWAV(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeWAV() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("DecodeWAV() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreparePromptClip(t *testing.T) {
+	// 立体声 8kHz -> 单声道 16kHz
+	stereo := []int16{100, 300, 100, 300, 100, 300, 100, 300}
+	clip, err := preparePromptClip(buildWAV(stereo, 8000, 2, false), 16000)
+	if err != nil {
+		t.Fatalf("preparePromptClip() error = %v", err)
+	}
+
+	samples := bytesToInt16(clip)
+	if len(samples) != 8 {
+		t.Fatalf("expected 8 samples after resampling, got %d", len(samples))
+	}
+	for i, s := range samples {
+		if s != 200 {
+			t.Fatalf("sample %d = %d, want 200", i, s)
+		}
+	}
+}
+
+func TestGenerateEarcon(t *testing.T) {
+	clip := GenerateEarcon(16000, 660, 880)
+	samples := bytesToInt16(clip)
+	if want := 2 * 16000 * earconToneMs / 1000; len(samples) != want {
+		t.Fatalf("earcon has %d samples, want %d", len(samples), want)
+	}
+	// 首尾淡入淡出，中间有声音
+	if samples[0] != 0 || samples[len(samples)-1] != 0 {
+		t.Fatalf("earcon should start and end silent, got %d / %d", samples[0], samples[len(samples)-1])
+	}
+	var peak int16
+	for _, s := range samples {
+		peak = max(peak, s)
+	}
+	if peak < 5000 {
+		t.Fatalf("earcon peak = %d, want audible tone", peak)
+	}
+}
+
+func TestPromptsPlay(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "wake.wav"), buildWAV([]int16{1, 2, 3}, 16000, 1, false), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	mixer := newMockMixer()
+	prompts, err := NewPrompts(&PromptsConfig{
+		Dir: dir,
+		Files: map[string]string{
+			PromptWake:  "wake.wav",
+			PromptError: "missing.wav",
+		},
+	}, mixer)
+	if err != nil {
+		t.Fatalf("NewPrompts() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(prompts.Names(), []string{PromptWake}) {
+		t.Fatalf("unexpected prompts loaded: %v", prompts.Names())
+	}
+	if err := prompts.Play(PromptError); !errors.Is(err, ErrPromptNotFound) {
+		t.Fatalf("expected ErrPromptNotFound, got %v", err)
+	}
+
+	if err := prompts.Play(PromptWake); err != nil {
+		t.Fatalf("Play() error = %v", err)
+	}
+	stream := mixer.getPromptStream()
+	if stream == nil {
+		t.Fatal("expected prompt stream on mixer")
+	}
+	data, _ := io.ReadAll(stream)
+	if !reflect.DeepEqual(bytesToInt16(data), []int16{1, 2, 3}) {
+		t.Fatalf("unexpected prompt audio: %v", bytesToInt16(data))
+	}
+
+	prompts.Stop()
+	if mixer.getPromptStream() != nil {
+		t.Fatal("expected prompt stream removed after Stop")
+	}
+}
+
+func TestNewPromptsInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "bad.wav"), []byte("not a wav file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := NewPrompts(&PromptsConfig{Dir: dir, Files: map[string]string{"bad": "bad.wav"}}, newMockMixer())
+	if err == nil {
+		t.Fatal("expected error for invalid wav")
+	}
+}
+
+func TestSynthesizePrompt(t *testing.T) {
+	provider := newMockTTSProvider()
+
+	clip, err := SynthesizePrompt(context.Background(), provider, tts.Config{Format: "mp3"}, "ab", 16000)
+	if err != nil {
+		t.Fatalf("SynthesizePrompt() error = %v", err)
+	}
+	// mock stream 每个字符生成 100 字节，采样率一致时不重采样
+	if len(clip) != 200 {
+		t.Fatalf("expected 200 bytes, got %d", len(clip))
+	}
+	if provider.getLastConfig().Format != "pcm" {
+		t.Fatalf("expected pcm format, got %s", provider.getLastConfig().Format)
+	}
+
+	prompts, _ := NewPrompts(&PromptsConfig{}, newMockMixer())
+	prompts.Register(PromptThinking, clip)
+	if !prompts.Has(PromptThinking) {
+		t.Fatal("expected registered prompt")
+	}
+}
@@ -0,0 +1,158 @@
+package audio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/liuscraft/orion-x/internal/audio/wav"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// recorderQueueSize 调试录音待写盘的块数，写盘跟不上时丢弃新块
+const recorderQueueSize = 256
+
+// WAVRecorder 把 16-bit PCM 写入 WAV 文件的调试录音器
+// Write 复制数据后立即返回，由后台协程写盘，可以在音频回调中调用；队列满时丢弃，Close 后的写入被忽略
+type WAVRecorder struct {
+	path   string
+	file   *os.File
+	writer *wav.Writer
+	queue  chan []byte
+	done   chan struct{}
+
+	mu      sync.Mutex
+	closed  bool
+	dropped int64
+}
+
+// NewWAVRecorder 创建 WAV 文件并开始接收音频，Close 时回填文件头长度
+func NewWAVRecorder(path string, sampleRate, channels int) (*WAVRecorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := wav.NewWriter(file, wav.PCM16(sampleRate, channels))
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("write wav header %s: %w", path, err)
+	}
+	r := &WAVRecorder{
+		path:   path,
+		file:   file,
+		writer: writer,
+		queue:  make(chan []byte, recorderQueueSize),
+		done:   make(chan struct{}),
+	}
+	go r.run()
+	return r, nil
+}
+
+// Path 录音文件路径
+func (r *WAVRecorder) Path() string {
+	return r.path
+}
+
+// Write 实现 io.Writer，总是返回 len(p)
+func (r *WAVRecorder) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return len(p), nil
+	}
+	select {
+	case r.queue <- append([]byte(nil), p...):
+	default:
+		r.dropped++
+		if r.dropped%100 == 1 {
+			logging.Warnf("WAVRecorder: %s queue full, dropped %d block(s)", r.path, r.dropped)
+		}
+	}
+	return len(p), nil
+}
+
+func (r *WAVRecorder) run() {
+	defer close(r.done)
+	failed := false
+	for block := range r.queue {
+		if failed {
+			continue
+		}
+		if _, err := r.writer.Write(block); err != nil {
+			logging.Errorf("WAVRecorder: failed to write %s: %v", r.path, err)
+			failed = true
+		}
+	}
+}
+
+// Close 写完队列中的音频，回填文件头并关闭文件；可重复调用
+func (r *WAVRecorder) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.queue)
+	r.mu.Unlock()
+
+	<-r.done
+	err := r.writer.Close()
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// RecordingSource 把读到的音频同时写入录音器的 AudioSource 装饰器，用于录制调试音频
+type RecordingSource struct {
+	source   AudioSource
+	recorder io.WriteCloser
+}
+
+// NewRecordingSource 创建 RecordingSource，Close 时一并关闭 recorder
+func NewRecordingSource(source AudioSource, recorder io.WriteCloser) *RecordingSource {
+	return &RecordingSource{source: source, recorder: recorder}
+}
+
+func (s *RecordingSource) Read(ctx context.Context) ([]byte, error) {
+	data, err := s.source.Read(ctx)
+	if len(data) > 0 {
+		s.recorder.Write(data)
+	}
+	return data, err
+}
+
+func (s *RecordingSource) Close() error {
+	err := s.source.Close()
+	if recErr := s.recorder.Close(); err == nil {
+		err = recErr
+	}
+	return err
+}
+
+// downmixS16 把各声道的浮点输出下混为单声道 16-bit PCM 写入 buf 并返回；
+// buf 长度与帧数不符时重新分配，音频回调中复用同一缓冲，避免每次回调分配
+func downmixS16(out [][]float32, buf []byte) []byte {
+	if len(out) == 0 {
+		return buf[:0]
+	}
+	frames := len(out[0])
+	if len(buf) != frames*2 {
+		buf = make([]byte, frames*2)
+	}
+	for i := 0; i < frames; i++ {
+		var sum float32
+		for _, channel := range out {
+			sum += channel[i]
+		}
+		v := floatToInt16(float64(sum / float32(len(out))))
+		buf[i*2], buf[i*2+1] = byte(v), byte(v>>8)
+	}
+	return buf
+}
@@ -0,0 +1,109 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/liuscraft/orion-x/internal/audio/wav"
+)
+
+func TestWAVRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mic.wav")
+	recorder, err := NewWAVRecorder(path, 16000, 1)
+	if err != nil {
+		t.Fatalf("NewWAVRecorder() error = %v", err)
+	}
+	recorder.Write(s16Bytes(1, 2))
+	recorder.Write(s16Bytes(3))
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	// Close 之后的写入被忽略，重复 Close 无副作用
+	recorder.Write(s16Bytes(4))
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("second Close() error = %v", err)
+	}
+
+	header, pcm, err := wav.ReadFile(path)
+	if err != nil {
+		t.Fatalf("wav.ReadFile() error = %v", err)
+	}
+	if header != wav.PCM16(16000, 1) {
+		t.Errorf("header = %+v", header)
+	}
+	if !bytes.Equal(pcm, s16Bytes(1, 2, 3)) {
+		t.Errorf("pcm = %v, want samples 1, 2, 3", bytesToInt16(pcm))
+	}
+}
+
+// bufferRecorder 记录写入的数据和是否被关闭
+type bufferRecorder struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (r *bufferRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestRecordingSource(t *testing.T) {
+	recorder := &bufferRecorder{}
+	source := NewRecordingSource(&stubSource{data: s16Bytes(7, 8)}, recorder)
+
+	for i := 0; i < 2; i++ {
+		data, err := source.Read(context.Background())
+		if err != nil || !bytes.Equal(data, s16Bytes(7, 8)) {
+			t.Fatalf("Read() = %v, %v", data, err)
+		}
+	}
+	if !bytes.Equal(recorder.Bytes(), s16Bytes(7, 8, 7, 8)) {
+		t.Errorf("recorded %v", bytesToInt16(recorder.Bytes()))
+	}
+	if err := source.Close(); err != nil || !recorder.closed {
+		t.Errorf("Close() error = %v, recorder closed = %v", err, recorder.closed)
+	}
+}
+
+func TestMixerOutputRecorder(t *testing.T) {
+	m := &mixerImpl{config: DefaultMixerConfig(), currentTTSVolume: 1.0, volume: 1.0}
+	recorder := &bufferRecorder{}
+	m.SetOutputRecorder(recorder)
+
+	// 立体声输出下混为单声道录制，与实际播放的混音结果一致
+	m.AddTTSStream(bytes.NewReader(s16Bytes(16384, 16384, -16384, -16384)))
+	m.audioCallback([][]float32{make([]float32, 4), make([]float32, 4)})
+
+	got := bytesToInt16(recorder.Bytes())
+	want := []int16{16384, 16384, -16384, -16384}
+	if len(got) != len(want) {
+		t.Fatalf("recorded %d samples, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("sample %d = %d, want %d", i, got[i], want[i])
+		}
+	}
+
+	m.SetOutputRecorder(nil)
+	m.audioCallback([][]float32{make([]float32, 4), make([]float32, 4)})
+	if recorder.Len() != len(want)*2 {
+		t.Errorf("recorded after SetOutputRecorder(nil): %d bytes", recorder.Len())
+	}
+}
+
+func TestDownmixS16ReusesBuffer(t *testing.T) {
+	out := [][]float32{{0.5, -0.5}, {0.5, -0.5}}
+	buf := downmixS16(out, nil)
+	if got := bytesToInt16(buf); got[0] != 16384 || got[1] != -16384 {
+		t.Fatalf("downmixS16() = %v, want [16384 -16384]", got)
+	}
+	if again := downmixS16(out, buf); &again[0] != &buf[0] {
+		t.Error("downmixS16() reallocated for the same frame count")
+	}
+	if grown := downmixS16([][]float32{make([]float32, 4)}, buf); len(grown) != 8 {
+		t.Errorf("downmixS16() length = %d, want 8", len(grown))
+	}
+}
@@ -0,0 +1,86 @@
+package audio
+
+import (
+	"errors"
+	"sync"
+)
+
+// RemoteStream 由网络对端（如浏览器）驱动的全双工流，代替本地音频设备：
+// 每收到一块麦克风输入就渲染等长的 Mixer 输出返回，与 DuplexStream 一样回声参考与输入严格对齐。
+// 对端断开期间 Mixer 不被渲染，播放随之暂停
+type RemoteStream struct {
+	duplex *DuplexStream
+
+	mu  sync.Mutex
+	out [][]float32 // 复用的渲染缓冲
+}
+
+// NewRemoteStream 创建远端音频流；输入固定为单声道，输出下混为单声道返回
+func NewRemoteStream(config DuplexConfig, renderer AudioRenderer) (*RemoteStream, error) {
+	if renderer == nil {
+		return nil, errors.New("remote stream requires a renderer")
+	}
+	config.InputChannels = 1
+	config = normalizeDuplexConfig(config)
+	return &RemoteStream{duplex: newDuplexStream(config, renderer)}, nil
+}
+
+// Process 处理对端发来的一块麦克风输入（16-bit 小端单声道 PCM），返回等长的输出音频（同样格式）
+func (r *RemoteStream) Process(in []byte) []byte {
+	samples := bytesToInt16(in)
+	frames := len(samples)
+	if frames == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.out) == 0 || len(r.out[0]) < frames {
+		r.out = make([][]float32, r.duplex.config.OutputChannels)
+		for i := range r.out {
+			r.out[i] = make([]float32, frames)
+		}
+	}
+	out := make([][]float32, len(r.out))
+	for i := range r.out {
+		out[i] = r.out[i][:frames]
+	}
+	r.duplex.callback(samples, out)
+
+	mono := make([]int16, frames)
+	for i := 0; i < frames; i++ {
+		var sum float32
+		for _, channel := range out {
+			sum += channel[i]
+		}
+		mono[i] = floatToInt16(float64(sum / float32(len(out))))
+	}
+	data := make([]byte, frames*2)
+	int16ToBytes(mono, data)
+	return data
+}
+
+// SetReferenceSink 设置回声参考接收方
+func (r *RemoteStream) SetReferenceSink(sink ReferenceSink) {
+	r.duplex.SetReferenceSink(sink)
+}
+
+// SampleRate 对端收发音频的采样率
+func (r *RemoteStream) SampleRate() int {
+	return r.duplex.config.SampleRate
+}
+
+// Channels 输入声道数（固定为 1）
+func (r *RemoteStream) Channels() int {
+	return r.duplex.config.InputChannels
+}
+
+// Source 返回读取对端麦克风输入的 AudioSource
+func (r *RemoteStream) Source() AudioSource {
+	return r.duplex.source
+}
+
+// Close 关闭流，Source 的 Read 随后返回 io.EOF
+func (r *RemoteStream) Close() error {
+	return r.duplex.source.Close()
+}
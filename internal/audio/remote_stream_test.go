@@ -0,0 +1,54 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestRemoteStreamProcess(t *testing.T) {
+	stream, err := NewRemoteStream(DuplexConfig{SampleRate: 16000, InputChannels: 2, ReadFrames: 4}, &constRenderer{value: 0.5})
+	if err != nil {
+		t.Fatalf("NewRemoteStream() error = %v", err)
+	}
+	sink := &captureSink{}
+	stream.SetReferenceSink(sink)
+	if stream.Channels() != 1 || stream.SampleRate() != 16000 {
+		t.Fatalf("channels=%d sampleRate=%d, want 1 / 16000", stream.Channels(), stream.SampleRate())
+	}
+
+	in := make([]byte, 8)
+	int16ToBytes([]int16{1, 2, 3, 4}, in)
+	out := stream.Process(in)
+	if len(out) != len(in) {
+		t.Fatalf("output length = %d, want %d", len(out), len(in))
+	}
+	for i, sample := range bytesToInt16(out) {
+		if want := floatToInt16(0.5); sample != want {
+			t.Fatalf("output[%d] = %d, want %d", i, sample, want)
+		}
+	}
+	if len(sink.writes) != 1 || len(sink.writes[0]) != len(in) {
+		t.Fatalf("reference writes = %v, want one frame of %d bytes", sink.writes, len(in))
+	}
+	if out := stream.Process(in[:1]); out != nil {
+		t.Fatalf("Process(1 byte) = %v, want nil", out)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	data, err := stream.Source().Read(ctx)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got := bytesToInt16(data); len(got) != 4 || got[0] != 1 || got[3] != 4 {
+		t.Fatalf("Read() = %v, want [1 2 3 4]", got)
+	}
+
+	stream.Close()
+	if _, err := stream.Source().Read(ctx); !errors.Is(err, io.EOF) {
+		t.Fatalf("Read() after Close error = %v, want io.EOF", err)
+	}
+}
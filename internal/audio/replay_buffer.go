@@ -0,0 +1,49 @@
+package audio
+
+// audioReplayBuffer 有界的音频环形缓冲，按原始分片保存最近的音频
+// 超出容量时丢弃最早的分片，用于 ASR 重连后补发断线期间的音频
+type audioReplayBuffer struct {
+	chunks   [][]byte
+	size     int
+	capacity int
+}
+
+func newAudioReplayBuffer(capacity int) *audioReplayBuffer {
+	return &audioReplayBuffer{capacity: capacity}
+}
+
+// Write 追加一个音频分片（会拷贝数据）
+func (b *audioReplayBuffer) Write(audio []byte) {
+	if b.capacity <= 0 || len(audio) == 0 {
+		return
+	}
+	if len(audio) > b.capacity {
+		audio = audio[len(audio)-b.capacity:]
+	}
+	chunk := make([]byte, len(audio))
+	copy(chunk, audio)
+	b.chunks = append(b.chunks, chunk)
+	b.size += len(chunk)
+
+	for b.size > b.capacity {
+		b.size -= len(b.chunks[0])
+		b.chunks[0] = nil
+		b.chunks = b.chunks[1:]
+	}
+}
+
+// Chunks 返回当前缓存的分片（按时间顺序）
+func (b *audioReplayBuffer) Chunks() [][]byte {
+	return append([][]byte(nil), b.chunks...)
+}
+
+// Size 当前缓存的字节数
+func (b *audioReplayBuffer) Size() int {
+	return b.size
+}
+
+// Reset 清空缓存
+func (b *audioReplayBuffer) Reset() {
+	b.chunks = nil
+	b.size = 0
+}
@@ -0,0 +1,37 @@
+package audio
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAudioReplayBuffer(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		writes   [][]byte
+		want     [][]byte
+	}{
+		{"keeps all within capacity", 4, [][]byte{{1}, {2, 3}}, [][]byte{{1}, {2, 3}}},
+		{"drops oldest chunks", 4, [][]byte{{1, 1}, {2, 2}, {3, 3}}, [][]byte{{2, 2}, {3, 3}}},
+		{"truncates oversized chunk", 2, [][]byte{{1, 2, 3, 4}}, [][]byte{{3, 4}}},
+		{"disabled", 0, [][]byte{{1}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newAudioReplayBuffer(tt.capacity)
+			for _, w := range tt.writes {
+				b.Write(w)
+			}
+			got := b.Chunks()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Chunks() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if !bytes.Equal(got[i], tt.want[i]) {
+					t.Errorf("chunk %d = %v, want %v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -50,6 +50,12 @@ func NewResamplingReader(source io.Reader, inputRate, outputRate, channels int,
 	}
 }
 
+// NewFormatResamplingReader 创建重采样 Reader，source 为 format 格式的 PCM（如 float32 或 24-bit），
+// 先转换为 16-bit 再重采样；输出始终为 16-bit PCM
+func NewFormatResamplingReader(source io.Reader, format SampleFormat, inputRate, outputRate, channels int, resampler Resampler) *ResamplingReader {
+	return NewResamplingReader(NewS16Reader(source, format), inputRate, outputRate, channels, resampler)
+}
+
 // Read 实现 io.Reader 接口
 // 从 source 读取数据，重采样后写入 p
 func (r *ResamplingReader) Read(p []byte) (n int, err error) {
@@ -0,0 +1,69 @@
+package audio
+
+import (
+	"context"
+	"io"
+)
+
+// ResamplingSource 将 AudioSource 的采样率转换为目标采样率的装饰器
+// 内部通过 ResamplingReader 完成重采样，用于设备不支持 ASR 所需采样率的场景
+type ResamplingSource struct {
+	source AudioSource
+	reader *ResamplingReader
+	input  *audioSourceReader
+	buffer []byte
+}
+
+func NewResamplingSource(source AudioSource, inputRate, outputRate, channels int, resampler Resampler) *ResamplingSource {
+	input := &audioSourceReader{source: source}
+	return &ResamplingSource{
+		source: source,
+		reader: NewResamplingReader(input, inputRate, outputRate, channels, resampler),
+		input:  input,
+		buffer: make([]byte, 8192),
+	}
+}
+
+func (s *ResamplingSource) Read(ctx context.Context) ([]byte, error) {
+	if s.source == nil {
+		return nil, io.EOF
+	}
+	s.input.ctx = ctx
+	n, err := s.reader.Read(s.buffer)
+	if n > 0 {
+		return append([]byte(nil), s.buffer[:n]...), nil
+	}
+	return nil, err
+}
+
+func (s *ResamplingSource) Close() error {
+	if s.source != nil {
+		return s.source.Close()
+	}
+	return nil
+}
+
+// audioSourceReader 把 AudioSource 适配为 io.Reader，缓存未读完的数据
+type audioSourceReader struct {
+	source  AudioSource
+	ctx     context.Context
+	pending []byte
+}
+
+func (r *audioSourceReader) Read(p []byte) (int, error) {
+	if len(r.pending) == 0 {
+		ctx := r.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		data, err := r.source.Read(ctx)
+		if err != nil {
+			return 0, err
+		}
+		r.pending = data
+	}
+	// 按整帧（16-bit 采样）切分，避免采样跨两次读取
+	n := copy(p[:len(p)&^1], r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
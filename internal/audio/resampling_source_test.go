@@ -0,0 +1,66 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+// finiteSource 返回固定次数的数据后返回 io.EOF
+type finiteSource struct {
+	data  []byte
+	reads int
+}
+
+func (s *finiteSource) Read(ctx context.Context) ([]byte, error) {
+	if s.reads <= 0 {
+		return nil, io.EOF
+	}
+	s.reads--
+	return append([]byte(nil), s.data...), nil
+}
+
+func (s *finiteSource) Close() error {
+	return nil
+}
+
+func TestResamplingSource(t *testing.T) {
+	tests := []struct {
+		name       string
+		inputRate  int
+		outputRate int
+		chunk      int // 每次源读取的样本数
+	}{
+		{"48k to 16k", 48000, 16000, 9600},
+		{"44.1k to 16k", 44100, 16000, 8820},
+		{"same rate passes through", 16000, 16000, 3200},
+	}
+	const reads = 5
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := floatsToPCM(sineSamples(tt.chunk, tt.inputRate, 440, 0.3))
+			src := NewResamplingSource(&finiteSource{data: data, reads: reads}, tt.inputRate, tt.outputRate, 1, nil)
+
+			total := 0
+			for {
+				out, err := src.Read(context.Background())
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Read() error = %v", err)
+				}
+				if len(out)%2 != 0 {
+					t.Fatalf("Read() returned odd length %d", len(out))
+				}
+				total += len(out) / 2
+			}
+
+			want := float64(tt.chunk*reads) * float64(tt.outputRate) / float64(tt.inputRate)
+			if diff := float64(total) - want; diff > want*0.02 || diff < -want*0.02 {
+				t.Errorf("output samples = %d, want about %.0f", total, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package audio
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// SampleFormat PCM 采样格式（均为 little-endian、多声道交错）
+// 管线内部统一使用 SampleFormatS16，其他格式在进入管线时转换
+type SampleFormat int
+
+const (
+	// SampleFormatS16 16-bit 有符号整数（默认）
+	SampleFormatS16 SampleFormat = iota
+	// SampleFormatS24 24-bit 有符号整数，每个样本 3 字节
+	SampleFormatS24
+	// SampleFormatS32 32-bit 有符号整数（常见于 24-bit 有效位的 USB 声卡）
+	SampleFormatS32
+	// SampleFormatF32 32-bit 浮点，取值 -1~1
+	SampleFormatF32
+)
+
+var sampleFormatNames = map[SampleFormat]string{
+	SampleFormatS16: "s16",
+	SampleFormatS24: "s24",
+	SampleFormatS32: "s32",
+	SampleFormatF32: "f32",
+}
+
+// ParseSampleFormat 解析采样格式名称：s16 / s24 / s32 / f32（可带 le 后缀，如 f32le），空字符串为 s16
+func ParseSampleFormat(name string) (SampleFormat, error) {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), "le")
+	if name == "" {
+		return SampleFormatS16, nil
+	}
+	for format, formatName := range sampleFormatNames {
+		if name == formatName {
+			return format, nil
+		}
+	}
+	return SampleFormatS16, fmt.Errorf("unknown sample format %q (want s16, s24, s32 or f32)", name)
+}
+
+func (f SampleFormat) String() string {
+	if name, ok := sampleFormatNames[f]; ok {
+		return name
+	}
+	return fmt.Sprintf("SampleFormat(%d)", int(f))
+}
+
+// BytesPerSample 每个样本（单声道）的字节数
+func (f SampleFormat) BytesPerSample() int {
+	switch f {
+	case SampleFormatS24:
+		return 3
+	case SampleFormatS32, SampleFormatF32:
+		return 4
+	default:
+		return 2
+	}
+}
+
+// ConvertToS16 把 format 格式的 PCM 转换为 16-bit PCM；末尾不足一个样本的字节被丢弃，
+// format 为 SampleFormatS16 时返回 data 本身
+func ConvertToS16(data []byte, format SampleFormat) []byte {
+	size := format.BytesPerSample()
+	samples := len(data) / size
+	if format == SampleFormatS16 {
+		return data[:samples*2]
+	}
+	out := make([]byte, samples*2)
+	for i := 0; i < samples; i++ {
+		b := data[i*size : (i+1)*size]
+		var v int16
+		switch format {
+		case SampleFormatS24:
+			v = int16(uint16(b[1]) | uint16(b[2])<<8)
+		case SampleFormatS32:
+			v = int16(int32(binary.LittleEndian.Uint32(b)) >> 16)
+		case SampleFormatF32:
+			f := math.Float32frombits(binary.LittleEndian.Uint32(b))
+			if math.IsNaN(float64(f)) {
+				f = 0
+			}
+			v = floatToInt16(float64(f))
+		}
+		binary.LittleEndian.PutUint16(out[i*2:], uint16(v))
+	}
+	return out
+}
+
+// ConvertFromS16 把 16-bit PCM 转换为 format 格式，用于向只接受其他格式的设备或服务输出；
+// format 为 SampleFormatS16 时返回 pcm 本身
+func ConvertFromS16(pcm []byte, format SampleFormat) []byte {
+	samples := len(pcm) / 2
+	if format == SampleFormatS16 {
+		return pcm[:samples*2]
+	}
+	size := format.BytesPerSample()
+	out := make([]byte, samples*size)
+	for i := 0; i < samples; i++ {
+		v := int16(binary.LittleEndian.Uint16(pcm[i*2:]))
+		b := out[i*size : (i+1)*size]
+		switch format {
+		case SampleFormatS24:
+			b[0], b[1], b[2] = 0, byte(v), byte(v>>8)
+		case SampleFormatS32:
+			binary.LittleEndian.PutUint32(b, uint32(int32(v)<<16))
+		case SampleFormatF32:
+			binary.LittleEndian.PutUint32(b, math.Float32bits(float32(v)/32768))
+		}
+	}
+	return out
+}
+
+// NewS16Reader 把 format 格式的 PCM 流转换为 16-bit PCM 流，跨 Read 边界的不完整样本留到下次转换；
+// format 为 SampleFormatS16 时原样返回 r
+func NewS16Reader(r io.Reader, format SampleFormat) io.Reader {
+	if format == SampleFormatS16 || r == nil {
+		return r
+	}
+	return newFrameReader(r, format.BytesPerSample(), func(data []byte) []byte {
+		return ConvertToS16(data, format)
+	})
+}
+
+// frameReader 按整帧转换 PCM 流：每次只把完整的 frameSize 字节交给 convert，不完整的帧留到下次
+type frameReader struct {
+	source    io.Reader
+	frameSize int
+	convert   func([]byte) []byte
+	buf       []byte
+	pending   []byte // 不足一帧的输入
+	out       []byte // 已转换未读出的输出
+	err       error
+}
+
+func newFrameReader(source io.Reader, frameSize int, convert func([]byte) []byte) *frameReader {
+	return &frameReader{source: source, frameSize: frameSize, convert: convert, buf: make([]byte, 4096)}
+}
+
+func (r *frameReader) Read(p []byte) (int, error) {
+	for len(r.out) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		n, err := r.source.Read(r.buf)
+		r.pending = append(r.pending, r.buf[:n]...)
+		whole := len(r.pending) - len(r.pending)%r.frameSize
+		if whole > 0 {
+			r.out = r.convert(r.pending[:whole])
+		}
+		r.pending = append(r.pending[:0], r.pending[whole:]...)
+		if err != nil {
+			// 流结束时丢弃末尾不完整的帧
+			r.err = err
+		}
+	}
+	n := copy(p, r.out)
+	r.out = r.out[n:]
+	return n, nil
+}
+
+// NewS16Source 把输出 format 格式 PCM 的 AudioSource 转换为输出 16-bit PCM；
+// format 为 SampleFormatS16 时原样返回 source
+func NewS16Source(source AudioSource, format SampleFormat) AudioSource {
+	if format == SampleFormatS16 || source == nil {
+		return source
+	}
+	return &s16Source{source: source, format: format}
+}
+
+type s16Source struct {
+	source  AudioSource
+	format  SampleFormat
+	pending []byte
+}
+
+func (s *s16Source) Read(ctx context.Context) ([]byte, error) {
+	for {
+		data, err := s.source.Read(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.pending = append(s.pending, data...)
+		whole := len(s.pending) - len(s.pending)%s.format.BytesPerSample()
+		if whole == 0 {
+			continue
+		}
+		out := ConvertToS16(s.pending[:whole], s.format)
+		s.pending = append(s.pending[:0], s.pending[whole:]...)
+		return out, nil
+	}
+}
+
+func (s *s16Source) Close() error {
+	return s.source.Close()
+}
@@ -0,0 +1,155 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseSampleFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    SampleFormat
+		wantErr bool
+	}{
+		{"", SampleFormatS16, false},
+		{"s16", SampleFormatS16, false},
+		{"S16LE", SampleFormatS16, false},
+		{"s24le", SampleFormatS24, false},
+		{"s32", SampleFormatS32, false},
+		{" f32le ", SampleFormatF32, false},
+		{"f64", SampleFormatS16, true},
+		{"u8", SampleFormatS16, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSampleFormat(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSampleFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSampleFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func f32Bytes(values ...float32) []byte {
+	out := make([]byte, len(values)*4)
+	for i, v := range values {
+		binary.LittleEndian.PutUint32(out[i*4:], math.Float32bits(v))
+	}
+	return out
+}
+
+func s16Bytes(samples ...int16) []byte {
+	out := make([]byte, len(samples)*2)
+	int16ToBytes(samples, out)
+	return out
+}
+
+func TestConvertToS16(t *testing.T) {
+	tests := []struct {
+		name   string
+		format SampleFormat
+		data   []byte
+		want   []int16
+	}{
+		{"s16 passes through", SampleFormatS16, s16Bytes(1, -2, 300), []int16{1, -2, 300}},
+		{"s24", SampleFormatS24, []byte{0xff, 0x34, 0x12, 0x00, 0x00, 0x80, 0x00, 0xff, 0xff}, []int16{0x1234, -32768, -1}},
+		{"s32", SampleFormatS32, []byte{0xff, 0xff, 0x34, 0x12, 0x00, 0x00, 0x00, 0x80}, []int16{0x1234, -32768}},
+		{"f32", SampleFormatF32, f32Bytes(0, 0.5, -0.5, -1), []int16{0, 16384, -16384, -32768}},
+		{"f32 clips and drops NaN", SampleFormatF32, f32Bytes(2, -2, float32(math.NaN())), []int16{32767, -32768, 0}},
+		{"trailing partial sample dropped", SampleFormatS24, []byte{0, 1, 0, 0, 2}, []int16{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bytesToInt16(ConvertToS16(tt.data, tt.format)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ConvertToS16() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertFromS16RoundTrip(t *testing.T) {
+	pcm := s16Bytes(0, 1, -1, 12345, -32768, 32767)
+	for _, format := range []SampleFormat{SampleFormatS16, SampleFormatS24, SampleFormatS32, SampleFormatF32} {
+		t.Run(format.String(), func(t *testing.T) {
+			converted := ConvertFromS16(pcm, format)
+			if len(converted) != len(pcm)/2*format.BytesPerSample() {
+				t.Fatalf("ConvertFromS16() len = %d", len(converted))
+			}
+			if got := ConvertToS16(converted, format); !bytes.Equal(got, pcm) {
+				t.Errorf("ConvertToS16(ConvertFromS16()) = %v, want %v", bytesToInt16(got), bytesToInt16(pcm))
+			}
+		})
+	}
+}
+
+// chunkReader 每次 Read 最多返回 size 字节，用于模拟样本跨 Read 边界
+type chunkReader struct {
+	data []byte
+	size int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	n := r.size
+	if n > len(p) {
+		n = len(p)
+	}
+	if n > len(r.data) {
+		n = len(r.data)
+	}
+	n = copy(p, r.data[:n])
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestS16Reader(t *testing.T) {
+	values := []float32{0.25, -0.25, 0.5, -0.5, 1, -1}
+	want := s16Bytes(8192, -8192, 16384, -16384, 32767, -32768)
+	for _, size := range []int{1, 3, 5, 4096} {
+		data := append(f32Bytes(values...), 0x01, 0x02) // 末尾不完整样本被丢弃
+		got, err := io.ReadAll(NewS16Reader(&chunkReader{data: data, size: size}, SampleFormatF32))
+		if err != nil {
+			t.Fatalf("chunk %d: ReadAll() error = %v", size, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("chunk %d: got %v, want %v", size, bytesToInt16(got), bytesToInt16(want))
+		}
+	}
+
+	r := bytes.NewReader(nil)
+	if NewS16Reader(r, SampleFormatS16) != io.Reader(r) {
+		t.Error("NewS16Reader(s16) should return the reader itself")
+	}
+}
+
+func TestS16Source(t *testing.T) {
+	// 每次读取 6 字节，不是 32-bit 样本的整数倍，剩余字节与下一次读取拼接
+	src := &finiteSource{data: []byte{0, 1, 0, 0, 2, 0}, reads: 3}
+	s := NewS16Source(src, SampleFormatS32)
+	var got []int16
+	for {
+		data, err := s.Read(context.Background())
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		got = append(got, bytesToInt16(data)...)
+	}
+	// 18 字节按 32-bit 可拼出 4 个样本：00 01 00 00 | 02 00 00 01 | 00 00 02 00 | 00 01 00 00
+	want := []int16{0, 256, 2, 0}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("samples = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,247 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+// FileConfig 文件音频源配置
+type FileConfig struct {
+	// SampleRate 输出采样率，文件采样率不同时重采样
+	SampleRate int
+	// ChunkMs 每次 Read 返回的音频时长
+	ChunkMs int
+	// Speed 播放速度：1 表示按实时速度送出，2 表示两倍速，<=0 表示不等待、尽快读完
+	Speed float64
+	// PCMSampleRate / PCMChannels / PCMFormat 裸 PCM 文件（.pcm / .raw，little-endian）的格式，PCMFormat 默认 16-bit
+	PCMSampleRate int
+	PCMChannels   int
+	PCMFormat     audio.SampleFormat
+}
+
+// DefaultFileConfig 默认配置：16kHz 单声道输出，100ms 一块，实时速度
+func DefaultFileConfig() FileConfig {
+	return FileConfig{
+		SampleRate:    16000,
+		ChunkMs:       100,
+		Speed:         1,
+		PCMSampleRate: 16000,
+		PCMChannels:   1,
+	}
+}
+
+// FileSource 从音频文件读取 16-bit 单声道 PCM，按实时节奏送出，用于用录音复现识别效果
+// 支持 WAV、裸 PCM，其他格式（如 MP3）通过 ffmpeg 解码
+type FileSource struct {
+	samples    []int16
+	sampleRate int
+	chunk      int
+	interval   time.Duration
+	offset     int
+	next       time.Time
+	mu         sync.Mutex
+	closed     bool
+	closeCh    chan struct{}
+	closeOnce  sync.Once
+}
+
+// NewFileSource 读取并解码整个文件，转换为 cfg.SampleRate 的单声道音频
+func NewFileSource(path string, cfg FileConfig) (*FileSource, error) {
+	defaults := DefaultFileConfig()
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = defaults.SampleRate
+	}
+	if cfg.ChunkMs <= 0 {
+		cfg.ChunkMs = defaults.ChunkMs
+	}
+	if cfg.PCMSampleRate <= 0 {
+		cfg.PCMSampleRate = defaults.PCMSampleRate
+	}
+	if cfg.PCMChannels <= 0 {
+		cfg.PCMChannels = defaults.PCMChannels
+	}
+
+	samples, err := DecodeFile(path, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FileSource{
+		samples:    samples,
+		sampleRate: cfg.SampleRate,
+		chunk:      cfg.SampleRate * cfg.ChunkMs / 1000,
+		closeCh:    make(chan struct{}),
+	}
+	if cfg.Speed > 0 {
+		s.interval = time.Duration(float64(time.Duration(cfg.ChunkMs)*time.Millisecond) / cfg.Speed)
+	}
+	return s, nil
+}
+
+// Duration 返回文件音频的总时长
+func (s *FileSource) Duration() time.Duration {
+	return time.Duration(len(s.samples)) * time.Second / time.Duration(s.sampleRate)
+}
+
+// Read 返回下一块音频，读完后返回 io.EOF
+func (s *FileSource) Read(ctx context.Context) ([]byte, error) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil, io.EOF
+	}
+	if s.offset >= len(s.samples) {
+		s.mu.Unlock()
+		return nil, io.EOF
+	}
+	wait := time.Duration(0)
+	if s.interval > 0 {
+		now := time.Now()
+		if s.next.IsZero() {
+			s.next = now
+		}
+		wait = s.next.Sub(now)
+		s.next = s.next.Add(s.interval)
+	}
+	end := s.offset + s.chunk
+	if end > len(s.samples) {
+		end = len(s.samples)
+	}
+	chunk := s.samples[s.offset:end]
+	s.offset = end
+	s.mu.Unlock()
+
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-s.closeCh:
+			return nil, io.EOF
+		}
+	}
+
+	data := make([]byte, len(chunk)*2)
+	for i, v := range chunk {
+		binary.LittleEndian.PutUint16(data[i*2:], uint16(v))
+	}
+	return data, nil
+}
+
+// Close 关闭音频源，阻塞中的 Read 返回 io.EOF
+func (s *FileSource) Close() error {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		s.closed = true
+		s.mu.Unlock()
+		close(s.closeCh)
+	})
+	return nil
+}
+
+// DecodeFile 把音频文件解码为 cfg.SampleRate 的单声道 16-bit 样本
+// .wav 与 .pcm / .raw 直接解析，其他扩展名交给 ffmpeg 解码
+func DecodeFile(path string, cfg FileConfig) ([]int16, error) {
+	var (
+		samples  []int16
+		rate     int
+		channels int
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".wav":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		wav, err := audio.DecodeWAV(data)
+		if err != nil {
+			return nil, fmt.Errorf("decode %s: %w", path, err)
+		}
+		samples, rate, channels = wav.Samples, wav.SampleRate, wav.Channels
+	case ".pcm", ".raw":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		samples, rate, channels = pcmToInt16(audio.ConvertToS16(data, cfg.PCMFormat)), cfg.PCMSampleRate, cfg.PCMChannels
+	default:
+		decoded, err := decodeWithFFmpeg(path, cfg.SampleRate)
+		if err != nil {
+			return nil, err
+		}
+		samples, rate, channels = decoded, cfg.SampleRate, 1
+	}
+
+	samples = downmix(samples, channels)
+	if rate != cfg.SampleRate {
+		resampled, err := audio.NewLinearResampler().Resample(samples, rate, cfg.SampleRate, 1)
+		if err != nil {
+			return nil, fmt.Errorf("resample %s: %w", path, err)
+		}
+		samples = resampled
+	}
+	return samples, nil
+}
+
+// decodeWithFFmpeg 调用 ffmpeg 解码为指定采样率的单声道 s16le
+func decodeWithFFmpeg(path string, sampleRate int) ([]int16, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	ffmpeg, err := exec.LookPath("ffmpeg")
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: ffmpeg not found in PATH (required for formats other than WAV/PCM)", path)
+	}
+	cmd := exec.Command(ffmpeg, "-nostdin", "-loglevel", "error", "-i", path,
+		"-f", "s16le", "-ac", "1", "-ar", strconv.Itoa(sampleRate), "-")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() > 0 {
+			return nil, fmt.Errorf("decode %s: ffmpeg: %s", path, strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return pcmToInt16(out), nil
+}
+
+func pcmToInt16(data []byte) []int16 {
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[i*2:]))
+	}
+	return samples
+}
+
+// downmix 多声道取平均得到单声道
+func downmix(samples []int16, channels int) []int16 {
+	if channels <= 1 {
+		return samples
+	}
+	mono := make([]int16, len(samples)/channels)
+	for i := range mono {
+		sum := 0
+		for c := 0; c < channels; c++ {
+			sum += int(samples[i*channels+c])
+		}
+		mono[i] = int16(sum / channels)
+	}
+	return mono
+}
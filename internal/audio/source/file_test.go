@@ -0,0 +1,156 @@
+package source
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+// writeWAV 写出 16-bit PCM WAV 文件
+func writeWAV(t *testing.T, path string, samples []int16, sampleRate, channels int) {
+	t.Helper()
+	pcm := make([]byte, len(samples)*2)
+	for i, v := range samples {
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(v))
+	}
+	if err := os.WriteFile(path, audio.EncodeWAV(pcm, sampleRate, channels), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readAll(t *testing.T, s *FileSource) []byte {
+	t.Helper()
+	var out []byte
+	for {
+		chunk, err := s.Read(context.Background())
+		if errors.Is(err, io.EOF) {
+			return out
+		}
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		out = append(out, chunk...)
+	}
+}
+
+func TestDecodeFile(t *testing.T) {
+	dir := t.TempDir()
+
+	// 8kHz 立体声 WAV：下混为单声道并重采样到 16kHz
+	stereo := make([]int16, 8000*2)
+	for i := 0; i < 8000; i++ {
+		stereo[i*2] = 1000
+		stereo[i*2+1] = 3000
+	}
+	wavPath := filepath.Join(dir, "stereo.wav")
+	writeWAV(t, wavPath, stereo, 8000, 2)
+
+	samples, err := DecodeFile(wavPath, DefaultFileConfig())
+	if err != nil {
+		t.Fatalf("DecodeFile(wav) error = %v", err)
+	}
+	if len(samples) < 15990 || len(samples) > 16010 {
+		t.Errorf("len(samples) = %d, want about 16000", len(samples))
+	}
+	if samples[100] != 2000 {
+		t.Errorf("samples[100] = %d, want 2000 (downmixed)", samples[100])
+	}
+
+	// 裸 PCM 按配置的格式解析
+	pcm := make([]byte, 16000*2)
+	binary.LittleEndian.PutUint16(pcm[0:], uint16(1234))
+	pcmPath := filepath.Join(dir, "speech.pcm")
+	if err := os.WriteFile(pcmPath, pcm, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	samples, err = DecodeFile(pcmPath, DefaultFileConfig())
+	if err != nil {
+		t.Fatalf("DecodeFile(pcm) error = %v", err)
+	}
+	if len(samples) != 16000 || samples[0] != 1234 {
+		t.Errorf("pcm decode = len %d first %d, want len 16000 first 1234", len(samples), samples[0])
+	}
+
+	// 32-bit 浮点裸 PCM
+	f32 := audio.ConvertFromS16(pcm, audio.SampleFormatF32)
+	f32Path := filepath.Join(dir, "speech.raw")
+	if err := os.WriteFile(f32Path, f32, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f32Cfg := DefaultFileConfig()
+	f32Cfg.PCMFormat = audio.SampleFormatF32
+	samples, err = DecodeFile(f32Path, f32Cfg)
+	if err != nil {
+		t.Fatalf("DecodeFile(f32 raw) error = %v", err)
+	}
+	if len(samples) != 16000 || samples[0] != 1234 {
+		t.Errorf("f32 decode = len %d first %d, want len 16000 first 1234", len(samples), samples[0])
+	}
+
+	if _, err := DecodeFile(filepath.Join(dir, "missing.mp3"), DefaultFileConfig()); err == nil {
+		t.Error("DecodeFile(missing.mp3) expected error")
+	}
+}
+
+func TestFileSourceRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "speech.wav")
+	writeWAV(t, path, make([]int16, 16000), 16000, 1)
+
+	cfg := DefaultFileConfig()
+	cfg.Speed = 0
+	s, err := NewFileSource(path, cfg)
+	if err != nil {
+		t.Fatalf("NewFileSource() error = %v", err)
+	}
+	if s.Duration() != time.Second {
+		t.Errorf("Duration() = %v, want 1s", s.Duration())
+	}
+	first, err := s.Read(context.Background())
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(first) != 1600*2 {
+		t.Errorf("chunk = %d bytes, want %d (100ms)", len(first), 1600*2)
+	}
+	if total := len(first) + len(readAll(t, s)); total != 16000*2 {
+		t.Errorf("total = %d bytes, want %d", total, 16000*2)
+	}
+}
+
+func TestFileSourcePacing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "speech.wav")
+	writeWAV(t, path, make([]int16, 1600), 16000, 1)
+
+	cfg := DefaultFileConfig()
+	cfg.ChunkMs = 20
+	cfg.Speed = 1
+	s, err := NewFileSource(path, cfg)
+	if err != nil {
+		t.Fatalf("NewFileSource() error = %v", err)
+	}
+	start := time.Now()
+	readAll(t, s)
+	// 5 块 20ms，首块立即返回，其余按实时节奏送出
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("read 100ms of audio in %v, want paced at real time", elapsed)
+	}
+
+	s, err = NewFileSource(path, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Read(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	if _, err := s.Read(context.Background()); !errors.Is(err, io.EOF) {
+		t.Errorf("Read() after Close error = %v, want io.EOF", err)
+	}
+}
@@ -3,19 +3,17 @@ package source
 import (
 	"context"
 	"encoding/binary"
-	"fmt"
 	"io"
-	"strings"
 	"sync"
 	"time"
 
-	"github.com/gordonklaus/portaudio"
+	"github.com/liuscraft/orion-x/internal/audio"
 	"github.com/liuscraft/orion-x/internal/logging"
 )
 
 // MicrophoneSource 麦克风音频源
 type MicrophoneSource struct {
-	stream     audioStream
+	stream     audio.InputStream
 	sampleRate int
 	channels   int
 	bufferSize int
@@ -36,14 +34,6 @@ type MicrophoneSource struct {
 	mu           sync.Mutex
 }
 
-type audioStream interface {
-	Start() error
-	Read() error
-	Abort() error
-	Stop() error
-	Close() error
-}
-
 // NewMicrophoneSource 创建新的麦克风音频源
 // Note: The stream is NOT started immediately. Call Start() or Read() to start the stream.
 // This avoids input overflow errors when there's a delay between creation and first read.
@@ -58,101 +48,37 @@ func NewMicrophoneSourceWithLatency(sampleRate, channels, bufferSize int, highLa
 	return NewMicrophoneSourceWithDevice(sampleRate, channels, bufferSize, highLatency, "")
 }
 
-// NewMicrophoneSourceWithDevice 创建麦克风音频源，支持指定设备和高延迟模式
+// NewMicrophoneSourceWithDevice 通过 PortAudio 创建麦克风音频源，支持指定设备和高延迟模式
 // highLatency: 如果为 true，使用设备的默认高延迟设置（适合蓝牙设备）
 // deviceName: 设备名称（部分匹配），空字符串表示使用默认设备
 // Note: The stream is NOT started immediately. Call Start() or Read() to start the stream.
 func NewMicrophoneSourceWithDevice(sampleRate, channels, bufferSize int, highLatency bool, deviceName string) (*MicrophoneSource, error) {
-	// Note: PortAudio should be initialized by the caller before creating MicrophoneSource
-	// This avoids multiple Initialize() calls which can cause device conflicts
-	logging.Infof("MicrophoneSource: creating source (highLatency=%v, deviceName=%q)...", highLatency, deviceName)
-
-	buffer := make([]int16, bufferSize)
-
-	// 查找输入设备
-	var inputDevice *portaudio.DeviceInfo
-	var err error
-
-	if deviceName != "" {
-		// 按名称查找设备
-		inputDevice, err = findInputDeviceByName(deviceName)
-		if err != nil {
-			logging.Warnf("MicrophoneSource: device %q not found, falling back to default: %v", deviceName, err)
-			inputDevice = nil
-		}
-	}
-
-	if inputDevice == nil {
-		// 使用默认输入设备
-		inputDevice, err = portaudio.DefaultInputDevice()
-		if err != nil {
-			logging.Errorf("MicrophoneSource: failed to get default input device: %v", err)
-			// Fallback to simple stream
-			stream, err := portaudio.OpenDefaultStream(channels, 0, float64(sampleRate), len(buffer), &buffer)
-			if err != nil {
-				return nil, err
-			}
-			logging.Infof("MicrophoneSource: created with fallback (sampleRate=%d, channels=%d, bufferSize=%d)", sampleRate, channels, bufferSize)
-			return newMicrophoneSourceWithStream(stream, sampleRate, channels, bufferSize, buffer), nil
-		}
-	}
-
-	// 选择延迟模式
-	latency := inputDevice.DefaultLowInputLatency
-	latencyMode := "low"
-	if highLatency {
-		latency = inputDevice.DefaultHighInputLatency
-		latencyMode = "high"
-	}
-
-	logging.Infof("MicrophoneSource: device=%s, %s latency=%.1fms",
-		inputDevice.Name, latencyMode, latency.Seconds()*1000)
-
-	// 使用 StreamParameters 打开流，允许指定延迟
-	streamParams := portaudio.StreamParameters{
-		Input: portaudio.StreamDeviceParameters{
-			Device:   inputDevice,
-			Channels: channels,
-			Latency:  latency,
-		},
-		SampleRate:      float64(sampleRate),
+	return NewMicrophoneSourceWithBackend(audio.PortAudioBackend(), audio.StreamConfig{
+		Device:          deviceName,
+		SampleRate:      sampleRate,
+		Channels:        channels,
 		FramesPerBuffer: bufferSize,
-	}
-
-	stream, err := portaudio.OpenStream(streamParams, &buffer)
-	if err != nil {
-		logging.Errorf("MicrophoneSource: failed to open stream with params: %v, falling back to default", err)
-		// Fallback to simple stream
-		stream, err := portaudio.OpenDefaultStream(channels, 0, float64(sampleRate), len(buffer), &buffer)
-		if err != nil {
-			return nil, err
-		}
-		logging.Infof("MicrophoneSource: created with fallback (sampleRate=%d, channels=%d, bufferSize=%d)", sampleRate, channels, bufferSize)
-		return newMicrophoneSourceWithStream(stream, sampleRate, channels, bufferSize, buffer), nil
-	}
-
-	logging.Infof("MicrophoneSource: created with sampleRate=%d, channels=%d, bufferSize=%d, latency=%s (stream not started yet)",
-		sampleRate, channels, bufferSize, latencyMode)
-
-	return newMicrophoneSourceWithStream(stream, sampleRate, channels, bufferSize, buffer), nil
+		HighLatency:     highLatency,
+	})
 }
 
-// findInputDeviceByName 按名称查找输入设备（支持部分匹配）
-func findInputDeviceByName(name string) (*portaudio.DeviceInfo, error) {
-	devices, err := portaudio.Devices()
+// NewMicrophoneSourceWithBackend 通过指定的音频后端创建麦克风音频源，cfg.FramesPerBuffer 为每次 Read 的帧数
+// 设备不支持请求的格式时后端可能改用原生格式，调用方通过 Channels()/SampleRate() 获知实际格式并做声道映射和重采样；
+// cfg.Format 为采集格式，Read 总是返回 16-bit PCM
+// Note: The stream is NOT started immediately. Call Start() or Read() to start the stream.
+func NewMicrophoneSourceWithBackend(backend audio.AudioBackend, cfg audio.StreamConfig) (*MicrophoneSource, error) {
+	// Note: the backend should be initialized by the caller before creating MicrophoneSource
+	// This avoids multiple Initialize() calls which can cause device conflicts
+	logging.Infof("MicrophoneSource: creating source (backend=%s, highLatency=%v, deviceName=%q)...",
+		backend.Name(), cfg.HighLatency, cfg.Device)
+	stream, actual, err := backend.OpenInput(cfg)
 	if err != nil {
 		return nil, err
 	}
-
-	nameLower := strings.ToLower(name)
-	for _, dev := range devices {
-		if dev.MaxInputChannels > 0 && strings.Contains(strings.ToLower(dev.Name), nameLower) {
-			logging.Infof("MicrophoneSource: found device %q matching %q", dev.Name, name)
-			return dev, nil
-		}
-	}
-
-	return nil, fmt.Errorf("no input device found matching %q", name)
+	logging.Infof("MicrophoneSource: created with sampleRate=%d, channels=%d, bufferSize=%d, format=%s (stream not started yet)",
+		actual.SampleRate, actual.Channels, actual.FramesPerBuffer, actual.Format)
+	buffer := make([]int16, actual.FramesPerBuffer*actual.Channels)
+	return newMicrophoneSourceWithStream(stream, actual.SampleRate, actual.Channels, actual.FramesPerBuffer, buffer), nil
 }
 
 // Start starts the audio stream. This is called automatically on first Read(),
@@ -171,7 +97,7 @@ func (m *MicrophoneSource) Start() error {
 	return m.startErr
 }
 
-func newMicrophoneSourceWithStream(stream audioStream, sampleRate, channels, bufferSize int, buffer []int16) *MicrophoneSource {
+func newMicrophoneSourceWithStream(stream audio.InputStream, sampleRate, channels, bufferSize int, buffer []int16) *MicrophoneSource {
 	return &MicrophoneSource{
 		stream:     stream,
 		sampleRate: sampleRate,
@@ -182,6 +108,16 @@ func newMicrophoneSourceWithStream(stream audioStream, sampleRate, channels, buf
 	}
 }
 
+// SampleRate 返回实际打开的采样率（可能与请求的不同），Read 返回该采样率的 PCM
+func (m *MicrophoneSource) SampleRate() int {
+	return m.sampleRate
+}
+
+// Channels 返回实际打开的声道数（可能与请求的不同），Read 返回该声道数的交错 PCM
+func (m *MicrophoneSource) Channels() int {
+	return m.channels
+}
+
 // Read 读取音频数据
 // The stream is started automatically on first Read() if not already started.
 func (m *MicrophoneSource) Read(ctx context.Context) ([]byte, error) {
@@ -197,7 +133,7 @@ func (m *MicrophoneSource) Read(ctx context.Context) ([]byte, error) {
 	readStart := time.Now()
 	readErr := make(chan error, 1)
 	go func() {
-		readErr <- m.stream.Read()
+		readErr <- m.stream.Read(m.buffer)
 	}()
 
 	select {
@@ -260,8 +196,8 @@ func (m *MicrophoneSource) Close() error {
 
 	logging.Infof("MicrophoneSource: stream closed successfully")
 
-	// Note: We don't terminate portaudio here as it may be used by other components
-	// The program will terminate portaudio when it exits
+	// Note: We don't terminate the backend here as it may be used by other components
+	// The program will terminate it when it exits
 
 	return nil
 }
@@ -19,7 +19,7 @@ func newBlockingStream() *blockingStream {
 	}
 }
 
-func (s *blockingStream) Read() error {
+func (s *blockingStream) Read(buf []int16) error {
 	close(s.readStarted)
 	<-s.abortCalled
 	return errors.New("aborted")
@@ -2,11 +2,29 @@ package audio
 
 import (
 	"context"
+	"time"
 )
 
 // PlaybackFinishedCallback 播放完成回调
 type PlaybackFinishedCallback func()
 
+// TTSErrorCallback 单句 TTS 合成失败回调（不含打断导致的取消）
+type TTSErrorCallback func(text string, err error)
+
+// TTSErrorReporter 支持上报 TTS 合成失败的组件（TTSPipeline、AudioOutPipe 实现）
+type TTSErrorReporter interface {
+	SetOnTTSError(callback TTSErrorCallback)
+}
+
+// TTSAudioCallback 一段 TTS 完整播放后回调其音频（Mixer 格式：单声道 16-bit PCM），
+// sentences 为合并播放的句数；被打断或合成失败的段不回调
+type TTSAudioCallback func(text string, sentences int, pcm []byte)
+
+// TTSAudioRecorder 支持回调已播放 TTS 音频的组件（TTSPipeline、AudioOutPipe 实现），用于缓存回复以便重播
+type TTSAudioRecorder interface {
+	SetOnTTSAudio(callback TTSAudioCallback)
+}
+
 // TTSPipeline TTS 异步处理管道
 // 负责管理文本队列、TTS 生成队列、播放队列
 // 支持快速中断（清空所有队列）
@@ -45,6 +63,10 @@ type PipelineStats struct {
 	TotalEnqueued   int  // 总入队数
 	TotalPlayed     int  // 总播放数
 	TotalInterrupts int  // 总中断次数
+	Underruns       int  // 播放欠载次数
+	UnderrunMs      int  // 欠载插入的静音总时长（毫秒）
+	// BackpressurePauses TTS 流未读音频超过高水位、暂停接收的次数（Provider 支持 tts.BackpressureNotifier 时统计）
+	BackpressurePauses int
 }
 
 // TTSPipelineConfig TTS Pipeline 配置
@@ -65,6 +87,26 @@ type TTSPipelineConfig struct {
 	// 超出则阻塞入队（保护内存）
 	// 默认: 100
 	TextQueueSize int `json:"text_queue_size"`
+
+	// PrerollMs 播放抖动缓冲的预缓冲时长（毫秒）
+	// 每段 TTS 缓冲到该时长再开始播放，网络卡顿欠载后同样重新预缓冲
+	// 0 表示关闭抖动缓冲，Mixer 直接读取网络流
+	// 默认: 200
+	PrerollMs int `json:"preroll_ms"`
+
+	// BatchMaxChars 合并连续短句送入同一个 TTS 流的字数上限（按字符计）
+	// 减少“好的。”“嗯，”这类短句各开一个 TTS 流带来的韵律割裂和调用成本
+	// 0 表示不合并
+	// 默认: 60
+	BatchMaxChars int `json:"batch_max_chars"`
+
+	// BatchMinChars 合并后仍短于该字数时，最多等待 BatchWaitMs 接收下一句
+	// 默认: 10
+	BatchMinChars int `json:"batch_min_chars"`
+
+	// BatchWaitMs 短句等待后续句子的最长时间（毫秒），0 表示只合并已在队列中的句子
+	// 默认: 150
+	BatchWaitMs int `json:"batch_wait_ms"`
 }
 
 // DefaultTTSPipelineConfig 默认 TTS Pipeline 配置
@@ -73,11 +115,42 @@ func DefaultTTSPipelineConfig() *TTSPipelineConfig {
 		MaxTTSBuffer:     3,
 		MaxConcurrentTTS: 2,
 		TextQueueSize:    100,
+		PrerollMs:        200,
+		BatchMaxChars:    60,
+		BatchMinChars:    10,
+		BatchWaitMs:      150,
 	}
 }
 
+// TTSPriority TTS 入队优先级
+type TTSPriority int
+
+const (
+	// TTSPriorityNormal 普通句（LLM 回复），按入队顺序播放
+	TTSPriorityNormal TTSPriority = iota
+	// TTSPriorityUrgent 紧急播报（致歉、提醒等系统提示），排在所有尚未开始播放的普通句之前，紧急项之间按入队顺序播放；
+	// 紧急项不属于回复，不回调播放完成、播放时间点、播放音频和合成失败
+	TTSPriorityUrgent
+)
+
+// SpeechOptions 按优先级入队的选项
+type SpeechOptions struct {
+	Priority TTSPriority
+	// Preempt 紧急项入队时停止正在播放的普通句（淡出后不再续播，照常回调播放完成），普通优先级时忽略
+	Preempt  bool
+	Emotion  string
+	Language string
+	// Audio 预先合成的音频（Mixer 格式），非空时直接播放、不调用 TTS
+	Audio []byte
+}
+
 // textItem 文本队列项
 type textItem struct {
-	Text    string
-	Emotion string
+	Text       string
+	Emotion    string
+	Language   string // 回复语言，用于选择音色，为空时只按情绪选择
+	EnqueuedAt time.Time
+	Count      int             // 合并的句子数
+	Audio      []byte          // 预先合成的音频（Mixer 格式），非空时直接播放、不调用 TTS
+	Ctx        context.Context // 调用方的 ctx，只用于日志关联（trace / 轮次 ID），取消仍通过 Interrupt
 }
@@ -1,12 +1,15 @@
 package audio
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
 	"sync"
 	"sync/atomic"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/liuscraft/orion-x/internal/logging"
 	"github.com/liuscraft/orion-x/internal/tts"
@@ -50,10 +53,113 @@ func (r *eofNotifyReader) Close() {
 type ttsItem struct {
 	Reader     *eofNotifyReader // 带 EOF 通知的 reader
 	OrigReader io.Reader        // 原始 reader（用于关闭）
+	Jitter     *jitterBuffer    // 播放抖动缓冲，未启用时为 nil
+	Count      int              // 合并的句子数，播放完成时按句回调
+	Text       string           // 本段文本（合并的多句已拼接）
+	Capture    *captureReader   // 设置了 onTTSAudio 时记录本段音频，未设置时为 nil
 	Emotion    string
-	DoneCh     chan struct{} // 播放完成信号
-	StreamID   int64         // 用于追踪
-	SeqNum     int64         // 序号，用于保证播放顺序
+	Ctx        context.Context // 日志关联用的 ctx，见 textItem.Ctx
+	DoneCh     chan struct{}   // 播放完成信号
+	StreamID   int64           // 用于追踪
+	SeqNum     int64           // 序号，用于保证播放顺序
+	Urgent     bool            // 紧急通道的项，不会被抢占，不回调播放完成
+}
+
+// close 关闭 reader，解除 playItem 和 Mixer 的阻塞
+func (item *ttsItem) close() {
+	item.Reader.Close()
+	if closer, ok := item.OrigReader.(io.Closer); ok {
+		closer.Close()
+	}
+}
+
+// ttsLane 一个优先级的通道：文本队列 → 并发合成 → 按序号排序 → TTS 缓冲，同一通道内按入队顺序播放
+type ttsLane struct {
+	urgent    bool
+	textQueue chan textItem
+	ttsBuffer chan *ttsItem
+
+	// 顺序控制：保证 TTS 按入队顺序播放
+	pendingMu      sync.Mutex
+	nextSeqNum     int64              // 下一个要分配的序号
+	nextPlaySeqNum int64              // 下一个要播放的序号
+	pendingItems   map[int64]*ttsItem // 已完成但等待播放的 TTS 项
+
+	// outstanding 已入队、尚未进入 ttsBuffer 的项数（仅紧急通道统计），不为 0 时播放器不开始新的普通句
+	outstanding int64
+	settled     chan struct{} // 项合成失败或被取消、不会进入 ttsBuffer 时通知播放器
+}
+
+func newTTSLane(queueSize, bufferSize int, urgent bool) *ttsLane {
+	return &ttsLane{
+		urgent:         urgent,
+		textQueue:      make(chan textItem, queueSize),
+		ttsBuffer:      make(chan *ttsItem, bufferSize),
+		nextSeqNum:     1,
+		nextPlaySeqNum: 1,
+		pendingItems:   make(map[int64]*ttsItem),
+		settled:        make(chan struct{}, 1),
+	}
+}
+
+// nextSeq 分配序号
+func (l *ttsLane) nextSeq() int64 {
+	l.pendingMu.Lock()
+	defer l.pendingMu.Unlock()
+	seqNum := l.nextSeqNum
+	l.nextSeqNum++
+	return seqNum
+}
+
+// settle 一个紧急项离开合成阶段（进入 ttsBuffer、失败或被取消），通知等待中的播放器
+func (l *ttsLane) settle() {
+	if !l.urgent {
+		return
+	}
+	for {
+		n := atomic.LoadInt64(&l.outstanding)
+		if n <= 0 || atomic.CompareAndSwapInt64(&l.outstanding, n, n-1) {
+			break
+		}
+	}
+	select {
+	case l.settled <- struct{}{}:
+	default:
+	}
+}
+
+// clear 清空文本队列、等待排序的项和 TTS 缓冲（关闭未播放的 reader），并重置序号
+func (l *ttsLane) clear() {
+drainText:
+	for {
+		select {
+		case <-l.textQueue:
+		default:
+			break drainText
+		}
+	}
+
+	l.pendingMu.Lock()
+	for _, item := range l.pendingItems {
+		if item != nil {
+			item.close()
+		}
+	}
+	l.pendingItems = make(map[int64]*ttsItem)
+	l.nextSeqNum = 1
+	l.nextPlaySeqNum = 1
+	l.pendingMu.Unlock()
+
+drainBuffer:
+	for {
+		select {
+		case item := <-l.ttsBuffer:
+			item.close()
+		default:
+			break drainBuffer
+		}
+	}
+	atomic.StoreInt64(&l.outstanding, 0)
 }
 
 // ttsPipelineImpl TTSPipeline 实现
@@ -62,26 +168,26 @@ type ttsPipelineImpl struct {
 	provider    tts.Provider
 	ttsConfig   tts.Config
 	voiceMap    map[string]string
+	prosodyMap  map[string]tts.Prosody // 情绪 → 韵律，键与 voiceMap 相同，为 0 的字段沿用 ttsConfig
 	mixerConfig *MixerConfig
+	ssml        *tts.SSMLBuilder // 非空且开启 EnableSSML 时，句子会被包装为 SSML
 
 	// 外部依赖（可动态设置）
 	mixer              AudioMixer
 	reference          ReferenceSink
 	onPlaybackFinished PlaybackFinishedCallback
+	onTTSTiming        TTSTimingCallback
+	onTTSError         TTSErrorCallback
+	onTTSAudio         TTSAudioCallback
 
-	// 队列
-	textQueue chan textItem
-	ttsBuffer chan *ttsItem
+	// 通道：normal 为 LLM 回复等普通句，urgent 为紧急播报，播放器优先播放 urgent
+	normal    *ttsLane
+	urgent    *ttsLane
+	preemptCh chan struct{} // 紧急项要求停止正在播放的普通句
 
-	// 并发控制
+	// 并发控制（只限制普通通道，紧急项不排在普通句的合成之后）
 	ttsSemaphore chan struct{}
 
-	// 顺序控制：保证 TTS 按入队顺序播放
-	nextSeqNum     int64              // 下一个要分配的序号
-	nextPlaySeqNum int64              // 下一个要播放的序号
-	pendingItems   map[int64]*ttsItem // 已完成但等待播放的 TTS 项
-	pendingMu      sync.Mutex         // 保护 pendingItems
-
 	// 状态
 	currentItem   *ttsItem
 	parentCtx     context.Context
@@ -98,6 +204,9 @@ type ttsPipelineImpl struct {
 	totalEnqueued   int64
 	totalPlayed     int64
 	totalInterrupts int64
+	totalUnderruns  int64
+	totalUnderrunMs int64
+	totalPauses     int64
 }
 
 // NewTTSPipeline 创建新的 TTS Pipeline
@@ -121,17 +230,15 @@ func NewTTSPipeline(
 	}
 
 	return &ttsPipelineImpl{
-		config:         config,
-		provider:       provider,
-		ttsConfig:      ttsConfig,
-		voiceMap:       voiceMap,
-		mixerConfig:    mixerConfig,
-		textQueue:      make(chan textItem, config.TextQueueSize),
-		ttsBuffer:      make(chan *ttsItem, config.MaxTTSBuffer),
-		ttsSemaphore:   make(chan struct{}, config.MaxConcurrentTTS),
-		nextSeqNum:     1,
-		nextPlaySeqNum: 1,
-		pendingItems:   make(map[int64]*ttsItem),
+		config:       config,
+		provider:     provider,
+		ttsConfig:    ttsConfig,
+		voiceMap:     voiceMap,
+		mixerConfig:  mixerConfig,
+		normal:       newTTSLane(config.TextQueueSize, config.MaxTTSBuffer, false),
+		urgent:       newTTSLane(config.TextQueueSize, config.MaxTTSBuffer, true),
+		preemptCh:    make(chan struct{}, 1),
+		ttsSemaphore: make(chan struct{}, config.MaxConcurrentTTS),
 	}
 }
 
@@ -156,8 +263,9 @@ func (p *ttsPipelineImpl) Start(ctx context.Context) error {
 
 func (p *ttsPipelineImpl) startWorkers() {
 	// Text Consumer - 从文本队列取出，启动 TTS Worker
-	p.wg.Add(1)
-	go p.textConsumer()
+	p.wg.Add(2)
+	go p.textConsumer(p.normal)
+	go p.textConsumer(p.urgent)
 
 	// Audio Player - 从 TTS 缓冲区取出，播放
 	p.wg.Add(1)
@@ -192,34 +300,19 @@ func (p *ttsPipelineImpl) Stop() error {
 
 	// 启动一个 goroutine 持续清空 ttsBuffer，直到被通知停止
 	// 这样 ttsWorker 中的 notifySeqCompleted 才不会因为 buffer 满而阻塞
+	// 停止后剩余的项由 clearQueues 关闭
 	stopDrainer := make(chan struct{})
 	drainerDone := make(chan struct{})
 	go func() {
 		defer close(drainerDone)
-		drained := 0
 		for {
 			select {
 			case <-stopDrainer:
-				// 被通知停止，做最后一次清理
-				for {
-					select {
-					case item := <-p.ttsBuffer:
-						item.Reader.Close()
-						if closer, ok := item.OrigReader.(io.Closer); ok {
-							closer.Close()
-						}
-						drained++
-					default:
-						return
-					}
-				}
-			case item := <-p.ttsBuffer:
-				// 消费 buffer 中的 item
-				item.Reader.Close()
-				if closer, ok := item.OrigReader.(io.Closer); ok {
-					closer.Close()
-				}
-				drained++
+				return
+			case item := <-p.normal.ttsBuffer:
+				item.close()
+			case item := <-p.urgent.ttsBuffer:
+				item.close()
 			}
 		}
 	}()
@@ -243,10 +336,51 @@ func (p *ttsPipelineImpl) Stop() error {
 }
 
 func (p *ttsPipelineImpl) EnqueueText(text string, emotion string) error {
+	return p.EnqueueTextWithLanguage(text, emotion, "")
+}
+
+// EnqueueTextWithLanguage 入队文本，并按 language 选择该语言的音色
+func (p *ttsPipelineImpl) EnqueueTextWithLanguage(text, emotion, language string) error {
+	return p.EnqueueTextContext(context.Background(), text, emotion, language)
+}
+
+// EnqueueTextContext 入队文本，ctx 中的 trace / 轮次 ID 用于该句的合成与播放日志
+func (p *ttsPipelineImpl) EnqueueTextContext(logCtx context.Context, text, emotion, language string) error {
 	if text == "" {
 		return nil
 	}
+	return p.enqueue(p.normal, textItem{Text: text, Emotion: emotion, Language: language, Ctx: logCtx})
+}
+
+// EnqueueAudioContext 入队预先合成的音频（Mixer 格式），与文本句子按序播放，不调用 TTS 也不与其他句子合并
+func (p *ttsPipelineImpl) EnqueueAudioContext(logCtx context.Context, text string, pcm []byte) error {
+	if len(pcm) == 0 {
+		return nil
+	}
+	return p.enqueue(p.normal, textItem{Text: text, Audio: pcm, Ctx: logCtx})
+}
 
+// EnqueueWithOptions 按优先级入队文本或预先合成的音频：紧急项排在尚未开始播放的普通句之前，
+// opts.Preempt 时停止正在播放的普通句
+func (p *ttsPipelineImpl) EnqueueWithOptions(logCtx context.Context, text string, opts SpeechOptions) error {
+	if text == "" && len(opts.Audio) == 0 {
+		return nil
+	}
+	item := textItem{Text: text, Emotion: opts.Emotion, Language: opts.Language, Audio: opts.Audio, Ctx: logCtx}
+	if opts.Priority != TTSPriorityUrgent {
+		return p.enqueue(p.normal, item)
+	}
+	if err := p.enqueue(p.urgent, item); err != nil {
+		return err
+	}
+	if opts.Preempt {
+		p.preemptNormal()
+	}
+	return nil
+}
+
+// enqueue 把一项放入通道的文本队列，队列满时阻塞
+func (p *ttsPipelineImpl) enqueue(lane *ttsLane, item textItem) error {
 	p.mu.Lock()
 	ctx := p.ctx
 	if !p.started {
@@ -255,15 +389,38 @@ func (p *ttsPipelineImpl) EnqueueText(text string, emotion string) error {
 	}
 	p.mu.Unlock()
 
+	item.EnqueuedAt = time.Now()
+	item.Count = 1
+	if lane.urgent {
+		// 先计数再入队，播放器看到计数后不会在紧急项合成期间开始新的普通句
+		atomic.AddInt64(&lane.outstanding, 1)
+	}
 	select {
 	case <-ctx.Done():
+		if lane.urgent {
+			lane.settle()
+		}
 		return ctx.Err()
-	case p.textQueue <- textItem{Text: text, Emotion: emotion}:
+	case lane.textQueue <- item:
 		atomic.AddInt64(&p.totalEnqueued, 1)
 		return nil
 	}
 }
 
+// preemptNormal 正在播放普通句时通知播放器停止该句
+func (p *ttsPipelineImpl) preemptNormal() {
+	p.mu.Lock()
+	playingNormal := p.currentItem != nil && !p.currentItem.Urgent
+	p.mu.Unlock()
+	if !playingNormal {
+		return
+	}
+	select {
+	case p.preemptCh <- struct{}{}:
+	default:
+	}
+}
+
 func (p *ttsPipelineImpl) Interrupt() error {
 	// 使用独立的互斥锁防止并发 Interrupt 调用
 	p.interruptMu.Lock()
@@ -307,17 +464,10 @@ func (p *ttsPipelineImpl) Interrupt() error {
 	// 4. 等待所有 worker 退出
 	p.wg.Wait()
 
-	// 5. 清空队列
+	// 5. 清空队列并重置序号
 	p.clearQueues()
 
-	// 6. 重置序号计数器
-	p.pendingMu.Lock()
-	p.nextSeqNum = 1
-	p.nextPlaySeqNum = 1
-	p.pendingItems = make(map[int64]*ttsItem)
-	p.pendingMu.Unlock()
-
-	// 7. 重新创建 context 和 workers
+	// 6. 重新创建 context 和 workers
 	p.mu.Lock()
 	if p.parentCtx != nil && p.parentCtx.Err() == nil {
 		p.ctx, p.cancel = context.WithCancel(p.parentCtx)
@@ -337,12 +487,16 @@ func (p *ttsPipelineImpl) Stats() PipelineStats {
 	p.mu.Unlock()
 
 	return PipelineStats{
-		TextQueueSize:   len(p.textQueue),
-		TTSBufferSize:   len(p.ttsBuffer),
+		TextQueueSize:   len(p.normal.textQueue) + len(p.urgent.textQueue),
+		TTSBufferSize:   len(p.normal.ttsBuffer) + len(p.urgent.ttsBuffer),
 		IsPlaying:       isPlaying,
 		TotalEnqueued:   int(atomic.LoadInt64(&p.totalEnqueued)),
 		TotalPlayed:     int(atomic.LoadInt64(&p.totalPlayed)),
 		TotalInterrupts: int(atomic.LoadInt64(&p.totalInterrupts)),
+		Underruns:       int(atomic.LoadInt64(&p.totalUnderruns)),
+		UnderrunMs:      int(atomic.LoadInt64(&p.totalUnderrunMs)),
+
+		BackpressurePauses: int(atomic.LoadInt64(&p.totalPauses)),
 	}
 }
 
@@ -364,57 +518,196 @@ func (p *ttsPipelineImpl) SetOnPlaybackFinished(callback PlaybackFinishedCallbac
 	p.onPlaybackFinished = callback
 }
 
+// SetOnTTSTiming 设置每句开始播放时的时间点回调
+func (p *ttsPipelineImpl) SetOnTTSTiming(callback TTSTimingCallback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onTTSTiming = callback
+}
+
+// SetOnTTSError 设置单句合成失败回调
+func (p *ttsPipelineImpl) SetOnTTSError(callback TTSErrorCallback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onTTSError = callback
+}
+
+// SetOnTTSAudio 设置已完整播放的 TTS 音频回调，设置后每段 TTS 的音频都会在内存中记录到播放结束
+func (p *ttsPipelineImpl) SetOnTTSAudio(callback TTSAudioCallback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onTTSAudio = callback
+}
+
+// SetSSMLBuilder 设置 SSML 生成器（仅在 tts.Config.EnableSSML 为 true 时生效）
+func (p *ttsPipelineImpl) SetSSMLBuilder(builder *tts.SSMLBuilder) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ssml = builder
+}
+
+// SetEmotionProsody 设置按情绪调整的语速、音调与音量，查找顺序与音色相同（见 VoiceKey）
+func (p *ttsPipelineImpl) SetEmotionProsody(prosody map[string]tts.Prosody) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.prosodyMap = prosody
+}
+
 // textConsumer 文本消费者 goroutine
-// 从 textQueue 取出文本，分配序号，启动 TTS Worker 生成音频
-func (p *ttsPipelineImpl) textConsumer() {
+// 从通道的 textQueue 取出文本，分配序号，启动 TTS Worker 生成音频；紧急通道不合并句子
+func (p *ttsPipelineImpl) textConsumer(lane *ttsLane) {
 	defer p.wg.Done()
 
+	// carry 为合并时因情绪不同或超出字数而留到下一批的句子
+	var carry *textItem
 	for {
-		select {
-		case <-p.ctx.Done():
+		var item textItem
+		if carry != nil {
+			item, carry = *carry, nil
+		} else {
+			select {
+			case <-p.ctx.Done():
+				return
+			case item = <-lane.textQueue:
+			}
+		}
+
+		if !lane.urgent {
+			item, carry = p.batchText(item)
+		}
+		if p.ctx.Err() != nil {
 			return
-		case item := <-p.textQueue:
-			// 分配序号（保证顺序）
-			p.pendingMu.Lock()
-			seqNum := p.nextSeqNum
-			p.nextSeqNum++
-			p.pendingMu.Unlock()
+		}
 
-			// 启动 TTS Worker（受 semaphore 限制）
-			p.wg.Add(1)
-			go p.ttsWorker(item, seqNum)
+		// 分配序号（保证顺序），启动 TTS Worker
+		p.wg.Add(1)
+		go p.ttsWorker(lane, item, lane.nextSeq())
+	}
+}
+
+// batchText 把后续同情绪的句子合并进 first，直到达到 BatchMaxChars
+// 合并结果短于 BatchMinChars 时最多等待 BatchWaitMs；返回不能合并的下一句（若有）
+func (p *ttsPipelineImpl) batchText(first textItem) (textItem, *textItem) {
+	maxChars := p.config.BatchMaxChars
+	if maxChars <= 0 || first.Audio != nil {
+		return first, nil
+	}
+
+	batch := first
+	chars := utf8.RuneCountInString(batch.Text)
+	var timeout <-chan time.Time
+	for chars < maxChars {
+		var next textItem
+		select {
+		case next = <-p.normal.textQueue:
+		default:
+			if chars >= p.config.BatchMinChars || p.config.BatchWaitMs <= 0 {
+				return batch, nil
+			}
+			if timeout == nil {
+				timer := time.NewTimer(time.Duration(p.config.BatchWaitMs) * time.Millisecond)
+				defer timer.Stop()
+				timeout = timer.C
+			}
+			select {
+			case <-p.ctx.Done():
+				return batch, nil
+			case <-timeout:
+				return batch, nil
+			case next = <-p.normal.textQueue:
+			}
+		}
+
+		nextChars := utf8.RuneCountInString(next.Text)
+		if next.Audio != nil || next.Emotion != batch.Emotion || next.Language != batch.Language || chars+nextChars > maxChars ||
+			!sameTurn(next.Ctx, batch.Ctx) {
+			return batch, &next
 		}
+		batch.Text = joinSentences(batch.Text, next.Text)
+		batch.Count += next.Count
+		chars += nextChars
+	}
+	return batch, nil
+}
+
+// sameTurn 两句是否属于同一轮（未携带轮次 ID 时视为同一轮）
+func sameTurn(a, b context.Context) bool {
+	turnA, _ := logging.TurnFromContext(a)
+	turnB, _ := logging.TurnFromContext(b)
+	return turnA == turnB
+}
+
+// joinSentences 拼接两句，西文之间补一个空格，中文直接相连
+func joinSentences(a, b string) string {
+	last, _ := utf8.DecodeLastRuneInString(a)
+	first, _ := utf8.DecodeRuneInString(b)
+	if last < utf8.RuneSelf && first < utf8.RuneSelf && !unicode.IsSpace(last) && !unicode.IsSpace(first) {
+		return a + " " + b
 	}
+	return a + b
 }
 
 // ttsWorker TTS 生成 worker
 // 生成 TTS 音频流，通过 pendingItems 保证顺序
-func (p *ttsPipelineImpl) ttsWorker(item textItem, seqNum int64) {
+func (p *ttsPipelineImpl) ttsWorker(lane *ttsLane, item textItem, seqNum int64) {
 	defer p.wg.Done()
 
-	// 获取 semaphore（限制并发数）
-	select {
-	case <-p.ctx.Done():
-		// 被取消，需要通知可能在等待的 audioPlayer
-		p.notifySeqCompleted(seqNum, nil)
-		return
-	case p.ttsSemaphore <- struct{}{}:
-		defer func() { <-p.ttsSemaphore }()
+	// 获取 semaphore（限制并发数），紧急项不受限制
+	if !lane.urgent {
+		select {
+		case <-p.ctx.Done():
+			// 被取消，需要通知可能在等待的 audioPlayer
+			p.notifySeqCompleted(lane, seqNum, nil)
+			return
+		case p.ttsSemaphore <- struct{}{}:
+			defer func() { <-p.ttsSemaphore }()
+		}
 	}
 
 	streamID := atomic.AddInt64(&p.streamCounter, 1)
 
-	// 生成 TTS
-	reader, err := p.generateTTS(p.ctx, item.Text, item.Emotion)
+	// 生成 TTS，预先合成的音频直接播放
+	var reader io.Reader
+	var err error
+	if item.Audio != nil {
+		reader = bytes.NewReader(item.Audio)
+	} else {
+		reader, err = p.generateTTS(logging.CopyTurn(p.ctx, item.Ctx), item.Text, item.Emotion, item.Language)
+	}
 	if err != nil {
 		if !errors.Is(err, context.Canceled) {
-			logging.Errorf("TTSPipeline: [stream-%d seq-%d] TTS generation error: %v", streamID, seqNum, err)
+			logging.ErrorfCtx(item.Ctx, "TTSPipeline: [stream-%d seq-%d] TTS generation error: %v", streamID, seqNum, err)
+			p.mu.Lock()
+			errorCallback := p.onTTSError
+			p.mu.Unlock()
+			if errorCallback != nil && !lane.urgent {
+				errorCallback(item.Text, err)
+			}
 		}
 		// 通知序号完成（即使失败），让后续序号可以继续
-		p.notifySeqCompleted(seqNum, nil)
+		p.notifySeqCompleted(lane, seqNum, nil)
 		return
 	}
 
+	// 紧急项是系统播报，不属于回复，不回调播放时间点、播放音频和播放完成
+	var timingCallback TTSTimingCallback
+	recording := false
+	if !lane.urgent {
+		p.mu.Lock()
+		timingCallback = p.onTTSTiming
+		recording = p.onTTSAudio != nil
+		p.mu.Unlock()
+	}
+	// 在抖动缓冲之前记录，欠载时插入的静音不计入
+	var capture *captureReader
+	if recording {
+		capture = &captureReader{reader: reader}
+		reader = capture
+	}
+	timing := newTTSTimingRecorder(item.Text, item.EnqueuedAt, timingCallback)
+	reader = &firstReadReader{reader: reader, onFirst: timing.FirstByte}
+	reader, jitter := p.playbackReader(reader, timing.Playback)
+
 	// 创建带 EOF 通知的 reader
 	notifyReader := newEOFNotifyReader(reader)
 
@@ -422,40 +715,47 @@ func (p *ttsPipelineImpl) ttsWorker(item textItem, seqNum int64) {
 	ttsItem := &ttsItem{
 		Reader:     notifyReader,
 		OrigReader: reader,
+		Jitter:     jitter,
+		Count:      item.Count,
+		Text:       item.Text,
+		Capture:    capture,
 		Emotion:    item.Emotion,
+		Ctx:        item.Ctx,
 		DoneCh:     make(chan struct{}),
 		StreamID:   streamID,
 		SeqNum:     seqNum,
+		Urgent:     lane.urgent,
 	}
 
 	// 通知序号完成，放入 pending 等待按序播放
-	p.notifySeqCompleted(seqNum, ttsItem)
+	p.notifySeqCompleted(lane, seqNum, ttsItem)
 }
 
-// notifySeqCompleted 通知某个序号的 TTS 已完成（成功或失败）
-func (p *ttsPipelineImpl) notifySeqCompleted(seqNum int64, item *ttsItem) {
-	p.pendingMu.Lock()
+// notifySeqCompleted 通知通道中某个序号的 TTS 已完成（成功或失败）
+func (p *ttsPipelineImpl) notifySeqCompleted(lane *ttsLane, seqNum int64, item *ttsItem) {
+	lane.pendingMu.Lock()
 
-	if item != nil {
-		p.pendingItems[seqNum] = item
-	}
+	lane.pendingItems[seqNum] = item
 
 	// 收集需要按顺序发送的 items
 	var itemsToSend []*ttsItem
 	for {
-		nextItem, ok := p.pendingItems[p.nextPlaySeqNum]
+		nextItem, ok := lane.pendingItems[lane.nextPlaySeqNum]
 		if !ok {
 			// 下一个序号还没完成，等待
 			break
 		}
-		delete(p.pendingItems, p.nextPlaySeqNum)
-		p.nextPlaySeqNum++
+		delete(lane.pendingItems, lane.nextPlaySeqNum)
+		lane.nextPlaySeqNum++
 
 		if nextItem != nil {
 			itemsToSend = append(itemsToSend, nextItem)
 		}
 	}
-	p.pendingMu.Unlock()
+	lane.pendingMu.Unlock()
+	if item == nil {
+		lane.settle()
+	}
 
 	// 在锁外按顺序同步发送到 ttsBuffer
 	for _, itm := range itemsToSend {
@@ -467,22 +767,38 @@ func (p *ttsPipelineImpl) notifySeqCompleted(seqNum int64, item *ttsItem) {
 				closer.Close()
 			}
 			return
-		case p.ttsBuffer <- itm:
+		case lane.ttsBuffer <- itm:
 			// 成功入队
+			lane.settle()
 		}
 	}
 }
 
 // audioPlayer 音频播放器 goroutine
-// 从 ttsBuffer 取出 TTS 流，播放
+// 从 ttsBuffer 取出 TTS 流，播放；紧急项优先，紧急项合成期间不开始新的普通句
 func (p *ttsPipelineImpl) audioPlayer() {
 	defer p.wg.Done()
 
 	for {
+		select {
+		case item := <-p.urgent.ttsBuffer:
+			p.playItem(item)
+			continue
+		default:
+		}
+
+		normal := p.normal.ttsBuffer
+		if atomic.LoadInt64(&p.urgent.outstanding) > 0 {
+			normal = nil
+		}
 		select {
 		case <-p.ctx.Done():
 			return
-		case item := <-p.ttsBuffer:
+		case <-p.urgent.settled:
+			// 紧急项进入缓冲或失败，重新检查
+		case item := <-p.urgent.ttsBuffer:
+			p.playItem(item)
+		case item := <-normal:
 			p.playItem(item)
 		}
 	}
@@ -490,6 +806,16 @@ func (p *ttsPipelineImpl) audioPlayer() {
 
 // playItem 播放单个 TTS 流
 func (p *ttsPipelineImpl) playItem(item *ttsItem) {
+	// 之前的抢占请求针对已结束的句子，先丢弃再设置当前项；紧急项不会被抢占
+	select {
+	case <-p.preemptCh:
+	default:
+	}
+	var preempt <-chan struct{}
+	if !item.Urgent {
+		preempt = p.preemptCh
+	}
+
 	p.mu.Lock()
 	p.currentItem = item
 	mixer := p.mixer
@@ -504,12 +830,23 @@ func (p *ttsPipelineImpl) playItem(item *ttsItem) {
 	// 等待播放完成：Mixer 读取到 EOF 时，item.Reader.Done() 会被关闭
 	select {
 	case <-p.ctx.Done():
-		// 被打断，确保通知 reader done
+		// 被打断：先等 Mixer 淡出并移除该流，再关闭 reader，否则淡出读不到音频
+		if mixer != nil {
+			mixer.RemoveTTSStream()
+		}
+		// 确保通知 reader done
 		item.Reader.Close()
 		// 同时关闭原始 reader，解除可能的读取阻塞
 		if closer, ok := item.OrigReader.(io.Closer); ok {
 			closer.Close()
 		}
+	case <-preempt:
+		// 被紧急项抢占：淡出后停止该句，不再续播
+		logging.InfofCtx(item.Ctx, "TTSPipeline: [stream-%d seq-%d] preempted by urgent speech", item.StreamID, item.SeqNum)
+		if mixer != nil {
+			mixer.RemoveTTSStream()
+		}
+		item.close()
 	case <-item.Reader.Done():
 		// Mixer 读取完毕
 	}
@@ -529,25 +866,70 @@ func (p *ttsPipelineImpl) playItem(item *ttsItem) {
 		closer.Close()
 	}
 
-	atomic.AddInt64(&p.totalPlayed, 1)
+	if item.Jitter != nil {
+		if underruns, silentBytes := item.Jitter.Stats(); underruns > 0 {
+			silentMs := int64(silentBytes) * 1000 / int64(p.systemSampleRate()*2)
+			atomic.AddInt64(&p.totalUnderruns, int64(underruns))
+			atomic.AddInt64(&p.totalUnderrunMs, silentMs)
+			logging.WarnfCtx(item.Ctx, "TTSPipeline: [stream-%d seq-%d] playback underrun %d time(s), %dms silence inserted",
+				item.StreamID, item.SeqNum, underruns, silentMs)
+		}
+	}
+
+	count := item.Count
+	if count <= 0 {
+		count = 1
+	}
+	atomic.AddInt64(&p.totalPlayed, int64(count))
 	close(item.DoneCh)
 
-	// 通知播放完成
+	// 通知播放完成（合并的每一句各通知一次，与入队次数对应），完整播放的音频先于播放完成回调
 	p.mu.Lock()
 	callback := p.onPlaybackFinished
+	audioCallback := p.onTTSAudio
 	p.mu.Unlock()
-	if callback != nil {
-		callback()
+	if audioCallback != nil && item.Capture != nil {
+		if pcm, complete := item.Capture.Audio(); complete {
+			audioCallback(item.Text, count, pcm)
+		}
+	}
+	if callback != nil && !item.Urgent {
+		for i := 0; i < count; i++ {
+			callback()
+		}
 	}
 }
 
 // generateTTS 生成 TTS 音频流
-func (p *ttsPipelineImpl) generateTTS(ctx context.Context, text string, emotion string) (io.Reader, error) {
-	voice := p.getVoice(emotion)
+func (p *ttsPipelineImpl) generateTTS(ctx context.Context, text, emotion, language string) (io.Reader, error) {
+	voice := p.getVoice(emotion, language)
 
 	cfg := p.ttsConfig
 	cfg.Voice = voice
 
+	p.mu.Lock()
+	ssml := p.ssml
+	prosody, _ := lookupEmotion(p.prosodyMap, emotion, language)
+	p.mu.Unlock()
+	if prosody.Rate > 0 {
+		cfg.Rate = prosody.Rate
+	}
+	if prosody.Pitch > 0 {
+		cfg.Pitch = prosody.Pitch
+	}
+	if prosody.Volume > 0 {
+		cfg.Volume = prosody.Volume
+	}
+	// 只有 SSMLBuilder 生成的文档原样发送，其余文本由 Stream 转义
+	var doc tts.SSML
+	if cfg.EnableSSML && ssml != nil {
+		doc = ssml.Build(text, emotion, voice, tts.Prosody{
+			Rate:   cfg.Rate,
+			Pitch:  cfg.Pitch,
+			Volume: cfg.Volume,
+		})
+	}
+
 	// 创建带超时的 context
 	ttsCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
@@ -558,98 +940,117 @@ func (p *ttsPipelineImpl) generateTTS(ctx context.Context, text string, emotion
 		return nil, err
 	}
 
-	// 写入文本
-	if err := stream.WriteTextChunk(ttsCtx, text); err != nil {
-		stream.Close(ttsCtx)
-		return nil, err
+	// Provider 输出 float32 / 24-bit 等格式时先转换为 16-bit
+	format := SampleFormatS16
+	if formatter, ok := stream.(tts.SampleFormatter); ok {
+		if format, err = ParseSampleFormat(formatter.SampleFormat()); err != nil {
+			stream.Close(ttsCtx)
+			return nil, err
+		}
 	}
 
-	// 关闭写入（通知 TTS 服务文本发送完毕）
-	if err := stream.Close(ttsCtx); err != nil {
+	if notifier, ok := stream.(tts.BackpressureNotifier); ok {
+		notifier.OnBackpressure(func(paused bool) {
+			if paused {
+				atomic.AddInt64(&p.totalPauses, 1)
+				logging.DebugfCtx(ctx, "TTSPipeline: audio buffer above high watermark, pausing TTS receive")
+			}
+		})
+	}
+
+	// 写入文本，Stream 不支持 tts.SSMLWriter 时按纯文本发送
+	if writer, ok := stream.(tts.SSMLWriter); ok && doc != "" {
+		err = writer.WriteSSML(ttsCtx, doc)
+	} else {
+		err = stream.WriteTextChunk(ttsCtx, text)
+	}
+	if err != nil {
+		stream.Close(ttsCtx)
 		return nil, err
 	}
 
+	// 后台关闭写入（通知 TTS 服务文本发送完毕）并等待合成结束：
+	// Stream 的音频缓冲有上限，若等合成结束再把 reader 交给 Mixer，音频超过缓冲容量时两边会互相等待
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		if err := stream.Close(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			logging.ErrorfCtx(ctx, "TTSPipeline: TTS finish error: %v", err)
+		}
+	}()
+
 	// 获取音频 reader
 	audioReader := stream.AudioReader()
 
-	// 检测采样率并进行重采样
-	ttsSampleRate := stream.SampleRate()
-	ttsChannels := stream.Channels()
-	systemSampleRate := 16000
-	if p.mixerConfig != nil && p.mixerConfig.SampleRate > 0 {
-		systemSampleRate = p.mixerConfig.SampleRate
-	}
+	// 转换为 Mixer 采样率的 16-bit 单声道
+	reader := NewMixerStreamReader(audioReader, stream.SampleRate(), stream.Channels(), format, p.systemSampleRate())
+	return reader, nil
+}
 
-	var reader io.Reader = audioReader
-	if ttsSampleRate != systemSampleRate {
-		resampler := NewLinearResampler()
-		reader = NewResamplingReader(audioReader, ttsSampleRate, systemSampleRate, ttsChannels, resampler)
+// playbackReader 为播放包装抖动缓冲和回声参考，Mixer 开始读到真实音频时调用 onPlayback
+// 参考信号在抖动缓冲之后写入，与 Mixer 实际播放的时刻对齐
+func (p *ttsPipelineImpl) playbackReader(reader io.Reader, onPlayback func()) (io.Reader, *jitterBuffer) {
+	var jitter *jitterBuffer
+	if prerollBytes := jitterPrerollBytes(p.systemSampleRate(), 1, p.config.PrerollMs); prerollBytes > 0 {
+		jitter = newJitterBuffer(reader, prerollBytes)
+		jitter.onStart = onPlayback
+		reader = jitter
+	} else {
+		reader = &firstReadReader{reader: reader, onFirst: onPlayback}
 	}
 
 	// 添加 reference sink（用于 AEC）
 	p.mu.Lock()
 	reference := p.reference
+	mixer := p.mixer
 	p.mu.Unlock()
 
 	if reference != nil {
-		reader = &referenceTeeReader{reader: reader, sink: reference}
+		tee := &referenceTeeReader{reader: reader, sink: reference}
+		// Mixer 在音频回调中读取，按回调的播放进度为参考音频打时间戳
+		if timed, ok := reference.(TimedReferenceSink); ok {
+			if clock, ok := mixer.(PlaybackClock); ok {
+				tee.timed, tee.clock = timed, clock
+			}
+		}
+		reader = tee
 	}
-
-	return reader, nil
+	return reader, jitter
 }
 
-func (p *ttsPipelineImpl) getVoice(emotion string) string {
-	if voice, ok := p.voiceMap[emotion]; ok {
-		return voice
+// systemSampleRate Mixer 播放采样率
+func (p *ttsPipelineImpl) systemSampleRate() int {
+	if p.mixerConfig != nil && p.mixerConfig.SampleRate > 0 {
+		return p.mixerConfig.SampleRate
 	}
-	if voice, ok := p.voiceMap["default"]; ok {
+	return 16000
+}
+
+func (p *ttsPipelineImpl) getVoice(emotion, language string) string {
+	if voice, ok := lookupEmotion(p.voiceMap, emotion, language); ok {
 		return voice
 	}
 	return "longanyang"
 }
 
-func (p *ttsPipelineImpl) clearQueues() {
-	// 清空 textQueue
-	cleared := 0
-	for {
-		select {
-		case <-p.textQueue:
-			cleared++
-		default:
-			goto clearPending
-		}
+// lookupEmotion 依次查找 "情绪:语言"、"default:语言"、"情绪"、"default"
+func lookupEmotion[T any](m map[string]T, emotion, language string) (T, bool) {
+	keys := []string{emotion, "default"}
+	if language != "" {
+		keys = []string{VoiceKey(emotion, language), VoiceKey("default", language), emotion, "default"}
 	}
-
-clearPending:
-	// 清空 pendingItems
-	p.pendingMu.Lock()
-	for _, item := range p.pendingItems {
-		if item != nil {
-			item.Reader.Close()
-			if closer, ok := item.OrigReader.(io.Closer); ok {
-				closer.Close()
-			}
+	for _, key := range keys {
+		if value, ok := m[key]; ok {
+			return value, true
 		}
 	}
-	p.pendingItems = make(map[int64]*ttsItem)
-	p.pendingMu.Unlock()
+	var zero T
+	return zero, false
+}
 
-	// 清空 ttsBuffer，关闭所有未播放的 reader
-	cleared = 0
-	for {
-		select {
-		case item := <-p.ttsBuffer:
-			// 关闭 eofNotifyReader 的通知
-			item.Reader.Close()
-			// 关闭原始 reader
-			if closer, ok := item.OrigReader.(io.Closer); ok {
-				closer.Close()
-			}
-			cleared++
-		default:
-			return
-		}
-	}
+func (p *ttsPipelineImpl) clearQueues() {
+	p.normal.clear()
+	p.urgent.clear()
 }
 
 // truncateText 截断文本用于日志显示
@@ -661,16 +1062,64 @@ func truncateText(text string, maxLen int) string {
 	return string(runes[:maxLen])
 }
 
-// referenceTeeReader 将读取的数据同时写入 reference sink
+// captureReader 记录读出的音频，读到 EOF 才算完整
+type captureReader struct {
+	reader io.Reader
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	eof    bool
+}
+
+func (r *captureReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.mu.Lock()
+	r.buf.Write(p[:n])
+	r.eof = r.eof || err == io.EOF
+	r.mu.Unlock()
+	return n, err
+}
+
+// Audio 返回已记录的音频，以及是否已读到 EOF
+func (r *captureReader) Audio() ([]byte, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.buf.Bytes(), r.eof
+}
+
+// Close 关闭被包装的 reader（若支持）
+func (r *captureReader) Close() error {
+	if closer, ok := r.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// referenceTeeReader 将读取的数据同时写入 reference sink；clock 非空时按 Mixer 播放进度写入时间戳
 type referenceTeeReader struct {
 	reader io.Reader
 	sink   ReferenceSink
+	timed  TimedReferenceSink
+	clock  PlaybackClock
 }
 
 func (r *referenceTeeReader) Read(p []byte) (int, error) {
 	n, err := r.reader.Read(p)
 	if n > 0 && r.sink != nil {
+		if r.clock != nil {
+			if position := r.clock.PlaybackPosition(); !position.Time.IsZero() {
+				r.timed.WriteReferenceAt(p[:n], position.Time)
+				return n, err
+			}
+		}
 		r.sink.WriteReference(p[:n])
 	}
 	return n, err
 }
+
+// Close 关闭被包装的 reader（若支持）
+func (r *referenceTeeReader) Close() error {
+	if closer, ok := r.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
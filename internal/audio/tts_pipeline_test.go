@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -304,6 +305,65 @@ func TestTTSPipelineVoiceMap(t *testing.T) {
 	}
 }
 
+func TestTTSPipelineVoiceByLanguage(t *testing.T) {
+	voiceMap := map[string]string{
+		"happy":                   "voice_happy",
+		"default":                 "voice_default",
+		VoiceKey("happy", "en"):   "voice_happy_en",
+		VoiceKey("default", "en"): "voice_default_en",
+	}
+	pipeline := NewTTSPipeline(newMockTTSProvider(), DefaultTTSPipelineConfig(), tts.Config{APIKey: "test"}, voiceMap, nil).(*ttsPipelineImpl)
+
+	tests := []struct {
+		emotion  string
+		language string
+		want     string
+	}{
+		{"happy", "", "voice_happy"},
+		{"happy", "en", "voice_happy_en"},
+		{"sad", "en", "voice_default_en"},
+		{"happy", "ja", "voice_happy"},
+		{"sad", "ja", "voice_default"},
+	}
+	for _, tt := range tests {
+		if got := pipeline.getVoice(tt.emotion, tt.language); got != tt.want {
+			t.Errorf("getVoice(%q, %q) = %s, want %s", tt.emotion, tt.language, got, tt.want)
+		}
+	}
+}
+
+// TestTTSPipelineEmotionProsody 测试按情绪调整语速、音调与音量
+func TestTTSPipelineEmotionProsody(t *testing.T) {
+	provider := newMockTTSProvider()
+	ttsConfig := tts.Config{APIKey: "test", Rate: 1.0, Pitch: 1.0, Volume: 50}
+	pipeline := NewTTSPipeline(provider, DefaultTTSPipelineConfig(), ttsConfig, nil, nil).(*ttsPipelineImpl)
+	pipeline.SetEmotionProsody(map[string]tts.Prosody{
+		"excited": {Rate: 1.2, Pitch: 1.1},
+		"sad:en":  {Volume: 30},
+	})
+
+	tests := []struct {
+		emotion  string
+		language string
+		want     tts.Prosody
+	}{
+		{"excited", "", tts.Prosody{Rate: 1.2, Pitch: 1.1, Volume: 50}},
+		{"sad", "en", tts.Prosody{Rate: 1.0, Pitch: 1.0, Volume: 30}},
+		{"calm", "", tts.Prosody{Rate: 1.0, Pitch: 1.0, Volume: 50}},
+	}
+	for _, tt := range tests {
+		if _, err := pipeline.generateTTS(context.Background(), "你好", tt.emotion, tt.language); err != nil {
+			t.Fatalf("generateTTS(%q): %v", tt.emotion, err)
+		}
+		cfg := provider.getLastConfig()
+		got := tts.Prosody{Rate: cfg.Rate, Pitch: cfg.Pitch, Volume: cfg.Volume}
+		if got != tt.want {
+			t.Errorf("%s/%s prosody = %+v, want %+v", tt.emotion, tt.language, got, tt.want)
+		}
+	}
+	pipeline.wg.Wait()
+}
+
 // TestTTSPipelineContextCancellation 测试 context 取消
 func TestTTSPipelineContextCancellation(t *testing.T) {
 	provider := newMockTTSProvider()
@@ -347,6 +407,8 @@ func TestTTSPipelineTTSError(t *testing.T) {
 	ttsConfig := tts.Config{APIKey: "test"}
 
 	pipeline := NewTTSPipeline(provider, config, ttsConfig, nil, nil)
+	failed := make(chan string, 1)
+	pipeline.(TTSErrorReporter).SetOnTTSError(func(text string, err error) { failed <- text })
 
 	ctx := context.Background()
 	err := pipeline.Start(ctx)
@@ -361,8 +423,16 @@ func TestTTSPipelineTTSError(t *testing.T) {
 		t.Fatalf("Failed to enqueue text: %v", err)
 	}
 
-	// 等待处理（应该失败但不崩溃）
-	time.Sleep(200 * time.Millisecond)
+	// 应该失败但不崩溃，并上报失败的句子
+	select {
+	case text := <-failed:
+		if text != "Hello" {
+			t.Errorf("failed text = %q, want Hello", text)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("TTS error callback not called")
+	}
+	time.Sleep(100 * time.Millisecond)
 
 	stats := pipeline.Stats()
 	if stats.TotalEnqueued != 1 {
@@ -647,6 +717,208 @@ func TestTTSPipelinePlaybackOrder(t *testing.T) {
 	t.Logf("Playback order verified: %v", playedOrder)
 }
 
+// gatedMixer 在后台读取 TTS 流：读到第一个字节（句子标识）后上报，等 release 或 RemoveTTSStream 后才读完，模拟播放时长
+type gatedMixer struct {
+	*orderTrackingMixer
+	played  chan string
+	release chan struct{}
+
+	mu      sync.Mutex
+	removed chan struct{} // 当前 TTS 流被移除时关闭
+}
+
+func newGatedMixer() *gatedMixer {
+	return &gatedMixer{orderTrackingMixer: newOrderTrackingMixer(), played: make(chan string, 10), release: make(chan struct{})}
+}
+
+func (m *gatedMixer) AddTTSStream(audio io.Reader) {
+	removed := make(chan struct{})
+	m.mu.Lock()
+	m.removed = removed
+	m.mu.Unlock()
+	go func() {
+		first := make([]byte, 1)
+		if _, err := io.ReadFull(audio, first); err != nil {
+			return
+		}
+		m.played <- string(first)
+		select {
+		case <-m.release:
+		case <-removed:
+		}
+		io.Copy(io.Discard, audio)
+	}()
+}
+
+func (m *gatedMixer) RemoveTTSStream() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.removed != nil {
+		close(m.removed)
+		m.removed = nil
+	}
+}
+
+func (m *gatedMixer) next(t *testing.T) string {
+	t.Helper()
+	select {
+	case id := <-m.played:
+		return id
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for playback")
+		return ""
+	}
+}
+
+func (m *gatedMixer) expectIdle(t *testing.T) {
+	t.Helper()
+	select {
+	case id := <-m.played:
+		t.Fatalf("unexpected playback of %q", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// newPriorityTestPipeline 创建关闭抖动缓冲、使用 gatedMixer 的 Pipeline，clip 入队一段以 id 开头的预合成音频
+func newPriorityTestPipeline(t *testing.T) (*ttsPipelineImpl, *gatedMixer, func(id string, opts SpeechOptions), *int32) {
+	t.Helper()
+	config := DefaultTTSPipelineConfig()
+	config.PrerollMs = 0
+	p := NewTTSPipeline(newMockTTSProvider(), config, tts.Config{}, nil, nil).(*ttsPipelineImpl)
+	mixer := newGatedMixer()
+	p.SetMixer(mixer)
+	var finished int32
+	p.SetOnPlaybackFinished(func() { atomic.AddInt32(&finished, 1) })
+	if err := p.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		close(mixer.release)
+		p.Stop()
+	})
+	clip := func(id string, opts SpeechOptions) {
+		t.Helper()
+		opts.Audio = []byte(id + "-")
+		if err := p.EnqueueWithOptions(context.Background(), id, opts); err != nil {
+			t.Fatalf("EnqueueWithOptions(%s) error = %v", id, err)
+		}
+	}
+	return p, mixer, clip, &finished
+}
+
+func TestTTSPipelineUrgentJumpsQueue(t *testing.T) {
+	_, mixer, clip, finished := newPriorityTestPipeline(t)
+	urgent := SpeechOptions{Priority: TTSPriorityUrgent}
+
+	clip("A", SpeechOptions{})
+	if got := mixer.next(t); got != "A" {
+		t.Fatalf("first = %q, want A", got)
+	}
+	clip("B", SpeechOptions{})
+	clip("C", SpeechOptions{})
+	clip("U", urgent)
+	clip("V", urgent)
+	// 紧急项不打断正在播放的 A
+	mixer.expectIdle(t)
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		mixer.release <- struct{}{}
+		got = append(got, mixer.next(t))
+	}
+	if want := []string{"U", "V", "B", "C"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("order after A = %v, want %v", got, want)
+	}
+	mixer.release <- struct{}{}
+	deadline := time.Now().Add(time.Second)
+	// 紧急项不回调播放完成
+	for atomic.LoadInt32(finished) != 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if n := atomic.LoadInt32(finished); n != 3 {
+		t.Fatalf("playback finished %d times, want 3", n)
+	}
+}
+
+func TestTTSPipelineUrgentPreempt(t *testing.T) {
+	p, mixer, clip, finished := newPriorityTestPipeline(t)
+	preempt := SpeechOptions{Priority: TTSPriorityUrgent, Preempt: true}
+
+	clip("A", SpeechOptions{})
+	if got := mixer.next(t); got != "A" {
+		t.Fatalf("first = %q, want A", got)
+	}
+	clip("B", SpeechOptions{})
+	clip("U", preempt)
+	// A 被抢占（照常回调播放完成），不等 release 就播放 U
+	if got := mixer.next(t); got != "U" {
+		t.Fatalf("after preempt = %q, want U", got)
+	}
+	if n := atomic.LoadInt32(finished); n != 1 {
+		t.Fatalf("playback finished %d times after preempt, want 1", n)
+	}
+
+	// 紧急项不会被抢占
+	clip("V", preempt)
+	mixer.expectIdle(t)
+	mixer.release <- struct{}{}
+	if got := mixer.next(t); got != "V" {
+		t.Fatalf("after U = %q, want V", got)
+	}
+	mixer.release <- struct{}{}
+	if got := mixer.next(t); got != "B" {
+		t.Fatalf("after V = %q, want B", got)
+	}
+	if stats := p.Stats(); stats.TotalEnqueued != 4 {
+		t.Fatalf("TotalEnqueued = %d, want 4", stats.TotalEnqueued)
+	}
+}
+
+// TestTTSPipelineRecordsAndReplaysAudio 测试回调已播放的音频，并直接播放预先合成的音频
+func TestTTSPipelineRecordsAndReplaysAudio(t *testing.T) {
+	provider := newDelayMockTTSProvider()
+	config := &TTSPipelineConfig{MaxTTSBuffer: 10, MaxConcurrentTTS: 2, TextQueueSize: 10}
+	pipeline := NewTTSPipeline(provider, config, tts.Config{APIKey: "test"}, nil, nil).(*ttsPipelineImpl)
+	orderMixer := newOrderTrackingMixer()
+	pipeline.SetMixer(orderMixer)
+
+	type recorded struct {
+		text      string
+		sentences int
+		pcm       string
+	}
+	recordedCh := make(chan recorded, 2)
+	pipeline.SetOnTTSAudio(func(text string, sentences int, pcm []byte) {
+		recordedCh <- recorded{text, sentences, string(pcm)}
+	})
+	if err := pipeline.Start(context.Background()); err != nil {
+		t.Fatalf("Failed to start pipeline: %v", err)
+	}
+	defer pipeline.Stop()
+
+	if err := pipeline.EnqueueText("First sentence.", "default"); err != nil {
+		t.Fatalf("Failed to enqueue text: %v", err)
+	}
+	if err := pipeline.EnqueueAudioContext(context.Background(), "Cached.", []byte("cached audio")); err != nil {
+		t.Fatalf("Failed to enqueue audio: %v", err)
+	}
+
+	want := []recorded{{"First sentence.", 1, "First sentence."}, {"Cached.", 1, "cached audio"}}
+	for i, w := range want {
+		select {
+		case got := <-recordedCh:
+			if got != w {
+				t.Fatalf("recorded[%d] = %+v, want %+v", i, got, w)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected recorded audio %d", i)
+		}
+	}
+	if got := orderMixer.getPlayedOrder(); len(got) != 2 || got[1] != "cached audio" {
+		t.Fatalf("played = %q, want cached audio played after the first sentence", got)
+	}
+}
+
 // delayMockTTSProvider 带延迟控制的 TTS Provider，用于测试播放顺序
 type delayMockTTSProvider struct {
 	mu       sync.Mutex
@@ -797,6 +1069,8 @@ func (m *orderTrackingMixer) AddTTSStream(audio io.Reader) {
 func (m *orderTrackingMixer) AddResourceStream(audio io.Reader) {}
 func (m *orderTrackingMixer) RemoveTTSStream()                  {}
 func (m *orderTrackingMixer) RemoveResourceStream()             {}
+func (m *orderTrackingMixer) AddPromptStream(audio io.Reader)   {}
+func (m *orderTrackingMixer) RemovePromptStream()               {}
 func (m *orderTrackingMixer) SetTTSVolume(volume float64)       {}
 func (m *orderTrackingMixer) SetResourceVolume(volume float64)  {}
 func (m *orderTrackingMixer) OnTTSStarted()                     {}
@@ -1000,3 +1274,210 @@ func (r *slowMockReader) Close() error {
 	r.closed = true
 	return nil
 }
+
+// TestTTSPipelineBatchText 测试短句合并
+func TestTTSPipelineBatchText(t *testing.T) {
+	item := func(text, emotion string) textItem {
+		return textItem{Text: text, Emotion: emotion, Count: 1}
+	}
+	tests := []struct {
+		name      string
+		config    TTSPipelineConfig
+		queued    []textItem
+		wantText  string
+		wantCount int
+		wantCarry string
+	}{
+		{
+			name:      "merges queued short sentences",
+			config:    TTSPipelineConfig{BatchMaxChars: 60, BatchMinChars: 10},
+			queued:    []textItem{item("好的。", "default"), item("嗯，", "default"), item("今天天气不错。", "default")},
+			wantText:  "好的。嗯，今天天气不错。",
+			wantCount: 3,
+		},
+		{
+			name:      "stops at max chars",
+			config:    TTSPipelineConfig{BatchMaxChars: 6, BatchMinChars: 2},
+			queued:    []textItem{item("好的。", "default"), item("今天天气不错。", "default")},
+			wantText:  "好的。",
+			wantCount: 1,
+			wantCarry: "今天天气不错。",
+		},
+		{
+			name:      "keeps emotions separate",
+			config:    TTSPipelineConfig{BatchMaxChars: 60, BatchMinChars: 10},
+			queued:    []textItem{item("好的。", "happy"), item("很抱歉。", "sad")},
+			wantText:  "好的。",
+			wantCount: 1,
+			wantCarry: "很抱歉。",
+		},
+		{
+			name:      "spaces between english sentences",
+			config:    TTSPipelineConfig{BatchMaxChars: 60, BatchMinChars: 10},
+			queued:    []textItem{item("OK.", "default"), item("Sure.", "default")},
+			wantText:  "OK. Sure.",
+			wantCount: 2,
+		},
+		{
+			name:      "disabled",
+			config:    TTSPipelineConfig{},
+			queued:    []textItem{item("好的。", "default"), item("嗯，", "default")},
+			wantText:  "好的。",
+			wantCount: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			config.TextQueueSize = 10
+			p := NewTTSPipeline(newMockTTSProvider(), &config, tts.Config{}, nil, nil).(*ttsPipelineImpl)
+			p.ctx = context.Background()
+			for _, queued := range tt.queued[1:] {
+				p.normal.textQueue <- queued
+			}
+
+			batch, carry := p.batchText(tt.queued[0])
+			if batch.Text != tt.wantText || batch.Count != tt.wantCount {
+				t.Fatalf("batchText() = %q (%d), want %q (%d)", batch.Text, batch.Count, tt.wantText, tt.wantCount)
+			}
+			gotCarry := ""
+			if carry != nil {
+				gotCarry = carry.Text
+			}
+			if gotCarry != tt.wantCarry {
+				t.Fatalf("carry = %q, want %q", gotCarry, tt.wantCarry)
+			}
+		})
+	}
+}
+
+// TestTTSPipelineBatchWait 测试短句等待后续句子
+func TestTTSPipelineBatchWait(t *testing.T) {
+	config := &TTSPipelineConfig{TextQueueSize: 10, BatchMaxChars: 60, BatchMinChars: 10, BatchWaitMs: 200}
+	p := NewTTSPipeline(newMockTTSProvider(), config, tts.Config{}, nil, nil).(*ttsPipelineImpl)
+	p.ctx = context.Background()
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		p.normal.textQueue <- textItem{Text: "我来查一下明天的天气。", Count: 1}
+	}()
+	batch, _ := p.batchText(textItem{Text: "好的，", Count: 1})
+	if batch.Text != "好的，我来查一下明天的天气。" || batch.Count != 2 {
+		t.Fatalf("batchText() = %q (%d)", batch.Text, batch.Count)
+	}
+
+	// 等待超时后单独发送
+	start := time.Now()
+	batch, _ = p.batchText(textItem{Text: "嗯。", Count: 1})
+	if batch.Text != "嗯。" || time.Since(start) < 150*time.Millisecond {
+		t.Fatalf("batchText() = %q after %v, want wait for timeout", batch.Text, time.Since(start))
+	}
+}
+
+// pipeTTSProvider 音频经 io.Pipe 输出、Close 等待音频被读完的 Provider，
+// 模拟缓冲写满后暂停接收、任务迟迟不能结束的 TTS 服务
+type pipeTTSProvider struct {
+	audioBytes int
+}
+
+func (p *pipeTTSProvider) Start(ctx context.Context, cfg tts.Config) (tts.Stream, error) {
+	r, w := io.Pipe()
+	return &pipeTTSStream{reader: r, writer: w, audioBytes: p.audioBytes}, nil
+}
+
+type pipeTTSStream struct {
+	reader     *io.PipeReader
+	writer     *io.PipeWriter
+	audioBytes int
+}
+
+func (s *pipeTTSStream) WriteTextChunk(ctx context.Context, text string) error { return nil }
+
+func (s *pipeTTSStream) Close(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.writer.Write(make([]byte, s.audioBytes))
+		s.writer.Close()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		s.writer.CloseWithError(ctx.Err())
+		return ctx.Err()
+	}
+}
+
+func (s *pipeTTSStream) AudioReader() io.ReadCloser { return s.reader }
+func (s *pipeTTSStream) SampleRate() int            { return 16000 }
+func (s *pipeTTSStream) Channels() int              { return 1 }
+
+// TestTTSPipelinePlaysWhileSynthesizing 合成结束前就把音频交给 Mixer，有界缓冲不会互相等待
+func TestTTSPipelinePlaysWhileSynthesizing(t *testing.T) {
+	config := DefaultTTSPipelineConfig()
+	config.PrerollMs = 0
+	pipeline := NewTTSPipeline(&pipeTTSProvider{audioBytes: 64000}, config, tts.Config{}, nil, nil)
+	mixer := newMockMixer()
+	pipeline.SetMixer(mixer)
+	if err := pipeline.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	defer pipeline.Stop()
+
+	if err := pipeline.EnqueueText("很长的一句话。", "default"); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.After(3 * time.Second)
+	for mixer.getTTSStream() == nil {
+		select {
+		case <-deadline:
+			t.Fatal("TTS stream not handed to mixer before synthesis finished")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+	data, err := io.ReadAll(mixer.getTTSStream())
+	if err != nil || len(data) != 64000 {
+		t.Fatalf("read %d bytes, %v; want 64000", len(data), err)
+	}
+	select {
+	case <-mixer.finishedCh:
+	case <-deadline:
+		t.Fatal("playback not finished")
+	}
+}
+
+func TestSynthesizePromptReadsWhileSynthesizing(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	clip, err := SynthesizePrompt(ctx, &pipeTTSProvider{audioBytes: 64000}, tts.Config{}, "让我想想", 16000)
+	if err != nil {
+		t.Fatalf("SynthesizePrompt() error = %v", err)
+	}
+	if len(clip) != 64000 {
+		t.Errorf("clip = %d bytes, want 64000", len(clip))
+	}
+}
+
+// TestTTSPipelineSSML 开启 SSML 时句子经 SSMLBuilder 转义包装后通过 WriteSSML 发送，形如 SSML 的文本不能注入标签
+func TestTTSPipelineSSML(t *testing.T) {
+	provider := newMockTTSProvider()
+	p := NewTTSPipeline(provider, nil, tts.Config{EnableSSML: true}, nil, nil).(*ttsPipelineImpl)
+	p.SetSSMLBuilder(tts.NewSSMLBuilder(tts.SSMLConfig{}))
+
+	reader, err := p.generateTTS(context.Background(), `<speak rate="2">好</speak>`, "", "")
+	if err != nil {
+		t.Fatalf("generateTTS() error = %v", err)
+	}
+	io.Copy(io.Discard, reader)
+	p.wg.Wait()
+
+	stream := provider.streams[0]
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	want := tts.SSML(`<speak rate="1" pitch="1">&lt;speak rate=&quot;2&quot;&gt;好&lt;/speak&gt;</speak>`)
+	if stream.ssml != want {
+		t.Fatalf("WriteSSML() doc = %q, want %q", stream.ssml, want)
+	}
+}
@@ -0,0 +1,87 @@
+package audio
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// TTSTiming 单句 TTS 的关键时间点，用于端到端延迟分析
+type TTSTiming struct {
+	Text        string
+	EnqueuedAt  time.Time // 文本送入 Pipeline
+	FirstByteAt time.Time // 收到 TTS 服务的首个音频字节
+	PlaybackAt  time.Time // Mixer 开始播放真实音频（不含设备输出延迟）
+}
+
+// TTSTimingCallback 每句开始播放时回调一次
+type TTSTimingCallback func(timing TTSTiming)
+
+// TTSTimingReporter 支持上报 TTS 时间点的组件（TTSPipeline、AudioOutPipe 实现）
+type TTSTimingReporter interface {
+	SetOnTTSTiming(callback TTSTimingCallback)
+}
+
+// ttsTimingRecorder 记录单句的首字节与开始播放时间，开始播放时触发一次回调
+type ttsTimingRecorder struct {
+	mu       sync.Mutex
+	timing   TTSTiming
+	callback TTSTimingCallback
+	reported bool
+}
+
+func newTTSTimingRecorder(text string, enqueuedAt time.Time, callback TTSTimingCallback) *ttsTimingRecorder {
+	return &ttsTimingRecorder{
+		timing:   TTSTiming{Text: text, EnqueuedAt: enqueuedAt},
+		callback: callback,
+	}
+}
+
+// FirstByte 记录首个音频字节到达时间
+func (r *ttsTimingRecorder) FirstByte() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.timing.FirstByteAt.IsZero() {
+		r.timing.FirstByteAt = time.Now()
+	}
+}
+
+// Playback 记录开始播放时间并回调；可能在 Mixer 音频回调中调用，回调异步执行
+func (r *ttsTimingRecorder) Playback() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.reported {
+		return
+	}
+	r.reported = true
+	r.timing.PlaybackAt = time.Now()
+	if r.timing.FirstByteAt.IsZero() {
+		r.timing.FirstByteAt = r.timing.PlaybackAt
+	}
+	if r.callback != nil {
+		go r.callback(r.timing)
+	}
+}
+
+// firstReadReader 首次读到数据时触发 onFirst
+type firstReadReader struct {
+	reader  io.Reader
+	onFirst func()
+	once    sync.Once
+}
+
+func (r *firstReadReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.once.Do(r.onFirst)
+	}
+	return n, err
+}
+
+// Close 关闭被包装的 reader（若支持）
+func (r *firstReadReader) Close() error {
+	if closer, ok := r.reader.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
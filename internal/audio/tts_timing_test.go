@@ -0,0 +1,39 @@
+package audio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestTTSTimingRecorder(t *testing.T) {
+	enqueuedAt := time.Now()
+	reported := make(chan TTSTiming, 2)
+	recorder := newTTSTimingRecorder("你好", enqueuedAt, func(timing TTSTiming) { reported <- timing })
+
+	reader := &firstReadReader{reader: bytes.NewReader([]byte{1, 2, 3, 4}), onFirst: recorder.FirstByte}
+	if _, err := io.ReadAll(reader); err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	recorder.Playback()
+	recorder.Playback()
+
+	select {
+	case timing := <-reported:
+		if timing.Text != "你好" || !timing.EnqueuedAt.Equal(enqueuedAt) {
+			t.Fatalf("timing = %+v", timing)
+		}
+		if timing.FirstByteAt.IsZero() || timing.PlaybackAt.Before(timing.FirstByteAt) {
+			t.Fatalf("timing out of order: first byte %v, playback %v", timing.FirstByteAt, timing.PlaybackAt)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timing callback not called")
+	}
+
+	select {
+	case <-reported:
+		t.Fatal("timing callback called twice")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
@@ -0,0 +1,155 @@
+package audio
+
+import (
+	"errors"
+	"math"
+)
+
+// 声纹特征参数：25ms 帧、10ms 帧移，24 个 Mel 频带，取 1~12 阶倒谱系数
+const (
+	voiceprintFrameMs  = 25
+	voiceprintHopMs    = 10
+	voiceprintMelBands = 24
+	voiceprintCepstra  = 12
+	// voiceprintMinFrames 有效语音帧下限（约 0.5s），太短的语音无法可靠比对
+	voiceprintMinFrames = 50
+	// voiceprintSilenceDB 能量比最响帧低于该值（dB）的帧视为静音，不参与统计
+	voiceprintSilenceDB = 35
+	// voiceprintFloorDB 低于该电平（dBFS）的帧一律视为静音
+	voiceprintFloorDB = -55
+)
+
+// VoiceprintDims 声纹向量维度：倒谱均值 + 倒谱标准差
+const VoiceprintDims = voiceprintCepstra * 2
+
+// ErrVoiceprintTooShort 有效语音太短，无法提取声纹
+var ErrVoiceprintTooShort = errors.New("voiceprint: not enough voiced audio")
+
+// ExtractVoiceprint 从 16-bit 单声道 PCM 提取声纹向量（MFCC 均值与标准差）
+// 只统计有声帧，静音和过短的音频返回 ErrVoiceprintTooShort
+func ExtractVoiceprint(pcm []byte, sampleRate int) ([]float32, error) {
+	if sampleRate <= 0 {
+		return nil, errors.New("voiceprint: invalid sample rate")
+	}
+	samples := bytesToInt16(pcm)
+	frameLen := sampleRate * voiceprintFrameMs / 1000
+	hop := sampleRate * voiceprintHopMs / 1000
+	if frameLen <= 0 || hop <= 0 || len(samples) < frameLen {
+		return nil, ErrVoiceprintTooShort
+	}
+
+	fftSize := nextPowerOfTwo(frameLen)
+	window := hammingWindow(frameLen)
+	filters := melFilterbank(voiceprintMelBands, fftSize, sampleRate)
+
+	var frames [][]float64
+	var energies []float64
+	maxEnergy := math.Inf(-1)
+	buf := make([]complex128, fftSize)
+	for start := 0; start+frameLen <= len(samples); start += hop {
+		var energy float64
+		for i := range buf {
+			buf[i] = 0
+		}
+		for i := 0; i < frameLen; i++ {
+			v := float64(samples[start+i]) / 32768
+			energy += v * v
+			buf[i] = complex(v*window[i], 0)
+		}
+		energy = 10 * math.Log10(energy/float64(frameLen)+1e-12)
+		maxEnergy = math.Max(maxEnergy, energy)
+
+		fft(buf, false)
+		power := make([]float64, fftSize/2+1)
+		for i := range power {
+			re, im := real(buf[i]), imag(buf[i])
+			power[i] = re*re + im*im
+		}
+		frames = append(frames, mfcc(power, filters))
+		energies = append(energies, energy)
+	}
+
+	var voiced [][]float64
+	for i, frame := range frames {
+		if energies[i] >= maxEnergy-voiceprintSilenceDB && energies[i] >= voiceprintFloorDB {
+			voiced = append(voiced, frame)
+		}
+	}
+	if len(voiced) < voiceprintMinFrames {
+		return nil, ErrVoiceprintTooShort
+	}
+
+	vector := make([]float32, VoiceprintDims)
+	for c := 0; c < voiceprintCepstra; c++ {
+		var sum, sumSq float64
+		for _, frame := range voiced {
+			sum += frame[c]
+			sumSq += frame[c] * frame[c]
+		}
+		mean := sum / float64(len(voiced))
+		vector[c] = float32(mean)
+		vector[voiceprintCepstra+c] = float32(math.Sqrt(math.Max(sumSq/float64(len(voiced))-mean*mean, 0)))
+	}
+	return vector, nil
+}
+
+// mfcc 由功率谱计算 1~voiceprintCepstra 阶 Mel 倒谱系数（丢弃与音量相关的 0 阶）
+func mfcc(power []float64, filters [][]float64) []float64 {
+	logMel := make([]float64, len(filters))
+	for b, filter := range filters {
+		var sum float64
+		for i, w := range filter {
+			sum += w * power[i]
+		}
+		logMel[b] = math.Log(sum + 1e-10)
+	}
+	cepstra := make([]float64, voiceprintCepstra)
+	n := float64(len(logMel))
+	for c := range cepstra {
+		var sum float64
+		for b, v := range logMel {
+			sum += v * math.Cos(math.Pi*float64(c+1)*(float64(b)+0.5)/n)
+		}
+		cepstra[c] = sum
+	}
+	return cepstra
+}
+
+// melFilterbank 三角 Mel 滤波器组，覆盖 0 ~ min(8kHz, 奈奎斯特频率)
+func melFilterbank(bands, fftSize, sampleRate int) [][]float64 {
+	toMel := func(hz float64) float64 { return 2595 * math.Log10(1+hz/700) }
+	toHz := func(mel float64) float64 { return 700 * (math.Pow(10, mel/2595) - 1) }
+
+	maxHz := math.Min(8000, float64(sampleRate)/2)
+	points := make([]float64, bands+2)
+	for i := range points {
+		mel := toMel(maxHz) * float64(i) / float64(bands+1)
+		points[i] = toHz(mel) * float64(fftSize) / float64(sampleRate)
+	}
+
+	bins := fftSize/2 + 1
+	filters := make([][]float64, bands)
+	for b := range filters {
+		filter := make([]float64, bins)
+		left, center, right := points[b], points[b+1], points[b+2]
+		for i := range filter {
+			f := float64(i)
+			switch {
+			case f > left && f <= center:
+				filter[i] = (f - left) / (center - left)
+			case f > center && f < right:
+				filter[i] = (right - f) / (right - center)
+			}
+		}
+		filters[b] = filter
+	}
+	return filters
+}
+
+func hammingWindow(n int) []float64 {
+	window := make([]float64, n)
+	for i := range window {
+		window[i] = 0.54 - 0.46*math.Cos(2*math.Pi*float64(i)/float64(n-1))
+	}
+	return window
+}
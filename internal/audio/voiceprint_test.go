@@ -0,0 +1,73 @@
+package audio
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// synthVoice 合成带谐波的“嗓音”：f0 为基频，tilt 控制高次谐波衰减，seed 决定噪声
+func synthVoice(f0, tilt float64, seconds float64, seed int64) []byte {
+	const rate = 16000
+	rng := rand.New(rand.NewSource(seed))
+	samples := make([]int16, int(seconds*rate))
+	for i := range samples {
+		t := float64(i) / rate
+		var v float64
+		for h := 1; h <= 20; h++ {
+			v += math.Pow(tilt, float64(h)) * math.Sin(2*math.Pi*f0*float64(h)*t)
+		}
+		v = v*0.2 + rng.NormFloat64()*0.002
+		samples[i] = floatToInt16(v)
+	}
+	data := make([]byte, len(samples)*2)
+	int16ToBytes(samples, data)
+	return data
+}
+
+func cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+func TestExtractVoiceprint(t *testing.T) {
+	alice1, err := ExtractVoiceprint(synthVoice(120, 0.7, 1.5, 1), 16000)
+	if err != nil {
+		t.Fatalf("ExtractVoiceprint failed: %v", err)
+	}
+	if len(alice1) != VoiceprintDims {
+		t.Fatalf("len = %d, want %d", len(alice1), VoiceprintDims)
+	}
+	alice2, _ := ExtractVoiceprint(synthVoice(122, 0.7, 1.2, 2), 16000)
+	bob, _ := ExtractVoiceprint(synthVoice(230, 0.4, 1.5, 3), 16000)
+
+	same := cosine(alice1, alice2)
+	different := cosine(alice1, bob)
+	if same <= different {
+		t.Errorf("same speaker similarity %.3f should exceed different speaker %.3f", same, different)
+	}
+}
+
+func TestExtractVoiceprintTooShort(t *testing.T) {
+	tests := []struct {
+		name string
+		pcm  []byte
+	}{
+		{"empty", nil},
+		{"short", synthVoice(120, 0.7, 0.2, 1)},
+		{"silence", make([]byte, 16000*2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ExtractVoiceprint(tt.pcm, 16000); !errors.Is(err, ErrVoiceprintTooShort) {
+				t.Errorf("err = %v, want ErrVoiceprintTooShort", err)
+			}
+		})
+	}
+}
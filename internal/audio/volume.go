@@ -0,0 +1,106 @@
+package audio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// ClampVolume 把音量限制在 0~1，NaN 视为 0
+func ClampVolume(volume float64) float64 {
+	if math.IsNaN(volume) {
+		return 0
+	}
+	return min(max(volume, 0), 1)
+}
+
+// VolumeControl 整体音量：语音命令、setVolume 工具和 Orchestrator API 共用，
+// 设置时限幅并写入状态文件，重启后恢复上次的音量
+type VolumeControl struct {
+	mu     sync.Mutex
+	target VolumeController
+	volume float64
+	path   string
+}
+
+// volumeState 音量状态文件
+type volumeState struct {
+	Volume float64 `json:"volume"`
+}
+
+// NewVolumeControl 创建音量控制，path 为空时不持久化；状态文件不存在时使用 initial
+func NewVolumeControl(path string, initial float64) (*VolumeControl, error) {
+	control := &VolumeControl{volume: ClampVolume(initial), path: path}
+	if path == "" {
+		return control, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return control, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state volumeState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parse volume state %s: %w", path, err)
+	}
+	control.volume = ClampVolume(state.Volume)
+	return control, nil
+}
+
+// Bind 绑定实际输出（通常是 Mixer）并应用当前音量；Mixer 晚于工具创建时在创建后绑定
+func (c *VolumeControl) Bind(target VolumeController) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.target = target
+	if target != nil {
+		target.SetVolume(c.volume)
+	}
+}
+
+// Volume 返回当前整体音量
+func (c *VolumeControl) Volume() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.volume
+}
+
+// SetVolume 限幅后设置整体音量，音量变化时写入状态文件（写入失败只记录警告）
+func (c *VolumeControl) SetVolume(volume float64) {
+	volume = ClampVolume(volume)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	changed := volume != c.volume
+	c.volume = volume
+	if c.target != nil {
+		c.target.SetVolume(volume)
+	}
+	if !changed || c.path == "" {
+		return
+	}
+	if err := c.save(); err != nil {
+		logging.Warnf("VolumeControl: failed to save volume to %s: %v", c.path, err)
+	}
+}
+
+// save 写入状态文件，调用方需持有 c.mu
+func (c *VolumeControl) save() error {
+	data, err := json.MarshalIndent(volumeState{Volume: c.volume}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(c.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o644)
+}
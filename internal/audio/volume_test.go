@@ -0,0 +1,85 @@
+package audio
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type fakeVolume struct{ volume float64 }
+
+func (f *fakeVolume) Volume() float64          { return f.volume }
+func (f *fakeVolume) SetVolume(volume float64) { f.volume = volume }
+
+func TestClampVolume(t *testing.T) {
+	tests := []struct {
+		in, want float64
+	}{
+		{0.5, 0.5},
+		{-0.1, 0},
+		{1.5, 1},
+		{math.NaN(), 0},
+		{math.Inf(1), 1},
+	}
+	for _, tt := range tests {
+		if got := ClampVolume(tt.in); got != tt.want {
+			t.Errorf("ClampVolume(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestVolumeControlPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "volume.json")
+
+	control, err := NewVolumeControl(path, 0.7)
+	if err != nil {
+		t.Fatalf("NewVolumeControl() error = %v", err)
+	}
+	mixer := &fakeVolume{volume: 1}
+	control.Bind(mixer)
+	if mixer.volume != 0.7 {
+		t.Fatalf("Bind() applied %v, want 0.7", mixer.volume)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("state file written before any change: %v", err)
+	}
+
+	control.SetVolume(1.4)
+	if got := control.Volume(); got != 1 || mixer.volume != 1 {
+		t.Fatalf("SetVolume(1.4) = %v (mixer %v), want clamped to 1", got, mixer.volume)
+	}
+	control.SetVolume(0.3)
+
+	restored, err := NewVolumeControl(path, 0.7)
+	if err != nil {
+		t.Fatalf("NewVolumeControl() error = %v", err)
+	}
+	if got := restored.Volume(); got != 0.3 {
+		t.Fatalf("restored volume = %v, want 0.3", got)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewVolumeControl(path, 0.7); err == nil {
+		t.Fatal("NewVolumeControl() should fail on a corrupt state file")
+	}
+}
+
+func TestVolumeControlWithoutState(t *testing.T) {
+	control, err := NewVolumeControl("", 2)
+	if err != nil {
+		t.Fatalf("NewVolumeControl() error = %v", err)
+	}
+	if got := control.Volume(); got != 1 {
+		t.Fatalf("Volume() = %v, want 1", got)
+	}
+	// 未绑定输出时只记录音量
+	control.SetVolume(0.4)
+	mixer := &fakeVolume{volume: 1}
+	control.Bind(mixer)
+	if mixer.volume != 0.4 {
+		t.Fatalf("Bind() applied %v, want 0.4", mixer.volume)
+	}
+}
@@ -0,0 +1,72 @@
+package audio
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/liuscraft/orion-x/internal/audio/wav"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// WAVData 解码后的 WAV 音频（16-bit PCM，其他采样格式在解码时转换）
+type WAVData struct {
+	Samples    []int16
+	SampleRate int
+	Channels   int
+}
+
+// DecodeWAV 解码 WAV 文件，支持 16 / 24 / 32-bit 整数与 32-bit 浮点 PCM，统一转换为 16-bit
+// 按 chunk 遍历，兼容 fmt 与 data 之间存在 LIST 等附加 chunk 的文件
+func DecodeWAV(data []byte) (*WAVData, error) {
+	header, pcm, err := wav.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	format, err := WAVSampleFormat(header)
+	if err != nil {
+		return nil, err
+	}
+	return &WAVData{
+		Samples:    bytesToInt16(ConvertToS16(pcm, format)),
+		SampleRate: header.SampleRate,
+		Channels:   header.Channels,
+	}, nil
+}
+
+// EncodeWAV 把 16-bit little-endian PCM 封装为 WAV 文件
+func EncodeWAV(pcm []byte, sampleRate, channels int) []byte {
+	return wav.Encode(pcm, wav.PCM16(sampleRate, channels))
+}
+
+// WAVSampleFormat 把 WAV 的格式码与位深映射为 SampleFormat
+func WAVSampleFormat(header wav.Header) (SampleFormat, error) {
+	switch {
+	case header.Format == wav.FormatPCM && header.BitsPerSample == 16:
+		return SampleFormatS16, nil
+	case header.Format == wav.FormatPCM && header.BitsPerSample == 24:
+		return SampleFormatS24, nil
+	case header.Format == wav.FormatPCM && header.BitsPerSample == 32:
+		return SampleFormatS32, nil
+	case header.Format == wav.FormatFloat && header.BitsPerSample == 32:
+		return SampleFormatF32, nil
+	}
+	return SampleFormatS16, fmt.Errorf("wav: unsupported format %d/%d-bit, only 16/24/32-bit PCM and 32-bit float are supported", header.Format, header.BitsPerSample)
+}
+
+// wavResourceOptions 资源音频为 wav.Reader（如 wav.Open 打开的文件）且未声明格式时，按文件头填写采样率、声道与采样格式
+func wavResourceOptions(audio io.Reader, opts ResourceOptions) ResourceOptions {
+	reader, ok := audio.(*wav.Reader)
+	if !ok || opts.SampleRate != 0 || opts.Channels != 0 || opts.Format != SampleFormatS16 {
+		return opts
+	}
+	header := reader.Header()
+	format, err := WAVSampleFormat(header)
+	if err != nil {
+		logging.Warnf("AudioMixer: %v, playing as 16-bit PCM", err)
+		return opts
+	}
+	opts.SampleRate = header.SampleRate
+	opts.Channels = header.Channels
+	opts.Format = format
+	return opts
+}
@@ -0,0 +1,282 @@
+// Package wav 读写 RIFF/WAVE 文件：按 chunk 解析任意布局的文件头、流式读取 data chunk，
+// 以及写出（回填长度的）WAV 头；不做采样格式转换，见 audio.DecodeWAV
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// 格式码（fmt chunk 的 AudioFormat 字段）
+const (
+	FormatPCM        = 1
+	FormatFloat      = 3
+	FormatExtensible = 0xFFFE
+)
+
+// HeaderSize Writer / Encode 写出的标准 44 字节文件头长度
+const HeaderSize = 44
+
+// maxFmtChunkSize fmt chunk 的合理上限，超过时视为损坏的文件
+const maxFmtChunkSize = 1 << 10
+
+var (
+	// ErrNotWAV 不是 RIFF/WAVE 文件
+	ErrNotWAV = errors.New("wav: not a RIFF/WAVE file")
+	// ErrNoData 找不到 data chunk
+	ErrNoData = errors.New("wav: data chunk not found")
+)
+
+// Header WAV 音频格式
+type Header struct {
+	// Format 格式码，WAVE_FORMAT_EXTENSIBLE 已解析为实际格式（FormatPCM 或 FormatFloat）
+	Format        int
+	Channels      int
+	SampleRate    int
+	BitsPerSample int
+}
+
+// PCM16 16-bit PCM 格式的 Header
+func PCM16(sampleRate, channels int) Header {
+	return Header{Format: FormatPCM, Channels: channels, SampleRate: sampleRate, BitsPerSample: 16}
+}
+
+// BlockAlign 每帧（所有声道各一个样本）的字节数
+func (h Header) BlockAlign() int {
+	return h.Channels * h.BitsPerSample / 8
+}
+
+// ByteRate 每秒字节数
+func (h Header) ByteRate() int {
+	return h.SampleRate * h.BlockAlign()
+}
+
+func (h Header) validate() error {
+	if h.Channels <= 0 || h.SampleRate <= 0 {
+		return fmt.Errorf("wav: invalid sample rate %d or channels %d", h.SampleRate, h.Channels)
+	}
+	if h.BitsPerSample <= 0 || h.BitsPerSample%8 != 0 {
+		return fmt.Errorf("wav: invalid bits per sample %d", h.BitsPerSample)
+	}
+	return nil
+}
+
+// Reader 流式读取 WAV 文件的 data chunk，不把整个文件读入内存
+type Reader struct {
+	header    Header
+	r         io.Reader
+	remaining int64 // data chunk 剩余字节，-1 表示长度未知，读到 EOF 为止
+}
+
+// NewReader 解析文件头直到 data chunk，之后 Read 返回 data chunk 的 PCM 数据
+// 跳过 fmt 与 data 之间的 LIST 等附加 chunk；data 长度为 0 或 0xFFFFFFFF（边录边读、流式写出）时读到 EOF 为止
+func NewReader(r io.Reader) (*Reader, error) {
+	var riff [12]byte
+	if _, err := io.ReadFull(r, riff[:]); err != nil {
+		return nil, ErrNotWAV
+	}
+	if string(riff[0:4]) != "RIFF" || string(riff[8:12]) != "WAVE" {
+		return nil, ErrNotWAV
+	}
+
+	var (
+		header    Header
+		hasFormat bool
+		chunk     [8]byte
+	)
+	for {
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return nil, ErrNoData
+		}
+		id := string(chunk[0:4])
+		size := int64(binary.LittleEndian.Uint32(chunk[4:8]))
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, fmt.Errorf("wav: fmt chunk too short")
+			}
+			if size > maxFmtChunkSize {
+				return nil, fmt.Errorf("wav: fmt chunk too large (%d bytes)", size)
+			}
+			body := make([]byte, size+size%2)
+			if _, err := io.ReadFull(r, body); err != nil {
+				return nil, fmt.Errorf("wav: read fmt chunk: %w", err)
+			}
+			header = parseFmt(body[:size])
+			hasFormat = true
+		case "data":
+			if !hasFormat {
+				return nil, fmt.Errorf("wav: data chunk before fmt chunk")
+			}
+			if err := header.validate(); err != nil {
+				return nil, err
+			}
+			if size == 0 || size == 0xFFFFFFFF {
+				size = -1
+			}
+			return &Reader{header: header, r: r, remaining: size}, nil
+		default:
+			// chunk 按 2 字节对齐
+			if _, err := io.CopyN(io.Discard, r, size+size%2); err != nil {
+				return nil, ErrNoData
+			}
+		}
+	}
+}
+
+// parseFmt 解析 fmt chunk，WAVE_FORMAT_EXTENSIBLE 的实际格式在 SubFormat GUID 的前两个字节
+func parseFmt(body []byte) Header {
+	header := Header{
+		Format:        int(binary.LittleEndian.Uint16(body[0:2])),
+		Channels:      int(binary.LittleEndian.Uint16(body[2:4])),
+		SampleRate:    int(binary.LittleEndian.Uint32(body[4:8])),
+		BitsPerSample: int(binary.LittleEndian.Uint16(body[14:16])),
+	}
+	if header.Format == FormatExtensible && len(body) >= 26 {
+		header.Format = int(binary.LittleEndian.Uint16(body[24:26]))
+	}
+	return header
+}
+
+// Header 返回音频格式
+func (r *Reader) Header() Header {
+	return r.header
+}
+
+// Read 读取 data chunk 的 PCM 数据；data chunk 长度超过文件实际长度时读到文件末尾为止
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.remaining == 0 {
+		return 0, io.EOF
+	}
+	if r.remaining > 0 && int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.r.Read(p)
+	if r.remaining > 0 {
+		r.remaining -= int64(n)
+	}
+	return n, err
+}
+
+// Close 关闭底层 reader（如果支持）
+func (r *Reader) Close() error {
+	if closer, ok := r.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Open 打开 WAV 文件并解析文件头，PCM 数据由返回的 Reader 流式读取，读完后需调用 Close
+func Open(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	reader, err := NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return reader, nil
+}
+
+// Decode 解析内存中的 WAV 文件，返回音频格式与 data chunk 的 PCM 数据
+func Decode(data []byte) (Header, []byte, error) {
+	reader, err := NewReader(bytes.NewReader(data))
+	if err != nil {
+		return Header{}, nil, err
+	}
+	pcm, err := io.ReadAll(reader)
+	return reader.header, pcm, err
+}
+
+// ReadFile 读取整个 WAV 文件，返回音频格式与 PCM 数据
+func ReadFile(path string) (Header, []byte, error) {
+	reader, err := Open(path)
+	if err != nil {
+		return Header{}, nil, err
+	}
+	defer reader.Close()
+	pcm, err := io.ReadAll(reader)
+	return reader.header, pcm, err
+}
+
+// Encode 把 PCM 数据封装为 WAV 文件
+func Encode(pcm []byte, header Header) []byte {
+	data := make([]byte, HeaderSize+len(pcm))
+	PutHeader(data, header, len(pcm))
+	copy(data[HeaderSize:], pcm)
+	return data
+}
+
+// PutHeader 写入 44 字节的标准 WAV 头，dst 至少 HeaderSize 字节
+func PutHeader(dst []byte, header Header, dataSize int) {
+	copy(dst[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(dst[4:8], uint32(36+dataSize))
+	copy(dst[8:16], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(dst[16:20], 16)
+	binary.LittleEndian.PutUint16(dst[20:22], uint16(header.Format))
+	binary.LittleEndian.PutUint16(dst[22:24], uint16(header.Channels))
+	binary.LittleEndian.PutUint32(dst[24:28], uint32(header.SampleRate))
+	binary.LittleEndian.PutUint32(dst[28:32], uint32(header.ByteRate()))
+	binary.LittleEndian.PutUint16(dst[32:34], uint16(header.BlockAlign()))
+	binary.LittleEndian.PutUint16(dst[34:36], uint16(header.BitsPerSample))
+	copy(dst[36:40], "data")
+	binary.LittleEndian.PutUint32(dst[40:44], uint32(dataSize))
+}
+
+// Writer 流式写出 WAV：先写占位头，Close 时回填 RIFF 与 data 长度，适合录制长音频
+type Writer struct {
+	w      io.WriteSeeker
+	header Header
+	size   int64
+}
+
+// NewWriter 创建 Writer 并写入占位头
+func NewWriter(w io.WriteSeeker, header Header) (*Writer, error) {
+	if err := header.validate(); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, HeaderSize)
+	PutHeader(buf, header, 0)
+	if _, err := w.Write(buf); err != nil {
+		return nil, err
+	}
+	return &Writer{w: w, header: header}, nil
+}
+
+// Write 写入 PCM 数据
+func (ww *Writer) Write(p []byte) (int, error) {
+	n, err := ww.w.Write(p)
+	ww.size += int64(n)
+	return n, err
+}
+
+// Close 回填头部长度，不关闭底层 writer
+func (ww *Writer) Close() error {
+	if ww.size%2 != 0 {
+		// 样本不完整时补齐，保持 data chunk 按 2 字节对齐
+		if _, err := ww.w.Write([]byte{0}); err != nil {
+			return err
+		}
+		ww.size++
+	}
+	if ww.size > int64(^uint32(0))-36 {
+		return errors.New("wav: data exceeds 4GB")
+	}
+	buf := make([]byte, HeaderSize)
+	PutHeader(buf, ww.header, int(ww.size))
+	if _, err := ww.w.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := ww.w.Write(buf); err != nil {
+		return err
+	}
+	_, err := ww.w.Seek(0, io.SeekEnd)
+	return err
+}
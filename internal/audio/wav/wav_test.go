@@ -0,0 +1,201 @@
+package wav
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildFile 按顺序拼接 chunk 生成 WAV 文件
+func buildFile(chunks ...[]byte) []byte {
+	var body []byte
+	body = append(body, "WAVE"...)
+	for _, chunk := range chunks {
+		body = append(body, chunk...)
+	}
+	header := make([]byte, 8)
+	copy(header, "RIFF")
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(body)))
+	return append(header, body...)
+}
+
+// chunk 生成 chunk，size < 0 时按 data 实际长度填写
+func chunk(id string, data []byte, size int) []byte {
+	if size < 0 {
+		size = len(data)
+	}
+	out := make([]byte, 8, 8+len(data)+1)
+	copy(out, id)
+	binary.LittleEndian.PutUint32(out[4:8], uint32(size))
+	out = append(out, data...)
+	if len(data)%2 == 1 {
+		out = append(out, 0)
+	}
+	return out
+}
+
+func fmtChunk(format, channels, sampleRate, bits int, extensible bool) []byte {
+	size := 16
+	if extensible {
+		size = 40
+	}
+	body := make([]byte, size)
+	code := format
+	if extensible {
+		code = FormatExtensible
+		binary.LittleEndian.PutUint16(body[16:18], 22)
+		binary.LittleEndian.PutUint16(body[24:26], uint16(format))
+	}
+	binary.LittleEndian.PutUint16(body[0:2], uint16(code))
+	binary.LittleEndian.PutUint16(body[2:4], uint16(channels))
+	binary.LittleEndian.PutUint32(body[4:8], uint32(sampleRate))
+	binary.LittleEndian.PutUint16(body[14:16], uint16(bits))
+	return chunk("fmt ", body, -1)
+}
+
+func TestDecode(t *testing.T) {
+	pcm := []byte{1, 0, 2, 0, 3, 0, 4, 0}
+	tests := []struct {
+		name       string
+		data       []byte
+		wantHeader Header
+		wantPCM    []byte
+		wantErr    bool
+	}{
+		{
+			name:       "standard",
+			data:       Encode(pcm, PCM16(16000, 1)),
+			wantHeader: PCM16(16000, 1),
+			wantPCM:    pcm,
+		},
+		{
+			name:       "chunks around data",
+			data:       buildFile(chunk("JUNK", []byte{1, 2, 3}, -1), fmtChunk(FormatPCM, 2, 8000, 16, false), chunk("LIST", []byte("INFOabc"), -1), chunk("data", pcm, -1), chunk("LIST", []byte("INFO"), -1)),
+			wantHeader: PCM16(8000, 2),
+			wantPCM:    pcm,
+		},
+		{
+			name:       "extensible float",
+			data:       buildFile(fmtChunk(FormatFloat, 1, 48000, 32, true), chunk("data", pcm, -1)),
+			wantHeader: Header{Format: FormatFloat, Channels: 1, SampleRate: 48000, BitsPerSample: 32},
+			wantPCM:    pcm,
+		},
+		{
+			name:       "data size larger than file",
+			data:       buildFile(fmtChunk(FormatPCM, 1, 16000, 16, false), chunk("data", pcm, 1000)),
+			wantHeader: PCM16(16000, 1),
+			wantPCM:    pcm,
+		},
+		{
+			name:       "streaming data size",
+			data:       buildFile(fmtChunk(FormatPCM, 1, 16000, 16, false), chunk("data", pcm, 0)),
+			wantHeader: PCM16(16000, 1),
+			wantPCM:    pcm,
+		},
+		{name: "not wav", data: []byte("hello world, not a wav"), wantErr: true},
+		{name: "data before fmt", data: buildFile(chunk("data", pcm, -1), fmtChunk(FormatPCM, 1, 16000, 16, false)), wantErr: true},
+		{name: "no data", data: buildFile(fmtChunk(FormatPCM, 1, 16000, 16, false)), wantErr: true},
+		{name: "zero channels", data: buildFile(fmtChunk(FormatPCM, 0, 16000, 16, false), chunk("data", pcm, -1)), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header, got, err := Decode(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if header != tt.wantHeader {
+				t.Errorf("header = %+v, want %+v", header, tt.wantHeader)
+			}
+			if !bytes.Equal(got, tt.wantPCM) {
+				t.Errorf("pcm = %v, want %v", got, tt.wantPCM)
+			}
+		})
+	}
+
+	if _, _, err := Decode([]byte("RIFF\x00\x00\x00\x00WAVE")); !errors.Is(err, ErrNoData) {
+		t.Errorf("Decode(empty WAVE) error = %v, want ErrNoData", err)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "out.wav")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWriter(file, PCM16(22050, 1))
+	if err != nil {
+		t.Fatalf("NewWriter() error = %v", err)
+	}
+	// 分多次写入，其中一次跨样本边界
+	for _, chunk := range [][]byte{{1, 0, 2}, {0, 3, 0}, {4, 0}} {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	header, pcm, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if header != PCM16(22050, 1) || !bytes.Equal(pcm, []byte{1, 0, 2, 0, 3, 0, 4, 0}) {
+		t.Fatalf("ReadFile() = %+v %v", header, pcm)
+	}
+
+	if _, err := NewWriter(file, PCM16(0, 1)); err == nil {
+		t.Error("NewWriter(sampleRate=0) expected error")
+	}
+}
+
+func TestOpenStreams(t *testing.T) {
+	pcm := make([]byte, 64*1024)
+	for i := range pcm {
+		pcm[i] = byte(i)
+	}
+	path := filepath.Join(t.TempDir(), "long.wav")
+	if err := os.WriteFile(path, Encode(pcm, PCM16(16000, 2)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer reader.Close()
+	if reader.Header() != PCM16(16000, 2) {
+		t.Errorf("Header() = %+v", reader.Header())
+	}
+	// 小缓冲多次读取，不越过 data chunk
+	var got []byte
+	buf := make([]byte, 1000)
+	for {
+		n, err := reader.Read(buf)
+		got = append(got, buf[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+	}
+	if !bytes.Equal(got, pcm) {
+		t.Errorf("read %d bytes, want %d", len(got), len(pcm))
+	}
+
+	if _, err := Open(filepath.Join(t.TempDir(), "missing.wav")); err == nil {
+		t.Error("Open(missing) expected error")
+	}
+}
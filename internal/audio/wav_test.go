@@ -0,0 +1,59 @@
+package audio
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeWAVRoundTrip(t *testing.T) {
+	samples := []int16{1, -2, 300, -400, 5000, -6000}
+	pcm := make([]byte, len(samples)*2)
+	int16ToBytes(samples, pcm)
+
+	got, err := DecodeWAV(EncodeWAV(pcm, 24000, 2))
+	if err != nil {
+		t.Fatalf("DecodeWAV() error = %v", err)
+	}
+	want := &WAVData{Samples: samples, SampleRate: 24000, Channels: 2}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DecodeWAV(EncodeWAV()) = %+v, want %+v", got, want)
+	}
+}
+
+// wavFile 构造指定格式码与位深的 WAV 文件
+func wavFile(format, bitsPerSample uint16, channels, sampleRate int, body []byte) []byte {
+	data := EncodeWAV(body, sampleRate, channels)
+	binary.LittleEndian.PutUint16(data[20:22], format)
+	binary.LittleEndian.PutUint16(data[34:36], bitsPerSample)
+	return data
+}
+
+func TestDecodeWAVSampleFormats(t *testing.T) {
+	want := []int16{0, 16384, -16384, -32768}
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr bool
+	}{
+		{"24-bit", wavFile(1, 24, 1, 48000, ConvertFromS16(s16Bytes(want...), SampleFormatS24)), false},
+		{"32-bit", wavFile(1, 32, 1, 48000, ConvertFromS16(s16Bytes(want...), SampleFormatS32)), false},
+		{"float32", wavFile(3, 32, 1, 48000, f32Bytes(0, 0.5, -0.5, -1)), false},
+		{"8-bit unsupported", wavFile(1, 8, 1, 48000, []byte{1, 2}), true},
+		{"float64 unsupported", wavFile(3, 64, 1, 48000, make([]byte, 16)), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeWAV(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeWAV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.Samples, want) || got.SampleRate != 48000 || got.Channels != 1 {
+				t.Errorf("DecodeWAV() = %+v, want samples %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package calendar
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CalDAVConfig CalDAV 日历（Nextcloud、Radicale、iCloud 等）
+type CalDAVConfig struct {
+	URL      string // 日历集合地址，如 https://dav.example.com/remote.php/dav/calendars/alice/personal/
+	Username string // Basic 认证用户名，为空时不认证
+	Password string
+	Client   *http.Client // nil 时使用 http.DefaultClient
+	Location *time.Location
+}
+
+// calDAV 通过 PUT 写入 .ics 资源、REPORT calendar-query 查询日程
+type calDAV struct {
+	config CalDAVConfig
+	base   *url.URL
+}
+
+// NewCalDAV 创建 CalDAV 后端；Location 为 nil 时不带时区的时间按本地时区解释
+func NewCalDAV(config CalDAVConfig) (Calendar, error) {
+	base, err := url.Parse(config.URL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("calendar: invalid caldav url %q", config.URL)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	if config.Location == nil {
+		config.Location = time.Local
+	}
+	return &calDAV{config: config, base: base}, nil
+}
+
+func (c *calDAV) CreateEvent(ctx context.Context, event Event) (Event, error) {
+	if err := event.validate(); err != nil {
+		return Event{}, err
+	}
+	if event.ID == "" {
+		event.ID = newEventID() + "@orion-x"
+	}
+	target := c.base.ResolveReference(&url.URL{Path: event.ID + ".ics"})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), strings.NewReader(encodeICal(event, time.Now())))
+	if err != nil {
+		return Event{}, err
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+	req.Header.Set("If-None-Match", "*")
+	resp, err := c.do(req)
+	if err != nil {
+		return Event{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return Event{}, statusError(resp)
+	}
+	return event, nil
+}
+
+// calendarQuery 按时间范围查询 VEVENT，并请求服务端展开重复日程
+const calendarQuery = `<?xml version="1.0" encoding="utf-8"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop>
+    <C:calendar-data>
+      <C:expand start="%[1]s" end="%[2]s"/>
+    </C:calendar-data>
+  </D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VEVENT">
+        <C:time-range start="%[1]s" end="%[2]s"/>
+      </C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`
+
+// multistatus WebDAV 207 响应中需要的部分
+type multistatus struct {
+	Responses []struct {
+		Href      string `xml:"DAV: href"`
+		Propstats []struct {
+			Status string `xml:"DAV: status"`
+			Prop   struct {
+				CalendarData string `xml:"urn:ietf:params:xml:ns:caldav calendar-data"`
+			} `xml:"DAV: prop"`
+		} `xml:"DAV: propstat"`
+	} `xml:"DAV: response"`
+}
+
+func (c *calDAV) ListEvents(ctx context.Context, from, to time.Time) ([]Event, error) {
+	body := fmt.Sprintf(calendarQuery, from.UTC().Format(icalUTCLayout), to.UTC().Format(icalUTCLayout))
+	req, err := http.NewRequestWithContext(ctx, "REPORT", c.base.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusMultiStatus {
+		return nil, statusError(resp)
+	}
+
+	var status multistatus
+	if err := xml.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return nil, fmt.Errorf("calendar: invalid caldav response: %w", err)
+	}
+	var events []Event
+	for _, response := range status.Responses {
+		for _, propstat := range response.Propstats {
+			data := propstat.Prop.CalendarData
+			if data == "" || (propstat.Status != "" && !strings.Contains(propstat.Status, " 200 ")) {
+				continue
+			}
+			for _, event := range parseICalEvents(data, c.config.Location) {
+				// 未展开重复日程的服务端会返回范围外的实例
+				if event.End.After(from) && event.Start.Before(to) {
+					events = append(events, event)
+				}
+			}
+		}
+	}
+	sortEvents(events)
+	return events, nil
+}
+
+func (c *calDAV) do(req *http.Request) (*http.Response, error) {
+	if c.config.Username != "" {
+		req.SetBasicAuth(c.config.Username, c.config.Password)
+	}
+	resp, err := c.config.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		return nil, errors.New("calendar: caldav authentication failed")
+	}
+	return resp, nil
+}
@@ -0,0 +1,128 @@
+package calendar
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeCalDAV 内存中的 CalDAV 集合：PUT 保存 .ics，REPORT 原样返回全部资源
+type fakeCalDAV struct {
+	mu        sync.Mutex
+	resources map[string]string
+	reports   []string
+}
+
+func (f *fakeCalDAV) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, pass, _ := r.BasicAuth(); user != "alice" || pass != "secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		if r.Header.Get("If-None-Match") != "*" || !strings.HasSuffix(r.URL.Path, ".ics") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.resources[r.URL.Path] = string(body)
+		w.WriteHeader(http.StatusCreated)
+	case "REPORT":
+		f.reports = append(f.reports, string(body))
+		w.WriteHeader(http.StatusMultiStatus)
+		io.WriteString(w, `<?xml version="1.0"?><d:multistatus xmlns:d="DAV:" xmlns:cal="urn:ietf:params:xml:ns:caldav">`)
+		for path, data := range f.resources {
+			io.WriteString(w, `<d:response><d:href>`+path+`</d:href><d:propstat><d:prop><cal:calendar-data><![CDATA[`+data+`]]></cal:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>`)
+		}
+		io.WriteString(w, `</d:multistatus>`)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestCalDAVCreateAndList(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	fake := &fakeCalDAV{resources: map[string]string{
+		// 其他客户端写入的日程：TZID 时间、全天日程和范围外的日程
+		"/cal/other.ics": "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:other\r\nDTSTART;TZID=UTC:20241016T010000\r\nDTEND;TZID=UTC:20241016T020000\r\nSUMMARY:站会\r\nEND:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\nUID:holiday\r\nDTSTART;VALUE=DATE:20241016\r\nSUMMARY:假期\r\nEND:VEVENT\r\n" +
+			"BEGIN:VEVENT\r\nUID:old\r\nDTSTART:20240101T010000Z\r\nDTEND:20240101T020000Z\r\nSUMMARY:过期\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n",
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cal, err := NewCalDAV(CalDAVConfig{URL: server.URL + "/cal", Username: "alice", Password: "secret", Client: server.Client(), Location: loc})
+	if err != nil {
+		t.Fatalf("NewCalDAV() error = %v", err)
+	}
+	ctx := context.Background()
+	start := time.Date(2024, 10, 16, 10, 0, 0, 0, loc)
+	created, err := cal.CreateEvent(ctx, Event{
+		Title:       "评审会议，讨论; 方案",
+		Start:       start,
+		End:         start.Add(time.Hour),
+		Location:    strings.Repeat("三楼会议室", 10),
+		Description: "第一行\n第二行",
+	})
+	if err != nil {
+		t.Fatalf("CreateEvent() error = %v", err)
+	}
+	if created.ID == "" {
+		t.Fatal("CreateEvent() returned empty ID")
+	}
+	for _, data := range fake.resources {
+		for _, line := range strings.Split(data, "\r\n") {
+			if len(line) > 75 {
+				t.Errorf("line longer than 75 octets: %q", line)
+			}
+		}
+	}
+
+	from := time.Date(2024, 10, 16, 0, 0, 0, 0, loc)
+	events, err := cal.ListEvents(ctx, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if len(fake.reports) != 1 || !strings.Contains(fake.reports[0], `start="20241015T160000Z" end="20241016T160000Z"`) {
+		t.Errorf("REPORT body = %v", fake.reports)
+	}
+	want := []Event{
+		{ID: "holiday", Title: "假期", Start: from, End: from.AddDate(0, 0, 1), AllDay: true},
+		{ID: "other", Title: "站会", Start: time.Date(2024, 10, 16, 9, 0, 0, 0, loc), End: time.Date(2024, 10, 16, 10, 0, 0, 0, loc)},
+		{ID: created.ID, Title: created.Title, Start: start, End: start.Add(time.Hour), Location: created.Location, Description: created.Description},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("ListEvents() = %+v, want %d events", events, len(want))
+	}
+	for i, event := range events {
+		w := want[i]
+		if event.ID != w.ID || event.Title != w.Title || !event.Start.Equal(w.Start) || !event.End.Equal(w.End) ||
+			event.AllDay != w.AllDay || event.Location != w.Location || event.Description != w.Description {
+			t.Errorf("event %d = %+v, want %+v", i, event, w)
+		}
+	}
+}
+
+func TestCalDAVErrors(t *testing.T) {
+	server := httptest.NewServer(&fakeCalDAV{resources: map[string]string{}})
+	defer server.Close()
+
+	if _, err := NewCalDAV(CalDAVConfig{URL: "dav.example.com/cal"}); err == nil {
+		t.Error("NewCalDAV() with relative url: want error")
+	}
+	cal, _ := NewCalDAV(CalDAVConfig{URL: server.URL, Username: "alice", Password: "wrong", Client: server.Client()})
+	start := time.Now()
+	if _, err := cal.CreateEvent(context.Background(), Event{Title: "x", Start: start, End: start.Add(time.Hour)}); err == nil || !strings.Contains(err.Error(), "authentication") {
+		t.Errorf("CreateEvent() with wrong password error = %v", err)
+	}
+	if _, err := cal.CreateEvent(context.Background(), Event{Start: start, End: start.Add(time.Hour)}); err == nil {
+		t.Error("CreateEvent() without title: want error")
+	}
+}
@@ -0,0 +1,67 @@
+// Package calendar 日历服务：Calendar 接口及 CalDAV、Google Calendar 两种后端，
+// 以及供日程工具使用的中文口语时间解析（ParseTime）
+package calendar
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Event 日程；全天日程的 Start 为当天 0 点，End 为结束日期的次日 0 点
+type Event struct {
+	ID          string
+	Title       string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Location    string
+	Description string
+}
+
+// Calendar 日历后端
+type Calendar interface {
+	// CreateEvent 创建日程，返回带 ID 的日程
+	CreateEvent(ctx context.Context, event Event) (Event, error)
+	// ListEvents 返回与 [from, to) 有交集的日程（重复日程展开为单次），按开始时间排序
+	ListEvents(ctx context.Context, from, to time.Time) ([]Event, error)
+}
+
+// ErrInvalidEvent 日程缺少标题或时间不合法
+var ErrInvalidEvent = errors.New("calendar: invalid event")
+
+// validate 检查标题和时间，End 为零值时不合法（由调用方补全时长）
+func (e Event) validate() error {
+	if strings.TrimSpace(e.Title) == "" {
+		return fmt.Errorf("%w: title is required", ErrInvalidEvent)
+	}
+	if e.Start.IsZero() || e.End.IsZero() || !e.End.After(e.Start) {
+		return fmt.Errorf("%w: end must be after start", ErrInvalidEvent)
+	}
+	return nil
+}
+
+// sortEvents 按开始时间排序
+func sortEvents(events []Event) {
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+}
+
+// newEventID 生成随机的日程 ID
+func newEventID() string {
+	buf := make([]byte, 16)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// statusError 日历服务返回的非预期状态码
+func statusError(resp *http.Response) error {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("calendar: http %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+}
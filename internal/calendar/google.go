@@ -0,0 +1,232 @@
+package calendar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultGoogleTokenURL = "https://oauth2.googleapis.com/token"
+	defaultGoogleBaseURL  = "https://www.googleapis.com/calendar/v3"
+	// tokenExpiryMargin 访问令牌提前刷新的时间
+	tokenExpiryMargin = time.Minute
+)
+
+// GoogleConfig Google Calendar：用 OAuth 刷新令牌换取访问令牌（需要 calendar.events 权限）
+type GoogleConfig struct {
+	CalendarID   string // 为空时为 primary
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+	TokenURL     string       // 为空时为 Google 的令牌地址
+	BaseURL      string       // 为空时为 Calendar API v3 地址
+	Client       *http.Client // nil 时使用 http.DefaultClient
+	Location     *time.Location
+}
+
+// google Calendar API v3 后端，访问令牌过期前自动刷新
+type google struct {
+	config GoogleConfig
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// NewGoogle 创建 Google Calendar 后端；Location 为 nil 时全天日程按本地时区解释
+func NewGoogle(config GoogleConfig) (Calendar, error) {
+	if config.ClientID == "" || config.ClientSecret == "" || config.RefreshToken == "" {
+		return nil, errors.New("calendar: google client_id, client_secret and refresh_token are required")
+	}
+	if config.CalendarID == "" {
+		config.CalendarID = "primary"
+	}
+	if config.TokenURL == "" {
+		config.TokenURL = defaultGoogleTokenURL
+	}
+	if config.BaseURL == "" {
+		config.BaseURL = defaultGoogleBaseURL
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+	if config.Location == nil {
+		config.Location = time.Local
+	}
+	return &google{config: config}, nil
+}
+
+// googleTime Google 事件的开始 / 结束时间，全天日程只有 date
+type googleTime struct {
+	DateTime string `json:"dateTime,omitempty"`
+	Date     string `json:"date,omitempty"`
+}
+
+type googleEvent struct {
+	ID          string     `json:"id,omitempty"`
+	Summary     string     `json:"summary"`
+	Location    string     `json:"location,omitempty"`
+	Description string     `json:"description,omitempty"`
+	Start       googleTime `json:"start"`
+	End         googleTime `json:"end"`
+}
+
+func (g *google) CreateEvent(ctx context.Context, event Event) (Event, error) {
+	if err := event.validate(); err != nil {
+		return Event{}, err
+	}
+	body := googleEvent{
+		Summary:     event.Title,
+		Location:    event.Location,
+		Description: event.Description,
+		Start:       toGoogleTime(event.Start, event.AllDay),
+		End:         toGoogleTime(event.End, event.AllDay),
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return Event{}, err
+	}
+	var created googleEvent
+	if err := g.call(ctx, http.MethodPost, g.eventsURL(nil), data, &created); err != nil {
+		return Event{}, err
+	}
+	return g.fromGoogle(created), nil
+}
+
+func (g *google) ListEvents(ctx context.Context, from, to time.Time) ([]Event, error) {
+	query := url.Values{
+		"timeMin":      {from.Format(time.RFC3339)},
+		"timeMax":      {to.Format(time.RFC3339)},
+		"singleEvents": {"true"},
+		"orderBy":      {"startTime"},
+		"maxResults":   {"250"},
+	}
+	var list struct {
+		Items []googleEvent `json:"items"`
+	}
+	if err := g.call(ctx, http.MethodGet, g.eventsURL(query), nil, &list); err != nil {
+		return nil, err
+	}
+	events := make([]Event, 0, len(list.Items))
+	for _, item := range list.Items {
+		events = append(events, g.fromGoogle(item))
+	}
+	sortEvents(events)
+	return events, nil
+}
+
+func (g *google) eventsURL(query url.Values) string {
+	target := fmt.Sprintf("%s/calendars/%s/events", g.config.BaseURL, url.PathEscape(g.config.CalendarID))
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	return target
+}
+
+// call 发起带访问令牌的请求；401 时刷新令牌重试一次
+func (g *google) call(ctx context.Context, method, target string, body []byte, out interface{}) error {
+	for attempt := 0; ; attempt++ {
+		token, err := g.accessToken(ctx)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := g.config.Client.Do(req)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			resp.Body.Close()
+			g.mu.Lock()
+			g.token = ""
+			g.mu.Unlock()
+			continue
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return statusError(resp)
+		}
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+}
+
+// accessToken 返回未过期的访问令牌，需要时用刷新令牌换取
+func (g *google) accessToken(ctx context.Context) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.token != "" && time.Now().Before(g.expiry) {
+		return g.token, nil
+	}
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {g.config.RefreshToken},
+		"client_id":     {g.config.ClientID},
+		"client_secret": {g.config.ClientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := g.config.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("calendar: refresh google token: %w", statusError(resp))
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("calendar: refresh google token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("calendar: refresh google token: empty access token")
+	}
+	g.token = token.AccessToken
+	g.expiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenExpiryMargin)
+	return g.token, nil
+}
+
+func toGoogleTime(t time.Time, allDay bool) googleTime {
+	if allDay {
+		return googleTime{Date: t.Format("2006-01-02")}
+	}
+	return googleTime{DateTime: t.Format(time.RFC3339)}
+}
+
+func (g *google) fromGoogle(item googleEvent) Event {
+	event := Event{ID: item.ID, Title: item.Summary, Location: item.Location, Description: item.Description}
+	if item.Start.Date != "" {
+		event.AllDay = true
+		event.Start, _ = time.ParseInLocation("2006-01-02", item.Start.Date, g.config.Location)
+		event.End, _ = time.ParseInLocation("2006-01-02", item.End.Date, g.config.Location)
+		return event
+	}
+	if start, err := time.Parse(time.RFC3339, item.Start.DateTime); err == nil {
+		event.Start = start.In(g.config.Location)
+	}
+	if end, err := time.Parse(time.RFC3339, item.End.DateTime); err == nil {
+		event.End = end.In(g.config.Location)
+	}
+	return event
+}
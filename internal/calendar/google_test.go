@@ -0,0 +1,102 @@
+package calendar
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoogleCreateAndList(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	var refreshes int
+	var created googleEvent
+	var listQuery string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "refresh-1" || r.Form.Get("client_secret") != "client-secret" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		refreshes++
+		json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access-" + string(rune('0'+refreshes)), "expires_in": 3600})
+	})
+	mux.HandleFunc("/calendar/v3/calendars/work@example.com/events", func(w http.ResponseWriter, r *http.Request) {
+		// 第一个访问令牌视为已被吊销，验证 401 后刷新重试
+		if r.Header.Get("Authorization") != "Bearer access-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			data, _ := io.ReadAll(r.Body)
+			json.Unmarshal(data, &created)
+			created.ID = "evt-1"
+			json.NewEncoder(w).Encode(created)
+		case http.MethodGet:
+			listQuery = r.URL.RawQuery
+			io.WriteString(w, `{"items":[
+				{"id":"evt-2","summary":"周会","start":{"dateTime":"2024-10-16T02:00:00Z"},"end":{"dateTime":"2024-10-16T03:00:00Z"}},
+				{"id":"evt-3","summary":"团建","start":{"date":"2024-10-16"},"end":{"date":"2024-10-17"}}
+			]}`)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	cal, err := NewGoogle(GoogleConfig{
+		CalendarID:   "work@example.com",
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RefreshToken: "refresh-1",
+		TokenURL:     server.URL + "/token",
+		BaseURL:      server.URL + "/calendar/v3/",
+		Client:       server.Client(),
+		Location:     loc,
+	})
+	if err != nil {
+		t.Fatalf("NewGoogle() error = %v", err)
+	}
+	ctx := context.Background()
+	start := time.Date(2024, 10, 16, 10, 0, 0, 0, loc)
+	event, err := cal.CreateEvent(ctx, Event{Title: "评审", Start: start, End: start.Add(30 * time.Minute), Location: "三楼"})
+	if err != nil {
+		t.Fatalf("CreateEvent() error = %v", err)
+	}
+	if event.ID != "evt-1" || !event.Start.Equal(start) || event.Location != "三楼" {
+		t.Errorf("CreateEvent() = %+v", event)
+	}
+	if created.Start.DateTime != "2024-10-16T10:00:00+08:00" || created.End.DateTime != "2024-10-16T10:30:00+08:00" {
+		t.Errorf("request times = %+v / %+v", created.Start, created.End)
+	}
+	if refreshes != 2 {
+		t.Errorf("token refreshed %d times, want 2 (initial + after 401)", refreshes)
+	}
+
+	from := time.Date(2024, 10, 16, 0, 0, 0, 0, loc)
+	events, err := cal.ListEvents(ctx, from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+	if refreshes != 2 {
+		t.Errorf("token refreshed %d times, want cached token reused", refreshes)
+	}
+	if listQuery == "" || !strings.Contains(listQuery, "singleEvents=true") || !strings.Contains(listQuery, "timeMin=2024-10-16T00%3A00%3A00%2B08%3A00") {
+		t.Errorf("list query = %s", listQuery)
+	}
+	if len(events) != 2 || events[0].Title != "团建" || !events[0].AllDay || !events[0].Start.Equal(from) ||
+		events[1].Title != "周会" || !events[1].Start.Equal(time.Date(2024, 10, 16, 10, 0, 0, 0, loc)) {
+		t.Errorf("ListEvents() = %+v", events)
+	}
+}
+
+func TestNewGoogleRequiresCredentials(t *testing.T) {
+	if _, err := NewGoogle(GoogleConfig{ClientID: "id", ClientSecret: "secret"}); err == nil {
+		t.Error("NewGoogle() without refresh token: want error")
+	}
+}
@@ -0,0 +1,167 @@
+package calendar
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	icalUTCLayout   = "20060102T150405Z"
+	icalLocalLayout = "20060102T150405"
+	icalDateLayout  = "20060102"
+)
+
+// icalTextEscaper 转义 iCalendar TEXT 值（RFC 5545 3.3.11）
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// icalTextUnescaper 反转义 TEXT 值
+var icalTextUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+
+// encodeICal 把日程编码为只含一个 VEVENT 的 VCALENDAR，时间统一使用 UTC
+func encodeICal(event Event, stamp time.Time) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//orion-x//voicebot//CN",
+		"BEGIN:VEVENT",
+		"UID:" + event.ID,
+		"DTSTAMP:" + stamp.UTC().Format(icalUTCLayout),
+	}
+	if event.AllDay {
+		lines = append(lines,
+			"DTSTART;VALUE=DATE:"+event.Start.Format(icalDateLayout),
+			"DTEND;VALUE=DATE:"+event.End.Format(icalDateLayout))
+	} else {
+		lines = append(lines,
+			"DTSTART:"+event.Start.UTC().Format(icalUTCLayout),
+			"DTEND:"+event.End.UTC().Format(icalUTCLayout))
+	}
+	lines = append(lines, "SUMMARY:"+icalTextEscaper.Replace(event.Title))
+	if event.Location != "" {
+		lines = append(lines, "LOCATION:"+icalTextEscaper.Replace(event.Location))
+	}
+	if event.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+icalTextEscaper.Replace(event.Description))
+	}
+	lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldICalLine(line))
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// foldICalLine 按 75 字节折行，不拆开多字节字符
+func foldICalLine(line string) string {
+	const limit = 75
+	if len(line) <= limit {
+		return line
+	}
+	var b strings.Builder
+	width := limit
+	for len(line) > width {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		width = limit - 1 // 续行开头的空格占一个字节
+	}
+	b.WriteString(line)
+	return b.String()
+}
+
+// icalProperty 一行属性：名称、参数和值
+type icalProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseICalEvents 解析 iCalendar 文本中的全部 VEVENT；不带时区的时间按 loc 解释
+func parseICalEvents(data string, loc *time.Location) []Event {
+	var events []Event
+	var current *Event
+	for _, line := range unfoldICal(data) {
+		prop, ok := parseICalProperty(line)
+		if !ok {
+			continue
+		}
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			current = &Event{}
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT"):
+			if current != nil && !current.Start.IsZero() {
+				if current.End.IsZero() {
+					current.End = current.Start
+					if current.AllDay {
+						current.End = current.Start.AddDate(0, 0, 1)
+					}
+				}
+				events = append(events, *current)
+			}
+			current = nil
+		case current == nil:
+		case prop.name == "UID":
+			current.ID = prop.value
+		case prop.name == "SUMMARY":
+			current.Title = icalTextUnescaper.Replace(prop.value)
+		case prop.name == "LOCATION":
+			current.Location = icalTextUnescaper.Replace(prop.value)
+		case prop.name == "DESCRIPTION":
+			current.Description = icalTextUnescaper.Replace(prop.value)
+		case prop.name == "DTSTART":
+			current.Start, current.AllDay = parseICalTime(prop, loc)
+		case prop.name == "DTEND":
+			current.End, _ = parseICalTime(prop, loc)
+		}
+	}
+	return events
+}
+
+// unfoldICal 合并折行并按行拆分
+func unfoldICal(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+	data = strings.ReplaceAll(data, "\n ", "")
+	data = strings.ReplaceAll(data, "\n\t", "")
+	return strings.Split(data, "\n")
+}
+
+func parseICalProperty(line string) (icalProperty, bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return icalProperty{}, false
+	}
+	parts := strings.Split(head, ";")
+	prop := icalProperty{name: strings.ToUpper(strings.TrimSpace(parts[0])), params: map[string]string{}, value: strings.TrimSpace(value)}
+	for _, param := range parts[1:] {
+		key, val, _ := strings.Cut(param, "=")
+		prop.params[strings.ToUpper(key)] = strings.Trim(val, `"`)
+	}
+	return prop, true
+}
+
+// parseICalTime 解析 DATE / DATE-TIME 值，支持 UTC（Z 结尾）、TZID 参数和浮动时间
+func parseICalTime(prop icalProperty, loc *time.Location) (time.Time, bool) {
+	if prop.params["VALUE"] == "DATE" || len(prop.value) == len(icalDateLayout) {
+		t, err := time.ParseInLocation(icalDateLayout, prop.value, loc)
+		return t, err == nil
+	}
+	if strings.HasSuffix(prop.value, "Z") {
+		t, _ := time.Parse(icalUTCLayout, prop.value)
+		return t.In(loc), false
+	}
+	zone := loc
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if named, err := time.LoadLocation(tzid); err == nil {
+			zone = named
+		}
+	}
+	t, _ := time.ParseInLocation(icalLocalLayout, prop.value, zone)
+	return t.In(loc), false
+}
@@ -0,0 +1,395 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// 口语时段未给出钟点时的默认时间
+var periodDefaultHour = map[string]int{
+	"凌晨": 3, "早上": 8, "早晨": 8, "上午": 9, "中午": 12, "下午": 15, "傍晚": 18, "晚上": 20, "夜里": 22, "今晚": 20, "晚": 20,
+}
+
+// 按长度从长到短匹配的相对日期
+var relativeDays = []struct {
+	word string
+	days int
+}{
+	{"大后天", 3}, {"后天", 2}, {"明天", 1}, {"明日", 1}, {"今天", 0}, {"今日", 0}, {"昨天", -1}, {"前天", -2},
+}
+
+var periods = []string{"凌晨", "早上", "早晨", "上午", "中午", "下午", "傍晚", "晚上", "夜里", "今晚", "早", "晚"}
+
+var weekdayNames = map[rune]time.Weekday{
+	'一': time.Monday, '二': time.Tuesday, '三': time.Wednesday, '四': time.Thursday, '五': time.Friday,
+	'六': time.Saturday, '日': time.Sunday, '天': time.Sunday, '七': time.Sunday,
+	'1': time.Monday, '2': time.Tuesday, '3': time.Wednesday, '4': time.Thursday, '5': time.Friday,
+	'6': time.Saturday, '7': time.Sunday,
+}
+
+// ParseTime 解析 LLM 或用户给出的时间：RFC 3339、2006-01-02 15:04、2006-01-02，以及中文口语时间，
+// 如“明天上午十点”“后天下午三点半”“下周一”“10月15日晚上8点”“三天后”“半小时后”。
+// 相对时间以 now 为基准并使用 now 的时区；只给出钟点且已经过去时取明天的该钟点。
+// dateOnly 为 true 表示只给出了日期，返回当天 0 点
+func ParseTime(text string, now time.Time) (t time.Time, dateOnly bool, err error) {
+	text = strings.TrimSpace(text)
+	loc := now.Location()
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006/01/02 15:04"} {
+		if parsed, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return parsed, false, nil
+		}
+	}
+	for _, layout := range []string{"2006-01-02", "2006/01/02"} {
+		if parsed, err := time.ParseInLocation(layout, text, loc); err == nil {
+			return parsed, true, nil
+		}
+	}
+
+	p := &timeParser{text: []rune(strings.ReplaceAll(text, " ", "")), now: now}
+	if t, ok := p.relativeDuration(); ok {
+		return t, false, nil
+	}
+	day, hasDate, err := p.date()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	p.consume("的")
+	period := p.period()
+	hour, minute, hasClock, err := p.clock()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	p.consume("钟")
+	if p.pos < len(p.text) || (!hasDate && period == "" && !hasClock) {
+		return time.Time{}, false, fmt.Errorf("unrecognized time %q", text)
+	}
+	if !hasClock && period == "" {
+		return day, true, nil
+	}
+	if !hasClock {
+		hour = periodDefaultHour[period]
+	}
+	hour, err = applyPeriod(period, hour)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid time %q: %w", text, err)
+	}
+	t = time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, loc)
+	if !hasDate && t.Before(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, false, nil
+}
+
+// applyPeriod 按上午 / 下午等时段换算为 24 小时制，“晚上12点”为次日 0 点（返回 24）
+func applyPeriod(period string, hour int) (int, error) {
+	if hour < 0 || hour > 24 {
+		return 0, fmt.Errorf("hour %d out of range", hour)
+	}
+	switch period {
+	case "凌晨":
+		if hour == 12 {
+			hour = 0
+		}
+	case "中午":
+		if hour < 11 {
+			hour += 12
+		}
+	case "下午", "傍晚", "晚上", "夜里", "今晚", "晚":
+		if hour < 12 {
+			hour += 12
+		} else if hour == 12 && period != "下午" {
+			hour = 24
+		}
+	}
+	return hour, nil
+}
+
+type timeParser struct {
+	text []rune
+	pos  int
+	now  time.Time
+}
+
+func (p *timeParser) rest() string {
+	return string(p.text[p.pos:])
+}
+
+// consume 匹配任一前缀时前进并返回该前缀
+func (p *timeParser) consume(words ...string) (string, bool) {
+	rest := p.rest()
+	for _, word := range words {
+		if strings.HasPrefix(rest, word) {
+			p.pos += len([]rune(word))
+			return word, true
+		}
+	}
+	return "", false
+}
+
+func (p *timeParser) today() time.Time {
+	return time.Date(p.now.Year(), p.now.Month(), p.now.Day(), 0, 0, 0, 0, p.now.Location())
+}
+
+// relativeDuration 整句为“N小时后”“半小时后”“N分钟后”时返回 now 加上该时长
+func (p *timeParser) relativeDuration() (time.Time, bool) {
+	start := p.pos
+	var amount float64
+	if _, ok := p.consume("半"); ok {
+		amount = 0.5
+	} else if n, ok := p.number(); ok {
+		amount = float64(n)
+		if _, ok := p.consume("个半"); ok {
+			amount += 0.5
+		} else {
+			p.consume("个")
+		}
+	} else {
+		return time.Time{}, false
+	}
+	unit, ok := p.consume("小时", "钟头", "分钟", "分")
+	if ok {
+		_, ok = p.consume("以后", "之后", "后")
+	}
+	if !ok || p.pos < len(p.text) {
+		p.pos = start
+		return time.Time{}, false
+	}
+	duration := time.Duration(amount * float64(time.Hour))
+	if unit == "分钟" || unit == "分" {
+		duration = time.Duration(amount * float64(time.Minute))
+	}
+	return p.now.Add(duration).Truncate(time.Minute), true
+}
+
+// date 解析日期部分，没有日期时返回今天
+func (p *timeParser) date() (time.Time, bool, error) {
+	today := p.today()
+	if p.pos == len(p.text) {
+		return today, false, nil
+	}
+	if word, ok := p.consume("今晚"); ok {
+		p.pos -= len([]rune(word)) // 日期为今天，时段留给 period
+		return today, true, nil
+	}
+	for _, relative := range relativeDays {
+		if _, ok := p.consume(relative.word); ok {
+			return today.AddDate(0, 0, relative.days), true, nil
+		}
+	}
+	if day, ok, err := p.weekday(today); ok || err != nil {
+		return day, ok, err
+	}
+
+	start := p.pos
+	first, ok := p.number()
+	if !ok {
+		return today, false, nil
+	}
+	switch {
+	case p.peek("天以后", "天之后", "天后"):
+		p.consume("天以后", "天之后", "天后")
+		return today.AddDate(0, 0, first), true, nil
+	case p.peek("年"):
+		p.consume("年")
+		month, ok := p.number()
+		if !ok || !p.peek("月") {
+			return time.Time{}, false, fmt.Errorf("unrecognized date %q", string(p.text))
+		}
+		p.consume("月")
+		return p.monthDay(first, month)
+	case p.peek("月"):
+		p.consume("月")
+		day, ok, err := p.monthDay(today.Year(), first)
+		if ok && day.Before(today) {
+			day = day.AddDate(1, 0, 0)
+		}
+		return day, ok, err
+	case p.peek("日", "号"):
+		p.consume("日", "号")
+		if first < 1 || first > 31 {
+			return time.Time{}, false, fmt.Errorf("day %d out of range", first)
+		}
+		day := time.Date(today.Year(), today.Month(), first, 0, 0, 0, 0, today.Location())
+		if day.Before(today) {
+			day = time.Date(today.Year(), today.Month()+1, first, 0, 0, 0, 0, today.Location())
+		}
+		return day, true, nil
+	}
+	// 数字属于钟点，交给 clock 解析
+	p.pos = start
+	return today, false, nil
+}
+
+// monthDay 解析“月”之后的日（可省略日、号），返回该日期
+func (p *timeParser) monthDay(year, month int) (time.Time, bool, error) {
+	dayOfMonth, ok := p.number()
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("unrecognized date %q", string(p.text))
+	}
+	p.consume("日", "号")
+	if month < 1 || month > 12 || dayOfMonth < 1 || dayOfMonth > 31 {
+		return time.Time{}, false, fmt.Errorf("date %d-%d out of range", month, dayOfMonth)
+	}
+	return time.Date(year, time.Month(month), dayOfMonth, 0, 0, 0, 0, p.now.Location()), true, nil
+}
+
+// weekday 解析“下周一”“这周三”“星期五”“周日”“下周”；不带“这周 / 本周”且已经过去时取下一周
+func (p *timeParser) weekday(today time.Time) (time.Time, bool, error) {
+	start := p.pos
+	weeks := 0
+	explicit := true
+	switch {
+	case p.consumeAny("下下周", "下下星期", "下下礼拜"):
+		weeks = 2
+	case p.consumeAny("下周", "下星期", "下礼拜"):
+		weeks = 1
+	case p.consumeAny("上周", "上星期", "上礼拜"):
+		weeks = -1
+	case p.consumeAny("这周", "本周", "这星期", "本星期", "这礼拜"):
+	case p.consumeAny("周", "星期", "礼拜"):
+		explicit = false
+	default:
+		return time.Time{}, false, nil
+	}
+	// 一周从周一开始，“下周”“这周”不带星期几时为该周的周一
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	var weekday time.Weekday
+	var ok bool
+	if p.pos < len(p.text) {
+		weekday, ok = weekdayNames[p.text[p.pos]]
+	}
+	if !ok {
+		if explicit {
+			return monday.AddDate(0, 0, 7*weeks), true, nil
+		}
+		p.pos = start
+		return time.Time{}, false, fmt.Errorf("unrecognized weekday in %q", string(p.text))
+	}
+	p.pos++
+	day := monday.AddDate(0, 0, 7*weeks+(int(weekday)+6)%7)
+	if !explicit && day.Before(today) {
+		day = day.AddDate(0, 0, 7)
+	}
+	return day, true, nil
+}
+
+func (p *timeParser) consumeAny(words ...string) bool {
+	_, ok := p.consume(words...)
+	return ok
+}
+
+func (p *timeParser) peek(words ...string) bool {
+	rest := p.rest()
+	for _, word := range words {
+		if strings.HasPrefix(rest, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *timeParser) period() string {
+	word, _ := p.consume(periods...)
+	switch word {
+	case "早":
+		return "早上"
+	}
+	return word
+}
+
+// clock 解析“十点”“3点半”“8点一刻”“十点二十分”“14:30”
+func (p *timeParser) clock() (hour, minute int, ok bool, err error) {
+	start := p.pos
+	hour, ok = p.number()
+	if !ok {
+		return 0, 0, false, nil
+	}
+	if _, colon := p.consume(":", "："); colon {
+		minute, ok = p.number()
+		if !ok {
+			return 0, 0, false, fmt.Errorf("unrecognized clock %q", string(p.text[start:]))
+		}
+	} else if _, ok := p.consume("点", "时"); ok {
+		switch {
+		case p.consumeAny("半"):
+			minute = 30
+		case p.consumeAny("一刻"):
+			minute = 15
+		case p.consumeAny("三刻"):
+			minute = 45
+		case p.consumeAny("整"):
+		default:
+			if n, ok := p.number(); ok {
+				minute = n
+				p.consume("分")
+			}
+		}
+	} else {
+		p.pos = start
+		return 0, 0, false, nil
+	}
+	if minute < 0 || minute > 59 {
+		return 0, 0, false, fmt.Errorf("minute %d out of range", minute)
+	}
+	return hour, minute, true, nil
+}
+
+// number 读取阿拉伯数字或中文数字（支持十、两、零 / 〇，以及“二〇二四”这类逐位写法）
+func (p *timeParser) number() (int, bool) {
+	start := p.pos
+	for p.pos < len(p.text) && unicode.IsDigit(p.text[p.pos]) {
+		p.pos++
+	}
+	if p.pos > start {
+		n := 0
+		for _, r := range p.text[start:p.pos] {
+			n = n*10 + int(r-'0')
+		}
+		return n, true
+	}
+
+	digits := map[rune]int{'零': 0, '〇': 0, '一': 1, '二': 2, '两': 2, '三': 3, '四': 4, '五': 5, '六': 6, '七': 7, '八': 8, '九': 9}
+	for p.pos < len(p.text) {
+		r := p.text[p.pos]
+		if _, ok := digits[r]; !ok && r != '十' {
+			break
+		}
+		p.pos++
+	}
+	chars := p.text[start:p.pos]
+	if len(chars) == 0 {
+		return 0, false
+	}
+	tens := -1
+	for i, r := range chars {
+		if r == '十' {
+			tens = i
+			break
+		}
+	}
+	if tens < 0 {
+		n := 0
+		for _, r := range chars {
+			n = n*10 + digits[r]
+		}
+		return n, true
+	}
+	n := 10
+	if tens == 1 {
+		n = digits[chars[0]] * 10
+	} else if tens > 1 {
+		p.pos = start
+		return 0, false
+	}
+	switch rest := chars[tens+1:]; len(rest) {
+	case 0:
+	case 1:
+		n += digits[rest[0]]
+	default:
+		p.pos = start
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,75 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTime(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	// 2024-10-15 是星期二
+	now := time.Date(2024, 10, 15, 11, 20, 30, 0, loc)
+	at := func(month time.Month, day, hour, minute int) time.Time {
+		return time.Date(2024, month, day, hour, minute, 0, 0, loc)
+	}
+	tests := []struct {
+		text     string
+		want     time.Time
+		dateOnly bool
+		wantErr  bool
+	}{
+		{text: "明天上午十点", want: at(10, 16, 10, 0)},
+		{text: "后天下午三点半", want: at(10, 17, 15, 30)},
+		{text: "大后天晚上8点一刻", want: at(10, 18, 20, 15)},
+		{text: "今晚七点", want: at(10, 15, 19, 0)},
+		{text: "今天中午一点", want: at(10, 15, 13, 0)},
+		{text: "明天凌晨十二点", want: at(10, 16, 0, 0)},
+		{text: "明天晚上12点", want: at(10, 17, 0, 0)},
+		{text: "明天下午", want: at(10, 16, 15, 0)},
+		{text: "下午两点二十分", want: at(10, 15, 14, 20)},
+		{text: "十点", want: at(10, 16, 10, 0)},
+		{text: "14:30", want: at(10, 15, 14, 30)},
+		{text: "明天 9:05", want: at(10, 16, 9, 5)},
+		{text: "明天", want: at(10, 16, 0, 0), dateOnly: true},
+		{text: "下周一", want: at(10, 21, 0, 0), dateOnly: true},
+		{text: "下周", want: at(10, 21, 0, 0), dateOnly: true},
+		{text: "这周日下午四点", want: at(10, 20, 16, 0)},
+		{text: "周一", want: at(10, 21, 0, 0), dateOnly: true},
+		{text: "星期三", want: at(10, 16, 0, 0), dateOnly: true},
+		{text: "10月20日晚上8点", want: at(10, 20, 20, 0)},
+		{text: "十月二十号", want: at(10, 20, 0, 0), dateOnly: true},
+		{text: "3月1日", want: time.Date(2025, 3, 1, 0, 0, 0, 0, loc), dateOnly: true},
+		{text: "二〇二五年一月二日上午九点", want: time.Date(2025, 1, 2, 9, 0, 0, 0, loc)},
+		{text: "20号", want: at(10, 20, 0, 0), dateOnly: true},
+		{text: "三天后", want: at(10, 18, 0, 0), dateOnly: true},
+		{text: "两天后上午十点", want: at(10, 17, 10, 0)},
+		{text: "半小时后", want: at(10, 15, 11, 50)},
+		{text: "一个半小时后", want: at(10, 15, 12, 50)},
+		{text: "十分钟后", want: at(10, 15, 11, 30)},
+		{text: "2024-10-18 09:30", want: at(10, 18, 9, 30)},
+		{text: "2024-10-18T09:30:00+08:00", want: at(10, 18, 9, 30)},
+		{text: "2024-10-18", want: at(10, 18, 0, 0), dateOnly: true},
+		{text: "明天上午二十五点", wantErr: true},
+		{text: "十点七十分", wantErr: true},
+		{text: "随便什么时候", wantErr: true},
+		{text: "明天上午十点开会", wantErr: true},
+		{text: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, dateOnly, err := ParseTime(tt.text, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseTime(%q) = %v, want error", tt.text, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTime(%q) error = %v", tt.text, err)
+			}
+			if !got.Equal(tt.want) || dateOnly != tt.dateOnly {
+				t.Errorf("ParseTime(%q) = %v (date only %v), want %v (date only %v)", tt.text, got, dateOnly, tt.want, tt.dateOnly)
+			}
+		})
+	}
+}
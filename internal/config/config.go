@@ -5,11 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 )
 
 const DefaultPath = "config/voicebot.json"
 
+// ProfileEnv 选择配置档的环境变量，优先级低于 --profile、高于配置文件中的 profile
+const ProfileEnv = "VOICEBOT_PROFILE"
+
 type AppConfig struct {
 	Logging LoggingConfig `json:"logging"`
 	ASR     ASRConfig     `json:"asr"`
@@ -17,70 +23,262 @@ type AppConfig struct {
 	LLM     LLMConfig     `json:"llm"`
 	Audio   AudioConfig   `json:"audio"`
 	Tools   ToolsConfig   `json:"tools"`
+
+	Conversation ConversationConfig `json:"conversation"`
+	Knowledge    KnowledgeConfig    `json:"knowledge"`
+	Speaker      SpeakerConfig      `json:"speaker"`
+	Translation  TranslationConfig  `json:"translation"`
+
+	ContentFilter ContentFilterConfig `json:"content_filter"`
+	Web           WebConfig           `json:"web"`
+	Telephony     TelephonyConfig     `json:"telephony"`
+	MQTT          MQTTConfig          `json:"mqtt"`
+	Indicator     IndicatorConfig     `json:"indicator"`
+	Realtime      RealtimeConfig      `json:"realtime"`
+	Calendar      CalendarConfig      `json:"calendar"`
+	News          NewsConfig          `json:"news"`
+	Routines      []RoutineConfig     `json:"routines"`
+
+	// Profile 默认启用的配置档，多个用逗号分隔、按顺序叠加
+	Profile string `json:"profile"`
+	// Profiles 命名配置档（如 "bluetooth"、"studio"），内容与配置文件结构相同，只写需要覆盖的字段
+	Profiles map[string]json.RawMessage `json:"profiles,omitempty"`
+	// ActiveProfiles 加载时实际叠加的配置档
+	ActiveProfiles []string `json:"-"`
 }
 
 type LoggingConfig struct {
-	Level  string `json:"level"`
-	Format string `json:"format"`
+	Level  string            `json:"level"`
+	Format string            `json:"format"`
+	Levels map[string]string `json:"levels"` // 按包名覆盖日志级别，如 {"audio": "debug", "agent": "warn"}
+	File   LogFileConfig     `json:"file"`
+}
+
+type LogFileConfig struct {
+	Path        string `json:"path"`         // 日志文件路径（JSON 格式），为空时只输出到 stderr
+	Level       string `json:"level"`        // 文件的日志级别，为空时与 logging.level 相同
+	MaxSizeMB   int    `json:"max_size_mb"`  // 超过该大小时轮转，0 表示不按大小轮转
+	RotateHours int    `json:"rotate_hours"` // 每隔该小时数轮转，0 表示不按时间轮转
+	MaxBackups  int    `json:"max_backups"`  // 保留的历史文件数，0 表示全部保留
 }
 
 type ASRConfig struct {
-	APIKey   string `json:"api_key"`
-	Model    string `json:"model"`
-	Endpoint string `json:"endpoint"`
+	APIKey      string `json:"api_key"`
+	APIKeyFile  string `json:"api_key_file"` // 从文件读取 api_key（去掉首尾空白），设置时优先于 api_key
+	Model       string `json:"model"`
+	Endpoint    string `json:"endpoint"`
+	Heartbeat   bool   `json:"heartbeat"`    // 开启服务端心跳，长时间静音不结束任务
+	KeepaliveMs int    `json:"keepalive_ms"` // 超过该时长未发送音频时补发静音帧，0 表示关闭
+	// LanguageHints 识别语言提示（如 ["zh", "en"]），为空时由服务自动识别
+	LanguageHints []string `json:"language_hints"`
+	// VocabularyID 识别时使用的热词表 ID，由 `go run ./cmd/asr vocab sync` 根据 vocabulary 创建后写入
+	VocabularyID string           `json:"vocabulary_id"`
+	Vocabulary   VocabularyConfig `json:"vocabulary"`
+}
+
+// VocabularyConfig 热词表内容：产品名、人名等领域词汇，提高识别准确率
+type VocabularyConfig struct {
+	Prefix   string          `json:"prefix"` // 热词表前缀（小写字母和数字，不超过 10 个字符），用于区分不同项目
+	Hotwords []HotwordConfig `json:"hotwords"`
+}
+
+type HotwordConfig struct {
+	Text   string `json:"text"`
+	Weight int    `json:"weight"` // 1~5，0 表示默认 4
+	Lang   string `json:"lang"`   // 如 zh / en，为空时由服务判断
 }
 
 type TTSConfig struct {
-	APIKey               string            `json:"api_key"`
-	Endpoint             string            `json:"endpoint"`
-	Workspace            string            `json:"workspace"`
-	Model                string            `json:"model"`
-	Voice                string            `json:"voice"`
-	Format               string            `json:"format"`
-	SampleRate           int               `json:"sample_rate"`
-	Volume               int               `json:"volume"`
-	Rate                 float64           `json:"rate"`
-	Pitch                float64           `json:"pitch"`
-	EnableSSML           bool              `json:"enable_ssml"`
-	TextType             string            `json:"text_type"`
-	EnableDataInspection *bool             `json:"enable_data_inspection"`
-	VoiceMap             map[string]string `json:"voice_map"`
+	APIKey               string                 `json:"api_key"`
+	APIKeyFile           string                 `json:"api_key_file"`
+	Endpoint             string                 `json:"endpoint"`
+	Workspace            string                 `json:"workspace"`
+	Model                string                 `json:"model"`
+	Voice                string                 `json:"voice"`
+	Format               string                 `json:"format"`
+	SampleRate           int                    `json:"sample_rate"`
+	Volume               int                    `json:"volume"`
+	Rate                 float64                `json:"rate"`
+	Pitch                float64                `json:"pitch"`
+	EnableSSML           bool                   `json:"enable_ssml"`
+	TextType             string                 `json:"text_type"`
+	EnableDataInspection *bool                  `json:"enable_data_inspection"`
+	BufferBytes          int                    `json:"buffer_bytes"`  // 每个 TTS 流未读音频的缓冲上限，0 表示默认 1MB
+	BufferPolicy         string                 `json:"buffer_policy"` // 缓冲写满时：block（暂停接收）/ drop_oldest / drop_newest
+	VoiceMap             map[string]VoiceConfig `json:"voice_map"`     // 情绪 → 音色及韵律
+	SSML                 SSMLConfig             `json:"ssml"`
+	Normalization        NormalizationConfig    `json:"normalization"`
+}
+
+// NormalizationConfig 送入 TTS 前的文本规范化（数字、单位、网址等）
+type NormalizationConfig struct {
+	Enable bool   `json:"enable"`
+	Locale string `json:"locale"` // zh：数字转中文读法；en：只展开单位和符号
+}
+
+// SSMLConfig 仅在 enable_ssml 为 true 时生效
+type SSMLConfig struct {
+	CommaBreakMs          int                      `json:"comma_break_ms"`          // 逗号后的停顿时长（毫秒）
+	ExclamationPitchBoost float64                  `json:"exclamation_pitch_boost"` // 感叹句音调提升比例
+	EmotionProsody        map[string]ProsodyConfig `json:"emotion_prosody"`         // 按情绪调整韵律
+	VoiceProsody          map[string]ProsodyConfig `json:"voice_prosody"`           // 按音色调整韵律
+}
+
+// VoiceConfig 某个情绪使用的音色与韵律，JSON 中也可直接写音色名字符串
+type VoiceConfig struct {
+	Voice  string  `json:"voice"`  // 音色，空表示沿用 default 音色
+	Rate   float64 `json:"rate"`   // 语速倍率，0 表示沿用 tts.rate
+	Pitch  float64 `json:"pitch"`  // 音调倍率，0 表示沿用 tts.pitch
+	Volume int     `json:"volume"` // 音量 0-100，0 表示沿用 tts.volume
+}
+
+// UnmarshalJSON 兼容旧格式 "happy": "longanyang"
+func (v *VoiceConfig) UnmarshalJSON(data []byte) error {
+	var voice string
+	if err := json.Unmarshal(data, &voice); err == nil {
+		*v = VoiceConfig{Voice: voice}
+		return nil
+	}
+	type plain VoiceConfig
+	var cfg plain
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return err
+	}
+	*v = VoiceConfig(cfg)
+	return nil
+}
+
+type ProsodyConfig struct {
+	Rate   float64 `json:"rate"`   // 语速倍率，0 表示不调整
+	Pitch  float64 `json:"pitch"`  // 音调倍率，0 表示不调整
+	Volume int     `json:"volume"` // 音量 0-100，0 表示不调整
 }
 
 type LLMConfig struct {
-	APIKey  string `json:"api_key"`
-	BaseURL string `json:"base_url"`
-	Model   string `json:"model"`
+	Provider         string             `json:"provider"` // openai（OpenAI 兼容接口）或 gemini
+	APIKey           string             `json:"api_key"`
+	APIKeyFile       string             `json:"api_key_file"`
+	BaseURL          string             `json:"base_url"`          // 为空时使用服务商的默认地址
+	Model            string             `json:"model"`             // 为空时使用服务商的默认模型
+	SystemPrompt     string             `json:"system_prompt"`     // 系统提示词模板，为空时使用内置模板
+	Persona          string             `json:"persona"`           // 人设，对应模板变量 {{persona}}
+	Language         string             `json:"language"`          // 回复语言，对应模板变量 {{language}}
+	ToolDescriptions map[string]string  `json:"tool_descriptions"` // 工具说明，渲染为模板变量 {{tools}}
+	PromptVariables  map[string]string  `json:"prompt_variables"`  // 自定义模板变量
+	Fallbacks        []LLMEndpoint      `json:"fallbacks"`         // 备用 LLM，主 LLM 失败时按顺序切换
+	MaxRetries       int                `json:"max_retries"`       // 瞬时错误（超时、429、5xx）重试次数
+	RetryBackoffMs   int                `json:"retry_backoff_ms"`  // 重试间隔
+	IntentRouter     IntentRouterConfig `json:"intent_router"`
+}
+
+// IntentRouterConfig 意图路由：每轮先用小模型从工具中选出需要的工具，只把这些工具交给主 LLM
+type IntentRouterConfig struct {
+	Enable     bool   `json:"enable"`
+	Provider   string `json:"provider"` // 为空时沿用 llm.provider
+	APIKey     string `json:"api_key"`  // 为空时沿用 llm.api_key
+	APIKeyFile string `json:"api_key_file"`
+	BaseURL    string `json:"base_url"`   // 为空时沿用 llm.base_url（服务商不同时使用其默认地址）
+	Model      string `json:"model"`      // 路由小模型，如 glm-4-flash；为空时沿用 llm.model
+	MaxTools   int    `json:"max_tools"`  // 每轮最多选择的工具数，工具总数不超过该值时不路由；0 表示不限制
+	TimeoutMs  int    `json:"timeout_ms"` // 超时或失败时本轮使用全部工具，0 表示不限制
+}
+
+// LLMEndpoint 备用 LLM 端点，字段为空时沿用主 LLM 配置（服务商不同时 base_url、model 使用该服务商的默认值）
+type LLMEndpoint struct {
+	Name       string `json:"name"`
+	Provider   string `json:"provider"`
+	APIKey     string `json:"api_key"`
+	APIKeyFile string `json:"api_key_file"`
+	BaseURL    string `json:"base_url"`
+	Model      string `json:"model"`
 }
 
 type AudioConfig struct {
 	Mixer       MixerConfig       `json:"mixer"`
 	InPipe      InPipeConfig      `json:"in_pipe"`
 	TTSPipeline TTSPipelineConfig `json:"tts_pipeline"`
+	Prompts     PromptsConfig     `json:"prompts"`
+	// Backend 麦克风与扬声器的音频后端：portaudio（默认）、alsa（通过 arecord / aplay，不依赖 PortAudio 设备层）
+	// 或 pulse（通过 parec / pacat 接入 PulseAudio / PipeWire，设备名为 sink / source）
+	Backend string `json:"backend"`
+	// FullDuplex 使用单个 PortAudio 全双工流同时驱动播放与采集（解决 macOS 蓝牙设备输入输出流冲突）
+	FullDuplex bool `json:"full_duplex"`
+	// Levels 麦克风 / 扬声器电平监测
+	Levels LevelsConfig `json:"levels"`
+	// DebugRecord 录制调试音频
+	DebugRecord DebugRecordConfig `json:"debug_record"`
+}
+
+// DebugRecordConfig 把送入 ASR 的麦克风音频和 Mixer 输出分别录制为 WAV 文件，用于排查回声、噪声和播放问题
+type DebugRecordConfig struct {
+	Enable bool   `json:"enable"`
+	Dir    string `json:"dir"` // 录音目录，每次启动写入 mic-<时间>.wav 与 output-<时间>.wav
+}
+
+type LevelsConfig struct {
+	SilenceThreshold float64 `json:"silence_threshold"` // 麦克风 RMS 低于该值视为静音（0~1）
+	SilenceWarnMs    int     `json:"silence_warn_ms"`   // 持续静音超过该时长时告警，0 表示不检测
+	ClipWarnMs       int     `json:"clip_warn_ms"`      // 两次削波告警的最短间隔
+}
+
+type PromptsConfig struct {
+	Enable bool              `json:"enable"`
+	Dir    string            `json:"dir"`   // 提示音目录
+	Files  map[string]string `json:"files"` // 提示音名称 -> WAV 文件（16-bit PCM），为空时使用默认文件名
 }
 
 type TTSPipelineConfig struct {
 	MaxTTSBuffer     int `json:"max_tts_buffer"`
 	MaxConcurrentTTS int `json:"max_concurrent_tts"`
 	TextQueueSize    int `json:"text_queue_size"`
+	PrerollMs        int `json:"preroll_ms"`      // 播放抖动缓冲预缓冲时长，0 表示关闭
+	BatchMaxChars    int `json:"batch_max_chars"` // 连续短句合并送入 TTS 的字数上限，0 表示不合并
+	BatchMinChars    int `json:"batch_min_chars"` // 合并后仍短于该字数时等待后续句子
+	BatchWaitMs      int `json:"batch_wait_ms"`   // 短句等待后续句子的最长时间
 }
 
 type MixerConfig struct {
 	TTSVolume      float64 `json:"tts_volume"`
 	ResourceVolume float64 `json:"resource_volume"`
-	SampleRate     int     `json:"sample_rate"`
-	Channels       int     `json:"channels"`
+	SampleRate     int     `json:"sample_rate"`   // 播放采样率，TTS 与提示音重采样到该采样率
+	Channels       int     `json:"channels"`      // 输出声道数：1 或 2
+	OutputDevice   string  `json:"output_device"` // 输出设备名称（部分匹配），空字符串表示使用默认设备
+	CrossfadeMs    int     `json:"crossfade_ms"`  // 提示音与 TTS 交叉淡化时长
+	FadeOutMs      int     `json:"fade_out_ms"`   // 打断 / 移除音频流时的淡出时长
+	Volume         float64 `json:"volume"`        // 整体音量（0~1），与 TTS / 资源音量相乘
+	VolumeState    string  `json:"volume_state"`  // 保存语音命令 / 工具调整后的音量，重启后优先于 volume，为空时不保存
 }
 
 type InPipeConfig struct {
-	SampleRate   int       `json:"sample_rate"`
-	Channels     int       `json:"channels"`
-	EnableVAD    bool      `json:"enable_vad"`
-	VADThreshold float64   `json:"vad_threshold"`
-	BufferSize   int       `json:"buffer_size"`  // 缓冲区大小（样本数），默认 3200
-	HighLatency  bool      `json:"high_latency"` // 高延迟模式，适合蓝牙设备
-	InputDevice  string    `json:"input_device"` // 输入设备名称，空字符串表示使用默认设备
-	AEC          AECConfig `json:"aec"`
+	SampleRate    int            `json:"sample_rate"`
+	Channels      int            `json:"channels"`
+	EnableVAD     bool           `json:"enable_vad"`
+	VADThreshold  float64        `json:"vad_threshold"`
+	BufferSize    int            `json:"buffer_size"`    // 缓冲区大小（样本数），默认 3200
+	HighLatency   bool           `json:"high_latency"`   // 高延迟模式，适合蓝牙设备
+	InputDevice   string         `json:"input_device"`   // 输入设备名称，空字符串表示使用默认设备
+	InputChannels int            `json:"input_channels"` // 打开输入设备的声道数，0 表示与 channels 相同
+	SampleFormat  string         `json:"sample_format"`  // 麦克风采集格式：s16（默认）、s24、s32 或 f32，采集后转换为 16-bit
+	ChannelSelect int            `json:"channel_select"` // 多声道输入选取的声道（从 0 开始），-1 表示下混
+	InputDevices  []string       `json:"input_devices"`  // 多个输入设备（如两个 USB 麦克风），配置后代替 input_device，按 mic_array 合并
+	MicArray      MicArrayConfig `json:"mic_array"`
+	AEC           AECConfig      `json:"aec"`
+	DSP           DSPConfig      `json:"dsp"` // 输入处理：降噪与自动增益，位于麦克风与回声消除之间
+
+	ReconnectInitialBackoffMs int `json:"reconnect_initial_backoff_ms"` // ASR 断线重连初始退避，之后每次翻倍
+	ReconnectMaxBackoffMs     int `json:"reconnect_max_backoff_ms"`     // ASR 断线重连退避上限
+	ReplayBufferMs            int `json:"replay_buffer_ms"`             // 重连后补发的音频时长上限，0 表示不补发
+
+	ASRFrameMs     int     `json:"asr_frame_ms"`      // 发送给 ASR 的帧长，音频块切分或合并为该长度，0 表示按读取的块发送
+	ASRMaxSendRate float64 `json:"asr_max_send_rate"` // 发送速率上限（实时倍数），0 表示不限速
+}
+
+// MicArrayConfig 多麦克风合并：多个输入设备，或单个多声道设备配置了 strategy 时代替 channel_select
+type MicArrayConfig struct {
+	Strategy    string  `json:"strategy"`      // select（按句选信噪比最高的声道）或 delay_sum（延迟求和），多个设备时默认 select
+	SpeechSNRDb float64 `json:"speech_snr_db"` // 判定有人说话的信噪比，默认 10dB
+	HangoverMs  int     `json:"hangover_ms"`   // 静音超过该时长视为一句结束，之后才允许换路，默认 500ms
+	MaxDelayMs  float64 `json:"max_delay_ms"`  // delay_sum 搜索的最大声道间时延，默认 1ms
 }
 
 type AECConfig struct {
@@ -89,11 +287,381 @@ type AECConfig struct {
 	FrameMs                 int    `json:"frame_ms"`
 	FarEndDelayMs           int    `json:"far_end_delay_ms"`
 	ReferenceActiveWindowMs int    `json:"reference_active_window_ms"`
+	AlignPlayback           bool   `json:"align_playback"` // 按 Mixer 播放进度为回声参考打时间戳并与麦克风输入对齐
+	// gate 模式：播放期间衰减的分贝数（0 为静音）、状态保持时间、近端说话判定的能量比（0 关闭检测）
+	GateAttenuationDb   float64 `json:"gate_attenuation_db"`
+	GateHangoverMs      int     `json:"gate_hangover_ms"`
+	GateDoubleTalkRatio float64 `json:"gate_double_talk_ratio"`
+}
+
+type DSPConfig struct {
+	HighPass         HighPassConfig         `json:"high_pass"`
+	AGC              AGCConfig              `json:"agc"`
+	NoiseSuppression NoiseSuppressionConfig `json:"noise_suppression"`
+}
+
+type HighPassConfig struct {
+	Enable   bool    `json:"enable"`
+	CutoffHz float64 `json:"cutoff_hz"` // 截止频率，默认 80Hz
+	Q        float64 `json:"q"`         // 品质因数，默认 0.707
+}
+
+type AGCConfig struct {
+	Enable    bool    `json:"enable"`
+	TargetRMS float64 `json:"target_rms"` // 目标电平（归一化 RMS，0~1）
+	MaxGain   float64 `json:"max_gain"`   // 最大增益倍数
+	AttackMs  int     `json:"attack_ms"`  // 增益下降时间常数
+	ReleaseMs int     `json:"release_ms"` // 增益上升时间常数
+}
+
+type NoiseSuppressionConfig struct {
+	Enable   bool    `json:"enable"`
+	Strength float64 `json:"strength"` // 过减因子
+	Floor    float64 `json:"floor"`    // 频谱增益下限（0~1）
+}
+
+type ConversationConfig struct {
+	ResumeInterrupted   bool     `json:"resume_interrupted"`     // 打断后允许用“继续”恢复未播放的回复
+	ResumePhrases       []string `json:"resume_phrases"`         // 恢复播放的触发话术，为空时使用默认话术
+	FillerDelayMs       int      `json:"filler_delay_ms"`        // LLM 首个响应超过该时长时播放填充音，0 表示关闭
+	FillerPrompt        string   `json:"filler_prompt"`          // 填充提示音名称
+	FillerText          string   `json:"filler_text"`            // 填充提示音未加载时，启动时用 TTS 预合成该文本
+	ShutdownDrainMs     int      `json:"shutdown_drain_ms"`      // 收到 SIGTERM 时等待当前回复播放完毕的最长时间，0 表示立即停止
+	SegmentFlushMs      int      `json:"segment_flush_ms"`       // LLM 句中停顿超过该时长时先播报已缓冲的半句，0 表示关闭
+	EndOfTurnSilenceMs  int      `json:"end_of_turn_silence_ms"` // 用户停顿超过该时长才算说完一轮，窗口内的多句识别结果合并，0 表示每句即一轮
+	ProcessingTimeoutMs int      `json:"processing_timeout_ms"`  // 等待 LLM 响应的最长时间，超时提示出错并回到空闲，0 表示不限制
+	SpeakingTimeoutMs   int      `json:"speaking_timeout_ms"`    // 播放无进展的最长时间，超时强制打断，0 表示不限制
+	FollowUpWindowMs    int      `json:"follow_up_window_ms"`    // 回复以追问结尾时，播完后保持聆听等待回答的时长，0 表示关闭
+	ErrorText           string   `json:"error_text"`             // 组件出错时的致歉语，未加载 error 提示音时启动阶段用 TTS 预合成
+	ErrorRetries        int      `json:"error_retries"`          // LLM 瞬时错误且尚未回复时重新处理本轮的次数
+	ErrorRetryDelayMs   int      `json:"error_retry_delay_ms"`   // 重试前的等待时长，第 n 次重试等待 n 倍
+	DetectLanguage      bool     `json:"detect_language"`        // 按识别结果检测用户语言，用同一语言回复并切换音色
+	ReplayAudio         bool     `json:"replay_audio"`           // 缓存每轮回复的 TTS 音频，“再说一遍”时直接重播，关闭时重新合成
+	MicMuteEarcon       bool     `json:"mic_mute_earcon"`        // 开关麦克风（隐私模式）时播放确认音，没有 mic_off / mic_on 提示音文件时使用内置提示音
+	HalfDuplex          bool     `json:"half_duplex"`            // 播报期间暂停向 ASR 推流（本地 VAD 仍可打断），用于没有回声消除的设备
+	Mode                string   `json:"mode"`                   // assistant（语音助手）或 translate（同声传译）
+
+	Reprompt  RepromptConfig  `json:"reprompt"`
+	EchoGuard EchoGuardConfig `json:"echo_guard"`
+	Response  ResponseConfig  `json:"response"`
+	Commands  CommandsConfig  `json:"commands"`
+	Sleep     SleepConfig     `json:"sleep"`
+}
+
+// SleepConfig 长时间没有对话时进入休眠并播报，休眠期间只响应唤醒词
+type SleepConfig struct {
+	IdleMinutes int      `json:"idle_minutes"` // 空闲超过该分钟数进入休眠，0 表示关闭
+	WakeWords   []string `json:"wake_words"`   // 唤醒词，为空时任何语句都会唤醒
+	PauseASR    bool     `json:"pause_asr"`    // 休眠期间暂停向 ASR 推流，只能由文本输入、播报或接口唤醒，不能与 wake_words 同时配置
+	Text        string   `json:"text"`         // 进入休眠时的播报，为空时使用默认话术
+	WakePrompt  string   `json:"wake_prompt"`  // 只说了唤醒词时播放的提示音
+}
+
+// CommandsConfig “停”“大声点”“再说一遍”等语音控制命令，由编排器直接处理、不调用 LLM
+type CommandsConfig struct {
+	Enable     bool                `json:"enable"`
+	Phrases    map[string][]string `json:"phrases"`     // 覆盖指定命令的触发话术（stop / volume_up / volume_down / repeat / mute / unmute / mic_off），未列出的命令使用默认话术
+	VolumeStep float64             `json:"volume_step"` // 每次调大 / 调小的音量（0~1）
+}
+
+// controlCommands 可配置话术的语音控制命令
+var controlCommands = map[string]bool{
+	"stop": true, "volume_up": true, "volume_down": true, "repeat": true, "mute": true, "unmute": true, "mic_off": true,
+}
+
+// ResponseConfig 回复长度控制：写入系统提示词，并在播报时硬截断
+type ResponseConfig struct {
+	MaxSentences  int    `json:"max_sentences"`  // 每轮最多播报的句数，0 表示不限制
+	MaxChars      int    `json:"max_chars"`      // 每轮最多播报的字数，0 表示不限制
+	SummarizeOver int    `json:"summarize_over"` // 要求 LLM 在完整回答超过该句数时先概括并询问是否展开，0 表示不要求
+	FollowUp      string `json:"follow_up"`      // 截断后的追问
+}
+
+// RepromptConfig 识别结果置信度低或过短时请用户再说一遍
+type RepromptConfig struct {
+	Enable         bool     `json:"enable"`
+	MinConfidence  float64  `json:"min_confidence"`  // ASR 置信度低于该值时重问（0~1），0 表示不检查；ASR 未返回置信度时不检查
+	MinChars       int      `json:"min_chars"`       // 去掉标点和语气词后少于该字数时重问，0 表示不检查
+	Fillers        []string `json:"fillers"`         // 不计入字数的语气词，为空时使用默认列表
+	Text           string   `json:"text"`            // 重问话术，未加载 repeat 提示音时启动阶段用 TTS 预合成
+	MaxConsecutive int      `json:"max_consecutive"` // 连续重问的上限，达到后下一句直接交给 LLM
+}
+
+// EchoGuardConfig 识别结果与最近播报的回复相似时视为识别到了自己的声音，丢弃而不交给 LLM
+type EchoGuardConfig struct {
+	Enable        bool    `json:"enable"`
+	WindowMs      int     `json:"window_ms"`      // 回复播完后仍参与比对的时长
+	MinSimilarity float64 `json:"min_similarity"` // 与播报文本最相近片段的相似度（0~1）不低于该值时丢弃
+	MinChars      int     `json:"min_chars"`      // 去掉标点后少于该字数的识别结果不比对
+}
+
+type KnowledgeConfig struct {
+	Enable       bool            `json:"enable"`
+	Paths        []string        `json:"paths"`         // 导入的文档文件或目录（递归），支持 .txt / .md
+	ChunkSize    int             `json:"chunk_size"`    // 每个片段的最大字符数
+	ChunkOverlap int             `json:"chunk_overlap"` // 相邻片段重叠的字符数
+	TopK         int             `json:"top_k"`         // 每轮插入提示词的片段数
+	MinScore     float64         `json:"min_score"`     // 相似度下限，低于该值的片段不使用
+	Embedding    EmbeddingConfig `json:"embedding"`
+}
+
+type EmbeddingConfig struct {
+	Provider   string `json:"provider"` // local（本地哈希向量）或 openai（OpenAI 兼容 /embeddings 接口）
+	APIKey     string `json:"api_key"`  // 为空时使用 llm.api_key
+	APIKeyFile string `json:"api_key_file"`
+	BaseURL    string `json:"base_url"` // 为空时使用 llm.base_url
+	Model      string `json:"model"`
+}
+
+type TranslationConfig struct {
+	Target string `json:"target"` // 目标语言，如 en
+	Source string `json:"source"` // 另一方的语言，非空时双向翻译
+}
+
+// WebConfig 内嵌网页界面：实时字幕、对话记录、状态显示和文本输入
+type WebConfig struct {
+	Enable      bool   `json:"enable"`
+	Addr        string `json:"addr"`         // 监听地址，默认只监听本机；网页没有鉴权，对外开放前请自行加反向代理
+	HistorySize int    `json:"history_size"` // 网页上保留的对话条数
+	// Audio 浏览器作为音频设备（优先 WebRTC 传输 Opus，不可用时回退为 WebSocket 传输 PCM），代替本机麦克风和扬声器，
+	// 不再打开 PortAudio 设备
+	Audio bool `json:"audio"`
+	// ICEServers WebRTC 使用的 STUN / TURN 服务器（如 stun:stun.l.google.com:19302），局域网或本机访问时可以为空
+	ICEServers []string `json:"ice_servers"`
+}
+
+// TelephonyConfig 电话接入：通过 Asterisk / FreeSWITCH 的音频分流接入通话，代替本机麦克风和扬声器
+type TelephonyConfig struct {
+	Enable        bool   `json:"enable"`
+	Protocol      string `json:"protocol"`        // audiosocket（Asterisk AudioSocket，TCP）或 rtp（PCMU，UDP）
+	Addr          string `json:"addr"`            // 监听地址
+	IdleTimeoutMs int    `json:"idle_timeout_ms"` // rtp 超过该时长没有收到音频视为挂断
+}
+
+// MQTTConfig MQTT 桥接：发布对话事件，订阅主题上的消息直接播报或作为对话轮次
+type MQTTConfig struct {
+	Enable       bool     `json:"enable"`
+	Broker       string   `json:"broker"` // tcp://host:1883，TLS 使用 ssl://host:8883
+	ClientID     string   `json:"client_id"`
+	Username     string   `json:"username"`
+	Password     string   `json:"password"`       // 可用环境变量 MQTT_PASSWORD 覆盖
+	PasswordFile string   `json:"password_file"`  // 从文件读取 password，设置时优先于 password
+	KeepAliveSec int      `json:"keep_alive_sec"` // 心跳间隔
+	EventTopic   string   `json:"event_topic"`    // 对话事件主题前缀，为空时不发布
+	SayTopics    []string `json:"say_topics"`     // 消息内容直接播报，如 home/doorbell
+	AskTopics    []string `json:"ask_topics"`     // 消息内容作为用户的一句话交给 Agent
+}
+
+// IndicatorConfig 设备状态指示（如树莓派 LED 灯环）：启动 Command 并把语音活动逐行写入其标准输入
+type IndicatorConfig struct {
+	// Command 程序及参数，为空时不启动；每行一个活动：idle、listening、speech、thinking、
+	// speaking_started、speaking_ended、sleeping
+	Command []string `json:"command"`
+}
+
+// RealtimeConfig 端到端实时语音：麦克风音频直接发送给实时语音大模型，代替 ASR + LLM + TTS
+type RealtimeConfig struct {
+	Enable     bool   `json:"enable"`
+	Provider   string `json:"provider"` // openai 或 glm（事件协议相同），决定下列字段的默认值
+	URL        string `json:"url"`      // WebSocket 地址，为空时使用服务商默认地址
+	APIKey     string `json:"api_key"`  // 为空时使用 llm.api_key，可用环境变量 REALTIME_API_KEY 覆盖
+	APIKeyFile string `json:"api_key_file"`
+	Model      string `json:"model"`
+	Voice      string `json:"voice"`
+	// TranscriptionModel 用户语音转写模型，转写结果用于字幕、语音命令和触发回复
+	TranscriptionModel string `json:"transcription_model"`
+	SampleRate         int    `json:"sample_rate"` // 与服务端交换的 PCM16 采样率
+	ClipMs             int    `json:"clip_ms"`     // 回复语音按该时长切段播放
+}
+
+// CalendarConfig 日历：启用后注册 createEvent / getEvents 工具
+type CalendarConfig struct {
+	Enable                 bool                 `json:"enable"`
+	Provider               string               `json:"provider"`                 // caldav 或 google
+	Timezone               string               `json:"timezone"`                 // IANA 时区，如 Asia/Shanghai，为空时使用本机时区
+	DefaultDurationMinutes int                  `json:"default_duration_minutes"` // 未给出结束时间时的日程时长
+	CalDAV                 CalDAVCalendarConfig `json:"caldav"`
+	Google                 GoogleCalendarConfig `json:"google"`
+}
+
+// CalDAVCalendarConfig CalDAV 日历集合及 Basic 认证
+type CalDAVCalendarConfig struct {
+	URL          string `json:"url"` // 日历集合地址
+	Username     string `json:"username"`
+	Password     string `json:"password"` // 建议使用应用专用密码
+	PasswordFile string `json:"password_file"`
+}
+
+// GoogleCalendarConfig Google Calendar OAuth 客户端及刷新令牌（需要 calendar.events 权限）
+type GoogleCalendarConfig struct {
+	CalendarID       string `json:"calendar_id"`
+	ClientID         string `json:"client_id"`
+	ClientSecret     string `json:"client_secret"`
+	ClientSecretFile string `json:"client_secret_file"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshTokenFile string `json:"refresh_token_file"`
+}
+
+// NewsConfig 新闻：启用后注册 getNews 工具
+type NewsConfig struct {
+	Enable       bool             `json:"enable"`
+	Feeds        []NewsFeedConfig `json:"feeds"`
+	Limit        int              `json:"limit"`         // 未指定条数时返回的新闻数
+	SummaryChars int              `json:"summary_chars"` // 每条摘要最多字符数
+	CacheMinutes int              `json:"cache_minutes"` // 订阅内容缓存时间
+}
+
+// NewsFeedConfig 一个 RSS / Atom 新闻源
+type NewsFeedConfig struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// RoutineConfig 定时例程：到点时把 text 作为一轮用户输入（ask）或直接播报（say）
+type RoutineConfig struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"` // cron 表达式：分 时 日 月 周，如 "30 7 * * 1-5"
+	Text     string `json:"text"`
+	Action   string `json:"action"` // ask（默认）或 say
+}
+
+// 例程动作
+const (
+	RoutineActionAsk = "ask"
+	RoutineActionSay = "say"
+)
+
+// ContentFilterConfig 敏感内容过滤，用于展台、儿童等场景
+type ContentFilterConfig struct {
+	Enable       bool     `json:"enable"`
+	Words        []string `json:"words"`         // 敏感词，英文按整词、不区分大小写匹配
+	WordsFile    string   `json:"words_file"`    // 词表文件，每行一个词，# 开头为注释
+	Patterns     []string `json:"patterns"`      // 正则表达式（Go RE2 语法）
+	Mask         string   `json:"mask"`          // 屏蔽时替换每个字符的字符串
+	InputAction  string   `json:"input_action"`  // 用户语句命中时：mask（屏蔽后交给 LLM）、reject（不交给 LLM）或 none
+	OutputAction string   `json:"output_action"` // 回复命中时：mask（屏蔽后播报）、reject（停止本轮回复）或 none
+	RejectText   string   `json:"reject_text"`   // reject 时的回应，未加载 refuse 提示音时启动阶段用 TTS 预合成
+}
+
+// 内容过滤动作
+const (
+	FilterActionNone   = "none"
+	FilterActionMask   = "mask"
+	FilterActionReject = "reject"
+)
+
+// 对话模式
+const (
+	ModeAssistant = "assistant"
+	ModeTranslate = "translate"
+)
+
+type SpeakerConfig struct {
+	Enable        bool    `json:"enable"`
+	Voiceprints   string  `json:"voiceprints"`    // 声纹库文件，由 cmd/speaker 注册
+	Threshold     float64 `json:"threshold"`      // 声纹相似度阈值（0~1）
+	IgnoreUnknown bool    `json:"ignore_unknown"` // 忽略未注册说话人的语句，关闭时只标注说话人
 }
 
 type ToolsConfig struct {
-	Types           map[string]string `json:"types"`
-	ActionResponses map[string]string `json:"action_responses"`
+	Types           map[string]string      `json:"types"`
+	ActionResponses map[string]string      `json:"action_responses"`
+	Audit           ToolAuditConfig        `json:"audit"`
+	Confirmation    ToolConfirmationConfig `json:"confirmation"`
+	SlotFilling     ToolSlotFillingConfig  `json:"slot_filling"`
+	Progress        ToolProgressConfig     `json:"progress"`
+	Retrieval       ToolRetrievalConfig    `json:"retrieval"`
+	HTTP            []HTTPToolConfig       `json:"http"` // 配置声明的 HTTP 工具，启动时注册到 ToolExecutor
+	Shell           ShellToolsConfig       `json:"shell"`
+}
+
+// ToolAuditConfig 工具审计日志：每次工具执行写入独立的 JSON Lines 文件，按会话（trace ID）查询
+type ToolAuditConfig struct {
+	Enable         bool     `json:"enable"`
+	Path           string   `json:"path"`
+	RedactFields   []string `json:"redact_fields"`    // 需要脱敏的参数名，不区分大小写
+	ResultMaxChars int      `json:"result_max_chars"` // 结果摘要的最大字符数，0 表示不截断
+}
+
+// ToolConfirmationConfig 动作类工具的口头确认：列出的工具执行前先播报确认话术，用户回答肯定话术后才执行
+type ToolConfirmationConfig struct {
+	Tools         map[string]string `json:"tools"`          // 工具名 -> 确认话术（支持 {{参数名}}），为空字符串时使用默认话术
+	YesPhrases    []string          `json:"yes_phrases"`    // 肯定回答，为空时使用内置列表
+	NoPhrases     []string          `json:"no_phrases"`     // 否定回答，为空时使用内置列表
+	TimeoutMs     int               `json:"timeout_ms"`     // 等待回答的时长，0 表示不限制
+	ConfirmedText string            `json:"confirmed_text"` // 确认执行时的播报
+	CancelledText string            `json:"cancelled_text"` // 取消时的播报
+	DryRun        bool              `json:"dry_run"`        // 演练模式：确认后也不实际执行
+}
+
+// ToolSlotFillingConfig 工具调用缺少必填参数时先追问用户（如“请问要查哪个城市？”），把回答填入参数后再执行
+type ToolSlotFillingConfig struct {
+	Enable         bool     `json:"enable"`
+	TimeoutMs      int      `json:"timeout_ms"`       // 等待回答的时长，0 表示不限制
+	MaxAnswerChars int      `json:"max_answer_chars"` // 回答超过该字数时视为新的请求、放弃本次调用，0 表示不限制
+	CancelPhrases  []string `json:"cancel_phrases"`   // 放弃本次调用的回答，为空时使用内置列表
+	FilledText     string   `json:"filled_text"`      // 参数补全、开始执行时的播报
+	CancelledText  string   `json:"cancelled_text"`   // 放弃时的播报
+}
+
+// ToolProgressConfig 长耗时工具的进度提醒：超过 delay_ms 未返回时发布进度事件，并在没有播报时说一句等待话术
+type ToolProgressConfig struct {
+	DelayMs    int    `json:"delay_ms"`    // 第一次提醒前的等待时长，0 表示关闭
+	IntervalMs int    `json:"interval_ms"` // 之后的提醒间隔，0 表示只提醒一次
+	Speak      bool   `json:"speak"`       // 播报等待话术，关闭时只发布事件
+	Text       string `json:"text"`        // 等待话术，工具自己上报进度时播报工具的描述
+}
+
+// HTTPToolConfig 配置声明的 HTTP 工具：按模板拼出请求（{{参数名}}、{{env:NAME}}），从 JSON 响应中按路径取出结果
+type HTTPToolConfig struct {
+	Name        string                         `json:"name"`
+	Description string                         `json:"description"`
+	Action      bool                           `json:"action"` // 动作类工具（直接播报），否则为查询类
+	Parameters  map[string]ToolParameterConfig `json:"parameters"`
+	Method      string                         `json:"method"` // 为空时为 GET
+	URL         string                         `json:"url"`
+	Headers     map[string]string              `json:"headers"`
+	Body        string                         `json:"body"`
+	Result      string                         `json:"result"`     // JSONPath 子集，如 $.data.price，为空时返回整个响应
+	TimeoutMs   int                            `json:"timeout_ms"` // 0 表示不限制
+}
+
+// ShellToolsConfig 本地命令工具：默认关闭，开启时必须同时开启 tools.audit
+type ShellToolsConfig struct {
+	Enable bool              `json:"enable"`
+	Tools  []ShellToolConfig `json:"tools"`
+}
+
+// ShellToolConfig 配置声明的本地命令工具：直接执行程序（不经过 shell），command 中的每一项单独替换 {{参数名}}
+type ShellToolConfig struct {
+	Name           string                         `json:"name"`
+	Description    string                         `json:"description"`
+	Action         bool                           `json:"action"`
+	Parameters     map[string]ToolParameterConfig `json:"parameters"`
+	Command        []string                       `json:"command"`          // 程序及参数，程序本身不能包含占位符
+	Dir            string                         `json:"dir"`              // 工作目录
+	TimeoutMs      int                            `json:"timeout_ms"`       // 0 时为 10 秒
+	Env            []string                       `json:"env"`              // 允许传给命令的环境变量名，其余不传递
+	MaxOutputBytes int                            `json:"max_output_bytes"` // 0 时为 4096
+}
+
+// ToolParameterConfig 工具参数定义
+type ToolParameterConfig struct {
+	Type        string   `json:"type"` // string、integer、number、boolean，为空时为 string
+	Description string   `json:"description"`
+	Required    bool     `json:"required"`
+	Enum        []string `json:"enum"`
+	Ask         string   `json:"ask"` // 缺少该必填参数时的追问话术，为空时按 description 生成
+}
+
+// ToolRetrievalConfig 工具检索：工具很多时每轮按用户输入与工具名称、说明的相似度选出 top_k 个工具，只把这些工具交给主 LLM
+type ToolRetrievalConfig struct {
+	Enable    bool            `json:"enable"`
+	TopK      int             `json:"top_k"`     // 每轮选择的工具数，工具总数不超过 top_k 加 always 时不检索
+	MinScore  float64         `json:"min_score"` // 相似度下限，低于该值的工具不选
+	Always    []string        `json:"always"`    // 每轮都绑定的工具，不占 top_k
+	Shadow    bool            `json:"shadow"`    // 影子模式：只统计选择准确率，仍绑定全部工具
+	Embedding EmbeddingConfig `json:"embedding"` // local 为字符 n-gram 关键词匹配；openai 时密钥和地址为空则沿用 llm
 }
 
 func DefaultConfig() *AppConfig {
@@ -102,7 +670,10 @@ func DefaultConfig() *AppConfig {
 	return &AppConfig{
 		Logging: LoggingConfig{},
 		ASR: ASRConfig{
-			Model: "fun-asr-realtime",
+			Model:       "fun-asr-realtime",
+			Heartbeat:   true,
+			KeepaliveMs: 10000,
+			Vocabulary:  VocabularyConfig{Prefix: "orionx"},
 		},
 		TTS: TTSConfig{
 			Model:                "cosyvoice-v3-flash",
@@ -114,41 +685,108 @@ func DefaultConfig() *AppConfig {
 			Pitch:                1.0,
 			TextType:             "PlainText",
 			EnableDataInspection: &enableDataInspection,
-			VoiceMap: map[string]string{
-				"happy":   "longanyang",
-				"sad":     "zhichu",
-				"angry":   "zhimeng",
-				"calm":    "longxiaochun",
-				"excited": "longanyang",
-				"default": "longanyang",
+			VoiceMap: map[string]VoiceConfig{
+				"happy":   {Voice: "longanyang"},
+				"sad":     {Voice: "zhichu"},
+				"angry":   {Voice: "zhimeng"},
+				"calm":    {Voice: "longxiaochun"},
+				"excited": {Voice: "longanyang"},
+				"default": {Voice: "longanyang"},
+			},
+			SSML: SSMLConfig{
+				CommaBreakMs:          150,
+				ExclamationPitchBoost: 0.05,
+				EmotionProsody: map[string]ProsodyConfig{
+					"happy":   {Rate: 1.05, Pitch: 1.05},
+					"sad":     {Rate: 0.9, Pitch: 0.95},
+					"angry":   {Rate: 1.1, Pitch: 1.0},
+					"calm":    {Rate: 0.95, Pitch: 1.0},
+					"excited": {Rate: 1.1, Pitch: 1.1},
+				},
+			},
+			Normalization: NormalizationConfig{
+				Enable: true,
+				Locale: "zh",
 			},
 		},
 		LLM: LLMConfig{
-			BaseURL: "https://open.bigmodel.cn/api/coding/paas/v4",
-			Model:   "glm-4-flash",
+			Provider:       "openai",
+			MaxRetries:     1,
+			RetryBackoffMs: 300,
+			IntentRouter: IntentRouterConfig{
+				MaxTools:  8,
+				TimeoutMs: 2000,
+			},
 		},
 		Audio: AudioConfig{
+			Backend: "portaudio",
 			Mixer: MixerConfig{
 				TTSVolume:      1.0,
 				ResourceVolume: 1.0,
+				SampleRate:     16000,
+				Channels:       2,
+				CrossfadeMs:    120,
+				FadeOutMs:      50,
+				Volume:         1.0,
+				VolumeState:    "config/volume.json",
 			},
 			TTSPipeline: TTSPipelineConfig{
 				MaxTTSBuffer:     3,
 				MaxConcurrentTTS: 2,
 				TextQueueSize:    100,
+				PrerollMs:        200,
+				BatchMaxChars:    60,
+				BatchMinChars:    10,
+				BatchWaitMs:      150,
+			},
+			Prompts: PromptsConfig{
+				Dir: "assets/prompts",
+			},
+			Levels: LevelsConfig{
+				SilenceThreshold: 0.001,
+				SilenceWarnMs:    60000,
+				ClipWarnMs:       10000,
+			},
+			DebugRecord: DebugRecordConfig{
+				Dir: "recordings",
 			},
 			InPipe: InPipeConfig{
-				SampleRate:   16000,
-				Channels:     1,
-				EnableVAD:    true,
-				VADThreshold: 0.5,
+				SampleRate:    16000,
+				Channels:      1,
+				EnableVAD:     true,
+				VADThreshold:  0.5,
+				ChannelSelect: -1,
 				AEC: AECConfig{
 					Enable:                  true,
 					Mode:                    "gate",
 					FrameMs:                 10,
 					FarEndDelayMs:           50,
 					ReferenceActiveWindowMs: 200,
+					AlignPlayback:           true,
+					GateAttenuationDb:       30,
+					GateHangoverMs:          150,
+					GateDoubleTalkRatio:     1,
 				},
+				DSP: DSPConfig{
+					HighPass: HighPassConfig{
+						Enable:   true,
+						CutoffHz: 80,
+						Q:        0.707,
+					},
+					AGC: AGCConfig{
+						TargetRMS: 0.1,
+						MaxGain:   10,
+						AttackMs:  10,
+						ReleaseMs: 500,
+					},
+					NoiseSuppression: NoiseSuppressionConfig{
+						Strength: 2,
+						Floor:    0.1,
+					},
+				},
+				ReconnectInitialBackoffMs: 500,
+				ReconnectMaxBackoffMs:     30000,
+				ReplayBufferMs:            3000,
 			},
 		},
 		Tools: ToolsConfig{
@@ -165,11 +803,141 @@ func DefaultConfig() *AppConfig {
 				"setVolume":  "已将音量设置为{{level}}",
 				"pauseMusic": "音乐已暂停",
 			},
+			Audit: ToolAuditConfig{
+				Path:           "logs/tool-audit.jsonl",
+				RedactFields:   []string{"password", "pin", "token", "api_key", "secret"},
+				ResultMaxChars: 200,
+			},
+			Confirmation: ToolConfirmationConfig{
+				TimeoutMs:     15000,
+				ConfirmedText: "好的",
+				CancelledText: "好的，已取消",
+			},
+			SlotFilling: ToolSlotFillingConfig{
+				Enable:         true,
+				TimeoutMs:      15000,
+				MaxAnswerChars: 15,
+				FilledText:     "好的",
+				CancelledText:  "好的",
+			},
+			Progress: ToolProgressConfig{
+				DelayMs:    5000,
+				IntervalMs: 15000,
+				Speak:      true,
+				Text:       "还在查询，请稍等",
+			},
+			Retrieval: ToolRetrievalConfig{
+				TopK:     5,
+				MinScore: 0.05,
+				Embedding: EmbeddingConfig{
+					Provider: "local",
+				},
+			},
+		},
+		Conversation: ConversationConfig{
+			FillerPrompt:        "thinking",
+			FillerText:          "让我想想…",
+			ShutdownDrainMs:     5000,
+			SegmentFlushMs:      800,
+			EndOfTurnSilenceMs:  600,
+			ProcessingTimeoutMs: 30000,
+			SpeakingTimeoutMs:   30000,
+			FollowUpWindowMs:    8000,
+			ErrorText:           "抱歉，我这边网络不太好",
+			ErrorRetries:        1,
+			ErrorRetryDelayMs:   1000,
+			DetectLanguage:      true,
+			ReplayAudio:         true,
+			MicMuteEarcon:       true,
+			Mode:                ModeAssistant,
+			Reprompt: RepromptConfig{
+				Enable:         true,
+				MinConfidence:  0.4,
+				MinChars:       1,
+				Text:           "不好意思，没听清，您再说一遍？",
+				MaxConsecutive: 2,
+			},
+			EchoGuard: EchoGuardConfig{
+				Enable:        true,
+				WindowMs:      3000,
+				MinSimilarity: 0.8,
+				MinChars:      4,
+			},
+			Sleep: SleepConfig{
+				WakePrompt: "wake",
+			},
+			Response: ResponseConfig{
+				MaxSentences:  6,
+				MaxChars:      300,
+				SummarizeOver: 4,
+				FollowUp:      "需要我继续吗？",
+			},
+			Commands: CommandsConfig{
+				Enable:     true,
+				VolumeStep: 0.2,
+			},
+		},
+		ContentFilter: ContentFilterConfig{
+			Mask:         "*",
+			InputAction:  FilterActionReject,
+			OutputAction: FilterActionMask,
+			RejectText:   "这个话题我们换一个吧。",
+		},
+		Web: WebConfig{
+			Addr:        "127.0.0.1:8080",
+			HistorySize: 50,
+		},
+		MQTT: MQTTConfig{
+			Broker:       "tcp://127.0.0.1:1883",
+			ClientID:     "orion-x-voicebot",
+			KeepAliveSec: 60,
+			EventTopic:   "orion-x/events",
+		},
+		Realtime: RealtimeConfig{
+			Provider:   "openai",
+			SampleRate: 24000,
+			ClipMs:     500,
+		},
+		News: NewsConfig{
+			Limit:        5,
+			SummaryChars: 120,
+			CacheMinutes: 10,
+		},
+		Calendar: CalendarConfig{
+			Provider:               "caldav",
+			DefaultDurationMinutes: 60,
+			Google: GoogleCalendarConfig{
+				CalendarID: "primary",
+			},
+		},
+		Telephony: TelephonyConfig{
+			Protocol:      "audiosocket",
+			Addr:          "127.0.0.1:9092",
+			IdleTimeoutMs: 5000,
+		},
+		Speaker: SpeakerConfig{
+			Voiceprints: "config/voiceprints.json",
+			Threshold:   0.85,
+		},
+		Knowledge: KnowledgeConfig{
+			ChunkSize:    300,
+			ChunkOverlap: 50,
+			TopK:         3,
+			MinScore:     0.2,
+			Embedding: EmbeddingConfig{
+				Provider: "local",
+			},
 		},
 	}
 }
 
 func Load(path string) (*AppConfig, error) {
+	return LoadProfile(path, "")
+}
+
+// LoadProfile 加载配置文件并叠加配置档：profile 为空时依次取 VOICEBOT_PROFILE 和文件中的 profile，
+// 多个配置档用逗号分隔、按顺序叠加。配置档中的对象逐字段合并，数组整体替换
+func LoadProfile(path, profile string) (*AppConfig, error) {
 	path = strings.TrimSpace(path)
 	if path == "" {
 		path = DefaultPath
@@ -177,22 +945,127 @@ func Load(path string) (*AppConfig, error) {
 
 	cfg := DefaultConfig()
 	data, err := os.ReadFile(path)
-	if err != nil {
-		if errors.Is(err, os.ErrNotExist) {
-			cfg.ApplyEnv()
-			return cfg, cfg.Validate()
-		}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
 		return nil, fmt.Errorf("read config %s: %w", path, err)
 	}
+	if err == nil {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("parse config %s: %w", path, err)
+		}
+	}
 
-	if err := json.Unmarshal(data, cfg); err != nil {
-		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	if strings.TrimSpace(profile) == "" {
+		profile = os.Getenv(ProfileEnv)
+	}
+	if strings.TrimSpace(profile) == "" {
+		profile = cfg.Profile
+	}
+	if err := cfg.applyProfiles(profile); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := cfg.resolveSecrets(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
 	}
 
 	cfg.ApplyEnv()
 	return cfg, cfg.Validate()
 }
 
+// applyProfiles 按顺序把逗号分隔的配置档叠加到当前配置
+func (c *AppConfig) applyProfiles(profile string) error {
+	profiles := c.Profiles
+	for _, name := range strings.Split(profile, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		overlay, ok := profiles[name]
+		if !ok {
+			return fmt.Errorf("unknown profile %q (available: %s)", name, profileNames(profiles))
+		}
+		var keys map[string]json.RawMessage
+		if err := json.Unmarshal(overlay, &keys); err != nil {
+			return fmt.Errorf("parse profile %q: %w", name, err)
+		}
+		if _, nested := keys["profiles"]; nested {
+			return fmt.Errorf("profile %q must not define profiles", name)
+		}
+		if _, nested := keys["profile"]; nested {
+			return fmt.Errorf("profile %q must not select profiles", name)
+		}
+		if err := json.Unmarshal(overlay, c); err != nil {
+			return fmt.Errorf("parse profile %q: %w", name, err)
+		}
+		c.ActiveProfiles = append(c.ActiveProfiles, name)
+	}
+	return nil
+}
+
+func profileNames(profiles map[string]json.RawMessage) string {
+	if len(profiles) == 0 {
+		return "none"
+	}
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// UpdateFile 把 values 写回配置文件，键为点分路径（如 "audio.in_pipe.vad_threshold"）。
+// 文件中的其他字段原样保留（键按字母序重新排列），缺失的中间对象自动创建；文件不存在时新建。
+// 写入前按合并默认值后的完整配置校验，校验失败时不修改文件。
+func UpdateFile(path string, values map[string]interface{}) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		path = DefaultPath
+	}
+
+	root := map[string]interface{}{}
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		decoder := json.NewDecoder(strings.NewReader(string(data)))
+		decoder.UseNumber()
+		if err := decoder.Decode(&root); err != nil {
+			return fmt.Errorf("parse config %s: %w", path, err)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return fmt.Errorf("read config %s: %w", path, err)
+	}
+
+	for key, value := range values {
+		parts := strings.Split(key, ".")
+		node := root
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := node[part].(map[string]interface{})
+			if !ok {
+				if _, exists := node[part]; exists {
+					return fmt.Errorf("config key %s: %s is not an object", key, part)
+				}
+				child = map[string]interface{}{}
+				node[part] = child
+			}
+			node = child
+		}
+		node[parts[len(parts)-1]] = value
+	}
+
+	out, err := json.MarshalIndent(root, "", "    ")
+	if err != nil {
+		return fmt.Errorf("encode config %s: %w", path, err)
+	}
+	cfg := DefaultConfig()
+	if err := json.Unmarshal(out, cfg); err != nil {
+		return fmt.Errorf("encode config %s: %w", path, err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0o600)
+}
+
 func (c *AppConfig) ApplyEnv() {
 	if level := strings.TrimSpace(os.Getenv("LOG_LEVEL")); level != "" {
 		c.Logging.Level = level
@@ -200,6 +1073,9 @@ func (c *AppConfig) ApplyEnv() {
 	if format := strings.TrimSpace(os.Getenv("LOG_FORMAT")); format != "" {
 		c.Logging.Format = format
 	}
+	if file := strings.TrimSpace(os.Getenv("LOG_FILE")); file != "" {
+		c.Logging.File.Path = file
+	}
 
 	if dash := strings.TrimSpace(os.Getenv("DASHSCOPE_API_KEY")); dash != "" {
 		c.ASR.APIKey = dash
@@ -212,16 +1088,64 @@ func (c *AppConfig) ApplyEnv() {
 	if zhipu := strings.TrimSpace(os.Getenv("ZHIPU_API_KEY")); zhipu != "" {
 		c.LLM.APIKey = zhipu
 	}
+	if gemini := strings.TrimSpace(os.Getenv("GEMINI_API_KEY")); gemini != "" && c.LLM.Provider == "gemini" {
+		c.LLM.APIKey = gemini
+	}
+
+	if password := os.Getenv("MQTT_PASSWORD"); password != "" {
+		c.MQTT.Password = password
+	}
+	if key := strings.TrimSpace(os.Getenv("REALTIME_API_KEY")); key != "" {
+		c.Realtime.APIKey = key
+	}
 }
 
 func (c *AppConfig) Validate() error {
+	for name := range c.Profiles {
+		if strings.TrimSpace(name) == "" || strings.ContainsAny(name, ".,") {
+			return fmt.Errorf("profiles: invalid profile name %q", name)
+		}
+	}
 	if c.Audio.InPipe.SampleRate <= 0 {
 		return errors.New("audio.in_pipe.sample_rate must be positive")
 	}
+	switch c.Audio.Backend {
+	case "", "portaudio":
+	case "alsa", "pulse":
+		if c.Audio.FullDuplex {
+			return errors.New("audio.full_duplex requires audio.backend portaudio")
+		}
+	default:
+		return fmt.Errorf("audio.backend must be portaudio, alsa or pulse, got %q", c.Audio.Backend)
+	}
 	if c.TTS.SampleRate <= 0 {
 		return errors.New("tts.sample_rate must be positive")
 	}
 
+	for component, level := range c.Logging.Levels {
+		if !isLogLevel(level) {
+			return fmt.Errorf("logging.levels.%s must be debug, info, warn or error, got %q", component, level)
+		}
+	}
+	if c.Logging.File.Level != "" && !isLogLevel(c.Logging.File.Level) {
+		return fmt.Errorf("logging.file.level must be debug, info, warn or error, got %q", c.Logging.File.Level)
+	}
+	if c.Logging.File.MaxSizeMB < 0 || c.Logging.File.RotateHours < 0 || c.Logging.File.MaxBackups < 0 {
+		return errors.New("logging.file.max_size_mb, rotate_hours and max_backups must be non-negative")
+	}
+
+	if prefix := c.ASR.Vocabulary.Prefix; prefix != "" && !isVocabularyPrefix(prefix) {
+		return fmt.Errorf("asr.vocabulary.prefix must be 1-10 lowercase letters or digits, got %q", prefix)
+	}
+	for i, word := range c.ASR.Vocabulary.Hotwords {
+		if strings.TrimSpace(word.Text) == "" {
+			return fmt.Errorf("asr.vocabulary.hotwords[%d].text must not be empty", i)
+		}
+		if word.Weight < 0 || word.Weight > 5 {
+			return fmt.Errorf("asr.vocabulary.hotwords[%d].weight must be between 1 and 5 (0 for default)", i)
+		}
+	}
+
 	for name, value := range c.Tools.Types {
 		lower := strings.ToLower(strings.TrimSpace(value))
 		switch lower {
@@ -231,6 +1155,46 @@ func (c *AppConfig) Validate() error {
 			return fmt.Errorf("invalid tool type for %s: %s", name, value)
 		}
 	}
+	if c.Tools.Audit.Enable && strings.TrimSpace(c.Tools.Audit.Path) == "" {
+		return errors.New("tools.audit.path is required when audit is enabled")
+	}
+	if c.Tools.Audit.ResultMaxChars < 0 {
+		return errors.New("tools.audit.result_max_chars must be non-negative")
+	}
+	if c.Tools.Confirmation.TimeoutMs < 0 {
+		return errors.New("tools.confirmation.timeout_ms must be non-negative")
+	}
+	if c.Tools.SlotFilling.TimeoutMs < 0 || c.Tools.SlotFilling.MaxAnswerChars < 0 {
+		return errors.New("tools.slot_filling.timeout_ms and max_answer_chars must be non-negative")
+	}
+	if c.Tools.Progress.DelayMs < 0 || c.Tools.Progress.IntervalMs < 0 {
+		return errors.New("tools.progress.delay_ms and interval_ms must be non-negative")
+	}
+
+	if c.TTS.BufferBytes < 0 {
+		return errors.New("tts.buffer_bytes must be non-negative")
+	}
+	switch c.TTS.BufferPolicy {
+	case "", "block", "drop_oldest", "drop_newest":
+	default:
+		return fmt.Errorf("invalid tts.buffer_policy: %s", c.TTS.BufferPolicy)
+	}
+
+	for emotion, v := range c.TTS.VoiceMap {
+		if v.Rate != 0 && (v.Rate < 0.5 || v.Rate > 2) {
+			return fmt.Errorf("tts.voice_map.%s.rate must be within [0.5, 2]", emotion)
+		}
+		if v.Pitch != 0 && (v.Pitch < 0.5 || v.Pitch > 2) {
+			return fmt.Errorf("tts.voice_map.%s.pitch must be within [0.5, 2]", emotion)
+		}
+		if v.Volume < 0 || v.Volume > 100 {
+			return fmt.Errorf("tts.voice_map.%s.volume must be within [0, 100]", emotion)
+		}
+	}
+
+	if c.TTS.SSML.CommaBreakMs < 0 {
+		return errors.New("tts.ssml.comma_break_ms must be non-negative")
+	}
 
 	if c.Audio.InPipe.AEC.FrameMs < 0 {
 		return errors.New("audio.in_pipe.aec.frame_ms must be non-negative")
@@ -241,10 +1205,467 @@ func (c *AppConfig) Validate() error {
 	if c.Audio.InPipe.AEC.ReferenceActiveWindowMs < 0 {
 		return errors.New("audio.in_pipe.aec.reference_active_window_ms must be non-negative")
 	}
+	if c.Audio.InPipe.AEC.GateAttenuationDb < 0 || c.Audio.InPipe.AEC.GateHangoverMs < 0 || c.Audio.InPipe.AEC.GateDoubleTalkRatio < 0 {
+		return errors.New("audio.in_pipe.aec gate_attenuation_db, gate_hangover_ms and gate_double_talk_ratio must be non-negative")
+	}
+	if c.ASR.KeepaliveMs < 0 {
+		return errors.New("asr.keepalive_ms must be non-negative")
+	}
+	if c.Audio.InPipe.InputChannels < 0 {
+		return errors.New("audio.in_pipe.input_channels must be non-negative")
+	}
+	if c.Audio.InPipe.BufferSize < 0 {
+		return errors.New("audio.in_pipe.buffer_size must be non-negative")
+	}
+	if c.Audio.Mixer.SampleRate != 0 && (c.Audio.Mixer.SampleRate < 8000 || c.Audio.Mixer.SampleRate > 192000) {
+		return fmt.Errorf("audio.mixer.sample_rate must be between 8000 and 192000, got %d", c.Audio.Mixer.SampleRate)
+	}
+	if c.Audio.Mixer.Channels < 0 || c.Audio.Mixer.Channels > 2 {
+		return fmt.Errorf("audio.mixer.channels must be 1 or 2, got %d", c.Audio.Mixer.Channels)
+	}
+	if c.Audio.InPipe.ChannelSelect < -1 {
+		return errors.New("audio.in_pipe.channel_select must be -1 (downmix) or a channel index")
+	}
+	switch strings.TrimSuffix(strings.ToLower(c.Audio.InPipe.SampleFormat), "le") {
+	case "", "s16", "s24", "s32", "f32":
+	default:
+		return fmt.Errorf("audio.in_pipe.sample_format must be s16, s24, s32 or f32, got %q", c.Audio.InPipe.SampleFormat)
+	}
+	micArray := c.Audio.InPipe.MicArray
+	switch micArray.Strategy {
+	case "", "select", "delay_sum":
+	default:
+		return fmt.Errorf("audio.in_pipe.mic_array.strategy must be select or delay_sum, got %q", micArray.Strategy)
+	}
+	if micArray.SpeechSNRDb < 0 || micArray.HangoverMs < 0 {
+		return errors.New("audio.in_pipe.mic_array speech_snr_db and hangover_ms must be non-negative")
+	}
+	if micArray.MaxDelayMs < 0 || micArray.MaxDelayMs > 50 {
+		return errors.New("audio.in_pipe.mic_array.max_delay_ms must be between 0 and 50")
+	}
+	highPass := c.Audio.InPipe.DSP.HighPass
+	if highPass.CutoffHz < 0 || highPass.Q < 0 {
+		return errors.New("audio.in_pipe.dsp.high_pass values must be non-negative")
+	}
+	if highPass.CutoffHz >= float64(c.Audio.InPipe.SampleRate)/2 {
+		return errors.New("audio.in_pipe.dsp.high_pass.cutoff_hz must be below half the sample rate")
+	}
+	agc := c.Audio.InPipe.DSP.AGC
+	if agc.TargetRMS < 0 || agc.TargetRMS > 1 {
+		return errors.New("audio.in_pipe.dsp.agc.target_rms must be between 0 and 1")
+	}
+	if agc.MaxGain < 0 || agc.AttackMs < 0 || agc.ReleaseMs < 0 {
+		return errors.New("audio.in_pipe.dsp.agc values must be non-negative")
+	}
+	ns := c.Audio.InPipe.DSP.NoiseSuppression
+	if ns.Strength < 0 {
+		return errors.New("audio.in_pipe.dsp.noise_suppression.strength must be non-negative")
+	}
+	if ns.Floor < 0 || ns.Floor >= 1 {
+		return errors.New("audio.in_pipe.dsp.noise_suppression.floor must be in [0, 1)")
+	}
+	if c.Audio.InPipe.ReconnectInitialBackoffMs < 0 {
+		return errors.New("audio.in_pipe.reconnect_initial_backoff_ms must be non-negative")
+	}
+	if c.Audio.InPipe.ReconnectMaxBackoffMs < 0 {
+		return errors.New("audio.in_pipe.reconnect_max_backoff_ms must be non-negative")
+	}
+	if c.Audio.InPipe.ReplayBufferMs < 0 {
+		return errors.New("audio.in_pipe.replay_buffer_ms must be non-negative")
+	}
+	if c.Audio.InPipe.ASRFrameMs < 0 || c.Audio.InPipe.ASRFrameMs > 1000 {
+		return errors.New("audio.in_pipe.asr_frame_ms must be between 0 and 1000")
+	}
+	if rate := c.Audio.InPipe.ASRMaxSendRate; rate != 0 && rate < 1 {
+		return errors.New("audio.in_pipe.asr_max_send_rate must be 0 (unlimited) or at least 1")
+	}
+	if c.Audio.Mixer.CrossfadeMs < 0 {
+		return errors.New("audio.mixer.crossfade_ms must be non-negative")
+	}
+	if c.Audio.Mixer.FadeOutMs < 0 {
+		return errors.New("audio.mixer.fade_out_ms must be non-negative")
+	}
+	if c.Audio.Mixer.Volume < 0 || c.Audio.Mixer.Volume > 1 {
+		return errors.New("audio.mixer.volume must be between 0 and 1")
+	}
+	if c.Audio.TTSPipeline.PrerollMs < 0 {
+		return errors.New("audio.tts_pipeline.preroll_ms must be non-negative")
+	}
+	if c.Audio.TTSPipeline.BatchMaxChars < 0 || c.Audio.TTSPipeline.BatchMinChars < 0 || c.Audio.TTSPipeline.BatchWaitMs < 0 {
+		return errors.New("audio.tts_pipeline.batch_* must be non-negative")
+	}
+	if c.Audio.Levels.SilenceThreshold < 0 || c.Audio.Levels.SilenceThreshold >= 1 {
+		return errors.New("audio.levels.silence_threshold must be in [0, 1)")
+	}
+	if c.Audio.Levels.SilenceWarnMs < 0 || c.Audio.Levels.ClipWarnMs < 0 {
+		return errors.New("audio.levels.silence_warn_ms and clip_warn_ms must be non-negative")
+	}
+	if c.Audio.DebugRecord.Enable && c.Audio.DebugRecord.Dir == "" {
+		return errors.New("audio.debug_record.dir is required when debug recording is enabled")
+	}
+	if c.LLM.MaxRetries < 0 || c.LLM.RetryBackoffMs < 0 {
+		return errors.New("llm.max_retries and llm.retry_backoff_ms must be non-negative")
+	}
+	if !validLLMProvider(c.LLM.Provider) {
+		return fmt.Errorf("llm.provider must be openai or gemini, got %q", c.LLM.Provider)
+	}
+	for _, endpoint := range c.LLM.Fallbacks {
+		if !validLLMProvider(endpoint.Provider) {
+			return fmt.Errorf("llm.fallbacks provider must be openai or gemini, got %q", endpoint.Provider)
+		}
+	}
+	if router := c.LLM.IntentRouter; router.Enable {
+		if !validLLMProvider(router.Provider) {
+			return fmt.Errorf("llm.intent_router.provider must be openai or gemini, got %q", router.Provider)
+		}
+		if router.MaxTools < 0 || router.TimeoutMs < 0 {
+			return errors.New("llm.intent_router.max_tools and timeout_ms must be non-negative")
+		}
+	}
+	if c.Conversation.FillerDelayMs < 0 {
+		return errors.New("conversation.filler_delay_ms must be non-negative")
+	}
+	if c.Conversation.ShutdownDrainMs < 0 {
+		return errors.New("conversation.shutdown_drain_ms must be non-negative")
+	}
+	if c.Conversation.ProcessingTimeoutMs < 0 || c.Conversation.SpeakingTimeoutMs < 0 {
+		return errors.New("conversation.processing_timeout_ms and speaking_timeout_ms must be non-negative")
+	}
+	if c.Conversation.ErrorRetries < 0 || c.Conversation.ErrorRetryDelayMs < 0 {
+		return errors.New("conversation.error_retries and error_retry_delay_ms must be non-negative")
+	}
+	if c.Conversation.Reprompt.MinConfidence < 0 || c.Conversation.Reprompt.MinConfidence > 1 {
+		return errors.New("conversation.reprompt.min_confidence must be between 0 and 1")
+	}
+	if c.Conversation.Reprompt.MinChars < 0 || c.Conversation.Reprompt.MaxConsecutive < 0 {
+		return errors.New("conversation.reprompt.min_chars and max_consecutive must be non-negative")
+	}
+	if c.Conversation.EchoGuard.Enable {
+		if c.Conversation.EchoGuard.WindowMs <= 0 {
+			return errors.New("conversation.echo_guard.window_ms must be positive")
+		}
+		if c.Conversation.EchoGuard.MinSimilarity <= 0 || c.Conversation.EchoGuard.MinSimilarity > 1 {
+			return errors.New("conversation.echo_guard.min_similarity must be in (0, 1]")
+		}
+		if c.Conversation.EchoGuard.MinChars < 0 {
+			return errors.New("conversation.echo_guard.min_chars must be non-negative")
+		}
+	}
+	if c.Conversation.Sleep.IdleMinutes < 0 {
+		return errors.New("conversation.sleep.idle_minutes must be non-negative")
+	}
+	if c.Conversation.Sleep.PauseASR && len(c.Conversation.Sleep.WakeWords) > 0 {
+		return errors.New("conversation.sleep.wake_words cannot be heard while pause_asr is enabled")
+	}
+	if c.Conversation.Response.MaxSentences < 0 || c.Conversation.Response.MaxChars < 0 || c.Conversation.Response.SummarizeOver < 0 {
+		return errors.New("conversation.response.max_sentences, max_chars and summarize_over must be non-negative")
+	}
+	if c.Conversation.Commands.VolumeStep < 0 || c.Conversation.Commands.VolumeStep > 1 {
+		return errors.New("conversation.commands.volume_step must be between 0 and 1")
+	}
+	for command := range c.Conversation.Commands.Phrases {
+		if !controlCommands[command] {
+			return fmt.Errorf("conversation.commands.phrases: unknown command %q", command)
+		}
+	}
+	if c.Conversation.EndOfTurnSilenceMs < 0 {
+		return errors.New("conversation.end_of_turn_silence_ms must be non-negative")
+	}
+	if c.Conversation.SegmentFlushMs < 0 {
+		return errors.New("conversation.segment_flush_ms must be non-negative")
+	}
+	if c.Conversation.FollowUpWindowMs < 0 {
+		return errors.New("conversation.follow_up_window_ms must be non-negative")
+	}
+	if c.Knowledge.ChunkSize < 0 || c.Knowledge.ChunkOverlap < 0 || c.Knowledge.TopK < 0 {
+		return errors.New("knowledge.chunk_size, chunk_overlap and top_k must be non-negative")
+	}
+	if c.Knowledge.ChunkSize > 0 && c.Knowledge.ChunkOverlap >= c.Knowledge.ChunkSize {
+		return errors.New("knowledge.chunk_overlap must be less than knowledge.chunk_size")
+	}
+	switch c.Conversation.Mode {
+	case "", ModeAssistant:
+	case ModeTranslate:
+		if strings.TrimSpace(c.Translation.Target) == "" {
+			return errors.New("translation.target is required in translate mode")
+		}
+	default:
+		return fmt.Errorf("conversation.mode must be assistant or translate, got %q", c.Conversation.Mode)
+	}
+	if err := c.ContentFilter.validate(); err != nil {
+		return err
+	}
+	if c.Web.Enable && strings.TrimSpace(c.Web.Addr) == "" {
+		return errors.New("web.addr is required when web is enabled")
+	}
+	if c.Web.Audio && !c.Web.Enable {
+		return errors.New("web.audio requires web.enable")
+	}
+	if c.Web.HistorySize < 0 {
+		return errors.New("web.history_size must be non-negative")
+	}
+	for _, server := range c.Web.ICEServers {
+		switch scheme, _, _ := strings.Cut(server, ":"); scheme {
+		case "stun", "stuns", "turn", "turns":
+		default:
+			return fmt.Errorf("web.ice_servers entries must be stun: / stuns: / turn: / turns: URLs, got %q", server)
+		}
+	}
+	if c.Telephony.Enable {
+		switch c.Telephony.Protocol {
+		case "audiosocket", "rtp":
+		default:
+			return fmt.Errorf("telephony.protocol must be audiosocket or rtp, got %q", c.Telephony.Protocol)
+		}
+		if strings.TrimSpace(c.Telephony.Addr) == "" {
+			return errors.New("telephony.addr is required when telephony is enabled")
+		}
+		if c.Web.Enable && c.Web.Audio {
+			return errors.New("telephony cannot be combined with web.audio")
+		}
+	}
+	if c.Telephony.IdleTimeoutMs < 0 {
+		return errors.New("telephony.idle_timeout_ms must be non-negative")
+	}
+	if c.MQTT.Enable {
+		if strings.TrimSpace(c.MQTT.Broker) == "" {
+			return errors.New("mqtt.broker is required when mqtt is enabled")
+		}
+		if strings.TrimSpace(c.MQTT.ClientID) == "" {
+			return errors.New("mqtt.client_id is required when mqtt is enabled")
+		}
+		for _, topic := range append(append([]string(nil), c.MQTT.SayTopics...), c.MQTT.AskTopics...) {
+			if strings.TrimSpace(topic) == "" {
+				return errors.New("mqtt.say_topics and ask_topics must not contain empty topics")
+			}
+		}
+	}
+	if c.MQTT.KeepAliveSec < 0 {
+		return errors.New("mqtt.keep_alive_sec must be non-negative")
+	}
+	if len(c.Indicator.Command) > 0 && strings.TrimSpace(c.Indicator.Command[0]) == "" {
+		return errors.New("indicator.command must start with a program")
+	}
+	if c.Realtime.Enable {
+		switch c.Realtime.Provider {
+		case "openai", "glm":
+		default:
+			return fmt.Errorf("realtime.provider must be openai or glm, got %q", c.Realtime.Provider)
+		}
+		if c.Conversation.Mode == ModeTranslate {
+			return errors.New("realtime cannot be combined with conversation.mode translate")
+		}
+	}
+	if c.Realtime.SampleRate < 0 || c.Realtime.ClipMs < 0 {
+		return errors.New("realtime.sample_rate and clip_ms must be non-negative")
+	}
+	if err := c.Calendar.validate(); err != nil {
+		return err
+	}
+	if err := c.News.validate(); err != nil {
+		return err
+	}
+	routineNames := make(map[string]bool, len(c.Routines))
+	for i, routine := range c.Routines {
+		if strings.TrimSpace(routine.Name) == "" {
+			return fmt.Errorf("routines[%d].name is required", i)
+		}
+		if routineNames[routine.Name] {
+			return fmt.Errorf("routines[%s]: duplicate name", routine.Name)
+		}
+		routineNames[routine.Name] = true
+		if strings.TrimSpace(routine.Schedule) == "" || strings.TrimSpace(routine.Text) == "" {
+			return fmt.Errorf("routines[%s]: schedule and text are required", routine.Name)
+		}
+		switch routine.Action {
+		case "", RoutineActionAsk, RoutineActionSay:
+		default:
+			return fmt.Errorf("routines[%s].action must be ask or say, got %q", routine.Name, routine.Action)
+		}
+	}
+	if c.Speaker.Threshold < 0 || c.Speaker.Threshold > 1 {
+		return errors.New("speaker.threshold must be between 0 and 1")
+	}
+	switch c.Knowledge.Embedding.Provider {
+	case "", "local", "openai":
+	default:
+		return fmt.Errorf("knowledge.embedding.provider must be local or openai, got %q", c.Knowledge.Embedding.Provider)
+	}
+	// 配置声明的工具（HTTP 与命令）名称不能重复
+	declaredTools := make(map[string]bool, len(c.Tools.HTTP)+len(c.Tools.Shell.Tools))
+	for i, tool := range c.Tools.HTTP {
+		if strings.TrimSpace(tool.Name) == "" {
+			return fmt.Errorf("tools.http[%d].name is required", i)
+		}
+		if declaredTools[tool.Name] {
+			return fmt.Errorf("tools.http: duplicate tool %q", tool.Name)
+		}
+		declaredTools[tool.Name] = true
+		switch strings.ToUpper(tool.Method) {
+		case "", "GET", "POST", "PUT", "PATCH", "DELETE":
+		default:
+			return fmt.Errorf("tools.http[%s].method must be GET, POST, PUT, PATCH or DELETE, got %q", tool.Name, tool.Method)
+		}
+		if !strings.HasPrefix(tool.URL, "http://") && !strings.HasPrefix(tool.URL, "https://") {
+			return fmt.Errorf("tools.http[%s].url must start with http:// or https://", tool.Name)
+		}
+		if tool.TimeoutMs < 0 {
+			return fmt.Errorf("tools.http[%s].timeout_ms must be non-negative", tool.Name)
+		}
+		if err := validateToolParameters("tools.http["+tool.Name+"]", tool.Parameters); err != nil {
+			return err
+		}
+	}
+	if shell := c.Tools.Shell; shell.Enable {
+		if len(shell.Tools) > 0 && !c.Tools.Audit.Enable {
+			return errors.New("tools.shell requires tools.audit.enable")
+		}
+		for i, tool := range shell.Tools {
+			if strings.TrimSpace(tool.Name) == "" {
+				return fmt.Errorf("tools.shell.tools[%d].name is required", i)
+			}
+			if declaredTools[tool.Name] {
+				return fmt.Errorf("tools.shell: duplicate tool %q", tool.Name)
+			}
+			declaredTools[tool.Name] = true
+			if len(tool.Command) == 0 || strings.TrimSpace(tool.Command[0]) == "" {
+				return fmt.Errorf("tools.shell.tools[%s].command is required", tool.Name)
+			}
+			if tool.TimeoutMs < 0 || tool.MaxOutputBytes < 0 {
+				return fmt.Errorf("tools.shell.tools[%s].timeout_ms and max_output_bytes must be non-negative", tool.Name)
+			}
+			if err := validateToolParameters("tools.shell.tools["+tool.Name+"]", tool.Parameters); err != nil {
+				return err
+			}
+		}
+	}
+	if retrieval := c.Tools.Retrieval; retrieval.Enable {
+		if retrieval.TopK < 0 {
+			return errors.New("tools.retrieval.top_k must be non-negative")
+		}
+		if retrieval.MinScore < 0 || retrieval.MinScore > 1 {
+			return errors.New("tools.retrieval.min_score must be between 0 and 1")
+		}
+		switch retrieval.Embedding.Provider {
+		case "", "local", "openai":
+		default:
+			return fmt.Errorf("tools.retrieval.embedding.provider must be local or openai, got %q", retrieval.Embedding.Provider)
+		}
+		if c.LLM.IntentRouter.Enable {
+			return errors.New("tools.retrieval and llm.intent_router cannot both be enabled")
+		}
+	}
 
 	return nil
 }
 
+func (c ContentFilterConfig) validate() error {
+	actions := []struct{ name, value string }{
+		{"input_action", c.InputAction},
+		{"output_action", c.OutputAction},
+	}
+	for _, action := range actions {
+		switch action.value {
+		case "", FilterActionNone, FilterActionMask, FilterActionReject:
+		default:
+			return fmt.Errorf("content_filter.%s must be none, mask or reject, got %q", action.name, action.value)
+		}
+	}
+	for _, pattern := range c.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("content_filter.patterns: invalid pattern %q: %w", pattern, err)
+		}
+	}
+	if c.Enable && len(c.Words) == 0 && c.WordsFile == "" && len(c.Patterns) == 0 {
+		return errors.New("content_filter requires words, words_file or patterns when enabled")
+	}
+	return nil
+}
+
+func (c NewsConfig) validate() error {
+	if c.Limit < 0 || c.SummaryChars < 0 || c.CacheMinutes < 0 {
+		return errors.New("news.limit, summary_chars and cache_minutes must be non-negative")
+	}
+	if !c.Enable {
+		return nil
+	}
+	if len(c.Feeds) == 0 {
+		return errors.New("news.feeds must not be empty when news is enabled")
+	}
+	names := make(map[string]bool, len(c.Feeds))
+	for i, feed := range c.Feeds {
+		if strings.TrimSpace(feed.Name) == "" {
+			return fmt.Errorf("news.feeds[%d].name is required", i)
+		}
+		if names[feed.Name] {
+			return fmt.Errorf("news.feeds[%s]: duplicate name", feed.Name)
+		}
+		names[feed.Name] = true
+		if !strings.HasPrefix(feed.URL, "http://") && !strings.HasPrefix(feed.URL, "https://") {
+			return fmt.Errorf("news.feeds[%s].url must start with http:// or https://", feed.Name)
+		}
+	}
+	return nil
+}
+
+func (c CalendarConfig) validate() error {
+	if c.DefaultDurationMinutes < 0 {
+		return errors.New("calendar.default_duration_minutes must be non-negative")
+	}
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("calendar.timezone: %w", err)
+		}
+	}
+	if !c.Enable {
+		return nil
+	}
+	switch c.Provider {
+	case "caldav":
+		if !strings.HasPrefix(c.CalDAV.URL, "http://") && !strings.HasPrefix(c.CalDAV.URL, "https://") {
+			return fmt.Errorf("calendar.caldav.url must start with http:// or https://, got %q", c.CalDAV.URL)
+		}
+	case "google":
+		if c.Google.ClientID == "" || c.Google.ClientSecret == "" || c.Google.RefreshToken == "" {
+			return errors.New("calendar.google requires client_id, client_secret and refresh_token")
+		}
+	default:
+		return fmt.Errorf("calendar.provider must be caldav or google, got %q", c.Provider)
+	}
+	return nil
+}
+
+func isVocabularyPrefix(prefix string) bool {
+	if len(prefix) > 10 {
+		return false
+	}
+	for _, r := range prefix {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func isLogLevel(level string) bool {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug", "info", "warn", "error":
+		return true
+	default:
+		return false
+	}
+}
+
+// validLLMProvider 空值表示默认（主 LLM 为 openai，备用 LLM 沿用主 LLM）
+func validLLMProvider(provider string) bool {
+	switch provider {
+	case "", "openai", "gemini":
+		return true
+	default:
+		return false
+	}
+}
+
 func (c *AppConfig) ValidateKeys(requireASR, requireTTS, requireLLM bool) error {
 	if requireASR && strings.TrimSpace(c.ASR.APIKey) == "" {
 		return errors.New("asr api_key is required")
@@ -257,3 +1678,15 @@ func (c *AppConfig) ValidateKeys(requireASR, requireTTS, requireLLM bool) error
 	}
 	return nil
 }
+
+// validateToolParameters 检查配置声明的工具参数类型，空值按 string
+func validateToolParameters(prefix string, params map[string]ToolParameterConfig) error {
+	for name, param := range params {
+		switch param.Type {
+		case "", "string", "integer", "number", "boolean":
+		default:
+			return fmt.Errorf("%s.parameters.%s.type must be string, integer, number or boolean, got %q", prefix, name, param.Type)
+		}
+	}
+	return nil
+}
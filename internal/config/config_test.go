@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -68,3 +69,1084 @@ func TestValidateToolTypes(t *testing.T) {
 		t.Fatalf("expected invalid tool type error")
 	}
 }
+
+func TestValidateHighPassCutoff(t *testing.T) {
+	cfg := DefaultConfig()
+	if !cfg.Audio.InPipe.DSP.HighPass.Enable || cfg.Audio.InPipe.DSP.HighPass.CutoffHz != 80 {
+		t.Fatalf("expected high-pass enabled at 80Hz by default")
+	}
+	cfg.Audio.InPipe.DSP.HighPass.CutoffHz = 9000
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected error for cutoff above Nyquist")
+	}
+}
+
+func TestValidateEchoGate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*AppConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *AppConfig) {}, false},
+		{"mute without double talk", func(c *AppConfig) {
+			c.Audio.InPipe.AEC.GateAttenuationDb, c.Audio.InPipe.AEC.GateDoubleTalkRatio = 0, 0
+		}, false},
+		{"negative attenuation", func(c *AppConfig) { c.Audio.InPipe.AEC.GateAttenuationDb = -10 }, true},
+		{"negative hangover", func(c *AppConfig) { c.Audio.InPipe.AEC.GateHangoverMs = -1 }, true},
+		{"negative ratio", func(c *AppConfig) { c.Audio.InPipe.AEC.GateDoubleTalkRatio = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateKnowledge(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*KnowledgeConfig)
+		wantErr bool
+	}{
+		{"defaults", func(k *KnowledgeConfig) {}, false},
+		{"negative top_k", func(k *KnowledgeConfig) { k.TopK = -1 }, true},
+		{"overlap not less than chunk size", func(k *KnowledgeConfig) { k.ChunkOverlap = k.ChunkSize }, true},
+		{"openai provider", func(k *KnowledgeConfig) { k.Embedding.Provider = "openai" }, false},
+		{"unknown provider", func(k *KnowledgeConfig) { k.Embedding.Provider = "bert" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(&cfg.Knowledge)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLogging(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*LoggingConfig)
+		wantErr bool
+	}{
+		{"defaults", func(l *LoggingConfig) {}, false},
+		{"component levels", func(l *LoggingConfig) { l.Levels = map[string]string{"audio": "debug", "agent": "WARN"} }, false},
+		{"invalid component level", func(l *LoggingConfig) { l.Levels = map[string]string{"audio": "verbose"} }, true},
+		{"file rotation", func(l *LoggingConfig) {
+			l.File = LogFileConfig{Path: "logs/voicebot.log", MaxSizeMB: 50, MaxBackups: 5}
+		}, false},
+		{"invalid file level", func(l *LoggingConfig) { l.File.Level = "trace" }, true},
+		{"negative max size", func(l *LoggingConfig) { l.File.MaxSizeMB = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(&cfg.Logging)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateAudioLevels(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*LevelsConfig)
+		wantErr bool
+	}{
+		{"defaults", func(l *LevelsConfig) {}, false},
+		{"silence detection off", func(l *LevelsConfig) { l.SilenceWarnMs = 0 }, false},
+		{"threshold out of range", func(l *LevelsConfig) { l.SilenceThreshold = 1 }, true},
+		{"negative clip interval", func(l *LevelsConfig) { l.ClipWarnMs = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(&cfg.Audio.Levels)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateInputSampleFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantErr bool
+	}{
+		{"", false},
+		{"s16", false},
+		{"F32LE", false},
+		{"s24", false},
+		{"u8", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Audio.InPipe.SampleFormat = tt.format
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDebugRecord(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*DebugRecordConfig)
+		wantErr bool
+	}{
+		{"disabled", func(d *DebugRecordConfig) { d.Dir = "" }, false},
+		{"enabled", func(d *DebugRecordConfig) { d.Enable = true }, false},
+		{"enabled without dir", func(d *DebugRecordConfig) { d.Enable, d.Dir = true, "" }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(&cfg.Audio.DebugRecord)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMixerVolume(t *testing.T) {
+	tests := []struct {
+		volume  float64
+		wantErr bool
+	}{
+		{1, false},
+		{0, false},
+		{0.5, false},
+		{1.2, true},
+		{-0.1, true},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig()
+		cfg.Audio.Mixer.Volume = tt.volume
+		if err := cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Fatalf("volume %v: Validate() error = %v, wantErr %v", tt.volume, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateReprompt(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*RepromptConfig)
+		wantErr bool
+	}{
+		{"defaults", func(r *RepromptConfig) {}, false},
+		{"disabled checks", func(r *RepromptConfig) { r.MinConfidence, r.MinChars = 0, 0 }, false},
+		{"confidence out of range", func(r *RepromptConfig) { r.MinConfidence = 1.5 }, true},
+		{"negative min chars", func(r *RepromptConfig) { r.MinChars = -1 }, true},
+		{"negative max consecutive", func(r *RepromptConfig) { r.MaxConsecutive = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(&cfg.Conversation.Reprompt)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateEchoGuard(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*EchoGuardConfig)
+		wantErr bool
+	}{
+		{"defaults", func(e *EchoGuardConfig) {}, false},
+		{"disabled ignores values", func(e *EchoGuardConfig) { e.Enable, e.WindowMs = false, 0 }, false},
+		{"zero window", func(e *EchoGuardConfig) { e.WindowMs = 0 }, true},
+		{"similarity out of range", func(e *EchoGuardConfig) { e.MinSimilarity = 1.2 }, true},
+		{"zero similarity", func(e *EchoGuardConfig) { e.MinSimilarity = 0 }, true},
+		{"negative min chars", func(e *EchoGuardConfig) { e.MinChars = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(&cfg.Conversation.EchoGuard)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSleep(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*AppConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *AppConfig) {}, false},
+		{"wake words", func(c *AppConfig) {
+			c.Conversation.Sleep.IdleMinutes = 10
+			c.Conversation.Sleep.WakeWords = []string{"小欧"}
+		}, false},
+		{"pause asr", func(c *AppConfig) {
+			c.Conversation.Sleep.IdleMinutes = 10
+			c.Conversation.Sleep.PauseASR = true
+		}, false},
+		{"negative idle minutes", func(c *AppConfig) { c.Conversation.Sleep.IdleMinutes = -1 }, true},
+		{"wake words with paused asr", func(c *AppConfig) {
+			c.Conversation.Sleep.PauseASR = true
+			c.Conversation.Sleep.WakeWords = []string{"小欧"}
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	cfg.Conversation.Response.MaxChars = -1
+	if err := cfg.Validate(); err == nil {
+		t.Fatalf("expected error for negative conversation.response.max_chars")
+	}
+}
+
+func TestValidateCommands(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*CommandsConfig)
+		wantErr bool
+	}{
+		{"default", func(c *CommandsConfig) {}, false},
+		{"phrases", func(c *CommandsConfig) { c.Phrases = map[string][]string{"stop": {"打住"}} }, false},
+		{"unknown command", func(c *CommandsConfig) { c.Phrases = map[string][]string{"pause": {"暂停"}} }, true},
+		{"volume step too large", func(c *CommandsConfig) { c.VolumeStep = 1.5 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(&cfg.Conversation.Commands)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateContentFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*ContentFilterConfig)
+		wantErr bool
+	}{
+		{"disabled", func(c *ContentFilterConfig) {}, false},
+		{"words", func(c *ContentFilterConfig) { c.Enable, c.Words = true, []string{"笨蛋"} }, false},
+		{"enabled without rules", func(c *ContentFilterConfig) { c.Enable = true }, true},
+		{"unknown action", func(c *ContentFilterConfig) { c.OutputAction = "block" }, true},
+		{"invalid pattern", func(c *ContentFilterConfig) { c.Patterns = []string{"("} }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(&cfg.ContentFilter)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTTSBuffer(t *testing.T) {
+	tests := []struct {
+		name    string
+		bytes   int
+		policy  string
+		wantErr bool
+	}{
+		{"defaults", 0, "", false},
+		{"drop oldest", 65536, "drop_oldest", false},
+		{"negative size", -1, "block", true},
+		{"unknown policy", 0, "spill", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.TTS.BufferBytes = tt.bytes
+			cfg.TTS.BufferPolicy = tt.policy
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadVoiceMapMixedForms(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "voicebot.json")
+	data := `{"tts": {"voice_map": {
+		"sad": "voice_sad",
+		"excited": {"voice": "voice_excited", "rate": 1.2, "pitch": 1.1, "volume": 70}
+	}}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got := cfg.TTS.VoiceMap["sad"]; got != (VoiceConfig{Voice: "voice_sad"}) {
+		t.Fatalf("sad = %+v", got)
+	}
+	want := VoiceConfig{Voice: "voice_excited", Rate: 1.2, Pitch: 1.1, Volume: 70}
+	if got := cfg.TTS.VoiceMap["excited"]; got != want {
+		t.Fatalf("excited = %+v, want %+v", got, want)
+	}
+	if cfg.TTS.VoiceMap["default"].Voice != "longanyang" {
+		t.Fatalf("expected default voice to be preserved")
+	}
+}
+
+func TestValidateVoiceMapProsody(t *testing.T) {
+	tests := []struct {
+		name    string
+		voice   VoiceConfig
+		wantErr bool
+	}{
+		{"voice only", VoiceConfig{Voice: "longanyang"}, false},
+		{"prosody", VoiceConfig{Rate: 1.2, Pitch: 0.8, Volume: 80}, false},
+		{"rate too fast", VoiceConfig{Rate: 3}, true},
+		{"pitch too low", VoiceConfig{Pitch: 0.2}, true},
+		{"volume too loud", VoiceConfig{Volume: 120}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.TTS.VoiceMap["excited"] = tt.voice
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateSpeakerThreshold(t *testing.T) {
+	for _, threshold := range []float64{-0.1, 1.5} {
+		cfg := DefaultConfig()
+		cfg.Speaker.Threshold = threshold
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("expected error for speaker.threshold=%v", threshold)
+		}
+	}
+}
+
+func TestValidateWeb(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*WebConfig)
+		wantErr bool
+	}{
+		{"defaults", func(w *WebConfig) {}, false},
+		{"enabled", func(w *WebConfig) { w.Enable = true }, false},
+		{"enabled without addr", func(w *WebConfig) { w.Enable, w.Addr = true, " " }, true},
+		{"disabled without addr", func(w *WebConfig) { w.Addr = "" }, false},
+		{"negative history", func(w *WebConfig) { w.HistorySize = -1 }, true},
+		{"browser audio", func(w *WebConfig) { w.Enable, w.Audio = true, true }, false},
+		{"browser audio without web", func(w *WebConfig) { w.Audio = true }, true},
+		{"ice servers", func(w *WebConfig) {
+			w.ICEServers = []string{"stun:stun.l.google.com:19302", "turn:turn.example.com:3478"}
+		}, false},
+		{"invalid ice server", func(w *WebConfig) { w.ICEServers = []string{"stun.l.google.com:19302"} }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(&cfg.Web)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTelephony(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*AppConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *AppConfig) {}, false},
+		{"audiosocket", func(c *AppConfig) { c.Telephony.Enable = true }, false},
+		{"rtp", func(c *AppConfig) { c.Telephony.Enable, c.Telephony.Protocol = true, "rtp" }, false},
+		{"unknown protocol", func(c *AppConfig) { c.Telephony.Enable, c.Telephony.Protocol = true, "sip" }, true},
+		{"missing addr", func(c *AppConfig) { c.Telephony.Enable, c.Telephony.Addr = true, "" }, true},
+		{"negative idle timeout", func(c *AppConfig) { c.Telephony.IdleTimeoutMs = -1 }, true},
+		{"with browser audio", func(c *AppConfig) {
+			c.Telephony.Enable = true
+			c.Web.Enable, c.Web.Audio = true, true
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateMQTT(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*MQTTConfig)
+		wantErr bool
+	}{
+		{"defaults", func(m *MQTTConfig) {}, false},
+		{"enabled", func(m *MQTTConfig) { m.Enable, m.SayTopics = true, []string{"home/doorbell"} }, false},
+		{"missing broker", func(m *MQTTConfig) { m.Enable, m.Broker = true, "" }, true},
+		{"missing client id", func(m *MQTTConfig) { m.Enable, m.ClientID = true, " " }, true},
+		{"empty topic", func(m *MQTTConfig) { m.Enable, m.AskTopics = true, []string{""} }, true},
+		{"negative keep alive", func(m *MQTTConfig) { m.KeepAliveSec = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(&cfg.MQTT)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateIndicator(t *testing.T) {
+	tests := []struct {
+		name    string
+		command []string
+		wantErr bool
+	}{
+		{"disabled", nil, false},
+		{"command", []string{"python3", "led_ring.py"}, false},
+		{"empty program", []string{" ", "led_ring.py"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Indicator.Command = tt.command
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateRealtime(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*AppConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *AppConfig) {}, false},
+		{"enabled", func(c *AppConfig) { c.Realtime.Enable = true }, false},
+		{"glm", func(c *AppConfig) { c.Realtime.Enable, c.Realtime.Provider = true, "glm" }, false},
+		{"unknown provider", func(c *AppConfig) { c.Realtime.Enable, c.Realtime.Provider = true, "gemini" }, true},
+		{"translate mode", func(c *AppConfig) {
+			c.Realtime.Enable = true
+			c.Conversation.Mode, c.Translation.Target = ModeTranslate, "en"
+		}, true},
+		{"negative clip", func(c *AppConfig) { c.Realtime.ClipMs = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateToolAudit(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*AppConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *AppConfig) {}, false},
+		{"enabled", func(c *AppConfig) { c.Tools.Audit.Enable = true }, false},
+		{"enabled without path", func(c *AppConfig) { c.Tools.Audit.Enable, c.Tools.Audit.Path = true, " " }, true},
+		{"negative result chars", func(c *AppConfig) { c.Tools.Audit.ResultMaxChars = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateAudioDevices(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*AppConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *AppConfig) {}, false},
+		{"bluetooth input", func(c *AppConfig) {
+			c.Audio.InPipe.InputDevice = "AirPods"
+			c.Audio.InPipe.HighLatency = true
+			c.Audio.InPipe.BufferSize = 9600
+		}, false},
+		{"negative buffer size", func(c *AppConfig) { c.Audio.InPipe.BufferSize = -1 }, true},
+		{"mixer 48k mono", func(c *AppConfig) {
+			c.Audio.Mixer.SampleRate = 48000
+			c.Audio.Mixer.Channels = 1
+			c.Audio.Mixer.OutputDevice = "USB"
+		}, false},
+		{"mixer default rate", func(c *AppConfig) { c.Audio.Mixer.SampleRate = 0 }, false},
+		{"mixer rate too low", func(c *AppConfig) { c.Audio.Mixer.SampleRate = 4000 }, true},
+		{"mixer too many channels", func(c *AppConfig) { c.Audio.Mixer.Channels = 6 }, true},
+		{"negative mixer channels", func(c *AppConfig) { c.Audio.Mixer.Channels = -1 }, true},
+		{"dual mic", func(c *AppConfig) {
+			c.Audio.InPipe.InputDevices = []string{"USB Mic A", "USB Mic B"}
+			c.Audio.InPipe.MicArray.Strategy = "delay_sum"
+			c.Audio.InPipe.MicArray.MaxDelayMs = 5
+		}, false},
+		{"unknown mic array strategy", func(c *AppConfig) { c.Audio.InPipe.MicArray.Strategy = "mvdr" }, true},
+		{"mic array delay too large", func(c *AppConfig) { c.Audio.InPipe.MicArray.MaxDelayMs = 100 }, true},
+		{"negative mic array hangover", func(c *AppConfig) { c.Audio.InPipe.MicArray.HangoverMs = -1 }, true},
+		{"alsa backend", func(c *AppConfig) {
+			c.Audio.Backend = "alsa"
+			c.Audio.InPipe.InputDevice = "plughw:1,0"
+		}, false},
+		{"pulse sink", func(c *AppConfig) {
+			c.Audio.Backend = "pulse"
+			c.Audio.Mixer.OutputDevice = "bluez_output.AC_80_0A_11_22_33.1"
+		}, false},
+		{"asr frames", func(c *AppConfig) {
+			c.Audio.InPipe.ASRFrameMs = 100
+			c.Audio.InPipe.ASRMaxSendRate = 1.5
+		}, false},
+		{"negative asr frame", func(c *AppConfig) { c.Audio.InPipe.ASRFrameMs = -1 }, true},
+		{"asr frame too long", func(c *AppConfig) { c.Audio.InPipe.ASRFrameMs = 2000 }, true},
+		{"asr send rate below realtime", func(c *AppConfig) { c.Audio.InPipe.ASRMaxSendRate = 0.5 }, true},
+		{"empty backend", func(c *AppConfig) { c.Audio.Backend = "" }, false},
+		{"unknown backend", func(c *AppConfig) { c.Audio.Backend = "coreaudio" }, true},
+		{"alsa full duplex", func(c *AppConfig) {
+			c.Audio.Backend = "alsa"
+			c.Audio.FullDuplex = true
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateToolConfirmation(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*AppConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *AppConfig) {}, false},
+		{"tools", func(c *AppConfig) { c.Tools.Confirmation.Tools = map[string]string{"sendEmail": ""} }, false},
+		{"no timeout", func(c *AppConfig) { c.Tools.Confirmation.TimeoutMs = 0 }, false},
+		{"negative timeout", func(c *AppConfig) { c.Tools.Confirmation.TimeoutMs = -1 }, true},
+		{"progress disabled", func(c *AppConfig) { c.Tools.Progress.DelayMs = 0 }, false},
+		{"negative progress interval", func(c *AppConfig) { c.Tools.Progress.IntervalMs = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateHTTPTools(t *testing.T) {
+	valid := HTTPToolConfig{Name: "getQuote", URL: "https://api.example.com/quote?symbol={{symbol}}"}
+	tests := []struct {
+		name    string
+		tools   func() []HTTPToolConfig
+		wantErr bool
+	}{
+		{"valid", func() []HTTPToolConfig { return []HTTPToolConfig{valid} }, false},
+		{"missing name", func() []HTTPToolConfig {
+			tool := valid
+			tool.Name = ""
+			return []HTTPToolConfig{tool}
+		}, true},
+		{"duplicate name", func() []HTTPToolConfig { return []HTTPToolConfig{valid, valid} }, true},
+		{"unknown method", func() []HTTPToolConfig {
+			tool := valid
+			tool.Method = "TRACE"
+			return []HTTPToolConfig{tool}
+		}, true},
+		{"lowercase method", func() []HTTPToolConfig {
+			tool := valid
+			tool.Method = "post"
+			return []HTTPToolConfig{tool}
+		}, false},
+		{"relative url", func() []HTTPToolConfig {
+			tool := valid
+			tool.URL = "/quote"
+			return []HTTPToolConfig{tool}
+		}, true},
+		{"negative timeout", func() []HTTPToolConfig {
+			tool := valid
+			tool.TimeoutMs = -1
+			return []HTTPToolConfig{tool}
+		}, true},
+		{"unknown parameter type", func() []HTTPToolConfig {
+			tool := valid
+			tool.Parameters = map[string]ToolParameterConfig{"symbol": {Type: "array"}}
+			return []HTTPToolConfig{tool}
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Tools.HTTP = tt.tools()
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateShellTools(t *testing.T) {
+	tool := ShellToolConfig{Name: "restartRouter", Command: []string{"/usr/local/bin/restart-router"}}
+	tests := []struct {
+		name    string
+		mutate  func(*AppConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *AppConfig) {}, false},
+		{"enabled with audit", func(c *AppConfig) {
+			c.Tools.Audit.Enable = true
+			c.Tools.Shell = ShellToolsConfig{Enable: true, Tools: []ShellToolConfig{tool}}
+		}, false},
+		{"requires audit", func(c *AppConfig) {
+			c.Tools.Shell = ShellToolsConfig{Enable: true, Tools: []ShellToolConfig{tool}}
+		}, true},
+		{"disabled not checked", func(c *AppConfig) {
+			c.Tools.Shell = ShellToolsConfig{Tools: []ShellToolConfig{{Name: "broken"}}}
+		}, false},
+		{"missing command", func(c *AppConfig) {
+			c.Tools.Audit.Enable = true
+			c.Tools.Shell = ShellToolsConfig{Enable: true, Tools: []ShellToolConfig{{Name: "broken"}}}
+		}, true},
+		{"duplicate of http tool", func(c *AppConfig) {
+			c.Tools.Audit.Enable = true
+			c.Tools.HTTP = []HTTPToolConfig{{Name: tool.Name, URL: "https://example.com"}}
+			c.Tools.Shell = ShellToolsConfig{Enable: true, Tools: []ShellToolConfig{tool}}
+		}, true},
+		{"negative max output", func(c *AppConfig) {
+			c.Tools.Audit.Enable = true
+			broken := tool
+			broken.MaxOutputBytes = -1
+			c.Tools.Shell = ShellToolsConfig{Enable: true, Tools: []ShellToolConfig{broken}}
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateCalendar(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*AppConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *AppConfig) {}, false},
+		{"caldav", func(c *AppConfig) {
+			c.Calendar.Enable = true
+			c.Calendar.CalDAV.URL = "https://dav.example.com/calendars/alice/personal/"
+		}, false},
+		{"caldav without url", func(c *AppConfig) { c.Calendar.Enable = true }, true},
+		{"google", func(c *AppConfig) {
+			c.Calendar.Enable = true
+			c.Calendar.Provider = "google"
+			c.Calendar.Google.ClientID = "id"
+			c.Calendar.Google.ClientSecret = "secret"
+			c.Calendar.Google.RefreshToken = "token"
+		}, false},
+		{"google without refresh token", func(c *AppConfig) {
+			c.Calendar.Enable = true
+			c.Calendar.Provider = "google"
+			c.Calendar.Google.ClientID = "id"
+			c.Calendar.Google.ClientSecret = "secret"
+		}, true},
+		{"unknown provider", func(c *AppConfig) {
+			c.Calendar.Enable = true
+			c.Calendar.Provider = "outlook"
+		}, true},
+		{"timezone", func(c *AppConfig) { c.Calendar.Timezone = "Asia/Shanghai" }, false},
+		{"unknown timezone", func(c *AppConfig) { c.Calendar.Timezone = "Mars/Olympus" }, true},
+		{"negative duration", func(c *AppConfig) { c.Calendar.DefaultDurationMinutes = -1 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateNewsAndRoutines(t *testing.T) {
+	feed := NewsFeedConfig{Name: "科技", URL: "https://example.com/feed"}
+	tests := []struct {
+		name    string
+		mutate  func(*AppConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *AppConfig) {}, false},
+		{"news", func(c *AppConfig) {
+			c.News.Enable = true
+			c.News.Feeds = []NewsFeedConfig{feed}
+		}, false},
+		{"news without feeds", func(c *AppConfig) { c.News.Enable = true }, true},
+		{"duplicate feed", func(c *AppConfig) {
+			c.News.Enable = true
+			c.News.Feeds = []NewsFeedConfig{feed, feed}
+		}, true},
+		{"feed url", func(c *AppConfig) {
+			c.News.Enable = true
+			c.News.Feeds = []NewsFeedConfig{{Name: "科技", URL: "example.com/feed"}}
+		}, true},
+		{"negative limit", func(c *AppConfig) { c.News.Limit = -1 }, true},
+		{"routine", func(c *AppConfig) {
+			c.Routines = []RoutineConfig{{Name: "briefing", Schedule: "30 7 * * 1-5", Text: "播报早间简报"}}
+		}, false},
+		{"say routine", func(c *AppConfig) {
+			c.Routines = []RoutineConfig{{Name: "water", Schedule: "0 * * * *", Text: "该喝水了", Action: RoutineActionSay}}
+		}, false},
+		{"routine without text", func(c *AppConfig) {
+			c.Routines = []RoutineConfig{{Name: "briefing", Schedule: "30 7 * * *"}}
+		}, true},
+		{"routine action", func(c *AppConfig) {
+			c.Routines = []RoutineConfig{{Name: "briefing", Schedule: "30 7 * * *", Text: "x", Action: "shout"}}
+		}, true},
+		{"duplicate routine", func(c *AppConfig) {
+			routine := RoutineConfig{Name: "briefing", Schedule: "30 7 * * *", Text: "x"}
+			c.Routines = []RoutineConfig{routine, routine}
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateToolRetrieval(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*AppConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *AppConfig) {}, false},
+		{"enabled", func(c *AppConfig) { c.Tools.Retrieval.Enable = true }, false},
+		{"openai embedding", func(c *AppConfig) {
+			c.Tools.Retrieval.Enable = true
+			c.Tools.Retrieval.Embedding.Provider = "openai"
+		}, false},
+		{"unknown embedding", func(c *AppConfig) {
+			c.Tools.Retrieval.Enable = true
+			c.Tools.Retrieval.Embedding.Provider = "bert"
+		}, true},
+		{"negative top k", func(c *AppConfig) {
+			c.Tools.Retrieval.Enable = true
+			c.Tools.Retrieval.TopK = -1
+		}, true},
+		{"min score above 1", func(c *AppConfig) {
+			c.Tools.Retrieval.Enable = true
+			c.Tools.Retrieval.MinScore = 1.5
+		}, true},
+		{"with intent router", func(c *AppConfig) {
+			c.Tools.Retrieval.Enable = true
+			c.LLM.IntentRouter.Enable = true
+		}, true},
+		{"disabled not checked", func(c *AppConfig) { c.Tools.Retrieval.TopK = -1 }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateLLMProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*AppConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *AppConfig) {}, false},
+		{"gemini", func(c *AppConfig) { c.LLM.Provider = "gemini" }, false},
+		{"empty", func(c *AppConfig) { c.LLM.Provider = "" }, false},
+		{"unknown provider", func(c *AppConfig) { c.LLM.Provider = "claude" }, true},
+		{"gemini fallback", func(c *AppConfig) { c.LLM.Fallbacks = []LLMEndpoint{{Provider: "gemini"}} }, false},
+		{"unknown fallback provider", func(c *AppConfig) { c.LLM.Fallbacks = []LLMEndpoint{{Provider: "qwen"}} }, true},
+		{"intent router", func(c *AppConfig) { c.LLM.IntentRouter = IntentRouterConfig{Enable: true, Model: "glm-4-flash"} }, false},
+		{"unknown router provider", func(c *AppConfig) { c.LLM.IntentRouter = IntentRouterConfig{Enable: true, Provider: "qwen"} }, true},
+		{"negative router max tools", func(c *AppConfig) { c.LLM.IntentRouter = IntentRouterConfig{Enable: true, MaxTools: -1} }, true},
+		{"disabled router not checked", func(c *AppConfig) { c.LLM.IntentRouter = IntentRouterConfig{Provider: "qwen"} }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			tt.mutate(cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyEnvGeminiKey(t *testing.T) {
+	t.Setenv("ZHIPU_API_KEY", "zhipu-key")
+	t.Setenv("GEMINI_API_KEY", "gemini-key")
+
+	cfg := DefaultConfig()
+	cfg.ApplyEnv()
+	if cfg.LLM.APIKey != "zhipu-key" {
+		t.Fatalf("GEMINI_API_KEY should only apply to gemini provider, got %q", cfg.LLM.APIKey)
+	}
+	cfg = DefaultConfig()
+	cfg.LLM.Provider = "gemini"
+	cfg.ApplyEnv()
+	if cfg.LLM.APIKey != "gemini-key" {
+		t.Fatalf("expected LLM api key from GEMINI_API_KEY, got %q", cfg.LLM.APIKey)
+	}
+}
+
+func TestValidateConversationMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		target  string
+		wantErr bool
+	}{
+		{"assistant", ModeAssistant, "", false},
+		{"translate with target", ModeTranslate, "en", false},
+		{"translate without target", ModeTranslate, "", true},
+		{"unknown mode", "karaoke", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.Conversation.Mode = tt.mode
+			cfg.Translation.Target = tt.target
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUpdateFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "voicebot.json")
+	data := `{"logging": {"level": "debug"}, "audio": {"in_pipe": {"sample_rate": 16000}}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	err := UpdateFile(path, map[string]interface{}{
+		"audio.in_pipe.vad_threshold":      0.02,
+		"audio.in_pipe.dsp.agc.target_rms": 0.12,
+	})
+	if err != nil {
+		t.Fatalf("UpdateFile() error = %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Logging.Level != "debug" || cfg.Audio.InPipe.SampleRate != 16000 {
+		t.Errorf("existing values not preserved: level=%q sample_rate=%d", cfg.Logging.Level, cfg.Audio.InPipe.SampleRate)
+	}
+	if cfg.Audio.InPipe.VADThreshold != 0.02 || cfg.Audio.InPipe.DSP.AGC.TargetRMS != 0.12 {
+		t.Errorf("updated values = %v / %v", cfg.Audio.InPipe.VADThreshold, cfg.Audio.InPipe.DSP.AGC.TargetRMS)
+	}
+
+	if err := UpdateFile(path, map[string]interface{}{"audio.in_pipe.dsp.agc.target_rms": 2.0}); err == nil {
+		t.Error("expected validation error for target_rms > 1")
+	}
+	if err := UpdateFile(path, map[string]interface{}{"logging.level.nested": "x"}); err == nil {
+		t.Error("expected error when a path segment is not an object")
+	}
+}
+
+func TestUpdateFileCreatesMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "voicebot.json")
+	if err := UpdateFile(path, map[string]interface{}{"audio.in_pipe.vad_threshold": 0.03}); err != nil {
+		t.Fatalf("UpdateFile() error = %v", err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Audio.InPipe.VADThreshold != 0.03 {
+		t.Errorf("vad_threshold = %v, want 0.03", cfg.Audio.InPipe.VADThreshold)
+	}
+}
+
+func TestValidateVocabulary(t *testing.T) {
+	tests := []struct {
+		name    string
+		vocab   VocabularyConfig
+		wantErr bool
+	}{
+		{"default", DefaultConfig().ASR.Vocabulary, false},
+		{"hotwords", VocabularyConfig{Prefix: "shop01", Hotwords: []HotwordConfig{{Text: "Orion-X", Weight: 5}}}, false},
+		{"bad prefix", VocabularyConfig{Prefix: "Shop_01"}, true},
+		{"empty text", VocabularyConfig{Hotwords: []HotwordConfig{{Text: " "}}}, true},
+		{"weight out of range", VocabularyConfig{Hotwords: []HotwordConfig{{Text: "a", Weight: 9}}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.ASR.Vocabulary = tt.vocab
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "voicebot.json")
+	data := `{
+		"profile": "studio",
+		"audio": {"in_pipe": {"sample_rate": 16000, "vad_threshold": 0.01}},
+		"tools": {"audit": {"redact_fields": ["password", "token"]}},
+		"profiles": {
+			"studio": {"audio": {"in_pipe": {"vad_threshold": 0.005}}},
+			"bluetooth": {"audio": {"in_pipe": {"vad_threshold": 0.03, "input_device": "bt"}}},
+			"server": {"logging": {"format": "json"}, "tools": {"audit": {"redact_fields": ["password"]}}},
+			"nested": {"profiles": {}}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		profile    string
+		env        string
+		wantActive string
+		wantVAD    float64
+		wantRedact int
+		wantErr    bool
+	}{
+		{name: "file default", wantActive: "studio", wantVAD: 0.005, wantRedact: 2},
+		{name: "env overrides file", env: "bluetooth", wantActive: "bluetooth", wantVAD: 0.03, wantRedact: 2},
+		{name: "flag overrides env", profile: "server", env: "bluetooth", wantActive: "server", wantVAD: 0.01, wantRedact: 1},
+		{name: "stacked in order", profile: "bluetooth, server", wantActive: "bluetooth,server", wantVAD: 0.03, wantRedact: 1},
+		{name: "unknown profile", profile: "car", wantErr: true},
+		{name: "nested profiles", profile: "nested", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(ProfileEnv, tt.env)
+			cfg, err := LoadProfile(path, tt.profile)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := strings.Join(cfg.ActiveProfiles, ","); got != tt.wantActive {
+				t.Errorf("active profiles = %q, want %q", got, tt.wantActive)
+			}
+			if cfg.Audio.InPipe.VADThreshold != tt.wantVAD {
+				t.Errorf("vad_threshold = %v, want %v", cfg.Audio.InPipe.VADThreshold, tt.wantVAD)
+			}
+			if cfg.Audio.InPipe.SampleRate != 16000 {
+				t.Errorf("sample_rate = %d, base value not preserved", cfg.Audio.InPipe.SampleRate)
+			}
+			if len(cfg.Tools.Audit.RedactFields) != tt.wantRedact {
+				t.Errorf("redact_fields = %v, want %d entries", cfg.Tools.Audit.RedactFields, tt.wantRedact)
+			}
+		})
+	}
+}
+
+func TestUpdateFileProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "voicebot.json")
+	data := `{"profiles": {"bluetooth": {"audio": {"in_pipe": {"input_device": "bt"}}}}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	if err := UpdateFile(path, map[string]interface{}{"profiles.bluetooth.audio.in_pipe.vad_threshold": 0.04}); err != nil {
+		t.Fatalf("UpdateFile() error = %v", err)
+	}
+	base, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if base.Audio.InPipe.VADThreshold == 0.04 {
+		t.Error("profile value leaked into the base config")
+	}
+	cfg, err := LoadProfile(path, "bluetooth")
+	if err != nil {
+		t.Fatalf("LoadProfile() error = %v", err)
+	}
+	if cfg.Audio.InPipe.VADThreshold != 0.04 || cfg.Audio.InPipe.InputDevice != "bt" {
+		t.Errorf("profile values = %v / %q", cfg.Audio.InPipe.VADThreshold, cfg.Audio.InPipe.InputDevice)
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// KeyringPrefix 密钥字段以该前缀开头时从系统钥匙串读取，格式为 keyring:<service>/<account>
+const KeyringPrefix = "keyring:"
+
+// redactedSecret Redacted 中替换密钥的文本
+const redactedSecret = "***"
+
+// keyringLookup 读取系统钥匙串，测试中替换
+var keyringLookup = lookupKeyring
+
+// secretField 一个密钥字段及对应的 *_file 字段
+type secretField struct {
+	name  string
+	value *string
+	file  *string
+}
+
+// secretFields 返回全部密钥字段（包括备用 LLM）
+func (c *AppConfig) secretFields() []secretField {
+	fields := []secretField{
+		{"asr.api_key", &c.ASR.APIKey, &c.ASR.APIKeyFile},
+		{"tts.api_key", &c.TTS.APIKey, &c.TTS.APIKeyFile},
+		{"llm.api_key", &c.LLM.APIKey, &c.LLM.APIKeyFile},
+		{"llm.intent_router.api_key", &c.LLM.IntentRouter.APIKey, &c.LLM.IntentRouter.APIKeyFile},
+		{"knowledge.embedding.api_key", &c.Knowledge.Embedding.APIKey, &c.Knowledge.Embedding.APIKeyFile},
+		{"tools.retrieval.embedding.api_key", &c.Tools.Retrieval.Embedding.APIKey, &c.Tools.Retrieval.Embedding.APIKeyFile},
+		{"mqtt.password", &c.MQTT.Password, &c.MQTT.PasswordFile},
+		{"realtime.api_key", &c.Realtime.APIKey, &c.Realtime.APIKeyFile},
+		{"calendar.caldav.password", &c.Calendar.CalDAV.Password, &c.Calendar.CalDAV.PasswordFile},
+		{"calendar.google.client_secret", &c.Calendar.Google.ClientSecret, &c.Calendar.Google.ClientSecretFile},
+		{"calendar.google.refresh_token", &c.Calendar.Google.RefreshToken, &c.Calendar.Google.RefreshTokenFile},
+	}
+	for i := range c.LLM.Fallbacks {
+		fallback := &c.LLM.Fallbacks[i]
+		fields = append(fields, secretField{fmt.Sprintf("llm.fallbacks[%d].api_key", i), &fallback.APIKey, &fallback.APIKeyFile})
+	}
+	return fields
+}
+
+// resolveSecrets 读取 *_file 指定的文件和 keyring: 引用，结果写回对应的密钥字段
+func (c *AppConfig) resolveSecrets() error {
+	for _, field := range c.secretFields() {
+		if path := strings.TrimSpace(*field.file); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("%s_file: %w", field.name, err)
+			}
+			*field.value = strings.TrimSpace(string(data))
+		}
+		ref, ok := strings.CutPrefix(strings.TrimSpace(*field.value), KeyringPrefix)
+		if !ok {
+			continue
+		}
+		service, account, ok := strings.Cut(ref, "/")
+		if !ok || service == "" || account == "" {
+			return fmt.Errorf("%s: keyring reference must be keyring:<service>/<account>", field.name)
+		}
+		secret, err := keyringLookup(service, account)
+		if err != nil {
+			return fmt.Errorf("%s: read keyring %s/%s: %w", field.name, service, account, err)
+		}
+		*field.value = strings.TrimSpace(secret)
+	}
+	return nil
+}
+
+// Secrets 返回已解析的非空密钥，用于登记到日志脱敏（logging.RegisterSecrets）
+func (c *AppConfig) Secrets() []string {
+	var secrets []string
+	for _, field := range c.secretFields() {
+		if value := strings.TrimSpace(*field.value); value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+	return secrets
+}
+
+// Redacted 返回密钥替换为 *** 的副本，用于输出配置；*_file 路径保留。
+// 配置档已叠加到副本中，profiles 原文（可能含密钥）不再输出，profile 为实际启用的配置档
+func (c *AppConfig) Redacted() *AppConfig {
+	out := *c
+	out.Profile = strings.Join(c.ActiveProfiles, ",")
+	out.Profiles = nil
+	out.LLM.Fallbacks = append([]LLMEndpoint(nil), c.LLM.Fallbacks...)
+	for _, field := range out.secretFields() {
+		if *field.value != "" {
+			*field.value = redactedSecret
+		}
+	}
+	return &out
+}
+
+// lookupKeyring 通过系统命令读取钥匙串：macOS 使用 security，Linux 使用 libsecret 的 secret-tool
+func lookupKeyring(service, account string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", service, "-a", account, "-w")
+	case "linux", "freebsd", "openbsd":
+		cmd = exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	default:
+		return "", fmt.Errorf("keyring is not supported on %s", runtime.GOOS)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s: %s", cmd.Args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	secret := strings.TrimSpace(string(out))
+	if secret == "" {
+		return "", errors.New("secret not found")
+	}
+	return secret, nil
+}
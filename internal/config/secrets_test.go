@@ -0,0 +1,119 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadResolvesSecrets(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "dashscope.key")
+	if err := os.WriteFile(keyFile, []byte("  file-dash-key\n"), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	keyringLookup = func(service, account string) (string, error) {
+		if service == "orion-x" && account == "zhipu" {
+			return "keyring-llm-key\n", nil
+		}
+		return "", errors.New("secret not found")
+	}
+	t.Cleanup(func() { keyringLookup = lookupKeyring })
+	for _, env := range []string{"DASHSCOPE_API_KEY", "ZHIPU_API_KEY", "GEMINI_API_KEY", "MQTT_PASSWORD", "REALTIME_API_KEY", ProfileEnv} {
+		t.Setenv(env, "")
+	}
+
+	tests := []struct {
+		name    string
+		config  string
+		check   func(*AppConfig) bool
+		wantErr string
+	}{
+		{
+			name:   "file overrides inline value",
+			config: `{"asr": {"api_key": "inline", "api_key_file": "` + keyFile + `"}}`,
+			check:  func(c *AppConfig) bool { return c.ASR.APIKey == "file-dash-key" },
+		},
+		{
+			name:   "keyring reference",
+			config: `{"llm": {"api_key": "keyring:orion-x/zhipu", "fallbacks": [{"api_key_file": "` + keyFile + `"}]}}`,
+			check: func(c *AppConfig) bool {
+				return c.LLM.APIKey == "keyring-llm-key" && c.LLM.Fallbacks[0].APIKey == "file-dash-key"
+			},
+		},
+		{
+			name:   "mqtt password file",
+			config: `{"mqtt": {"password_file": "` + keyFile + `"}}`,
+			check:  func(c *AppConfig) bool { return c.MQTT.Password == "file-dash-key" },
+		},
+		{
+			name:    "missing file",
+			config:  `{"tts": {"api_key_file": "` + filepath.Join(dir, "missing") + `"}}`,
+			wantErr: "tts.api_key_file",
+		},
+		{
+			name:    "unknown keyring entry",
+			config:  `{"realtime": {"api_key": "keyring:orion-x/openai"}}`,
+			wantErr: "realtime.api_key",
+		},
+		{
+			name:    "malformed keyring reference",
+			config:  `{"llm": {"api_key": "keyring:zhipu"}}`,
+			wantErr: "keyring:<service>/<account>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, "voicebot.json")
+			if err := os.WriteFile(path, []byte(tt.config), 0o600); err != nil {
+				t.Fatalf("write config: %v", err)
+			}
+			cfg, err := Load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if !tt.check(cfg) {
+				t.Errorf("secrets not resolved: asr=%q llm=%q mqtt=%q", cfg.ASR.APIKey, cfg.LLM.APIKey, cfg.MQTT.Password)
+			}
+		})
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.ASR.APIKey = "dash-secret"
+	cfg.LLM.APIKeyFile = "/run/secrets/llm"
+	cfg.LLM.Fallbacks = []LLMEndpoint{{Name: "backup", APIKey: "backup-secret"}}
+	cfg.MQTT.Password = "mqtt-secret"
+	cfg.Profiles = map[string]json.RawMessage{"server": json.RawMessage(`{"tts": {"api_key": "profile-secret"}}`)}
+	cfg.ActiveProfiles = []string{"server"}
+
+	data, err := json.Marshal(cfg.Redacted())
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	out := string(data)
+	for _, secret := range []string{"dash-secret", "backup-secret", "mqtt-secret", "profile-secret"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("redacted config contains %q", secret)
+		}
+	}
+	if !strings.Contains(out, `"api_key_file":"/run/secrets/llm"`) || !strings.Contains(out, `"profile":"server"`) {
+		t.Errorf("redacted config = %s", out)
+	}
+	if cfg.ASR.APIKey != "dash-secret" || cfg.LLM.Fallbacks[0].APIKey != "backup-secret" {
+		t.Error("Redacted() modified the original config")
+	}
+	if got := cfg.Secrets(); len(got) != 3 {
+		t.Errorf("Secrets() = %v, want 3 values", got)
+	}
+}
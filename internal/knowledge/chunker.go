@@ -0,0 +1,66 @@
+package knowledge
+
+import (
+	"strings"
+)
+
+// splitChunks 按段落切块：尽量在段落、句子边界处切分，单块不超过 size 个字符，相邻块重叠 overlap 个字符
+func splitChunks(text string, size, overlap int) []string {
+	var chunks []string
+	var current []rune
+
+	flush := func() {
+		if s := strings.TrimSpace(string(current)); s != "" {
+			chunks = append(chunks, s)
+		}
+		if overlap > 0 && len(current) > overlap {
+			current = append([]rune(nil), current[len(current)-overlap:]...)
+		} else {
+			current = current[:0]
+		}
+	}
+
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		for _, sentence := range splitSentences(paragraph) {
+			runes := []rune(sentence)
+			// 超长句子按 size 硬切
+			for len(runes) > 0 {
+				room := size - len(current)
+				if room <= 0 || (len(current) > overlap && len(runes) > room) {
+					flush()
+					room = size - len(current)
+				}
+				n := min(room, len(runes))
+				current = append(current, runes[:n]...)
+				runes = runes[n:]
+			}
+		}
+		current = append(current, '\n')
+	}
+	if len(current) > overlap || len(chunks) == 0 {
+		flush()
+	}
+	return chunks
+}
+
+// splitSentences 在句末标点后切分，保留标点
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	runes := []rune(text)
+	for i, r := range runes {
+		switch r {
+		case '。', '！', '？', '；', '.', '!', '?', ';', '\n':
+			sentences = append(sentences, string(runes[start:i+1]))
+			start = i + 1
+		}
+	}
+	if start < len(runes) {
+		sentences = append(sentences, string(runes[start:]))
+	}
+	return sentences
+}
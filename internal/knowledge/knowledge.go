@@ -0,0 +1,174 @@
+// Package knowledge 本地知识库检索：把文档切块、向量化后存入内存向量库，对话时检索相关片段补充到提示词
+package knowledge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// Config 知识库配置
+type Config struct {
+	ChunkSize    int     // 每个片段的最大字符数
+	ChunkOverlap int     // 相邻片段重叠的字符数
+	TopK         int     // 每次检索返回的片段数
+	MinScore     float64 // 相似度下限（余弦相似度），低于该值的片段不返回
+}
+
+// DefaultConfig 默认知识库配置
+func DefaultConfig() Config {
+	return Config{
+		ChunkSize:    300,
+		ChunkOverlap: 50,
+		TopK:         3,
+		MinScore:     0.2,
+	}
+}
+
+// Snippet 检索到的文档片段
+type Snippet struct {
+	Source string  // 来源文件
+	Text   string  // 片段内容
+	Score  float64 // 与问题的相似度
+}
+
+// supportedExtensions 支持导入的文档类型
+var supportedExtensions = map[string]bool{".txt": true, ".md": true, ".markdown": true}
+
+// Base 知识库：文档切块 → 向量化 → 内存向量检索
+type Base struct {
+	config   Config
+	embedder Embedder
+
+	mu     sync.RWMutex
+	chunks []chunk
+}
+
+type chunk struct {
+	source string
+	text   string
+	vector []float32
+}
+
+// NewBase 创建知识库，embedder 为 nil 时使用本地哈希向量
+func NewBase(config Config, embedder Embedder) *Base {
+	defaults := DefaultConfig()
+	if config.ChunkSize <= 0 {
+		config.ChunkSize = defaults.ChunkSize
+	}
+	if config.ChunkOverlap < 0 || config.ChunkOverlap >= config.ChunkSize {
+		config.ChunkOverlap = 0
+	}
+	if config.TopK <= 0 {
+		config.TopK = defaults.TopK
+	}
+	if embedder == nil {
+		embedder = NewHashEmbedder(0)
+	}
+	return &Base{config: config, embedder: embedder}
+}
+
+// Len 已导入的片段数
+func (b *Base) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.chunks)
+}
+
+// IngestPaths 导入文件或目录（递归）中的 .txt / .md 文档
+func (b *Base) IngestPaths(ctx context.Context, paths ...string) error {
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(file string, entry os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || !supportedExtensions[strings.ToLower(filepath.Ext(file))] {
+				return nil
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			return b.IngestText(ctx, file, string(data))
+		})
+		if err != nil {
+			return fmt.Errorf("ingest %s: %w", path, err)
+		}
+	}
+	logging.Infof("Knowledge: ingested %d chunk(s) from %d path(s)", b.Len(), len(paths))
+	return nil
+}
+
+// IngestText 导入一段文本，source 用于标注来源
+func (b *Base) IngestText(ctx context.Context, source, text string) error {
+	texts := splitChunks(text, b.config.ChunkSize, b.config.ChunkOverlap)
+	if len(texts) == 0 {
+		return nil
+	}
+	vectors, err := b.embedder.Embed(ctx, texts)
+	if err != nil {
+		return fmt.Errorf("embed %s: %w", source, err)
+	}
+	if len(vectors) != len(texts) {
+		return errors.New("embedder returned mismatched vector count")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, text := range texts {
+		b.chunks = append(b.chunks, chunk{source: source, text: text, vector: normalize(vectors[i])})
+	}
+	return nil
+}
+
+// Retrieve 检索与问题最相关的片段，按相似度从高到低排序
+func (b *Base) Retrieve(ctx context.Context, query string) ([]Snippet, error) {
+	if strings.TrimSpace(query) == "" || b.Len() == 0 {
+		return nil, nil
+	}
+	vectors, err := b.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("embed query: %w", err)
+	}
+	if len(vectors) != 1 {
+		return nil, errors.New("embedder returned mismatched vector count")
+	}
+	queryVector := normalize(vectors[0])
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	snippets := make([]Snippet, 0, b.config.TopK)
+	for _, c := range b.chunks {
+		score := dot(queryVector, c.vector)
+		if score < b.config.MinScore {
+			continue
+		}
+		snippets = insertTopK(snippets, Snippet{Source: c.source, Text: c.text, Score: score}, b.config.TopK)
+	}
+	return snippets, nil
+}
+
+// insertTopK 按分数有序插入，只保留前 k 个
+func insertTopK(snippets []Snippet, snippet Snippet, k int) []Snippet {
+	i := len(snippets)
+	for i > 0 && snippets[i-1].Score < snippet.Score {
+		i--
+	}
+	if i >= k {
+		return snippets
+	}
+	snippets = append(snippets, Snippet{})
+	copy(snippets[i+1:], snippets[i:])
+	snippets[i] = snippet
+	if len(snippets) > k {
+		snippets = snippets[:k]
+	}
+	return snippets
+}
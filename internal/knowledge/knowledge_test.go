@@ -0,0 +1,169 @@
+package knowledge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSplitChunks(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		size    int
+		overlap int
+		want    int
+	}{
+		{"empty", "  \n\n ", 100, 0, 0},
+		{"single paragraph", "报销需要提交发票。", 100, 0, 1},
+		{"split by size", strings.Repeat("一二三四五。", 10), 12, 0, 5},
+		{"long sentence hard split", strings.Repeat("字", 25), 10, 0, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := splitChunks(tt.text, tt.size, tt.overlap)
+			if len(chunks) != tt.want {
+				t.Fatalf("got %d chunks %q, want %d", len(chunks), chunks, tt.want)
+			}
+			for _, c := range chunks {
+				if n := len([]rune(c)); n > tt.size {
+					t.Errorf("chunk %q has %d runes, exceeds %d", c, n, tt.size)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitChunksOverlap(t *testing.T) {
+	chunks := splitChunks("第一句话。第二句话。第三句话。", 10, 5)
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got %q", chunks)
+	}
+	if !strings.HasPrefix(chunks[1], "第二句话。") {
+		t.Errorf("expected second chunk to overlap previous sentence, got %q", chunks[1])
+	}
+}
+
+func TestBaseRetrieve(t *testing.T) {
+	ctx := context.Background()
+	base := NewBase(DefaultConfig(), nil)
+	docs := map[string]string{
+		"expense.md": "报销流程：员工在系统中提交报销单，附上发票，由部门经理审批后财务打款。",
+		"leave.md":   "请假流程：提前三天在系统中提交请假申请，由直属主管审批。",
+		"wifi.txt":   "办公室无线网络名称是 Orion，密码请咨询前台。",
+	}
+	for source, text := range docs {
+		if err := base.IngestText(ctx, source, text); err != nil {
+			t.Fatalf("IngestText failed: %v", err)
+		}
+	}
+
+	snippets, err := base.Retrieve(ctx, "我们公司的报销流程是什么")
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if len(snippets) == 0 {
+		t.Fatal("expected at least one snippet")
+	}
+	if snippets[0].Source != "expense.md" {
+		t.Errorf("top snippet source = %s, want expense.md", snippets[0].Source)
+	}
+	for i := 1; i < len(snippets); i++ {
+		if snippets[i].Score > snippets[i-1].Score {
+			t.Errorf("snippets not sorted by score: %v", snippets)
+		}
+	}
+
+	if snippets, _ := base.Retrieve(ctx, "   "); snippets != nil {
+		t.Errorf("expected no snippets for empty query, got %v", snippets)
+	}
+}
+
+func TestBaseRetrieveTopKAndMinScore(t *testing.T) {
+	ctx := context.Background()
+	base := NewBase(Config{ChunkSize: 100, TopK: 1, MinScore: 0.99}, nil)
+	_ = base.IngestText(ctx, "a", "报销流程")
+	_ = base.IngestText(ctx, "b", "报销流程说明")
+
+	snippets, err := base.Retrieve(ctx, "报销流程")
+	if err != nil {
+		t.Fatalf("Retrieve failed: %v", err)
+	}
+	if len(snippets) != 1 || snippets[0].Source != "a" {
+		t.Errorf("expected only exact match, got %v", snippets)
+	}
+}
+
+func TestBaseIngestPaths(t *testing.T) {
+	dir := t.TempDir()
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(dir, "a.md"):     "# 报销\n\n报销需要发票。",
+		filepath.Join(sub, "b.txt"):    "请假需要审批。",
+		filepath.Join(dir, "skip.bin"): "ignored",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	base := NewBase(DefaultConfig(), nil)
+	if err := base.IngestPaths(context.Background(), dir); err != nil {
+		t.Fatalf("IngestPaths failed: %v", err)
+	}
+	if base.Len() != 2 {
+		t.Errorf("expected 2 chunks, got %d", base.Len())
+	}
+
+	if err := base.IngestPaths(context.Background(), filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected error for missing path")
+	}
+}
+
+func TestOpenAIEmbedder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer key" {
+			t.Errorf("unexpected auth header %q", got)
+		}
+		var req embeddingRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		// 倒序返回，验证按 index 归位
+		w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer server.Close()
+
+	embedder := NewOpenAIEmbedder("key", server.URL+"/", "test-model")
+	vectors, err := embedder.Embed(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if vectors[0][0] != 1 || vectors[1][1] != 1 {
+		t.Errorf("vectors out of order: %v", vectors)
+	}
+}
+
+func TestOpenAIEmbedderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad key", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	_, err := NewOpenAIEmbedder("", server.URL, "").Embed(context.Background(), []string{"a"})
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected 401 error, got %v", err)
+	}
+}
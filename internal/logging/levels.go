@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// componentLevelCore 按调用方所在的包（如 audio、agent、voicebot）覆盖日志级别
+// 调用位置只在写入时可知，因此 Enabled 按所有级别中最低的放行，Write 时再按包过滤
+type componentLevelCore struct {
+	zapcore.Core
+	level     zapcore.Level
+	overrides map[string]zapcore.Level
+	minLevel  zapcore.Level
+}
+
+func newComponentLevelCore(core zapcore.Core, level zapcore.Level, overrides map[string]zapcore.Level) zapcore.Core {
+	minLevel := level
+	for _, l := range overrides {
+		if l < minLevel {
+			minLevel = l
+		}
+	}
+	return &componentLevelCore{Core: core, level: level, overrides: overrides, minLevel: minLevel}
+}
+
+func (c *componentLevelCore) Enabled(level zapcore.Level) bool {
+	return level >= c.minLevel && c.Core.Enabled(level)
+}
+
+func (c *componentLevelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &componentLevelCore{
+		Core:      c.Core.With(fields),
+		level:     c.level,
+		overrides: c.overrides,
+		minLevel:  c.minLevel,
+	}
+}
+
+func (c *componentLevelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *componentLevelCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if entry.Level < c.levelFor(entry.Caller) {
+		return nil
+	}
+	return c.Core.Write(entry, fields)
+}
+
+func (c *componentLevelCore) levelFor(caller zapcore.EntryCaller) zapcore.Level {
+	if len(c.overrides) == 0 || !caller.Defined {
+		return c.level
+	}
+	if level, ok := c.overrides[callerPackage(caller.Function)]; ok {
+		return level
+	}
+	return c.level
+}
+
+// callerPackage 从函数全名中取包名，如 ".../internal/audio.(*inPipeImpl).Start" → "audio"
+func callerPackage(function string) string {
+	if i := strings.LastIndex(function, "/"); i >= 0 {
+		function = function[i+1:]
+	}
+	if i := strings.Index(function, "."); i >= 0 {
+		function = function[:i]
+	}
+	return function
+}
+
+// parseLevels 解析按包名的级别覆盖
+func parseLevels(levels map[string]string) (map[string]zapcore.Level, error) {
+	if len(levels) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]zapcore.Level, len(levels))
+	for component, value := range levels {
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(strings.ToLower(strings.TrimSpace(value)))); err != nil {
+			return nil, fmt.Errorf("invalid log level for %s: %s", component, value)
+		}
+		parsed[strings.TrimSpace(component)] = level
+	}
+	return parsed, nil
+}
+
+// ParseLevelList 解析 "audio=debug,agent=warn" 形式的级别覆盖（LOG_LEVELS 环境变量）
+func ParseLevelList(s string) (map[string]string, error) {
+	levels := make(map[string]string)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		component, level, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(component) == "" {
+			return nil, fmt.Errorf("invalid log level override %q, want component=level", item)
+		}
+		levels[strings.TrimSpace(component)] = strings.TrimSpace(level)
+	}
+	return levels, nil
+}
@@ -0,0 +1,62 @@
+package logging
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestComponentLevelOverrides(t *testing.T) {
+	inner, recorded := observer.New(zapcore.DebugLevel)
+	overrides, err := parseLevels(map[string]string{"audio": "debug", "agent": "warn"})
+	if err != nil {
+		t.Fatalf("parseLevels() error = %v", err)
+	}
+	core := newComponentLevelCore(inner, zapcore.InfoLevel, overrides)
+
+	tests := []struct {
+		function string
+		level    zapcore.Level
+		want     bool
+	}{
+		{"github.com/liuscraft/orion-x/internal/audio.(*inPipeImpl).detectSpeech", zapcore.DebugLevel, true},
+		{"github.com/liuscraft/orion-x/internal/agent.(*voiceAgentImpl).Process.func1", zapcore.InfoLevel, false},
+		{"github.com/liuscraft/orion-x/internal/agent.streamWithFallback", zapcore.WarnLevel, true},
+		{"github.com/liuscraft/orion-x/internal/voicebot.(*orchestratorImpl).Start", zapcore.DebugLevel, false},
+		{"github.com/liuscraft/orion-x/internal/voicebot.(*orchestratorImpl).Start", zapcore.InfoLevel, true},
+		{"main.main", zapcore.InfoLevel, true},
+	}
+	for _, tt := range tests {
+		before := recorded.Len()
+		entry := zapcore.Entry{
+			Level:   tt.level,
+			Message: tt.function,
+			Caller:  zapcore.EntryCaller{Defined: true, Function: tt.function},
+		}
+		if checked := core.With(nil).Check(entry, nil); checked != nil {
+			checked.Entry.Caller = entry.Caller
+			checked.Write()
+		}
+		if got := recorded.Len() > before; got != tt.want {
+			t.Errorf("%s at %s written = %v, want %v", tt.function, tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestParseLevelList(t *testing.T) {
+	got, err := ParseLevelList(" audio=debug, asr=info ,")
+	if err != nil {
+		t.Fatalf("ParseLevelList() error = %v", err)
+	}
+	if want := map[string]string{"audio": "debug", "asr": "info"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseLevelList() = %v, want %v", got, want)
+	}
+	if _, err := ParseLevelList("audio"); err == nil {
+		t.Fatal("expected error for missing level")
+	}
+	if _, err := parseLevels(map[string]string{"audio": "loud"}); err == nil {
+		t.Fatal("expected error for invalid level")
+	}
+}
@@ -1,9 +1,11 @@
 package logging
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"sync/atomic"
@@ -15,11 +17,18 @@ import (
 type Config struct {
 	Level  string
 	Format string
+	// Levels 按包名覆盖日志级别，如 {"audio": "debug", "agent": "warn"}，对 stderr 和文件都生效
+	Levels map[string]string
+	// File 额外写入的日志文件（JSON 格式，支持轮转），Path 为空时只输出到 stderr
+	File FileConfig
+	// Output 替代 stderr 的日志输出（如 TUI 的日志面板），不带终端颜色码；为 nil 时输出到 stderr
+	Output io.Writer
 }
 
 var (
 	baseLogger *zap.Logger
 	sugar      *zap.SugaredLogger
+	logFile    *rotatingFile
 	traceID    atomic.Value
 	turnID     uint64
 )
@@ -29,10 +38,19 @@ func init() {
 	sugar = baseLogger.Sugar()
 }
 
+// InitFromEnv 按 LOG_LEVEL、LOG_FORMAT、LOG_LEVELS（如 audio=debug,agent=warn）、LOG_FILE 初始化
 func InitFromEnv() error {
 	cfg := Config{
 		Level:  os.Getenv("LOG_LEVEL"),
 		Format: os.Getenv("LOG_FORMAT"),
+		File:   FileConfig{Path: strings.TrimSpace(os.Getenv("LOG_FILE"))},
+	}
+	if list := os.Getenv("LOG_LEVELS"); list != "" {
+		levels, err := ParseLevelList(list)
+		if err != nil {
+			return err
+		}
+		cfg.Levels = levels
 	}
 	return Init(cfg)
 }
@@ -59,22 +77,77 @@ func Init(cfg Config) error {
 		return fmt.Errorf("invalid LOG_FORMAT: %s", cfg.Format)
 	}
 
-	atomLevel := zap.NewAtomicLevel()
-	if err := atomLevel.UnmarshalText([]byte(level)); err != nil {
+	var baseLevel zapcore.Level
+	if err := baseLevel.UnmarshalText([]byte(level)); err != nil {
 		return fmt.Errorf("invalid LOG_LEVEL: %s", cfg.Level)
 	}
-	zapCfg.Level = atomLevel
+	overrides, err := parseLevels(cfg.Levels)
+	if err != nil {
+		return err
+	}
+	fileLevel := baseLevel
+	if fl := strings.ToLower(strings.TrimSpace(cfg.File.Level)); fl != "" {
+		if err := fileLevel.UnmarshalText([]byte(fl)); err != nil {
+			return fmt.Errorf("invalid log file level: %s", cfg.File.Level)
+		}
+	}
+
+	// 底层 core 按最低级别放行，具体过滤交给 componentLevelCore
+	minLevel := baseLevel
+	if cfg.File.Path != "" && fileLevel < minLevel {
+		minLevel = fileLevel
+	}
+	for _, l := range overrides {
+		if l < minLevel {
+			minLevel = l
+		}
+	}
+	zapCfg.Level = zap.NewAtomicLevelAt(minLevel)
+
+	var file *rotatingFile
+	if cfg.File.Path != "" {
+		file, err = newRotatingFile(cfg.File)
+		if err != nil {
+			return err
+		}
+	}
 
 	logger, err := zapCfg.Build(
 		zap.AddCaller(),
 		zap.AddCallerSkip(1),
+		zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+			if cfg.Output != nil {
+				encoderCfg := zapCfg.EncoderConfig
+				encoderCfg.EncodeLevel = zapcore.CapitalLevelEncoder
+				encoder := zapcore.NewConsoleEncoder(encoderCfg)
+				if format == "json" {
+					encoder = zapcore.NewJSONEncoder(encoderCfg)
+				}
+				core = zapcore.NewCore(encoder, zapcore.AddSync(cfg.Output), zapCfg.Level)
+			}
+			core = newComponentLevelCore(core, baseLevel, overrides)
+			if file == nil {
+				return newRedactCore(core)
+			}
+			// 文件固定使用 JSON 格式，便于检索且不含终端颜色码
+			fileCore := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), file, zapcore.DebugLevel)
+			return newRedactCore(zapcore.NewTee(core, newComponentLevelCore(fileCore, fileLevel, overrides)))
+		}),
 	)
 	if err != nil {
+		if file != nil {
+			file.Close()
+		}
 		return fmt.Errorf("build logger: %w", err)
 	}
 
+	if logFile != nil {
+		_ = baseLogger.Sync()
+		logFile.Close()
+	}
 	baseLogger = logger
 	sugar = logger.Sugar()
+	logFile = file
 	return nil
 }
 
@@ -103,6 +176,72 @@ func StartTurn() uint64 {
 	return atomic.AddUint64(&turnID, 1)
 }
 
+type logContextKey struct{}
+
+// logContext 随 ctx 传递的日志关联字段，为空的字段使用全局值
+type logContext struct {
+	traceID string
+	turnID  uint64
+}
+
+func logContextFrom(ctx context.Context) logContext {
+	if ctx == nil {
+		return logContext{}
+	}
+	lc, _ := ctx.Value(logContextKey{}).(logContext)
+	return lc
+}
+
+// WithTraceID 把 trace ID 附加到 ctx，同一进程内的多个会话可各自使用不同的 trace ID
+func WithTraceID(ctx context.Context, id string) context.Context {
+	lc := logContextFrom(ctx)
+	lc.traceID = strings.TrimSpace(id)
+	return context.WithValue(ctx, logContextKey{}, lc)
+}
+
+// WithTurn 把轮次 ID 附加到 ctx，Agent、TTS、工具等子系统用 *Ctx 日志函数记录时带上同一轮的 turn_id
+func WithTurn(ctx context.Context, turn uint64) context.Context {
+	lc := logContextFrom(ctx)
+	lc.turnID = turn
+	return context.WithValue(ctx, logContextKey{}, lc)
+}
+
+// StartTurnContext 开始新的一轮，返回携带该轮次 ID 的 ctx
+func StartTurnContext(ctx context.Context) (context.Context, uint64) {
+	turn := StartTurn()
+	return WithTurn(ctx, turn), turn
+}
+
+// TurnFromContext 返回 ctx 中的轮次 ID
+func TurnFromContext(ctx context.Context) (uint64, bool) {
+	lc := logContextFrom(ctx)
+	return lc.turnID, lc.turnID != 0
+}
+
+// TraceIDFromContext 返回 ctx 中的 trace ID
+func TraceIDFromContext(ctx context.Context) (string, bool) {
+	lc := logContextFrom(ctx)
+	return lc.traceID, lc.traceID != ""
+}
+
+// CurrentTraceID 返回 ctx 中的 trace ID，未设置时返回全局 trace ID
+func CurrentTraceID(ctx context.Context) string {
+	if id, ok := TraceIDFromContext(ctx); ok {
+		return id
+	}
+	id, _ := traceID.Load().(string)
+	return id
+}
+
+// CopyTurn 把 src 中的 trace ID 与轮次 ID 附加到 dst，用于取消语义不同（如 Pipeline 自身的 ctx）但属于同一轮的调用
+func CopyTurn(dst, src context.Context) context.Context {
+	lc := logContextFrom(src)
+	if lc == (logContext{}) {
+		return dst
+	}
+	return context.WithValue(dst, logContextKey{}, lc)
+}
+
 func Debugf(format string, args ...interface{}) {
 	withFields().Debugf(format, args...)
 }
@@ -123,12 +262,43 @@ func Fatalf(format string, args ...interface{}) {
 	withFields().Fatalf(format, args...)
 }
 
+// DebugfCtx 同 Debugf，trace_id / turn_id 优先取 ctx 中的值
+func DebugfCtx(ctx context.Context, format string, args ...interface{}) {
+	withContextFields(ctx).Debugf(format, args...)
+}
+
+// InfofCtx 同 Infof，trace_id / turn_id 优先取 ctx 中的值
+func InfofCtx(ctx context.Context, format string, args ...interface{}) {
+	withContextFields(ctx).Infof(format, args...)
+}
+
+// WarnfCtx 同 Warnf，trace_id / turn_id 优先取 ctx 中的值
+func WarnfCtx(ctx context.Context, format string, args ...interface{}) {
+	withContextFields(ctx).Warnf(format, args...)
+}
+
+// ErrorfCtx 同 Errorf，trace_id / turn_id 优先取 ctx 中的值
+func ErrorfCtx(ctx context.Context, format string, args ...interface{}) {
+	withContextFields(ctx).Errorf(format, args...)
+}
+
 func withFields() *zap.SugaredLogger {
-	tid, _ := traceID.Load().(string)
+	return withContextFields(context.Background())
+}
+
+func withContextFields(ctx context.Context) *zap.SugaredLogger {
+	lc := logContextFrom(ctx)
+	tid := lc.traceID
+	if tid == "" {
+		tid, _ = traceID.Load().(string)
+	}
 	if tid == "" {
 		tid = "trace-unknown"
 	}
-	currentTurn := atomic.LoadUint64(&turnID)
+	currentTurn := lc.turnID
+	if currentTurn == 0 {
+		currentTurn = atomic.LoadUint64(&turnID)
+	}
 	return sugar.With(
 		"trace_id", tid,
 		"turn_id", currentTurn,
@@ -1,6 +1,8 @@
 package logging
 
 import (
+	"context"
+	"strings"
 	"testing"
 
 	"go.uber.org/zap"
@@ -45,3 +47,62 @@ func TestStartTurnAddsLogFields(t *testing.T) {
 		t.Fatalf("expected log_id to be trace-123-1, got %v", fields["log_id"])
 	}
 }
+
+func TestContextFieldsOverrideGlobal(t *testing.T) {
+	core, recorded := observer.New(zapcore.InfoLevel)
+	baseLogger = zap.New(core)
+	sugar = baseLogger.Sugar()
+	traceID.Store("")
+	turnID = 0
+
+	SetTraceID("trace-global")
+	ctx, turn := StartTurnContext(WithTraceID(context.Background(), "trace-session"))
+	StartTurn() // 另一个会话开始了新的一轮
+	InfofCtx(ctx, "from turn context")
+	Infof("from global")
+
+	copied := CopyTurn(context.Background(), ctx)
+	if got, ok := TurnFromContext(copied); !ok || got != turn {
+		t.Fatalf("TurnFromContext(copied) = %d, %v, want %d", got, ok, turn)
+	}
+
+	logs := recorded.All()
+	if len(logs) != 2 {
+		t.Fatalf("expected 2 log entries, got %d", len(logs))
+	}
+	tests := []struct {
+		trace string
+		turn  uint64
+		logID string
+	}{
+		{"trace-session", 1, "trace-session-1"},
+		{"trace-global", 2, "trace-global-2"},
+	}
+	for i, tt := range tests {
+		fields := logs[i].ContextMap()
+		if fields["trace_id"] != tt.trace || fields["turn_id"] != tt.turn || fields["log_id"] != tt.logID {
+			t.Errorf("entry %d fields = %v, want trace_id=%s turn_id=%d", i, fields, tt.trace, tt.turn)
+		}
+	}
+}
+
+func TestInitOutput(t *testing.T) {
+	var out strings.Builder
+	if err := Init(Config{Level: "info", Output: &out}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	t.Cleanup(func() {
+		baseLogger = zap.NewNop()
+		sugar = baseLogger.Sugar()
+	})
+
+	Debugf("hidden")
+	Warnf("to the panel")
+	got := out.String()
+	if !strings.Contains(got, "WARN") || !strings.Contains(got, "to the panel") || strings.Contains(got, "hidden") {
+		t.Fatalf("output = %q", got)
+	}
+	if strings.Contains(got, "\x1b[") {
+		t.Fatalf("output should not contain color codes: %q", got)
+	}
+}
@@ -0,0 +1,98 @@
+package logging
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// redactedSecret 日志中替换密钥的文本
+const redactedSecret = "***"
+
+// minSecretLength 短于该长度的值不当作密钥，避免误替换普通文本
+const minSecretLength = 6
+
+var (
+	secretsMu sync.Mutex
+	secrets   []string
+	scrubber  atomic.Pointer[strings.Replacer]
+)
+
+// RegisterSecrets 登记密钥（API Key、密码等），之后写入的日志中出现这些值时替换为 ***，
+// 对 stderr、日志文件和 Output 都生效；空值和过短的值被忽略
+func RegisterSecrets(values ...string) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len(value) < minSecretLength {
+			continue
+		}
+		if !slices.Contains(secrets, value) {
+			secrets = append(secrets, value)
+		}
+	}
+	pairs := make([]string, 0, len(secrets)*2)
+	for _, secret := range secrets {
+		pairs = append(pairs, secret, redactedSecret)
+	}
+	scrubber.Store(strings.NewReplacer(pairs...))
+}
+
+// Redact 把已登记的密钥替换为 ***
+func Redact(text string) string {
+	if r := scrubber.Load(); r != nil {
+		return r.Replace(text)
+	}
+	return text
+}
+
+// redactCore 写入前替换日志消息和字符串字段中的密钥
+type redactCore struct {
+	zapcore.Core
+}
+
+func newRedactCore(core zapcore.Core) zapcore.Core {
+	return &redactCore{Core: core}
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if scrubber.Load() == nil {
+		return c.Core.Write(entry, fields)
+	}
+	entry.Message = Redact(entry.Message)
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	if scrubber.Load() == nil {
+		return fields
+	}
+	out := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		switch field.Type {
+		case zapcore.StringType:
+			field.String = Redact(field.String)
+		case zapcore.ErrorType:
+			if err, ok := field.Interface.(error); ok && err != nil {
+				field = zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: Redact(err.Error())}
+			}
+		}
+		out[i] = field
+	}
+	return out
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestRegisterSecretsRedactsOutput(t *testing.T) {
+	var out strings.Builder
+	if err := Init(Config{Level: "info", Format: "json", Output: &out}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	t.Cleanup(func() {
+		baseLogger = zap.NewNop()
+		sugar = baseLogger.Sugar()
+		secretsMu.Lock()
+		secrets = nil
+		secretsMu.Unlock()
+		scrubber.Store(nil)
+	})
+
+	RegisterSecrets("sk-live-123456", "", "abc")
+	Infof("connecting with key sk-live-123456")
+	baseLogger.With(zap.String("token", "Bearer sk-live-123456")).Info("request", zap.Error(errors.New("401 for sk-live-123456")))
+	Infof("short values like abc are kept")
+
+	got := out.String()
+	if strings.Contains(got, "sk-live-123456") {
+		t.Fatalf("secret leaked into output: %q", got)
+	}
+	for _, want := range []string{"connecting with key ***", `"token":"Bearer ***"`, "401 for ***", "abc are kept"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output missing %q: %q", want, got)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	t.Cleanup(func() {
+		secretsMu.Lock()
+		secrets = nil
+		secretsMu.Unlock()
+		scrubber.Store(nil)
+	})
+	if got := Redact("key secret-value"); got != "key secret-value" {
+		t.Fatalf("Redact() without secrets = %q", got)
+	}
+	RegisterSecrets("secret-value", "secret-value", "other-secret")
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"key secret-value", "key ***"},
+		{"other-secret and secret-value", "*** and ***"},
+		{"nothing here", "nothing here"},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.in); got != tt.want {
+			t.Errorf("Redact(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileConfig 日志文件输出，Path 为空时不写文件
+type FileConfig struct {
+	Path string
+	// Level 文件的日志级别，为空时与 Config.Level 相同
+	Level string
+	// MaxSizeMB 文件超过该大小时轮转，0 表示不按大小轮转
+	MaxSizeMB int
+	// RotateInterval 文件打开超过该时长时轮转，0 表示不按时间轮转
+	RotateInterval time.Duration
+	// MaxBackups 保留的历史文件数，0 表示全部保留
+	MaxBackups int
+}
+
+// backupTimeFormat 历史文件后缀，按字典序即时间顺序
+const backupTimeFormat = "20060102-150405.000"
+
+// rotatingFile 按大小 / 时间轮转的日志文件，历史文件重命名为 <path>.<时间>
+type rotatingFile struct {
+	config FileConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+func newRotatingFile(config FileConfig) (*rotatingFile, error) {
+	r := &rotatingFile{config: config, now: time.Now}
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("create log dir: %w", err)
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	file, err := os.OpenFile(r.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("stat log file: %w", err)
+	}
+	r.file = file
+	r.size = info.Size()
+	r.openedAt = r.now()
+	return nil
+}
+
+// Write 写入一条日志，写入前按需轮转；一条日志不会被拆到两个文件
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.shouldRotate(len(p)) {
+		if err := r.rotate(); err != nil {
+			fmt.Fprintf(os.Stderr, "logging: rotate %s: %v\n", r.config.Path, err)
+		}
+	}
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *rotatingFile) shouldRotate(next int) bool {
+	if r.size == 0 {
+		return false
+	}
+	if max := int64(r.config.MaxSizeMB) * 1024 * 1024; max > 0 && r.size+int64(next) > max {
+		return true
+	}
+	return r.config.RotateInterval > 0 && r.now().Sub(r.openedAt) >= r.config.RotateInterval
+}
+
+// rotate 关闭当前文件并重命名为历史文件，打开新文件后清理超出 MaxBackups 的历史文件
+func (r *rotatingFile) rotate() error {
+	if r.file != nil {
+		r.file.Close()
+		r.file = nil
+	}
+	backup := r.config.Path + "." + r.now().Format(backupTimeFormat)
+	if err := os.Rename(r.config.Path, backup); err != nil && !os.IsNotExist(err) {
+		// 重命名失败时继续追加写入原文件
+		if openErr := r.open(); openErr != nil {
+			return openErr
+		}
+		return err
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+	return r.prune()
+}
+
+func (r *rotatingFile) prune() error {
+	if r.config.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := filepath.Glob(r.config.Path + ".*")
+	if err != nil {
+		return err
+	}
+	if len(backups) <= r.config.MaxBackups {
+		return nil
+	}
+	sort.Strings(backups)
+	for _, old := range backups[:len(backups)-r.config.MaxBackups] {
+		if err := os.Remove(old); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *rotatingFile) Sync() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Sync()
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
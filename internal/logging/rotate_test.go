@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFileRotatesAndPrunes(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      FileConfig
+		step        time.Duration
+		writes      int
+		wantBackups int
+	}{
+		{
+			name:        "by size",
+			config:      FileConfig{MaxSizeMB: 1, MaxBackups: 2},
+			step:        time.Millisecond,
+			writes:      5, // 每次写入 600KB，第 2 次起每次都轮转
+			wantBackups: 2,
+		},
+		{
+			name:        "by interval",
+			config:      FileConfig{RotateInterval: time.Hour},
+			step:        30 * time.Minute,
+			writes:      4, // 第 2、4 次写入时距打开已满一小时
+			wantBackups: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.config
+			cfg.Path = filepath.Join(t.TempDir(), "logs", "voicebot.log")
+			r, err := newRotatingFile(cfg)
+			if err != nil {
+				t.Fatalf("newRotatingFile() error = %v", err)
+			}
+			defer r.Close()
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			r.now = func() time.Time { return now }
+			r.openedAt = now
+
+			line := []byte(strings.Repeat("x", 600*1024) + "\n")
+			if tt.config.MaxSizeMB == 0 {
+				line = []byte("hello\n")
+			}
+			for i := 0; i < tt.writes; i++ {
+				now = now.Add(tt.step)
+				if _, err := r.Write(line); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+
+			backups, _ := filepath.Glob(cfg.Path + ".*")
+			if len(backups) != tt.wantBackups {
+				t.Fatalf("backups = %v, want %d", backups, tt.wantBackups)
+			}
+			info, err := os.Stat(cfg.Path)
+			if err != nil || info.Size() != int64(len(line)) {
+				t.Fatalf("current file size = %v (%v), want one line", info.Size(), err)
+			}
+		})
+	}
+}
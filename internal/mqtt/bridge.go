@@ -0,0 +1,183 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/voicebot"
+)
+
+// Bot 桥接使用的编排器接口（voicebot.Orchestrator 的子集）
+type Bot interface {
+	Subscribe(eventType voicebot.EventType, handler voicebot.EventHandler)
+	// Announce 播报 SayTopics 收到的文本
+	Announce(text string) bool
+	// SubmitText AskTopics 收到的文本作为一轮用户输入交给 Agent
+	SubmitText(text string) bool
+}
+
+// BridgeConfig 桥接配置
+type BridgeConfig struct {
+	Client ClientConfig
+	// EventTopic 对话事件的主题前缀，发布到 <EventTopic>/state、/user、/tool、/mic、/activity；为空时不发布
+	EventTopic string
+	SayTopics  []string // 消息内容直接播报（如“门铃响了”）
+	AskTopics  []string // 消息内容作为一轮用户输入交给 Agent
+	// MaxBackoff 断线重连的最长等待，<=0 时使用 30 秒
+	MaxBackoff time.Duration
+}
+
+// Bridge 把对话事件发布到 MQTT，并把订阅主题上的消息转成播报或对话轮次；断线时自动重连
+type Bridge struct {
+	bot    Bot
+	config BridgeConfig
+
+	mu     sync.Mutex
+	client *Client // 未连接时为 nil，期间的事件直接丢弃
+}
+
+// NewBridge 创建桥接并订阅编排器事件，连接在 Run 中建立
+func NewBridge(bot Bot, cfg BridgeConfig) *Bridge {
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 30 * time.Second
+	}
+	b := &Bridge{bot: bot, config: cfg}
+	if cfg.EventTopic != "" {
+		bot.Subscribe(voicebot.EventTypeStateChanged, b.onEvent)
+		bot.Subscribe(voicebot.EventTypeASRFinal, b.onEvent)
+		bot.Subscribe(voicebot.EventTypeToolResult, b.onEvent)
+		bot.Subscribe(voicebot.EventTypeMicMuted, b.onEvent)
+		bot.Subscribe(voicebot.EventTypeActivity, b.onEvent)
+	}
+	return b
+}
+
+// Run 连接 Broker 并处理消息，断线后按指数退避重连，直到 ctx 结束
+func (b *Bridge) Run(ctx context.Context) {
+	backoff := time.Second
+	for ctx.Err() == nil {
+		connected, err := b.session(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if connected {
+			backoff = time.Second
+		}
+		logging.Warnf("MQTT: connection to %s lost, retrying in %v: %v", b.config.Client.Broker, backoff, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, b.config.MaxBackoff)
+	}
+}
+
+// session 建立一次连接并读取到断开为止，connected 表示握手是否成功
+func (b *Bridge) session(ctx context.Context) (connected bool, err error) {
+	client, err := Dial(ctx, b.config.Client)
+	if err != nil {
+		return false, err
+	}
+	defer client.Close()
+	topics := append(append([]string(nil), b.config.SayTopics...), b.config.AskTopics...)
+	if err := client.Subscribe(topics...); err != nil {
+		return true, err
+	}
+	logging.Infof("MQTT: connected to %s (say=%v, ask=%v)", b.config.Client.Broker, b.config.SayTopics, b.config.AskTopics)
+
+	b.setClient(client)
+	defer b.setClient(nil)
+	return true, client.Run(ctx, b.onMessage)
+}
+
+func (b *Bridge) setClient(client *Client) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.client = client
+}
+
+// onMessage 按主题把消息转成播报或对话轮次
+func (b *Bridge) onMessage(msg Message) {
+	text := messageText(msg.Payload)
+	if text == "" {
+		return
+	}
+	for _, filter := range b.config.SayTopics {
+		if TopicMatches(filter, msg.Topic) {
+			logging.Infof("MQTT: announce from %s: %s", msg.Topic, text)
+			b.bot.Announce(text)
+			return
+		}
+	}
+	for _, filter := range b.config.AskTopics {
+		if TopicMatches(filter, msg.Topic) {
+			logging.Infof("MQTT: turn from %s: %s", msg.Topic, text)
+			b.bot.SubmitText(text)
+			return
+		}
+	}
+}
+
+// messageText 消息内容可以是纯文本，也可以是 {"text": "..."}
+func messageText(payload []byte) string {
+	var body struct {
+		Text string `json:"text"`
+	}
+	if json.Unmarshal(payload, &body) == nil && body.Text != "" {
+		return strings.TrimSpace(body.Text)
+	}
+	return strings.TrimSpace(string(payload))
+}
+
+func (b *Bridge) onEvent(event voicebot.Event) {
+	var topic string
+	var payload interface{}
+	switch e := event.(type) {
+	case *voicebot.StateChangedEvent:
+		topic = "state"
+		payload = map[string]string{"state": e.NewState.String(), "previous": e.OldState.String()}
+	case *voicebot.ASRFinalEvent:
+		if e.Attempt > 0 {
+			return
+		}
+		topic = "user"
+		payload = map[string]string{"text": e.Text}
+	case *voicebot.ToolResultEvent:
+		result := map[string]interface{}{"tool": e.Tool, "args": e.Args, "duration_ms": e.Duration.Milliseconds()}
+		if e.Error != nil {
+			result["error"] = e.Error.Error()
+		}
+		topic, payload = "tool", result
+	case *voicebot.MicMutedEvent:
+		topic = "mic"
+		payload = map[string]bool{"muted": e.Muted}
+	case *voicebot.ActivityEvent:
+		topic = "activity"
+		payload = map[string]string{"activity": string(e.Activity)}
+	default:
+		return
+	}
+	b.publish(topic, payload)
+}
+
+func (b *Bridge) publish(topic string, payload interface{}) {
+	b.mu.Lock()
+	client := b.client
+	b.mu.Unlock()
+	if client == nil {
+		return
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		logging.Errorf("MQTT: marshal %s event: %v", topic, err)
+		return
+	}
+	if err := client.Publish(b.config.EventTopic+"/"+topic, data, false); err != nil {
+		logging.Warnf("MQTT: publish %s: %v", topic, err)
+	}
+}
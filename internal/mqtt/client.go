@@ -0,0 +1,379 @@
+// Package mqtt 最小的 MQTT 3.1.1 客户端（只支持 QoS 0）以及对话事件与 IoT 消息的桥接
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MQTT 控制报文类型（固定报头高 4 位）
+const (
+	packetConnect     = 1
+	packetConnAck     = 2
+	packetPublish     = 3
+	packetPubAck      = 4
+	packetSubscribe   = 8
+	packetSubAck      = 9
+	packetPingReq     = 12
+	packetPingResp    = 13
+	packetDisconnect  = 14
+	maxRemainingBytes = 268435455
+)
+
+// ClientConfig 客户端配置
+type ClientConfig struct {
+	// Broker 地址：tcp://host:1883（或 mqtt://），TLS 使用 ssl://、tls:// 或 mqtts://（默认端口 8883）
+	Broker    string
+	ClientID  string
+	Username  string
+	Password  string
+	KeepAlive time.Duration // <=0 时使用 60 秒
+}
+
+// Message 收到的消息
+type Message struct {
+	Topic   string
+	Payload []byte
+}
+
+// Client MQTT 客户端连接；Publish / Subscribe 可并发调用，消息由 Run 读取
+type Client struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	keepAlive time.Duration
+
+	mu       sync.Mutex // 保护写入和 packetID
+	packetID uint16
+}
+
+// Dial 连接 Broker 并完成 CONNECT / CONNACK 握手（clean session）
+func Dial(ctx context.Context, cfg ClientConfig) (*Client, error) {
+	addr, useTLS, err := parseBroker(cfg.Broker)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	var conn net.Conn
+	if useTLS {
+		host, _, _ := net.SplitHostPort(addr)
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host}}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keepAlive := cfg.KeepAlive
+	if keepAlive <= 0 {
+		keepAlive = 60 * time.Second
+	}
+	c := &Client{conn: conn, reader: bufio.NewReader(conn), keepAlive: keepAlive}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(10 * time.Second))
+	}
+	if err := c.connect(cfg); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	return c, nil
+}
+
+// parseBroker 解析 Broker 地址，返回 host:port 和是否使用 TLS
+func parseBroker(broker string) (string, bool, error) {
+	u, err := url.Parse(broker)
+	if err != nil || u.Host == "" {
+		return "", false, fmt.Errorf("invalid MQTT broker %q", broker)
+	}
+	useTLS := false
+	port := "1883"
+	switch u.Scheme {
+	case "tcp", "mqtt":
+	case "ssl", "tls", "mqtts":
+		useTLS = true
+		port = "8883"
+	default:
+		return "", false, fmt.Errorf("unsupported MQTT broker scheme %q", u.Scheme)
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+	return net.JoinHostPort(u.Hostname(), port), useTLS, nil
+}
+
+func (c *Client) connect(cfg ClientConfig) error {
+	var body []byte
+	body = appendString(body, "MQTT")
+	body = append(body, 4) // 协议级别 3.1.1
+	flags := byte(0x02)    // clean session
+	if cfg.Username != "" {
+		flags |= 0x80
+		if cfg.Password != "" {
+			flags |= 0x40
+		}
+	}
+	body = append(body, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(c.keepAlive/time.Second))
+	body = appendString(body, cfg.ClientID)
+	if cfg.Username != "" {
+		body = appendString(body, cfg.Username)
+		if cfg.Password != "" {
+			body = appendString(body, cfg.Password)
+		}
+	}
+	if err := c.writePacket(packetConnect<<4, body); err != nil {
+		return err
+	}
+
+	kind, payload, err := readPacket(c.reader)
+	if err != nil {
+		return fmt.Errorf("read CONNACK: %w", err)
+	}
+	if kind>>4 != packetConnAck || len(payload) < 2 {
+		return fmt.Errorf("unexpected packet %d, want CONNACK", kind>>4)
+	}
+	if code := payload[1]; code != 0 {
+		return fmt.Errorf("connection refused: %s", connAckReason(code))
+	}
+	return nil
+}
+
+func connAckReason(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "identifier rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad user name or password"
+	case 5:
+		return "not authorized"
+	default:
+		return fmt.Sprintf("code %d", code)
+	}
+}
+
+// Publish 以 QoS 0 发布消息
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	header := byte(packetPublish << 4)
+	if retain {
+		header |= 0x01
+	}
+	body := appendString(nil, topic)
+	body = append(body, payload...)
+	return c.writePacket(header, body)
+}
+
+// Subscribe 以 QoS 0 订阅主题（可使用 + / # 通配符），SUBACK 由 Run 处理
+func (c *Client) Subscribe(topics ...string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+	c.mu.Lock()
+	c.packetID++
+	if c.packetID == 0 {
+		c.packetID = 1
+	}
+	id := c.packetID
+	c.mu.Unlock()
+
+	body := binary.BigEndian.AppendUint16(nil, id)
+	for _, topic := range topics {
+		body = appendString(body, topic)
+		body = append(body, 0) // QoS 0
+	}
+	return c.writePacket(packetSubscribe<<4|0x02, body)
+}
+
+// Run 读取消息并按 KeepAlive 发送心跳，直到连接断开或 ctx 结束；handler 在读取协程中调用
+func (c *Client) Run(ctx context.Context, handler func(Message)) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(c.keepAlive / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ctx.Done():
+				c.conn.Close()
+				return
+			case <-ticker.C:
+				if err := c.writePacket(packetPingReq<<4, nil); err != nil {
+					c.conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		// 超过 1.5 倍 KeepAlive 没有收到任何报文（包括 PINGRESP）视为连接已断开
+		c.conn.SetReadDeadline(time.Now().Add(c.keepAlive * 3 / 2))
+		kind, payload, err := readPacket(c.reader)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		switch kind >> 4 {
+		case packetPublish:
+			msg, id, err := parsePublish(kind, payload)
+			if err != nil {
+				return err
+			}
+			if id != 0 {
+				// Broker 按 QoS 1 下发时确认
+				if err := c.writePacket(packetPubAck<<4, binary.BigEndian.AppendUint16(nil, id)); err != nil {
+					return err
+				}
+			}
+			handler(msg)
+		case packetSubAck:
+			for _, code := range payload[min(2, len(payload)):] {
+				if code == 0x80 {
+					return errors.New("subscription rejected by broker")
+				}
+			}
+		case packetPingResp:
+		}
+	}
+}
+
+// Close 发送 DISCONNECT 并关闭连接
+func (c *Client) Close() error {
+	c.writePacket(packetDisconnect<<4, nil)
+	return c.conn.Close()
+}
+
+func (c *Client) writePacket(header byte, body []byte) error {
+	if len(body) > maxRemainingBytes {
+		return errors.New("MQTT packet too large")
+	}
+	packet := append([]byte{header}, encodeLength(len(body))...)
+	packet = append(packet, body...)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	_, err := c.conn.Write(packet)
+	return err
+}
+
+// parsePublish 解析 PUBLISH，QoS > 0 时返回需要确认的报文 ID
+func parsePublish(header byte, payload []byte) (Message, uint16, error) {
+	topic, rest, err := readString(payload)
+	if err != nil {
+		return Message{}, 0, err
+	}
+	var id uint16
+	if qos := (header >> 1) & 0x03; qos > 0 {
+		if len(rest) < 2 {
+			return Message{}, 0, errors.New("truncated PUBLISH packet id")
+		}
+		id = binary.BigEndian.Uint16(rest)
+		rest = rest[2:]
+	}
+	return Message{Topic: topic, Payload: rest}, id, nil
+}
+
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		if i == 3 {
+			return 0, nil, errors.New("malformed MQTT remaining length")
+		}
+		multiplier *= 128
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return header, payload, nil
+}
+
+func encodeLength(n int) []byte {
+	var out []byte
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if n == 0 {
+			return out
+		}
+	}
+}
+
+func appendString(dst []byte, s string) []byte {
+	dst = binary.BigEndian.AppendUint16(dst, uint16(len(s)))
+	return append(dst, s...)
+}
+
+func readString(data []byte) (string, []byte, error) {
+	if len(data) < 2 {
+		return "", nil, errors.New("truncated MQTT string")
+	}
+	n := int(binary.BigEndian.Uint16(data))
+	if len(data) < 2+n {
+		return "", nil, errors.New("truncated MQTT string")
+	}
+	return string(data[2 : 2+n]), data[2+n:], nil
+}
+
+// TopicMatches 判断主题是否匹配订阅过滤器（支持 + 和 # 通配符）
+func TopicMatches(filter, topic string) bool {
+	for {
+		fi, ti := indexSlash(filter), indexSlash(topic)
+		fLevel, tLevel := filter[:fi], topic[:ti]
+		switch {
+		case fLevel == "#":
+			return true
+		case fLevel != "+" && fLevel != tLevel:
+			return false
+		}
+		filterDone, topicDone := fi == len(filter), ti == len(topic)
+		if filterDone || topicDone {
+			// "a/#" 也匹配 "a"
+			return filterDone == topicDone || (topicDone && filter[fi+1:] == "#")
+		}
+		filter, topic = filter[fi+1:], topic[ti+1:]
+	}
+}
+
+func indexSlash(s string) int {
+	if i := strings.IndexByte(s, '/'); i >= 0 {
+		return i
+	}
+	return len(s)
+}
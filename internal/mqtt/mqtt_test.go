@@ -0,0 +1,266 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/voicebot"
+)
+
+func TestTopicMatches(t *testing.T) {
+	tests := []struct {
+		filter, topic string
+		want          bool
+	}{
+		{"home/doorbell", "home/doorbell", true},
+		{"home/doorbell", "home/doorbell/front", false},
+		{"home/+", "home/doorbell", true},
+		{"home/+", "home/doorbell/front", false},
+		{"home/+/front", "home/doorbell/front", true},
+		{"home/#", "home/doorbell/front", true},
+		{"home/#", "home", true},
+		{"#", "anything/at/all", true},
+		{"home/doorbell", "home", false},
+		{"office/#", "home/doorbell", false},
+	}
+	for _, tt := range tests {
+		if got := TopicMatches(tt.filter, tt.topic); got != tt.want {
+			t.Errorf("TopicMatches(%q, %q) = %v, want %v", tt.filter, tt.topic, got, tt.want)
+		}
+	}
+}
+
+func TestParseBroker(t *testing.T) {
+	tests := []struct {
+		broker  string
+		addr    string
+		tls     bool
+		wantErr bool
+	}{
+		{"tcp://localhost", "localhost:1883", false, false},
+		{"mqtt://10.0.0.2:1884", "10.0.0.2:1884", false, false},
+		{"ssl://broker.example.com", "broker.example.com:8883", true, false},
+		{"mqtts://broker.example.com:443", "broker.example.com:443", true, false},
+		{"ws://broker", "", false, true},
+		{"localhost:1883", "", false, true},
+	}
+	for _, tt := range tests {
+		addr, useTLS, err := parseBroker(tt.broker)
+		if (err != nil) != tt.wantErr || addr != tt.addr || useTLS != tt.tls {
+			t.Errorf("parseBroker(%q) = %q, %v, %v; want %q, %v, err=%v", tt.broker, addr, useTLS, err, tt.addr, tt.tls, tt.wantErr)
+		}
+	}
+}
+
+func TestMessageText(t *testing.T) {
+	tests := map[string]string{
+		"门铃响了":              "门铃响了",
+		`{"text":" 门铃响了 "}`: "门铃响了",
+		`{"other":1}`:       `{"other":1}`,
+		"  ":                "",
+	}
+	for payload, want := range tests {
+		if got := messageText([]byte(payload)); got != want {
+			t.Errorf("messageText(%q) = %q, want %q", payload, got, want)
+		}
+	}
+}
+
+// fakeBroker 接受一个连接，记录 CONNECT 和 SUBSCRIBE，转发 PUBLISH
+type fakeBroker struct {
+	listener  net.Listener
+	mu        sync.Mutex
+	conn      net.Conn
+	connect   []byte
+	subscribe []byte
+	published chan Message
+	ready     chan struct{}
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	b := &fakeBroker{listener: listener, published: make(chan Message, 8), ready: make(chan struct{})}
+	go b.serve()
+	t.Cleanup(func() { listener.Close() })
+	return b
+}
+
+func (b *fakeBroker) url() string { return "tcp://" + b.listener.Addr().String() }
+
+func (b *fakeBroker) serve() {
+	conn, err := b.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		kind, payload, err := readPacket(reader)
+		if err != nil {
+			return
+		}
+		switch kind >> 4 {
+		case packetConnect:
+			b.mu.Lock()
+			b.conn, b.connect = conn, payload
+			b.mu.Unlock()
+			conn.Write([]byte{packetConnAck << 4, 2, 0, 0})
+		case packetSubscribe:
+			b.mu.Lock()
+			b.subscribe = payload
+			b.mu.Unlock()
+			conn.Write([]byte{packetSubAck << 4, 3, payload[0], payload[1], 0})
+			close(b.ready)
+		case packetPublish:
+			msg, _, _ := parsePublish(kind, payload)
+			b.published <- msg
+		case packetPingReq:
+			conn.Write([]byte{packetPingResp << 4, 0})
+		}
+	}
+}
+
+// send 向客户端下发一条消息
+func (b *fakeBroker) send(topic, payload string) {
+	body := appendString(nil, topic)
+	body = append(body, payload...)
+	packet := append([]byte{packetPublish << 4}, encodeLength(len(body))...)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.conn.Write(append(packet, body...))
+}
+
+func TestClientConnect(t *testing.T) {
+	broker := newFakeBroker(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	client, err := Dial(ctx, ClientConfig{Broker: broker.url(), ClientID: "orion", Username: "bot", Password: "secret", KeepAlive: 30 * time.Second})
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer client.Close()
+
+	broker.mu.Lock()
+	connect := broker.connect
+	broker.mu.Unlock()
+	name, rest, _ := readString(connect)
+	if name != "MQTT" || rest[0] != 4 || rest[1] != 0xc2 || rest[2] != 0 || rest[3] != 30 {
+		t.Fatalf("CONNECT header = %q %v", name, rest[:4])
+	}
+	id, rest, _ := readString(rest[4:])
+	user, rest, _ := readString(rest)
+	pass, _, _ := readString(rest)
+	if id != "orion" || user != "bot" || pass != "secret" {
+		t.Fatalf("CONNECT payload = %q %q %q", id, user, pass)
+	}
+}
+
+// fakeBot 记录播报和注入的文本
+type fakeBot struct {
+	mu        sync.Mutex
+	handlers  map[voicebot.EventType][]voicebot.EventHandler
+	announced []string
+	turns     []string
+	done      chan struct{}
+}
+
+func (b *fakeBot) Subscribe(eventType voicebot.EventType, handler voicebot.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+func (b *fakeBot) Announce(text string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.announced = append(b.announced, text)
+	return true
+}
+
+func (b *fakeBot) SubmitText(text string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.turns = append(b.turns, text)
+	close(b.done)
+	return true
+}
+
+func (b *fakeBot) publish(event voicebot.Event) {
+	b.mu.Lock()
+	handlers := b.handlers[event.Type()]
+	b.mu.Unlock()
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+func TestBridge(t *testing.T) {
+	broker := newFakeBroker(t)
+	bot := &fakeBot{handlers: make(map[voicebot.EventType][]voicebot.EventHandler), done: make(chan struct{})}
+	bridge := NewBridge(bot, BridgeConfig{
+		Client:     ClientConfig{Broker: broker.url(), ClientID: "orion"},
+		EventTopic: "orion/events",
+		SayTopics:  []string{"home/+/say"},
+		AskTopics:  []string{"home/ask"},
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bridge.Run(ctx)
+
+	select {
+	case <-broker.ready:
+	case <-time.After(2 * time.Second):
+		t.Fatal("bridge did not subscribe")
+	}
+	broker.mu.Lock()
+	subscribe := broker.subscribe
+	broker.mu.Unlock()
+	first, rest, _ := readString(subscribe[2:])
+	second, _, _ := readString(rest[1:])
+	if first != "home/+/say" || second != "home/ask" {
+		t.Fatalf("subscribed to %q, %q", first, second)
+	}
+
+	broker.send("home/door/say", "门铃响了")
+	broker.send("home/ask", `{"text":"明天天气怎么样"}`)
+	select {
+	case <-bot.done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected injected turn")
+	}
+	bot.mu.Lock()
+	announced, turns := bot.announced, bot.turns
+	bot.mu.Unlock()
+	if !reflect.DeepEqual(announced, []string{"门铃响了"}) || !reflect.DeepEqual(turns, []string{"明天天气怎么样"}) {
+		t.Fatalf("announced = %v, turns = %v", announced, turns)
+	}
+
+	bot.publish(voicebot.NewStateChangedEvent(voicebot.StateIdle, voicebot.StateListening))
+	select {
+	case msg := <-broker.published:
+		if msg.Topic != "orion/events/state" || !strings.Contains(string(msg.Payload), `"state":"Listening"`) {
+			t.Fatalf("published %s %s", msg.Topic, msg.Payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected state event")
+	}
+
+	bot.publish(voicebot.NewActivityEvent(voicebot.ActivitySpeakingStarted))
+	select {
+	case msg := <-broker.published:
+		if msg.Topic != "orion/events/activity" || string(msg.Payload) != `{"activity":"speaking_started"}` {
+			t.Fatalf("published %s %s", msg.Topic, msg.Payload)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected activity event")
+	}
+}
@@ -0,0 +1,191 @@
+// Package news 新闻源：抓取 RSS 2.0 / RSS 1.0 / Atom 订阅，按时间合并各新闻源的条目，并把正文整理成适合播报的摘要
+package news
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+const (
+	defaultCacheTTL     = 10 * time.Minute
+	defaultSummaryChars = 120
+	// maxFeedBytes 单个订阅最多读取的字节数
+	maxFeedBytes = 4 << 20
+)
+
+// Feed 一个新闻源
+type Feed struct {
+	Name string
+	URL  string
+}
+
+// Item 一条新闻，Summary 为去掉 HTML 并截断后的摘要
+type Item struct {
+	Source    string
+	Title     string
+	Link      string
+	Summary   string
+	Published time.Time // 订阅中没有或无法解析时为零值
+}
+
+// Config 新闻源配置
+type Config struct {
+	Feeds        []Feed
+	Client       *http.Client  // nil 时使用带 15 秒超时的客户端
+	CacheTTL     time.Duration // 订阅内容缓存时间，<=0 时为 10 分钟
+	SummaryChars int           // 摘要最多字符数，<=0 时为 120
+}
+
+// ErrUnknownFeed 指定的新闻源不在配置中
+var ErrUnknownFeed = errors.New("news: unknown feed")
+
+// Reader 抓取并缓存新闻源
+type Reader struct {
+	config Config
+
+	mu    sync.Mutex
+	cache map[string]cachedFeed
+}
+
+type cachedFeed struct {
+	items   []Item
+	fetched time.Time
+}
+
+// NewReader 创建新闻源读取器
+func NewReader(config Config) *Reader {
+	if config.Client == nil {
+		config.Client = &http.Client{Timeout: 15 * time.Second}
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaultCacheTTL
+	}
+	if config.SummaryChars <= 0 {
+		config.SummaryChars = defaultSummaryChars
+	}
+	return &Reader{config: config, cache: make(map[string]cachedFeed)}
+}
+
+// FeedNames 返回配置的新闻源名称
+func (r *Reader) FeedNames() []string {
+	names := make([]string, 0, len(r.config.Feeds))
+	for _, feed := range r.config.Feeds {
+		names = append(names, feed.Name)
+	}
+	return names
+}
+
+// Latest 返回最新的 limit 条新闻（按发布时间倒序，标题相同的只保留一条）；
+// source 为空时汇总全部新闻源。部分新闻源抓取失败时只记录警告，全部失败才返回错误
+func (r *Reader) Latest(ctx context.Context, source string, limit int) ([]Item, error) {
+	feeds := r.config.Feeds
+	if source != "" {
+		feeds = nil
+		for _, feed := range r.config.Feeds {
+			if strings.EqualFold(feed.Name, source) {
+				feeds = append(feeds, feed)
+			}
+		}
+		if len(feeds) == 0 {
+			return nil, fmt.Errorf("%w %q", ErrUnknownFeed, source)
+		}
+	}
+
+	results := make([][]Item, len(feeds))
+	errs := make([]error, len(feeds))
+	var wg sync.WaitGroup
+	for i, feed := range feeds {
+		wg.Add(1)
+		go func(i int, feed Feed) {
+			defer wg.Done()
+			results[i], errs[i] = r.feedItems(ctx, feed)
+		}(i, feed)
+	}
+	wg.Wait()
+
+	var items []Item
+	var failed []error
+	for i, err := range errs {
+		if err != nil {
+			logging.WarnfCtx(ctx, "News: fetch %s failed: %v", feeds[i].Name, err)
+			failed = append(failed, fmt.Errorf("%s: %w", feeds[i].Name, err))
+			continue
+		}
+		items = append(items, results[i]...)
+	}
+	if len(failed) == len(feeds) && len(feeds) > 0 {
+		return nil, errors.Join(failed...)
+	}
+
+	// 没有发布时间的条目排在有时间的之后，同一新闻源内保持订阅中的顺序
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].Published, items[j].Published
+		if a.IsZero() || b.IsZero() {
+			return !a.IsZero() && b.IsZero()
+		}
+		return a.After(b)
+	})
+	seen := make(map[string]bool, len(items))
+	latest := items[:0]
+	for _, item := range items {
+		key := strings.ToLower(item.Title)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		latest = append(latest, item)
+	}
+	if limit > 0 && len(latest) > limit {
+		latest = latest[:limit]
+	}
+	return latest, nil
+}
+
+// feedItems 返回新闻源的条目，缓存未过期时不重新抓取
+func (r *Reader) feedItems(ctx context.Context, feed Feed) ([]Item, error) {
+	r.mu.Lock()
+	cached, ok := r.cache[feed.URL]
+	r.mu.Unlock()
+	if ok && time.Since(cached.fetched) < r.config.CacheTTL {
+		return cached.items, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feed.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/xml;q=0.9, */*;q=0.8")
+	resp, err := r.config.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("http %d", resp.StatusCode)
+	}
+	items, err := parseFeed(io.LimitReader(resp.Body, maxFeedBytes))
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Source = feed.Name
+		items[i].Summary = Summarize(items[i].Summary, r.config.SummaryChars)
+		if items[i].Summary == items[i].Title {
+			items[i].Summary = ""
+		}
+	}
+
+	r.mu.Lock()
+	r.cache[feed.URL] = cachedFeed{items: items, fetched: time.Now()}
+	r.mu.Unlock()
+	return items, nil
+}
@@ -0,0 +1,189 @@
+package news
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const rssFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/">
+<channel>
+  <title>科技</title>
+  <item>
+    <title>芯片出口数据公布</title>
+    <link>https://example.com/a</link>
+    <description><![CDATA[<p>九月芯片出口同比增长<b>12%</b>。</p><script>track()</script><p>分析认为需求回暖。</p>]]></description>
+    <pubDate>Tue, 15 Oct 2024 08:00:00 +0800</pubDate>
+  </item>
+  <item>
+    <title>无摘要的新闻</title>
+    <link>https://example.com/b</link>
+    <pubDate>Mon, 14 Oct 2024 20:00:00 +0800</pubDate>
+  </item>
+</channel>
+</rss>`
+
+const atomFeed = `<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>World</title>
+  <entry>
+    <title>Markets rally &amp; bonds slip</title>
+    <link rel="alternate" href="https://example.org/markets"/>
+    <summary type="html">&lt;p&gt;Stocks rose on Tuesday.&lt;/p&gt;</summary>
+    <updated>2024-10-15T01:30:00Z</updated>
+  </entry>
+  <entry>
+    <title>芯片出口数据公布</title>
+    <updated>2024-10-14T00:00:00Z</updated>
+  </entry>
+</feed>`
+
+func TestParseFeed(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantTitle []string
+		wantLink  string
+		wantErr   bool
+	}{
+		{name: "rss", data: rssFeed, wantTitle: []string{"芯片出口数据公布", "无摘要的新闻"}, wantLink: "https://example.com/a"},
+		{name: "atom", data: atomFeed, wantTitle: []string{"Markets rally & bonds slip", "芯片出口数据公布"}, wantLink: "https://example.org/markets"},
+		{name: "rss 1.0", data: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel><title>x</title></channel><item><title>RDF 新闻</title><link>https://example.net/1</link><dc:date>2024-10-15T09:00:00+08:00</dc:date></item></rdf:RDF>`,
+			wantTitle: []string{"RDF 新闻"}, wantLink: "https://example.net/1"},
+		{name: "html page", data: `<html><body>not a feed</body></html>`, wantErr: true},
+		{name: "gbk", data: `<?xml version="1.0" encoding="GBK"?><rss><channel></channel></rss>`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := parseFeed(strings.NewReader(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseFeed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(items) != len(tt.wantTitle) {
+				t.Fatalf("parseFeed() = %+v, want %d items", items, len(tt.wantTitle))
+			}
+			for i, title := range tt.wantTitle {
+				if items[i].Title != title {
+					t.Errorf("item %d title = %q, want %q", i, items[i].Title, title)
+				}
+				if items[i].Published.IsZero() {
+					t.Errorf("item %d has no published time", i)
+				}
+			}
+			if items[0].Link != tt.wantLink {
+				t.Errorf("link = %q, want %q", items[0].Link, tt.wantLink)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxChars int
+		want     string
+	}{
+		{"strip tags", `<p>九月芯片出口同比增长<b>12%</b>。</p><p>需求回暖。</p>`, 0, "九月芯片出口同比增长12%。 需求回暖。"},
+		{"drop script and style", `<style>p{color:red}</style>正文<script>alert("x")</script>结束`, 0, "正文结束"},
+		{"entities", `Tom &amp; Jerry&nbsp;&lt;3`, 0, "Tom & Jerry <3"},
+		{"cut at sentence", "第一句话说完了。第二句话很长很长很长很长", 12, "第一句话说完了。"},
+		{"cut english sentence", "Stocks rose. Bonds fell sharply today", 20, "Stocks rose."},
+		{"ellipsis without sentence end", "一二三四五六七八九十", 5, "一二三四五…"},
+		{"short text kept", "短新闻", 10, "短新闻"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Summarize(tt.text, tt.maxChars); got != tt.want {
+				t.Errorf("Summarize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReaderLatest(t *testing.T) {
+	var rssRequests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rss", func(w http.ResponseWriter, r *http.Request) {
+		rssRequests.Add(1)
+		io.WriteString(w, rssFeed)
+	})
+	mux.HandleFunc("/atom", func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, atomFeed) })
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusBadGateway) })
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	reader := NewReader(Config{
+		Feeds: []Feed{
+			{Name: "科技", URL: server.URL + "/rss"},
+			{Name: "World", URL: server.URL + "/atom"},
+			{Name: "坏源", URL: server.URL + "/broken"},
+		},
+		Client:       server.Client(),
+		SummaryChars: 24,
+	})
+	ctx := context.Background()
+
+	items, err := reader.Latest(ctx, "", 0)
+	if err != nil {
+		t.Fatalf("Latest() error = %v", err)
+	}
+	// 按发布时间倒序合并，Atom 中重复标题的条目被去掉，坏源只记录警告
+	wantTitles := []string{"Markets rally & bonds slip", "芯片出口数据公布", "无摘要的新闻"}
+	if len(items) != len(wantTitles) {
+		t.Fatalf("Latest() = %+v", items)
+	}
+	for i, title := range wantTitles {
+		if items[i].Title != title {
+			t.Errorf("item %d = %q, want %q", i, items[i].Title, title)
+		}
+	}
+	if items[0].Source != "World" || items[0].Summary != "Stocks rose on Tuesday." {
+		t.Errorf("atom item = %+v", items[0])
+	}
+	if items[1].Source != "科技" || items[1].Summary != "九月芯片出口同比增长12%。 分析认为需求回暖。" {
+		t.Errorf("rss item = %+v", items[1])
+	}
+
+	items, err = reader.Latest(ctx, "科技", 1)
+	if err != nil || len(items) != 1 || items[0].Title != "芯片出口数据公布" {
+		t.Errorf("Latest(科技, 1) = %+v, %v", items, err)
+	}
+	if got := rssRequests.Load(); got != 1 {
+		t.Errorf("rss fetched %d times, want cached", got)
+	}
+
+	if _, err := reader.Latest(ctx, "体育", 5); !errors.Is(err, ErrUnknownFeed) {
+		t.Errorf("Latest(unknown) error = %v", err)
+	}
+	if _, err := reader.Latest(ctx, "坏源", 5); err == nil {
+		t.Error("Latest(broken) want error")
+	}
+}
+
+func TestReaderCacheExpires(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		io.WriteString(w, rssFeed)
+	}))
+	defer server.Close()
+
+	reader := NewReader(Config{Feeds: []Feed{{Name: "科技", URL: server.URL}}, Client: server.Client(), CacheTTL: time.Millisecond})
+	reader.Latest(context.Background(), "", 1)
+	time.Sleep(5 * time.Millisecond)
+	reader.Latest(context.Background(), "", 1)
+	if got := requests.Load(); got != 2 {
+		t.Errorf("requests = %d, want 2 after cache expiry", got)
+	}
+}
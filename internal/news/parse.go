@@ -0,0 +1,185 @@
+package news
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// feedDocument 同时覆盖 RSS 2.0（rss/channel/item）、RSS 1.0（rdf:RDF/item）和 Atom（feed/entry）
+type feedDocument struct {
+	XMLName      xml.Name
+	ChannelItems []feedEntry `xml:"channel>item"`
+	Items        []feedEntry `xml:"item"`
+	Entries      []feedEntry `xml:"entry"`
+}
+
+type feedEntry struct {
+	Title       string     `xml:"title"`
+	Links       []feedLink `xml:"link"`
+	Description string     `xml:"description"`
+	Encoded     string     `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Summary     string     `xml:"summary"`
+	Content     string     `xml:"content"`
+	PubDate     string     `xml:"pubDate"`
+	Date        string     `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Published   string     `xml:"published"`
+	Updated     string     `xml:"updated"`
+}
+
+// feedLink RSS 的 <link>地址</link> 或 Atom 的 <link rel="alternate" href="..."/>
+type feedLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Text string `xml:",chardata"`
+}
+
+// feedTimeLayouts RSS（RFC 822 及常见变体）与 Atom（RFC 3339）的时间格式
+var feedTimeLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"Mon, 02 Jan 2006 15:04 -0700",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// parseFeed 解析订阅内容；只支持 UTF-8 编码
+func parseFeed(r io.Reader) ([]Item, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	decoder.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) {
+		switch strings.ToLower(charset) {
+		case "utf-8", "utf8", "us-ascii":
+			return input, nil
+		}
+		return nil, fmt.Errorf("unsupported charset %q", charset)
+	}
+	var doc feedDocument
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid feed: %w", err)
+	}
+	switch doc.XMLName.Local {
+	case "rss", "RDF", "feed":
+	default:
+		return nil, fmt.Errorf("invalid feed: unexpected root element <%s>", doc.XMLName.Local)
+	}
+
+	entries := append(append(doc.ChannelItems, doc.Items...), doc.Entries...)
+	items := make([]Item, 0, len(entries))
+	for _, entry := range entries {
+		title := collapseSpace(html.UnescapeString(entry.Title))
+		if title == "" {
+			continue
+		}
+		items = append(items, Item{
+			Title:     title,
+			Link:      entry.link(),
+			Summary:   firstNonEmpty(entry.Description, entry.Summary, entry.Encoded, entry.Content),
+			Published: parseFeedTime(firstNonEmpty(entry.PubDate, entry.Published, entry.Updated, entry.Date)),
+		})
+	}
+	return items, nil
+}
+
+func (e feedEntry) link() string {
+	for _, link := range e.Links {
+		if link.Href != "" && (link.Rel == "" || link.Rel == "alternate") {
+			return link.Href
+		}
+	}
+	for _, link := range e.Links {
+		if text := strings.TrimSpace(link.Text); text != "" {
+			return text
+		}
+	}
+	return ""
+}
+
+func parseFeedTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Summarize 把 HTML 正文整理成适合播报的摘要：去掉标签（及 script / style 内容）、反转义实体、合并空白，
+// 超过 maxChars 个字符时在最后一个句末标点处截断，没有合适的句末时截断并加省略号
+func Summarize(text string, maxChars int) string {
+	text = collapseSpace(html.UnescapeString(stripTags(text)))
+	if maxChars <= 0 || utf8.RuneCountInString(text) <= maxChars {
+		return text
+	}
+	runes := []rune(text)[:maxChars]
+	for i := len(runes) - 1; i >= maxChars/2; i-- {
+		if strings.ContainsRune("。！？!?；;", runes[i]) || (runes[i] == '.' && (i+1 == len(runes) || unicode.IsSpace(runes[i+1]))) {
+			return string(runes[:i+1])
+		}
+	}
+	return strings.TrimSpace(string(runes)) + "…"
+}
+
+// blockTags 换成空格的块级标签，其余（b、a、span 等）直接去掉，避免在中文词语中间插入空格
+var blockTags = map[string]bool{
+	"p": true, "br": true, "div": true, "li": true, "ul": true, "ol": true, "tr": true, "td": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true, "blockquote": true, "hr": true,
+}
+
+// stripTags 去掉 HTML 标签，script / style 的内容一并去掉
+func stripTags(text string) string {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(text, '<')
+		if start < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+		b.WriteString(text[:start])
+		end := strings.IndexByte(text[start:], '>')
+		if end < 0 {
+			return b.String()
+		}
+		raw := text[start+1 : start+end]
+		text = text[start+end+1:]
+		closing := strings.HasPrefix(raw, "/")
+		name := strings.ToLower(strings.TrimLeft(raw, "/"))
+		if i := strings.IndexAny(name, " \t\n/"); i >= 0 {
+			name = name[:i]
+		}
+		if !closing && (name == "script" || name == "style") {
+			if i := strings.Index(strings.ToLower(text), "</"+name); i >= 0 {
+				text = text[i:]
+			} else {
+				return b.String()
+			}
+		}
+		if blockTags[name] {
+			b.WriteByte(' ')
+		}
+	}
+}
+
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,360 @@
+package realtime
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// responseStream 一次 Process 期间转交过来的回复事件
+type responseStream struct {
+	events chan serverEvent
+	closed chan struct{} // 消费方已退出
+	failed chan struct{} // 连接断开，err 为原因
+
+	// 以下字段由 Session.mu 保护
+	id       string // 当前回复的 ID，response.created 之前为空
+	awaiting bool   // 已发送 response.create，尚未收到 response.created
+	active   bool   // 已收到 response.created，尚未收到 response.done
+
+	closeOnce, failOnce sync.Once
+	err                 error
+}
+
+func newResponseStream() *responseStream {
+	return &responseStream{
+		events: make(chan serverEvent, 256),
+		closed: make(chan struct{}),
+		failed: make(chan struct{}),
+	}
+}
+
+func (r *responseStream) deliver(event serverEvent) {
+	select {
+	case r.events <- event:
+	case <-r.closed:
+	}
+}
+
+func (r *responseStream) fail(err error) {
+	r.failOnce.Do(func() {
+		r.err = err
+		close(r.failed)
+	})
+}
+
+func (r *responseStream) close() {
+	r.closeOnce.Do(func() { close(r.closed) })
+}
+
+// Process 触发一轮回复：用户语音已由服务端 VAD 提交时直接请求回复，否则（文本输入、MQTT 等）
+// 先把 input 作为用户消息加入对话；ctx 取消（打断）时取消服务端回复
+func (s *Session) Process(ctx context.Context, input string) (<-chan agent.AgentEvent, error) {
+	if _, err := s.ensureConn(ctx); err != nil {
+		return nil, err
+	}
+	logging.InfofCtx(ctx, "Realtime: processing input: %s", input)
+
+	stream := newResponseStream()
+	s.mu.Lock()
+	previous := s.response
+	s.response = stream
+	audioTurn := s.pendingAudio > 0
+	s.pendingAudio = 0
+	cancelPrevious := previous != nil && (previous.awaiting || previous.active)
+	s.mu.Unlock()
+	if previous != nil {
+		previous.close()
+	}
+	if cancelPrevious {
+		// 上一轮的回复可能还没结束（打断后立即开始新一轮），先取消，它剩余的事件按 ID 丢弃
+		_ = s.send(map[string]string{"type": "response.cancel"})
+	}
+
+	if interruption, ok := agent.InterruptionFromContext(ctx); ok {
+		if err := s.send(messageItem("system", interruption.Prompt())); err != nil {
+			return nil, err
+		}
+	}
+	if !audioTurn {
+		if err := s.send(messageItem("user", input)); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.createResponse(stream); err != nil {
+		return nil, err
+	}
+
+	events := make(chan agent.AgentEvent)
+	go s.runResponse(ctx, stream, events)
+	return events, nil
+}
+
+// GetToolType 返回工具分类
+func (s *Session) GetToolType(tool string) agent.ToolType {
+	return s.classifier.GetToolType(tool)
+}
+
+// messageItem 一条文本消息
+func messageItem(role, text string) map[string]interface{} {
+	return map[string]interface{}{
+		"type": "conversation.item.create",
+		"item": map[string]interface{}{
+			"type":    "message",
+			"role":    role,
+			"content": []map[string]string{{"type": "input_text", "text": text}},
+		},
+	}
+}
+
+// createResponse 请求服务端生成一次回复
+func (s *Session) createResponse(stream *responseStream) error {
+	s.mu.Lock()
+	if s.response == stream {
+		stream.id, stream.awaiting, stream.active = "", true, false
+	}
+	s.mu.Unlock()
+	return s.send(map[string]string{"type": "response.create"})
+}
+
+// accept 在读取协程中过滤回复事件：记录本轮回复的 ID，丢弃属于已取消回复的事件；调用方需持有 s.mu
+func (r *responseStream) accept(event serverEvent) bool {
+	switch event.Type {
+	case eventResponseCreated:
+		if !r.awaiting || event.Response == nil {
+			return false
+		}
+		r.id, r.awaiting, r.active = event.Response.ID, false, true
+		return true
+	case eventResponseDone:
+		if event.Response == nil || event.Response.ID != r.id {
+			return false
+		}
+		r.active = false
+		return true
+	case eventError:
+		return true
+	default:
+		return r.active && event.ResponseID == r.id
+	}
+}
+
+// finishResponse 回复结束；被打断时若仍是当前回复，请求服务端停止生成
+func (s *Session) finishResponse(stream *responseStream, interrupted bool) {
+	stream.close()
+	s.mu.Lock()
+	current := s.response == stream
+	pending := stream.awaiting || stream.active
+	if current {
+		s.response = nil
+	}
+	s.mu.Unlock()
+	if current && interrupted && pending {
+		_ = s.send(map[string]string{"type": "response.cancel"})
+	}
+}
+
+// runResponse 把服务端回复转换为 Agent 事件：语音按 ClipDuration 切段输出 AudioChunkEvent，
+// 工具调用回传结果后请求模型继续回复，全部结束后输出 FinishedEvent
+func (s *Session) runResponse(ctx context.Context, stream *responseStream, events chan<- agent.AgentEvent) {
+	defer close(events)
+	interrupted := true
+	defer func() { s.finishResponse(stream, interrupted) }()
+
+	emit := func(event agent.AgentEvent) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	start := time.Now()
+	var usage agent.Usage
+	clip := &clipBuffer{limit: pcmBytes(s.config.OutputSampleRate, s.config.ClipDuration)}
+	flush := func() bool {
+		if text, pcm, ok := clip.take(); ok {
+			return emit(&agent.AudioChunkEvent{Text: text, PCM: pcm})
+		}
+		return true
+	}
+
+	for {
+		calls := 0
+		var respErr error
+	response:
+		for {
+			select {
+			case <-ctx.Done():
+				logging.InfofCtx(ctx, "Realtime: response cancelled")
+				return
+			case <-stream.failed:
+				respErr = stream.err
+				break response
+			case event := <-stream.events:
+				switch event.Type {
+				case eventAudioDelta, eventOutputAudioDelta:
+					pcm, err := s.decodeAudio(event.Delta)
+					if err != nil {
+						logging.WarnfCtx(ctx, "Realtime: invalid audio delta: %v", err)
+						continue
+					}
+					if usage.FirstTokenLatency == 0 {
+						usage.FirstTokenLatency = time.Since(start)
+					}
+					if clip.add(pcm) && !flush() {
+						return
+					}
+				case eventAudioTranscript, eventOutputTranscript:
+					clip.text += event.Delta
+				case eventFunctionCallArgsDone:
+					if !flush() || !s.handleToolCall(ctx, event, emit) {
+						return
+					}
+					calls++
+				case eventResponseDone:
+					if u := event.Response.Usage; u != nil {
+						usage.PromptTokens += u.InputTokens
+						usage.CompletionTokens += u.OutputTokens
+					}
+					if status := event.Response.Status; status == "failed" || status == "incomplete" {
+						respErr = fmt.Errorf("realtime: response %s", status)
+					}
+					break response
+				case eventError:
+					respErr = serverError(event)
+					break response
+				}
+			}
+		}
+		if !flush() {
+			return
+		}
+		if respErr != nil || calls == 0 {
+			interrupted = false
+			logging.InfofCtx(ctx, "Realtime: response finished (tokens: %d+%d, first audio: %v)",
+				usage.PromptTokens, usage.CompletionTokens, usage.FirstTokenLatency)
+			emit(&agent.FinishedEvent{Error: respErr, Usage: usage})
+			return
+		}
+		// 工具结果已回传，请求模型基于结果继续回复
+		if err := s.createResponse(stream); err != nil {
+			interrupted = false
+			emit(&agent.FinishedEvent{Error: err, Usage: usage})
+			return
+		}
+	}
+}
+
+// handleToolCall 查询类工具由 Executor 执行并把结果回传给模型；动作类工具交给编排器执行，
+// 模型只得到“已受理”，由模型自己确认播报
+func (s *Session) handleToolCall(ctx context.Context, event serverEvent, emit func(agent.AgentEvent) bool) bool {
+	args := map[string]interface{}{}
+	if event.Arguments != "" {
+		if err := json.Unmarshal([]byte(event.Arguments), &args); err != nil {
+			logging.ErrorfCtx(ctx, "Realtime: invalid arguments for tool %s: %v", event.Name, err)
+		}
+	}
+	toolType := s.classifier.GetToolType(event.Name)
+	logging.InfofCtx(ctx, "Realtime: tool call requested: %s (type: %s), args: %v", event.Name, toolType, args)
+
+	var output interface{}
+	if toolType == agent.ToolTypeQuery && s.config.Executor != nil {
+		start := time.Now()
+		result, _, err := s.config.Executor.Execute(ctx, event.Name, args)
+		if !emit(&agent.ToolResultEvent{Tool: event.Name, Args: args, Result: result, Duration: time.Since(start), Error: err}) {
+			return false
+		}
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return false
+			}
+			output = map[string]string{"error": err.Error()}
+		} else {
+			output = result
+		}
+	} else {
+		if !emit(&agent.ToolCallRequestedEvent{Tool: event.Name, Args: args, ToolType: toolType}) {
+			return false
+		}
+		output = map[string]string{"status": "accepted"}
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		data = []byte(fmt.Sprintf(`{"error":%q}`, err.Error()))
+	}
+	err = s.send(map[string]interface{}{
+		"type": "conversation.item.create",
+		"item": map[string]string{
+			"type":    "function_call_output",
+			"call_id": event.CallID,
+			"output":  string(data),
+		},
+	})
+	if err != nil {
+		logging.WarnfCtx(ctx, "Realtime: send tool output: %v", err)
+	}
+	return true
+}
+
+// decodeAudio 解码一段 base64 PCM16 并转换为输出采样率
+func (s *Session) decodeAudio(delta string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(delta)
+	if err != nil {
+		return nil, err
+	}
+	if s.config.SampleRate == s.config.OutputSampleRate {
+		return data, nil
+	}
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[i*2:]))
+	}
+	resampled, err := s.resampler.Resample(samples, s.config.SampleRate, s.config.OutputSampleRate, 1)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, len(resampled)*2)
+	for _, sample := range resampled {
+		out = binary.LittleEndian.AppendUint16(out, uint16(sample))
+	}
+	return out, nil
+}
+
+// clipBuffer 累积回复语音及对应文字，达到 limit 字节时切出一段
+type clipBuffer struct {
+	limit int
+	pcm   []byte
+	text  string
+}
+
+// add 追加音频，返回是否已达到切段长度
+func (c *clipBuffer) add(pcm []byte) bool {
+	c.pcm = append(c.pcm, pcm...)
+	return len(c.pcm) >= c.limit
+}
+
+// take 取出已累积的音频和文字，没有音频时文字留到下一段
+func (c *clipBuffer) take() (string, []byte, bool) {
+	if len(c.pcm) == 0 {
+		return "", nil, false
+	}
+	text, pcm := c.text, c.pcm
+	c.text, c.pcm = "", nil
+	return text, pcm, true
+}
+
+// pcmBytes 单声道 16-bit PCM 在 d 时长内的字节数
+func pcmBytes(sampleRate int, d time.Duration) int {
+	return int(int64(sampleRate) * int64(d) / int64(time.Second) * 2)
+}
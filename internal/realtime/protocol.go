@@ -0,0 +1,139 @@
+package realtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// 支持的服务商，均使用 OpenAI Realtime 的事件协议（GLM-Realtime 兼容该协议）
+const (
+	ProviderOpenAI = "openai"
+	ProviderGLM    = "glm"
+)
+
+// providerDefaults 服务商的默认地址、模型、音色和用户语音转写模型
+type providerDefaults struct {
+	url, model, voice, transcription string
+}
+
+var defaults = map[string]providerDefaults{
+	ProviderOpenAI: {"wss://api.openai.com/v1/realtime", "gpt-4o-realtime-preview", "alloy", "whisper-1"},
+	ProviderGLM:    {"wss://open.bigmodel.cn/api/paas/v4/realtime", "glm-realtime", "tongtong", ""},
+}
+
+// DefaultInstructions 未配置 llm.system_prompt 时的实时模型指令模板（变量同 agent.PromptConfig）；
+// 与文本模型的默认模板不同，不要求情绪标签，回复直接合成为语音
+const DefaultInstructions = `{{persona}}
+请使用{{language}}口语化地简短回答，不要使用 Markdown 或列表。今天是{{date}}，{{weekday}}。
+需要实时信息（如时间、天气）或执行操作时，请调用工具获取准确结果，不要编造。`
+
+// dialURL 返回连接地址和请求头：OpenAI 的模型放在查询参数中，GLM 在 session.update 中指定
+func dialURL(cfg Config) (string, http.Header, error) {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid realtime url %q: %w", cfg.URL, err)
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+cfg.APIKey)
+	if cfg.Provider == ProviderOpenAI {
+		query := u.Query()
+		if query.Get("model") == "" {
+			query.Set("model", cfg.Model)
+		}
+		u.RawQuery = query.Encode()
+		header.Set("OpenAI-Beta", "realtime=v1")
+	}
+	return u.String(), header, nil
+}
+
+// sessionUpdate 会话配置：服务端 VAD 只用于检测说话和切分语句，不自动回复也不自动打断，
+// 回复由编排器在拿到转写后通过 response.create 触发，打断由编排器的 response.cancel 完成
+func sessionUpdate(cfg Config) map[string]interface{} {
+	session := map[string]interface{}{
+		"modalities":          []string{"text", "audio"},
+		"model":               cfg.Model,
+		"instructions":        cfg.Instructions,
+		"voice":               cfg.Voice,
+		"input_audio_format":  "pcm16",
+		"output_audio_format": "pcm16",
+		"turn_detection": map[string]interface{}{
+			"type":               "server_vad",
+			"create_response":    false,
+			"interrupt_response": false,
+		},
+	}
+	transcription := map[string]string{}
+	if cfg.TranscriptionModel != "" {
+		transcription["model"] = cfg.TranscriptionModel
+	}
+	session["input_audio_transcription"] = transcription
+	if len(cfg.Tools) > 0 {
+		session["tools"] = functionTools(cfg.Tools)
+		session["tool_choice"] = "auto"
+	}
+	return map[string]interface{}{"type": "session.update", "session": session}
+}
+
+// functionTools 把工具定义转换为 Realtime 的 function 工具
+func functionTools(definitions []tools.ToolDefinition) []map[string]interface{} {
+	out := make([]map[string]interface{}, 0, len(definitions))
+	for _, definition := range definitions {
+		out = append(out, map[string]interface{}{
+			"type":        "function",
+			"name":        definition.Name,
+			"description": definition.Description,
+			"parameters":  definition.JSONSchema(),
+		})
+	}
+	return out
+}
+
+// serverEvent 服务端事件（只解析用到的字段）
+type serverEvent struct {
+	Type       string `json:"type"`
+	ResponseID string `json:"response_id"`
+	Delta      string `json:"delta"`
+	Transcript string `json:"transcript"`
+	CallID     string `json:"call_id"`
+	Name       string `json:"name"`
+	Arguments  string `json:"arguments"`
+	Response   *struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		Usage  *struct {
+			InputTokens  int `json:"input_tokens"`
+			OutputTokens int `json:"output_tokens"`
+		} `json:"usage"`
+	} `json:"response"`
+	Error *struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func parseServerEvent(data []byte) (serverEvent, error) {
+	var event serverEvent
+	err := json.Unmarshal(data, &event)
+	return event, err
+}
+
+// 服务端事件类型；回复音频与文字同时兼容 beta（response.audio.*）和正式版（response.output_audio.*）的命名
+const (
+	eventError                = "error"
+	eventSpeechStarted        = "input_audio_buffer.speech_started"
+	eventCommitted            = "input_audio_buffer.committed"
+	eventTranscriptionDelta   = "conversation.item.input_audio_transcription.delta"
+	eventTranscriptionDone    = "conversation.item.input_audio_transcription.completed"
+	eventResponseCreated      = "response.created"
+	eventResponseDone         = "response.done"
+	eventAudioDelta           = "response.audio.delta"
+	eventOutputAudioDelta     = "response.output_audio.delta"
+	eventAudioTranscript      = "response.audio_transcript.delta"
+	eventOutputTranscript     = "response.output_audio_transcript.delta"
+	eventFunctionCallArgsDone = "response.function_call_arguments.done"
+)
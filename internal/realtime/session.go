@@ -0,0 +1,477 @@
+// Package realtime 端到端实时语音模式：麦克风音频直接发送给实时语音大模型（OpenAI Realtime 协议，
+// GLM-Realtime 兼容），由模型完成识别、理解和语音合成，代替 ASR + LLM + TTS 三段链路。
+// Session 同时实现 audio.AudioInPipe 和 agent.VoiceAgent，编排器照常负责打断、工具调用、状态和播放
+package realtime
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// Config 实时语音会话配置
+type Config struct {
+	Provider string // openai 或 glm，决定 URL、Model、Voice、TranscriptionModel 的默认值
+	URL      string
+	APIKey   string
+	Model    string
+	Voice    string
+	// Instructions 系统指令（已渲染的提示词），只在建立连接时发送
+	Instructions string
+	// TranscriptionModel 用户语音转写模型；转写结果作为 ASR final 交给编排器（字幕、命令、触发回复）
+	TranscriptionModel string
+
+	// SampleRate 与服务端交换的 PCM16 采样率，<=0 时为 24000
+	SampleRate int
+	// OutputSampleRate 回复音频转换到的采样率（Mixer 格式），<=0 时为 16000
+	OutputSampleRate int
+	// ClipDuration 回复音频按该时长切段送入播放队列，越短首音延迟越低，<=0 时为 500ms
+	ClipDuration time.Duration
+
+	// Tools 绑定到模型的工具；动作类交给编排器执行，查询类由 Executor 执行后把结果回传给模型
+	Tools     []tools.ToolDefinition
+	ToolTypes map[string]agent.ToolType // 覆盖按定义推断的分类
+	Executor  tools.ToolExecutor        // 为 nil 时查询类工具也交给编排器，模型拿不到结果
+
+	// ReconnectInitialBackoff / ReconnectMaxBackoff 断线重连的退避时间，<=0 时为 500ms / 30s
+	ReconnectInitialBackoff time.Duration
+	ReconnectMaxBackoff     time.Duration
+}
+
+// normalize 校验配置并按服务商补全默认值
+func (c Config) normalize() (Config, error) {
+	if c.Provider == "" {
+		c.Provider = ProviderOpenAI
+	}
+	d, ok := defaults[c.Provider]
+	if !ok {
+		return c, fmt.Errorf("unsupported realtime provider %q", c.Provider)
+	}
+	if strings.TrimSpace(c.APIKey) == "" {
+		return c, errors.New("realtime api_key is required")
+	}
+	if c.URL == "" {
+		c.URL = d.url
+	}
+	if c.Model == "" {
+		c.Model = d.model
+	}
+	if c.Voice == "" {
+		c.Voice = d.voice
+	}
+	if c.TranscriptionModel == "" {
+		c.TranscriptionModel = d.transcription
+	}
+	if c.SampleRate <= 0 {
+		c.SampleRate = 24000
+	}
+	if c.OutputSampleRate <= 0 {
+		c.OutputSampleRate = 16000
+	}
+	if c.ClipDuration <= 0 {
+		c.ClipDuration = 500 * time.Millisecond
+	}
+	if c.ReconnectInitialBackoff <= 0 {
+		c.ReconnectInitialBackoff = 500 * time.Millisecond
+	}
+	if c.ReconnectMaxBackoff <= 0 {
+		c.ReconnectMaxBackoff = 30 * time.Second
+	}
+	return c, nil
+}
+
+// Session 一个实时语音会话（一条 WebSocket 连接），断线后自动重连
+type Session struct {
+	config     Config
+	classifier *agent.ToolClassifier
+	resampler  audio.Resampler
+
+	mu      sync.Mutex
+	conn    *websocket.Conn // 未连接时为 nil
+	source  audio.AudioSource
+	ctx     context.Context // Start 之后有效
+	cancel  context.CancelFunc
+	started bool
+	closed  bool
+	muted   bool
+	// 服务端 VAD 已提交、尚未回复的用户语音条数；Process 时有待回复的语音则不再重复添加文本
+	pendingAudio int
+	partial      string          // 当前语句的转写中间结果
+	response     *responseStream // 进行中的回复
+
+	resultHandler   func(text string, isFinal bool)
+	speakingHandler func()
+	statusHandler   func(available bool, err error)
+
+	dialMu  sync.Mutex // 串行化建立连接（重连与 Process 可能同时发起）
+	writeMu sync.Mutex
+	wg      sync.WaitGroup
+}
+
+var (
+	_ audio.AudioInPipe = (*Session)(nil)
+	_ audio.MicMuter    = (*Session)(nil)
+	_ agent.VoiceAgent  = (*Session)(nil)
+)
+
+// NewSession 创建会话，连接在 Start（或首次 Process）时建立
+func NewSession(cfg Config) (*Session, error) {
+	cfg, err := cfg.normalize()
+	if err != nil {
+		return nil, err
+	}
+	return &Session{
+		config:     cfg,
+		classifier: agent.NewToolClassifierWithTypes(toolTypes(cfg.Tools, cfg.ToolTypes)),
+		resampler:  audio.NewLinearResampler(),
+	}, nil
+}
+
+// toolTypes 按定义的 Action 标记分类，explicit 中的配置优先
+func toolTypes(definitions []tools.ToolDefinition, explicit map[string]agent.ToolType) map[string]agent.ToolType {
+	types := make(map[string]agent.ToolType, len(definitions)+len(explicit))
+	for _, definition := range definitions {
+		types[definition.Name] = agent.ToolTypeQuery
+		if definition.Action {
+			types[definition.Name] = agent.ToolTypeAction
+		}
+	}
+	for name, toolType := range explicit {
+		types[name] = toolType
+	}
+	return types
+}
+
+// SetAudioSource 设置麦克风输入（单声道 16-bit PCM），采样率与 SampleRate 不同时自动重采样；
+// 不设置时只能通过 SendAudio 送入音频，或只作为 VoiceAgent 处理文本输入
+func (s *Session) SetAudioSource(source audio.AudioSource, sampleRate int) {
+	if source != nil && sampleRate != s.config.SampleRate {
+		source = audio.NewResamplingSource(source, sampleRate, s.config.SampleRate, 1, s.resampler)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.source = source
+}
+
+// Start 建立连接并开始发送麦克风音频
+func (s *Session) Start(ctx context.Context) error {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return errors.New("realtime: session already started")
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.started = true
+	source := s.source
+	s.mu.Unlock()
+
+	if _, err := s.ensureConn(s.ctx); err != nil {
+		return err
+	}
+	if source != nil {
+		s.wg.Add(1)
+		go s.pumpAudio(source)
+	}
+	return nil
+}
+
+// Stop 关闭连接和音频源
+func (s *Session) Stop() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	cancel, conn, source := s.cancel, s.conn, s.source
+	s.conn = nil
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	if source != nil {
+		source.Close()
+	}
+	if conn != nil {
+		conn.Close()
+	}
+	s.wg.Wait()
+	logging.Infof("Realtime: session stopped")
+	return nil
+}
+
+// SendAudio 发送一段 SampleRate 采样率的单声道 PCM16，未连接或静音时丢弃
+func (s *Session) SendAudio(pcm []byte) error {
+	if len(pcm) == 0 {
+		return nil
+	}
+	s.mu.Lock()
+	skip := s.muted || s.conn == nil
+	s.mu.Unlock()
+	if skip {
+		return nil
+	}
+	return s.send(map[string]string{
+		"type":  "input_audio_buffer.append",
+		"audio": base64.StdEncoding.EncodeToString(pcm),
+	})
+}
+
+func (s *Session) OnASRResult(handler func(text string, isFinal bool)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resultHandler = handler
+}
+
+func (s *Session) OnUserSpeakingDetected(handler func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.speakingHandler = handler
+}
+
+// OnASRUsage 实时模型不单独返回识别时长，用量计入 FinishedEvent 的 token
+func (s *Session) OnASRUsage(handler func(durationSec int)) {}
+
+func (s *Session) OnRecognizerStatus(handler func(available bool, err error)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statusHandler = handler
+}
+
+// SetMicMuted 静音期间不发送麦克风音频，并清空服务端尚未提交的输入
+func (s *Session) SetMicMuted(muted bool) {
+	s.mu.Lock()
+	if s.muted == muted {
+		s.mu.Unlock()
+		return
+	}
+	s.muted = muted
+	s.partial = ""
+	connected := s.conn != nil
+	s.mu.Unlock()
+	if muted && connected {
+		_ = s.send(map[string]string{"type": "input_audio_buffer.clear"})
+	}
+	logging.Infof("Realtime: microphone muted=%v", muted)
+}
+
+// pumpAudio 把麦克风音频持续发送给服务端，直到 Stop
+func (s *Session) pumpAudio(source audio.AudioSource) {
+	defer s.wg.Done()
+	for {
+		pcm, err := source.Read(s.ctx)
+		if err != nil {
+			if s.ctx.Err() == nil {
+				logging.Errorf("Realtime: audio source error: %v", err)
+			}
+			return
+		}
+		if err := s.SendAudio(pcm); err != nil {
+			logging.Debugf("Realtime: send audio: %v", err)
+		}
+	}
+}
+
+// ensureConn 返回当前连接，未连接时建立连接并发送会话配置
+func (s *Session) ensureConn(ctx context.Context) (*websocket.Conn, error) {
+	s.dialMu.Lock()
+	defer s.dialMu.Unlock()
+	s.mu.Lock()
+	conn, closed := s.conn, s.closed
+	s.mu.Unlock()
+	if closed {
+		return nil, errors.New("realtime: session stopped")
+	}
+	if conn != nil {
+		return conn, nil
+	}
+
+	target, header, err := dialURL(s.config)
+	if err != nil {
+		return nil, err
+	}
+	conn, resp, err := websocket.DefaultDialer.DialContext(ctx, target, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("realtime: connect %s: %w (HTTP %d)", s.config.URL, err, resp.StatusCode)
+		}
+		return nil, fmt.Errorf("realtime: connect %s: %w", s.config.URL, err)
+	}
+	s.writeMu.Lock()
+	err = conn.WriteJSON(sessionUpdate(s.config))
+	s.writeMu.Unlock()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("realtime: send session.update: %w", err)
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		conn.Close()
+		return nil, errors.New("realtime: session stopped")
+	}
+	s.conn = conn
+	s.pendingAudio = 0
+	s.partial = ""
+	s.mu.Unlock()
+	logging.Infof("Realtime: connected to %s (provider=%s, model=%s, voice=%s)",
+		s.config.URL, s.config.Provider, s.config.Model, s.config.Voice)
+
+	s.wg.Add(1)
+	go s.readLoop(conn)
+	return conn, nil
+}
+
+// send 以 JSON 发送一个客户端事件
+func (s *Session) send(event interface{}) error {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	if conn == nil {
+		return errors.New("realtime: not connected")
+	}
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	return conn.WriteJSON(event)
+}
+
+func (s *Session) readLoop(conn *websocket.Conn) {
+	defer s.wg.Done()
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			s.disconnected(conn, err)
+			return
+		}
+		event, err := parseServerEvent(data)
+		if err != nil {
+			logging.Warnf("Realtime: invalid server event: %v", err)
+			continue
+		}
+		s.dispatch(event)
+	}
+}
+
+// dispatch 处理会话级事件，回复相关的事件转交给进行中的回复
+func (s *Session) dispatch(event serverEvent) {
+	switch event.Type {
+	case eventSpeechStarted:
+		s.mu.Lock()
+		handler, muted := s.speakingHandler, s.muted
+		s.mu.Unlock()
+		if handler != nil && !muted {
+			handler()
+		}
+	case eventCommitted:
+		s.mu.Lock()
+		s.pendingAudio++
+		s.mu.Unlock()
+	case eventTranscriptionDelta:
+		s.mu.Lock()
+		s.partial += event.Delta
+		text, handler := s.partial, s.resultHandler
+		s.mu.Unlock()
+		if handler != nil && strings.TrimSpace(text) != "" {
+			handler(text, false)
+		}
+	case eventTranscriptionDone:
+		s.mu.Lock()
+		s.partial = ""
+		handler := s.resultHandler
+		s.mu.Unlock()
+		text := strings.TrimSpace(event.Transcript)
+		if handler != nil && text != "" {
+			handler(text, true)
+		}
+	default:
+		if event.Type != eventError && !strings.HasPrefix(event.Type, "response.") {
+			return
+		}
+		s.mu.Lock()
+		stream := s.response
+		accepted := stream != nil && stream.accept(event)
+		s.mu.Unlock()
+		if accepted {
+			stream.deliver(event)
+		} else if event.Type == eventError {
+			logging.Warnf("Realtime: server error: %v", serverError(event))
+		}
+	}
+}
+
+// serverError 把 error 事件转换为 error
+func serverError(event serverEvent) error {
+	if event.Error == nil {
+		return errors.New("realtime: unknown server error")
+	}
+	if event.Error.Code != "" {
+		return fmt.Errorf("realtime: %s (%s): %s", event.Error.Type, event.Error.Code, event.Error.Message)
+	}
+	return fmt.Errorf("realtime: %s: %s", event.Error.Type, event.Error.Message)
+}
+
+// disconnected 连接断开：结束进行中的回复，Start 之后按退避重连
+func (s *Session) disconnected(conn *websocket.Conn, err error) {
+	s.mu.Lock()
+	if s.conn == conn {
+		s.conn = nil
+	}
+	closed, started := s.closed, s.started
+	stream, handler := s.response, s.statusHandler
+	s.mu.Unlock()
+	if closed {
+		return
+	}
+	err = fmt.Errorf("realtime: connection lost: %w", err)
+	logging.Warnf("%v", err)
+	if stream != nil {
+		stream.fail(err)
+	}
+	if handler != nil {
+		handler(false, err)
+	}
+	if started {
+		s.wg.Add(1)
+		go s.reconnect()
+	}
+}
+
+func (s *Session) reconnect() {
+	defer s.wg.Done()
+	backoff := s.config.ReconnectInitialBackoff
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		if _, err := s.ensureConn(s.ctx); err != nil {
+			if s.ctx.Err() != nil {
+				return
+			}
+			backoff = min(backoff*2, s.config.ReconnectMaxBackoff)
+			logging.Warnf("Realtime: reconnect failed, retrying in %v: %v", backoff, err)
+			continue
+		}
+		s.mu.Lock()
+		handler := s.statusHandler
+		s.mu.Unlock()
+		if handler != nil {
+			handler(true, nil)
+		}
+		return
+	}
+}
@@ -0,0 +1,324 @@
+package realtime
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// fakeServer 模拟实时语音服务：记录客户端事件，由测试下发服务端事件
+type fakeServer struct {
+	server   *httptest.Server
+	header   chan http.Header
+	received chan map[string]interface{}
+
+	mu   sync.Mutex
+	conn *websocket.Conn
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	f := &fakeServer{header: make(chan http.Header, 1), received: make(chan map[string]interface{}, 64)}
+	upgrader := websocket.Upgrader{}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		f.header <- r.Header
+		f.mu.Lock()
+		f.conn = conn
+		f.mu.Unlock()
+		for {
+			var event map[string]interface{}
+			if err := conn.ReadJSON(&event); err != nil {
+				return
+			}
+			f.received <- event
+		}
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeServer) url() string { return "ws" + strings.TrimPrefix(f.server.URL, "http") }
+
+func (f *fakeServer) send(t *testing.T, event string) {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.conn.WriteMessage(websocket.TextMessage, []byte(event)); err != nil {
+		t.Fatalf("server send: %v", err)
+	}
+}
+
+// expect 等待下一个客户端事件并检查类型
+func (f *fakeServer) expect(t *testing.T, eventType string) map[string]interface{} {
+	t.Helper()
+	select {
+	case event := <-f.received:
+		if event["type"] != eventType {
+			t.Fatalf("client sent %v, want %s", event["type"], eventType)
+		}
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatalf("timed out waiting for %s", eventType)
+		return nil
+	}
+}
+
+func newTestSession(t *testing.T, server *fakeServer, executor tools.ToolExecutor) *Session {
+	t.Helper()
+	var definitions []tools.ToolDefinition
+	if executor != nil {
+		definitions = executor.Definitions()
+	}
+	session, err := NewSession(Config{
+		URL:              server.url(),
+		APIKey:           "test-key",
+		SampleRate:       16000,
+		OutputSampleRate: 16000,
+		ClipDuration:     10 * time.Millisecond,
+		Tools:            definitions,
+		Executor:         executor,
+	})
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	t.Cleanup(func() { session.Stop() })
+	return session
+}
+
+func audioDelta(responseID string, samples int) string {
+	pcm := base64.StdEncoding.EncodeToString(make([]byte, samples*2))
+	return `{"type":"response.audio.delta","response_id":"` + responseID + `","delta":"` + pcm + `"}`
+}
+
+func collect(t *testing.T, events <-chan agent.AgentEvent) []agent.AgentEvent {
+	t.Helper()
+	var out []agent.AgentEvent
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return out
+			}
+			out = append(out, event)
+		case <-timeout:
+			t.Fatalf("timed out collecting events, got %d", len(out))
+		}
+	}
+}
+
+func TestSessionAudioTurnWithTool(t *testing.T) {
+	server := newFakeServer(t)
+	executor := tools.NewToolExecutor()
+	executor.Register(tools.ToolDefinition{Name: "getTime", Description: "获取当前时间"},
+		func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+			return map[string]string{"time": "10:30"}, nil, nil
+		})
+	session := newTestSession(t, server, executor)
+
+	var mu sync.Mutex
+	var finals []string
+	speaking := make(chan struct{}, 1)
+	session.OnASRResult(func(text string, isFinal bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if isFinal {
+			finals = append(finals, text)
+		}
+	})
+	session.OnUserSpeakingDetected(func() { speaking <- struct{}{} })
+
+	if err := session.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	header := <-server.header
+	if header.Get("Authorization") != "Bearer test-key" || header.Get("OpenAI-Beta") != "realtime=v1" {
+		t.Fatalf("unexpected headers: %v", header)
+	}
+	update := server.expect(t, "session.update")["session"].(map[string]interface{})
+	turn := update["turn_detection"].(map[string]interface{})
+	if turn["create_response"] != false || turn["interrupt_response"] != false {
+		t.Fatalf("turn_detection = %v, want no automatic response or interruption", turn)
+	}
+	if tools := update["tools"].([]interface{}); len(tools) != 1 {
+		t.Fatalf("tools = %v", tools)
+	}
+
+	if err := session.SendAudio(make([]byte, 320)); err != nil {
+		t.Fatalf("SendAudio() error = %v", err)
+	}
+	server.expect(t, "input_audio_buffer.append")
+
+	server.send(t, `{"type":"input_audio_buffer.speech_started"}`)
+	select {
+	case <-speaking:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected speaking callback")
+	}
+	server.send(t, `{"type":"input_audio_buffer.committed","item_id":"item1"}`)
+	server.send(t, `{"type":"conversation.item.input_audio_transcription.completed","transcript":" 现在几点 "}`)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		got := append([]string(nil), finals...)
+		mu.Unlock()
+		if len(got) == 1 {
+			if got[0] != "现在几点" {
+				t.Fatalf("final = %q", got[0])
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected final transcript")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	events, err := session.Process(context.Background(), "现在几点")
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	collected := make(chan []agent.AgentEvent, 1)
+	go func() { collected <- collect(t, events) }()
+	// 用户语音已提交，不再重复添加文本消息
+	server.expect(t, "response.create")
+	server.send(t, `{"type":"response.created","response":{"id":"r1"}}`)
+	server.send(t, `{"type":"response.function_call_arguments.done","response_id":"r1","call_id":"c1","name":"getTime","arguments":"{}"}`)
+	output := server.expect(t, "conversation.item.create")["item"].(map[string]interface{})
+	if output["type"] != "function_call_output" || output["call_id"] != "c1" || output["output"] != `{"time":"10:30"}` {
+		t.Fatalf("tool output = %v", output)
+	}
+	server.send(t, `{"type":"response.done","response":{"id":"r1","status":"completed","usage":{"input_tokens":10,"output_tokens":2}}}`)
+
+	server.expect(t, "response.create")
+	server.send(t, `{"type":"response.created","response":{"id":"r2"}}`)
+	server.send(t, `{"type":"response.audio_transcript.delta","response_id":"r2","delta":"十点半"}`)
+	server.send(t, audioDelta("r2", 200))
+	server.send(t, audioDelta("stale", 200))
+	server.send(t, `{"type":"response.done","response":{"id":"r2","status":"completed","usage":{"input_tokens":20,"output_tokens":5}}}`)
+
+	got := <-collected
+	if len(got) != 3 {
+		t.Fatalf("got %d events: %#v", len(got), got)
+	}
+	if result, ok := got[0].(*agent.ToolResultEvent); !ok || result.Tool != "getTime" || result.Error != nil {
+		t.Fatalf("event[0] = %#v, want getTime result", got[0])
+	}
+	if chunk, ok := got[1].(*agent.AudioChunkEvent); !ok || chunk.Text != "十点半" || len(chunk.PCM) != 400 {
+		t.Fatalf("event[1] = %#v, want audio chunk", got[1])
+	}
+	finished, ok := got[2].(*agent.FinishedEvent)
+	if !ok || finished.Error != nil || finished.Usage.PromptTokens != 30 || finished.Usage.CompletionTokens != 7 {
+		t.Fatalf("event[2] = %#v, want finished with usage", got[2])
+	}
+}
+
+func TestSessionTextTurnAndCancel(t *testing.T) {
+	server := newFakeServer(t)
+	session := newTestSession(t, server, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := session.Process(ctx, "讲个故事")
+	if err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	server.expect(t, "session.update")
+	item := server.expect(t, "conversation.item.create")["item"].(map[string]interface{})
+	content := item["content"].([]interface{})[0].(map[string]interface{})
+	if item["role"] != "user" || content["text"] != "讲个故事" {
+		t.Fatalf("item = %v", item)
+	}
+	server.expect(t, "response.create")
+	server.send(t, `{"type":"response.created","response":{"id":"r1"}}`)
+	server.send(t, audioDelta("r1", 200))
+
+	select {
+	case event := <-events:
+		if _, ok := event.(*agent.AudioChunkEvent); !ok {
+			t.Fatalf("event = %#v, want audio chunk", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected audio chunk")
+	}
+
+	// 打断：取消 ctx 后请求服务端停止生成
+	cancel()
+	server.expect(t, "response.cancel")
+	collect(t, events)
+}
+
+func TestResponseStreamAccept(t *testing.T) {
+	event := func(data string) serverEvent {
+		e, err := parseServerEvent([]byte(data))
+		if err != nil {
+			t.Fatalf("parse %s: %v", data, err)
+		}
+		return e
+	}
+	stream := &responseStream{awaiting: true}
+	steps := []struct {
+		event string
+		want  bool
+	}{
+		{`{"type":"response.audio.delta","response_id":"old"}`, false},
+		{`{"type":"response.created","response":{"id":"r1"}}`, true},
+		{`{"type":"response.audio.delta","response_id":"old"}`, false},
+		{`{"type":"response.audio.delta","response_id":"r1"}`, true},
+		{`{"type":"response.done","response":{"id":"old"}}`, false},
+		{`{"type":"error","error":{"type":"invalid_request_error","message":"bad"}}`, true},
+		{`{"type":"response.done","response":{"id":"r1"}}`, true},
+		{`{"type":"response.audio.delta","response_id":"r1"}`, false},
+	}
+	for i, step := range steps {
+		if got := stream.accept(event(step.event)); got != step.want {
+			t.Errorf("step %d accept(%s) = %v, want %v", i, step.event, got, step.want)
+		}
+	}
+}
+
+func TestConfigDefaults(t *testing.T) {
+	tests := []struct {
+		provider string
+		url      string
+		model    string
+		wantErr  bool
+	}{
+		{"", "wss://api.openai.com/v1/realtime", "gpt-4o-realtime-preview", false},
+		{ProviderGLM, "wss://open.bigmodel.cn/api/paas/v4/realtime", "glm-realtime", false},
+		{"gemini", "", "", true},
+	}
+	for _, tt := range tests {
+		cfg, err := Config{Provider: tt.provider, APIKey: "k"}.normalize()
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("normalize(%q) error = %v", tt.provider, err)
+		}
+		if err == nil && (cfg.URL != tt.url || cfg.Model != tt.model || cfg.SampleRate != 24000) {
+			t.Errorf("normalize(%q) = %s %s %d", tt.provider, cfg.URL, cfg.Model, cfg.SampleRate)
+		}
+	}
+	if _, err := (Config{}).normalize(); err == nil {
+		t.Error("normalize() without api key should fail")
+	}
+}
+
+func TestSessionUpdateOmitsEmptyTranscriptionModel(t *testing.T) {
+	cfg, _ := Config{Provider: ProviderGLM, APIKey: "k"}.normalize()
+	data, _ := json.Marshal(sessionUpdate(cfg))
+	if !strings.Contains(string(data), `"input_audio_transcription":{}`) {
+		t.Fatalf("session.update = %s", data)
+	}
+}
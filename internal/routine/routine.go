@@ -0,0 +1,134 @@
+// Package routine 定时例程：按 cron 表达式在设定时间主动发起一轮对话（如早间简报）或直接播报一段文本
+package routine
+
+import (
+	"context"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/voicebot"
+)
+
+const (
+	defaultMaxDelay = 5 * time.Minute
+	busyPollPeriod  = 5 * time.Second
+)
+
+// Bot 例程使用的编排器接口（voicebot.Orchestrator 的子集）
+type Bot interface {
+	GetState() voicebot.State
+	// SubmitText 例程文本作为一轮用户输入交给 Agent（由 Agent 调用天气、日程、新闻等工具组织回复）
+	SubmitText(text string) bool
+	// Alert 例程文本不经过 LLM 作为提醒紧急播报，插到进行中的回复之前
+	Alert(text string) bool
+}
+
+// Routine 一个定时例程
+type Routine struct {
+	Name     string
+	Schedule Schedule
+	Text     string
+	Announce bool // true 时把 Text 作为提醒直接播报，否则把 Text 作为一轮用户输入
+}
+
+// Config 例程调度配置
+type Config struct {
+	Routines []Routine
+	Location *time.Location // 计算触发时间的时区，nil 时为本地时区
+	// MaxDelay 到点时正在对话（用户说话、处理中或播报中）则等待空闲，超过该时长仍未空闲时跳过本次，<=0 时为 5 分钟
+	MaxDelay time.Duration
+}
+
+// Scheduler 按时触发例程；到点时不打断进行中的对话，提醒插到进行中的回复之前播报
+type Scheduler struct {
+	bot    Bot
+	config Config
+
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+// NewScheduler 创建例程调度器，例程在 Run 中触发
+func NewScheduler(bot Bot, config Config) *Scheduler {
+	if config.Location == nil {
+		config.Location = time.Local
+	}
+	if config.MaxDelay <= 0 {
+		config.MaxDelay = defaultMaxDelay
+	}
+	return &Scheduler{bot: bot, config: config, now: time.Now, after: time.After}
+}
+
+// Run 按时触发例程，直到 ctx 结束
+func (s *Scheduler) Run(ctx context.Context) {
+	if len(s.config.Routines) == 0 {
+		return
+	}
+	next := make([]time.Time, len(s.config.Routines))
+	now := s.now().In(s.config.Location)
+	for i, routine := range s.config.Routines {
+		next[i] = routine.Schedule.Next(now)
+		logging.Infof("Routine %s: next run at %s", routine.Name, next[i].Format("2006-01-02 15:04"))
+	}
+	for ctx.Err() == nil {
+		var wake time.Time
+		for _, t := range next {
+			if !t.IsZero() && (wake.IsZero() || t.Before(wake)) {
+				wake = t
+			}
+		}
+		if wake.IsZero() {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.after(wake.Sub(s.now())):
+		}
+
+		now := s.now().In(s.config.Location)
+		for i, routine := range s.config.Routines {
+			if next[i].IsZero() || next[i].After(now) {
+				continue
+			}
+			s.fire(ctx, routine)
+			next[i] = routine.Schedule.Next(s.now().In(s.config.Location))
+		}
+	}
+}
+
+// fire 等待对话空闲后触发例程，提醒只等待用户说完
+func (s *Scheduler) fire(ctx context.Context, routine Routine) {
+	deadline := s.now().Add(s.config.MaxDelay)
+	for busy(s.bot.GetState(), routine.Announce) {
+		if !s.now().Before(deadline) {
+			logging.Warnf("Routine %s: skipped, conversation still busy after %v", routine.Name, s.config.MaxDelay)
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.after(busyPollPeriod):
+		}
+	}
+
+	var ok bool
+	if routine.Announce {
+		ok = s.bot.Alert(routine.Text)
+	} else {
+		ok = s.bot.SubmitText(routine.Text)
+	}
+	if !ok {
+		logging.Warnf("Routine %s: not accepted by orchestrator", routine.Name)
+		return
+	}
+	logging.Infof("Routine %s: triggered", routine.Name)
+}
+
+// busy 用户正在说话，或正在处理、播报回复；提醒不等待回复结束
+func busy(state voicebot.State, alert bool) bool {
+	if alert {
+		return state == voicebot.StateListening
+	}
+	return state == voicebot.StateListening || state == voicebot.StateProcessing || state == voicebot.StateSpeaking
+}
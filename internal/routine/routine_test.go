@@ -0,0 +1,232 @@
+package routine
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/voicebot"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"30 7 * * 1-5", false},
+		{"*/15 9-18 * * *", false},
+		{"0 8,12,18 1 */2 0,6", false},
+		{"5/20 * * * 7", false},
+		{"@daily", false},
+		{"@weekdays", false},
+		{"30 7 * *", true},
+		{"60 7 * * *", true},
+		{"0 24 * * *", true},
+		{"0 7 0 * *", true},
+		{"0 7 * 13 *", true},
+		{"0 7 * * 8", true},
+		{"0 7 * * 5-1", true},
+		{"0 7 * * mon", true},
+		{"*/0 * * * *", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			if _, err := ParseSchedule(tt.spec); (err != nil) != tt.wantErr {
+				t.Errorf("ParseSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	// 2024-10-15 是星期二
+	base := time.Date(2024, 10, 15, 7, 30, 20, 0, loc)
+	tests := []struct {
+		spec  string
+		after time.Time
+		want  time.Time
+	}{
+		{"30 7 * * 1-5", base, time.Date(2024, 10, 16, 7, 30, 0, 0, loc)},
+		{"30 7 * * 1-5", base.Add(-time.Minute), time.Date(2024, 10, 15, 7, 30, 0, 0, loc)},
+		{"30 7 * * 1-5", time.Date(2024, 10, 18, 8, 0, 0, 0, loc), time.Date(2024, 10, 21, 7, 30, 0, 0, loc)},
+		{"0 9 * * 0", base, time.Date(2024, 10, 20, 9, 0, 0, 0, loc)},
+		{"0 9 * * 7", base, time.Date(2024, 10, 20, 9, 0, 0, 0, loc)},
+		{"*/15 * * * *", base, time.Date(2024, 10, 15, 7, 45, 0, 0, loc)},
+		{"0 0 1 * *", base, time.Date(2024, 11, 1, 0, 0, 0, 0, loc)},
+		// 日与周都有限制时满足任一即可：20 日（周日）之前先遇到周五 18 日
+		{"0 12 20 * 5", base, time.Date(2024, 10, 18, 12, 0, 0, 0, loc)},
+		{"0 0 29 2 *", base, time.Date(2028, 2, 29, 0, 0, 0, 0, loc)},
+		{"@hourly", base, time.Date(2024, 10, 15, 8, 0, 0, 0, loc)},
+		{"0 0 30 2 *", base, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatalf("ParseSchedule() error = %v", err)
+			}
+			if got := schedule.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeBot 记录触发的例程；states 依次作为 GetState 的返回值，用完后为 Idle
+type fakeBot struct {
+	mu        sync.Mutex
+	states    []voicebot.State
+	turns     []string
+	announced []string
+	times     []time.Time
+	clock     *fakeClock
+	onTrigger func()
+}
+
+func (b *fakeBot) GetState() voicebot.State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.states) == 0 {
+		return voicebot.StateIdle
+	}
+	state := b.states[0]
+	b.states = b.states[1:]
+	return state
+}
+
+func (b *fakeBot) SubmitText(text string) bool {
+	b.mu.Lock()
+	b.turns = append(b.turns, text)
+	b.times = append(b.times, b.clock.Now())
+	b.mu.Unlock()
+	b.onTrigger()
+	return true
+}
+
+func (b *fakeBot) Alert(text string) bool {
+	b.mu.Lock()
+	b.announced = append(b.announced, text)
+	b.times = append(b.times, b.clock.Now())
+	b.mu.Unlock()
+	b.onTrigger()
+	return true
+}
+
+// fakeClock 等待立即返回并把时钟拨到等待结束的时刻
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	now := c.now
+	c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	ch <- now
+	return ch
+}
+
+func newTestScheduler(bot *fakeBot, start time.Time, config Config) *Scheduler {
+	clock := &fakeClock{now: start}
+	bot.clock = clock
+	s := NewScheduler(bot, config)
+	s.now = clock.Now
+	s.after = clock.After
+	return s
+}
+
+func mustSchedule(t *testing.T, spec string) Schedule {
+	t.Helper()
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		t.Fatalf("ParseSchedule(%q) error = %v", spec, err)
+	}
+	return schedule
+}
+
+func TestSchedulerRun(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	start := time.Date(2024, 10, 15, 6, 0, 0, 0, loc)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	triggers := 0
+	bot := &fakeBot{onTrigger: func() {
+		if triggers++; triggers == 3 {
+			cancel()
+		}
+	}}
+	s := newTestScheduler(bot, start, Config{
+		Location: loc,
+		Routines: []Routine{
+			{Name: "briefing", Schedule: mustSchedule(t, "30 7 * * *"), Text: "播报早间简报"},
+			{Name: "water", Schedule: mustSchedule(t, "0 7 * * *"), Text: "该喝水了", Announce: true},
+		},
+	})
+	s.Run(ctx)
+
+	want := []time.Time{
+		time.Date(2024, 10, 15, 7, 0, 0, 0, loc),
+		time.Date(2024, 10, 15, 7, 30, 0, 0, loc),
+		time.Date(2024, 10, 16, 7, 0, 0, 0, loc),
+	}
+	if len(bot.times) != len(want) {
+		t.Fatalf("triggered at %v, want %v", bot.times, want)
+	}
+	for i := range want {
+		if !bot.times[i].Equal(want[i]) {
+			t.Errorf("trigger %d at %s, want %s", i, bot.times[i], want[i])
+		}
+	}
+	if len(bot.turns) != 1 || bot.turns[0] != "播报早间简报" || len(bot.announced) != 2 || bot.announced[0] != "该喝水了" {
+		t.Errorf("turns = %v, announced = %v", bot.turns, bot.announced)
+	}
+}
+
+func TestSchedulerWaitsWhileBusy(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	start := time.Date(2024, 10, 15, 7, 30, 0, 0, loc)
+
+	tests := []struct {
+		name     string
+		states   []voicebot.State
+		announce bool
+		maxDelay time.Duration
+		wantAt   time.Time // 零值表示跳过本次
+	}{
+		{"idle", nil, false, time.Minute, time.Date(2024, 10, 15, 7, 30, 0, 0, loc)},
+		{"muted is not busy", []voicebot.State{voicebot.StateMuted}, false, time.Minute, time.Date(2024, 10, 15, 7, 30, 0, 0, loc)},
+		{"waits for reply", []voicebot.State{voicebot.StateSpeaking, voicebot.StateProcessing}, false, time.Minute, time.Date(2024, 10, 15, 7, 30, 10, 0, loc)},
+		{"alert does not wait for reply", []voicebot.State{voicebot.StateSpeaking}, true, time.Minute, time.Date(2024, 10, 15, 7, 30, 0, 0, loc)},
+		{"alert waits for user", []voicebot.State{voicebot.StateListening, voicebot.StateSpeaking}, true, time.Minute, time.Date(2024, 10, 15, 7, 30, 5, 0, loc)},
+		{"skips after max delay", []voicebot.State{
+			voicebot.StateListening, voicebot.StateSpeaking, voicebot.StateSpeaking, voicebot.StateSpeaking,
+		}, false, 10 * time.Second, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := &fakeBot{states: tt.states, onTrigger: func() {}}
+			s := newTestScheduler(bot, start, Config{Location: loc, MaxDelay: tt.maxDelay})
+			s.fire(context.Background(), Routine{Name: "briefing", Text: "播报早间简报", Announce: tt.announce})
+			if tt.wantAt.IsZero() {
+				if len(bot.times) != 0 {
+					t.Errorf("triggered at %v, want skipped", bot.times)
+				}
+				return
+			}
+			if len(bot.times) != 1 || !bot.times[0].Equal(tt.wantAt) {
+				t.Errorf("triggered at %v, want %s", bot.times, tt.wantAt)
+			}
+		})
+	}
+}
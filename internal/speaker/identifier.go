@@ -0,0 +1,69 @@
+package speaker
+
+import (
+	"math"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+// DefaultThreshold 默认的声纹相似度阈值（余弦相似度）
+const DefaultThreshold = 0.85
+
+// Match 说话人识别结果
+type Match struct {
+	Speaker string  // 最相似的已注册说话人，声纹库为空或无法提取声纹时为空
+	Score   float64 // 与该说话人的相似度
+	Known   bool    // 相似度达到阈值，视为已注册说话人
+}
+
+// Identifier 把语音与声纹库比对
+type Identifier struct {
+	store     *Store
+	threshold float64
+}
+
+// NewIdentifier 创建识别器，threshold <= 0 时使用 DefaultThreshold
+func NewIdentifier(store *Store, threshold float64) *Identifier {
+	if threshold <= 0 {
+		threshold = DefaultThreshold
+	}
+	return &Identifier{store: store, threshold: threshold}
+}
+
+// Identify 返回与声纹向量最相似的已注册说话人
+func (i *Identifier) Identify(vector []float32) Match {
+	var best Match
+	for _, vp := range i.store.List() {
+		score := cosine(vector, vp.Vector)
+		if best.Speaker == "" || score > best.Score {
+			best = Match{Speaker: vp.Name, Score: score}
+		}
+	}
+	best.Known = best.Speaker != "" && best.Score >= i.threshold
+	return best
+}
+
+// IdentifyPCM 从 16-bit 单声道 PCM 提取声纹后识别，语音过短时返回 audio.ErrVoiceprintTooShort
+func (i *Identifier) IdentifyPCM(pcm []byte, sampleRate int) (Match, error) {
+	vector, err := audio.ExtractVoiceprint(pcm, sampleRate)
+	if err != nil {
+		return Match{}, err
+	}
+	return i.Identify(vector), nil
+}
+
+func cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
@@ -0,0 +1,87 @@
+package speaker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+func vector(values ...float32) []float32 {
+	v := make([]float32, audio.VoiceprintDims)
+	copy(v, values)
+	return v
+}
+
+func TestStoreEnrollAndPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "voiceprints.json")
+	store, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore on missing file failed: %v", err)
+	}
+	if err := store.Enroll("alice", vector(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Enroll("alice", vector(0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Enroll("bob", vector(0, 0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Enroll(" ", vector(1)); err == nil {
+		t.Error("expected error for empty name")
+	}
+	if err := store.Enroll("carol", []float32{1}); err == nil {
+		t.Error("expected error for wrong dims")
+	}
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := LoadStore(path)
+	if err != nil {
+		t.Fatalf("LoadStore failed: %v", err)
+	}
+	list := loaded.List()
+	if len(list) != 2 || list[0].Name != "alice" || list[1].Name != "bob" {
+		t.Fatalf("unexpected speakers: %+v", list)
+	}
+	if list[0].Samples != 2 || list[0].Vector[0] != 0.5 || list[0].Vector[1] != 0.5 {
+		t.Errorf("alice should be averaged over 2 samples, got %+v", list[0])
+	}
+
+	if !loaded.Remove("bob") || loaded.Remove("bob") {
+		t.Error("Remove should succeed once")
+	}
+}
+
+func TestIdentifierIdentify(t *testing.T) {
+	store, _ := LoadStore(filepath.Join(t.TempDir(), "v.json"))
+	identifier := NewIdentifier(store, 0.9)
+
+	if match := identifier.Identify(vector(1)); match.Known || match.Speaker != "" {
+		t.Errorf("empty store should not match, got %+v", match)
+	}
+
+	store.Enroll("alice", vector(1, 0))
+	store.Enroll("bob", vector(0, 1))
+
+	tests := []struct {
+		name        string
+		input       []float32
+		wantSpeaker string
+		wantKnown   bool
+	}{
+		{"alice", vector(1, 0.1), "alice", true},
+		{"bob", vector(0.1, 1), "bob", true},
+		{"in between", vector(1, 0.9), "alice", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := identifier.Identify(tt.input)
+			if match.Speaker != tt.wantSpeaker || match.Known != tt.wantKnown {
+				t.Errorf("got %+v, want speaker=%s known=%v", match, tt.wantSpeaker, tt.wantKnown)
+			}
+		})
+	}
+}
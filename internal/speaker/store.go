@@ -0,0 +1,137 @@
+// Package speaker 声纹识别：注册说话人声纹，并对每句语音判断是否来自已注册的说话人
+package speaker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+// Voiceprint 已注册的声纹
+type Voiceprint struct {
+	Name      string    `json:"name"`
+	Vector    []float32 `json:"vector"`
+	Samples   int       `json:"samples"` // 参与平均的录音条数
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Store 声纹库，以 JSON 文件持久化
+type Store struct {
+	path string
+
+	mu     sync.RWMutex
+	prints map[string]*Voiceprint
+}
+
+type storeFile struct {
+	Speakers []*Voiceprint `json:"speakers"`
+}
+
+// LoadStore 从文件加载声纹库，文件不存在时返回空库
+func LoadStore(path string) (*Store, error) {
+	store := &Store{path: path, prints: make(map[string]*Voiceprint)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var file storeFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse voiceprints %s: %w", path, err)
+	}
+	for _, vp := range file.Speakers {
+		if vp.Name == "" || len(vp.Vector) != audio.VoiceprintDims {
+			return nil, fmt.Errorf("invalid voiceprint %q in %s", vp.Name, path)
+		}
+		store.prints[vp.Name] = vp
+	}
+	return store, nil
+}
+
+// Enroll 注册一条录音的声纹；同名说话人已存在时与已有声纹加权平均，多次注册可提高稳定性
+func (s *Store) Enroll(name string, vector []float32) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("speaker name is required")
+	}
+	if len(vector) != audio.VoiceprintDims {
+		return fmt.Errorf("voiceprint has %d dims, want %d", len(vector), audio.VoiceprintDims)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vp, ok := s.prints[name]
+	if !ok {
+		vp = &Voiceprint{Name: name, Vector: make([]float32, len(vector))}
+		s.prints[name] = vp
+	}
+	n := float32(vp.Samples)
+	for i, v := range vector {
+		vp.Vector[i] = (vp.Vector[i]*n + v) / (n + 1)
+	}
+	vp.Samples++
+	vp.UpdatedAt = time.Now()
+	return nil
+}
+
+// EnrollPCM 从 16-bit 单声道 PCM 提取声纹并注册
+func (s *Store) EnrollPCM(name string, pcm []byte, sampleRate int) error {
+	vector, err := audio.ExtractVoiceprint(pcm, sampleRate)
+	if err != nil {
+		return err
+	}
+	return s.Enroll(name, vector)
+}
+
+// Remove 删除说话人，不存在时返回 false
+func (s *Store) Remove(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.prints[name]; !ok {
+		return false
+	}
+	delete(s.prints, name)
+	return true
+}
+
+// List 按名称排序返回已注册的声纹
+func (s *Store) List() []Voiceprint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]Voiceprint, 0, len(s.prints))
+	for _, vp := range s.prints {
+		list = append(list, *vp)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Save 写回声纹库文件
+func (s *Store) Save() error {
+	list := s.List()
+	file := storeFile{Speakers: make([]*Voiceprint, len(list))}
+	for i := range list {
+		file.Speakers[i] = &list[i]
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(s.path, data, 0o644)
+}
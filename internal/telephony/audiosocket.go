@@ -0,0 +1,112 @@
+package telephony
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// AudioSocket 消息类型（Asterisk res_audiosocket）：1 字节类型 + 2 字节大端长度 + 负载
+const (
+	audioSocketHangup = 0x00
+	audioSocketUUID   = 0x01
+	audioSocketDTMF   = 0x03
+	audioSocketAudio  = 0x10 // 8kHz 16-bit 小端单声道 PCM
+	audioSocketError  = 0xff
+)
+
+// serveAudioSocket 接受 Asterisk AudioSocket() 的 TCP 连接，每个连接是一路通话
+func serveAudioSocket(ctx context.Context, addr string, b *bridge) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	logging.Infof("Telephony: AudioSocket listening on %s", listener.Addr())
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go handleAudioSocket(ctx, conn, b)
+	}
+}
+
+func handleAudioSocket(ctx context.Context, conn net.Conn, b *bridge) {
+	defer conn.Close()
+	if !b.begin() {
+		logging.Warnf("Telephony: rejecting call from %s, another call is active", conn.RemoteAddr())
+		writeAudioSocket(conn, audioSocketHangup, nil)
+		return
+	}
+	defer b.end()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	logging.Infof("Telephony: call connected from %s", conn.RemoteAddr())
+	reader := bufio.NewReader(conn)
+	for {
+		kind, payload, err := readAudioSocket(reader)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Err() == nil {
+				logging.Warnf("Telephony: AudioSocket read error: %v", err)
+			}
+			logging.Infof("Telephony: call disconnected")
+			return
+		}
+		switch kind {
+		case audioSocketHangup:
+			logging.Infof("Telephony: call hung up")
+			return
+		case audioSocketUUID:
+			logging.Infof("Telephony: call id %s", hex.EncodeToString(payload))
+		case audioSocketDTMF:
+			logging.Debugf("Telephony: DTMF %q", payload)
+		case audioSocketError:
+			logging.Warnf("Telephony: AudioSocket error from peer: %x", payload)
+			return
+		case audioSocketAudio:
+			out := b.process(payload)
+			if out == nil {
+				continue
+			}
+			if err := writeAudioSocket(conn, audioSocketAudio, out); err != nil {
+				logging.Warnf("Telephony: AudioSocket write error: %v", err)
+				return
+			}
+		}
+	}
+}
+
+func readAudioSocket(r io.Reader) (byte, []byte, error) {
+	var header [3]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, binary.BigEndian.Uint16(header[1:]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	return header[0], payload, nil
+}
+
+func writeAudioSocket(w io.Writer, kind byte, payload []byte) error {
+	msg := make([]byte, 3+len(payload))
+	msg[0] = kind
+	binary.BigEndian.PutUint16(msg[1:], uint16(len(payload)))
+	copy(msg[3:], payload)
+	_, err := w.Write(msg)
+	return err
+}
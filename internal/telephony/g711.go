@@ -0,0 +1,60 @@
+package telephony
+
+const (
+	mulawBias = 0x84
+	mulawClip = 32635
+)
+
+// MulawToLinear 把一个 G.711 μ-law 字节解码为 16-bit 线性 PCM 样本
+func MulawToLinear(b byte) int16 {
+	b = ^b
+	sign := b & 0x80
+	exponent := (b >> 4) & 0x07
+	mantissa := b & 0x0f
+	sample := ((int32(mantissa) << 3) + mulawBias) << exponent
+	sample -= mulawBias
+	if sign != 0 {
+		return int16(-sample)
+	}
+	return int16(sample)
+}
+
+// LinearToMulaw 把一个 16-bit 线性 PCM 样本编码为 G.711 μ-law 字节
+func LinearToMulaw(sample int16) byte {
+	s := int32(sample)
+	sign := byte(0)
+	if s < 0 {
+		s = -s
+		sign = 0x80
+	}
+	if s > mulawClip {
+		s = mulawClip
+	}
+	s += mulawBias
+	exponent := byte(7)
+	for mask := int32(0x4000); s&mask == 0 && exponent > 0; mask >>= 1 {
+		exponent--
+	}
+	mantissa := byte(s>>(exponent+3)) & 0x0f
+	return ^(sign | exponent<<4 | mantissa)
+}
+
+// DecodeMulaw 把 μ-law 数据解码为 16-bit 小端 PCM
+func DecodeMulaw(data []byte) []byte {
+	pcm := make([]byte, len(data)*2)
+	for i, b := range data {
+		s := MulawToLinear(b)
+		pcm[i*2] = byte(s)
+		pcm[i*2+1] = byte(s >> 8)
+	}
+	return pcm
+}
+
+// EncodeMulaw 把 16-bit 小端 PCM 编码为 μ-law，末尾不足一个样本的字节被忽略
+func EncodeMulaw(pcm []byte) []byte {
+	data := make([]byte, len(pcm)/2)
+	for i := range data {
+		data[i] = LinearToMulaw(int16(pcm[i*2]) | int16(pcm[i*2+1])<<8)
+	}
+	return data
+}
@@ -0,0 +1,137 @@
+package telephony
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+const (
+	rtpHeaderSize  = 12
+	rtpPayloadPCMU = 0
+	rtpMaxPacket   = 1500
+)
+
+// rtpPacket 解析出的 RTP 包（只保留需要的字段）
+type rtpPacket struct {
+	payloadType byte
+	payload     []byte
+}
+
+// parseRTP 解析 RTP 包，跳过 CSRC、扩展头和填充
+func parseRTP(data []byte) (rtpPacket, error) {
+	if len(data) < rtpHeaderSize || data[0]>>6 != 2 {
+		return rtpPacket{}, errors.New("not an RTP v2 packet")
+	}
+	offset := rtpHeaderSize + int(data[0]&0x0f)*4
+	if data[0]&0x10 != 0 {
+		if len(data) < offset+4 {
+			return rtpPacket{}, errors.New("truncated RTP extension")
+		}
+		offset += 4 + int(binary.BigEndian.Uint16(data[offset+2:]))*4
+	}
+	end := len(data)
+	if data[0]&0x20 != 0 && end > 0 {
+		end -= int(data[end-1])
+	}
+	if offset > end {
+		return rtpPacket{}, errors.New("truncated RTP packet")
+	}
+	return rtpPacket{payloadType: data[1] & 0x7f, payload: data[offset:end]}, nil
+}
+
+// rtpSender 生成发往对端的 RTP 包
+type rtpSender struct {
+	ssrc      uint32
+	seq       uint16
+	timestamp uint32
+}
+
+func newRTPSender() *rtpSender {
+	return &rtpSender{ssrc: rand.Uint32(), seq: uint16(rand.Uint32()), timestamp: rand.Uint32()}
+}
+
+func (s *rtpSender) packet(payload []byte) []byte {
+	packet := make([]byte, rtpHeaderSize+len(payload))
+	packet[0] = 2 << 6
+	packet[1] = rtpPayloadPCMU
+	binary.BigEndian.PutUint16(packet[2:], s.seq)
+	binary.BigEndian.PutUint32(packet[4:], s.timestamp)
+	binary.BigEndian.PutUint32(packet[8:], s.ssrc)
+	copy(packet[rtpHeaderSize:], payload)
+	s.seq++
+	s.timestamp += uint32(len(payload)) // PCMU 每字节一个 8kHz 样本
+	return packet
+}
+
+// serveRTP 接收 PCMU RTP 音频（如 Asterisk ARI externalMedia、FreeSWITCH），回复发往来源地址；
+// 第一个来源地址即为当前通话，IdleTimeout 内没有收到音频视为挂断
+func serveRTP(ctx context.Context, addr string, b *bridge) error {
+	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return err
+	}
+	conn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	logging.Infof("Telephony: RTP (PCMU) listening on %s", conn.LocalAddr())
+
+	var peer *net.UDPAddr
+	var sender *rtpSender
+	hangup := func(reason string) {
+		logging.Infof("Telephony: call from %s ended (%s)", peer, reason)
+		peer, sender = nil, nil
+		conn.SetReadDeadline(time.Time{})
+		b.end()
+	}
+	buf := make([]byte, rtpMaxPacket)
+	for {
+		n, from, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() && peer != nil {
+				hangup("idle")
+				continue
+			}
+			if peer != nil {
+				hangup("closed")
+			}
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		packet, err := parseRTP(buf[:n])
+		if err != nil || packet.payloadType != rtpPayloadPCMU {
+			continue
+		}
+		if peer == nil {
+			if !b.begin() {
+				continue
+			}
+			peer, sender = from, newRTPSender()
+			logging.Infof("Telephony: call connected from %s", peer)
+		} else if !from.IP.Equal(peer.IP) || from.Port != peer.Port {
+			// 只接入一路通话，其他来源的音频忽略
+			continue
+		}
+		conn.SetReadDeadline(time.Now().Add(b.config.IdleTimeout))
+
+		out := b.process(DecodeMulaw(packet.payload))
+		if out == nil {
+			continue
+		}
+		if _, err := conn.WriteToUDP(sender.packet(EncodeMulaw(out)), peer); err != nil {
+			logging.Warnf("Telephony: RTP write error: %v", err)
+		}
+	}
+}
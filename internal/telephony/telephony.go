@@ -0,0 +1,142 @@
+// Package telephony 电话接入：通过 Asterisk AudioSocket 或 RTP（PCMU）音频分流接入通话，
+// 把 8kHz 通话音频重采样后交给 voicebot 的输入链路，并把 Mixer 输出送回通话。
+// SIP 信令由 Asterisk / FreeSWITCH 处理，本包只负责音频
+package telephony
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+// CallSampleRate 电话音频的采样率
+const CallSampleRate = 8000
+
+// 支持的接入协议
+const (
+	ProtocolAudioSocket = "audiosocket"
+	ProtocolRTP         = "rtp"
+)
+
+// Device 通话音频的收发端（audio.RemoteStream）
+type Device interface {
+	// Process 处理一块麦克风输入（16-bit 小端单声道 PCM，SampleRate 采样率），返回等长的扬声器输出
+	Process(in []byte) []byte
+	SampleRate() int
+}
+
+// Config 电话接入配置
+type Config struct {
+	Protocol string // audiosocket 或 rtp
+	Addr     string // 监听地址
+	// IdleTimeout RTP 超过该时长没有收到音频视为挂断，<=0 时使用 5 秒
+	IdleTimeout time.Duration
+	Device      Device
+	// OnCall 通话接通（active=true）和挂断时回调，用于打断挂断时未播完的回复等
+	OnCall func(active bool)
+}
+
+// Serve 按 cfg.Protocol 监听通话音频，直到 ctx 结束；同一时间只接入一路通话
+func Serve(ctx context.Context, cfg Config) error {
+	if cfg.Device == nil {
+		return fmt.Errorf("telephony requires an audio device")
+	}
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = 5 * time.Second
+	}
+	b := &bridge{config: cfg, resampler: audio.NewLinearResampler()}
+	switch cfg.Protocol {
+	case ProtocolAudioSocket:
+		return serveAudioSocket(ctx, cfg.Addr, b)
+	case ProtocolRTP:
+		return serveRTP(ctx, cfg.Addr, b)
+	default:
+		return fmt.Errorf("unknown telephony protocol %q", cfg.Protocol)
+	}
+}
+
+// bridge 在 8kHz 通话音频与 Device 采样率之间转换，并保证只有一路通话
+type bridge struct {
+	config    Config
+	resampler audio.Resampler
+
+	mu     sync.Mutex
+	active bool
+}
+
+// begin 开始一路通话，已有通话时返回 false
+func (b *bridge) begin() bool {
+	b.mu.Lock()
+	if b.active {
+		b.mu.Unlock()
+		return false
+	}
+	b.active = true
+	b.mu.Unlock()
+	if b.config.OnCall != nil {
+		b.config.OnCall(true)
+	}
+	return true
+}
+
+func (b *bridge) end() {
+	b.mu.Lock()
+	b.active = false
+	b.mu.Unlock()
+	if b.config.OnCall != nil {
+		b.config.OnCall(false)
+	}
+}
+
+// process 处理一块 8kHz 16-bit PCM 通话音频，返回等长的 8kHz 输出
+func (b *bridge) process(in []byte) []byte {
+	rate := b.config.Device.SampleRate()
+	samples := toInt16(in)
+	if len(samples) == 0 {
+		return nil
+	}
+	if rate != CallSampleRate {
+		upsampled, err := b.resampler.Resample(samples, CallSampleRate, rate, 1)
+		if err != nil {
+			return nil
+		}
+		samples = upsampled
+	}
+	out := toInt16(b.config.Device.Process(toBytes(samples)))
+	if rate != CallSampleRate && len(out) > 0 {
+		downsampled, err := b.resampler.Resample(out, rate, CallSampleRate, 1)
+		if err != nil {
+			return nil
+		}
+		out = downsampled
+	}
+	// 重采样的取整误差可能差一个样本，按输入长度对齐
+	want := len(in) / 2
+	if len(out) > want {
+		out = out[:want]
+	}
+	for len(out) < want {
+		out = append(out, 0)
+	}
+	return toBytes(out)
+}
+
+func toInt16(data []byte) []int16 {
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(data[i*2]) | int16(data[i*2+1])<<8
+	}
+	return samples
+}
+
+func toBytes(samples []int16) []byte {
+	data := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		data[i*2] = byte(s)
+		data[i*2+1] = byte(s >> 8)
+	}
+	return data
+}
@@ -0,0 +1,219 @@
+package telephony
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+func TestMulawRoundTrip(t *testing.T) {
+	tests := []struct {
+		sample int16
+		maxErr int
+	}{
+		{0, 0},
+		{100, 8},
+		{-100, 8},
+		{1000, 32},
+		{-12345, 512},
+		{32767, 1024},
+		{-32768, 1024},
+	}
+	for _, tt := range tests {
+		got := MulawToLinear(LinearToMulaw(tt.sample))
+		if diff := int(got) - int(tt.sample); diff > tt.maxErr || diff < -tt.maxErr {
+			t.Errorf("round trip %d = %d, error %d > %d", tt.sample, got, diff, tt.maxErr)
+		}
+	}
+	if got := LinearToMulaw(0); got != 0xff {
+		t.Errorf("LinearToMulaw(0) = %#x, want 0xff", got)
+	}
+	pcm := toBytes([]int16{0, 500, -500})
+	if got := toInt16(DecodeMulaw(EncodeMulaw(pcm))); len(got) != 3 || got[1] <= 0 || got[2] >= 0 {
+		t.Errorf("DecodeMulaw(EncodeMulaw) = %v", got)
+	}
+}
+
+// fakeDevice 记录收到的输入，输出固定值
+type fakeDevice struct {
+	rate int
+	mu   sync.Mutex
+	in   [][]int16
+}
+
+func (d *fakeDevice) SampleRate() int { return d.rate }
+
+func (d *fakeDevice) Process(in []byte) []byte {
+	samples := toInt16(in)
+	d.mu.Lock()
+	d.in = append(d.in, samples)
+	d.mu.Unlock()
+	out := make([]int16, len(samples))
+	for i := range out {
+		out[i] = 1000
+	}
+	return toBytes(out)
+}
+
+func (d *fakeDevice) inputs() [][]int16 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([][]int16(nil), d.in...)
+}
+
+func TestBridgeResamples(t *testing.T) {
+	device := &fakeDevice{rate: 16000}
+	b := &bridge{config: Config{Device: device}, resampler: audio.NewLinearResampler()}
+	out := b.process(make([]byte, 320)) // 20ms @ 8kHz
+	if len(out) != 320 {
+		t.Fatalf("output = %d bytes, want 320", len(out))
+	}
+	if in := device.inputs(); len(in) != 1 || len(in[0]) != 320 {
+		t.Fatalf("device input = %d samples, want 320 (16kHz)", len(in[0]))
+	}
+	if samples := toInt16(out); samples[0] != 1000 || samples[159] != 1000 {
+		t.Fatalf("output samples = %v, want 1000", samples[:4])
+	}
+}
+
+func TestBridgeSingleCall(t *testing.T) {
+	var calls []bool
+	b := &bridge{config: Config{OnCall: func(active bool) { calls = append(calls, active) }}}
+	if !b.begin() || b.begin() {
+		t.Fatal("expected only the first begin() to succeed")
+	}
+	b.end()
+	if !b.begin() {
+		t.Fatal("begin() after end() = false")
+	}
+	if want := []bool{true, false, true}; len(calls) != len(want) || calls[0] != want[0] || calls[1] != want[1] || calls[2] != want[2] {
+		t.Fatalf("OnCall = %v, want %v", calls, want)
+	}
+}
+
+func TestAudioSocket(t *testing.T) {
+	device := &fakeDevice{rate: 8000}
+	ended := make(chan struct{})
+	b := &bridge{config: Config{Device: device, OnCall: func(active bool) {
+		if !active {
+			close(ended)
+		}
+	}}, resampler: audio.NewLinearResampler()}
+	server, client := net.Pipe()
+	go handleAudioSocket(context.Background(), server, b)
+	defer client.Close()
+	client.SetDeadline(time.Now().Add(2 * time.Second))
+
+	if err := writeAudioSocket(client, audioSocketUUID, make([]byte, 16)); err != nil {
+		t.Fatalf("write uuid: %v", err)
+	}
+	if err := writeAudioSocket(client, audioSocketAudio, toBytes([]int16{1, 2, 3})); err != nil {
+		t.Fatalf("write audio: %v", err)
+	}
+	kind, payload, err := readAudioSocket(client)
+	if err != nil || kind != audioSocketAudio || len(payload) != 6 || toInt16(payload)[0] != 1000 {
+		t.Fatalf("reply = %#x %v %v, want 3 samples of audio", kind, payload, err)
+	}
+	if err := writeAudioSocket(client, audioSocketHangup, nil); err != nil {
+		t.Fatalf("write hangup: %v", err)
+	}
+	select {
+	case <-ended:
+	case <-time.After(time.Second):
+		t.Fatal("expected call to end after hangup")
+	}
+	if in := device.inputs(); len(in) != 1 || in[0][2] != 3 {
+		t.Fatalf("device input = %v, want [[1 2 3]]", in)
+	}
+}
+
+func TestParseRTP(t *testing.T) {
+	payload := []byte{0xff, 0x7f, 0x00}
+	tests := []struct {
+		name   string
+		packet []byte
+	}{
+		{"plain", append([]byte{0x80, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1}, payload...)},
+		{"csrc", append([]byte{0x81, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 9, 9, 9, 9}, payload...)},
+		{"extension", append([]byte{0x90, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 0xbe, 0xde, 0, 1, 7, 7, 7, 7}, payload...)},
+		{"padding", append(append([]byte{0xa0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1}, payload...), 0, 0, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packet, err := parseRTP(tt.packet)
+			if err != nil || packet.payloadType != rtpPayloadPCMU || string(packet.payload) != string(payload) {
+				t.Fatalf("parseRTP = %+v, %v, want payload %v", packet, err, payload)
+			}
+		})
+	}
+	if _, err := parseRTP([]byte{0x80, 0}); err == nil {
+		t.Fatal("expected error for short packet")
+	}
+}
+
+func TestServeRTP(t *testing.T) {
+	device := &fakeDevice{rate: 8000}
+	calls := make(chan bool, 4)
+	b := &bridge{config: Config{Device: device, IdleTimeout: 200 * time.Millisecond, OnCall: func(active bool) { calls <- active }},
+		resampler: audio.NewLinearResampler()}
+
+	// 先占用一个端口再释放，供服务端监听
+	probe, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := probe.LocalAddr().String()
+	probe.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- serveRTP(ctx, addr, b) }()
+
+	client, err := net.Dial("udp", addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer client.Close()
+	sender := newRTPSender()
+	reply := make([]byte, rtpMaxPacket)
+	var n int
+	for i := 0; i < 20; i++ {
+		client.Write(sender.packet(EncodeMulaw(toBytes(make([]int16, 160)))))
+		client.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		if n, err = client.Read(reply); err == nil {
+			break
+		}
+		// 服务端可能尚未开始监听
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatalf("no RTP reply: %v", err)
+	}
+	packet, err := parseRTP(reply[:n])
+	if err != nil || len(packet.payload) != 160 {
+		t.Fatalf("reply = %+v, %v, want 160 bytes of PCMU", packet, err)
+	}
+	if got := MulawToLinear(packet.payload[0]); got < 900 || got > 1100 {
+		t.Fatalf("reply sample = %d, want about 1000", got)
+	}
+
+	for _, want := range []bool{true, false} {
+		select {
+		case got := <-calls:
+			if got != want {
+				t.Fatalf("OnCall = %v, want %v", got, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected OnCall(%v)", want)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("serveRTP() error = %v", err)
+	}
+}
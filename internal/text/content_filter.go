@@ -0,0 +1,139 @@
+package text
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ContentFilter 敏感内容过滤：词表（不区分大小写）加可选正则
+// 英文词按整词匹配，避免误伤包含该词的正常单词；中文等其他词按子串匹配
+type ContentFilter struct {
+	patterns []*regexp.Regexp
+	mask     string
+}
+
+// ContentFilterConfig 过滤规则
+type ContentFilterConfig struct {
+	Words    []string // 敏感词
+	Patterns []string // 正则表达式（RE2 语法）
+	Mask     string   // 屏蔽时替换每个字符的字符串，默认 "*"
+}
+
+// NewContentFilter 编译过滤规则，词表和正则都为空时返回错误
+func NewContentFilter(cfg ContentFilterConfig) (*ContentFilter, error) {
+	f := &ContentFilter{mask: cfg.Mask}
+	if f.mask == "" {
+		f.mask = "*"
+	}
+
+	var ascii, other []string
+	for _, word := range cfg.Words {
+		word = strings.TrimSpace(word)
+		if word == "" {
+			continue
+		}
+		if isASCII(word) {
+			ascii = append(ascii, wordBoundary(word))
+		} else {
+			other = append(other, regexp.QuoteMeta(word))
+		}
+	}
+	// 长词优先，保证重叠的词整体被屏蔽
+	byLength := func(words []string) {
+		sort.SliceStable(words, func(i, j int) bool { return len(words[i]) > len(words[j]) })
+	}
+	if len(ascii) > 0 {
+		byLength(ascii)
+		f.patterns = append(f.patterns, regexp.MustCompile(`(?i)(?:`+strings.Join(ascii, "|")+`)`))
+	}
+	if len(other) > 0 {
+		byLength(other)
+		f.patterns = append(f.patterns, regexp.MustCompile(`(?i)(?:`+strings.Join(other, "|")+`)`))
+	}
+	for _, pattern := range cfg.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("content filter pattern %q: %w", pattern, err)
+		}
+		f.patterns = append(f.patterns, re)
+	}
+	if len(f.patterns) == 0 {
+		return nil, fmt.Errorf("content filter has no words or patterns")
+	}
+	return f, nil
+}
+
+// Match 判断文本是否包含敏感内容
+func (f *ContentFilter) Match(s string) bool {
+	for _, re := range f.patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Mask 把敏感内容的每个字符替换为 Mask，返回结果及是否有内容被屏蔽
+func (f *ContentFilter) Mask(s string) (string, bool) {
+	masked := false
+	for _, re := range f.patterns {
+		s = re.ReplaceAllStringFunc(s, func(m string) string {
+			masked = true
+			return strings.Repeat(f.mask, utf8.RuneCountInString(m))
+		})
+	}
+	return s, masked
+}
+
+// LoadWordList 读取词表文件：每行一个词，忽略空行和 # 开头的注释
+func LoadWordList(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// wordBoundary 转义英文词，并在首尾为字母数字时加上单词边界
+func wordBoundary(word string) string {
+	quoted := regexp.QuoteMeta(word)
+	if isWordByte(word[0]) {
+		quoted = `\b` + quoted
+	}
+	if isWordByte(word[len(word)-1]) {
+		quoted += `\b`
+	}
+	return quoted
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || ('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package text
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestContentFilterMask(t *testing.T) {
+	f, err := NewContentFilter(ContentFilterConfig{
+		Words:    []string{"笨蛋", "大笨蛋", "damn", "a$$"},
+		Patterns: []string{`\d{11}`},
+	})
+	if err != nil {
+		t.Fatalf("NewContentFilter() error = %v", err)
+	}
+
+	tests := []struct {
+		input      string
+		want       string
+		wantMasked bool
+	}{
+		{"你这个大笨蛋！", "你这个***！", true},
+		{"Damn it", "**** it", true},
+		{"the dam is damned", "the dam is damned", false},
+		{"what an a$$.", "what an ***.", true},
+		{"我的手机号是13800138000", "我的手机号是***********", true},
+		{"今天天气不错", "今天天气不错", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, masked := f.Mask(tt.input)
+			if got != tt.want || masked != tt.wantMasked {
+				t.Fatalf("Mask(%q) = (%q, %v), want (%q, %v)", tt.input, got, masked, tt.want, tt.wantMasked)
+			}
+			if f.Match(tt.input) != tt.wantMasked {
+				t.Fatalf("Match(%q) = %v, want %v", tt.input, !tt.wantMasked, tt.wantMasked)
+			}
+		})
+	}
+}
+
+func TestNewContentFilterErrors(t *testing.T) {
+	if _, err := NewContentFilter(ContentFilterConfig{Words: []string{" "}}); err == nil {
+		t.Fatalf("expected error for empty rules")
+	}
+	if _, err := NewContentFilter(ContentFilterConfig{Patterns: []string{"("}}); err == nil {
+		t.Fatalf("expected error for invalid pattern")
+	}
+}
+
+func TestLoadWordList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "words.txt")
+	if err := os.WriteFile(path, []byte("# 注释\n笨蛋\n\n  damn  \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	words, err := LoadWordList(path)
+	if err != nil {
+		t.Fatalf("LoadWordList() error = %v", err)
+	}
+	if want := []string{"笨蛋", "damn"}; !reflect.DeepEqual(words, want) {
+		t.Fatalf("words = %v, want %v", words, want)
+	}
+}
@@ -0,0 +1,48 @@
+package text
+
+import (
+	"unicode"
+)
+
+// 语言检测额外支持的语言（规范化只支持 LocaleZh / LocaleEn）
+const (
+	LocaleJa = "ja"
+	LocaleKo = "ko"
+)
+
+// DetectLanguage 按文字系统粗略判断一句话的语言，返回 LocaleZh、LocaleEn、LocaleJa、LocaleKo，
+// 没有可判断的文字（纯数字、标点）时返回空字符串
+// 中英混杂时按汉字数与英文单词数比较，“帮我查一下 iPhone 价格”判为中文
+func DetectLanguage(s string) string {
+	var han, kana, hangul, latinWords int
+	inWord := false
+	for _, r := range s {
+		isLatin := r < unicode.MaxASCII && unicode.IsLetter(r)
+		if isLatin && !inWord {
+			latinWords++
+		}
+		inWord = isLatin
+
+		switch {
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Han, r):
+			han++
+		}
+	}
+
+	switch {
+	case kana > 0:
+		return LocaleJa
+	case hangul > 0 && hangul >= han:
+		return LocaleKo
+	case han > 0 && han >= latinWords:
+		return LocaleZh
+	case latinWords > 0:
+		return LocaleEn
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,26 @@
+package text
+
+import "testing"
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"今天天气怎么样", LocaleZh},
+		{"What's the weather like today?", LocaleEn},
+		{"帮我查一下 iPhone 价格", LocaleZh},
+		{"Play 周杰伦 songs for me please", LocaleEn},
+		{"今日の天気はどうですか", LocaleJa},
+		{"오늘 날씨 어때요", LocaleKo},
+		{"OK", LocaleEn},
+		{"好", LocaleZh},
+		{"123！", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := DetectLanguage(tt.input); got != tt.want {
+			t.Errorf("DetectLanguage(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,340 @@
+package text
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 支持的规范化语言
+const (
+	LocaleZh = "zh"
+	LocaleEn = "en"
+)
+
+// Normalizer TTS 前的文本规范化：把数字、单位、网址等转换为适合朗读的形式
+// 中文（zh）下数字转为中文读法，并在中英文之间补空格；英文（en）下只展开单位和符号，数字交给 TTS
+type Normalizer struct {
+	locale string
+}
+
+// NewNormalizer 创建规范化器，未知 locale 按中文处理
+func NewNormalizer(locale string) *Normalizer {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if strings.HasPrefix(locale, LocaleEn) {
+		locale = LocaleEn
+	} else {
+		locale = LocaleZh
+	}
+	return &Normalizer{locale: locale}
+}
+
+// Locale 当前使用的语言
+func (n *Normalizer) Locale() string {
+	return n.locale
+}
+
+var (
+	urlPattern      = regexp.MustCompile(`https?://[A-Za-z0-9.\-]+(?::\d+)?(?:/[\w\-./?=&%#~+:@]*)?`)
+	emailPattern    = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)+`)
+	wwwPattern      = regexp.MustCompile(`\bwww\.[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)+`)
+	alnumHyphen     = regexp.MustCompile(`([A-Za-z])-(\d)`)
+	datePattern     = regexp.MustCompile(`(\d{4})[-/.](\d{1,2})[-/.](\d{1,2})`)
+	yearPattern     = regexp.MustCompile(`(\d{4})年`)
+	timePattern     = regexp.MustCompile(`(\d{1,2}):(\d{2})`)
+	percentPattern  = regexp.MustCompile(`(-?\d+(?:\.\d+)?)\s*[%％]`)
+	unitPattern     = regexp.MustCompile(`(^|[^A-Za-z0-9.,])(\d+(?:,\d{3})*(?:\.\d+)?)\s*(°C|℃|°F|℉|km/h|kHz|km|kg|cm|mm|mg|ml|mL|min|ms|Hz|m|g|L|s|h)([^A-Za-z]|$)`)
+	rangePattern    = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*[-~～]\s*(\d)`)
+	negativePattern = regexp.MustCompile(`(^|[^\w])-(\d)`)
+	numberPattern   = regexp.MustCompile(`\d+(?:,\d{3})*(?:\.\d+)?`)
+)
+
+var zhUnits = map[string]string{
+	"°C": "摄氏度", "℃": "摄氏度", "°F": "华氏度", "℉": "华氏度",
+	"km/h": "公里每小时", "km": "公里", "m": "米", "cm": "厘米", "mm": "毫米",
+	"kg": "公斤", "g": "克", "mg": "毫克", "ml": "毫升", "mL": "毫升", "L": "升",
+	"min": "分钟", "ms": "毫秒", "s": "秒", "h": "小时", "Hz": "赫兹", "kHz": "千赫兹",
+}
+
+var enUnits = map[string]string{
+	"°C": "degrees Celsius", "℃": "degrees Celsius", "°F": "degrees Fahrenheit", "℉": "degrees Fahrenheit",
+	"km/h": "kilometers per hour", "km": "kilometers", "m": "meters", "cm": "centimeters", "mm": "millimeters",
+	"kg": "kilograms", "g": "grams", "mg": "milligrams", "ml": "milliliters", "mL": "milliliters", "L": "liters",
+	"min": "minutes", "ms": "milliseconds", "s": "seconds", "h": "hours", "Hz": "hertz", "kHz": "kilohertz",
+}
+
+// Normalize 规范化一句待朗读的文本
+func (n *Normalizer) Normalize(input string) string {
+	if strings.TrimSpace(input) == "" {
+		return input
+	}
+	s := input
+
+	s = urlPattern.ReplaceAllStringFunc(s, n.readURL)
+	s = emailPattern.ReplaceAllStringFunc(s, n.readEmail)
+	s = wwwPattern.ReplaceAllStringFunc(s, n.readDomain)
+	// GPT-4o、COVID-19 这类型号中的连字符不读出来
+	s = alnumHyphen.ReplaceAllString(s, "$1 $2")
+
+	if n.locale == LocaleEn {
+		s = percentPattern.ReplaceAllString(s, "$1 percent")
+		s = replaceSubmatch(unitPattern, s, func(m []string) string {
+			return m[1] + m[2] + " " + enUnits[m[3]] + m[4]
+		})
+		s = strings.ReplaceAll(s, "&", " and ")
+		return collapseSpaces(s)
+	}
+
+	s = replaceSubmatch(datePattern, s, func(m []string) string {
+		month, _ := strconv.Atoi(m[2])
+		day, _ := strconv.Atoi(m[3])
+		if month < 1 || month > 12 || day < 1 || day > 31 {
+			return m[0]
+		}
+		return readDigits(m[1]) + "年" + readInteger(m[2]) + "月" + readInteger(m[3]) + "日"
+	})
+	s = replaceSubmatch(yearPattern, s, func(m []string) string {
+		return readDigits(m[1]) + "年"
+	})
+	s = replaceSubmatch(timePattern, s, func(m []string) string {
+		hour, _ := strconv.Atoi(m[1])
+		minute, _ := strconv.Atoi(m[2])
+		if hour > 24 || minute > 59 {
+			return m[0]
+		}
+		if minute == 0 {
+			return readInteger(m[1]) + "点整"
+		}
+		if minute < 10 {
+			return readInteger(m[1]) + "点零" + readInteger(m[2]) + "分"
+		}
+		return readInteger(m[1]) + "点" + readInteger(m[2]) + "分"
+	})
+	s = replaceSubmatch(percentPattern, s, func(m []string) string {
+		return "百分之" + readNumber(m[1])
+	})
+	s = rangePattern.ReplaceAllString(s, "${1}到${2}")
+	s = replaceSubmatch(unitPattern, s, func(m []string) string {
+		return m[1] + readCount(m[2]) + zhUnits[m[3]] + m[4]
+	})
+	s = negativePattern.ReplaceAllString(s, "${1}负${2}")
+	s = n.readNumbers(s)
+
+	s = strings.ReplaceAll(s, "&", "和")
+	return spaceMixed(s)
+}
+
+// zhMeasureWords 数字“2”后跟这些量词时读作“两”
+var zhMeasureWords = "个位只本次天周种件条张台辆双对名家点块斤倍遍层"
+
+// readNumbers 把独立的数字转为中文读法；与字母相连的数字（型号、化学式等）保持原样
+func (n *Normalizer) readNumbers(s string) string {
+	matches := numberPattern.FindAllStringIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		b.WriteString(s[last:start])
+		last = end
+		if isASCIILetterBefore(s, start) || isASCIILetterAfter(s, end) {
+			b.WriteString(s[start:end])
+			continue
+		}
+		if next, _ := utf8.DecodeRuneInString(s[end:]); strings.ContainsRune(zhMeasureWords, next) {
+			b.WriteString(readCount(s[start:end]))
+		} else {
+			b.WriteString(readNumber(s[start:end]))
+		}
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// readURL 只读域名，路径和参数不读；句末标点被正则吞入时保留下来
+func (n *Normalizer) readURL(url string) string {
+	trimmed := strings.TrimRight(url, ".,;:!?")
+	tail := url[len(trimmed):]
+	host := trimmed[strings.Index(trimmed, "://")+3:]
+	if i := strings.IndexAny(host, ":/"); i >= 0 {
+		host = host[:i]
+	}
+	return n.readDomain(host) + tail
+}
+
+func (n *Normalizer) readDomain(domain string) string {
+	domain = strings.TrimPrefix(domain, "www.")
+	if n.locale == LocaleEn {
+		return strings.ReplaceAll(domain, ".", " dot ")
+	}
+	return strings.ReplaceAll(domain, ".", "点")
+}
+
+func (n *Normalizer) readEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	return email[:at] + " at " + n.readDomain(email[at+1:])
+}
+
+var zhDigits = []string{"零", "一", "二", "三", "四", "五", "六", "七", "八", "九"}
+
+// readNumber 读出整数或小数（可带千分位逗号）
+func readNumber(number string) string {
+	number = strings.ReplaceAll(number, ",", "")
+	negative := strings.HasPrefix(number, "-")
+	number = strings.TrimPrefix(number, "-")
+
+	intPart, fracPart, hasFrac := strings.Cut(number, ".")
+	// 以 0 开头的多位数（编号）或过长的数字（电话、账号）逐位读
+	var result string
+	if (len(intPart) > 1 && intPart[0] == '0') || len(intPart) > 8 {
+		result = readDigits(intPart)
+	} else {
+		result = readInteger(intPart)
+	}
+	if hasFrac {
+		result += "点" + readDigits(fracPart)
+	}
+	if negative {
+		result = "负" + result
+	}
+	return result
+}
+
+// readCount 读作数量：单独的“2”读作“两”（两公斤、两个）
+func readCount(number string) string {
+	if number == "2" {
+		return "两"
+	}
+	return readNumber(number)
+}
+
+// readDigits 逐位读数字
+func readDigits(digits string) string {
+	var b strings.Builder
+	for _, r := range digits {
+		if r >= '0' && r <= '9' {
+			b.WriteString(zhDigits[r-'0'])
+		}
+	}
+	return b.String()
+}
+
+// readInteger 按中文计数读整数（最多 16 位）
+func readInteger(digits string) string {
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		return zhDigits[0]
+	}
+	if len(digits) > 16 {
+		return readDigits(digits)
+	}
+
+	groupUnits := []string{"", "万", "亿", "万亿"}
+	var groups []string
+	for end := len(digits); end > 0; end -= 4 {
+		start := end - 4
+		if start < 0 {
+			start = 0
+		}
+		groups = append([]string{digits[start:end]}, groups...)
+	}
+
+	var b strings.Builder
+	needZero := false
+	for i, group := range groups {
+		value, _ := strconv.Atoi(group)
+		if value == 0 {
+			needZero = b.Len() > 0
+			continue
+		}
+		if b.Len() > 0 && (needZero || value < 1000) {
+			b.WriteString("零")
+		}
+		unit := groupUnits[len(groups)-1-i]
+		if value == 2 && unit != "" {
+			b.WriteString("两")
+		} else {
+			b.WriteString(readGroup(group))
+		}
+		b.WriteString(unit)
+		needZero = false
+	}
+
+	result := b.String()
+	// 10~19 读作“十几”而不是“一十几”
+	if strings.HasPrefix(result, "一十") {
+		result = strings.TrimPrefix(result, "一")
+	}
+	return result
+}
+
+// readGroup 读不超过四位的一组数字，组内不输出前导零
+func readGroup(group string) string {
+	units := []string{"", "十", "百", "千"}
+	var b strings.Builder
+	zero := false
+	for i, r := range group {
+		pos := len(group) - 1 - i
+		d := int(r - '0')
+		if d == 0 {
+			zero = b.Len() > 0
+			continue
+		}
+		if zero {
+			b.WriteString("零")
+			zero = false
+		}
+		if d == 2 && pos >= 2 {
+			b.WriteString("两")
+		} else {
+			b.WriteString(zhDigits[d])
+		}
+		b.WriteString(units[pos])
+	}
+	return b.String()
+}
+
+// replaceSubmatch 与 ReplaceAllStringFunc 类似，但回调能拿到子匹配
+func replaceSubmatch(re *regexp.Regexp, s string, fn func(m []string) string) string {
+	return re.ReplaceAllStringFunc(s, func(match string) string {
+		return fn(re.FindStringSubmatch(match))
+	})
+}
+
+func isASCIILetterBefore(s string, i int) bool {
+	return i > 0 && isASCIILetter(s[i-1])
+}
+
+func isASCIILetterAfter(s string, i int) bool {
+	return i < len(s) && isASCIILetter(s[i])
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+// spaceMixed 在汉字与字母、数字之间补空格，帮助 TTS 切换中英文发音
+func spaceMixed(s string) string {
+	var b strings.Builder
+	var prev rune
+	for i, r := range s {
+		if i > 0 && ((unicode.Is(unicode.Han, prev) && isASCIIAlnum(r)) || (isASCIIAlnum(prev) && unicode.Is(unicode.Han, r))) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+func isASCIIAlnum(r rune) bool {
+	return r < utf8.RuneSelf && (isASCIILetter(byte(r)) || (r >= '0' && r <= '9'))
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.FieldsFunc(s, unicode.IsSpace), " ")
+}
@@ -0,0 +1,89 @@
+package text
+
+import "testing"
+
+func TestNormalizerZh(t *testing.T) {
+	n := NewNormalizer("zh-CN")
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"整数", "今天有25个人", "今天有二十五个人"},
+		{"十几", "第12名", "第十二名"},
+		{"中间零", "共1005元", "共一千零五元"},
+		{"万", "人口约20000", "人口约两万"},
+		{"万级中间零", "售价102030", "售价十万两千零三十"},
+		{"千分位", "大约1,500,000人", "大约一百五十万人"},
+		{"小数", "圆周率约3.14", "圆周率约三点一四"},
+		{"负数", "气温 -5 度", "气温 负五 度"},
+		{"温度", "北京今天25°C", "北京今天二十五摄氏度"},
+		{"温度符号", "最高30℃。", "最高三十摄氏度。"},
+		{"温度范围", "20-25℃之间", "二十到二十五摄氏度之间"},
+		{"百分比", "增长了12.5%", "增长了百分之十二点五"},
+		{"单位", "距离3km，重2kg", "距离三公里，重两公斤"},
+		{"年份", "2024年", "二零二四年"},
+		{"日期", "2024-03-08开会", "二零二四年三月八日开会"},
+		{"时间", "明天10:30出发", "明天十点三十分出发"},
+		{"整点", "8:00起床", "八点整起床"},
+		{"电话", "拨打13800138000", "拨打一三八零零一三八零零零"},
+		{"型号", "GPT-4o很强", "GPT 4o 很强"},
+		{"字母数字", "MP3格式", "MP3 格式"},
+		{"网址", "请访问https://www.example.com/docs?id=1了解", "请访问 example 点 com 了解"},
+		{"邮箱", "发邮件到foo@bar.com。", "发邮件到 foo at bar 点 com。"},
+		{"中英文空格", "用Python写", "用 Python 写"},
+		{"符号", "猫&狗", "猫和狗"},
+		{"量词", "买2个苹果", "买两个苹果"},
+		{"网址句末", "详见https://example.com.", "详见 example 点 com."},
+		{"无需处理", "你好，今天天气不错。", "你好，今天天气不错。"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := n.Normalize(tt.input); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizerEn(t *testing.T) {
+	n := NewNormalizer("en-US")
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"temperature", "It is 25°C today.", "It is 25 degrees Celsius today."},
+		{"percent", "Up 12%", "Up 12 percent"},
+		{"unit", "Run 5km & rest", "Run 5 kilometers and rest"},
+		{"url", "See https://example.com/a for more", "See example dot com for more"},
+		{"email", "Mail me@example.org", "Mail me at example dot org"},
+		{"model", "GPT-4o is fast", "GPT 4o is fast"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := n.Normalize(tt.input); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadInteger(t *testing.T) {
+	tests := map[string]string{
+		"0":         "零",
+		"10":        "十",
+		"20":        "二十",
+		"110":       "一百一十",
+		"200":       "两百",
+		"1000":      "一千",
+		"10001":     "一万零一",
+		"100000000": "一亿",
+		"100010000": "一亿零一万",
+	}
+	for input, want := range tests {
+		if got := readInteger(input); got != want {
+			t.Errorf("readInteger(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -28,6 +28,11 @@ func (s *Segmenter) Feed(text string) []string {
 	return outputs
 }
 
+// Buffered 返回尚未成句的缓冲字符数
+func (s *Segmenter) Buffered() int {
+	return len(s.buffer)
+}
+
 func (s *Segmenter) Flush() string {
 	return s.flushBuffer()
 }
@@ -0,0 +1,198 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// redactedValue 脱敏后的参数值
+const redactedValue = "***"
+
+// AuditRecord 一次工具执行的审计记录，按 JSON Lines 写入审计文件
+type AuditRecord struct {
+	Time       time.Time              `json:"time"`
+	Session    string                 `json:"session"` // 会话的 trace ID，与主日志的 trace_id 相同
+	Turn       uint64                 `json:"turn,omitempty"`
+	Tool       string                 `json:"tool"`
+	Args       map[string]interface{} `json:"args,omitempty"`
+	DurationMs int64                  `json:"duration_ms"`
+	Result     string                 `json:"result,omitempty"` // 结果的 JSON 摘要，超过长度限制时截断
+	Audio      bool                   `json:"audio,omitempty"`  // 工具返回了资源音频
+	Error      string                 `json:"error,omitempty"`
+}
+
+// AuditConfig 工具审计日志配置
+type AuditConfig struct {
+	Path           string   // 审计文件路径，与主日志分开
+	RedactFields   []string // 需要脱敏的参数名（不区分大小写，嵌套对象中同样生效）
+	ResultMaxChars int      // 结果摘要的最大字符数，0 表示不截断
+}
+
+// AuditLog 工具审计日志，并发安全
+type AuditLog struct {
+	mu       sync.Mutex
+	file     *os.File
+	redact   map[string]bool
+	maxChars int
+}
+
+// OpenAuditLog 以追加方式打开审计文件，目录不存在时自动创建
+func OpenAuditLog(config AuditConfig) (*AuditLog, error) {
+	if strings.TrimSpace(config.Path) == "" {
+		return nil, errors.New("audit log path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(config.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("create audit log dir: %w", err)
+	}
+	file, err := os.OpenFile(config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	redact := make(map[string]bool, len(config.RedactFields))
+	for _, field := range config.RedactFields {
+		redact[strings.ToLower(strings.TrimSpace(field))] = true
+	}
+	return &AuditLog{file: file, redact: redact, maxChars: config.ResultMaxChars}, nil
+}
+
+// Record 写入一条审计记录，参数按配置脱敏（不修改传入的参数）
+func (a *AuditLog) Record(record AuditRecord) error {
+	record.Args = a.redactArgs(record.Args)
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err = a.file.Write(append(data, '\n'))
+	return err
+}
+
+// Close 关闭审计文件
+func (a *AuditLog) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.file.Close()
+}
+
+func (a *AuditLog) redactArgs(args map[string]interface{}) map[string]interface{} {
+	if args == nil {
+		return nil
+	}
+	out, _ := a.redactValue(args).(map[string]interface{})
+	return out
+}
+
+// redactValue 复制参数值，敏感字段替换为 ***
+func (a *AuditLog) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if a.redact[strings.ToLower(key)] {
+				out[key] = redactedValue
+				continue
+			}
+			out[key] = a.redactValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			out[i] = a.redactValue(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// summarize 把结果编码为 JSON 并按 maxChars 截断
+func (a *AuditLog) summarize(result interface{}) string {
+	if result == nil {
+		return ""
+	}
+	var summary string
+	if text, ok := result.(string); ok {
+		summary = text
+	} else if data, err := json.Marshal(result); err == nil {
+		summary = string(data)
+	} else {
+		summary = fmt.Sprintf("%v", result)
+	}
+	if runes := []rune(summary); a.maxChars > 0 && len(runes) > a.maxChars {
+		summary = string(runes[:a.maxChars]) + "…"
+	}
+	return summary
+}
+
+// auditedExecutor 为每次 Execute 写入审计记录的 ToolExecutor
+type auditedExecutor struct {
+	ToolExecutor
+	log *AuditLog
+}
+
+// NewAuditedExecutor 包装 executor：每次工具执行（包括失败和取消）写入一条审计记录，
+// 会话和轮次取自 ctx 中的 trace ID / turn ID
+func NewAuditedExecutor(executor ToolExecutor, log *AuditLog) ToolExecutor {
+	return &auditedExecutor{ToolExecutor: executor, log: log}
+}
+
+func (e *auditedExecutor) Execute(ctx context.Context, tool string, args map[string]interface{}) (interface{}, io.Reader, error) {
+	start := time.Now()
+	result, audio, err := e.ToolExecutor.Execute(ctx, tool, args)
+	record := AuditRecord{
+		Time:       start,
+		Session:    logging.CurrentTraceID(ctx),
+		Tool:       tool,
+		Args:       args,
+		DurationMs: time.Since(start).Milliseconds(),
+		Result:     e.log.summarize(result),
+		Audio:      audio != nil,
+	}
+	record.Turn, _ = logging.TurnFromContext(ctx)
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if auditErr := e.log.Record(record); auditErr != nil {
+		logging.WarnfCtx(ctx, "ToolExecutor: write audit record for %s: %v", tool, auditErr)
+	}
+	return result, audio, err
+}
+
+// ReadAuditLog 读取审计文件中指定会话的记录，session 为空时返回全部记录
+func ReadAuditLog(path, session string) ([]AuditRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []AuditRecord
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var record AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if session == "" || record.Session == session {
+			records = append(records, record)
+		}
+	}
+	return records, scanner.Err()
+}
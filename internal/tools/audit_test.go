@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+func TestAuditedExecutor(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit", "tools.jsonl")
+	log, err := OpenAuditLog(AuditConfig{Path: path, RedactFields: []string{"PIN", "token"}, ResultMaxChars: 8})
+	if err != nil {
+		t.Fatalf("OpenAuditLog() error = %v", err)
+	}
+	executor := NewAuditedExecutor(NewToolExecutor(), log)
+	executor.RegisterTool("unlockDoor", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		return map[string]string{"status": "unlocked"}, nil, nil
+	})
+	executor.RegisterTool("broken", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		return nil, nil, errors.New("device offline")
+	})
+
+	args := map[string]interface{}{
+		"door": "front",
+		"pin":  "1234",
+		"auth": map[string]interface{}{"Token": "secret", "user": "bob"},
+	}
+	ctx := logging.WithTurn(logging.WithTraceID(context.Background(), "session-a"), 3)
+	if _, _, err := executor.Execute(ctx, "unlockDoor", args); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if args["pin"] != "1234" {
+		t.Fatal("redaction must not modify the caller's args")
+	}
+	executor.Execute(logging.WithTraceID(context.Background(), "session-b"), "broken", nil)
+	executor.Execute(ctx, "missing", nil)
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	records, err := ReadAuditLog(path, "session-a")
+	if err != nil {
+		t.Fatalf("ReadAuditLog() error = %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records for session-a, want 2", len(records))
+	}
+	unlock := records[0]
+	wantArgs := map[string]interface{}{
+		"door": "front",
+		"pin":  "***",
+		"auth": map[string]interface{}{"Token": "***", "user": "bob"},
+	}
+	if unlock.Tool != "unlockDoor" || unlock.Turn != 3 || !reflect.DeepEqual(unlock.Args, wantArgs) {
+		t.Fatalf("unexpected record: %+v", unlock)
+	}
+	if unlock.Result != `{"status…` || unlock.Error != "" {
+		t.Fatalf("result = %q, error = %q", unlock.Result, unlock.Error)
+	}
+	if records[1].Tool != "missing" || records[1].Error != ErrToolNotFound.Error() {
+		t.Fatalf("unexpected record: %+v", records[1])
+	}
+
+	all, err := ReadAuditLog(path, "")
+	if err != nil || len(all) != 3 {
+		t.Fatalf("ReadAuditLog(all) = %d records, %v", len(all), err)
+	}
+	if broken := all[1]; broken.Session != "session-b" || !strings.Contains(broken.Error, "device offline") {
+		t.Fatalf("unexpected record: %+v", broken)
+	}
+}
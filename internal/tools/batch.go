@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// ToolCall 一次工具调用请求
+type ToolCall struct {
+	Tool string
+	Args map[string]interface{}
+}
+
+// ToolCallResult 单个工具调用的执行结果
+type ToolCallResult struct {
+	ToolCall
+	Result   interface{}
+	Audio    io.Reader
+	Err      error
+	Duration time.Duration
+}
+
+// ToolCallBatch 同一轮回复中的多个工具调用：并发执行，按加入顺序汇总结果
+type ToolCallBatch struct {
+	executor ToolExecutor
+
+	mu       sync.Mutex
+	calls    []ToolCall
+	progress ProgressConfig
+}
+
+// NewToolCallBatch 创建工具调用批次
+func NewToolCallBatch(executor ToolExecutor) *ToolCallBatch {
+	return &ToolCallBatch{executor: executor}
+}
+
+// Add 加入一个工具调用
+func (b *ToolCallBatch) Add(tool string, args map[string]interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls = append(b.calls, ToolCall{Tool: tool, Args: args})
+}
+
+// SetProgress 设置长耗时调用的进度提醒，对之后的 Execute 生效
+func (b *ToolCallBatch) SetProgress(config ProgressConfig) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.progress = config
+}
+
+// Len 返回尚未执行的调用数
+func (b *ToolCallBatch) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.calls)
+}
+
+// Reset 丢弃尚未执行的调用（如回复被打断）
+func (b *ToolCallBatch) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.calls = nil
+}
+
+// Execute 并发执行已加入的全部调用并清空批次，结果顺序与加入顺序一致
+// ctx 取消时进行中的工具随之取消，对应结果的 Err 为 ctx 的错误
+func (b *ToolCallBatch) Execute(ctx context.Context) []ToolCallResult {
+	b.mu.Lock()
+	calls := b.calls
+	b.calls = nil
+	progress := b.progress
+	b.mu.Unlock()
+
+	if len(calls) == 0 {
+		return nil
+	}
+
+	results := make([]ToolCallResult, len(calls))
+	var wg sync.WaitGroup
+	for i, call := range calls {
+		wg.Add(1)
+		go func(i int, call ToolCall) {
+			defer wg.Done()
+			start := time.Now()
+			tracker, callCtx := startProgress(ctx, call.Tool, progress)
+			result, audio, err := b.executor.Execute(callCtx, call.Tool, call.Args)
+			tracker.stop()
+			results[i] = ToolCallResult{
+				ToolCall: call,
+				Result:   result,
+				Audio:    audio,
+				Err:      err,
+				Duration: time.Since(start),
+			}
+		}(i, call)
+	}
+	wg.Wait()
+
+	logging.InfofCtx(ctx, "ToolCallBatch: executed %d tool call(s)", len(calls))
+	return results
+}
@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestToolCallBatchExecute(t *testing.T) {
+	executor := NewToolExecutor()
+	executor.RegisterTool("slow", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		time.Sleep(80 * time.Millisecond)
+		return args["name"], nil, nil
+	})
+	executor.RegisterTool("fast", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		return args["name"], nil, nil
+	})
+
+	batch := NewToolCallBatch(executor)
+	batch.Add("slow", map[string]interface{}{"name": "first"})
+	batch.Add("fast", map[string]interface{}{"name": "second"})
+	batch.Add("missing", nil)
+	batch.Add("slow", map[string]interface{}{"name": "fourth"})
+
+	start := time.Now()
+	results := batch.Execute(context.Background())
+	elapsed := time.Since(start)
+
+	if elapsed > 150*time.Millisecond {
+		t.Fatalf("Execute() took %v, want calls to run concurrently", elapsed)
+	}
+	if len(results) != 4 {
+		t.Fatalf("len(results) = %d, want 4", len(results))
+	}
+	want := []interface{}{"first", "second", nil, "fourth"}
+	for i, result := range results {
+		if result.Result != want[i] {
+			t.Errorf("results[%d].Result = %v, want %v", i, result.Result, want[i])
+		}
+	}
+	if !errors.Is(results[2].Err, ErrToolNotFound) {
+		t.Errorf("results[2].Err = %v, want ErrToolNotFound", results[2].Err)
+	}
+	if batch.Len() != 0 {
+		t.Errorf("batch.Len() = %d after Execute, want 0", batch.Len())
+	}
+	if results := batch.Execute(context.Background()); results != nil {
+		t.Errorf("Execute() on empty batch = %v, want nil", results)
+	}
+}
+
+func TestToolCallBatchProgress(t *testing.T) {
+	executor := NewToolExecutor()
+	executor.RegisterTool("slow", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		time.Sleep(30 * time.Millisecond)
+		ReportProgress(ctx, "已完成一半")
+		time.Sleep(90 * time.Millisecond)
+		return "done", nil, nil
+	})
+	executor.RegisterTool("fast", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		return "done", nil, nil
+	})
+
+	var mu sync.Mutex
+	var got []ToolProgress
+	batch := NewToolCallBatch(executor)
+	batch.SetProgress(ProgressConfig{
+		Delay:    20 * time.Millisecond,
+		Interval: 40 * time.Millisecond,
+		OnProgress: func(p ToolProgress) {
+			mu.Lock()
+			got = append(got, p)
+			mu.Unlock()
+		},
+	})
+	batch.Add("slow", nil)
+	batch.Add("fast", nil)
+	batch.Execute(context.Background())
+
+	mu.Lock()
+	count := len(got)
+	var ticks int
+	var reported bool
+	for _, p := range got {
+		if p.Tool != "slow" {
+			t.Errorf("progress for %q, want only the slow tool", p.Tool)
+		}
+		if p.Message == "" {
+			ticks++
+		} else if p.Message == "已完成一半" {
+			reported = true
+		}
+	}
+	mu.Unlock()
+	// 20ms、60ms、100ms 各提醒一次
+	if ticks < 2 || ticks > 3 {
+		t.Errorf("timer progress = %d, want 2-3", ticks)
+	}
+	if !reported {
+		t.Error("progress reported by the tool not delivered")
+	}
+
+	time.Sleep(80 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != count {
+		t.Errorf("progress delivered after Execute returned: %+v", got[count:])
+	}
+}
+
+func TestToolCallBatchProgressCancelled(t *testing.T) {
+	executor := NewToolExecutor()
+	executor.RegisterTool("slow", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		<-ctx.Done()
+		ReportProgress(ctx, "不会送达")
+		return nil, nil, ctx.Err()
+	})
+
+	var mu sync.Mutex
+	var got []ToolProgress
+	batch := NewToolCallBatch(executor)
+	batch.SetProgress(ProgressConfig{Delay: 50 * time.Millisecond, OnProgress: func(p ToolProgress) {
+		mu.Lock()
+		got = append(got, p)
+		mu.Unlock()
+	}})
+	batch.Add("slow", nil)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	batch.Execute(ctx)
+
+	time.Sleep(60 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	if len(got) != 0 {
+		t.Errorf("progress after cancellation: %+v", got)
+	}
+}
@@ -0,0 +1,190 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/calendar"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+const (
+	defaultEventDuration = time.Hour
+	maxEventQueryDays    = 31
+	eventTimeLayout      = "2006-01-02 15:04"
+	eventDateLayout      = "2006-01-02"
+)
+
+// CalendarToolConfig 日程工具配置
+type CalendarToolConfig struct {
+	Calendar        calendar.Calendar
+	Location        *time.Location   // 解析口语时间和格式化结果使用的时区，nil 时为本地时区
+	DefaultDuration time.Duration    // 未给出结束时间时的日程时长，<=0 时为 1 小时
+	Now             func() time.Time // 测试用，nil 时为 time.Now
+}
+
+func (c CalendarToolConfig) now() time.Time {
+	now := time.Now
+	if c.Now != nil {
+		now = c.Now
+	}
+	loc := c.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	return now().In(loc)
+}
+
+// NewCreateEventTool 创建日程工具：start / end 支持“明天上午十点”这类口语时间，只给日期时创建全天日程
+func NewCreateEventTool(config CalendarToolConfig) ToolExecutorFunc {
+	duration := config.DefaultDuration
+	if duration <= 0 {
+		duration = defaultEventDuration
+	}
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		title := strings.TrimSpace(stringArg(args, "title"))
+		if title == "" {
+			return nil, nil, errors.New("title is required")
+		}
+		now := config.now()
+		start, allDay, err := calendar.ParseTime(stringArg(args, "start"), now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid start: %w", err)
+		}
+		end := start.Add(duration)
+		if allDay {
+			end = start.AddDate(0, 0, 1)
+		}
+		if text := strings.TrimSpace(stringArg(args, "end")); text != "" {
+			if end, err = parseEventEnd(text, start); err != nil {
+				return nil, nil, fmt.Errorf("invalid end: %w", err)
+			}
+		}
+		if !end.After(start) {
+			return nil, nil, fmt.Errorf("end %s is not after start %s", end.Format(eventTimeLayout), start.Format(eventTimeLayout))
+		}
+
+		event, err := config.Calendar.CreateEvent(ctx, calendar.Event{
+			Title:    title,
+			Start:    start,
+			End:      end,
+			AllDay:   allDay,
+			Location: strings.TrimSpace(stringArg(args, "location")),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		logging.InfofCtx(ctx, "CreateEventTool: created event %q at %s", event.Title, event.Start.Format(eventTimeLayout))
+		result := formatEvent(event)
+		result["status"] = "created"
+		return result, nil, nil
+	}
+}
+
+// NewGetEventsTool 查询日程工具：date 为起始日期（默认今天），days 为天数（默认 1）
+func NewGetEventsTool(config CalendarToolConfig) ToolExecutorFunc {
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		now := config.now()
+		from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		if text := strings.TrimSpace(stringArg(args, "date")); text != "" {
+			parsed, _, err := calendar.ParseTime(text, now)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid date: %w", err)
+			}
+			from = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, parsed.Location())
+		}
+		days, err := parseDays(args["days"])
+		if err != nil {
+			return nil, nil, err
+		}
+		to := from.AddDate(0, 0, days)
+
+		events, err := config.Calendar.ListEvents(ctx, from, to)
+		if err != nil {
+			return nil, nil, err
+		}
+		logging.InfofCtx(ctx, "GetEventsTool: %d events from %s, %d day(s)", len(events), from.Format(eventDateLayout), days)
+		items := make([]map[string]interface{}, 0, len(events))
+		for _, event := range events {
+			items = append(items, formatEvent(event))
+		}
+		return map[string]interface{}{
+			"from":   from.Format(eventDateLayout),
+			"to":     to.AddDate(0, 0, -1).Format(eventDateLayout),
+			"count":  len(items),
+			"events": items,
+		}, nil, nil
+	}
+}
+
+// parseEventEnd 以开始时间为基准解析结束时间，“十一点”指开始当天的十一点；
+// 开始于下午时“四点”按下午四点理解，而不是次日凌晨。只给日期时结束于该日末尾
+func parseEventEnd(text string, start time.Time) (time.Time, error) {
+	end, dateOnly, err := calendar.ParseTime(text, start)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if dateOnly {
+		return end.AddDate(0, 0, 1), nil
+	}
+	if start.Hour() >= 12 && end.YearDay() != start.YearDay() {
+		if afternoon, _, err := calendar.ParseTime("下午"+text, start); err == nil && afternoon.After(start) && afternoon.YearDay() == start.YearDay() {
+			return afternoon, nil
+		}
+	}
+	return end, nil
+}
+
+// formatEvent 把日程转为返回给 LLM 的结果，全天日程只给日期
+func formatEvent(event calendar.Event) map[string]interface{} {
+	result := map[string]interface{}{
+		"id":      event.ID,
+		"title":   event.Title,
+		"all_day": event.AllDay,
+	}
+	if event.AllDay {
+		result["start"] = event.Start.Format(eventDateLayout)
+		result["end"] = event.End.AddDate(0, 0, -1).Format(eventDateLayout)
+	} else {
+		result["start"] = event.Start.Format(eventTimeLayout)
+		result["end"] = event.End.Format(eventTimeLayout)
+	}
+	if event.Location != "" {
+		result["location"] = event.Location
+	}
+	return result
+}
+
+// parseDays 解析 1~31 的天数
+func parseDays(value interface{}) (int, error) {
+	if value == nil {
+		return 1, nil
+	}
+	days, err := parsePositiveInt("days", value)
+	if err != nil {
+		return 0, err
+	}
+	return min(days, maxEventQueryDays), nil
+}
+
+// parsePositiveInt 解析正整数参数，LLM 可能传字符串或数字
+func parsePositiveInt(name string, value interface{}) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(formatArg(value)))
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid %s %v", name, value)
+	}
+	return n, nil
+}
+
+func stringArg(args map[string]interface{}, name string) string {
+	value, ok := args[name]
+	if !ok || value == nil {
+		return ""
+	}
+	return formatArg(value)
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/calendar"
+)
+
+// fakeCalendar 记录创建的日程，查询时返回与范围有交集的日程
+type fakeCalendar struct {
+	events   []calendar.Event
+	from, to time.Time
+}
+
+func (f *fakeCalendar) CreateEvent(ctx context.Context, event calendar.Event) (calendar.Event, error) {
+	event.ID = "evt-1"
+	f.events = append(f.events, event)
+	return event, nil
+}
+
+func (f *fakeCalendar) ListEvents(ctx context.Context, from, to time.Time) ([]calendar.Event, error) {
+	f.from, f.to = from, to
+	var events []calendar.Event
+	for _, event := range f.events {
+		if event.End.After(from) && event.Start.Before(to) {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func TestCreateEventTool(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	// 2024-10-15 是星期二
+	now := time.Date(2024, 10, 15, 9, 0, 0, 0, loc)
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantStart string
+		wantEnd   string
+		wantAll   bool
+		wantErr   bool
+	}{
+		{name: "spoken start with default duration", args: map[string]interface{}{"title": "评审", "start": "明天上午十点"}, wantStart: "2024-10-16 10:00", wantEnd: "2024-10-16 10:30"},
+		{name: "spoken end relative to start", args: map[string]interface{}{"title": "评审", "start": "下周一下午两点", "end": "四点"}, wantStart: "2024-10-21 14:00", wantEnd: "2024-10-21 16:00"},
+		{name: "iso start", args: map[string]interface{}{"title": "评审", "start": "2024-10-20 08:15"}, wantStart: "2024-10-20 08:15", wantEnd: "2024-10-20 08:45"},
+		{name: "date only is all day", args: map[string]interface{}{"title": "假期", "start": "后天"}, wantStart: "2024-10-17", wantEnd: "2024-10-17", wantAll: true},
+		{name: "all day range", args: map[string]interface{}{"title": "出差", "start": "10月20日", "end": "10月22日"}, wantStart: "2024-10-20", wantEnd: "2024-10-22", wantAll: true},
+		{name: "missing title", args: map[string]interface{}{"start": "明天上午十点"}, wantErr: true},
+		{name: "invalid start", args: map[string]interface{}{"title": "评审", "start": "改天"}, wantErr: true},
+		{name: "end before start", args: map[string]interface{}{"title": "评审", "start": "明天上午十点", "end": "2024-10-16 09:00"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cal := &fakeCalendar{}
+			tool := NewCreateEventTool(CalendarToolConfig{
+				Calendar:        cal,
+				Location:        loc,
+				DefaultDuration: 30 * time.Minute,
+				Now:             func() time.Time { return now },
+			})
+			result, _, err := tool(context.Background(), tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if len(cal.events) != 0 {
+					t.Errorf("event created on error: %+v", cal.events)
+				}
+				return
+			}
+			got := result.(map[string]interface{})
+			if got["start"] != tt.wantStart || got["end"] != tt.wantEnd || got["all_day"] != tt.wantAll || got["status"] != "created" {
+				t.Errorf("result = %v, want start %s end %s all_day %v", got, tt.wantStart, tt.wantEnd, tt.wantAll)
+			}
+		})
+	}
+}
+
+func TestGetEventsTool(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	now := time.Date(2024, 10, 15, 21, 0, 0, 0, loc)
+	cal := &fakeCalendar{events: []calendar.Event{
+		{ID: "a", Title: "周会", Start: time.Date(2024, 10, 16, 10, 0, 0, 0, loc), End: time.Date(2024, 10, 16, 11, 0, 0, 0, loc), Location: "三楼"},
+		{ID: "b", Title: "团建", Start: time.Date(2024, 10, 18, 0, 0, 0, 0, loc), End: time.Date(2024, 10, 19, 0, 0, 0, 0, loc), AllDay: true},
+	}}
+	tool := NewGetEventsTool(CalendarToolConfig{Calendar: cal, Location: loc, Now: func() time.Time { return now }})
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantFrom  string
+		wantTo    string
+		wantCount int
+		wantErr   bool
+	}{
+		{name: "default today", args: map[string]interface{}{}, wantFrom: "2024-10-15", wantTo: "2024-10-15", wantCount: 0},
+		{name: "tomorrow", args: map[string]interface{}{"date": "明天"}, wantFrom: "2024-10-16", wantTo: "2024-10-16", wantCount: 1},
+		{name: "days as number", args: map[string]interface{}{"date": "明天", "days": float64(3)}, wantFrom: "2024-10-16", wantTo: "2024-10-18", wantCount: 2},
+		{name: "days as string", args: map[string]interface{}{"days": "7"}, wantFrom: "2024-10-15", wantTo: "2024-10-21", wantCount: 2},
+		{name: "days capped", args: map[string]interface{}{"days": float64(100)}, wantFrom: "2024-10-15", wantTo: "2024-11-14", wantCount: 2},
+		{name: "invalid days", args: map[string]interface{}{"days": float64(0)}, wantErr: true},
+		{name: "invalid date", args: map[string]interface{}{"date": "改天"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, err := tool(context.Background(), tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := result.(map[string]interface{})
+			if got["from"] != tt.wantFrom || got["to"] != tt.wantTo || got["count"] != tt.wantCount {
+				t.Errorf("result = %v, want %s..%s count %d", got, tt.wantFrom, tt.wantTo, tt.wantCount)
+			}
+		})
+	}
+
+	result, _, _ := tool(context.Background(), map[string]interface{}{"days": float64(7)})
+	events := result.(map[string]interface{})["events"].([]map[string]interface{})
+	if events[0]["start"] != "2024-10-16 10:00" || events[0]["location"] != "三楼" || events[1]["start"] != "2024-10-18" || events[1]["end"] != "2024-10-18" {
+		t.Errorf("events = %v", events)
+	}
+}
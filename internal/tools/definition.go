@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Parameter 工具参数定义（JSON Schema 的常用子集）
+type Parameter struct {
+	Type        string // string、integer、number、boolean
+	Description string
+	Required    bool
+	Enum        []string
+	Ask         string // 缺少该必填参数时向用户追问的话术，为空时按 Description 生成
+}
+
+// ToolDefinition 工具的声明式定义，同时用于绑定到 LLM（function calling）和渲染系统提示词
+type ToolDefinition struct {
+	Name        string
+	Description string
+	Action      bool // 动作类工具（直接执行并播报），否则为查询类
+	Parameters  map[string]Parameter
+}
+
+// JSONSchema 返回参数的 JSON Schema（type: object）
+func (d ToolDefinition) JSONSchema() map[string]interface{} {
+	properties := make(map[string]interface{}, len(d.Parameters))
+	required := make([]string, 0)
+	for _, name := range d.ParameterNames() {
+		param := d.Parameters[name]
+		prop := map[string]interface{}{
+			"type":        param.Type,
+			"description": param.Description,
+		}
+		if len(param.Enum) > 0 {
+			prop["enum"] = param.Enum
+		}
+		properties[name] = prop
+		if param.Required {
+			required = append(required, name)
+		}
+	}
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// ParameterNames 按名称排序的参数名，保证输出稳定
+func (d ToolDefinition) ParameterNames() []string {
+	names := make([]string, 0, len(d.Parameters))
+	for name := range d.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MissingParameters 返回 args 中缺少（或为空字符串）的必填参数名，按名称排序
+func (d ToolDefinition) MissingParameters(args map[string]interface{}) []string {
+	var missing []string
+	for _, name := range d.ParameterNames() {
+		if !d.Parameters[name].Required {
+			continue
+		}
+		switch value := args[name].(type) {
+		case nil:
+			missing = append(missing, name)
+		case string:
+			if strings.TrimSpace(value) == "" {
+				missing = append(missing, name)
+			}
+		}
+	}
+	return missing
+}
+
+// AskFor 返回向用户询问参数 name 的话术：优先使用 Ask，否则取 Description 的第一个分句生成
+func (d ToolDefinition) AskFor(name string) string {
+	param := d.Parameters[name]
+	if ask := strings.TrimSpace(param.Ask); ask != "" {
+		return ask
+	}
+	subject := strings.TrimSpace(param.Description)
+	if i := strings.IndexAny(subject, "，,；;（("); i >= 0 {
+		subject = strings.TrimSpace(subject[:i])
+	}
+	if subject == "" {
+		subject = name
+	}
+	return fmt.Sprintf("请问%s是什么？", subject)
+}
+
+// Summary 一行工具说明（描述 + 参数），用于系统提示词
+func (d ToolDefinition) Summary() string {
+	if len(d.Parameters) == 0 {
+		return d.Description
+	}
+	params := make([]string, 0, len(d.Parameters))
+	for _, name := range d.ParameterNames() {
+		param := d.Parameters[name]
+		required := "可选"
+		if param.Required {
+			required = "必填"
+		}
+		params = append(params, fmt.Sprintf("%s（%s，%s）：%s", name, param.Type, required, param.Description))
+	}
+	return d.Description + "。参数：" + strings.Join(params, "；")
+}
+
+// 内置工具定义
+var (
+	GetTimeDefinition = ToolDefinition{
+		Name:        "getTime",
+		Description: "获取当前时间，返回日期、时间、星期、时区等信息",
+	}
+	GetWeatherDefinition = ToolDefinition{
+		Name:        "getWeather",
+		Description: "获取指定城市的天气信息",
+		Parameters: map[string]Parameter{
+			"city": {Type: "string", Description: "城市名称", Required: true, Ask: "请问要查哪个城市？"},
+		},
+	}
+	SearchDefinition = ToolDefinition{
+		Name:        "search",
+		Description: "搜索网络信息",
+		Parameters: map[string]Parameter{
+			"query": {Type: "string", Description: "搜索关键词", Required: true, Ask: "请问要搜索什么？"},
+		},
+	}
+	PlayMusicDefinition = ToolDefinition{
+		Name:        "playMusic",
+		Description: "播放指定歌曲",
+		Action:      true,
+		Parameters: map[string]Parameter{
+			"song": {Type: "string", Description: "歌曲名称", Required: true, Ask: "想听哪首歌？"},
+		},
+	}
+	PauseMusicDefinition = ToolDefinition{
+		Name:        "pauseMusic",
+		Description: "暂停正在播放的音乐",
+		Action:      true,
+	}
+	SetVolumeDefinition = ToolDefinition{
+		Name:        "setVolume",
+		Description: "设置播放音量",
+		Action:      true,
+		Parameters: map[string]Parameter{
+			"level": {Type: "string", Description: "音量大小（0-100）", Required: true, Ask: "音量调到多少？"},
+		},
+	}
+)
+
+// 日程工具定义（需要配置 calendar 后才会注册）
+var (
+	CreateEventDefinition = ToolDefinition{
+		Name:        "createEvent",
+		Description: "在日历中创建日程",
+		Parameters: map[string]Parameter{
+			"title":    {Type: "string", Description: "日程标题", Required: true, Ask: "日程的标题是什么？"},
+			"start":    {Type: "string", Description: "开始时间，可直接使用用户原话，如“明天上午十点”，或 2006-01-02 15:04", Required: true, Ask: "日程什么时候开始？"},
+			"end":      {Type: "string", Description: "结束时间，格式同 start；不填时按默认时长"},
+			"location": {Type: "string", Description: "地点"},
+		},
+	}
+	GetEventsDefinition = ToolDefinition{
+		Name:        "getEvents",
+		Description: "查询日历中的日程",
+		Parameters: map[string]Parameter{
+			"date": {Type: "string", Description: "查询的起始日期，如“今天”“明天”“下周一”或 2006-01-02；不填为今天"},
+			"days": {Type: "integer", Description: "查询的天数，默认 1，最多 31"},
+		},
+	}
+)
@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToolDefinitionMissingParameters(t *testing.T) {
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want []string
+	}{
+		{"all present", map[string]interface{}{"title": "开会", "start": "明天上午十点"}, nil},
+		{"nil args", nil, []string{"start", "title"}},
+		{"blank string", map[string]interface{}{"title": "开会", "start": "  "}, []string{"start"}},
+		{"explicit null", map[string]interface{}{"title": nil, "start": "明天"}, []string{"title"}},
+		{"optional missing", map[string]interface{}{"title": "开会", "start": "明天", "end": ""}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CreateEventDefinition.MissingParameters(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MissingParameters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToolDefinitionAskFor(t *testing.T) {
+	definition := ToolDefinition{Name: "quote", Parameters: map[string]Parameter{
+		"symbol": {Description: "股票代码，如 600519", Required: true},
+		"market": {Required: true},
+	}}
+	tests := []struct {
+		definition ToolDefinition
+		param      string
+		want       string
+	}{
+		{GetWeatherDefinition, "city", "请问要查哪个城市？"},
+		{definition, "symbol", "请问股票代码是什么？"},
+		{definition, "market", "请问market是什么？"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.param, func(t *testing.T) {
+			if got := tt.definition.AskFor(tt.param); got != tt.want {
+				t.Errorf("AskFor(%q) = %q, want %q", tt.param, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,20 +1,27 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"sort"
 
 	"github.com/liuscraft/orion-x/internal/logging"
 )
 
 // ToolExecutor 工具执行器接口
+// ctx 随 Agent 一起在打断时取消，耗时的工具（HTTP 请求等）应据此中止
 type ToolExecutor interface {
-	Execute(tool string, args map[string]interface{}) (result interface{}, audio io.Reader, err error)
+	Execute(ctx context.Context, tool string, args map[string]interface{}) (result interface{}, audio io.Reader, err error)
 	RegisterTool(name string, executor ToolExecutorFunc)
+	// Register 按声明式定义注册工具，定义会通过 Definitions 暴露给 LLM
+	Register(definition ToolDefinition, executor ToolExecutorFunc)
+	// Definitions 返回已注册的工具定义（按名称排序）
+	Definitions() []ToolDefinition
 }
 
 // ToolExecutorFunc 工具执行函数
-type ToolExecutorFunc func(args map[string]interface{}) (interface{}, io.Reader, error)
+type ToolExecutorFunc func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error)
 
 // ToolResult 工具执行结果
 type ToolResult struct {
@@ -25,12 +32,14 @@ type ToolResult struct {
 
 // ToolRegistry 工具注册表
 type ToolRegistry struct {
-	tools map[string]ToolExecutorFunc
+	tools       map[string]ToolExecutorFunc
+	definitions map[string]ToolDefinition
 }
 
 func NewToolRegistry() *ToolRegistry {
 	return &ToolRegistry{
-		tools: make(map[string]ToolExecutorFunc),
+		tools:       make(map[string]ToolExecutorFunc),
+		definitions: make(map[string]ToolDefinition),
 	}
 }
 
@@ -38,12 +47,42 @@ func (r *ToolRegistry) RegisterTool(name string, executor ToolExecutorFunc) {
 	r.tools[name] = executor
 }
 
-func (r *ToolRegistry) Execute(tool string, args map[string]interface{}) (interface{}, io.Reader, error) {
+// Register 注册工具及其定义
+func (r *ToolRegistry) Register(definition ToolDefinition, executor ToolExecutorFunc) {
+	r.tools[definition.Name] = executor
+	r.definitions[definition.Name] = definition
+}
+
+// Definitions 返回已注册的工具定义（按名称排序）
+func (r *ToolRegistry) Definitions() []ToolDefinition {
+	definitions := make([]ToolDefinition, 0, len(r.definitions))
+	for _, definition := range r.definitions {
+		definitions = append(definitions, definition)
+	}
+	sort.Slice(definitions, func(i, j int) bool { return definitions[i].Name < definitions[j].Name })
+	return definitions
+}
+
+func (r *ToolRegistry) Execute(ctx context.Context, tool string, args map[string]interface{}) (interface{}, io.Reader, error) {
 	executor, ok := r.tools[tool]
 	if !ok {
 		return nil, nil, ErrToolNotFound
 	}
-	return executor(args)
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	result, audio, err := executor(ctx, args)
+	// 工具未感知 ctx 时，取消后的结果同样丢弃
+	if ctxErr := ctx.Err(); ctxErr != nil && err == nil {
+		if closer, ok := audio.(io.Closer); ok {
+			closer.Close()
+		}
+		return nil, nil, ctxErr
+	}
+	return result, audio, err
 }
 
 // ToolExecutor 实现ToolExecutor接口
@@ -57,9 +96,9 @@ func NewToolExecutor() ToolExecutor {
 	}
 }
 
-func (e *toolExecutor) Execute(tool string, args map[string]interface{}) (interface{}, io.Reader, error) {
-	logging.Infof("ToolExecutor: executing tool: %s, args: %v", tool, args)
-	return e.registry.Execute(tool, args)
+func (e *toolExecutor) Execute(ctx context.Context, tool string, args map[string]interface{}) (interface{}, io.Reader, error) {
+	logging.InfofCtx(ctx, "ToolExecutor: executing tool: %s, args: %v", tool, args)
+	return e.registry.Execute(ctx, tool, args)
 }
 
 func (e *toolExecutor) RegisterTool(name string, executor ToolExecutorFunc) {
@@ -67,6 +106,15 @@ func (e *toolExecutor) RegisterTool(name string, executor ToolExecutorFunc) {
 	e.registry.RegisterTool(name, executor)
 }
 
+func (e *toolExecutor) Register(definition ToolDefinition, executor ToolExecutorFunc) {
+	logging.Infof("ToolExecutor: registered tool: %s (with definition)", definition.Name)
+	e.registry.Register(definition, executor)
+}
+
+func (e *toolExecutor) Definitions() []ToolDefinition {
+	return e.registry.Definitions()
+}
+
 // 错误定义
 var (
 	ErrToolNotFound = fmt.Errorf("tool not found")
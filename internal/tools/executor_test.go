@@ -0,0 +1,98 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestToolExecutorCancellation(t *testing.T) {
+	executor := NewToolExecutor()
+	executor.RegisterTool("slow", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+			return "done", nil, nil
+		}
+	})
+	executor.RegisterTool("unaware", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		time.Sleep(50 * time.Millisecond)
+		return "stale", nil, nil
+	})
+
+	tests := []struct {
+		name   string
+		tool   string
+		cancel time.Duration
+	}{
+		{name: "cancel in flight", tool: "slow", cancel: 20 * time.Millisecond},
+		{name: "already cancelled", tool: "slow", cancel: 0},
+		{name: "tool ignores context", tool: "unaware", cancel: 10 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			if tt.cancel == 0 {
+				cancel()
+			} else {
+				time.AfterFunc(tt.cancel, cancel)
+			}
+			defer cancel()
+
+			start := time.Now()
+			result, _, err := executor.Execute(ctx, tt.tool, nil)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("Execute() error = %v, want context.Canceled", err)
+			}
+			if result != nil {
+				t.Fatalf("Execute() result = %v, want nil after cancellation", result)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("Execute() took %v after cancellation", elapsed)
+			}
+		})
+	}
+}
+
+func TestToolExecutorNotFound(t *testing.T) {
+	executor := NewToolExecutor()
+	if _, _, err := executor.Execute(context.Background(), "missing", nil); !errors.Is(err, ErrToolNotFound) {
+		t.Fatalf("Execute() error = %v, want ErrToolNotFound", err)
+	}
+}
+
+func TestToolExecutorDefinitions(t *testing.T) {
+	executor := NewToolExecutor()
+	executor.Register(GetWeatherDefinition, GetWeatherTool)
+	executor.Register(GetTimeDefinition, GetTimeTool)
+	executor.RegisterTool("hidden", GetTimeTool)
+
+	definitions := executor.Definitions()
+	if len(definitions) != 2 || definitions[0].Name != "getTime" || definitions[1].Name != "getWeather" {
+		t.Fatalf("Definitions() = %+v, want getTime and getWeather sorted by name", definitions)
+	}
+	if _, _, err := executor.Execute(context.Background(), "hidden", nil); err != nil {
+		t.Fatalf("tool registered without definition should still execute: %v", err)
+	}
+}
+
+func TestToolDefinitionSchema(t *testing.T) {
+	schema := GetWeatherDefinition.JSONSchema()
+	if schema["type"] != "object" {
+		t.Fatalf("type = %v, want object", schema["type"])
+	}
+	properties := schema["properties"].(map[string]interface{})
+	city, ok := properties["city"].(map[string]interface{})
+	if !ok || city["type"] != "string" {
+		t.Fatalf("properties = %v, want city string", properties)
+	}
+	if required := schema["required"].([]string); len(required) != 1 || required[0] != "city" {
+		t.Fatalf("required = %v, want [city]", required)
+	}
+	if got, want := GetWeatherDefinition.Summary(), "获取指定城市的天气信息。参数：city（string，必填）：城市名称"; got != want {
+		t.Fatalf("Summary() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,342 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+const (
+	// httpToolMaxResponse 响应体的读取上限，超出部分丢弃
+	httpToolMaxResponse = 1 << 20
+	// httpToolErrorSnippet 错误信息中保留的响应体长度
+	httpToolErrorSnippet = 200
+)
+
+// HTTPToolConfig 配置中声明的 HTTP 工具：按模板拼出请求，调用后从 JSON 响应中取出结果交给 LLM
+// 模板中的 {{参数名}} 替换为 LLM 传入的参数，{{env:NAME}} 替换为环境变量（用于令牌等不写进配置的值）
+type HTTPToolConfig struct {
+	Definition ToolDefinition
+	Method     string            // 请求方法，为空时为 GET
+	URL        string            // URL 模板，? 之前的值按路径转义，之后的按查询参数转义
+	Headers    map[string]string // 请求头模板
+	// Body 请求体模板：Content-Type 为 JSON（未设置时请求体以 { 或 [ 开头也按 JSON）时字符串值按 JSON 转义，
+	// 为 application/x-www-form-urlencoded 时按表单转义，否则原样替换
+	Body string
+	// Result 结果提取路径（JSONPath 子集，如 $.data.items[0].name、$.list[*].title），为空时返回整个响应
+	Result  string
+	Timeout time.Duration // 请求超时，0 表示只受 ctx 控制
+}
+
+// placeholderPattern 匹配模板中的 {{name}} / {{env:NAME}}
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_:.\-]+)\s*\}\}`)
+
+// bodyEncoding 请求体中参数值的转义方式
+type bodyEncoding int
+
+const (
+	bodyRaw bodyEncoding = iota
+	bodyJSON
+	bodyForm
+)
+
+// httpTool 已校验的 HTTP 工具
+type httpTool struct {
+	config HTTPToolConfig
+	path   []jsonPathStep
+	body   bodyEncoding
+	client *http.Client
+}
+
+// NewHTTPTool 校验配置并创建工具执行函数：模板只能引用已声明的参数，结果路径必须可解析；client 为 nil 时使用 http.DefaultClient
+func NewHTTPTool(config HTTPToolConfig, client *http.Client) (ToolExecutorFunc, error) {
+	name := config.Definition.Name
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.New("http tool: name is required")
+	}
+	config.Method = strings.ToUpper(strings.TrimSpace(config.Method))
+	if config.Method == "" {
+		config.Method = http.MethodGet
+	}
+	parsed, err := url.Parse(placeholderPattern.ReplaceAllString(config.URL, "x"))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("http tool %s: invalid url %q", name, config.URL)
+	}
+	templates := []string{config.URL, config.Body}
+	for _, value := range config.Headers {
+		templates = append(templates, value)
+	}
+	for _, template := range templates {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(template, -1) {
+			key := match[1]
+			if strings.HasPrefix(key, "env:") {
+				continue
+			}
+			if _, ok := config.Definition.Parameters[key]; !ok {
+				return nil, fmt.Errorf("http tool %s: template references undeclared parameter %q", name, key)
+			}
+		}
+	}
+	path, err := parseJSONPath(config.Result)
+	if err != nil {
+		return nil, fmt.Errorf("http tool %s: %w", name, err)
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	tool := &httpTool{config: config, path: path, body: detectBodyEncoding(config.Headers, config.Body), client: client}
+	return tool.execute, nil
+}
+
+// detectBodyEncoding 按 Content-Type 决定请求体的转义方式
+func detectBodyEncoding(headers map[string]string, body string) bodyEncoding {
+	for key, value := range headers {
+		if !strings.EqualFold(key, "Content-Type") {
+			continue
+		}
+		switch {
+		case strings.Contains(value, "json"):
+			return bodyJSON
+		case strings.Contains(value, "x-www-form-urlencoded"):
+			return bodyForm
+		default:
+			return bodyRaw
+		}
+	}
+	if trimmed := strings.TrimSpace(body); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return bodyJSON
+	}
+	return bodyRaw
+}
+
+func (t *httpTool) execute(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+	for name, param := range t.config.Definition.Parameters {
+		if _, ok := args[name]; param.Required && !ok {
+			return nil, nil, fmt.Errorf("missing required parameter %q", name)
+		}
+	}
+	if t.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.config.Timeout)
+		defer cancel()
+	}
+
+	target := t.renderURL(args)
+	var body io.Reader
+	if t.config.Body != "" {
+		body = strings.NewReader(renderTemplate(t.config.Body, args, t.escapeBody))
+	}
+	req, err := http.NewRequestWithContext(ctx, t.config.Method, target, body)
+	if err != nil {
+		return nil, nil, err
+	}
+	for key, value := range t.config.Headers {
+		req.Header.Set(key, renderTemplate(value, args, nil))
+	}
+	if body != nil && req.Header.Get("Content-Type") == "" && t.body == bodyJSON {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, httpToolMaxResponse))
+	if err != nil {
+		return nil, nil, err
+	}
+	// 查询参数可能带令牌，日志只记录主机和路径
+	logging.InfofCtx(ctx, "HTTPTool: %s %s %s%s -> %d in %v", t.config.Definition.Name, t.config.Method, req.URL.Host, req.URL.Path,
+		resp.StatusCode, time.Since(start).Round(time.Millisecond))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, nil, fmt.Errorf("http %d: %s", resp.StatusCode, snippet(string(data), httpToolErrorSnippet))
+	}
+
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		if len(t.path) > 0 {
+			return nil, nil, fmt.Errorf("response is not JSON: %s", snippet(string(data), httpToolErrorSnippet))
+		}
+		return strings.TrimSpace(string(data)), nil, nil
+	}
+	result, err := extractJSONPath(decoded, t.path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("extract %s: %w", t.config.Result, err)
+	}
+	return result, nil, nil
+}
+
+// renderURL 替换 URL 模板：? 之前按路径转义，之后按查询参数转义
+func (t *httpTool) renderURL(args map[string]interface{}) string {
+	path, query, hasQuery := strings.Cut(t.config.URL, "?")
+	rendered := renderTemplate(path, args, url.PathEscape)
+	if hasQuery {
+		rendered += "?" + renderTemplate(query, args, url.QueryEscape)
+	}
+	return rendered
+}
+
+func (t *httpTool) escapeBody(value string) string {
+	switch t.body {
+	case bodyJSON:
+		encoded, _ := json.Marshal(value)
+		return string(encoded[1 : len(encoded)-1])
+	case bodyForm:
+		return url.QueryEscape(value)
+	default:
+		return value
+	}
+}
+
+// renderTemplate 替换 {{参数名}} 和 {{env:NAME}}，缺少的参数替换为空字符串；escape 为 nil 时原样替换
+func renderTemplate(template string, args map[string]interface{}, escape func(string) string) string {
+	return placeholderPattern.ReplaceAllStringFunc(template, func(match string) string {
+		key := placeholderPattern.FindStringSubmatch(match)[1]
+		var value string
+		if name, ok := strings.CutPrefix(key, "env:"); ok {
+			value = os.Getenv(name)
+		} else if arg, ok := args[key]; ok && arg != nil {
+			value = formatArg(arg)
+		}
+		if escape != nil {
+			value = escape(value)
+		}
+		return value
+	})
+}
+
+// formatArg 参数值转为字符串，整数值的 float64（JSON 数字）不带小数点
+func formatArg(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func snippet(text string, max int) string {
+	text = strings.TrimSpace(text)
+	if runes := []rune(text); len(runes) > max {
+		return string(runes[:max]) + "…"
+	}
+	return text
+}
+
+// jsonPathStep 结果路径的一步：对象字段、数组下标或 [*]（对数组每一项应用剩余路径）
+type jsonPathStep struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath 解析 $.a.b[0].c、$.list[*].title、$['a b'] 形式的路径，$ 可省略
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	path = strings.TrimSpace(path)
+	path = strings.TrimPrefix(path, "$")
+	var steps []jsonPathStep
+	for path != "" {
+		switch path[0] {
+		case '.':
+			path = path[1:]
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid result path: empty field")
+			}
+			steps = append(steps, jsonPathStep{field: path[:end]})
+			path = path[end:]
+		case '[':
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid result path: missing ]")
+			}
+			inner := strings.TrimSpace(path[1:end])
+			path = path[end+1:]
+			switch {
+			case inner == "*":
+				steps = append(steps, jsonPathStep{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				steps = append(steps, jsonPathStep{field: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid result path: bad index %q", inner)
+				}
+				steps = append(steps, jsonPathStep{index: index, isIndex: true})
+			}
+		default:
+			// 允许省略开头的 $.
+			if len(steps) > 0 {
+				return nil, fmt.Errorf("invalid result path near %q", path)
+			}
+			path = "." + path
+		}
+	}
+	return steps, nil
+}
+
+// extractJSONPath 按路径取值，负数下标从末尾计数
+func extractJSONPath(value interface{}, steps []jsonPathStep) (interface{}, error) {
+	for i, step := range steps {
+		switch {
+		case step.wildcard:
+			items, ok := value.([]interface{})
+			if !ok {
+				return nil, errors.New("[*] applied to non-array")
+			}
+			out := make([]interface{}, 0, len(items))
+			for _, item := range items {
+				extracted, err := extractJSONPath(item, steps[i+1:])
+				if err != nil {
+					continue
+				}
+				out = append(out, extracted)
+			}
+			return out, nil
+		case step.isIndex:
+			items, ok := value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("index %d applied to non-array", step.index)
+			}
+			index := step.index
+			if index < 0 {
+				index += len(items)
+			}
+			if index < 0 || index >= len(items) {
+				return nil, fmt.Errorf("index %d out of range (%d items)", step.index, len(items))
+			}
+			value = items[index]
+		default:
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("field %q applied to non-object", step.field)
+			}
+			if value, ok = object[step.field]; !ok {
+				return nil, fmt.Errorf("field %q not found", step.field)
+			}
+		}
+	}
+	return value, nil
+}
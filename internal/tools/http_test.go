@@ -0,0 +1,161 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewHTTPToolValidation(t *testing.T) {
+	definition := ToolDefinition{
+		Name:       "getQuote",
+		Parameters: map[string]Parameter{"symbol": {Type: "string", Required: true}},
+	}
+	tests := []struct {
+		name    string
+		config  HTTPToolConfig
+		wantErr string
+	}{
+		{name: "valid", config: HTTPToolConfig{Definition: definition, URL: "https://api.example.com/quote/{{symbol}}", Result: "$.data.price"}},
+		{name: "env placeholder", config: HTTPToolConfig{Definition: definition, URL: "https://api.example.com/q", Headers: map[string]string{"Authorization": "Bearer {{env:QUOTE_TOKEN}}"}}},
+		{name: "missing name", config: HTTPToolConfig{URL: "https://api.example.com"}, wantErr: "name is required"},
+		{name: "bad scheme", config: HTTPToolConfig{Definition: definition, URL: "ftp://example.com"}, wantErr: "invalid url"},
+		{name: "undeclared parameter", config: HTTPToolConfig{Definition: definition, URL: "https://api.example.com/{{ticker}}"}, wantErr: `undeclared parameter "ticker"`},
+		{name: "bad result path", config: HTTPToolConfig{Definition: definition, URL: "https://api.example.com", Result: "$.items[x]"}, wantErr: "bad index"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHTTPTool(tt.config, nil)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("NewHTTPTool() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("NewHTTPTool() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHTTPToolExecute(t *testing.T) {
+	t.Setenv("HTTP_TOOL_TOKEN", "secret-token")
+
+	var gotMethod, gotPath, gotQuery, gotAuth, gotType, gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotQuery = r.Method, r.URL.EscapedPath(), r.URL.RawQuery
+		gotAuth, gotType = r.Header.Get("Authorization"), r.Header.Get("Content-Type")
+		data, _ := io.ReadAll(r.Body)
+		gotBody = string(data)
+		switch r.URL.Path {
+		case "/fail":
+			http.Error(w, "upstream down", http.StatusBadGateway)
+		case "/text":
+			io.WriteString(w, "pong\n")
+		default:
+			io.WriteString(w, `{"data":{"price":12.5,"items":[{"title":"a"},{"title":"b"}]}}`)
+		}
+	}))
+	defer server.Close()
+
+	params := map[string]Parameter{
+		"city":  {Type: "string", Required: true},
+		"limit": {Type: "integer"},
+	}
+	tests := []struct {
+		name      string
+		config    HTTPToolConfig
+		args      map[string]interface{}
+		want      interface{}
+		wantErr   string
+		wantPath  string
+		wantQuery string
+		wantBody  string
+		wantType  string
+	}{
+		{
+			name: "get with path and query escaping",
+			config: HTTPToolConfig{
+				URL:     server.URL + "/city/{{city}}?q={{city}}&limit={{limit}}",
+				Headers: map[string]string{"Authorization": "Bearer {{env:HTTP_TOOL_TOKEN}}"},
+				Result:  "$.data.price",
+			},
+			args:      map[string]interface{}{"city": "San Jose/CA", "limit": float64(3)},
+			want:      12.5,
+			wantPath:  "/city/San%20Jose%2FCA",
+			wantQuery: "q=San+Jose%2FCA&limit=3",
+		},
+		{
+			name:     "json body",
+			config:   HTTPToolConfig{Method: "post", URL: server.URL + "/items", Body: `{"city":"{{city}}","limit":{{limit}}}`, Result: "$.data.items[*].title"},
+			args:     map[string]interface{}{"city": `北京"东城"`, "limit": float64(2)},
+			want:     []interface{}{"a", "b"},
+			wantPath: "/items",
+			wantBody: `{"city":"北京\"东城\"","limit":2}`,
+			wantType: "application/json",
+		},
+		{
+			name: "form body",
+			config: HTTPToolConfig{
+				Method:  "POST",
+				URL:     server.URL + "/form",
+				Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+				Body:    "city={{city}}",
+				Result:  "data.items[-1].title",
+			},
+			args:     map[string]interface{}{"city": "a&b"},
+			want:     "b",
+			wantPath: "/form",
+			wantBody: "city=a%26b",
+			wantType: "application/x-www-form-urlencoded",
+		},
+		{name: "plain text response", config: HTTPToolConfig{URL: server.URL + "/text"}, args: map[string]interface{}{"city": "x"}, want: "pong", wantPath: "/text"},
+		{name: "http error", config: HTTPToolConfig{URL: server.URL + "/fail"}, args: map[string]interface{}{"city": "x"}, wantErr: "http 502: upstream down"},
+		{name: "missing required parameter", config: HTTPToolConfig{URL: server.URL}, args: map[string]interface{}{}, wantErr: `missing required parameter "city"`},
+		{name: "result path not found", config: HTTPToolConfig{URL: server.URL, Result: "$.data.volume"}, args: map[string]interface{}{"city": "x"}, wantErr: `field "volume" not found`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotMethod, gotPath, gotQuery, gotAuth, gotType, gotBody = "", "", "", "", "", ""
+			tt.config.Definition = ToolDefinition{Name: "cityTool", Parameters: params}
+			execute, err := NewHTTPTool(tt.config, server.Client())
+			if err != nil {
+				t.Fatalf("NewHTTPTool() error = %v", err)
+			}
+			result, audio, err := execute(context.Background(), tt.args)
+			if audio != nil {
+				t.Errorf("audio = %v, want nil", audio)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("execute() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("execute() error = %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.want) {
+				t.Errorf("result = %#v, want %#v", result, tt.want)
+			}
+			wantMethod := strings.ToUpper(tt.config.Method)
+			if wantMethod == "" {
+				wantMethod = http.MethodGet
+			}
+			if gotMethod != wantMethod || gotPath != tt.wantPath || gotQuery != tt.wantQuery {
+				t.Errorf("request = %s %s?%s, want %s %s?%s", gotMethod, gotPath, gotQuery, wantMethod, tt.wantPath, tt.wantQuery)
+			}
+			if gotBody != tt.wantBody || gotType != tt.wantType {
+				t.Errorf("body = %q (%s), want %q (%s)", gotBody, gotType, tt.wantBody, tt.wantType)
+			}
+			if tt.config.Headers["Authorization"] != "" && gotAuth != "Bearer secret-token" {
+				t.Errorf("Authorization = %q", gotAuth)
+			}
+		})
+	}
+}
@@ -1,13 +1,19 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"strconv"
+	"strings"
+
+	"github.com/liuscraft/orion-x/internal/logging"
 )
 
 // PlayMusicTool 音乐播放工具
-func PlayMusicTool(args map[string]interface{}) (interface{}, io.Reader, error) {
+func PlayMusicTool(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
 	song := args["song"].(string)
 
 	// TODO: 实际从音乐服务获取音频流
@@ -29,18 +35,55 @@ func PlayMusicTool(args map[string]interface{}) (interface{}, io.Reader, error)
 }
 
 // PauseMusicTool 暂停音乐工具
-func PauseMusicTool(args map[string]interface{}) (interface{}, io.Reader, error) {
+func PauseMusicTool(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
 	return map[string]interface{}{
 		"status": "paused",
 	}, nil, nil
 }
 
-// SetVolumeTool 设置音量工具
-func SetVolumeTool(args map[string]interface{}) (interface{}, io.Reader, error) {
-	level := args["level"].(string)
+// VolumeSetter 整体音量控制（通常是 audio.VolumeControl），音量取值 0~1
+type VolumeSetter interface {
+	Volume() float64
+	SetVolume(volume float64)
+}
 
-	return map[string]interface{}{
-		"level":  level,
-		"status": "success",
-	}, nil, nil
+// NewSetVolumeTool 设置音量工具：level 为 0-100（可带 %），超出范围时限幅，返回实际生效的音量
+func NewSetVolumeTool(volume VolumeSetter) ToolExecutorFunc {
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		level, err := parseVolumeLevel(args["level"])
+		if err != nil {
+			return nil, nil, err
+		}
+		volume.SetVolume(float64(level) / 100)
+		applied := int(math.Round(volume.Volume() * 100))
+		logging.InfofCtx(ctx, "SetVolumeTool: volume set to %d", applied)
+
+		return map[string]interface{}{
+			"level":  applied,
+			"status": "success",
+		}, nil, nil
+	}
+}
+
+// parseVolumeLevel 解析 0-100 的音量，LLM 可能传字符串（"50"、"50%"）或数字
+func parseVolumeLevel(value interface{}) (int, error) {
+	var level float64
+	switch v := value.(type) {
+	case float64:
+		level = v
+	case int:
+		level = float64(v)
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(v), "%"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid volume level %q", v)
+		}
+		level = parsed
+	default:
+		return 0, fmt.Errorf("invalid volume level %v", value)
+	}
+	if math.IsNaN(level) {
+		return 0, fmt.Errorf("invalid volume level %v", value)
+	}
+	return int(math.Round(min(max(level, 0), 100))), nil
 }
@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"context"
+	"testing"
+)
+
+type fakeVolume struct{ volume float64 }
+
+func (f *fakeVolume) Volume() float64          { return f.volume }
+func (f *fakeVolume) SetVolume(volume float64) { f.volume = min(max(volume, 0), 1) }
+
+func TestSetVolumeTool(t *testing.T) {
+	tests := []struct {
+		level   interface{}
+		want    int
+		wantErr bool
+	}{
+		{"50", 50, false},
+		{" 30% ", 30, false},
+		{float64(75), 75, false},
+		{"150", 100, false},
+		{-5, 0, false},
+		{"loud", 0, true},
+		{nil, 0, true},
+	}
+	for _, tt := range tests {
+		volume := &fakeVolume{volume: 1}
+		tool := NewSetVolumeTool(volume)
+		result, audio, err := tool(context.Background(), map[string]interface{}{"level": tt.level})
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("level %v: expected error", tt.level)
+			}
+			if volume.volume != 1 {
+				t.Errorf("level %v: volume changed to %v on error", tt.level, volume.volume)
+			}
+			continue
+		}
+		if err != nil || audio != nil {
+			t.Fatalf("level %v: unexpected (%v, %v)", tt.level, audio, err)
+		}
+		got := result.(map[string]interface{})["level"]
+		if got != tt.want || volume.volume != float64(tt.want)/100 {
+			t.Errorf("level %v: result %v, volume %v, want %d", tt.level, got, volume.volume, tt.want)
+		}
+	}
+}
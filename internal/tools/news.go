@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/news"
+)
+
+const (
+	defaultNewsLimit = 5
+	maxNewsLimit     = 20
+)
+
+// NewsDefinition getNews 工具定义，feeds 为配置的新闻源名称，作为 source 参数的可选值
+func NewsDefinition(feeds []string) ToolDefinition {
+	return ToolDefinition{
+		Name:        "getNews",
+		Description: "获取最新新闻（标题和摘要），播报时请挑重点概括，不要逐字朗读",
+		Parameters: map[string]Parameter{
+			"source": {Type: "string", Description: "新闻源名称，不填时汇总全部新闻源", Enum: feeds},
+			"limit":  {Type: "integer", Description: "新闻条数，默认 5，最多 20"},
+		},
+	}
+}
+
+// NewGetNewsTool 获取新闻工具：按发布时间倒序返回各新闻源合并后的最新条目
+func NewGetNewsTool(reader *news.Reader, defaultLimit int) ToolExecutorFunc {
+	if defaultLimit <= 0 {
+		defaultLimit = defaultNewsLimit
+	}
+	return func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		limit := defaultLimit
+		if value := args["limit"]; value != nil {
+			parsed, err := parsePositiveInt("limit", value)
+			if err != nil {
+				return nil, nil, err
+			}
+			limit = min(parsed, maxNewsLimit)
+		}
+		source := strings.TrimSpace(stringArg(args, "source"))
+		items, err := reader.Latest(ctx, source, limit)
+		if err != nil {
+			return nil, nil, err
+		}
+		logging.InfofCtx(ctx, "GetNewsTool: %d items (source: %q)", len(items), source)
+
+		results := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			result := map[string]interface{}{
+				"source": item.Source,
+				"title":  item.Title,
+			}
+			if item.Summary != "" {
+				result["summary"] = item.Summary
+			}
+			if !item.Published.IsZero() {
+				result["published"] = item.Published.Local().Format(eventTimeLayout)
+			}
+			results = append(results, result)
+		}
+		return map[string]interface{}{
+			"count": len(results),
+			"items": results,
+		}, nil, nil
+	}
+}
@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/liuscraft/orion-x/internal/news"
+)
+
+func TestGetNewsTool(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var b strings.Builder
+		b.WriteString(`<rss version="2.0"><channel>`)
+		for i := 1; i <= 30; i++ {
+			fmt.Fprintf(&b, `<item><title>新闻 %d</title><description>&lt;p&gt;摘要 %d&lt;/p&gt;</description><pubDate>Tue, 15 Oct 2024 %02d:%02d:00 +0000</pubDate></item>`, i, i, i/60, 60-i)
+		}
+		b.WriteString(`</channel></rss>`)
+		io.WriteString(w, b.String())
+	}))
+	defer server.Close()
+	reader := news.NewReader(news.Config{Feeds: []news.Feed{{Name: "科技", URL: server.URL}}, Client: server.Client()})
+	tool := NewGetNewsTool(reader, 3)
+
+	tests := []struct {
+		name      string
+		args      map[string]interface{}
+		wantCount int
+		wantErr   bool
+	}{
+		{name: "default limit", args: map[string]interface{}{}, wantCount: 3},
+		{name: "limit as number", args: map[string]interface{}{"limit": float64(7)}, wantCount: 7},
+		{name: "limit as string", args: map[string]interface{}{"limit": "2", "source": "科技"}, wantCount: 2},
+		{name: "limit capped", args: map[string]interface{}{"limit": float64(100)}, wantCount: maxNewsLimit},
+		{name: "invalid limit", args: map[string]interface{}{"limit": "many"}, wantErr: true},
+		{name: "unknown source", args: map[string]interface{}{"source": "体育"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _, err := tool(context.Background(), tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got := result.(map[string]interface{})
+			items := got["items"].([]map[string]interface{})
+			if got["count"] != tt.wantCount || len(items) != tt.wantCount {
+				t.Fatalf("count = %v, want %d", got["count"], tt.wantCount)
+			}
+			if items[0]["title"] != "新闻 1" || items[0]["summary"] != "摘要 1" || items[0]["source"] != "科技" {
+				t.Errorf("first item = %v", items[0])
+			}
+		})
+	}
+}
+
+func TestNewsDefinition(t *testing.T) {
+	definition := NewsDefinition([]string{"科技", "World"})
+	if got := definition.Parameters["source"].Enum; len(got) != 2 || got[0] != "科技" {
+		t.Errorf("source enum = %v", got)
+	}
+	if definition.Action {
+		t.Error("getNews should be a query tool")
+	}
+}
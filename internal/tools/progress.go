@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ToolProgress 长耗时工具调用的进度
+type ToolProgress struct {
+	Tool    string
+	Message string // 工具通过 ReportProgress 上报的进度描述，计时提醒时为空
+	Elapsed time.Duration
+}
+
+// ProgressConfig 工具调用进度提醒
+type ProgressConfig struct {
+	// Delay 调用超过该时长仍未返回时第一次提醒，0 表示不做计时提醒（工具自己上报的进度照常回调）
+	Delay time.Duration
+	// Interval 第一次提醒之后每隔该时长再提醒一次，0 表示只提醒一次
+	Interval time.Duration
+	// OnProgress 接收进度，在计时器或工具 goroutine 中调用，不应阻塞；调用返回或被取消后不会再回调
+	OnProgress func(ToolProgress)
+}
+
+type progressKey struct{}
+
+// ReportProgress 工具执行中上报进度（如“已获取 3/5 个新闻源”），
+// ctx 不是由设置了进度回调的 ToolCallBatch 提供时忽略
+func ReportProgress(ctx context.Context, message string) {
+	if tracker, ok := ctx.Value(progressKey{}).(*progressTracker); ok {
+		tracker.report(message)
+	}
+}
+
+// progressTracker 单个工具调用的进度计时器，stop 之后不再回调
+type progressTracker struct {
+	ctx    context.Context
+	tool   string
+	start  time.Time
+	config ProgressConfig
+
+	mu      sync.Mutex
+	stopped bool
+	timer   *time.Timer
+}
+
+// startProgress 开始跟踪一个工具调用，返回携带进度上报入口的 ctx；config.OnProgress 为 nil 时返回 nil
+func startProgress(ctx context.Context, tool string, config ProgressConfig) (*progressTracker, context.Context) {
+	if config.OnProgress == nil {
+		return nil, ctx
+	}
+	t := &progressTracker{tool: tool, start: time.Now(), config: config}
+	ctx = context.WithValue(ctx, progressKey{}, t)
+	t.ctx = ctx
+	if config.Delay > 0 {
+		// 持锁赋值，避免极短的 Delay 下 tick 先于 timer 赋值执行
+		t.mu.Lock()
+		t.timer = time.AfterFunc(config.Delay, t.tick)
+		t.mu.Unlock()
+	}
+	return t, ctx
+}
+
+func (t *progressTracker) tick() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped || t.ctx.Err() != nil {
+		return
+	}
+	t.config.OnProgress(ToolProgress{Tool: t.tool, Elapsed: time.Since(t.start)})
+	if t.config.Interval > 0 {
+		t.timer.Reset(t.config.Interval)
+	}
+}
+
+func (t *progressTracker) report(message string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopped || t.ctx.Err() != nil {
+		return
+	}
+	t.config.OnProgress(ToolProgress{Tool: t.tool, Message: message, Elapsed: time.Since(t.start)})
+}
+
+// stop 停止计时；返回后不会再有进度回调，可重复调用
+func (t *progressTracker) stop() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.stopped = true
+	t.mu.Unlock()
+	if t.timer != nil {
+		t.timer.Stop()
+	}
+}
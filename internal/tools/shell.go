@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+const (
+	// defaultShellTimeout 未配置超时时命令的最长运行时间
+	defaultShellTimeout = 10 * time.Second
+	// defaultShellMaxOutput 未配置输出上限时保留的输出字节数
+	defaultShellMaxOutput = 4096
+)
+
+// ShellToolConfig 配置声明的本地命令工具：直接执行程序（不经过 shell），每个参数单独替换 {{参数名}}，
+// 参数值不会被拆分或解释为 shell 语法
+type ShellToolConfig struct {
+	Definition ToolDefinition
+	Command    []string      // 程序及参数模板，程序本身不能包含占位符
+	Dir        string        // 工作目录，为空时为当前目录
+	Timeout    time.Duration // 超时后结束进程，0 时为 10 秒
+	// Env 允许传给命令的环境变量名，其余环境变量（包括 API 密钥）不传递；为空时命令没有环境变量
+	Env       []string
+	MaxOutput int // 保留的输出字节数（stdout 与 stderr 合并），超出部分截断；0 时为 4096
+}
+
+// shellTool 已校验的命令工具
+type shellTool struct {
+	config ShellToolConfig
+}
+
+// NewShellTool 校验配置并创建工具执行函数：参数模板只能引用已声明的参数，工作目录必须存在
+func NewShellTool(config ShellToolConfig) (ToolExecutorFunc, error) {
+	name := config.Definition.Name
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.New("shell tool: name is required")
+	}
+	if len(config.Command) == 0 || strings.TrimSpace(config.Command[0]) == "" {
+		return nil, fmt.Errorf("shell tool %s: command is required", name)
+	}
+	if placeholderPattern.MatchString(config.Command[0]) {
+		return nil, fmt.Errorf("shell tool %s: program %q must not contain placeholders", name, config.Command[0])
+	}
+	for _, arg := range config.Command[1:] {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(arg, -1) {
+			key := match[1]
+			if strings.HasPrefix(key, "env:") {
+				return nil, fmt.Errorf("shell tool %s: use env instead of %q", name, match[0])
+			}
+			if _, ok := config.Definition.Parameters[key]; !ok {
+				return nil, fmt.Errorf("shell tool %s: command references undeclared parameter %q", name, key)
+			}
+		}
+	}
+	if config.Dir != "" {
+		if info, err := os.Stat(config.Dir); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("shell tool %s: dir %q is not a directory", name, config.Dir)
+		}
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultShellTimeout
+	}
+	if config.MaxOutput <= 0 {
+		config.MaxOutput = defaultShellMaxOutput
+	}
+	tool := &shellTool{config: config}
+	return tool.execute, nil
+}
+
+func (t *shellTool) execute(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+	if err := t.checkArgs(args); err != nil {
+		return nil, nil, err
+	}
+	argv := make([]string, len(t.config.Command))
+	argv[0] = t.config.Command[0]
+	for i, arg := range t.config.Command[1:] {
+		argv[i+1] = renderTemplate(arg, args, nil)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, t.config.Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Dir = t.config.Dir
+	cmd.Env = t.environ()
+	// 子进程把输出管道交给后台进程时，超时后不再等待管道关闭
+	cmd.WaitDelay = time.Second
+	output := &limitedBuffer{max: t.config.MaxOutput}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	start := time.Now()
+	err := cmd.Run()
+	exitCode := cmd.ProcessState.ExitCode()
+	logging.InfofCtx(ctx, "ShellTool: %s %q exited %d in %v", t.config.Definition.Name, argv,
+		exitCode, time.Since(start).Round(time.Millisecond))
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, nil, fmt.Errorf("command timed out after %v", t.config.Timeout)
+	}
+	if err != nil {
+		if text := strings.TrimSpace(output.String()); text != "" {
+			return nil, nil, fmt.Errorf("%w: %s", err, snippet(text, httpToolErrorSnippet))
+		}
+		return nil, nil, err
+	}
+	return map[string]interface{}{
+		"exit_code": exitCode,
+		"output":    strings.TrimSpace(output.String()),
+		"truncated": output.truncated,
+	}, nil, nil
+}
+
+// checkArgs 检查必填参数、枚举取值，字符串参数不能以 - 开头（避免被程序当作选项）
+func (t *shellTool) checkArgs(args map[string]interface{}) error {
+	for name, param := range t.config.Definition.Parameters {
+		value, ok := args[name]
+		if !ok || value == nil {
+			if param.Required {
+				return fmt.Errorf("missing required parameter %q", name)
+			}
+			continue
+		}
+		text := formatArg(value)
+		if len(param.Enum) > 0 && !slices.Contains(param.Enum, text) {
+			return fmt.Errorf("parameter %q must be one of %v, got %q", name, param.Enum, text)
+		}
+		if param.Type == "string" && strings.HasPrefix(text, "-") {
+			return fmt.Errorf("parameter %q must not start with '-'", name)
+		}
+	}
+	return nil
+}
+
+// environ 只保留允许的环境变量
+func (t *shellTool) environ() []string {
+	env := make([]string, 0, len(t.config.Env))
+	for _, name := range t.config.Env {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// limitedBuffer 只保留前 max 个字节的输出，之后的写入丢弃（仍返回成功，避免子进程因写失败退出）
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	return strings.ToValidUTF8(b.buf.String(), "")
+}
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewShellToolValidation(t *testing.T) {
+	definition := ToolDefinition{
+		Name:       "restartService",
+		Parameters: map[string]Parameter{"service": {Type: "string", Required: true}},
+	}
+	tests := []struct {
+		name    string
+		config  ShellToolConfig
+		wantErr string
+	}{
+		{name: "valid", config: ShellToolConfig{Definition: definition, Command: []string{"echo", "restart", "{{service}}"}}},
+		{name: "missing name", config: ShellToolConfig{Command: []string{"echo"}}, wantErr: "name is required"},
+		{name: "missing command", config: ShellToolConfig{Definition: definition}, wantErr: "command is required"},
+		{name: "placeholder program", config: ShellToolConfig{Definition: definition, Command: []string{"{{service}}"}}, wantErr: "must not contain placeholders"},
+		{name: "undeclared parameter", config: ShellToolConfig{Definition: definition, Command: []string{"echo", "{{host}}"}}, wantErr: `undeclared parameter "host"`},
+		{name: "env placeholder", config: ShellToolConfig{Definition: definition, Command: []string{"echo", "{{env:HOME}}"}}, wantErr: "use env instead"},
+		{name: "missing dir", config: ShellToolConfig{Definition: definition, Command: []string{"echo"}, Dir: "/nonexistent/orion-x"}, wantErr: "is not a directory"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewShellTool(tt.config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("NewShellTool() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("NewShellTool() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShellToolExecute(t *testing.T) {
+	t.Setenv("SHELL_TOOL_ALLOWED", "visible")
+	t.Setenv("SHELL_TOOL_SECRET", "hidden")
+	dir := t.TempDir()
+
+	params := map[string]Parameter{
+		"target": {Type: "string", Required: true},
+		"mode":   {Type: "string", Enum: []string{"soft", "hard"}},
+	}
+	tests := []struct {
+		name          string
+		config        ShellToolConfig
+		args          map[string]interface{}
+		wantOutput    string
+		wantTruncated bool
+		wantErr       string
+	}{
+		{
+			name:       "argument is not split or interpreted",
+			config:     ShellToolConfig{Command: []string{"sh", "-c", `printf '%s|%s' "$0" "$1"`, "{{target}}", "mode={{mode}}"}},
+			args:       map[string]interface{}{"target": "a b; rm -rf /", "mode": "soft"},
+			wantOutput: "a b; rm -rf /|mode=soft",
+		},
+		{
+			name:       "env allowlist",
+			config:     ShellToolConfig{Command: []string{"sh", "-c", `echo "$SHELL_TOOL_ALLOWED:$SHELL_TOOL_SECRET:{{target}}"`}, Env: []string{"SHELL_TOOL_ALLOWED"}},
+			args:       map[string]interface{}{"target": "x"},
+			wantOutput: "visible::x",
+		},
+		{
+			name:       "working directory",
+			config:     ShellToolConfig{Command: []string{"pwd"}, Dir: dir},
+			args:       map[string]interface{}{"target": "x"},
+			wantOutput: dir,
+		},
+		{
+			name:          "output limit",
+			config:        ShellToolConfig{Command: []string{"sh", "-c", "printf 0123456789"}, MaxOutput: 4},
+			args:          map[string]interface{}{"target": "x"},
+			wantOutput:    "0123",
+			wantTruncated: true,
+		},
+		{name: "non-zero exit", config: ShellToolConfig{Command: []string{"sh", "-c", "echo boom >&2; exit 3"}}, args: map[string]interface{}{"target": "x"}, wantErr: "exit status 3: boom"},
+		{name: "timeout", config: ShellToolConfig{Command: []string{"sleep", "5"}, Timeout: 50 * time.Millisecond}, args: map[string]interface{}{"target": "x"}, wantErr: "timed out"},
+		{name: "missing required parameter", config: ShellToolConfig{Command: []string{"true"}}, args: map[string]interface{}{}, wantErr: `missing required parameter "target"`},
+		{name: "value outside enum", config: ShellToolConfig{Command: []string{"true"}}, args: map[string]interface{}{"target": "x", "mode": "reboot"}, wantErr: "must be one of"},
+		{name: "option injection", config: ShellToolConfig{Command: []string{"true"}}, args: map[string]interface{}{"target": "--force"}, wantErr: "must not start with '-'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Definition = ToolDefinition{Name: "shellTool", Parameters: params}
+			execute, err := NewShellTool(tt.config)
+			if err != nil {
+				t.Fatalf("NewShellTool() error = %v", err)
+			}
+			start := time.Now()
+			result, _, err := execute(context.Background(), tt.args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("execute() error = %v, want %q", err, tt.wantErr)
+				}
+				if elapsed := time.Since(start); elapsed > 3*time.Second {
+					t.Errorf("execute() took %v", elapsed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("execute() error = %v", err)
+			}
+			output := result.(map[string]interface{})
+			if output["output"] != tt.wantOutput || output["truncated"] != tt.wantTruncated || output["exit_code"] != 0 {
+				t.Errorf("result = %v, want output %q truncated %v", output, tt.wantOutput, tt.wantTruncated)
+			}
+		})
+	}
+}
@@ -1,12 +1,13 @@
 package tools
 
 import (
+	"context"
 	"testing"
 	"time"
 )
 
 func TestGetTimeTool(t *testing.T) {
-	result, audio, err := GetTimeTool(nil)
+	result, audio, err := GetTimeTool(context.Background(), nil)
 
 	if err != nil {
 		t.Fatalf("GetTimeTool returned error: %v", err)
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"time"
@@ -9,10 +10,10 @@ import (
 )
 
 // GetWeatherTool 获取天气工具
-func GetWeatherTool(args map[string]interface{}) (interface{}, io.Reader, error) {
+func GetWeatherTool(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
 	city := args["city"].(string)
 
-	logging.Infof("GetWeatherTool: querying weather for city: %s", city)
+	logging.InfofCtx(ctx, "GetWeatherTool: querying weather for city: %s", city)
 
 	// TODO: 实际调用天气API
 	// 这里模拟天气数据
@@ -24,13 +25,13 @@ func GetWeatherTool(args map[string]interface{}) (interface{}, io.Reader, error)
 		"wind":        "东风3级",
 	}
 
-	logging.Infof("GetWeatherTool: weather result: %v", weather)
+	logging.InfofCtx(ctx, "GetWeatherTool: weather result: %v", weather)
 	return weather, nil, nil
 }
 
 // GetTimeTool 获取时间工具
-func GetTimeTool(args map[string]interface{}) (interface{}, io.Reader, error) {
-	logging.Infof("GetTimeTool: getting current time")
+func GetTimeTool(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+	logging.InfofCtx(ctx, "GetTimeTool: getting current time")
 
 	now := map[string]interface{}{
 		"current":   getCurrentTimeFormatted(),
@@ -45,12 +46,12 @@ func GetTimeTool(args map[string]interface{}) (interface{}, io.Reader, error) {
 		"timestamp": getCurrentTimestamp(),
 	}
 
-	logging.Infof("GetTimeTool: time result: %v", now)
+	logging.InfofCtx(ctx, "GetTimeTool: time result: %v", now)
 	return now, nil, nil
 }
 
 // SearchTool 搜索工具
-func SearchTool(args map[string]interface{}) (interface{}, io.Reader, error) {
+func SearchTool(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
 	query := args["query"].(string)
 
 	// TODO: 实际调用搜索API
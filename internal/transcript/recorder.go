@@ -0,0 +1,50 @@
+package transcript
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Recorder 把流式识别结果（中间结果 + final）整理成带起止时间的句子
+// 一句的开始时间取上一句 final 之后的第一个中间结果，结束时间取 final 到达的时刻
+type Recorder struct {
+	mu      sync.Mutex
+	start   time.Time
+	now     func() time.Time
+	pending time.Time // 当前句第一个中间结果的时间，零值表示尚未开始
+	onSeg   func(Segment)
+}
+
+// NewRecorder 创建 Recorder，时间戳相对于创建时刻；onSegment 在每句 final 时调用
+func NewRecorder(onSegment func(Segment)) *Recorder {
+	return newRecorderWithClock(onSegment, time.Now)
+}
+
+func newRecorderWithClock(onSegment func(Segment), now func() time.Time) *Recorder {
+	return &Recorder{start: now(), now: now, onSeg: onSegment}
+}
+
+// OnResult 处理一条识别结果，签名与 AudioInPipe.OnASRResult 的回调一致
+func (r *Recorder) OnResult(text string, isFinal bool) {
+	r.mu.Lock()
+	now := r.now()
+	if r.pending.IsZero() {
+		r.pending = now
+	}
+	if !isFinal {
+		r.mu.Unlock()
+		return
+	}
+	seg := Segment{
+		Start: r.pending.Sub(r.start),
+		End:   now.Sub(r.start),
+		Text:  strings.TrimSpace(text),
+	}
+	r.pending = time.Time{}
+	r.mu.Unlock()
+
+	if seg.Text != "" && r.onSeg != nil {
+		r.onSeg(seg)
+	}
+}
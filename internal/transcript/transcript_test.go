@@ -0,0 +1,111 @@
+package transcript
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{name: "txt", want: FormatText},
+		{name: "SRT", want: FormatSRT},
+		{name: ".vtt", want: FormatVTT},
+		{name: "webvtt", want: FormatVTT},
+		{name: "docx", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseFormat(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("ParseFormat(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseFormat(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if got := FormatFromPath("meeting.SRT"); got != FormatSRT {
+		t.Errorf("FormatFromPath = %q, want srt", got)
+	}
+	if got := FormatFromPath("meeting.log"); got != FormatText {
+		t.Errorf("FormatFromPath = %q, want txt", got)
+	}
+}
+
+func TestWriterFormats(t *testing.T) {
+	segments := []Segment{
+		{Start: 1500 * time.Millisecond, End: 3200 * time.Millisecond, Text: "你好"},
+		{Start: 0, End: 0, Text: "   "},
+		{Start: time.Hour + 2*time.Minute + 3*time.Second + 4*time.Millisecond, End: time.Hour + 2*time.Minute + 5*time.Second, Text: "再见"},
+	}
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{
+			format: FormatSRT,
+			want: "1\n00:00:01,500 --> 00:00:03,200\n你好\n\n" +
+				"2\n01:02:03,004 --> 01:02:05,000\n再见\n\n",
+		},
+		{
+			format: FormatVTT,
+			want: "WEBVTT\n\n00:00:01.500 --> 00:00:03.200\n你好\n\n" +
+				"01:02:03.004 --> 01:02:05.000\n再见\n\n",
+		},
+		{
+			format: FormatText,
+			want:   "[00:00:01] 你好\n[01:02:03] 再见\n",
+		},
+	}
+	for _, tt := range tests {
+		var sb strings.Builder
+		w := NewWriter(&sb, tt.format)
+		for _, seg := range segments {
+			if err := w.Write(seg); err != nil {
+				t.Fatalf("%s: Write() error = %v", tt.format, err)
+			}
+		}
+		if sb.String() != tt.want {
+			t.Errorf("%s output =\n%q\nwant\n%q", tt.format, sb.String(), tt.want)
+		}
+		if w.Count() != 2 {
+			t.Errorf("%s Count() = %d, want 2", tt.format, w.Count())
+		}
+	}
+}
+
+func TestRecorderTiming(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := base
+	var got []Segment
+	r := newRecorderWithClock(func(seg Segment) { got = append(got, seg) }, func() time.Time { return now })
+
+	now = base.Add(2 * time.Second)
+	r.OnResult("今天", false)
+	now = base.Add(3 * time.Second)
+	r.OnResult("今天天气", false)
+	now = base.Add(4 * time.Second)
+	r.OnResult("今天天气不错。", true)
+	// 没有中间结果直接 final 时，起止时间相同
+	now = base.Add(6 * time.Second)
+	r.OnResult("好的。", true)
+	now = base.Add(7 * time.Second)
+	r.OnResult("", true)
+
+	want := []Segment{
+		{Start: 2 * time.Second, End: 4 * time.Second, Text: "今天天气不错。"},
+		{Start: 6 * time.Second, End: 6 * time.Second, Text: "好的。"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d segments, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("segment %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Package transcript 把识别结果写成带时间戳的转写文件（txt / SRT / WebVTT）
+package transcript
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Format 转写文件格式
+type Format string
+
+const (
+	FormatText Format = "txt"
+	FormatSRT  Format = "srt"
+	FormatVTT  Format = "vtt"
+)
+
+// ParseFormat 解析格式名称（不区分大小写，允许带 "."），未知格式返回错误
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimPrefix(strings.TrimSpace(name), "."))) {
+	case FormatText, "text":
+		return FormatText, nil
+	case FormatSRT:
+		return FormatSRT, nil
+	case FormatVTT, "webvtt":
+		return FormatVTT, nil
+	}
+	return "", fmt.Errorf("unknown transcript format %q (want txt, srt or vtt)", name)
+}
+
+// FormatFromPath 按文件扩展名推断格式，无法识别时返回 txt
+func FormatFromPath(path string) Format {
+	format, err := ParseFormat(filepath.Ext(path))
+	if err != nil {
+		return FormatText
+	}
+	return format
+}
+
+// Segment 一句转写结果，时间相对于转写开始
+type Segment struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// Writer 逐句写出转写结果，每句写完立即落盘，进程中断时已写出的内容仍然可用
+type Writer struct {
+	mu      sync.Mutex
+	w       io.Writer
+	format  Format
+	count   int
+	started bool
+}
+
+// NewWriter 创建指定格式的 Writer
+func NewWriter(w io.Writer, format Format) *Writer {
+	return &Writer{w: w, format: format}
+}
+
+// Count 返回已写出的句数
+func (tw *Writer) Count() int {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.count
+}
+
+// Write 写出一句，空文本忽略
+func (tw *Writer) Write(seg Segment) error {
+	text := strings.TrimSpace(seg.Text)
+	if text == "" {
+		return nil
+	}
+	if seg.End < seg.Start {
+		seg.End = seg.Start
+	}
+
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if !tw.started && tw.format == FormatVTT {
+		if _, err := io.WriteString(tw.w, "WEBVTT\n\n"); err != nil {
+			return err
+		}
+	}
+	tw.started = true
+	tw.count++
+
+	var entry string
+	switch tw.format {
+	case FormatSRT:
+		entry = fmt.Sprintf("%d\n%s --> %s\n%s\n\n", tw.count,
+			formatTimestamp(seg.Start, ','), formatTimestamp(seg.End, ','), text)
+	case FormatVTT:
+		entry = fmt.Sprintf("%s --> %s\n%s\n\n",
+			formatTimestamp(seg.Start, '.'), formatTimestamp(seg.End, '.'), text)
+	default:
+		entry = fmt.Sprintf("[%s] %s\n", formatTimestamp(seg.Start, '.')[:8], text)
+	}
+	_, err := io.WriteString(tw.w, entry)
+	return err
+}
+
+// formatTimestamp 格式化为 HH:MM:SS<sep>mmm，SRT 使用逗号，WebVTT 使用点号
+func formatTimestamp(d time.Duration, sep byte) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%c%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
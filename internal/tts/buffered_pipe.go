@@ -0,0 +1,200 @@
+package tts
+
+import (
+	"io"
+	"sync"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// BufferPolicy Stream 音频缓冲写满时的处理方式
+type BufferPolicy string
+
+const (
+	// BufferBlock 写满时暂停接收，等待播放端读走数据（默认）
+	BufferBlock BufferPolicy = "block"
+	// BufferDropOldest 写满时丢弃最早的音频，保证最新的音频可播放
+	BufferDropOldest BufferPolicy = "drop_oldest"
+	// BufferDropNewest 写满时丢弃新到的音频
+	BufferDropNewest BufferPolicy = "drop_newest"
+)
+
+// DefaultBufferBytes 默认每个 Stream 的音频缓冲上限（16kHz 16-bit 单声道约 32 秒）
+const DefaultBufferBytes = 1024 * 1024
+
+// Valid 是否为已知策略，空值按 BufferBlock 处理
+func (p BufferPolicy) Valid() bool {
+	switch p {
+	case "", BufferBlock, BufferDropOldest, BufferDropNewest:
+		return true
+	}
+	return false
+}
+
+// bufferedPipe 有容量上限的音频管道：WebSocket 接收协程写入，Mixer 读取
+// 缓冲超过高水位（3/4 容量）时回调 paused=true，回落到低水位（1/4 容量）时回调 paused=false；
+// BufferBlock 策略下写入方在两次回调之间暂停接收
+// 丢弃策略按 2 字节对齐，只适用于 16-bit PCM
+type bufferedPipe struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	buf    []byte
+	closed bool
+
+	maxLen         int
+	policy         BufferPolicy
+	high           int
+	low            int
+	paused         bool
+	dropped        int
+	onBackpressure func(paused bool)
+}
+
+func newBufferedPipe(maxLen int, policy BufferPolicy) *bufferedPipe {
+	if maxLen <= 0 {
+		maxLen = DefaultBufferBytes
+	}
+	if policy == "" {
+		policy = BufferBlock
+	}
+	bp := &bufferedPipe{
+		maxLen: maxLen,
+		policy: policy,
+		high:   maxLen * 3 / 4,
+		low:    maxLen / 4,
+	}
+	bp.cond = sync.NewCond(&bp.mu)
+	return bp
+}
+
+// setBackpressureHandler 设置高 / 低水位回调
+func (bp *bufferedPipe) setBackpressureHandler(handler func(paused bool)) {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	bp.onBackpressure = handler
+}
+
+func (bp *bufferedPipe) Write(p []byte) (int, error) {
+	bp.mu.Lock()
+	if bp.closed {
+		bp.mu.Unlock()
+		return 0, io.ErrClosedPipe
+	}
+
+	total := len(p)
+	var err error
+	switch bp.policy {
+	case BufferDropNewest:
+		n := min(len(p), bp.maxLen-len(bp.buf)) &^ 1
+		bp.buf = append(bp.buf, p[:n]...)
+		bp.drop(len(p) - n)
+	case BufferDropOldest:
+		if len(p) > bp.maxLen {
+			skip := (len(p) - bp.maxLen + 1) &^ 1
+			bp.drop(skip)
+			p = p[skip:]
+		}
+		if over := len(bp.buf) + len(p) - bp.maxLen; over > 0 {
+			over = min((over+1)&^1, len(bp.buf))
+			bp.buf = bp.buf[over:]
+			bp.drop(over)
+		}
+		bp.buf = append(bp.buf, p...)
+	default:
+		written := 0
+		for len(p) > 0 {
+			for len(bp.buf) >= bp.maxLen && !bp.closed {
+				bp.cond.Wait()
+			}
+			if bp.closed {
+				total, err = written, io.ErrClosedPipe
+				break
+			}
+			n := min(len(p), bp.maxLen-len(bp.buf))
+			bp.buf = append(bp.buf, p[:n]...)
+			p = p[n:]
+			written += n
+			bp.cond.Broadcast()
+		}
+	}
+	bp.cond.Broadcast()
+
+	notify := bp.updatePaused()
+	bp.mu.Unlock()
+	notify()
+	return total, err
+}
+
+// waitResumed BufferBlock 策略下缓冲超过高水位后阻塞，直到回落到低水位或管道关闭
+func (bp *bufferedPipe) waitResumed() {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	for bp.policy == BufferBlock && bp.paused && !bp.closed {
+		bp.cond.Wait()
+	}
+}
+
+func (bp *bufferedPipe) Read(p []byte) (int, error) {
+	bp.mu.Lock()
+	for len(bp.buf) == 0 && !bp.closed {
+		bp.cond.Wait()
+	}
+	if len(bp.buf) == 0 && bp.closed {
+		bp.mu.Unlock()
+		return 0, io.EOF
+	}
+
+	n := copy(p, bp.buf)
+	bp.buf = bp.buf[n:]
+	bp.cond.Broadcast()
+
+	notify := bp.updatePaused()
+	bp.mu.Unlock()
+	notify()
+	return n, nil
+}
+
+func (bp *bufferedPipe) Close() error {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+
+	bp.closed = true
+	bp.cond.Broadcast()
+	return nil
+}
+
+// Dropped 返回因缓冲写满被丢弃的字节数
+func (bp *bufferedPipe) Dropped() int {
+	bp.mu.Lock()
+	defer bp.mu.Unlock()
+	return bp.dropped
+}
+
+// drop 记录丢弃的字节，首次丢弃时输出日志；调用方持有锁
+func (bp *bufferedPipe) drop(n int) {
+	if n <= 0 {
+		return
+	}
+	if bp.dropped == 0 {
+		logging.Warnf("tts: audio buffer full (%d bytes), dropping audio (policy %s)", bp.maxLen, bp.policy)
+	}
+	bp.dropped += n
+}
+
+// updatePaused 根据水位更新暂停状态，返回需在锁外调用的回调；调用方持有锁
+func (bp *bufferedPipe) updatePaused() func() {
+	switch {
+	case !bp.paused && len(bp.buf) >= bp.high:
+		bp.paused = true
+	case bp.paused && len(bp.buf) <= bp.low:
+		bp.paused = false
+		bp.cond.Broadcast()
+	default:
+		return func() {}
+	}
+	handler, paused := bp.onBackpressure, bp.paused
+	if handler == nil {
+		return func() {}
+	}
+	return func() { handler(paused) }
+}
@@ -0,0 +1,146 @@
+package tts
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBufferedPipeDropPolicies(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      BufferPolicy
+		writes      [][]byte
+		want        []byte
+		wantDropped int
+	}{
+		{
+			name:   "drop oldest keeps latest audio",
+			policy: BufferDropOldest,
+			writes: [][]byte{{1, 1, 2, 2}, {3, 3, 4, 4}},
+			want:   []byte{2, 2, 3, 3, 4, 4},
+			// 超出 2 字节，按 16-bit 对齐丢弃
+			wantDropped: 2,
+		},
+		{
+			name:        "drop oldest oversized write",
+			policy:      BufferDropOldest,
+			writes:      [][]byte{{1, 1, 2, 2, 3, 3, 4, 4}},
+			want:        []byte{2, 2, 3, 3, 4, 4},
+			wantDropped: 2,
+		},
+		{
+			name:        "drop newest keeps earliest audio",
+			policy:      BufferDropNewest,
+			writes:      [][]byte{{1, 1, 2, 2}, {3, 3, 4, 4}},
+			want:        []byte{1, 1, 2, 2, 3, 3},
+			wantDropped: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bp := newBufferedPipe(6, tt.policy)
+			for _, w := range tt.writes {
+				if n, err := bp.Write(w); err != nil || n != len(w) {
+					t.Fatalf("Write() = %d, %v", n, err)
+				}
+			}
+			bp.Close()
+			got, _ := io.ReadAll(bp)
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("read %v, want %v", got, tt.want)
+			}
+			if bp.Dropped() != tt.wantDropped {
+				t.Errorf("Dropped() = %d, want %d", bp.Dropped(), tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestBufferedPipeBlocksWhenFull(t *testing.T) {
+	bp := newBufferedPipe(4, BufferBlock)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if n, err := bp.Write([]byte{1, 2, 3, 4, 5, 6}); err != nil || n != 6 {
+			t.Errorf("Write() = %d, %v", n, err)
+		}
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("Write() did not block on a full buffer")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	buf := make([]byte, 4)
+	if n, _ := bp.Read(buf); n != 4 {
+		t.Fatalf("Read() = %d bytes, want 4", n)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Write() not unblocked by Read()")
+	}
+	if n, _ := bp.Read(buf); n != 2 || buf[0] != 5 {
+		t.Errorf("Read() = %v, want remaining [5 6]", buf[:n])
+	}
+
+	// Close 解除阻塞中的写入
+	bp.Write([]byte{1, 2, 3, 4})
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := bp.Write([]byte{5})
+		errCh <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	bp.Close()
+	if err := <-errCh; err != io.ErrClosedPipe {
+		t.Errorf("Write() after Close error = %v, want io.ErrClosedPipe", err)
+	}
+}
+
+func TestBufferedPipeWatermarks(t *testing.T) {
+	bp := newBufferedPipe(8, BufferBlock)
+	var mu sync.Mutex
+	var events []bool
+	bp.setBackpressureHandler(func(paused bool) {
+		mu.Lock()
+		events = append(events, paused)
+		mu.Unlock()
+	})
+
+	bp.Write(make([]byte, 6)) // 达到高水位 6
+	resumed := make(chan struct{})
+	go func() {
+		bp.waitResumed()
+		close(resumed)
+	}()
+	select {
+	case <-resumed:
+		t.Fatal("waitResumed() returned above the high watermark")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	buf := make([]byte, 3)
+	bp.Read(buf) // 剩 3，仍高于低水位 2
+	select {
+	case <-resumed:
+		t.Fatal("waitResumed() returned above the low watermark")
+	case <-time.After(30 * time.Millisecond):
+	}
+	bp.Read(buf[:1]) // 剩 2，回落到低水位
+	select {
+	case <-resumed:
+	case <-time.After(time.Second):
+		t.Fatal("waitResumed() not released at the low watermark")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(events) != 2 || !events[0] || events[1] {
+		t.Errorf("backpressure events = %v, want [true false]", events)
+	}
+}
@@ -35,11 +35,8 @@ func (p *DashScopeProvider) Start(ctx context.Context, cfg Config) (Stream, erro
 		return nil, err
 	}
 
-	// Use a buffered channel-based pipe to avoid deadlock
-	// The standard io.Pipe blocks on Write if no one is reading,
-	// but generateTTS waits for Close() before returning the reader.
-	// This creates a deadlock. Using a buffer allows writes to proceed.
-	audioBuf := newBufferedPipe(1024 * 1024) // 1MB buffer for audio data
+	// 有上限的缓冲：播放端读取过慢时按 BufferPolicy 暂停接收或丢弃音频，避免内存无限增长
+	audioBuf := newBufferedPipe(normalized.BufferBytes, normalized.BufferPolicy)
 
 	stream := &dashScopeStream{
 		cfg:       normalized,
@@ -83,62 +80,9 @@ type dashScopeStream struct {
 	finishOnce  sync.Once
 }
 
-// bufferedPipe is a thread-safe buffered pipe that doesn't block on write
-type bufferedPipe struct {
-	buf    []byte
-	mu     sync.Mutex
-	cond   *sync.Cond
-	closed bool
-	maxLen int
-}
-
-func newBufferedPipe(maxLen int) *bufferedPipe {
-	bp := &bufferedPipe{
-		buf:    make([]byte, 0, maxLen),
-		maxLen: maxLen,
-	}
-	bp.cond = sync.NewCond(&bp.mu)
-	return bp
-}
-
-func (bp *bufferedPipe) Write(p []byte) (int, error) {
-	bp.mu.Lock()
-	defer bp.mu.Unlock()
-
-	if bp.closed {
-		return 0, io.ErrClosedPipe
-	}
-
-	// If buffer is full, wait for some space or just append (may grow)
-	bp.buf = append(bp.buf, p...)
-	bp.cond.Signal()
-	return len(p), nil
-}
-
-func (bp *bufferedPipe) Read(p []byte) (int, error) {
-	bp.mu.Lock()
-	defer bp.mu.Unlock()
-
-	for len(bp.buf) == 0 && !bp.closed {
-		bp.cond.Wait()
-	}
-
-	if len(bp.buf) == 0 && bp.closed {
-		return 0, io.EOF
-	}
-
-	n := copy(p, bp.buf)
-	bp.buf = bp.buf[n:]
-	return n, nil
-}
-
-func (bp *bufferedPipe) Close() error {
-	bp.mu.Lock()
-	defer bp.mu.Unlock()
-
-	bp.closed = true
-	bp.cond.Broadcast()
-	return nil
+// OnBackpressure 缓冲越过高 / 低水位时回调；BufferBlock 策略下高水位期间暂停读取 WebSocket
+func (s *dashScopeStream) OnBackpressure(handler func(paused bool)) {
+	s.audioBuf.setBackpressureHandler(handler)
 }
 
 func (s *dashScopeStream) AudioReader() io.ReadCloser {
@@ -163,6 +107,25 @@ func (s *dashScopeStream) WriteTextChunk(ctx context.Context, text string) error
 	if strings.TrimSpace(text) == "" {
 		return nil
 	}
+	// 开启 SSML 时纯文本一律转义后包装，<、& 等字符或文本中的 SSML 标签不会被当作标记解析
+	if s.cfg.EnableSSML {
+		text = WrapSSML(text)
+	}
+	return s.writeChunk(ctx, text)
+}
+
+// WriteSSML 原样发送 SSMLBuilder 生成的文档，未开启 SSML 时返回错误
+func (s *dashScopeStream) WriteSSML(ctx context.Context, doc SSML) error {
+	if !s.cfg.EnableSSML {
+		return errors.New("dashscope tts: SSML is not enabled")
+	}
+	if strings.TrimSpace(string(doc)) == "" {
+		return nil
+	}
+	return s.writeChunk(ctx, string(doc))
+}
+
+func (s *dashScopeStream) writeChunk(ctx context.Context, text string) error {
 	if err := s.waitStarted(ctx); err != nil {
 		return err
 	}
@@ -191,6 +154,8 @@ func (s *dashScopeStream) Close(ctx context.Context) error {
 		_ = s.conn.Close()
 		return err
 	case <-ctx.Done():
+		// 关闭缓冲，解除接收协程在写满的缓冲上的阻塞
+		s.closeWithError(ctx.Err())
 		_ = s.conn.Close()
 		return ctx.Err()
 	}
@@ -295,6 +260,8 @@ func (s *dashScopeStream) sendFinishTask(ctx context.Context) error {
 func (s *dashScopeStream) startReceiver() {
 	go func() {
 		for {
+			// 缓冲超过高水位时暂停读取，由 TCP 流控让服务端放慢发送
+			s.audioBuf.waitResumed()
 			messageType, data, err := s.conn.ReadMessage()
 			if err != nil {
 				s.closeWithError(err)
@@ -390,6 +357,15 @@ func normalizeConfig(cfg Config) (Config, error) {
 	if cfg.Format == "" {
 		cfg.Format = "mp3"
 	}
+	if cfg.BufferBytes <= 0 {
+		cfg.BufferBytes = DefaultBufferBytes
+	}
+	if cfg.BufferPolicy == "" {
+		cfg.BufferPolicy = BufferBlock
+	}
+	if !cfg.BufferPolicy.Valid() {
+		return Config{}, fmt.Errorf("unknown tts buffer policy %q", cfg.BufferPolicy)
+	}
 	if cfg.SampleRate == 0 {
 		cfg.SampleRate = 22050
 	}
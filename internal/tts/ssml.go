@@ -0,0 +1,157 @@
+package tts
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Prosody 韵律参数
+// Rate、Pitch 为倍率（0 表示不调整），Volume 为绝对音量 0-100（0 表示不调整）
+type Prosody struct {
+	Rate   float64
+	Pitch  float64
+	Volume int
+}
+
+// SSMLConfig SSML 生成配置
+type SSMLConfig struct {
+	// CommaBreakMs 逗号、顿号后插入的停顿时长，0 表示不插入
+	CommaBreakMs int
+	// ExclamationPitchBoost 感叹句的音调提升比例，用于强调，0 表示不强调
+	ExclamationPitchBoost float64
+	// EmotionProsody 按情绪调整韵律
+	EmotionProsody map[string]Prosody
+	// VoiceProsody 按音色调整韵律（不同音色的基础语速、音调差异较大）
+	VoiceProsody map[string]Prosody
+}
+
+// DefaultSSMLConfig 默认 SSML 配置
+func DefaultSSMLConfig() SSMLConfig {
+	return SSMLConfig{
+		CommaBreakMs:          150,
+		ExclamationPitchBoost: 0.05,
+		EmotionProsody: map[string]Prosody{
+			"happy":   {Rate: 1.05, Pitch: 1.05},
+			"sad":     {Rate: 0.9, Pitch: 0.95},
+			"angry":   {Rate: 1.1, Pitch: 1.0},
+			"calm":    {Rate: 0.95, Pitch: 1.0},
+			"excited": {Rate: 1.1, Pitch: 1.1},
+		},
+		VoiceProsody: map[string]Prosody{},
+	}
+}
+
+// SSML 由 SSMLBuilder 生成、可以原样发送给 TTS 服务的 SSML 文档；
+// 普通字符串（包括看起来像 <speak> 文档的 LLM 输出）一律按纯文本转义
+type SSML string
+
+// SSMLBuilder 将纯文本句子包装为 SSML
+// 生成的标签遵循 CosyVoice SSML 子集：<speak rate pitch volume> 与 <break time>
+type SSMLBuilder struct {
+	config SSMLConfig
+}
+
+func NewSSMLBuilder(config SSMLConfig) *SSMLBuilder {
+	return &SSMLBuilder{config: config}
+}
+
+// Build 根据情绪、音色和标点生成 SSML，base 为 TTS 配置中的基础韵律
+func (b *SSMLBuilder) Build(text, emotion, voice string, base Prosody) SSML {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return ""
+	}
+
+	rate := orOne(base.Rate)
+	pitch := orOne(base.Pitch)
+	volume := base.Volume
+
+	for _, adjust := range []Prosody{b.config.VoiceProsody[voice], b.config.EmotionProsody[emotion]} {
+		rate *= orOne(adjust.Rate)
+		pitch *= orOne(adjust.Pitch)
+		if adjust.Volume > 0 {
+			volume = adjust.Volume
+		}
+	}
+	if b.config.ExclamationPitchBoost > 0 && isExclamation(text) {
+		pitch *= 1 + b.config.ExclamationPitchBoost
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<speak")
+	fmt.Fprintf(&sb, ` rate="%s"`, formatProsody(clamp(rate, 0.5, 2)))
+	fmt.Fprintf(&sb, ` pitch="%s"`, formatProsody(clamp(pitch, 0.5, 2)))
+	if volume > 0 {
+		fmt.Fprintf(&sb, ` volume="%d"`, int(clamp(float64(volume), 0, 100)))
+	}
+	sb.WriteString(">")
+	sb.WriteString(b.markupPauses(text))
+	sb.WriteString("</speak>")
+	return SSML(sb.String())
+}
+
+// markupPauses 转义文本并在逗号后插入停顿
+func (b *SSMLBuilder) markupPauses(text string) string {
+	runes := []rune(text)
+	var sb strings.Builder
+	for i, r := range runes {
+		sb.WriteString(EscapeSSML(string(r)))
+		if b.config.CommaBreakMs > 0 && isCommaPause(r) && i < len(runes)-1 {
+			fmt.Fprintf(&sb, `<break time="%dms"/>`, b.config.CommaBreakMs)
+		}
+	}
+	return sb.String()
+}
+
+// EscapeSSML 转义 XML 特殊字符，避免用户文本破坏 SSML 结构
+func EscapeSSML(text string) string {
+	replacer := strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		`"`, "&quot;",
+		"'", "&apos;",
+	)
+	return replacer.Replace(text)
+}
+
+// WrapSSML 将纯文本转义后包装为最简 SSML 文档
+func WrapSSML(text string) string {
+	return "<speak>" + EscapeSSML(text) + "</speak>"
+}
+
+func isCommaPause(r rune) bool {
+	switch r {
+	case ',', '，', '、':
+		return true
+	default:
+		return false
+	}
+}
+
+func isExclamation(text string) bool {
+	return strings.HasSuffix(text, "!") || strings.HasSuffix(text, "！")
+}
+
+func orOne(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	return v
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+func formatProsody(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
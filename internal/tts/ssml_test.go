@@ -0,0 +1,85 @@
+package tts
+
+import "testing"
+
+func TestSSMLBuilderBuild(t *testing.T) {
+	builder := NewSSMLBuilder(DefaultSSMLConfig())
+
+	tests := []struct {
+		name    string
+		text    string
+		emotion string
+		voice   string
+		base    Prosody
+		want    SSML
+	}{
+		{
+			name:    "comma pause and emotion",
+			text:    "你好，欢迎回来。",
+			emotion: "sad",
+			base:    Prosody{Rate: 1, Pitch: 1, Volume: 50},
+			want:    `<speak rate="0.9" pitch="0.95" volume="50">你好，<break time="150ms"/>欢迎回来。</speak>`,
+		},
+		{
+			name:    "exclamation emphasis",
+			text:    "太棒了！",
+			emotion: "default",
+			base:    Prosody{Rate: 1, Pitch: 1},
+			want:    `<speak rate="1" pitch="1.05">太棒了！</speak>`,
+		},
+		{
+			name:    "escapes user text",
+			text:    `a<b & "c"`,
+			emotion: "default",
+			want:    `<speak rate="1" pitch="1">a&lt;b &amp; &quot;c&quot;</speak>`,
+		},
+		{
+			name:    "trailing comma has no break",
+			text:    "然后，",
+			emotion: "default",
+			want:    `<speak rate="1" pitch="1">然后，</speak>`,
+		},
+		{
+			name: "empty text",
+			text: "  ",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := builder.Build(tt.text, tt.emotion, tt.voice, tt.base)
+			if got != tt.want {
+				t.Fatalf("Build() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSSMLBuilderVoiceProsody(t *testing.T) {
+	cfg := DefaultSSMLConfig()
+	cfg.VoiceProsody["zhichu"] = Prosody{Rate: 1.2, Volume: 80}
+	builder := NewSSMLBuilder(cfg)
+
+	got := builder.Build("好的", "excited", "zhichu", Prosody{Rate: 1.5, Pitch: 1, Volume: 50})
+	want := SSML(`<speak rate="1.98" pitch="1.1" volume="80">好的</speak>`)
+	if got != want {
+		t.Fatalf("Build() = %q, want %q", got, want)
+	}
+}
+
+func TestWrapSSML(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"1 < 2", "<speak>1 &lt; 2</speak>"},
+		// 形如 SSML 文档的文本（如 LLM 输出）同样转义，不能注入标签
+		{`<speak rate="2"><break time="10s"/></speak>`, `<speak>&lt;speak rate=&quot;2&quot;&gt;&lt;break time=&quot;10s&quot;/&gt;&lt;/speak&gt;</speak>`},
+	}
+	for _, tt := range tests {
+		if got := WrapSSML(tt.text); got != tt.want {
+			t.Errorf("WrapSSML(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
@@ -20,6 +20,10 @@ type Config struct {
 	EnableSSML           bool
 	TextType             string
 	EnableDataInspection *bool
+	// BufferBytes 每个 Stream 未读音频的缓冲上限，<=0 时使用 DefaultBufferBytes
+	BufferBytes int
+	// BufferPolicy 缓冲写满时的处理方式，默认 BufferBlock
+	BufferPolicy BufferPolicy
 }
 
 type Provider interface {
@@ -34,6 +38,22 @@ type Stream interface {
 	Channels() int   // 返回音频声道数 (1=mono, 2=stereo)
 }
 
+// SSMLWriter 可选接口：原样发送 SSMLBuilder 生成的 SSML 文档；开启 SSML 时 WriteTextChunk 总是把文本转义后包装
+type SSMLWriter interface {
+	WriteSSML(ctx context.Context, doc SSML) error
+}
+
+// BackpressureNotifier 可选接口：Stream 未读音频超过高水位（暂停接收）或回落到低水位（恢复接收）时回调
+type BackpressureNotifier interface {
+	OnBackpressure(handler func(paused bool))
+}
+
+// SampleFormatter 可选接口：Stream 输出的音频不是 16-bit PCM 时返回采样格式名称（如 "f32"、"s24"），
+// 由管线转换为 16-bit；未实现时按 16-bit PCM 处理
+type SampleFormatter interface {
+	SampleFormat() string
+}
+
 var (
 	ErrTransient  = errors.New("tts transient error")
 	ErrAuth       = errors.New("tts auth error")
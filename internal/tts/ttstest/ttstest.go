@@ -0,0 +1,292 @@
+// Package ttstest 提供确定性的 TTS 测试替身，供嵌入 orion-x 的下游代码在不访问真实服务的情况下测试
+package ttstest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/tts"
+)
+
+// ErrStreamClosed 向已关闭的 Stream 写入文本时返回
+var ErrStreamClosed = errors.New("ttstest: stream closed")
+
+// Config 假 Provider 配置
+type Config struct {
+	// StartDelay Start 返回前的等待时长，模拟建连耗时
+	StartDelay time.Duration
+	// ChunkLatency 每个文本块写入后到对应音频可读的延迟，模拟合成耗时
+	ChunkLatency time.Duration
+	// BytesPerRune 每个字符生成的音频字节数，默认 100
+	BytesPerRune int
+	SampleRate   int // 默认 16000
+	Channels     int // 默认 1
+
+	// StartErr / WriteErr / CloseErr 非空时对应调用返回该错误
+	StartErr error
+	WriteErr error
+	CloseErr error
+}
+
+// DefaultConfig 默认配置：无延迟、无错误，16kHz 单声道，每字 100 字节
+func DefaultConfig() Config {
+	return Config{
+		BytesPerRune: 100,
+		SampleRate:   16000,
+		Channels:     1,
+	}
+}
+
+// Provider 实现 tts.Provider：每个字符生成固定长度、内容确定的音频，并记录所有调用
+type Provider struct {
+	mu      sync.Mutex
+	cfg     Config
+	configs []tts.Config
+	streams []*Stream
+}
+
+var _ tts.Provider = (*Provider)(nil)
+
+// NewProvider 使用默认配置创建假 Provider
+func NewProvider() *Provider {
+	return NewProviderWithConfig(DefaultConfig())
+}
+
+// NewProviderWithConfig 使用指定配置创建假 Provider，未设置的字段取默认值
+func NewProviderWithConfig(cfg Config) *Provider {
+	defaults := DefaultConfig()
+	if cfg.BytesPerRune <= 0 {
+		cfg.BytesPerRune = defaults.BytesPerRune
+	}
+	if cfg.SampleRate <= 0 {
+		cfg.SampleRate = defaults.SampleRate
+	}
+	if cfg.Channels <= 0 {
+		cfg.Channels = defaults.Channels
+	}
+	return &Provider{cfg: cfg}
+}
+
+// SetStartErr 修改后续 Start 返回的错误，nil 表示恢复正常
+func (p *Provider) SetStartErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cfg.StartErr = err
+}
+
+// SetWriteErr 修改后续创建的 Stream 写入时返回的错误
+func (p *Provider) SetWriteErr(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cfg.WriteErr = err
+}
+
+func (p *Provider) Start(ctx context.Context, cfg tts.Config) (tts.Stream, error) {
+	p.mu.Lock()
+	p.configs = append(p.configs, cfg)
+	pcfg := p.cfg
+	p.mu.Unlock()
+
+	if err := sleep(ctx, pcfg.StartDelay); err != nil {
+		return nil, err
+	}
+	if pcfg.StartErr != nil {
+		return nil, pcfg.StartErr
+	}
+
+	s := newStream(pcfg)
+	p.mu.Lock()
+	p.streams = append(p.streams, s)
+	p.mu.Unlock()
+	return s, nil
+}
+
+// Starts 返回 Start 被调用的次数（包括失败的调用）
+func (p *Provider) Starts() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.configs)
+}
+
+// Configs 返回每次 Start 收到的配置
+func (p *Provider) Configs() []tts.Config {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]tts.Config(nil), p.configs...)
+}
+
+// LastConfig 返回最近一次 Start 收到的配置
+func (p *Provider) LastConfig() tts.Config {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.configs) == 0 {
+		return tts.Config{}
+	}
+	return p.configs[len(p.configs)-1]
+}
+
+// Streams 返回成功创建的 Stream
+func (p *Provider) Streams() []*Stream {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*Stream(nil), p.streams...)
+}
+
+// Stream 实现 tts.Stream：写入的文本按顺序、经过 ChunkLatency 后转换为音频
+type Stream struct {
+	cfg Config
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	texts   []string
+	pending []string
+	audio   []byte
+	read    int
+	written int
+	closing bool
+	done    bool
+	closed  bool // 读端已关闭
+}
+
+var _ tts.Stream = (*Stream)(nil)
+
+func newStream(cfg Config) *Stream {
+	s := &Stream{cfg: cfg}
+	s.cond = sync.NewCond(&s.mu)
+	go s.synthesize()
+	return s
+}
+
+// synthesize 按写入顺序逐块生成音频
+func (s *Stream) synthesize() {
+	for {
+		s.mu.Lock()
+		for len(s.pending) == 0 && !s.closing {
+			s.cond.Wait()
+		}
+		if len(s.pending) == 0 {
+			s.done = true
+			s.cond.Broadcast()
+			s.mu.Unlock()
+			return
+		}
+		text := s.pending[0]
+		s.pending = s.pending[1:]
+		s.mu.Unlock()
+
+		if s.cfg.ChunkLatency > 0 {
+			time.Sleep(s.cfg.ChunkLatency)
+		}
+
+		s.mu.Lock()
+		s.audio = append(s.audio, Audio(text, s.cfg.BytesPerRune, s.written)...)
+		s.written += len([]rune(text)) * s.cfg.BytesPerRune
+		s.cond.Broadcast()
+		s.mu.Unlock()
+	}
+}
+
+func (s *Stream) WriteTextChunk(ctx context.Context, text string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closing {
+		return ErrStreamClosed
+	}
+	s.texts = append(s.texts, text)
+	if s.cfg.WriteErr != nil {
+		return s.cfg.WriteErr
+	}
+	s.pending = append(s.pending, text)
+	s.cond.Broadcast()
+	return nil
+}
+
+// Close 结束输入：已写入的文本合成完毕后音频读取返回 io.EOF
+func (s *Stream) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closing = true
+	s.cond.Broadcast()
+	return s.cfg.CloseErr
+}
+
+func (s *Stream) AudioReader() io.ReadCloser {
+	return streamReader{s}
+}
+
+func (s *Stream) SampleRate() int {
+	return s.cfg.SampleRate
+}
+
+func (s *Stream) Channels() int {
+	return s.cfg.Channels
+}
+
+// Texts 返回写入过的全部文本块
+func (s *Stream) Texts() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.texts...)
+}
+
+// Closed 返回 Close 是否已被调用
+func (s *Stream) Closed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closing
+}
+
+type streamReader struct {
+	s *Stream
+}
+
+func (r streamReader) Read(p []byte) (int, error) {
+	s := r.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for s.read >= len(s.audio) && !s.done && !s.closed {
+		s.cond.Wait()
+	}
+	if s.closed || s.read >= len(s.audio) {
+		return 0, io.EOF
+	}
+	n := copy(p, s.audio[s.read:])
+	s.read += n
+	return n, nil
+}
+
+func (r streamReader) Close() error {
+	s := r.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	s.closing = true
+	s.cond.Broadcast()
+	return nil
+}
+
+// Audio 返回文本对应的确定性音频：每个字符 bytesPerRune 字节，内容为从 offset 起的递增字节序列
+func Audio(text string, bytesPerRune, offset int) []byte {
+	data := make([]byte, len([]rune(text))*bytesPerRune)
+	for i := range data {
+		data[i] = byte((offset + i) % 256)
+	}
+	return data
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,116 @@
+package ttstest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/tts"
+)
+
+func TestProviderSynthesizesDeterministicAudio(t *testing.T) {
+	p := NewProvider()
+	stream, err := p.Start(context.Background(), tts.Config{Voice: "longanyang"})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	for _, chunk := range []string{"你好", "。"} {
+		if err := stream.WriteTextChunk(context.Background(), chunk); err != nil {
+			t.Fatalf("WriteTextChunk() error = %v", err)
+		}
+	}
+	if err := stream.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	got, err := io.ReadAll(stream.AudioReader())
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if want := Audio("你好。", 100, 0); !bytes.Equal(got, want) {
+		t.Errorf("audio = %d bytes, want %d deterministic bytes", len(got), len(want))
+	}
+	if p.Starts() != 1 || p.LastConfig().Voice != "longanyang" {
+		t.Errorf("Starts() = %d, LastConfig().Voice = %q", p.Starts(), p.LastConfig().Voice)
+	}
+	s := p.Streams()[0]
+	if texts := s.Texts(); len(texts) != 2 || !s.Closed() {
+		t.Errorf("Texts() = %q, Closed() = %v", texts, s.Closed())
+	}
+	if err := stream.WriteTextChunk(context.Background(), "再见"); !errors.Is(err, ErrStreamClosed) {
+		t.Errorf("WriteTextChunk() after Close error = %v, want ErrStreamClosed", err)
+	}
+}
+
+func TestProviderLatency(t *testing.T) {
+	p := NewProviderWithConfig(Config{StartDelay: 20 * time.Millisecond, ChunkLatency: 30 * time.Millisecond})
+	start := time.Now()
+	stream, err := p.Start(context.Background(), tts.Config{})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Start() returned after %v, want >= StartDelay", elapsed)
+	}
+
+	written := time.Now()
+	if err := stream.WriteTextChunk(context.Background(), "好"); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	n, err := stream.AudioReader().Read(buf)
+	if err != nil || n != 100 {
+		t.Fatalf("Read() = %d, %v; want 100 bytes", n, err)
+	}
+	if elapsed := time.Since(written); elapsed < 30*time.Millisecond {
+		t.Errorf("audio readable after %v, want >= ChunkLatency", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := p.Start(ctx, tts.Config{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Start() with canceled ctx error = %v, want context.Canceled", err)
+	}
+}
+
+func TestProviderErrorInjection(t *testing.T) {
+	p := NewProviderWithConfig(Config{StartErr: tts.ErrTransient})
+	if _, err := p.Start(context.Background(), tts.Config{}); !errors.Is(err, tts.ErrTransient) {
+		t.Fatalf("Start() error = %v, want ErrTransient", err)
+	}
+	if p.Starts() != 1 || len(p.Streams()) != 0 {
+		t.Errorf("Starts() = %d, Streams() = %d", p.Starts(), len(p.Streams()))
+	}
+
+	p.SetStartErr(nil)
+	p.SetWriteErr(tts.ErrBadRequest)
+	stream, err := p.Start(context.Background(), tts.Config{})
+	if err != nil {
+		t.Fatalf("Start() after SetStartErr(nil) error = %v", err)
+	}
+	if err := stream.WriteTextChunk(context.Background(), "好"); !errors.Is(err, tts.ErrBadRequest) {
+		t.Errorf("WriteTextChunk() error = %v, want ErrBadRequest", err)
+	}
+}
+
+func TestStreamReaderClose(t *testing.T) {
+	stream, _ := NewProvider().Start(context.Background(), tts.Config{})
+	reader := stream.AudioReader()
+	done := make(chan error, 1)
+	go func() {
+		_, err := reader.Read(make([]byte, 16))
+		done <- err
+	}()
+	reader.Close()
+	select {
+	case err := <-done:
+		if err != io.EOF {
+			t.Errorf("Read() after reader Close error = %v, want io.EOF", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Read() not unblocked by reader Close")
+	}
+}
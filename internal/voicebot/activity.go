@@ -0,0 +1,116 @@
+package voicebot
+
+import (
+	"sync"
+)
+
+// Activity 面向设备指示灯的语音活动状态，比 State 更细：区分检测到说话和真正开始 / 结束出声
+type Activity string
+
+const (
+	ActivityIdle            Activity = "idle"             // 空闲，等待说话
+	ActivityListening       Activity = "listening"        // 开始聆听（打断后或等待追问的回答）
+	ActivitySpeech          Activity = "speech"           // 检测到用户说话（VAD 或中间识别结果）
+	ActivityThinking        Activity = "thinking"         // 已收到一句话，等待 Agent 回复或工具结果
+	ActivitySpeakingStarted Activity = "speaking_started" // 回复开始出声（首句真正开始播放）
+	ActivitySpeakingEnded   Activity = "speaking_ended"   // 回复播完或被打断
+	ActivitySleeping        Activity = "sleeping"         // 休眠，只响应唤醒词
+)
+
+// ActivityIndicator 设备状态指示（如树莓派 LED 灯环），按发生顺序接收 Activity；
+// 在事件总线的订阅协程中调用，处理较慢时丢弃的是自己的事件，不影响对话
+type ActivityIndicator interface {
+	OnActivity(activity Activity)
+}
+
+// stateActivity 状态对应的 Activity；Speaking 以实际出声为准（见 activityTracker），不由状态决定
+func stateActivity(state State) (Activity, bool) {
+	switch state {
+	case StateIdle:
+		return ActivityIdle, true
+	case StateListening:
+		return ActivityListening, true
+	case StateProcessing:
+		return ActivityThinking, true
+	case StateSleeping:
+		return ActivitySleeping, true
+	default:
+		return "", false
+	}
+}
+
+// activityTracker 把状态变化、说话检测和播放进度合成为 Activity 序列：
+// 出声期间状态变化（如边播报边执行工具）只记录，播完后再补发；连续的说话检测只发布一次
+type activityTracker struct {
+	mu       sync.Mutex
+	state    Activity // 当前状态对应的 Activity
+	shown    Activity // 最近发布的状态 Activity
+	last     Activity // 最近发布的 Activity
+	speaking bool
+	publish  func(activity Activity)
+}
+
+func newActivityTracker(publish func(activity Activity)) *activityTracker {
+	return &activityTracker{state: ActivityIdle, shown: ActivityIdle, last: ActivityIdle, publish: publish}
+}
+
+// emitLocked 发布并记录，调用方需持有 t.mu（保证发布顺序）
+func (t *activityTracker) emitLocked(activity Activity) {
+	t.last = activity
+	t.publish(activity)
+}
+
+// stateChanged 状态切换后调用
+func (t *activityTracker) stateChanged(state State) {
+	activity, ok := stateActivity(state)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if activity == t.state {
+		return
+	}
+	t.state = activity
+	if t.speaking {
+		return
+	}
+	t.shown = activity
+	t.emitLocked(activity)
+}
+
+// speech 检测到用户说话，出声期间（未打断）忽略
+func (t *activityTracker) speech() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.speaking || t.last == ActivitySpeech {
+		return
+	}
+	t.emitLocked(ActivitySpeech)
+}
+
+// speakingStarted 回复开始出声，已在出声时忽略
+func (t *activityTracker) speakingStarted() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.speaking {
+		return
+	}
+	t.speaking = true
+	t.emitLocked(ActivitySpeakingStarted)
+}
+
+// speakingEnded 回复播完或被打断；出声期间状态有变化时随后补发当前状态
+func (t *activityTracker) speakingEnded() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.speaking {
+		return
+	}
+	t.speaking = false
+	t.emitLocked(ActivitySpeakingEnded)
+	if t.state != t.shown {
+		t.shown = t.state
+		t.emitLocked(t.state)
+	}
+}
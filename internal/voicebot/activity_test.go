@@ -0,0 +1,145 @@
+package voicebot
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+)
+
+func TestActivityTracker(t *testing.T) {
+	tests := []struct {
+		name  string
+		steps func(tr *activityTracker)
+		want  []Activity
+	}{
+		{
+			name: "turn",
+			steps: func(tr *activityTracker) {
+				tr.speech()
+				tr.speech()
+				tr.stateChanged(StateProcessing)
+				tr.speakingStarted()
+				tr.stateChanged(StateSpeaking)
+				tr.speakingStarted()
+				tr.speakingEnded()
+				tr.stateChanged(StateIdle)
+			},
+			want: []Activity{ActivitySpeech, ActivityThinking, ActivitySpeakingStarted, ActivitySpeakingEnded, ActivityIdle},
+		},
+		{
+			name: "state changes while speaking",
+			steps: func(tr *activityTracker) {
+				tr.stateChanged(StateProcessing)
+				tr.speakingStarted()
+				tr.speech()
+				tr.stateChanged(StateIdle)
+				tr.stateChanged(StateProcessing)
+				tr.speakingEnded()
+			},
+			want: []Activity{ActivityThinking, ActivitySpeakingStarted, ActivitySpeakingEnded},
+		},
+		{
+			name: "ended after state change",
+			steps: func(tr *activityTracker) {
+				tr.speakingStarted()
+				tr.stateChanged(StateListening)
+				tr.speakingEnded()
+				tr.speakingEnded()
+			},
+			want: []Activity{ActivitySpeakingStarted, ActivitySpeakingEnded, ActivityListening},
+		},
+		{
+			name: "sleep",
+			steps: func(tr *activityTracker) {
+				tr.stateChanged(StateIdle)
+				tr.stateChanged(StateSleeping)
+				tr.stateChanged(StateIdle)
+			},
+			want: []Activity{ActivitySleeping, ActivityIdle},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Activity
+			tt.steps(newActivityTracker(func(activity Activity) { got = append(got, activity) }))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("activities = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// recordingIndicator 记录收到的 Activity
+type recordingIndicator struct {
+	mu         sync.Mutex
+	activities []Activity
+}
+
+func (r *recordingIndicator) OnActivity(activity Activity) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.activities = append(r.activities, activity)
+}
+
+func (r *recordingIndicator) get() []Activity {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Activity(nil), r.activities...)
+}
+
+func waitForActivities(t *testing.T, indicator *recordingIndicator, want []Activity) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !reflect.DeepEqual(indicator.get(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("activities = %v, want %v", indicator.get(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestActivityIndicator(t *testing.T) {
+	tests := []struct {
+		name      string
+		interrupt bool
+		want      []Activity
+	}{
+		{"reply played", false, []Activity{
+			ActivitySpeech, ActivityThinking, ActivitySpeakingStarted, ActivitySpeakingEnded, ActivityIdle,
+		}},
+		{"interrupted", true, []Activity{
+			ActivitySpeech, ActivityThinking, ActivitySpeakingStarted, ActivitySpeakingEnded, ActivityListening, ActivitySpeech,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{
+				&agent.TextChunkEvent{Chunk: "北京今天晴。"},
+				&agent.FinishedEvent{},
+			}}
+			indicator := &recordingIndicator{}
+			cfg := DefaultOrchestratorConfig()
+			cfg.Indicator = indicator
+			orch := NewOrchestratorWithConfig(voiceAgent, newMockOutPipe(), nil, nil, cfg).(*orchestratorImpl)
+			if err := orch.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			defer orch.Stop()
+
+			orch.handleUserSpeakingDetected(NewUserSpeakingDetectedEvent())
+			orch.handleASRFinal(NewASRFinalEvent("北京天气怎么样"))
+			waitForTurns(t, voiceAgent, 1)
+			orch.wg.Wait()
+			if tt.interrupt {
+				orch.handleUserSpeakingDetected(NewUserSpeakingDetectedEvent())
+			} else {
+				orch.onTTSPlaybackFinished()
+			}
+			waitForActivities(t, indicator, tt.want)
+		})
+	}
+}
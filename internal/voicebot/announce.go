@@ -0,0 +1,81 @@
+package voicebot
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// Announce 不经过 LLM 直接播报文本，进行中的回复被打断；播报内容同样经过内容过滤和回复长度限制
+func (o *orchestratorImpl) Announce(content string) bool {
+	content = strings.TrimSpace(content)
+	if content == "" || o.isDraining() {
+		return false
+	}
+
+	o.mu.Lock()
+	o.reply.Reset()
+	o.takeHeldLocked()
+	o.mu.Unlock()
+
+	o.stopReply()
+	o.transitionTo(StateProcessing)
+	logging.Infof("Orchestrator: announcing %q", content)
+	o.speakText(content)
+
+	// 全部被内容过滤拒绝时没有可播放的句子
+	o.mu.Lock()
+	pending := o.ttsPendingCount
+	o.mu.Unlock()
+	if pending == 0 {
+		o.transitionTo(StateIdle)
+	}
+	return true
+}
+
+// Alert 不经过 LLM 紧急播报一段提醒（如定时提醒），不打断进行中的回复：正在处理或播报回复且 AudioOutPipe
+// 支持 audio.PrioritySpeechPlayer 时，提醒插到尚未播放的回复句之前，播完后继续回复；否则同 Announce
+func (o *orchestratorImpl) Alert(content string) bool {
+	content = strings.TrimSpace(content)
+	if content == "" || o.isDraining() {
+		return false
+	}
+
+	o.mu.Lock()
+	replying := o.agentCancel != nil || o.ttsPendingCount > 0
+	o.mu.Unlock()
+	if !replying || !o.speakUrgent(content, nil) {
+		return o.Announce(content)
+	}
+	logging.Infof("Orchestrator: alerting %q", content)
+	return true
+}
+
+// speakUrgent 把系统播报作为紧急项排入 TTS 播放队列，clip 非空时播放预合成音频；
+// 紧急播报不计入回复进度，AudioOutPipe 不支持 audio.PrioritySpeechPlayer 或入队失败时返回 false
+func (o *orchestratorImpl) speakUrgent(content string, clip []byte) bool {
+	player, ok := o.audioOutPipe.(audio.PrioritySpeechPlayer)
+	if !ok {
+		return false
+	}
+
+	o.mu.Lock()
+	language := o.language
+	o.mu.Unlock()
+	if clip == nil {
+		if normalizer := o.normalizerFor(language); normalizer != nil {
+			content = normalizer.Normalize(content)
+		}
+	}
+	opts := audio.SpeechOptions{Priority: audio.TTSPriorityUrgent, Language: language, Audio: clip}
+	if err := player.PlayTTSWithOptions(o.ctx, content, opts); err != nil {
+		logging.Errorf("Orchestrator: urgent speech error: %v", err)
+		return false
+	}
+	if clip == nil {
+		o.usage.AddTTSChars(utf8.RuneCountInString(content))
+	}
+	return true
+}
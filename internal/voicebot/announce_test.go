@@ -0,0 +1,80 @@
+package voicebot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestAnnounce(t *testing.T) {
+	outPipe := newMockOutPipe()
+	voiceAgent := &mockVoiceAgent{}
+	orch := NewOrchestratorWithConfig(voiceAgent, outPipe, nil, nil, DefaultOrchestratorConfig()).(*orchestratorImpl)
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	if orch.Announce("  ") {
+		t.Fatal("Announce(blank) = true, want false")
+	}
+	if !orch.Announce("门铃响了。有人在门口。") {
+		t.Fatal("Announce() = false, want true")
+	}
+	if got, want := outPipe.getPlayed(), []string{"门铃响了。", "有人在门口。"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("played = %v, want %v", got, want)
+	}
+	if got := orch.GetState(); got != StateSpeaking {
+		t.Fatalf("state = %s, want Speaking", got)
+	}
+	if got := len(voiceAgent.getTurns()); got != 0 {
+		t.Fatalf("agent turns = %d, want 0", got)
+	}
+}
+
+func TestAlert(t *testing.T) {
+	tests := []struct {
+		name       string
+		replying   bool
+		wantUrgent []string
+		wantPlayed []string
+		wantState  State
+	}{
+		{"idle falls back to announce", false, nil, []string{"该喝水了。"}, StateSpeaking},
+		{"jumps ahead of reply", true, []string{"该喝水了。"}, nil, StateIdle},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outPipe := newUrgentOutPipe()
+			orch := NewOrchestratorWithConfig(&mockVoiceAgent{}, outPipe, nil, nil, DefaultOrchestratorConfig()).(*orchestratorImpl)
+			if err := orch.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			defer orch.Stop()
+			if tt.replying {
+				orch.mu.Lock()
+				orch.ttsPendingCount = 1
+				orch.mu.Unlock()
+			}
+
+			if orch.Alert(" ") {
+				t.Fatal("Alert(blank) = true, want false")
+			}
+			if !orch.Alert("该喝水了。") {
+				t.Fatal("Alert() = false, want true")
+			}
+			if urgent, _ := outPipe.getUrgent(); !reflect.DeepEqual(urgent, tt.wantUrgent) {
+				t.Fatalf("urgent = %v, want %v", urgent, tt.wantUrgent)
+			}
+			if got := outPipe.getPlayed(); !reflect.DeepEqual(got, tt.wantPlayed) {
+				t.Fatalf("played = %v, want %v", got, tt.wantPlayed)
+			}
+			if tt.replying && outPipe.getInterrupts() != 0 {
+				t.Fatal("Alert interrupted the reply")
+			}
+			if got := orch.GetState(); got != tt.wantState {
+				t.Fatalf("state = %s, want %s", got, tt.wantState)
+			}
+		})
+	}
+}
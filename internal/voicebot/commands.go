@@ -0,0 +1,191 @@
+package voicebot
+
+import (
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/text"
+)
+
+// ControlCommand 由 Orchestrator 直接处理、不交给 Agent 的语音控制命令
+type ControlCommand string
+
+const (
+	CommandStop       ControlCommand = "stop"        // 停止当前回复
+	CommandVolumeUp   ControlCommand = "volume_up"   // 调大音量
+	CommandVolumeDown ControlCommand = "volume_down" // 调小音量
+	CommandRepeat     ControlCommand = "repeat"      // 重新播报上一轮回复
+	CommandMute       ControlCommand = "mute"        // 静音
+	CommandUnmute     ControlCommand = "unmute"      // 取消静音
+	CommandMicOff     ControlCommand = "mic_off"     // 关闭麦克风（隐私模式），只能通过快捷键或 API 恢复
+)
+
+// CommandPolicy 语音控制命令：整句匹配话术（忽略标点、空白和大小写），命中时不调用 Agent
+type CommandPolicy struct {
+	// Phrases 各命令的触发话术，为空时关闭语音控制命令
+	Phrases map[ControlCommand][]string
+
+	// VolumeStep 每次调大 / 调小的整体音量，作用于 OrchestratorConfig.Volume
+	VolumeStep float64
+}
+
+// DefaultCommandPhrases 默认的控制命令话术
+func DefaultCommandPhrases() map[ControlCommand][]string {
+	return map[ControlCommand][]string{
+		CommandStop:       {"停", "停下", "停一下", "别说了", "不要说了", "闭嘴", "安静", "stop", "be quiet"},
+		CommandVolumeUp:   {"大声点", "大声一点", "声音大一点", "调大音量", "louder", "volume up"},
+		CommandVolumeDown: {"小声点", "小声一点", "声音小一点", "调小音量", "quieter", "volume down"},
+		CommandRepeat:     {"再说一遍", "再说一次", "重复一遍", "重复一下", "repeat", "say that again"},
+		CommandMute:       {"静音", "mute"},
+		CommandUnmute:     {"取消静音", "unmute"},
+		CommandMicOff:     {"关闭麦克风", "关掉麦克风", "别听了", "隐私模式", "mic off", "stop listening"},
+	}
+}
+
+// DefaultCommandPolicy 默认开启控制命令，每次调节 20% 音量
+func DefaultCommandPolicy() CommandPolicy {
+	return CommandPolicy{
+		Phrases:    DefaultCommandPhrases(),
+		VolumeStep: 0.2,
+	}
+}
+
+// match 返回整句匹配的控制命令
+func (p CommandPolicy) match(input string) (ControlCommand, bool) {
+	normalized := normalizeUtterance(input)
+	if normalized == "" {
+		return "", false
+	}
+	for command, phrases := range p.Phrases {
+		for _, phrase := range phrases {
+			if normalizeUtterance(phrase) == normalized {
+				return command, true
+			}
+		}
+	}
+	return "", false
+}
+
+// handleCommand 处理语音控制命令，返回 true 表示本句已处理、不再交给 Agent；
+// 没有可重复的回复或未配置音量控制时返回 false，由 Agent 照常回答
+func (o *orchestratorImpl) handleCommand(asrEvent *ASRFinalEvent) bool {
+	command, ok := o.config.Commands.match(asrEvent.Text)
+	if !ok {
+		return false
+	}
+
+	switch command {
+	case CommandStop:
+		o.stopCommand()
+	case CommandMicOff:
+		o.SetMicMuted(true)
+	case CommandRepeat:
+		if !o.RepeatLastReply() {
+			return false
+		}
+	case CommandVolumeUp, CommandVolumeDown, CommandMute, CommandUnmute:
+		if !o.changeVolume(command) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	logging.Infof("Orchestrator: handled control command %s: %q", command, asrEvent.Text)
+	o.mu.Lock()
+	o.reprompts = 0
+	o.mu.Unlock()
+	o.eventBus.Publish(NewControlCommandEvent(command, asrEvent.Text))
+	return true
+}
+
+// stopCommand 停止正在生成或播放的回复并回到 Idle；被打断的内容仍可用“继续”恢复
+func (o *orchestratorImpl) stopCommand() {
+	o.mu.Lock()
+	active := o.agentCancel != nil || o.ttsPendingCount > 0
+	o.mu.Unlock()
+	state := o.stateMachine.GetCurrentState()
+	if active || state == StateProcessing || state == StateSpeaking {
+		o.stopReply()
+	}
+	o.transitionTo(StateIdle)
+}
+
+// changeVolume 调节整体音量；静音时记住原音量，取消静音或调大音量时恢复
+func (o *orchestratorImpl) changeVolume(command ControlCommand) bool {
+	control := o.config.Volume
+	if control == nil {
+		return false
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	current := control.Volume()
+	if current == 0 && o.mutedVolume > 0 && command != CommandMute {
+		current = o.mutedVolume
+	}
+
+	volume := current
+	switch command {
+	case CommandVolumeUp:
+		volume = current + o.config.Commands.VolumeStep
+	case CommandVolumeDown:
+		volume = current - o.config.Commands.VolumeStep
+	case CommandMute:
+		if current > 0 {
+			o.mutedVolume = current
+		}
+		volume = 0
+	case CommandUnmute:
+		if volume == 0 {
+			volume = 1
+		}
+	}
+	volume = audio.ClampVolume(volume)
+	if command != CommandMute {
+		o.mutedVolume = 0
+	}
+	logging.Infof("Orchestrator: volume %.2f -> %.2f", current, volume)
+	control.SetVolume(volume)
+	return true
+}
+
+// Volume 返回当前整体音量，未配置音量控制时返回 false
+func (o *orchestratorImpl) Volume() (float64, bool) {
+	if o.config.Volume == nil {
+		return 0, false
+	}
+	return o.config.Volume.Volume(), true
+}
+
+// SetVolume 设置整体音量（限幅到 0~1），返回实际生效的音量；未配置音量控制时返回 false
+func (o *orchestratorImpl) SetVolume(volume float64) (float64, bool) {
+	control := o.config.Volume
+	if control == nil {
+		return 0, false
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	current := control.Volume()
+	volume = audio.ClampVolume(volume)
+	if volume > 0 {
+		o.mutedVolume = 0
+	}
+	logging.Infof("Orchestrator: volume %.2f -> %.2f", current, volume)
+	control.SetVolume(volume)
+	return control.Volume(), true
+}
+
+// speakText 把一段完整文本重新分句后播报，打断时仍能按句追踪播放进度
+func (o *orchestratorImpl) speakText(content string) {
+	segmenter := text.NewSegmenter(o.segmenter.MaxRunes)
+	sentences := segmenter.Feed(content)
+	if last := segmenter.Flush(); last != "" {
+		sentences = append(sentences, last)
+	}
+	for _, sentence := range sentences {
+		if err := o.speak(sentence); err != nil {
+			break
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package voicebot
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio/audiotest"
+)
+
+func TestCommandPolicyMatch(t *testing.T) {
+	policy := DefaultCommandPolicy()
+
+	tests := []struct {
+		input string
+		want  ControlCommand
+		ok    bool
+	}{
+		{"停。", CommandStop, true},
+		{"别说了！", CommandStop, true},
+		{"Stop.", CommandStop, true},
+		{"大声点", CommandVolumeUp, true},
+		{"声音小一点。", CommandVolumeDown, true},
+		{"再说一遍？", CommandRepeat, true},
+		{"静音", CommandMute, true},
+		{"取消静音", CommandUnmute, true},
+		{"停车场在哪里", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := policy.match(tt.input)
+			if got != tt.want || ok != tt.ok {
+				t.Fatalf("match(%q) = (%q, %v), want (%q, %v)", tt.input, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	if _, ok := (CommandPolicy{}).match("停"); ok {
+		t.Fatalf("match() should not match without phrases")
+	}
+}
+
+func TestControlCommands(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply string
+		run   func(t *testing.T, orch *orchestratorImpl, voiceAgent *mockVoiceAgent, outPipe *mockOutPipe, mixer *audiotest.Mixer)
+	}{
+		{
+			name:  "repeat",
+			reply: "今天晴。气温二十度。",
+			run: func(t *testing.T, orch *orchestratorImpl, voiceAgent *mockVoiceAgent, outPipe *mockOutPipe, _ *audiotest.Mixer) {
+				commands := make(chan *ControlCommandEvent, 1)
+				SubscribeTyped(orch, EventTypeControlCommand, func(e *ControlCommandEvent) { commands <- e })
+
+				// 还没有回复时交给 Agent
+				orch.handleASRFinal(NewASRFinalEvent("再说一遍"))
+				waitForTurns(t, voiceAgent, 1)
+				orch.wg.Wait()
+
+				orch.handleASRFinal(NewASRFinalEvent("再说一遍"))
+				want := []string{"今天晴。", "气温二十度。", "今天晴。", "气温二十度。"}
+				if got := outPipe.getPlayed(); !reflect.DeepEqual(got, want) {
+					t.Fatalf("played = %v, want %v", got, want)
+				}
+				if got := len(voiceAgent.getTurns()); got != 1 {
+					t.Fatalf("agent turns = %d, want 1", got)
+				}
+				select {
+				case e := <-commands:
+					if e.Command != CommandRepeat {
+						t.Fatalf("command event = %+v, want repeat", e)
+					}
+				case <-time.After(time.Second):
+					t.Fatal("expected ControlCommandEvent")
+				}
+			},
+		},
+		{
+			name:  "stop",
+			reply: "好的。",
+			run: func(t *testing.T, orch *orchestratorImpl, voiceAgent *mockVoiceAgent, outPipe *mockOutPipe, _ *audiotest.Mixer) {
+				orch.transitionTo(StateProcessing)
+				orch.transitionTo(StateSpeaking)
+
+				orch.handleASRFinal(NewASRFinalEvent("别说了"))
+				if got := len(voiceAgent.getTurns()); got != 0 {
+					t.Fatalf("agent turns = %d, want 0", got)
+				}
+				if got := outPipe.getInterrupts(); got != 1 {
+					t.Fatalf("interrupts = %d, want 1", got)
+				}
+				if got := orch.GetState(); got != StateIdle {
+					t.Fatalf("state = %s, want Idle", got)
+				}
+			},
+		},
+		{
+			name:  "volume",
+			reply: "好的。",
+			run: func(t *testing.T, orch *orchestratorImpl, voiceAgent *mockVoiceAgent, _ *mockOutPipe, mixer *audiotest.Mixer) {
+				steps := []struct {
+					input string
+					want  float64
+				}{
+					{"小声点", 0.8},
+					{"小声点", 0.6},
+					{"静音", 0},
+					{"大声点", 0.8},
+					{"静音", 0},
+					{"取消静音", 0.8},
+					{"大声点", 1},
+					{"大声点", 1},
+				}
+				for _, step := range steps {
+					orch.handleASRFinal(NewASRFinalEvent(step.input))
+					if got := mixer.Volume(); math.Abs(got-step.want) > 1e-9 {
+						t.Fatalf("after %q volume = %v, want %v", step.input, got, step.want)
+					}
+				}
+				if got := len(voiceAgent.getTurns()); got != 0 {
+					t.Fatalf("agent turns = %d, want 0", got)
+				}
+
+				// 未配置音量控制时交给 Agent
+				orch.config.Volume = nil
+				orch.handleASRFinal(NewASRFinalEvent("大声点"))
+				waitForTurns(t, voiceAgent, 1)
+			},
+		},
+		{
+			name:  "set volume",
+			reply: "好的。",
+			run: func(t *testing.T, orch *orchestratorImpl, _ *mockVoiceAgent, _ *mockOutPipe, mixer *audiotest.Mixer) {
+				sets := []struct {
+					set, want float64
+				}{
+					{0.5, 0.5},
+					{1.5, 1},
+					{-1, 0},
+				}
+				for _, tt := range sets {
+					got, ok := orch.SetVolume(tt.set)
+					if !ok || got != tt.want || mixer.Volume() != tt.want {
+						t.Fatalf("SetVolume(%v) = (%v, %v), mixer %v, want %v", tt.set, got, ok, mixer.Volume(), tt.want)
+					}
+				}
+
+				// 通过 API 静音后“取消静音”恢复到最大音量
+				orch.handleASRFinal(NewASRFinalEvent("取消静音"))
+				if got, _ := orch.Volume(); got != 1 {
+					t.Fatalf("volume after unmute = %v, want 1", got)
+				}
+
+				orch.config.Volume = nil
+				if _, ok := orch.SetVolume(0.5); ok {
+					t.Fatal("SetVolume() should fail without a volume control")
+				}
+				if _, ok := orch.Volume(); ok {
+					t.Fatal("Volume() should fail without a volume control")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{
+				&agent.TextChunkEvent{Chunk: tt.reply},
+				&agent.FinishedEvent{},
+			}}
+			mixer := audiotest.NewMixer()
+			cfg := DefaultOrchestratorConfig()
+			cfg.Volume = mixer
+			orch, outPipe := newTestOrchestrator(t, voiceAgent, cfg)
+			tt.run(t, orch, voiceAgent, outPipe, mixer)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package voicebot
+
+import (
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/speaker"
+	"github.com/liuscraft/orion-x/internal/text"
+)
+
+// OrchestratorConfig Orchestrator 配置
+type OrchestratorConfig struct {
+	// ResumeInterrupted 打断后保留未播放的剩余回复，用户说“继续”时重新送入 TTS
+	// 关闭时剩余回复只作为上下文传给下一轮 Agent
+	ResumeInterrupted bool
+
+	// ResumePhrases 触发恢复播放的话术（整句匹配，忽略标点、空白和大小写）
+	ResumePhrases []string
+
+	// FillerDelay LLM 首个响应超过该时长仍未到达时播放填充提示音（如“让我想想…”），0 表示关闭
+	// 真正的回复开始播放时，Mixer 会将填充音与 TTS 交叉淡化
+	FillerDelay time.Duration
+
+	// FillerPrompt 填充提示音名称（需已在 Prompts 中加载）
+	FillerPrompt string
+
+	// SegmentFlushDelay LLM 输出在句中停顿超过该时长时，把已缓冲的半句送入 TTS，0 表示只按标点分句
+	SegmentFlushDelay time.Duration
+
+	// EndOfTurnSilence 用户停顿判定窗口：ASR final 之后该时长内没有新的语音活动（VAD / 中间结果 / final）
+	// 才视为说完一轮，窗口内连续的 final 合并为一句交给 Agent；0 表示每句 final 都是完整的一轮
+	EndOfTurnSilence time.Duration
+
+	// ProcessingTimeout Processing 状态下超过该时长没有收到 Agent 事件时放弃本轮、播放出错提示音并回到 Idle，0 表示不限制
+	ProcessingTimeout time.Duration
+
+	// SpeakingTimeout Speaking 状态下超过该时长没有播放进度（句子开始或播完）时强制打断并回到 Idle，0 表示不限制
+	SpeakingTimeout time.Duration
+
+	// ErrorPolicy ASR / TTS / LLM 失败时的处理：错误分类、致歉提示音和 LLM 重试
+	ErrorPolicy ErrorPolicy
+
+	// Reprompt 识别结果置信度低或过短时请用户再说一遍，不交给 Agent
+	Reprompt RepromptPolicy
+
+	// EchoGuard 识别结果与最近播报的回复相似时视为回声丢弃，避免机器人回答自己
+	EchoGuard EchoGuardPolicy
+
+	// ReplyLimit 单轮回复的播报上限，超过后询问用户是否继续
+	ReplyLimit ReplyLimit
+
+	// ReplayAudio 缓存每轮回复完整播放的 TTS 音频，“再说一遍”时直接重播、不再调用 TTS（需要 AudioOutPipe 支持）
+	// 关闭时只保留文本，重播时重新合成
+	ReplayAudio bool
+
+	// Commands “停”“大声点”“再说一遍”等语音控制命令，由 Orchestrator 直接处理
+	Commands CommandPolicy
+
+	// Confirmation 指定的动作类工具执行前需要用户口头确认
+	Confirmation ConfirmationPolicy
+
+	// ToolProgress 长耗时工具的进度提醒：发布 ToolProgressEvent，并可在没有播报时说一句等待话术
+	ToolProgress ToolProgressPolicy
+
+	// HalfDuplex 半双工：播报期间暂停向 ASR 推流（本地 VAD 照常，可说话打断），播报结束后恢复，
+	// 用于没有回声消除的设备，避免识别到自己的声音并节省 ASR 用量；需要 AudioInPipe 实现 audio.StreamPauser
+	HalfDuplex bool
+
+	// FollowUpWindow Agent 回复以追问结尾（如“还需要查别的城市吗？”）时，播完后保持 Listening 等待回答的时长，
+	// 窗口内的下一轮会把追问带给 Agent；超时回到 Idle，0 表示关闭
+	FollowUpWindow time.Duration
+
+	// SlotFilling 工具调用缺少必填参数时先向用户追问，补全后再执行；追问播完后同样按 FollowUpWindow 保持聆听
+	SlotFilling SlotFillingPolicy
+
+	// Sleep 长时间空闲后进入休眠，只响应唤醒词
+	Sleep SleepPolicy
+
+	// Indicator 设备状态指示（如 LED 灯环），按顺序接收 ActivityEvent 中的语音活动，为 nil 时只发布事件
+	Indicator ActivityIndicator
+
+	// Volume 整体音量控制（通常是 audio.VolumeControl），供音量命令和 SetVolume 使用；
+	// 为 nil 时音量和静音话术照常交给 Agent
+	Volume audio.VolumeController
+
+	// Content 用户语句与回复的敏感内容过滤（屏蔽或拒绝）
+	Content ContentPolicy
+
+	// LevelMonitor 麦克风 / 扬声器电平监测：削波和麦克风长时间静音告警
+	LevelMonitor LevelMonitorConfig
+
+	// NormalizeText 送入 TTS 前规范化文本（数字读法、单位、网址等）
+	NormalizeText bool
+
+	// NormalizeLocale 文本规范化使用的语言规则（zh / en）
+	NormalizeLocale string
+
+	// DetectLanguage 按 ASR 结果检测用户语言：传给 Agent 要求用同一语言回复，
+	// 并按语言选择 TTS 音色（VoiceMap 中的 "情绪:语言" 键）和文本规范化规则
+	DetectLanguage bool
+
+	// ReplyLanguage 固定的回复语言（如翻译模式的目标语言），非空时不再检测用户语言，
+	// 只用于选择 TTS 音色和文本规范化规则
+	ReplyLanguage string
+
+	// SpeakerID 声纹识别器，非 nil 且 AudioInPipe 实现 audio.UtteranceAudioReporter 时，
+	// 对每句 ASR final 的音频识别说话人，结果记录在 ASRFinalEvent.Speaker 中
+	SpeakerID *speaker.Identifier
+
+	// SpeakerSampleRate 送入声纹识别的音频采样率（与 ASR 采样率一致）
+	SpeakerSampleRate int
+
+	// IgnoreUnknownSpeakers 忽略未注册说话人（或语音过短无法识别）的语句；关闭时只标注说话人
+	IgnoreUnknownSpeakers bool
+
+	// OnReplyText 按生成顺序同步接收 LLM 回复文本块（在 Agent goroutine 中调用，不应阻塞），
+	// 用于文本模式打印流式回复；事件总线缓冲写满时会丢弃事件，不适合逐块传递回复文本
+	OnReplyText func(chunk string)
+
+	// OnReplyFinished 每轮 Agent 结束（完成、出错或被打断）时调用
+	OnReplyFinished func()
+}
+
+// DefaultOrchestratorConfig 默认 Orchestrator 配置
+func DefaultOrchestratorConfig() *OrchestratorConfig {
+	return &OrchestratorConfig{
+		ResumeInterrupted: false,
+		ResumePhrases: []string{
+			"继续",
+			"继续说",
+			"接着说",
+			"你刚才说什么",
+			"你刚才说到哪了",
+			"continue",
+			"go on",
+			"you were saying",
+		},
+		FillerDelay:       0,
+		FillerPrompt:      audio.PromptThinking,
+		SegmentFlushDelay: 800 * time.Millisecond,
+		FollowUpWindow:    8 * time.Second,
+		ProcessingTimeout: 30 * time.Second,
+		SpeakingTimeout:   30 * time.Second,
+		ErrorPolicy:       DefaultErrorPolicy(),
+		Reprompt:          DefaultRepromptPolicy(),
+		EchoGuard:         DefaultEchoGuardPolicy(),
+		Commands:          DefaultCommandPolicy(),
+		Confirmation:      DefaultConfirmationPolicy(),
+		ToolProgress:      DefaultToolProgressPolicy(),
+		SlotFilling:       DefaultSlotFillingPolicy(),
+		Sleep:             DefaultSleepPolicy(),
+		ReplayAudio:       true,
+		Content:           ContentPolicy{RejectPrompt: audio.PromptRefuse},
+		ReplyLimit:        ReplyLimit{FollowUp: "需要我继续吗？"},
+		LevelMonitor:      DefaultLevelMonitorConfig(),
+		NormalizeText:     true,
+		NormalizeLocale:   text.LocaleZh,
+		DetectLanguage:    true,
+		SpeakerSampleRate: 16000,
+	}
+}
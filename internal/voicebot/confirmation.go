@@ -0,0 +1,200 @@
+package voicebot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// ErrDryRun 演练模式下确认的工具不会实际执行，工具结果事件带该错误
+var ErrDryRun = errors.New("dry run: tool not executed")
+
+// DefaultConfirmationPrompt 未配置确认话术时使用的话术，{{tool}} 为工具名
+const DefaultConfirmationPrompt = "确认执行{{tool}}吗？"
+
+// ConfirmationPolicy 高风险动作类工具（发送邮件、删除文件等）的口头确认：Agent 请求这些工具时不立即执行，
+// 先播报确认话术，用户回答肯定话术后才执行；回答否定话术、超时或说了别的话时放弃
+type ConfirmationPolicy struct {
+	// Tools 需要确认的工具及确认话术（如 "sendEmail": "确认发送给{{to}}吗？"），话术支持 {{参数名}} 替换，
+	// 为空字符串时使用 DefaultConfirmationPrompt；为空时关闭确认
+	Tools map[string]string
+
+	// YesPhrases / NoPhrases 肯定和否定回答（整句匹配，忽略标点、空白和大小写）
+	YesPhrases []string
+	NoPhrases  []string
+
+	// Timeout 等待回答的时长，超时后的下一句按普通对话处理，0 表示不限制
+	Timeout time.Duration
+
+	// ConfirmedText / CancelledText 确认执行、取消时的播报，为空时不播报
+	ConfirmedText string
+	CancelledText string
+
+	// DryRun 演练模式：确认后也不实际执行，只发布带 ErrDryRun 的工具结果事件
+	DryRun bool
+}
+
+// DefaultConfirmationPolicy 默认不需要确认任何工具，只提供回答话术和 15 秒超时
+func DefaultConfirmationPolicy() ConfirmationPolicy {
+	return ConfirmationPolicy{
+		YesPhrases:    []string{"确认", "确定", "是", "是的", "对", "对的", "好", "好的", "可以", "执行", "发送", "没问题", "yes", "ok", "okay", "confirm", "sure"},
+		NoPhrases:     []string{"不", "不要", "不用", "不用了", "不是", "取消", "算了", "别", "no", "cancel", "don't"},
+		Timeout:       15 * time.Second,
+		ConfirmedText: "好的",
+		CancelledText: "好的，已取消",
+	}
+}
+
+// ConfirmationStatus 待确认操作的状态
+type ConfirmationStatus string
+
+const (
+	ConfirmationRequested ConfirmationStatus = "requested" // 已播报确认话术，等待回答
+	ConfirmationConfirmed ConfirmationStatus = "confirmed" // 用户确认，工具已执行（演练模式下未执行）
+	ConfirmationCancelled ConfirmationStatus = "cancelled" // 用户否定或说了别的话
+	ConfirmationExpired   ConfirmationStatus = "expired"   // 超时未回答
+)
+
+// pendingAction 等待用户确认的工具调用
+type pendingAction struct {
+	call     tools.ToolCall
+	prompt   string
+	deadline time.Time // 零值表示不限制
+}
+
+// requiresConfirmation 返回工具是否需要确认及渲染后的确认话术
+func (p ConfirmationPolicy) requiresConfirmation(tool string, args map[string]interface{}) (string, bool) {
+	template, ok := p.Tools[tool]
+	if !ok {
+		return "", false
+	}
+	if strings.TrimSpace(template) == "" {
+		template = DefaultConfirmationPrompt
+	}
+	prompt := strings.ReplaceAll(template, "{{tool}}", tool)
+	for key, value := range args {
+		prompt = strings.ReplaceAll(prompt, "{{"+key+"}}", fmt.Sprint(value))
+	}
+	return prompt, true
+}
+
+// answer 判断回答是肯定（true）还是否定（false），都不是时 ok 为 false
+func (p ConfirmationPolicy) answer(input string) (yes bool, ok bool) {
+	normalized := normalizeUtterance(input)
+	if normalized == "" {
+		return false, false
+	}
+	for _, phrase := range p.NoPhrases {
+		if normalizeUtterance(phrase) == normalized {
+			return false, true
+		}
+	}
+	for _, phrase := range p.YesPhrases {
+		if normalizeUtterance(phrase) == normalized {
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// requestConfirmation 需要确认的工具调用挂起并播报确认话术，返回 true 表示调用已挂起、不再执行；
+// 同一轮的多个待确认调用共用一次回答
+func (o *orchestratorImpl) requestConfirmation(tool string, args map[string]interface{}) bool {
+	policy := o.config.Confirmation
+	prompt, ok := policy.requiresConfirmation(tool, args)
+	if !ok {
+		return false
+	}
+	action := pendingAction{call: tools.ToolCall{Tool: tool, Args: args}, prompt: prompt}
+	if policy.Timeout > 0 {
+		action.deadline = time.Now().Add(policy.Timeout)
+	}
+
+	o.mu.Lock()
+	o.pendingActions = append(o.pendingActions, action)
+	o.mu.Unlock()
+
+	logging.Infof("Orchestrator: tool %s requires confirmation, asking %q", tool, prompt)
+	o.eventBus.Publish(NewActionConfirmationEvent(tool, args, prompt, ConfirmationRequested))
+	o.speakText(prompt)
+	return true
+}
+
+// handleConfirmationAnswer 有待确认的操作时处理用户的回答，返回 true 表示本句是确认或否定、不再交给 Agent；
+// 超时或说了别的话时放弃待确认的操作，本句按普通对话处理
+func (o *orchestratorImpl) handleConfirmationAnswer(asrEvent *ASRFinalEvent) bool {
+	o.mu.Lock()
+	actions := o.pendingActions
+	o.pendingActions = nil
+	o.mu.Unlock()
+	if len(actions) == 0 {
+		return false
+	}
+
+	policy := o.config.Confirmation
+	if deadline := actions[0].deadline; !deadline.IsZero() && time.Now().After(deadline) {
+		logging.Infof("Orchestrator: confirmation expired, discarding %d pending tool call(s)", len(actions))
+		o.publishConfirmation(actions, ConfirmationExpired)
+		return false
+	}
+	yes, ok := policy.answer(asrEvent.Text)
+	if !ok {
+		logging.Infof("Orchestrator: no confirmation in %q, discarding %d pending tool call(s)", asrEvent.Text, len(actions))
+		o.publishConfirmation(actions, ConfirmationCancelled)
+		return false
+	}
+
+	o.transitionTo(StateProcessing)
+	if !yes {
+		logging.Infof("Orchestrator: user cancelled %d pending tool call(s)", len(actions))
+		o.publishConfirmation(actions, ConfirmationCancelled)
+		o.speakConfirmation(policy.CancelledText)
+		return true
+	}
+
+	logging.Infof("Orchestrator: user confirmed %d pending tool call(s) (dry run: %v)", len(actions), policy.DryRun)
+	o.publishConfirmation(actions, ConfirmationConfirmed)
+	o.speakConfirmation(policy.ConfirmedText)
+	ctx, _ := logging.StartTurnContext(o.ctx)
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		if policy.DryRun {
+			results := make([]tools.ToolCallResult, 0, len(actions))
+			for _, action := range actions {
+				results = append(results, tools.ToolCallResult{ToolCall: action.call, Err: ErrDryRun})
+			}
+			o.deliverToolResults(results)
+			return
+		}
+		batch := o.newToolBatch()
+		for _, action := range actions {
+			batch.Add(action.call.Tool, action.call.Args)
+		}
+		o.deliverToolResults(batch.Execute(ctx))
+	}()
+	return true
+}
+
+// speakConfirmation 播报确认结果，没有可播报的内容时回到 Idle
+func (o *orchestratorImpl) speakConfirmation(content string) {
+	if content != "" {
+		o.speakText(content)
+	}
+	o.mu.Lock()
+	pending := o.ttsPendingCount
+	o.mu.Unlock()
+	if pending == 0 {
+		o.transitionTo(StateIdle)
+	}
+}
+
+func (o *orchestratorImpl) publishConfirmation(actions []pendingAction, status ConfirmationStatus) {
+	for _, action := range actions {
+		o.eventBus.Publish(NewActionConfirmationEvent(action.call.Tool, action.call.Args, action.prompt, status))
+	}
+}
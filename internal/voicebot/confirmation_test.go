@@ -0,0 +1,148 @@
+package voicebot
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+func TestConfirmationPolicyAnswer(t *testing.T) {
+	policy := DefaultConfirmationPolicy()
+
+	tests := []struct {
+		input string
+		yes   bool
+		ok    bool
+	}{
+		{"确认。", true, true},
+		{"好的！", true, true},
+		{"OK", true, true},
+		{"取消", false, true},
+		{"不要", false, true},
+		{"No.", false, true},
+		{"明天天气怎么样", false, false},
+		{"", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			yes, ok := policy.answer(tt.input)
+			if yes != tt.yes || ok != tt.ok {
+				t.Fatalf("answer(%q) = (%v, %v), want (%v, %v)", tt.input, yes, ok, tt.yes, tt.ok)
+			}
+		})
+	}
+}
+
+func TestConfirmationPolicyPrompt(t *testing.T) {
+	policy := ConfirmationPolicy{Tools: map[string]string{
+		"sendEmail":  "确认发送给{{to}}吗？",
+		"deleteFile": "",
+	}}
+
+	tests := []struct {
+		tool string
+		args map[string]interface{}
+		want string
+		ok   bool
+	}{
+		{"sendEmail", map[string]interface{}{"to": "张三"}, "确认发送给张三吗？", true},
+		{"deleteFile", nil, "确认执行deleteFile吗？", true},
+		{"getTime", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			got, ok := policy.requiresConfirmation(tt.tool, tt.args)
+			if got != tt.want || ok != tt.ok {
+				t.Fatalf("requiresConfirmation(%q) = (%q, %v), want (%q, %v)", tt.tool, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestOrchestratorConfirmsActionTool(t *testing.T) {
+	tests := []struct {
+		name      string
+		answer    string
+		dryRun    bool
+		timeout   time.Duration // 非 0 时等确认超时后再回答
+		handled   bool
+		wantCalls int32
+		wantErr   error
+		status    ConfirmationStatus
+		spoken    string
+	}{
+		{"confirm", "确认", false, 0, true, 1, nil, ConfirmationConfirmed, "好的"},
+		{"dry run", "好的", true, 0, true, 0, ErrDryRun, ConfirmationConfirmed, "好的"},
+		{"cancel", "取消", false, 0, true, 0, nil, ConfirmationCancelled, "好的，已取消"},
+		{"other", "明天天气怎么样", false, 0, false, 0, nil, ConfirmationCancelled, ""},
+		{"expired", "确认", false, time.Millisecond, false, 0, nil, ConfirmationExpired, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// sendEmail 需要确认，calls 记录工具实际执行次数
+			var calls int32
+			executor := tools.NewToolExecutor()
+			executor.RegisterTool("sendEmail", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+				atomic.AddInt32(&calls, 1)
+				return "sent", nil, nil
+			})
+			cfg := DefaultOrchestratorConfig()
+			cfg.Confirmation.Tools = map[string]string{"sendEmail": "确认发送给{{to}}吗？"}
+			cfg.Confirmation.DryRun = tt.dryRun
+			if tt.timeout > 0 {
+				cfg.Confirmation.Timeout = tt.timeout
+			}
+			orch, outPipe := newTestOrchestratorWithTools(t, nil, executor, cfg)
+			confirmations := make(chan *ActionConfirmationEvent, 4)
+			SubscribeTyped(orch, EventTypeActionConfirmation, func(e *ActionConfirmationEvent) { confirmations <- e })
+			results := make(chan *ToolResultsEvent, 1)
+			SubscribeTyped(orch, EventTypeToolResults, func(e *ToolResultsEvent) { results <- e })
+
+			orch.handleAgentEvent(&agent.ToolCallRequestedEvent{Tool: "sendEmail", Args: map[string]interface{}{"to": "张三"}})
+			orch.handleAgentEvent(&agent.FinishedEvent{})
+			if got := (<-confirmations).Status; got != ConfirmationRequested {
+				t.Fatalf("status = %q, want requested", got)
+			}
+			if got := outPipe.getPlayed(); !reflect.DeepEqual(got, []string{"确认发送给张三吗？"}) {
+				t.Fatalf("played = %v", got)
+			}
+			time.Sleep(5 * tt.timeout)
+
+			if handled := orch.handleConfirmationAnswer(NewASRFinalEvent(tt.answer)); handled != tt.handled {
+				t.Fatalf("handleConfirmationAnswer(%q) = %v, want %v", tt.answer, handled, tt.handled)
+			}
+			if got := (<-confirmations).Status; got != tt.status {
+				t.Fatalf("status = %q, want %q", got, tt.status)
+			}
+			if tt.status == ConfirmationConfirmed {
+				select {
+				case got := <-results:
+					if len(got.Results) != 1 || !errors.Is(got.Results[0].Err, tt.wantErr) {
+						t.Fatalf("results = %+v", got.Results)
+					}
+				case <-time.After(time.Second):
+					t.Fatal("ToolResultsEvent not published")
+				}
+			}
+			orch.wg.Wait()
+			if got := atomic.LoadInt32(&calls); got != tt.wantCalls {
+				t.Fatalf("tool calls = %d, want %d", got, tt.wantCalls)
+			}
+			played := outPipe.getPlayed()
+			if tt.spoken != "" && played[len(played)-1] != tt.spoken {
+				t.Fatalf("played = %v, want last %q", played, tt.spoken)
+			}
+			// 回答之后不再有待确认的操作
+			if orch.handleConfirmationAnswer(NewASRFinalEvent("确认")) {
+				t.Fatal("pending actions should be cleared after the answer")
+			}
+		})
+	}
+}
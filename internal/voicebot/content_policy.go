@@ -0,0 +1,95 @@
+package voicebot
+
+import (
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/text"
+)
+
+// ContentAction 命中敏感内容时的处理方式
+type ContentAction string
+
+const (
+	ContentActionNone   ContentAction = ""       // 不过滤
+	ContentActionMask   ContentAction = "mask"   // 屏蔽敏感内容后继续
+	ContentActionReject ContentAction = "reject" // 放弃本轮并播放拒绝提示音
+)
+
+// ContentPolicy 敏感内容过滤：Input 作用于交给 Agent 之前的 ASR 文本，Output 作用于送入 TTS 之前的回复
+type ContentPolicy struct {
+	// Filter 过滤规则，为 nil 时不过滤
+	Filter *text.ContentFilter
+
+	// Input 用户语句命中时的处理：mask 把屏蔽后的文本交给 Agent，reject 不调用 Agent
+	Input ContentAction
+
+	// Output 回复命中时的处理：mask 屏蔽后播报，reject 停止本轮回复（已播报的部分不受影响）
+	Output ContentAction
+
+	// RejectPrompt reject 时播放的提示音名称（如“这个话题我们换一个吧”），未加载时不播放
+	RejectPrompt string
+}
+
+// filterInput 按 ContentPolicy.Input 过滤用户语句；返回 false 表示本轮被拒绝
+func (o *orchestratorImpl) filterInput(asrEvent *ASRFinalEvent) (*ASRFinalEvent, bool) {
+	policy := o.config.Content
+	if policy.Filter == nil || policy.Input == ContentActionNone {
+		return asrEvent, true
+	}
+	masked, hit := policy.Filter.Mask(asrEvent.Text)
+	if !hit {
+		return asrEvent, true
+	}
+	o.eventBus.Publish(NewContentFilteredEvent(AudioInput, policy.Input, masked))
+	if policy.Input == ContentActionReject {
+		logging.Infof("Orchestrator: user input rejected by content filter: %s", masked)
+		o.transitionTo(StateIdle)
+		o.playPrompt(policy.RejectPrompt)
+		return nil, false
+	}
+	logging.Infof("Orchestrator: user input masked by content filter: %s", masked)
+	filtered := *asrEvent
+	filtered.Text = masked
+	return &filtered, true
+}
+
+// filterOutput 按 ContentPolicy.Output 过滤即将送入 TTS 的句子；返回 false 表示本轮回复已被停止
+func (o *orchestratorImpl) filterOutput(sentence string) (string, bool) {
+	policy := o.config.Content
+	if policy.Filter == nil || policy.Output == ContentActionNone {
+		return sentence, true
+	}
+	masked, hit := policy.Filter.Mask(sentence)
+	if !hit {
+		return sentence, true
+	}
+	o.eventBus.Publish(NewContentFilteredEvent(AudioOutput, policy.Output, masked))
+	if policy.Output == ContentActionReject {
+		logging.Infof("Orchestrator: reply rejected by content filter: %s", masked)
+		o.rejectReply()
+		return "", false
+	}
+	logging.Infof("Orchestrator: reply masked by content filter: %s", masked)
+	return masked, true
+}
+
+// maskReplyText 屏蔽流式回复文本块中的敏感内容（用于 OnReplyText，跨块的敏感词无法识别）
+func (o *orchestratorImpl) maskReplyText(chunk string) string {
+	policy := o.config.Content
+	if policy.Filter == nil || policy.Output == ContentActionNone {
+		return chunk
+	}
+	masked, _ := policy.Filter.Mask(chunk)
+	return masked
+}
+
+// rejectReply 停止当前回复并播放拒绝提示音；被拒绝的内容不作为打断上下文传给下一轮
+func (o *orchestratorImpl) rejectReply() {
+	o.stopReply()
+	o.mu.Lock()
+	o.lastInterruption = nil
+	o.reply.Reset()
+	o.takeHeldLocked()
+	o.mu.Unlock()
+	o.transitionTo(StateIdle)
+	o.playPrompt(o.config.Content.RejectPrompt)
+}
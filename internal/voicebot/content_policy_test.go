@@ -0,0 +1,79 @@
+package voicebot
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/text"
+)
+
+func TestContentPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       ContentAction
+		output      ContentAction
+		asr         string
+		reply       string
+		wantInputs  []string // nil 表示不调用 Agent
+		wantPlayed  []string
+		wantPrompts []string
+	}{
+		{"input mask", ContentActionMask, ContentActionNone, "你这个笨蛋", "好的。", []string{"你这个**"}, []string{"好的。"}, nil},
+		{"input reject", ContentActionReject, ContentActionNone, "你这个笨蛋", "好的。", nil, nil, []string{audio.PromptRefuse}},
+		{"output mask", ContentActionNone, ContentActionMask, "夸夸我", "你真是个笨蛋。今天天气不错。", []string{"夸夸我"}, []string{"你真是个**。", "今天天气不错。"}, nil},
+		{"output reject", ContentActionNone, ContentActionReject, "夸夸我", "你真是个笨蛋。今天天气不错。", []string{"夸夸我"}, nil, []string{audio.PromptRefuse}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := text.NewContentFilter(text.ContentFilterConfig{Words: []string{"笨蛋"}})
+			if err != nil {
+				t.Fatalf("NewContentFilter() error = %v", err)
+			}
+			voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{
+				&agent.TextChunkEvent{Chunk: tt.reply},
+				&agent.FinishedEvent{},
+			}}
+			cfg := DefaultOrchestratorConfig()
+			cfg.Content.Filter = filter
+			cfg.Content.Input = tt.input
+			cfg.Content.Output = tt.output
+			orch, outPipe := newTestOrchestrator(t, voiceAgent, cfg)
+			prompts := newMockPrompts(audio.PromptRefuse)
+			orch.SetPrompts(prompts)
+			filtered := make(chan *ContentFilteredEvent, 1)
+			SubscribeTyped(orch, EventTypeContentFiltered, func(e *ContentFilteredEvent) { filtered <- e })
+
+			orch.handleASRFinal(NewASRFinalEvent(tt.asr))
+			if tt.wantInputs == nil {
+				if got := voiceAgent.getTurns(); len(got) != 0 {
+					t.Fatalf("agent called %d times, want 0", len(got))
+				}
+			} else {
+				waitForTurns(t, voiceAgent, 1)
+				if got := voiceAgent.getInputs(); !reflect.DeepEqual(got, tt.wantInputs) {
+					t.Fatalf("agent inputs = %v, want %v", got, tt.wantInputs)
+				}
+			}
+			if tt.output != ContentActionNone {
+				select {
+				case e := <-filtered:
+					if e.Direction != AudioOutput || e.Action != tt.output || e.Text != "你真是个**。" {
+						t.Fatalf("filtered event = %+v", e)
+					}
+				case <-time.After(time.Second):
+					t.Fatal("expected ContentFilteredEvent")
+				}
+			}
+			orch.wg.Wait()
+			if got := outPipe.getPlayed(); !reflect.DeepEqual(got, tt.wantPlayed) {
+				t.Fatalf("played = %v, want %v", got, tt.wantPlayed)
+			}
+			if got := prompts.getPlayed(); !reflect.DeepEqual(got, tt.wantPrompts) {
+				t.Fatalf("prompts = %v, want %v", got, tt.wantPrompts)
+			}
+		})
+	}
+}
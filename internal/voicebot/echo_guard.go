@@ -0,0 +1,132 @@
+package voicebot
+
+import (
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// EchoGuardPolicy 自我识别拦截：ASR 偶尔会把扬声器播放的回复识别成用户说话，进而触发新一轮回复形成循环。
+// ASR final 与播报中或刚播完的 TTS 文本模糊匹配时视为回声，丢弃而不交给 Agent
+type EchoGuardPolicy struct {
+	// Window 回复播完后仍参与比对的时长（覆盖播放延迟与识别延迟），0 表示关闭
+	Window time.Duration
+
+	// MinSimilarity 识别文本与播报文本中最相近片段的相似度（1 - 编辑距离 / 识别文本长度）不低于该值时拦截
+	MinSimilarity float64
+
+	// MinChars 去掉标点和空白后少于该字数的识别结果不比对，避免“好的”“嗯”这类简短回答被误拦
+	MinChars int
+}
+
+// DefaultEchoGuardPolicy 默认策略：播完 3 秒内、至少 4 个字且相似度不低于 0.8 时拦截
+func DefaultEchoGuardPolicy() EchoGuardPolicy {
+	return EchoGuardPolicy{
+		Window:        3 * time.Second,
+		MinSimilarity: 0.8,
+		MinChars:      4,
+	}
+}
+
+// maxSpokenHistory 参与比对的最近播报句数
+const maxSpokenHistory = 20
+
+// spokenHistory 最近播报的 TTS 文本（归一化后）
+type spokenHistory struct {
+	mu         sync.Mutex
+	texts      []string
+	lastActive time.Time // 最近一次送入或播完 TTS 的时间
+}
+
+// add 记录送入 TTS 的文本；送入的是规范化后的文本时，原句与规范化结果都参与比对
+func (h *spokenHistory) add(now time.Time, texts ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, text := range texts {
+		if normalized := normalizeUtterance(text); normalized != "" {
+			h.texts = append(h.texts, normalized)
+		}
+	}
+	if len(h.texts) > maxSpokenHistory {
+		h.texts = append(h.texts[:0], h.texts[len(h.texts)-maxSpokenHistory:]...)
+	}
+	h.lastActive = now
+}
+
+// touch 一句播放完成
+func (h *spokenHistory) touch(now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastActive = now
+}
+
+// match 返回 text 与窗口内播报文本的最高相似度；speaking 为 true 时不论窗口都参与比对
+func (h *spokenHistory) match(text string, now time.Time, speaking bool, policy EchoGuardPolicy) (float64, bool) {
+	if policy.Window <= 0 {
+		return 0, false
+	}
+	heard := []rune(normalizeUtterance(text))
+	if len(heard) == 0 || len(heard) < policy.MinChars {
+		return 0, false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !speaking && now.Sub(h.lastActive) > policy.Window {
+		// 窗口之外的播报不会再产生回声
+		h.texts = nil
+		return 0, false
+	}
+	best := 0.0
+	for i, text := range h.texts {
+		best = max(best, substringSimilarity(heard, []rune(text)))
+		if i > 0 {
+			// 识别结果可能跨越相邻两句
+			best = max(best, substringSimilarity(heard, []rune(h.texts[i-1]+text)))
+		}
+	}
+	return best, best >= policy.MinSimilarity
+}
+
+// substringSimilarity 计算 pattern 与 text 中最相近的子串的相似度：1 - 编辑距离 / len(pattern)，
+// 子串可以从 text 的任意位置开始和结束（近似子串匹配）
+func substringSimilarity(pattern, text []rune) float64 {
+	if len(pattern) == 0 {
+		return 0
+	}
+	// prev[j]：pattern 前 i 个字符与以 text[j-1] 结尾的某个子串的最小编辑距离
+	prev := make([]int, len(text)+1)
+	curr := make([]int, len(text)+1)
+	for i := 1; i <= len(pattern); i++ {
+		curr[0] = i
+		for j := 1; j <= len(text); j++ {
+			cost := 1
+			if pattern[i-1] == text[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j-1]+cost, prev[j]+1, curr[j-1]+1)
+		}
+		prev, curr = curr, prev
+	}
+	dist := len(pattern)
+	for _, d := range prev {
+		dist = min(dist, d)
+	}
+	return 1 - float64(dist)/float64(len(pattern))
+}
+
+// isEcho 判断 ASR final 是否是识别到了自己的播报，是则发布 EchoSuppressedEvent 并返回 true
+func (o *orchestratorImpl) isEcho(asrEvent *ASRFinalEvent) bool {
+	o.mu.Lock()
+	speaking := o.ttsPendingCount > 0
+	o.mu.Unlock()
+
+	similarity, ok := o.spoken.match(asrEvent.Text, time.Now(), speaking, o.config.EchoGuard)
+	if !ok {
+		return false
+	}
+	logging.Infof("Orchestrator: ASR final matches recent TTS output (similarity=%.2f), dropping: %q", similarity, asrEvent.Text)
+	o.eventBus.Publish(NewEchoSuppressedEvent(asrEvent.Text, similarity))
+	return true
+}
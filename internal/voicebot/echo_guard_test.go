@@ -0,0 +1,113 @@
+package voicebot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+)
+
+func TestSubstringSimilarity(t *testing.T) {
+	tests := []struct {
+		pattern string
+		text    string
+		want    float64
+	}{
+		{"今天天气", "今天天气晴朗", 1},
+		{"天气晴朗", "今天天气晴朗适合出门", 1},
+		{"天汽晴朗", "今天天气晴朗", 0.75}, // 一个字识别错
+		{"天气很晴朗", "今天天气晴朗", 0.8}, // 多识别出一个字
+		{"帮我定闹钟", "今天天气晴朗", 0},
+		{"今天天气晴朗适合", "天气晴朗", 0.5}, // 识别文本比播报长
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got := substringSimilarity([]rune(tt.pattern), []rune(tt.text))
+			if d := got - tt.want; d > 1e-9 || d < -1e-9 {
+				t.Errorf("substringSimilarity(%q, %q) = %v, want %v", tt.pattern, tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpokenHistoryMatch(t *testing.T) {
+	policy := DefaultEchoGuardPolicy()
+	start := time.Now()
+	h := &spokenHistory{}
+	h.add(start, "今天北京晴，最高气温25度。", "今天北京晴，最高气温二十五度。")
+	h.add(start, "适合出门散步。")
+
+	tests := []struct {
+		name     string
+		text     string
+		at       time.Duration
+		speaking bool
+		want     bool
+	}{
+		{"exact echo", "今天北京晴，最高气温二十五度", time.Second, false, true},
+		{"partial echo with error", "最高气问二十五度", time.Second, false, true},
+		{"spans two sentences", "二十五度适合出门", time.Second, false, true},
+		{"user speech", "帮我定个明天的闹钟", time.Second, false, false},
+		{"too short", "晴。", time.Second, false, false},
+		{"still speaking", "适合出门散步", 10 * time.Second, true, true},
+		{"after window", "适合出门散步", 10 * time.Second, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			similarity, got := h.match(tt.text, start.Add(tt.at), tt.speaking, policy)
+			if got != tt.want {
+				t.Errorf("match(%q) = %v (similarity %.2f), want %v", tt.text, got, similarity, tt.want)
+			}
+		})
+	}
+
+	// 窗口过期后历史被清空，之后的播报重新计时
+	if _, got := h.match("今天北京晴最高气温", start.Add(time.Second), false, policy); got {
+		t.Error("expired history should be cleared")
+	}
+	if _, got := h.match("今天北京晴最高气温", start, false, EchoGuardPolicy{}); got {
+		t.Error("disabled policy should never match")
+	}
+}
+
+func TestEchoGuardDropsSelfTranscription(t *testing.T) {
+	voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{&agent.FinishedEvent{}}}
+	outPipe := newMockOutPipe()
+	orch := NewOrchestratorWithConfig(voiceAgent, outPipe, nil, nil, DefaultOrchestratorConfig()).(*orchestratorImpl)
+	suppressed := make(chan *EchoSuppressedEvent, 2)
+	SubscribeTyped(orch, EventTypeEchoSuppressed, func(e *EchoSuppressedEvent) { suppressed <- e })
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	orch.transitionTo(StateProcessing)
+	if err := orch.enqueueTTS("好的，已经为您打开客厅的灯。"); err != nil {
+		t.Fatalf("enqueueTTS() error = %v", err)
+	}
+
+	// 播报中识别到自己的声音：丢弃，不交给 Agent
+	orch.handleASRFinal(NewASRFinalEvent("已经为您打开客厅的灯"))
+	select {
+	case e := <-suppressed:
+		if e.Text != "已经为您打开客厅的灯" || e.Similarity < 0.99 {
+			t.Fatalf("EchoSuppressedEvent = %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected EchoSuppressedEvent")
+	}
+	if got := len(voiceAgent.getTurns()); got != 0 {
+		t.Fatalf("agent turns = %d, want 0", got)
+	}
+
+	// 注入的文本不比对
+	injected := NewASRFinalEvent("已经为您打开客厅的灯")
+	injected.Injected = true
+	orch.handleASRFinal(injected)
+	waitForTurns(t, voiceAgent, 1)
+
+	// 用户说的其他内容照常处理
+	orch.handleASRFinal(NewASRFinalEvent("再把卧室的空调关掉"))
+	waitForTurns(t, voiceAgent, 2)
+}
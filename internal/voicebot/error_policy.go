@@ -0,0 +1,152 @@
+package voicebot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+// ErrorSource 出错的组件
+type ErrorSource string
+
+const (
+	ErrorSourceASR ErrorSource = "asr"
+	ErrorSourceTTS ErrorSource = "tts"
+	ErrorSourceLLM ErrorSource = "llm"
+)
+
+// ErrorKind 错误分类
+type ErrorKind int
+
+const (
+	// ErrorTransient 瞬时错误（超时、限流、5xx、网络中断），稍后重试可能成功
+	ErrorTransient ErrorKind = iota
+	// ErrorPermanent 重试无意义的错误（鉴权失败、参数错误等）
+	ErrorPermanent
+)
+
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorTransient:
+		return "transient"
+	case ErrorPermanent:
+		return "permanent"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrorPolicy 组件失败时的处理策略：分类错误、向用户致歉、安排重试
+type ErrorPolicy struct {
+	// ApologyPrompt 致歉提示音名称（如预合成的“抱歉，我这边网络不太好”），未加载时不播放
+	ApologyPrompt string
+
+	// ApologyInterval 两次致歉的最短间隔，避免服务持续异常时反复播放
+	ApologyInterval time.Duration
+
+	// MaxRetries LLM 瞬时错误且本轮尚未产生回复时，重新处理本轮的最大次数，0 表示不重试
+	MaxRetries int
+
+	// RetryDelay 每次重试前的等待时长，第 n 次重试等待 n 倍
+	RetryDelay time.Duration
+
+	// Classify 错误分类函数，为 nil 时使用 ClassifyError
+	Classify func(err error) ErrorKind
+}
+
+// DefaultErrorPolicy 默认策略：LLM 瞬时错误重试 1 次，10 秒内最多致歉一次
+func DefaultErrorPolicy() ErrorPolicy {
+	return ErrorPolicy{
+		ApologyPrompt:   audio.PromptError,
+		ApologyInterval: 10 * time.Second,
+		MaxRetries:      1,
+		RetryDelay:      time.Second,
+	}
+}
+
+// ClassifyError 默认错误分类：超时、限流、5xx 和网络错误视为瞬时错误
+func ClassifyError(err error) ErrorKind {
+	if errors.Is(err, context.DeadlineExceeded) || agent.IsTransientError(err) {
+		return ErrorTransient
+	}
+	return ErrorPermanent
+}
+
+func (p ErrorPolicy) classify(err error) ErrorKind {
+	if p.Classify != nil {
+		return p.Classify(err)
+	}
+	return ClassifyError(err)
+}
+
+// shouldRetry 第 attempt 次尝试（从 0 开始）失败后是否重试
+func (p ErrorPolicy) shouldRetry(kind ErrorKind, attempt int) bool {
+	return kind == ErrorTransient && attempt < p.MaxRetries
+}
+
+// retryDelay 第 attempt 次尝试失败后的等待时长
+func (p ErrorPolicy) retryDelay(attempt int) time.Duration {
+	return p.RetryDelay * time.Duration(attempt+1)
+}
+
+// errorHandler 按 ErrorPolicy 限制致歉频率并管理待执行的重试
+type errorHandler struct {
+	policy ErrorPolicy
+
+	mu          sync.Mutex
+	lastApology time.Time
+	retry       *time.Timer
+	retryGen    uint64
+}
+
+func newErrorHandler(policy ErrorPolicy) *errorHandler {
+	return &errorHandler{policy: policy}
+}
+
+// allowApology 距上次致歉超过 ApologyInterval 时返回 true 并记录本次时间
+func (h *errorHandler) allowApology(now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.lastApology.IsZero() && now.Sub(h.lastApology) < h.policy.ApologyInterval {
+		return false
+	}
+	h.lastApology = now
+	return true
+}
+
+// scheduleRetry delay 后执行 fn，替换尚未执行的重试
+func (h *errorHandler) scheduleRetry(delay time.Duration, fn func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.retry != nil {
+		h.retry.Stop()
+	}
+	h.retryGen++
+	gen := h.retryGen
+	h.retry = time.AfterFunc(delay, func() {
+		h.mu.Lock()
+		current := gen == h.retryGen
+		if current {
+			h.retry = nil
+		}
+		h.mu.Unlock()
+		if current {
+			fn()
+		}
+	})
+}
+
+// cancelRetry 取消尚未执行的重试（用户开始新一轮、打断或停止时）
+func (h *errorHandler) cancelRetry() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.retryGen++
+	if h.retry != nil {
+		h.retry.Stop()
+		h.retry = nil
+	}
+}
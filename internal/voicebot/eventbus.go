@@ -1,45 +1,199 @@
 package voicebot
 
 import (
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
+
+	"github.com/liuscraft/orion-x/internal/logging"
 )
 
+// EventBusConfig 事件总线配置，作为每个订阅者的默认值
+type EventBusConfig struct {
+	// BufferSize 每个订阅者的事件缓冲，写满后新事件被丢弃并计入 Dropped
+	BufferSize int
+	// Workers 每个订阅者的处理协程数，1 表示按发布顺序串行处理
+	Workers int
+}
+
+// DefaultEventBusConfig 默认配置：每个订阅者缓冲 64 个事件，单协程按顺序处理
+func DefaultEventBusConfig() EventBusConfig {
+	return EventBusConfig{
+		BufferSize: 64,
+		Workers:    1,
+	}
+}
+
+// SubscribeOptions 单个订阅者的缓冲与并发，为 0 的字段使用 EventBusConfig 中的默认值
+type SubscribeOptions struct {
+	BufferSize int
+	Workers    int
+}
+
+// EventBusStats 事件总线累计统计
+type EventBusStats struct {
+	Published uint64 // Publish 调用次数
+	Delivered uint64 // 处理器执行完成的次数（含 panic）
+	Dropped   uint64 // 订阅者缓冲已满而丢弃的次数
+	Panics    uint64 // 处理器 panic 的次数
+}
+
+// subscriber 一个订阅者：独立的缓冲 channel 与处理协程，慢订阅者不影响发布方和其他订阅者
+type subscriber struct {
+	eventType EventType
+	handler   EventHandler
+	ch        chan Event
+	dropped   atomic.Uint64
+}
+
 // eventBus 事件总线实现
 type eventBus struct {
-	subscribers map[EventType][]EventHandler
+	config      EventBusConfig
+	subscribers map[EventType][]*subscriber
+	closed      bool
 	mu          sync.RWMutex
+	wg          sync.WaitGroup
+
+	published atomic.Uint64
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	panics    atomic.Uint64
 }
 
 func NewEventBus() EventBus {
+	return NewEventBusWithConfig(DefaultEventBusConfig())
+}
+
+// NewEventBusWithConfig 使用指定配置创建事件总线，未设置的字段取默认值
+func NewEventBusWithConfig(config EventBusConfig) EventBus {
+	defaults := DefaultEventBusConfig()
+	if config.BufferSize <= 0 {
+		config.BufferSize = defaults.BufferSize
+	}
+	if config.Workers <= 0 {
+		config.Workers = defaults.Workers
+	}
 	return &eventBus{
-		subscribers: make(map[EventType][]EventHandler),
+		config:      config,
+		subscribers: make(map[EventType][]*subscriber),
 	}
 }
 
-// Publish 发布事件
+// Publish 发布事件：只把事件放入各订阅者的缓冲，不等待处理，可在音频回调中调用
 func (eb *eventBus) Publish(event Event) {
 	eb.mu.RLock()
-	handlers, ok := eb.subscribers[event.Type()]
-	eb.mu.RUnlock()
+	defer eb.mu.RUnlock()
+	if eb.closed {
+		return
+	}
+	eb.published.Add(1)
 
-	if ok {
-		for _, handler := range handlers {
-			go handler(event)
+	for _, sub := range eb.subscribers[event.Type()] {
+		select {
+		case sub.ch <- event:
+		default:
+			eb.dropped.Add(1)
+			// 首次及此后每 100 次丢弃记录一次，避免刷屏
+			if n := sub.dropped.Add(1); n == 1 || n%100 == 0 {
+				logging.Warnf("EventBus: subscriber buffer full for event type %d, dropped %d event(s)", sub.eventType, n)
+			}
 		}
 	}
 }
 
-// Subscribe 订阅事件
+// Subscribe 订阅事件，使用默认的缓冲与并发
 func (eb *eventBus) Subscribe(eventType EventType, handler EventHandler) {
+	eb.SubscribeWithOptions(eventType, handler, SubscribeOptions{})
+}
+
+// SubscribeWithOptions 订阅事件，Workers>1 时同一订阅者的事件并发处理、不再保证顺序
+func (eb *eventBus) SubscribeWithOptions(eventType EventType, handler EventHandler, opts SubscribeOptions) {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = eb.config.BufferSize
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = eb.config.Workers
+	}
+	sub := &subscriber{
+		eventType: eventType,
+		handler:   handler,
+		ch:        make(chan Event, opts.BufferSize),
+	}
+
 	eb.mu.Lock()
 	defer eb.mu.Unlock()
+	if eb.closed {
+		return
+	}
+	eb.subscribers[eventType] = append(eb.subscribers[eventType], sub)
+	for i := 0; i < opts.Workers; i++ {
+		eb.wg.Add(1)
+		go eb.run(sub)
+	}
+}
+
+// run 订阅者的处理协程
+func (eb *eventBus) run(sub *subscriber) {
+	defer eb.wg.Done()
+	for event := range sub.ch {
+		eb.dispatch(sub, event)
+	}
+}
+
+// dispatch 调用处理器，panic 只影响当前事件
+func (eb *eventBus) dispatch(sub *subscriber, event Event) {
+	defer eb.delivered.Add(1)
+	defer func() {
+		if r := recover(); r != nil {
+			eb.panics.Add(1)
+			logging.Errorf("EventBus: handler for event type %d panicked: %v\n%s", sub.eventType, r, debug.Stack())
+		}
+	}()
+	sub.handler(event)
+}
 
-	eb.subscribers[eventType] = append(eb.subscribers[eventType], handler)
+// Stats 返回累计统计
+func (eb *eventBus) Stats() EventBusStats {
+	return EventBusStats{
+		Published: eb.published.Load(),
+		Delivered: eb.delivered.Load(),
+		Dropped:   eb.dropped.Load(),
+		Panics:    eb.panics.Load(),
+	}
 }
 
-// Unsubscribe 取消订阅
-// 注意：由于函数不能直接比较，此方法需要基于ID或其他方式实现
-// 暂时不实现，使用闭包引用的方式管理订阅
-func (eb *eventBus) Unsubscribe(eventType EventType, handler EventHandler) {
-	// TODO: 需要使用订阅ID或其他机制来实现取消订阅
+// Close 停止接收新事件；已缓冲的事件仍会处理完，不等待处理协程退出
+func (eb *eventBus) Close() {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	if eb.closed {
+		return
+	}
+	eb.closed = true
+	for _, subs := range eb.subscribers {
+		for _, sub := range subs {
+			close(sub.ch)
+		}
+	}
+}
+
+// Wait 等待 Close 之后所有已缓冲的事件处理完毕
+func (eb *eventBus) Wait() {
+	eb.wg.Wait()
+}
+
+// Subscriber 可订阅事件的对象（EventBus、Orchestrator）
+type Subscriber interface {
+	Subscribe(eventType EventType, handler EventHandler)
+}
+
+// SubscribeTyped 以具体事件类型订阅，类型不符的事件被忽略
+//
+//	voicebot.SubscribeTyped(orch, voicebot.EventTypePartialTranscript, func(e *voicebot.PartialTranscriptEvent) { ... })
+func SubscribeTyped[T Event](s Subscriber, eventType EventType, handler func(T)) {
+	s.Subscribe(eventType, func(event Event) {
+		if typed, ok := event.(T); ok {
+			handler(typed)
+		}
+	})
 }
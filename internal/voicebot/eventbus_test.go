@@ -0,0 +1,122 @@
+package voicebot
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEventBusDeliversInOrder(t *testing.T) {
+	eb := NewEventBus()
+	defer eb.Close()
+
+	var mu sync.Mutex
+	var got []string
+	done := make(chan struct{})
+	eb.Subscribe(EventTypeASRFinal, func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, event.(*ASRFinalEvent).Text)
+		if len(got) == 3 {
+			close(done)
+		}
+	})
+	for _, text := range []string{"a", "b", "c"} {
+		eb.Publish(NewASRFinalEvent(text))
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("events not delivered")
+	}
+	if !reflect.DeepEqual(got, []string{"a", "b", "c"}) {
+		t.Fatalf("delivery order = %v", got)
+	}
+}
+
+func TestEventBusSlowSubscriberDropsWithoutBlocking(t *testing.T) {
+	eb := NewEventBusWithConfig(EventBusConfig{BufferSize: 2})
+	defer eb.Close()
+
+	release := make(chan struct{})
+	eb.Subscribe(EventTypeUserSpeakingDetected, func(Event) { <-release })
+	fast := make(chan struct{}, 10)
+	eb.Subscribe(EventTypeUserSpeakingDetected, func(Event) { fast <- struct{}{} })
+
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		eb.Publish(NewUserSpeakingDetectedEvent())
+		time.Sleep(time.Millisecond)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("Publish blocked by slow subscriber for %v", elapsed)
+	}
+	close(release)
+
+	for i := 0; i < 10; i++ {
+		select {
+		case <-fast:
+		case <-time.After(time.Second):
+			t.Fatalf("fast subscriber received %d of 10 events", i)
+		}
+	}
+	// 慢订阅者：1 个处理中 + 2 个缓冲，其余丢弃
+	if stats := eb.Stats(); stats.Published != 10 || stats.Dropped != 7 {
+		t.Fatalf("stats = %+v, want 10 published / 7 dropped", stats)
+	}
+}
+
+func TestEventBusRecoversHandlerPanic(t *testing.T) {
+	eb := NewEventBus()
+	defer eb.Close()
+
+	received := make(chan string, 2)
+	eb.Subscribe(EventTypeASRFinal, func(event Event) {
+		text := event.(*ASRFinalEvent).Text
+		if text == "boom" {
+			panic("handler failure")
+		}
+		received <- text
+	})
+	eb.Publish(NewASRFinalEvent("boom"))
+	eb.Publish(NewASRFinalEvent("ok"))
+
+	select {
+	case got := <-received:
+		if got != "ok" {
+			t.Fatalf("received %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscriber stopped after panic")
+	}
+	if stats := eb.Stats(); stats.Panics != 1 {
+		t.Fatalf("panics = %d, want 1", stats.Panics)
+	}
+}
+
+func TestSubscribeTypedAndClose(t *testing.T) {
+	eb := NewEventBus()
+	received := make(chan *ASRFinalEvent, 2)
+	SubscribeTyped(eb, EventTypeASRFinal, func(e *ASRFinalEvent) { received <- e })
+
+	eb.Publish(NewASRFinalEvent("hello"))
+	select {
+	case e := <-received:
+		if e.Text != "hello" {
+			t.Fatalf("text = %q", e.Text)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("typed handler not called")
+	}
+
+	eb.Close()
+	eb.Publish(NewASRFinalEvent("after close"))
+	eb.(*eventBus).Wait()
+	select {
+	case e := <-received:
+		t.Fatalf("unexpected event after close: %q", e.Text)
+	default:
+	}
+}
@@ -2,7 +2,13 @@ package voicebot
 
 import (
 	"io"
+	"maps"
 	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/speaker"
+	"github.com/liuscraft/orion-x/internal/tools"
 )
 
 // Event 事件实现
@@ -36,7 +42,11 @@ func NewUserSpeakingDetectedEvent() *UserSpeakingDetectedEvent {
 // ASRFinalEvent ASR识别完成事件
 type ASRFinalEvent struct {
 	BaseEvent
-	Text string
+	Text       string
+	Speaker    *speaker.Match // 声纹识别结果，未开启声纹识别时为 nil
+	Attempt    int            // 按 ErrorPolicy 重新处理本轮的次数，用户新说的话为 0
+	Confidence *float64       // ASR 置信度（0~1），ASR 未返回时为 nil；合并多句时取最低值
+	Injected   bool           // 通过 SubmitText 注入的文本轮次，不是语音识别结果
 }
 
 func NewASRFinalEvent(text string) *ASRFinalEvent {
@@ -49,6 +59,33 @@ func NewASRFinalEvent(text string) *ASRFinalEvent {
 	}
 }
 
+// NewASRFinalEventWithSpeaker 创建带说话人识别结果的 ASR 完成事件
+func NewASRFinalEventWithSpeaker(text string, match speaker.Match) *ASRFinalEvent {
+	event := NewASRFinalEvent(text)
+	event.Speaker = &match
+	return event
+}
+
+// PartialTranscriptEvent ASR 中间识别结果事件，供 UI 显示实时字幕
+// 同一句话的中间结果共享 UtteranceStart，Text 为该句到目前为止的完整识别文本（不是增量）；
+// 同一订阅者按发布顺序收到（订阅时 Workers 为 1），订阅者缓冲写满时事件会被丢弃
+type PartialTranscriptEvent struct {
+	BaseEvent
+	Text           string
+	UtteranceStart time.Time // 本句第一个中间结果的时间
+}
+
+func NewPartialTranscriptEvent(text string, utteranceStart time.Time) *PartialTranscriptEvent {
+	return &PartialTranscriptEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypePartialTranscript,
+			timestamp: time.Now(),
+		},
+		Text:           text,
+		UtteranceStart: utteranceStart,
+	}
+}
+
 // ToolCallRequestedEvent 工具调用请求事件
 type ToolCallRequestedEvent struct {
 	BaseEvent
@@ -130,3 +167,406 @@ func NewStateChangedEvent(oldState, newState State) *StateChangedEvent {
 		NewState: newState,
 	}
 }
+
+// RecognizerStatusEvent 识别服务可用性变化事件
+type RecognizerStatusEvent struct {
+	BaseEvent
+	Available bool
+	Err       error // 不可用的原因，恢复时为 nil
+}
+
+func NewRecognizerStatusEvent(available bool, err error) *RecognizerStatusEvent {
+	return &RecognizerStatusEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeRecognizerStatus,
+			timestamp: time.Now(),
+		},
+		Available: available,
+		Err:       err,
+	}
+}
+
+// StateTimeoutEvent 状态看门狗超时事件：Processing / Speaking 超过配置时长没有进展
+type StateTimeoutEvent struct {
+	BaseEvent
+	State State
+	Idle  time.Duration // 该状态下最后一次活动至今的时长
+}
+
+func NewStateTimeoutEvent(state State, idle time.Duration) *StateTimeoutEvent {
+	return &StateTimeoutEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeStateTimeout,
+			timestamp: time.Now(),
+		},
+		State: state,
+		Idle:  idle,
+	}
+}
+
+// TurnLatencyEvent 单轮端到端延迟分解事件（本轮首句开始播放时发布）
+type TurnLatencyEvent struct {
+	BaseEvent
+	Latency TurnLatency
+}
+
+func NewTurnLatencyEvent(latency TurnLatency) *TurnLatencyEvent {
+	return &TurnLatencyEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeTurnLatency,
+			timestamp: time.Now(),
+		},
+		Latency: latency,
+	}
+}
+
+// ToolResultEvent 工具执行结果事件
+type ToolResultEvent struct {
+	BaseEvent
+	Tool     string
+	Args     map[string]interface{}
+	Result   interface{} // 工具返回的结构化结果
+	Duration time.Duration
+	Error    error
+}
+
+func NewToolResultEvent(tool string, args map[string]interface{}, result interface{}, duration time.Duration, err error) *ToolResultEvent {
+	return &ToolResultEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeToolResult,
+			timestamp: time.Now(),
+		},
+		Tool:     tool,
+		Args:     args,
+		Result:   result,
+		Duration: duration,
+		Error:    err,
+	}
+}
+
+// CitationEvent 回复引用来源事件
+type CitationEvent struct {
+	BaseEvent
+	Tool      string
+	Citations []agent.Citation
+}
+
+func NewCitationEvent(tool string, citations []agent.Citation) *CitationEvent {
+	return &CitationEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeCitation,
+			timestamp: time.Now(),
+		},
+		Tool:      tool,
+		Citations: citations,
+	}
+}
+
+// ToolAudioFinishedEvent 工具返回的资源音频播放结束事件
+type ToolAudioFinishedEvent struct {
+	BaseEvent
+	Tool        string // 通过 OnToolAudioReady 播放时为空
+	Interrupted bool   // 被打断、抢占而未播完
+}
+
+func NewToolAudioFinishedEvent(tool string, interrupted bool) *ToolAudioFinishedEvent {
+	return &ToolAudioFinishedEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeToolAudioFinished,
+			timestamp: time.Now(),
+		},
+		Tool:        tool,
+		Interrupted: interrupted,
+	}
+}
+
+// ToolProgressEvent 长耗时工具的进度：调用超过 ToolProgress.Delay 未返回时定时发布，
+// 或由工具通过 tools.ReportProgress 上报
+type ToolProgressEvent struct {
+	BaseEvent
+	Tool    string
+	Message string // 工具上报的进度描述，计时提醒时为空
+	Elapsed time.Duration
+}
+
+func NewToolProgressEvent(tool, message string, elapsed time.Duration) *ToolProgressEvent {
+	return &ToolProgressEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeToolProgress,
+			timestamp: time.Now(),
+		},
+		Tool:    tool,
+		Message: message,
+		Elapsed: elapsed,
+	}
+}
+
+// ToolResultsEvent 一批工具调用的汇总结果，顺序与请求顺序一致
+type ToolResultsEvent struct {
+	BaseEvent
+	Results []tools.ToolCallResult
+}
+
+func NewToolResultsEvent(results []tools.ToolCallResult) *ToolResultsEvent {
+	return &ToolResultsEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeToolResults,
+			timestamp: time.Now(),
+		},
+		Results: results,
+	}
+}
+
+// ErrorEvent 组件失败事件：ASR / TTS / LLM 出错时发布，Retrying 表示已安排重试（或组件自动重连）
+type ErrorEvent struct {
+	BaseEvent
+	Source   ErrorSource
+	Kind     ErrorKind
+	Err      error
+	Retrying bool
+}
+
+func NewErrorEvent(source ErrorSource, kind ErrorKind, err error, retrying bool) *ErrorEvent {
+	return &ErrorEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeError,
+			timestamp: time.Now(),
+		},
+		Source:   source,
+		Kind:     kind,
+		Err:      err,
+		Retrying: retrying,
+	}
+}
+
+// AudioLevelEvent 音频电平事件，输入和输出各每 100ms 发布一次，供 UI 显示电平表
+type AudioLevelEvent struct {
+	BaseEvent
+	Direction AudioDirection
+	Level     audio.AudioLevel
+}
+
+func NewAudioLevelEvent(direction AudioDirection, level audio.AudioLevel) *AudioLevelEvent {
+	return &AudioLevelEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeAudioLevel,
+			timestamp: time.Now(),
+		},
+		Direction: direction,
+		Level:     level,
+	}
+}
+
+// AudioLevelAlertEvent 电平告警事件：削波、麦克风长时间静音及恢复，Duration 为已静音的时长
+type AudioLevelAlertEvent struct {
+	BaseEvent
+	Direction AudioDirection
+	Alert     LevelAlert
+	Level     audio.AudioLevel
+	Duration  time.Duration
+}
+
+func NewAudioLevelAlertEvent(direction AudioDirection, alert LevelAlert, level audio.AudioLevel, duration time.Duration) *AudioLevelAlertEvent {
+	return &AudioLevelAlertEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeAudioLevelAlert,
+			timestamp: time.Now(),
+		},
+		Direction: direction,
+		Alert:     alert,
+		Level:     level,
+		Duration:  duration,
+	}
+}
+
+// RepromptEvent 识别结果不可靠、已请用户再说一遍，Count 为连续重问的次数
+type RepromptEvent struct {
+	BaseEvent
+	Text       string
+	Confidence *float64
+	Reason     RepromptReason
+	Count      int
+}
+
+func NewRepromptEvent(text string, confidence *float64, reason RepromptReason, count int) *RepromptEvent {
+	return &RepromptEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeReprompt,
+			timestamp: time.Now(),
+		},
+		Text:       text,
+		Confidence: confidence,
+		Reason:     reason,
+		Count:      count,
+	}
+}
+
+// ContentFilteredEvent 用户语句（Direction 为 input）或回复（output）命中敏感内容，Text 为屏蔽后的文本
+type ContentFilteredEvent struct {
+	BaseEvent
+	Direction AudioDirection
+	Action    ContentAction
+	Text      string
+}
+
+func NewContentFilteredEvent(direction AudioDirection, action ContentAction, text string) *ContentFilteredEvent {
+	return &ContentFilteredEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeContentFiltered,
+			timestamp: time.Now(),
+		},
+		Direction: direction,
+		Action:    action,
+		Text:      text,
+	}
+}
+
+// ReplyTruncatedEvent 本轮回复达到 ReplyLimit，已播报 Sentences 句、Chars 字，其余内容未播报
+type ReplyTruncatedEvent struct {
+	BaseEvent
+	Sentences int
+	Chars     int
+}
+
+func NewReplyTruncatedEvent(sentences, chars int) *ReplyTruncatedEvent {
+	return &ReplyTruncatedEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeReplyTruncated,
+			timestamp: time.Now(),
+		},
+		Sentences: sentences,
+		Chars:     chars,
+	}
+}
+
+// ControlCommandEvent 用户语句被识别为控制命令并已由 Orchestrator 直接处理，Text 为原句
+type ControlCommandEvent struct {
+	BaseEvent
+	Command ControlCommand
+	Text    string
+}
+
+func NewControlCommandEvent(command ControlCommand, text string) *ControlCommandEvent {
+	return &ControlCommandEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeControlCommand,
+			timestamp: time.Now(),
+		},
+		Command: command,
+		Text:    text,
+	}
+}
+
+// MicMutedEvent 麦克风静音（隐私模式）开关状态变化
+type MicMutedEvent struct {
+	BaseEvent
+	Muted bool
+}
+
+func NewMicMutedEvent(muted bool) *MicMutedEvent {
+	return &MicMutedEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeMicMuted,
+			timestamp: time.Now(),
+		},
+		Muted: muted,
+	}
+}
+
+// ActionConfirmationEvent 需要口头确认的工具调用状态变化（请求确认、确认、取消、超时）
+type ActionConfirmationEvent struct {
+	BaseEvent
+	Tool   string
+	Args   map[string]interface{}
+	Prompt string // 播报的确认话术
+	Status ConfirmationStatus
+}
+
+func NewActionConfirmationEvent(tool string, args map[string]interface{}, prompt string, status ConfirmationStatus) *ActionConfirmationEvent {
+	return &ActionConfirmationEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeActionConfirmation,
+			timestamp: time.Now(),
+		},
+		Tool:   tool,
+		Args:   args,
+		Prompt: prompt,
+		Status: status,
+	}
+}
+
+// EchoSuppressedEvent ASR final 与最近播报的回复相似，判定为识别到了自己的声音而被丢弃
+type EchoSuppressedEvent struct {
+	BaseEvent
+	Text       string
+	Similarity float64
+}
+
+func NewEchoSuppressedEvent(text string, similarity float64) *EchoSuppressedEvent {
+	return &EchoSuppressedEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeEchoSuppressed,
+			timestamp: time.Now(),
+		},
+		Text:       text,
+		Similarity: similarity,
+	}
+}
+
+// FollowUpEvent Agent 追问的状态变化（提出追问、用户回答、等待超时）
+type FollowUpEvent struct {
+	BaseEvent
+	Question string
+	Status   FollowUpStatus
+}
+
+func NewFollowUpEvent(question string, status FollowUpStatus) *FollowUpEvent {
+	return &FollowUpEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeFollowUp,
+			timestamp: time.Now(),
+		},
+		Question: question,
+		Status:   status,
+	}
+}
+
+// SlotFillingEvent 工具调用参数追问的状态变化（追问、填入、取消、超时），Args 为当前已有的参数
+type SlotFillingEvent struct {
+	BaseEvent
+	Tool     string
+	Args     map[string]interface{}
+	Param    string // 正在询问的参数
+	Question string
+	Status   SlotFillingStatus
+}
+
+func NewSlotFillingEvent(tool string, args map[string]interface{}, param, question string, status SlotFillingStatus) *SlotFillingEvent {
+	return &SlotFillingEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeSlotFilling,
+			timestamp: time.Now(),
+		},
+		Tool:     tool,
+		Args:     maps.Clone(args),
+		Param:    param,
+		Question: question,
+		Status:   status,
+	}
+}
+
+// ActivityEvent 面向设备指示灯的语音活动变化（开始聆听、检测到说话、思考、开始 / 结束出声），见 ActivityIndicator
+type ActivityEvent struct {
+	BaseEvent
+	Activity Activity
+}
+
+func NewActivityEvent(activity Activity) *ActivityEvent {
+	return &ActivityEvent{
+		BaseEvent: BaseEvent{
+			eventType: EventTypeActivity,
+			timestamp: time.Now(),
+		},
+		Activity: activity,
+	}
+}
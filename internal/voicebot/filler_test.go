@@ -0,0 +1,60 @@
+package voicebot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+func TestOrchestratorFiller(t *testing.T) {
+	tests := []struct {
+		name       string
+		agentDelay time.Duration
+		wantFiller bool
+	}{
+		{name: "slow LLM plays filler", agentDelay: 150 * time.Millisecond, wantFiller: true},
+		{name: "fast LLM skips filler", agentDelay: 0, wantFiller: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultOrchestratorConfig()
+			cfg.FillerDelay = 50 * time.Millisecond
+
+			voiceAgent := &mockVoiceAgent{
+				delay: tt.agentDelay,
+				events: []agent.AgentEvent{
+					&agent.TextChunkEvent{Chunk: "好的。", Emotion: "default"},
+					&agent.FinishedEvent{},
+				},
+			}
+			outPipe := newMockOutPipe()
+			prompts := newMockPrompts(audio.PromptThinking)
+
+			orch := NewOrchestratorWithConfig(voiceAgent, outPipe, nil, nil, cfg)
+			orch.SetPrompts(prompts)
+			if err := orch.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			orch.OnASRFinal("今天天气怎么样")
+			time.Sleep(250 * time.Millisecond)
+			orch.Stop()
+
+			var want []string
+			if tt.wantFiller {
+				want = []string{audio.PromptThinking}
+			}
+			if got := prompts.getPlayed(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("played prompts = %v, want %v", got, want)
+			}
+			if got := outPipe.getPlayed(); !reflect.DeepEqual(got, []string{"好的。"}) {
+				t.Fatalf("played TTS = %v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package voicebot
+
+import (
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// FollowUpStatus Agent 追问的状态
+type FollowUpStatus string
+
+const (
+	FollowUpAsked    FollowUpStatus = "asked"    // 回复以追问结尾，播完后进入聆听窗口
+	FollowUpAnswered FollowUpStatus = "answered" // 用户开始了新一轮，追问已带给 Agent
+	FollowUpExpired  FollowUpStatus = "expired"  // 窗口内没有回答，回到空闲
+)
+
+// onFollowUp Agent 回复以追问结尾：记录追问，回复播完后进入 FollowUpWindow 时长的聆听窗口
+func (o *orchestratorImpl) onFollowUp(question string) {
+	if o.config.FollowUpWindow <= 0 {
+		return
+	}
+	o.mu.Lock()
+	o.followUp = question
+	o.mu.Unlock()
+	logging.Infof("Orchestrator: agent asked follow-up question: %s", question)
+	o.eventBus.Publish(NewFollowUpEvent(question, FollowUpAsked))
+}
+
+// openFollowUpWindow 回复播完时有待回答的追问（或参数追问）则进入 Listening 等待回答，返回 false 表示没有追问
+func (o *orchestratorImpl) openFollowUpWindow() bool {
+	o.mu.Lock()
+	pending := o.followUp != "" || o.pendingSlot != nil
+	o.mu.Unlock()
+	if !pending || !o.transitionTo(StateListening) {
+		return false
+	}
+	logging.Infof("Orchestrator: listening for follow-up answer (window: %v)", o.config.FollowUpWindow)
+	return true
+}
+
+// takeFollowUpLocked 取出并清除待回答的追问，调用方需持有 o.mu
+func (o *orchestratorImpl) takeFollowUpLocked() string {
+	question := o.followUp
+	o.followUp = ""
+	return question
+}
+
+// expireFollowUp 聆听窗口超时，放弃待回答的追问；没有追问（如打断后的 Listening）时返回 false
+func (o *orchestratorImpl) expireFollowUp() bool {
+	o.mu.Lock()
+	question := o.takeFollowUpLocked()
+	o.mu.Unlock()
+	if question == "" {
+		return false
+	}
+	logging.Infof("Orchestrator: no answer to follow-up question within %v", o.config.FollowUpWindow)
+	o.eventBus.Publish(NewFollowUpEvent(question, FollowUpExpired))
+	return true
+}
@@ -0,0 +1,113 @@
+package voicebot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+)
+
+const testFollowUpQuestion = "还需要查别的城市吗？"
+
+func newFollowUpAgent() *mockVoiceAgent {
+	return &mockVoiceAgent{events: []agent.AgentEvent{
+		&agent.TextChunkEvent{Chunk: "北京今天晴。" + testFollowUpQuestion},
+		&agent.FollowUpEvent{Question: testFollowUpQuestion},
+		&agent.FinishedEvent{},
+	}}
+}
+
+// finishReply 等待本轮 Agent 结束并播完所有句子
+func finishReply(t *testing.T, orch *orchestratorImpl, voiceAgent *mockVoiceAgent, turns int) {
+	t.Helper()
+	waitForTurns(t, voiceAgent, turns)
+	orch.wg.Wait()
+	for i := 0; i < 2; i++ {
+		orch.onTTSPlaybackFinished()
+	}
+}
+
+func TestFollowUpWindow(t *testing.T) {
+	voiceAgent := newFollowUpAgent()
+	cfg := DefaultOrchestratorConfig()
+	cfg.FollowUpWindow = 100 * time.Millisecond
+	orch := NewOrchestratorWithConfig(voiceAgent, newMockOutPipe(), nil, nil, cfg).(*orchestratorImpl)
+	events := make(chan *FollowUpEvent, 8)
+	SubscribeTyped(orch, EventTypeFollowUp, func(e *FollowUpEvent) { events <- e })
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	// 回复以追问结尾：播完后进入 Listening，下一轮带上追问
+	orch.handleASRFinal(NewASRFinalEvent("北京天气怎么样"))
+	finishReply(t, orch, voiceAgent, 1)
+	if got := orch.GetState(); got != StateListening {
+		t.Fatalf("state after reply = %s, want Listening", got)
+	}
+	orch.handleASRFinal(NewASRFinalEvent("上海呢"))
+	finishReply(t, orch, voiceAgent, 2)
+
+	// 窗口内没有回答：回到 Idle，下一轮不再带追问
+	waitForState(t, orch, StateIdle)
+	orch.handleASRFinal(NewASRFinalEvent("现在几点"))
+	waitForTurns(t, voiceAgent, 3)
+	if got := voiceAgent.getFollowUps(); !reflect.DeepEqual(got, []string{"", testFollowUpQuestion, ""}) {
+		t.Fatalf("follow-ups passed to agent = %q", got)
+	}
+
+	want := []FollowUpStatus{FollowUpAsked, FollowUpAnswered, FollowUpAsked, FollowUpExpired, FollowUpAsked}
+	for i, status := range want {
+		select {
+		case e := <-events:
+			if e.Status != status || e.Question != testFollowUpQuestion {
+				t.Fatalf("event %d = %+v, want status %s", i, e, status)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected follow-up event %d (%s)", i, status)
+		}
+	}
+}
+
+func TestFollowUpWindowDisabledOrInterrupted(t *testing.T) {
+	tests := []struct {
+		name      string
+		window    time.Duration
+		interrupt bool
+	}{
+		{"disabled", 0, false},
+		{"interrupted", time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			voiceAgent := newFollowUpAgent()
+			cfg := DefaultOrchestratorConfig()
+			cfg.FollowUpWindow = tt.window
+			orch := NewOrchestratorWithConfig(voiceAgent, newMockOutPipe(), nil, nil, cfg).(*orchestratorImpl)
+			if err := orch.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			defer orch.Stop()
+
+			orch.handleASRFinal(NewASRFinalEvent("北京天气怎么样"))
+			if tt.interrupt {
+				// 播报中被打断：追问随打断上下文带给下一轮，不再等待回答
+				waitForTurns(t, voiceAgent, 1)
+				orch.wg.Wait()
+				orch.handleUserSpeakingDetected(NewUserSpeakingDetectedEvent())
+			} else {
+				finishReply(t, orch, voiceAgent, 1)
+				if got := orch.GetState(); got != StateIdle {
+					t.Fatalf("state after reply = %s, want Idle", got)
+				}
+			}
+			orch.handleASRFinal(NewASRFinalEvent("上海呢"))
+			waitForTurns(t, voiceAgent, 2)
+			if got := voiceAgent.getFollowUps(); !reflect.DeepEqual(got, []string{"", ""}) {
+				t.Fatalf("follow-ups passed to agent = %q, want none", got)
+			}
+		})
+	}
+}
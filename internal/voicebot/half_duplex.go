@@ -0,0 +1,86 @@
+package voicebot
+
+import (
+	"context"
+	"sync"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// halfDuplex 半双工：Speaking 期间暂停向云端 ASR 推流（AudioInPipe 的本地 VAD 照常，用于打断），
+// 离开 Speaking 后恢复。暂停 / 恢复需要网络往返，在后台串行执行，只保证最终与最后一次请求一致
+type halfDuplex struct {
+	pauser audio.StreamPauser
+	wg     *sync.WaitGroup
+
+	mu      sync.Mutex
+	ctx     context.Context
+	want    bool // 最近一次请求的暂停状态
+	paused  bool // 已生效的暂停状态
+	running bool // 后台协程正在切换
+}
+
+func newHalfDuplex(pauser audio.StreamPauser, wg *sync.WaitGroup) *halfDuplex {
+	return &halfDuplex{pauser: pauser, wg: wg}
+}
+
+// start 设置切换使用的 context，Orchestrator 停止时取消
+func (h *halfDuplex) start(ctx context.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.ctx = ctx
+	h.want, h.paused = false, false
+}
+
+// set 请求暂停或恢复推流，立即返回
+func (h *halfDuplex) set(paused bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.want = paused
+	if h.running || h.ctx == nil || h.want == h.paused {
+		return
+	}
+	h.running = true
+	h.wg.Add(1)
+	go h.run(h.ctx)
+}
+
+func (h *halfDuplex) run(ctx context.Context) {
+	defer h.wg.Done()
+	for {
+		h.mu.Lock()
+		if h.want == h.paused || ctx.Err() != nil {
+			h.running = false
+			h.mu.Unlock()
+			return
+		}
+		target := h.want
+		h.mu.Unlock()
+
+		var err error
+		if target {
+			err = h.pauser.PauseStreaming(ctx)
+		} else {
+			err = h.pauser.ResumeStreaming(ctx)
+		}
+		if err != nil && ctx.Err() == nil {
+			// 暂停失败时推流已停止，恢复失败时 AudioInPipe 转入重连，都按目标状态记录
+			logging.Warnf("Orchestrator: half-duplex paused=%v: %v", target, err)
+		} else {
+			logging.Debugf("Orchestrator: half-duplex ASR streaming paused=%v", target)
+		}
+
+		h.mu.Lock()
+		h.paused = target
+		h.mu.Unlock()
+	}
+}
+
+// updateHalfDuplex 半双工模式下进入 Speaking 时暂停 ASR 推流，离开时恢复；
+// SleepPolicy.PauseASR 开启时休眠期间同样暂停
+func (o *orchestratorImpl) updateHalfDuplex(state State) {
+	if o.duplex != nil {
+		o.duplex.set(o.config.HalfDuplex && state == StateSpeaking || o.config.Sleep.PauseASR && state == StateSleeping)
+	}
+}
@@ -0,0 +1,128 @@
+package voicebot
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// pausingInPipe 记录暂停 / 恢复推流调用的输入管道，delay 模拟网络往返
+type pausingInPipe struct {
+	*TextInPipe
+	delay time.Duration
+	mu    sync.Mutex
+	calls []string
+}
+
+func (p *pausingInPipe) PauseStreaming(ctx context.Context) error {
+	time.Sleep(p.delay)
+	p.record("pause")
+	return nil
+}
+
+func (p *pausingInPipe) ResumeStreaming(ctx context.Context) error {
+	time.Sleep(p.delay)
+	p.record("resume")
+	return nil
+}
+
+func (p *pausingInPipe) record(call string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, call)
+}
+
+func (p *pausingInPipe) getCalls() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.calls...)
+}
+
+// waitCalls 等待调用序列达到 want 并保持稳定
+func waitCalls(t *testing.T, p *pausingInPipe, want []string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if reflect.DeepEqual(p.getCalls(), want) {
+			time.Sleep(30 * time.Millisecond)
+			if got := p.getCalls(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("calls = %v, want %v", got, want)
+			}
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("calls = %v, want %v", p.getCalls(), want)
+}
+
+func TestHalfDuplexPausesWhileSpeaking(t *testing.T) {
+	tests := []struct {
+		name       string
+		halfDuplex bool
+		want       []string
+	}{
+		{name: "enabled", halfDuplex: true, want: []string{"pause", "resume", "pause", "resume"}},
+		{name: "disabled", halfDuplex: false, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultOrchestratorConfig()
+			cfg.HalfDuplex = tt.halfDuplex
+			inPipe := &pausingInPipe{TextInPipe: NewTextInPipe()}
+			orch := NewOrchestratorWithConfig(&mockVoiceAgent{}, newMockOutPipe(), inPipe, nil, cfg).(*orchestratorImpl)
+			if err := orch.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			defer orch.Stop()
+
+			// 播完回到 Idle
+			orch.transitionTo(StateProcessing)
+			orch.transitionTo(StateSpeaking)
+			waitCalls(t, inPipe, tt.want[:min(1, len(tt.want))])
+			orch.transitionTo(StateIdle)
+			waitCalls(t, inPipe, tt.want[:min(2, len(tt.want))])
+
+			// 播报中说话打断，进入 Listening 后恢复识别
+			orch.transitionTo(StateProcessing)
+			orch.transitionTo(StateSpeaking)
+			waitCalls(t, inPipe, tt.want[:min(3, len(tt.want))])
+			orch.handleUserSpeakingDetected(NewUserSpeakingDetectedEvent())
+			if got := orch.GetState(); got != StateListening {
+				t.Fatalf("state after barge-in = %s, want Listening", got)
+			}
+			waitCalls(t, inPipe, tt.want)
+		})
+	}
+}
+
+func TestHalfDuplexCoalescesRapidChanges(t *testing.T) {
+	cfg := DefaultOrchestratorConfig()
+	cfg.HalfDuplex = true
+	inPipe := &pausingInPipe{TextInPipe: NewTextInPipe(), delay: 20 * time.Millisecond}
+	orch := NewOrchestratorWithConfig(&mockVoiceAgent{}, newMockOutPipe(), inPipe, nil, cfg).(*orchestratorImpl)
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	// 暂停进行中时连续切换：中间状态被合并，最终停在最后一次请求（暂停）
+	orch.transitionTo(StateProcessing)
+	orch.transitionTo(StateSpeaking)
+	orch.transitionTo(StateProcessing)
+	orch.transitionTo(StateSpeaking)
+	orch.transitionTo(StateProcessing)
+	orch.transitionTo(StateSpeaking)
+	time.Sleep(100 * time.Millisecond)
+
+	calls := inPipe.getCalls()
+	if len(calls) == 0 || calls[len(calls)-1] != "pause" || len(calls) > 3 {
+		t.Fatalf("calls = %v, want at most 3 ending with pause", calls)
+	}
+	for i := 1; i < len(calls); i++ {
+		if calls[i] == calls[i-1] {
+			t.Fatalf("calls = %v, want alternating", calls)
+		}
+	}
+}
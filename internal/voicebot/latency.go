@@ -0,0 +1,107 @@
+package voicebot
+
+import (
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+// TurnLatency 单轮端到端延迟分解
+// 各阶段缺少对应时间点时为 0
+type TurnLatency struct {
+	Turn                      uint64
+	SpeechEndToASRFinal       time.Duration // 用户停止说话（最后一个中间识别结果）→ ASR final
+	ASRFinalToFirstToken      time.Duration // ASR final → LLM 首个文本
+	FirstSentenceToFirstAudio time.Duration // 首句送入 TTS → 收到首个音频字节
+	FirstAudioToPlayback      time.Duration // 首个音频字节 → 开始播放（含抖动缓冲预缓冲）
+	EndToEnd                  time.Duration // 用户停止说话 → 开始播放
+}
+
+// latencyTracker 记录当前轮次的关键时间点，首句开始播放时生成延迟分解
+type latencyTracker struct {
+	mu              sync.Mutex
+	turn            uint64
+	lastSpeechAt    time.Time // 最近一次中间识别结果
+	speechEndAt     time.Time
+	asrFinalAt      time.Time
+	firstTokenAt    time.Time
+	firstSentenceAt time.Time
+	reported        bool
+}
+
+func newLatencyTracker() *latencyTracker {
+	return &latencyTracker{}
+}
+
+// Speech 记录用户仍在说话（收到中间识别结果）
+func (t *latencyTracker) Speech(at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastSpeechAt = at
+}
+
+// BeginTurn 开始新一轮，最近一次中间结果视为用户停止说话的时刻
+func (t *latencyTracker) BeginTurn(turn uint64, asrFinalAt time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	speechEndAt := t.lastSpeechAt
+	if speechEndAt.IsZero() || speechEndAt.After(asrFinalAt) {
+		speechEndAt = asrFinalAt
+	}
+	t.turn = turn
+	t.lastSpeechAt = time.Time{}
+	t.speechEndAt = speechEndAt
+	t.asrFinalAt = asrFinalAt
+	t.firstTokenAt = time.Time{}
+	t.firstSentenceAt = time.Time{}
+	t.reported = false
+}
+
+// FirstToken 记录本轮 LLM 首个文本到达
+func (t *latencyTracker) FirstToken(at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.turn != 0 && t.firstTokenAt.IsZero() {
+		t.firstTokenAt = at
+	}
+}
+
+// SentenceEnqueued 记录本轮首句送入 TTS
+func (t *latencyTracker) SentenceEnqueued(at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.turn != 0 && t.firstSentenceAt.IsZero() {
+		t.firstSentenceAt = at
+	}
+}
+
+// Playback 处理一句开始播放，本轮首句时返回延迟分解（每轮只返回一次）
+func (t *latencyTracker) Playback(timing audio.TTSTiming) (TurnLatency, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	// 只统计本轮送入的句子，忽略上一轮残留的播放
+	if t.reported || t.firstSentenceAt.IsZero() || timing.EnqueuedAt.Before(t.firstSentenceAt) {
+		return TurnLatency{}, false
+	}
+	t.reported = true
+
+	return TurnLatency{
+		Turn:                      t.turn,
+		SpeechEndToASRFinal:       since(t.speechEndAt, t.asrFinalAt),
+		ASRFinalToFirstToken:      since(t.asrFinalAt, t.firstTokenAt),
+		FirstSentenceToFirstAudio: since(t.firstSentenceAt, timing.FirstByteAt),
+		FirstAudioToPlayback:      since(timing.FirstByteAt, timing.PlaybackAt),
+		EndToEnd:                  since(t.speechEndAt, timing.PlaybackAt),
+	}, true
+}
+
+// since 两个时间点的间隔，任一缺失或顺序颠倒时为 0
+func since(from, to time.Time) time.Duration {
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
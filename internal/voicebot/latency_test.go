@@ -0,0 +1,55 @@
+package voicebot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	base := time.Now()
+	at := func(ms int) time.Time { return base.Add(time.Duration(ms) * time.Millisecond) }
+
+	tracker := newLatencyTracker()
+	tracker.Speech(at(0))
+	tracker.Speech(at(100))
+	tracker.BeginTurn(1, at(400))
+	tracker.FirstToken(at(900))
+	tracker.FirstToken(at(950))
+	tracker.SentenceEnqueued(at(1000))
+	tracker.SentenceEnqueued(at(1200))
+
+	// 上一轮残留的句子不计入本轮
+	if _, ok := tracker.Playback(audio.TTSTiming{EnqueuedAt: at(300), FirstByteAt: at(500), PlaybackAt: at(600)}); ok {
+		t.Fatal("Playback() reported a sentence enqueued before this turn")
+	}
+
+	got, ok := tracker.Playback(audio.TTSTiming{EnqueuedAt: at(1000), FirstByteAt: at(1300), PlaybackAt: at(1500)})
+	if !ok {
+		t.Fatal("Playback() did not report the first sentence")
+	}
+	want := TurnLatency{
+		Turn:                      1,
+		SpeechEndToASRFinal:       300 * time.Millisecond,
+		ASRFinalToFirstToken:      500 * time.Millisecond,
+		FirstSentenceToFirstAudio: 300 * time.Millisecond,
+		FirstAudioToPlayback:      200 * time.Millisecond,
+		EndToEnd:                  1400 * time.Millisecond,
+	}
+	if got != want {
+		t.Fatalf("Playback() = %+v, want %+v", got, want)
+	}
+
+	if _, ok := tracker.Playback(audio.TTSTiming{EnqueuedAt: at(1200), FirstByteAt: at(1600), PlaybackAt: at(1700)}); ok {
+		t.Fatal("Playback() reported twice in one turn")
+	}
+
+	// 没有中间结果时以 ASR final 作为说话结束时刻
+	tracker.BeginTurn(2, at(2000))
+	tracker.SentenceEnqueued(at(2100))
+	got, ok = tracker.Playback(audio.TTSTiming{EnqueuedAt: at(2100), FirstByteAt: at(2200), PlaybackAt: at(2300)})
+	if !ok || got.SpeechEndToASRFinal != 0 || got.ASRFinalToFirstToken != 0 || got.EndToEnd != 300*time.Millisecond {
+		t.Fatalf("Playback() = %+v, %v", got, ok)
+	}
+}
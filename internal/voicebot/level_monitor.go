@@ -0,0 +1,157 @@
+package voicebot
+
+import (
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// AudioDirection 音频方向
+type AudioDirection string
+
+const (
+	AudioInput  AudioDirection = "input"  // 麦克风
+	AudioOutput AudioDirection = "output" // 扬声器（Mixer 混音输出）
+)
+
+// LevelAlert 电平告警类型
+type LevelAlert int
+
+const (
+	// LevelAlertClipping 信号削波（麦克风增益过高或输出音量过大）
+	LevelAlertClipping LevelAlert = iota
+	// LevelAlertSilence 麦克风长时间接近静音（可能被静音、拔出或选错设备）
+	LevelAlertSilence
+	// LevelAlertRecovered 静音告警后麦克风重新有信号
+	LevelAlertRecovered
+)
+
+func (a LevelAlert) String() string {
+	switch a {
+	case LevelAlertClipping:
+		return "clipping"
+	case LevelAlertSilence:
+		return "silence"
+	case LevelAlertRecovered:
+		return "recovered"
+	default:
+		return "unknown"
+	}
+}
+
+// LevelMonitorConfig 电平监测配置
+type LevelMonitorConfig struct {
+	// SilenceThreshold 麦克风 RMS 低于该值视为静音（0.001 约 -60 dBFS）
+	SilenceThreshold float64
+
+	// SilenceDuration 麦克风持续静音超过该时长时告警，0 表示不检测
+	SilenceDuration time.Duration
+
+	// WarnInterval 两次削波告警的最短间隔，避免持续削波时刷屏
+	WarnInterval time.Duration
+}
+
+// DefaultLevelMonitorConfig 默认配置：静音 60 秒告警，削波告警 10 秒内最多一次
+func DefaultLevelMonitorConfig() LevelMonitorConfig {
+	return LevelMonitorConfig{
+		SilenceThreshold: 0.001,
+		SilenceDuration:  60 * time.Second,
+		WarnInterval:     10 * time.Second,
+	}
+}
+
+// LevelStats 单个方向的电平统计
+type LevelStats struct {
+	Current        audio.AudioLevel // 最近一个窗口的电平
+	MaxPeak        float64          // 会话内的最大峰值
+	ClippedSamples int              // 会话内累计削波样本数
+	SilentFor      time.Duration    // 麦克风已持续静音的时长（仅输入）
+}
+
+// levelMonitor 汇总输入 / 输出电平，发布电平事件，并在削波或长时间静音时告警
+type levelMonitor struct {
+	config  LevelMonitorConfig
+	publish func(event Event)
+
+	mu             sync.Mutex
+	stats          map[AudioDirection]*LevelStats
+	lastClipWarn   map[AudioDirection]time.Time
+	silentSince    time.Time
+	silenceAlerted bool
+}
+
+func newLevelMonitor(config LevelMonitorConfig, publish func(event Event)) *levelMonitor {
+	return &levelMonitor{
+		config:  config,
+		publish: publish,
+		stats: map[AudioDirection]*LevelStats{
+			AudioInput:  {},
+			AudioOutput: {},
+		},
+		lastClipWarn: make(map[AudioDirection]time.Time),
+	}
+}
+
+// observe 处理一个统计窗口的电平（在音频线程中调用，只做计算和非阻塞发布）
+func (m *levelMonitor) observe(direction AudioDirection, level audio.AudioLevel) {
+	var alerts []*AudioLevelAlertEvent
+
+	m.mu.Lock()
+	stats := m.stats[direction]
+	stats.Current = level
+	stats.ClippedSamples += level.Clipped
+	if level.Peak > stats.MaxPeak {
+		stats.MaxPeak = level.Peak
+	}
+
+	if direction == AudioInput && m.config.SilenceDuration > 0 {
+		if level.RMS < m.config.SilenceThreshold {
+			if m.silentSince.IsZero() {
+				m.silentSince = level.At
+			}
+			stats.SilentFor = level.At.Sub(m.silentSince)
+			if !m.silenceAlerted && stats.SilentFor >= m.config.SilenceDuration {
+				m.silenceAlerted = true
+				alerts = append(alerts, NewAudioLevelAlertEvent(direction, LevelAlertSilence, level, stats.SilentFor))
+			}
+		} else {
+			if m.silenceAlerted {
+				alerts = append(alerts, NewAudioLevelAlertEvent(direction, LevelAlertRecovered, level, stats.SilentFor))
+			}
+			m.silentSince = time.Time{}
+			m.silenceAlerted = false
+			stats.SilentFor = 0
+		}
+	}
+
+	if level.Clipped > 0 {
+		last := m.lastClipWarn[direction]
+		if last.IsZero() || level.At.Sub(last) >= m.config.WarnInterval {
+			m.lastClipWarn[direction] = level.At
+			alerts = append(alerts, NewAudioLevelAlertEvent(direction, LevelAlertClipping, level, 0))
+		}
+	}
+	m.mu.Unlock()
+
+	m.publish(NewAudioLevelEvent(direction, level))
+	for _, alert := range alerts {
+		switch alert.Alert {
+		case LevelAlertClipping:
+			logging.Warnf("LevelMonitor: %s clipping (%d samples, peak=%.3f), check gain / volume", direction, level.Clipped, level.Peak)
+		case LevelAlertSilence:
+			logging.Warnf("LevelMonitor: microphone near-silent for %v (rms=%.5f), check that it is unmuted and connected", alert.Duration, level.RMS)
+		case LevelAlertRecovered:
+			logging.Infof("LevelMonitor: microphone signal recovered after %v of silence", alert.Duration)
+		}
+		m.publish(alert)
+	}
+}
+
+// Stats 返回输入和输出的电平统计快照
+func (m *levelMonitor) Stats() (input, output LevelStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return *m.stats[AudioInput], *m.stats[AudioOutput]
+}
@@ -0,0 +1,71 @@
+package voicebot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+func TestLevelMonitorAlerts(t *testing.T) {
+	var alerts []*AudioLevelAlertEvent
+	levelEvents := 0
+	monitor := newLevelMonitor(LevelMonitorConfig{
+		SilenceThreshold: 0.01,
+		SilenceDuration:  time.Second,
+		WarnInterval:     5 * time.Second,
+	}, func(event Event) {
+		switch e := event.(type) {
+		case *AudioLevelAlertEvent:
+			alerts = append(alerts, e)
+		case *AudioLevelEvent:
+			levelEvents++
+		}
+	})
+
+	start := time.Unix(1000, 0)
+	at := func(ms int) time.Time { return start.Add(time.Duration(ms) * time.Millisecond) }
+
+	// 1.5 秒静音：1 秒时告警一次
+	for ms := 0; ms <= 1500; ms += 100 {
+		monitor.observe(AudioInput, audio.AudioLevel{RMS: 0.001, Peak: 0.002, At: at(ms)})
+	}
+	// 恢复后削波两次，第二次在告警间隔内被抑制
+	monitor.observe(AudioInput, audio.AudioLevel{RMS: 0.3, Peak: 1, Clipped: 12, At: at(1600)})
+	monitor.observe(AudioInput, audio.AudioLevel{RMS: 0.3, Peak: 1, Clipped: 3, At: at(1700)})
+	// 输出方向的静音不告警，削波单独计时
+	monitor.observe(AudioOutput, audio.AudioLevel{RMS: 0, At: at(1800)})
+	monitor.observe(AudioOutput, audio.AudioLevel{RMS: 0.5, Peak: 1, Clipped: 1, At: at(1900)})
+
+	want := []struct {
+		direction AudioDirection
+		alert     LevelAlert
+	}{
+		{AudioInput, LevelAlertSilence},
+		{AudioInput, LevelAlertRecovered},
+		{AudioInput, LevelAlertClipping},
+		{AudioOutput, LevelAlertClipping},
+	}
+	if len(alerts) != len(want) {
+		t.Fatalf("got %d alerts, want %d", len(alerts), len(want))
+	}
+	for i, w := range want {
+		if alerts[i].Direction != w.direction || alerts[i].Alert != w.alert {
+			t.Errorf("alert %d = %s %s, want %s %s", i, alerts[i].Direction, alerts[i].Alert, w.direction, w.alert)
+		}
+	}
+	if alerts[0].Duration != time.Second || alerts[1].Duration != 1500*time.Millisecond {
+		t.Errorf("silence durations = %v / %v", alerts[0].Duration, alerts[1].Duration)
+	}
+	if levelEvents != 20 {
+		t.Errorf("level events = %d, want 20", levelEvents)
+	}
+
+	input, output := monitor.Stats()
+	if input.ClippedSamples != 15 || input.MaxPeak != 1 || input.SilentFor != 0 {
+		t.Errorf("input stats = %+v", input)
+	}
+	if output.ClippedSamples != 1 || output.Current.RMS != 0.5 {
+		t.Errorf("output stats = %+v", output)
+	}
+}
@@ -0,0 +1,43 @@
+package voicebot
+
+import (
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// SetMicMuted 开关麦克风（隐私模式）：AudioInPipe 支持 audio.MicMuter 时不再发送音频，
+// 否则只忽略识别结果；正在播放的回复照常播完，静音时正在说的半句被丢弃
+func (o *orchestratorImpl) SetMicMuted(muted bool) bool {
+	o.mu.Lock()
+	if o.micMuted == muted {
+		o.mu.Unlock()
+		return false
+	}
+	o.micMuted = muted
+	o.mu.Unlock()
+
+	if muter, ok := o.audioInPipe.(audio.MicMuter); ok {
+		muter.SetMicMuted(muted)
+	} else if o.audioInPipe != nil && muted {
+		logging.Warnf("Orchestrator: AudioInPipe cannot mute the microphone, audio is still sent to ASR but results are ignored")
+	}
+	if muted && o.stateMachine.GetCurrentState() == StateListening {
+		o.transitionTo(StateIdle)
+	}
+
+	logging.Infof("Orchestrator: microphone muted=%v", muted)
+	if muted {
+		o.playPrompt(audio.PromptMicOff)
+	} else {
+		o.playPrompt(audio.PromptMicOn)
+	}
+	o.eventBus.Publish(NewMicMutedEvent(muted))
+	return true
+}
+
+// MicMuted 麦克风是否已静音
+func (o *orchestratorImpl) MicMuted() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.micMuted
+}
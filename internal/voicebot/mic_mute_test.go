@@ -0,0 +1,92 @@
+package voicebot
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+// mutingInPipe 记录麦克风静音调用的输入管道
+type mutingInPipe struct {
+	*TextInPipe
+	mu    sync.Mutex
+	calls []bool
+}
+
+func (p *mutingInPipe) SetMicMuted(muted bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.calls = append(p.calls, muted)
+}
+
+func (p *mutingInPipe) getCalls() []bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]bool(nil), p.calls...)
+}
+
+func TestSetMicMuted(t *testing.T) {
+	voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{
+		&agent.TextChunkEvent{Chunk: "好的。"},
+		&agent.FinishedEvent{},
+	}}
+	inPipe := &mutingInPipe{TextInPipe: NewTextInPipe()}
+	prompts := newMockPrompts(audio.PromptMicOff, audio.PromptMicOn)
+	orch := NewOrchestratorWithConfig(voiceAgent, newMockOutPipe(), inPipe, nil, DefaultOrchestratorConfig()).(*orchestratorImpl)
+	orch.SetPrompts(prompts)
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+	events := make(chan *MicMutedEvent, 4)
+	SubscribeTyped(orch, EventTypeMicMuted, func(e *MicMutedEvent) { events <- e })
+
+	// 语音命令进入隐私模式
+	orch.handleASRFinal(NewASRFinalEvent("关闭麦克风"))
+	if !orch.MicMuted() || orch.GetState() != StateMuted || !orch.Stats().MicMuted {
+		t.Fatalf("muted=%v state=%s stats=%v, want muted", orch.MicMuted(), orch.GetState(), orch.Stats().MicMuted)
+	}
+	if orch.SetMicMuted(true) {
+		t.Fatal("SetMicMuted(true) should report no change when already muted")
+	}
+
+	// 静音期间的识别结果和说话检测都被忽略
+	orch.handleASRFinal(NewASRFinalEvent("今天天气怎么样"))
+	orch.transitionTo(StateProcessing)
+	orch.handleUserSpeakingDetected(NewUserSpeakingDetectedEvent())
+	if got := orch.GetState(); got != StateProcessing {
+		t.Fatalf("state = %s, want Processing (no barge-in while muted)", got)
+	}
+	orch.transitionTo(StateIdle)
+
+	if !orch.SetMicMuted(false) {
+		t.Fatal("SetMicMuted(false) = false, want true")
+	}
+	if got := orch.GetState(); got != StateIdle {
+		t.Fatalf("state after unmute = %s, want Idle", got)
+	}
+	if got := len(voiceAgent.getTurns()); got != 0 {
+		t.Fatalf("agent turns = %d, want 0", got)
+	}
+	if got, want := inPipe.getCalls(), []bool{true, false}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("in pipe mute calls = %v, want %v", got, want)
+	}
+	if got, want := prompts.getPlayed(), []string{audio.PromptMicOff, audio.PromptMicOn}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("prompts = %v, want %v", got, want)
+	}
+	for _, want := range []bool{true, false} {
+		select {
+		case e := <-events:
+			if e.Muted != want {
+				t.Fatalf("MicMutedEvent.Muted = %v, want %v", e.Muted, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("expected MicMutedEvent")
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package voicebot
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// newTestOrchestrator 用 mockOutPipe 创建并启动编排器，测试结束时停止
+func newTestOrchestrator(t *testing.T, voiceAgent agent.VoiceAgent, cfg *OrchestratorConfig) (*orchestratorImpl, *mockOutPipe) {
+	t.Helper()
+	return newTestOrchestratorWithTools(t, voiceAgent, nil, cfg)
+}
+
+// newTestOrchestratorWithTools 同 newTestOrchestrator，工具由 executor 执行
+func newTestOrchestratorWithTools(t *testing.T, voiceAgent agent.VoiceAgent, executor tools.ToolExecutor, cfg *OrchestratorConfig) (*orchestratorImpl, *mockOutPipe) {
+	t.Helper()
+	outPipe := newMockOutPipe()
+	orch := NewOrchestratorWithConfig(voiceAgent, outPipe, nil, executor, cfg).(*orchestratorImpl)
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { orch.Stop() })
+	return orch, outPipe
+}
+
+// mockVoiceAgent 模拟 VoiceAgent，延迟 delay 后依次发送 events（事件之间间隔 gap）
+// 并记录每次调用时 ctx 中的用户语言和追问；errs 非空时前几次调用依次返回其中的错误
+type mockVoiceAgent struct {
+	delay  time.Duration
+	gap    time.Duration
+	events []agent.AgentEvent
+	errs   []error
+
+	mu        sync.Mutex
+	languages []string
+	followUps []string
+	turns     []uint64
+	inputs    []string
+}
+
+func (a *mockVoiceAgent) Process(ctx context.Context, text string) (<-chan agent.AgentEvent, error) {
+	language, _ := agent.LanguageFromContext(ctx)
+	followUp, _ := agent.FollowUpFromContext(ctx)
+	a.mu.Lock()
+	a.languages = append(a.languages, language)
+	a.followUps = append(a.followUps, followUp)
+	turn, _ := logging.TurnFromContext(ctx)
+	a.turns = append(a.turns, turn)
+	a.inputs = append(a.inputs, text)
+	if len(a.errs) > 0 {
+		err := a.errs[0]
+		a.errs = a.errs[1:]
+		a.mu.Unlock()
+		return nil, err
+	}
+	a.mu.Unlock()
+
+	ch := make(chan agent.AgentEvent, len(a.events))
+	go func() {
+		defer close(ch)
+		select {
+		case <-time.After(a.delay):
+		case <-ctx.Done():
+			return
+		}
+		for i, event := range a.events {
+			if i > 0 && a.gap > 0 {
+				select {
+				case <-time.After(a.gap):
+				case <-ctx.Done():
+					return
+				}
+			}
+			ch <- event
+		}
+	}()
+	return ch, nil
+}
+
+func (a *mockVoiceAgent) GetToolType(tool string) agent.ToolType { return agent.ToolTypeQuery }
+
+func (a *mockVoiceAgent) getInputs() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.inputs...)
+}
+
+func (a *mockVoiceAgent) getFollowUps() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.followUps...)
+}
+
+func (a *mockVoiceAgent) getTurns() []uint64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]uint64(nil), a.turns...)
+}
+
+func (a *mockVoiceAgent) getLanguages() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.languages...)
+}
+
+// mockOutPipe 模拟 AudioOutPipe，记录送入 TTS 的文本
+type mockOutPipe struct {
+	mu         sync.Mutex
+	played     []string
+	emotions   []string
+	languages  []string
+	interrupts int
+	onFinished audio.PlaybackFinishedCallback
+}
+
+func newMockOutPipe() *mockOutPipe {
+	return &mockOutPipe{}
+}
+
+func (p *mockOutPipe) Start(ctx context.Context) error { return nil }
+func (p *mockOutPipe) Stop() error                     { return nil }
+
+func (p *mockOutPipe) PlayTTS(text string, emotion string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.played = append(p.played, text)
+	p.emotions = append(p.emotions, emotion)
+	return nil
+}
+
+func (p *mockOutPipe) PlayTTSWithLanguage(text, emotion, language string) error {
+	p.PlayTTS(text, emotion)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.languages = append(p.languages, language)
+	return nil
+}
+
+func (p *mockOutPipe) PlayResource(audio io.Reader) error { return nil }
+
+func (p *mockOutPipe) Interrupt() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.interrupts++
+	return nil
+}
+
+func (p *mockOutPipe) SetMixer(mixer audio.AudioMixer)           {}
+func (p *mockOutPipe) SetReferenceSink(sink audio.ReferenceSink) {}
+func (p *mockOutPipe) Stats() audio.PipelineStats                { return audio.PipelineStats{} }
+func (p *mockOutPipe) SetOnPlaybackFinished(cb audio.PlaybackFinishedCallback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onFinished = cb
+}
+
+func (p *mockOutPipe) getEmotions() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.emotions...)
+}
+
+func (p *mockOutPipe) getLanguages() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.languages...)
+}
+
+func (p *mockOutPipe) getInterrupts() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.interrupts
+}
+
+func (p *mockOutPipe) getPlayed() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.played...)
+}
+
+// urgentOutPipe 支持 audio.PrioritySpeechPlayer 的 mockOutPipe，另行记录紧急播报的文本和音频
+type urgentOutPipe struct {
+	*mockOutPipe
+	urgent []string
+	clips  [][]byte
+}
+
+func newUrgentOutPipe() *urgentOutPipe {
+	return &urgentOutPipe{mockOutPipe: newMockOutPipe()}
+}
+
+func (p *urgentOutPipe) PlayTTSWithOptions(ctx context.Context, text string, opts audio.SpeechOptions) error {
+	if opts.Priority != audio.TTSPriorityUrgent {
+		return p.PlayTTS(text, opts.Emotion)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.urgent = append(p.urgent, text)
+	p.clips = append(p.clips, opts.Audio)
+	return nil
+}
+
+func (p *urgentOutPipe) getUrgent() ([]string, [][]byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.urgent...), append([][]byte(nil), p.clips...)
+}
+
+// mockPrompts 模拟 Prompts，记录播放的提示音
+type mockPrompts struct {
+	mu     sync.Mutex
+	loaded map[string]bool
+	played []string
+	stops  int
+}
+
+func newMockPrompts(names ...string) *mockPrompts {
+	loaded := make(map[string]bool)
+	for _, name := range names {
+		loaded[name] = true
+	}
+	return &mockPrompts{loaded: loaded}
+}
+
+func (p *mockPrompts) Play(name string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.loaded[name] {
+		return audio.ErrPromptNotFound
+	}
+	p.played = append(p.played, name)
+	return nil
+}
+
+func (p *mockPrompts) Stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stops++
+}
+
+func (p *mockPrompts) Has(name string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.loaded[name]
+}
+
+func (p *mockPrompts) Names() []string { return nil }
+
+// Clip 返回以提示音名称为内容的音频
+func (p *mockPrompts) Clip(name string) ([]byte, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.loaded[name] {
+		return nil, false
+	}
+	return []byte(name), true
+}
+
+func (p *mockPrompts) Register(name string, clip []byte) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.loaded[name] = true
+}
+
+func (p *mockPrompts) getPlayed() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.played...)
+}
+
+func (p *mockPrompts) getStops() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stops
+}
@@ -3,14 +3,20 @@ package voicebot
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"strings"
 	"sync"
+	"time"
+	"unicode/utf8"
 
 	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/asr"
 	"github.com/liuscraft/orion-x/internal/audio"
 	"github.com/liuscraft/orion-x/internal/logging"
 	"github.com/liuscraft/orion-x/internal/text"
 	"github.com/liuscraft/orion-x/internal/tools"
+	"github.com/liuscraft/orion-x/pkg/markdown"
 )
 
 // State 表示语音机器人的状态
@@ -21,6 +27,10 @@ const (
 	StateListening
 	StateProcessing
 	StateSpeaking
+	// StateMuted 麦克风已静音且没有进行中的回复，只由 GetState 返回，不是状态机中的状态
+	StateMuted
+	// StateSleeping 长时间空闲后休眠，只响应唤醒词（SleepPolicy）
+	StateSleeping
 )
 
 func (s State) String() string {
@@ -33,6 +43,10 @@ func (s State) String() string {
 		return "Processing"
 	case StateSpeaking:
 		return "Speaking"
+	case StateMuted:
+		return "Muted"
+	case StateSleeping:
+		return "Sleeping"
 	default:
 		return "Unknown"
 	}
@@ -42,18 +56,65 @@ func (s State) String() string {
 type Orchestrator interface {
 	Start(ctx context.Context) error
 	Stop() error
+	// StopGracefully 停止接收新输入，等待进行中的回复生成并播放完毕（最长到 ctx 结束）后再 Stop
+	StopGracefully(ctx context.Context) error
 	GetState() State
 
 	OnASRFinal(text string)
+	// SubmitText 注入一轮文本输入（聊天界面、Webhook、测试），与 ASR final 走同一条 Agent / TTS 路径，
+	// 但不等待说完判定、不受麦克风静音和“没听清”重问影响，并打断进行中的回复；
+	// 文本为空或正在优雅停止时返回 false
+	SubmitText(text string) bool
 	OnUserSpeakingDetected()
 	OnToolCall(tool string, args map[string]interface{})
 	OnToolAudioReady(audio io.Reader)
 	OnLLMTextChunk(chunk string)
 	OnLLMFinished()
+
+	// ResumeInterrupted 重新播放上一轮被打断时未播放的剩余回复
+	// 需要开启 OrchestratorConfig.ResumeInterrupted，没有可恢复的内容时返回 false
+	ResumeInterrupted() bool
+
+	// RepeatLastReply 从头重播最近一次回复（“再说一遍”），开启 OrchestratorConfig.ReplayAudio 且缓存了
+	// 完整音频时直接播放、不再调用 TTS；没有可重复的内容时返回 false
+	RepeatLastReply() bool
+
+	// Announce 不经过 LLM 直接播报一段文本（如 IoT 通知“门铃响了”），会打断进行中的回复；
+	// 文本为空或正在优雅停止时返回 false
+	Announce(text string) bool
+
+	// Alert 不经过 LLM 紧急播报一段提醒（如定时提醒）：正在回复时插到尚未播放的回复句之前、不打断回复，
+	// 空闲时同 Announce；文本为空或正在优雅停止时返回 false
+	Alert(text string) bool
+
+	// Volume / SetVolume 读取和设置整体音量（0~1，超出范围时限幅），与“大声点”“静音”等语音命令共用
+	// OrchestratorConfig.Volume；未配置音量控制时返回 false
+	Volume() (float64, bool)
+	SetVolume(volume float64) (float64, bool)
+
+	// SetPrompts 设置预合成提示音，为 nil 时不播放提示音
+	SetPrompts(prompts audio.Prompts)
+
+	// SetMicMuted 开关麦克风（隐私模式）：静音期间不向云端 ASR 发送麦克风音频、不响应说话打断，
+	// 切换时播放确认音并发布 MicMutedEvent；状态未变化时返回 false
+	SetMicMuted(muted bool) bool
+	MicMuted() bool
+
+	// Sleep / Wake 进入和退出休眠（SleepPolicy）：只能从空闲状态进入休眠，状态未变化时返回 false
+	Sleep() bool
+	Wake() bool
+
+	// Stats 返回会话累计用量（token、首 token 延迟、ASR 时长、TTS 字符数）及麦克风 / 扬声器电平
+	Stats() UsageStats
+
+	// Subscribe 订阅编排器事件（工具结果、引用来源、状态变化、延迟等），供 UI 或服务端转发
+	// handler 在独立 goroutine 中调用
+	Subscribe(eventType EventType, handler EventHandler)
 }
 
 // orchestratorImpl Orchestrator 实现
 type orchestratorImpl struct {
+	config       *OrchestratorConfig
 	stateMachine *StateMachine
 	eventBus     EventBus
 
@@ -61,10 +122,15 @@ type orchestratorImpl struct {
 	audioOutPipe   audio.AudioOutPipe
 	audioInPipe    audio.AudioInPipe
 	toolExecutor   tools.ToolExecutor
+	toolBatch      *tools.ToolCallBatch // 本轮 Agent 请求的工具调用，回复结束后并发执行
+	prompts        audio.Prompts
 	segmenter      *text.Segmenter
 	markdownFilter agent.MarkdownFilter
+	streamFilter   *markdown.StreamFilter // 分句前过滤跨 chunk 的 Markdown 结构（代码块、表格）
+	normalizer     *text.Normalizer       // 为 nil 时不做文本规范化
 
 	currentEmotion string
+	language       string // 最近一次检测到的用户语言，为空表示未检测
 	ctx            context.Context
 	cancel         context.CancelFunc
 
@@ -75,6 +141,60 @@ type orchestratorImpl struct {
 	// TTS 播放计数（用于追踪是否有 TTS 正在播放）
 	ttsPendingCount int
 
+	// 正在运行的 Agent 数量，以及是否处于优雅停止的排空阶段（不再接收新输入）
+	activeAgents int
+	draining     bool
+
+	// 当前回复的播放进度，以及最近一次被打断的回复（下一轮传给 Agent）
+	reply            *replyTracker
+	lastInterruption *agent.Interruption
+	// 最近一次完整播放的回复（文本和音频），供“再说一遍”重播
+	lastReply *replyCache
+	// 本轮回复是否已达到 ReplyLimit，以及超限后截留、未送入 TTS 的句子
+	truncated bool
+	held      []string
+
+	usage   *usageStore
+	latency *latencyTracker
+
+	// 把静音窗口内连续的 ASR final 合并为一轮后再发布 ASRFinalEvent
+	turns *turnAggregator
+	// 当前这句话第一个中间识别结果的时间，收到 final 时清零
+	utteranceStart time.Time
+
+	// 按 ErrorPolicy 限制致歉频率并管理 LLM 失败后的重试
+	failures *errorHandler
+
+	// 连续因识别结果不可靠而重问的次数，有语句交给 Agent 时清零
+	reprompts int
+	// 语音命令静音前的整体音量，取消静音时恢复
+	mutedVolume float64
+	// 麦克风静音（隐私模式）：不向 ASR 发送音频、不响应说话打断
+	micMuted bool
+	// 半双工：播报期间暂停 ASR 推流，未开启或 AudioInPipe 不支持时为 nil
+	duplex *halfDuplex
+	// 最近播报的文本，用于识别回声（EchoGuard）
+	spoken *spokenHistory
+	// 声纹识别开启时，final 音频回调之前先收到的识别置信度
+	finalConfidence *float64
+
+	// 麦克风 / 扬声器电平统计与告警
+	levels *levelMonitor
+
+	// 等待用户口头确认的工具调用（ConfirmationPolicy）
+	pendingActions []pendingAction
+	// Agent 回复末尾等待用户回答的追问，下一轮带给 Agent
+	followUp string
+	// 缺少必填参数、等待用户补全的工具调用（SlotFillingPolicy）
+	pendingSlot *pendingSlot
+	// 面向设备指示灯的语音活动（ActivityEvent）
+	activity *activityTracker
+	// AudioOutPipe 能上报句子开始播放时间，此时以实际出声作为 speaking_started，否则以进入 Speaking 为准
+	playbackTiming bool
+
+	// 串行化工具进度播报（ToolProgressPolicy）
+	progressMu sync.Mutex
+
 	wg sync.WaitGroup
 	mu sync.Mutex
 }
@@ -86,7 +206,31 @@ func NewOrchestrator(
 	audioInPipe audio.AudioInPipe,
 	toolExecutor tools.ToolExecutor,
 ) Orchestrator {
-	return &orchestratorImpl{
+	return NewOrchestratorWithConfig(voiceAgent, audioOutPipe, audioInPipe, toolExecutor, DefaultOrchestratorConfig())
+}
+
+// NewOrchestratorWithConfig 创建新的Orchestrator（带配置）
+func NewOrchestratorWithConfig(
+	voiceAgent agent.VoiceAgent,
+	audioOutPipe audio.AudioOutPipe,
+	audioInPipe audio.AudioInPipe,
+	toolExecutor tools.ToolExecutor,
+	config *OrchestratorConfig,
+) Orchestrator {
+	if config == nil {
+		config = DefaultOrchestratorConfig()
+	}
+	var normalizer *text.Normalizer
+	if config.NormalizeText {
+		normalizer = text.NewNormalizer(config.NormalizeLocale)
+	}
+	// 符号按规范化语言转换为文字（& → 和 / and）
+	symbolWords := markdown.ChineseSymbolWords
+	if text.NewNormalizer(config.NormalizeLocale).Locale() == text.LocaleEn {
+		symbolWords = markdown.EnglishSymbolWords
+	}
+	o := &orchestratorImpl{
+		config:         config,
 		stateMachine:   NewStateMachine(),
 		eventBus:       NewEventBus(),
 		voiceAgent:     voiceAgent,
@@ -94,8 +238,41 @@ func NewOrchestrator(
 		audioInPipe:    audioInPipe,
 		toolExecutor:   toolExecutor,
 		segmenter:      text.NewSegmenter(120),
-		markdownFilter: agent.NewMarkdownFilter(),
+		markdownFilter: agent.NewMarkdownFilterWithOptions(markdown.SpeechOptions(symbolWords)),
+		streamFilter:   markdown.NewStreamFilter(markdown.SpeechOptions(symbolWords)),
+		normalizer:     normalizer,
+		language:       config.ReplyLanguage,
+		reply:          newReplyTracker(),
+		usage:          newUsageStore(),
+		latency:        newLatencyTracker(),
+		failures:       newErrorHandler(config.ErrorPolicy),
+		spoken:         &spokenHistory{},
+	}
+	if toolExecutor != nil {
+		o.toolBatch = o.newToolBatch()
 	}
+	o.levels = newLevelMonitor(config.LevelMonitor, o.eventBus.Publish)
+	_, o.playbackTiming = audioOutPipe.(audio.TTSTimingReporter)
+	o.activity = newActivityTracker(func(activity Activity) {
+		o.eventBus.Publish(NewActivityEvent(activity))
+	})
+	if config.HalfDuplex || config.Sleep.PauseASR {
+		if pauser, ok := audioInPipe.(audio.StreamPauser); ok {
+			o.duplex = newHalfDuplex(pauser, &o.wg)
+		} else if audioInPipe != nil {
+			logging.Warnf("Orchestrator: AudioInPipe cannot pause ASR streaming, half-duplex and sleep pause disabled")
+		}
+	}
+	o.turns = newTurnAggregator(config.EndOfTurnSilence, func(event *ASRFinalEvent) {
+		o.eventBus.Publish(event)
+	})
+	o.stateMachine.SetTimeout(StateProcessing, config.ProcessingTimeout)
+	o.stateMachine.SetTimeout(StateSpeaking, config.SpeakingTimeout)
+	o.stateMachine.SetTimeout(StateListening, config.FollowUpWindow)
+	o.stateMachine.OnTimeout(func(state State, idle time.Duration) {
+		o.eventBus.Publish(NewStateTimeoutEvent(state, idle))
+	})
+	return o
 }
 
 // Start 启动Orchestrator
@@ -112,6 +289,17 @@ func (o *orchestratorImpl) Start(ctx context.Context) error {
 	o.eventBus.Subscribe(EventTypeToolCallRequested, o.handleToolCallRequested)
 	o.eventBus.Subscribe(EventTypeToolAudioReady, o.handleToolAudioReady)
 	o.eventBus.Subscribe(EventTypeLLMEmotionChanged, o.handleLLMEmotionChanged)
+	o.eventBus.Subscribe(EventTypeRecognizerStatus, o.handleRecognizerStatus)
+	o.eventBus.Subscribe(EventTypeStateTimeout, o.handleStateTimeout)
+	if indicator := o.config.Indicator; indicator != nil {
+		o.eventBus.Subscribe(EventTypeActivity, func(event Event) {
+			if e, ok := event.(*ActivityEvent); ok {
+				indicator.OnActivity(e.Activity)
+			}
+		})
+	}
+	// 订阅超时事件后再开始空闲计时
+	o.stateMachine.SetTimeout(StateIdle, o.config.Sleep.IdleTimeout)
 
 	logging.Infof("Orchestrator: event handlers registered")
 
@@ -122,28 +310,79 @@ func (o *orchestratorImpl) Start(ctx context.Context) error {
 			return err
 		}
 		logging.Infof("Orchestrator: AudioInPipe started")
+		if o.duplex != nil {
+			o.duplex.start(o.ctx)
+		}
+
+		speakerGate := false
+		if reporter, ok := o.audioInPipe.(audio.UtteranceAudioReporter); ok && o.config.SpeakerID != nil {
+			// final 结果连同音频一起回调，识别说话人后再发布 ASRFinalEvent
+			reporter.OnASRFinalAudio(o.onASRFinalAudio)
+			speakerGate = true
+		} else if o.config.SpeakerID != nil {
+			logging.Warnf("Orchestrator: AudioInPipe does not report utterance audio, speaker identification disabled")
+		}
+
+		detailGate := false
+		if reporter, ok := o.audioInPipe.(audio.ASRResultDetailReporter); ok {
+			// final 结果带上置信度后再发布，供 Reprompt 判断是否没听清
+			reporter.OnASRResultDetail(func(result asr.Result) {
+				o.onASRFinalDetail(result, speakerGate)
+			})
+			detailGate = true
+		}
 
 		o.audioInPipe.OnASRResult(func(text string, isFinal bool) {
+			o.publishTranscript(text, isFinal)
+			if isFinal && (speakerGate || detailGate) {
+				return
+			}
 			if isFinal {
 				// ASR final 表示用户说完了，直接处理，不触发打断
 				logging.Infof("Orchestrator: ASR final result: %s", text)
 				o.OnASRFinal(text)
 			} else if text != "" {
 				// 只有非 final 的中间结果才触发打断（用户正在说话）
+				o.latency.Speech(time.Now())
 				logging.Infof("Orchestrator: user speaking detected (interim): %s", text)
 				o.OnUserSpeakingDetected()
 			}
 		})
+		o.audioInPipe.OnASRUsage(func(durationSec int) {
+			o.usage.AddASRDuration(time.Duration(durationSec) * time.Second)
+		})
+		o.audioInPipe.OnRecognizerStatus(func(available bool, err error) {
+			o.eventBus.Publish(NewRecognizerStatusEvent(available, err))
+		})
 		o.audioInPipe.OnUserSpeakingDetected(func() {
 			logging.Infof("Orchestrator: VAD user speaking detected")
 			o.OnUserSpeakingDetected()
 		})
+		if reporter, ok := o.audioInPipe.(audio.LevelReporter); ok {
+			reporter.SetOnLevel(func(level audio.AudioLevel) {
+				o.levels.observe(AudioInput, level)
+			})
+		}
 	}
 
 	if o.audioOutPipe != nil {
 		logging.Infof("Orchestrator: starting AudioOutPipe...")
 		// 设置播放完成回调
 		o.audioOutPipe.SetOnPlaybackFinished(o.onTTSPlaybackFinished)
+		if reporter, ok := o.audioOutPipe.(audio.TTSTimingReporter); ok {
+			reporter.SetOnTTSTiming(o.onTTSTiming)
+		}
+		if reporter, ok := o.audioOutPipe.(audio.TTSErrorReporter); ok {
+			reporter.SetOnTTSError(o.onTTSError)
+		}
+		if recorder, ok := o.audioOutPipe.(audio.TTSAudioRecorder); ok && o.config.ReplayAudio {
+			recorder.SetOnTTSAudio(o.onTTSAudio)
+		}
+		if reporter, ok := o.audioOutPipe.(audio.LevelReporter); ok {
+			reporter.SetOnLevel(func(level audio.AudioLevel) {
+				o.levels.observe(AudioOutput, level)
+			})
+		}
 		if err := o.audioOutPipe.Start(o.ctx); err != nil {
 			logging.Errorf("Orchestrator: failed to start AudioOutPipe: %v", err)
 			return err
@@ -170,6 +409,9 @@ func (o *orchestratorImpl) Stop() error {
 	if o.cancel != nil {
 		o.cancel()
 	}
+	o.turns.Stop()
+	o.stateMachine.Stop()
+	o.failures.cancelRetry()
 
 	// 获取组件引用后释放锁，避免死锁
 	// 因为子组件的 Stop 可能会触发回调，回调中需要获取锁
@@ -191,22 +433,128 @@ func (o *orchestratorImpl) Stop() error {
 	logging.Infof("Orchestrator: waiting for goroutines to finish...")
 	o.wg.Wait()
 
+	o.eventBus.Close()
+	if stats := o.eventBus.Stats(); stats.Dropped > 0 || stats.Panics > 0 {
+		logging.Warnf("Orchestrator: event bus published %d, dropped %d, handler panics %d",
+			stats.Published, stats.Dropped, stats.Panics)
+	}
+
 	logging.Infof("Orchestrator: stopped, final state: %s", o.stateMachine.GetCurrentState())
 	return nil
 }
 
-// GetState 获取当前状态
+// drainPollInterval 优雅停止时检查回复是否播放完毕的间隔
+const drainPollInterval = 50 * time.Millisecond
+
+// StopGracefully 优雅停止：不再响应新的识别结果和打断，等待 Agent 完成、TTS 队列播放完毕
+// ctx 结束时不再等待，直接停止（剩余音频被截断）
+func (o *orchestratorImpl) StopGracefully(ctx context.Context) error {
+	o.mu.Lock()
+	o.draining = true
+	o.mu.Unlock()
+
+	logging.Infof("Orchestrator: draining before shutdown...")
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+	for !o.drained() {
+		select {
+		case <-ctx.Done():
+			o.mu.Lock()
+			pending := o.ttsPendingCount
+			o.mu.Unlock()
+			logging.Warnf("Orchestrator: drain deadline reached, stopping with %d TTS pending", pending)
+			return o.Stop()
+		case <-ticker.C:
+		}
+	}
+	logging.Infof("Orchestrator: drained, stopping")
+	return o.Stop()
+}
+
+// drained Agent 已结束且所有 TTS 播放完毕
+func (o *orchestratorImpl) drained() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.activeAgents == 0 && o.ttsPendingCount <= 0
+}
+
+// isDraining 是否正在优雅停止
+func (o *orchestratorImpl) isDraining() bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.draining
+}
+
+// GetState 获取当前状态，麦克风静音且空闲时返回 StateMuted
 func (o *orchestratorImpl) GetState() State {
-	return o.stateMachine.GetCurrentState()
+	state := o.stateMachine.GetCurrentState()
+	if state == StateIdle && o.MicMuted() {
+		return StateMuted
+	}
+	return state
+}
+
+// publishTranscript 把中间识别结果发布为 PartialTranscriptEvent，final 时结束当前句
+func (o *orchestratorImpl) publishTranscript(text string, isFinal bool) {
+	o.mu.Lock()
+	if isFinal {
+		o.utteranceStart = time.Time{}
+		o.mu.Unlock()
+		return
+	}
+	if text == "" {
+		o.mu.Unlock()
+		return
+	}
+	if o.utteranceStart.IsZero() {
+		o.utteranceStart = time.Now()
+	}
+	start := o.utteranceStart
+	o.mu.Unlock()
+
+	o.eventBus.Publish(NewPartialTranscriptEvent(text, start))
 }
 
 // OnASRFinal 处理ASR识别完成
 func (o *orchestratorImpl) OnASRFinal(text string) {
-	o.eventBus.Publish(NewASRFinalEvent(text))
+	o.turns.Add(NewASRFinalEvent(text))
+}
+
+// SubmitText 注入一轮文本输入，绕过 turnAggregator 直接发布
+func (o *orchestratorImpl) SubmitText(text string) bool {
+	text = strings.TrimSpace(text)
+	if text == "" || o.isDraining() {
+		return false
+	}
+	logging.Infof("Orchestrator: text turn submitted: %s", text)
+	event := NewASRFinalEvent(text)
+	event.Injected = true
+	o.eventBus.Publish(event)
+	return true
+}
+
+// onASRFinalDetail 处理带置信度的 ASR final；声纹识别开启时只暂存置信度，随后的音频回调再发布事件
+func (o *orchestratorImpl) onASRFinalDetail(result asr.Result, speakerGate bool) {
+	if !result.IsFinal {
+		return
+	}
+	if speakerGate {
+		o.mu.Lock()
+		o.finalConfidence = result.Confidence
+		o.mu.Unlock()
+		return
+	}
+	logging.Infof("Orchestrator: ASR final result: %s", result.Text)
+	event := NewASRFinalEvent(result.Text)
+	event.Confidence = result.Confidence
+	o.turns.Add(event)
 }
 
 // OnUserSpeakingDetected 处理用户说话检测
 func (o *orchestratorImpl) OnUserSpeakingDetected() {
+	o.turns.Activity()
+	o.stateMachine.Touch(StateIdle)
+	o.stateMachine.Touch(StateListening)
 	o.eventBus.Publish(NewUserSpeakingDetectedEvent())
 }
 
@@ -220,6 +568,42 @@ func (o *orchestratorImpl) OnToolAudioReady(audio io.Reader) {
 	o.eventBus.Publish(NewToolAudioReadyEvent(audio))
 }
 
+// ResumeInterrupted 重新播放上一轮被打断时未播放的剩余回复
+func (o *orchestratorImpl) ResumeInterrupted() bool {
+	o.mu.Lock()
+	interruption := o.lastInterruption
+	if !o.config.ResumeInterrupted || interruption == nil || strings.TrimSpace(interruption.UnspokenText()) == "" {
+		o.mu.Unlock()
+		return false
+	}
+	o.lastInterruption = nil
+	o.reply.Reset()
+	o.takeHeldLocked()
+	o.mu.Unlock()
+
+	logging.Infof("Orchestrator: resuming interrupted reply from rune %d", interruption.InterruptedAt)
+	o.transitionTo(StateProcessing)
+
+	// 剩余回复可能包含多句，重新分句以便打断时仍能追踪播放进度
+	o.speakText(interruption.UnspokenText())
+	return true
+}
+
+// SetPrompts 设置预合成提示音
+func (o *orchestratorImpl) SetPrompts(prompts audio.Prompts) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.prompts = prompts
+}
+
+// Stats 返回会话累计用量
+func (o *orchestratorImpl) Stats() UsageStats {
+	stats := o.usage.Stats()
+	stats.InputLevel, stats.OutputLevel = o.levels.Stats()
+	stats.MicMuted = o.MicMuted()
+	return stats
+}
+
 // OnLLMTextChunk 处理LLM文本流
 func (o *orchestratorImpl) OnLLMTextChunk(chunk string) {
 	logging.Infof("LLM chunk: %s", chunk)
@@ -239,6 +623,16 @@ func (o *orchestratorImpl) handleStateChanged(event Event) {
 }
 
 func (o *orchestratorImpl) handleUserSpeakingDetected(event Event) {
+	// 优雅停止期间不打断，让当前回复说完；麦克风静音或休眠时也不打断（休眠只由唤醒词唤醒）
+	if o.isDraining() || o.MicMuted() || o.stateMachine.GetCurrentState() == StateSleeping {
+		return
+	}
+	o.interruptReply("UserSpeakingDetected")
+	o.activity.speech()
+}
+
+// interruptReply 有进行中的回复（生成中、播放中或 TTS 未完成）时停止并进入 Listening
+func (o *orchestratorImpl) interruptReply(reason string) {
 	currentState := o.stateMachine.GetCurrentState()
 
 	// 检查是否有 TTS 正在播放
@@ -249,60 +643,193 @@ func (o *orchestratorImpl) handleUserSpeakingDetected(event Event) {
 	// 只在 Processing、Speaking 状态或有 TTS pending 时才需要打断
 	needInterrupt := currentState == StateSpeaking || currentState == StateProcessing || ttsPending
 	if needInterrupt {
-		logging.Infof("Orchestrator: UserSpeakingDetected - interrupting (state=%s, ttsPending=%d)", currentState, o.ttsPendingCount)
+		logging.Infof("Orchestrator: %s - interrupting (state=%s, ttsPending=%t)", reason, currentState, ttsPending)
+		o.stopReply()
+		o.transitionTo(StateListening)
+	}
+}
 
-		// 1. 取消 Agent（停止 LLM 生成）
-		o.mu.Lock()
-		if o.agentCancel != nil {
-			logging.Infof("Orchestrator: cancelling Agent...")
-			o.agentCancel()
-			o.agentCancel = nil
-		}
-		o.mu.Unlock()
+// stopReply 停止当前回复：取消 Agent、记录打断位置、中断播放并重置分句与 TTS 计数
+func (o *orchestratorImpl) stopReply() {
+	// 1. 取消 Agent（停止 LLM 生成）及尚未执行的重试
+	o.failures.cancelRetry()
+	o.mu.Lock()
+	if o.agentCancel != nil {
+		logging.Infof("Orchestrator: cancelling Agent...")
+		o.agentCancel()
+		o.agentCancel = nil
+	}
+	o.mu.Unlock()
 
-		// 2. 中断 TTS Pipeline（清空队列、停止播放）
-		if o.audioOutPipe != nil {
-			logging.Infof("Orchestrator: interrupting AudioOutPipe...")
-			o.audioOutPipe.Interrupt()
-		}
+	// 2. 记录用户实际听到的内容（需在中断播放前完成，避免被打断的句子计为已播放）
+	o.captureInterruption()
 
-		// 3. 重置分句器
-		o.segmenter.Flush()
+	// 3. 中断 TTS Pipeline（清空队列、停止播放）
+	if o.audioOutPipe != nil {
+		logging.Infof("Orchestrator: interrupting AudioOutPipe...")
+		o.audioOutPipe.Interrupt()
+	}
 
-		// 4. 重置 TTS 计数
-		o.mu.Lock()
-		o.ttsPendingCount = 0
-		o.mu.Unlock()
+	// 同时停止正在播放的提示音
+	o.stopPrompt()
 
-		// 5. 状态转换
-		o.transitionTo(StateListening)
+	// 4. 重置分句器
+	o.segmenter.Flush()
+	o.streamFilter.Reset()
+	if o.toolBatch != nil {
+		o.toolBatch.Reset()
+	}
+
+	// 5. 重置 TTS 计数，被打断的追问随打断上下文带给下一轮，不再等待回答
+	o.mu.Lock()
+	o.ttsPendingCount = 0
+	o.followUp = ""
+	o.mu.Unlock()
+	o.activity.speakingEnded()
+}
+
+// handleStateTimeout 看门狗：Processing 超时（LLM 无响应）时放弃本轮并提示用户；
+// Speaking 超时（长时间没有播放进度）时强制打断，追问的聆听窗口超时时放弃追问，都回到 Idle 重新接收语音；Idle 超时进入休眠
+func (o *orchestratorImpl) handleStateTimeout(event Event) {
+	timeoutEvent, ok := event.(*StateTimeoutEvent)
+	if !ok {
+		return
+	}
+	// 事件投递期间状态可能已经变化
+	if o.stateMachine.GetCurrentState() != timeoutEvent.State {
+		return
+	}
+
+	switch timeoutEvent.State {
+	case StateProcessing:
+		logging.Warnf("Orchestrator: no agent response for %v, abandoning turn", timeoutEvent.Idle)
+		o.stopReply()
+		err := fmt.Errorf("no agent response for %v: %w", timeoutEvent.Idle, context.DeadlineExceeded)
+		o.reportError(ErrorSourceLLM, ErrorTransient, err, false)
+		o.apologize()
+	case StateSpeaking:
+		logging.Warnf("Orchestrator: no playback progress for %v, forcing interrupt", timeoutEvent.Idle)
+		o.stopReply()
+	case StateListening:
+		expired := o.expireFollowUp()
+		if o.expireSlot() {
+			expired = true
+		}
+		if !expired {
+			return
+		}
+	case StateIdle:
+		logging.Infof("Orchestrator: idle for %v", timeoutEvent.Idle)
+		o.Sleep()
+		return
+	default:
+		return
 	}
+	o.transitionTo(StateIdle)
 }
 
 // onTTSPlaybackFinished TTS 播放完成回调（由 TTSPipeline 调用）
 func (o *orchestratorImpl) onTTSPlaybackFinished() {
+	o.stateMachine.Touch(StateSpeaking)
+	o.spoken.touch(time.Now())
 	o.mu.Lock()
 	o.ttsPendingCount--
 	pending := o.ttsPendingCount
+	o.reply.Played()
+	if pending <= 0 {
+		o.cacheReplyLocked()
+	}
 	o.mu.Unlock()
 
 	logging.Infof("Orchestrator: TTS playback finished, pending count: %d", pending)
 
-	// 如果所有 TTS 都播放完成，转为 Idle
+	// 如果所有 TTS 都播放完成，转为 Idle；回复以追问结尾时进入 Listening 等待回答
 	if pending <= 0 {
+		o.activity.speakingEnded()
 		currentState := o.stateMachine.GetCurrentState()
-		if currentState == StateSpeaking {
+		if currentState == StateSpeaking && !o.openFollowUpWindow() {
 			logging.Infof("Orchestrator: All TTS finished, transitioning to Idle")
 			o.transitionTo(StateIdle)
 		}
 	}
 }
 
+// onTTSTiming 句子开始播放回调，本轮首句播放时输出延迟分解
+func (o *orchestratorImpl) onTTSTiming(timing audio.TTSTiming) {
+	o.stateMachine.Touch(StateSpeaking)
+	// 打断后才到达的旧句子回调不算出声
+	if o.stateMachine.GetCurrentState() == StateSpeaking {
+		o.activity.speakingStarted()
+	}
+	latency, ok := o.latency.Playback(timing)
+	if !ok {
+		return
+	}
+	logging.Infof("Orchestrator: turn latency - speech_end→asr_final: %v, asr_final→first_token: %v, first_sentence→first_audio: %v, first_audio→playback: %v, total: %v",
+		latency.SpeechEndToASRFinal, latency.ASRFinalToFirstToken, latency.FirstSentenceToFirstAudio,
+		latency.FirstAudioToPlayback, latency.EndToEnd)
+	o.eventBus.Publish(NewTurnLatencyEvent(latency))
+}
+
 func (o *orchestratorImpl) handleASRFinal(event Event) {
 	asrEvent, ok := event.(*ASRFinalEvent)
 	if !ok {
 		return
 	}
+	if o.isDraining() {
+		logging.Infof("Orchestrator: draining, ignoring ASR final: %s", asrEvent.Text)
+		return
+	}
+	if asrEvent.Injected {
+		// 注入的文本没有 VAD 打断，开始新一轮前先停止进行中的回复
+		o.wake("text input")
+		o.interruptReply("text turn")
+	} else if o.MicMuted() {
+		// 静音前已送出的音频仍可能返回识别结果
+		logging.Infof("Orchestrator: microphone muted, ignoring ASR final: %s", asrEvent.Text)
+		return
+	} else if asrEvent.Attempt == 0 && o.isEcho(asrEvent) {
+		return
+	} else if o.stateMachine.GetCurrentState() == StateSleeping {
+		if asrEvent, ok = o.handleSleepingASRFinal(asrEvent); !ok {
+			return
+		}
+	}
+	if o.ignoreSpeaker(asrEvent.Speaker) {
+		logging.Infof("Orchestrator: ignoring ASR final from unknown speaker: %s", asrEvent.Text)
+		return
+	}
+
+	// 用户说了新的话（或重试开始执行），之前安排的重试不再需要
+	if asrEvent.Attempt == 0 {
+		o.failures.cancelRetry()
+	}
+
+	if o.isResumeIntent(asrEvent.Text) && o.ResumeInterrupted() {
+		return
+	}
+	// 对待确认操作的回答（“确认”“取消”）不调用 Agent
+	if asrEvent.Attempt == 0 && o.handleConfirmationAnswer(asrEvent) {
+		return
+	}
+	// “停”“大声点”等控制命令直接处理，不调用 Agent
+	if asrEvent.Attempt == 0 && o.handleCommand(asrEvent) {
+		return
+	}
+
+	// 重试的是已交给过 Agent 的语句，不再判断是否没听清或重新过滤；注入的文本不存在没听清
+	if asrEvent.Attempt == 0 {
+		if !asrEvent.Injected && o.reprompt(asrEvent) {
+			return
+		}
+		if asrEvent, ok = o.filterInput(asrEvent); !ok {
+			return
+		}
+		// 对参数追问的回答（“北京”）填入挂起的工具调用后直接执行，不调用 Agent
+		if o.handleSlotAnswer(asrEvent) {
+			return
+		}
+	}
 
 	// 如果之前有 Agent 在运行，先取消
 	o.mu.Lock()
@@ -311,41 +838,133 @@ func (o *orchestratorImpl) handleASRFinal(event Event) {
 		o.agentCancel()
 	}
 
-	// 为新的 Agent 调用创建独立的 context
-	o.agentCtx, o.agentCancel = context.WithCancel(o.ctx)
+	// 为新的 Agent 调用创建独立的 context，并带上轮次 ID，Agent、工具、TTS 的日志据此关联到本轮
+	turnCtx, turn := logging.StartTurnContext(o.ctx)
+	o.agentCtx, o.agentCancel = context.WithCancel(turnCtx)
 	agentCtx := o.agentCtx
+
+	// 上一轮回复被打断时，把用户实际听到的内容带给 Agent
+	processCtx := agentCtx
+	if o.lastInterruption != nil {
+		processCtx = agent.WithInterruption(agentCtx, *o.lastInterruption)
+		o.lastInterruption = nil
+	}
+	// 上一轮回复以追问结尾时，把追问带给 Agent
+	followUp := o.takeFollowUpLocked()
+	if followUp != "" {
+		processCtx = agent.WithFollowUp(processCtx, followUp)
+	}
+	// 无法判断语言的语句（如纯数字）沿用上一句的语言
+	if o.config.DetectLanguage && o.config.ReplyLanguage == "" {
+		if language := text.DetectLanguage(asrEvent.Text); language != "" {
+			o.language = language
+		}
+		if o.language != "" {
+			processCtx = agent.WithLanguage(processCtx, o.language)
+		}
+	}
+	o.reply.Reset()
+	o.takeHeldLocked()
+	o.activeAgents++
 	o.mu.Unlock()
 
-	logging.StartTurn()
-	logging.Infof("Orchestrator: ASR final event received: %s", asrEvent.Text)
+	if followUp != "" {
+		o.eventBus.Publish(NewFollowUpEvent(followUp, FollowUpAnswered))
+	}
+	o.usage.BeginTurn(turn)
+	o.latency.BeginTurn(turn, asrEvent.Timestamp())
+	logging.InfofCtx(agentCtx, "Orchestrator: ASR final event received: %s", asrEvent.Text)
 	o.transitionTo(StateProcessing)
 
 	o.wg.Add(1)
 	go func() {
 		defer o.wg.Done()
+		defer func() {
+			o.mu.Lock()
+			o.activeAgents--
+			o.mu.Unlock()
+		}()
+		if o.config.OnReplyFinished != nil {
+			defer o.config.OnReplyFinished()
+		}
+
+		// LLM 首个响应过慢时播放填充音，收到任意 Agent 事件后取消
+		stopFiller := o.startFiller(agentCtx)
+		defer stopFiller()
 
 		// 使用 agentCtx 调用 Agent（可被打断）
-		eventChan, err := o.voiceAgent.Process(agentCtx, asrEvent.Text)
+		eventChan, err := o.voiceAgent.Process(processCtx, asrEvent.Text)
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
-				logging.Infof("Orchestrator: VoiceAgent process cancelled (normal interruption)")
+				logging.InfofCtx(agentCtx, "Orchestrator: VoiceAgent process cancelled (normal interruption)")
 			} else {
-				logging.Errorf("Orchestrator: VoiceAgent process error: %v", err)
+				logging.ErrorfCtx(agentCtx, "Orchestrator: VoiceAgent process error: %v", err)
+				o.handleLLMFailure(asrEvent, err, false)
 			}
 			o.transitionTo(StateIdle)
 			return
 		}
 
-		for agentEvent := range eventChan {
-			// 检查是否被取消
+		// 本轮已产生的回复内容，以及流式过程中 Agent 报告的错误
+		replied := false
+		var streamErr error
+
+		// LLM 在句中停顿超过 SegmentFlushDelay 时，把分句器中的半句先送入 TTS
+		flushTimer := time.NewTimer(time.Hour)
+		flushTimer.Stop()
+		defer flushTimer.Stop()
+
+	events:
+		for {
 			select {
-			case <-agentCtx.Done():
-				logging.Infof("Orchestrator: Agent cancelled, stopping event processing")
-				return
-			default:
+			case agentEvent, ok := <-eventChan:
+				if !ok {
+					break events
+				}
+				stopFiller()
+				o.stateMachine.Touch(StateProcessing)
+
+				// 检查是否被取消
+				select {
+				case <-agentCtx.Done():
+					logging.InfofCtx(agentCtx, "Orchestrator: Agent cancelled, stopping event processing")
+					return
+				default:
+				}
+
+				switch e := agentEvent.(type) {
+				case *agent.TextChunkEvent:
+					replied = replied || e.Chunk != ""
+				case *agent.ToolCallRequestedEvent, *agent.MissingParamEvent, *agent.AudioChunkEvent:
+					replied = true
+				case *agent.FinishedEvent:
+					if e.Error != nil && !errors.Is(e.Error, context.Canceled) {
+						streamErr = e.Error
+					}
+				}
+				o.handleAgentEvent(agentEvent)
+				if o.config.SegmentFlushDelay > 0 && o.segmenter.Buffered() > 0 {
+					flushTimer.Reset(o.config.SegmentFlushDelay)
+				} else {
+					flushTimer.Stop()
+				}
+			case <-flushTimer.C:
+				if agentCtx.Err() != nil {
+					continue
+				}
+				if pending := o.segmenter.Flush(); pending != "" {
+					logging.InfofCtx(agentCtx, "Orchestrator: LLM paused for %v, flushing partial sentence", o.config.SegmentFlushDelay)
+					_ = o.speakSentences([]string{pending})
+				}
 			}
+		}
 
-			o.handleAgentEvent(agentEvent)
+		if streamErr != nil && agentCtx.Err() == nil {
+			logging.ErrorfCtx(agentCtx, "Orchestrator: VoiceAgent stream error: %v", streamErr)
+			o.handleLLMFailure(asrEvent, streamErr, replied)
+			if !replied {
+				o.transitionTo(StateIdle)
+			}
 		}
 
 		// Agent 完成后清理
@@ -365,23 +984,50 @@ func (o *orchestratorImpl) handleToolCallRequested(event Event) {
 
 	logging.Infof("Orchestrator: ToolCallRequested event - tool: %s, args: %v", toolEvent.Tool, toolEvent.Args)
 
+	// 工具随当前 Agent 一起被打断取消
+	o.mu.Lock()
+	toolCtx := o.agentCtx
+	if toolCtx == nil {
+		toolCtx = o.ctx
+	}
+	o.mu.Unlock()
+
 	o.wg.Add(1)
 	go func() {
 		defer o.wg.Done()
 
-		result, audioReader, err := o.toolExecutor.Execute(toolEvent.Tool, toolEvent.Args)
-		if err != nil {
-			logging.Errorf("Orchestrator: Tool execution error: %v", err)
-			return
+		batch := o.newToolBatch()
+		batch.Add(toolEvent.Tool, toolEvent.Args)
+		o.deliverToolResults(batch.Execute(toolCtx))
+	}()
+}
+
+// deliverToolResults 按请求顺序发布工具结果并播放工具返回的音频，最后发布整批结果
+// 不同事件类型的订阅者各自处理，需要一次拿到整批结果时应订阅 EventTypeToolResults
+func (o *orchestratorImpl) deliverToolResults(results []tools.ToolCallResult) {
+	if len(results) == 0 {
+		return
+	}
+	defer o.eventBus.Publish(NewToolResultsEvent(results))
+
+	for _, r := range results {
+		o.publishToolResult(r.Tool, r.Args, r.Result, r.Duration, r.Err)
+		if r.Err != nil {
+			if errors.Is(r.Err, context.Canceled) {
+				logging.Infof("Orchestrator: tool %s cancelled (normal interruption)", r.Tool)
+			} else {
+				logging.Errorf("Orchestrator: Tool execution error: %v", r.Err)
+			}
+			continue
 		}
 
-		if audioReader != nil {
-			logging.Infof("Orchestrator: tool returned audio, playing...")
-			o.OnToolAudioReady(audioReader)
+		if r.Audio != nil {
+			logging.Infof("Orchestrator: tool %s returned audio, playing...", r.Tool)
+			o.playResource(r.Tool, r.Audio)
 		}
 
-		logging.Infof("Orchestrator: Tool execution result: %v", result)
-	}()
+		logging.Infof("Orchestrator: Tool execution result: %v", r.Result)
+	}
 }
 
 func (o *orchestratorImpl) handleToolAudioReady(event Event) {
@@ -391,7 +1037,24 @@ func (o *orchestratorImpl) handleToolAudioReady(event Event) {
 	}
 
 	logging.Infof("Orchestrator: ToolAudioReady event, playing resource audio...")
-	err := o.audioOutPipe.PlayResource(audioEvent.Audio)
+	o.playResource("", audioEvent.Audio)
+}
+
+// playResource 排队播放工具返回的音频，播放结束时发布 ToolAudioFinishedEvent
+// AudioOutPipe 未实现 audio.ResourcePlayer 时退化为 PlayResource，不发布结束事件
+func (o *orchestratorImpl) playResource(tool string, audioReader io.Reader) {
+	var err error
+	if player, ok := o.audioOutPipe.(audio.ResourcePlayer); ok {
+		err = player.PlayResourceWithOptions(audioReader, audio.ResourceOptions{
+			Mode: audio.ResourceQueue,
+			OnFinished: func(interrupted bool) {
+				logging.Infof("Orchestrator: tool audio finished (tool=%s, interrupted=%v)", tool, interrupted)
+				o.eventBus.Publish(NewToolAudioFinishedEvent(tool, interrupted))
+			},
+		})
+	} else {
+		err = o.audioOutPipe.PlayResource(audioReader)
+	}
 	if err != nil {
 		logging.Errorf("Orchestrator: Play resource error: %v", err)
 	}
@@ -403,66 +1066,130 @@ func (o *orchestratorImpl) handleLLMEmotionChanged(event Event) {
 		return
 	}
 
-	o.currentEmotion = emotionEvent.Emotion
+	// currentEmotion 已在 switchEmotion 中同步更新，这里只做记录，避免与 Agent 协程并发写
 	logging.Infof("Orchestrator: LLM emotion changed to: %s", emotionEvent.Emotion)
 }
 
+func (o *orchestratorImpl) handleRecognizerStatus(event Event) {
+	statusEvent, ok := event.(*RecognizerStatusEvent)
+	if !ok {
+		return
+	}
+
+	if statusEvent.Available {
+		logging.Infof("Orchestrator: recognizer available again")
+		return
+	}
+	// 识别中断期间用户说的话不会立即得到响应，AudioInPipe 自动重连的同时向用户致歉
+	logging.Warnf("Orchestrator: recognizer unavailable: %v", statusEvent.Err)
+	o.reportError(ErrorSourceASR, o.config.ErrorPolicy.classify(statusEvent.Err), statusEvent.Err, true)
+	o.apologize()
+}
+
+// onTTSError 单句合成失败回调：该句不会播放，向用户致歉
+func (o *orchestratorImpl) onTTSError(text string, err error) {
+	o.reportError(ErrorSourceTTS, o.config.ErrorPolicy.classify(err), err, false)
+	o.apologize()
+}
+
+// handleLLMFailure 本轮 Agent 失败：瞬时错误且尚未产生任何回复时，按 ErrorPolicy 延迟后重新处理本轮，
+// 否则（或重试次数用尽）向用户致歉
+func (o *orchestratorImpl) handleLLMFailure(asrEvent *ASRFinalEvent, err error, replied bool) {
+	policy := o.config.ErrorPolicy
+	kind := policy.classify(err)
+	retrying := !replied && policy.shouldRetry(kind, asrEvent.Attempt)
+	o.reportError(ErrorSourceLLM, kind, err, retrying)
+	if !retrying {
+		o.apologize()
+		return
+	}
+
+	delay := policy.retryDelay(asrEvent.Attempt)
+	retry := *asrEvent
+	retry.Attempt++
+	logging.Infof("Orchestrator: retrying turn in %v (attempt %d/%d): %s", delay, retry.Attempt, policy.MaxRetries, retry.Text)
+	o.failures.scheduleRetry(delay, func() {
+		o.eventBus.Publish(&retry)
+	})
+}
+
+// reportError 发布 ErrorEvent，供 UI 或监控展示
+func (o *orchestratorImpl) reportError(source ErrorSource, kind ErrorKind, err error, retrying bool) {
+	logging.Warnf("Orchestrator: %s error (%s, retrying=%v): %v", source, kind, retrying, err)
+	o.eventBus.Publish(NewErrorEvent(source, kind, err, retrying))
+}
+
+// apologize 播放致歉提示音，ApologyInterval 内只播放一次；AudioOutPipe 支持 audio.PrioritySpeechPlayer 时
+// 提示音作为紧急播报插到尚未播放的回复句之前，不与回复重叠，否则直接通过提示音通道播放
+func (o *orchestratorImpl) apologize() {
+	prompt := o.config.ErrorPolicy.ApologyPrompt
+	if prompt == "" || !o.failures.allowApology(time.Now()) {
+		return
+	}
+	if clip, ok := o.promptClip(prompt); ok && o.speakUrgent(prompt, clip) {
+		return
+	}
+	o.playPrompt(prompt)
+}
+
 func (o *orchestratorImpl) handleAgentEvent(event agent.AgentEvent) {
 	switch e := event.(type) {
 	case *agent.TextChunkEvent:
+		if e.Chunk != "" {
+			o.latency.FirstToken(time.Now())
+		}
 		o.OnLLMTextChunk(e.Chunk)
-		if e.Emotion != "" && e.Emotion != o.currentEmotion {
-			o.currentEmotion = e.Emotion
-			o.eventBus.Publish(NewLLMEmotionChangedEvent(e.Emotion))
-		}
-
-		sentences := o.segmenter.Feed(e.Chunk)
-		for _, sentence := range sentences {
-			if sentence != "" {
-				// 移除 Markdown 格式，避免 TTS 播放特殊符号
-				sentence = o.markdownFilter.Filter(sentence)
-				logging.Infof("Orchestrator: enqueuing TTS for sentence: %s", sentence)
-				// PlayTTS 现在是异步的，立即返回
-				err := o.audioOutPipe.PlayTTS(sentence, o.currentEmotion)
-				if err != nil {
-					if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-						logging.Infof("Orchestrator: PlayTTS cancelled (normal interruption)")
-						return // 被打断，停止处理
-					} else {
-						logging.Errorf("Orchestrator: PlayTTS error: %v", err)
-					}
-				}
-				// 增加 TTS 计数
-				o.mu.Lock()
-				o.ttsPendingCount++
-				o.mu.Unlock()
-				o.transitionTo(StateSpeaking)
-			}
+		if o.config.OnReplyText != nil && e.Chunk != "" {
+			o.config.OnReplyText(o.maskReplyText(e.Chunk))
+		}
+		o.switchEmotion(e.Emotion)
+
+		// 先经过流式 Markdown 过滤，跨 chunk 的代码块、表格不会被拆进句子里
+		if err := o.speakSentences(o.segmenter.Feed(o.streamFilter.Feed(e.Chunk))); err != nil {
+			return // 被打断，停止处理
 		}
+	case *agent.AudioChunkEvent:
+		o.playAudioChunk(e)
 	case *agent.EmotionChangedEvent:
-		o.currentEmotion = e.Emotion
-		o.eventBus.Publish(NewLLMEmotionChangedEvent(e.Emotion))
+		o.switchEmotion(e.Emotion)
 	case *agent.ToolCallRequestedEvent:
-		o.OnToolCall(e.Tool, e.Args)
+		o.requestTool(e.Tool, e.Args)
+	case *agent.MissingParamEvent:
+		if !o.requestSlot(e) {
+			o.requestTool(e.Tool, e.Args)
+		}
+	case *agent.ToolResultEvent:
+		o.publishToolResult(e.Tool, e.Args, e.Result, e.Duration, e.Error)
+	case *agent.CitationEvent:
+		logging.Infof("Orchestrator: %d citation(s) from %q", len(e.Citations), e.Tool)
+		o.eventBus.Publish(NewCitationEvent(e.Tool, e.Citations))
+	case *agent.FollowUpEvent:
+		o.onFollowUp(e.Question)
+	case *agent.DegradedModeEvent:
+		logging.Warnf("Orchestrator: running in degraded mode, LLM switched to %s: %v", e.Provider, e.Reason)
 	case *agent.FinishedEvent:
+		o.usage.RecordLLM(e.Usage)
+		turn := o.usage.Current()
+		logging.Infof("Orchestrator: turn usage - tokens: %d+%d, first token: %v, asr: %v",
+			turn.PromptTokens, turn.CompletionTokens, turn.FirstTokenLatency, turn.ASRDuration)
+		if err := o.speakSentences(o.segmenter.Feed(o.streamFilter.Flush())); err != nil {
+			return
+		}
 		if last := o.segmenter.Flush(); last != "" {
 			// 移除 Markdown 格式，避免 TTS 播放特殊符号
 			last = o.markdownFilter.Filter(last)
 			logging.Infof("Orchestrator: enqueuing final TTS sentence: %s", last)
-			// PlayTTS 现在是异步的，立即返回
-			err := o.audioOutPipe.PlayTTS(last, o.currentEmotion)
-			if err != nil {
-				if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-					logging.Infof("Orchestrator: PlayTTS cancelled (normal interruption)")
-				} else {
-					logging.Errorf("Orchestrator: PlayTTS error: %v", err)
-				}
-			}
-			// 增加 TTS 计数
+			_ = o.speak(last)
+		}
+		o.finishTruncatedReply()
+		if o.toolBatch != nil && o.toolBatch.Len() > 0 {
 			o.mu.Lock()
-			o.ttsPendingCount++
+			ctx := o.agentCtx
 			o.mu.Unlock()
-			o.transitionTo(StateSpeaking)
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			o.deliverToolResults(o.toolBatch.Execute(ctx))
 		}
 		logging.Infof("Orchestrator: VoiceAgent finished (TTS pending: %d)", o.ttsPendingCount)
 		// 注意：不转为 Idle，保持 Speaking 状态直到所有 TTS 播放完成
@@ -470,19 +1197,280 @@ func (o *orchestratorImpl) handleAgentEvent(event agent.AgentEvent) {
 	}
 }
 
+// requestTool 处理 Agent 请求的工具调用：需要确认的先挂起，其余加入本轮批次或直接执行
+func (o *orchestratorImpl) requestTool(tool string, args map[string]interface{}) {
+	if o.requestConfirmation(tool, args) {
+		return
+	}
+	if o.toolBatch == nil {
+		o.OnToolCall(tool, args)
+		return
+	}
+	// 同一轮的多个工具调用在回复结束后并发执行，结果按请求顺序交付
+	logging.Infof("Orchestrator: queued tool call %s (batch size: %d)", tool, o.toolBatch.Len()+1)
+	o.toolBatch.Add(tool, args)
+}
+
+// playAudioChunk 播放端到端语音模型直接生成的语音，不经过分句和 TTS；
+// AudioOutPipe 不支持播放音频片段时退化为用 TTS 朗读对应的文字
+func (o *orchestratorImpl) playAudioChunk(e *agent.AudioChunkEvent) {
+	o.latency.FirstToken(time.Now())
+	if o.config.OnReplyText != nil && e.Text != "" {
+		o.config.OnReplyText(o.maskReplyText(e.Text))
+	}
+	player, ok := o.audioOutPipe.(audio.AudioClipPlayer)
+	if !ok {
+		_ = o.speakSentences([]string{e.Text})
+		return
+	}
+	_ = o.enqueueAudio(player, e.Text, e.PCM)
+}
+
+// speakSentences 逐句过滤 Markdown 后送入 TTS，被打断时返回错误
+func (o *orchestratorImpl) speakSentences(sentences []string) error {
+	for _, sentence := range sentences {
+		if sentence == "" {
+			continue
+		}
+		// 移除 Markdown 格式，避免 TTS 播放特殊符号
+		sentence = o.markdownFilter.Filter(sentence)
+		logging.Infof("Orchestrator: enqueuing TTS for sentence: %s", sentence)
+		if err := o.speak(sentence); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// switchEmotion 切换播报情绪（决定 TTS 音色）
+// 分句器中尚未送入 TTS 的文本属于切换前的内容，先用旧情绪播报
+func (o *orchestratorImpl) switchEmotion(emotion string) {
+	if emotion == "" || emotion == o.currentEmotion {
+		return
+	}
+	if pending := o.segmenter.Flush(); pending != "" {
+		if pending = o.markdownFilter.Filter(pending); pending != "" {
+			logging.Infof("Orchestrator: flushing pending text before emotion change: %s", pending)
+			_ = o.speak(pending)
+		}
+	}
+	o.currentEmotion = emotion
+	o.eventBus.Publish(NewLLMEmotionChangedEvent(emotion))
+}
+
+// isResumeIntent 判断用户是否在要求继续被打断的回复
+func (o *orchestratorImpl) isResumeIntent(input string) bool {
+	normalized := normalizeUtterance(input)
+	if normalized == "" {
+		return false
+	}
+	for _, phrase := range o.config.ResumePhrases {
+		if normalizeUtterance(phrase) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// speak 播报一句回复：先经过内容过滤，超过 ReplyLimit 时截留，否则送入 TTS
+// 仅在被打断（context 取消）或被内容过滤拒绝时返回错误，其余错误只记录日志
+func (o *orchestratorImpl) speak(sentence string) error {
+	sentence, ok := o.filterOutput(sentence)
+	if !ok {
+		// 回复被内容过滤拒绝，调用方按打断处理
+		return context.Canceled
+	}
+	if o.holdOverLimit(sentence) {
+		return nil
+	}
+	return o.enqueueTTS(sentence)
+}
+
+// enqueueTTS 将句子送入 TTS 并记录播放进度
+// 送入 TTS 的是规范化后的文本，播放进度仍记录原句，打断时传给 Agent 的是它自己的原话
+func (o *orchestratorImpl) enqueueTTS(sentence string) error {
+	o.mu.Lock()
+	language := o.language
+	turnCtx := o.agentCtx
+	o.mu.Unlock()
+
+	spoken := sentence
+	if normalizer := o.normalizerFor(language); normalizer != nil {
+		spoken = normalizer.Normalize(sentence)
+	}
+	// PlayTTS 现在是异步的，立即返回
+	enqueuedAt := time.Now()
+	var err error
+	if player, ok := o.audioOutPipe.(audio.ContextTTSPlayer); ok && turnCtx != nil {
+		err = player.PlayTTSContext(turnCtx, spoken, o.currentEmotion, language)
+	} else if player, ok := o.audioOutPipe.(audio.LanguageTTSPlayer); ok && language != "" {
+		err = player.PlayTTSWithLanguage(spoken, o.currentEmotion, language)
+	} else {
+		err = o.audioOutPipe.PlayTTS(spoken, o.currentEmotion)
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			logging.Infof("Orchestrator: PlayTTS cancelled (normal interruption)")
+			return err
+		}
+		logging.Errorf("Orchestrator: PlayTTS error: %v", err)
+	}
+	// 增加 TTS 计数
+	o.spoken.add(enqueuedAt, sentence, spoken)
+	o.mu.Lock()
+	o.ttsPendingCount++
+	o.reply.Enqueued(sentence)
+	o.mu.Unlock()
+	o.usage.AddTTSChars(utf8.RuneCountInString(spoken))
+	o.latency.SentenceEnqueued(enqueuedAt)
+	o.transitionTo(StateSpeaking)
+	return nil
+}
+
+// normalizerFor 返回适用于回复语言的规范化器：检测到中文或英文时使用对应规则，否则使用配置的规则
+func (o *orchestratorImpl) normalizerFor(language string) *text.Normalizer {
+	if o.normalizer == nil {
+		return nil
+	}
+	if (language == text.LocaleZh || language == text.LocaleEn) && language != o.normalizer.Locale() {
+		return text.NewNormalizer(language)
+	}
+	return o.normalizer
+}
+
+// playPrompt 播放提示音，未设置或未加载时忽略
+func (o *orchestratorImpl) playPrompt(name string) {
+	o.mu.Lock()
+	prompts := o.prompts
+	o.mu.Unlock()
+
+	if prompts == nil || !prompts.Has(name) {
+		return
+	}
+	if err := prompts.Play(name); err != nil {
+		logging.Errorf("Orchestrator: play prompt %s error: %v", name, err)
+	}
+}
+
+// promptClip 返回已加载的提示音音频，Prompts 未设置或不支持 audio.PromptClipper 时返回 false
+func (o *orchestratorImpl) promptClip(name string) ([]byte, bool) {
+	o.mu.Lock()
+	prompts := o.prompts
+	o.mu.Unlock()
+
+	clipper, ok := prompts.(audio.PromptClipper)
+	if !ok {
+		return nil, false
+	}
+	return clipper.Clip(name)
+}
+
+// startFiller 启动填充音计时器，返回的 stop 函数可重复调用
+// 计时器到期时若 Agent 仍未产生任何事件且未被取消，则播放填充提示音
+func (o *orchestratorImpl) startFiller(ctx context.Context) (stop func()) {
+	delay := o.config.FillerDelay
+	if delay <= 0 {
+		return func() {}
+	}
+
+	var mu sync.Mutex
+	stopped := false
+	timer := time.AfterFunc(delay, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if stopped || ctx.Err() != nil {
+			return
+		}
+		logging.Infof("Orchestrator: no LLM response after %v, playing filler prompt", delay)
+		o.playPrompt(o.config.FillerPrompt)
+	})
+
+	return func() {
+		mu.Lock()
+		stopped = true
+		mu.Unlock()
+		timer.Stop()
+	}
+}
+
+// stopPrompt 停止正在播放的提示音
+func (o *orchestratorImpl) stopPrompt() {
+	o.mu.Lock()
+	prompts := o.prompts
+	o.mu.Unlock()
+
+	if prompts != nil {
+		prompts.Stop()
+	}
+}
+
+// captureInterruption 记录被打断回复的上下文，供下一轮 Agent 调用使用
+func (o *orchestratorImpl) captureInterruption() {
+	remainder := o.segmenter.Flush() + o.streamFilter.Flush()
+	if remainder != "" {
+		remainder = o.markdownFilter.Filter(remainder)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	// 超过 ReplyLimit 被截留的句子排在分句器剩余文本之前
+	remainder = o.takeHeldLocked() + remainder
+	interruption := o.reply.Snapshot(remainder)
+	o.reply.Reset()
+	if interruption.IsEmpty() {
+		return
+	}
+	o.lastInterruption = &interruption
+	logging.Infof("Orchestrator: reply interrupted at rune %d (spoken: %q)", interruption.InterruptedAt, interruption.SpokenText)
+}
+
+// normalizeUtterance 去除标点、空白并转为小写，用于话术匹配
+func normalizeUtterance(input string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(input) {
+		if skipUtteranceRune(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Subscribe 订阅编排器事件
+func (o *orchestratorImpl) Subscribe(eventType EventType, handler EventHandler) {
+	o.eventBus.Subscribe(eventType, handler)
+}
+
+// publishToolResult 发布工具执行结果（Orchestrator 自己执行的工具和 Agent 上报的工具结果）
+func (o *orchestratorImpl) publishToolResult(tool string, args map[string]interface{}, result interface{}, duration time.Duration, err error) {
+	logging.Infof("Orchestrator: tool %s finished in %v (error: %v)", tool, duration, err)
+	o.eventBus.Publish(NewToolResultEvent(tool, args, result, duration, err))
+}
+
 func (o *orchestratorImpl) transitionTo(newState State) bool {
 	oldState := o.stateMachine.GetCurrentState()
 	if o.stateMachine.Transition(newState) {
+		o.updateHalfDuplex(newState)
 		o.eventBus.Publish(NewStateChangedEvent(oldState, newState))
+		if newState == StateSpeaking && !o.playbackTiming {
+			o.activity.speakingStarted()
+		}
+		o.activity.stateChanged(newState)
 		return true
 	}
 	return false
 }
 
 // EventBus 事件总线，负责组件间异步通信
+// 每个订阅者有独立的缓冲与处理协程：Publish 不阻塞，慢订阅者只会丢弃自己的事件，处理器 panic 不影响其他事件
 type EventBus interface {
 	Publish(event Event)
 	Subscribe(eventType EventType, handler EventHandler)
+	SubscribeWithOptions(eventType EventType, handler EventHandler, opts SubscribeOptions)
+	Stats() EventBusStats
+	// Close 停止接收新事件，已缓冲的事件仍会处理完
+	Close()
 }
 
 // Event 事件接口
@@ -501,6 +1489,28 @@ const (
 	EventTypeLLMEmotionChanged
 	EventTypeTTSInterrupt
 	EventTypeStateChanged
+	EventTypeRecognizerStatus
+	EventTypeTurnLatency
+	EventTypeToolResult
+	EventTypeCitation
+	EventTypeToolResults
+	EventTypeToolAudioFinished
+	EventTypePartialTranscript
+	EventTypeStateTimeout
+	EventTypeError
+	EventTypeAudioLevel
+	EventTypeAudioLevelAlert
+	EventTypeReprompt
+	EventTypeContentFiltered
+	EventTypeReplyTruncated
+	EventTypeControlCommand
+	EventTypeMicMuted
+	EventTypeActionConfirmation
+	EventTypeToolProgress
+	EventTypeEchoSuppressed
+	EventTypeFollowUp
+	EventTypeSlotFilling
+	EventTypeActivity
 )
 
 // EventHandler 事件处理器
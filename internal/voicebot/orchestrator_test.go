@@ -2,8 +2,14 @@ package voicebot
 
 import (
 	"context"
+	"errors"
+	"reflect"
 	"sync"
 	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
 )
 
 func TestStateMachine(t *testing.T) {
@@ -108,6 +114,223 @@ func TestEventBus(t *testing.T) {
 	}
 }
 
+func TestStateMachineTimeout(t *testing.T) {
+	sm := NewStateMachine()
+	defer sm.Stop()
+	fired := make(chan State, 4)
+	sm.OnTimeout(func(state State, idle time.Duration) { fired <- state })
+	sm.SetTimeout(StateProcessing, 60*time.Millisecond)
+
+	sm.Transition(StateProcessing)
+	// 活动推迟超时
+	time.Sleep(40 * time.Millisecond)
+	sm.Touch(StateProcessing)
+	time.Sleep(40 * time.Millisecond)
+	select {
+	case <-fired:
+		t.Fatal("timeout fired despite activity")
+	default:
+	}
+	select {
+	case state := <-fired:
+		if state != StateProcessing {
+			t.Fatalf("timeout state = %s", state)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout not fired")
+	}
+
+	// 离开状态后计时失效
+	sm.Transition(StateIdle)
+	sm.Transition(StateProcessing)
+	sm.Transition(StateSpeaking)
+	select {
+	case state := <-fired:
+		t.Fatalf("unexpected timeout for %s after leaving state", state)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestOrchestratorProcessingTimeout(t *testing.T) {
+	cfg := DefaultOrchestratorConfig()
+	cfg.ProcessingTimeout = 100 * time.Millisecond
+	prompts := newMockPrompts(audio.PromptError)
+	// Agent 一直不返回任何事件
+	orch := NewOrchestratorWithConfig(&mockVoiceAgent{delay: time.Hour}, newMockOutPipe(), nil, nil, cfg)
+	orch.SetPrompts(prompts)
+	timeouts := make(chan *StateTimeoutEvent, 1)
+	SubscribeTyped(orch, EventTypeStateTimeout, func(e *StateTimeoutEvent) { timeouts <- e })
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	orch.OnASRFinal("今天天气怎么样")
+	select {
+	case e := <-timeouts:
+		if e.State != StateProcessing {
+			t.Fatalf("timeout state = %s", e.State)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("StateTimeoutEvent not published")
+	}
+	deadline := time.Now().Add(time.Second)
+	for orch.GetState() != StateIdle && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if state := orch.GetState(); state != StateIdle {
+		t.Fatalf("state = %s, want Idle", state)
+	}
+	if got := prompts.getPlayed(); !reflect.DeepEqual(got, []string{audio.PromptError}) {
+		t.Fatalf("played prompts = %v, want error prompt", got)
+	}
+}
+
+func TestOrchestratorApologyUrgent(t *testing.T) {
+	prompts := newMockPrompts(audio.PromptError)
+	outPipe := newUrgentOutPipe()
+	orch := NewOrchestratorWithConfig(&mockVoiceAgent{errs: []error{errors.New("invalid api key")}}, outPipe, nil, nil, DefaultOrchestratorConfig())
+	orch.SetPrompts(prompts)
+	errorEvents := make(chan *ErrorEvent, 1)
+	SubscribeTyped(orch, EventTypeError, func(e *ErrorEvent) { errorEvents <- e })
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	orch.OnASRFinal("今天天气怎么样")
+	select {
+	case <-errorEvents:
+	case <-time.After(time.Second):
+		t.Fatal("error event not published")
+	}
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if urgent, _ := outPipe.getUrgent(); len(urgent) > 0 {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// 致歉提示音作为紧急播报排入 TTS 队列，不再走提示音通道
+	urgent, clips := outPipe.getUrgent()
+	if !reflect.DeepEqual(urgent, []string{audio.PromptError}) || len(clips) != 1 || string(clips[0]) != audio.PromptError {
+		t.Fatalf("urgent = %v, clips = %q, want error prompt clip", urgent, clips)
+	}
+	if got := prompts.getPlayed(); len(got) != 0 {
+		t.Fatalf("played prompts = %v, want none", got)
+	}
+}
+
+func TestOrchestratorLLMErrorPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		errs         []error
+		wantRetrying []bool
+		wantPlayed   []string
+		wantReply    bool
+	}{
+		{
+			name:         "transient error retried",
+			errs:         []error{context.DeadlineExceeded},
+			wantRetrying: []bool{true},
+			wantReply:    true,
+		},
+		{
+			name:         "retries exhausted",
+			errs:         []error{context.DeadlineExceeded, context.DeadlineExceeded},
+			wantRetrying: []bool{true, false},
+			wantPlayed:   []string{audio.PromptError},
+		},
+		{
+			name:         "permanent error apologizes",
+			errs:         []error{errors.New("invalid api key")},
+			wantRetrying: []bool{false},
+			wantPlayed:   []string{audio.PromptError},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultOrchestratorConfig()
+			cfg.ErrorPolicy.RetryDelay = 20 * time.Millisecond
+			prompts := newMockPrompts(audio.PromptError)
+			outPipe := newMockOutPipe()
+			voiceAgent := &mockVoiceAgent{
+				errs: tt.errs,
+				events: []agent.AgentEvent{
+					&agent.TextChunkEvent{Chunk: "你好。"},
+					&agent.FinishedEvent{},
+				},
+			}
+			orch := NewOrchestratorWithConfig(voiceAgent, outPipe, nil, nil, cfg)
+			orch.SetPrompts(prompts)
+			errorEvents := make(chan *ErrorEvent, 4)
+			SubscribeTyped(orch, EventTypeError, func(e *ErrorEvent) { errorEvents <- e })
+			if err := orch.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			defer orch.Stop()
+
+			orch.OnASRFinal("今天天气怎么样")
+			for i, want := range tt.wantRetrying {
+				select {
+				case e := <-errorEvents:
+					if e.Source != ErrorSourceLLM || e.Retrying != want {
+						t.Fatalf("error event %d = %s retrying=%v, want llm retrying=%v", i, e.Source, e.Retrying, want)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("error event %d not published", i)
+				}
+			}
+			time.Sleep(100 * time.Millisecond)
+
+			if got := prompts.getPlayed(); !reflect.DeepEqual(got, tt.wantPlayed) {
+				t.Fatalf("played prompts = %v, want %v", got, tt.wantPlayed)
+			}
+			if replied := len(outPipe.getPlayed()) > 0; replied != tt.wantReply {
+				t.Fatalf("reply spoken = %v, want %v", replied, tt.wantReply)
+			}
+		})
+	}
+}
+
+func TestOrchestratorPropagatesTurnContext(t *testing.T) {
+	voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{&agent.FinishedEvent{}}}
+	orch := NewOrchestratorWithConfig(voiceAgent, newMockOutPipe(), nil, nil, DefaultOrchestratorConfig())
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	orch.OnASRFinal("你好")
+	orch.OnASRFinal("今天天气怎么样")
+	deadline := time.Now().Add(time.Second)
+	for len(voiceAgent.getTurns()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	turns := voiceAgent.getTurns()
+	if len(turns) != 2 {
+		t.Fatalf("agent called %d time(s), want 2", len(turns))
+	}
+	if turns[0] == 0 || turns[1] == 0 || turns[0] == turns[1] {
+		t.Fatalf("turn ids in agent ctx = %v, want distinct non-zero ids", turns)
+	}
+}
+
+func TestErrorHandlerApologyInterval(t *testing.T) {
+	h := newErrorHandler(ErrorPolicy{ApologyInterval: 10 * time.Second})
+	now := time.Now()
+	if !h.allowApology(now) {
+		t.Fatal("first apology not allowed")
+	}
+	if h.allowApology(now.Add(5 * time.Second)) {
+		t.Fatal("apology allowed within interval")
+	}
+	if !h.allowApology(now.Add(11 * time.Second)) {
+		t.Fatal("apology not allowed after interval")
+	}
+}
+
 func TestStateString(t *testing.T) {
 	tests := []struct {
 		state    State
@@ -207,3 +430,313 @@ func TestOrchestratorMarkdownFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestOrchestratorEmotionSwitchFlushesPendingText(t *testing.T) {
+	outPipe := newMockOutPipe()
+	orch := NewOrchestrator(nil, outPipe, nil, nil).(*orchestratorImpl)
+	orch.transitionTo(StateProcessing)
+
+	orch.handleAgentEvent(&agent.TextChunkEvent{Chunk: "好的，", Emotion: "default"})
+	orch.handleAgentEvent(&agent.EmotionChangedEvent{Emotion: "sad"})
+	orch.handleAgentEvent(&agent.TextChunkEvent{Chunk: "很抱歉没有找到。", Emotion: "sad"})
+
+	wantText := []string{"好的，", "很抱歉没有找到。"}
+	wantEmotions := []string{"default", "sad"}
+	if got := outPipe.getPlayed(); !reflect.DeepEqual(got, wantText) {
+		t.Fatalf("played = %v, want %v", got, wantText)
+	}
+	if got := outPipe.getEmotions(); !reflect.DeepEqual(got, wantEmotions) {
+		t.Fatalf("emotions = %v, want %v", got, wantEmotions)
+	}
+}
+
+func TestOrchestratorNormalizesTextBeforeTTS(t *testing.T) {
+	outPipe := newMockOutPipe()
+	orch := NewOrchestrator(nil, outPipe, nil, nil).(*orchestratorImpl)
+	orch.transitionTo(StateProcessing)
+
+	orch.handleAgentEvent(&agent.TextChunkEvent{Chunk: "北京今天**25°C**。", Emotion: "default"})
+
+	want := []string{"北京今天二十五摄氏度。"}
+	if got := outPipe.getPlayed(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("played = %v, want %v", got, want)
+	}
+
+	config := DefaultOrchestratorConfig()
+	config.NormalizeText = false
+	raw := newMockOutPipe()
+	orch = NewOrchestratorWithConfig(nil, raw, nil, nil, config).(*orchestratorImpl)
+	orch.transitionTo(StateProcessing)
+	orch.handleAgentEvent(&agent.TextChunkEvent{Chunk: "北京今天25°C。", Emotion: "default"})
+	if got := raw.getPlayed(); !reflect.DeepEqual(got, []string{"北京今天25°C。"}) {
+		t.Fatalf("played without normalization = %v", got)
+	}
+}
+
+func TestOrchestratorStreamFiltersCodeBlocks(t *testing.T) {
+	outPipe := newMockOutPipe()
+	orch := NewOrchestrator(nil, outPipe, nil, nil).(*orchestratorImpl)
+	orch.transitionTo(StateProcessing)
+
+	chunks := []string{"示例如下：\n```go\nfmt.Println(\"hi\")", "\nreturn nil\n```\n", "就是这样。"}
+	for _, chunk := range chunks {
+		orch.handleAgentEvent(&agent.TextChunkEvent{Chunk: chunk, Emotion: "default"})
+	}
+	orch.handleAgentEvent(&agent.FinishedEvent{})
+
+	want := []string{"示例如下：", "就是这样。"}
+	if got := outPipe.getPlayed(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("played = %v, want %v", got, want)
+	}
+}
+
+func TestOrchestratorSegmentFlushDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		flushDelay time.Duration
+		want       []string
+	}{
+		{name: "flush partial sentence on pause", flushDelay: 50 * time.Millisecond, want: []string{"今天北京的天气", "是晴天。"}},
+		{name: "disabled waits for punctuation", flushDelay: 0, want: []string{"今天北京的天气是晴天。"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultOrchestratorConfig()
+			cfg.SegmentFlushDelay = tt.flushDelay
+			voiceAgent := &mockVoiceAgent{
+				gap: 150 * time.Millisecond,
+				events: []agent.AgentEvent{
+					&agent.TextChunkEvent{Chunk: "今天北京的天气", Emotion: "default"},
+					&agent.TextChunkEvent{Chunk: "是晴天。", Emotion: "default"},
+					&agent.FinishedEvent{},
+				},
+			}
+			outPipe := newMockOutPipe()
+			orch := NewOrchestratorWithConfig(voiceAgent, outPipe, nil, nil, cfg)
+			if err := orch.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			orch.OnASRFinal("今天天气怎么样")
+			time.Sleep(500 * time.Millisecond)
+			orch.Stop()
+
+			if got := outPipe.getPlayed(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("played = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrchestratorDetectsLanguage(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		reply         string
+		wantLanguage  string
+		wantPlayed    string
+		detectEnabled bool
+		replyLanguage string
+	}{
+		{"english", "How much is it?", "It is 25% off.", "en", "It is 25 percent off.", true, ""},
+		{"chinese", "多少钱", "打25%的折扣。", "zh", "打百分之二十五的折扣。", true, ""},
+		{"disabled", "How much is it?", "打折。", "", "打折。", false, ""},
+		{"fixed reply language", "多少钱", "It is 25% off.", "", "It is 25 percent off.", true, "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultOrchestratorConfig()
+			cfg.DetectLanguage = tt.detectEnabled
+			cfg.ReplyLanguage = tt.replyLanguage
+			voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{
+				&agent.TextChunkEvent{Chunk: tt.reply, Emotion: "default"},
+				&agent.FinishedEvent{},
+			}}
+			outPipe := newMockOutPipe()
+			orch := NewOrchestratorWithConfig(voiceAgent, outPipe, nil, nil, cfg)
+			if err := orch.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			orch.OnASRFinal(tt.input)
+			time.Sleep(200 * time.Millisecond)
+			orch.Stop()
+
+			if got := voiceAgent.getLanguages(); !reflect.DeepEqual(got, []string{tt.wantLanguage}) {
+				t.Errorf("agent languages = %v, want [%s]", got, tt.wantLanguage)
+			}
+			if got := outPipe.getPlayed(); !reflect.DeepEqual(got, []string{tt.wantPlayed}) {
+				t.Errorf("played = %v, want [%s]", got, tt.wantPlayed)
+			}
+			var wantTTSLanguages []string
+			if tt.wantLanguage != "" {
+				wantTTSLanguages = []string{tt.wantLanguage}
+			}
+			if tt.replyLanguage != "" {
+				wantTTSLanguages = []string{tt.replyLanguage}
+			}
+			if got := outPipe.getLanguages(); !reflect.DeepEqual(got, wantTTSLanguages) {
+				t.Errorf("tts languages = %v, want %v", got, wantTTSLanguages)
+			}
+		})
+	}
+}
+
+func TestOrchestratorPrompts(t *testing.T) {
+	outPipe := newMockOutPipe()
+	prompts := newMockPrompts(audio.PromptError)
+	orch := NewOrchestrator(nil, outPipe, nil, nil).(*orchestratorImpl)
+	orch.SetPrompts(prompts)
+
+	orch.playPrompt(audio.PromptError)
+	orch.playPrompt(audio.PromptWake) // 未加载，忽略
+	if got := prompts.getPlayed(); !reflect.DeepEqual(got, []string{audio.PromptError}) {
+		t.Fatalf("played prompts = %v", got)
+	}
+
+	// 打断时停止正在播放的提示音
+	orch.transitionTo(StateProcessing)
+	orch.handleUserSpeakingDetected(NewUserSpeakingDetectedEvent())
+	if prompts.getStops() != 1 {
+		t.Fatalf("expected prompt stopped on interrupt, got %d stops", prompts.getStops())
+	}
+}
+
+func TestOrchestratorRecognizerStatus(t *testing.T) {
+	prompts := newMockPrompts(audio.PromptError)
+	orch := NewOrchestrator(nil, newMockOutPipe(), nil, nil).(*orchestratorImpl)
+	orch.SetPrompts(prompts)
+
+	orch.handleRecognizerStatus(NewRecognizerStatusEvent(false, errors.New("connection reset")))
+	orch.handleRecognizerStatus(NewRecognizerStatusEvent(true, nil))
+
+	if got := prompts.getPlayed(); !reflect.DeepEqual(got, []string{audio.PromptError}) {
+		t.Fatalf("played prompts = %v, want only error prompt on outage", got)
+	}
+}
+
+func TestOrchestratorPublishesPartialTranscripts(t *testing.T) {
+	inPipe := &simInPipe{}
+	orch := NewOrchestrator(&mockVoiceAgent{}, newMockOutPipe(), inPipe, nil)
+	partials := make(chan *PartialTranscriptEvent, 8)
+	orch.Subscribe(EventTypePartialTranscript, func(event Event) {
+		partials <- event.(*PartialTranscriptEvent)
+	})
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	inPipe.emit("帮我", false)
+	inPipe.emit("帮我查天气", false)
+	inPipe.emit("帮我查天气。", true)
+	time.Sleep(10 * time.Millisecond)
+	inPipe.emit("明天", false)
+
+	got := make(map[string]*PartialTranscriptEvent)
+	for len(got) < 3 {
+		select {
+		case e := <-partials:
+			got[e.Text] = e
+		case <-time.After(time.Second):
+			t.Fatalf("expected 3 partial transcripts, got %d", len(got))
+		}
+	}
+	if !got["帮我"].UtteranceStart.Equal(got["帮我查天气"].UtteranceStart) {
+		t.Fatal("partials of one utterance should share UtteranceStart")
+	}
+	if !got["明天"].UtteranceStart.After(got["帮我"].UtteranceStart) {
+		t.Fatal("expected a new UtteranceStart after final")
+	}
+	if got["帮我查天气"].Timestamp().Before(got["帮我查天气"].UtteranceStart) {
+		t.Fatal("partial timestamp before utterance start")
+	}
+}
+
+func TestOrchestratorStopGracefully(t *testing.T) {
+	tests := []struct {
+		name          string
+		finishPlaying bool
+		timeout       time.Duration
+	}{
+		{"waits for playback", true, 2 * time.Second},
+		{"stops at deadline", false, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outPipe := newMockOutPipe()
+			orch := NewOrchestrator(nil, outPipe, nil, nil).(*orchestratorImpl)
+			orch.transitionTo(StateProcessing)
+			orch.handleAgentEvent(&agent.TextChunkEvent{Chunk: "好的。"})
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+			done := make(chan error, 1)
+			go func() { done <- orch.StopGracefully(ctx) }()
+
+			time.Sleep(3 * drainPollInterval)
+			// 排空期间忽略新的输入与打断（voiceAgent 为 nil，若未忽略会 panic）
+			orch.handleASRFinal(NewASRFinalEvent("新问题"))
+			orch.handleUserSpeakingDetected(NewUserSpeakingDetectedEvent())
+			if outPipe.interrupts != 0 {
+				t.Fatalf("interrupts = %d during drain, want 0", outPipe.interrupts)
+			}
+
+			if tt.finishPlaying {
+				select {
+				case <-done:
+					t.Fatal("StopGracefully() returned before playback finished")
+				default:
+				}
+				orch.onTTSPlaybackFinished()
+			}
+
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatalf("StopGracefully() error = %v", err)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("StopGracefully() did not return")
+			}
+		})
+	}
+}
+
+func TestOrchestratorPlaysAgentAudioChunks(t *testing.T) {
+	voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{
+		&agent.AudioChunkEvent{Text: "现在是", PCM: []byte("a")},
+		&agent.AudioChunkEvent{Text: "十点半。", PCM: []byte("b")},
+		&agent.FinishedEvent{},
+	}}
+	outPipe := &clipOutPipe{mockOutPipe: newMockOutPipe()}
+	var replyText []string
+	config := DefaultOrchestratorConfig()
+	config.OnReplyText = func(text string) { replyText = append(replyText, text) }
+	orch := NewOrchestratorWithConfig(voiceAgent, outPipe, nil, nil, config).(*orchestratorImpl)
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	orch.handleASRFinal(NewASRFinalEvent("现在几点"))
+	waitForTurns(t, voiceAgent, 1)
+	orch.wg.Wait()
+
+	if got := outPipe.getClips(); !reflect.DeepEqual(got, []string{"现在是|a", "十点半。|b"}) {
+		t.Fatalf("clips = %v", got)
+	}
+	if got := outPipe.getPlayed(); len(got) != 0 {
+		t.Fatalf("TTS played = %v, want none", got)
+	}
+	if !reflect.DeepEqual(replyText, []string{"现在是", "十点半。"}) {
+		t.Fatalf("reply text = %v", replyText)
+	}
+	if state := orch.GetState(); state != StateSpeaking {
+		t.Fatalf("state = %s, want Speaking until clips finish", state)
+	}
+	outPipe.finish("", 2, "")
+	if state := orch.GetState(); state != StateIdle {
+		t.Fatalf("state = %s, want Idle", state)
+	}
+}
@@ -0,0 +1,122 @@
+package voicebot
+
+import (
+	"strings"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// replyClip 一段完整播放过的回复音频（Mixer 格式），对应连续的 sentences 句
+type replyClip struct {
+	sentences int
+	pcm       []byte
+}
+
+// replyCache 最近一次播放完的回复：原文按句保存；每句都有完整音频时可直接重播，不再调用 TTS
+type replyCache struct {
+	sentences []string
+	clips     []replyClip
+}
+
+// Text 返回回复全文
+func (c *replyCache) Text() string {
+	return strings.Join(c.sentences, "")
+}
+
+// audioClips 返回按音频段拼好的原文与音频；有句子缺少音频（合成失败或未开启录制）时返回 false
+func (c *replyCache) audioClips() (texts []string, clips [][]byte, ok bool) {
+	next := 0
+	for _, clip := range c.clips {
+		end := next + clip.sentences
+		if end > len(c.sentences) {
+			return nil, nil, false
+		}
+		texts = append(texts, strings.Join(c.sentences[next:end], ""))
+		clips = append(clips, clip.pcm)
+		next = end
+	}
+	if next == 0 || next != len(c.sentences) {
+		return nil, nil, false
+	}
+	return texts, clips, true
+}
+
+// onTTSAudio 记录本轮完整播放的 TTS 音频，供重播时直接使用
+func (o *orchestratorImpl) onTTSAudio(text string, sentences int, pcm []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.reply.Recorded(sentences, pcm)
+}
+
+// cacheReplyLocked 本轮已送入 TTS 的句子都播放完时保存为最近一次回复，调用方需持有 o.mu
+func (o *orchestratorImpl) cacheReplyLocked() {
+	if cache := o.reply.Cache(); cache != nil {
+		o.lastReply = cache
+	}
+}
+
+// RepeatLastReply 从头重播最近一次回复：被打断或尚未播放完的回复重新合成完整内容，
+// 完整播放过且缓存了音频的回复直接播放缓存；没有可重复的内容时返回 false
+func (o *orchestratorImpl) RepeatLastReply() bool {
+	o.mu.Lock()
+	last := o.lastReply
+	switch {
+	case o.lastInterruption != nil:
+		last = &replyCache{sentences: []string{o.lastInterruption.FullText}}
+	case o.reply.Cache() == nil && o.reply.Text() != "":
+		// 本轮回复还没播放完
+		last = &replyCache{sentences: []string{o.reply.Text()}}
+	}
+	if last == nil || strings.TrimSpace(last.Text()) == "" {
+		o.mu.Unlock()
+		return false
+	}
+	o.lastInterruption = nil
+	o.reply.Reset()
+	o.takeHeldLocked()
+	o.mu.Unlock()
+
+	// 上一轮可能仍在播放，先停止再从头播报
+	o.stopReply()
+	o.transitionTo(StateProcessing)
+
+	player, canReplay := o.audioOutPipe.(audio.AudioClipPlayer)
+	if texts, clips, ok := last.audioClips(); ok && canReplay && o.config.ReplayAudio {
+		logging.Infof("Orchestrator: repeating last reply from cached audio (%d clip(s))", len(clips))
+		for i := range clips {
+			if err := o.enqueueAudio(player, texts[i], clips[i]); err != nil {
+				// 无法播放缓存音频时其余部分重新合成
+				o.speakText(strings.Join(texts[i:], ""))
+				break
+			}
+		}
+		return true
+	}
+	logging.Infof("Orchestrator: repeating last reply via TTS")
+	o.speakText(last.Text())
+	return true
+}
+
+// enqueueAudio 把缓存的音频作为一句送入播放队列，与 enqueueTTS 一样记录播放进度
+func (o *orchestratorImpl) enqueueAudio(player audio.AudioClipPlayer, sentence string, pcm []byte) error {
+	o.mu.Lock()
+	turnCtx := o.agentCtx
+	o.mu.Unlock()
+	if turnCtx == nil {
+		turnCtx = o.ctx
+	}
+
+	if err := player.PlayAudioClip(turnCtx, sentence, pcm); err != nil {
+		logging.Errorf("Orchestrator: PlayAudioClip error: %v", err)
+		return err
+	}
+	o.spoken.add(time.Now(), sentence)
+	o.mu.Lock()
+	o.ttsPendingCount++
+	o.reply.Enqueued(sentence)
+	o.mu.Unlock()
+	o.transitionTo(StateSpeaking)
+	return nil
+}
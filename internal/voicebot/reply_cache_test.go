@@ -0,0 +1,113 @@
+package voicebot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+// clipOutPipe 支持录制与重播音频的 mockOutPipe，测试中手动模拟播放完成
+type clipOutPipe struct {
+	*mockOutPipe
+	onAudio audio.TTSAudioCallback
+	clips   []string
+}
+
+func (p *clipOutPipe) SetOnTTSAudio(callback audio.TTSAudioCallback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onAudio = callback
+}
+
+func (p *clipOutPipe) PlayAudioClip(ctx context.Context, text string, pcm []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.clips = append(p.clips, text+"|"+string(pcm))
+	return nil
+}
+
+// finish 模拟一段 TTS 完整播放：先回调音频，再按句回调播放完成
+func (p *clipOutPipe) finish(text string, sentences int, pcm string) {
+	p.mu.Lock()
+	onAudio, onFinished := p.onAudio, p.onFinished
+	p.mu.Unlock()
+	if onAudio != nil {
+		onAudio(text, sentences, []byte(pcm))
+	}
+	for i := 0; i < sentences; i++ {
+		onFinished()
+	}
+}
+
+func (p *clipOutPipe) getClips() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.clips...)
+}
+
+func TestReplyCacheAudioClips(t *testing.T) {
+	tests := []struct {
+		name      string
+		cache     replyCache
+		wantTexts []string
+		wantOK    bool
+	}{
+		{"merged and single", replyCache{
+			sentences: []string{"一。", "二。", "三。"},
+			clips:     []replyClip{{sentences: 2, pcm: []byte("a")}, {sentences: 1, pcm: []byte("b")}},
+		}, []string{"一。二。", "三。"}, true},
+		{"missing audio", replyCache{
+			sentences: []string{"一。", "二。"},
+			clips:     []replyClip{{sentences: 1, pcm: []byte("a")}},
+		}, nil, false},
+		{"no audio", replyCache{sentences: []string{"一。"}}, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			texts, _, ok := tt.cache.audioClips()
+			if ok != tt.wantOK || !reflect.DeepEqual(texts, tt.wantTexts) {
+				t.Fatalf("audioClips() = (%v, %v), want (%v, %v)", texts, ok, tt.wantTexts, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestRepeatLastReplyFromCachedAudio(t *testing.T) {
+	voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{
+		&agent.TextChunkEvent{Chunk: "今天晴。气温二十度。"},
+		&agent.FinishedEvent{},
+	}}
+	outPipe := &clipOutPipe{mockOutPipe: newMockOutPipe()}
+	orch := NewOrchestratorWithConfig(voiceAgent, outPipe, nil, nil, DefaultOrchestratorConfig()).(*orchestratorImpl)
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	if orch.RepeatLastReply() {
+		t.Fatalf("RepeatLastReply() = true before any reply")
+	}
+	orch.handleASRFinal(NewASRFinalEvent("今天天气怎么样"))
+	waitForTurns(t, voiceAgent, 1)
+	orch.wg.Wait()
+	outPipe.finish("今天晴。气温二十度。", 2, "pcm")
+
+	if !orch.RepeatLastReply() {
+		t.Fatalf("RepeatLastReply() = false, want true")
+	}
+	if got := outPipe.getClips(); !reflect.DeepEqual(got, []string{"今天晴。气温二十度。|pcm"}) {
+		t.Fatalf("clips = %v, want cached audio", got)
+	}
+	if got := outPipe.getPlayed(); len(got) != 2 {
+		t.Fatalf("TTS played = %v, want only the original reply", got)
+	}
+
+	// 重播完成后仍可再次重播
+	outPipe.finish("今天晴。气温二十度。", 1, "pcm")
+	if !orch.RepeatLastReply() || len(outPipe.getClips()) != 2 {
+		t.Fatalf("second repeat clips = %v, want 2", outPipe.getClips())
+	}
+}
@@ -0,0 +1,92 @@
+package voicebot
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// ReplyLimit 单轮回复的播报上限：超过后不再送入 TTS，播报 FollowUp 询问用户是否继续。
+// 超出部分保留为打断上下文，用户说“继续”时（ResumeInterrupted）接着播报，或由 Agent 接着讲
+type ReplyLimit struct {
+	// MaxSentences 每轮最多播报的句数，0 表示不限制
+	MaxSentences int
+
+	// MaxChars 每轮最多播报的字数，0 表示不限制；第一句总会播报
+	MaxChars int
+
+	// FollowUp 截断后播报的追问（如“需要我继续吗？”），为空时直接停止
+	FollowUp string
+}
+
+func (l ReplyLimit) enabled() bool {
+	return l.MaxSentences > 0 || l.MaxChars > 0
+}
+
+// exceeded 已播报 sentences 句、chars 字时，再加入 next 是否超过上限
+func (l ReplyLimit) exceeded(sentences, chars int, next string) bool {
+	if sentences == 0 {
+		return false
+	}
+	if l.MaxSentences > 0 && sentences >= l.MaxSentences {
+		return true
+	}
+	return l.MaxChars > 0 && chars+utf8.RuneCountInString(next) > l.MaxChars
+}
+
+// holdOverLimit 本轮回复达到 ReplyLimit 后截留句子而不送入 TTS；
+// 首次超限时播报追问并发布 ReplyTruncatedEvent，返回 true 表示句子已被截留
+func (o *orchestratorImpl) holdOverLimit(sentence string) bool {
+	limit := o.config.ReplyLimit
+	if !limit.enabled() {
+		return false
+	}
+
+	o.mu.Lock()
+	if o.truncated {
+		o.held = append(o.held, sentence)
+		o.mu.Unlock()
+		return true
+	}
+	sentences, chars := o.reply.Size()
+	if !limit.exceeded(sentences, chars, sentence) {
+		o.mu.Unlock()
+		return false
+	}
+	o.truncated = true
+	o.held = []string{sentence}
+	o.mu.Unlock()
+
+	logging.Infof("Orchestrator: reply reached limit (%d sentences, %d chars), holding the rest", sentences, chars)
+	o.eventBus.Publish(NewReplyTruncatedEvent(sentences, chars))
+	if limit.FollowUp != "" {
+		_ = o.enqueueTTS(limit.FollowUp)
+	}
+	return true
+}
+
+// finishTruncatedReply Agent 结束时把截留的内容记为打断上下文，供“继续”恢复或下一轮 Agent 接着讲
+func (o *orchestratorImpl) finishTruncatedReply() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !o.truncated || len(o.held) == 0 {
+		return
+	}
+	spoken := o.reply.Text()
+	o.lastInterruption = &agent.Interruption{
+		SpokenText:    spoken,
+		FullText:      spoken + strings.Join(o.held, ""),
+		InterruptedAt: utf8.RuneCountInString(spoken),
+		Truncated:     true,
+	}
+}
+
+// takeHeldLocked 取出并清空截留的句子，调用方需持有 o.mu
+func (o *orchestratorImpl) takeHeldLocked() string {
+	held := strings.Join(o.held, "")
+	o.held = nil
+	o.truncated = false
+	return held
+}
@@ -0,0 +1,82 @@
+package voicebot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+)
+
+func TestReplyLimitExceeded(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     ReplyLimit
+		sentences int
+		chars     int
+		next      string
+		want      bool
+	}{
+		{"first sentence always spoken", ReplyLimit{MaxChars: 5}, 0, 0, "这是一句很长的话。", false},
+		{"under sentence limit", ReplyLimit{MaxSentences: 3}, 2, 20, "好的。", false},
+		{"sentence limit reached", ReplyLimit{MaxSentences: 3}, 3, 20, "好的。", true},
+		{"char limit exceeded", ReplyLimit{MaxChars: 20}, 2, 18, "好的。", true},
+		{"char limit not exceeded", ReplyLimit{MaxChars: 21}, 2, 18, "好的。", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limit.exceeded(tt.sentences, tt.chars, tt.next); got != tt.want {
+				t.Fatalf("exceeded() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplyLimitTruncatesAndResumes(t *testing.T) {
+	voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{
+		&agent.TextChunkEvent{Chunk: "第一点是早睡。第二点是早起。第三点是多运动。第四点是少熬夜。"},
+		&agent.FinishedEvent{},
+	}}
+	outPipe := newMockOutPipe()
+	cfg := DefaultOrchestratorConfig()
+	cfg.ResumeInterrupted = true
+	cfg.ReplyLimit = ReplyLimit{MaxSentences: 2, FollowUp: "需要我继续吗？"}
+	orch := NewOrchestratorWithConfig(voiceAgent, outPipe, nil, nil, cfg).(*orchestratorImpl)
+	truncated := make(chan *ReplyTruncatedEvent, 1)
+	SubscribeTyped(orch, EventTypeReplyTruncated, func(e *ReplyTruncatedEvent) { truncated <- e })
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	orch.handleASRFinal(NewASRFinalEvent("怎么保持健康"))
+	waitForTurns(t, voiceAgent, 1)
+	orch.wg.Wait()
+
+	want := []string{"第一点是早睡。", "第二点是早起。", "需要我继续吗？"}
+	if got := outPipe.getPlayed(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("played = %v, want %v", got, want)
+	}
+	select {
+	case e := <-truncated:
+		if e.Sentences != 2 {
+			t.Fatalf("truncated after %d sentences, want 2", e.Sentences)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected ReplyTruncatedEvent")
+	}
+	if orch.lastInterruption == nil || !orch.lastInterruption.Truncated {
+		t.Fatalf("expected truncated reply to be kept, got %+v", orch.lastInterruption)
+	}
+	if got := orch.lastInterruption.UnspokenText(); got != "第三点是多运动。第四点是少熬夜。" {
+		t.Fatalf("unspoken text = %q", got)
+	}
+
+	// 用户说“继续”，播报截留的内容
+	orch.handleASRFinal(NewASRFinalEvent("继续"))
+	want = append(want, "第三点是多运动。", "第四点是少熬夜。")
+	if got := outPipe.getPlayed(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("played after resume = %v, want %v", got, want)
+	}
+}
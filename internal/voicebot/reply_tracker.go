@@ -0,0 +1,88 @@
+package voicebot
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+)
+
+// replyTracker 记录当前轮次回复的播放进度
+// 每个送入 TTS 的句子按顺序记录，每次播放完成推进一次，
+// 打断时据此还原用户实际听到的内容（非并发安全，由 Orchestrator 加锁保护）
+type replyTracker struct {
+	sentences []string
+	played    int
+	clips     []replyClip // 已完整播放的 TTS 音频，按播放顺序对应 sentences
+}
+
+func newReplyTracker() *replyTracker {
+	return &replyTracker{}
+}
+
+// Enqueued 记录一个已送入 TTS 的句子
+func (t *replyTracker) Enqueued(sentence string) {
+	t.sentences = append(t.sentences, sentence)
+}
+
+// Played 标记下一个句子已播放完成（TTSPipeline 保证按入队顺序播放）
+func (t *replyTracker) Played() {
+	if t.played < len(t.sentences) {
+		t.played++
+	}
+}
+
+// Recorded 记录下一段完整播放的音频，覆盖 sentences 句；超出已入队句数的音频（上一轮残留）被丢弃
+func (t *replyTracker) Recorded(sentences int, pcm []byte) {
+	covered := 0
+	for _, clip := range t.clips {
+		covered += clip.sentences
+	}
+	if sentences <= 0 || covered+sentences > len(t.sentences) {
+		return
+	}
+	t.clips = append(t.clips, replyClip{sentences: sentences, pcm: pcm})
+}
+
+// Cache 返回已播放完的回复（文本与音频），尚有句子未播放或没有内容时返回 nil
+func (t *replyTracker) Cache() *replyCache {
+	if len(t.sentences) == 0 || t.played < len(t.sentences) {
+		return nil
+	}
+	return &replyCache{
+		sentences: append([]string(nil), t.sentences...),
+		clips:     append([]replyClip(nil), t.clips...),
+	}
+}
+
+// Snapshot 生成打断上下文
+// remainder 为分句器中尚未送入 TTS 的剩余文本
+func (t *replyTracker) Snapshot(remainder string) agent.Interruption {
+	spoken := strings.Join(t.sentences[:t.played], "")
+	full := strings.Join(t.sentences, "") + remainder
+	return agent.Interruption{
+		SpokenText:    spoken,
+		FullText:      full,
+		InterruptedAt: len([]rune(spoken)),
+	}
+}
+
+// Size 返回已送入 TTS 的句数和字数
+func (t *replyTracker) Size() (sentences, chars int) {
+	for _, sentence := range t.sentences {
+		chars += utf8.RuneCountInString(sentence)
+	}
+	return len(t.sentences), chars
+}
+
+// Text 返回已送入 TTS 的全部文本
+func (t *replyTracker) Text() string {
+	return strings.Join(t.sentences, "")
+}
+
+// Reset 开始新的轮次
+func (t *replyTracker) Reset() {
+	t.sentences = nil
+	t.played = 0
+	t.clips = nil
+}
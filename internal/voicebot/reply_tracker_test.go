@@ -0,0 +1,43 @@
+package voicebot
+
+import "testing"
+
+func TestReplyTrackerSnapshot(t *testing.T) {
+	tracker := newReplyTracker()
+	tracker.Enqueued("明天北京晴。")
+	tracker.Enqueued("最高气温二十五度。")
+	tracker.Enqueued("适合出门。")
+	tracker.Played()
+
+	interruption := tracker.Snapshot("记得带伞")
+	if interruption.SpokenText != "明天北京晴。" {
+		t.Fatalf("SpokenText = %q", interruption.SpokenText)
+	}
+	if interruption.FullText != "明天北京晴。最高气温二十五度。适合出门。记得带伞" {
+		t.Fatalf("FullText = %q", interruption.FullText)
+	}
+	if interruption.InterruptedAt != len([]rune("明天北京晴。")) {
+		t.Fatalf("InterruptedAt = %d", interruption.InterruptedAt)
+	}
+	if interruption.UnspokenText() != "最高气温二十五度。适合出门。记得带伞" {
+		t.Fatalf("UnspokenText() = %q", interruption.UnspokenText())
+	}
+}
+
+func TestReplyTrackerPlayedDoesNotOverrun(t *testing.T) {
+	tracker := newReplyTracker()
+	tracker.Played()
+	tracker.Enqueued("你好。")
+	tracker.Played()
+	tracker.Played()
+
+	interruption := tracker.Snapshot("")
+	if interruption.SpokenText != "你好。" {
+		t.Fatalf("SpokenText = %q", interruption.SpokenText)
+	}
+
+	tracker.Reset()
+	if !tracker.Snapshot("").IsEmpty() {
+		t.Fatalf("expected empty snapshot after Reset")
+	}
+}
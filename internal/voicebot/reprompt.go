@@ -0,0 +1,141 @@
+package voicebot
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// RepromptReason 识别结果被判定为不可靠的原因
+type RepromptReason string
+
+const (
+	RepromptLowConfidence RepromptReason = "low_confidence" // ASR 置信度过低
+	RepromptTooShort      RepromptReason = "too_short"      // 有效字符过少或只有语气词
+)
+
+// RepromptPolicy 识别结果不可靠时请用户再说一遍，而不是把噪声交给 LLM
+type RepromptPolicy struct {
+	// MinConfidence ASR 置信度低于该值时重问，0 表示不检查；ASR 未返回置信度时不检查
+	MinConfidence float64
+
+	// MinChars 去掉标点、空白和 Fillers 后的有效字符数少于该值时重问，0 表示不检查
+	MinChars int
+
+	// Fillers 不计入有效字符的语气词（如“呃”“um”），整句只有语气词时视为没听清
+	Fillers []string
+
+	// Prompt 重问提示音名称（如预合成的“不好意思，没听清，您再说一遍？”），未加载时只丢弃本句
+	Prompt string
+
+	// MaxConsecutive 连续重问的上限，达到后下一句不再拦截、直接交给 Agent，0 表示关闭重问
+	MaxConsecutive int
+}
+
+// DefaultRepromptPolicy 默认策略：置信度低于 0.4 或没有有效字符时重问，最多连续 2 次
+func DefaultRepromptPolicy() RepromptPolicy {
+	return RepromptPolicy{
+		MinConfidence:  0.4,
+		MinChars:       1,
+		Fillers:        []string{"呃", "额", "唔", "uh", "um", "erm", "hmm"},
+		Prompt:         audio.PromptRepeat,
+		MaxConsecutive: 2,
+	}
+}
+
+// check 返回 text 是否需要重问及原因；confidence 为 nil 表示 ASR 未返回置信度
+func (p RepromptPolicy) check(text string, confidence *float64) (RepromptReason, bool) {
+	if p.MaxConsecutive <= 0 {
+		return "", false
+	}
+	if p.MinChars > 0 && p.meaningfulChars(text) < p.MinChars {
+		return RepromptTooShort, true
+	}
+	if p.MinConfidence > 0 && confidence != nil && *confidence < p.MinConfidence {
+		return RepromptLowConfidence, true
+	}
+	return "", false
+}
+
+// meaningfulChars 统计去掉标点、空白和语气词后的字符数
+func (p RepromptPolicy) meaningfulChars(text string) int {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			// 非字母数字作为分隔，避免 "um hmm" 拼成一个词
+			b.WriteRune(' ')
+		}
+	}
+	cleaned := b.String()
+	for _, filler := range p.Fillers {
+		if filler = strings.ToLower(strings.TrimSpace(filler)); filler == "" {
+			continue
+		}
+		if isASCIIWord(filler) {
+			// 英文语气词按整词匹配，避免误删 "human" 中的 "um"
+			fields := strings.Fields(cleaned)
+			for i, field := range fields {
+				if field == filler {
+					fields[i] = ""
+				}
+			}
+			cleaned = strings.Join(fields, " ")
+		} else {
+			cleaned = strings.ReplaceAll(cleaned, filler, " ")
+		}
+	}
+	count := 0
+	for _, r := range cleaned {
+		if r != ' ' {
+			count++
+		}
+	}
+	return count
+}
+
+func isASCIIWord(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII {
+			return false
+		}
+	}
+	return true
+}
+
+// reprompt 判断本句是否需要重问：需要时播放重问提示音并返回 true。
+// 连续重问达到 MaxConsecutive 后放行下一句，任何一句被放行都会清零计数
+func (o *orchestratorImpl) reprompt(asrEvent *ASRFinalEvent) bool {
+	policy := o.config.Reprompt
+	reason, unclear := policy.check(asrEvent.Text, asrEvent.Confidence)
+
+	o.mu.Lock()
+	if !unclear || o.reprompts >= policy.MaxConsecutive {
+		if unclear {
+			logging.Infof("Orchestrator: %d consecutive reprompts, passing unclear utterance to Agent: %q", o.reprompts, asrEvent.Text)
+		}
+		o.reprompts = 0
+		o.mu.Unlock()
+		return false
+	}
+	o.reprompts++
+	count := o.reprompts
+	o.mu.Unlock()
+
+	if asrEvent.Confidence != nil {
+		logging.Infof("Orchestrator: unclear utterance (%s, confidence=%.2f), reprompting (%d/%d): %q",
+			reason, *asrEvent.Confidence, count, policy.MaxConsecutive, asrEvent.Text)
+	} else {
+		logging.Infof("Orchestrator: unclear utterance (%s), reprompting (%d/%d): %q",
+			reason, count, policy.MaxConsecutive, asrEvent.Text)
+	}
+	o.eventBus.Publish(NewRepromptEvent(asrEvent.Text, asrEvent.Confidence, reason, count))
+	o.transitionTo(StateIdle)
+	if policy.Prompt != "" {
+		o.playPrompt(policy.Prompt)
+	}
+	return true
+}
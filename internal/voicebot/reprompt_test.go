@@ -0,0 +1,107 @@
+package voicebot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+func TestRepromptPolicyCheck(t *testing.T) {
+	low, high := 0.2, 0.9
+	policy := DefaultRepromptPolicy()
+
+	tests := []struct {
+		name       string
+		text       string
+		confidence *float64
+		wantReason RepromptReason
+		want       bool
+	}{
+		{"clear", "今天天气怎么样", &high, "", false},
+		{"no confidence", "今天天气怎么样", nil, "", false},
+		{"low confidence", "今天天气怎么样", &low, RepromptLowConfidence, true},
+		{"punctuation only", "。？", nil, RepromptTooShort, true},
+		{"filler only", "呃……", &high, RepromptTooShort, true},
+		{"english fillers", "Um, hmm.", nil, RepromptTooShort, true},
+		{"english word containing filler", "human", nil, "", false},
+		{"short answer", "好", &high, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, got := policy.check(tt.text, tt.confidence)
+			if got != tt.want || reason != tt.wantReason {
+				t.Fatalf("check(%q) = (%q, %v), want (%q, %v)", tt.text, reason, got, tt.wantReason, tt.want)
+			}
+		})
+	}
+
+	policy.MaxConsecutive = 0
+	if _, got := policy.check("。", &low); got {
+		t.Fatalf("check() should never reprompt when MaxConsecutive is 0")
+	}
+}
+
+func TestRepromptLimitsConsecutiveReprompts(t *testing.T) {
+	voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{&agent.FinishedEvent{}}}
+	prompts := newMockPrompts(audio.PromptRepeat)
+	cfg := DefaultOrchestratorConfig()
+	orch := NewOrchestratorWithConfig(voiceAgent, newMockOutPipe(), nil, nil, cfg).(*orchestratorImpl)
+	orch.SetPrompts(prompts)
+	reprompts := make(chan *RepromptEvent, 4)
+	SubscribeTyped(orch, EventTypeReprompt, func(e *RepromptEvent) { reprompts <- e })
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	low := 0.1
+	unclear := func() *ASRFinalEvent {
+		event := NewASRFinalEvent("天汽怎")
+		event.Confidence = &low
+		return event
+	}
+
+	// 前两句重问，第三句达到上限后交给 Agent
+	for i := 1; i <= 3; i++ {
+		orch.handleASRFinal(unclear())
+	}
+	wantPlayed := []string{audio.PromptRepeat, audio.PromptRepeat}
+	if got := prompts.getPlayed(); !reflect.DeepEqual(got, wantPlayed) {
+		t.Fatalf("played prompts = %v, want %v", got, wantPlayed)
+	}
+	for i := 1; i <= 2; i++ {
+		select {
+		case e := <-reprompts:
+			if e.Count != i || e.Reason != RepromptLowConfidence {
+				t.Fatalf("reprompt event = %+v, want count %d and low confidence", e, i)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected reprompt event %d", i)
+		}
+	}
+	waitForTurns(t, voiceAgent, 1)
+
+	// 放行后计数清零，下一句没听清的话再次重问
+	orch.handleASRFinal(unclear())
+	if got := len(prompts.getPlayed()); got != 3 {
+		t.Fatalf("played %d prompts, want 3 after counter reset", got)
+	}
+	if got := len(voiceAgent.getTurns()); got != 1 {
+		t.Fatalf("agent turns = %d, want 1", got)
+	}
+}
+
+func waitForTurns(t *testing.T, voiceAgent *mockVoiceAgent, want int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for len(voiceAgent.getTurns()) < want {
+		if time.Now().After(deadline) {
+			t.Fatalf("agent turns = %d, want %d", len(voiceAgent.getTurns()), want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,89 @@
+package voicebot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+)
+
+func TestResumeInterrupted(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		run     func(t *testing.T, orch *orchestratorImpl, outPipe *mockOutPipe)
+	}{
+		{
+			name:    "replays unspoken text",
+			enabled: true,
+			run: func(t *testing.T, orch *orchestratorImpl, outPipe *mockOutPipe) {
+				if !orch.ResumeInterrupted() {
+					t.Fatalf("ResumeInterrupted() = false, want true")
+				}
+
+				want := []string{"最高气温二十五度。", "适合出门"}
+				if got := outPipe.getPlayed(); !reflect.DeepEqual(got, want) {
+					t.Fatalf("played = %v, want %v", got, want)
+				}
+				if orch.GetState() != StateSpeaking {
+					t.Fatalf("state = %s, want Speaking", orch.GetState())
+				}
+				if orch.ResumeInterrupted() {
+					t.Fatalf("expected interruption to be consumed after resume")
+				}
+			},
+		},
+		{
+			name:    "disabled",
+			enabled: false,
+			run: func(t *testing.T, orch *orchestratorImpl, outPipe *mockOutPipe) {
+				if orch.ResumeInterrupted() {
+					t.Fatalf("ResumeInterrupted() = true when disabled")
+				}
+				if len(outPipe.getPlayed()) != 0 {
+					t.Fatalf("expected nothing to be played")
+				}
+				if orch.lastInterruption == nil {
+					t.Fatalf("interruption should be kept as agent context when resume is disabled")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultOrchestratorConfig()
+			cfg.ResumeInterrupted = tt.enabled
+			orch, outPipe := newTestOrchestrator(t, &mockVoiceAgent{}, cfg)
+			orch.lastInterruption = &agent.Interruption{
+				SpokenText:    "今天北京晴。",
+				FullText:      "今天北京晴。最高气温二十五度。适合出门",
+				InterruptedAt: len([]rune("今天北京晴。")),
+			}
+			tt.run(t, orch, outPipe)
+		})
+	}
+}
+
+func TestIsResumeIntent(t *testing.T) {
+	orch := NewOrchestrator(nil, nil, nil, nil).(*orchestratorImpl)
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"继续。", true},
+		{"接着说吧", false},
+		{"Go on!", true},
+		{"You were saying?", true},
+		{"继续播放音乐", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := orch.isResumeIntent(tt.input); got != tt.want {
+				t.Errorf("isResumeIntent(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,508 @@
+package voicebot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/audio/source"
+)
+
+// Scenario 端到端回放脚本：按时间轴模拟用户说话，Agent 按脚本回复，TTS 按固定时长“播放”
+type Scenario struct {
+	Name       string
+	Utterances []Utterance
+	// Replies 按轮次顺序的 Agent 回复，轮次多于回复时重复最后一条，为空时回复“好的。”
+	Replies []ScriptedReply
+	// SentenceDuration 每句 TTS 的模拟播放时长，默认 200ms
+	SentenceDuration time.Duration
+	// Timeout 整个场景的最长运行时间，默认 10s
+	Timeout time.Duration
+	Expect  Expectations
+}
+
+// Utterance 一句用户输入
+type Utterance struct {
+	// At 相对场景开始、用户开始说话的时刻；早于上一句结束时紧接上一句
+	At   time.Duration
+	Text string
+	// Audio 可选的音频文件（WAV / PCM / MP3），说话时长取文件时长；ASR 结果仍为 Text
+	Audio string
+	// Duration 说话时长（没有 Audio 时使用），默认 500ms；期间每 100ms 产生一次中间识别结果
+	Duration time.Duration
+	// ExpectInterrupt 期望这句话打断正在进行的回复
+	ExpectInterrupt bool
+}
+
+// ScriptedReply 一轮脚本化的 Agent 回复
+type ScriptedReply struct {
+	Chunks []string
+	// Delay 首个文本块之前的延迟（模拟 LLM 首 token 延迟），Gap 为文本块之间的间隔
+	Delay time.Duration
+	Gap   time.Duration
+}
+
+// Expectations 场景断言，字段为空时不检查
+type Expectations struct {
+	// States 期望依次出现的状态（按顺序的子序列，允许中间有其他状态）
+	States []State
+	// Spoken 期望送入 TTS 的全部句子
+	Spoken []string
+	// Interruptions 期望的打断次数
+	Interruptions *int
+}
+
+// TimelineEntry 回放时间轴上的一条记录
+type TimelineEntry struct {
+	At     time.Duration // 相对场景开始
+	Kind   string
+	Detail string
+}
+
+// 时间轴记录类型
+const (
+	TimelineUserSpeaking = "user_speaking" // 中间识别结果
+	TimelineUserFinal    = "user_final"    // ASR final
+	TimelineState        = "state"
+	TimelineTTSEnqueue   = "tts_enqueue"
+	TimelineTTSPlayed    = "tts_played"
+	TimelineInterrupt    = "interrupt"
+)
+
+// SimulationResult 回放结果
+type SimulationResult struct {
+	Timeline      []TimelineEntry
+	States        []State  // 依次进入的状态
+	Spoken        []string // 送入 TTS 的句子
+	Played        []string // 完整播放完的句子
+	Interruptions []time.Duration
+}
+
+// Simulator 用脚本化的 ASR / Agent / TTS 驱动真实的 Orchestrator，回放场景并检查状态转换和 TTS 队列行为
+type Simulator struct {
+	config *OrchestratorConfig
+
+	start    time.Time
+	mu       sync.Mutex
+	timeline []TimelineEntry
+}
+
+// NewSimulator 创建回放器，config 为 nil 时使用默认 Orchestrator 配置
+func NewSimulator(config *OrchestratorConfig) *Simulator {
+	if config == nil {
+		config = DefaultOrchestratorConfig()
+	}
+	return &Simulator{config: config}
+}
+
+// Run 回放场景，返回时间轴与汇总结果；期望不满足时返回的 error 列出所有不符合项
+func (s *Simulator) Run(ctx context.Context, scenario Scenario) (*SimulationResult, error) {
+	if scenario.SentenceDuration <= 0 {
+		scenario.SentenceDuration = 200 * time.Millisecond
+	}
+	if scenario.Timeout <= 0 {
+		scenario.Timeout = 10 * time.Second
+	}
+	utterances, err := prepareUtterances(scenario.Utterances)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, scenario.Timeout)
+	defer cancel()
+
+	s.mu.Lock()
+	s.start = time.Now()
+	s.timeline = nil
+	s.mu.Unlock()
+
+	inPipe := &simInPipe{}
+	outPipe := newSimOutPipe(s, scenario.SentenceDuration)
+	simAgent := &simAgent{replies: scenario.Replies}
+
+	orch := NewOrchestratorWithConfig(simAgent, outPipe, inPipe, nil, s.config)
+	orch.Subscribe(EventTypeStateChanged, func(event Event) {
+		if e, ok := event.(*StateChangedEvent); ok {
+			s.recordAt(e.Timestamp(), TimelineState, e.NewState.String())
+		}
+	})
+	if err := orch.Start(ctx); err != nil {
+		return nil, fmt.Errorf("start orchestrator: %w", err)
+	}
+	defer orch.Stop()
+
+	for _, u := range utterances {
+		if err := s.speak(ctx, inPipe, u); err != nil {
+			return s.result(), fmt.Errorf("scenario %q: %w", scenario.Name, err)
+		}
+	}
+	if err := s.waitSettled(ctx, orch, outPipe); err != nil {
+		return s.result(), fmt.Errorf("scenario %q: %w", scenario.Name, err)
+	}
+
+	result := s.result()
+	return result, result.verify(scenario.Expect, utterances)
+}
+
+// prepareUtterances 补全说话时长（音频文件取文件时长）并按开始时间排序
+func prepareUtterances(utterances []Utterance) ([]Utterance, error) {
+	prepared := make([]Utterance, len(utterances))
+	copy(prepared, utterances)
+	for i := range prepared {
+		u := &prepared[i]
+		if u.Audio != "" {
+			cfg := source.DefaultFileConfig()
+			samples, err := source.DecodeFile(u.Audio, cfg)
+			if err != nil {
+				return nil, err
+			}
+			u.Duration = time.Duration(len(samples)) * time.Second / time.Duration(cfg.SampleRate)
+		}
+		if u.Duration <= 0 {
+			u.Duration = 500 * time.Millisecond
+		}
+	}
+	sort.SliceStable(prepared, func(i, j int) bool { return prepared[i].At < prepared[j].At })
+	return prepared, nil
+}
+
+// speak 模拟一句话：说话期间每 100ms 产生一次逐渐变长的中间结果，结束时产生 final
+func (s *Simulator) speak(ctx context.Context, inPipe *simInPipe, u Utterance) error {
+	if err := s.sleepUntil(ctx, u.At); err != nil {
+		return err
+	}
+	runes := []rune(u.Text)
+	const interimInterval = 100 * time.Millisecond
+	steps := int(u.Duration / interimInterval)
+	for i := 1; i <= steps; i++ {
+		partial := string(runes[:len(runes)*i/(steps+1)])
+		if partial != "" {
+			s.record(TimelineUserSpeaking, partial)
+			inPipe.emit(partial, false)
+		}
+		if err := sleepContext(ctx, interimInterval); err != nil {
+			return err
+		}
+	}
+	if rest := u.Duration - time.Duration(steps)*interimInterval; rest > 0 {
+		if err := sleepContext(ctx, rest); err != nil {
+			return err
+		}
+	}
+	s.record(TimelineUserFinal, u.Text)
+	inPipe.emit(u.Text, true)
+	return nil
+}
+
+// waitSettled 等待 Orchestrator 回到 Idle 且 TTS 队列清空，并保持一小段时间
+func (s *Simulator) waitSettled(ctx context.Context, orch Orchestrator, outPipe *simOutPipe) error {
+	const poll = 20 * time.Millisecond
+	stable := 0
+	for stable < 3 {
+		if err := sleepContext(ctx, poll); err != nil {
+			return fmt.Errorf("did not settle (state=%s): %w", orch.GetState(), err)
+		}
+		if orch.GetState() == StateIdle && !outPipe.busy() {
+			stable++
+		} else {
+			stable = 0
+		}
+	}
+	return nil
+}
+
+func (s *Simulator) sleepUntil(ctx context.Context, at time.Duration) error {
+	s.mu.Lock()
+	deadline := s.start.Add(at)
+	s.mu.Unlock()
+	return sleepContext(ctx, time.Until(deadline))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Simulator) record(kind, detail string) {
+	s.recordAt(time.Now(), kind, detail)
+}
+
+func (s *Simulator) recordAt(at time.Time, kind, detail string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timeline = append(s.timeline, TimelineEntry{At: at.Sub(s.start), Kind: kind, Detail: detail})
+}
+
+// result 按时间排序时间轴并汇总（状态事件由事件总线异步投递，需按事件时间重排）
+func (s *Simulator) result() *SimulationResult {
+	s.mu.Lock()
+	timeline := append([]TimelineEntry(nil), s.timeline...)
+	s.mu.Unlock()
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].At < timeline[j].At })
+
+	result := &SimulationResult{Timeline: timeline}
+	for _, entry := range timeline {
+		switch entry.Kind {
+		case TimelineState:
+			result.States = append(result.States, parseState(entry.Detail))
+		case TimelineTTSEnqueue:
+			result.Spoken = append(result.Spoken, entry.Detail)
+		case TimelineTTSPlayed:
+			result.Played = append(result.Played, entry.Detail)
+		case TimelineInterrupt:
+			result.Interruptions = append(result.Interruptions, entry.At)
+		}
+	}
+	return result
+}
+
+func parseState(name string) State {
+	for _, state := range []State{StateIdle, StateListening, StateProcessing, StateSpeaking} {
+		if state.String() == name {
+			return state
+		}
+	}
+	return State(-1)
+}
+
+// verify 检查期望，返回所有不符合项
+func (r *SimulationResult) verify(expect Expectations, utterances []Utterance) error {
+	var errs []error
+	if len(expect.States) > 0 && !isSubsequence(expect.States, r.States) {
+		errs = append(errs, fmt.Errorf("states %v do not contain %v in order", r.States, expect.States))
+	}
+	if expect.Spoken != nil && !reflect.DeepEqual(r.Spoken, expect.Spoken) {
+		errs = append(errs, fmt.Errorf("spoken %q, want %q", r.Spoken, expect.Spoken))
+	}
+	if expect.Interruptions != nil && len(r.Interruptions) != *expect.Interruptions {
+		errs = append(errs, fmt.Errorf("interruptions = %d, want %d", len(r.Interruptions), *expect.Interruptions))
+	}
+	for _, u := range utterances {
+		if !u.ExpectInterrupt {
+			continue
+		}
+		if !r.interruptedWithin(u.At, u.At+u.Duration) {
+			errs = append(errs, fmt.Errorf("utterance %q at %v did not interrupt the reply", u.Text, u.At))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *SimulationResult) interruptedWithin(from, to time.Duration) bool {
+	for _, at := range r.Interruptions {
+		if at >= from && at <= to {
+			return true
+		}
+	}
+	return false
+}
+
+func isSubsequence(want, got []State) bool {
+	i := 0
+	for _, state := range got {
+		if i < len(want) && state == want[i] {
+			i++
+		}
+	}
+	return i == len(want)
+}
+
+// simInPipe 脚本化的 AudioInPipe，由 Simulator 直接产生识别结果
+type simInPipe struct {
+	mu         sync.Mutex
+	asrHandler func(text string, isFinal bool)
+}
+
+func (p *simInPipe) emit(text string, isFinal bool) {
+	p.mu.Lock()
+	handler := p.asrHandler
+	p.mu.Unlock()
+	if handler != nil {
+		handler(text, isFinal)
+	}
+}
+
+func (p *simInPipe) Start(ctx context.Context) error { return nil }
+func (p *simInPipe) Stop() error                     { return nil }
+func (p *simInPipe) SendAudio(audio []byte) error    { return nil }
+func (p *simInPipe) OnASRResult(handler func(text string, isFinal bool)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.asrHandler = handler
+}
+func (p *simInPipe) OnUserSpeakingDetected(handler func())                      {}
+func (p *simInPipe) OnASRUsage(handler func(durationSec int))                   {}
+func (p *simInPipe) OnRecognizerStatus(handler func(available bool, err error)) {}
+
+// simAgent 按轮次返回脚本化回复的 VoiceAgent
+type simAgent struct {
+	mu      sync.Mutex
+	turn    int
+	replies []ScriptedReply
+}
+
+func (a *simAgent) Process(ctx context.Context, input string) (<-chan agent.AgentEvent, error) {
+	a.mu.Lock()
+	reply := ScriptedReply{Chunks: []string{"好的。"}}
+	if len(a.replies) > 0 {
+		index := a.turn
+		if index >= len(a.replies) {
+			index = len(a.replies) - 1
+		}
+		reply = a.replies[index]
+	}
+	a.turn++
+	a.mu.Unlock()
+
+	ch := make(chan agent.AgentEvent, len(reply.Chunks)+1)
+	go func() {
+		defer close(ch)
+		if sleepContext(ctx, reply.Delay) != nil {
+			return
+		}
+		for i, chunk := range reply.Chunks {
+			if i > 0 && sleepContext(ctx, reply.Gap) != nil {
+				return
+			}
+			ch <- &agent.TextChunkEvent{Chunk: chunk, Emotion: "default"}
+		}
+		ch <- &agent.FinishedEvent{}
+	}()
+	return ch, nil
+}
+
+func (a *simAgent) GetToolType(tool string) agent.ToolType { return agent.ToolTypeQuery }
+
+// simOutPipe 模拟 TTS 播放队列：每句按固定时长顺序播放，打断时清空队列并停止当前句
+type simOutPipe struct {
+	sim      *Simulator
+	duration time.Duration
+
+	mu         sync.Mutex
+	queue      []string
+	playing    bool
+	stop       chan struct{} // 当前句的停止信号
+	wake       chan struct{}
+	onFinished audio.PlaybackFinishedCallback
+	cancel     context.CancelFunc
+}
+
+func newSimOutPipe(sim *Simulator, duration time.Duration) *simOutPipe {
+	return &simOutPipe{sim: sim, duration: duration, wake: make(chan struct{}, 1)}
+}
+
+func (p *simOutPipe) Start(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	p.mu.Lock()
+	p.cancel = cancel
+	p.mu.Unlock()
+	go p.run(ctx)
+	return nil
+}
+
+func (p *simOutPipe) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cancel != nil {
+		p.cancel()
+	}
+	return nil
+}
+
+func (p *simOutPipe) run(ctx context.Context) {
+	for {
+		p.mu.Lock()
+		if len(p.queue) == 0 {
+			p.playing = false
+			p.mu.Unlock()
+			select {
+			case <-p.wake:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		text := p.queue[0]
+		p.queue = p.queue[1:]
+		stop := make(chan struct{})
+		p.stop = stop
+		p.playing = true
+		p.mu.Unlock()
+
+		timer := time.NewTimer(p.duration)
+		select {
+		case <-timer.C:
+			p.mu.Lock()
+			p.stop = nil
+			callback := p.onFinished
+			p.mu.Unlock()
+			p.sim.record(TimelineTTSPlayed, text)
+			if callback != nil {
+				callback()
+			}
+		case <-stop:
+			timer.Stop()
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+	}
+}
+
+func (p *simOutPipe) busy() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.playing || len(p.queue) > 0
+}
+
+func (p *simOutPipe) PlayTTS(text string, emotion string) error {
+	p.sim.record(TimelineTTSEnqueue, text)
+	p.mu.Lock()
+	p.queue = append(p.queue, text)
+	p.playing = true
+	p.mu.Unlock()
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (p *simOutPipe) PlayResource(audio io.Reader) error { return nil }
+
+func (p *simOutPipe) Interrupt() error {
+	p.sim.record(TimelineInterrupt, "")
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queue = nil
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+	return nil
+}
+
+func (p *simOutPipe) SetMixer(mixer audio.AudioMixer)           {}
+func (p *simOutPipe) SetReferenceSink(sink audio.ReferenceSink) {}
+func (p *simOutPipe) SetOnPlaybackFinished(callback audio.PlaybackFinishedCallback) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onFinished = callback
+}
+func (p *simOutPipe) Stats() audio.PipelineStats { return audio.PipelineStats{} }
@@ -0,0 +1,122 @@
+package voicebot
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+func TestSimulatorSingleTurn(t *testing.T) {
+	noInterruptions := 0
+	scenario := Scenario{
+		Name:       "single turn",
+		Utterances: []Utterance{{Text: "今天天气怎么样", Duration: 200 * time.Millisecond}},
+		Replies: []ScriptedReply{
+			{Chunks: []string{"今天晴，", "二十度。", "适合出门。"}, Delay: 20 * time.Millisecond, Gap: 10 * time.Millisecond},
+		},
+		SentenceDuration: 30 * time.Millisecond,
+		Expect: Expectations{
+			States:        []State{StateProcessing, StateSpeaking, StateIdle},
+			Spoken:        []string{"今天晴，二十度。", "适合出门。"},
+			Interruptions: &noInterruptions,
+		},
+	}
+
+	result, err := NewSimulator(nil).Run(context.Background(), scenario)
+	if err != nil {
+		t.Fatalf("Run() error = %v\ntimeline: %+v", err, result.Timeline)
+	}
+	if len(result.Played) != 2 {
+		t.Errorf("played = %q, want both sentences played", result.Played)
+	}
+}
+
+func TestSimulatorBargeIn(t *testing.T) {
+	oneInterruption := 1
+	scenario := Scenario{
+		Name: "barge-in",
+		Utterances: []Utterance{
+			{Text: "讲个故事", Duration: 100 * time.Millisecond},
+			// 第一轮回复播放期间开口，应打断
+			{At: 350 * time.Millisecond, Text: "换一个", Duration: 200 * time.Millisecond, ExpectInterrupt: true},
+		},
+		Replies: []ScriptedReply{
+			{Chunks: []string{"从前有座山。", "山里有座庙。", "庙里有个老和尚。"}},
+			{Chunks: []string{"好的。"}},
+		},
+		SentenceDuration: 150 * time.Millisecond,
+		Expect: Expectations{
+			States:        []State{StateProcessing, StateSpeaking, StateListening, StateProcessing, StateSpeaking, StateIdle},
+			Interruptions: &oneInterruption,
+		},
+	}
+
+	result, err := NewSimulator(nil).Run(context.Background(), scenario)
+	if err != nil {
+		t.Fatalf("Run() error = %v\ntimeline: %+v", err, result.Timeline)
+	}
+	for _, played := range result.Played {
+		if played == "庙里有个老和尚。" {
+			t.Errorf("sentence queued before the interruption was still played: %q", result.Played)
+		}
+	}
+	if last := result.Played[len(result.Played)-1]; last != "好的。" {
+		t.Errorf("last played = %q, want reply to the second utterance", last)
+	}
+}
+
+func TestSimulatorReportsFailedExpectations(t *testing.T) {
+	none := 0
+	scenario := Scenario{
+		Utterances:       []Utterance{{Text: "你好", Duration: 100 * time.Millisecond, ExpectInterrupt: true}},
+		SentenceDuration: 10 * time.Millisecond,
+		Expect: Expectations{
+			Spoken:        []string{"你好呀。"},
+			Interruptions: &none,
+		},
+	}
+
+	_, err := NewSimulator(nil).Run(context.Background(), scenario)
+	if err == nil {
+		t.Fatal("Run() expected error for unmet expectations")
+	}
+	for _, want := range []string{"spoken", "did not interrupt"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "interruptions =") {
+		t.Errorf("error %q reports interruption count that was met", err)
+	}
+}
+
+func TestSimulatorAudioUtterance(t *testing.T) {
+	// 300ms 的录音：说话时长取文件时长
+	path := filepath.Join(t.TempDir(), "hello.wav")
+	if err := os.WriteFile(path, audio.EncodeWAV(make([]byte, 16000*2*3/10), 16000, 1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	scenario := Scenario{
+		Utterances:       []Utterance{{Text: "你好", Audio: path}},
+		SentenceDuration: 10 * time.Millisecond,
+	}
+
+	result, err := NewSimulator(nil).Run(context.Background(), scenario)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for _, entry := range result.Timeline {
+		if entry.Kind == TimelineUserFinal {
+			if entry.At < 300*time.Millisecond {
+				t.Errorf("final at %v, want after the 300ms recording", entry.At)
+			}
+			return
+		}
+	}
+	t.Fatal("no final result in timeline")
+}
@@ -0,0 +1,135 @@
+package voicebot
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// DefaultSleepText 未配置休眠播报时使用的话术
+const DefaultSleepText = "我先休息一下，有需要随时叫我"
+
+// SleepPolicy 休眠：空闲超过 IdleTimeout 后进入 StateSleeping 并播报 Text。
+// 休眠期间只响应包含唤醒词的语句（未配置唤醒词时任何语句都会唤醒），文本输入、Announce 和 Wake 同样会唤醒
+type SleepPolicy struct {
+	// IdleTimeout 空闲超过该时长进入休眠，0 表示不自动休眠（仍可调用 Sleep）
+	IdleTimeout time.Duration
+	// WakeWords 唤醒词（忽略标点、空白和大小写），唤醒词之后的内容作为新一轮输入
+	WakeWords []string
+	// PauseASR 休眠期间暂停向 ASR 推流（需要 AudioInPipe 实现 audio.StreamPauser），
+	// 此时听不到唤醒词，只能由文本输入、Announce 或 Wake 唤醒
+	PauseASR bool
+	// Text 进入休眠时的播报，为空时不播报
+	Text string
+	// WakePrompt 只说了唤醒词时播放的提示音
+	WakePrompt string
+}
+
+// DefaultSleepPolicy 默认不自动休眠，任何语句都会唤醒
+func DefaultSleepPolicy() SleepPolicy {
+	return SleepPolicy{Text: DefaultSleepText, WakePrompt: audio.PromptWake}
+}
+
+// matchWakeWord 查找语句中的唤醒词，返回唤醒词之后的原文；未配置唤醒词时任何语句都匹配
+func (p SleepPolicy) matchWakeWord(input string) (string, bool) {
+	if len(p.WakeWords) == 0 {
+		return input, true
+	}
+	// 按 normalizeUtterance 的规则逐字比对，并记录每个字在原文中的结束位置，用于截取唤醒词之后的内容
+	var (
+		normalized []rune
+		ends       []int
+	)
+	for i, r := range input {
+		if skipUtteranceRune(r) {
+			continue
+		}
+		normalized = append(normalized, unicode.ToLower(r))
+		ends = append(ends, i+utf8.RuneLen(r))
+	}
+	for _, word := range p.WakeWords {
+		w := []rune(normalizeUtterance(word))
+		if len(w) == 0 {
+			continue
+		}
+		for i := 0; i+len(w) <= len(normalized); i++ {
+			if slices.Equal(normalized[i:i+len(w)], w) {
+				return strings.TrimLeftFunc(input[ends[i+len(w)-1]:], skipUtteranceRune), true
+			}
+		}
+	}
+	return "", false
+}
+
+func skipUtteranceRune(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSpace(r) || unicode.IsSymbol(r)
+}
+
+// Sleep 进入休眠并播报 SleepPolicy.Text；只能从空闲状态进入，正在对话或已在休眠时返回 false
+func (o *orchestratorImpl) Sleep() bool {
+	if o.isDraining() || !o.transitionTo(StateSleeping) {
+		return false
+	}
+	logging.Infof("Orchestrator: going to sleep")
+	if content := o.config.Sleep.Text; content != "" {
+		// 休眠状态下播报不会转入 Speaking，播完仍保持休眠
+		o.mu.Lock()
+		o.reply.Reset()
+		o.takeHeldLocked()
+		o.mu.Unlock()
+		o.speakText(content)
+	}
+	return true
+}
+
+// Wake 从休眠中唤醒并播放唤醒提示音，未在休眠时返回 false
+func (o *orchestratorImpl) Wake() bool {
+	if !o.wake("wake requested") {
+		return false
+	}
+	o.playPrompt(o.config.Sleep.WakePrompt)
+	return true
+}
+
+// wake 休眠时回到 Idle，停止尚未播完的休眠播报
+func (o *orchestratorImpl) wake(reason string) bool {
+	if o.stateMachine.GetCurrentState() != StateSleeping {
+		return false
+	}
+	o.mu.Lock()
+	pending := o.ttsPendingCount > 0
+	o.mu.Unlock()
+	if pending {
+		o.stopReply()
+	}
+	if !o.transitionTo(StateIdle) {
+		return false
+	}
+	logging.Infof("Orchestrator: woke up (%s)", reason)
+	return true
+}
+
+// handleSleepingASRFinal 休眠期间的识别结果：包含唤醒词时唤醒，返回唤醒词之后的内容作为本轮输入；
+// 没有唤醒词或只说了唤醒词时返回 false，本句不交给 Agent
+func (o *orchestratorImpl) handleSleepingASRFinal(asrEvent *ASRFinalEvent) (*ASRFinalEvent, bool) {
+	rest, ok := o.config.Sleep.matchWakeWord(asrEvent.Text)
+	if !ok {
+		logging.Infof("Orchestrator: sleeping, ignoring ASR final without wake word: %s", asrEvent.Text)
+		return nil, false
+	}
+	if !o.wake("wake word") {
+		return asrEvent, true
+	}
+	if normalizeUtterance(rest) == "" {
+		o.playPrompt(o.config.Sleep.WakePrompt)
+		return nil, false
+	}
+	woken := *asrEvent
+	woken.Text = rest
+	return &woken, true
+}
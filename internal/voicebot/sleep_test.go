@@ -0,0 +1,134 @@
+package voicebot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+func TestSleepPolicyMatchWakeWord(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []string
+		input    string
+		wantRest string
+		wantOK   bool
+	}{
+		{"no wake words", nil, "今天天气怎么样", "今天天气怎么样", true},
+		{"wake word with request", []string{"小欧"}, "小欧，今天天气怎么样？", "今天天气怎么样？", true},
+		{"wake word only", []string{"小欧"}, "小欧。", "", true},
+		{"wake word mid sentence", []string{"小欧"}, "嗯小欧 开灯", "开灯", true},
+		{"english ignores case and spaces", []string{"hey orion"}, "Hey, Orion! what time is it", "what time is it", true},
+		{"missing wake word", []string{"小欧"}, "今天天气怎么样", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rest, ok := SleepPolicy{WakeWords: tt.words}.matchWakeWord(tt.input)
+			if rest != tt.wantRest || ok != tt.wantOK {
+				t.Errorf("matchWakeWord(%q) = %q, %v, want %q, %v", tt.input, rest, ok, tt.wantRest, tt.wantOK)
+			}
+		})
+	}
+}
+
+func waitForState(t *testing.T, orch *orchestratorImpl, want State) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for orch.GetState() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("state = %s, want %s", orch.GetState(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestSleepAfterIdleTimeout(t *testing.T) {
+	voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{&agent.FinishedEvent{}}}
+	outPipe := newMockOutPipe()
+	prompts := newMockPrompts(audio.PromptWake)
+	cfg := DefaultOrchestratorConfig()
+	cfg.Sleep.IdleTimeout = 50 * time.Millisecond
+	cfg.Sleep.WakeWords = []string{"小欧"}
+	orch := NewOrchestratorWithConfig(voiceAgent, outPipe, NewTextInPipe(), nil, cfg).(*orchestratorImpl)
+	orch.SetPrompts(prompts)
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	// 空闲超时进入休眠并播报
+	waitForState(t, orch, StateSleeping)
+	if got := outPipe.getPlayed(); !reflect.DeepEqual(got, []string{DefaultSleepText}) {
+		t.Fatalf("played = %v, want the sleep announcement", got)
+	}
+	if orch.Sleep() {
+		t.Error("Sleep() should report no change when already sleeping")
+	}
+
+	// 没有唤醒词的语句和说话检测都被忽略
+	orch.handleASRFinal(NewASRFinalEvent("今天天气怎么样"))
+	orch.handleUserSpeakingDetected(NewUserSpeakingDetectedEvent())
+	if got := orch.GetState(); got != StateSleeping || len(voiceAgent.getInputs()) != 0 {
+		t.Fatalf("state = %s, inputs = %v, want still sleeping", got, voiceAgent.getInputs())
+	}
+
+	// 只说唤醒词：回到空闲并播放唤醒提示音
+	orch.handleASRFinal(NewASRFinalEvent("小欧。"))
+	if got := orch.GetState(); got != StateIdle {
+		t.Fatalf("state after wake word = %s, want Idle", got)
+	}
+	if got := prompts.getPlayed(); !reflect.DeepEqual(got, []string{audio.PromptWake}) {
+		t.Fatalf("prompts = %v, want wake prompt", got)
+	}
+
+	// 再次休眠后，唤醒词之后的内容直接交给 Agent
+	waitForState(t, orch, StateSleeping)
+	orch.handleASRFinal(NewASRFinalEvent("小欧，今天天气怎么样"))
+	waitForTurns(t, voiceAgent, 1)
+	if got := voiceAgent.getInputs(); !reflect.DeepEqual(got, []string{"今天天气怎么样"}) {
+		t.Fatalf("agent inputs = %v", got)
+	}
+}
+
+func TestSleepWakeAndPauseASR(t *testing.T) {
+	voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{&agent.FinishedEvent{}}}
+	cfg := DefaultOrchestratorConfig()
+	cfg.Sleep.PauseASR = true
+	cfg.Sleep.Text = ""
+	inPipe := &pausingInPipe{TextInPipe: NewTextInPipe()}
+	outPipe := newMockOutPipe()
+	orch := NewOrchestratorWithConfig(voiceAgent, outPipe, inPipe, nil, cfg).(*orchestratorImpl)
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	// 未配置 IdleTimeout 时不会自动休眠，Sleep 手动进入；半双工未开启时播报不暂停推流
+	if !orch.Sleep() || orch.GetState() != StateSleeping {
+		t.Fatalf("Sleep() did not enter sleeping, state = %s", orch.GetState())
+	}
+	waitCalls(t, inPipe, []string{"pause"})
+	if len(outPipe.getPlayed()) != 0 {
+		t.Errorf("played = %v, want no announcement", outPipe.getPlayed())
+	}
+	if !orch.Wake() || orch.Wake() {
+		t.Fatal("Wake() should succeed once")
+	}
+	waitCalls(t, inPipe, []string{"pause", "resume"})
+
+	// 文本输入同样会唤醒
+	orch.Sleep()
+	waitCalls(t, inPipe, []string{"pause", "resume", "pause"})
+	if !orch.SubmitText("开灯") {
+		t.Fatal("SubmitText() = false")
+	}
+	waitForTurns(t, voiceAgent, 1)
+	waitCalls(t, inPipe, []string{"pause", "resume", "pause", "resume"})
+	if got := voiceAgent.getInputs(); !reflect.DeepEqual(got, []string{"开灯"}) {
+		t.Fatalf("agent inputs = %v", got)
+	}
+}
@@ -0,0 +1,203 @@
+package voicebot
+
+import (
+	"maps"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// SlotFillingPolicy 工具调用缺少必填参数（agent.MissingParamEvent，如“天气怎么样”没有说城市）时的追问：
+// 依次播报各参数的追问话术，把用户的回答填入参数后再执行工具；回答取消话术、超时或说了较长的新请求时放弃
+type SlotFillingPolicy struct {
+	// Enable 关闭时缺少参数的工具调用按 LLM 给出的参数直接执行
+	Enable bool
+
+	// Timeout 等待回答的时长，超时后的下一句按普通对话处理，0 表示不限制
+	Timeout time.Duration
+
+	// MaxAnswerChars 回答（去掉标点和空白）超过该字数时视为新的请求：放弃本次调用，追问作为上下文交给 Agent；0 表示不限制
+	MaxAnswerChars int
+
+	// CancelPhrases 放弃本次调用的回答（整句匹配，忽略标点、空白和大小写）
+	CancelPhrases []string
+
+	// FilledText / CancelledText 参数补全后执行工具、放弃时的播报，为空时不播报
+	FilledText    string
+	CancelledText string
+}
+
+// DefaultSlotFillingPolicy 默认开启，等待 15 秒，超过 15 个字的回答视为新请求
+func DefaultSlotFillingPolicy() SlotFillingPolicy {
+	return SlotFillingPolicy{
+		Enable:         true,
+		Timeout:        15 * time.Second,
+		MaxAnswerChars: 15,
+		CancelPhrases:  []string{"取消", "算了", "不用了", "不查了", "不要了", "cancel", "never mind"},
+		FilledText:     "好的",
+		CancelledText:  "好的",
+	}
+}
+
+// SlotFillingStatus 参数追问的状态
+type SlotFillingStatus string
+
+const (
+	SlotRequested SlotFillingStatus = "requested" // 已播报追问，等待回答
+	SlotFilled    SlotFillingStatus = "filled"    // 用户回答已填入参数
+	SlotCancelled SlotFillingStatus = "cancelled" // 用户取消或说了别的请求
+	SlotExpired   SlotFillingStatus = "expired"   // 超时未回答
+)
+
+// slotAnswerParticles 回答末尾的语气词（“北京吧”“上海呢”），填入参数前去掉
+const slotAnswerParticles = "吧呢啊呀嘛哦吗"
+
+// pendingSlot 等待用户补全参数的工具调用
+type pendingSlot struct {
+	tool     string
+	args     map[string]interface{}
+	missing  []agent.MissingParam // 第一个为正在询问的参数
+	deadline time.Time            // 零值表示不限制
+}
+
+func (p SlotFillingPolicy) cancelled(input string) bool {
+	normalized := normalizeUtterance(input)
+	for _, phrase := range p.CancelPhrases {
+		if normalizeUtterance(phrase) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// slotValue 从回答中取出参数值：去掉首尾标点、空白和末尾语气词
+func slotValue(answer string) string {
+	value := strings.TrimFunc(answer, skipUtteranceRune)
+	if last, size := utf8.DecodeLastRuneInString(value); size > 0 && size < len(value) && strings.ContainsRune(slotAnswerParticles, last) {
+		value = strings.TrimRightFunc(value[:len(value)-size], skipUtteranceRune)
+	}
+	return value
+}
+
+// requestSlot 缺少参数的工具调用挂起并播报第一个参数的追问，返回 false 表示未开启追问、应按原参数执行
+func (o *orchestratorImpl) requestSlot(e *agent.MissingParamEvent) bool {
+	if !o.config.SlotFilling.Enable || len(e.Missing) == 0 {
+		return false
+	}
+	slot := &pendingSlot{tool: e.Tool, args: maps.Clone(e.Args), missing: e.Missing}
+	if slot.args == nil {
+		slot.args = make(map[string]interface{})
+	}
+
+	// 同一时间只追问一个调用
+	o.mu.Lock()
+	waiting := o.pendingSlot
+	o.mu.Unlock()
+	if waiting != nil {
+		logging.Warnf("Orchestrator: already asking for %s parameters, dropping incomplete call to %s", waiting.tool, e.Tool)
+		return true
+	}
+	o.askSlot(slot)
+	return true
+}
+
+// askSlot 记录待补全的调用并播报当前参数的追问
+func (o *orchestratorImpl) askSlot(slot *pendingSlot) {
+	if o.config.SlotFilling.Timeout > 0 {
+		slot.deadline = time.Now().Add(o.config.SlotFilling.Timeout)
+	}
+	param := slot.missing[0]
+	o.mu.Lock()
+	o.pendingSlot = slot
+	o.mu.Unlock()
+
+	logging.Infof("Orchestrator: tool %s is missing %q, asking %q", slot.tool, param.Name, param.Question)
+	o.eventBus.Publish(NewSlotFillingEvent(slot.tool, slot.args, param.Name, param.Question, SlotRequested))
+	o.speakText(param.Question)
+}
+
+// handleSlotAnswer 有待补全参数的工具调用时，把本句作为参数值：参数齐全后执行工具（需要确认的工具先确认），
+// 还缺参数时继续追问，返回 true 表示本句已处理、不再交给 Agent；
+// 超时、取消或回答过长时放弃调用，过长的回答连同追问一起按普通对话交给 Agent
+func (o *orchestratorImpl) handleSlotAnswer(asrEvent *ASRFinalEvent) bool {
+	o.mu.Lock()
+	slot := o.pendingSlot
+	o.pendingSlot = nil
+	o.mu.Unlock()
+	if slot == nil {
+		return false
+	}
+
+	policy := o.config.SlotFilling
+	param := slot.missing[0]
+	if !slot.deadline.IsZero() && time.Now().After(slot.deadline) {
+		logging.Infof("Orchestrator: slot %s.%s expired", slot.tool, param.Name)
+		o.eventBus.Publish(NewSlotFillingEvent(slot.tool, slot.args, param.Name, param.Question, SlotExpired))
+		return false
+	}
+	if policy.cancelled(asrEvent.Text) {
+		logging.Infof("Orchestrator: user cancelled %s while asked for %q", slot.tool, param.Name)
+		o.eventBus.Publish(NewSlotFillingEvent(slot.tool, slot.args, param.Name, param.Question, SlotCancelled))
+		o.transitionTo(StateProcessing)
+		o.speakConfirmation(policy.CancelledText)
+		return true
+	}
+	value := slotValue(asrEvent.Text)
+	if value == "" || policy.MaxAnswerChars > 0 && utf8.RuneCountInString(normalizeUtterance(value)) > policy.MaxAnswerChars {
+		logging.Infof("Orchestrator: %q is not an answer for %s.%s, discarding the call", asrEvent.Text, slot.tool, param.Name)
+		o.eventBus.Publish(NewSlotFillingEvent(slot.tool, slot.args, param.Name, param.Question, SlotCancelled))
+		// 追问随下一轮交给 Agent，便于模型理解用户是在换一种说法
+		o.mu.Lock()
+		o.followUp = param.Question
+		o.mu.Unlock()
+		return false
+	}
+
+	slot.args[param.Name] = value
+	slot.missing = slot.missing[1:]
+	logging.Infof("Orchestrator: filled %s.%s = %q", slot.tool, param.Name, value)
+	o.eventBus.Publish(NewSlotFillingEvent(slot.tool, slot.args, param.Name, param.Question, SlotFilled))
+
+	o.mu.Lock()
+	o.takeFollowUpLocked()
+	o.reply.Reset()
+	o.takeHeldLocked()
+	o.mu.Unlock()
+	o.transitionTo(StateProcessing)
+	if len(slot.missing) > 0 {
+		o.askSlot(slot)
+		return true
+	}
+	if o.requestConfirmation(slot.tool, slot.args) {
+		return true
+	}
+
+	o.speakConfirmation(policy.FilledText)
+	ctx, _ := logging.StartTurnContext(o.ctx)
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		batch := o.newToolBatch()
+		batch.Add(slot.tool, slot.args)
+		o.deliverToolResults(batch.Execute(ctx))
+	}()
+	return true
+}
+
+// expireSlot 聆听窗口超时，放弃待补全参数的工具调用，没有时返回 false
+func (o *orchestratorImpl) expireSlot() bool {
+	o.mu.Lock()
+	slot := o.pendingSlot
+	o.pendingSlot = nil
+	o.mu.Unlock()
+	if slot == nil {
+		return false
+	}
+	param := slot.missing[0]
+	logging.Infof("Orchestrator: no answer for %s.%s within %v", slot.tool, param.Name, o.config.FollowUpWindow)
+	o.eventBus.Publish(NewSlotFillingEvent(slot.tool, slot.args, param.Name, param.Question, SlotExpired))
+	return true
+}
@@ -0,0 +1,159 @@
+package voicebot
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+const testCityQuestion = "请问要查哪个城市？"
+
+func TestSlotValue(t *testing.T) {
+	tests := []struct {
+		answer string
+		want   string
+	}{
+		{"北京", "北京"},
+		{"北京吧。", "北京"},
+		{" 上海呢？", "上海"},
+		{"New York.", "New York"},
+		{"吧", "吧"},
+		{"。", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.answer, func(t *testing.T) {
+			if got := slotValue(tt.answer); got != tt.want {
+				t.Errorf("slotValue(%q) = %q, want %q", tt.answer, got, tt.want)
+			}
+		})
+	}
+}
+
+// slotTestTools 记录每次 getWeather 调用的参数
+type slotTestTools struct {
+	mu    sync.Mutex
+	calls []map[string]interface{}
+}
+
+func (s *slotTestTools) executor() tools.ToolExecutor {
+	executor := tools.NewToolExecutor()
+	executor.RegisterTool("getWeather", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.calls = append(s.calls, args)
+		return "晴", nil, nil
+	})
+	return executor
+}
+
+func (s *slotTestTools) getCalls() []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]map[string]interface{}(nil), s.calls...)
+}
+
+// slotAbandoned 返回放弃追问的检查：第一轮缺少城市，第二轮回答 answer
+func slotAbandoned(answer string, wantCalls, wantTurns int, wantFollowUp string) func(t *testing.T, orch *orchestratorImpl, voiceAgent *mockVoiceAgent, outPipe *mockOutPipe, recorder *slotTestTools) {
+	return func(t *testing.T, orch *orchestratorImpl, voiceAgent *mockVoiceAgent, _ *mockOutPipe, recorder *slotTestTools) {
+		orch.handleASRFinal(NewASRFinalEvent("天气怎么样"))
+		waitForTurns(t, voiceAgent, 1)
+		orch.wg.Wait()
+		orch.handleASRFinal(NewASRFinalEvent(answer))
+		waitForTurns(t, voiceAgent, wantTurns)
+		orch.wg.Wait()
+
+		// 未开启追问时两轮都按原参数执行（缺少城市）
+		if got := len(recorder.getCalls()); got != wantCalls {
+			t.Fatalf("tool calls = %d, want %d", got, wantCalls)
+		}
+		if got := len(voiceAgent.getTurns()); got != wantTurns {
+			t.Fatalf("agent turns = %d, want %d", got, wantTurns)
+		}
+		if wantTurns == 2 {
+			if got := voiceAgent.getFollowUps()[1]; got != wantFollowUp {
+				t.Fatalf("follow-up passed to agent = %q, want %q", got, wantFollowUp)
+			}
+		}
+	}
+}
+
+func TestSlotFilling(t *testing.T) {
+	tests := []struct {
+		name      string
+		configure func(cfg *OrchestratorConfig)
+		run       func(t *testing.T, orch *orchestratorImpl, voiceAgent *mockVoiceAgent, outPipe *mockOutPipe, recorder *slotTestTools)
+	}{
+		{"merges answer", nil, func(t *testing.T, orch *orchestratorImpl, voiceAgent *mockVoiceAgent, outPipe *mockOutPipe, recorder *slotTestTools) {
+			events := make(chan *SlotFillingEvent, 4)
+			SubscribeTyped(orch, EventTypeSlotFilling, func(e *SlotFillingEvent) { events <- e })
+
+			// 缺少城市：不执行工具，播报追问，播完后进入聆听
+			orch.handleASRFinal(NewASRFinalEvent("天气怎么样"))
+			waitForTurns(t, voiceAgent, 1)
+			orch.wg.Wait()
+			if got := outPipe.getPlayed(); !reflect.DeepEqual(got, []string{testCityQuestion}) {
+				t.Fatalf("played = %v, want the slot question", got)
+			}
+			if got := recorder.getCalls(); len(got) != 0 {
+				t.Fatalf("tool called before the slot was filled: %v", got)
+			}
+			orch.onTTSPlaybackFinished()
+			if got := orch.GetState(); got != StateListening {
+				t.Fatalf("state = %s, want Listening", got)
+			}
+
+			// 回答填入参数后直接执行工具，不再调用 Agent
+			orch.handleASRFinal(NewASRFinalEvent("北京吧。"))
+			orch.wg.Wait()
+			want := []map[string]interface{}{{"unit": "celsius", "city": "北京"}}
+			if got := recorder.getCalls(); !reflect.DeepEqual(got, want) {
+				t.Fatalf("tool calls = %v, want %v", got, want)
+			}
+			if got := len(voiceAgent.getTurns()); got != 1 {
+				t.Fatalf("agent turns = %d, want 1", got)
+			}
+			if got := outPipe.getPlayed(); !reflect.DeepEqual(got, []string{testCityQuestion, "好的"}) {
+				t.Fatalf("played = %v", got)
+			}
+			for _, status := range []SlotFillingStatus{SlotRequested, SlotFilled} {
+				select {
+				case e := <-events:
+					if e.Status != status || e.Tool != "getWeather" || e.Param != "city" {
+						t.Fatalf("event = %+v, want %s", e, status)
+					}
+				case <-time.After(time.Second):
+					t.Fatalf("expected %s event", status)
+				}
+			}
+		}},
+		{"cancel phrase", nil, slotAbandoned("算了", 0, 1, "")},
+		{"new request", nil, slotAbandoned("帮我放一首周杰伦的晴天，声音大一点", 0, 2, testCityQuestion)},
+		{"expired", func(cfg *OrchestratorConfig) { cfg.SlotFilling.Timeout = time.Nanosecond }, slotAbandoned("北京", 0, 2, "")},
+		{"disabled", func(cfg *OrchestratorConfig) { cfg.SlotFilling.Enable = false }, slotAbandoned("北京", 2, 2, "")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			voiceAgent := &mockVoiceAgent{events: []agent.AgentEvent{
+				&agent.MissingParamEvent{
+					Tool:    "getWeather",
+					Args:    map[string]interface{}{"unit": "celsius"},
+					Missing: []agent.MissingParam{{Name: "city", Question: testCityQuestion}},
+				},
+				&agent.FinishedEvent{},
+			}}
+			cfg := DefaultOrchestratorConfig()
+			if tt.configure != nil {
+				tt.configure(cfg)
+			}
+			recorder := &slotTestTools{}
+			orch, outPipe := newTestOrchestratorWithTools(t, voiceAgent, recorder.executor(), cfg)
+			tt.run(t, orch, voiceAgent, outPipe, recorder)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package voicebot
+
+import (
+	"errors"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/speaker"
+)
+
+// onASRFinalAudio 识别一句 ASR final 的说话人，带识别结果交给 turnAggregator 后发布 ASRFinalEvent
+func (o *orchestratorImpl) onASRFinalAudio(text string, pcm []byte) {
+	match, err := o.config.SpeakerID.IdentifyPCM(pcm, o.config.SpeakerSampleRate)
+	switch {
+	case errors.Is(err, audio.ErrVoiceprintTooShort):
+		logging.Infof("Orchestrator: utterance too short for speaker identification: %s", text)
+	case err != nil:
+		logging.Warnf("Orchestrator: speaker identification failed: %v", err)
+	case match.Known:
+		logging.Infof("Orchestrator: ASR final result from %s (score=%.2f): %s", match.Speaker, match.Score, text)
+	default:
+		logging.Infof("Orchestrator: ASR final result from unknown speaker (closest=%s, score=%.2f): %s",
+			match.Speaker, match.Score, text)
+	}
+	event := NewASRFinalEventWithSpeaker(text, match)
+	o.mu.Lock()
+	event.Confidence, o.finalConfidence = o.finalConfidence, nil
+	o.mu.Unlock()
+	o.turns.Add(event)
+}
+
+// ignoreSpeaker 开启 IgnoreUnknownSpeakers 时，未识别为已注册说话人的语句不进入对话
+func (o *orchestratorImpl) ignoreSpeaker(match *speaker.Match) bool {
+	return match != nil && !match.Known && o.config.IgnoreUnknownSpeakers
+}
@@ -0,0 +1,63 @@
+package voicebot
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/speaker"
+)
+
+func TestIgnoreSpeaker(t *testing.T) {
+	known := &speaker.Match{Speaker: "alice", Score: 0.95, Known: true}
+	unknown := &speaker.Match{Speaker: "alice", Score: 0.3}
+
+	tests := []struct {
+		name          string
+		ignoreUnknown bool
+		match         *speaker.Match
+		want          bool
+	}{
+		{"speaker id disabled", true, nil, false},
+		{"known speaker", true, known, false},
+		{"unknown speaker ignored", true, unknown, true},
+		{"unknown speaker tagged only", false, unknown, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultOrchestratorConfig()
+			cfg.IgnoreUnknownSpeakers = tt.ignoreUnknown
+			orch := NewOrchestratorWithConfig(nil, nil, nil, nil, cfg).(*orchestratorImpl)
+			if got := orch.ignoreSpeaker(tt.match); got != tt.want {
+				t.Errorf("ignoreSpeaker() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOnASRFinalAudioTagsSpeaker(t *testing.T) {
+	store, err := speaker.LoadStore(filepath.Join(t.TempDir(), "voiceprints.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := DefaultOrchestratorConfig()
+	cfg.SpeakerID = speaker.NewIdentifier(store, 0)
+	orch := NewOrchestratorWithConfig(nil, nil, nil, nil, cfg).(*orchestratorImpl)
+
+	events := make(chan *ASRFinalEvent, 1)
+	orch.Subscribe(EventTypeASRFinal, func(event Event) {
+		events <- event.(*ASRFinalEvent)
+	})
+
+	// 语音过短无法提取声纹，按未知说话人标注
+	orch.onASRFinalAudio("你好", make([]byte, 320))
+
+	select {
+	case event := <-events:
+		if event.Text != "你好" || event.Speaker == nil || event.Speaker.Known {
+			t.Errorf("unexpected event: text=%q speaker=%+v", event.Text, event.Speaker)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ASRFinalEvent not published")
+	}
+}
@@ -1,27 +1,113 @@
 package voicebot
 
-import "slices"
+import (
+	"slices"
+	"sync"
+	"time"
+)
 
-// StateMachine 状态机
+// StateTimeoutHandler 状态超时回调，idle 为该状态下最后一次活动（进入或 Touch）至今的时长
+type StateTimeoutHandler func(state State, idle time.Duration)
+
+// StateMachine 状态机（并发安全）
+// 可为状态设置超时：进入该状态或 Touch 后超过时长仍未离开时回调 OnTimeout（在计时协程中调用）
 type StateMachine struct {
+	mu           sync.Mutex
 	currentState State
+
+	timeouts  map[State]time.Duration
+	onTimeout StateTimeoutHandler
+	timer     *time.Timer
+	epoch     uint64 // 每次转换或 Touch 递增，使过期的计时失效
+	stopped   bool
 }
 
 func NewStateMachine() *StateMachine {
 	return &StateMachine{
 		currentState: StateIdle,
+		timeouts:     make(map[State]time.Duration),
+	}
+}
+
+// SetTimeout 设置状态超时，d<=0 表示取消；对当前状态立即生效
+func (sm *StateMachine) SetTimeout(state State, d time.Duration) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if d > 0 {
+		sm.timeouts[state] = d
+	} else {
+		delete(sm.timeouts, state)
+	}
+	if state == sm.currentState {
+		sm.restartTimerLocked()
+	}
+}
+
+// OnTimeout 设置状态超时回调
+func (sm *StateMachine) OnTimeout(handler StateTimeoutHandler) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.onTimeout = handler
+}
+
+// Touch 当前状态仍为 state 时记录一次活动，重新开始超时计时
+func (sm *StateMachine) Touch(state State) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.currentState == state {
+		sm.restartTimerLocked()
+	}
+}
+
+// Stop 停止超时计时，之后不再回调
+func (sm *StateMachine) Stop() {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	sm.stopped = true
+	sm.epoch++
+	if sm.timer != nil {
+		sm.timer.Stop()
+	}
+}
+
+func (sm *StateMachine) restartTimerLocked() {
+	sm.epoch++
+	if sm.timer != nil {
+		sm.timer.Stop()
+		sm.timer = nil
+	}
+	d, ok := sm.timeouts[sm.currentState]
+	if !ok || sm.stopped {
+		return
 	}
+	epoch, state := sm.epoch, sm.currentState
+	sm.timer = time.AfterFunc(d, func() {
+		sm.mu.Lock()
+		handler := sm.onTimeout
+		current := sm.epoch == epoch
+		sm.mu.Unlock()
+		if current && handler != nil {
+			handler(state, d)
+		}
+	})
 }
 
 // CanTransition 检查是否可以转换
 func (sm *StateMachine) CanTransition(to State) bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.canTransitionLocked(to)
+}
+
+func (sm *StateMachine) canTransitionLocked(to State) bool {
 	from := sm.currentState
 
 	validTransitions := map[State][]State{
-		StateIdle:       {StateListening, StateProcessing},
+		StateIdle:       {StateListening, StateProcessing, StateSleeping},
 		StateListening:  {StateProcessing, StateIdle},
 		StateProcessing: {StateSpeaking, StateIdle},
 		StateSpeaking:   {StateListening, StateIdle, StateProcessing},
+		StateSleeping:   {StateIdle, StateProcessing},
 	}
 
 	validTo, ok := validTransitions[from]
@@ -34,8 +120,11 @@ func (sm *StateMachine) CanTransition(to State) bool {
 
 // Transition 状态转换
 func (sm *StateMachine) Transition(to State) bool {
-	if sm.CanTransition(to) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.canTransitionLocked(to) {
 		sm.currentState = to
+		sm.restartTimerLocked()
 		return true
 	}
 	return false
@@ -43,5 +132,7 @@ func (sm *StateMachine) Transition(to State) bool {
 
 // GetCurrentState 获取当前状态
 func (sm *StateMachine) GetCurrentState() State {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
 	return sm.currentState
 }
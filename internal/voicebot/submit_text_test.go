@@ -0,0 +1,77 @@
+package voicebot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+)
+
+func TestSubmitText(t *testing.T) {
+	tests := []struct {
+		name  string
+		delay time.Duration // Agent 回复前的延迟
+		run   func(t *testing.T, orch *orchestratorImpl, voiceAgent *mockVoiceAgent, outPipe *mockOutPipe)
+	}{
+		{
+			name: "muted and filler",
+			run: func(t *testing.T, orch *orchestratorImpl, voiceAgent *mockVoiceAgent, _ *mockOutPipe) {
+				orch.SetMicMuted(true)
+
+				if orch.SubmitText("  ") {
+					t.Fatal("SubmitText() should reject empty text")
+				}
+				// 麦克风静音和“没听清”重问（语气词）都不影响注入的文本
+				if !orch.SubmitText(" 呃 ") {
+					t.Fatal("SubmitText() = false, want true")
+				}
+				waitForTurns(t, voiceAgent, 1)
+				if got := voiceAgent.getInputs(); !reflect.DeepEqual(got, []string{"呃"}) {
+					t.Fatalf("agent inputs = %v", got)
+				}
+			},
+		},
+		{
+			name:  "interrupts reply",
+			delay: 200 * time.Millisecond,
+			run: func(t *testing.T, orch *orchestratorImpl, voiceAgent *mockVoiceAgent, outPipe *mockOutPipe) {
+				orch.SubmitText("讲个故事")
+				waitForTurns(t, voiceAgent, 1)
+				orch.SubmitText("算了，现在几点")
+				waitForTurns(t, voiceAgent, 2)
+				if got := outPipe.getInterrupts(); got != 1 {
+					t.Fatalf("interrupts = %d, want 1", got)
+				}
+				if got := voiceAgent.getInputs(); !reflect.DeepEqual(got, []string{"讲个故事", "算了，现在几点"}) {
+					t.Fatalf("agent inputs = %v", got)
+				}
+			},
+		},
+		{
+			name: "while draining",
+			run: func(t *testing.T, orch *orchestratorImpl, _ *mockVoiceAgent, _ *mockOutPipe) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				orch.StopGracefully(ctx)
+				if orch.SubmitText("你好") {
+					t.Fatal("SubmitText() should be rejected while stopping")
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			voiceAgent := &mockVoiceAgent{delay: tt.delay, events: []agent.AgentEvent{
+				&agent.TextChunkEvent{Chunk: "好的。"},
+				&agent.FinishedEvent{},
+			}}
+			cfg := DefaultOrchestratorConfig()
+			// ASR final 要等静音窗口结束才交给 Agent，注入的文本不等待
+			cfg.EndOfTurnSilence = time.Hour
+			orch, outPipe := newTestOrchestrator(t, voiceAgent, cfg)
+			tt.run(t, orch, voiceAgent, outPipe)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package voicebot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/liuscraft/orion-x/internal/audio"
+)
+
+// TextInPipe 文本输入管道：实现 audio.AudioInPipe，把输入的文本当作 ASR final 结果交给 Orchestrator，
+// 用于没有麦克风时调试 Agent 逻辑（voicebot --text-mode）
+type TextInPipe struct {
+	mu         sync.Mutex
+	started    bool
+	asrHandler func(text string, isFinal bool)
+}
+
+var _ audio.AudioInPipe = (*TextInPipe)(nil)
+
+// NewTextInPipe 创建文本输入管道
+func NewTextInPipe() *TextInPipe {
+	return &TextInPipe{}
+}
+
+// Submit 提交一轮用户输入，相当于一句 ASR final：正在生成的上一轮回复会被取消
+func (p *TextInPipe) Submit(text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	p.mu.Lock()
+	started := p.started
+	asrHandler := p.asrHandler
+	p.mu.Unlock()
+
+	if !started {
+		return errors.New("TextInPipe: not started")
+	}
+	if asrHandler != nil {
+		asrHandler(text, true)
+	}
+	return nil
+}
+
+func (p *TextInPipe) Start(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.started = true
+	return nil
+}
+
+func (p *TextInPipe) Stop() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.started = false
+	return nil
+}
+
+// SendAudio 文本模式不接收音频
+func (p *TextInPipe) SendAudio(audio []byte) error {
+	return nil
+}
+
+func (p *TextInPipe) OnASRResult(handler func(text string, isFinal bool)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.asrHandler = handler
+}
+
+// OnUserSpeakingDetected 文本输入没有 VAD，不会触发
+func (p *TextInPipe) OnUserSpeakingDetected(handler func()) {}
+
+func (p *TextInPipe) OnASRUsage(handler func(durationSec int)) {}
+
+func (p *TextInPipe) OnRecognizerStatus(handler func(available bool, err error)) {}
@@ -0,0 +1,68 @@
+package voicebot
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+)
+
+func TestTextInPipeDrivesOrchestrator(t *testing.T) {
+	voiceAgent := &mockVoiceAgent{
+		gap: 5 * time.Millisecond,
+		events: []agent.AgentEvent{
+			&agent.TextChunkEvent{Chunk: "你好，", Emotion: "default"},
+			&agent.TextChunkEvent{Chunk: "我是小助手。", Emotion: "default"},
+			&agent.FinishedEvent{},
+		},
+	}
+	textIn := NewTextInPipe()
+
+	var (
+		mu    sync.Mutex
+		reply strings.Builder
+	)
+	finished := make(chan struct{}, 1)
+	cfg := DefaultOrchestratorConfig()
+	cfg.OnReplyText = func(chunk string) {
+		mu.Lock()
+		reply.WriteString(chunk)
+		mu.Unlock()
+	}
+	cfg.OnReplyFinished = func() {
+		finished <- struct{}{}
+	}
+
+	if err := textIn.Submit("你好"); err == nil {
+		t.Fatal("Submit() before Start expected error")
+	}
+
+	outPipe := newMockOutPipe()
+	orch := NewOrchestratorWithConfig(voiceAgent, outPipe, textIn, nil, cfg)
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	if err := textIn.Submit("  你好  "); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+
+	select {
+	case <-finished:
+	case <-time.After(2 * time.Second):
+		t.Fatal("OnReplyFinished not called")
+	}
+	mu.Lock()
+	got := reply.String()
+	mu.Unlock()
+	if got != "你好，我是小助手。" {
+		t.Errorf("reply text = %q, want streamed chunks in order", got)
+	}
+	if played := outPipe.getPlayed(); len(played) == 0 {
+		t.Error("reply was not sent to TTS")
+	}
+}
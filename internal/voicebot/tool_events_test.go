@@ -0,0 +1,150 @@
+package voicebot
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+	"github.com/liuscraft/orion-x/internal/audio"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+func TestOrchestratorPublishesToolResult(t *testing.T) {
+	executor := tools.NewToolExecutor()
+	executor.RegisterTool("getTime", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		return map[string]string{"time": "10:00"}, nil, nil
+	})
+
+	orch := NewOrchestrator(nil, newMockOutPipe(), nil, executor)
+	results := make(chan *ToolResultEvent, 1)
+	orch.Subscribe(EventTypeToolResult, func(event Event) {
+		results <- event.(*ToolResultEvent)
+	})
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	orch.OnToolCall("getTime", map[string]interface{}{"zone": "UTC"})
+
+	select {
+	case got := <-results:
+		if got.Tool != "getTime" || got.Error != nil {
+			t.Fatalf("tool result = %+v", got)
+		}
+		if !reflect.DeepEqual(got.Result, map[string]string{"time": "10:00"}) {
+			t.Fatalf("result = %v", got.Result)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ToolResultEvent not published")
+	}
+}
+
+// resourceOutPipe 支持 audio.ResourcePlayer 的 mockOutPipe：读完资源音频后立即回调完成
+type resourceOutPipe struct {
+	*mockOutPipe
+	modes chan audio.ResourceMode
+}
+
+func (p *resourceOutPipe) PlayResourceWithOptions(r io.Reader, opts audio.ResourceOptions) error {
+	io.Copy(io.Discard, r)
+	p.modes <- opts.Mode
+	if opts.OnFinished != nil {
+		go opts.OnFinished(false)
+	}
+	return nil
+}
+
+func TestOrchestratorPublishesToolAudioFinished(t *testing.T) {
+	executor := tools.NewToolExecutor()
+	executor.RegisterTool("playMusic", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		return "playing", strings.NewReader("pcm"), nil
+	})
+
+	outPipe := &resourceOutPipe{mockOutPipe: newMockOutPipe(), modes: make(chan audio.ResourceMode, 1)}
+	orch := NewOrchestrator(nil, outPipe, nil, executor)
+	finished := make(chan *ToolAudioFinishedEvent, 1)
+	orch.Subscribe(EventTypeToolAudioFinished, func(event Event) {
+		finished <- event.(*ToolAudioFinishedEvent)
+	})
+	if err := orch.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer orch.Stop()
+
+	orch.OnToolCall("playMusic", nil)
+
+	select {
+	case got := <-finished:
+		if got.Tool != "playMusic" || got.Interrupted {
+			t.Fatalf("ToolAudioFinishedEvent = %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ToolAudioFinishedEvent not published")
+	}
+	if mode := <-outPipe.modes; mode != audio.ResourceQueue {
+		t.Errorf("resource mode = %v, want ResourceQueue", mode)
+	}
+}
+
+func TestOrchestratorForwardsAgentCitations(t *testing.T) {
+	orch := NewOrchestrator(nil, newMockOutPipe(), nil, nil).(*orchestratorImpl)
+	citations := make(chan *CitationEvent, 1)
+	orch.Subscribe(EventTypeCitation, func(event Event) {
+		citations <- event.(*CitationEvent)
+	})
+
+	want := []agent.Citation{{Title: "天气预报", URL: "https://example.com/weather"}}
+	orch.handleAgentEvent(&agent.CitationEvent{Tool: "search", Citations: want})
+
+	select {
+	case got := <-citations:
+		if got.Tool != "search" || !reflect.DeepEqual(got.Citations, want) {
+			t.Fatalf("citation event = %+v", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("CitationEvent not published")
+	}
+}
+
+func TestOrchestratorBatchesAgentToolCalls(t *testing.T) {
+	executor := tools.NewToolExecutor()
+	executor.RegisterTool("slow", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		time.Sleep(80 * time.Millisecond)
+		return "slow result", nil, nil
+	})
+	executor.RegisterTool("fast", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+		return "fast result", nil, nil
+	})
+
+	orch := NewOrchestrator(nil, newMockOutPipe(), nil, executor).(*orchestratorImpl)
+	batches := make(chan *ToolResultsEvent, 1)
+	orch.Subscribe(EventTypeToolResults, func(event Event) {
+		batches <- event.(*ToolResultsEvent)
+	})
+
+	orch.handleAgentEvent(&agent.ToolCallRequestedEvent{Tool: "slow"})
+	orch.handleAgentEvent(&agent.ToolCallRequestedEvent{Tool: "fast"})
+	start := time.Now()
+	orch.handleAgentEvent(&agent.FinishedEvent{})
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Fatalf("tool calls took %v, want concurrent execution", elapsed)
+	}
+
+	select {
+	case got := <-batches:
+		var order []interface{}
+		for _, r := range got.Results {
+			order = append(order, r.Result)
+		}
+		if want := []interface{}{"slow result", "fast result"}; !reflect.DeepEqual(order, want) {
+			t.Fatalf("results = %v, want %v", order, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("ToolResultsEvent not published")
+	}
+}
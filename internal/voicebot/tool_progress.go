@@ -0,0 +1,69 @@
+package voicebot
+
+import (
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+// DefaultToolProgressText 未配置等待话术时使用的话术
+const DefaultToolProgressText = "还在查询，请稍等"
+
+// ToolProgressPolicy 长耗时工具的进度提醒：工具调用超过 Delay 未返回时发布 ToolProgressEvent，
+// 之后每隔 Interval 再发布一次；工具通过 tools.ReportProgress 上报的进度也发布为 ToolProgressEvent。
+// Speak 开启且当前没有正在播放的 TTS 时播报一句（工具上报的描述优先，否则为 Text），避免长时间无声
+type ToolProgressPolicy struct {
+	// Delay 第一次提醒前的等待时长，0 表示关闭计时提醒
+	Delay time.Duration
+	// Interval 之后的提醒间隔，0 表示只提醒一次
+	Interval time.Duration
+	// Speak 是否播报进度，关闭时只发布事件（供 UI 展示）
+	Speak bool
+	// Text 计时提醒的播报话术，为空时使用 DefaultToolProgressText
+	Text string
+}
+
+// DefaultToolProgressPolicy 默认关闭计时提醒，开启后播报 DefaultToolProgressText
+func DefaultToolProgressPolicy() ToolProgressPolicy {
+	return ToolProgressPolicy{Speak: true, Text: DefaultToolProgressText}
+}
+
+// newToolBatch 创建工具调用批次，执行中的调用按 ToolProgress 提醒进度
+func (o *orchestratorImpl) newToolBatch() *tools.ToolCallBatch {
+	batch := tools.NewToolCallBatch(o.toolExecutor)
+	batch.SetProgress(tools.ProgressConfig{
+		Delay:      o.config.ToolProgress.Delay,
+		Interval:   o.config.ToolProgress.Interval,
+		OnProgress: o.onToolProgress,
+	})
+	return batch
+}
+
+// onToolProgress 发布进度事件，没有正在播放的 TTS 时播报等待话术；
+// 由 ToolCallBatch 在调用返回或被打断前同步调用，之后不会再回调
+func (o *orchestratorImpl) onToolProgress(progress tools.ToolProgress) {
+	logging.Infof("Orchestrator: tool %s still running after %v (progress=%q)", progress.Tool, progress.Elapsed.Round(time.Second), progress.Message)
+	o.eventBus.Publish(NewToolProgressEvent(progress.Tool, progress.Message, progress.Elapsed))
+
+	policy := o.config.ToolProgress
+	if !policy.Speak {
+		return
+	}
+	content := progress.Message
+	if content == "" {
+		content = policy.Text
+	}
+	if content == "" {
+		content = DefaultToolProgressText
+	}
+	// 正在播报回复或上一条提醒时不插话；串行检查，同一批次中多个工具同时到期也只播报一次
+	o.progressMu.Lock()
+	defer o.progressMu.Unlock()
+	o.mu.Lock()
+	speaking := o.ttsPendingCount > 0
+	o.mu.Unlock()
+	if !speaking {
+		o.speakText(content)
+	}
+}
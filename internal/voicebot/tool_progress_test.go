@@ -0,0 +1,99 @@
+package voicebot
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/tools"
+)
+
+func TestOrchestratorToolProgress(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     ToolProgressPolicy
+		report     string
+		wantEvents int // 至少发布的进度事件数
+		wantPlayed []string
+	}{
+		{
+			name:       "timer speaks once while TTS pending",
+			policy:     ToolProgressPolicy{Delay: 40 * time.Millisecond, Interval: 40 * time.Millisecond, Speak: true, Text: "稍等"},
+			wantEvents: 2,
+			wantPlayed: []string{"稍等"},
+		},
+		{
+			name:       "events only",
+			policy:     ToolProgressPolicy{Delay: 40 * time.Millisecond, Interval: 40 * time.Millisecond},
+			wantEvents: 2,
+		},
+		{
+			name:       "tool reported message",
+			policy:     ToolProgressPolicy{Speak: true},
+			report:     "已查到两条结果",
+			wantEvents: 1,
+			wantPlayed: []string{"已查到两条结果"},
+		},
+		{
+			name:   "disabled",
+			policy: ToolProgressPolicy{Speak: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := tools.NewToolExecutor()
+			executor.RegisterTool("search", func(ctx context.Context, args map[string]interface{}) (interface{}, io.Reader, error) {
+				time.Sleep(30 * time.Millisecond)
+				if tt.report != "" {
+					tools.ReportProgress(ctx, tt.report)
+				}
+				time.Sleep(120 * time.Millisecond)
+				return "ok", nil, nil
+			})
+			cfg := DefaultOrchestratorConfig()
+			cfg.ToolProgress = tt.policy
+			outPipe := newMockOutPipe()
+			orch := NewOrchestratorWithConfig(nil, outPipe, nil, executor, cfg)
+
+			var mu sync.Mutex
+			var events []*ToolProgressEvent
+			SubscribeTyped(orch, EventTypeToolProgress, func(e *ToolProgressEvent) {
+				mu.Lock()
+				events = append(events, e)
+				mu.Unlock()
+			})
+			done := make(chan struct{})
+			orch.Subscribe(EventTypeToolResults, func(event Event) { close(done) })
+			if err := orch.Start(context.Background()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			defer orch.Stop()
+
+			orch.OnToolCall("search", nil)
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("ToolResultsEvent not published")
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if len(events) < tt.wantEvents || (tt.wantEvents == 0 && len(events) > 0) {
+				t.Fatalf("progress events = %d, want at least %d", len(events), tt.wantEvents)
+			}
+			for _, e := range events {
+				if e.Tool != "search" || e.Message != tt.report || e.Elapsed <= 0 {
+					t.Errorf("progress event = %+v", e)
+				}
+			}
+			if got := outPipe.getPlayed(); !reflect.DeepEqual(got, tt.wantPlayed) {
+				t.Errorf("played TTS = %v, want %v", got, tt.wantPlayed)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package voicebot
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// turnAggregator 判断用户是否说完一轮：ASR final 先缓冲，静音窗口内又有语音活动（VAD / 中间结果）
+// 或新的 final 时继续等待，窗口内无语音时把缓冲的语句合并为一个 ASRFinalEvent 发出
+type turnAggregator struct {
+	window time.Duration
+	emit   func(*ASRFinalEvent)
+
+	mu      sync.Mutex
+	pending *ASRFinalEvent
+	count   int
+	timer   *time.Timer
+	stopped bool
+}
+
+// newTurnAggregator window<=0 时不缓冲，每句 final 立即发出
+func newTurnAggregator(window time.Duration, emit func(*ASRFinalEvent)) *turnAggregator {
+	return &turnAggregator{window: window, emit: emit}
+}
+
+// Add 缓冲一句 ASR final 并重新开始静音计时
+func (a *turnAggregator) Add(event *ASRFinalEvent) {
+	if a.window <= 0 {
+		a.emit(event)
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.stopped {
+		return
+	}
+	if a.pending == nil {
+		// 说话人取第一句的识别结果
+		a.pending = event
+		a.count = 1
+	} else {
+		a.pending.Text = joinUtterances(a.pending.Text, event.Text)
+		a.pending.Confidence = minConfidence(a.pending.Confidence, event.Confidence)
+		a.count++
+	}
+	a.resetLocked()
+}
+
+// Activity 用户仍在说话，有缓冲的语句时重新开始静音计时
+func (a *turnAggregator) Activity() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.pending != nil && !a.stopped {
+		a.resetLocked()
+	}
+}
+
+// Stop 停止计时并丢弃缓冲的语句
+func (a *turnAggregator) Stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stopped = true
+	a.pending = nil
+	if a.timer != nil {
+		a.timer.Stop()
+	}
+}
+
+func (a *turnAggregator) resetLocked() {
+	if a.timer == nil {
+		a.timer = time.AfterFunc(a.window, a.flush)
+		return
+	}
+	a.timer.Reset(a.window)
+}
+
+// flush 静音超过窗口，发出合并后的语句
+func (a *turnAggregator) flush() {
+	a.mu.Lock()
+	event, count := a.pending, a.count
+	a.pending = nil
+	a.count = 0
+	a.mu.Unlock()
+
+	if event == nil {
+		return
+	}
+	if count > 1 {
+		logging.Infof("Orchestrator: merged %d ASR finals into one turn: %s", count, event.Text)
+	}
+	merged := NewASRFinalEvent(event.Text)
+	merged.Speaker = event.Speaker
+	merged.Confidence = event.Confidence
+	a.emit(merged)
+}
+
+// minConfidence 合并两句的置信度，任一句缺失时取另一句
+func minConfidence(a, b *float64) *float64 {
+	if a == nil || (b != nil && *b < *a) {
+		return b
+	}
+	return a
+}
+
+// joinUtterances 拼接两句识别结果：中文直接相连，其他语言之间加空格
+func joinUtterances(prev, next string) string {
+	prev = strings.TrimSpace(prev)
+	next = strings.TrimSpace(next)
+	if prev == "" || next == "" {
+		return prev + next
+	}
+	last, _ := utf8.DecodeLastRuneInString(prev)
+	first, _ := utf8.DecodeRuneInString(next)
+	if isCJK(last) || isCJK(first) {
+		return prev + next
+	}
+	return prev + " " + next
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) ||
+		(r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
@@ -0,0 +1,100 @@
+package voicebot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/speaker"
+)
+
+func TestTurnAggregatorMergesFinalsWithinWindow(t *testing.T) {
+	emitted := make(chan *ASRFinalEvent, 4)
+	a := newTurnAggregator(80*time.Millisecond, func(e *ASRFinalEvent) { emitted <- e })
+	defer a.Stop()
+
+	a.Add(NewASRFinalEventWithSpeaker("帮我查一下", speaker.Match{Speaker: "alice", Known: true}))
+	time.Sleep(50 * time.Millisecond)
+	a.Activity() // 用户还在说话，重新计时
+	time.Sleep(50 * time.Millisecond)
+	a.Add(NewASRFinalEvent("明天的天气"))
+
+	select {
+	case e := <-emitted:
+		if e.Text != "帮我查一下明天的天气" {
+			t.Fatalf("merged text = %q", e.Text)
+		}
+		if e.Speaker == nil || e.Speaker.Speaker != "alice" {
+			t.Fatalf("expected speaker from first utterance, got %+v", e.Speaker)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("turn not emitted after silence")
+	}
+	select {
+	case e := <-emitted:
+		t.Fatalf("unexpected extra turn: %q", e.Text)
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
+func TestTurnAggregatorKeepsLowestConfidence(t *testing.T) {
+	emitted := make(chan *ASRFinalEvent, 1)
+	a := newTurnAggregator(30*time.Millisecond, func(e *ASRFinalEvent) { emitted <- e })
+	defer a.Stop()
+
+	high, low := 0.9, 0.3
+	first := NewASRFinalEvent("帮我查一下")
+	first.Confidence = &high
+	second := NewASRFinalEvent("明天的天气")
+	second.Confidence = &low
+	a.Add(first)
+	a.Add(second)
+	a.Add(NewASRFinalEvent("吧")) // 未返回置信度的句子不影响结果
+
+	select {
+	case e := <-emitted:
+		if e.Confidence == nil || *e.Confidence != low {
+			t.Fatalf("merged confidence = %v, want %v", e.Confidence, low)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("turn not emitted after silence")
+	}
+}
+
+func TestTurnAggregatorDisabledEmitsImmediately(t *testing.T) {
+	var got []string
+	a := newTurnAggregator(0, func(e *ASRFinalEvent) { got = append(got, e.Text) })
+	a.Add(NewASRFinalEvent("你好"))
+	a.Add(NewASRFinalEvent("在吗"))
+	if len(got) != 2 {
+		t.Fatalf("expected each final emitted immediately, got %v", got)
+	}
+}
+
+func TestTurnAggregatorStopDropsPending(t *testing.T) {
+	emitted := make(chan *ASRFinalEvent, 1)
+	a := newTurnAggregator(20*time.Millisecond, func(e *ASRFinalEvent) { emitted <- e })
+	a.Add(NewASRFinalEvent("你好"))
+	a.Stop()
+	select {
+	case e := <-emitted:
+		t.Fatalf("unexpected turn after stop: %q", e.Text)
+	case <-time.After(60 * time.Millisecond):
+	}
+}
+
+func TestJoinUtterances(t *testing.T) {
+	tests := []struct {
+		prev, next string
+		want       string
+	}{
+		{"帮我查一下，", "明天的天气。", "帮我查一下，明天的天气。"},
+		{"what's the weather", "tomorrow", "what's the weather tomorrow"},
+		{"播放", "Yesterday", "播放Yesterday"},
+		{"", "你好", "你好"},
+	}
+	for _, tt := range tests {
+		if got := joinUtterances(tt.prev, tt.next); got != tt.want {
+			t.Errorf("joinUtterances(%q, %q) = %q, want %q", tt.prev, tt.next, got, tt.want)
+		}
+	}
+}
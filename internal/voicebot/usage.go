@@ -0,0 +1,140 @@
+package voicebot
+
+import (
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+)
+
+// maxRecentTurns Stats 中保留的最近轮次数量
+const maxRecentTurns = 20
+
+// TurnUsage 单轮对话的用量
+type TurnUsage struct {
+	Turn              uint64
+	PromptTokens      int
+	CompletionTokens  int
+	FirstTokenLatency time.Duration // LLM 首 token 延迟
+	ASRDuration       time.Duration // ASR 计费时长
+	TTSChars          int           // 送入 TTS 的字符数
+}
+
+// UsageStats 会话累计用量
+type UsageStats struct {
+	Turns                int
+	PromptTokens         int
+	CompletionTokens     int
+	ASRDuration          time.Duration
+	TTSChars             int
+	AvgFirstTokenLatency time.Duration
+	MaxFirstTokenLatency time.Duration
+	// Recent 最近若干轮的用量（按时间顺序，包含进行中的一轮）
+	Recent []TurnUsage
+	// InputLevel / OutputLevel 麦克风和扬声器的电平统计，由 Orchestrator 填充
+	InputLevel  LevelStats
+	OutputLevel LevelStats
+	// MicMuted 麦克风是否已静音（隐私模式），由 Orchestrator 填充
+	MicMuted bool
+}
+
+// usageStore 按轮次记录并汇总用量
+type usageStore struct {
+	mu         sync.Mutex
+	stats      UsageStats
+	recent     []TurnUsage   // 最后一项为进行中的一轮
+	pendingASR time.Duration // 新一轮开始前上报的 ASR 用量，归入下一轮
+	ttftSum    time.Duration
+	ttftCount  int
+}
+
+func newUsageStore() *usageStore {
+	return &usageStore{}
+}
+
+// BeginTurn 开始新一轮，之前上报的 ASR 用量计入本轮
+func (s *usageStore) BeginTurn(turn uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recent = append(s.recent, TurnUsage{Turn: turn, ASRDuration: s.pendingASR})
+	if len(s.recent) > maxRecentTurns {
+		s.recent = s.recent[len(s.recent)-maxRecentTurns:]
+	}
+	s.pendingASR = 0
+	s.stats.Turns++
+}
+
+// AddASRDuration 记录 ASR 计费时长
+func (s *usageStore) AddASRDuration(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.ASRDuration += d
+	s.pendingASR += d
+}
+
+// AddTTSChars 记录送入 TTS 的字符数
+func (s *usageStore) AddTTSChars(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.TTSChars += n
+	if current := s.currentTurn(); current != nil {
+		current.TTSChars += n
+	}
+}
+
+// RecordLLM 记录本轮 LLM 用量
+func (s *usageStore) RecordLLM(usage agent.Usage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats.PromptTokens += usage.PromptTokens
+	s.stats.CompletionTokens += usage.CompletionTokens
+	if usage.FirstTokenLatency > 0 {
+		s.ttftSum += usage.FirstTokenLatency
+		s.ttftCount++
+		if usage.FirstTokenLatency > s.stats.MaxFirstTokenLatency {
+			s.stats.MaxFirstTokenLatency = usage.FirstTokenLatency
+		}
+	}
+	if current := s.currentTurn(); current != nil {
+		current.PromptTokens += usage.PromptTokens
+		current.CompletionTokens += usage.CompletionTokens
+		if current.FirstTokenLatency == 0 {
+			current.FirstTokenLatency = usage.FirstTokenLatency
+		}
+	}
+}
+
+// Current 返回进行中一轮的用量
+func (s *usageStore) Current() TurnUsage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current := s.currentTurn(); current != nil {
+		return *current
+	}
+	return TurnUsage{}
+}
+
+// currentTurn 进行中的一轮，调用方需持有锁
+func (s *usageStore) currentTurn() *TurnUsage {
+	if len(s.recent) == 0 {
+		return nil
+	}
+	return &s.recent[len(s.recent)-1]
+}
+
+// Stats 返回累计用量快照
+func (s *usageStore) Stats() UsageStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.stats
+	if s.ttftCount > 0 {
+		stats.AvgFirstTokenLatency = s.ttftSum / time.Duration(s.ttftCount)
+	}
+	stats.Recent = append([]TurnUsage(nil), s.recent...)
+	return stats
+}
@@ -0,0 +1,66 @@
+package voicebot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/agent"
+)
+
+func TestUsageStore(t *testing.T) {
+	store := newUsageStore()
+
+	// ASR 用量在轮次开始前上报，归入下一轮
+	store.AddASRDuration(2 * time.Second)
+	store.BeginTurn(1)
+	store.AddTTSChars(5)
+	store.RecordLLM(agent.Usage{PromptTokens: 100, CompletionTokens: 20, FirstTokenLatency: 300 * time.Millisecond})
+
+	store.AddASRDuration(time.Second)
+	store.BeginTurn(2)
+	store.AddTTSChars(3)
+	store.RecordLLM(agent.Usage{PromptTokens: 120, CompletionTokens: 10, FirstTokenLatency: 100 * time.Millisecond})
+
+	stats := store.Stats()
+	if stats.Turns != 2 || stats.PromptTokens != 220 || stats.CompletionTokens != 30 {
+		t.Fatalf("unexpected totals: %+v", stats)
+	}
+	if stats.ASRDuration != 3*time.Second || stats.TTSChars != 8 {
+		t.Fatalf("unexpected asr/tts totals: %+v", stats)
+	}
+	if stats.AvgFirstTokenLatency != 200*time.Millisecond || stats.MaxFirstTokenLatency != 300*time.Millisecond {
+		t.Fatalf("unexpected first token latency: avg=%v max=%v", stats.AvgFirstTokenLatency, stats.MaxFirstTokenLatency)
+	}
+
+	want := []TurnUsage{
+		{Turn: 1, PromptTokens: 100, CompletionTokens: 20, FirstTokenLatency: 300 * time.Millisecond, ASRDuration: 2 * time.Second, TTSChars: 5},
+		{Turn: 2, PromptTokens: 120, CompletionTokens: 10, FirstTokenLatency: 100 * time.Millisecond, ASRDuration: time.Second, TTSChars: 3},
+	}
+	if len(stats.Recent) != len(want) {
+		t.Fatalf("len(Recent) = %d, want %d", len(stats.Recent), len(want))
+	}
+	for i := range want {
+		if stats.Recent[i] != want[i] {
+			t.Errorf("Recent[%d] = %+v, want %+v", i, stats.Recent[i], want[i])
+		}
+	}
+}
+
+func TestUsageStoreKeepsRecentTurns(t *testing.T) {
+	store := newUsageStore()
+	for i := 1; i <= maxRecentTurns+5; i++ {
+		store.BeginTurn(uint64(i))
+		store.AddTTSChars(1)
+	}
+
+	stats := store.Stats()
+	if len(stats.Recent) != maxRecentTurns {
+		t.Fatalf("len(Recent) = %d, want %d", len(stats.Recent), maxRecentTurns)
+	}
+	if stats.Recent[0].Turn != 6 || stats.Recent[maxRecentTurns-1].TTSChars != 1 {
+		t.Fatalf("unexpected recent turns: first=%+v last=%+v", stats.Recent[0], stats.Recent[maxRecentTurns-1])
+	}
+	if stats.TTSChars != maxRecentTurns+5 {
+		t.Fatalf("TTSChars = %d", stats.TTSChars)
+	}
+}
@@ -0,0 +1,113 @@
+package webui
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/liuscraft/orion-x/internal/logging"
+)
+
+// maxAudioMessage 单条音频消息的上限（16kHz 下约 1 秒）
+const maxAudioMessage = 64 << 10
+
+// AudioDevice 浏览器作为音频设备时的收发端（audio.RemoteStream）
+type AudioDevice interface {
+	// Process 处理一块麦克风输入（16-bit 小端单声道 PCM），返回等长的扬声器输出
+	Process(in []byte) []byte
+	SampleRate() int
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  4096,
+	WriteBufferSize: 4096,
+}
+
+// audioSession 同一时间只允许一个浏览器作为音频设备，避免多路麦克风输入交错
+type audioSession struct {
+	mu     sync.Mutex
+	active bool
+	closer func() // 不随请求结束的连接（WebRTC）在服务关闭时由 close 断开
+}
+
+func (a *audioSession) acquire() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.active {
+		return false
+	}
+	a.active = true
+	a.closer = nil
+	return true
+}
+
+func (a *audioSession) release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.active = false
+	a.closer = nil
+}
+
+func (a *audioSession) setCloser(closer func()) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.active {
+		a.closer = closer
+	}
+}
+
+// close 断开当前的 WebRTC 连接
+func (a *audioSession) close() {
+	a.mu.Lock()
+	closer := a.closer
+	a.mu.Unlock()
+	if closer != nil {
+		closer()
+	}
+}
+
+// handleAudio WebSocket 音频通道：浏览器按 AudioDevice.SampleRate 发送二进制 PCM 麦克风块，
+// 每块回复等长的扬声器输出，由浏览器的采集节奏驱动 Mixer 渲染
+func (s *Server) handleAudio(w http.ResponseWriter, r *http.Request) {
+	if s.audio == nil {
+		http.Error(w, "browser audio is disabled", http.StatusNotFound)
+		return
+	}
+	if !s.audioSession.acquire() {
+		http.Error(w, "another browser is already connected as the audio device", http.StatusConflict)
+		return
+	}
+	defer s.audioSession.release()
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logging.Warnf("WebUI: audio upgrade failed: %v", err)
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(maxAudioMessage)
+	logging.Infof("WebUI: browser audio connected from %s", r.RemoteAddr)
+	defer logging.Infof("WebUI: browser audio disconnected")
+
+	// 服务关闭时断开连接，ReadMessage 随之返回
+	go func() {
+		<-r.Context().Done()
+		conn.Close()
+	}()
+	for {
+		kind, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		if kind != websocket.BinaryMessage {
+			continue
+		}
+		out := s.audio.Process(data)
+		if out == nil {
+			continue
+		}
+		if err := conn.WriteMessage(websocket.BinaryMessage, out); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package webui
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// echoDevice 把麦克风输入原样作为扬声器输出返回
+type echoDevice struct{}
+
+func (echoDevice) Process(in []byte) []byte { return append([]byte(nil), in...) }
+func (echoDevice) SampleRate() int          { return 16000 }
+
+func TestServerAudio(t *testing.T) {
+	server := New(Config{Audio: echoDevice{}})
+	server.Attach(newFakeBot())
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/audio"
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial /audio: %v", err)
+	}
+	defer conn.Close()
+
+	// 同一时间只允许一个音频设备
+	if _, resp, err := websocket.DefaultDialer.Dial(url, nil); err == nil || resp == nil || resp.StatusCode != http.StatusConflict {
+		t.Fatalf("second dial error = %v, want 409", err)
+	}
+
+	block := []byte{1, 0, 2, 0, 3, 0}
+	if err := conn.WriteMessage(websocket.BinaryMessage, block); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	kind, data, err := conn.ReadMessage()
+	if err != nil || kind != websocket.BinaryMessage || !bytes.Equal(data, block) {
+		t.Fatalf("read = %d %v %v, want echoed block", kind, data, err)
+	}
+
+	resp, err := http.Get(ts.URL + "/api/state")
+	if err != nil {
+		t.Fatalf("GET /api/state: %v", err)
+	}
+	defer resp.Body.Close()
+	var body bytes.Buffer
+	body.ReadFrom(resp.Body)
+	if !strings.Contains(body.String(), `"audio_rate":16000`) {
+		t.Fatalf("state = %s, want audio_rate 16000", body.String())
+	}
+}
+
+func TestServerAudioDisabled(t *testing.T) {
+	server := New(Config{})
+	server.Attach(newFakeBot())
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	_, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/audio", nil)
+	if err == nil || resp == nil || resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("dial error = %v, want 404", err)
+	}
+}
@@ -0,0 +1,88 @@
+package webui
+
+import (
+	"fmt"
+
+	"github.com/godeps/opus"
+)
+
+// opusFrameMs 服务端编码的 Opus 帧长
+const opusFrameMs = 20
+
+// opusMaxFrameMs Opus 单个包最长 120ms
+const opusMaxFrameMs = 120
+
+// opusMaxPacket 单个 Opus 包的上限（RFC 6716 建议的最大有效负载）
+const opusMaxPacket = 1275
+
+// opusRate 判断采样率是否可以直接用于 Opus 编解码
+func opusRate(sampleRate int) bool {
+	switch sampleRate {
+	case 8000, 12000, 16000, 24000, 48000:
+		return true
+	}
+	return false
+}
+
+// opusCodec 浏览器音频的 Opus 编解码：按 AudioDevice 采样率解码为单声道 16-bit PCM，
+// 输出攒够 opusFrameMs 后编码为一个包
+type opusCodec struct {
+	decoder *opus.Decoder
+	encoder *opus.Encoder
+	frame   int     // 每个输出包的样本数
+	pcm     []int16 // 解码缓冲
+	pending []int16 // 不足一帧的输出
+	packet  []byte
+}
+
+func newOpusCodec(sampleRate int) (*opusCodec, error) {
+	if !opusRate(sampleRate) {
+		return nil, fmt.Errorf("opus does not support %d Hz", sampleRate)
+	}
+	decoder, err := opus.NewDecoder(sampleRate, 1)
+	if err != nil {
+		return nil, fmt.Errorf("create opus decoder: %w", err)
+	}
+	encoder, err := opus.NewEncoder(sampleRate, 1, opus.AppVoIP)
+	if err != nil {
+		return nil, fmt.Errorf("create opus encoder: %w", err)
+	}
+	return &opusCodec{
+		decoder: decoder,
+		encoder: encoder,
+		frame:   sampleRate * opusFrameMs / 1000,
+		pcm:     make([]int16, sampleRate*opusMaxFrameMs/1000),
+		packet:  make([]byte, opusMaxPacket),
+	}, nil
+}
+
+// decode 把一个 Opus 包解码为 16-bit 小端 PCM
+func (c *opusCodec) decode(packet []byte) ([]byte, error) {
+	n, err := c.decoder.Decode(packet, c.pcm)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, n*2)
+	for i, v := range c.pcm[:n] {
+		out[i*2] = byte(v)
+		out[i*2+1] = byte(v >> 8)
+	}
+	return out, nil
+}
+
+// encode 追加 16-bit 小端 PCM，返回已凑满的 Opus 包
+func (c *opusCodec) encode(pcm []byte) ([][]byte, error) {
+	for i := 0; i+1 < len(pcm); i += 2 {
+		c.pending = append(c.pending, int16(pcm[i])|int16(pcm[i+1])<<8)
+	}
+	var packets [][]byte
+	for len(c.pending) >= c.frame {
+		n, err := c.encoder.Encode(c.pending[:c.frame], c.packet)
+		if err != nil {
+			return packets, err
+		}
+		packets = append(packets, append([]byte(nil), c.packet[:n]...))
+		c.pending = append(c.pending[:0], c.pending[c.frame:]...)
+	}
+	return packets, nil
+}
@@ -0,0 +1,75 @@
+package webui
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOpusRate(t *testing.T) {
+	tests := []struct {
+		rate int
+		want bool
+	}{
+		{8000, true},
+		{16000, true},
+		{48000, true},
+		{22050, false},
+		{44100, false},
+		{0, false},
+	}
+	for _, tt := range tests {
+		if got := opusRate(tt.rate); got != tt.want {
+			t.Errorf("opusRate(%d) = %v, want %v", tt.rate, got, tt.want)
+		}
+	}
+	if _, err := newOpusCodec(44100); err == nil {
+		t.Error("newOpusCodec(44100) error = nil, want unsupported rate")
+	}
+}
+
+// sinePCM 生成 16-bit 小端单声道正弦波
+func sinePCM(rate, samples int) []byte {
+	pcm := make([]byte, samples*2)
+	for i := 0; i < samples; i++ {
+		v := int16(8000 * math.Sin(2*math.Pi*440*float64(i)/float64(rate)))
+		pcm[i*2] = byte(v)
+		pcm[i*2+1] = byte(v >> 8)
+	}
+	return pcm
+}
+
+func TestOpusCodecRoundTrip(t *testing.T) {
+	codec, err := newOpusCodec(16000)
+	if err != nil {
+		t.Fatalf("newOpusCodec() error = %v", err)
+	}
+
+	// 不足一帧时先缓存，凑满 20ms 后才输出一个包
+	pcm := sinePCM(16000, 640)
+	packets, err := codec.encode(pcm[:320*2-2])
+	if err != nil || len(packets) != 0 {
+		t.Fatalf("encode(short) = %d packets, %v; want 0", len(packets), err)
+	}
+	packets, err = codec.encode(pcm[320*2-2:])
+	if err != nil || len(packets) != 2 {
+		t.Fatalf("encode() = %d packets, %v; want 2", len(packets), err)
+	}
+
+	var energy float64
+	for _, packet := range packets {
+		out, err := codec.decode(packet)
+		if err != nil {
+			t.Fatalf("decode() error = %v", err)
+		}
+		if len(out) != 320*2 {
+			t.Fatalf("decoded %d bytes, want one 20ms frame", len(out))
+		}
+		for i := 0; i+1 < len(out); i += 2 {
+			v := float64(int16(out[i]) | int16(out[i+1])<<8)
+			energy += v * v
+		}
+	}
+	if energy == 0 {
+		t.Error("decoded audio is silent")
+	}
+}
@@ -0,0 +1,296 @@
+// Package webui 内嵌的网页监控与对话界面：实时字幕、对话记录、状态显示，以及注入文本轮次的输入框
+package webui
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/liuscraft/orion-x/internal/voicebot"
+)
+
+//go:embed static
+var staticFiles embed.FS
+
+// clientBuffer 每个 SSE 连接缓冲的消息数，写不过来的慢连接直接丢弃消息
+const clientBuffer = 64
+
+// Bot 网页界面使用的编排器接口（voicebot.Orchestrator 的子集）
+type Bot interface {
+	GetState() voicebot.State
+	Stats() voicebot.UsageStats
+	Subscribe(eventType voicebot.EventType, handler voicebot.EventHandler)
+	// SubmitText 文本输入框提交的内容作为一轮用户输入
+	SubmitText(text string) bool
+}
+
+// Config 网页界面配置
+type Config struct {
+	// HistorySize 保留的对话条数（用户与机器人各算一条），<=0 时使用 50
+	HistorySize int
+	// Audio 非 nil 时提供 /rtc WebRTC（Opus）与 /audio WebSocket（PCM），浏览器代替本机麦克风和扬声器
+	Audio AudioDevice
+	// ICEServers WebRTC 使用的 STUN / TURN 服务器地址（如 stun:stun.l.google.com:19302），局域网内可以为空
+	ICEServers []string
+}
+
+// Entry 一条对话记录
+type Entry struct {
+	Role string    `json:"role"` // user 或 bot
+	Text string    `json:"text"`
+	Time time.Time `json:"time"`
+}
+
+// message 推送给浏览器的 SSE 消息
+type message struct {
+	event string
+	data  []byte
+}
+
+// Server 网页界面：把编排器事件转成 SSE 推送，并保存最近的对话记录
+type Server struct {
+	bot          Bot
+	historySize  int
+	audio        AudioDevice
+	audioSession audioSession
+	iceServers   []string
+
+	mu      sync.Mutex
+	history []Entry
+	reply   strings.Builder // 正在生成的回复
+	clients map[chan message]struct{}
+}
+
+// New 创建网页界面；流式回复需要把 OnReplyText / OnReplyFinished 接到 OrchestratorConfig，
+// 编排器创建后再调用 Attach
+func New(cfg Config) *Server {
+	size := cfg.HistorySize
+	if size <= 0 {
+		size = 50
+	}
+	return &Server{historySize: size, audio: cfg.Audio, iceServers: cfg.ICEServers, clients: make(map[chan message]struct{})}
+}
+
+// Attach 绑定编排器并订阅事件，需在 Handler / ListenAndServe 之前调用
+func (s *Server) Attach(bot Bot) {
+	s.bot = bot
+	bot.Subscribe(voicebot.EventTypeStateChanged, s.onEvent)
+	bot.Subscribe(voicebot.EventTypePartialTranscript, s.onEvent)
+	bot.Subscribe(voicebot.EventTypeASRFinal, s.onEvent)
+	bot.Subscribe(voicebot.EventTypeMicMuted, s.onEvent)
+	bot.Subscribe(voicebot.EventTypeToolProgress, s.onEvent)
+}
+
+// Handler 返回网页界面的路由：/ 页面，/events SSE 事件流，/api/history 对话记录，/api/state 状态，POST /api/turn 输入文本，
+// POST /rtc 浏览器音频（WebRTC 信令），/audio 浏览器音频（WebSocket）
+func (s *Server) Handler() http.Handler {
+	static, _ := fs.Sub(staticFiles, "static")
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(static))
+	mux.HandleFunc("GET /events", s.handleEvents)
+	mux.HandleFunc("GET /api/history", s.handleHistory)
+	mux.HandleFunc("GET /api/state", s.handleState)
+	mux.HandleFunc("POST /api/turn", s.handleTurn)
+	mux.HandleFunc("POST /rtc", s.handleRTC)
+	mux.HandleFunc("GET /audio", s.handleAudio)
+	return mux
+}
+
+// ListenAndServe 在 addr 上提供网页界面，ctx 结束时关闭服务（SSE 连接随之断开）
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Handler:     s.Handler(),
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	go func() {
+		<-ctx.Done()
+		s.audioSession.close()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	logging.Infof("WebUI: listening on http://%s", listener.Addr())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// OnReplyText 推送流式回复，作为 OrchestratorConfig.OnReplyText
+func (s *Server) OnReplyText(chunk string) {
+	s.mu.Lock()
+	s.reply.WriteString(chunk)
+	s.mu.Unlock()
+	s.broadcast("reply", map[string]string{"text": chunk})
+}
+
+// OnReplyFinished 把本轮回复记入对话记录，作为 OrchestratorConfig.OnReplyFinished
+func (s *Server) OnReplyFinished() {
+	s.mu.Lock()
+	text := strings.TrimSpace(s.reply.String())
+	s.reply.Reset()
+	s.mu.Unlock()
+	if text != "" {
+		s.addEntry(Entry{Role: "bot", Text: text, Time: time.Now()})
+	}
+	s.broadcast("reply_end", map[string]string{"text": text})
+}
+
+// History 返回最近的对话记录（按时间顺序）
+func (s *Server) History() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Entry{}, s.history...)
+}
+
+func (s *Server) onEvent(event voicebot.Event) {
+	switch e := event.(type) {
+	case *voicebot.StateChangedEvent:
+		s.broadcast("state", map[string]string{"state": e.NewState.String()})
+	case *voicebot.PartialTranscriptEvent:
+		s.broadcast("partial", map[string]string{"text": e.Text})
+	case *voicebot.ASRFinalEvent:
+		if e.Attempt > 0 {
+			return
+		}
+		entry := Entry{Role: "user", Text: e.Text, Time: e.Timestamp()}
+		s.addEntry(entry)
+		s.broadcast("user", entry)
+	case *voicebot.MicMutedEvent:
+		s.broadcast("mic", map[string]bool{"muted": e.Muted})
+	case *voicebot.ToolProgressEvent:
+		s.broadcast("tool_progress", map[string]interface{}{
+			"tool": e.Tool, "message": e.Message, "elapsed_ms": e.Elapsed.Milliseconds(),
+		})
+	}
+}
+
+func (s *Server) addEntry(entry Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.history = append(s.history, entry)
+	if extra := len(s.history) - s.historySize; extra > 0 {
+		s.history = append(s.history[:0], s.history[extra:]...)
+	}
+}
+
+// broadcast 推送给所有 SSE 连接，缓冲已满的连接丢弃本条消息
+func (s *Server) broadcast(event string, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		logging.Errorf("WebUI: marshal %s event: %v", event, err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for client := range s.clients {
+		select {
+		case client <- message{event: event, data: data}:
+		default:
+		}
+	}
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	client := make(chan message, clientBuffer)
+	s.mu.Lock()
+	s.clients[client] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.clients, client)
+		s.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// 新连接先收到当前状态，不用等下一次状态变化
+	state, _ := json.Marshal(s.stateSnapshot())
+	fmt.Fprintf(w, "event: snapshot\ndata: %s\n\n", state)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg := <-client:
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.event, msg.data)
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.History())
+}
+
+// stateView /api/state 和 SSE snapshot 的内容
+type stateView struct {
+	State    string `json:"state"`
+	MicMuted bool   `json:"mic_muted"`
+	Turns    int    `json:"turns"`
+	// AudioRate 浏览器音频的采样率，0 表示未开启
+	AudioRate int `json:"audio_rate"`
+	// AudioRTC 浏览器音频可以走 WebRTC（/rtc），否则只能用 /audio WebSocket
+	AudioRTC bool `json:"audio_rtc"`
+}
+
+func (s *Server) stateSnapshot() stateView {
+	stats := s.bot.Stats()
+	view := stateView{State: s.bot.GetState().String(), MicMuted: stats.MicMuted, Turns: stats.Turns}
+	if s.audio != nil {
+		view.AudioRate = s.audio.SampleRate()
+		view.AudioRTC = s.rtcEnabled()
+	}
+	return view
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.stateSnapshot())
+}
+
+func (s *Server) handleTurn(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Text string `json:"text"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 16<<10)).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON body"})
+		return
+	}
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "text is required"})
+		return
+	}
+	logging.Infof("WebUI: text turn: %s", text)
+	if !s.bot.SubmitText(text) {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "bot is stopping"})
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]string{"status": "accepted"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,185 @@
+package webui
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/voicebot"
+)
+
+// fakeBot 记录订阅的事件处理器和注入的文本
+type fakeBot struct {
+	mu       sync.Mutex
+	handlers map[voicebot.EventType][]voicebot.EventHandler
+	turns    []string
+}
+
+func newFakeBot() *fakeBot {
+	return &fakeBot{handlers: make(map[voicebot.EventType][]voicebot.EventHandler)}
+}
+
+func (b *fakeBot) GetState() voicebot.State { return voicebot.StateIdle }
+func (b *fakeBot) Stats() voicebot.UsageStats {
+	return voicebot.UsageStats{Turns: 3, MicMuted: true}
+}
+
+func (b *fakeBot) Subscribe(eventType voicebot.EventType, handler voicebot.EventHandler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+func (b *fakeBot) SubmitText(text string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.turns = append(b.turns, text)
+	return true
+}
+
+func (b *fakeBot) publish(event voicebot.Event) {
+	b.mu.Lock()
+	handlers := b.handlers[event.Type()]
+	b.mu.Unlock()
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+func TestServerHistory(t *testing.T) {
+	bot := newFakeBot()
+	server := New(Config{HistorySize: 3})
+	server.Attach(bot)
+
+	bot.publish(voicebot.NewASRFinalEvent("你好"))
+	server.OnReplyText("你好，")
+	server.OnReplyText("有什么可以帮你？")
+	server.OnReplyFinished()
+	retry := voicebot.NewASRFinalEvent("你好")
+	retry.Attempt = 1
+	bot.publish(retry)
+	bot.publish(voicebot.NewASRFinalEvent("几点了"))
+	bot.publish(voicebot.NewASRFinalEvent("算了"))
+
+	var got []string
+	for _, entry := range server.History() {
+		got = append(got, entry.Role+":"+entry.Text)
+	}
+	want := []string{"bot:你好，有什么可以帮你？", "user:几点了", "user:算了"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("history = %v, want %v", got, want)
+	}
+}
+
+func TestServerHandlers(t *testing.T) {
+	bot := newFakeBot()
+	server := New(Config{})
+	server.Attach(bot)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		contains           string
+	}{
+		{"GET", "/", "", http.StatusOK, "<title>orion-x voicebot</title>"},
+		{"GET", "/api/state", "", http.StatusOK, `"mic_muted":true`},
+		{"GET", "/api/history", "", http.StatusOK, "[]"},
+		{"POST", "/api/turn", `{"text":"  现在几点  "}`, http.StatusAccepted, "accepted"},
+		{"POST", "/api/turn", `{"text":" "}`, http.StatusBadRequest, "text is required"},
+		{"POST", "/api/turn", `not json`, http.StatusBadRequest, "invalid JSON"},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, ts.URL+tt.path, strings.NewReader(tt.body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.path, err)
+		}
+		var body strings.Builder
+		bufio.NewReader(resp.Body).WriteTo(&body)
+		resp.Body.Close()
+		if resp.StatusCode != tt.status || !strings.Contains(body.String(), tt.contains) {
+			t.Errorf("%s %s = %d %q, want %d containing %q", tt.method, tt.path, resp.StatusCode, body.String(), tt.status, tt.contains)
+		}
+	}
+	if want := []string{"现在几点"}; !reflect.DeepEqual(bot.turns, want) {
+		t.Fatalf("injected turns = %v, want %v", bot.turns, want)
+	}
+}
+
+func TestServerEventStream(t *testing.T) {
+	bot := newFakeBot()
+	server := New(Config{})
+	server.Attach(bot)
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.URL+"/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET /events: %v", err)
+	}
+	defer resp.Body.Close()
+	reader := bufio.NewReader(resp.Body)
+
+	next := func() (string, map[string]interface{}) {
+		t.Helper()
+		var event string
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("read event: %v", err)
+			}
+			line = strings.TrimSpace(line)
+			if strings.HasPrefix(line, "event: ") {
+				event = strings.TrimPrefix(line, "event: ")
+			} else if strings.HasPrefix(line, "data: ") {
+				var data map[string]interface{}
+				if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &data); err != nil {
+					t.Fatalf("decode %s: %v", event, err)
+				}
+				return event, data
+			}
+		}
+	}
+
+	if event, data := next(); event != "snapshot" || data["state"] != "Idle" {
+		t.Fatalf("first event = %s %v, want snapshot", event, data)
+	}
+	bot.publish(voicebot.NewStateChangedEvent(voicebot.StateIdle, voicebot.StateListening))
+	bot.publish(voicebot.NewPartialTranscriptEvent("今天", time.Now()))
+	bot.publish(voicebot.NewASRFinalEvent("今天天气"))
+	server.OnReplyText("晴。")
+	server.OnReplyFinished()
+	bot.publish(voicebot.NewMicMutedEvent(true))
+	bot.publish(voicebot.NewToolProgressEvent("getNews", "", 5*time.Second))
+
+	want := []struct {
+		event, key string
+		value      interface{}
+	}{
+		{"state", "state", "Listening"},
+		{"partial", "text", "今天"},
+		{"user", "text", "今天天气"},
+		{"reply", "text", "晴。"},
+		{"reply_end", "text", "晴。"},
+		{"mic", "muted", true},
+		{"tool_progress", "tool", "getNews"},
+	}
+	for _, w := range want {
+		event, data := next()
+		if event != w.event || data[w.key] != w.value {
+			t.Fatalf("event = %s %v, want %s %s=%v", event, data, w.event, w.key, w.value)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package webui
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/liuscraft/orion-x/internal/logging"
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+)
+
+// rtcGatherTimeout 等待服务端 ICE candidate 收集完成的上限
+const rtcGatherTimeout = 5 * time.Second
+
+// opusCapability 浏览器与服务端收发的 Opus 音轨（RTP 时钟固定为 48kHz 立体声，实际编码为单声道）
+var opusCapability = webrtc.RTPCodecCapability{
+	MimeType:    webrtc.MimeTypeOpus,
+	ClockRate:   48000,
+	Channels:    2,
+	SDPFmtpLine: "minptime=10;useinbandfec=1",
+}
+
+// rtcEnabled 浏览器音频是否可以走 WebRTC：AudioDevice 的采样率需要是 Opus 支持的采样率
+func (s *Server) rtcEnabled() bool {
+	return s.audio != nil && opusRate(s.audio.SampleRate())
+}
+
+// newPeerConnection 创建只协商 Opus 的 PeerConnection
+func (s *Server) newPeerConnection() (*webrtc.PeerConnection, error) {
+	engine := &webrtc.MediaEngine{}
+	if err := engine.RegisterCodec(webrtc.RTPCodecParameters{RTPCodecCapability: opusCapability, PayloadType: 111}, webrtc.RTPCodecTypeAudio); err != nil {
+		return nil, err
+	}
+	var config webrtc.Configuration
+	if len(s.iceServers) > 0 {
+		config.ICEServers = []webrtc.ICEServer{{URLs: s.iceServers}}
+	}
+	return webrtc.NewAPI(webrtc.WithMediaEngine(engine)).NewPeerConnection(config)
+}
+
+// handleRTC WebRTC 音频通道（不使用 trickle ICE）：浏览器收集完 candidate 后 POST SDP offer，返回包含全部
+// candidate 的 answer。浏览器麦克风的 Opus 包解码为 AudioDevice.SampleRate 的 PCM 交给 Process，输出每 20ms
+// 编码为一个 Opus 包回传；与 /audio 一样由浏览器的发送节奏驱动 Mixer 渲染。连接断开或服务关闭时释放音频设备
+func (s *Server) handleRTC(w http.ResponseWriter, r *http.Request) {
+	if s.audio == nil {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "browser audio is disabled"})
+		return
+	}
+	if !s.rtcEnabled() {
+		writeJSON(w, http.StatusNotImplemented, map[string]string{"error": "webrtc audio requires a sample rate supported by opus"})
+		return
+	}
+	var offer webrtc.SessionDescription
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64<<10)).Decode(&offer); err != nil || offer.Type != webrtc.SDPTypeOffer {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid SDP offer"})
+		return
+	}
+	if !s.audioSession.acquire() {
+		writeJSON(w, http.StatusConflict, map[string]string{"error": "another browser is already connected as the audio device"})
+		return
+	}
+
+	session, err := s.startRTC(offer)
+	if err != nil {
+		s.audioSession.release()
+		logging.Warnf("WebUI: webrtc negotiation failed: %v", err)
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	s.audioSession.setCloser(session.close)
+	logging.Infof("WebUI: browser audio (webrtc) connecting from %s", r.RemoteAddr)
+	writeJSON(w, http.StatusOK, session.pc.LocalDescription())
+}
+
+// rtcSession 一个浏览器的 WebRTC 音频连接
+type rtcSession struct {
+	server *Server
+	pc     *webrtc.PeerConnection
+	track  *webrtc.TrackLocalStaticSample
+	codec  *opusCodec
+	once   sync.Once
+}
+
+// startRTC 按 offer 建立连接并返回 answer 已就绪的会话
+func (s *Server) startRTC(offer webrtc.SessionDescription) (*rtcSession, error) {
+	codec, err := newOpusCodec(s.audio.SampleRate())
+	if err != nil {
+		return nil, err
+	}
+	pc, err := s.newPeerConnection()
+	if err != nil {
+		return nil, err
+	}
+	session := &rtcSession{server: s, pc: pc, codec: codec}
+	fail := func(err error) (*rtcSession, error) {
+		pc.Close()
+		return nil, err
+	}
+
+	session.track, err = webrtc.NewTrackLocalStaticSample(opusCapability, "audio", "voicebot")
+	if err != nil {
+		return fail(err)
+	}
+	sender, err := pc.AddTrack(session.track)
+	if err != nil {
+		return fail(err)
+	}
+	// 读取并丢弃 RTCP，否则发送端的缓冲会被塞满
+	go func() {
+		buf := make([]byte, 1500)
+		for {
+			if _, _, err := sender.Read(buf); err != nil {
+				return
+			}
+		}
+	}()
+
+	var tracks sync.Once
+	pc.OnTrack(func(remote *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		if remote.Kind() != webrtc.RTPCodecTypeAudio {
+			return
+		}
+		// 只处理第一条音轨，多路麦克风输入会交错
+		tracks.Do(func() { go session.pump(remote) })
+	})
+	pc.OnConnectionStateChange(func(state webrtc.PeerConnectionState) {
+		logging.Infof("WebUI: webrtc connection %s", state)
+		switch state {
+		case webrtc.PeerConnectionStateFailed, webrtc.PeerConnectionStateDisconnected, webrtc.PeerConnectionStateClosed:
+			// 不在回调中关闭 PeerConnection
+			go session.close()
+		}
+	})
+
+	if err := pc.SetRemoteDescription(offer); err != nil {
+		return fail(err)
+	}
+	answer, err := pc.CreateAnswer(nil)
+	if err != nil {
+		return fail(err)
+	}
+	gathered := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(answer); err != nil {
+		return fail(err)
+	}
+	select {
+	case <-gathered:
+	case <-time.After(rtcGatherTimeout):
+		// 超时时使用已收集的 candidate
+		logging.Warnf("WebUI: webrtc ICE gathering timed out")
+	}
+	return session, nil
+}
+
+// pump 把浏览器的每个 Opus 包解码后交给 AudioDevice，输出编码后回传
+func (r *rtcSession) pump(remote *webrtc.TrackRemote) {
+	defer r.close()
+	for {
+		packet, _, err := remote.ReadRTP()
+		if err != nil {
+			return
+		}
+		if len(packet.Payload) == 0 {
+			continue
+		}
+		in, err := r.codec.decode(packet.Payload)
+		if err != nil {
+			logging.Warnf("WebUI: decode opus packet: %v", err)
+			continue
+		}
+		out := r.server.audio.Process(in)
+		packets, err := r.codec.encode(out)
+		if err != nil {
+			logging.Warnf("WebUI: encode opus packet: %v", err)
+		}
+		for _, data := range packets {
+			if err := r.track.WriteSample(media.Sample{Data: data, Duration: opusFrameMs * time.Millisecond}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// close 关闭连接并释放音频设备，可重复调用
+func (r *rtcSession) close() {
+	r.once.Do(func() {
+		r.pc.Close()
+		r.server.audioSession.release()
+		logging.Infof("WebUI: browser audio (webrtc) disconnected")
+	})
+}
@@ -0,0 +1,165 @@
+package webui
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pion/webrtc/v4"
+	"github.com/pion/webrtc/v4/pkg/media"
+)
+
+// rateDevice 采样率可配置的 echoDevice
+type rateDevice struct {
+	echoDevice
+	rate int
+}
+
+func (d rateDevice) SampleRate() int { return d.rate }
+
+func TestServerRTCRejects(t *testing.T) {
+	offer := `{"type":"offer","sdp":"v=0"}`
+	tests := []struct {
+		name   string
+		audio  AudioDevice
+		busy   bool
+		body   string
+		status int
+	}{
+		{"audio disabled", nil, false, offer, http.StatusNotFound},
+		{"unsupported rate", rateDevice{rate: 44100}, false, offer, http.StatusNotImplemented},
+		{"invalid json", echoDevice{}, false, "{", http.StatusBadRequest},
+		{"not an offer", echoDevice{}, false, `{"type":"answer","sdp":"v=0"}`, http.StatusBadRequest},
+		{"busy", echoDevice{}, true, offer, http.StatusConflict},
+		{"invalid sdp", echoDevice{}, false, offer, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := New(Config{Audio: tt.audio})
+			server.Attach(newFakeBot())
+			if tt.busy {
+				server.audioSession.acquire()
+			}
+			ts := httptest.NewServer(server.Handler())
+			defer ts.Close()
+
+			resp, err := http.Post(ts.URL+"/rtc", "application/json", strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatalf("POST /rtc: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			// 协商失败后音频设备可以再次使用
+			if !tt.busy && !server.audioSession.acquire() {
+				t.Error("audio session still held after rejected request")
+			}
+		})
+	}
+}
+
+func TestServerRTC(t *testing.T) {
+	server := New(Config{Audio: echoDevice{}})
+	server.Attach(newFakeBot())
+	ts := httptest.NewServer(server.Handler())
+	defer ts.Close()
+
+	pc, err := webrtc.NewPeerConnection(webrtc.Configuration{})
+	if err != nil {
+		t.Fatalf("NewPeerConnection() error = %v", err)
+	}
+	defer pc.Close()
+	mic, err := webrtc.NewTrackLocalStaticSample(opusCapability, "audio", "browser")
+	if err != nil {
+		t.Fatalf("NewTrackLocalStaticSample() error = %v", err)
+	}
+	if _, err := pc.AddTrack(mic); err != nil {
+		t.Fatalf("AddTrack() error = %v", err)
+	}
+	received := make(chan []byte, 1)
+	pc.OnTrack(func(remote *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+		for {
+			packet, _, err := remote.ReadRTP()
+			if err != nil {
+				return
+			}
+			if len(packet.Payload) > 0 {
+				select {
+				case received <- packet.Payload:
+				default:
+				}
+			}
+		}
+	})
+
+	offer, err := pc.CreateOffer(nil)
+	if err != nil {
+		t.Fatalf("CreateOffer() error = %v", err)
+	}
+	gathered := webrtc.GatheringCompletePromise(pc)
+	if err := pc.SetLocalDescription(offer); err != nil {
+		t.Fatalf("SetLocalDescription() error = %v", err)
+	}
+	<-gathered
+	body, _ := json.Marshal(pc.LocalDescription())
+	resp, err := http.Post(ts.URL+"/rtc", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("POST /rtc: %v", err)
+	}
+	var answer webrtc.SessionDescription
+	err = json.NewDecoder(resp.Body).Decode(&answer)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || err != nil {
+		t.Fatalf("POST /rtc = %d, %v", resp.StatusCode, err)
+	}
+	if err := pc.SetRemoteDescription(answer); err != nil {
+		t.Fatalf("SetRemoteDescription() error = %v", err)
+	}
+
+	// 同一时间只允许一个音频设备
+	if resp, err := http.Post(ts.URL+"/rtc", "application/json", bytes.NewReader(body)); err != nil || resp.StatusCode != http.StatusConflict {
+		t.Fatalf("second POST /rtc = %v, %v; want 409", resp, err)
+	}
+
+	// 服务端把麦克风的 Opus 包解码、经 echoDevice 原样返回后重新编码回传
+	codec, err := newOpusCodec(16000)
+	if err != nil {
+		t.Fatalf("newOpusCodec() error = %v", err)
+	}
+	packets, err := codec.encode(sinePCM(16000, 320))
+	if err != nil || len(packets) != 1 {
+		t.Fatalf("encode() = %d packets, %v", len(packets), err)
+	}
+	ticker := time.NewTicker(opusFrameMs * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case payload := <-received:
+			if _, err := codec.decode(payload); err != nil {
+				t.Fatalf("decode echoed packet: %v", err)
+			}
+			pc.Close()
+			// 浏览器断开后释放音频设备
+			deadline := time.Now().Add(10 * time.Second)
+			for !server.audioSession.acquire() {
+				if time.Now().After(deadline) {
+					t.Fatal("audio session not released after disconnect")
+				}
+				time.Sleep(20 * time.Millisecond)
+			}
+			return
+		case <-ticker.C:
+			if err := mic.WriteSample(media.Sample{Data: packets[0], Duration: opusFrameMs * time.Millisecond}); err != nil {
+				t.Fatalf("WriteSample() error = %v", err)
+			}
+		case <-timeout:
+			t.Fatal("no audio received over webrtc")
+		}
+	}
+}
@@ -23,6 +23,20 @@ type Options struct {
 	StripListLeaders bool
 	// KeepLinks keeps the full link format [text](url) instead of just text.
 	KeepLinks bool
+	// DropCode removes inline code and unterminated code fences entirely
+	// instead of keeping their content. Complete code blocks are always removed.
+	DropCode bool
+	// StripEmoji removes emoji (including modifiers and joiners) that are
+	// not converted by EmojiWords.
+	StripEmoji bool
+	// EmojiWords converts specific emoji to words before stripping.
+	EmojiWords map[string]string
+	// StripBullets removes bullet symbols such as "•", "▪" and "►".
+	StripBullets bool
+	// SymbolWords translates symbols into words (e.g. "&" to "and").
+	// Symbols touching a digit are kept so that forms like "25%" or "3~5"
+	// can be read by a number-aware normalizer later.
+	SymbolWords map[string]string
 }
 
 // FilterWithOptions removes Markdown formatting with custom options.
@@ -35,6 +49,7 @@ func FilterWithOptions(text string, opts Options) string {
 var (
 	patterns struct {
 		codeBlock        *regexp.Regexp // ```code```
+		openCodeBlock    *regexp.Regexp // ```code (unterminated)
 		inlineCode       *regexp.Regexp // `code`
 		boldAsterisk     *regexp.Regexp // **text**
 		boldUnderscore   *regexp.Regexp // __text__
@@ -52,6 +67,7 @@ var (
 		footnote         *regexp.Regexp // [^1]
 		refLink          *regexp.Regexp // [1]: url
 		multipleNewlines *regexp.Regexp // 3+ newlines
+		bullet           *regexp.Regexp // • item
 	}
 )
 
@@ -78,6 +94,8 @@ func init() {
 	patterns.footnote = regexp.MustCompile(`\[\^.+?\](?::\s*.+?$)?`)
 	patterns.refLink = regexp.MustCompile(`(?m)^\s{0,2}\[.+?\]:\s*\S+.*?$`)
 	patterns.multipleNewlines = regexp.MustCompile(`\n{3,}`)
+	patterns.openCodeBlock = regexp.MustCompile("```[\\s\\S]*$")
+	patterns.bullet = regexp.MustCompile(`[•◦▪▫●○■□◆◇►▶▸‣⁃]\s*`)
 }
 
 // filterWithOptions performs the actual filtering with given options.
@@ -86,6 +104,10 @@ func filterWithOptions(text string, opts Options) string {
 
 	// Remove code blocks first (multiline)
 	result = patterns.codeBlock.ReplaceAllString(result, "")
+	if opts.DropCode {
+		result = patterns.openCodeBlock.ReplaceAllString(result, "")
+		result = patterns.inlineCode.ReplaceAllString(result, "")
+	}
 
 	// Process headers
 	result = patterns.headerAtx.ReplaceAllString(result, "$1")
@@ -136,6 +158,20 @@ func filterWithOptions(text string, opts Options) string {
 	// Remove reference links
 	result = patterns.refLink.ReplaceAllString(result, "")
 
+	// Speech-oriented cleanup
+	if len(opts.EmojiWords) > 0 {
+		result = replaceWords(result, opts.EmojiWords)
+	}
+	if opts.StripEmoji {
+		result = stripEmoji(result)
+	}
+	if opts.StripBullets {
+		result = patterns.bullet.ReplaceAllString(result, "")
+	}
+	if len(opts.SymbolWords) > 0 {
+		result = translateSymbols(result, opts.SymbolWords)
+	}
+
 	// Clean up excessive newlines
 	result = patterns.multipleNewlines.ReplaceAllString(result, "\n\n")
 
@@ -161,6 +161,54 @@ func TestFilterWithOptions(t *testing.T) {
 			input:    "**bold** and *italic* and `code`",
 			expected: "bold and italic and code",
 		},
+		{
+			name:     "drop inline code",
+			input:    "run `go test` now",
+			opts:     Options{DropCode: true},
+			expected: "run  now",
+		},
+		{
+			name:     "drop unterminated code block",
+			input:    "example:\n```go\nfunc main() {",
+			opts:     Options{DropCode: true},
+			expected: "example:\n",
+		},
+		{
+			name:     "strip emoji",
+			input:    "好的😊👍🏻！👨‍👩‍👧 ✨",
+			opts:     Options{StripEmoji: true},
+			expected: "好的！ ",
+		},
+		{
+			name:     "convert emoji",
+			input:    "太棒了🎉",
+			opts:     Options{StripEmoji: true, EmojiWords: map[string]string{"🎉": "，恭喜"}},
+			expected: "太棒了，恭喜",
+		},
+		{
+			name:     "strip bullets",
+			input:    "• 苹果\n▪ 香蕉",
+			opts:     Options{StripBullets: true},
+			expected: "苹果\n香蕉",
+		},
+		{
+			name:     "translate symbols",
+			input:    "猫&狗~",
+			opts:     Options{SymbolWords: ChineseSymbolWords},
+			expected: "猫和狗",
+		},
+		{
+			name:     "keep symbols next to digits",
+			input:    "Up 25% & 3~5 days",
+			opts:     Options{SymbolWords: EnglishSymbolWords},
+			expected: "Up 25%  and  3~5 days",
+		},
+		{
+			name:     "speech options",
+			input:    "**注意**：运行 `rm -rf` 前请备份 🙏",
+			opts:     SpeechOptions(ChineseSymbolWords),
+			expected: "注意：运行  前请备份 ",
+		},
 	}
 
 	for _, tt := range tests {
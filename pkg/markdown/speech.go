@@ -0,0 +1,104 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ChineseSymbolWords translates common symbols into Chinese words for speech.
+var ChineseSymbolWords = map[string]string{
+	"&": "和",
+	"%": "百分比",
+	"~": "",
+	"～": "",
+	"+": "加",
+	"=": "等于",
+	"→": "到",
+	"×": "乘",
+}
+
+// EnglishSymbolWords translates common symbols into English words for speech.
+var EnglishSymbolWords = map[string]string{
+	"&": " and ",
+	"%": " percent ",
+	"~": "",
+	"～": "",
+	"+": " plus ",
+	"=": " equals ",
+	"→": " to ",
+	"×": " times ",
+}
+
+// SpeechOptions returns options suitable for text that will be spoken by TTS:
+// code is dropped, emoji and bullets are stripped and common symbols are
+// translated using symbolWords (may be nil).
+func SpeechOptions(symbolWords map[string]string) Options {
+	return Options{
+		SkipImages:       true,
+		StripListLeaders: true,
+		DropCode:         true,
+		StripEmoji:       true,
+		StripBullets:     true,
+		SymbolWords:      symbolWords,
+	}
+}
+
+// replaceWords replaces every occurrence of the map keys with their values.
+func replaceWords(text string, words map[string]string) string {
+	for from, to := range words {
+		text = strings.ReplaceAll(text, from, to)
+	}
+	return text
+}
+
+// stripEmoji removes emoji, skin tone modifiers, variation selectors and
+// zero-width joiners.
+func stripEmoji(text string) string {
+	return strings.Map(func(r rune) rune {
+		if isEmoji(r) {
+			return -1
+		}
+		return r
+	}, text)
+}
+
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // pictographs, emoticons, flags, skin tones
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // misc symbols and dingbats
+		return true
+	case r >= 0x2B00 && r <= 0x2BFF: // arrows and stars such as ⭐
+		return true
+	case r >= 0xE0020 && r <= 0xE007F: // tag sequences
+		return true
+	case r == 0xFE0F || r == 0xFE0E || r == 0x200D || r == 0x20E3:
+		return true
+	}
+	return false
+}
+
+// translateSymbols replaces symbols with words unless they touch a digit.
+func translateSymbols(text string, words map[string]string) string {
+	var b strings.Builder
+	var prev rune
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		word, ok := words[string(r)]
+		if ok {
+			next, _ := utf8.DecodeRuneInString(text[i+size:])
+			if unicode.IsDigit(prev) || unicode.IsDigit(next) {
+				ok = false
+			}
+		}
+		if ok {
+			b.WriteString(word)
+		} else {
+			b.WriteRune(r)
+		}
+		prev = r
+		i += size
+	}
+	return b.String()
+}
@@ -0,0 +1,176 @@
+package markdown
+
+import (
+	"strings"
+)
+
+// maxHold caps how many bytes of a line StreamFilter holds back while
+// waiting for an inline construct to close.
+const maxHold = 256
+
+// StreamFilter filters Markdown that arrives in chunks (e.g. streamed LLM output).
+// Unlike Filter, it tracks constructs that span Feed calls: fenced code blocks
+// are dropped until their closing fence, table rows are held until complete and
+// read as plain cells, and unterminated inline markers (**, `, [link]) are held
+// back until they close or the line ends.
+type StreamFilter struct {
+	opts    Options
+	buf     string // unprocessed text of the current line
+	midLine bool   // part of the current line has already been emitted
+	inCode  bool   // inside a fenced code block
+}
+
+// NewStreamFilter creates a streaming filter using the given options.
+func NewStreamFilter(opts Options) *StreamFilter {
+	return &StreamFilter{opts: opts}
+}
+
+// Feed adds a chunk and returns the filtered text that is safe to emit now.
+func (f *StreamFilter) Feed(chunk string) string {
+	f.buf += chunk
+
+	var out strings.Builder
+	for {
+		idx := strings.IndexByte(f.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		line := f.buf[:idx+1]
+		f.buf = f.buf[idx+1:]
+		out.WriteString(f.processLine(line))
+	}
+
+	if f.buf == "" || f.holdLine() {
+		return out.String()
+	}
+
+	start := 0
+	if !f.midLine {
+		// A leading "* " list marker is not an emphasis marker
+		if loc := patterns.listLeader.FindStringIndex(f.buf); loc != nil {
+			start = loc[1]
+		}
+	}
+	split := start + inlineSplit(f.buf[start:])
+	if len(f.buf)-split > maxHold {
+		split = len(f.buf)
+	}
+	if split > 0 {
+		out.WriteString(filterWithOptions(f.buf[:split], f.opts))
+		f.buf = f.buf[split:]
+		f.midLine = true
+	}
+	return out.String()
+}
+
+// Flush returns whatever is still held back and resets the filter.
+// An unterminated code block is dropped.
+func (f *StreamFilter) Flush() string {
+	var result string
+	if f.buf != "" {
+		result = f.processLine(f.buf)
+	}
+	f.Reset()
+	return result
+}
+
+// Reset discards held text and any open construct.
+func (f *StreamFilter) Reset() {
+	f.buf = ""
+	f.midLine = false
+	f.inCode = false
+}
+
+// holdLine reports whether the partial line must wait for its newline:
+// it may be a code fence or table row, or it is only block markers so far
+// ("#", "-", "1." ...) whose meaning depends on what follows.
+func (f *StreamFilter) holdLine() bool {
+	if f.inCode {
+		return true
+	}
+	if f.midLine {
+		return false
+	}
+	trimmed := strings.TrimLeft(f.buf, " \t")
+	if strings.HasPrefix(trimmed, "`") || strings.HasPrefix(trimmed, "|") {
+		return true
+	}
+	return strings.TrimLeft(trimmed, "#>-*+=_0123456789. \t") == ""
+}
+
+// processLine filters one complete line (including its trailing newline, if any).
+func (f *StreamFilter) processLine(line string) string {
+	midLine := f.midLine
+	f.midLine = false
+	trimmed := strings.TrimSpace(line)
+
+	if !midLine && strings.HasPrefix(trimmed, "```") {
+		// A fence opens or closes a block unless the whole block is on one line
+		if strings.Count(trimmed, "```") == 1 {
+			f.inCode = !f.inCode
+			return ""
+		}
+	}
+	if f.inCode {
+		return ""
+	}
+	if !midLine && strings.HasPrefix(trimmed, "|") {
+		return tableRow(trimmed, line)
+	}
+	return filterWithOptions(line, f.opts)
+}
+
+// tableRow turns a Markdown table row into comma-separated cells;
+// separator rows (|---|:---:|) are dropped.
+func tableRow(trimmed, line string) string {
+	if strings.Trim(trimmed, "|-: \t") == "" {
+		return ""
+	}
+	var cells []string
+	for _, cell := range strings.Split(strings.Trim(trimmed, "|"), "|") {
+		if cell = strings.TrimSpace(cell); cell != "" {
+			cells = append(cells, Filter(cell))
+		}
+	}
+	result := strings.Join(cells, "，")
+	if strings.HasSuffix(line, "\n") {
+		result += "\n"
+	}
+	return result
+}
+
+// inlineSplit returns the position of the earliest inline construct that is
+// still open at the end of text; everything before it can be emitted.
+func inlineSplit(text string) int {
+	split := len(text)
+	if strings.Count(text, "`")%2 == 1 {
+		split = min(split, strings.LastIndex(text, "`"))
+	}
+	if strings.Count(text, "**")%2 == 1 {
+		split = min(split, strings.LastIndex(text, "**"))
+	} else if strings.Count(strings.ReplaceAll(text, "**", ""), "*")%2 == 1 {
+		split = min(split, strings.LastIndex(text, "*"))
+	}
+	if strings.Count(text, "~~")%2 == 1 {
+		split = min(split, strings.LastIndex(text, "~~"))
+	} else if strings.HasSuffix(text, "~") {
+		// A trailing "~" may be the start of "~~"
+		split = min(split, len(text)-1)
+	}
+	if i := strings.LastIndex(text, "["); i >= 0 {
+		rest := text[i:]
+		closeIdx := strings.Index(rest, "]")
+		// "[text" is open, "[text]" may still be followed by "(url)", or "(url" is open
+		if closeIdx < 0 || closeIdx == len(rest)-1 ||
+			(rest[closeIdx+1] == '(' && !strings.Contains(rest[closeIdx:], ")")) {
+			if i > 0 && text[i-1] == '!' {
+				i--
+			}
+			split = min(split, i)
+		}
+	}
+	if i := strings.LastIndex(text, "<"); i >= 0 && !strings.Contains(text[i:], ">") {
+		split = min(split, i)
+	}
+	return split
+}
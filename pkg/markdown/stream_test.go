@@ -0,0 +1,102 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStreamFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		chunks   []string
+		opts     Options
+		expected string
+	}{
+		{
+			name:     "plain text passes through",
+			chunks:   []string{"Hello ", "world."},
+			expected: "Hello world.",
+		},
+		{
+			name:     "bold split across chunks",
+			chunks:   []string{"This is **bo", "ld** text."},
+			expected: "This is bold text.",
+		},
+		{
+			name:     "inline code split across chunks",
+			chunks:   []string{"Run `go ", "test` now."},
+			expected: "Run go test now.",
+		},
+		{
+			name:     "link split across chunks",
+			chunks:   []string{"See [the ", "docs](https://exa", "mple.com) here."},
+			expected: "See the docs here.",
+		},
+		{
+			name:     "multi-line code block",
+			chunks:   []string{"Example:\n```go\nfunc main() {\n", "  fmt.Println(1)\n}\n``", "`\nDone."},
+			expected: "Example:\nDone.",
+		},
+		{
+			name:     "unterminated code block is dropped",
+			chunks:   []string{"Example:\n```\ncode line\n", "more code"},
+			expected: "Example:\n",
+		},
+		{
+			name:     "table rows",
+			chunks:   []string{"| 城市 | 温度 |\n|---|", "---|\n| 北京 | **25** |\n"},
+			expected: "城市，温度\n北京，25\n",
+		},
+		{
+			name:     "header split after marker",
+			chunks:   []string{"#", "# Title\nBody"},
+			expected: "Title\nBody",
+		},
+		{
+			name:     "list item emits before newline",
+			chunks:   []string{"* first item", " continues\n"},
+			expected: "first item continues\n",
+		},
+		{
+			name:     "strikethrough split across chunks",
+			chunks:   []string{"old ~", "~gone~~ new"},
+			expected: "old gone new",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewStreamFilter(tt.opts)
+			var got strings.Builder
+			for _, chunk := range tt.chunks {
+				got.WriteString(f.Feed(chunk))
+			}
+			got.WriteString(f.Flush())
+			if got.String() != tt.expected {
+				t.Errorf("StreamFilter = %q, want %q", got.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestStreamFilterEmitsEarly(t *testing.T) {
+	f := NewStreamFilter(Options{})
+	if got := f.Feed("好的，今天天气"); got != "好的，今天天气" {
+		t.Fatalf("Feed() = %q, want text emitted before newline", got)
+	}
+	if got := f.Feed("不错 **真"); got != "不错 " {
+		t.Fatalf("Feed() = %q, want open emphasis held back", got)
+	}
+	if got := f.Feed("的**。"); got != "真的。" {
+		t.Fatalf("Feed() = %q, want closed emphasis", got)
+	}
+}
+
+func TestStreamFilterReset(t *testing.T) {
+	f := NewStreamFilter(Options{})
+	f.Feed("```\ncode")
+	f.Reset()
+	if got := f.Feed("after reset"); got != "after reset" {
+		t.Fatalf("Feed() after Reset = %q", got)
+	}
+}